@@ -0,0 +1,150 @@
+"""
+Client-side batching of job submissions.
+
+Workflow engines often submit thousands of jobs one at a time. The
+SubmitBatcher coalesces such submissions into as few SubmitJobs requests
+as possible.
+"""
+
+import threading
+import uuid
+from concurrent.futures import Future
+from typing import Dict, List, Optional, Tuple
+
+from armada_client.armada import submit_pb2
+from armada_client.client import ArmadaClient
+
+# Response header via which the server advertises the number of jobs to
+# include in each submit request.
+OPTIMAL_JOBS_PER_REQUEST_HEADER = "armada-optimal-jobs-per-request"
+
+# Maximum number of jobs per request, matching the Go client.
+MAX_JOBS_PER_REQUEST = 200
+
+
+class _PendingBatch:
+    def __init__(self):
+        self.items: List[submit_pb2.JobSubmitRequestItem] = []
+        self.futures: List[Future] = []
+        self.in_flight = False
+
+
+class SubmitBatcher:
+    """
+    Batches jobs submitted concurrently into as few requests as possible.
+
+    For each job set, at most one request is in flight at a time, and jobs
+    submitted while a request is in flight are sent together in the next
+    request. Hence, a lone job is sent immediately, whereas jobs submitted
+    in quick succession are batched. The number of jobs per request is the
+    batch size most recently advertised by the server, which is reduced when
+    the server is under load.
+
+    Usage:
+
+    .. code-block:: python
+
+        batcher = SubmitBatcher(client)
+        future = batcher.submit(queue, job_set_id, job_request_item)
+        job_id = future.result().job_id
+
+    :param client: The Armada client used to submit jobs.
+    :param timeout: Timeout in seconds of each SubmitJobs request.
+    """
+
+    def __init__(self, client: ArmadaClient, timeout: Optional[float] = 30):
+        self.client = client
+        self.timeout = timeout
+        self.batch_size = MAX_JOBS_PER_REQUEST
+        self._batches: Dict[Tuple[str, str], _PendingBatch] = {}
+        self._lock = threading.Lock()
+
+    def submit(
+        self,
+        queue: str,
+        job_set_id: str,
+        job_request_item: submit_pb2.JobSubmitRequestItem,
+    ) -> Future:
+        """Submit a job as part of a batch.
+
+        If the job has no client id, one is assigned, such that retried
+        submissions are deduplicated by the server.
+
+        :param queue: The name of the queue
+        :param job_set_id: The name of the job set (a grouping of jobs)
+        :param job_request_item: The job to submit.
+        :return: A Future resolving to the JobSubmitResponseItem of the job.
+        """
+        if not job_request_item.client_id:
+            job_request_item.client_id = str(uuid.uuid4())
+        future: Future = Future()
+
+        with self._lock:
+            key = (queue, job_set_id)
+            batch = self._batches.setdefault(key, _PendingBatch())
+            batch.items.append(job_request_item)
+            batch.futures.append(future)
+            if not batch.in_flight:
+                self._flush(key, batch)
+        return future
+
+    def _flush(self, key: Tuple[str, str], batch: _PendingBatch) -> None:
+        # Must be called with self._lock held.
+        n = min(len(batch.items), self.batch_size)
+        items, futures = batch.items[:n], batch.futures[:n]
+        batch.items, batch.futures = batch.items[n:], batch.futures[n:]
+        batch.in_flight = True
+        threading.Thread(
+            target=self._send, args=(key, batch, items, futures), daemon=True
+        ).start()
+
+    def _send(self, key, batch, items, futures) -> None:
+        queue, job_set_id = key
+        try:
+            request = submit_pb2.JobSubmitRequest(
+                queue=queue, job_set_id=job_set_id, job_request_items=items
+            )
+            response, call = self.client.submit_stub.SubmitJobs.with_call(
+                request, timeout=self.timeout
+            )
+            batch_size = _advertised_batch_size(call.initial_metadata())
+            if batch_size:
+                with self._lock:
+                    self.batch_size = batch_size
+            response_items = response.job_response_items
+            if len(response_items) != len(items):
+                raise RuntimeError(
+                    f"expected {len(items)} response items "
+                    f"but got {len(response_items)}"
+                )
+            for future, item in zip(futures, response_items):
+                if item.error:
+                    future.set_exception(
+                        RuntimeError(f"failed to submit job: {item.error}")
+                    )
+                else:
+                    future.set_result(item)
+        except Exception as e:
+            for future in futures:
+                if not future.done():
+                    future.set_exception(e)
+
+        with self._lock:
+            batch.in_flight = False
+            if batch.items:
+                self._flush(key, batch)
+            else:
+                del self._batches[key]
+
+
+def _advertised_batch_size(metadata) -> Optional[int]:
+    for header, value in metadata or ():
+        if header == OPTIMAL_JOBS_PER_REQUEST_HEADER:
+            try:
+                size = int(value)
+            except ValueError:
+                return None
+            if size <= 0:
+                return None
+            return min(size, MAX_JOBS_PER_REQUEST)
+    return None
@@ -0,0 +1,154 @@
+"""
+Waiting for jobs to reach a terminal state via the job watch http api.
+
+Workflow engines, e.g., Airflow, Prefect, or Dagster sensors, often track
+thousands of jobs at once. Rather than polling each job, or streaming all
+events of their job sets, the JobWatcher subscribes to the jobs once and
+long-polls the subscription, which returns as soon as any of them reach a
+terminal state.
+"""
+
+import json
+import urllib.error
+import urllib.request
+from dataclasses import dataclass
+from typing import Callable, Dict, Iterable, List, Optional, Tuple
+
+JOB_WATCH_PATH = "/api/v1/job/watch"
+
+
+@dataclass(frozen=True)
+class JobWatchResult:
+    """The terminal state a watched job reached."""
+
+    job_id: str
+    queue: str
+    job_set_id: str
+    # One of "succeeded", "failed", or "cancelled".
+    outcome: str
+    # Reason the job failed or was cancelled, if any.
+    reason: str = ""
+
+
+class JobWatcher:
+    """
+    Waits for jobs to reach a terminal state.
+
+    If the subscription is lost, e.g., since it expired or the server
+    instance holding it was replaced, the watcher subscribes again to the
+    jobs yet to reach a terminal state.
+
+    Usage:
+
+    .. code-block:: python
+
+        watcher = JobWatcher("https://armada.example.com", headers=auth_headers)
+        results = watcher.wait({("queue", "job-set"): job_ids})
+        failed = [r for r in results.values() if r.outcome != "succeeded"]
+
+    :param url: The url of the rest api of the Armada server.
+    :param headers: Headers to include in each request, e.g., for authentication.
+    :param poll_timeout: Seconds each poll waits for jobs to reach a terminal
+        state; capped by the server.
+    """
+
+    def __init__(
+        self,
+        url: str,
+        headers: Optional[Dict[str, str]] = None,
+        poll_timeout: float = 30,
+    ):
+        if not url.startswith("http"):
+            url = f"http://{url}"
+        self.url = url.rstrip("/")
+        self.headers = headers or {}
+        self.poll_timeout = poll_timeout
+
+    def wait(
+        self,
+        job_ids_by_job_set: Dict[Tuple[str, str], Iterable[str]],
+        on_result: Optional[Callable[[JobWatchResult], None]] = None,
+    ) -> Dict[str, JobWatchResult]:
+        """Block until all jobs reached a terminal state.
+
+        :param job_ids_by_job_set: The ids of the jobs to wait for,
+            indexed by (queue, job set id).
+        :param on_result: Called once for each job as it reaches a terminal state.
+        :return: The result of each job, indexed by job id.
+        """
+        pending = {key: set(job_ids) for key, job_ids in job_ids_by_job_set.items()}
+        pending = {key: job_ids for key, job_ids in pending.items() if job_ids}
+        results: Dict[str, JobWatchResult] = {}
+        while pending:
+            subscription_id = self._subscribe(pending)
+            try:
+                while pending:
+                    poll = self._poll(subscription_id)
+                    if poll is None:
+                        # Subscription lost; subscribe again to the remaining jobs.
+                        break
+                    for job in poll["jobs"] or []:
+                        result = JobWatchResult(
+                            job_id=job["jobId"],
+                            queue=job["queue"],
+                            job_set_id=job["jobSetId"],
+                            outcome=job["outcome"],
+                            reason=job.get("reason", ""),
+                        )
+                        key = (result.queue, result.job_set_id)
+                        if result.job_id not in pending.get(key, ()):
+                            continue
+                        pending[key].discard(result.job_id)
+                        if not pending[key]:
+                            del pending[key]
+                        results[result.job_id] = result
+                        if on_result:
+                            on_result(result)
+                    if poll["done"]:
+                        return results
+            except BaseException:
+                self._unsubscribe(subscription_id)
+                raise
+        return results
+
+    def _subscribe(self, pending: Dict[Tuple[str, str], set]) -> str:
+        job_sets: List[dict] = [
+            {"queue": queue, "jobSetId": job_set_id, "jobIds": sorted(job_ids)}
+            for (queue, job_set_id), job_ids in pending.items()
+        ]
+        response = self._request(
+            "POST", JOB_WATCH_PATH, {"jobSets": job_sets}, timeout=30
+        )
+        return response["subscriptionId"]
+
+    def _poll(self, subscription_id: str) -> Optional[dict]:
+        path = f"{JOB_WATCH_PATH}/{subscription_id}?timeout={self.poll_timeout}s"
+        try:
+            return self._request("GET", path, timeout=self.poll_timeout + 30)
+        except urllib.error.HTTPError as e:
+            if e.code == 404:
+                return None
+            raise
+
+    def _unsubscribe(self, subscription_id: str) -> None:
+        # Best effort, such that the subscription doesn't linger until its
+        # lease expires.
+        try:
+            self._request("DELETE", f"{JOB_WATCH_PATH}/{subscription_id}", timeout=5)
+        except Exception:
+            pass
+
+    def _request(
+        self, method: str, path: str, body: Optional[dict] = None, timeout: float = 30
+    ) -> Optional[dict]:
+        data = None
+        headers = dict(self.headers)
+        if body is not None:
+            data = json.dumps(body).encode()
+            headers["Content-Type"] = "application/json"
+        request = urllib.request.Request(
+            self.url + path, data=data, headers=headers, method=method
+        )
+        with urllib.request.urlopen(request, timeout=timeout) as response:
+            content = response.read()
+        return json.loads(content) if content else None
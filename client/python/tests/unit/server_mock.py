@@ -19,6 +19,8 @@ class SubmitService(submit_pb2_grpc.SubmitServicer):
         return submit_pb2.Queue(name=request.name)
 
     def SubmitJobs(self, request, context):
+        context.send_initial_metadata((("armada-optimal-jobs-per-request", "100"),))
+
         # read job_ids from request.job_request_items
         job_ids = [f"job-{i}" for i in range(1, len(request.job_request_items) + 1)]
 
@@ -0,0 +1,54 @@
+from concurrent import futures
+
+import grpc
+import pytest
+
+from server_mock import SubmitService
+
+from armada_client.armada import submit_pb2_grpc, submit_pb2
+from armada_client.batcher import (
+    MAX_JOBS_PER_REQUEST,
+    OPTIMAL_JOBS_PER_REQUEST_HEADER,
+    SubmitBatcher,
+    _advertised_batch_size,
+)
+from armada_client.client import ArmadaClient
+
+
+@pytest.fixture(scope="module", autouse=True)
+def server_mock():
+    server = grpc.server(futures.ThreadPoolExecutor(max_workers=10))
+    submit_pb2_grpc.add_SubmitServicer_to_server(SubmitService(), server)
+    server.add_insecure_port("[::]:50052")
+    server.start()
+
+    yield
+    server.stop(False)
+
+
+def test_submit_batcher():
+    client = ArmadaClient(grpc.insecure_channel(target="127.0.0.1:50052"))
+    batcher = SubmitBatcher(client)
+
+    items = [submit_pb2.JobSubmitRequestItem(priority=1) for _ in range(10)]
+    results = [batcher.submit("test", "job-set", item) for item in items]
+
+    for item, result in zip(items, results):
+        assert result.result(timeout=10).job_id.startswith("job-")
+        assert item.client_id
+
+    assert batcher.batch_size == 100
+
+
+@pytest.mark.parametrize(
+    "metadata,expected",
+    [
+        ((), None),
+        (((OPTIMAL_JOBS_PER_REQUEST_HEADER, "50"),), 50),
+        (((OPTIMAL_JOBS_PER_REQUEST_HEADER, "100000"),), MAX_JOBS_PER_REQUEST),
+        (((OPTIMAL_JOBS_PER_REQUEST_HEADER, "many"),), None),
+        (((OPTIMAL_JOBS_PER_REQUEST_HEADER, "0"),), None),
+    ],
+)
+def test_advertised_batch_size(metadata, expected):
+    assert _advertised_batch_size(metadata) == expected
@@ -0,0 +1,69 @@
+import json
+import threading
+from http.server import BaseHTTPRequestHandler, HTTPServer
+
+import pytest
+
+from armada_client.job_watch import JobWatcher
+
+
+class JobWatchHandler(BaseHTTPRequestHandler):
+    subscriptions: list = []
+    polls = 0
+
+    def do_POST(self):
+        body = json.loads(self.rfile.read(int(self.headers["Content-Length"])))
+        JobWatchHandler.subscriptions.append(body)
+        self._reply({"subscriptionId": str(len(JobWatchHandler.subscriptions))})
+
+    def do_GET(self):
+        JobWatchHandler.polls += 1
+        if JobWatchHandler.polls == 1:
+            job = {"jobId": "a", "queue": "queue", "jobSetId": "set"}
+            self._reply({"jobs": [dict(job, outcome="succeeded")], "done": False})
+        elif JobWatchHandler.polls == 2:
+            # The subscription was lost, e.g., since the server restarted.
+            self.send_error(404)
+        else:
+            job = {"jobId": "b", "queue": "queue", "jobSetId": "set"}
+            job.update(outcome="failed", reason="oom")
+            self._reply({"jobs": [job], "done": True})
+
+    def _reply(self, body):
+        content = json.dumps(body).encode()
+        self.send_response(200)
+        self.send_header("Content-Type", "application/json")
+        self.send_header("Content-Length", str(len(content)))
+        self.end_headers()
+        self.wfile.write(content)
+
+    def log_message(self, *args):
+        pass
+
+
+@pytest.fixture
+def server_url():
+    server = HTTPServer(("127.0.0.1", 0), JobWatchHandler)
+    thread = threading.Thread(target=server.serve_forever, daemon=True)
+    thread.start()
+    yield f"http://127.0.0.1:{server.server_port}"
+    server.shutdown()
+
+
+def test_job_watcher_wait(server_url):
+    watcher = JobWatcher(server_url, poll_timeout=1)
+    seen = []
+
+    results = watcher.wait({("queue", "set"): ["a", "b"]}, on_result=seen.append)
+
+    assert {job_id: r.outcome for job_id, r in results.items()} == {
+        "a": "succeeded",
+        "b": "failed",
+    }
+    assert results["b"].reason == "oom"
+    assert [r.job_id for r in seen] == ["a", "b"]
+    # After losing the subscription, the watcher subscribes again to only the
+    # remaining jobs.
+    assert JobWatchHandler.subscriptions[1]["jobSets"] == [
+        {"queue": "queue", "jobSetId": "set", "jobIds": ["b"]}
+    ]
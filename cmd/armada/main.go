@@ -108,7 +108,7 @@ func main() {
 
 	// Start Armada server
 	g.Go(func() error {
-		return armada.Serve(ctx, &config, healthChecks)
+		return armada.Serve(ctx, &config, healthChecks, mux)
 	})
 
 	// Assume the server is ready if there are no errors within 10 seconds.
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/armadaproject/armada/internal/armadactl"
+)
+
+func adminCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Administer an Armada installation. Supported: backup, restore",
+	}
+	cmd.AddCommand(
+		backupCmd(armadactl.New()),
+		restoreCmd(armadactl.New()),
+	)
+	return cmd
+}
+
+func backupCmd(a *armadactl.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup <archive>",
+		Short: "Export all queues and unfinished jobs to an archive",
+		Long: `Exports all queues, along with their settings and permissions, and all jobs that haven't finished,
+along with the state of their most recent run, to a gzipped tar archive, e.g., for disaster-recovery drills or migrations.
+The archive can be imported into another installation using "armadactl admin restore".`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return initParams(cmd, a.Params)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.Backup(args[0])
+		},
+	}
+	return cmd
+}
+
+func restoreCmd(a *armadactl.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <archive>",
+		Short: "Import the queues and unfinished jobs of an archive",
+		Long: `Validates an archive created by "armadactl admin backup" and imports it, e.g., into a fresh installation.
+Queues that already exist are left as is. Jobs are resubmitted to their original job sets and hence get new ids;
+the mapping from old to new ids is printed. Jobs that were leased or running at the time of the backup are only
+resubmitted if --include-running is set, since they may still be running in the original installation.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return initParams(cmd, a.Params)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			includeRunning, err := cmd.Flags().GetBool("include-running")
+			if err != nil {
+				return err
+			}
+			dryRun, err := cmd.Flags().GetBool("dry-run")
+			if err != nil {
+				return err
+			}
+			return a.Restore(args[0], includeRunning, dryRun)
+		},
+	}
+	cmd.Flags().Bool("include-running", false, "Also resubmit jobs that were leased or running at the time of the backup.")
+	cmd.Flags().Bool("dry-run", false, "Validate the archive and exit without making any changes.")
+	return cmd
+}
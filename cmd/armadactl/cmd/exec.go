@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/armadaproject/armada/internal/armadactl"
+)
+
+func execCmd() *cobra.Command {
+	a := armadactl.New()
+	cmd := &cobra.Command{
+		Use:   "exec -- <command>",
+		Short: "Run a command in a running job.",
+		Long: `Run a command in a container of a running job, e.g., to debug it.
+
+The command is run using kubectl exec, tunnelled through the binoculars instance of the cluster the job is running on,
+which is located using the binocularsHttpUrlTemplate setting, e.g., "http://{{cluster}}-binoculars:8080".
+Requires kubectl to be installed, but not access to the cluster.`,
+		Example: `armadactl exec --queue my-queue --jobSet my-set --jobId 123456 -it -- bash`,
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return initParams(cmd, a.Params)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobId, queueName, jobSetId, podNumber, err := getJobPodFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			container, err := cmd.Flags().GetString("container")
+			if err != nil {
+				return fmt.Errorf("error reading container: %s", err)
+			}
+
+			stdin, err := cmd.Flags().GetBool("stdin")
+			if err != nil {
+				return fmt.Errorf("error reading stdin: %s", err)
+			}
+
+			tty, err := cmd.Flags().GetBool("tty")
+			if err != nil {
+				return fmt.Errorf("error reading tty: %s", err)
+			}
+
+			return a.Exec(jobId, queueName, jobSetId, podNumber, container, stdin, tty, args)
+		},
+	}
+	addJobPodFlags(cmd)
+	cmd.Flags().StringP(
+		"container", "c", "", "[optional] container to run the command in; defaults to the first container")
+	cmd.Flags().BoolP(
+		"stdin", "i", false, "[optional] pass stdin to the container")
+	cmd.Flags().BoolP(
+		"tty", "t", false, "[optional] stdin is a TTY")
+	return cmd
+}
+
+func portForwardCmd() *cobra.Command {
+	a := armadactl.New()
+	cmd := &cobra.Command{
+		Use:   "port-forward <[localPort:]remotePort>...",
+		Short: "Forward local ports to a running job.",
+		Long: `Forward one or more local ports to a running job, e.g., to connect to a debugger or dashboard.
+
+Ports are forwarded using kubectl port-forward, tunnelled through the binoculars instance of the cluster the job is running on,
+which is located using the binocularsHttpUrlTemplate setting, e.g., "http://{{cluster}}-binoculars:8080".
+Requires kubectl to be installed, but not access to the cluster.`,
+		Example: `armadactl port-forward --queue my-queue --jobSet my-set --jobId 123456 8888:80`,
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return initParams(cmd, a.Params)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobId, queueName, jobSetId, podNumber, err := getJobPodFlags(cmd)
+			if err != nil {
+				return err
+			}
+			return a.PortForward(jobId, queueName, jobSetId, podNumber, args)
+		},
+	}
+	addJobPodFlags(cmd)
+	return cmd
+}
+
+// addJobPodFlags adds the flags identifying a pod of a job.
+func addJobPodFlags(cmd *cobra.Command) {
+	cmd.Flags().String(
+		"jobId", "", "job to access")
+	if err := cmd.MarkFlagRequired("jobId"); err != nil {
+		panic(err)
+	}
+	cmd.Flags().String(
+		"queue", "", "queue of the job")
+	if err := cmd.MarkFlagRequired("queue"); err != nil {
+		panic(err)
+	}
+	cmd.Flags().String(
+		"jobSet", "", "jobSet of the job")
+	if err := cmd.MarkFlagRequired("jobSet"); err != nil {
+		panic(err)
+	}
+	cmd.Flags().Int(
+		"podNumber", 0, "[optional] for jobs with multiple pods, index of the pod")
+}
+
+// getJobPodFlags reads the flags added by addJobPodFlags.
+func getJobPodFlags(cmd *cobra.Command) (string, string, string, int, error) {
+	jobId, err := cmd.Flags().GetString("jobId")
+	if err != nil {
+		return "", "", "", 0, fmt.Errorf("error reading jobId: %s", err)
+	}
+
+	queueName, err := cmd.Flags().GetString("queue")
+	if err != nil {
+		return "", "", "", 0, fmt.Errorf("error reading queueName: %s", err)
+	}
+
+	jobSetId, err := cmd.Flags().GetString("jobSet")
+	if err != nil {
+		return "", "", "", 0, fmt.Errorf("error reading jobSet: %s", err)
+	}
+
+	podNumber, err := cmd.Flags().GetInt("podNumber")
+	if err != nil {
+		return "", "", "", 0, fmt.Errorf("error reading podNumber: %s", err)
+	}
+
+	return jobId, queueName, jobSetId, podNumber, nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/armadaproject/armada/internal/armadactl"
+)
+
+func logsCmd() *cobra.Command {
+	a := armadactl.New()
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Print the logs of a job.",
+		Long: `Print the logs of a running or recently finished job.
+
+Logs are retrieved via the binoculars instance of the cluster the job was assigned to,
+which is located using the binocularsUrlTemplate setting, e.g., "{{cluster}}-binoculars:50051".`,
+		Example: `armadactl logs --queue my-queue --jobSet my-set --jobId 123456 --follow`,
+		Args:    cobra.ExactArgs(0),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return initParams(cmd, a.Params)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobId, err := cmd.Flags().GetString("jobId")
+			if err != nil {
+				return fmt.Errorf("error reading jobId: %s", err)
+			}
+
+			queueName, err := cmd.Flags().GetString("queue")
+			if err != nil {
+				return fmt.Errorf("error reading queueName: %s", err)
+			}
+
+			jobSetId, err := cmd.Flags().GetString("jobSet")
+			if err != nil {
+				return fmt.Errorf("error reading jobSet: %s", err)
+			}
+
+			podNumber, err := cmd.Flags().GetInt("podNumber")
+			if err != nil {
+				return fmt.Errorf("error reading podNumber: %s", err)
+			}
+
+			follow, err := cmd.Flags().GetBool("follow")
+			if err != nil {
+				return fmt.Errorf("error reading follow: %s", err)
+			}
+
+			return a.Logs(jobId, queueName, jobSetId, podNumber, follow)
+		},
+	}
+	cmd.Flags().String(
+		"jobId", "", "job to print the logs of")
+	if err := cmd.MarkFlagRequired("jobId"); err != nil {
+		panic(err)
+	}
+	cmd.Flags().String(
+		"queue", "", "queue of the job")
+	if err := cmd.MarkFlagRequired("queue"); err != nil {
+		panic(err)
+	}
+	cmd.Flags().String(
+		"jobSet", "", "jobSet of the job")
+	if err := cmd.MarkFlagRequired("jobSet"); err != nil {
+		panic(err)
+	}
+	cmd.Flags().Int(
+		"podNumber", 0, "[optional] for jobs with multiple pods, index of the pod")
+	cmd.Flags().BoolP(
+		"follow", "f", false, "[optional] keep printing new log lines as they become available")
+	return cmd
+}
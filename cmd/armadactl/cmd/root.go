@@ -29,6 +29,7 @@ The location of this file can be passed in using --config argument or picked fro
 	client.AddArmadaApiConnectionCommandlineArgs(cmd)
 
 	cmd.AddCommand(
+		adminCmd(),
 		analyzeCmd(),
 		cancelCmd(),
 		createCmd(armadactl.New()),
@@ -37,6 +38,9 @@ The location of this file can be passed in using --config argument or picked fro
 		describeCmd(),
 		getCmd(),
 		kubeCmd(),
+		logsCmd(),
+		execCmd(),
+		portForwardCmd(),
 		reprioritizeCmd(),
 		resourcesCmd(),
 		submitCmd(),
@@ -45,6 +49,7 @@ The location of this file can be passed in using --config argument or picked fro
 		getSchedulingReportCmd(armadactl.New()),
 		getQueueSchedulingReportCmd(armadactl.New()),
 		getJobSchedulingReportCmd(armadactl.New()),
+		triggerSchedulingRoundCmd(armadactl.New()),
 	)
 
 	return cmd
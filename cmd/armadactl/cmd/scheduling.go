@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -107,3 +108,37 @@ func getJobSchedulingReportCmd(a *armadactl.App) *cobra.Command {
 	cmd.Flags().String("jobId", "", "Id of job to query reports for.")
 	return cmd
 }
+
+func triggerSchedulingRoundCmd(a *armadactl.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trigger-scheduling-round",
+		Short: "Run a scheduling round immediately",
+		Long: `Makes the scheduler run a scheduling round immediately rather than waiting for the next regular round,
+optionally restricted to one pool or queue, and prints a summary of the decisions made.
+Requires the trigger_scheduling_rounds permission.`,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return initParams(cmd, a.Params)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pool, err := cmd.Flags().GetString("pool")
+			if err != nil {
+				return err
+			}
+			queueName, err := cmd.Flags().GetString("queue")
+			if err != nil {
+				return err
+			}
+			timeout, err := cmd.Flags().GetDuration("timeout")
+			if err != nil {
+				return err
+			}
+			return a.TriggerSchedulingRound(strings.TrimSpace(pool), strings.TrimSpace(queueName), timeout)
+		},
+	}
+	cmd.Flags().String("pool", "", "only schedule onto executors of this pool")
+	cmd.Flags().String("queue", "", "only schedule jobs of this queue")
+	cmd.Flags().Duration("timeout", 2*time.Minute, "how long to wait for the round to complete")
+	return cmd
+}
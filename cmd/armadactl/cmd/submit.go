@@ -11,7 +11,7 @@ import (
 func submitCmd() *cobra.Command {
 	a := armadactl.New()
 	cmd := &cobra.Command{
-		Use:   "submit ./path/to/jobs.yaml",
+		Use:   "submit ./path/to/jobs.yaml | --from-dir ./path/to/dir",
 		Short: "Submit jobs to armada",
 		Long: `Submit jobs to armada from file.
 
@@ -22,8 +22,32 @@ jobs:
 	priority: 0
 	jobSetId: set1
 	podSpec:
-	... kubernetes pod spec ...`,
-		Args: cobra.ExactArgs(1),
+	... kubernetes pod spec ...
+
+Alternatively, submit jobs rendered from templates with --from-dir,
+which reads manifest.yaml from the given directory, e.g.,
+
+queue: test
+jobSetId: set1
+submissions:
+- template: templates/train.yaml
+	values:
+	- values/small.yaml
+	- values/large.yaml
+
+Each template is a Go template rendering to a jobs file as above,
+and is rendered once per values file, with the values available as .Values.
+Use --dry-run to print the rendered jobs instead of submitting them.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			fromDir, err := cmd.Flags().GetString("from-dir")
+			if err != nil {
+				return fmt.Errorf("error reading flag from-dir: %s", err)
+			}
+			if fromDir != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return initParams(cmd, a.Params)
 		},
@@ -33,11 +57,20 @@ jobs:
 				return fmt.Errorf("error reading flag dry-run: %s", err)
 			}
 
+			fromDir, err := cmd.Flags().GetString("from-dir")
+			if err != nil {
+				return fmt.Errorf("error reading flag from-dir: %s", err)
+			}
+			if fromDir != "" {
+				return a.SubmitFromDir(fromDir, dryRun)
+			}
+
 			path := args[0]
 
 			return a.Submit(path, dryRun)
 		},
 	}
 	cmd.Flags().Bool("dry-run", false, "Performs basic validation on the submitted file. Does no actual submission of jobs to the server.")
+	cmd.Flags().String("from-dir", "", "Submits jobs rendered from the templates listed in manifest.yaml in this directory.")
 	return cmd
 }
@@ -76,7 +76,7 @@ func main() {
 	)
 	defer shutdownGateway()
 
-	shutdown, wg := binoculars.StartUp(&config)
+	shutdown, wg := binoculars.StartUp(&config, mux)
 	go func() {
 		<-stopSignal
 		shutdown()
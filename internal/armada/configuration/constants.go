@@ -14,10 +14,133 @@ const (
 	// Specifically, if provided, all gang jobs are scheduled onto nodes for which the value of the provided label is equal.
 	// Used to ensure, e.g., that all gang jobs are scheduled onto the same cluster or rack.
 	GangNodeUniformityLabelAnnotation = "armadaproject.io/gangNodeUniformityLabel"
+	// Gangs for which this annotation has value "true" treat the node uniformity constraint as a preference.
+	// The scheduler first tries to schedule the gang onto nodes with a single value for the node uniformity label;
+	// if that's not possible, the gang is scheduled across any nodes instead of failing to schedule,
+	// and the extent to which it was spread out is recorded in the scheduling report.
+	GangNodeUniformitySoftAnnotation = "armadaproject.io/gangNodeUniformitySoft"
+	// GangNodeUniformityLabelsAnnotation Gangs may instead provide a comma-separated list of node uniformity labels, ordered from coarsest to finest,
+	// e.g., "region,zone,rack". The scheduler first tries to schedule the gang onto nodes with a single value for the finest label,
+	// falling back to each coarser label in turn. Can't be combined with GangNodeUniformityLabelAnnotation.
+	GangNodeUniformityLabelsAnnotation = "armadaproject.io/gangNodeUniformityLabels"
+	// GangFailureDomainLabelAnnotation Gangs may request to be spread across failure domains, e.g., racks, identified by the value of this node label,
+	// such that the gang can survive the failure of any one domain. Must be set together with GangMinFailureDomainsAnnotation.
+	GangFailureDomainLabelAnnotation = "armadaproject.io/gangFailureDomainLabel"
+	// GangMinFailureDomainsAnnotation The minimum number of distinct values of the failure-domain label the nodes of a gang must span.
+	// Should be expressed as a positive integer no greater than the gang minimum cardinality, e.g., "3".
+	GangMinFailureDomainsAnnotation = "armadaproject.io/gangMinFailureDomains"
+	// GangSpreadLabelAnnotation Gangs may require each of their jobs to be scheduled onto a node with a distinct value of this node label,
+	// e.g., to force the replicas of a training job across racks or zones for fault tolerance; the inverse of GangNodeUniformityLabelAnnotation.
+	// Can't be combined with a node uniformity or failure-domain constraint on the same gang.
+	GangSpreadLabelAnnotation = "armadaproject.io/gangSpreadLabel"
+	// GangQueueAnnotation Gangs may be made up of jobs submitted to different queues, e.g., shared infrastructure jobs and user jobs,
+	// in which case all jobs of the gang must set this annotation to the queue owning the gang.
+	// The gang is scheduled in the turn of the owning queue, while each job counts against the limits of, and is allocated to, the queue it's submitted to.
+	GangQueueAnnotation = "armadaproject.io/gangQueue"
+	// GangSchedulingDeadlineAnnotation Gangs may set this annotation to a duration, e.g., "2h", after which the gang is failed if it
+	// hasn't been scheduled, counting from the submission of its first job, instead of remaining queued indefinitely.
+	// Must be equal for all jobs of the gang.
+	GangSchedulingDeadlineAnnotation = "armadaproject.io/gangSchedulingDeadline"
+	// GangRoleAnnotation Jobs in a gang may be assigned a role, e.g., "ps" or "worker", to support gangs made up of jobs of different shapes.
+	// Role-specific placement constraints are expressed via the node selectors, affinities, and tolerations of each job.
+	GangRoleAnnotation = "armadaproject.io/gangRole"
+	// GangRoleCardinalityAnnotation All jobs with a role must specify the total number of jobs in the gang with that role via this annotation.
+	// The cardinality should be expressed as a positive integer, e.g., "8".
+	GangRoleCardinalityAnnotation = "armadaproject.io/gangRoleCardinality"
+	// GangRoleMinimumCardinalityAnnotation Jobs with a role may specify the minimum number of jobs with that role that must be scheduled
+	// for the gang to be schedulable via this annotation. Defaults to the role cardinality, i.e., all jobs with the role are required.
+	GangRoleMinimumCardinalityAnnotation = "armadaproject.io/gangRoleMinimumCardinality"
+	// GangLeaderAnnotation Within a gang, the services and ingresses of the job for which this annotation has value "true"
+	// are named after the gang rather than the job, e.g., armada-gang-<gangId>-headless for a headless service,
+	// such that other jobs in the gang can reach it at an address known before the gang is scheduled,
+	// e.g., to connect to the head of a distributed training job. At most one job per gang may be the leader.
+	GangLeaderAnnotation = "armadaproject.io/gangLeader"
+	// ColocationGroupAnnotation Jobs may request to be scheduled alongside running jobs in the same queue with equal value for this annotation,
+	// e.g., to be co-located with a data cache, regardless of which job set those jobs were submitted as part of.
+	// Only jobs bound to nodes at the start of a scheduling round are considered; if there are none, jobs are scheduled as usual.
+	ColocationGroupAnnotation = "armadaproject.io/colocationGroup"
+	// ColocationLabelAnnotation Jobs in a colocation group are scheduled onto the same node as a running job in the group,
+	// or, if this annotation is set, onto a node with the same value for the given node label as a running job in the group.
+	ColocationLabelAnnotation = "armadaproject.io/colocationLabel"
 	// Armada normally tries to re-schedule jobs for which a pod fails to start.
 	// Pods for which this annotation has value "true" are not retried.
 	// Instead, the job the pod is part of fails immediately.
 	FailFastAnnotation = "armadaproject.io/failFast"
+	// ParentJobIdAnnotation Jobs may record the id of the job they were spawned from, e.g., when resubmitting a failed job.
+	// Used to build the lineage graph of jobs.
+	ParentJobIdAnnotation = "armadaproject.io/parentJobId"
+	// Annotations set by the Airflow operator to identify the workflow task that submitted a job.
+	// Jobs with equal dag id and task run id were spawned by the same workflow run.
+	AirflowDagIdAnnotation     = "armadaproject.io/dagId"
+	AirflowTaskIdAnnotation    = "armadaproject.io/taskId"
+	AirflowTaskRunIdAnnotation = "armadaproject.io/taskRunId"
+	// JobTemplateAnnotation Jobs with equal queue and value for this annotation are considered instances of the same template
+	// when computing rightsizing recommendations. If not set, the Airflow dag and task id are used instead, or else the job set.
+	JobTemplateAnnotation = "armadaproject.io/jobTemplate"
+	// AutoRightsizeAnnotation Jobs for which this annotation has value "true" opt in to having their resource requests
+	// lowered at submission according to the rightsizing recommendation for their template.
+	AutoRightsizeAnnotation = "armadaproject.io/autoRightsize"
+	// CaptureOutputAnnotation Jobs may request that, once they succeed, the executor uploads their output to object storage
+	// and records its location on the job succeeded event, e.g., for downstream Airflow tasks to consume via XCom.
+	// The value is either CaptureOutputLogs, to capture the logs of the first container,
+	// or the absolute path of a file written by the first container. Files are captured via the container's termination message
+	// and are hence limited to 4KiB.
+	CaptureOutputAnnotation = "armadaproject.io/captureOutput"
+	CaptureOutputLogs       = "logs"
+	// SecretReferencesAnnotation Jobs may reference secrets by name via this annotation, as a comma-separated list, e.g., "db-creds,api-token".
+	// Referenced secrets are resolved by the executor from its configured secret provider when creating the job's pod,
+	// such that secret values are never submitted to or stored by Armada. Within the pod spec, referenced secrets are used
+	// as regular Kubernetes secrets, e.g., via secretKeyRef, envFrom, or secret volumes, and are rewritten by the executor
+	// to refer to a secret holding the resolved values that's deleted together with the pod.
+	SecretReferencesAnnotation = "armadaproject.io/secretRefs"
+	// RequiredFeaturesAnnotation Jobs may require executor features via this annotation, as a comma-separated list, e.g., "in-place-resize".
+	// Such jobs are only scheduled onto clusters whose executor reported all of the listed features when registering with the scheduler.
+	RequiredFeaturesAnnotation = "armadaproject.io/requiredFeatures"
+	// MinKubernetesVersionAnnotation Jobs may require a minimum Kubernetes version via this annotation, e.g., "v1.27".
+	// Such jobs are only scheduled onto clusters whose executor reported at least this version when registering with the scheduler.
+	MinKubernetesVersionAnnotation = "armadaproject.io/minKubernetesVersion"
+	// NodeFlavorAnnotation Jobs may request a node flavor, configured via scheduling.nodeFlavors, via this annotation, e.g., "gpu-a100-1x",
+	// instead of specifying resource requests. The resources, node selector, and tolerations of the flavor are applied at submission.
+	NodeFlavorAnnotation = "armadaproject.io/nodeFlavor"
+	// ExpectedRuntimeAnnotation Jobs may give their predicted runtime via this annotation, as a duration, e.g., "30m".
+	// Used to order jobs in queues using the ShortestJobFirst intra-queue ordering.
+	ExpectedRuntimeAnnotation = "armadaproject.io/expectedRuntime"
+	// JobSetMaxRunningJobsAnnotation Jobs may limit the number of jobs of their job set running at the same time via this annotation,
+	// e.g., "50" to run at most 50 jobs of a job set of 5000 jobs at once. Jobs of the same job set should specify the same limit.
+	JobSetMaxRunningJobsAnnotation = "armadaproject.io/jobSetMaxRunningJobs"
+	// ScheduleNearJobsAnnotation Jobs may give a comma-separated list of ids of previously submitted jobs via this annotation,
+	// e.g., to make use of data those jobs cached on the nodes they ran on. The job then prefers, but doesn't require, to be scheduled
+	// onto the nodes those jobs most recently ran on, with weight scheduling.scheduleNearJobsPreferenceWeight.
+	ScheduleNearJobsAnnotation = "armadaproject.io/scheduleNearJobs"
+	// NonPreemptibleAnnotation Jobs with this annotation set to "true" aren't preempted to balance resource usage across queues
+	// for as long as their queue is allocated at most its fair share. They may still be preempted, e.g., by jobs of higher priority.
+	NonPreemptibleAnnotation = "armadaproject.io/nonPreemptible"
+	// PreferSpotAnnotation Jobs with this annotation set to "true" prefer, but don't require, to be scheduled onto spot nodes,
+	// i.e., nodes with all of scheduling.spotNodeLabels, with weight scheduling.spotPreferenceWeight.
+	PreferSpotAnnotation = "armadaproject.io/preferSpot"
+	// PausedAnnotation Set by Armada on jobs submitted in excess of the queued jobs limit of their queue
+	// when queueManagement.queuedJobsLimitBehavior is Pause. Paused jobs aren't scheduled for as long as their queue has other queued jobs.
+	PausedAnnotation = "armadaproject.io/paused"
+	// PoolAnnotation Jobs with this annotation are only scheduled onto executors of the given pool, e.g., "gpu".
+	// Set by Armada at submission on jobs matching any of scheduling.poolRoutingRules, overriding any value given by the client.
+	PoolAnnotation = "armadaproject.io/pool"
+	// RuntimeClassNameAnnotation Set by Armada on the scheduling requirements of jobs with a runtime class,
+	// such that they're only scheduled onto clusters whose executor reported that runtime class when registering with the scheduler.
+	RuntimeClassNameAnnotation = "armadaproject.io/runtimeClassName"
+	// BindToExecutorAnnotation and BindToNodeAnnotation Jobs already placed by an external scheduler may be submitted bound to a node
+	// by setting both annotations to the id of the executor and the name of the node, respectively. Armada doesn't schedule such jobs;
+	// instead, they're leased to the given node as soon as the executor reports it, but are otherwise accounted for and managed
+	// like any other job. Submitting bound jobs requires the submit_bind_only_jobs permission.
+	BindToExecutorAnnotation = "armadaproject.io/bindToExecutor"
+	BindToNodeAnnotation     = "armadaproject.io/bindToNode"
+	// JobSpecRefAnnotation Jobs may, instead of including their spec, reference a job spec stored as an OCI artifact
+	// by setting this annotation to a digest-pinned reference, e.g., registry.example.com/team/spec@sha256:<digest>.
+	// The server fetches the spec at submission; fields set on the submitted job take precedence over those of the spec.
+	JobSpecRefAnnotation = "armadaproject.io/jobSpecRef"
+	// SingleNumaCellAnnotation Jobs with this annotation set to "true" are only scheduled such that their cpu and memory requests
+	// fit within a single NUMA cell of the node they're scheduled onto, e.g., for latency-sensitive HPC jobs.
+	// Requires scheduling.numaCellsLabel to be set.
+	SingleNumaCellAnnotation = "armadaproject.io/singleNumaCell"
 )
 
 var ReturnLeaseRequestTrackedAnnotations = map[string]struct{}{
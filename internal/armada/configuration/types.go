@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	authconfig "github.com/armadaproject/armada/internal/common/auth/configuration"
+	commonconfig "github.com/armadaproject/armada/internal/common/config"
 	grpcconfig "github.com/armadaproject/armada/internal/common/grpc/configuration"
 	armadaresource "github.com/armadaproject/armada/internal/common/resource"
 	"github.com/armadaproject/armada/internal/common/types"
@@ -39,8 +40,16 @@ type ArmadaConfig struct {
 	Scheduling                        SchedulingConfig
 	NewScheduler                      NewSchedulerConfig
 	QueueManagement                   QueueManagementConfig
+	QueueOnboarding                   QueueOnboardingConfig
+	AutoRightsize                     AutoRightsizeConfig
+	SubmissionRateLimits              SubmissionRateLimitConfig
+	Regions                           RegionsConfig
+	JobSpecArtifacts                  JobSpecArtifactsConfig
+	JobWatch                          JobWatchConfig
+	OptimalJobsPerSubmitRequest       int // Batch size advertised to clients; zero disables advertising
 	Pulsar                            PulsarConfig
-	Postgres                          PostgresConfig // Used for Pulsar submit API deduplication
+	JobSpecEncryption                 commonconfig.EncryptionConfig // Envelope encryption of job specs stored in Redis
+	Postgres                          PostgresConfig                // Used for Pulsar submit API deduplication
 	EventApi                          EventApiConfig
 	Metrics                           MetricsConfig
 	IgnoreJobSubmitChecks             bool // Temporary flag to stop us rejecting jobs on switch over
@@ -61,16 +70,27 @@ type PulsarConfig struct {
 	MaxConnectionsPerBroker int
 	// Whether Pulsar authentication is enabled
 	AuthenticationEnabled bool
-	// Authentication type. For now only "JWT" auth is valid
+	// Authentication type. Either "JWT" or "TLS"
 	AuthenticationType string
 	// Path to the JWT token (must exist). This must be set if AutheticationType is "JWT"
-	JwtTokenPath                string
+	JwtTokenPath string
+	// Paths to the client certificate and key (must exist). These must be set if AuthenticationType is "TLS".
+	// The files are reloaded when they change, such that rotated certificates are used for new connections.
+	TLSCertFilePath             string
+	TLSKeyFilePath              string
 	JobsetEventsTopic           string
 	RedisFromPulsarSubscription string
 	// Compression to use.  Valid values are "None", "LZ4", "Zlib", "Zstd".  Default is "None"
 	CompressionType pulsar.CompressionType
 	// Compression Level to use.  Valid values are "Default", "Better", "Faster".  Default is "Default"
 	CompressionLevel pulsar.CompressionLevel
+	// If non-empty, messages are end-to-end encrypted using Pulsar's built-in envelope encryption,
+	// with the session key of each message encrypted with the public key of each of these keys.
+	EncryptionKeyNames []string
+	// Directory containing, for each encryption key, its public key as <name>.pub and its private key as <name>.pem.
+	// Producers need only the public keys of EncryptionKeyNames; consumers need the private keys of all keys messages may have been encrypted with.
+	// To rotate keys, add the new key pair to the directory of consumers, then replace the key in EncryptionKeyNames of producers.
+	EncryptionKeyDirectory string
 	// Used to construct an executorconfig.IngressConfiguration,
 	// which is used when converting Armada-specific IngressConfig and ServiceConfig objects into k8s objects.
 	HostnameSuffix string
@@ -117,7 +137,20 @@ type SchedulingConfig struct {
 	// If true, schedule jobs across all executors in the same pool in a unified manner.
 	// Otherwise, schedule each executor separately.
 	UnifiedSchedulingByPool bool
-	Preemption              PreemptionConfig
+	// Maximum number of pools scheduled concurrently within a round if UnifiedSchedulingByPool is true.
+	// Pools are scheduled independently, each with its own scheduling context and nodeDb;
+	// pools whose decisions overlap with those of another pool scheduled concurrently, e.g., by scheduling the same queued job,
+	// are re-scheduled once the decisions of the other pool have been applied.
+	// If zero or one, or if UnifiedSchedulingByPool is false, executor groups are scheduled one at a time.
+	MaxConcurrentPools uint
+	Preemption         PreemptionConfig
+	// Pools in which running jobs are never preempted, neither to balance resource usage across queues
+	// nor to make room for jobs of higher priority; jobs are only scheduled onto unallocated resources in these pools.
+	// Resources allocated in these pools still count towards the fair share of each queue.
+	// Priority classes with per-pool limits for any of these pools may not have higher priority than any preemptible priority class,
+	// since jobs of such a priority class would only be scheduled in these pools by means of preemption.
+	// Only used by the new scheduler.
+	PreemptionFreePools []string
 	// Number of jobs to load from the database at a time.
 	MaxQueueLookback uint
 	// In each invocation of the scheduler, no more jobs are scheduled once this limit has been exceeded.
@@ -125,6 +158,12 @@ type SchedulingConfig struct {
 	MaximumResourceFractionToSchedule map[string]float64
 	// Overrides MaximalClusterFractionToSchedule if set for the current pool.
 	MaximumResourceFractionToScheduleByPool map[string]map[string]float64
+	// Minimum amount of each resource jobs must request to be scheduled in a given pool, indexed by pool name,
+	// e.g., {"gpu": {"nvidia.com/gpu": "1"}, "highmem": {"memory": "32Gi"}}, to prevent small jobs from fragmenting specialised nodes.
+	// Applies in addition to the MinimumJobSize of each executor.
+	// Jobs targeting a pool via PoolAnnotation that request less are accepted, but a warning is returned at submission.
+	// Only used by the new scheduler.
+	MinimumJobSizeByPool map[string]map[string]resource.Quantity
 	// The rate at which Armada schedules jobs is rate-limited using a token bucket approach.
 	// Specifically, there is a token bucket that persists between scheduling rounds.
 	// The bucket fills up at a rate of MaximumSchedulingRate tokens per second and has capacity MaximumSchedulingBurst.
@@ -164,10 +203,73 @@ type SchedulingConfig struct {
 	DefaultJobTolerationsByPriorityClass map[string][]v1.Toleration
 	// Set of tolerations added to all submitted pods with a given resource request.
 	DefaultJobTolerationsByResourceRequest map[string][]v1.Toleration
+	// Pod policies enforced at submission for jobs submitted to a given queue, indexed by queue name.
+	QueuePodPolicies map[string]QueuePodPolicy
+	// Defaults injected at submission into the pods of jobs submitted to a given queue, indexed by queue name,
+	// e.g., to steer all jobs of a queue to a particular pool without changing the specs of those jobs.
+	// Applied before any other defaults, such that, e.g., DefaultJobTolerationsByPriorityClass applies to the priority class set here.
+	QueueJobDefaults map[string]QueueJobDefaults
+	// Node selector added to all submitted pods that use a given runtime class, indexed by runtime class name,
+	// e.g., to ensure pods using gVisor are only scheduled onto nodes labelled as supporting gVisor.
+	// If non-empty, pods using a runtime class not in this map are rejected at submission.
+	RuntimeClassNodeSelectors map[string]map[string]string
+	// Node flavors, i.e., named node shapes, jobs may request via NodeFlavorAnnotation instead of specifying resources,
+	// indexed by flavor name, e.g., "gpu-a100-1x".
+	NodeFlavors map[string]NodeFlavor
+	// Rules routing submitted jobs to the pool appropriate for their requirements, e.g., jobs requesting GPUs to a GPU pool.
+	// Jobs are routed by the first rule they match, by setting PoolAnnotation; jobs matching no rule are left as submitted.
+	PoolRoutingRules []PoolRoutingRule
 	// Maximum number of times a job is retried before considered failed.
 	MaxRetries uint
+	// Number of consecutive scheduling rounds a queued job may be unsatisfiable, i.e., fail to fit onto any node
+	// even if that node were empty, before the job is failed with an error explaining why.
+	// If zero, unsatisfiable jobs are left queued.
+	// Only used by the new scheduler.
+	MaxUnsatisfiableRounds uint
+	// Maximum number of job scheduling contexts of unsuccessful scheduling attempts retained per queue and scheduling round,
+	// bounding the memory used by rounds in which many jobs fail to schedule.
+	// Beyond this limit, a sample including at least one context per unschedulable reason is retained,
+	// and the number of unsuccessful attempts is counted per reason. If zero, all contexts are retained.
+	MaxUnsuccessfulJobSchedulingContextsPerQueue uint
+	// Maximum number of consecutive scheduling rounds skipped since their inputs, i.e., the jobs, nodes, and queues,
+	// are unchanged from the previous round, in which case the scheduling decisions would be unchanged as well.
+	// A full round is run at least once after this many skipped rounds, e.g., to account for replenished rate limits.
+	// If zero, no rounds are skipped.
+	// Only used by the new scheduler.
+	MaxSkippedUnchangedRounds uint
+	// Maximum number of consecutive incremental scheduling rounds, which only consider the queued jobs of queues
+	// whose queued jobs changed since the previous round, provided nothing else changed and that round made no decisions.
+	// A full round, considering the queued jobs of all queues, is run at least once after this many incremental rounds.
+	// If zero, all rounds are full rounds.
+	// Only used by the new scheduler.
+	MaxConsecutiveIncrementalRounds uint
+	// Optimization-based placement of large gangs.
+	// Only used by the new scheduler.
+	GangPacking GangPackingConfig
+	// Reserving capacity freed across rounds for large gangs that can't otherwise be scheduled.
+	// Only used by the new scheduler.
+	GangAccumulation GangAccumulationConfig
+	// Crediting queues for work lost to preemption, such that they're prioritised when rescheduling.
+	// Only used by the new scheduler.
+	PreemptionCompensation PreemptionCompensationConfig
 	// Controls how fairness is calculated. Can be either AssetFairness or DominantResourceFairness.
 	FairnessModel FairnessModel
+	// Order in which the queued jobs of each queue are considered for scheduling,
+	// unless overridden for a particular queue via IntraQueueOrderingByQueue. Defaults to Fifo.
+	IntraQueueOrdering IntraQueueOrdering
+	// Order in which the queued jobs of a particular queue are considered for scheduling, indexed by queue name.
+	IntraQueueOrderingByQueue map[string]IntraQueueOrdering
+	// Criterion by which the gang scheduler compares candidate placements of a gang, e.g., onto nodes with different values
+	// of the gang's node uniformity label, unless overridden for a particular pool via GangPlacementScoringByPool.
+	// Defaults to MeanScheduledAtPriority.
+	GangPlacementScoring GangPlacementScoring
+	// Criterion by which candidate gang placements are compared in a particular pool, indexed by pool name.
+	GangPlacementScoringByPool map[string]GangPlacementScoring
+	// Node label whose value is the cost of a node, e.g., its hourly price, used by NodeCost gang placement scoring.
+	// Nodes without the label, or with a value that isn't a number, have zero cost.
+	GangPlacementNodeCostLabel string
+	// Criterion by which the gang scheduler chooses between candidate placements with equal score. Defaults to Lexicographic.
+	GangPlacementTieBreak GangPlacementTieBreak
 	// List of resource names, e.g., []string{"cpu", "memory"}, to consider when computing DominantResourceFairness.
 	DominantResourceFairnessResourcesToConsider []string
 	// Weights used to compute fair share when using AssetFairness.
@@ -184,6 +286,29 @@ type SchedulingConfig struct {
 	// In particular, the score expresses whether preemption is necessary to schedule a pod.
 	// Hence, a larger MaxExtraNodesToConsider would reduce the expected number of preemptions.
 	MaxExtraNodesToConsider uint
+	// Like MaxExtraNodesToConsider, but for jobs with preferred node affinity terms.
+	// Nodes are scored by the sum of the weights of the preferred terms they match;
+	// hence, a larger value makes it more likely that jobs are scheduled onto the nodes they prefer.
+	MaxExtraNodesToConsiderForPreferences uint
+	// Weight, between 1 and 100, of the node preference given to jobs asking to be scheduled near other jobs via ScheduleNearJobsAnnotation
+	// for the nodes those jobs most recently ran on. If zero, the annotation is ignored.
+	// Only used by the new scheduler.
+	ScheduleNearJobsPreferenceWeight int32 `validate:"gte=0,lte=100"`
+	// Node labels identifying spot nodes, e.g., {"node.kubernetes.io/lifecycle": "spot"}.
+	// Jobs setting PreferSpotAnnotation are given a node preference for nodes with all of these labels at submission.
+	// If empty, the annotation is ignored.
+	SpotNodeLabels map[string]string
+	// Weight, between 1 and 100, of the node preference given to jobs setting PreferSpotAnnotation. If zero, the annotation is ignored.
+	SpotPreferenceWeight int32 `validate:"gte=0,lte=100"`
+	// Maximum number of Armada jobs bound to any node at the same time; zero means unlimited.
+	MaxJobsPerNode uint
+	// Maximum number of members of the same gang bound to any node at the same time; zero means unlimited.
+	// Limits the number of members of a gang lost if a single node fails.
+	MaxGangMembersPerNode uint
+	// Node label whose value is the number of NUMA cells of a node, e.g., as reported by executors probing node hardware.
+	// Jobs with SingleNumaCellAnnotation set are only scheduled onto nodes with this label,
+	// such that their cpu and memory requests fit within a single cell, assuming the resources of each node are split evenly across its cells.
+	NumaCellsLabel string
 	// Resources, e.g., "cpu", "memory", and "nvidia.com/gpu",
 	// for which the scheduler creates indexes for efficient lookup.
 	// Applies only to the new scheduler.
@@ -208,6 +333,41 @@ type SchedulingConfig struct {
 	IndexedTaints []string
 	// Default value of GangNodeUniformityLabelAnnotation if none is provided.
 	DefaultGangNodeUniformityLabel string
+	// Maximum number of values of a gang's node uniformity label to make scheduling attempts for.
+	// Values are ranked by free capacity relative to the resources requested by the gang
+	// and only the top values are considered. Making an attempt for every value is prohibitively slow
+	// for labels with many values, e.g., one per rack.
+	//
+	// If zero, all values are considered.
+	MaxNodeUniformityLabelValuesToConsider uint
+	// Maximum number of values of a gang's node uniformity label to make scheduling attempts for in a particular pool,
+	// indexed by pool name. Overrides MaxNodeUniformityLabelValuesToConsider, e.g., for pools with many racks.
+	MaxNodeUniformityLabelValuesToConsiderByPool map[string]uint
+	// If true, members of a gang left unscheduled once the gang's minimum cardinality is met aren't failed.
+	// Instead, they remain queued and are later scheduled as a remainder gang, i.e., a gang made up of those
+	// members only that rejoins the original gang once scheduled.
+	//
+	// If false, such members are failed.
+	EnableGangRemainders bool
+	// If true, members of running gangs that fail since their lease expired, e.g., since their node was lost,
+	// or since they were evicted or ran out of memory, are requeued as replacements rather than failed,
+	// provided they haven't reached the maximum number of attempts and other members of the gang are still running.
+	// Replacements are scheduled as remainder gangs, onto nodes with the same node uniformity label value as the surviving members.
+	// Requires EnableGangRemainders.
+	EnableGangHealing bool
+	// If true, when a gang fails to schedule since its jobs don't fit, every node is evaluated against the requirements
+	// of its jobs and the number of nodes rejected for each reason, e.g., insufficient cpu or an untolerated taint,
+	// is recorded in their job scheduling contexts and appended to their unschedulable reasons.
+	// Since this considers every node for every such gang, it may slow down scheduling rounds of large pools.
+	EnableNodeRejectionDiagnostics bool
+	// Resources set aside on each node, in addition to any resources reserved by Kubernetes, that jobs are never scheduled onto.
+	// Used to leave headroom for, e.g., growth in daemonset resource usage,
+	// which could otherwise cause the kubelet to reject pods placed onto nodes by Armada.
+	// Maps resource name to the amount reserved on each node, e.g., {"cpu": "1", "memory": "4Gi"}.
+	NodeReservedResources map[string]resource.Quantity
+	// Like NodeReservedResources, but maps resource name to the fraction of the total amount of that resource on each node to reserve,
+	// e.g., {"memory": 0.05}. If both are set for a resource, the larger of the two amounts is reserved.
+	NodeReservedResourceFractions map[string]float64
 	// Kubernetes pods may specify a termination grace period.
 	// When Pods are cancelled/preempted etc., they are first sent a SIGTERM.
 	// If a pod has not exited within its termination grace period,
@@ -248,6 +408,20 @@ type SchedulingConfig struct {
 	ExecutorUpdateFrequency time.Duration
 	// Enable new preemption strategy.
 	EnableNewPreemptionStrategy bool
+	// If true, Armada shares clusters with other schedulers. Resources allocated to pods not managed by Armada,
+	// which executors report with each heartbeat, are then treated as unpreemptible, i.e., as unavailable to Armada jobs of any priority,
+	// and preemptible Armada jobs are always evicted from nodes that have become oversubscribed, e.g., because of pods scheduled externally,
+	// to be re-scheduled elsewhere if possible.
+	EnableExternalWorkloadCoexistence bool
+}
+
+// EffectiveNodeOversubscriptionEvictionProbability returns the probability with which jobs are evicted from oversubscribed nodes,
+// accounting for EnableExternalWorkloadCoexistence.
+func (c SchedulingConfig) EffectiveNodeOversubscriptionEvictionProbability() float64 {
+	if c.EnableExternalWorkloadCoexistence {
+		return 1
+	}
+	return c.Preemption.NodeOversubscriptionEvictionProbability
 }
 
 // FairnessModel controls how fairness is computed.
@@ -264,6 +438,88 @@ const (
 	DominantResourceFairness FairnessModel = "DominantResourceFairness"
 )
 
+// IntraQueueOrderingForQueue returns the order in which the queued jobs of the given queue are considered for scheduling.
+// IsPreemptionFreePool returns true if running jobs are never preempted in the given pool.
+func (c SchedulingConfig) IsPreemptionFreePool(pool string) bool {
+	return slices.Contains(c.PreemptionFreePools, pool)
+}
+
+func (c SchedulingConfig) IntraQueueOrderingForQueue(queue string) IntraQueueOrdering {
+	if ordering, ok := c.IntraQueueOrderingByQueue[queue]; ok && ordering != "" {
+		return ordering
+	}
+	if c.IntraQueueOrdering != "" {
+		return c.IntraQueueOrdering
+	}
+	return Fifo
+}
+
+// IntraQueueOrdering controls the order in which the queued jobs of a queue are considered for scheduling.
+// Across queues, jobs are always considered in the order determined by the FairnessModel.
+type IntraQueueOrdering string
+
+const (
+	// Fifo orders jobs first by priority class priority, with higher values first,
+	// second by in-queue priority, with smaller values first, and finally by submit time, with earlier submit times first.
+	Fifo IntraQueueOrdering = "Fifo"
+	// JobSetRoundRobin takes one job from each job set in turn, such that a large job set can't starve the others.
+	// Job sets take turns in the order of their first job in Fifo order, and the jobs of each job set retain their Fifo order.
+	JobSetRoundRobin IntraQueueOrdering = "JobSetRoundRobin"
+	// ShortestJobFirst orders jobs by the runtime predicted via ExpectedRuntimeAnnotation, shortest first.
+	// Jobs without a predicted runtime come last. Jobs with equal predicted runtime retain their Fifo order.
+	ShortestJobFirst IntraQueueOrdering = "ShortestJobFirst"
+)
+
+// MaxNodeUniformityLabelValuesToConsiderForPool returns the maximum number of values of a gang's node uniformity label
+// to make scheduling attempts for in the given pool, or zero if all values are to be considered.
+func (c SchedulingConfig) MaxNodeUniformityLabelValuesToConsiderForPool(pool string) uint {
+	if n, ok := c.MaxNodeUniformityLabelValuesToConsiderByPool[pool]; ok {
+		return n
+	}
+	return c.MaxNodeUniformityLabelValuesToConsider
+}
+
+func (c SchedulingConfig) GangPlacementScoringForPool(pool string) GangPlacementScoring {
+	if scoring, ok := c.GangPlacementScoringByPool[pool]; ok && scoring != "" {
+		return scoring
+	}
+	if c.GangPlacementScoring != "" {
+		return c.GangPlacementScoring
+	}
+	return MeanScheduledAtPriority
+}
+
+// GangPlacementScoring controls how the gang scheduler compares candidate placements of a gang.
+// Each criterion assigns a score to each placement, and the placement with the smallest score is chosen.
+type GangPlacementScoring string
+
+const (
+	// MeanScheduledAtPriority scores placements by the mean priority the jobs of the gang are scheduled at,
+	// such that placements requiring preemption of jobs of higher priority are avoided.
+	MeanScheduledAtPriority GangPlacementScoring = "MeanScheduledAtPriority"
+	// PreemptionCount scores placements by the number of jobs of the gang that only fit by preempting other jobs.
+	PreemptionCount GangPlacementScoring = "PreemptionCount"
+	// Fragmentation scores placements by the resources left unallocated on the nodes the gang is placed on,
+	// such that gangs fill partially allocated nodes rather than leaving fragments of many nodes unallocated.
+	Fragmentation GangPlacementScoring = "Fragmentation"
+	// NodeCost scores placements by the total cost of the nodes the gang is placed on, as given by GangPlacementNodeCostLabel.
+	NodeCost GangPlacementScoring = "NodeCost"
+)
+
+// GangPlacementTieBreak controls how the gang scheduler chooses between candidate placements of a gang with equal score,
+// e.g., onto nodes with different values of the gang's node uniformity label, such that placements are reproducible.
+type GangPlacementTieBreak string
+
+const (
+	// Lexicographic chooses the placement onto nodes with the lexicographically smallest node uniformity label value.
+	Lexicographic GangPlacementTieBreak = "Lexicographic"
+	// FewestNodes chooses the placement onto the fewest nodes, such that gangs are packed tightly.
+	FewestNodes GangPlacementTieBreak = "FewestNodes"
+	// MostHeadroom chooses the placement onto nodes with the most resources left unallocated relative to the resources
+	// requested by the gang, such that similar gangs placed later are more likely to fit alongside it.
+	MostHeadroom GangPlacementTieBreak = "MostHeadroom"
+)
+
 type IndexedResource struct {
 	// Resource name. E.g., "cpu", "memory", or "nvidia.com/gpu".
 	Name string
@@ -277,6 +533,97 @@ type NewSchedulerConfig struct {
 	Enabled bool
 }
 
+// NodeFlavor is a named job shape along with the nodes jobs of that shape are scheduled onto.
+// Since all jobs of a flavor request the same resources, capacity can be planned in units of flavors.
+type NodeFlavor struct {
+	// Resources requested by jobs of this flavor; set as both requests and limits of the first container.
+	Resources v1.ResourceList
+	// Node selector added to jobs of this flavor, e.g., to select the node type or pool providing the flavor.
+	NodeSelector map[string]string
+	// Tolerations added to jobs of this flavor, e.g., for the taints of dedicated GPU nodes.
+	Tolerations []v1.Toleration
+}
+
+// PoolRoutingRule routes submitted jobs meeting all its conditions to a pool.
+type PoolRoutingRule struct {
+	// Pool jobs matching this rule are scheduled in.
+	Pool string
+	// If non-empty, jobs only match if they request a non-zero amount of any of these resources, e.g., "nvidia.com/gpu".
+	Resources []string
+	// If non-empty, jobs only match if their node selector includes all these labels, e.g., {"kubernetes.io/arch": "arm64"}.
+	NodeSelector map[string]string
+}
+
+// QueuePodPolicy is a set of constraints on the pods of jobs submitted to a particular queue.
+type QueuePodPolicy struct {
+	// If set, pods that don't specify a runtime class are assigned this runtime class,
+	// and pods that specify any other runtime class are rejected, e.g., to require that jobs run in gVisor or Kata sandboxes.
+	RuntimeClassName string
+	// If true, each container must run as non-root,
+	// i.e., the container or pod security context must set runAsNonRoot or a non-zero runAsUser.
+	RequireRunAsNonRoot bool
+	// If true, privileged containers and containers allowing privilege escalation are rejected.
+	DisallowPrivileged bool
+	// If true, pods using the host network, PID, or IPC namespace are rejected.
+	DisallowHostNamespaces bool
+}
+
+// QueueJobDefaults is a set of defaults injected into the pods of jobs submitted to a particular queue.
+type QueueJobDefaults struct {
+	// Tolerations added to each pod, unless the pod already has an identical toleration.
+	Tolerations []v1.Toleration
+	// Node selector added to each pod. Keys the pod already selects on are left as submitted, unless OverrideNodeSelector is true.
+	NodeSelector map[string]string
+	// Priority class assigned to pods that don't specify one, or to all pods if OverridePriorityClassName is true.
+	PriorityClassName string
+	// Runtime class assigned to pods that don't specify one, or to all pods if OverrideRuntimeClassName is true.
+	RuntimeClassName string
+	// If true, the corresponding default replaces the value specified by the pod, if any, instead of only filling in missing values.
+	OverrideNodeSelector      bool
+	OverridePriorityClassName bool
+	OverrideRuntimeClassName  bool
+}
+
+// GangPackingConfig configures optimization-based placement of large gangs, as an alternative to placing the jobs of a gang
+// greedily one at a time. The solver minimizes the cost of the nodes the gang is placed on, trading scheduling latency for utilization.
+type GangPackingConfig struct {
+	// If true, gangs of at least MinGangCardinality jobs are placed by the solver.
+	Enabled            bool
+	MinGangCardinality uint
+	// Maximum time spent by the solver on each gang. Gangs the solver finds no placement for within this time are placed greedily.
+	TimeBudget time.Duration
+	// Cost of placing jobs on a node with no jobs bound to it, relative to a cost of 1 for a node with jobs bound to it,
+	// such that the solver prefers keeping empty nodes free, e.g., for other large gangs. Values smaller than 1 are treated as 1.
+	EmptyNodeCost float64
+}
+
+// GangAccumulationConfig configures reserving capacity for large gangs across scheduling rounds,
+// since the resources such gangs require may otherwise never be free all at once.
+// If a gang of at least MinGangCardinality jobs doesn't fit onto the nodes of a pool, enough nodes to fit the gang are reserved for it.
+// Jobs not in the gang aren't scheduled onto reserved nodes, such that capacity accumulates on them as the jobs bound to them finish,
+// until the gang is scheduled or Timeout expires. At most one gang per pool accumulates capacity at a time.
+type GangAccumulationConfig struct {
+	// If true, gangs of at least MinGangCardinality jobs accumulate capacity.
+	Enabled            bool
+	MinGangCardinality uint
+	// Maximum time nodes are reserved for a gang. Gangs whose reservation expires aren't reserved nodes for again while queued.
+	Timeout time.Duration
+}
+
+// PreemptionCompensationConfig configures crediting queues for the work they lose to preemption,
+// i.e., the share of the pool allocated to each preempted run multiplied by how long it had been running.
+// Credit increases the weight of a queue, and hence its fair share, such that it's prioritised when rescheduling
+// and queues of similar share don't repeatedly preempt each other.
+type PreemptionCompensationConfig struct {
+	// Credit decays exponentially with this half-life. Compensation is disabled if zero.
+	HalfLife time.Duration
+	// Fraction by which the weight of a queue increases per unit of credit, where a unit of credit is an entire pool for an hour.
+	// E.g., 0.5 increases the weight of a queue by 50% for each pool-hour lost to preemption.
+	WeightBoostPerPoolHour float64
+	// Maximum fraction by which the weight of a queue increases, e.g., 1 at most doubles it.
+	MaxWeightBoost float64
+}
+
 // TODO: Remove. Move PriorityClasses and DefaultPriorityClass into SchedulingConfig.
 type PreemptionConfig struct {
 	// If using PreemptToFairShare,
@@ -349,6 +696,135 @@ type QueueManagementConfig struct {
 	AutoCreateQueues       bool
 	DefaultPriorityFactor  float64
 	DefaultQueuedJobsLimit int
+	// Overrides DefaultQueuedJobsLimit for particular queues, indexed by queue name.
+	QueuedJobsLimitByQueue map[string]int
+	// What happens to jobs submitted in excess of the queued jobs limit of their queue. Defaults to RejectJobs.
+	QueuedJobsLimitBehavior QueuedJobsLimitBehavior
+}
+
+// QueuedJobsLimitForQueue returns the maximum number of queued jobs of the given queue; zero means unlimited.
+func (c QueueManagementConfig) QueuedJobsLimitForQueue(queue string) int {
+	if limit, ok := c.QueuedJobsLimitByQueue[queue]; ok {
+		return limit
+	}
+	return c.DefaultQueuedJobsLimit
+}
+
+// QueuedJobsLimitBehavior controls what happens to jobs submitted in excess of the queued jobs limit of their queue.
+type QueuedJobsLimitBehavior string
+
+const (
+	// RejectJobs rejects the entire submit request.
+	RejectJobs QueuedJobsLimitBehavior = "Reject"
+	// PauseJobs accepts the jobs in excess of the limit, but marks them as paused.
+	// Paused jobs aren't scheduled for as long as their queue has other queued jobs.
+	PauseJobs QueuedJobsLimitBehavior = "Pause"
+)
+
+// QueueOnboardingConfig configures the self-service queue request API.
+type QueueOnboardingConfig struct {
+	// Approver that new queue requests are routed to; one of "manual", "quota", or "webhook".
+	// Requests not approved or rejected by the approver remain pending until reviewed by an administrator.
+	Approver string
+	// Used by the quota approver; requests within these bounds are approved automatically.
+	AutoApproveMinPriorityFactor float64
+	AutoApproveMaxResourceLimits map[string]float64
+	// Used by the webhook approver.
+	ApprovalWebhookUrl     string
+	ApprovalWebhookTimeout time.Duration
+}
+
+// AutoRightsizeConfig configures the lowering of resource requests at submission for jobs that opt in to it,
+// according to the rightsizing recommendations computed by Lookout.
+type AutoRightsizeConfig struct {
+	Enabled bool
+	// Url of the Lookout rightsizing api, e.g., http://lookoutv2:10000/api/v1/rightsizing.
+	RecommendationsUrl string
+	// How often recommendations are fetched from Lookout.
+	RefreshInterval time.Duration
+	Timeout         time.Duration
+}
+
+// SubmissionRateLimitConfig limits the rate at which jobs may be submitted,
+// such that a single client can't flood Pulsar and starve ingestion for everyone else.
+// Limits are enforced by each server replica independently.
+type SubmissionRateLimitConfig struct {
+	// Limits applied to each user across all queues.
+	PerUser SubmissionRateLimit
+	// Limits applied to each queue across all users.
+	PerQueue SubmissionRateLimit
+}
+
+// SubmissionRateLimit is a pair of token bucket limits, on the number of jobs and on the size of submit requests.
+// A rate of zero disables the corresponding limit.
+// Requests larger than the burst are always rejected; hence, bursts should be at least as large as the largest allowed request.
+type SubmissionRateLimit struct {
+	JobsPerSecond  float64
+	JobBurst       int
+	BytesPerSecond float64
+	ByteBurst      int
+}
+
+// JobSpecArtifactsConfig configures fetching job specs stored as OCI artifacts,
+// which jobs reference by digest via JobSpecRefAnnotation instead of including their spec in the submission.
+// Artifacts must be single-layer, with the layer a JobSubmitRequestItem encoded as json or yaml,
+// and are fetched anonymously over https using the OCI distribution api.
+type JobSpecArtifactsConfig struct {
+	Enabled bool
+	// Registries artifacts may be fetched from, e.g., registry.example.com:5000.
+	// References to artifacts in other registries are rejected.
+	AllowedRegistries []string
+	// Maximum size in bytes of the spec stored in an artifact.
+	MaxSize int64
+	// Maximum number of specs to cache. Since references are digest-pinned, cached specs never go stale.
+	CacheSize int
+	// Timeout of each request to a registry.
+	Timeout time.Duration
+}
+
+// JobWatchConfig configures the job watch API, via which workflow engines, e.g., Airflow sensors,
+// wait for sets of jobs to reach a terminal state by long-polling a subscription rather than polling each job.
+// Subscriptions are held in memory by the server instance that created them.
+type JobWatchConfig struct {
+	Enabled bool
+	// Subscriptions not polled for this long expire; each poll renews the lease.
+	LeaseDuration time.Duration
+	// Maximum time a poll waits for jobs to reach a terminal state before returning.
+	MaxPollTimeout time.Duration
+	// Interval at which waiting polls check the job sets of their subscription for new events.
+	PollInterval time.Duration
+	// Maximum number of jobs per subscription.
+	MaxJobsPerSubscription int
+	// Maximum number of subscriptions held by each server instance.
+	MaxSubscriptions int
+}
+
+// RegionsConfig configures running control planes in multiple regions, e.g., for global installations.
+// Each queue is homed to a region, whose control plane schedules its jobs onto the clusters of that region.
+// Job submissions, cancellations, and reprioritisations for queues homed to another region are forwarded to that region,
+// and events of jobs of such queues are streamed from that region.
+type RegionsConfig struct {
+	// Region of this control plane. If empty, all queues are homed to this control plane.
+	LocalRegion string
+	// Home region of each queue. Queues not listed are homed to LocalRegion.
+	QueueHomeRegions map[string]string
+	// Control planes of other regions, indexed by region.
+	Remotes map[string]RemoteRegionConfig
+}
+
+type RemoteRegionConfig struct {
+	// Endpoints of the control plane of the region, e.g., one per zone.
+	// Requests are sent to the endpoint with the lowest observed latency.
+	// Endpoints should not configure authentication; the credentials of the caller are forwarded instead.
+	Endpoints []client.ApiConnectionDetails
+}
+
+// HomeRegion returns the region the queue is homed to.
+func (c RegionsConfig) HomeRegion(queue string) string {
+	if region, ok := c.QueueHomeRegions[queue]; ok {
+		return region
+	}
+	return c.LocalRegion
 }
 
 type MetricsConfig struct {
@@ -361,6 +837,8 @@ type MetricsConfig struct {
 type SchedulerMetricsConfig struct {
 	ScheduleCycleTimeHistogramSettings  HistogramConfig
 	ReconcileCycleTimeHistogramSettings HistogramConfig
+	// Buckets, in seconds, of the histogram of the time jobs spend in each segment of the path from submission to being leased.
+	JobStartLatencyHistogramSettings HistogramConfig
 }
 
 type HistogramConfig struct {
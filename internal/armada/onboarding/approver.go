@@ -0,0 +1,147 @@
+package onboarding
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+// Decision is the outcome of reviewing a queue request.
+// A Pending decision leaves the request to be approved or rejected manually by an administrator.
+type Decision struct {
+	Status QueueRequestStatus `json:"status"`
+	Reason string             `json:"reason,omitempty"`
+}
+
+// Approver is the hook through which queue requests are routed to an approval workflow.
+// Review is called once for each new request.
+type Approver interface {
+	Name() string
+	Review(ctx *armadacontext.Context, request *QueueRequest) (Decision, error)
+}
+
+// NewApprover returns the approver specified by config.
+func NewApprover(config configuration.QueueOnboardingConfig) (Approver, error) {
+	switch config.Approver {
+	case "", "manual":
+		return ManualApprover{}, nil
+	case "quota":
+		return NewQuotaApprover(config.AutoApproveMinPriorityFactor, config.AutoApproveMaxResourceLimits), nil
+	case "webhook":
+		if config.ApprovalWebhookUrl == "" {
+			return nil, errors.New("the webhook queue request approver requires an approval webhook url")
+		}
+		return NewWebhookApprover(config.ApprovalWebhookUrl, config.ApprovalWebhookTimeout), nil
+	default:
+		return nil, errors.Errorf("unknown queue request approver %q", config.Approver)
+	}
+}
+
+// ManualApprover leaves all requests pending, such that they have to be approved by an administrator.
+type ManualApprover struct{}
+
+func (ManualApprover) Name() string {
+	return "manual"
+}
+
+func (ManualApprover) Review(_ *armadacontext.Context, _ *QueueRequest) (Decision, error) {
+	return Decision{Status: Pending}, nil
+}
+
+// QuotaApprover automatically approves requests for quotas within configured bounds,
+// and leaves all other requests pending.
+type QuotaApprover struct {
+	// Requests for a priority factor lower than this are not approved automatically.
+	// Lower priority factors mean a larger share of resources.
+	minPriorityFactor float64
+	// Requests for resource limits larger than these are not approved automatically.
+	// Resources not in this map are never approved automatically.
+	maxResourceLimits map[string]float64
+}
+
+func NewQuotaApprover(minPriorityFactor float64, maxResourceLimits map[string]float64) *QuotaApprover {
+	return &QuotaApprover{
+		minPriorityFactor: minPriorityFactor,
+		maxResourceLimits: maxResourceLimits,
+	}
+}
+
+func (a *QuotaApprover) Name() string {
+	return "quota"
+}
+
+func (a *QuotaApprover) Review(_ *armadacontext.Context, request *QueueRequest) (Decision, error) {
+	if request.PriorityFactor != 0 && request.PriorityFactor < a.minPriorityFactor {
+		return Decision{
+			Status: Pending,
+			Reason: fmt.Sprintf("priority factor %f is below the auto-approval minimum of %f", request.PriorityFactor, a.minPriorityFactor),
+		}, nil
+	}
+	for resourceType, limit := range request.ResourceLimits {
+		maxLimit, ok := a.maxResourceLimits[resourceType]
+		if !ok || limit > maxLimit {
+			return Decision{
+				Status: Pending,
+				Reason: fmt.Sprintf("limit %f for resource %s exceeds the auto-approval limit", limit, resourceType),
+			}, nil
+		}
+	}
+	return Decision{Status: Approved, Reason: "within auto-approval quotas"}, nil
+}
+
+// WebhookApprover posts requests as json to an external approval service,
+// which is expected to respond with a json-encoded Decision.
+// External services that approve requests asynchronously should respond with a Pending decision
+// and approve or reject the request later via the queue request API.
+type WebhookApprover struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookApprover(url string, timeout time.Duration) *WebhookApprover {
+	return &WebhookApprover{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (a *WebhookApprover) Name() string {
+	return "webhook"
+}
+
+func (a *WebhookApprover) Review(ctx *armadacontext.Context, request *QueueRequest) (Decision, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return Decision{}, errors.WithStack(err)
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, errors.WithStack(err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(httpRequest)
+	if err != nil {
+		return Decision{}, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Decision{}, errors.Errorf("approval webhook %s returned status %s", a.url, resp.Status)
+	}
+	var decision Decision
+	if err := json.NewDecoder(resp.Body).Decode(&decision); err != nil {
+		return Decision{}, errors.WithStack(err)
+	}
+	switch decision.Status {
+	case Pending, Approved, Rejected:
+	default:
+		return Decision{}, errors.Errorf("approval webhook %s returned unknown status %q", a.url, decision.Status)
+	}
+	return decision, nil
+}
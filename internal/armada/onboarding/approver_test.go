@@ -0,0 +1,90 @@
+package onboarding
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+func TestQuotaApprover(t *testing.T) {
+	approver := NewQuotaApprover(100, map[string]float64{"cpu": 0.1, "memory": 0.2})
+	tests := map[string]struct {
+		request        *QueueRequest
+		expectedStatus QueueRequestStatus
+	}{
+		"within quotas": {
+			request:        &QueueRequest{PriorityFactor: 100, ResourceLimits: map[string]float64{"cpu": 0.1, "memory": 0.2}},
+			expectedStatus: Approved,
+		},
+		"default priority factor": {
+			request:        &QueueRequest{},
+			expectedStatus: Approved,
+		},
+		"priority factor too low": {
+			request:        &QueueRequest{PriorityFactor: 10},
+			expectedStatus: Pending,
+		},
+		"resource limit too high": {
+			request:        &QueueRequest{ResourceLimits: map[string]float64{"cpu": 0.5}},
+			expectedStatus: Pending,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			decision, err := approver.Review(armadacontext.Background(), tc.request)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, decision.Status)
+		})
+	}
+}
+
+func TestWebhookApprover(t *testing.T) {
+	var received QueueRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`{"status": "rejected", "reason": "no budget"}`))
+	}))
+	defer server.Close()
+
+	decision, err := NewWebhookApprover(server.URL, time.Second).Review(
+		armadacontext.Background(),
+		&QueueRequest{Id: "id", Type: QueueCreation, Queue: "queue-a"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, Decision{Status: Rejected, Reason: "no budget"}, decision)
+	assert.Equal(t, "queue-a", received.Queue)
+}
+
+func TestWebhookApprover_InvalidResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status": "maybe"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewWebhookApprover(server.URL, time.Second).Review(armadacontext.Background(), &QueueRequest{})
+	assert.Error(t, err)
+}
+
+func TestNewApprover(t *testing.T) {
+	approver, err := NewApprover(configuration.QueueOnboardingConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, "manual", approver.Name())
+
+	approver, err = NewApprover(configuration.QueueOnboardingConfig{Approver: "quota"})
+	require.NoError(t, err)
+	assert.Equal(t, "quota", approver.Name())
+
+	_, err = NewApprover(configuration.QueueOnboardingConfig{Approver: "webhook"})
+	assert.Error(t, err)
+
+	_, err = NewApprover(configuration.QueueOnboardingConfig{Approver: "foo"})
+	assert.Error(t, err)
+}
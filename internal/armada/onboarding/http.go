@@ -0,0 +1,113 @@
+package onboarding
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/auth/authorization"
+	"github.com/armadaproject/armada/internal/common/logging"
+)
+
+const (
+	QueueRequestsPath       = "/api/v1/queueRequests"
+	ApproveQueueRequestPath = "/api/v1/queueRequests/approve"
+	RejectQueueRequestPath  = "/api/v1/queueRequests/reject"
+)
+
+// decisionRequest is the body of approve and reject requests.
+type decisionRequest struct {
+	Id     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// HttpHandler exposes the onboarding Service as a json http API:
+//
+//	GET  /api/v1/queueRequests?queue=<queue>&status=<status>  lists requests
+//	POST /api/v1/queueRequests                                 submits a QueueRequest
+//	POST /api/v1/queueRequests/approve                         approves a request, e.g., {"id": "...", "reason": "..."}
+//	POST /api/v1/queueRequests/reject                          rejects a request
+//
+// Requests are authenticated using the same authentication services as the gRPC API.
+type HttpHandler struct {
+	service      *Service
+	authServices []authorization.AuthService
+}
+
+func NewHttpHandler(service *Service, authServices []authorization.AuthService) *HttpHandler {
+	return &HttpHandler{
+		service:      service,
+		authServices: authServices,
+	}
+}
+
+// RegisterRoutes registers the handler with mux.
+func (h *HttpHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle(QueueRequestsPath, h)
+	mux.Handle(ApproveQueueRequestPath, h)
+	mux.Handle(RejectQueueRequestPath, h)
+}
+
+func (h *HttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := authorization.AuthenticateHttpRequest(r, h.authServices)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	ctx := armadacontext.New(authCtx, log.NewEntry(log.StandardLogger()))
+
+	var rv interface{}
+	switch {
+	case r.URL.Path == QueueRequestsPath && r.Method == http.MethodGet:
+		rv, err = h.service.List(ctx, r.URL.Query().Get("queue"), QueueRequestStatus(r.URL.Query().Get("status")))
+	case r.URL.Path == QueueRequestsPath && r.Method == http.MethodPost:
+		request := &QueueRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rv, err = h.service.Submit(ctx, request)
+	case r.URL.Path == ApproveQueueRequestPath && r.Method == http.MethodPost:
+		decision := &decisionRequest{}
+		if err := json.NewDecoder(r.Body).Decode(decision); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rv, err = h.service.Approve(ctx, decision.Id, decision.Reason)
+	case r.URL.Path == RejectQueueRequestPath && r.Method == http.MethodPost:
+		decision := &decisionRequest{}
+		if err := json.NewDecoder(r.Body).Decode(decision); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rv, err = h.service.Reject(ctx, decision.Id, decision.Reason)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		statusCode := httpStatusFromError(err)
+		if statusCode == http.StatusInternalServerError {
+			logging.WithStacktrace(ctx, err).Errorf("failed to serve %s %s", r.Method, r.URL.Path)
+		}
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rv); err != nil {
+		logging.WithStacktrace(ctx, err).Error("failed to write queue request response")
+	}
+}
+
+func httpStatusFromError(err error) int {
+	var e *armadaerrors.ErrUnauthorized
+	if errors.As(err, &e) {
+		return http.StatusForbidden
+	}
+	return runtime.HTTPStatusFromCode(armadaerrors.CodeFromError(err))
+}
@@ -0,0 +1,68 @@
+package onboarding
+
+import (
+	"encoding/json"
+
+	"github.com/go-redis/redis"
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+)
+
+const queueRequestHashKey = "QueueRequest"
+
+type QueueRequestRepository interface {
+	GetQueueRequests() ([]*QueueRequest, error)
+	GetQueueRequest(id string) (*QueueRequest, error)
+	StoreQueueRequest(request *QueueRequest) error
+}
+
+// RedisQueueRequestRepository stores queue requests as json in a Redis hash keyed by request id.
+type RedisQueueRequestRepository struct {
+	db redis.UniversalClient
+}
+
+func NewRedisQueueRequestRepository(db redis.UniversalClient) *RedisQueueRequestRepository {
+	return &RedisQueueRequestRepository{db: db}
+}
+
+func (r *RedisQueueRequestRepository) GetQueueRequests() ([]*QueueRequest, error) {
+	result, err := r.db.HGetAll(queueRequestHashKey).Result()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	requests := make([]*QueueRequest, 0, len(result))
+	for _, v := range result {
+		request := &QueueRequest{}
+		if err := json.Unmarshal([]byte(v), request); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+func (r *RedisQueueRequestRepository) GetQueueRequest(id string) (*QueueRequest, error) {
+	result, err := r.db.HGet(queueRequestHashKey, id).Result()
+	if err == redis.Nil {
+		return nil, errors.WithStack(&armadaerrors.ErrNotFound{
+			Type:  "QueueRequest",
+			Value: id,
+		})
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	request := &QueueRequest{}
+	if err := json.Unmarshal([]byte(result), request); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return request, nil
+}
+
+func (r *RedisQueueRequestRepository) StoreQueueRequest(request *QueueRequest) error {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(r.db.HSet(queueRequestHashKey, request.Id, data).Err())
+}
@@ -0,0 +1,48 @@
+package onboarding
+
+import (
+	"time"
+)
+
+type QueueRequestType string
+
+const (
+	// Request for a new queue.
+	QueueCreation QueueRequestType = "queueCreation"
+	// Request to change the priority factor or resource limits of an existing queue.
+	QuotaChange QueueRequestType = "quotaChange"
+)
+
+type QueueRequestStatus string
+
+const (
+	Pending  QueueRequestStatus = "pending"
+	Approved QueueRequestStatus = "approved"
+	Rejected QueueRequestStatus = "rejected"
+)
+
+// QueueRequest is a request, made by a queue user, to create a queue or to change the quotas of an existing queue.
+// Requests are reviewed by an Approver; approved requests are provisioned automatically.
+type QueueRequest struct {
+	Id        string           `json:"id"`
+	Type      QueueRequestType `json:"type"`
+	Queue     string           `json:"queue"`
+	Requester string           `json:"requester"`
+	// Free-form justification provided by the requester.
+	Reason string `json:"reason,omitempty"`
+	// Requested quotas. For quota changes, a zero priority factor leaves the priority factor unchanged.
+	PriorityFactor float64            `json:"priorityFactor,omitempty"`
+	ResourceLimits map[string]float64 `json:"resourceLimits,omitempty"`
+	// Owners of the queue; only used for queue creation requests.
+	UserOwners  []string `json:"userOwners,omitempty"`
+	GroupOwners []string `json:"groupOwners,omitempty"`
+	// Quotas of the queue before a quota change was provisioned.
+	PreviousPriorityFactor float64            `json:"previousPriorityFactor,omitempty"`
+	PreviousResourceLimits map[string]float64 `json:"previousResourceLimits,omitempty"`
+	Status                 QueueRequestStatus `json:"status"`
+	Created                time.Time          `json:"created"`
+	Decided                *time.Time         `json:"decided,omitempty"`
+	// Name of the principal or approver that approved or rejected the request.
+	DecidedBy      string `json:"decidedBy,omitempty"`
+	DecisionReason string `json:"decisionReason,omitempty"`
+}
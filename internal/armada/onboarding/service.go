@@ -0,0 +1,309 @@
+package onboarding
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+
+	"github.com/armadaproject/armada/internal/armada/permissions"
+	"github.com/armadaproject/armada/internal/armada/repository"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/auth/authorization"
+	"github.com/armadaproject/armada/internal/common/logging"
+	"github.com/armadaproject/armada/internal/common/util"
+	"github.com/armadaproject/armada/pkg/api"
+	"github.com/armadaproject/armada/pkg/client/queue"
+)
+
+// Service implements self-service queue onboarding.
+// Users request new queues or quota changes, requests are routed to an Approver,
+// and approved requests are provisioned without any further administrator involvement.
+// Administrators (i.e., principals with the create_queue permission) may approve or reject pending requests.
+type Service struct {
+	requestRepository     QueueRequestRepository
+	queueRepository       repository.QueueRepository
+	approver              Approver
+	permissions           authorization.PermissionChecker
+	defaultPriorityFactor float64
+	clock                 util.Clock
+	// Serialises decisions to avoid provisioning the same request twice.
+	mu sync.Mutex
+}
+
+func NewService(
+	requestRepository QueueRequestRepository,
+	queueRepository repository.QueueRepository,
+	approver Approver,
+	permissions authorization.PermissionChecker,
+	defaultPriorityFactor float64,
+) *Service {
+	return &Service{
+		requestRepository:     requestRepository,
+		queueRepository:       queueRepository,
+		approver:              approver,
+		permissions:           permissions,
+		defaultPriorityFactor: defaultPriorityFactor,
+		clock:                 &util.UTCClock{},
+	}
+}
+
+// Submit validates a new request and passes it to the approver.
+// If the approver approves the request, the queue is provisioned immediately.
+func (s *Service) Submit(ctx *armadacontext.Context, request *QueueRequest) (*QueueRequest, error) {
+	principal := authorization.GetPrincipal(ctx)
+	request.Id = util.NewULID()
+	request.Requester = principal.GetName()
+	request.Created = s.clock.Now()
+	request.Status = Pending
+	request.Decided = nil
+	request.DecidedBy = ""
+	request.DecisionReason = ""
+	request.PreviousPriorityFactor = 0
+	request.PreviousResourceLimits = nil
+	if err := s.validate(ctx, request); err != nil {
+		return nil, err
+	}
+	if err := s.requestRepository.StoreQueueRequest(request); err != nil {
+		return nil, err
+	}
+
+	decision, err := s.approver.Review(ctx, request)
+	if err != nil {
+		// The request remains pending such that it can be approved manually.
+		logging.WithStacktrace(ctx, err).Warnf("%s approver failed to review queue request %s", s.approver.Name(), request.Id)
+		return request, nil
+	}
+	if decision.Status == Pending {
+		return request, nil
+	}
+	return s.decide(ctx, request.Id, decision, s.approver.Name())
+}
+
+// Approve approves and provisions a pending request.
+func (s *Service) Approve(ctx *armadacontext.Context, id string, reason string) (*QueueRequest, error) {
+	if err := s.checkIsAdmin(ctx, "approve queue request"); err != nil {
+		return nil, err
+	}
+	return s.decide(ctx, id, Decision{Status: Approved, Reason: reason}, authorization.GetPrincipal(ctx).GetName())
+}
+
+// Reject rejects a pending request.
+func (s *Service) Reject(ctx *armadacontext.Context, id string, reason string) (*QueueRequest, error) {
+	if err := s.checkIsAdmin(ctx, "reject queue request"); err != nil {
+		return nil, err
+	}
+	return s.decide(ctx, id, Decision{Status: Rejected, Reason: reason}, authorization.GetPrincipal(ctx).GetName())
+}
+
+// List returns requests, optionally filtered by queue and status, ordered by creation time.
+// Administrators see all requests; other users only see the requests they made.
+func (s *Service) List(ctx *armadacontext.Context, queueName string, status QueueRequestStatus) ([]*QueueRequest, error) {
+	requests, err := s.requestRepository.GetQueueRequests()
+	if err != nil {
+		return nil, err
+	}
+	principal := authorization.GetPrincipal(ctx)
+	isAdmin := s.permissions.UserHasPermission(ctx, permissions.CreateQueue)
+	rv := make([]*QueueRequest, 0, len(requests))
+	for _, request := range requests {
+		if !isAdmin && request.Requester != principal.GetName() {
+			continue
+		}
+		if queueName != "" && request.Queue != queueName {
+			continue
+		}
+		if status != "" && request.Status != status {
+			continue
+		}
+		rv = append(rv, request)
+	}
+	slices.SortFunc(rv, func(a, b *QueueRequest) bool {
+		if a.Created.Equal(b.Created) {
+			return a.Id < b.Id
+		}
+		return a.Created.Before(b.Created)
+	})
+	return rv, nil
+}
+
+func (s *Service) decide(ctx *armadacontext.Context, id string, decision Decision, decidedBy string) (*QueueRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	request, err := s.requestRepository.GetQueueRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != Pending {
+		return nil, errors.WithStack(&armadaerrors.ErrInvalidArgument{
+			Name:    "id",
+			Value:   id,
+			Message: fmt.Sprintf("queue request has already been %s", request.Status),
+		})
+	}
+	if decision.Status == Approved {
+		if err := s.provision(request); err != nil {
+			return nil, err
+		}
+	}
+	now := s.clock.Now()
+	request.Status = decision.Status
+	request.Decided = &now
+	request.DecidedBy = decidedBy
+	request.DecisionReason = decision.Reason
+	if err := s.requestRepository.StoreQueueRequest(request); err != nil {
+		return nil, err
+	}
+	ctx.Infof("queue request %s for queue %s %s by %s", request.Id, request.Queue, request.Status, decidedBy)
+	return request, nil
+}
+
+func (s *Service) provision(request *QueueRequest) error {
+	switch request.Type {
+	case QueueCreation:
+		q, err := s.queueFromRequest(request)
+		if err != nil {
+			return err
+		}
+		return s.queueRepository.CreateQueue(q)
+	case QuotaChange:
+		q, err := s.queueRepository.GetQueue(request.Queue)
+		if err != nil {
+			return err
+		}
+		request.PreviousPriorityFactor = float64(q.PriorityFactor)
+		request.PreviousResourceLimits = make(map[string]float64, len(q.ResourceLimits))
+		for resourceName, limit := range q.ResourceLimits {
+			request.PreviousResourceLimits[string(resourceName)] = float64(limit)
+		}
+		if request.PriorityFactor != 0 {
+			q.PriorityFactor = queue.PriorityFactor(request.PriorityFactor)
+		}
+		if request.ResourceLimits != nil {
+			resourceLimits, err := queue.NewResourceLimits(request.ResourceLimits)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			q.ResourceLimits = resourceLimits
+		}
+		return s.queueRepository.UpdateQueue(q)
+	default:
+		return errors.Errorf("unknown queue request type %q", request.Type)
+	}
+}
+
+func (s *Service) validate(ctx *armadacontext.Context, request *QueueRequest) error {
+	if request.Queue == "" {
+		return errors.WithStack(&armadaerrors.ErrInvalidArgument{
+			Name:    "queue",
+			Value:   request.Queue,
+			Message: "queue name must not be empty",
+		})
+	}
+	switch request.Type {
+	case QueueCreation:
+		if _, err := s.queueFromRequest(request); err != nil {
+			return err
+		}
+		_, err := s.queueRepository.GetQueue(request.Queue)
+		var e *repository.ErrQueueNotFound
+		if err == nil {
+			return errors.WithStack(&armadaerrors.ErrAlreadyExists{Type: "queue", Value: request.Queue})
+		} else if !errors.As(err, &e) {
+			return err
+		}
+		return nil
+	case QuotaChange:
+		if request.PriorityFactor != 0 {
+			if _, err := queue.NewPriorityFactor(request.PriorityFactor); err != nil {
+				return errors.WithStack(&armadaerrors.ErrInvalidArgument{
+					Name:    "priorityFactor",
+					Value:   request.PriorityFactor,
+					Message: err.Error(),
+				})
+			}
+		}
+		if _, err := queue.NewResourceLimits(request.ResourceLimits); err != nil {
+			return errors.WithStack(&armadaerrors.ErrInvalidArgument{
+				Name:    "resourceLimits",
+				Value:   request.ResourceLimits,
+				Message: err.Error(),
+			})
+		}
+		q, err := s.queueRepository.GetQueue(request.Queue)
+		var e *repository.ErrQueueNotFound
+		if errors.As(err, &e) {
+			return errors.WithStack(&armadaerrors.ErrNotFound{Type: "queue", Value: request.Queue})
+		} else if err != nil {
+			return err
+		}
+		if !s.permissions.UserHasPermission(ctx, permissions.CreateQueue) && !isQueueMember(authorization.GetPrincipal(ctx), q) {
+			return errors.WithStack(&armadaerrors.ErrUnauthorized{
+				Principal: authorization.GetPrincipal(ctx).GetName(),
+				Action:    fmt.Sprintf("request quota change for queue %s", request.Queue),
+				Message:   "only users with permissions on the queue may request quota changes",
+			})
+		}
+		return nil
+	default:
+		return errors.WithStack(&armadaerrors.ErrInvalidArgument{
+			Name:    "type",
+			Value:   request.Type,
+			Message: fmt.Sprintf("must be one of %s or %s", QueueCreation, QuotaChange),
+		})
+	}
+}
+
+func (s *Service) queueFromRequest(request *QueueRequest) (queue.Queue, error) {
+	priorityFactor := request.PriorityFactor
+	if priorityFactor == 0 {
+		priorityFactor = s.defaultPriorityFactor
+	}
+	userOwners := request.UserOwners
+	if len(userOwners) == 0 {
+		userOwners = []string{request.Requester}
+	}
+	q, err := queue.NewQueue(&api.Queue{
+		Name:           request.Queue,
+		PriorityFactor: priorityFactor,
+		ResourceLimits: request.ResourceLimits,
+		UserOwners:     userOwners,
+		GroupOwners:    request.GroupOwners,
+	})
+	if err != nil {
+		return queue.Queue{}, errors.WithStack(&armadaerrors.ErrInvalidArgument{
+			Name:    "queue",
+			Value:   request.Queue,
+			Message: err.Error(),
+		})
+	}
+	return q, nil
+}
+
+func (s *Service) checkIsAdmin(ctx *armadacontext.Context, action string) error {
+	if !s.permissions.UserHasPermission(ctx, permissions.CreateQueue) {
+		return errors.WithStack(&armadaerrors.ErrUnauthorized{
+			Principal:  authorization.GetPrincipal(ctx).GetName(),
+			Permission: permissions.CreateQueue,
+			Action:     action,
+		})
+	}
+	return nil
+}
+
+// isQueueMember returns true if the principal, or any of its groups, has been granted any permissions on q.
+func isQueueMember(principal authorization.Principal, q queue.Queue) bool {
+	for _, permission := range q.Permissions {
+		for _, subject := range permission.Subjects {
+			if subject.Kind == queue.PermissionSubjectKindUser && subject.Name == principal.GetName() {
+				return true
+			}
+			if subject.Kind == queue.PermissionSubjectKindGroup && principal.IsInGroup(subject.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,289 @@
+package onboarding
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/armada/permissions"
+	"github.com/armadaproject/armada/internal/armada/repository"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/auth/authorization"
+	"github.com/armadaproject/armada/internal/common/auth/permission"
+	"github.com/armadaproject/armada/pkg/client/queue"
+)
+
+func TestService_QueueCreation(t *testing.T) {
+	tests := map[string]struct {
+		approver         Approver
+		expectedStatus   QueueRequestStatus
+		expectedQueueIds []string
+	}{
+		"manual approval": {
+			approver:       ManualApprover{},
+			expectedStatus: Pending,
+		},
+		"auto-approved": {
+			approver:         NewQuotaApprover(10, map[string]float64{"cpu": 0.5}),
+			expectedStatus:   Approved,
+			expectedQueueIds: []string{"queue-a"},
+		},
+		"exceeds auto-approval quotas": {
+			approver:       NewQuotaApprover(10, map[string]float64{"cpu": 0.1}),
+			expectedStatus: Pending,
+		},
+		"approver error": {
+			approver:       errorApprover{},
+			expectedStatus: Pending,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, queueRepository := newTestService(tc.approver)
+			request, err := service.Submit(userContext(), &QueueRequest{
+				Type:           QueueCreation,
+				Queue:          "queue-a",
+				PriorityFactor: 10,
+				ResourceLimits: map[string]float64{"cpu": 0.2},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, request.Status)
+			assert.Equal(t, "user", request.Requester)
+			assert.ElementsMatch(t, tc.expectedQueueIds, queueRepository.queueNames())
+		})
+	}
+}
+
+func TestService_ApproveAndReject(t *testing.T) {
+	service, queueRepository := newTestService(ManualApprover{})
+	request, err := service.Submit(userContext(), &QueueRequest{Type: QueueCreation, Queue: "queue-a"})
+	require.NoError(t, err)
+
+	// Only administrators may approve requests.
+	_, err = service.Approve(userContext(), request.Id, "")
+	var unauthorizedErr *armadaerrors.ErrUnauthorized
+	assert.ErrorAs(t, err, &unauthorizedErr)
+
+	approved, err := service.Approve(adminContext(), request.Id, "looks good")
+	require.NoError(t, err)
+	assert.Equal(t, Approved, approved.Status)
+	assert.Equal(t, "admin", approved.DecidedBy)
+	assert.Equal(t, "looks good", approved.DecisionReason)
+	assert.NotNil(t, approved.Decided)
+	q, err := queueRepository.GetQueue("queue-a")
+	require.NoError(t, err)
+	assert.Equal(t, queue.PriorityFactor(1000), q.PriorityFactor)
+	assert.True(t, isQueueMember(authorization.NewStaticPrincipal("user", nil), q))
+
+	// Decided requests can't be decided again.
+	_, err = service.Reject(adminContext(), request.Id, "")
+	var invalidArgumentErr *armadaerrors.ErrInvalidArgument
+	assert.ErrorAs(t, err, &invalidArgumentErr)
+
+	request, err = service.Submit(userContext(), &QueueRequest{Type: QueueCreation, Queue: "queue-b"})
+	require.NoError(t, err)
+	rejected, err := service.Reject(adminContext(), request.Id, "no")
+	require.NoError(t, err)
+	assert.Equal(t, Rejected, rejected.Status)
+	assert.ElementsMatch(t, []string{"queue-a"}, queueRepository.queueNames())
+}
+
+func TestService_QuotaChange(t *testing.T) {
+	service, queueRepository := newTestService(ManualApprover{})
+	require.NoError(t, queueRepository.CreateQueue(queue.Queue{
+		Name:           "queue-a",
+		PriorityFactor: 100,
+		ResourceLimits: queue.ResourceLimits{queue.ResourceNameCPU: 0.1},
+		Permissions:    []queue.Permissions{queue.NewPermissionsFromOwners([]string{"user"}, nil)},
+	}))
+	require.NoError(t, queueRepository.CreateQueue(queue.Queue{Name: "queue-b", PriorityFactor: 100}))
+
+	// Users may only request quota changes for queues they're members of.
+	_, err := service.Submit(userContext(), &QueueRequest{Type: QuotaChange, Queue: "queue-b", PriorityFactor: 10})
+	var unauthorizedErr *armadaerrors.ErrUnauthorized
+	assert.ErrorAs(t, err, &unauthorizedErr)
+
+	_, err = service.Submit(userContext(), &QueueRequest{Type: QuotaChange, Queue: "queue-c", PriorityFactor: 10})
+	var notFoundErr *armadaerrors.ErrNotFound
+	assert.ErrorAs(t, err, &notFoundErr)
+
+	request, err := service.Submit(userContext(), &QueueRequest{
+		Type:           QuotaChange,
+		Queue:          "queue-a",
+		ResourceLimits: map[string]float64{"cpu": 0.5},
+	})
+	require.NoError(t, err)
+	approved, err := service.Approve(adminContext(), request.Id, "")
+	require.NoError(t, err)
+	assert.Equal(t, float64(100), approved.PreviousPriorityFactor)
+	assert.Equal(t, map[string]float64{"cpu": 0.1}, approved.PreviousResourceLimits)
+
+	q, err := queueRepository.GetQueue("queue-a")
+	require.NoError(t, err)
+	assert.Equal(t, queue.PriorityFactor(100), q.PriorityFactor)
+	assert.Equal(t, queue.ResourceLimits{queue.ResourceNameCPU: 0.5}, q.ResourceLimits)
+
+	requests, err := service.List(userContext(), "queue-a", Approved)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, request.Id, requests[0].Id)
+}
+
+func TestService_Validation(t *testing.T) {
+	tests := map[string]*QueueRequest{
+		"missing queue name":      {Type: QueueCreation},
+		"unknown type":            {Type: "foo", Queue: "queue-b"},
+		"invalid priority factor": {Type: QueueCreation, Queue: "queue-b", PriorityFactor: 0.5},
+		"invalid resource limit":  {Type: QueueCreation, Queue: "queue-b", ResourceLimits: map[string]float64{"cpu": 2}},
+		"queue already exists":    {Type: QueueCreation, Queue: "queue-a"},
+	}
+	for name, request := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, queueRepository := newTestService(ManualApprover{})
+			require.NoError(t, queueRepository.CreateQueue(queue.Queue{Name: "queue-a", PriorityFactor: 1}))
+			_, err := service.Submit(userContext(), request)
+			assert.Error(t, err)
+			assert.Empty(t, service.requestRepository.(*inMemoryQueueRequestRepository).requests)
+		})
+	}
+}
+
+func TestService_List(t *testing.T) {
+	service, _ := newTestService(ManualApprover{})
+	_, err := service.Submit(userContext(), &QueueRequest{Type: QueueCreation, Queue: "queue-a"})
+	require.NoError(t, err)
+	_, err = service.Submit(adminContext(), &QueueRequest{Type: QueueCreation, Queue: "queue-b"})
+	require.NoError(t, err)
+
+	requests, err := service.List(userContext(), "", "")
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "queue-a", requests[0].Queue)
+
+	requests, err = service.List(adminContext(), "", Pending)
+	require.NoError(t, err)
+	assert.Len(t, requests, 2)
+}
+
+func newTestService(approver Approver) (*Service, *inMemoryQueueRepository) {
+	queueRepository := &inMemoryQueueRepository{queues: make(map[string]queue.Queue)}
+	permissionChecker := authorization.NewPrincipalPermissionChecker(
+		map[permission.Permission][]string{permissions.CreateQueue: {"admins"}},
+		nil,
+		nil,
+	)
+	service := NewService(
+		&inMemoryQueueRequestRepository{requests: make(map[string]*QueueRequest)},
+		queueRepository,
+		approver,
+		permissionChecker,
+		1000,
+	)
+	return service, queueRepository
+}
+
+func userContext() *armadacontext.Context {
+	return contextWithPrincipal(authorization.NewStaticPrincipal("user", nil))
+}
+
+func adminContext() *armadacontext.Context {
+	return contextWithPrincipal(authorization.NewStaticPrincipal("admin", []string{"admins"}))
+}
+
+func contextWithPrincipal(principal authorization.Principal) *armadacontext.Context {
+	return armadacontext.New(
+		authorization.WithPrincipal(armadacontext.Background(), principal),
+		log.NewEntry(log.StandardLogger()),
+	)
+}
+
+type errorApprover struct{}
+
+func (errorApprover) Name() string {
+	return "error"
+}
+
+func (errorApprover) Review(_ *armadacontext.Context, _ *QueueRequest) (Decision, error) {
+	return Decision{}, errors.New("approval service unavailable")
+}
+
+type inMemoryQueueRequestRepository struct {
+	requests map[string]*QueueRequest
+}
+
+func (r *inMemoryQueueRequestRepository) GetQueueRequests() ([]*QueueRequest, error) {
+	rv := make([]*QueueRequest, 0, len(r.requests))
+	for _, request := range r.requests {
+		request := *request
+		rv = append(rv, &request)
+	}
+	return rv, nil
+}
+
+func (r *inMemoryQueueRequestRepository) GetQueueRequest(id string) (*QueueRequest, error) {
+	request, ok := r.requests[id]
+	if !ok {
+		return nil, &armadaerrors.ErrNotFound{Type: "QueueRequest", Value: id}
+	}
+	rv := *request
+	return &rv, nil
+}
+
+func (r *inMemoryQueueRequestRepository) StoreQueueRequest(request *QueueRequest) error {
+	stored := *request
+	r.requests[request.Id] = &stored
+	return nil
+}
+
+type inMemoryQueueRepository struct {
+	queues map[string]queue.Queue
+}
+
+func (r *inMemoryQueueRepository) queueNames() []string {
+	var rv []string
+	for name := range r.queues {
+		rv = append(rv, name)
+	}
+	return rv
+}
+
+func (r *inMemoryQueueRepository) GetAllQueues() ([]queue.Queue, error) {
+	rv := make([]queue.Queue, 0, len(r.queues))
+	for _, q := range r.queues {
+		rv = append(rv, q)
+	}
+	return rv, nil
+}
+
+func (r *inMemoryQueueRepository) GetQueue(name string) (queue.Queue, error) {
+	q, ok := r.queues[name]
+	if !ok {
+		return queue.Queue{}, &repository.ErrQueueNotFound{QueueName: name}
+	}
+	return q, nil
+}
+
+func (r *inMemoryQueueRepository) CreateQueue(q queue.Queue) error {
+	if _, ok := r.queues[q.Name]; ok {
+		return &repository.ErrQueueAlreadyExists{QueueName: q.Name}
+	}
+	r.queues[q.Name] = q
+	return nil
+}
+
+func (r *inMemoryQueueRepository) UpdateQueue(q queue.Queue) error {
+	if _, ok := r.queues[q.Name]; !ok {
+		return &repository.ErrQueueNotFound{QueueName: q.Name}
+	}
+	r.queues[q.Name] = q
+	return nil
+}
+
+func (r *inMemoryQueueRepository) DeleteQueue(name string) error {
+	delete(r.queues, name)
+	return nil
+}
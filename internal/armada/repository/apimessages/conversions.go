@@ -6,6 +6,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/armadaproject/armada/internal/common/eventutil"
+	"github.com/armadaproject/armada/internal/executor/domain"
 	"github.com/armadaproject/armada/pkg/api"
 	"github.com/armadaproject/armada/pkg/armadaevents"
 )
@@ -260,6 +261,7 @@ func FromInternalJobSucceeded(queueName string, jobSetName string, time time.Tim
 		apiEvent.KubernetesId = ri.GetObjectMeta().GetKubernetesId()
 		apiEvent.NodeName = ri.GetPodInfo().GetNodeName()
 		apiEvent.PodNumber = ri.GetPodInfo().GetPodNumber()
+		apiEvent.OutputUrl = ri.GetObjectMeta().GetAnnotations()[domain.OutputUrl]
 	}
 
 	return []*api.EventMessage{
@@ -512,13 +514,19 @@ func FromInternalJobRunPreempted(queueName string, jobSetName string, time time.
 	}
 
 	apiEvent := &api.JobPreemptedEvent{
-		JobId:           jobId,
-		JobSetId:        jobSetName,
-		Queue:           queueName,
-		Created:         time,
-		RunId:           runId,
-		PreemptiveJobId: preemptiveJobId,
-		PreemptiveRunId: preemptiveRunId,
+		JobId:                   jobId,
+		JobSetId:                jobSetName,
+		Queue:                   queueName,
+		Created:                 time,
+		RunId:                   runId,
+		PreemptiveJobId:         preemptiveJobId,
+		PreemptiveRunId:         preemptiveRunId,
+		Cause:                   e.Cause,
+		PreemptiveQueue:         e.PreemptiveQueue,
+		PreemptivePriorityClass: e.PreemptivePriorityClass,
+		PreemptiveGangId:        e.PreemptiveGangId,
+		ExpectedRequeuePosition: e.ExpectedRequeuePosition,
+		QueueCompensated:        e.QueueCompensated,
 	}
 
 	return []*api.EventMessage{
@@ -11,6 +11,7 @@ import (
 	v11 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
+	"github.com/armadaproject/armada/internal/executor/domain"
 	"github.com/armadaproject/armada/pkg/api"
 	"github.com/armadaproject/armada/pkg/armadaevents"
 )
@@ -653,6 +654,58 @@ func TestConvertJobSucceeded(t *testing.T) {
 	assert.Equal(t, expected, apiEvents)
 }
 
+func TestConvertJobSucceeded_WithOutputUrl(t *testing.T) {
+	succeeded := &armadaevents.EventSequence_Event{
+		Created: &baseTime,
+		Event: &armadaevents.EventSequence_Event_JobSucceeded{
+			JobSucceeded: &armadaevents.JobSucceeded{
+				JobId: jobIdProto,
+				ResourceInfos: []*armadaevents.KubernetesResourceInfo{
+					{
+						ObjectMeta: &armadaevents.ObjectMeta{
+							ExecutorId:   executorId,
+							Namespace:    namespace,
+							Name:         podName,
+							KubernetesId: runIdString,
+							Annotations:  map[string]string{domain.OutputUrl: "https://store/bucket/output"},
+						},
+						Info: &armadaevents.KubernetesResourceInfo_PodInfo{
+							PodInfo: &armadaevents.PodInfo{
+								NodeName:  nodeName,
+								PodNumber: podNumber,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expected := []*api.EventMessage{
+		{
+			Events: &api.EventMessage_Succeeded{
+				Succeeded: &api.JobSucceededEvent{
+					JobId:        jobIdString,
+					JobSetId:     jobSetName,
+					Queue:        queue,
+					Created:      baseTime,
+					ClusterId:    executorId,
+					KubernetesId: runIdString,
+					NodeName:     nodeName,
+					PodNumber:    podNumber,
+					PodName:      podName,
+					PodNamespace: namespace,
+					OutputUrl:    "https://store/bucket/output",
+				},
+			},
+		},
+	}
+
+	apiEvents, err := FromEventSequence(toEventSeq(succeeded))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, apiEvents)
+}
+
 func TestConvertJobRunning(t *testing.T) {
 	running := &armadaevents.EventSequence_Event{
 		Created: &baseTime,
@@ -919,10 +972,16 @@ func TestConvertJobRunPreempted(t *testing.T) {
 		Created: &baseTime,
 		Event: &armadaevents.EventSequence_Event_JobRunPreempted{
 			JobRunPreempted: &armadaevents.JobRunPreempted{
-				PreemptedJobId:  jobIdProto,
-				PreemptedRunId:  runIdProto,
-				PreemptiveJobId: preemptiveJobIdProto,
-				PreemptiveRunId: preemptiveRunIdRunIdProto,
+				PreemptedJobId:          jobIdProto,
+				PreemptedRunId:          runIdProto,
+				PreemptiveJobId:         preemptiveJobIdProto,
+				PreemptiveRunId:         preemptiveRunIdRunIdProto,
+				Cause:                   "FairShare",
+				PreemptiveQueue:         "other-queue",
+				PreemptivePriorityClass: "armada-default",
+				PreemptiveGangId:        "gang",
+				ExpectedRequeuePosition: 2,
+				QueueCompensated:        true,
 			},
 		},
 	}
@@ -931,13 +990,19 @@ func TestConvertJobRunPreempted(t *testing.T) {
 		{
 			Events: &api.EventMessage_Preempted{
 				Preempted: &api.JobPreemptedEvent{
-					JobId:           jobIdString,
-					JobSetId:        jobSetName,
-					Queue:           queue,
-					Created:         baseTime,
-					RunId:           runIdString,
-					PreemptiveJobId: preemptiveJobIdString,
-					PreemptiveRunId: preemptiveRunIdString,
+					JobId:                   jobIdString,
+					JobSetId:                jobSetName,
+					Queue:                   queue,
+					Created:                 baseTime,
+					RunId:                   runIdString,
+					PreemptiveJobId:         preemptiveJobIdString,
+					PreemptiveRunId:         preemptiveRunIdString,
+					Cause:                   "FairShare",
+					PreemptiveQueue:         "other-queue",
+					PreemptivePriorityClass: "armada-default",
+					PreemptiveGangId:        "gang",
+					ExpectedRequeuePosition: 2,
+					QueueCompensated:        true,
 				},
 			},
 		},
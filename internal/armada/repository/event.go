@@ -51,7 +51,7 @@ func NewEventRepository(db redis.UniversalClient) *RedisEventRepository {
 
 	decompressorPool := pool.NewObjectPool(armadacontext.Background(), pool.NewPooledObjectFactorySimple(
 		func(context.Context) (interface{}, error) {
-			return compress.NewZlibDecompressor(), nil
+			return compress.NewDecompressor()
 		}), &poolConfig)
 
 	return &RedisEventRepository{db: db, decompressorPool: decompressorPool}
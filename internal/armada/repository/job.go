@@ -13,6 +13,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/encryption"
 	protoutil "github.com/armadaproject/armada/internal/common/proto"
 	"github.com/armadaproject/armada/internal/common/util"
 	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
@@ -89,6 +90,8 @@ type JobRepository interface {
 
 type RedisJobRepository struct {
 	db redis.UniversalClient
+	// Used to encrypt job specs at rest; may be nil, in which case job specs are stored unencrypted.
+	envelope *encryption.Envelope
 }
 
 func NewRedisJobRepository(
@@ -97,6 +100,28 @@ func NewRedisJobRepository(
 	return &RedisJobRepository{db: db}
 }
 
+// SetEncryption sets the envelope used to encrypt job specs stored in Redis.
+// Job specs stored before encryption was enabled remain readable.
+func (repo *RedisJobRepository) SetEncryption(envelope *encryption.Envelope) {
+	repo.envelope = envelope
+}
+
+func (repo *RedisJobRepository) marshalJob(job *api.Job) ([]byte, error) {
+	jobData, err := proto.Marshal(job)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return repo.envelope.Encrypt(jobData)
+}
+
+func (repo *RedisJobRepository) unmarshalJob(jobData []byte, job *api.Job) error {
+	jobData, err := repo.envelope.Decrypt(jobData)
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(proto.Unmarshal(jobData, job))
+}
+
 // TODO DuplicateDetected should be remove in favour of setting the error to
 // indicate the job already exists (e.g., by creating ErrJobExists).
 type SubmitJobResult struct {
@@ -113,9 +138,9 @@ func (repo *RedisJobRepository) AddJobs(jobs []*api.Job) ([]*SubmitJobResult, er
 
 	saveResults := make([]*redis.Cmd, 0, len(jobs))
 	for _, job := range jobs {
-		jobData, err := proto.Marshal(job)
+		jobData, err := repo.marshalJob(job)
 		if err != nil {
-			return nil, errors.WithStack(err)
+			return nil, err
 		}
 
 		result := addJob(pipe, job, &jobData)
@@ -373,7 +398,7 @@ func (repo *RedisJobRepository) GetJobsByIds(ids []string) ([]*JobResult, error)
 
 		d, _ := cmd.Bytes() // we already checked the error above
 		result.Job = &api.Job{}
-		err = proto.Unmarshal(d, result.Job)
+		err = repo.unmarshalJob(d, result.Job)
 		if err != nil {
 			err = errors.WithMessagef(err, "job id %s", ids[index])
 			return nil, errors.WithStack(err)
@@ -626,9 +651,9 @@ func (repo *RedisJobRepository) updateJobBatchWithRetry(
 		// Marshal the resulting jobs in preparation for writing back to Redis
 		jobDatas := make([][]byte, len(jobs))
 		for i, job := range jobs {
-			jobData, err := proto.Marshal(job)
+			jobData, err := repo.marshalJob(job)
 			if err != nil {
-				return errors.Wrapf(err, "job id %s", job.Id)
+				return errors.WithMessagef(err, "job id %s", job.Id)
 			}
 			jobDatas[i] = jobData
 		}
@@ -3,6 +3,7 @@ package armada
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
@@ -17,6 +18,7 @@ import (
 	"github.com/armadaproject/armada/internal/armada/cache"
 	"github.com/armadaproject/armada/internal/armada/configuration"
 	"github.com/armadaproject/armada/internal/armada/metrics"
+	"github.com/armadaproject/armada/internal/armada/onboarding"
 	"github.com/armadaproject/armada/internal/armada/repository"
 	"github.com/armadaproject/armada/internal/armada/scheduling"
 	"github.com/armadaproject/armada/internal/armada/server"
@@ -24,6 +26,7 @@ import (
 	"github.com/armadaproject/armada/internal/common/auth"
 	"github.com/armadaproject/armada/internal/common/auth/authorization"
 	"github.com/armadaproject/armada/internal/common/database"
+	"github.com/armadaproject/armada/internal/common/encryption"
 	grpcCommon "github.com/armadaproject/armada/internal/common/grpc"
 	"github.com/armadaproject/armada/internal/common/health"
 	commonmetrics "github.com/armadaproject/armada/internal/common/metrics"
@@ -38,7 +41,8 @@ import (
 	"github.com/armadaproject/armada/pkg/client"
 )
 
-func Serve(ctx *armadacontext.Context, config *configuration.ArmadaConfig, healthChecks *health.MultiChecker) error {
+// Serve starts the Armada server. Plain http APIs not served via the gRPC gateway are registered with mux.
+func Serve(ctx *armadacontext.Context, config *configuration.ArmadaConfig, healthChecks *health.MultiChecker, mux *http.ServeMux) error {
 	log.Info("Armada server starting")
 	log.Infof("Armada priority classes: %v", config.Scheduling.Preemption.PriorityClasses)
 	log.Infof("Default priority class: %s", config.Scheduling.Preemption.DefaultPriorityClass)
@@ -76,7 +80,10 @@ func Serve(ctx *armadacontext.Context, config *configuration.ArmadaConfig, healt
 	if err != nil {
 		return err
 	}
-	grpcServer := grpcCommon.CreateGrpcServer(config.Grpc.KeepaliveParams, config.Grpc.KeepaliveEnforcementPolicy, authServices, config.Grpc.Tls)
+	grpcServer, err := grpcCommon.CreateGrpcServer(config.Grpc.KeepaliveParams, config.Grpc.KeepaliveEnforcementPolicy, authServices, config.Grpc.Tls)
+	if err != nil {
+		return err
+	}
 
 	// Shut down grpcServer if the context is cancelled.
 	// Give the server 5 seconds to shut down gracefully.
@@ -105,7 +112,12 @@ func Serve(ctx *armadacontext.Context, config *configuration.ArmadaConfig, healt
 		}
 	}()
 
+	jobSpecEnvelope, err := encryption.NewEnvelopeFromConfig(config.JobSpecEncryption)
+	if err != nil {
+		return errors.WithMessage(err, "error creating job spec encryption envelope")
+	}
 	jobRepository := repository.NewRedisJobRepository(db)
+	jobRepository.SetEncryption(jobSpecEnvelope)
 	usageRepository := repository.NewRedisUsageRepository(db)
 	queueRepository := repository.NewRedisQueueRepository(db)
 	schedulingInfoRepository := repository.NewRedisSchedulingInfoRepository(db)
@@ -166,6 +178,7 @@ func Serve(ctx *armadacontext.Context, config *configuration.ArmadaConfig, healt
 		CompressionLevel: config.Pulsar.CompressionLevel,
 		BatchingMaxSize:  config.Pulsar.MaxAllowedMessageSize,
 		Topic:            config.Pulsar.JobsetEventsTopic,
+		Encryption:       pulsarutils.ProducerEncryption(&config.Pulsar),
 	})
 	if err != nil {
 		return errors.Wrapf(err, "error creating pulsar producer %s", serverPulsarProducerName)
@@ -184,6 +197,23 @@ func Serve(ctx *armadacontext.Context, config *configuration.ArmadaConfig, healt
 		&config.QueueManagement,
 		&config.Scheduling,
 	)
+	if config.AutoRightsize.Enabled {
+		if config.AutoRightsize.RecommendationsUrl == "" {
+			return errors.New("auto-rightsizing is enabled, but no recommendations url is provided")
+		}
+		rightsizer := server.NewRightsizer(config.AutoRightsize.RecommendationsUrl, config.AutoRightsize.Timeout)
+		submitServer.Rightsizer = rightsizer
+		services = append(services, func() error {
+			return rightsizer.Run(ctx, config.AutoRightsize.RefreshInterval)
+		})
+	}
+	if config.JobSpecArtifacts.Enabled {
+		jobSpecResolver, err := server.NewJobSpecResolver(config.JobSpecArtifacts)
+		if err != nil {
+			return err
+		}
+		submitServer.JobSpecResolver = jobSpecResolver
+	}
 
 	pulsarSubmitServer := &server.PulsarSubmitServer{
 		Producer:                          producer,
@@ -198,8 +228,10 @@ func Serve(ctx *armadacontext.Context, config *configuration.ArmadaConfig, healt
 		Rand:                              util.NewThreadsafeRand(time.Now().UnixNano()),
 		GangIdAnnotation:                  configuration.GangIdAnnotation,
 		IgnoreJobSubmitChecks:             config.IgnoreJobSubmitChecks,
+		SubmissionRateLimiter:             server.NewSubmissionRateLimiter(config.SubmissionRateLimits),
+		OptimalJobsPerRequest:             config.OptimalJobsPerSubmitRequest,
 	}
-	submitServerToRegister := pulsarSubmitServer
+	var submitServerToRegister api.SubmitServer = pulsarSubmitServer
 
 	// If postgres details were provided, enable deduplication.
 	if config.Pulsar.DedupTable != "" {
@@ -228,6 +260,7 @@ func Serve(ctx *armadacontext.Context, config *configuration.ArmadaConfig, healt
 		SubscriptionName:  config.Pulsar.RedisFromPulsarSubscription,
 		Type:              pulsar.KeyShared,
 		ReceiverQueueSize: config.Pulsar.ReceiverQueueSize,
+		Decryption:        pulsarutils.ConsumerDecryption(&config.Pulsar),
 	})
 	if err != nil {
 		return errors.WithStack(err)
@@ -248,12 +281,37 @@ func Serve(ctx *armadacontext.Context, config *configuration.ArmadaConfig, healt
 			Client:           pulsarClient,
 			Topic:            config.Pulsar.JobsetEventsTopic,
 			SubscriptionName: config.Pulsar.EventsPrinterSubscription,
+			Decryption:       pulsarutils.ConsumerDecryption(&config.Pulsar),
 		}
 		services = append(services, func() error {
 			return eventsPrinter.Run(ctx)
 		})
 	}
 
+	queueRequestApprover, err := onboarding.NewApprover(config.QueueOnboarding)
+	if err != nil {
+		return err
+	}
+	onboardingService := onboarding.NewService(
+		onboarding.NewRedisQueueRequestRepository(db),
+		queueRepository,
+		queueRequestApprover,
+		permissions,
+		config.QueueManagement.DefaultPriorityFactor,
+	)
+	onboarding.NewHttpHandler(onboardingService, authServices).RegisterRoutes(mux)
+	server.NewJobValidationHttpHandler(pulsarSubmitServer, authServices).RegisterRoutes(mux)
+	server.NewJobRunAttemptsHttpHandler(
+		server.NewJobRunAttemptsReader(permissions, eventRepository, queueRepository), authServices,
+	).RegisterRoutes(mux)
+	if config.JobWatch.Enabled {
+		jobWatcher, err := server.NewJobWatcher(config.JobWatch, permissions, eventRepository, queueRepository)
+		if err != nil {
+			return err
+		}
+		server.NewJobWatchHttpHandler(jobWatcher, authServices).RegisterRoutes(mux)
+	}
+
 	usageServer := server.NewUsageServer(permissions, config.PriorityHalfTime, &config.Scheduling, usageRepository, queueRepository)
 
 	aggregatedQueueServer := server.NewAggregatedQueueServer(
@@ -276,6 +334,7 @@ func Serve(ctx *armadacontext.Context, config *configuration.ArmadaConfig, healt
 	aggregatedQueueServer.SchedulingContextRepository = schedulingContextRepository
 
 	var schedulingReportsServer schedulerobjects.SchedulerReportingServer
+	var schedulerAdminServer schedulerobjects.SchedulerAdminServer
 	if config.PulsarSchedulerEnabled {
 		schedulerApiConnection, err := createApiConnection(config.SchedulerApiConnection)
 		if err != nil {
@@ -283,17 +342,35 @@ func Serve(ctx *armadacontext.Context, config *configuration.ArmadaConfig, healt
 		}
 		schedulerApiReportsClient := schedulerobjects.NewSchedulerReportingClient(schedulerApiConnection)
 		schedulingReportsServer = scheduler.NewProxyingSchedulingReportsServer(schedulerApiReportsClient)
+		schedulerAdminServer = server.NewSchedulerAdminServer(permissions, schedulerobjects.NewSchedulerAdminClient(schedulerApiConnection))
 	} else {
 		schedulingReportsServer = schedulingContextRepository
 	}
 
-	eventServer := server.NewEventServer(
+	var eventServer api.EventServer = server.NewEventServer(
 		permissions,
 		eventRepository,
 		eventStore,
 		queueRepository,
 		jobRepository,
 	)
+
+	// Forward requests for queues homed to other regions to the control planes of those regions.
+	if config.Regions.LocalRegion != "" {
+		regionRouter := server.NewRegionRouter(config.Regions)
+		for region, remote := range config.Regions.Remotes {
+			for _, endpoint := range remote.Endpoints {
+				conn, err := createApiConnection(endpoint)
+				if err != nil {
+					return errors.Wrapf(err, "error creating connection to region %s at %s", region, endpoint.ArmadaUrl)
+				}
+				defer conn.Close()
+				regionRouter.AddEndpoint(region, endpoint.ArmadaUrl, api.NewSubmitClient(conn), api.NewEventClient(conn))
+			}
+		}
+		submitServerToRegister = server.NewRegionForwardingSubmitServer(submitServerToRegister, regionRouter)
+		eventServer = server.NewRegionForwardingEventServer(eventServer, regionRouter)
+	}
 	leaseManager := scheduling.NewLeaseManager(jobRepository, queueRepository, eventStore, config.Scheduling.Lease.ExpireAfter)
 
 	// Allows for registering functions to be run periodically in the background.
@@ -311,6 +388,10 @@ func Serve(ctx *armadacontext.Context, config *configuration.ArmadaConfig, healt
 	api.RegisterUsageServer(grpcServer, usageServer)
 	api.RegisterEventServer(grpcServer, eventServer)
 	schedulerobjects.RegisterSchedulerReportingServer(grpcServer, schedulingReportsServer)
+	// Scheduling rounds can only be triggered on the Pulsar-backed scheduler.
+	if schedulerAdminServer != nil {
+		schedulerobjects.RegisterSchedulerAdminServer(grpcServer, schedulerAdminServer)
+	}
 
 	api.RegisterAggregatedQueueServer(grpcServer, aggregatedQueueServer)
 	grpc_prometheus.Register(grpcServer)
@@ -3,11 +3,15 @@ package server
 import (
 	"math"
 
+	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/armadaproject/armada/internal/armada/configuration"
 	armadaresource "github.com/armadaproject/armada/internal/common/resource"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
 )
 
 func applyDefaultsToAnnotations(annotations map[string]string, config configuration.SchedulingConfig) {
@@ -19,6 +23,9 @@ func applyDefaultsToAnnotations(annotations map[string]string, config configurat
 
 func applyDefaultNodeUniformityLabelAnnotation(annotations map[string]string, config configuration.SchedulingConfig) {
 	if _, ok := annotations[configuration.GangIdAnnotation]; ok {
+		if _, ok := annotations[configuration.GangNodeUniformityLabelsAnnotation]; ok {
+			return
+		}
 		if _, ok := annotations[configuration.GangNodeUniformityLabelAnnotation]; !ok {
 			annotations[configuration.GangNodeUniformityLabelAnnotation] = config.DefaultGangNodeUniformityLabel
 		}
@@ -36,6 +43,181 @@ func applyDefaultsToPodSpec(spec *v1.PodSpec, config configuration.SchedulingCon
 	applyDefaultTerminationGracePeriodToPodSpec(spec, config)
 }
 
+// applyQueueJobDefaultsToPodSpec injects the defaults configured for the queue, if any, into the pod spec.
+// Values specified by the pod are kept unless the defaults are configured to override them.
+func applyQueueJobDefaultsToPodSpec(queue string, spec *v1.PodSpec, config configuration.SchedulingConfig) {
+	if spec == nil {
+		return
+	}
+	defaults, ok := config.QueueJobDefaults[queue]
+	if !ok {
+		return
+	}
+	for i := range defaults.Tolerations {
+		toleration := defaults.Tolerations[i]
+		if slices.IndexFunc(spec.Tolerations, func(t v1.Toleration) bool { return t.MatchToleration(&toleration) }) == -1 {
+			spec.Tolerations = append(spec.Tolerations, toleration)
+		}
+	}
+	if len(defaults.NodeSelector) > 0 && spec.NodeSelector == nil {
+		spec.NodeSelector = make(map[string]string, len(defaults.NodeSelector))
+	}
+	for k, v := range defaults.NodeSelector {
+		if _, ok := spec.NodeSelector[k]; !ok || defaults.OverrideNodeSelector {
+			spec.NodeSelector[k] = v
+		}
+	}
+	if defaults.PriorityClassName != "" && (spec.PriorityClassName == "" || defaults.OverridePriorityClassName) {
+		spec.PriorityClassName = defaults.PriorityClassName
+	}
+	if defaults.RuntimeClassName != "" && (spec.RuntimeClassName == nil || defaults.OverrideRuntimeClassName) {
+		runtimeClassName := defaults.RuntimeClassName
+		spec.RuntimeClassName = &runtimeClassName
+	}
+}
+
+// applyQueueDefaultsToPodSpec sets the runtime class required by the pod policy of the queue, if any,
+// and adds the node selector of the runtime class of the pod.
+func applyQueueDefaultsToPodSpec(queue string, spec *v1.PodSpec, config configuration.SchedulingConfig) {
+	if spec == nil {
+		return
+	}
+	if policy, ok := config.QueuePodPolicies[queue]; ok && policy.RuntimeClassName != "" && spec.RuntimeClassName == nil {
+		runtimeClassName := policy.RuntimeClassName
+		spec.RuntimeClassName = &runtimeClassName
+	}
+	if spec.RuntimeClassName == nil {
+		return
+	}
+	nodeSelector, ok := config.RuntimeClassNodeSelectors[*spec.RuntimeClassName]
+	if !ok || len(nodeSelector) == 0 {
+		return
+	}
+	if spec.NodeSelector == nil {
+		spec.NodeSelector = make(map[string]string, len(nodeSelector))
+	}
+	for k, v := range nodeSelector {
+		spec.NodeSelector[k] = v
+	}
+}
+
+// applySpotPreferenceToPodSpec gives pods of jobs setting PreferSpotAnnotation a preferred node affinity for spot nodes,
+// i.e., nodes with all of the configured spot node labels. Does nothing if no spot node labels or preference weight are configured.
+func applySpotPreferenceToPodSpec(annotations map[string]string, spec *v1.PodSpec, config configuration.SchedulingConfig) {
+	if spec == nil || len(config.SpotNodeLabels) == 0 || config.SpotPreferenceWeight == 0 {
+		return
+	}
+	// Invalid annotations are rejected when the job is validated.
+	if schedulingAnnotations, _ := schedulercontext.ParseSchedulingAnnotations(annotations); !schedulingAnnotations.PreferSpot {
+		return
+	}
+	matchExpressions := make([]v1.NodeSelectorRequirement, 0, len(config.SpotNodeLabels))
+	for _, key := range maps.Keys(config.SpotNodeLabels) {
+		matchExpressions = append(matchExpressions, v1.NodeSelectorRequirement{
+			Key:      key,
+			Operator: v1.NodeSelectorOpIn,
+			Values:   []string{config.SpotNodeLabels[key]},
+		})
+	}
+	slices.SortFunc(matchExpressions, func(a, b v1.NodeSelectorRequirement) bool { return a.Key < b.Key })
+	if spec.Affinity == nil {
+		spec.Affinity = &v1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &v1.NodeAffinity{}
+	}
+	spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+		spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		v1.PreferredSchedulingTerm{
+			Weight:     config.SpotPreferenceWeight,
+			Preference: v1.NodeSelectorTerm{MatchExpressions: matchExpressions},
+		},
+	)
+}
+
+// applyPoolRoutingToAnnotations sets the pool annotation of a job with the given pod spec to the pool of the first
+// pool routing rule the job matches, if any. Returns the annotations, which are created if nil and a rule matches.
+func applyPoolRoutingToAnnotations(annotations map[string]string, spec *v1.PodSpec, config configuration.SchedulingConfig) map[string]string {
+	if spec == nil || len(config.PoolRoutingRules) == 0 {
+		return annotations
+	}
+	resourceRequest := armadaresource.TotalPodResourceRequest(spec)
+	for _, rule := range config.PoolRoutingRules {
+		if !poolRoutingRuleMatches(rule, resourceRequest, spec.NodeSelector) {
+			continue
+		}
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[configuration.PoolAnnotation] = rule.Pool
+		return annotations
+	}
+	return annotations
+}
+
+func poolRoutingRuleMatches(rule configuration.PoolRoutingRule, resourceRequest armadaresource.ComputeResources, nodeSelector map[string]string) bool {
+	if len(rule.Resources) > 0 {
+		requestsAny := false
+		for _, resourceName := range rule.Resources {
+			if q, ok := resourceRequest[resourceName]; ok && q.Cmp(resource.Quantity{}) == 1 {
+				requestsAny = true
+				break
+			}
+		}
+		if !requestsAny {
+			return false
+		}
+	}
+	for k, v := range rule.NodeSelector {
+		if nodeSelector[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// applyNodeFlavorToPodSpec sets the requests and limits of the first container of a pod requesting a node flavor
+// to the resources of the flavor, and adds the node selector and tolerations of the flavor.
+// Returns an error if the flavor doesn't exist or if any container specifies a resource set by the flavor.
+func applyNodeFlavorToPodSpec(annotations map[string]string, spec *v1.PodSpec, config configuration.SchedulingConfig) error {
+	name, ok := annotations[configuration.NodeFlavorAnnotation]
+	if !ok || spec == nil || len(spec.Containers) == 0 {
+		return nil
+	}
+	flavor, ok := config.NodeFlavors[name]
+	if !ok {
+		return errors.Errorf("node flavor %s does not exist", name)
+	}
+	for _, c := range spec.Containers {
+		for resourceName := range flavor.Resources {
+			_, hasRequest := c.Resources.Requests[resourceName]
+			_, hasLimit := c.Resources.Limits[resourceName]
+			if hasRequest || hasLimit {
+				return errors.Errorf("container %s specifies %s, which is set by node flavor %s", c.Name, resourceName, name)
+			}
+		}
+	}
+	c := &spec.Containers[0]
+	if c.Resources.Requests == nil {
+		c.Resources.Requests = make(v1.ResourceList, len(flavor.Resources))
+	}
+	if c.Resources.Limits == nil {
+		c.Resources.Limits = make(v1.ResourceList, len(flavor.Resources))
+	}
+	for resourceName, quantity := range flavor.Resources {
+		c.Resources.Requests[resourceName] = quantity.DeepCopy()
+		c.Resources.Limits[resourceName] = quantity.DeepCopy()
+	}
+	if len(flavor.NodeSelector) > 0 && spec.NodeSelector == nil {
+		spec.NodeSelector = make(map[string]string, len(flavor.NodeSelector))
+	}
+	for k, v := range flavor.NodeSelector {
+		spec.NodeSelector[k] = v
+	}
+	spec.Tolerations = append(spec.Tolerations, flavor.Tolerations...)
+	return nil
+}
+
 func applyDefaultRequestsAndLimitsToPodSpec(spec *v1.PodSpec, config configuration.SchedulingConfig) {
 	for i := range spec.Containers {
 		c := &spec.Containers[i]
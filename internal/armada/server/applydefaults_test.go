@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
@@ -401,3 +402,350 @@ func TestApplyDefaultsToPodSpec(t *testing.T) {
 func pointerFromValue[T any](v T) *T {
 	return &v
 }
+
+func TestApplyQueueDefaultsToPodSpec(t *testing.T) {
+	gvisor := "gvisor"
+	kata := "kata"
+	config := configuration.SchedulingConfig{
+		QueuePodPolicies: map[string]configuration.QueuePodPolicy{
+			"sandboxed": {RuntimeClassName: gvisor},
+		},
+		RuntimeClassNodeSelectors: map[string]map[string]string{
+			gvisor: {"sandbox.gke.io/runtime": "gvisor"},
+		},
+	}
+	tests := map[string]struct {
+		Queue    string
+		PodSpec  *v1.PodSpec
+		Expected *v1.PodSpec
+	}{
+		"queue without policy": {
+			Queue:    "other",
+			PodSpec:  &v1.PodSpec{},
+			Expected: &v1.PodSpec{},
+		},
+		"runtime class defaulted": {
+			Queue:   "sandboxed",
+			PodSpec: &v1.PodSpec{NodeSelector: map[string]string{"foo": "bar"}},
+			Expected: &v1.PodSpec{
+				RuntimeClassName: &gvisor,
+				NodeSelector:     map[string]string{"foo": "bar", "sandbox.gke.io/runtime": "gvisor"},
+			},
+		},
+		"runtime class not overridden": {
+			Queue:    "sandboxed",
+			PodSpec:  &v1.PodSpec{RuntimeClassName: &kata},
+			Expected: &v1.PodSpec{RuntimeClassName: &kata},
+		},
+		"runtime class node selector in queue without policy": {
+			Queue:   "other",
+			PodSpec: &v1.PodSpec{RuntimeClassName: &gvisor},
+			Expected: &v1.PodSpec{
+				RuntimeClassName: &gvisor,
+				NodeSelector:     map[string]string{"sandbox.gke.io/runtime": "gvisor"},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			applyQueueDefaultsToPodSpec(tc.Queue, tc.PodSpec, config)
+			assert.Equal(t, tc.Expected, tc.PodSpec)
+		})
+	}
+}
+
+func TestApplyPoolRoutingToAnnotations(t *testing.T) {
+	config := configuration.SchedulingConfig{
+		PoolRoutingRules: []configuration.PoolRoutingRule{
+			{Pool: "gpu", Resources: []string{"nvidia.com/gpu", "amd.com/gpu"}},
+			{Pool: "arm", NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"}},
+		},
+	}
+	podSpecWithRequests := func(requests v1.ResourceList, nodeSelector map[string]string) *v1.PodSpec {
+		return &v1.PodSpec{
+			NodeSelector: nodeSelector,
+			Containers:   []v1.Container{{Resources: v1.ResourceRequirements{Requests: requests}}},
+		}
+	}
+	tests := map[string]struct {
+		Annotations map[string]string
+		PodSpec     *v1.PodSpec
+		Expected    map[string]string
+	}{
+		"no matching rule": {
+			Annotations: map[string]string{"foo": "bar"},
+			PodSpec:     podSpecWithRequests(v1.ResourceList{"cpu": resource.MustParse("1")}, nil),
+			Expected:    map[string]string{"foo": "bar"},
+		},
+		"no matching rule with nil annotations": {
+			PodSpec: podSpecWithRequests(v1.ResourceList{"cpu": resource.MustParse("1")}, nil),
+		},
+		"routed by resource": {
+			PodSpec:  podSpecWithRequests(v1.ResourceList{"amd.com/gpu": resource.MustParse("1")}, nil),
+			Expected: map[string]string{configuration.PoolAnnotation: "gpu"},
+		},
+		"zero request not routed": {
+			PodSpec: podSpecWithRequests(v1.ResourceList{"nvidia.com/gpu": resource.MustParse("0")}, nil),
+		},
+		"routed by node selector": {
+			Annotations: map[string]string{"foo": "bar"},
+			PodSpec:     podSpecWithRequests(nil, map[string]string{"kubernetes.io/arch": "arm64"}),
+			Expected:    map[string]string{"foo": "bar", configuration.PoolAnnotation: "arm"},
+		},
+		"first matching rule wins": {
+			PodSpec: podSpecWithRequests(
+				v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+				map[string]string{"kubernetes.io/arch": "arm64"},
+			),
+			Expected: map[string]string{configuration.PoolAnnotation: "gpu"},
+		},
+		"client-provided pool overridden": {
+			Annotations: map[string]string{configuration.PoolAnnotation: "cpu"},
+			PodSpec:     podSpecWithRequests(v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}, nil),
+			Expected:    map[string]string{configuration.PoolAnnotation: "gpu"},
+		},
+		"client-provided pool kept if no rule matches": {
+			Annotations: map[string]string{configuration.PoolAnnotation: "cpu"},
+			PodSpec:     podSpecWithRequests(v1.ResourceList{"cpu": resource.MustParse("1")}, nil),
+			Expected:    map[string]string{configuration.PoolAnnotation: "cpu"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, applyPoolRoutingToAnnotations(tc.Annotations, tc.PodSpec, config))
+		})
+	}
+}
+
+func TestApplyNodeFlavorToPodSpec(t *testing.T) {
+	gpuResources := v1.ResourceList{
+		"cpu":            resource.MustParse("8"),
+		"memory":         resource.MustParse("64Gi"),
+		"nvidia.com/gpu": resource.MustParse("1"),
+	}
+	gpuToleration := v1.Toleration{Key: "nvidia.com/gpu", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule}
+	config := configuration.SchedulingConfig{
+		NodeFlavors: map[string]configuration.NodeFlavor{
+			"gpu-a100-1x": {
+				Resources:    gpuResources,
+				NodeSelector: map[string]string{"node-type": "a100"},
+				Tolerations:  []v1.Toleration{gpuToleration},
+			},
+		},
+	}
+	tests := map[string]struct {
+		Annotations   map[string]string
+		PodSpec       *v1.PodSpec
+		Expected      *v1.PodSpec
+		ExpectSuccess bool
+	}{
+		"no flavor": {
+			PodSpec:       &v1.PodSpec{Containers: []v1.Container{{Name: "main"}}},
+			Expected:      &v1.PodSpec{Containers: []v1.Container{{Name: "main"}}},
+			ExpectSuccess: true,
+		},
+		"flavor": {
+			Annotations: map[string]string{configuration.NodeFlavorAnnotation: "gpu-a100-1x"},
+			PodSpec: &v1.PodSpec{
+				NodeSelector: map[string]string{"foo": "bar"},
+				Containers: []v1.Container{
+					{
+						Name: "main",
+						Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{"ephemeral-storage": resource.MustParse("1Gi")},
+							Limits:   v1.ResourceList{"ephemeral-storage": resource.MustParse("1Gi")},
+						},
+					},
+					{Name: "sidecar"},
+				},
+			},
+			Expected: &v1.PodSpec{
+				NodeSelector: map[string]string{"foo": "bar", "node-type": "a100"},
+				Tolerations:  []v1.Toleration{gpuToleration},
+				Containers: []v1.Container{
+					{
+						Name: "main",
+						Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{
+								"cpu":               resource.MustParse("8"),
+								"memory":            resource.MustParse("64Gi"),
+								"nvidia.com/gpu":    resource.MustParse("1"),
+								"ephemeral-storage": resource.MustParse("1Gi"),
+							},
+							Limits: v1.ResourceList{
+								"cpu":               resource.MustParse("8"),
+								"memory":            resource.MustParse("64Gi"),
+								"nvidia.com/gpu":    resource.MustParse("1"),
+								"ephemeral-storage": resource.MustParse("1Gi"),
+							},
+						},
+					},
+					{Name: "sidecar"},
+				},
+			},
+			ExpectSuccess: true,
+		},
+		"unknown flavor": {
+			Annotations:   map[string]string{configuration.NodeFlavorAnnotation: "gpu-h100-1x"},
+			PodSpec:       &v1.PodSpec{Containers: []v1.Container{{Name: "main"}}},
+			ExpectSuccess: false,
+		},
+		"resource set by flavor": {
+			Annotations: map[string]string{configuration.NodeFlavorAnnotation: "gpu-a100-1x"},
+			PodSpec: &v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "main"},
+					{
+						Name:      "sidecar",
+						Resources: v1.ResourceRequirements{Requests: v1.ResourceList{"cpu": resource.MustParse("1")}},
+					},
+				},
+			},
+			ExpectSuccess: false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := applyNodeFlavorToPodSpec(tc.Annotations, tc.PodSpec, config)
+			if !tc.ExpectSuccess {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, tc.PodSpec)
+		})
+	}
+}
+
+func TestApplyQueueJobDefaultsToPodSpec(t *testing.T) {
+	gvisor := "gvisor"
+	kata := "kata"
+	gpuToleration := v1.Toleration{Key: "gpu", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule}
+	config := configuration.SchedulingConfig{
+		QueueJobDefaults: map[string]configuration.QueueJobDefaults{
+			"defaults": {
+				Tolerations:       []v1.Toleration{gpuToleration},
+				NodeSelector:      map[string]string{"pool": "gpu"},
+				PriorityClassName: "armada-preemptible",
+				RuntimeClassName:  gvisor,
+			},
+			"overrides": {
+				NodeSelector:              map[string]string{"pool": "gpu"},
+				PriorityClassName:         "armada-preemptible",
+				RuntimeClassName:          gvisor,
+				OverrideNodeSelector:      true,
+				OverridePriorityClassName: true,
+				OverrideRuntimeClassName:  true,
+			},
+		},
+	}
+	tests := map[string]struct {
+		Queue    string
+		PodSpec  *v1.PodSpec
+		Expected *v1.PodSpec
+	}{
+		"queue without defaults": {
+			Queue:    "other",
+			PodSpec:  &v1.PodSpec{},
+			Expected: &v1.PodSpec{},
+		},
+		"defaults injected": {
+			Queue:   "defaults",
+			PodSpec: &v1.PodSpec{NodeSelector: map[string]string{"foo": "bar"}},
+			Expected: &v1.PodSpec{
+				Tolerations:       []v1.Toleration{gpuToleration},
+				NodeSelector:      map[string]string{"foo": "bar", "pool": "gpu"},
+				PriorityClassName: "armada-preemptible",
+				RuntimeClassName:  &gvisor,
+			},
+		},
+		"values specified by the pod kept": {
+			Queue: "defaults",
+			PodSpec: &v1.PodSpec{
+				Tolerations:       []v1.Toleration{gpuToleration},
+				NodeSelector:      map[string]string{"pool": "cpu"},
+				PriorityClassName: "armada-default",
+				RuntimeClassName:  &kata,
+			},
+			Expected: &v1.PodSpec{
+				Tolerations:       []v1.Toleration{gpuToleration},
+				NodeSelector:      map[string]string{"pool": "cpu"},
+				PriorityClassName: "armada-default",
+				RuntimeClassName:  &kata,
+			},
+		},
+		"values specified by the pod overridden": {
+			Queue: "overrides",
+			PodSpec: &v1.PodSpec{
+				NodeSelector:      map[string]string{"pool": "cpu"},
+				PriorityClassName: "armada-default",
+				RuntimeClassName:  &kata,
+			},
+			Expected: &v1.PodSpec{
+				NodeSelector:      map[string]string{"pool": "gpu"},
+				PriorityClassName: "armada-preemptible",
+				RuntimeClassName:  &gvisor,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			applyQueueJobDefaultsToPodSpec(tc.Queue, tc.PodSpec, config)
+			assert.Equal(t, tc.Expected, tc.PodSpec)
+		})
+	}
+}
+
+func TestApplySpotPreferenceToPodSpec(t *testing.T) {
+	config := configuration.SchedulingConfig{
+		SpotNodeLabels:       map[string]string{"lifecycle": "spot", "capacity": "preemptible"},
+		SpotPreferenceWeight: 20,
+	}
+	spotTerm := v1.PreferredSchedulingTerm{
+		Weight: 20,
+		Preference: v1.NodeSelectorTerm{
+			MatchExpressions: []v1.NodeSelectorRequirement{
+				{Key: "capacity", Operator: v1.NodeSelectorOpIn, Values: []string{"preemptible"}},
+				{Key: "lifecycle", Operator: v1.NodeSelectorOpIn, Values: []string{"spot"}},
+			},
+		},
+	}
+	tests := map[string]struct {
+		Annotations map[string]string
+		Config      configuration.SchedulingConfig
+		Expected    *v1.PodSpec
+	}{
+		"no annotation": {
+			Config:   config,
+			Expected: &v1.PodSpec{},
+		},
+		"annotation set to false": {
+			Annotations: map[string]string{configuration.PreferSpotAnnotation: "false"},
+			Config:      config,
+			Expected:    &v1.PodSpec{},
+		},
+		"spot preferred": {
+			Annotations: map[string]string{configuration.PreferSpotAnnotation: "true"},
+			Config:      config,
+			Expected: &v1.PodSpec{
+				Affinity: &v1.Affinity{
+					NodeAffinity: &v1.NodeAffinity{
+						PreferredDuringSchedulingIgnoredDuringExecution: []v1.PreferredSchedulingTerm{spotTerm},
+					},
+				},
+			},
+		},
+		"no spot node labels configured": {
+			Annotations: map[string]string{configuration.PreferSpotAnnotation: "true"},
+			Config:      configuration.SchedulingConfig{SpotPreferenceWeight: 20},
+			Expected:    &v1.PodSpec{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			podSpec := &v1.PodSpec{}
+			applySpotPreferenceToPodSpec(tc.Annotations, podSpec, tc.Config)
+			assert.Equal(t, tc.Expected, podSpec)
+		})
+	}
+}
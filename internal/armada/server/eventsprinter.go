@@ -24,6 +24,8 @@ type EventsPrinter struct {
 	Client           pulsar.Client
 	Topic            string
 	SubscriptionName string
+	// Used to decrypt messages if Pulsar end-to-end encryption is enabled; may be nil.
+	Decryption *pulsar.MessageDecryptionInfo
 	// Logger from which the loggers used by this service are derived
 	// (e.g., using srv.Logger.WithField), or nil, in which case the global logrus logger is used.
 	Logger *logrus.Entry
@@ -60,6 +62,7 @@ func (srv *EventsPrinter) Run(ctx *armadacontext.Context) error {
 		Topic:            srv.Topic,
 		SubscriptionName: srv.SubscriptionName,
 		Type:             pulsar.Failover,
+		Decryption:       srv.Decryption,
 	})
 	if err != nil {
 		panic(err)
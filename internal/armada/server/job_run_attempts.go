@@ -0,0 +1,205 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/armadaproject/armada/internal/armada/repository"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/auth/authorization"
+	"github.com/armadaproject/armada/internal/common/logging"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+const JobRunAttemptsPath = "/api/v1/job/runAttempts"
+
+// JobRunAttempt summarises a single run of a job, as reconstructed from the events of its job set.
+type JobRunAttempt struct {
+	Executor string     `json:"executor"`
+	NodeName string     `json:"nodeName,omitempty"`
+	Created  time.Time  `json:"created"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+	// True if the run started, or if its lease was returned after the executor attempted to start it.
+	RunAttempted      bool                       `json:"runAttempted"`
+	TerminationReason jobdb.RunTerminationReason `json:"terminationReason,omitempty"`
+}
+
+// JobRunAttempts is the run history of a job, ordered from first to latest run.
+type JobRunAttempts struct {
+	JobId    string           `json:"jobId"`
+	Queue    string           `json:"queue"`
+	JobSetId string           `json:"jobSetId"`
+	Attempts []*JobRunAttempt `json:"attempts"`
+}
+
+// JobRunAttemptsReader returns the run history of jobs, e.g., to let users see how often and where a job was preempted.
+// Since the history is reconstructed from the events of the job set of the job, it's only available for as long as
+// these events are retained.
+type JobRunAttemptsReader struct {
+	permissions     authorization.PermissionChecker
+	eventRepository repository.EventRepository
+	queueRepository repository.QueueRepository
+}
+
+func NewJobRunAttemptsReader(
+	permissions authorization.PermissionChecker,
+	eventRepository repository.EventRepository,
+	queueRepository repository.QueueRepository,
+) *JobRunAttemptsReader {
+	return &JobRunAttemptsReader{
+		permissions:     permissions,
+		eventRepository: eventRepository,
+		queueRepository: queueRepository,
+	}
+}
+
+// Read returns the run history of the job with the provided id, which must be part of the given job set.
+// Returns ErrNotFound if the job set contains no events for the job.
+func (r *JobRunAttemptsReader) Read(ctx *armadacontext.Context, queueName string, jobSetId string, jobId string) (*JobRunAttempts, error) {
+	if queueName == "" || jobSetId == "" || jobId == "" {
+		return nil, errors.WithStack(&armadaerrors.ErrInvalidArgument{
+			Name:    "jobId",
+			Value:   jobId,
+			Message: "queue, job set id, and job id must be provided",
+		})
+	}
+	q, err := r.queueRepository.GetQueue(queueName)
+	var expected *repository.ErrQueueNotFound
+	if errors.As(err, &expected) {
+		return nil, errors.WithStack(&armadaerrors.ErrNotFound{Type: "queue", Value: queueName})
+	} else if err != nil {
+		return nil, err
+	}
+	if err := validateUserHasWatchPermissions(ctx, r.permissions, q, jobSetId); err != nil {
+		return nil, err
+	}
+
+	found := false
+	rv := &JobRunAttempts{JobId: jobId, Queue: queueName, JobSetId: jobSetId, Attempts: []*JobRunAttempt{}}
+	fromMessageId := ""
+	for {
+		messages, lastMessageId, err := r.eventRepository.ReadEvents(queueName, jobSetId, fromMessageId, jobWatchReadBatchSize, -1)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "error reading events of job set %s of queue %s", jobSetId, queueName)
+		}
+		if lastMessageId == nil {
+			break
+		}
+		fromMessageId = lastMessageId.String()
+		for _, message := range messages {
+			event, err := api.UnwrapEvent(message.Message)
+			if err != nil || event.GetJobId() != jobId {
+				continue
+			}
+			found = true
+			rv.Attempts = applyJobRunAttemptEvent(rv.Attempts, message.Message)
+		}
+	}
+	if !found {
+		return nil, errors.WithStack(&armadaerrors.ErrNotFound{Type: "job", Value: jobId})
+	}
+	return rv, nil
+}
+
+// applyJobRunAttemptEvent updates the run attempts of a job with an event of that job.
+// Each lease starts a new attempt; all other events update the latest attempt, if it hasn't yet terminated.
+func applyJobRunAttemptEvent(attempts []*JobRunAttempt, message *api.EventMessage) []*JobRunAttempt {
+	if e, ok := message.GetEvents().(*api.EventMessage_Leased); ok {
+		return append(attempts, &JobRunAttempt{Executor: e.Leased.ClusterId, Created: e.Leased.Created})
+	}
+	if len(attempts) == 0 {
+		return attempts
+	}
+	attempt := attempts[len(attempts)-1]
+	if attempt.TerminationReason != jobdb.RunTerminationReasonNone {
+		// E.g., the failed event following the preemption of a run.
+		return attempts
+	}
+	terminate := func(reason jobdb.RunTerminationReason, t time.Time) {
+		attempt.TerminationReason = reason
+		attempt.Finished = &t
+	}
+	switch e := message.GetEvents().(type) {
+	case *api.EventMessage_Running:
+		attempt.Started = &e.Running.Created
+		attempt.NodeName = e.Running.NodeName
+		attempt.RunAttempted = true
+	case *api.EventMessage_Preempted:
+		terminate(jobdb.RunTerminationReasonPreempted, e.Preempted.Created)
+	case *api.EventMessage_LeaseReturned:
+		attempt.RunAttempted = attempt.RunAttempted || e.LeaseReturned.RunAttempted
+		terminate(jobdb.RunTerminationReasonReturned, e.LeaseReturned.Created)
+	case *api.EventMessage_Failed:
+		if e.Failed.NodeName != "" {
+			attempt.NodeName = e.Failed.NodeName
+		}
+		terminate(jobdb.RunTerminationReasonFailed, e.Failed.Created)
+	case *api.EventMessage_Succeeded:
+		terminate(jobdb.RunTerminationReasonSucceeded, e.Succeeded.Created)
+	case *api.EventMessage_Cancelled:
+		terminate(jobdb.RunTerminationReasonCancelled, e.Cancelled.Created)
+	}
+	return attempts
+}
+
+// JobRunAttemptsHttpHandler exposes a JobRunAttemptsReader as a json http API:
+//
+//	GET /api/v1/job/runAttempts?queue={queue}&jobSetId={jobSetId}&jobId={jobId}   returns the JobRunAttempts of a job
+//
+// Requests are authenticated using the same authentication services as the gRPC API.
+type JobRunAttemptsHttpHandler struct {
+	reader       *JobRunAttemptsReader
+	authServices []authorization.AuthService
+}
+
+func NewJobRunAttemptsHttpHandler(reader *JobRunAttemptsReader, authServices []authorization.AuthService) *JobRunAttemptsHttpHandler {
+	return &JobRunAttemptsHttpHandler{
+		reader:       reader,
+		authServices: authServices,
+	}
+}
+
+// RegisterRoutes registers the handler with mux.
+func (h *JobRunAttemptsHttpHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle(JobRunAttemptsPath, h)
+}
+
+func (h *JobRunAttemptsHttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	authCtx, err := authorization.AuthenticateHttpRequest(r, h.authServices)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	ctx := armadacontext.New(authCtx, log.NewEntry(log.StandardLogger()))
+
+	query := r.URL.Query()
+	rv, err := h.reader.Read(ctx, query.Get("queue"), query.Get("jobSetId"), query.Get("jobId"))
+	if err != nil {
+		statusCode := runtime.HTTPStatusFromCode(armadaerrors.CodeFromError(err))
+		var e *armadaerrors.ErrUnauthorized
+		if errors.As(err, &e) {
+			statusCode = http.StatusForbidden
+		}
+		if statusCode == http.StatusInternalServerError {
+			logging.WithStacktrace(ctx, err).Error("failed to serve job run attempts request")
+		}
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rv); err != nil {
+		logging.WithStacktrace(ctx, err).Error("failed to write job run attempts response")
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+func TestJobRunAttemptsReader_Read(t *testing.T) {
+	t0 := time.Date(2023, 11, 15, 6, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return t0.Add(time.Duration(minutes) * time.Minute) }
+	eventRepository := &fakeJobWatchEventRepository{}
+	for _, event := range []*api.EventMessage{
+		{Events: &api.EventMessage_Submitted{Submitted: &api.JobSubmittedEvent{JobId: "a", JobSetId: "set", Queue: "queue", Created: at(0)}}},
+		{Events: &api.EventMessage_Leased{Leased: &api.JobLeasedEvent{JobId: "a", JobSetId: "set", Queue: "queue", Created: at(1), ClusterId: "executor-1"}}},
+		{Events: &api.EventMessage_Running{Running: &api.JobRunningEvent{JobId: "a", JobSetId: "set", Queue: "queue", Created: at(2), NodeName: "node-1"}}},
+		{Events: &api.EventMessage_Preempted{Preempted: &api.JobPreemptedEvent{JobId: "a", JobSetId: "set", Queue: "queue", Created: at(3)}}},
+		{Events: &api.EventMessage_Failed{Failed: &api.JobFailedEvent{JobId: "a", JobSetId: "set", Queue: "queue", Created: at(3)}}},
+		{Events: &api.EventMessage_Leased{Leased: &api.JobLeasedEvent{JobId: "other", JobSetId: "set", Queue: "queue", Created: at(4), ClusterId: "executor-1"}}},
+		{Events: &api.EventMessage_Leased{Leased: &api.JobLeasedEvent{JobId: "a", JobSetId: "set", Queue: "queue", Created: at(5), ClusterId: "executor-2"}}},
+		{Events: &api.EventMessage_LeaseReturned{LeaseReturned: &api.JobLeaseReturnedEvent{JobId: "a", JobSetId: "set", Queue: "queue", Created: at(6), RunAttempted: true}}},
+		{Events: &api.EventMessage_Leased{Leased: &api.JobLeasedEvent{JobId: "a", JobSetId: "set", Queue: "queue", Created: at(7), ClusterId: "executor-1"}}},
+		{Events: &api.EventMessage_Running{Running: &api.JobRunningEvent{JobId: "a", JobSetId: "set", Queue: "queue", Created: at(8), NodeName: "node-2"}}},
+	} {
+		eventRepository.add(event)
+	}
+	reader := NewJobRunAttemptsReader(FakePermissionChecker{}, eventRepository, &fakeQueueRepository{})
+	ctx := testJobWatchContext("alice")
+
+	rv, err := reader.Read(ctx, "queue", "set", "a")
+	require.NoError(t, err)
+	timePtr := func(t time.Time) *time.Time { return &t }
+	assert.Equal(
+		t,
+		[]*JobRunAttempt{
+			{
+				Executor:          "executor-1",
+				NodeName:          "node-1",
+				Created:           at(1),
+				Started:           timePtr(at(2)),
+				Finished:          timePtr(at(3)),
+				RunAttempted:      true,
+				TerminationReason: jobdb.RunTerminationReasonPreempted,
+			},
+			{
+				Executor:          "executor-2",
+				Created:           at(5),
+				Finished:          timePtr(at(6)),
+				RunAttempted:      true,
+				TerminationReason: jobdb.RunTerminationReasonReturned,
+			},
+			{
+				Executor:     "executor-1",
+				NodeName:     "node-2",
+				Created:      at(7),
+				Started:      timePtr(at(8)),
+				RunAttempted: true,
+			},
+		},
+		rv.Attempts,
+	)
+
+	// Jobs not yet leased have no run attempts.
+	eventRepository.add(&api.EventMessage{Events: &api.EventMessage_Submitted{Submitted: &api.JobSubmittedEvent{JobId: "b", JobSetId: "set", Queue: "queue", Created: at(9)}}})
+	rv, err = reader.Read(ctx, "queue", "set", "b")
+	require.NoError(t, err)
+	assert.Empty(t, rv.Attempts)
+
+	var notFound *armadaerrors.ErrNotFound
+	_, err = reader.Read(ctx, "queue", "set", "c")
+	assert.True(t, errors.As(err, &notFound))
+	var invalidArgument *armadaerrors.ErrInvalidArgument
+	_, err = reader.Read(ctx, "queue", "set", "")
+	assert.True(t, errors.As(err, &invalidArgument))
+}
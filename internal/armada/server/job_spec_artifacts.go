@@ -0,0 +1,238 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/pkg/api"
+	clientutil "github.com/armadaproject/armada/pkg/client/util"
+)
+
+const (
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	// Maximum size of the manifest of an artifact; manifests of single-layer artifacts are far smaller.
+	maxOciManifestSize = 1 << 20
+)
+
+// JobSpecResolver fills in the spec of jobs that reference a job spec stored as an OCI artifact via JobSpecRefAnnotation.
+// References are digest-pinned and the fetched manifest and spec are verified against their digests,
+// such that a reference always resolves to the same spec; resolved specs are cached accordingly.
+type JobSpecResolver struct {
+	config configuration.JobSpecArtifactsConfig
+	client *http.Client
+	// Maps references to the spec stored in the referenced artifact.
+	cache *lru.Cache
+}
+
+// jobSpecRef is a parsed reference to a job spec artifact, e.g., registry.example.com/team/spec@sha256:<digest>.
+type jobSpecRef struct {
+	registry   string
+	repository string
+	digest     string
+}
+
+// ociManifest is the subset of an OCI image manifest needed to locate the layer of an artifact.
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+func NewJobSpecResolver(config configuration.JobSpecArtifactsConfig) (*JobSpecResolver, error) {
+	cache, err := lru.New(config.CacheSize)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &JobSpecResolver{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		cache:  cache,
+	}, nil
+}
+
+// Resolve replaces each item of req that references a job spec artifact with the referenced spec,
+// overridden by any fields set on the item. Items referencing an artifact may not contain a pod spec.
+func (r *JobSpecResolver) Resolve(ctx *armadacontext.Context, req *api.JobSubmitRequest) error {
+	for i, item := range req.JobRequestItems {
+		reference, ok := item.Annotations[configuration.JobSpecRefAnnotation]
+		if !ok {
+			continue
+		}
+		spec, err := r.jobSpec(ctx, reference)
+		if err != nil {
+			return errors.Errorf("error resolving job spec %s of the %d-th job of job set %s: %v", reference, i, req.JobSetId, err)
+		}
+		if err := mergeJobSpec(item, spec); err != nil {
+			return errors.Errorf("error resolving job spec %s of the %d-th job of job set %s: %v", reference, i, req.JobSetId, err)
+		}
+	}
+	return nil
+}
+
+// jobSpec returns the job spec stored in the referenced artifact.
+// A new object is returned on each call, since specs are modified at submission.
+func (r *JobSpecResolver) jobSpec(ctx *armadacontext.Context, reference string) (*api.JobSubmitRequestItem, error) {
+	data, ok := r.cache.Get(reference)
+	if !ok {
+		ref, err := parseJobSpecRef(reference)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(r.config.AllowedRegistries, ref.registry) {
+			return nil, errors.Errorf("registry %s is not allowed", ref.registry)
+		}
+		data, err = r.fetch(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		r.cache.Add(reference, data)
+	}
+	spec := &api.JobSubmitRequestItem{}
+	if err := clientutil.BindJsonOrYamlBytes(data.([]byte), spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// fetch returns the contents of the single layer of the referenced artifact,
+// after verifying the manifest and layer against their digests.
+func (r *JobSpecResolver) fetch(ctx *armadacontext.Context, ref *jobSpecRef) ([]byte, error) {
+	manifestData, err := r.get(ctx, ref, "manifests", ref.digest, maxOciManifestSize)
+	if err != nil {
+		return nil, err
+	}
+	var manifest ociManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, errors.Wrap(err, "error parsing manifest")
+	}
+	if len(manifest.Layers) != 1 {
+		return nil, errors.Errorf("artifact has %d layers, but must have exactly one", len(manifest.Layers))
+	}
+	layer := manifest.Layers[0]
+	if layer.Size > r.config.MaxSize {
+		return nil, errors.Errorf("job spec of size %d exceeds the maximum size of %d", layer.Size, r.config.MaxSize)
+	}
+	return r.get(ctx, ref, "blobs", layer.Digest, r.config.MaxSize)
+}
+
+// get fetches the manifest or blob with the given digest from the repository of ref and verifies its contents against the digest.
+func (r *JobSpecResolver) get(ctx *armadacontext.Context, ref *jobSpecRef, kind string, digest string, maxSize int64) ([]byte, error) {
+	url := fmt.Sprintf("https://%s/v2/%s/%s/%s", ref.registry, ref.repository, kind, digest)
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if kind == "manifests" {
+		httpRequest.Header.Set("Accept", ociManifestMediaType)
+	}
+	resp, err := r.client.Do(httpRequest)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("registry returned status %s for %s", resp.Status, url)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, errors.Errorf("%s exceeds the maximum size of %d", url, maxSize)
+	}
+	if actualDigest := sha256Digest(data); actualDigest != digest {
+		return nil, errors.Errorf("digest %s of %s does not match the expected digest %s", actualDigest, url, digest)
+	}
+	return data, nil
+}
+
+// parseJobSpecRef parses a reference of the form <registry>/<repository>@sha256:<digest>.
+// Only digest-pinned references are accepted, since tags may be moved to another artifact.
+func parseJobSpecRef(reference string) (*jobSpecRef, error) {
+	name, digest, ok := strings.Cut(reference, "@")
+	if !ok {
+		return nil, errors.Errorf("reference %s is not pinned to a digest", reference)
+	}
+	hexDigest, ok := strings.CutPrefix(digest, "sha256:")
+	if !ok || len(hexDigest) != sha256.Size*2 {
+		return nil, errors.Errorf("reference %s does not have a valid sha256 digest", reference)
+	}
+	if _, err := hex.DecodeString(hexDigest); err != nil {
+		return nil, errors.Errorf("reference %s does not have a valid sha256 digest", reference)
+	}
+	registry, repository, ok := strings.Cut(name, "/")
+	if !ok || registry == "" || repository == "" {
+		return nil, errors.Errorf("reference %s does not specify both a registry and a repository", reference)
+	}
+	if strings.Contains(repository, ":") {
+		return nil, errors.Errorf("reference %s may not specify both a tag and a digest", reference)
+	}
+	return &jobSpecRef{registry: registry, repository: repository, digest: digest}, nil
+}
+
+// mergeJobSpec fills in item from spec. Fields set on item take precedence over those of spec,
+// except for pod specs, which may only be provided by spec.
+func mergeJobSpec(item *api.JobSubmitRequestItem, spec *api.JobSubmitRequestItem) error {
+	if item.PodSpec != nil || len(item.PodSpecs) > 0 {
+		return errors.New("jobs referencing a job spec may not contain a podSpec")
+	}
+	item.PodSpec = spec.PodSpec
+	item.PodSpecs = spec.PodSpecs
+	if item.Priority == 0 {
+		item.Priority = spec.Priority
+	}
+	if item.Namespace == "" {
+		item.Namespace = spec.Namespace
+	}
+	if item.Scheduler == "" {
+		item.Scheduler = spec.Scheduler
+	}
+	if item.QueueTtlSeconds == 0 {
+		item.QueueTtlSeconds = spec.QueueTtlSeconds
+	}
+	if len(item.Ingress) == 0 {
+		item.Ingress = spec.Ingress
+	}
+	if len(item.Services) == 0 {
+		item.Services = spec.Services
+	}
+	item.Labels = mergeStringMaps(spec.Labels, item.Labels)
+	item.Annotations = mergeStringMaps(spec.Annotations, item.Annotations)
+	item.RequiredNodeLabels = mergeStringMaps(spec.RequiredNodeLabels, item.RequiredNodeLabels)
+	return nil
+}
+
+// mergeStringMaps returns a map containing the entries of both a and b, with those of b taking precedence.
+func mergeStringMaps(a, b map[string]string) map[string]string {
+	if len(a) == 0 {
+		return b
+	}
+	rv := make(map[string]string, len(a)+len(b))
+	for k, v := range a {
+		rv[k] = v
+	}
+	for k, v := range b {
+		rv[k] = v
+	}
+	return rv
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
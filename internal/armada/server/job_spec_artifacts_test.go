@@ -0,0 +1,114 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+const testJobSpec = `
+priority: 1
+namespace: spec-namespace
+labels:
+  team: ml
+  stage: spec
+podSpecs:
+  - containers:
+      - name: main
+        image: alpine:3.18
+        resources:
+          requests:
+            cpu: 1
+            memory: 1Gi
+          limits:
+            cpu: 1
+            memory: 1Gi
+`
+
+func TestJobSpecResolver(t *testing.T) {
+	layerDigest := sha256Digest([]byte(testJobSpec))
+	manifest := []byte(fmt.Sprintf(
+		`{"schemaVersion": 2, "layers": [{"mediaType": "application/yaml", "digest": "%s", "size": %d}]}`,
+		layerDigest, len(testJobSpec),
+	))
+	manifestDigest := sha256Digest(manifest)
+	numRequests := 0
+	registry := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numRequests++
+		switch r.URL.Path {
+		case "/v2/team/spec/manifests/" + manifestDigest:
+			_, _ = w.Write(manifest)
+		case "/v2/team/spec/blobs/" + layerDigest:
+			_, _ = w.Write([]byte(testJobSpec))
+		case "/v2/team/tampered/manifests/" + manifestDigest:
+			_, _ = w.Write(append(manifest, ' '))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+	host := strings.TrimPrefix(registry.URL, "https://")
+
+	resolver, err := NewJobSpecResolver(configuration.JobSpecArtifactsConfig{
+		Enabled:           true,
+		AllowedRegistries: []string{host},
+		MaxSize:           1 << 20,
+		CacheSize:         10,
+		Timeout:           time.Second,
+	})
+	require.NoError(t, err)
+	resolver.client = registry.Client()
+
+	newRequest := func(reference string) *api.JobSubmitRequest {
+		return &api.JobSubmitRequest{
+			Queue:    "queue",
+			JobSetId: "job-set",
+			JobRequestItems: []*api.JobSubmitRequestItem{
+				{
+					Priority:    2,
+					Labels:      map[string]string{"stage": "submission"},
+					Annotations: map[string]string{configuration.JobSpecRefAnnotation: reference},
+				},
+			},
+		}
+	}
+
+	// Fields set on the submitted job take precedence over those of the spec.
+	req := newRequest(host + "/team/spec@" + manifestDigest)
+	require.NoError(t, resolver.Resolve(armadacontext.Background(), req))
+	item := req.JobRequestItems[0]
+	assert.Equal(t, float64(2), item.Priority)
+	assert.Equal(t, "spec-namespace", item.Namespace)
+	assert.Equal(t, map[string]string{"team": "ml", "stage": "submission"}, item.Labels)
+	require.Len(t, item.PodSpecs, 1)
+	assert.Equal(t, "alpine:3.18", item.PodSpecs[0].Containers[0].Image)
+	assert.Equal(t, 2, numRequests)
+
+	// Resolved specs are cached.
+	req = newRequest(host + "/team/spec@" + manifestDigest)
+	require.NoError(t, resolver.Resolve(armadacontext.Background(), req))
+	assert.Len(t, req.JobRequestItems[0].PodSpecs, 1)
+	assert.Equal(t, 2, numRequests)
+
+	// Artifacts not matching their digest are rejected.
+	assert.Error(t, resolver.Resolve(armadacontext.Background(), newRequest(host+"/team/tampered@"+manifestDigest)))
+
+	// Jobs referencing a spec may not contain a pod spec.
+	req = newRequest(host + "/team/spec@" + manifestDigest)
+	req.JobRequestItems[0].PodSpecs = item.PodSpecs
+	assert.Error(t, resolver.Resolve(armadacontext.Background(), req))
+
+	// References must be digest-pinned and refer to an allowed registry.
+	assert.Error(t, resolver.Resolve(armadacontext.Background(), newRequest(host+"/team/spec:latest")))
+	assert.Error(t, resolver.Resolve(armadacontext.Background(), newRequest("registry.example.com/team/spec@"+manifestDigest)))
+}
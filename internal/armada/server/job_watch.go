@@ -0,0 +1,468 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/utils/clock"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/armada/repository"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/auth/authorization"
+	"github.com/armadaproject/armada/internal/common/logging"
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+const JobWatchPath = "/api/v1/job/watch"
+
+// Number of events read from a job set per request to the event repository.
+const jobWatchReadBatchSize = 500
+
+// JobWatchJobSet is a set of jobs of the same job set to watch.
+type JobWatchJobSet struct {
+	Queue    string   `json:"queue"`
+	JobSetId string   `json:"jobSetId"`
+	JobIds   []string `json:"jobIds"`
+}
+
+// JobWatchRequest is a request to subscribe to the terminal states of jobs, possibly spanning several job sets.
+type JobWatchRequest struct {
+	JobSets []JobWatchJobSet `json:"jobSets"`
+}
+
+// JobWatchSubscription identifies a subscription to the terminal states of jobs.
+// The subscription expires at LeaseExpiry unless polled before then.
+type JobWatchSubscription struct {
+	SubscriptionId string    `json:"subscriptionId"`
+	LeaseExpiry    time.Time `json:"leaseExpiry"`
+}
+
+// JobWatchOutcome is the terminal state a watched job reached.
+type JobWatchOutcome string
+
+const (
+	JobWatchOutcomeSucceeded JobWatchOutcome = "succeeded"
+	JobWatchOutcomeFailed    JobWatchOutcome = "failed"
+	JobWatchOutcomeCancelled JobWatchOutcome = "cancelled"
+)
+
+// JobWatchResult is the terminal state of a watched job.
+type JobWatchResult struct {
+	JobId    string          `json:"jobId"`
+	Queue    string          `json:"queue"`
+	JobSetId string          `json:"jobSetId"`
+	Outcome  JobWatchOutcome `json:"outcome"`
+	// Reason the job failed or was cancelled, if any.
+	Reason string    `json:"reason,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// JobWatchPollResult contains the jobs of a subscription that reached a terminal state since the previous poll.
+// Once Done, i.e., once all watched jobs reached a terminal state, the subscription is removed.
+type JobWatchPollResult struct {
+	Jobs        []*JobWatchResult `json:"jobs"`
+	Pending     int               `json:"pending"`
+	Done        bool              `json:"done"`
+	LeaseExpiry time.Time         `json:"leaseExpiry,omitempty"`
+}
+
+type jobWatchJobSet struct {
+	queue    string
+	jobSetId string
+	// Id of the last event read from the job set.
+	fromMessageId string
+	// Ids of watched jobs yet to reach a terminal state.
+	pendingJobIds map[string]bool
+}
+
+type jobWatchSubscription struct {
+	// Name of the principal that created the subscription, which is the only principal allowed to poll it.
+	owner string
+	// Serialises polls of the subscription.
+	mu      sync.Mutex
+	jobSets []*jobWatchJobSet
+	// Guarded by JobWatcher.mu.
+	leaseExpiry time.Time
+}
+
+func (s *jobWatchSubscription) numPending() int {
+	rv := 0
+	for _, jobSet := range s.jobSets {
+		rv += len(jobSet.pendingJobIds)
+	}
+	return rv
+}
+
+// JobWatcher tracks the terminal states of sets of jobs on behalf of workflow engines.
+// Rather than streaming all events of a job set, or polling each job, clients subscribe to a set of job ids
+// and long-poll the subscription, which returns as soon as any of the jobs reached a terminal state.
+// Polls renew the lease of the subscription; subscriptions not polled within their lease expire.
+// Subscriptions are held in memory; clients should resubscribe to their remaining jobs if a poll reports
+// the subscription as not found, e.g., since it was served by another server instance.
+type JobWatcher struct {
+	config          configuration.JobWatchConfig
+	permissions     authorization.PermissionChecker
+	eventRepository repository.EventRepository
+	queueRepository repository.QueueRepository
+	clock           clock.Clock
+
+	mu            sync.Mutex
+	subscriptions map[string]*jobWatchSubscription
+}
+
+func NewJobWatcher(
+	config configuration.JobWatchConfig,
+	permissions authorization.PermissionChecker,
+	eventRepository repository.EventRepository,
+	queueRepository repository.QueueRepository,
+) (*JobWatcher, error) {
+	if config.PollInterval <= 0 {
+		// Polls would otherwise read the job sets of their subscription in a busy loop.
+		return nil, errors.Errorf("job watch poll interval must be positive, but is %s", config.PollInterval)
+	}
+	return &JobWatcher{
+		config:          config,
+		permissions:     permissions,
+		eventRepository: eventRepository,
+		queueRepository: queueRepository,
+		clock:           clock.RealClock{},
+		subscriptions:   make(map[string]*jobWatchSubscription),
+	}, nil
+}
+
+// Subscribe creates a subscription to the terminal states of the jobs of req.
+// Jobs that already reached a terminal state are reported by the first poll.
+func (w *JobWatcher) Subscribe(ctx *armadacontext.Context, req *JobWatchRequest) (*JobWatchSubscription, error) {
+	numJobs := 0
+	jobSetByKey := make(map[string]*jobWatchJobSet)
+	var jobSets []*jobWatchJobSet
+	for _, reqJobSet := range req.JobSets {
+		if reqJobSet.Queue == "" || reqJobSet.JobSetId == "" {
+			return nil, errors.WithStack(&armadaerrors.ErrInvalidArgument{
+				Name:    "jobSets",
+				Value:   reqJobSet,
+				Message: "queue and job set id must be provided",
+			})
+		}
+		key := reqJobSet.Queue + "/" + reqJobSet.JobSetId
+		jobSet, ok := jobSetByKey[key]
+		if !ok {
+			if err := w.authorize(ctx, reqJobSet.Queue, reqJobSet.JobSetId); err != nil {
+				return nil, err
+			}
+			jobSet = &jobWatchJobSet{
+				queue:         reqJobSet.Queue,
+				jobSetId:      reqJobSet.JobSetId,
+				pendingJobIds: make(map[string]bool),
+			}
+			jobSetByKey[key] = jobSet
+			jobSets = append(jobSets, jobSet)
+		}
+		for _, jobId := range reqJobSet.JobIds {
+			if !jobSet.pendingJobIds[jobId] {
+				jobSet.pendingJobIds[jobId] = true
+				numJobs++
+			}
+		}
+	}
+	if numJobs == 0 {
+		return nil, errors.WithStack(&armadaerrors.ErrInvalidArgument{
+			Name:    "jobSets",
+			Value:   req.JobSets,
+			Message: "at least one job id must be provided",
+		})
+	}
+	if w.config.MaxJobsPerSubscription > 0 && numJobs > w.config.MaxJobsPerSubscription {
+		return nil, errors.WithStack(&armadaerrors.ErrInvalidArgument{
+			Name:    "jobSets",
+			Value:   numJobs,
+			Message: fmt.Sprintf("at most %d jobs may be watched per subscription", w.config.MaxJobsPerSubscription),
+		})
+	}
+
+	principal := authorization.GetPrincipal(ctx)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.clock.Now()
+	w.removeExpiredSubscriptions(now)
+	if w.config.MaxSubscriptions > 0 && len(w.subscriptions) >= w.config.MaxSubscriptions {
+		return nil, errors.WithStack(&armadaerrors.ErrRateLimited{
+			Principal: principal.GetName(),
+			Limit:     "job watch subscriptions",
+			Message:   fmt.Sprintf("at most %d subscriptions may be active at a time", w.config.MaxSubscriptions),
+		})
+	}
+	subscription := &jobWatchSubscription{
+		owner:       principal.GetName(),
+		jobSets:     jobSets,
+		leaseExpiry: now.Add(w.config.LeaseDuration),
+	}
+	id := uuid.NewString()
+	w.subscriptions[id] = subscription
+	ctx.Infof("created job watch subscription %s for %d jobs of %d job sets", id, numJobs, len(jobSets))
+	return &JobWatchSubscription{SubscriptionId: id, LeaseExpiry: subscription.leaseExpiry}, nil
+}
+
+func (w *JobWatcher) authorize(ctx *armadacontext.Context, queueName string, jobSetId string) error {
+	q, err := w.queueRepository.GetQueue(queueName)
+	var expected *repository.ErrQueueNotFound
+	if errors.As(err, &expected) {
+		return errors.WithStack(&armadaerrors.ErrNotFound{Type: "queue", Value: queueName})
+	} else if err != nil {
+		return err
+	}
+	return validateUserHasWatchPermissions(ctx, w.permissions, q, jobSetId)
+}
+
+// removeExpiredSubscriptions removes subscriptions whose lease expired. Must be called with w.mu held.
+func (w *JobWatcher) removeExpiredSubscriptions(now time.Time) {
+	for id, subscription := range w.subscriptions {
+		if !now.Before(subscription.leaseExpiry) {
+			delete(w.subscriptions, id)
+		}
+	}
+}
+
+// getSubscription returns the subscription with the provided id, if it exists, hasn't expired, and is owned by the principal of ctx.
+// If renew is true, its lease is renewed.
+func (w *JobWatcher) getSubscription(ctx *armadacontext.Context, id string, renew bool) (*jobWatchSubscription, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.clock.Now()
+	subscription, ok := w.subscriptions[id]
+	if ok && !now.Before(subscription.leaseExpiry) {
+		delete(w.subscriptions, id)
+		ok = false
+	}
+	if !ok || subscription.owner != authorization.GetPrincipal(ctx).GetName() {
+		return nil, errors.WithStack(&armadaerrors.ErrNotFound{
+			Type:    "job watch subscription",
+			Value:   id,
+			Message: "the subscription may have expired or have been created via another server instance; subscribe again to the remaining jobs",
+		})
+	}
+	if renew {
+		subscription.leaseExpiry = now.Add(w.config.LeaseDuration)
+	}
+	return subscription, nil
+}
+
+// Poll returns the jobs of the subscription with the provided id that reached a terminal state since the previous poll.
+// If none did, Poll waits up to timeout, capped at the configured maximum, for any to do so.
+// Each poll renews the lease of the subscription. Once all jobs reached a terminal state, the subscription is removed.
+func (w *JobWatcher) Poll(ctx *armadacontext.Context, id string, timeout time.Duration) (*JobWatchPollResult, error) {
+	subscription, err := w.getSubscription(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	subscription.mu.Lock()
+	defer subscription.mu.Unlock()
+
+	if timeout > w.config.MaxPollTimeout {
+		timeout = w.config.MaxPollTimeout
+	}
+	deadline := w.clock.Now().Add(timeout)
+	var results []*JobWatchResult
+	for {
+		results, err = w.readTerminalJobs(subscription)
+		if err != nil {
+			return nil, err
+		}
+		remaining := deadline.Sub(w.clock.Now())
+		if len(results) > 0 || subscription.numPending() == 0 || remaining <= 0 {
+			break
+		}
+		wait := w.config.PollInterval
+		if remaining < wait {
+			wait = remaining
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-w.clock.After(wait):
+		}
+	}
+
+	rv := &JobWatchPollResult{Jobs: results, Pending: subscription.numPending()}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if rv.Pending == 0 {
+		delete(w.subscriptions, id)
+		rv.Done = true
+	} else {
+		// Leases start when polls return, such that long polls don't eat into the lease.
+		subscription.leaseExpiry = w.clock.Now().Add(w.config.LeaseDuration)
+		rv.LeaseExpiry = subscription.leaseExpiry
+	}
+	return rv, nil
+}
+
+// readTerminalJobs reads all events of the job sets of subscription since the previous read,
+// removing jobs that reached a terminal state from those pending and returning their results.
+// The subscription is only updated once the events of all its job sets were read, such that no results are lost
+// if reading fails; the events are then read again by the next poll.
+func (w *JobWatcher) readTerminalJobs(subscription *jobWatchSubscription) ([]*JobWatchResult, error) {
+	fromMessageIds := make([]string, len(subscription.jobSets))
+	resultsByJobSet := make([][]*JobWatchResult, len(subscription.jobSets))
+	for i, jobSet := range subscription.jobSets {
+		fromMessageIds[i] = jobSet.fromMessageId
+		terminalJobIds := make(map[string]bool)
+		for len(terminalJobIds) < len(jobSet.pendingJobIds) {
+			messages, lastMessageId, err := w.eventRepository.ReadEvents(
+				jobSet.queue, jobSet.jobSetId, fromMessageIds[i], jobWatchReadBatchSize, -1,
+			)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "error reading events of job set %s of queue %s", jobSet.jobSetId, jobSet.queue)
+			}
+			if lastMessageId == nil {
+				// No events since the previous read.
+				break
+			}
+			fromMessageIds[i] = lastMessageId.String()
+			for _, message := range messages {
+				result := jobWatchResultFromEvent(message.Message)
+				if result == nil || !jobSet.pendingJobIds[result.JobId] || terminalJobIds[result.JobId] {
+					continue
+				}
+				terminalJobIds[result.JobId] = true
+				resultsByJobSet[i] = append(resultsByJobSet[i], result)
+			}
+		}
+	}
+	var rv []*JobWatchResult
+	for i, jobSet := range subscription.jobSets {
+		jobSet.fromMessageId = fromMessageIds[i]
+		for _, result := range resultsByJobSet[i] {
+			delete(jobSet.pendingJobIds, result.JobId)
+			rv = append(rv, result)
+		}
+	}
+	return rv, nil
+}
+
+// jobWatchResultFromEvent returns the result corresponding to message if it reports a job reaching a terminal state, and nil otherwise.
+func jobWatchResultFromEvent(message *api.EventMessage) *JobWatchResult {
+	var event api.Event
+	var outcome JobWatchOutcome
+	reason := ""
+	switch e := message.GetEvents().(type) {
+	case *api.EventMessage_Succeeded:
+		event, outcome = e.Succeeded, JobWatchOutcomeSucceeded
+	case *api.EventMessage_Failed:
+		event, outcome, reason = e.Failed, JobWatchOutcomeFailed, e.Failed.Reason
+	case *api.EventMessage_Cancelled:
+		event, outcome, reason = e.Cancelled, JobWatchOutcomeCancelled, e.Cancelled.Reason
+	default:
+		return nil
+	}
+	return &JobWatchResult{
+		JobId:    event.GetJobId(),
+		Queue:    event.GetQueue(),
+		JobSetId: event.GetJobSetId(),
+		Outcome:  outcome,
+		Reason:   reason,
+		Time:     event.GetCreated(),
+	}
+}
+
+// Unsubscribe removes the subscription with the provided id.
+func (w *JobWatcher) Unsubscribe(ctx *armadacontext.Context, id string) error {
+	if _, err := w.getSubscription(ctx, id, false); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.subscriptions, id)
+	return nil
+}
+
+// JobWatchHttpHandler exposes a JobWatcher as a json http API:
+//
+//	POST   /api/v1/job/watch                    subscribes to the jobs of a JobWatchRequest, returning a JobWatchSubscription
+//	GET    /api/v1/job/watch/{id}?timeout=30s   long-polls a subscription, returning a JobWatchPollResult
+//	DELETE /api/v1/job/watch/{id}               removes a subscription
+//
+// Requests are authenticated using the same authentication services as the gRPC API.
+type JobWatchHttpHandler struct {
+	watcher      *JobWatcher
+	authServices []authorization.AuthService
+}
+
+func NewJobWatchHttpHandler(watcher *JobWatcher, authServices []authorization.AuthService) *JobWatchHttpHandler {
+	return &JobWatchHttpHandler{
+		watcher:      watcher,
+		authServices: authServices,
+	}
+}
+
+// RegisterRoutes registers the handler with mux.
+func (h *JobWatchHttpHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle(JobWatchPath, h)
+	mux.Handle(JobWatchPath+"/", h)
+}
+
+func (h *JobWatchHttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := authorization.AuthenticateHttpRequest(r, h.authServices)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	ctx := armadacontext.New(authCtx, log.NewEntry(log.StandardLogger()))
+
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, JobWatchPath), "/")
+	var rv interface{}
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		req := &JobWatchRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid job watch request: %s", err), http.StatusBadRequest)
+			return
+		}
+		rv, err = h.watcher.Subscribe(ctx, req)
+	case id != "" && r.Method == http.MethodGet:
+		var timeout time.Duration
+		if value := r.URL.Query().Get("timeout"); value != "" {
+			timeout, err = time.ParseDuration(value)
+			if err != nil || timeout < 0 {
+				http.Error(w, fmt.Sprintf("invalid timeout %q: must be a non-negative duration, e.g., \"30s\"", value), http.StatusBadRequest)
+				return
+			}
+		}
+		rv, err = h.watcher.Poll(ctx, id, timeout)
+	case id != "" && r.Method == http.MethodDelete:
+		err = h.watcher.Unsubscribe(ctx, id)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		statusCode := runtime.HTTPStatusFromCode(armadaerrors.CodeFromError(err))
+		var e *armadaerrors.ErrUnauthorized
+		if errors.As(err, &e) {
+			statusCode = http.StatusForbidden
+		}
+		if statusCode == http.StatusInternalServerError {
+			logging.WithStacktrace(ctx, err).Error("failed to serve job watch request")
+		}
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+	if rv == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rv); err != nil {
+		logging.WithStacktrace(ctx, err).Error("failed to write job watch response")
+	}
+}
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clock "k8s.io/utils/clock/testing"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/armada/repository/sequence"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/auth/authorization"
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+var testJobWatchConfig = configuration.JobWatchConfig{
+	Enabled:                true,
+	LeaseDuration:          time.Minute,
+	MaxPollTimeout:         time.Minute,
+	PollInterval:           time.Second,
+	MaxJobsPerSubscription: 3,
+	MaxSubscriptions:       2,
+}
+
+func TestJobWatcher_Poll(t *testing.T) {
+	eventRepository := &fakeJobWatchEventRepository{}
+	eventRepository.add(&api.EventMessage{Events: &api.EventMessage_Succeeded{Succeeded: &api.JobSucceededEvent{JobId: "a", JobSetId: "set", Queue: "queue"}}})
+	eventRepository.add(&api.EventMessage{Events: &api.EventMessage_Running{Running: &api.JobRunningEvent{JobId: "b", JobSetId: "set", Queue: "queue"}}})
+	eventRepository.add(&api.EventMessage{Events: &api.EventMessage_Failed{Failed: &api.JobFailedEvent{JobId: "unwatched", JobSetId: "set", Queue: "queue"}}})
+	watcher, err := NewJobWatcher(testJobWatchConfig, FakePermissionChecker{}, eventRepository, &fakeQueueRepository{})
+	require.NoError(t, err)
+	fakeClock := clock.NewFakeClock(time.Now())
+	watcher.clock = fakeClock
+	ctx := testJobWatchContext("alice")
+
+	subscription, err := watcher.Subscribe(ctx, &JobWatchRequest{
+		JobSets: []JobWatchJobSet{{Queue: "queue", JobSetId: "set", JobIds: []string{"a", "b", "c"}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, fakeClock.Now().Add(time.Minute), subscription.LeaseExpiry)
+
+	// Jobs that reached a terminal state before subscribing are returned by the first poll.
+	result, err := watcher.Poll(ctx, subscription.SubscriptionId, 0)
+	require.NoError(t, err)
+	require.Len(t, result.Jobs, 1)
+	assert.Equal(t, "a", result.Jobs[0].JobId)
+	assert.Equal(t, JobWatchOutcomeSucceeded, result.Jobs[0].Outcome)
+	assert.Equal(t, 2, result.Pending)
+	assert.False(t, result.Done)
+
+	// Only the owner of a subscription may poll it.
+	_, err = watcher.Poll(testJobWatchContext("bob"), subscription.SubscriptionId, 0)
+	var notFound *armadaerrors.ErrNotFound
+	assert.True(t, errors.As(err, &notFound))
+
+	// Polls wait for jobs to reach a terminal state.
+	done := make(chan *JobWatchPollResult)
+	go func() {
+		result, err := watcher.Poll(ctx, subscription.SubscriptionId, 30*time.Second)
+		assert.NoError(t, err)
+		done <- result
+	}()
+	require.Eventually(t, fakeClock.HasWaiters, time.Second, time.Millisecond)
+	eventRepository.add(&api.EventMessage{Events: &api.EventMessage_Failed{Failed: &api.JobFailedEvent{JobId: "b", JobSetId: "set", Queue: "queue", Reason: "oom"}}})
+	eventRepository.add(&api.EventMessage{Events: &api.EventMessage_Cancelled{Cancelled: &api.JobCancelledEvent{JobId: "c", JobSetId: "set", Queue: "queue"}}})
+	fakeClock.Step(time.Second)
+	result = <-done
+	require.Len(t, result.Jobs, 2)
+	assert.Equal(t, JobWatchOutcomeFailed, result.Jobs[0].Outcome)
+	assert.Equal(t, "oom", result.Jobs[0].Reason)
+	assert.Equal(t, JobWatchOutcomeCancelled, result.Jobs[1].Outcome)
+	assert.True(t, result.Done)
+
+	// Subscriptions are removed once done.
+	_, err = watcher.Poll(ctx, subscription.SubscriptionId, 0)
+	assert.True(t, errors.As(err, &notFound))
+}
+
+func TestJobWatcher_PollReadError(t *testing.T) {
+	eventRepository := &fakeJobWatchEventRepository{}
+	eventRepository.add(&api.EventMessage{Events: &api.EventMessage_Succeeded{Succeeded: &api.JobSucceededEvent{JobId: "a", JobSetId: "set", Queue: "queue"}}})
+	watcher, err := NewJobWatcher(testJobWatchConfig, FakePermissionChecker{}, eventRepository, &fakeQueueRepository{})
+	require.NoError(t, err)
+	ctx := testJobWatchContext("alice")
+	subscription, err := watcher.Subscribe(ctx, &JobWatchRequest{
+		JobSets: []JobWatchJobSet{
+			{Queue: "queue", JobSetId: "set", JobIds: []string{"a", "b"}},
+			{Queue: "queue", JobSetId: "otherSet", JobIds: []string{"c"}},
+		},
+	})
+	require.NoError(t, err)
+
+	// Jobs found to have reached a terminal state before reading another job set failed are still pending.
+	eventRepository.setErr("otherSet", errors.New("event repository unavailable"))
+	_, err = watcher.Poll(ctx, subscription.SubscriptionId, 0)
+	assert.Error(t, err)
+
+	// Hence, they're returned by the next poll.
+	eventRepository.setErr("otherSet", nil)
+	result, err := watcher.Poll(ctx, subscription.SubscriptionId, 0)
+	require.NoError(t, err)
+	require.Len(t, result.Jobs, 1)
+	assert.Equal(t, "a", result.Jobs[0].JobId)
+	assert.Equal(t, 2, result.Pending)
+	assert.False(t, result.Done)
+}
+
+func TestNewJobWatcher_InvalidPollInterval(t *testing.T) {
+	config := testJobWatchConfig
+	config.PollInterval = 0
+	_, err := NewJobWatcher(config, FakePermissionChecker{}, &fakeJobWatchEventRepository{}, &fakeQueueRepository{})
+	assert.Error(t, err)
+}
+
+func TestJobWatcher_Subscribe(t *testing.T) {
+	watcher, err := NewJobWatcher(testJobWatchConfig, FakePermissionChecker{}, &fakeJobWatchEventRepository{}, &fakeQueueRepository{})
+	require.NoError(t, err)
+	fakeClock := clock.NewFakeClock(time.Now())
+	watcher.clock = fakeClock
+	ctx := testJobWatchContext("alice")
+	request := func(jobIds ...string) *JobWatchRequest {
+		return &JobWatchRequest{JobSets: []JobWatchJobSet{{Queue: "queue", JobSetId: "set", JobIds: jobIds}}}
+	}
+	var invalidArgument *armadaerrors.ErrInvalidArgument
+	var rateLimited *armadaerrors.ErrRateLimited
+	var notFound *armadaerrors.ErrNotFound
+
+	_, err = watcher.Subscribe(ctx, request())
+	assert.True(t, errors.As(err, &invalidArgument))
+	_, err = watcher.Subscribe(ctx, request("a", "b", "c", "d"))
+	assert.True(t, errors.As(err, &invalidArgument))
+
+	first, err := watcher.Subscribe(ctx, request("a", "a", "b", "c"))
+	require.NoError(t, err)
+	_, err = watcher.Subscribe(ctx, request("a"))
+	require.NoError(t, err)
+	_, err = watcher.Subscribe(ctx, request("a"))
+	assert.True(t, errors.As(err, &rateLimited))
+
+	// Unsubscribing frees up capacity for new subscriptions.
+	require.NoError(t, watcher.Unsubscribe(ctx, first.SubscriptionId))
+	_, err = watcher.Subscribe(ctx, request("a"))
+	require.NoError(t, err)
+
+	// As does subscriptions expiring.
+	fakeClock.Step(time.Minute)
+	_, err = watcher.Subscribe(ctx, request("a"))
+	require.NoError(t, err)
+	_, err = watcher.Poll(ctx, first.SubscriptionId, 0)
+	assert.True(t, errors.As(err, &notFound))
+}
+
+func TestJobWatchHttpHandler(t *testing.T) {
+	eventRepository := &fakeJobWatchEventRepository{}
+	eventRepository.add(&api.EventMessage{Events: &api.EventMessage_Succeeded{Succeeded: &api.JobSucceededEvent{JobId: "a", JobSetId: "set", Queue: "queue"}}})
+	watcher, err := NewJobWatcher(testJobWatchConfig, FakePermissionChecker{}, eventRepository, &fakeQueueRepository{})
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	NewJobWatchHttpHandler(watcher, []authorization.AuthService{&authorization.AnonymousAuthService{}}).RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, JobWatchPath, strings.NewReader(`{"jobSets": [{"queue": "queue", "jobSetId": "set", "jobIds": ["a", "b"]}]}`)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	subscription := &JobWatchSubscription{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(subscription))
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, JobWatchPath+"/"+subscription.SubscriptionId+"?timeout=1s", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	result := &JobWatchPollResult{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(result))
+	require.Len(t, result.Jobs, 1)
+	assert.Equal(t, "a", result.Jobs[0].JobId)
+	assert.Equal(t, 1, result.Pending)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, JobWatchPath+"/"+subscription.SubscriptionId+"?timeout=soon", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, JobWatchPath+"/"+subscription.SubscriptionId, nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, JobWatchPath+"/"+subscription.SubscriptionId, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func testJobWatchContext(principal string) *armadacontext.Context {
+	return armadacontext.New(
+		authorization.WithPrincipal(armadacontext.Background(), authorization.NewStaticPrincipal(principal, nil)),
+		logrus.NewEntry(logrus.StandardLogger()),
+	)
+}
+
+// fakeJobWatchEventRepository stores the events of a single job set, with the i-th event having sequence number i+1.
+// The same events are returned for every job set, other than those for which reading fails.
+type fakeJobWatchEventRepository struct {
+	mu     sync.Mutex
+	events []*api.EventMessage
+	// Error returned when reading the events of each job set, if any.
+	errByJobSetId map[string]error
+}
+
+func (r *fakeJobWatchEventRepository) add(event *api.EventMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *fakeJobWatchEventRepository) setErr(jobSetId string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.errByJobSetId == nil {
+		r.errByJobSetId = make(map[string]error)
+	}
+	r.errByJobSetId[jobSetId] = err
+}
+
+func (r *fakeJobWatchEventRepository) CheckStreamExists(_ string, _ string) (bool, error) {
+	return true, nil
+}
+
+func (r *fakeJobWatchEventRepository) ReadEvents(_, jobSetId string, lastId string, limit int64, _ time.Duration) ([]*api.EventStreamMessage, *sequence.ExternalSeqNo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.errByJobSetId[jobSetId]; err != nil {
+		return nil, nil, err
+	}
+	from, err := sequence.Parse(lastId)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rv []*api.EventStreamMessage
+	var lastMessageId *sequence.ExternalSeqNo
+	for i := int(from.Time); i < len(r.events) && int64(len(rv)) < limit; i++ {
+		lastMessageId = &sequence.ExternalSeqNo{Time: int64(i + 1), Last: true}
+		rv = append(rv, &api.EventStreamMessage{Id: lastMessageId.String(), Message: r.events[i]})
+	}
+	return rv, lastMessageId, nil
+}
+
+func (r *fakeJobWatchEventRepository) GetLastMessageId(_, _ string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return (&sequence.ExternalSeqNo{Time: int64(len(r.events)), Last: true}).String(), nil
+}
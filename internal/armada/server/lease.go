@@ -328,6 +328,10 @@ func (q *AggregatedQueueServer) getJobs(ctx *armadacontext.Context, req *api.Str
 	if err != nil {
 		return nil, err
 	}
+	nodeDb.SetNodeReservedResources(q.schedulingConfig.NodeReservedResources, q.schedulingConfig.NodeReservedResourceFractions)
+	nodeDb.SetExternalWorkloadCoexistence(q.schedulingConfig.EnableExternalWorkloadCoexistence)
+	nodeDb.SetMaxExtraNodesToConsiderForPreferences(q.schedulingConfig.MaxExtraNodesToConsiderForPreferences)
+	nodeDb.SetPerNodeJobLimits(q.schedulingConfig.MaxJobsPerNode, q.schedulingConfig.MaxGangMembersPerNode)
 	txn := nodeDb.Txn(true)
 	defer txn.Abort()
 
@@ -522,6 +526,7 @@ func (q *AggregatedQueueServer) getJobs(ctx *armadacontext.Context, req *api.Str
 		if err := sctx.AddQueueSchedulingContext(queue, weight, allocatedByQueueAndPriorityClassForPool[queue], queueLimiter); err != nil {
 			return nil, err
 		}
+		sctx.QueueSchedulingContexts[queue].IntraQueueOrdering = q.schedulingConfig.IntraQueueOrderingForQueue(queue)
 	}
 	constraints := schedulerconstraints.SchedulingConstraintsFromSchedulingConfig(
 		req.Pool,
@@ -533,7 +538,7 @@ func (q *AggregatedQueueServer) getJobs(ctx *armadacontext.Context, req *api.Str
 		sctx,
 		constraints,
 		q.schedulingConfig.Preemption.NodeEvictionProbability,
-		q.schedulingConfig.Preemption.NodeOversubscriptionEvictionProbability,
+		q.schedulingConfig.EffectiveNodeOversubscriptionEvictionProbability(),
 		q.schedulingConfig.Preemption.ProtectedFractionOfFairShare,
 		&SchedulerJobRepositoryAdapter{
 			r: q.jobRepository,
@@ -552,6 +557,8 @@ func (q *AggregatedQueueServer) getJobs(ctx *armadacontext.Context, req *api.Str
 	if q.schedulingConfig.EnableNewPreemptionStrategy {
 		sch.EnableNewPreemptionStrategy()
 	}
+	sch.SetMaxNodeUniformityLabelValuesToConsider(q.schedulingConfig.MaxNodeUniformityLabelValuesToConsiderForPool(req.Pool))
+	sch.SetGangPlacementTieBreak(q.schedulingConfig.GangPlacementTieBreak)
 	log.Infof(
 		"starting scheduling with total resources %s",
 		schedulerobjects.ResourceList{Resources: totalCapacity}.CompactString(),
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+// poolMinimumJobSizeWarnings returns a warning for each job targeting a pool via PoolAnnotation
+// that requests less of any resource than the minimum job size of that pool, since such jobs are never scheduled.
+func poolMinimumJobSizeWarnings(jobs []*api.Job, config configuration.SchedulingConfig) []string {
+	if len(config.MinimumJobSizeByPool) == 0 {
+		return nil
+	}
+	var warnings []string
+	for _, job := range jobs {
+		pool := job.Annotations[configuration.PoolAnnotation]
+		minimumJobSize := config.MinimumJobSizeByPool[pool]
+		if pool == "" || len(minimumJobSize) == 0 {
+			continue
+		}
+		requests := job.TotalResourceRequest()
+		resourceNames := maps.Keys(minimumJobSize)
+		slices.Sort(resourceNames)
+		for _, t := range resourceNames {
+			minQuantity := minimumJobSize[t]
+			q := requests[t]
+			if minQuantity.Cmp(q) == 1 {
+				warnings = append(warnings, fmt.Sprintf(
+					"job %s requests %s %s, but the minimum for pool %s is %s; it will not be scheduled",
+					job.Id, q.String(), t, pool, minQuantity.String(),
+				))
+			}
+		}
+	}
+	return warnings
+}
+
+// warnOfJobsBelowPoolMinimumJobSize returns the warnings of poolMinimumJobSizeWarnings to the client via a response header.
+func warnOfJobsBelowPoolMinimumJobSize(ctx context.Context, jobs []*api.Job, config configuration.SchedulingConfig) {
+	warnings := poolMinimumJobSizeWarnings(jobs, config)
+	if len(warnings) == 0 {
+		return
+	}
+	md := metadata.MD{}
+	md.Append(api.SubmitWarningsHeader, warnings...)
+	// Fails only if there's no grpc stream associated with ctx, e.g., in tests.
+	_ = grpc.SetHeader(ctx, md)
+}
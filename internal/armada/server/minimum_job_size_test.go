@@ -0,0 +1,49 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+func TestPoolMinimumJobSizeWarnings(t *testing.T) {
+	config := configuration.SchedulingConfig{
+		MinimumJobSizeByPool: map[string]map[string]resource.Quantity{
+			"gpu":     {"nvidia.com/gpu": resource.MustParse("1")},
+			"highmem": {"memory": resource.MustParse("32Gi")},
+		},
+	}
+	newJob := func(id string, pool string, requests v1.ResourceList) *api.Job {
+		job := &api.Job{
+			Id: id,
+			PodSpec: &v1.PodSpec{
+				Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: requests}}},
+			},
+		}
+		if pool != "" {
+			job.Annotations = map[string]string{configuration.PoolAnnotation: pool}
+		}
+		return job
+	}
+	jobs := []*api.Job{
+		newJob("cpu-job-in-gpu-pool", "gpu", v1.ResourceList{"cpu": resource.MustParse("1")}),
+		newJob("gpu-job-in-gpu-pool", "gpu", v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}),
+		newJob("small-job-in-highmem-pool", "highmem", v1.ResourceList{"memory": resource.MustParse("1Gi")}),
+		newJob("small-job-without-pool", "", v1.ResourceList{"memory": resource.MustParse("1Gi")}),
+		newJob("small-job-in-other-pool", "cpu", v1.ResourceList{"memory": resource.MustParse("1Gi")}),
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"job cpu-job-in-gpu-pool requests 0 nvidia.com/gpu, but the minimum for pool gpu is 1; it will not be scheduled",
+			"job small-job-in-highmem-pool requests 1Gi memory, but the minimum for pool highmem is 32Gi; it will not be scheduled",
+		},
+		poolMinimumJobSizeWarnings(jobs, config),
+	)
+	assert.Empty(t, poolMinimumJobSizeWarnings(jobs, configuration.SchedulingConfig{}))
+}
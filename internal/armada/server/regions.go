@@ -0,0 +1,294 @@
+package server
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+const (
+	// forwardedFromRegionHeader is set on requests forwarded to another region, to the region they were forwarded from.
+	// Forwarded requests aren't forwarded again, such that inconsistent region configs can't cause requests to loop.
+	forwardedFromRegionHeader = "armada-forwarded-from-region"
+	// Weight of the most recent observation in the moving average of the latency of each endpoint.
+	regionEndpointLatencySmoothing = 0.2
+	// Latency recorded for endpoints that are unavailable, such that other endpoints are preferred.
+	unavailableRegionEndpointLatency = time.Minute
+)
+
+// RegionRouter determines which region each queue is homed to and provides clients for the control planes of other regions.
+type RegionRouter struct {
+	config configuration.RegionsConfig
+	// Endpoints of the control plane of each remote region.
+	endpointsByRegion map[string][]*regionEndpoint
+	clock             clock.Clock
+	mu                sync.Mutex
+}
+
+// regionEndpoint is an endpoint of the control plane of a remote region, along with its observed latency.
+type regionEndpoint struct {
+	url          string
+	submitClient api.SubmitClient
+	eventClient  api.EventClient
+	// Exponentially weighted moving average of the latency of requests to this endpoint.
+	// Zero if no requests have been sent yet, in which case the endpoint is preferred, such that all endpoints are tried.
+	latency time.Duration
+}
+
+func NewRegionRouter(config configuration.RegionsConfig) *RegionRouter {
+	return &RegionRouter{
+		config:            config,
+		endpointsByRegion: make(map[string][]*regionEndpoint),
+		clock:             clock.RealClock{},
+	}
+}
+
+// AddEndpoint adds an endpoint of the control plane of a remote region.
+func (r *RegionRouter) AddEndpoint(region string, url string, submitClient api.SubmitClient, eventClient api.EventClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.endpointsByRegion[region] = append(r.endpointsByRegion[region], &regionEndpoint{
+		url:          url,
+		submitClient: submitClient,
+		eventClient:  eventClient,
+	})
+}
+
+// remoteRegion returns the region the queue is homed to if that's not the local region, and otherwise the empty string.
+// Returns an error if the queue is homed to a remote region but the request was already forwarded from another region.
+func (r *RegionRouter) remoteRegion(ctx context.Context, queue string) (string, error) {
+	region := r.config.HomeRegion(queue)
+	if region == "" || region == r.config.LocalRegion {
+		return "", nil
+	}
+	if from := forwardedFromRegion(ctx); from != "" {
+		return "", status.Errorf(
+			codes.FailedPrecondition,
+			"queue %s is homed to region %s, but region %s forwarded the request to region %s",
+			queue, region, from, r.config.LocalRegion,
+		)
+	}
+	return region, nil
+}
+
+// endpoint returns the endpoint of the control plane of the region with the lowest observed latency.
+func (r *RegionRouter) endpoint(region string) (*regionEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rv *regionEndpoint
+	for _, endpoint := range r.endpointsByRegion[region] {
+		if rv == nil || endpoint.latency < rv.latency {
+			rv = endpoint
+		}
+	}
+	if rv == nil {
+		return nil, status.Errorf(codes.Unavailable, "no endpoints configured for region %s", region)
+	}
+	return rv, nil
+}
+
+// observe updates the latency of the endpoint with that of a request that started at the given time.
+func (r *RegionRouter) observe(endpoint *regionEndpoint, start time.Time, err error) {
+	latency := r.clock.Since(start)
+	if status.Code(err) == codes.Unavailable || status.Code(err) == codes.DeadlineExceeded {
+		latency = unavailableRegionEndpointLatency
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if endpoint.latency == 0 {
+		endpoint.latency = latency
+	} else {
+		endpoint.latency = time.Duration(regionEndpointLatencySmoothing*float64(latency) + (1-regionEndpointLatencySmoothing)*float64(endpoint.latency))
+	}
+}
+
+// forwardingContext returns a context for forwarding the request from the local region,
+// which carries the credentials and other metadata of the original request.
+func (r *RegionRouter) forwardingContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	md.Set(forwardedFromRegionHeader, r.config.LocalRegion)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func forwardedFromRegion(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(forwardedFromRegionHeader); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// forward calls f with the endpoint of the given region with the lowest latency and records the latency of the call.
+func forward[T any](ctx context.Context, r *RegionRouter, region string, f func(context.Context, *regionEndpoint) (T, error)) (T, error) {
+	endpoint, err := r.endpoint(region)
+	if err != nil {
+		var rv T
+		return rv, err
+	}
+	start := r.clock.Now()
+	rv, err := f(r.forwardingContext(ctx), endpoint)
+	r.observe(endpoint, start, err)
+	if err != nil {
+		log.WithError(err).Warnf("failed to forward request to region %s via %s", region, endpoint.url)
+	}
+	return rv, err
+}
+
+// RegionForwardingSubmitServer forwards job submissions, cancellations, and reprioritisations for queues homed to
+// another region to the control plane of that region; all other requests are handled by the local SubmitServer.
+type RegionForwardingSubmitServer struct {
+	api.SubmitServer
+	router *RegionRouter
+}
+
+func NewRegionForwardingSubmitServer(submitServer api.SubmitServer, router *RegionRouter) *RegionForwardingSubmitServer {
+	return &RegionForwardingSubmitServer{
+		SubmitServer: submitServer,
+		router:       router,
+	}
+}
+
+func (s *RegionForwardingSubmitServer) SubmitJobs(ctx context.Context, req *api.JobSubmitRequest) (*api.JobSubmitResponse, error) {
+	region, err := s.router.remoteRegion(ctx, req.Queue)
+	if err != nil {
+		return nil, err
+	}
+	if region == "" {
+		return s.SubmitServer.SubmitJobs(ctx, req)
+	}
+	return forward(ctx, s.router, region, func(ctx context.Context, endpoint *regionEndpoint) (*api.JobSubmitResponse, error) {
+		return endpoint.submitClient.SubmitJobs(ctx, req)
+	})
+}
+
+// CancelJobs forwards the request if it names a queue homed to another region.
+// Requests naming only a job id are handled locally.
+func (s *RegionForwardingSubmitServer) CancelJobs(ctx context.Context, req *api.JobCancelRequest) (*api.CancellationResult, error) {
+	region, err := s.router.remoteRegion(ctx, req.Queue)
+	if err != nil {
+		return nil, err
+	}
+	if region == "" {
+		return s.SubmitServer.CancelJobs(ctx, req)
+	}
+	return forward(ctx, s.router, region, func(ctx context.Context, endpoint *regionEndpoint) (*api.CancellationResult, error) {
+		return endpoint.submitClient.CancelJobs(ctx, req)
+	})
+}
+
+func (s *RegionForwardingSubmitServer) CancelJobSet(ctx context.Context, req *api.JobSetCancelRequest) (*types.Empty, error) {
+	region, err := s.router.remoteRegion(ctx, req.Queue)
+	if err != nil {
+		return nil, err
+	}
+	if region == "" {
+		return s.SubmitServer.CancelJobSet(ctx, req)
+	}
+	return forward(ctx, s.router, region, func(ctx context.Context, endpoint *regionEndpoint) (*types.Empty, error) {
+		return endpoint.submitClient.CancelJobSet(ctx, req)
+	})
+}
+
+// ReprioritizeJobs forwards the request if it names a queue homed to another region.
+// Requests naming only job ids are handled locally.
+func (s *RegionForwardingSubmitServer) ReprioritizeJobs(ctx context.Context, req *api.JobReprioritizeRequest) (*api.JobReprioritizeResponse, error) {
+	region, err := s.router.remoteRegion(ctx, req.Queue)
+	if err != nil {
+		return nil, err
+	}
+	if region == "" {
+		return s.SubmitServer.ReprioritizeJobs(ctx, req)
+	}
+	return forward(ctx, s.router, region, func(ctx context.Context, endpoint *regionEndpoint) (*api.JobReprioritizeResponse, error) {
+		return endpoint.submitClient.ReprioritizeJobs(ctx, req)
+	})
+}
+
+// RegionForwardingEventServer streams events of job sets of queues homed to another region from the control plane of
+// that region; all other requests are handled by the local EventServer.
+type RegionForwardingEventServer struct {
+	api.EventServer
+	router *RegionRouter
+}
+
+func NewRegionForwardingEventServer(eventServer api.EventServer, router *RegionRouter) *RegionForwardingEventServer {
+	return &RegionForwardingEventServer{
+		EventServer: eventServer,
+		router:      router,
+	}
+}
+
+func (s *RegionForwardingEventServer) GetJobSetEvents(req *api.JobSetRequest, stream api.Event_GetJobSetEventsServer) error {
+	region, err := s.router.remoteRegion(stream.Context(), req.Queue)
+	if err != nil {
+		return err
+	}
+	if region == "" {
+		return s.EventServer.GetJobSetEvents(req, stream)
+	}
+	// The latency observed is that of opening the stream, since the stream itself may stay open indefinitely.
+	client, err := forward(stream.Context(), s.router, region, func(ctx context.Context, endpoint *regionEndpoint) (api.Event_GetJobSetEventsClient, error) {
+		return endpoint.eventClient.GetJobSetEvents(ctx, req)
+	})
+	if err != nil {
+		return err
+	}
+	return proxyEvents(client, stream)
+}
+
+func (s *RegionForwardingEventServer) Watch(req *api.WatchRequest, stream api.Event_WatchServer) error {
+	region, err := s.router.remoteRegion(stream.Context(), req.Queue)
+	if err != nil {
+		return err
+	}
+	if region == "" {
+		return s.EventServer.Watch(req, stream)
+	}
+	client, err := forward(stream.Context(), s.router, region, func(ctx context.Context, endpoint *regionEndpoint) (api.Event_WatchClient, error) {
+		return endpoint.eventClient.Watch(ctx, req)
+	})
+	if err != nil {
+		return err
+	}
+	return proxyEvents(client, stream)
+}
+
+// eventStreamClient and eventStreamServer are the client and server sides of the event streams of the EventServer.
+type eventStreamClient interface {
+	Recv() (*api.EventStreamMessage, error)
+}
+
+type eventStreamServer interface {
+	Send(*api.EventStreamMessage) error
+}
+
+// proxyEvents sends all events received from client on stream until the client stream ends.
+func proxyEvents(client eventStreamClient, stream eventStreamServer) error {
+	for {
+		msg, err := client.Recv()
+		if errors.Is(err, io.EOF) || status.Code(err) == codes.Canceled {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+var testRegionsConfig = configuration.RegionsConfig{
+	LocalRegion: "eu",
+	QueueHomeRegions: map[string]string{
+		"eu-queue": "eu",
+		"us-queue": "us",
+	},
+}
+
+func TestRegionForwardingSubmitServer_SubmitJobs(t *testing.T) {
+	local := &fakeSubmitServer{}
+	remote := &fakeSubmitClient{}
+	router := NewRegionRouter(testRegionsConfig)
+	router.AddEndpoint("us", "us.example.com", remote, nil)
+	s := NewRegionForwardingSubmitServer(local, router)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+
+	// Queues homed to the local region, or not homed anywhere in particular, are handled locally.
+	for _, queue := range []string{"eu-queue", "other-queue"} {
+		_, err := s.SubmitJobs(ctx, &api.JobSubmitRequest{Queue: queue})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"eu-queue", "other-queue"}, local.queues)
+	assert.Empty(t, remote.queues)
+
+	// Requests for queues homed to another region are forwarded along with the credentials of the caller.
+	_, err := s.SubmitJobs(ctx, &api.JobSubmitRequest{Queue: "us-queue"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"us-queue"}, remote.queues)
+	if assert.Len(t, remote.metadata, 1) {
+		assert.Equal(t, []string{"Bearer token"}, remote.metadata[0].Get("authorization"))
+		assert.Equal(t, []string{"eu"}, remote.metadata[0].Get(forwardedFromRegionHeader))
+	}
+
+	// Requests already forwarded aren't forwarded again.
+	forwardedCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(forwardedFromRegionHeader, "us"))
+	_, err = s.SubmitJobs(forwardedCtx, &api.JobSubmitRequest{Queue: "us-queue"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Len(t, remote.queues, 1)
+}
+
+func TestRegionRouter_PrefersLowestLatencyEndpoint(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	router := NewRegionRouter(testRegionsConfig)
+	router.clock = fakeClock
+	slow := &fakeSubmitClient{clock: fakeClock, latency: time.Second}
+	fast := &fakeSubmitClient{clock: fakeClock, latency: 10 * time.Millisecond}
+	unavailable := &fakeSubmitClient{err: status.Error(codes.Unavailable, "unavailable")}
+	router.AddEndpoint("us", "slow", slow, nil)
+	router.AddEndpoint("us", "fast", fast, nil)
+	router.AddEndpoint("us", "unavailable", unavailable, nil)
+	s := NewRegionForwardingSubmitServer(&fakeSubmitServer{}, router)
+
+	// Each endpoint is tried once, after which the endpoint with the lowest latency is used.
+	for i := 0; i < 5; i++ {
+		_, _ = s.SubmitJobs(context.Background(), &api.JobSubmitRequest{Queue: "us-queue"})
+	}
+	assert.Len(t, slow.queues, 1)
+	assert.Len(t, unavailable.queues, 1)
+	assert.Len(t, fast.queues, 3)
+}
+
+type fakeSubmitServer struct {
+	api.SubmitServer
+	queues []string
+}
+
+func (s *fakeSubmitServer) SubmitJobs(_ context.Context, req *api.JobSubmitRequest) (*api.JobSubmitResponse, error) {
+	s.queues = append(s.queues, req.Queue)
+	return &api.JobSubmitResponse{}, nil
+}
+
+type fakeSubmitClient struct {
+	api.SubmitClient
+	clock    *clock.FakeClock
+	latency  time.Duration
+	err      error
+	queues   []string
+	metadata []metadata.MD
+}
+
+func (c *fakeSubmitClient) SubmitJobs(ctx context.Context, req *api.JobSubmitRequest, _ ...grpc.CallOption) (*api.JobSubmitResponse, error) {
+	c.queues = append(c.queues, req.Queue)
+	md, _ := metadata.FromOutgoingContext(ctx)
+	c.metadata = append(c.metadata, md)
+	if c.clock != nil {
+		c.clock.Step(c.latency)
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &api.JobSubmitResponse{}, nil
+}
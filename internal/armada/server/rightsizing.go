@@ -0,0 +1,143 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/logging"
+	"github.com/armadaproject/armada/internal/common/rightsizing"
+)
+
+// Rightsizer lowers the cpu and memory requests of jobs that opt in via the auto-rightsize annotation
+// to those recommended by Lookout based on the resources used by previous runs of the same template.
+// Requests are never increased, and jobs without a recommendation for their template are left unchanged.
+type Rightsizer struct {
+	url    string
+	client *http.Client
+	// Most recently fetched recommendations, indexed by queue and then template.
+	recommendations map[string]map[string]*rightsizing.Recommendation
+	mu              sync.RWMutex
+}
+
+func NewRightsizer(url string, timeout time.Duration) *Rightsizer {
+	return &Rightsizer{
+		url:             url,
+		client:          &http.Client{Timeout: timeout},
+		recommendations: make(map[string]map[string]*rightsizing.Recommendation),
+	}
+}
+
+// Run refreshes recommendations every interval until the provided context is cancelled.
+func (r *Rightsizer) Run(ctx *armadacontext.Context, interval time.Duration) error {
+	log := logrus.StandardLogger().WithField("service", "Rightsizer")
+	log.Info("service started")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Refresh(ctx); err != nil {
+			logging.WithStacktrace(log, err).Warn("failed to refresh rightsizing recommendations")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh replaces the recommendations of r with those currently served by Lookout.
+func (r *Rightsizer) Refresh(ctx *armadacontext.Context) error {
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	resp, err := r.client.Do(httpRequest)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("rightsizing api %s returned status %s", r.url, resp.Status)
+	}
+	var response struct {
+		Recommendations []*rightsizing.Recommendation `json:"recommendations"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return errors.WithStack(err)
+	}
+	recommendations := make(map[string]map[string]*rightsizing.Recommendation)
+	for _, recommendation := range response.Recommendations {
+		if recommendations[recommendation.Queue] == nil {
+			recommendations[recommendation.Queue] = make(map[string]*rightsizing.Recommendation)
+		}
+		recommendations[recommendation.Queue][recommendation.Template] = recommendation
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recommendations = recommendations
+	return nil
+}
+
+// Rightsize lowers the requests and limits of the containers of podSpec to the recommendation for the template
+// the job is an instance of, if the job has opted in to rightsizing. Returns true if podSpec was modified.
+// If the pod has several containers, their requests are lowered proportionally.
+func (r *Rightsizer) Rightsize(queue string, jobSet string, annotations map[string]string, podSpec *v1.PodSpec) bool {
+	if annotations[configuration.AutoRightsizeAnnotation] != "true" {
+		return false
+	}
+	r.mu.RLock()
+	recommendation, ok := r.recommendations[queue][rightsizing.TemplateKey(jobSet, annotations)]
+	r.mu.RUnlock()
+	if !ok {
+		return false
+	}
+	cpuLowered := lowerRequests(podSpec.Containers, v1.ResourceCPU, recommendation.RecommendedCpu, func(q resource.Quantity) int64 {
+		return q.MilliValue()
+	}, func(v int64) resource.Quantity {
+		return *resource.NewMilliQuantity(v, resource.DecimalSI)
+	})
+	memoryLowered := lowerRequests(podSpec.Containers, v1.ResourceMemory, recommendation.RecommendedMemory, func(q resource.Quantity) int64 {
+		return q.Value()
+	}, func(v int64) resource.Quantity {
+		return *resource.NewQuantity(v, resource.BinarySI)
+	})
+	return cpuLowered || memoryLowered
+}
+
+// lowerRequests scales the requests and limits for resourceName of all containers such that the total request is recommended.
+// Containers are left unchanged if their total request is already at most recommended.
+func lowerRequests(
+	containers []v1.Container,
+	resourceName v1.ResourceName,
+	recommended int64,
+	toInt func(resource.Quantity) int64,
+	fromInt func(int64) resource.Quantity,
+) bool {
+	var total int64
+	for _, container := range containers {
+		if q, ok := container.Resources.Requests[resourceName]; ok {
+			total += toInt(q)
+		}
+	}
+	if recommended <= 0 || total <= recommended {
+		return false
+	}
+	ratio := float64(recommended) / float64(total)
+	for _, container := range containers {
+		for _, resources := range []v1.ResourceList{container.Resources.Requests, container.Resources.Limits} {
+			if q, ok := resources[resourceName]; ok {
+				resources[resourceName] = fromInt(int64(float64(toInt(q)) * ratio))
+			}
+		}
+	}
+	return true
+}
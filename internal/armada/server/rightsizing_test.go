@@ -0,0 +1,108 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+func TestRightsizer(t *testing.T) {
+	lookout := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"recommendations": [
+			{"queue": "queue-a", "template": "my-template", "recommendedCpu": 1000, "recommendedMemory": 1073741824},
+			{"queue": "queue-a", "template": "job-set", "recommendedCpu": 8000, "recommendedMemory": 8589934592}
+		]}`))
+	}))
+	defer lookout.Close()
+	rightsizer := NewRightsizer(lookout.URL, time.Second)
+	require.NoError(t, rightsizer.Refresh(armadacontext.Background()))
+
+	tests := map[string]struct {
+		queue            string
+		annotations      map[string]string
+		containers       []v1.Container
+		expectedModified bool
+		expected         []v1.Container
+	}{
+		"lowered": {
+			queue:            "queue-a",
+			annotations:      map[string]string{configuration.AutoRightsizeAnnotation: "true", configuration.JobTemplateAnnotation: "my-template"},
+			containers:       []v1.Container{container("2", "4Gi")},
+			expectedModified: true,
+			expected:         []v1.Container{container("1", "1Gi")},
+		},
+		"lowered proportionally": {
+			queue:            "queue-a",
+			annotations:      map[string]string{configuration.AutoRightsizeAnnotation: "true", configuration.JobTemplateAnnotation: "my-template"},
+			containers:       []v1.Container{container("3", "2Gi"), container("1", "2Gi")},
+			expectedModified: true,
+			expected:         []v1.Container{container("750m", "512Mi"), container("250m", "512Mi")},
+		},
+		"never increased": {
+			queue:       "queue-a",
+			annotations: map[string]string{configuration.AutoRightsizeAnnotation: "true"},
+			containers:  []v1.Container{container("2", "4Gi")},
+			expected:    []v1.Container{container("2", "4Gi")},
+		},
+		"not opted in": {
+			queue:       "queue-a",
+			annotations: map[string]string{configuration.JobTemplateAnnotation: "my-template"},
+			containers:  []v1.Container{container("2", "4Gi")},
+			expected:    []v1.Container{container("2", "4Gi")},
+		},
+		"no recommendation": {
+			queue:       "queue-b",
+			annotations: map[string]string{configuration.AutoRightsizeAnnotation: "true", configuration.JobTemplateAnnotation: "my-template"},
+			containers:  []v1.Container{container("2", "4Gi")},
+			expected:    []v1.Container{container("2", "4Gi")},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			podSpec := &v1.PodSpec{Containers: tc.containers}
+			modified := rightsizer.Rightsize(tc.queue, "job-set", tc.annotations, podSpec)
+			assert.Equal(t, tc.expectedModified, modified)
+			require.Len(t, podSpec.Containers, len(tc.expected))
+			for i, expected := range tc.expected {
+				for _, resourceName := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+					assertQuantityEqual(t, expected.Resources.Requests[resourceName], podSpec.Containers[i].Resources.Requests[resourceName])
+					assertQuantityEqual(t, expected.Resources.Limits[resourceName], podSpec.Containers[i].Resources.Limits[resourceName])
+				}
+			}
+		})
+	}
+}
+
+func TestRightsizer_RefreshError(t *testing.T) {
+	lookout := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer lookout.Close()
+	assert.Error(t, NewRightsizer(lookout.URL, time.Second).Refresh(armadacontext.Background()))
+}
+
+func container(cpu string, memory string) v1.Container {
+	resources := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse(cpu),
+		v1.ResourceMemory: resource.MustParse(memory),
+	}
+	return v1.Container{
+		Resources: v1.ResourceRequirements{
+			Requests: resources,
+			Limits:   resources.DeepCopy(),
+		},
+	}
+}
+
+func assertQuantityEqual(t *testing.T, expected resource.Quantity, actual resource.Quantity) {
+	assert.True(t, expected.Equal(actual), "expected %s, but got %s", expected.String(), actual.String())
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/armadaproject/armada/internal/armada/permissions"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/auth/authorization"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// SchedulerAdminServer forwards admin requests to the scheduler, provided the caller has the required permissions.
+type SchedulerAdminServer struct {
+	permissions authorization.PermissionChecker
+	client      schedulerobjects.SchedulerAdminClient
+}
+
+func NewSchedulerAdminServer(permissions authorization.PermissionChecker, client schedulerobjects.SchedulerAdminClient) *SchedulerAdminServer {
+	return &SchedulerAdminServer{
+		permissions: permissions,
+		client:      client,
+	}
+}
+
+func (s *SchedulerAdminServer) TriggerSchedulingRound(grpcCtx context.Context, req *schedulerobjects.TriggerSchedulingRoundRequest) (*schedulerobjects.TriggerSchedulingRoundResponse, error) {
+	ctx := armadacontext.FromGrpcCtx(grpcCtx)
+	if err := checkPermission(s.permissions, ctx, permissions.TriggerSchedulingRounds); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "[TriggerSchedulingRound] error: %s", err)
+	}
+	ctx.Infof("%s triggered a scheduling round for pool %q and queue %q", authorization.GetPrincipal(ctx).GetName(), req.Pool, req.Queue)
+	return s.client.TriggerSchedulingRound(ctx, req)
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/auth/authorization"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+func TestSchedulerAdminServer_TriggerSchedulingRound(t *testing.T) {
+	tests := map[string]struct {
+		permissions     authorization.PermissionChecker
+		expectForwarded bool
+	}{
+		"permitted": {
+			permissions:     FakePermissionChecker{},
+			expectForwarded: true,
+		},
+		"not permitted": {
+			permissions: FakeDenyAllPermissionChecker{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := &fakeSchedulerAdminClient{response: &schedulerobjects.TriggerSchedulingRoundResponse{Report: "report"}}
+			server := NewSchedulerAdminServer(tc.permissions, client)
+			req := &schedulerobjects.TriggerSchedulingRoundRequest{Pool: "cpu"}
+
+			resp, err := server.TriggerSchedulingRound(armadacontext.Background(), req)
+			if tc.expectForwarded {
+				require.NoError(t, err)
+				assert.Equal(t, client.response, resp)
+				assert.Equal(t, []*schedulerobjects.TriggerSchedulingRoundRequest{req}, client.requests)
+			} else {
+				assert.Equal(t, codes.PermissionDenied, status.Code(err))
+				assert.Empty(t, client.requests)
+			}
+		})
+	}
+}
+
+type fakeSchedulerAdminClient struct {
+	requests []*schedulerobjects.TriggerSchedulingRoundRequest
+	response *schedulerobjects.TriggerSchedulingRoundResponse
+}
+
+func (c *fakeSchedulerAdminClient) TriggerSchedulingRound(_ context.Context, in *schedulerobjects.TriggerSchedulingRoundRequest, _ ...grpc.CallOption) (*schedulerobjects.TriggerSchedulingRoundResponse, error) {
+	c.requests = append(c.requests, in)
+	return c.response, nil
+}
@@ -40,6 +40,10 @@ type SubmitServer struct {
 	queueManagementConfig    *configuration.QueueManagementConfig
 	schedulingConfig         *configuration.SchedulingConfig
 	compressorPool           *pool.ObjectPool
+	// If not nil, lowers the resource requests of jobs that opt in to rightsizing at submission.
+	Rightsizer *Rightsizer
+	// If not nil, fills in the spec of jobs that reference a job spec stored as an OCI artifact.
+	JobSpecResolver *JobSpecResolver
 }
 
 func NewSubmitServer(
@@ -292,6 +296,9 @@ func (server *SubmitServer) SubmitJobs(grpcCtx context.Context, req *api.JobSubm
 	ctx := armadacontext.FromGrpcCtx(grpcCtx)
 	principal := authorization.GetPrincipal(ctx)
 
+	if err := server.resolveJobSpecs(ctx, req); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "[SubmitJobs] %s", err)
+	}
 	jobs, e := server.createJobs(req, principal.GetName(), principal.GetGroupNames())
 	if e != nil {
 		reqJson, _ := json.Marshal(req)
@@ -306,7 +313,7 @@ func (server *SubmitServer) SubmitJobs(grpcCtx context.Context, req *api.JobSubm
 		return nil, status.Errorf(armadaerrors.CodeFromError(err), "couldn't get/make queue: %s", err)
 	}
 
-	err = server.submittingJobsWouldSurpassLimit(*q, req)
+	err = server.applyQueuedJobsLimit(*q, jobs)
 	if err != nil {
 		return nil, status.Errorf(
 			codes.InvalidArgument,
@@ -408,8 +415,11 @@ func (server *SubmitServer) SubmitJobs(grpcCtx context.Context, req *api.JobSubm
 	return result, nil
 }
 
-func (server *SubmitServer) submittingJobsWouldSurpassLimit(q queue.Queue, jobSubmitRequest *api.JobSubmitRequest) error {
-	limit := server.queueManagementConfig.DefaultQueuedJobsLimit
+// applyQueuedJobsLimit enforces the queued jobs limit of q on jobs about to be submitted to it.
+// Depending on the configured behavior, it either returns an error if the limit would be surpassed,
+// or marks the jobs in excess of the limit as paused.
+func (server *SubmitServer) applyQueuedJobsLimit(q queue.Queue, jobs []*api.Job) error {
+	limit := server.queueManagementConfig.QueuedJobsLimitForQueue(q.Name)
 	if limit <= 0 {
 		return nil
 	}
@@ -419,14 +429,22 @@ func (server *SubmitServer) submittingJobsWouldSurpassLimit(q queue.Queue, jobSu
 		return err
 	}
 
-	queuedAfterSubmission := queued + int64(len(jobSubmitRequest.JobRequestItems))
-	if queuedAfterSubmission > int64(limit) {
-		return errors.Errorf(
-			"too many queued jobs: currently have %d, would have %d with new submission, limit is %d",
-			queued, queuedAfterSubmission, limit)
+	queuedAfterSubmission := queued + int64(len(jobs))
+	if queuedAfterSubmission <= int64(limit) {
+		return nil
 	}
-
-	return nil
+	if server.queueManagementConfig.QueuedJobsLimitBehavior == configuration.PauseJobs {
+		for i := len(jobs) - 1; i >= 0 && queued+int64(i) >= int64(limit); i-- {
+			if jobs[i].Annotations == nil {
+				jobs[i].Annotations = make(map[string]string)
+			}
+			jobs[i].Annotations[configuration.PausedAnnotation] = "true"
+		}
+		return nil
+	}
+	return errors.Errorf(
+		"too many queued jobs: currently have %d, would have %d with new submission, limit is %d",
+		queued, queuedAfterSubmission, limit)
 }
 
 func (server *SubmitServer) countQueuedJobs(q queue.Queue) (int64, error) {
@@ -785,6 +803,19 @@ func (server *SubmitServer) getQueueOrCreate(ctx *armadacontext.Context, queueNa
 	return nil, status.Errorf(codes.Unavailable, "Couldn't load queue %s: %s", queueName, e.Error())
 }
 
+// resolveJobSpecs fills in the spec of jobs in req that reference a job spec artifact.
+func (server *SubmitServer) resolveJobSpecs(ctx *armadacontext.Context, req *api.JobSubmitRequest) error {
+	if server.JobSpecResolver != nil {
+		return server.JobSpecResolver.Resolve(ctx, req)
+	}
+	for i, item := range req.JobRequestItems {
+		if _, ok := item.Annotations[configuration.JobSpecRefAnnotation]; ok {
+			return errors.Errorf("the %d-th job of job set %s references a job spec, but job spec artifacts are not enabled", i, req.JobSetId)
+		}
+	}
+	return nil
+}
+
 // createJobs returns a list of objects representing the jobs in a JobSubmitRequest.
 // This function validates the jobs in the request and the pod specs. in each job.
 // If any job or pod in invalid, an error is returned.
@@ -836,11 +867,26 @@ func (server *SubmitServer) createJobsObjects(request *api.JobSubmitRequest, own
 			namespace = "default"
 		}
 		fillContainerRequestsAndLimits(podSpec.Containers)
+		if err := applyNodeFlavorToPodSpec(item.Annotations, podSpec, *server.schedulingConfig); err != nil {
+			return nil, errors.Errorf("[createJobs] error applying the node flavor of the %d-th job of job set %s: %v", i, request.JobSetId, err)
+		}
 		applyDefaultsToAnnotations(item.Annotations, *server.schedulingConfig)
+		applyQueueJobDefaultsToPodSpec(request.Queue, podSpec, *server.schedulingConfig)
 		applyDefaultsToPodSpec(podSpec, *server.schedulingConfig)
+		applyQueueDefaultsToPodSpec(request.Queue, podSpec, *server.schedulingConfig)
+		applySpotPreferenceToPodSpec(item.Annotations, podSpec, *server.schedulingConfig)
+		item.Annotations = applyPoolRoutingToAnnotations(item.Annotations, podSpec, *server.schedulingConfig)
+		// Jobs of a node flavor keep the resources of the flavor.
+		_, hasNodeFlavor := item.Annotations[configuration.NodeFlavorAnnotation]
+		if server.Rightsizer != nil && !hasNodeFlavor && server.Rightsizer.Rightsize(request.Queue, request.JobSetId, item.Annotations, podSpec) {
+			log.Debugf("rightsized the %d-th job of job set %s", i, request.JobSetId)
+		}
 		if err := validation.ValidatePodSpec(podSpec, server.schedulingConfig); err != nil {
 			return nil, errors.Errorf("[createJobs] error validating the %d-th job of job set %s: %v", i, request.JobSetId, err)
 		}
+		if err := validation.ValidatePodSpecQueuePolicy(request.Queue, podSpec, server.schedulingConfig); err != nil {
+			return nil, errors.Errorf("[createJobs] error validating the %d-th job of job set %s: %v", i, request.JobSetId, err)
+		}
 
 		// TODO: remove, RequiredNodeLabels is deprecated and will be removed in future versions
 		for k, v := range item.RequiredNodeLabels {
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+	"k8s.io/utils/clock"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/metrics"
+)
+
+var (
+	submitRequestsRateLimited = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metrics.MetricPrefix + "submit_requests_rate_limited_total",
+			Help: "Number of job submit requests rejected due to exceeding a submission rate limit",
+		},
+		[]string{"queue", "limit"},
+	)
+	submitJobsRateLimited = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metrics.MetricPrefix + "submit_jobs_rate_limited_total",
+			Help: "Number of jobs rejected due to exceeding a submission rate limit",
+		},
+		[]string{"queue", "limit"},
+	)
+)
+
+// SubmissionRateLimiter enforces per-user and per-queue limits on the number of jobs and bytes submitted.
+// Each limit is a token bucket, i.e., clients may submit in bursts as long as they stay within the limit on average.
+type SubmissionRateLimiter struct {
+	config configuration.SubmissionRateLimitConfig
+	// Limiters are created lazily, keyed by the name of the limit and the user or queue it applies to.
+	limiters map[string]*rate.Limiter
+	clock    clock.Clock
+	mu       sync.Mutex
+}
+
+func NewSubmissionRateLimiter(config configuration.SubmissionRateLimitConfig) *SubmissionRateLimiter {
+	return &SubmissionRateLimiter{
+		config:   config,
+		limiters: make(map[string]*rate.Limiter),
+		clock:    clock.RealClock{},
+	}
+}
+
+type submissionLimit struct {
+	// Name of the limit, used for errors and metrics.
+	name      string
+	principal string
+	rate      float64
+	burst     int
+	n         int
+}
+
+// Allow consumes numJobs and numBytes from the limits of userId and queue,
+// or returns an armadaerrors.ErrRateLimited without consuming anything if any of those limits would be exceeded.
+// All limits are disabled if the limiter is nil.
+func (l *SubmissionRateLimiter) Allow(userId string, queue string, numJobs int, numBytes int) error {
+	if l == nil {
+		return nil
+	}
+	limits := []submissionLimit{
+		{name: "user jobs", principal: userId, rate: l.config.PerUser.JobsPerSecond, burst: l.config.PerUser.JobBurst, n: numJobs},
+		{name: "user bytes", principal: userId, rate: l.config.PerUser.BytesPerSecond, burst: l.config.PerUser.ByteBurst, n: numBytes},
+		{name: "queue jobs", principal: queue, rate: l.config.PerQueue.JobsPerSecond, burst: l.config.PerQueue.JobBurst, n: numJobs},
+		{name: "queue bytes", principal: queue, rate: l.config.PerQueue.BytesPerSecond, burst: l.config.PerQueue.ByteBurst, n: numBytes},
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	reservations := make([]*rate.Reservation, 0, len(limits))
+	for _, limit := range limits {
+		if limit.rate <= 0 {
+			continue
+		}
+		reservation := l.limiter(limit).ReserveN(now, limit.n)
+		if !reservation.OK() || reservation.DelayFrom(now) > 0 {
+			// Return the tokens of this and all earlier reservations, since the request is rejected.
+			reservation.CancelAt(now)
+			for _, r := range reservations {
+				r.CancelAt(now)
+			}
+			submitRequestsRateLimited.WithLabelValues(queue, limit.name).Inc()
+			submitJobsRateLimited.WithLabelValues(queue, limit.name).Add(float64(numJobs))
+			return &armadaerrors.ErrRateLimited{
+				Principal: limit.principal,
+				Limit:     limit.name,
+				Message: fmt.Sprintf(
+					"requested %d but at most %d are allowed at once, replenished at %g per second",
+					limit.n, limit.burst, limit.rate,
+				),
+			}
+		}
+		reservations = append(reservations, reservation)
+	}
+	return nil
+}
+
+func (l *SubmissionRateLimiter) limiter(limit submissionLimit) *rate.Limiter {
+	key := limit.name + "/" + limit.principal
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(limit.rate), limit.burst)
+		l.limiters[key] = limiter
+	}
+	return limiter
+}
+
+// AvailableJobs returns the number of jobs userId could currently submit to queue without exceeding a job limit,
+// or -1 if neither userId nor queue has a job limit.
+func (l *SubmissionRateLimiter) AvailableJobs(userId string, queue string) int {
+	if l == nil {
+		return -1
+	}
+	limits := []submissionLimit{
+		{name: "user jobs", principal: userId, rate: l.config.PerUser.JobsPerSecond, burst: l.config.PerUser.JobBurst},
+		{name: "queue jobs", principal: queue, rate: l.config.PerQueue.JobsPerSecond, burst: l.config.PerQueue.JobBurst},
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	available := -1
+	for _, limit := range limits {
+		if limit.rate <= 0 {
+			continue
+		}
+		tokens := int(l.limiter(limit).TokensAt(now))
+		if tokens < 0 {
+			tokens = 0
+		}
+		if available == -1 || tokens < available {
+			available = tokens
+		}
+	}
+	return available
+}
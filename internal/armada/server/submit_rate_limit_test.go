@@ -0,0 +1,124 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	clock "k8s.io/utils/clock/testing"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+)
+
+func TestSubmissionRateLimiter_Allow(t *testing.T) {
+	type submission struct {
+		userId      string
+		queue       string
+		numJobs     int
+		numBytes    int
+		expectError bool
+	}
+	tests := map[string]struct {
+		config      configuration.SubmissionRateLimitConfig
+		submissions []submission
+	}{
+		"no limits": {
+			submissions: []submission{
+				{userId: "alice", queue: "A", numJobs: 1000, numBytes: 1000000},
+				{userId: "alice", queue: "A", numJobs: 1000, numBytes: 1000000},
+			},
+		},
+		"user jobs": {
+			config: configuration.SubmissionRateLimitConfig{
+				PerUser: configuration.SubmissionRateLimit{JobsPerSecond: 1, JobBurst: 10},
+			},
+			submissions: []submission{
+				{userId: "alice", queue: "A", numJobs: 6},
+				{userId: "alice", queue: "B", numJobs: 6, expectError: true},
+				{userId: "alice", queue: "B", numJobs: 4},
+				{userId: "bob", queue: "A", numJobs: 10},
+			},
+		},
+		"request larger than burst": {
+			config: configuration.SubmissionRateLimitConfig{
+				PerUser: configuration.SubmissionRateLimit{JobsPerSecond: 100, JobBurst: 10},
+			},
+			submissions: []submission{
+				{userId: "alice", queue: "A", numJobs: 11, expectError: true},
+				{userId: "alice", queue: "A", numJobs: 10},
+			},
+		},
+		"queue bytes": {
+			config: configuration.SubmissionRateLimitConfig{
+				PerQueue: configuration.SubmissionRateLimit{BytesPerSecond: 1, ByteBurst: 100},
+			},
+			submissions: []submission{
+				{userId: "alice", queue: "A", numJobs: 1, numBytes: 60},
+				{userId: "bob", queue: "A", numJobs: 1, numBytes: 60, expectError: true},
+				{userId: "bob", queue: "B", numJobs: 1, numBytes: 60},
+			},
+		},
+		"rejected requests don't consume tokens of other limits": {
+			config: configuration.SubmissionRateLimitConfig{
+				PerUser:  configuration.SubmissionRateLimit{JobsPerSecond: 1, JobBurst: 10},
+				PerQueue: configuration.SubmissionRateLimit{JobsPerSecond: 1, JobBurst: 5},
+			},
+			submissions: []submission{
+				{userId: "alice", queue: "A", numJobs: 6, expectError: true},
+				{userId: "alice", queue: "B", numJobs: 5},
+				{userId: "alice", queue: "C", numJobs: 5},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			limiter := NewSubmissionRateLimiter(tc.config)
+			limiter.clock = clock.NewFakeClock(time.Now())
+			for i, s := range tc.submissions {
+				err := limiter.Allow(s.userId, s.queue, s.numJobs, s.numBytes)
+				if s.expectError {
+					assert.Equal(t, codes.ResourceExhausted, armadaerrors.CodeFromError(err), "submission %d", i)
+				} else {
+					assert.NoError(t, err, "submission %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestSubmissionRateLimiter_Replenish(t *testing.T) {
+	limiter := NewSubmissionRateLimiter(configuration.SubmissionRateLimitConfig{
+		PerUser: configuration.SubmissionRateLimit{JobsPerSecond: 2, JobBurst: 10},
+	})
+	fakeClock := clock.NewFakeClock(time.Now())
+	limiter.clock = fakeClock
+
+	assert.NoError(t, limiter.Allow("alice", "A", 10, 0))
+	assert.Error(t, limiter.Allow("alice", "A", 4, 0))
+
+	fakeClock.Step(2 * time.Second)
+	assert.NoError(t, limiter.Allow("alice", "A", 4, 0))
+	assert.Error(t, limiter.Allow("alice", "A", 1, 0))
+}
+
+func TestSubmissionRateLimiter_AvailableJobs(t *testing.T) {
+	limiter := NewSubmissionRateLimiter(configuration.SubmissionRateLimitConfig{
+		PerUser:  configuration.SubmissionRateLimit{JobsPerSecond: 1, JobBurst: 10},
+		PerQueue: configuration.SubmissionRateLimit{JobsPerSecond: 1, JobBurst: 8},
+	})
+	limiter.clock = clock.NewFakeClock(time.Now())
+
+	assert.Equal(t, 8, limiter.AvailableJobs("alice", "A"))
+	assert.NoError(t, limiter.Allow("alice", "B", 7, 0))
+	assert.Equal(t, 3, limiter.AvailableJobs("alice", "A"))
+	assert.Equal(t, 1, limiter.AvailableJobs("bob", "B"))
+}
+
+func TestSubmissionRateLimiter_Nil(t *testing.T) {
+	var limiter *SubmissionRateLimiter
+	assert.NoError(t, limiter.Allow("alice", "A", 1000, 1000000))
+	assert.Equal(t, -1, limiter.AvailableJobs("alice", "A"))
+	assert.Equal(t, -1, NewSubmissionRateLimiter(configuration.SubmissionRateLimitConfig{}).AvailableJobs("alice", "A"))
+}
@@ -514,6 +514,42 @@ func TestSubmitServer_SubmitJobs_RejectsIfTooManyJobsAreQueued(t *testing.T) {
 	})
 }
 
+func TestSubmitServer_SubmitJobs_QueuedJobsLimitByQueue(t *testing.T) {
+	withSubmitServer(func(s *SubmitServer, events *repository.TestEventStore) {
+		s.queueManagementConfig.DefaultQueuedJobsLimit = 1
+		s.queueManagementConfig.QueuedJobsLimitByQueue = map[string]int{"test": 3}
+		jobSetId := util.NewULID()
+
+		_, err := s.SubmitJobs(context.Background(), createJobRequest(jobSetId, 3))
+		assert.NoError(t, err)
+
+		_, err = s.SubmitJobs(context.Background(), createJobRequest(jobSetId, 1))
+		assert.Error(t, err)
+	})
+}
+
+func TestSubmitServer_SubmitJobs_PausesJobsInExcessOfQueuedJobsLimit(t *testing.T) {
+	withSubmitServer(func(s *SubmitServer, events *repository.TestEventStore) {
+		s.queueManagementConfig.DefaultQueuedJobsLimit = 3
+		s.queueManagementConfig.QueuedJobsLimitBehavior = configuration.PauseJobs
+		jobSetId := util.NewULID()
+
+		_, err := s.SubmitJobs(context.Background(), createJobRequest(jobSetId, 2))
+		require.NoError(t, err)
+		result, err := s.SubmitJobs(context.Background(), createJobRequest(jobSetId, 3))
+		require.NoError(t, err)
+		require.Len(t, result.JobResponseItems, 3)
+
+		for i, item := range result.JobResponseItems {
+			jobs, err := s.jobRepository.GetExistingJobsByIds([]string{item.JobId})
+			require.NoError(t, err)
+			require.Len(t, jobs, 1)
+			_, paused := jobs[0].Annotations[configuration.PausedAnnotation]
+			assert.Equal(t, i > 0, paused)
+		}
+	})
+}
+
 func TestSubmitServer_ReprioritizeJobs(t *testing.T) {
 	t.Run("job that doesn't exist", func(t *testing.T) {
 		withSubmitServerAndRepos(func(s *SubmitServer, jobRepo repository.JobRepository, events *repository.TestEventStore) {
@@ -5,6 +5,7 @@ import (
 	"crypto/sha1"
 	"fmt"
 	"math/rand"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,9 +15,12 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/exp/maps"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	armadaconfiguration "github.com/armadaproject/armada/internal/armada/configuration"
 	"github.com/armadaproject/armada/internal/armada/permissions"
 	"github.com/armadaproject/armada/internal/armada/repository"
 	"github.com/armadaproject/armada/internal/armada/validation"
@@ -67,6 +71,10 @@ type PulsarSubmitServer struct {
 	GangIdAnnotation string
 	// Temporary flag to stop us rejecting jobs as we switch over to new submit checks
 	IgnoreJobSubmitChecks bool
+	// Limits the rate at which each user and queue may submit jobs; may be nil, in which case submissions aren't limited.
+	SubmissionRateLimiter *SubmissionRateLimiter
+	// Number of jobs clients are asked to include in each submit request; zero disables advertising a batch size.
+	OptimalJobsPerRequest int
 }
 
 func (srv *PulsarSubmitServer) SubmitJobs(grpcCtx context.Context, req *api.JobSubmitRequest) (*api.JobSubmitResponse, error) {
@@ -75,6 +83,13 @@ func (srv *PulsarSubmitServer) SubmitJobs(grpcCtx context.Context, req *api.JobS
 	if err != nil {
 		return nil, err
 	}
+	srv.advertiseBatchSize(grpcCtx, userId, req.Queue)
+	if err := srv.SubmissionRateLimiter.Allow(userId, req.Queue, len(req.JobRequestItems), req.Size()); err != nil {
+		return nil, err
+	}
+	if err := srv.SubmitServer.resolveJobSpecs(ctx, req); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "[SubmitJobs] %s", err)
+	}
 
 	// Prepare an event sequence to be submitted to the log
 	pulsarSchedulerEvents := &armadaevents.EventSequence{
@@ -102,6 +117,16 @@ func (srv *PulsarSubmitServer) SubmitJobs(grpcCtx context.Context, req *api.JobS
 	if err := commonvalidation.ValidateApiJobs(apiJobs, *srv.SubmitServer.schedulingConfig); err != nil {
 		return nil, err
 	}
+	if err := srv.authorizeCrossQueueGangJobs(ctx, req.Queue, apiJobs); err != nil {
+		return nil, err
+	}
+	if err := srv.authorizeBindOnlyJobs(ctx, apiJobs); err != nil {
+		return nil, err
+	}
+	warnOfJobsBelowPoolMinimumJobSize(grpcCtx, apiJobs, *srv.SubmitServer.schedulingConfig)
+	if err := srv.SubmitServer.applyQueuedJobsLimit(queue.Queue{Name: req.Queue}, apiJobs); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "[SubmitJobs] error checking queue limit: %s", err)
+	}
 
 	schedulersByJobId, err := srv.assignScheduler(apiJobs)
 	if err != nil {
@@ -242,6 +267,25 @@ func (srv *PulsarSubmitServer) SubmitJobs(grpcCtx context.Context, req *api.JobS
 	return &api.JobSubmitResponse{JobResponseItems: responses}, nil
 }
 
+// advertiseBatchSize tells the client via a response header how many jobs to include in each submit request,
+// i.e., the configured optimal number of jobs, reduced to what the rate limits currently allow,
+// such that client-side batchers send fewer, larger requests without having them rejected.
+func (srv *PulsarSubmitServer) advertiseBatchSize(ctx context.Context, userId string, queue string) {
+	size := srv.OptimalJobsPerRequest
+	if available := srv.SubmissionRateLimiter.AvailableJobs(userId, queue); available >= 0 && (size <= 0 || available < size) {
+		// Ask for at least one job, such that clients keep making progress as the limits are replenished.
+		size = available
+		if size < 1 {
+			size = 1
+		}
+	}
+	if size <= 0 {
+		return
+	}
+	// Fails only if there's no grpc stream associated with ctx, e.g., in tests.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(api.OptimalJobsPerRequestHeader, strconv.Itoa(size)))
+}
+
 func (srv *PulsarSubmitServer) CancelJobs(grpcCtx context.Context, req *api.JobCancelRequest) (*api.CancellationResult, error) {
 	ctx := armadacontext.FromGrpcCtx(grpcCtx)
 
@@ -648,6 +692,46 @@ func (srv *PulsarSubmitServer) Authorize(
 	return
 }
 
+// authorizeCrossQueueGangJobs checks that the user has permission to submit jobs to the queue owning each cross-queue gang
+// that any of jobs, which are submitted to jobQueue, are members of, since such jobs are scheduled in the turn of the owning queue.
+func (srv *PulsarSubmitServer) authorizeCrossQueueGangJobs(ctx *armadacontext.Context, jobQueue string, jobs []*api.Job) error {
+	authorized := map[string]bool{jobQueue: true}
+	for _, job := range jobs {
+		gangQueue, ok := job.Annotations[armadaconfiguration.GangQueueAnnotation]
+		if !ok || authorized[gangQueue] {
+			continue
+		}
+		if _, _, err := srv.Authorize(ctx, gangQueue, permissions.SubmitAnyJobs, queue.PermissionVerbSubmit); err != nil {
+			return err
+		}
+		authorized[gangQueue] = true
+	}
+	return nil
+}
+
+// authorizeBindOnlyJobs checks that the user has permission to submit jobs bound to a node, if any of jobs are,
+// since such jobs bypass scheduling and hence fair share.
+func (srv *PulsarSubmitServer) authorizeBindOnlyJobs(ctx *armadacontext.Context, jobs []*api.Job) error {
+	for _, job := range jobs {
+		if isBindOnlyJob(job) && !srv.Permissions.UserHasPermission(ctx, permissions.SubmitBindOnlyJobs) {
+			principal := authorization.GetPrincipal(ctx)
+			return errors.WithStack(&armadaerrors.ErrUnauthorized{
+				Principal:  principal.GetName(),
+				Permission: permissions.SubmitBindOnlyJobs,
+				Action:     "submit jobs bound to a node",
+				Message:    "",
+			})
+		}
+	}
+	return nil
+}
+
+// isBindOnlyJob returns true if the job is bound to a node and hence isn't scheduled.
+func isBindOnlyJob(job *api.Job) bool {
+	_, ok := job.Annotations[armadaconfiguration.BindToNodeAnnotation]
+	return ok
+}
+
 // principalHasQueuePermissions returns true if the principal has permissions to perform some action,
 // as specified by the provided verb, for a specific queue, and false otherwise.
 func principalHasQueuePermissions(principal authorization.Principal, q queue.Queue, verb queue.PermissionVerb) bool {
@@ -805,6 +889,12 @@ func (srv *PulsarSubmitServer) assignScheduler(jobs []*api.Job) (map[string]sche
 			}
 		}
 
+		// Only the Pulsar scheduler leases jobs bound to a node.
+		if isBindOnlyJob(gang[0]) {
+			schedulerByGangId[gangId] = schedulers.Pulsar
+			continue
+		}
+
 		// If the first job in the gang explicitly targets either scheduler, assign to that scheduler.
 		if jobs[0].Scheduler == "pulsar" {
 			schedulerByGangId[gangId] = schedulers.Pulsar
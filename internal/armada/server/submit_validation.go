@@ -0,0 +1,134 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/armadaproject/armada/internal/armada/permissions"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/auth/authorization"
+	"github.com/armadaproject/armada/internal/common/logging"
+	commonvalidation "github.com/armadaproject/armada/internal/common/validation"
+	"github.com/armadaproject/armada/pkg/api"
+	"github.com/armadaproject/armada/pkg/client/queue"
+)
+
+const ValidateJobsPath = "/api/v1/job/validate"
+
+// JobValidationResult is the outcome of validating a job submit request without submitting it.
+type JobValidationResult struct {
+	Valid  bool                           `json:"valid"`
+	Errors []*commonvalidation.FieldError `json:"errors,omitempty"`
+}
+
+// ValidateJobs checks whether req would be accepted by SubmitJobs, without submitting any jobs.
+// The request is first checked against the supported job spec schema, reporting all invalid fields at once.
+// Only if it conforms are the jobs checked against the capabilities of the pools they may be scheduled onto.
+func (srv *PulsarSubmitServer) ValidateJobs(ctx *armadacontext.Context, req *api.JobSubmitRequest) (*JobValidationResult, error) {
+	if req.Queue != "" {
+		if _, _, err := srv.Authorize(ctx, req.Queue, permissions.SubmitAnyJobs, queue.PermissionVerbSubmit); err != nil {
+			return nil, err
+		}
+	}
+	if fieldErrors := commonvalidation.ValidateJobSubmitRequestFields(req, srv.SubmitServer.schedulingConfig); len(fieldErrors) > 0 {
+		return &JobValidationResult{Errors: fieldErrors}, nil
+	}
+
+	principal := authorization.GetPrincipal(ctx)
+	apiJobs, err := srv.SubmitServer.createJobs(req, principal.GetName(), principal.GetGroupNames())
+	if err != nil {
+		return invalidJobValidationResult("jobRequestItems", err), nil
+	}
+	if err := commonvalidation.ValidateApiJobs(apiJobs, *srv.SubmitServer.schedulingConfig); err != nil {
+		return invalidJobValidationResult("jobRequestItems", err), nil
+	}
+
+	indexByJobId := make(map[string]int, len(apiJobs))
+	for i, job := range apiJobs {
+		indexByJobId[job.Id] = i
+	}
+	rv := &JobValidationResult{}
+	for _, gang := range srv.groupJobsByGangId(apiJobs) {
+		if _, err := srv.assignScheduler(gang); err != nil {
+			rv.Errors = append(rv.Errors, &commonvalidation.FieldError{
+				Path:       fmt.Sprintf("jobRequestItems[%d]", indexByJobId[gang[0].Id]),
+				Reason:     err.Error(),
+				Suggestion: "request fewer resources or relax node selectors, tolerations, and required cluster capabilities, such that the job fits onto the nodes of at least one pool",
+			})
+		}
+	}
+	rv.Valid = len(rv.Errors) == 0
+	return rv, nil
+}
+
+func invalidJobValidationResult(path string, err error) *JobValidationResult {
+	return &JobValidationResult{
+		Errors: []*commonvalidation.FieldError{{Path: path, Reason: err.Error()}},
+	}
+}
+
+// JobValidationHttpHandler exposes PulsarSubmitServer.ValidateJobs as a json http API:
+//
+//	POST /api/v1/job/validate  validates a JobSubmitRequest, given in the same format as accepted by the REST submit API
+//
+// Requests are authenticated using the same authentication services as the gRPC API.
+// Invalid requests are reported with status 200 and a JobValidationResult listing the invalid fields.
+type JobValidationHttpHandler struct {
+	submitServer *PulsarSubmitServer
+	authServices []authorization.AuthService
+}
+
+func NewJobValidationHttpHandler(submitServer *PulsarSubmitServer, authServices []authorization.AuthService) *JobValidationHttpHandler {
+	return &JobValidationHttpHandler{
+		submitServer: submitServer,
+		authServices: authServices,
+	}
+}
+
+// RegisterRoutes registers the handler with mux.
+func (h *JobValidationHttpHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle(ValidateJobsPath, h)
+}
+
+func (h *JobValidationHttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	authCtx, err := authorization.AuthenticateHttpRequest(r, h.authServices)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	ctx := armadacontext.New(authCtx, log.NewEntry(log.StandardLogger()))
+
+	req := &api.JobSubmitRequest{}
+	if err := jsonpb.Unmarshal(r.Body, req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid job submit request: %s", err), http.StatusBadRequest)
+		return
+	}
+	rv, err := h.submitServer.ValidateJobs(ctx, req)
+	if err != nil {
+		statusCode := runtime.HTTPStatusFromCode(armadaerrors.CodeFromError(err))
+		var e *armadaerrors.ErrUnauthorized
+		if errors.As(err, &e) {
+			statusCode = http.StatusForbidden
+		}
+		if statusCode == http.StatusInternalServerError {
+			logging.WithStacktrace(ctx, err).Error("failed to validate jobs")
+		}
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rv); err != nil {
+		logging.WithStacktrace(ctx, err).Error("failed to write job validation response")
+	}
+}
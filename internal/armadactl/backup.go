@@ -0,0 +1,393 @@
+package armadactl
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/pkg/api"
+	"github.com/armadaproject/armada/pkg/client"
+	"github.com/armadaproject/armada/pkg/client/domain"
+)
+
+const (
+	// backupArchiveVersion is the version of the archive format written by Backup.
+	backupArchiveVersion = 1
+	backupManifestFile   = "manifest.json"
+	backupQueuesFile     = "queues.json"
+	backupJobsFile       = "jobs.json"
+)
+
+// backedUpJobStates are the states of jobs included in backups, i.e., those of jobs that haven't finished.
+var backedUpJobStates = map[domain.JobStatus]bool{
+	domain.Submitted: true,
+	domain.Queued:    true,
+	domain.Leased:    true,
+	domain.Pending:   true,
+	domain.Running:   true,
+}
+
+// BackupArchive is the state of an Armada installation exported by Backup,
+// stored as a gzipped tar archive of json files, such that it can be inspected with standard tools.
+type BackupArchive struct {
+	Manifest BackupManifest
+	Queues   []*api.Queue
+	Jobs     []*BackupJob
+}
+
+type BackupManifest struct {
+	Version   int       `json:"version"`
+	Created   time.Time `json:"created"`
+	ArmadaUrl string    `json:"armadaUrl"`
+	NumQueues int       `json:"numQueues"`
+	NumJobs   int       `json:"numJobs"`
+}
+
+// BackupJob is a job that hadn't finished at the time of the backup, along with the state of its most recent run.
+type BackupJob struct {
+	State     domain.JobStatus `json:"state"`
+	ClusterId string           `json:"clusterId,omitempty"`
+	Job       *api.Job         `json:"job"`
+}
+
+// Backup exports all queues and all jobs that haven't finished to an archive at outputPath.
+// Jobs are found via the active job sets of each queue, and their state is derived from the events of those job sets.
+func (a *App) Backup(outputPath string) error {
+	c, err := client.NewClient(a.Params.ApiConnectionDetails)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx := context.Background()
+
+	archive := &BackupArchive{
+		Manifest: BackupManifest{
+			Version:   backupArchiveVersion,
+			Created:   time.Now().UTC(),
+			ArmadaUrl: a.Params.ApiConnectionDetails.ArmadaUrl,
+		},
+	}
+	queues := c.Queues(ctx)
+	for queues.Next() {
+		archive.Queues = append(archive.Queues, queues.Queue())
+	}
+	if err := queues.Err(); err != nil {
+		return errors.WithMessage(err, "error getting queues")
+	}
+	for _, queue := range archive.Queues {
+		info, err := c.GetQueueInfo(ctx, queue.Name)
+		if err != nil {
+			return errors.WithMessagef(err, "error getting job sets of queue %s", queue.Name)
+		}
+		for _, jobSet := range info.ActiveJobSets {
+			state := domain.NewWatchContext()
+			events := c.JobSetEvents(ctx, queue.Name, jobSet.Name, client.WatchOptions{})
+			for events.Next() {
+				state.ProcessEvent(events.Event())
+			}
+			events.Close()
+			if err := events.Err(); err != nil {
+				return errors.WithMessagef(err, "error getting events of job set %s of queue %s", jobSet.Name, queue.Name)
+			}
+			archive.Jobs = append(archive.Jobs, backupJobsFromState(state)...)
+		}
+		fmt.Fprintf(a.Out, "Exported queue %s\n", queue.Name)
+	}
+	archive.Manifest.NumQueues = len(archive.Queues)
+	archive.Manifest.NumJobs = len(archive.Jobs)
+	if err := archive.Validate(); err != nil {
+		return errors.WithMessage(err, "exported state is inconsistent")
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+	if err := writeBackupArchive(f, archive); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.Out, "Exported %d queues and %d jobs to %s\n", len(archive.Queues), len(archive.Jobs), outputPath)
+	return f.Close()
+}
+
+// Restore imports the queues and jobs of the archive at inputPath, e.g., into a fresh installation.
+// Queues that already exist are left as is. Jobs are resubmitted to the job set and queue they were in and hence get new ids;
+// since the original job id is used as client id, restoring the same archive twice doesn't duplicate jobs if the server deduplicates submissions.
+// Jobs that were leased or running at the time of the backup are only resubmitted if includeRunning is true,
+// since they may still be running in the original installation. If dryRun is true, the archive is only validated.
+func (a *App) Restore(inputPath string, includeRunning bool, dryRun bool) error {
+	f, err := os.Open(inputPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+	archive, err := readBackupArchive(f)
+	if err != nil {
+		return err
+	}
+	if err := archive.Validate(); err != nil {
+		return errors.WithMessagef(err, "archive %s is invalid", inputPath)
+	}
+	jobsByQueueAndJobSet := make(map[string]map[string][]*BackupJob)
+	numJobs := 0
+	for _, job := range archive.Jobs {
+		if !includeRunning && job.State != domain.Submitted && job.State != domain.Queued {
+			continue
+		}
+		if jobsByQueueAndJobSet[job.Job.Queue] == nil {
+			jobsByQueueAndJobSet[job.Job.Queue] = make(map[string][]*BackupJob)
+		}
+		jobsByQueueAndJobSet[job.Job.Queue][job.Job.JobSetId] = append(jobsByQueueAndJobSet[job.Job.Queue][job.Job.JobSetId], job)
+		numJobs++
+	}
+	fmt.Fprintf(
+		a.Out, "Archive %s created %s from %s is valid; restoring %d queues and %d of %d jobs\n",
+		inputPath, archive.Manifest.Created.Format(time.RFC3339), archive.Manifest.ArmadaUrl, len(archive.Queues), numJobs, len(archive.Jobs),
+	)
+	if dryRun {
+		return nil
+	}
+
+	c, err := client.NewClient(a.Params.ApiConnectionDetails)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx := context.Background()
+	for _, queue := range archive.Queues {
+		if _, err := c.GetQueue(ctx, queue.Name); err == nil {
+			fmt.Fprintf(a.Out, "Queue %s already exists; leaving it as is\n", queue.Name)
+			continue
+		} else if status.Code(err) != codes.NotFound {
+			return errors.WithMessagef(err, "error getting queue %s", queue.Name)
+		}
+		if err := c.CreateQueue(ctx, queue); err != nil {
+			return errors.WithMessagef(err, "error creating queue %s", queue.Name)
+		}
+		fmt.Fprintf(a.Out, "Created queue %s\n", queue.Name)
+	}
+	for _, queue := range sortedKeys(jobsByQueueAndJobSet) {
+		for _, jobSetId := range sortedKeys(jobsByQueueAndJobSet[queue]) {
+			jobs := jobsByQueueAndJobSet[queue][jobSetId]
+			items := make([]*api.JobSubmitRequestItem, len(jobs))
+			for i, job := range jobs {
+				items[i] = submitRequestItemFromJob(job.Job)
+			}
+			responses, err := c.SubmitJobs(ctx, queue, jobSetId, items)
+			if err != nil {
+				return errors.WithMessagef(err, "error submitting jobs to job set %s of queue %s", jobSetId, queue)
+			}
+			for i, response := range responses {
+				if response.Error != "" {
+					return errors.Errorf("error submitting job %s to job set %s of queue %s: %s", jobs[i].Job.Id, jobSetId, queue, response.Error)
+				}
+				fmt.Fprintf(a.Out, "Restored job %s as %s\n", jobs[i].Job.Id, response.JobId)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate returns an error if the archive isn't internally consistent,
+// e.g., if any job refers to a queue that isn't part of the archive or if any gang is incomplete.
+func (archive *BackupArchive) Validate() error {
+	if archive.Manifest.Version != backupArchiveVersion {
+		return errors.Errorf("unsupported archive version %d; expected %d", archive.Manifest.Version, backupArchiveVersion)
+	}
+	if archive.Manifest.NumQueues != len(archive.Queues) {
+		return errors.Errorf("manifest lists %d queues, but archive contains %d", archive.Manifest.NumQueues, len(archive.Queues))
+	}
+	if archive.Manifest.NumJobs != len(archive.Jobs) {
+		return errors.Errorf("manifest lists %d jobs, but archive contains %d", archive.Manifest.NumJobs, len(archive.Jobs))
+	}
+	queues := make(map[string]bool, len(archive.Queues))
+	for _, queue := range archive.Queues {
+		if queue.GetName() == "" {
+			return errors.New("archive contains a queue without a name")
+		}
+		if queues[queue.Name] {
+			return errors.Errorf("archive contains queue %s more than once", queue.Name)
+		}
+		queues[queue.Name] = true
+	}
+	jobIds := make(map[string]bool, len(archive.Jobs))
+	numJobsByGangId := make(map[string]int)
+	cardinalityByGangId := make(map[string]int)
+	for _, job := range archive.Jobs {
+		if job.Job == nil || job.Job.Id == "" {
+			return errors.New("archive contains a job without an id")
+		}
+		if jobIds[job.Job.Id] {
+			return errors.Errorf("archive contains job %s more than once", job.Job.Id)
+		}
+		jobIds[job.Job.Id] = true
+		if !backedUpJobStates[job.State] {
+			return errors.Errorf("job %s is in unexpected state %s", job.Job.Id, job.State)
+		}
+		if !queues[job.Job.Queue] {
+			return errors.Errorf("job %s refers to queue %s, which isn't part of the archive", job.Job.Id, job.Job.Queue)
+		}
+		if job.Job.JobSetId == "" {
+			return errors.Errorf("job %s has no job set", job.Job.Id)
+		}
+		if job.Job.PodSpec == nil && len(job.Job.PodSpecs) == 0 {
+			return errors.Errorf("job %s has no pod spec", job.Job.Id)
+		}
+		if gangId := job.Job.Annotations[configuration.GangIdAnnotation]; gangId != "" {
+			gangKey := job.Job.Queue + "/" + gangId
+			cardinality, err := strconv.Atoi(job.Job.Annotations[configuration.GangCardinalityAnnotation])
+			if err != nil {
+				return errors.Errorf("job %s of gang %s has invalid cardinality: %s", job.Job.Id, gangId, err)
+			}
+			if c, ok := cardinalityByGangId[gangKey]; ok && c != cardinality {
+				return errors.Errorf("members of gang %s have different cardinalities %d and %d", gangId, c, cardinality)
+			}
+			cardinalityByGangId[gangKey] = cardinality
+			numJobsByGangId[gangKey]++
+		}
+	}
+	for gangKey, numJobs := range numJobsByGangId {
+		if numJobs != cardinalityByGangId[gangKey] {
+			return errors.Errorf("gang %s has %d members, but the archive contains %d of them", gangKey, cardinalityByGangId[gangKey], numJobs)
+		}
+	}
+	return nil
+}
+
+// backupJobsFromState returns the jobs of a job set that haven't finished, ordered by id.
+func backupJobsFromState(state *domain.WatchContext) []*BackupJob {
+	var rv []*BackupJob
+	for _, info := range state.GetCurrentState() {
+		if info.Job == nil || !backedUpJobStates[info.Status] {
+			continue
+		}
+		rv = append(rv, &BackupJob{
+			State:     info.Status,
+			ClusterId: info.ClusterId,
+			Job:       info.Job,
+		})
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		return rv[i].Job.Id < rv[j].Job.Id
+	})
+	return rv
+}
+
+// submitRequestItemFromJob returns a request to resubmit the job.
+// The original job id is used as client id unless the job already has one.
+func submitRequestItemFromJob(job *api.Job) *api.JobSubmitRequestItem {
+	clientId := job.ClientId
+	if clientId == "" {
+		clientId = job.Id
+	}
+	return &api.JobSubmitRequestItem{
+		Priority:           job.Priority,
+		Namespace:          job.Namespace,
+		ClientId:           clientId,
+		Labels:             job.Labels,
+		Annotations:        job.Annotations,
+		RequiredNodeLabels: job.RequiredNodeLabels,
+		PodSpec:            job.PodSpec,
+		PodSpecs:           job.PodSpecs,
+		Ingress:            job.Ingress,
+		Services:           job.Services,
+		Scheduler:          job.Scheduler,
+		QueueTtlSeconds:    job.QueueTtlSeconds,
+	}
+}
+
+func writeBackupArchive(w io.Writer, archive *BackupArchive) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	files := []struct {
+		name  string
+		value any
+	}{
+		{backupManifestFile, archive.Manifest},
+		{backupQueuesFile, archive.Queues},
+		{backupJobsFile, archive.Jobs},
+	}
+	for _, file := range files {
+		data, err := json.Marshal(file.value)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		header := &tar.Header{
+			Name:    file.name,
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: archive.Manifest.Created,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return errors.WithStack(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(gw.Close())
+}
+
+func readBackupArchive(r io.Reader) (*BackupArchive, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.WithMessage(err, "archive isn't gzipped")
+	}
+	tr := tar.NewReader(gr)
+	archive := &BackupArchive{}
+	found := make(map[string]bool)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		var value any
+		switch header.Name {
+		case backupManifestFile:
+			value = &archive.Manifest
+		case backupQueuesFile:
+			value = &archive.Queues
+		case backupJobsFile:
+			value = &archive.Jobs
+		default:
+			return nil, errors.Errorf("unexpected file %s in archive", header.Name)
+		}
+		if err := json.NewDecoder(tr).Decode(value); err != nil {
+			return nil, errors.WithMessagef(err, "error reading %s", header.Name)
+		}
+		found[header.Name] = true
+	}
+	for _, name := range []string{backupManifestFile, backupQueuesFile, backupJobsFile} {
+		if !found[name] {
+			return nil, errors.Errorf("archive doesn't contain %s", name)
+		}
+	}
+	return archive, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
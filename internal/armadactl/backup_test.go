@@ -0,0 +1,148 @@
+package armadactl
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/pkg/api"
+	"github.com/armadaproject/armada/pkg/client/domain"
+)
+
+func TestBackupArchive_RoundTrip(t *testing.T) {
+	archive := testBackupArchive()
+	require.NoError(t, archive.Validate())
+
+	var buf bytes.Buffer
+	require.NoError(t, writeBackupArchive(&buf, archive))
+	read, err := readBackupArchive(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, archive.Manifest, read.Manifest)
+	assert.Equal(t, archive.Queues, read.Queues)
+	require.Len(t, read.Jobs, len(archive.Jobs))
+	for i, job := range read.Jobs {
+		assert.Equal(t, archive.Jobs[i].State, job.State)
+		assert.Equal(t, archive.Jobs[i].Job.Id, job.Job.Id)
+		assert.Equal(t, archive.Jobs[i].Job.Annotations, job.Job.Annotations)
+	}
+}
+
+func TestBackupArchive_Validate(t *testing.T) {
+	tests := map[string]func(archive *BackupArchive){
+		"unsupported version": func(archive *BackupArchive) {
+			archive.Manifest.Version = backupArchiveVersion + 1
+		},
+		"manifest doesn't match contents": func(archive *BackupArchive) {
+			archive.Manifest.NumJobs++
+		},
+		"duplicate queue": func(archive *BackupArchive) {
+			archive.Queues = append(archive.Queues, archive.Queues[0])
+			archive.Manifest.NumQueues++
+		},
+		"job of unknown queue": func(archive *BackupArchive) {
+			archive.Jobs[0].Job.Queue = "unknown"
+		},
+		"duplicate job": func(archive *BackupArchive) {
+			archive.Jobs = append(archive.Jobs, archive.Jobs[0])
+			archive.Manifest.NumJobs++
+		},
+		"job without pod spec": func(archive *BackupArchive) {
+			archive.Jobs[0].Job.PodSpec = nil
+		},
+		"incomplete gang": func(archive *BackupArchive) {
+			archive.Jobs = archive.Jobs[:len(archive.Jobs)-1]
+			archive.Manifest.NumJobs--
+		},
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			archive := testBackupArchive()
+			mutate(archive)
+			assert.Error(t, archive.Validate())
+		})
+	}
+}
+
+func TestBackupJobsFromState(t *testing.T) {
+	created := time.Now()
+	queued := testBackupJob("queued", "")
+	running := testBackupJob("running", "")
+	succeeded := testBackupJob("succeeded", "")
+	events := []api.Event{
+		&api.JobSubmittedEvent{JobId: queued.Id, Created: created, Job: *queued},
+		&api.JobQueuedEvent{JobId: queued.Id, Created: created.Add(time.Second)},
+		&api.JobSubmittedEvent{JobId: running.Id, Created: created, Job: *running},
+		&api.JobRunningEvent{JobId: running.Id, Created: created.Add(time.Second), ClusterId: "cluster-a"},
+		&api.JobSubmittedEvent{JobId: succeeded.Id, Created: created, Job: *succeeded},
+		&api.JobSucceededEvent{JobId: succeeded.Id, Created: created.Add(time.Second)},
+	}
+	state := domain.NewWatchContext()
+	for _, event := range events {
+		state.ProcessEvent(event)
+	}
+
+	jobs := backupJobsFromState(state)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, queued.Id, jobs[0].Job.Id)
+	assert.Equal(t, domain.JobStatus(domain.Queued), jobs[0].State)
+	assert.Equal(t, running.Id, jobs[1].Job.Id)
+	assert.Equal(t, domain.JobStatus(domain.Running), jobs[1].State)
+	assert.Equal(t, "cluster-a", jobs[1].ClusterId)
+}
+
+func TestSubmitRequestItemFromJob(t *testing.T) {
+	job := testBackupJob("job", "")
+	assert.Equal(t, job.Id, submitRequestItemFromJob(job).ClientId)
+	job.ClientId = "client-id"
+	item := submitRequestItemFromJob(job)
+	assert.Equal(t, "client-id", item.ClientId)
+	assert.Equal(t, job.PodSpec, item.PodSpec)
+	assert.Equal(t, job.Priority, item.Priority)
+}
+
+func testBackupArchive() *BackupArchive {
+	gangAnnotations := map[string]string{
+		configuration.GangIdAnnotation:          "gang",
+		configuration.GangCardinalityAnnotation: "2",
+	}
+	jobs := []*BackupJob{
+		{State: domain.Queued, Job: testBackupJob("job-1", "")},
+		{State: domain.Running, ClusterId: "cluster-a", Job: testBackupJob("job-2", "")},
+		{State: domain.Queued, Job: testBackupJob("gang-member-1", "")},
+		{State: domain.Queued, Job: testBackupJob("gang-member-2", "")},
+	}
+	jobs[2].Job.Annotations = gangAnnotations
+	jobs[3].Job.Annotations = gangAnnotations
+	return &BackupArchive{
+		Manifest: BackupManifest{
+			Version:   backupArchiveVersion,
+			Created:   time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			ArmadaUrl: "localhost:50051",
+			NumQueues: 2,
+			NumJobs:   len(jobs),
+		},
+		Queues: []*api.Queue{
+			{Name: "queue-a", PriorityFactor: 1, UserOwners: []string{"alice"}},
+			{Name: "queue-b", PriorityFactor: 2},
+		},
+		Jobs: jobs,
+	}
+}
+
+func testBackupJob(id string, clientId string) *api.Job {
+	return &api.Job{
+		Id:       id,
+		ClientId: clientId,
+		Queue:    "queue-a",
+		JobSetId: "job-set",
+		Priority: 1,
+		PodSpec: &v1.PodSpec{
+			Containers: []v1.Container{{Name: "main", Image: "busybox:latest"}},
+		},
+	}
+}
@@ -0,0 +1,66 @@
+package armadactl
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common"
+	"github.com/armadaproject/armada/pkg/client"
+)
+
+// Exec runs a command in a container of the pod with the given podNumber of the job identified by jobId, queueName, and jobSetId.
+// The command is run via kubectl exec, tunnelled through the binoculars instance of the cluster the job is running on,
+// such that no direct access to executor clusters is needed.
+func (a *App) Exec(jobId string, queueName string, jobSetId string, podNumber int, container string, stdin bool, tty bool, command []string) error {
+	args := []string{"exec"}
+	if container != "" {
+		args = append(args, "--container", container)
+	}
+	if stdin {
+		args = append(args, "--stdin")
+	}
+	if tty {
+		args = append(args, "--tty")
+	}
+	args = append(args, podName(jobId, podNumber), "--")
+	args = append(args, command...)
+	return a.runKubectlViaBinoculars(jobId, queueName, jobSetId, args)
+}
+
+// PortForward forwards local ports to the pod with the given podNumber of the job identified by jobId, queueName, and jobSetId.
+// Ports are specified as for kubectl port-forward, e.g., "8888:80". Forwarding is done via kubectl port-forward,
+// tunnelled through the binoculars instance of the cluster the job is running on, until interrupted.
+func (a *App) PortForward(jobId string, queueName string, jobSetId string, podNumber int, ports []string) error {
+	args := append([]string{"port-forward", podName(jobId, podNumber)}, ports...)
+	return a.runKubectlViaBinoculars(jobId, queueName, jobSetId, args)
+}
+
+// runKubectlViaBinoculars runs kubectl with the given arguments against a local proxy to binoculars,
+// which authenticates requests with the credentials armadactl is configured with.
+func (a *App) runKubectlViaBinoculars(jobId string, queueName string, jobSetId string, args []string) error {
+	clusterId, namespace, err := a.getJobClusterAndNamespace(jobId, queueName, jobSetId)
+	if err != nil {
+		return err
+	}
+	proxyUrl, stopProxy, err := client.ServeBinocularsProxy(a.Params.ApiConnectionDetails, client.GetBinocularsHttpUrl(clusterId))
+	if err != nil {
+		return err
+	}
+	defer stopProxy()
+
+	cmd := exec.Command("kubectl", append([]string{"--server", proxyUrl, "--namespace", namespace}, args...)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = a.Out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "error running kubectl %s for job %s", args[0], jobId)
+	}
+	return nil
+}
+
+func podName(jobId string, podNumber int) string {
+	return fmt.Sprintf("%s%s-%d", common.PodNamePrefix, jobId, podNumber)
+}
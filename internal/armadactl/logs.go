@@ -0,0 +1,106 @@
+package armadactl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/armadaproject/armada/internal/common"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/pkg/api"
+	"github.com/armadaproject/armada/pkg/api/binoculars"
+	"github.com/armadaproject/armada/pkg/client"
+)
+
+// logsPollInterval is how often new log lines are requested when following logs.
+const logsPollInterval = time.Second
+
+// Logs prints the logs of the pod with the given podNumber of the job identified by jobId, queueName, and jobSetId.
+// Logs are fetched from the binoculars instance of the cluster the job was assigned to,
+// which retrieves them from Kubernetes on behalf of the user, such that no direct access to executor clusters is needed.
+// If follow is true, new log lines are printed as they become available until interrupted.
+func (a *App) Logs(jobId string, queueName string, jobSetId string, podNumber int, follow bool) error {
+	clusterId, namespace, err := a.getJobClusterAndNamespace(jobId, queueName, jobSetId)
+	if err != nil {
+		return err
+	}
+
+	return client.WithBinocularsClient(a.Params.ApiConnectionDetails, client.GetBinocularsUrl(clusterId), func(c binoculars.BinocularsClient) error {
+		var sinceTime string
+		for {
+			logLines, err := a.getLogs(c, jobId, podNumber, namespace, sinceTime)
+			if err != nil {
+				return err
+			}
+			for _, logLine := range logLines {
+				fmt.Fprintln(a.Out, logLine.Line)
+			}
+			if !follow {
+				return nil
+			}
+			if len(logLines) > 0 {
+				sinceTime = logLines[len(logLines)-1].Timestamp
+			}
+			time.Sleep(logsPollInterval)
+		}
+	})
+}
+
+// getJobClusterAndNamespace returns the id of the cluster the job was assigned to and the namespace of the job.
+func (a *App) getJobClusterAndNamespace(jobId string, queueName string, jobSetId string) (string, string, error) {
+	var clusterId, namespace string
+	err := client.WithEventClient(a.Params.ApiConnectionDetails, func(c api.EventClient) error {
+		state := client.GetJobSetState(c, queueName, jobSetId, armadacontext.Background(), true, false, false)
+		jobInfo := state.GetJobInfo(jobId)
+		if jobInfo == nil {
+			return errors.Errorf("could not find job %s", jobId)
+		}
+		if jobInfo.ClusterId == "" {
+			return errors.Errorf("job %s has not been assigned to a cluster yet", jobId)
+		}
+		clusterId = jobInfo.ClusterId
+		namespace = jobInfo.Job.Namespace
+		return nil
+	})
+	return clusterId, namespace, err
+}
+
+// getLogs returns the log lines of the pod logged after sinceTime.
+// If sinceTime is empty, all log lines are returned.
+func (a *App) getLogs(c binoculars.BinocularsClient, jobId string, podNumber int, namespace string, sinceTime string) ([]*binoculars.LogLine, error) {
+	ctx, cancel := common.ContextWithDefaultTimeout()
+	defer cancel()
+	res, err := c.Logs(ctx, &binoculars.LogRequest{
+		JobId:        jobId,
+		PodNumber:    int32(podNumber),
+		PodNamespace: namespace,
+		SinceTime:    sinceTime,
+		LogOptions:   &v1.PodLogOptions{},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting logs for job %s", jobId)
+	}
+	return logLinesAfter(res.Log, sinceTime), nil
+}
+
+// logLinesAfter returns the log lines with a timestamp strictly after sinceTime.
+// Needed since Kubernetes treats sinceTime as inclusive and truncates it to seconds.
+func logLinesAfter(logLines []*binoculars.LogLine, sinceTime string) []*binoculars.LogLine {
+	if sinceTime == "" {
+		return logLines
+	}
+	since, err := time.Parse(time.RFC3339Nano, sinceTime)
+	if err != nil {
+		return logLines
+	}
+	rv := make([]*binoculars.LogLine, 0, len(logLines))
+	for _, logLine := range logLines {
+		timestamp, err := time.Parse(time.RFC3339Nano, logLine.Timestamp)
+		if err != nil || timestamp.After(since) {
+			rv = append(rv, logLine)
+		}
+	}
+	return rv
+}
@@ -2,8 +2,10 @@ package armadactl
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/armadaproject/armada/internal/common"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
 	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
 	"github.com/armadaproject/armada/pkg/client"
 )
@@ -82,3 +84,18 @@ func (a *App) GetJobSchedulingReport(jobId string) error {
 		return nil
 	})
 }
+
+// TriggerSchedulingRound makes the scheduler run a scheduling round immediately, optionally restricted to one pool or queue,
+// and prints a summary of the decisions made. Since the round may take a while, the default timeout doesn't apply.
+func (a *App) TriggerSchedulingRound(pool string, queueName string, timeout time.Duration) error {
+	return client.WithSchedulerAdminClient(a.Params.ApiConnectionDetails, func(c schedulerobjects.SchedulerAdminClient) error {
+		ctx, cancel := armadacontext.WithTimeout(armadacontext.Background(), timeout)
+		defer cancel()
+		resp, err := c.TriggerSchedulingRound(ctx, &schedulerobjects.TriggerSchedulingRoundRequest{Pool: pool, Queue: queueName})
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(a.Out, resp.Report)
+		return nil
+	})
+}
@@ -29,14 +29,24 @@ func (a *App) Submit(path string, dryRun bool) error {
 	if dryRun {
 		return nil
 	}
+	return a.submitJobs([]*domain.JobSubmitFile{submitFile})
+}
 
-	requests := client.CreateChunkedSubmitRequests(submitFile.Queue, submitFile.JobSetId, submitFile.Jobs)
+// submitJobs submits the jobs of all submitFiles over a single connection.
+func (a *App) submitJobs(submitFiles []*domain.JobSubmitFile) error {
+	requests := make([]*api.JobSubmitRequest, 0, len(submitFiles))
+	for _, submitFile := range submitFiles {
+		requests = append(requests, client.CreateChunkedSubmitRequests(submitFile.Queue, submitFile.JobSetId, submitFile.Jobs)...)
+	}
 	return client.WithSubmitClient(a.Params.ApiConnectionDetails, func(c api.SubmitClient) error {
 		for _, request := range requests {
-			response, err := client.SubmitJobs(c, request)
+			response, warnings, err := client.SubmitJobsWithWarnings(c, request)
 			if err != nil {
 				return errors.WithMessagef(err, "error submitting request %#v", request)
 			}
+			for _, warning := range warnings {
+				fmt.Fprintf(a.Out, "Warning: %s\n", warning)
+			}
 
 			for _, jobResponseItem := range response.JobResponseItems {
 				if jobResponseItem.Error != "" {
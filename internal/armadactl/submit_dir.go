@@ -0,0 +1,212 @@
+package armadactl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/pkg/client/domain"
+	"github.com/armadaproject/armada/pkg/client/util"
+	"github.com/armadaproject/armada/pkg/client/validation"
+)
+
+// ManifestFileName is the name of the manifest SubmitFromDir reads from the directory it's given.
+const ManifestFileName = "manifest.yaml"
+
+// SubmitManifest describes the jobs to submit from a directory, e.g.,
+//
+//	queue: test
+//	jobSetId: set1
+//	submissions:
+//	- template: templates/train.yaml
+//	  values:
+//	  - values/small.yaml
+//	  - values/large.yaml
+//
+// Paths are relative to the directory containing the manifest.
+type SubmitManifest struct {
+	// Queue and job set used for rendered submit files that don't specify their own.
+	Queue    string
+	JobSetId string
+	// Each submission is rendered once per value file.
+	Submissions []*ManifestSubmission
+}
+
+// ManifestSubmission is a job template and the value files to render it with.
+type ManifestSubmission struct {
+	// Path to a Go template (https://pkg.go.dev/text/template) that renders to a submit file,
+	// as accepted by armadactl submit. The template can refer to .Values, .Queue, and .JobSetId,
+	// and use a subset of the sprig functions (see templateFuncs).
+	Template string
+	// Paths to YAML or JSON files containing values for the template.
+	// If empty, the template is rendered once with no values.
+	Values []string
+}
+
+// RenderedSubmitFile is a submit file rendered from a template.
+type RenderedSubmitFile struct {
+	Template string
+	// Path to the value file the template was rendered with, if any.
+	Values string
+	// The rendered template.
+	Data []byte
+	// The jobs parsed from the rendered template.
+	SubmitFile *domain.JobSubmitFile
+}
+
+// SubmitFromDir renders and validates all submissions of the manifest in dir, and then submits the resulting jobs.
+// If dryRun is true, the rendered submit files are printed instead of submitted.
+// Nothing is submitted unless all submissions render and validate successfully.
+func (a *App) SubmitFromDir(dir string, dryRun bool) error {
+	rendered, err := RenderManifest(dir)
+	if err != nil {
+		return err
+	}
+	submitFiles := make([]*domain.JobSubmitFile, len(rendered))
+	for i, r := range rendered {
+		if ok, err := validation.ValidateSubmitData(r.Data); !ok {
+			return errors.WithMessagef(err, "invalid jobs rendered from template %s with values %s", r.Template, r.Values)
+		}
+		submitFiles[i] = r.SubmitFile
+	}
+
+	if dryRun {
+		for _, r := range rendered {
+			fmt.Fprintf(a.Out, "---\n# Template: %s\n", r.Template)
+			if r.Values != "" {
+				fmt.Fprintf(a.Out, "# Values: %s\n", r.Values)
+			}
+			fmt.Fprintf(a.Out, "%s\n", strings.TrimSpace(string(r.Data)))
+		}
+		return nil
+	}
+	return a.submitJobs(submitFiles)
+}
+
+// RenderManifest renders all submissions of the manifest in dir.
+func RenderManifest(dir string) ([]*RenderedSubmitFile, error) {
+	manifest := &SubmitManifest{}
+	if err := util.BindJsonOrYaml(filepath.Join(dir, ManifestFileName), manifest); err != nil {
+		return nil, err
+	}
+	if len(manifest.Submissions) == 0 {
+		return nil, errors.Errorf("manifest in %s contains no submissions", dir)
+	}
+
+	var rendered []*RenderedSubmitFile
+	for _, submission := range manifest.Submissions {
+		templateData, err := os.ReadFile(filepath.Join(dir, submission.Template))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		tmpl, err := template.New(submission.Template).Funcs(templateFuncs).Parse(string(templateData))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to parse template %s", submission.Template)
+		}
+
+		valueFiles := submission.Values
+		if len(valueFiles) == 0 {
+			valueFiles = []string{""}
+		}
+		for _, valueFile := range valueFiles {
+			values := map[string]interface{}{}
+			if valueFile != "" {
+				if err := util.BindJsonOrYaml(filepath.Join(dir, valueFile), &values); err != nil {
+					return nil, err
+				}
+			}
+			r, err := renderSubmitFile(tmpl, manifest, values)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed to render template %s with values %s", submission.Template, valueFile)
+			}
+			r.Template = submission.Template
+			r.Values = valueFile
+			rendered = append(rendered, r)
+		}
+	}
+	return rendered, nil
+}
+
+func renderSubmitFile(tmpl *template.Template, manifest *SubmitManifest, values map[string]interface{}) (*RenderedSubmitFile, error) {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, map[string]interface{}{
+		"Values":   values,
+		"Queue":    manifest.Queue,
+		"JobSetId": manifest.JobSetId,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	submitFile := &domain.JobSubmitFile{}
+	if err := util.BindJsonOrYamlBytes(buf.Bytes(), submitFile); err != nil {
+		return nil, err
+	}
+	if submitFile.Queue == "" {
+		submitFile.Queue = manifest.Queue
+	}
+	if submitFile.JobSetId == "" {
+		submitFile.JobSetId = manifest.JobSetId
+	}
+	if submitFile.Queue == "" || submitFile.JobSetId == "" {
+		return nil, errors.New("queue and job set must be specified by either the manifest or the template")
+	}
+	return &RenderedSubmitFile{Data: buf.Bytes(), SubmitFile: submitFile}, nil
+}
+
+// templateFuncs are the functions available to job templates,
+// named and behaving like their counterparts in https://github.com/Masterminds/sprig.
+var templateFuncs = template.FuncMap{
+	"default": func(defaultValue interface{}, value ...interface{}) interface{} {
+		if len(value) == 0 || isEmpty(value[0]) {
+			return defaultValue
+		}
+		return value[0]
+	},
+	"required": func(message string, value interface{}) (interface{}, error) {
+		if isEmpty(value) {
+			return nil, errors.New(message)
+		}
+		return value, nil
+	},
+	"quote": func(value interface{}) string {
+		return fmt.Sprintf("%q", fmt.Sprint(value))
+	},
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"trim":    strings.TrimSpace,
+	"replace": func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"indent":  indent,
+	"nindent": func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+	"toJson": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
@@ -0,0 +1,91 @@
+package armadactl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTemplate = `jobs:
+- priority: {{ .Values.priority | default 1 }}
+  namespace: {{ .Queue }}
+  podSpec:
+    containers:
+    - name: {{ required "name is required" .Values.name | lower }}
+      image: busybox:latest
+      args: {{ .Values.args | toJson }}
+`
+
+func TestRenderManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ManifestFileName, `
+queue: test
+jobSetId: set1
+submissions:
+- template: job.yaml
+  values:
+  - small.yaml
+  - large.yaml
+`)
+	writeFile(t, dir, "job.yaml", testTemplate)
+	writeFile(t, dir, "small.yaml", "name: Small\nargs: [sleep, 1s]\n")
+	writeFile(t, dir, "large.yaml", "name: Large\npriority: 5\nargs: [sleep, 60s]\n")
+
+	rendered, err := RenderManifest(dir)
+	require.NoError(t, err)
+	require.Len(t, rendered, 2)
+
+	small, large := rendered[0], rendered[1]
+	assert.Equal(t, "small.yaml", small.Values)
+	assert.Equal(t, "test", small.SubmitFile.Queue)
+	assert.Equal(t, "set1", small.SubmitFile.JobSetId)
+	require.Len(t, small.SubmitFile.Jobs, 1)
+	assert.Equal(t, 1.0, small.SubmitFile.Jobs[0].Priority)
+	assert.Equal(t, "test", small.SubmitFile.Jobs[0].Namespace)
+	assert.Equal(t, "small", small.SubmitFile.Jobs[0].PodSpec.Containers[0].Name)
+	assert.Equal(t, []string{"sleep", "1s"}, small.SubmitFile.Jobs[0].PodSpec.Containers[0].Args)
+
+	assert.Equal(t, "large.yaml", large.Values)
+	assert.Equal(t, 5.0, large.SubmitFile.Jobs[0].Priority)
+	assert.Equal(t, "large", large.SubmitFile.Jobs[0].PodSpec.Containers[0].Name)
+}
+
+func TestRenderManifest_Errors(t *testing.T) {
+	tests := map[string]struct {
+		manifest string
+		values   string
+	}{
+		"no submissions": {
+			manifest: "queue: test\njobSetId: set1\n",
+		},
+		"missing required value": {
+			manifest: "queue: test\njobSetId: set1\nsubmissions:\n- template: job.yaml\n  values: [values.yaml]\n",
+			values:   "args: [sleep]\n",
+		},
+		"missing queue": {
+			manifest: "jobSetId: set1\nsubmissions:\n- template: job.yaml\n  values: [values.yaml]\n",
+			values:   "name: job\n",
+		},
+		"missing template": {
+			manifest: "queue: test\njobSetId: set1\nsubmissions:\n- template: missing.yaml\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, dir, ManifestFileName, tc.manifest)
+			writeFile(t, dir, "job.yaml", testTemplate)
+			writeFile(t, dir, "values.yaml", tc.values)
+
+			_, err := RenderManifest(dir)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func writeFile(t *testing.T, dir string, name string, contents string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644))
+}
@@ -7,6 +7,7 @@ import (
 
 type BinocularsConfig struct {
 	Cordon CordonConfiguration
+	Debug  DebugConfiguration
 	Auth   configuration.AuthConfig
 
 	GrpcPort    uint16
@@ -27,6 +28,14 @@ type KubernetesConfiguration struct {
 	QPS   float32
 }
 
+type DebugConfiguration struct {
+	// If true, users may exec into and port-forward to the pods of running jobs via binoculars.
+	Enabled bool
+	// Groups whose members may debug the jobs of a given queue, indexed by queue name.
+	// Users may always debug jobs they own.
+	QueueGroups map[string][]string
+}
+
 type CordonConfiguration struct {
 	AdditionalLabels map[string]string
 }
@@ -0,0 +1,211 @@
+package debug
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/armadaproject/armada/internal/armada/permissions"
+	"github.com/armadaproject/armada/internal/binoculars/configuration"
+	"github.com/armadaproject/armada/internal/common"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/auth/authorization"
+	"github.com/armadaproject/armada/internal/common/cluster"
+	"github.com/armadaproject/armada/internal/executor/domain"
+)
+
+// PodsPathPrefix is the prefix of the Kubernetes API paths served by the HttpHandler.
+const PodsPathPrefix = "/api/v1/namespaces/"
+
+const (
+	execSubresource        = "exec"
+	portForwardSubresource = "portforward"
+)
+
+// HttpHandler tunnels requests to exec into and port-forward to the pods of Armada jobs
+// to the Kubernetes API server of the cluster binoculars is running in, such that users can debug running jobs,
+// e.g., via kubectl exec and kubectl port-forward, without access to the cluster itself.
+//
+// Requests use the same paths as the Kubernetes API, i.e.,
+//
+//	GET       /api/v1/namespaces/<namespace>/pods/<pod>
+//	GET, POST /api/v1/namespaces/<namespace>/pods/<pod>/exec
+//	GET, POST /api/v1/namespaces/<namespace>/pods/<pod>/portforward
+//
+// and are authenticated using the same authentication services as the gRPC API.
+// Users may debug jobs they own, jobs in queues for which they're a member of one of the groups in config.QueueGroups,
+// or any job if they have the debug_any_jobs permission. All access is logged.
+type HttpHandler struct {
+	config            configuration.DebugConfiguration
+	authServices      []authorization.AuthService
+	permissionChecker authorization.PermissionChecker
+	clientProvider    cluster.KubernetesClientProvider
+	impersonateUsers  bool
+}
+
+func NewHttpHandler(
+	config configuration.DebugConfiguration,
+	authServices []authorization.AuthService,
+	permissionChecker authorization.PermissionChecker,
+	clientProvider cluster.KubernetesClientProvider,
+	impersonateUsers bool,
+) *HttpHandler {
+	return &HttpHandler{
+		config:            config,
+		authServices:      authServices,
+		permissionChecker: permissionChecker,
+		clientProvider:    clientProvider,
+		impersonateUsers:  impersonateUsers,
+	}
+}
+
+// RegisterRoutes registers the handler with mux.
+func (h *HttpHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle(PodsPathPrefix, h)
+}
+
+func (h *HttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	namespace, podName, subresource, ok := parsePodPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if subresource == "" && r.Method != http.MethodGet || subresource != "" && r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !strings.HasPrefix(podName, common.PodNamePrefix) {
+		http.Error(w, "only pods of Armada jobs may be accessed", http.StatusForbidden)
+		return
+	}
+
+	authCtx, err := authorization.AuthenticateHttpRequest(r, h.authServices)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	principal := authorization.GetPrincipal(authCtx)
+	ctx := armadacontext.New(authCtx, log.WithFields(log.Fields{
+		"user":        principal.GetName(),
+		"namespace":   namespace,
+		"pod":         podName,
+		"subresource": subresource,
+	}))
+
+	pod, err := h.clientProvider.Client().CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		ctx.Errorf("failed to get pod: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx = armadacontext.WithLogField(ctx, "queue", pod.Labels[domain.Queue])
+	ctx = armadacontext.WithLogField(ctx, "jobId", pod.Labels[domain.JobId])
+	if err := h.authorize(ctx, pod); err != nil {
+		ctx.Warnf("denied debug access: %s", err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	switch subresource {
+	case "":
+		ctx.Info("granted read access")
+	case execSubresource:
+		ctx.Infof("granted exec access with command %q", r.URL.Query()["command"])
+	case portForwardSubresource:
+		ctx.Info("granted port-forward access")
+	}
+
+	proxy, err := h.newProxy(principal)
+	if err != nil {
+		ctx.Errorf("failed to create proxy: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	proxy.ServeHTTP(w, r)
+}
+
+// authorize returns nil if the principal of ctx may debug the given pod and an error otherwise.
+func (h *HttpHandler) authorize(ctx *armadacontext.Context, pod *v1.Pod) error {
+	principal := authorization.GetPrincipal(ctx)
+	if h.permissionChecker.UserHasPermission(ctx, permissions.DebugAnyJobs) {
+		return nil
+	}
+	if owner, ok := pod.Annotations[domain.Owner]; ok && owner == principal.GetName() {
+		return nil
+	}
+	queue := pod.Labels[domain.Queue]
+	for _, group := range principal.GetGroupNames() {
+		if slices.Contains(h.config.QueueGroups[queue], group) {
+			return nil
+		}
+	}
+	return errors.WithStack(&armadaerrors.ErrUnauthorized{
+		Principal:  principal.GetName(),
+		Permission: permissions.DebugAnyJobs,
+		Action:     "debug jobs in queue " + queue,
+		Message:    "user neither owns the job nor is in a group allowed to debug jobs in the queue",
+	})
+}
+
+// newProxy returns a reverse proxy to the Kubernetes API server, impersonating the principal if configured to do so.
+func (h *HttpHandler) newProxy(principal authorization.Principal) (*httputil.ReverseProxy, error) {
+	restConfig := rest.CopyConfig(h.clientProvider.ClientConfig())
+	if h.impersonateUsers {
+		restConfig.Impersonate = rest.ImpersonationConfig{UserName: principal.GetName(), Groups: principal.GetGroupNames()}
+	}
+	transport, err := rest.TransportFor(restConfig)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	target, err := url.Parse(restConfig.Host)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	director := proxy.Director
+	proxy.Director = func(r *http.Request) {
+		director(r)
+		// Credentials provided by the user are for binoculars and must not be forwarded;
+		// the transport instead authenticates as binoculars, optionally impersonating the user.
+		r.Header.Del("Authorization")
+		for key := range r.Header {
+			if strings.HasPrefix(strings.ToLower(key), "impersonate-") {
+				r.Header.Del(key)
+			}
+		}
+	}
+	proxy.Transport = transport
+	return proxy, nil
+}
+
+// parsePodPath parses paths of the form /api/v1/namespaces/<namespace>/pods/<pod>[/<subresource>],
+// where subresource is either exec or portforward.
+func parsePodPath(path string) (namespace string, pod string, subresource string, ok bool) {
+	if !strings.HasPrefix(path, PodsPathPrefix) {
+		return "", "", "", false
+	}
+	parts := strings.Split(strings.TrimPrefix(path, PodsPathPrefix), "/")
+	if len(parts) < 3 || len(parts) > 4 || parts[1] != "pods" || parts[0] == "" || parts[2] == "" {
+		return "", "", "", false
+	}
+	if len(parts) == 4 {
+		if parts[3] != execSubresource && parts[3] != portForwardSubresource {
+			return "", "", "", false
+		}
+		subresource = parts[3]
+	}
+	return parts[0], parts[2], subresource, true
+}
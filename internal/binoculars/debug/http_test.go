@@ -0,0 +1,59 @@
+package debug
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePodPath(t *testing.T) {
+	tests := map[string]struct {
+		path                string
+		expectedNamespace   string
+		expectedPod         string
+		expectedSubresource string
+		expectedOk          bool
+	}{
+		"pod": {
+			path:              "/api/v1/namespaces/default/pods/armada-foo-0",
+			expectedNamespace: "default",
+			expectedPod:       "armada-foo-0",
+			expectedOk:        true,
+		},
+		"exec": {
+			path:                "/api/v1/namespaces/default/pods/armada-foo-0/exec",
+			expectedNamespace:   "default",
+			expectedPod:         "armada-foo-0",
+			expectedSubresource: "exec",
+			expectedOk:          true,
+		},
+		"portforward": {
+			path:                "/api/v1/namespaces/default/pods/armada-foo-0/portforward",
+			expectedNamespace:   "default",
+			expectedPod:         "armada-foo-0",
+			expectedSubresource: "portforward",
+			expectedOk:          true,
+		},
+		"unsupported subresource": {
+			path: "/api/v1/namespaces/default/pods/armada-foo-0/attach",
+		},
+		"not a pod": {
+			path: "/api/v1/namespaces/default/secrets/foo",
+		},
+		"pod list": {
+			path: "/api/v1/namespaces/default/pods",
+		},
+		"other path": {
+			path: "/v1/binoculars/log",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			namespace, pod, subresource, ok := parsePodPath(tc.path)
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.expectedNamespace, namespace)
+			assert.Equal(t, tc.expectedPod, pod)
+			assert.Equal(t, tc.expectedSubresource, subresource)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package binoculars
 
 import (
+	"net/http"
 	"os"
 	"sync"
 
@@ -8,6 +9,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/armadaproject/armada/internal/binoculars/configuration"
+	"github.com/armadaproject/armada/internal/binoculars/debug"
 	"github.com/armadaproject/armada/internal/binoculars/server"
 	"github.com/armadaproject/armada/internal/binoculars/service"
 	"github.com/armadaproject/armada/internal/common/auth"
@@ -17,7 +19,7 @@ import (
 	"github.com/armadaproject/armada/pkg/api/binoculars"
 )
 
-func StartUp(config *configuration.BinocularsConfig) (func(), *sync.WaitGroup) {
+func StartUp(config *configuration.BinocularsConfig, mux *http.ServeMux) (func(), *sync.WaitGroup) {
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
 
@@ -37,7 +39,11 @@ func StartUp(config *configuration.BinocularsConfig) (func(), *sync.WaitGroup) {
 		os.Exit(-1)
 	}
 
-	grpcServer := grpcCommon.CreateGrpcServer(config.Grpc.KeepaliveParams, config.Grpc.KeepaliveEnforcementPolicy, authServices, config.Grpc.Tls)
+	grpcServer, err := grpcCommon.CreateGrpcServer(config.Grpc.KeepaliveParams, config.Grpc.KeepaliveEnforcementPolicy, authServices, config.Grpc.Tls)
+	if err != nil {
+		log.Errorf("Failed to create grpc server %s", err)
+		os.Exit(-1)
+	}
 
 	permissionsChecker := authorization.NewPrincipalPermissionChecker(
 		config.Auth.PermissionGroupMapping,
@@ -51,6 +57,10 @@ func StartUp(config *configuration.BinocularsConfig) (func(), *sync.WaitGroup) {
 	binoculars.RegisterBinocularsServer(grpcServer, binocularsServer)
 	grpc_prometheus.Register(grpcServer)
 
+	if config.Debug.Enabled {
+		debug.NewHttpHandler(config.Debug, authServices, permissionsChecker, kubernetesClientProvider, config.ImpersonateUsers).RegisterRoutes(mux)
+	}
+
 	grpcCommon.Listen(config.GrpcPort, grpcServer, wg)
 
 	return grpcServer.GracefulStop, wg
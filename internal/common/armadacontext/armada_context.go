@@ -56,6 +56,36 @@ func WithCancel(parent *Context) (*Context, context.CancelFunc) {
 	}, cancel
 }
 
+// WithoutCancel returns a copy of parent that isn't cancelled when parent is, e.g., to complete work after a shutdown is requested.
+// It is analogous to context.WithoutCancel()
+func WithoutCancel(parent *Context) *Context {
+	return &Context{
+		Context:     withoutCancelCtx{parent: parent.Context},
+		FieldLogger: parent.FieldLogger,
+	}
+}
+
+// withoutCancelCtx retains the values, but not the deadline or cancellation, of its parent.
+type withoutCancelCtx struct {
+	parent context.Context
+}
+
+func (withoutCancelCtx) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (withoutCancelCtx) Done() <-chan struct{} {
+	return nil
+}
+
+func (withoutCancelCtx) Err() error {
+	return nil
+}
+
+func (c withoutCancelCtx) Value(key any) any {
+	return c.parent.Value(key)
+}
+
 // WithDeadline returns a copy of the parent context with the deadline adjusted to be no later than d.
 // It is analogous to context.WithDeadline()
 func WithDeadline(parent *Context, d time.Time) (*Context, context.CancelFunc) {
@@ -63,6 +63,17 @@ func TestWithValue(t *testing.T) {
 	require.Equal(t, "bar", ctx.Value("foo"))
 }
 
+func TestWithoutCancel(t *testing.T) {
+	parent, cancel := WithCancel(WithValue(WithLogField(Background(), "fish", "chips"), "foo", "bar"))
+	ctx := WithoutCancel(parent)
+	cancel()
+	require.Error(t, parent.Err())
+	require.NoError(t, ctx.Err())
+	require.Nil(t, ctx.Done())
+	require.Equal(t, "bar", ctx.Value("foo"))
+	require.Equal(t, parent.FieldLogger, ctx.FieldLogger)
+}
+
 func testDeadline(t *testing.T, c *Context) {
 	t.Helper()
 	d := quiescent(t)
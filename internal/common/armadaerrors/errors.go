@@ -112,6 +112,22 @@ func (err *ErrInvalidArgument) Error() string {
 	return fmt.Sprintf("value %q is invalid for field %q: %s", err.Value, err.Name, err.Message)
 }
 
+// ErrRateLimited is returned when a client exceeds a rate limit, e.g., on job submission.
+// Clients should retry after backing off.
+type ErrRateLimited struct {
+	Principal string // User or queue the limit applies to
+	Limit     string // Name of the exceeded limit, e.g., "jobs per second"
+	Message   string // An optional message to include with the error message
+}
+
+func (err *ErrRateLimited) Error() string {
+	s := fmt.Sprintf("%s exceeded rate limit on %s", err.Principal, err.Limit)
+	if err.Message != "" {
+		s += fmt.Sprintf("; %s", err.Message)
+	}
+	return s
+}
+
 // ErrMaxRetriesExceeded is an error that indicates we have retried an operation so many times that we have given up
 // The internal error should contain the last error before giving up
 type ErrMaxRetriesExceeded struct {
@@ -277,6 +293,12 @@ func CodeFromError(err error) codes.Code {
 			return codes.InvalidArgument
 		}
 	}
+	{
+		var e *ErrRateLimited
+		if errors.As(err, &e) {
+			return codes.ResourceExhausted
+		}
+	}
 
 	return codes.Unknown
 }
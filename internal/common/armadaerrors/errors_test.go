@@ -31,6 +31,7 @@ func TestCodeFromError(t *testing.T) {
 		"pkg.Error => ErrAlreadyExists":   {errors.WithMessage(&ErrAlreadyExists{}, "foo"), codes.AlreadyExists},
 		"pkg.Error => ErrNotFound":        {errors.WithMessage(&ErrNotFound{}, "foo"), codes.NotFound},
 		"pkg.Error => ErrInvalidArgument": {errors.WithMessage(&ErrInvalidArgument{}, "foo"), codes.InvalidArgument},
+		"ErrRateLimited":                  {errors.WithMessage(&ErrRateLimited{}, "foo"), codes.ResourceExhausted},
 		"pkg.Error":                       {errors.New("foo"), codes.Unknown},
 		"nil":                             {nil, codes.OK},
 		"gRPC status":                     {status.New(codes.Internal, "foo").Err(), codes.Internal},
@@ -0,0 +1,62 @@
+package authorization
+
+import (
+	"context"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/auth/configuration"
+)
+
+// ClientCertAuthService authenticates clients by the TLS client certificate they connected with (mTLS).
+// The certificate is verified against the client CAs of the gRPC server during the TLS handshake;
+// hence, this service only has to extract the principal from it.
+type ClientCertAuthService struct {
+	config configuration.ClientCertAuthConfig
+}
+
+func NewClientCertAuthService(config configuration.ClientCertAuthConfig) *ClientCertAuthService {
+	return &ClientCertAuthService{config: config}
+}
+
+func (authService *ClientCertAuthService) Name() string {
+	return "ClientCert"
+}
+
+func (authService *ClientCertAuthService) Authenticate(ctx context.Context) (Principal, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, &armadaerrors.ErrMissingCredentials{AuthService: authService.Name()}
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil, &armadaerrors.ErrMissingCredentials{AuthService: authService.Name()}
+	}
+	cert := tlsInfo.State.VerifiedChains[0][0]
+
+	name := cert.Subject.CommonName
+	if authService.config.UseEmailAsUserName {
+		if len(cert.EmailAddresses) == 0 {
+			return nil, &armadaerrors.ErrInvalidCredentials{
+				Username:    name,
+				AuthService: authService.Name(),
+				Message:     "client certificate contains no email address",
+			}
+		}
+		name = cert.EmailAddresses[0]
+	}
+	if name == "" {
+		return nil, &armadaerrors.ErrInvalidCredentials{
+			AuthService: authService.Name(),
+			Message:     "client certificate contains no username",
+		}
+	}
+
+	var groups []string
+	if authService.config.GroupsFromOrganizationalUnits {
+		groups = cert.Subject.OrganizationalUnit
+	}
+	return NewStaticPrincipal(name, groups), nil
+}
@@ -0,0 +1,21 @@
+package authorization
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// AuthenticateHttpRequest authenticates a plain (i.e., non-gRPC) http request using the given authentication services.
+// Request headers are passed to the services as gRPC metadata, in the same way the gRPC gateway does.
+// On success, the returned context contains the authenticated principal.
+func AuthenticateHttpRequest(r *http.Request, authServices []AuthService) (context.Context, error) {
+	md := metadata.MD{}
+	for key, values := range r.Header {
+		md.Append(strings.ToLower(key), values...)
+	}
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+	return CreateMiddlewareAuthFunction(authServices)(ctx)
+}
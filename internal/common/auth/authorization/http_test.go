@@ -0,0 +1,32 @@
+package authorization
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/auth/configuration"
+)
+
+func TestAuthenticateHttpRequest(t *testing.T) {
+	authServices := []AuthService{NewBasicAuthService(map[string]configuration.UserInfo{
+		"root": {"toor", []string{"admins"}},
+	})}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.SetBasicAuth("root", "toor")
+	ctx, err := AuthenticateHttpRequest(r, authServices)
+	require.NoError(t, err)
+	assert.Equal(t, "root", GetPrincipal(ctx).GetName())
+	assert.True(t, GetPrincipal(ctx).IsInGroup("admins"))
+
+	r = httptest.NewRequest("GET", "/", nil)
+	r.SetBasicAuth("root", "wrong")
+	_, err = AuthenticateHttpRequest(r, authServices)
+	assert.Error(t, err)
+
+	_, err = AuthenticateHttpRequest(httptest.NewRequest("GET", "/", nil), authServices)
+	assert.Error(t, err)
+}
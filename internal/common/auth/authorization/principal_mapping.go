@@ -0,0 +1,101 @@
+package authorization
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common/auth/configuration"
+)
+
+// PrincipalMappingAuthService authenticates using another AuthService and rewrites the principals it returns,
+// such that several auth methods can be used side by side while mapping their users onto the same principals.
+type PrincipalMappingAuthService struct {
+	authService AuthService
+	nameRules   []nameMappingRule
+	groupPrefix string
+	extraGroups []string
+}
+
+type nameMappingRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// WithPrincipalMapping returns an AuthService that rewrites the principals authenticated by authService according to config.
+// If config contains no rules, authService is returned unchanged.
+func WithPrincipalMapping(authService AuthService, config configuration.PrincipalMappingConfig) (AuthService, error) {
+	if len(config.NameRules) == 0 && config.GroupPrefix == "" && len(config.ExtraGroups) == 0 {
+		return authService, nil
+	}
+	nameRules := make([]nameMappingRule, len(config.NameRules))
+	for i, rule := range config.NameRules {
+		// Anchor the pattern, such that rules match entire usernames only.
+		pattern, err := regexp.Compile("^(?:" + rule.Pattern + ")$")
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid principal mapping pattern for %s", authService.Name())
+		}
+		nameRules[i] = nameMappingRule{pattern: pattern, replacement: rule.Replacement}
+	}
+	return &PrincipalMappingAuthService{
+		authService: authService,
+		nameRules:   nameRules,
+		groupPrefix: config.GroupPrefix,
+		extraGroups: config.ExtraGroups,
+	}, nil
+}
+
+func (authService *PrincipalMappingAuthService) Name() string {
+	return authService.authService.Name()
+}
+
+func (authService *PrincipalMappingAuthService) Authenticate(ctx context.Context) (Principal, error) {
+	principal, err := authService.authService.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := principal.GetName()
+	for _, rule := range authService.nameRules {
+		if rule.pattern.MatchString(name) {
+			name = rule.pattern.ReplaceAllString(name, rule.replacement)
+			break
+		}
+	}
+
+	groups := make(map[string]bool)
+	for _, group := range principal.GetGroupNames() {
+		if group != EveryoneGroup {
+			group = authService.groupPrefix + group
+		}
+		groups[group] = true
+	}
+	for _, group := range authService.extraGroups {
+		groups[group] = true
+	}
+	return &mappedPrincipal{Principal: principal, name: name, groups: groups}, nil
+}
+
+// mappedPrincipal overrides the name and groups of a principal, retaining its scopes and claims.
+type mappedPrincipal struct {
+	Principal
+	name   string
+	groups map[string]bool
+}
+
+func (p *mappedPrincipal) GetName() string {
+	return p.name
+}
+
+func (p *mappedPrincipal) GetGroupNames() []string {
+	names := make([]string, 0, len(p.groups))
+	for g := range p.groups {
+		names = append(names, g)
+	}
+	return names
+}
+
+func (p *mappedPrincipal) IsInGroup(group string) bool {
+	return p.groups[group]
+}
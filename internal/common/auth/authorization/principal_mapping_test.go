@@ -0,0 +1,152 @@
+package authorization
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/auth/configuration"
+)
+
+func TestWithPrincipalMapping(t *testing.T) {
+	tests := map[string]struct {
+		config         configuration.PrincipalMappingConfig
+		principal      Principal
+		expectedName   string
+		expectedGroups []string
+	}{
+		"no mapping": {
+			principal:      NewStaticPrincipal("alice@CORP.EXAMPLE.COM", []string{"admins"}),
+			expectedName:   "alice@CORP.EXAMPLE.COM",
+			expectedGroups: []string{"admins", EveryoneGroup},
+		},
+		"first matching rule applies": {
+			config: configuration.PrincipalMappingConfig{
+				NameRules: []configuration.NameMappingRule{
+					{Pattern: "svc-.*", Replacement: "service"},
+					{Pattern: `(.*)@CORP\.EXAMPLE\.COM`, Replacement: "$1"},
+					{Pattern: "alice", Replacement: "bob"},
+				},
+			},
+			principal:      NewStaticPrincipal("alice@CORP.EXAMPLE.COM", []string{"admins"}),
+			expectedName:   "alice",
+			expectedGroups: []string{"admins", EveryoneGroup},
+		},
+		"patterns match entire names only": {
+			config: configuration.PrincipalMappingConfig{
+				NameRules: []configuration.NameMappingRule{{Pattern: "alice", Replacement: "bob"}},
+			},
+			principal:      NewStaticPrincipal("alice@CORP.EXAMPLE.COM", nil),
+			expectedName:   "alice@CORP.EXAMPLE.COM",
+			expectedGroups: []string{EveryoneGroup},
+		},
+		"group prefix and extra groups": {
+			config: configuration.PrincipalMappingConfig{
+				GroupPrefix: "kerberos:",
+				ExtraGroups: []string{"corp"},
+			},
+			principal:      NewStaticPrincipal("alice", []string{"admins"}),
+			expectedName:   "alice",
+			expectedGroups: []string{"kerberos:admins", "corp", EveryoneGroup},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			authService, err := WithPrincipalMapping(&fakeAuthService{principal: tc.principal}, tc.config)
+			require.NoError(t, err)
+
+			principal, err := authService.Authenticate(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedName, principal.GetName())
+			assert.ElementsMatch(t, tc.expectedGroups, principal.GetGroupNames())
+			for _, group := range tc.expectedGroups {
+				assert.True(t, principal.IsInGroup(group))
+			}
+		})
+	}
+}
+
+func TestWithPrincipalMapping_PassesThroughErrors(t *testing.T) {
+	config := configuration.PrincipalMappingConfig{ExtraGroups: []string{"corp"}}
+	authService, err := WithPrincipalMapping(&fakeAuthService{err: &armadaerrors.ErrMissingCredentials{}}, config)
+	require.NoError(t, err)
+
+	_, err = authService.Authenticate(context.Background())
+	var missingCredsErr *armadaerrors.ErrMissingCredentials
+	assert.True(t, errors.As(err, &missingCredsErr))
+}
+
+func TestWithPrincipalMapping_InvalidPattern(t *testing.T) {
+	config := configuration.PrincipalMappingConfig{
+		NameRules: []configuration.NameMappingRule{{Pattern: "(", Replacement: "bob"}},
+	}
+	_, err := WithPrincipalMapping(&fakeAuthService{}, config)
+	assert.Error(t, err)
+}
+
+func TestClientCertAuthService(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:         "alice",
+			OrganizationalUnit: []string{"admins"},
+		},
+		EmailAddresses: []string{"alice@example.com"},
+	}
+	tests := map[string]struct {
+		config         configuration.ClientCertAuthConfig
+		ctx            context.Context
+		expectedName   string
+		expectedGroups []string
+		expectMissing  bool
+	}{
+		"common name": {
+			ctx:            contextWithClientCert(cert),
+			expectedName:   "alice",
+			expectedGroups: []string{EveryoneGroup},
+		},
+		"email and groups": {
+			config:         configuration.ClientCertAuthConfig{UseEmailAsUserName: true, GroupsFromOrganizationalUnits: true},
+			ctx:            contextWithClientCert(cert),
+			expectedName:   "alice@example.com",
+			expectedGroups: []string{"admins", EveryoneGroup},
+		},
+		"no certificate": {
+			ctx:           contextWithClientCert(nil),
+			expectMissing: true,
+		},
+		"no peer": {
+			ctx:           context.Background(),
+			expectMissing: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			principal, err := NewClientCertAuthService(tc.config).Authenticate(tc.ctx)
+			if tc.expectMissing {
+				var missingCredsErr *armadaerrors.ErrMissingCredentials
+				assert.True(t, errors.As(err, &missingCredsErr))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedName, principal.GetName())
+			assert.ElementsMatch(t, tc.expectedGroups, principal.GetGroupNames())
+		})
+	}
+}
+
+func contextWithClientCert(cert *x509.Certificate) context.Context {
+	state := tls.ConnectionState{}
+	if cert != nil {
+		state.VerifiedChains = [][]*x509.Certificate{{cert}}
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+}
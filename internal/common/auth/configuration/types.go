@@ -13,6 +13,7 @@ type AuthConfig struct {
 	KubernetesAuth KubernetesAuthConfig
 	OpenIdAuth     OpenIdAuthenticationConfig
 	Kerberos       KerberosAuthenticationConfig
+	ClientCertAuth ClientCertAuthConfig
 
 	PermissionGroupMapping map[permission.Permission][]string
 	PermissionScopeMapping map[permission.Permission][]string
@@ -33,18 +34,22 @@ type OpenIdAuthenticationConfig struct {
 	// Otherwise clientId is required
 	SkipClientIDCheck bool
 	ClientId          string
+
+	PrincipalMapping PrincipalMappingConfig
 }
 
 type BasicAuthenticationConfig struct {
-	Users map[string]UserInfo
+	Users            map[string]UserInfo
+	PrincipalMapping PrincipalMappingConfig
 }
 
 type KerberosAuthenticationConfig struct {
-	KeytabLocation  string
-	PrincipalName   string
-	UserNameSuffix  string
-	GroupNameSuffix string
-	LDAP            LDAPConfig
+	KeytabLocation   string
+	PrincipalName    string
+	UserNameSuffix   string
+	GroupNameSuffix  string
+	LDAP             LDAPConfig
+	PrincipalMapping PrincipalMappingConfig
 }
 
 type LDAPConfig struct {
@@ -58,4 +63,37 @@ type LDAPConfig struct {
 type KubernetesAuthConfig struct {
 	KidMappingFileLocation string
 	InvalidTokenExpiry     int64
+	PrincipalMapping       PrincipalMappingConfig
+}
+
+// ClientCertAuthConfig configures authentication via TLS client certificates (mTLS).
+// Client certificates are only requested and verified by the gRPC server if grpc.tls.clientCAPath is set.
+type ClientCertAuthConfig struct {
+	Enabled bool
+	// If true, the first email address among the subject alternative names of the certificate is used as username.
+	// Otherwise, the common name of the certificate subject is used.
+	UseEmailAsUserName bool
+	// If true, the organizational units of the certificate subject are used as groups.
+	GroupsFromOrganizationalUnits bool
+	PrincipalMapping              PrincipalMappingConfig
+}
+
+// PrincipalMappingConfig rewrites the principals authenticated by a particular auth method,
+// such that principals from different identity providers can be reconciled, e.g.,
+// "alice@CORP.EXAMPLE.COM" from Kerberos and "alice@example.com" from OIDC can both be mapped to "alice".
+type PrincipalMappingConfig struct {
+	// Rules are tried in order and the first rule whose pattern matches the username replaces it.
+	// If no rule matches, the username is unchanged.
+	NameRules []NameMappingRule
+	// Prefix added to each group name, e.g., to keep apart groups of the same name from different providers.
+	GroupPrefix string
+	// Groups added to every principal authenticated by this method.
+	ExtraGroups []string
+}
+
+type NameMappingRule struct {
+	// Regular expression matched against the entire username.
+	Pattern string
+	// Replacement for the username, which may refer to capture groups of Pattern, e.g., "$1".
+	Replacement string
 }
@@ -10,17 +10,33 @@ import (
 	"github.com/armadaproject/armada/internal/common/auth/configuration"
 )
 
+// ConfigureAuth returns the chain of auth services enabled by config.
+// Requests are authenticated by the first service in the chain for which the request contains credentials;
+// hence, any number of methods can be enabled at the same time.
+// The principals authenticated by each method are rewritten according to the principal mapping of that method.
 func ConfigureAuth(config configuration.AuthConfig) ([]authorization.AuthService, error) {
 	var authServices []authorization.AuthService
+	appendService := func(authService authorization.AuthService, mapping configuration.PrincipalMappingConfig) error {
+		mappedAuthService, err := authorization.WithPrincipalMapping(authService, mapping)
+		if err != nil {
+			return err
+		}
+		authServices = append(authServices, mappedAuthService)
+		return nil
+	}
 
 	if len(config.BasicAuth.Users) > 0 {
-		authServices = append(authServices,
-			authorization.NewBasicAuthService(config.BasicAuth.Users))
+		err := appendService(authorization.NewBasicAuthService(config.BasicAuth.Users), config.BasicAuth.PrincipalMapping)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if config.KubernetesAuth.KidMappingFileLocation != "" {
 		kubernetesAuthService := authorization.NewKubernetesNativeAuthService(config.KubernetesAuth)
-		authServices = append(authServices, &kubernetesAuthService)
+		if err := appendService(&kubernetesAuthService, config.KubernetesAuth.PrincipalMapping); err != nil {
+			return nil, err
+		}
 	}
 
 	if config.OpenIdAuth.ProviderUrl != "" {
@@ -28,7 +44,18 @@ func ConfigureAuth(config configuration.AuthConfig) ([]authorization.AuthService
 		if err != nil {
 			return nil, errors.WithMessage(err, "error initialising openId auth")
 		}
-		authServices = append(authServices, openIdAuthService)
+		if err := appendService(openIdAuthService, config.OpenIdAuth.PrincipalMapping); err != nil {
+			return nil, err
+		}
+	}
+
+	// Explicitly provided credentials, e.g., tokens, take precedence over client certificates,
+	// since the same certificate may be used by, e.g., a proxy acting on behalf of several users.
+	if config.ClientCertAuth.Enabled {
+		clientCertAuthService := authorization.NewClientCertAuthService(config.ClientCertAuth)
+		if err := appendService(clientCertAuthService, config.ClientCertAuth.PrincipalMapping); err != nil {
+			return nil, err
+		}
 	}
 
 	if config.AnonymousAuth {
@@ -46,7 +73,9 @@ func ConfigureAuth(config configuration.AuthConfig) ([]authorization.AuthService
 		if err != nil {
 			return nil, errors.WithMessage(err, "error initialising kerberos auth")
 		}
-		authServices = append(authServices, kerberosAuthService)
+		if err := appendService(kerberosAuthService, config.Kerberos.PrincipalMapping); err != nil {
+			return nil, err
+		}
 	}
 
 	if len(authServices) == 0 {
@@ -0,0 +1,93 @@
+package certs
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+// CachedCertPoolService is like CachedCertificateService, but for a bundle of CA certificates,
+// e.g., the ca.crt written by cert-manager or the trust bundle written by a SPIFFE helper.
+// The bundle is reloaded whenever the file changes, such that CAs can be rotated without restarts.
+type CachedCertPoolService struct {
+	path string
+
+	fileInfoLock sync.Mutex
+	fileInfo     os.FileInfo
+
+	certPoolLock sync.Mutex
+	certPool     *x509.CertPool
+
+	refreshInterval time.Duration
+}
+
+func NewCachedCertPoolService(path string, refreshInterval time.Duration) (*CachedCertPoolService, error) {
+	pool := &CachedCertPoolService{
+		path:            path,
+		refreshInterval: refreshInterval,
+	}
+	// Initialise the cert pool
+	if err := pool.refresh(); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+func (c *CachedCertPoolService) GetCertPool() *x509.CertPool {
+	c.certPoolLock.Lock()
+	defer c.certPoolLock.Unlock()
+	return c.certPool
+}
+
+func (c *CachedCertPoolService) Run(ctx *armadacontext.Context) {
+	ticker := time.NewTicker(c.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := c.refresh()
+			if err != nil {
+				log.WithError(err).Errorf("failed refreshing CA certificates from file %s", c.path)
+			}
+		}
+	}
+}
+
+func (c *CachedCertPoolService) refresh() error {
+	updatedFileInfo, err := os.Stat(c.path)
+	if err != nil {
+		return err
+	}
+	c.fileInfoLock.Lock()
+	modified := c.fileInfo == nil || updatedFileInfo.ModTime().After(c.fileInfo.ModTime())
+	c.fileInfoLock.Unlock()
+	if !modified {
+		return nil
+	}
+
+	log.Infof("refreshing CA certificates from file %s", c.path)
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return fmt.Errorf("no certificates found in %s", c.path)
+	}
+
+	c.fileInfoLock.Lock()
+	c.fileInfo = updatedFileInfo
+	c.fileInfoLock.Unlock()
+	c.certPoolLock.Lock()
+	c.certPool = pool
+	c.certPoolLock.Unlock()
+	return nil
+}
@@ -0,0 +1,91 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+// NewServerTlsConfig returns a TLS config presenting the certificate cached by certificates.
+// If clientCAs is non-nil, client certificates are verified against the CAs it caches;
+// if requireClientCert is also true, clients without a valid certificate are rejected (mTLS).
+// Rotated certificates and CAs are used for all subsequent handshakes.
+func NewServerTlsConfig(certificates *CachedCertificateService, clientCAs *CachedCertPoolService, requireClientCert bool) *tls.Config {
+	getCertificate := func(info *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		cert := certificates.GetCertificate()
+		if cert == nil {
+			return nil, fmt.Errorf("unexpectedly received nil from certificate cache")
+		}
+		return cert, nil
+	}
+	config := &tls.Config{GetCertificate: getCertificate}
+	if clientCAs == nil {
+		return config
+	}
+
+	clientAuth := tls.VerifyClientCertIfGiven
+	if requireClientCert {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+	// The client CAs of a config can't be changed once the server is running.
+	// Hence, a config with the current CAs is created for each handshake.
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return &tls.Config{
+			GetCertificate: getCertificate,
+			ClientCAs:      clientCAs.GetCertPool(),
+			ClientAuth:     clientAuth,
+			// Required by gRPC, which otherwise sets this on the config it's given only.
+			NextProtos: []string{"h2"},
+		}, nil
+	}
+	return config
+}
+
+// NewClientTlsConfig returns a TLS config for connecting to servers with a certificate signed by one of the CAs cached
+// by rootCAs or, if rootCAs is nil, by one of the system CAs. If certificates is non-nil, the certificate it caches is
+// presented to servers requesting a client certificate (mTLS). If serverName is non-empty, it overrides the name
+// the certificate of the server is verified against. Rotated certificates and CAs are used for all subsequent handshakes.
+func NewClientTlsConfig(rootCAs *CachedCertPoolService, certificates *CachedCertificateService, serverName string) *tls.Config {
+	config := &tls.Config{ServerName: serverName}
+	if certificates != nil {
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert := certificates.GetCertificate()
+			if cert == nil {
+				return nil, fmt.Errorf("unexpectedly received nil from certificate cache")
+			}
+			return cert, nil
+		}
+	}
+	if rootCAs != nil {
+		// The root CAs of a config can't be changed once connections have been created with it.
+		// Hence, standard verification is replaced by verification against the current CAs.
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			return verifyServerCertificate(state, rootCAs.GetCertPool(), serverName)
+		}
+	}
+	return config
+}
+
+func verifyServerCertificate(state tls.ConnectionState, roots *x509.CertPool, serverName string) error {
+	if len(state.PeerCertificates) == 0 {
+		return fmt.Errorf("server presented no certificate")
+	}
+	if serverName == "" {
+		// The name sent via SNI, which is empty if the server is addressed by IP.
+		serverName = state.ServerName
+	}
+	if serverName == "" {
+		return fmt.Errorf("no server name to verify the server certificate against; servers addressed by IP require an explicit server name")
+	}
+	options := x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       serverName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range state.PeerCertificates[1:] {
+		options.Intermediates.AddCert(cert)
+	}
+	_, err := state.PeerCertificates[0].Verify(options)
+	return err
+}
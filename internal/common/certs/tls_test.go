@@ -0,0 +1,191 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTlsConfigs_MutualTls(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, caPEM := createTestCA(t)
+	writeTestFile(t, dir, "ca.crt", caPEM)
+	writeTestKeyPair(t, dir, "server", ca, caKey, "server.armada.svc")
+	writeTestKeyPair(t, dir, "client", ca, caKey, "executor")
+
+	serverCerts := NewCachedCertificateService(filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"), time.Second)
+	clientCerts := NewCachedCertificateService(filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key"), time.Second)
+	caPool, err := NewCachedCertPoolService(filepath.Join(dir, "ca.crt"), time.Second)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		clientCertificates *CachedCertificateService
+		requireClientCert  bool
+		serverName         string
+		expectError        bool
+	}{
+		"mutual tls": {
+			clientCertificates: clientCerts,
+			requireClientCert:  true,
+			serverName:         "server.armada.svc",
+		},
+		"client certificate optional": {
+			serverName: "server.armada.svc",
+		},
+		"client certificate required": {
+			requireClientCert: true,
+			serverName:        "server.armada.svc",
+			expectError:       true,
+		},
+		"wrong server name": {
+			clientCertificates: clientCerts,
+			serverName:         "other.armada.svc",
+			expectError:        true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			serverConfig := NewServerTlsConfig(serverCerts, caPool, tc.requireClientCert)
+			clientConfig := NewClientTlsConfig(caPool, tc.clientCertificates, tc.serverName)
+			clientState, serverState, err := handshake(serverConfig, clientConfig)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "server.armada.svc", clientState.PeerCertificates[0].Subject.CommonName)
+			if tc.clientCertificates != nil {
+				require.Len(t, serverState.VerifiedChains, 1)
+				assert.Equal(t, "executor", serverState.VerifiedChains[0][0].Subject.CommonName)
+			} else {
+				assert.Empty(t, serverState.VerifiedChains)
+			}
+		})
+	}
+}
+
+func TestTlsConfigs_RotatedCA(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, caPEM := createTestCA(t)
+	writeTestFile(t, dir, "ca.crt", caPEM)
+	writeTestKeyPair(t, dir, "server", ca, caKey, "server.armada.svc")
+	serverCerts := NewCachedCertificateService(filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"), time.Second)
+	caPool, err := NewCachedCertPoolService(filepath.Join(dir, "ca.crt"), time.Second)
+	require.NoError(t, err)
+	serverConfig := NewServerTlsConfig(serverCerts, nil, false)
+	clientConfig := NewClientTlsConfig(caPool, nil, "server.armada.svc")
+
+	_, _, err = handshake(serverConfig, clientConfig)
+	require.NoError(t, err)
+
+	// Rotate both the CA and the server certificate; the same client config picks up the new CA.
+	newCA, newCAKey, newCAPEM := createTestCA(t)
+	writeTestKeyPair(t, dir, "server", newCA, newCAKey, "server.armada.svc")
+	require.NoError(t, serverCerts.refresh())
+	_, _, err = handshake(serverConfig, clientConfig)
+	assert.Error(t, err)
+
+	// Write the new CA with a later modification time, such that it's picked up by refresh.
+	writeTestFile(t, dir, "ca.crt", newCAPEM)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "ca.crt"), later, later))
+	require.NoError(t, caPool.refresh())
+	_, _, err = handshake(serverConfig, clientConfig)
+	assert.NoError(t, err)
+}
+
+func TestNewCachedCertPoolService_MissingFile(t *testing.T) {
+	_, err := NewCachedCertPoolService(filepath.Join(t.TempDir(), "ca.crt"), time.Second)
+	assert.Error(t, err)
+}
+
+func handshake(serverConfig *tls.Config, clientConfig *tls.Config) (tls.ConnectionState, tls.ConnectionState, error) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+	server := tls.Server(serverConn, serverConfig)
+	client := tls.Client(clientConn, clientConfig)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Handshake()
+		// Unblock the client if the server rejected it.
+		serverConn.Close()
+	}()
+	clientErr := client.Handshake()
+	if clientErr == nil {
+		// With TLS 1.3, the server verifies the client certificate after the client considers the handshake complete.
+		// Hence, read until the server closes the connection, such that rejections are reported via serverErr.
+		_, _ = client.Read(make([]byte, 1))
+	}
+	if err := <-serverErr; err != nil {
+		return tls.ConnectionState{}, tls.ConnectionState{}, err
+	}
+	if clientErr != nil {
+		return tls.ConnectionState{}, tls.ConnectionState{}, clientErr
+	}
+	return client.ConnectionState(), server.ConnectionState(), nil
+}
+
+func createTestCA(t *testing.T) (*x509.Certificate, *rsa.PrivateKey, []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "armada-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return ca, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func writeTestKeyPair(t *testing.T, dir string, name string, ca *x509.Certificate, caKey *rsa.PrivateKey, commonName string) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+
+	var certPEM, keyPEM bytes.Buffer
+	require.NoError(t, pem.Encode(&certPEM, &pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	require.NoError(t, pem.Encode(&keyPEM, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	writeTestFile(t, dir, name+".crt", certPEM.Bytes())
+	writeTestFile(t, dir, name+".key", keyPEM.Bytes())
+
+	// Ensure rewritten files have a later modification time, such that they're picked up by refresh.
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, name+".crt"), later, later))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, name+".key"), later, later))
+}
+
+func writeTestFile(t *testing.T, dir string, name string, data []byte) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+}
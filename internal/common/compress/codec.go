@@ -0,0 +1,190 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4"
+	"github.com/pkg/errors"
+)
+
+// Codec identifies the algorithm used to compress payloads.
+type Codec string
+
+const (
+	// CodecZlib payloads are written as plain zlib streams, as done before codecs were configurable,
+	// such that they can be read by readers that only support zlib.
+	CodecZlib Codec = "zlib"
+	// Payloads compressed with the below codecs are prefixed with a header identifying the codec,
+	// such that readers can decompress payloads without knowing how they were written.
+	CodecZstd   Codec = "zstd"
+	CodecLz4    Codec = "lz4"
+	CodecSnappy Codec = "snappy"
+)
+
+// Payloads written with a codec other than zlib start with framedPayloadMagic followed by a byte identifying the codec.
+// The lower four bits of the first byte of a zlib stream always equal 8, so framed payloads are never mistaken for zlib.
+const framedPayloadMagic byte = 0xAC
+
+// Identifiers of codecs written after framedPayloadMagic.
+// Payloads no larger than the minimum compression size are stored uncompressed, with identifier codecIdNone.
+const (
+	codecIdNone byte = iota
+	codecIdZstd
+	codecIdLz4
+	codecIdSnappy
+)
+
+var codecIdByCodec = map[Codec]byte{
+	CodecZstd:   codecIdZstd,
+	CodecLz4:    codecIdLz4,
+	CodecSnappy: codecIdSnappy,
+}
+
+// Validate returns an error if c isn't a supported codec. The empty codec is valid and equivalent to zlib.
+func (c Codec) Validate() error {
+	if c == "" || c == CodecZlib {
+		return nil
+	}
+	if _, ok := codecIdByCodec[c]; !ok {
+		return errors.Errorf("unsupported compression codec %q; must be one of %q, %q, %q, or %q", c, CodecZlib, CodecZstd, CodecLz4, CodecSnappy)
+	}
+	return nil
+}
+
+// NewCompressor returns a compressor for codec, which stores payloads of at most minCompressSize bytes uncompressed.
+// The empty codec is equivalent to zlib. Compressors of codecs other than zlib are thread safe.
+func NewCompressor(codec Codec, minCompressSize int) (Compressor, error) {
+	if err := codec.Validate(); err != nil {
+		return nil, err
+	}
+	switch codec {
+	case "", CodecZlib:
+		return NewZlibCompressor(minCompressSize)
+	case CodecZstd:
+		encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return &FramedCompressor{
+			codecId:         codecIdZstd,
+			minCompressSize: minCompressSize,
+			encode: func(dst, src []byte) ([]byte, error) {
+				return encoder.EncodeAll(src, dst), nil
+			},
+		}, nil
+	case CodecLz4:
+		return &FramedCompressor{codecId: codecIdLz4, minCompressSize: minCompressSize, encode: encodeLz4}, nil
+	default:
+		return &FramedCompressor{
+			codecId:         codecIdSnappy,
+			minCompressSize: minCompressSize,
+			encode: func(dst, src []byte) ([]byte, error) {
+				return append(dst, snappy.Encode(nil, src)...), nil
+			},
+		}, nil
+	}
+}
+
+// NewThreadSafeCompressor is like NewCompressor, but the returned compressor is always thread safe.
+func NewThreadSafeCompressor(codec Codec, minCompressSize int) (Compressor, error) {
+	if codec == "" || codec == CodecZlib {
+		return NewThreadSafeZlibCompressor(minCompressSize), nil
+	}
+	return NewCompressor(codec, minCompressSize)
+}
+
+// FramedCompressor compresses payloads with a codec other than zlib,
+// prefixing each with a header identifying the codec; see CodecDecompressor.
+type FramedCompressor struct {
+	codecId         byte
+	minCompressSize int
+	// Appends the compressed form of src to dst.
+	encode func(dst, src []byte) ([]byte, error)
+}
+
+func (c *FramedCompressor) Compress(b []byte) ([]byte, error) {
+	header := []byte{framedPayloadMagic, c.codecId}
+	if len(b) <= c.minCompressSize {
+		header[1] = codecIdNone
+		return append(header, b...), nil
+	}
+	return c.encode(header, b)
+}
+
+func encodeLz4(dst, src []byte) ([]byte, error) {
+	buffer := bytes.NewBuffer(dst)
+	writer := lz4.NewWriter(buffer)
+	if _, err := writer.Write(src); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// CodecDecompressor decompresses payloads written by any compressor returned by NewCompressor,
+// detecting the codec from the header of each payload.
+// Payloads without a header, including all those written before codecs were configurable, are decompressed as zlib.
+// Hence, writers may switch codec at any time, provided all readers use a CodecDecompressor.
+type CodecDecompressor struct {
+	zlibDecompressor Decompressor
+	zstdDecoder      *zstd.Decoder
+}
+
+// NewDecompressor returns a single threaded CodecDecompressor.
+func NewDecompressor() (*CodecDecompressor, error) {
+	return newCodecDecompressor(NewZlibDecompressor(), 1)
+}
+
+// NewThreadSafeDecompressor returns a thread safe CodecDecompressor.
+func NewThreadSafeDecompressor() (*CodecDecompressor, error) {
+	return newCodecDecompressor(NewThreadSafeZlibDecompressor(), 0)
+}
+
+// newCodecDecompressor returns a CodecDecompressor that decompresses zstd payloads on up to zstdConcurrency goroutines at a time,
+// or GOMAXPROCS if zero. Decoders are thread safe when used via DecodeAll.
+func newCodecDecompressor(zlibDecompressor Decompressor, zstdConcurrency int) (*CodecDecompressor, error) {
+	zstdDecoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(zstdConcurrency))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &CodecDecompressor{
+		zlibDecompressor: zlibDecompressor,
+		zstdDecoder:      zstdDecoder,
+	}, nil
+}
+
+func (d *CodecDecompressor) Decompress(b []byte) ([]byte, error) {
+	if len(b) < 2 || b[0] != framedPayloadMagic {
+		return d.zlibDecompressor.Decompress(b)
+	}
+	codecId, payload := b[1], b[2:]
+	switch codecId {
+	case codecIdNone:
+		return payload, nil
+	case codecIdZstd:
+		decompressed, err := d.zstdDecoder.DecodeAll(payload, nil)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return decompressed, nil
+	case codecIdLz4:
+		decompressed, err := io.ReadAll(lz4.NewReader(bytes.NewReader(payload)))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return decompressed, nil
+	case codecIdSnappy:
+		decompressed, err := snappy.Decode(nil, payload)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return decompressed, nil
+	default:
+		return nil, errors.Errorf("payload compressed with unknown codec %d", codecId)
+	}
+}
@@ -0,0 +1,73 @@
+package compress
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodecs(t *testing.T) {
+	inputs := []string{
+		"",
+		"hello world",
+		strings.Repeat("The quick brown fox jumps over the lazy dog", 100),
+	}
+	for _, codec := range []Codec{"", CodecZlib, CodecZstd, CodecLz4, CodecSnappy} {
+		for _, minCompressSize := range []int{0, 1024 * 1024} {
+			compressor, err := NewCompressor(codec, minCompressSize)
+			require.NoError(t, err)
+			threadSafeCompressor, err := NewThreadSafeCompressor(codec, minCompressSize)
+			require.NoError(t, err)
+			decompressor, err := NewDecompressor()
+			require.NoError(t, err)
+			threadSafeDecompressor, err := NewThreadSafeDecompressor()
+			require.NoError(t, err)
+			for _, input := range inputs {
+				for _, c := range []Compressor{compressor, threadSafeCompressor} {
+					compressed, err := c.Compress([]byte(input))
+					require.NoError(t, err)
+					for _, d := range []Decompressor{decompressor, threadSafeDecompressor} {
+						decompressed, err := d.Decompress(compressed)
+						require.NoError(t, err)
+						assert.Equal(t, input, string(decompressed), "codec %q", codec)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCodecs_ZlibIsBackwardsCompatible(t *testing.T) {
+	input := "hello world"
+	compressor, err := NewCompressor(CodecZlib, 0)
+	require.NoError(t, err)
+	compressed, err := compressor.Compress([]byte(input))
+	require.NoError(t, err)
+
+	// Readers that predate codecs can read zlib payloads.
+	decompressed, err := NewZlibDecompressor().Decompress(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, input, string(decompressed))
+
+	// Payloads written before codecs were configurable can be read by codec decompressors.
+	legacyCompressor, err := NewZlibCompressor(0)
+	require.NoError(t, err)
+	compressed, err = legacyCompressor.Compress([]byte(input))
+	require.NoError(t, err)
+	decompressor, err := NewDecompressor()
+	require.NoError(t, err)
+	decompressed, err = decompressor.Decompress(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, input, string(decompressed))
+}
+
+func TestCodec_Validate(t *testing.T) {
+	for _, codec := range []Codec{"", CodecZlib, CodecZstd, CodecLz4, CodecSnappy} {
+		assert.NoError(t, codec.Validate())
+	}
+	assert.Error(t, Codec("gzip").Validate())
+	_, err := NewCompressor("gzip", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,14 @@
+package config
+
+// EncryptionConfig controls envelope encryption of job specs stored by Armada.
+type EncryptionConfig struct {
+	// If true, newly stored job specs are encrypted with the key identified by CurrentKeyId.
+	// Job specs are decrypted whenever the key they were encrypted with is in Keys, regardless of this setting,
+	// such that encryption can be turned off without losing access to already encrypted data.
+	Enabled bool
+	// Id of the key used to encrypt new data.
+	CurrentKeyId string
+	// Base64-encoded 256-bit key-encryption keys by id.
+	// To rotate keys, add a new key, make it the current key, and remove the old key once data encrypted with it has expired.
+	Keys map[string]string
+}
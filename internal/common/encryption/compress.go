@@ -0,0 +1,49 @@
+package encryption
+
+import (
+	"github.com/armadaproject/armada/internal/common/compress"
+)
+
+// EncryptingCompressor is a compress.Compressor that encrypts data after compressing it,
+// such that it can be used wherever compressed data is stored.
+type EncryptingCompressor struct {
+	compressor compress.Compressor
+	envelope   *Envelope
+}
+
+func NewEncryptingCompressor(compressor compress.Compressor, envelope *Envelope) *EncryptingCompressor {
+	return &EncryptingCompressor{
+		compressor: compressor,
+		envelope:   envelope,
+	}
+}
+
+func (c *EncryptingCompressor) Compress(b []byte) ([]byte, error) {
+	compressed, err := c.compressor.Compress(b)
+	if err != nil {
+		return nil, err
+	}
+	return c.envelope.Encrypt(compressed)
+}
+
+// DecryptingDecompressor is a compress.Decompressor that reverses an EncryptingCompressor.
+// Data that isn't encrypted is decompressed as-is.
+type DecryptingDecompressor struct {
+	decompressor compress.Decompressor
+	envelope     *Envelope
+}
+
+func NewDecryptingDecompressor(decompressor compress.Decompressor, envelope *Envelope) *DecryptingDecompressor {
+	return &DecryptingDecompressor{
+		decompressor: decompressor,
+		envelope:     envelope,
+	}
+}
+
+func (d *DecryptingDecompressor) Decompress(b []byte) ([]byte, error) {
+	decrypted, err := d.envelope.Decrypt(b)
+	if err != nil {
+		return nil, err
+	}
+	return d.decompressor.Decompress(decrypted)
+}
@@ -0,0 +1,227 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common/config"
+)
+
+// Encrypted data starts with this header. Since field number 0 is invalid in protobuf and zlib streams start with 0x78,
+// neither serialised protobuf messages nor compressed data start with a zero byte; hence, unencrypted data is never mistaken for encrypted data.
+var header = []byte{0x00, 'A', 'E', 0x01}
+
+const (
+	dataKeySize = 32
+	nonceSize   = 12
+)
+
+// KeyProvider wraps and unwraps data keys with key-encryption keys identified by id, e.g., keys held in a KMS.
+type KeyProvider interface {
+	// CurrentKeyId returns the id of the key to wrap new data keys with.
+	CurrentKeyId() string
+	WrapKey(keyId string, dataKey []byte) ([]byte, error)
+	UnwrapKey(keyId string, wrappedKey []byte) ([]byte, error)
+}
+
+// Envelope encrypts data with a random data key per message, which is stored alongside the data wrapped by a key-encryption key.
+// Since each message records the id of the key its data key was wrapped with, keys can be rotated without re-encrypting stored data.
+//
+// All methods are safe to call on a nil Envelope, which leaves data unencrypted.
+type Envelope struct {
+	keyProvider KeyProvider
+	// If false, Encrypt returns data unencrypted; data encrypted earlier can still be decrypted.
+	encrypt bool
+}
+
+func NewEnvelope(keyProvider KeyProvider, encrypt bool) *Envelope {
+	return &Envelope{
+		keyProvider: keyProvider,
+		encrypt:     encrypt,
+	}
+}
+
+// NewEnvelopeFromConfig returns an Envelope using the keys in config, or nil if there are no keys.
+func NewEnvelopeFromConfig(config config.EncryptionConfig) (*Envelope, error) {
+	if len(config.Keys) == 0 {
+		if config.Enabled {
+			return nil, errors.New("encryption is enabled but no keys are configured")
+		}
+		return nil, nil
+	}
+	if config.Enabled && config.CurrentKeyId == "" {
+		return nil, errors.New("encryption is enabled but no current key is configured")
+	}
+	keyProvider, err := NewStaticKeyProvider(config.CurrentKeyId, config.Keys)
+	if err != nil {
+		return nil, err
+	}
+	return NewEnvelope(keyProvider, config.Enabled), nil
+}
+
+// Encrypt returns data encrypted, or data itself if encryption is disabled.
+func (e *Envelope) Encrypt(data []byte) ([]byte, error) {
+	if e == nil || !e.encrypt {
+		return data, nil
+	}
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	keyId := e.keyProvider.CurrentKeyId()
+	wrappedKey, err := e.keyProvider.WrapKey(keyId, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyId) > 255 || len(wrappedKey) > 65535 {
+		return nil, errors.Errorf("key id %s or wrapped key too long", keyId)
+	}
+	ciphertext, err := seal(dataKey, data)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(header)+1+len(keyId)+2+len(wrappedKey)+len(ciphertext))
+	out = append(out, header...)
+	out = append(out, byte(len(keyId)))
+	out = append(out, keyId...)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(wrappedKey)))
+	out = append(out, wrappedKey...)
+	out = append(out, ciphertext...)
+	return out, nil
+}
+
+// Decrypt returns data decrypted if it was encrypted by Encrypt, or data itself otherwise.
+func (e *Envelope) Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	if e == nil {
+		return nil, errors.New("data is encrypted but no encryption keys are configured")
+	}
+	rest := data[len(header):]
+	if len(rest) < 1 || len(rest) < 1+int(rest[0])+2 {
+		return nil, errors.New("encrypted data is truncated")
+	}
+	keyId := string(rest[1 : 1+int(rest[0])])
+	rest = rest[1+int(rest[0]):]
+	wrappedKeyLen := int(binary.BigEndian.Uint16(rest))
+	rest = rest[2:]
+	if len(rest) < wrappedKeyLen {
+		return nil, errors.New("encrypted data is truncated")
+	}
+	dataKey, err := e.keyProvider.UnwrapKey(keyId, rest[:wrappedKeyLen])
+	if err != nil {
+		return nil, err
+	}
+	return open(dataKey, rest[wrappedKeyLen:])
+}
+
+// IsEncrypted returns true if data was encrypted by an Envelope.
+func IsEncrypted(data []byte) bool {
+	if len(data) < len(header) {
+		return false
+	}
+	for i := range header {
+		if data[i] != header[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// StaticKeyProvider wraps data keys with AES-256-GCM using key-encryption keys provided up front, e.g., via config.
+type StaticKeyProvider struct {
+	currentKeyId string
+	keys         map[string][]byte
+}
+
+// NewStaticKeyProvider returns a StaticKeyProvider for the given base64-encoded 256-bit keys.
+// currentKeyId may be empty if the provider is only used to unwrap keys.
+func NewStaticKeyProvider(currentKeyId string, base64KeysById map[string]string) (*StaticKeyProvider, error) {
+	keys := make(map[string][]byte, len(base64KeysById))
+	for keyId, base64Key := range base64KeysById {
+		key, err := base64.StdEncoding.DecodeString(base64Key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode encryption key %s", keyId)
+		}
+		if len(key) != dataKeySize {
+			return nil, errors.Errorf("encryption key %s is %d bytes long; must be %d", keyId, len(key), dataKeySize)
+		}
+		keys[keyId] = key
+	}
+	if _, ok := keys[currentKeyId]; currentKeyId != "" && !ok {
+		return nil, errors.Errorf("current encryption key %s is not among the configured keys", currentKeyId)
+	}
+	return &StaticKeyProvider{
+		currentKeyId: currentKeyId,
+		keys:         keys,
+	}, nil
+}
+
+func (p *StaticKeyProvider) CurrentKeyId() string {
+	return p.currentKeyId
+}
+
+func (p *StaticKeyProvider) WrapKey(keyId string, dataKey []byte) ([]byte, error) {
+	key, ok := p.keys[keyId]
+	if !ok {
+		return nil, errors.Errorf("unknown encryption key %s", keyId)
+	}
+	return seal(key, dataKey)
+}
+
+func (p *StaticKeyProvider) UnwrapKey(keyId string, wrappedKey []byte) ([]byte, error) {
+	key, ok := p.keys[keyId]
+	if !ok {
+		return nil, errors.Errorf("unknown encryption key %s", keyId)
+	}
+	return open(key, wrappedKey)
+}
+
+// seal encrypts plaintext with AES-GCM, prefixing the result with a random nonce.
+func seal(key []byte, plaintext []byte) ([]byte, error) {
+	aead, err := newAead(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, nonceSize, nonceSize+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts ciphertext produced by seal.
+func open(key []byte, ciphertext []byte) ([]byte, error) {
+	aead, err := newAead(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < nonceSize {
+		return nil, errors.New("encrypted data is truncated")
+	}
+	plaintext, err := aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to decrypt data")
+	}
+	return plaintext, nil
+}
+
+func newAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return aead, nil
+}
@@ -0,0 +1,148 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/compress"
+	"github.com/armadaproject/armada/internal/common/config"
+)
+
+var (
+	key1 = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	key2 = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+)
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	envelope := newTestEnvelope(t, config.EncryptionConfig{Enabled: true, CurrentKeyId: "1", Keys: map[string]string{"1": key1}})
+	data := []byte("job spec")
+
+	encrypted, err := envelope.Encrypt(data)
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, string(encrypted), "job spec")
+
+	decrypted, err := envelope.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+}
+
+func TestEnvelope_KeyRotation(t *testing.T) {
+	old := newTestEnvelope(t, config.EncryptionConfig{Enabled: true, CurrentKeyId: "1", Keys: map[string]string{"1": key1}})
+	encrypted, err := old.Encrypt([]byte("job spec"))
+	require.NoError(t, err)
+
+	rotated := newTestEnvelope(t, config.EncryptionConfig{Enabled: true, CurrentKeyId: "2", Keys: map[string]string{"1": key1, "2": key2}})
+	decrypted, err := rotated.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("job spec"), decrypted)
+
+	// Data encrypted after the rotation can't be read with only the old key.
+	encrypted, err = rotated.Encrypt([]byte("job spec"))
+	require.NoError(t, err)
+	_, err = old.Decrypt(encrypted)
+	assert.Error(t, err)
+}
+
+func TestEnvelope_Disabled(t *testing.T) {
+	tests := map[string]*Envelope{
+		"nil envelope": nil,
+		"decrypt only": newTestEnvelope(t, config.EncryptionConfig{Keys: map[string]string{"1": key1}}),
+	}
+	for name, envelope := range tests {
+		t.Run(name, func(t *testing.T) {
+			encrypted, err := envelope.Encrypt([]byte("job spec"))
+			require.NoError(t, err)
+			assert.Equal(t, []byte("job spec"), encrypted)
+
+			decrypted, err := envelope.Decrypt(encrypted)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("job spec"), decrypted)
+		})
+	}
+}
+
+func TestEnvelope_DecryptWithoutKeys(t *testing.T) {
+	envelope := newTestEnvelope(t, config.EncryptionConfig{Enabled: true, CurrentKeyId: "1", Keys: map[string]string{"1": key1}})
+	encrypted, err := envelope.Encrypt([]byte("job spec"))
+	require.NoError(t, err)
+
+	var noKeys *Envelope
+	_, err = noKeys.Decrypt(encrypted)
+	assert.Error(t, err)
+	_, err = envelope.Decrypt(encrypted[:len(header)+1])
+	assert.Error(t, err)
+}
+
+func TestNewEnvelopeFromConfig(t *testing.T) {
+	tests := map[string]struct {
+		config      config.EncryptionConfig
+		expectNil   bool
+		expectError bool
+	}{
+		"no keys": {
+			expectNil: true,
+		},
+		"enabled without keys": {
+			config:      config.EncryptionConfig{Enabled: true},
+			expectError: true,
+		},
+		"enabled without current key": {
+			config:      config.EncryptionConfig{Enabled: true, Keys: map[string]string{"1": key1}},
+			expectError: true,
+		},
+		"unknown current key": {
+			config:      config.EncryptionConfig{Enabled: true, CurrentKeyId: "2", Keys: map[string]string{"1": key1}},
+			expectError: true,
+		},
+		"key of wrong size": {
+			config:      config.EncryptionConfig{Enabled: true, CurrentKeyId: "1", Keys: map[string]string{"1": "AAAA"}},
+			expectError: true,
+		},
+		"valid": {
+			config: config.EncryptionConfig{Enabled: true, CurrentKeyId: "1", Keys: map[string]string{"1": key1}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			envelope, err := NewEnvelopeFromConfig(tc.config)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectNil, envelope == nil)
+		})
+	}
+}
+
+func TestEncryptingCompressor(t *testing.T) {
+	envelope := newTestEnvelope(t, config.EncryptionConfig{Enabled: true, CurrentKeyId: "1", Keys: map[string]string{"1": key1}})
+	compressor, err := compress.NewZlibCompressor(0)
+	require.NoError(t, err)
+
+	compressed, err := NewEncryptingCompressor(compressor, envelope).Compress([]byte("job spec"))
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(compressed))
+
+	decompressed, err := NewDecryptingDecompressor(compress.NewZlibDecompressor(), envelope).Decompress(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("job spec"), decompressed)
+
+	// Data compressed before encryption was enabled can still be decompressed.
+	plain, err := compressor.Compress([]byte("job spec"))
+	require.NoError(t, err)
+	decompressed, err = NewDecryptingDecompressor(compress.NewZlibDecompressor(), envelope).Decompress(plain)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("job spec"), decompressed)
+}
+
+func newTestEnvelope(t *testing.T, config config.EncryptionConfig) *Envelope {
+	envelope, err := NewEnvelopeFromConfig(config)
+	require.NoError(t, err)
+	return envelope
+}
@@ -445,6 +445,27 @@ func CompactEventSequences(sequences []*armadaevents.EventSequence) []*armadaeve
 	return sequences
 }
 
+// IsPriorityEventSequence returns true if sequence consists only of events that ingesters should process
+// ahead of bulk events, i.e., cancellations and preemptions, since acting on these late causes the scheduler to
+// keep running jobs that users or the scheduler itself no longer want to run.
+func IsPriorityEventSequence(sequence *armadaevents.EventSequence) bool {
+	if sequence == nil || len(sequence.Events) == 0 {
+		return false
+	}
+	for _, event := range sequence.Events {
+		switch event.Event.(type) {
+		case *armadaevents.EventSequence_Event_CancelJob,
+			*armadaevents.EventSequence_Event_CancelJobSet,
+			*armadaevents.EventSequence_Event_CancelledJob,
+			*armadaevents.EventSequence_Event_JobRunPreemptionRequested,
+			*armadaevents.EventSequence_Event_JobRunPreempted:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 func groupsEqual(g1, g2 []string) bool {
 	if len(g1) == 0 && len(g2) == 0 {
 		// []string{} and nil are considered equal.
@@ -900,7 +921,7 @@ func EventSequenceFromApiEvent(msg *api.EventMessage) (sequence *armadaevents.Ev
 								Name:         m.Succeeded.PodName,
 								KubernetesId: m.Succeeded.KubernetesId,
 								// TODO: These should be included.
-								Annotations: nil,
+								Annotations: outputUrlAnnotations(m.Succeeded.OutputUrl),
 								Labels:      nil,
 							},
 							Info: &armadaevents.KubernetesResourceInfo_PodInfo{
@@ -926,7 +947,7 @@ func EventSequenceFromApiEvent(msg *api.EventMessage) (sequence *armadaevents.Ev
 								Name:         m.Succeeded.PodName,
 								KubernetesId: m.Succeeded.KubernetesId,
 								// TODO: These should be included.
-								Annotations: nil,
+								Annotations: outputUrlAnnotations(m.Succeeded.OutputUrl),
 								Labels:      nil,
 							},
 							Info: &armadaevents.KubernetesResourceInfo_PodInfo{
@@ -1152,6 +1173,15 @@ func EventSequenceFromApiEvent(msg *api.EventMessage) (sequence *armadaevents.Ev
 	return sequence, nil
 }
 
+// outputUrlAnnotations returns the annotations used to carry the location of captured job output
+// on the resource info of succeeded events, or nil if no output was captured.
+func outputUrlAnnotations(outputUrl string) map[string]string {
+	if outputUrl == "" {
+		return nil
+	}
+	return map[string]string{domain.OutputUrl: outputUrl}
+}
+
 // LEGACY_RUN_ID is used for messages for which we can't use the kubernetesId.
 const LEGACY_RUN_ID = "00000000-0000-0000-0000-000000000000"
 
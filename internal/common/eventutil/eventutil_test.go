@@ -853,3 +853,25 @@ func TestLimitSequencesByteSize(t *testing.T) {
 	}
 	assert.Equal(t, numSequences*numEvents, len(actual))
 }
+
+func TestIsPriorityEventSequence(t *testing.T) {
+	cancel := &armadaevents.EventSequence_Event{Event: &armadaevents.EventSequence_Event_CancelJob{CancelJob: &armadaevents.CancelJob{}}}
+	preempted := &armadaevents.EventSequence_Event{Event: &armadaevents.EventSequence_Event_JobRunPreempted{JobRunPreempted: &armadaevents.JobRunPreempted{}}}
+	submit := &armadaevents.EventSequence_Event{Event: &armadaevents.EventSequence_Event_SubmitJob{SubmitJob: &armadaevents.SubmitJob{}}}
+	tests := map[string]struct {
+		sequence *armadaevents.EventSequence
+		expected bool
+	}{
+		"nil":                     {sequence: nil, expected: false},
+		"empty":                   {sequence: &armadaevents.EventSequence{}, expected: false},
+		"cancel":                  {sequence: &armadaevents.EventSequence{Events: []*armadaevents.EventSequence_Event{cancel}}, expected: true},
+		"cancel and preempted":    {sequence: &armadaevents.EventSequence{Events: []*armadaevents.EventSequence_Event{cancel, preempted}}, expected: true},
+		"submit":                  {sequence: &armadaevents.EventSequence{Events: []*armadaevents.EventSequence_Event{submit}}, expected: false},
+		"cancel after submission": {sequence: &armadaevents.EventSequence{Events: []*armadaevents.EventSequence_Event{submit, cancel}}, expected: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsPriorityEventSequence(tc.sequence))
+		})
+	}
+}
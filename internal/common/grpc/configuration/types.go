@@ -20,4 +20,11 @@ type TlsConfig struct {
 	Enabled  bool
 	KeyPath  string
 	CertPath string
+	// If set, clients may authenticate with a certificate signed by one of the CAs in this file (see ClientCertAuthConfig).
+	// Clients without a certificate are still accepted unless RequireClientCert is true,
+	// such that other auth methods can be used alongside mTLS.
+	// The certificate, key, and CA files are reloaded when they change, e.g., when rotated by cert-manager or a SPIFFE helper.
+	ClientCAPath string
+	// If true, clients must present a certificate signed by one of the CAs in ClientCAPath (mTLS).
+	RequireClientCert bool
 }
@@ -1,7 +1,6 @@
 package grpc
 
 import (
-	"crypto/tls"
 	"fmt"
 	"net"
 	"runtime/debug"
@@ -14,6 +13,7 @@ import (
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -37,7 +37,7 @@ func CreateGrpcServer(
 	keepaliveEnforcementPolicy keepalive.EnforcementPolicy,
 	authServices []authorization.AuthService,
 	tlsConfig configuration.TlsConfig,
-) *grpc.Server {
+) (*grpc.Server, error) {
 	// Logging, authentication, etc. are implemented via gRPC interceptors
 	// (i.e., via functions that are called before handling the actual request).
 	// There are separate interceptors for unary and streaming gRPC calls.
@@ -93,20 +93,23 @@ func CreateGrpcServer(
 		go func() {
 			cachedCertificateService.Run(armadacontext.Background())
 		}()
-		tlsCreds := credentials.NewTLS(&tls.Config{
-			GetCertificate: func(info *tls.ClientHelloInfo) (*tls.Certificate, error) {
-				cert := cachedCertificateService.GetCertificate()
-				if cert == nil {
-					return nil, fmt.Errorf("unexpectedly received nil from certificate cache")
-				}
-				return cert, nil
-			},
-		})
-		serverOptions = append(serverOptions, grpc.Creds(tlsCreds))
+		var clientCAs *certs.CachedCertPoolService
+		if tlsConfig.ClientCAPath != "" {
+			var err error
+			clientCAs, err = certs.NewCachedCertPoolService(tlsConfig.ClientCAPath, time.Minute)
+			if err != nil {
+				return nil, errors.WithMessage(err, "error loading client CAs")
+			}
+			go func() {
+				clientCAs.Run(armadacontext.Background())
+			}()
+		}
+		serverTlsConfig := certs.NewServerTlsConfig(cachedCertificateService, clientCAs, tlsConfig.RequireClientCert)
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(serverTlsConfig)))
 	}
 
 	// Interceptors are registered at server creation
-	return grpc.NewServer(serverOptions...)
+	return grpc.NewServer(serverOptions...), nil
 }
 
 // TODO We don't need this function. Just do this at the caller.
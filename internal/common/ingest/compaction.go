@@ -0,0 +1,154 @@
+package ingest
+
+import (
+	"fmt"
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+
+	commonmetrics "github.com/armadaproject/armada/internal/common/ingest/metrics"
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+// EventCompactionConfig configures the event-compaction stage of an IngestionPipeline; see EventCompactor.
+type EventCompactionConfig struct {
+	// If true, redundant events are dropped before being converted.
+	Enabled bool
+	// Types of events that are redundant if repeated, e.g., "JobRunAssigned" for pods flapping between pending states.
+	// Of consecutive events of such a type for the same job run, only the first is retained.
+	// Event types are named as the fields of armadaevents.EventSequence_Event, e.g., "JobRunAssigned" or "JobRunRunning".
+	RepeatedEventTypes []string
+	// Number of jobs for which the most recent event is remembered, such that repeated events are also detected across batches.
+	// If zero, repeated events are only detected within a batch.
+	MaxTrackedJobs int
+}
+
+// EventCompactor drops redundant transitional events, e.g., repeated pending updates of flapping pods, before they're
+// converted and persisted. An event is redundant if it's of one of the configured types and the most recent event seen
+// for the same job was of the same type and for the same run, i.e., it doesn't change the state of the job.
+// Since the first of repeated events is retained, the time at which the transition happened is preserved.
+type EventCompactor struct {
+	repeatedEventTypes map[string]bool
+	// Most recent event of each job, as a compactionKey, from previous batches. Nil if only compacting within batches.
+	lastEventByJobId *lru.Cache
+	metrics          *commonmetrics.Metrics
+}
+
+// compactionKey identifies the type and run of an event of a known job.
+type compactionKey struct {
+	eventType string
+	runId     armadaevents.Uuid
+}
+
+func NewEventCompactor(config EventCompactionConfig, metrics *commonmetrics.Metrics) (*EventCompactor, error) {
+	knownEventTypes := make(map[string]bool)
+	for _, wrapper := range (*armadaevents.EventSequence_Event)(nil).XXX_OneofWrappers() {
+		knownEventTypes[eventTypeName(wrapper)] = true
+	}
+	repeatedEventTypes := make(map[string]bool, len(config.RepeatedEventTypes))
+	for _, eventType := range config.RepeatedEventTypes {
+		if !knownEventTypes[eventType] {
+			return nil, errors.Errorf("unknown event type %s", eventType)
+		}
+		repeatedEventTypes[eventType] = true
+	}
+	compactor := &EventCompactor{
+		repeatedEventTypes: repeatedEventTypes,
+		metrics:            metrics,
+	}
+	if config.MaxTrackedJobs > 0 {
+		cache, err := lru.New(config.MaxTrackedJobs)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		compactor.lastEventByJobId = cache
+	}
+	return compactor, nil
+}
+
+// Compact removes redundant events from the batch, as well as any sequences left without events.
+// Message ids are retained as is, such that all messages are acked.
+func (c *EventCompactor) Compact(batch *EventSequencesWithIds) *EventSequencesWithIds {
+	lastEventByJobId := make(map[string]compactionKey)
+	sequences := batch.EventSequences[:0]
+	for _, sequence := range batch.EventSequences {
+		events := sequence.Events[:0]
+		for _, event := range sequence.Events {
+			jobId, key, ok := c.compactionKeyFromEvent(event)
+			if !ok {
+				events = append(events, event)
+				continue
+			}
+			lastKey, seen := lastEventByJobId[jobId]
+			if !seen && c.lastEventByJobId != nil {
+				if value, ok := c.lastEventByJobId.Get(jobId); ok {
+					lastKey, seen = value.(compactionKey), true
+				}
+			}
+			if seen && lastKey == key && c.repeatedEventTypes[key.eventType] {
+				c.metrics.RecordCompactedEvent(key.eventType)
+				continue
+			}
+			lastEventByJobId[jobId] = key
+			events = append(events, event)
+		}
+		sequence.Events = events
+		if len(events) > 0 {
+			sequences = append(sequences, sequence)
+		}
+	}
+	if c.lastEventByJobId != nil {
+		for jobId, key := range lastEventByJobId {
+			c.lastEventByJobId.Add(jobId, key)
+		}
+	}
+	batch.EventSequences = sequences
+	return batch
+}
+
+// compactionKeyFromEvent returns the id of the job the event refers to and its compactionKey,
+// or false if the event doesn't refer to a job.
+func (c *EventCompactor) compactionKeyFromEvent(event *armadaevents.EventSequence_Event) (string, compactionKey, bool) {
+	if event.GetEvent() == nil {
+		return "", compactionKey{}, false
+	}
+	protoJobId, err := armadaevents.JobIdFromEvent(event)
+	if err != nil {
+		return "", compactionKey{}, false
+	}
+	jobId, err := armadaevents.UlidStringFromProtoUuid(protoJobId)
+	if err != nil {
+		return "", compactionKey{}, false
+	}
+	key := compactionKey{eventType: eventTypeName(event.GetEvent())}
+	if runId := runIdFromEvent(event); runId != nil {
+		key.runId = *runId
+	}
+	return jobId, key, true
+}
+
+// runIdFromEvent returns the id of the run the event refers to, or nil if it doesn't refer to a run.
+func runIdFromEvent(event *armadaevents.EventSequence_Event) *armadaevents.Uuid {
+	switch e := event.Event.(type) {
+	case *armadaevents.EventSequence_Event_JobRunLeased:
+		return e.JobRunLeased.RunId
+	case *armadaevents.EventSequence_Event_JobRunAssigned:
+		return e.JobRunAssigned.RunId
+	case *armadaevents.EventSequence_Event_JobRunRunning:
+		return e.JobRunRunning.RunId
+	case *armadaevents.EventSequence_Event_JobRunSucceeded:
+		return e.JobRunSucceeded.RunId
+	case *armadaevents.EventSequence_Event_JobRunErrors:
+		return e.JobRunErrors.RunId
+	case *armadaevents.EventSequence_Event_JobRunPreempted:
+		return e.JobRunPreempted.PreemptedRunId
+	default:
+		return nil
+	}
+}
+
+// eventTypeName returns the name of the type of an armadaevents.EventSequence_Event oneof wrapper, e.g., "JobRunAssigned".
+func eventTypeName(wrapper any) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", wrapper), "*armadaevents.EventSequence_Event_")
+}
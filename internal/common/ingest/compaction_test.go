@@ -0,0 +1,103 @@
+package ingest
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+func TestEventCompactor(t *testing.T) {
+	otherRunIdProto := armadaevents.ProtoUuidFromUuid(uuid.New())
+	assigned := assignedEvent(runIdProto)
+	assignedAgain := assignedEvent(runIdProto)
+	assignedOtherRun := assignedEvent(otherRunIdProto)
+	running := runningEvent()
+	tests := map[string]struct {
+		config   EventCompactionConfig
+		batches  [][]*armadaevents.EventSequence_Event
+		expected [][]*armadaevents.EventSequence_Event
+	}{
+		"repeated events are dropped": {
+			config:   EventCompactionConfig{RepeatedEventTypes: []string{"JobRunAssigned"}},
+			batches:  [][]*armadaevents.EventSequence_Event{{assigned, assignedAgain, running}},
+			expected: [][]*armadaevents.EventSequence_Event{{assigned, running}},
+		},
+		"repeated events of other types are retained": {
+			config:   EventCompactionConfig{RepeatedEventTypes: []string{"JobRunRunning"}},
+			batches:  [][]*armadaevents.EventSequence_Event{{assigned, assignedAgain}},
+			expected: [][]*armadaevents.EventSequence_Event{{assigned, assignedAgain}},
+		},
+		"events of different runs are retained": {
+			config:   EventCompactionConfig{RepeatedEventTypes: []string{"JobRunAssigned"}},
+			batches:  [][]*armadaevents.EventSequence_Event{{assigned, assignedOtherRun}},
+			expected: [][]*armadaevents.EventSequence_Event{{assigned, assignedOtherRun}},
+		},
+		"events that aren't consecutive are retained": {
+			config:   EventCompactionConfig{RepeatedEventTypes: []string{"JobRunAssigned"}},
+			batches:  [][]*armadaevents.EventSequence_Event{{assigned, running, assignedAgain}},
+			expected: [][]*armadaevents.EventSequence_Event{{assigned, running, assignedAgain}},
+		},
+		"repeated events are only dropped across batches if tracked": {
+			config:   EventCompactionConfig{RepeatedEventTypes: []string{"JobRunAssigned"}},
+			batches:  [][]*armadaevents.EventSequence_Event{{assigned}, {assignedAgain}},
+			expected: [][]*armadaevents.EventSequence_Event{{assigned}, {assignedAgain}},
+		},
+		"repeated events are dropped across batches": {
+			config:   EventCompactionConfig{RepeatedEventTypes: []string{"JobRunAssigned"}, MaxTrackedJobs: 10},
+			batches:  [][]*armadaevents.EventSequence_Event{{assigned}, {assignedAgain, running}},
+			expected: [][]*armadaevents.EventSequence_Event{{assigned}, {running}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			compactor, err := NewEventCompactor(tc.config, testMetrics)
+			require.NoError(t, err)
+			for i, events := range tc.batches {
+				batch := compactor.Compact(&EventSequencesWithIds{
+					EventSequences: []*armadaevents.EventSequence{
+						{Queue: "test", JobSetName: "test", Events: append([]*armadaevents.EventSequence_Event(nil), events...)},
+					},
+				})
+				var actual []*armadaevents.EventSequence_Event
+				for _, sequence := range batch.EventSequences {
+					assert.NotEmpty(t, sequence.Events)
+					actual = append(actual, sequence.Events...)
+				}
+				assert.Equal(t, tc.expected[i], actual)
+			}
+		})
+	}
+}
+
+func TestNewEventCompactor_UnknownEventType(t *testing.T) {
+	_, err := NewEventCompactor(EventCompactionConfig{RepeatedEventTypes: []string{"JobRunPending"}}, testMetrics)
+	assert.Error(t, err)
+}
+
+func assignedEvent(runId *armadaevents.Uuid) *armadaevents.EventSequence_Event {
+	return &armadaevents.EventSequence_Event{
+		Created: &baseTime,
+		Event: &armadaevents.EventSequence_Event_JobRunAssigned{
+			JobRunAssigned: &armadaevents.JobRunAssigned{
+				RunId: runId,
+				JobId: jobIdProto,
+			},
+		},
+	}
+}
+
+func runningEvent() *armadaevents.EventSequence_Event {
+	return &armadaevents.EventSequence_Event{
+		Created: &baseTime,
+		Event: &armadaevents.EventSequence_Event_JobRunRunning{
+			JobRunRunning: &armadaevents.JobRunRunning{
+				RunId: runIdProto,
+				JobId: jobIdProto,
+			},
+		},
+	}
+}
@@ -65,7 +65,11 @@ type IngestionPipeline[T HasPulsarMessageIds] struct {
 	msgFilter              func(msg pulsar.Message) bool
 	converter              InstructionConverter[T]
 	sink                   Sink[T]
+	// If positive, up to this many messages are buffered such that priority messages can overtake bulk messages.
+	priorityLaneBufferSize int
 	consumer               pulsar.Consumer // for test purposes only
+	// If non-nil, redundant events are dropped before being converted.
+	compactor *EventCompactor
 }
 
 // NewIngestionPipeline creates an IngestionPipeline that processes all pulsar messages
@@ -122,6 +126,27 @@ func NewFilteredMsgIngestionPipeline[T HasPulsarMessageIds](
 	}
 }
 
+// EnablePriorityLanes makes the pipeline process messages in the priority lane, e.g., cancellations,
+// ahead of messages in the bulk lane, e.g., submissions, when the pipeline is backlogged.
+// Up to bufferSize messages are read ahead to find priority messages.
+func (ingester *IngestionPipeline[T]) EnablePriorityLanes(bufferSize int) {
+	ingester.priorityLaneBufferSize = bufferSize
+}
+
+// EnableEventCompaction makes the pipeline drop redundant events, e.g., repeated pending updates, before converting them;
+// see EventCompactor. Returns an error if the config is invalid.
+func (ingester *IngestionPipeline[T]) EnableEventCompaction(config EventCompactionConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+	compactor, err := NewEventCompactor(config, ingester.metrics)
+	if err != nil {
+		return err
+	}
+	ingester.compactor = compactor
+	return nil
+}
+
 // Run will run the ingestion pipeline until the supplied context is shut down
 func (ingester *IngestionPipeline[T]) Run(ctx *armadacontext.Context) error {
 	shutdownMetricServer := common.ServeMetrics(ingester.metricsConfig.Port)
@@ -160,6 +185,11 @@ func (ingester *IngestionPipeline[T]) Run(ctx *armadacontext.Context) error {
 		}
 	}()
 
+	// Let priority messages overtake bulk messages when backlogged
+	if ingester.priorityLaneBufferSize > 0 {
+		pulsarMsgs = prioritise(pipelineShutdownContext, pulsarMsgs, ingester.priorityLaneBufferSize, ingester.metrics)
+	}
+
 	// Batch up messages
 	batchedMsgs := make(chan []pulsar.Message)
 	batcher := NewBatcher[pulsar.Message](pulsarMsgs, ingester.pulsarBatchSize, ingester.pulsarBatchDuration, func(b []pulsar.Message) { batchedMsgs <- b })
@@ -173,6 +203,9 @@ func (ingester *IngestionPipeline[T]) Run(ctx *armadacontext.Context) error {
 	go func() {
 		for msg := range batchedMsgs {
 			converted := unmarshalEventSequences(msg, ingester.msgFilter, ingester.metrics)
+			if ingester.compactor != nil {
+				converted = ingester.compactor.Compact(converted)
+			}
 			eventSequences <- converted
 		}
 		close(eventSequences)
@@ -240,6 +273,7 @@ func (ingester *IngestionPipeline[T]) subscribe() (pulsar.Consumer, func(), erro
 		Type:                        ingester.pulsarSubscriptionType,
 		ReceiverQueueSize:           ingester.pulsarConfig.ReceiverQueueSize,
 		SubscriptionInitialPosition: pulsar.SubscriptionPositionEarliest,
+		Decryption:                  pulsarutils.ConsumerDecryption(&ingester.pulsarConfig),
 	})
 	if err != nil {
 		return nil, nil, errors.WithMessage(err, "Error creating pulsar consumer")
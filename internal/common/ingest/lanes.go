@@ -0,0 +1,80 @@
+package ingest
+
+import (
+	"github.com/apache/pulsar-client-go/pulsar"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	commonmetrics "github.com/armadaproject/armada/internal/common/ingest/metrics"
+	"github.com/armadaproject/armada/internal/common/pulsarutils"
+)
+
+// prioritise forwards messages from input to the returned channel. Messages are buffered, up to bufferSize of them,
+// while the rest of the pipeline is busy; when forwarding buffered messages, messages in the priority lane
+// (see pulsarutils.PriorityLane) are forwarded ahead of messages in the bulk lane. Hence, cancellations and
+// preemptions aren't stuck behind a backlog of submissions.
+//
+// Messages are never forwarded ahead of earlier messages with the same key, i.e., of the same job set,
+// since the events of a job set may depend on each other, e.g., a job must be submitted before it can be cancelled.
+func prioritise(ctx *armadacontext.Context, input chan pulsar.Message, bufferSize int, metrics *commonmetrics.Metrics) chan pulsar.Message {
+	out := make(chan pulsar.Message)
+	go func() {
+		defer close(out)
+		buffer := make([]pulsar.Message, 0, bufferSize)
+		inputClosed := false
+		for !inputClosed || len(buffer) > 0 {
+			// Only read more messages if there's space in the buffer.
+			var in chan pulsar.Message
+			if !inputClosed && len(buffer) < bufferSize {
+				in = input
+			}
+			// Only forward a message if there's one buffered.
+			var send chan pulsar.Message
+			next := -1
+			if len(buffer) > 0 {
+				send = out
+				next = nextMessage(buffer)
+			}
+			var nextMsg pulsar.Message
+			if next >= 0 {
+				nextMsg = buffer[next]
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-in:
+				if !ok {
+					inputClosed = true
+				} else {
+					buffer = append(buffer, msg)
+				}
+			case send <- nextMsg:
+				if next > 0 {
+					metrics.RecordPrioritisedMessage()
+				}
+				buffer = append(buffer[:next], buffer[next+1:]...)
+			}
+		}
+	}()
+	return out
+}
+
+// nextMessage returns the index of the message in buffer to forward next,
+// i.e., the first priority message not preceded by a bulk message with the same key, or else the first message.
+func nextMessage(buffer []pulsar.Message) int {
+	blockedKeys := make(map[string]bool)
+	for i, msg := range buffer {
+		if isPriorityMessage(msg) {
+			if !blockedKeys[msg.Key()] {
+				return i
+			}
+		} else {
+			blockedKeys[msg.Key()] = true
+		}
+	}
+	return 0
+}
+
+func isPriorityMessage(msg pulsar.Message) bool {
+	return msg.Properties()[pulsarutils.LanePropertyName] == pulsarutils.PriorityLane
+}
@@ -0,0 +1,86 @@
+package ingest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/pulsarutils"
+)
+
+var priority = map[string]string{pulsarutils.LanePropertyName: pulsarutils.PriorityLane}
+
+func TestPrioritise(t *testing.T) {
+	tests := map[string]struct {
+		messages      []pulsar.Message
+		expectedOrder []pulsar.MessageID
+	}{
+		"bulk messages keep their order": {
+			messages: []pulsar.Message{
+				pulsarutils.NewKeyedPulsarMessage(1, "a", nil),
+				pulsarutils.NewKeyedPulsarMessage(2, "b", nil),
+				pulsarutils.NewKeyedPulsarMessage(3, "a", nil),
+			},
+			expectedOrder: []pulsar.MessageID{
+				pulsarutils.NewMessageId(1),
+				pulsarutils.NewMessageId(2),
+				pulsarutils.NewMessageId(3),
+			},
+		},
+		"priority messages overtake bulk messages of other job sets": {
+			messages: []pulsar.Message{
+				pulsarutils.NewKeyedPulsarMessage(1, "a", nil),
+				pulsarutils.NewKeyedPulsarMessage(2, "a", nil),
+				pulsarutils.NewKeyedPulsarMessage(3, "b", priority),
+				pulsarutils.NewKeyedPulsarMessage(4, "c", priority),
+			},
+			expectedOrder: []pulsar.MessageID{
+				pulsarutils.NewMessageId(3),
+				pulsarutils.NewMessageId(4),
+				pulsarutils.NewMessageId(1),
+				pulsarutils.NewMessageId(2),
+			},
+		},
+		"priority messages don't overtake bulk messages of the same job set": {
+			messages: []pulsar.Message{
+				pulsarutils.NewKeyedPulsarMessage(1, "a", nil),
+				pulsarutils.NewKeyedPulsarMessage(2, "b", nil),
+				pulsarutils.NewKeyedPulsarMessage(3, "b", priority),
+				pulsarutils.NewKeyedPulsarMessage(4, "a", priority),
+			},
+			expectedOrder: []pulsar.MessageID{
+				pulsarutils.NewMessageId(1),
+				pulsarutils.NewMessageId(4),
+				pulsarutils.NewMessageId(2),
+				pulsarutils.NewMessageId(3),
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := armadacontext.WithTimeout(armadacontext.Background(), 5*time.Second)
+			defer cancel()
+
+			// Buffer all messages before reading any, to simulate a backlogged pipeline.
+			input := make(chan pulsar.Message, len(tc.messages))
+			for _, msg := range tc.messages {
+				input <- msg
+			}
+			close(input)
+			out := prioritise(ctx, input, len(tc.messages), testMetrics)
+			for len(input) > 0 {
+				time.Sleep(time.Millisecond)
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			var order []pulsar.MessageID
+			for msg := range out {
+				order = append(order, msg.ID())
+			}
+			assert.Equal(t, tc.expectedOrder, order)
+		})
+	}
+}
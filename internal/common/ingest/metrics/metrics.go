@@ -23,12 +23,16 @@ const (
 	ArmadaLookoutIngesterMetricsPrefix   = "armada_lookout_ingester_"
 	ArmadaLookoutIngesterV2MetricsPrefix = "armada_lookout_ingester_v2_"
 	ArmadaEventIngesterMetricsPrefix     = "armada_event_ingester_"
+	ArmadaNotifierMetricsPrefix          = "armada_notifier_"
+	ArmadaJobStatePublisherMetricsPrefix = "armada_job_state_publisher_"
 )
 
 type Metrics struct {
 	dbErrorsCounter       *prometheus.CounterVec
 	pulsarConnectionError prometheus.Counter
 	pulsarMessageError    *prometheus.CounterVec
+	prioritisedMessages   prometheus.Counter
+	compactedEvents       *prometheus.CounterVec
 }
 
 func NewMetrics(prefix string) *Metrics {
@@ -44,10 +48,20 @@ func NewMetrics(prefix string) *Metrics {
 		Name: prefix + "pulsar_connection_errors",
 		Help: "Number of Pulsar connection errors",
 	}
+	prioritisedMessagesOpts := prometheus.CounterOpts{
+		Name: prefix + "prioritised_messages",
+		Help: "Number of Pulsar messages in the priority lane processed ahead of earlier messages",
+	}
+	compactedEventsOpts := prometheus.CounterOpts{
+		Name: prefix + "compacted_events",
+		Help: "Number of redundant events dropped before being stored, grouped by event type",
+	}
 	return &Metrics{
 		dbErrorsCounter:       promauto.NewCounterVec(dbErrorsCounterOpts, []string{"operation"}),
 		pulsarMessageError:    promauto.NewCounterVec(pulsarMessageErrorOpts, []string{"error"}),
 		pulsarConnectionError: promauto.NewCounter(pulsarConnectionErrorOpts),
+		prioritisedMessages:   promauto.NewCounter(prioritisedMessagesOpts),
+		compactedEvents:       promauto.NewCounterVec(compactedEventsOpts, []string{"event_type"}),
 	}
 }
 
@@ -62,3 +76,11 @@ func (m *Metrics) RecordPulsarMessageError(error PulsarMessageError) {
 func (m *Metrics) RecordPulsarConnectionError() {
 	m.pulsarConnectionError.Inc()
 }
+
+func (m *Metrics) RecordPrioritisedMessage() {
+	m.prioritisedMessages.Inc()
+}
+
+func (m *Metrics) RecordCompactedEvent(eventType string) {
+	m.compactedEvents.With(map[string]string{"event_type": eventType}).Inc()
+}
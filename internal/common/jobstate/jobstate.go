@@ -0,0 +1,113 @@
+// Package jobstate defines the states of a job and the legal transitions between them.
+// It's shared by the ingesters, the scheduler, and the executors, such that all components agree on the state of a job.
+package jobstate
+
+import (
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+type State string
+
+const (
+	Queued    State = "QUEUED"
+	Leased    State = "LEASED"
+	Pending   State = "PENDING"
+	Running   State = "RUNNING"
+	Succeeded State = "SUCCEEDED"
+	Failed    State = "FAILED"
+	Cancelled State = "CANCELLED"
+	Preempted State = "PREEMPTED"
+)
+
+// States is an ordered list of all states.
+var States = []State{
+	Queued,
+	Leased,
+	Pending,
+	Running,
+	Succeeded,
+	Failed,
+	Cancelled,
+	Preempted,
+}
+
+// transitions maps each state to the states a job may transition into from that state.
+//
+// Components only observe some of the states of a job, e.g., the scheduler doesn't distinguish between leased and
+// pending jobs, and events may be batched. Hence, jobs may skip over intermediate states, e.g., from leased to running.
+// Jobs return to the queue if their lease is returned or their run fails and is retried.
+// Only jobs with a run can succeed or be preempted. Terminal states have no outgoing transitions.
+var transitions = map[State][]State{
+	Queued:    {Leased, Pending, Running, Failed, Cancelled},
+	Leased:    {Queued, Pending, Running, Succeeded, Failed, Cancelled, Preempted},
+	Pending:   {Queued, Running, Succeeded, Failed, Cancelled, Preempted},
+	Running:   {Queued, Succeeded, Failed, Cancelled, Preempted},
+	Succeeded: {},
+	Failed:    {},
+	Cancelled: {},
+	Preempted: {},
+}
+
+// IsValid returns true if s is a known state.
+func (s State) IsValid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// IsTerminal returns true if no further state transitions are expected for a job in this state.
+func (s State) IsTerminal() bool {
+	return s.IsValid() && len(transitions[s]) == 0
+}
+
+// CanTransitionTo returns true if a job in state s may transition into state to.
+// Transitioning into the current state is a no-op and is always allowed for known states.
+func (s State) CanTransitionTo(to State) bool {
+	if !s.IsValid() || !to.IsValid() {
+		return false
+	}
+	if s == to {
+		return true
+	}
+	for _, state := range transitions[s] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ForEvent returns the state the provided event transitions a job into,
+// or false if the event doesn't change the state of a job.
+func ForEvent(event *armadaevents.EventSequence_Event) (State, bool) {
+	switch e := event.Event.(type) {
+	case *armadaevents.EventSequence_Event_SubmitJob:
+		return Queued, true
+	case *armadaevents.EventSequence_Event_JobRequeued:
+		return Queued, true
+	case *armadaevents.EventSequence_Event_JobRunLeased:
+		return Leased, true
+	case *armadaevents.EventSequence_Event_JobRunAssigned:
+		return Pending, true
+	case *armadaevents.EventSequence_Event_JobRunRunning:
+		return Running, true
+	case *armadaevents.EventSequence_Event_JobSucceeded:
+		return Succeeded, true
+	case *armadaevents.EventSequence_Event_CancelledJob:
+		return Cancelled, true
+	case *armadaevents.EventSequence_Event_JobRunPreempted:
+		return Preempted, true
+	case *armadaevents.EventSequence_Event_JobErrors:
+		for _, err := range e.JobErrors.Errors {
+			if !err.Terminal {
+				continue
+			}
+			if err.GetJobRunPreemptedError() != nil {
+				return Preempted, true
+			}
+			return Failed, true
+		}
+		return "", false
+	default:
+		return "", false
+	}
+}
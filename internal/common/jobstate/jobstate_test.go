@@ -0,0 +1,119 @@
+package jobstate
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+func TestCanTransitionTo(t *testing.T) {
+	tests := map[string]struct {
+		from     State
+		to       State
+		expected bool
+	}{
+		"queued to leased":            {from: Queued, to: Leased, expected: true},
+		"leased to running":           {from: Leased, to: Running, expected: true},
+		"running to queued":           {from: Running, to: Queued, expected: true},
+		"running to preempted":        {from: Running, to: Preempted, expected: true},
+		"queued to queued":            {from: Queued, to: Queued, expected: true},
+		"succeeded to succeeded":      {from: Succeeded, to: Succeeded, expected: true},
+		"queued to succeeded":         {from: Queued, to: Succeeded, expected: false},
+		"queued to preempted":         {from: Queued, to: Preempted, expected: false},
+		"running to pending":          {from: Running, to: Pending, expected: false},
+		"succeeded to running":        {from: Succeeded, to: Running, expected: false},
+		"cancelled to failed":         {from: Cancelled, to: Failed, expected: false},
+		"unknown state to queued":     {from: "UNKNOWN", to: Queued, expected: false},
+		"unknown state to unknown":    {from: "UNKNOWN", to: "UNKNOWN", expected: false},
+		"running to an unknown state": {from: Running, to: "UNKNOWN", expected: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.from.CanTransitionTo(tc.to))
+		})
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	for _, state := range States {
+		expected := state == Succeeded || state == Failed || state == Cancelled || state == Preempted
+		assert.Equal(t, expected, state.IsTerminal(), state)
+	}
+	assert.False(t, State("UNKNOWN").IsTerminal())
+}
+
+func TestForEvent(t *testing.T) {
+	tests := map[string]struct {
+		event    *armadaevents.EventSequence_Event
+		expected State
+		ok       bool
+	}{
+		"submitted": {
+			event:    &armadaevents.EventSequence_Event{Event: &armadaevents.EventSequence_Event_SubmitJob{SubmitJob: &armadaevents.SubmitJob{}}},
+			expected: Queued,
+			ok:       true,
+		},
+		"running": {
+			event:    &armadaevents.EventSequence_Event{Event: &armadaevents.EventSequence_Event_JobRunRunning{JobRunRunning: &armadaevents.JobRunRunning{}}},
+			expected: Running,
+			ok:       true,
+		},
+		"terminal error": {
+			event: &armadaevents.EventSequence_Event{Event: &armadaevents.EventSequence_Event_JobErrors{JobErrors: &armadaevents.JobErrors{
+				Errors: []*armadaevents.Error{{Terminal: true}},
+			}}},
+			expected: Failed,
+			ok:       true,
+		},
+		"terminal preemption error": {
+			event: &armadaevents.EventSequence_Event{Event: &armadaevents.EventSequence_Event_JobErrors{JobErrors: &armadaevents.JobErrors{
+				Errors: []*armadaevents.Error{{
+					Terminal: true,
+					Reason:   &armadaevents.Error_JobRunPreemptedError{JobRunPreemptedError: &armadaevents.JobRunPreemptedError{}},
+				}},
+			}}},
+			expected: Preempted,
+			ok:       true,
+		},
+		"non-terminal error": {
+			event: &armadaevents.EventSequence_Event{Event: &armadaevents.EventSequence_Event_JobErrors{JobErrors: &armadaevents.JobErrors{
+				Errors: []*armadaevents.Error{{Terminal: false}},
+			}}},
+		},
+		"reprioritised": {
+			event: &armadaevents.EventSequence_Event{Event: &armadaevents.EventSequence_Event_ReprioritisedJob{ReprioritisedJob: &armadaevents.ReprioritisedJob{}}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			state, ok := ForEvent(tc.event)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, state)
+		})
+	}
+}
+
+func TestStateMachine_Transition(t *testing.T) {
+	sm := NewStateMachine("test")
+	var observed []State
+	sm.OnTransition(func(jobId string, from State, to State) {
+		assert.Equal(t, "job", jobId)
+		observed = append(observed, to)
+	})
+
+	require.NoError(t, sm.Transition("job", Queued, Leased))
+	require.NoError(t, sm.Transition("job", Leased, Leased))
+	require.NoError(t, sm.Transition("job", Leased, Running))
+
+	err := sm.Transition("job", Running, Leased)
+	var invalidTransitionErr *InvalidTransitionError
+	require.True(t, errors.As(err, &invalidTransitionErr))
+	assert.Equal(t, &InvalidTransitionError{JobId: "job", From: Running, To: Leased, Component: "test"}, invalidTransitionErr)
+
+	// Hooks are only called for legal transitions between distinct states.
+	assert.Equal(t, []State{Leased, Running}, observed)
+}
@@ -0,0 +1,89 @@
+package jobstate
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	transitionsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "armada_job_state_transitions",
+			Help: "Number of job state transitions, grouped by the component observing them",
+		},
+		[]string{"component", "from", "to"},
+	)
+	invalidTransitionsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "armada_job_state_invalid_transitions",
+			Help: "Number of illegal job state transitions, grouped by the component observing them",
+		},
+		[]string{"component", "from", "to"},
+	)
+)
+
+// InvalidTransitionError is returned when a job is observed transitioning between states in a way that isn't legal,
+// which indicates that the component observing the transition has diverged from the true state of the job.
+type InvalidTransitionError struct {
+	JobId     string
+	From      State
+	To        State
+	Component string
+}
+
+func (err *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("%s observed invalid transition of job %s from state %s to state %s", err.Component, err.JobId, err.From, err.To)
+}
+
+// Hook is called for each legal transition between two distinct states.
+type Hook func(jobId string, from State, to State)
+
+// StateMachine validates job state transitions observed by a component, e.g., the scheduler,
+// records metrics for them, and calls the registered hooks for each legal transition.
+type StateMachine struct {
+	// Name of the component using this state machine, used to label metrics and errors.
+	component string
+	hooks     []Hook
+	mu        sync.RWMutex
+}
+
+func NewStateMachine(component string) *StateMachine {
+	return &StateMachine{
+		component: component,
+	}
+}
+
+// OnTransition registers a hook to be called for each legal transition.
+func (sm *StateMachine) OnTransition(hook Hook) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.hooks = append(sm.hooks, hook)
+}
+
+// Transition validates the transition of a job from one state to another.
+// Returns an *InvalidTransitionError if the transition isn't legal, in which case no hooks are called.
+// Transitioning into the current state is a no-op.
+func (sm *StateMachine) Transition(jobId string, from State, to State) error {
+	if !from.CanTransitionTo(to) {
+		invalidTransitionsCounter.WithLabelValues(sm.component, string(from), string(to)).Inc()
+		return &InvalidTransitionError{
+			JobId:     jobId,
+			From:      from,
+			To:        to,
+			Component: sm.component,
+		}
+	}
+	if from == to {
+		return nil
+	}
+	transitionsCounter.WithLabelValues(sm.component, string(from), string(to)).Inc()
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	for _, hook := range sm.hooks {
+		hook(jobId, from, to)
+	}
+	return nil
+}
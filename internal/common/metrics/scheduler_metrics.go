@@ -169,6 +169,13 @@ var ClusterAvailableCapacityDesc = prometheus.NewDesc(
 	nil,
 )
 
+var ClusterNonArmadaAllocatedDesc = prometheus.NewDesc(
+	MetricPrefix+"cluster_non_armada_allocated",
+	"Cluster capacity allocated to pods not managed by Armada, e.g., daemonsets and static pods, and hence unavailable to Armada jobs",
+	[]string{"cluster", "pool", "resourceType", "nodeType"},
+	nil,
+)
+
 var AllDescs = []*prometheus.Desc{
 	QueueSizeDesc,
 	QueuePriorityDesc,
@@ -194,6 +201,7 @@ var AllDescs = []*prometheus.Desc{
 	QueueLeasedPodCountDesc,
 	ClusterCapacityDesc,
 	ClusterAvailableCapacityDesc,
+	ClusterNonArmadaAllocatedDesc,
 }
 
 func Describe(out chan<- *prometheus.Desc) {
@@ -339,6 +347,10 @@ func NewClusterTotalCapacity(value float64, cluster string, pool string, resourc
 	return prometheus.MustNewConstMetric(ClusterCapacityDesc, prometheus.GaugeValue, value, cluster, pool, resource, nodeType)
 }
 
+func NewClusterNonArmadaAllocated(value float64, cluster string, pool string, resource string, nodeType string) prometheus.Metric {
+	return prometheus.MustNewConstMetric(ClusterNonArmadaAllocatedDesc, prometheus.GaugeValue, value, cluster, pool, resource, nodeType)
+}
+
 func NewQueueAllocated(value float64, queue string, cluster string, pool string, resource string, nodeType string) prometheus.Metric {
 	return prometheus.MustNewConstMetric(QueueAllocatedDesc, prometheus.GaugeValue, value, cluster, pool, queue, resource, nodeType)
 }
@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+// Message is a rendered notification ready to be delivered.
+type Message struct {
+	// Short summary, e.g., used as the email subject.
+	Title string
+	// Body of the message.
+	Text string
+}
+
+// Sender delivers messages to some destination, e.g., a webhook or a Slack channel.
+type Sender interface {
+	Send(ctx *armadacontext.Context, msg Message) error
+}
+
+const defaultTimeout = 10 * time.Second
+
+// WebhookSender posts messages as json to an http endpoint.
+type WebhookSender struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSender(url string) *WebhookSender {
+	return &WebhookSender{
+		url:    url,
+		client: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+func (s *WebhookSender) Send(ctx *armadacontext.Context, msg Message) error {
+	return postJson(ctx, s.client, s.url, struct {
+		Title string `json:"title"`
+		Text  string `json:"text"`
+	}{
+		Title: msg.Title,
+		Text:  msg.Text,
+	})
+}
+
+// SlackSender posts messages to a Slack incoming webhook.
+type SlackSender struct {
+	webhookUrl string
+	client     *http.Client
+}
+
+func NewSlackSender(webhookUrl string) *SlackSender {
+	return &SlackSender{
+		webhookUrl: webhookUrl,
+		client:     &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+func (s *SlackSender) Send(ctx *armadacontext.Context, msg Message) error {
+	text := msg.Text
+	if msg.Title != "" {
+		text = fmt.Sprintf("*%s*\n%s", msg.Title, msg.Text)
+	}
+	return postJson(ctx, s.client, s.webhookUrl, struct {
+		Text string `json:"text"`
+	}{
+		Text: text,
+	})
+}
+
+// TeamsSender posts messages to a Microsoft Teams incoming webhook.
+type TeamsSender struct {
+	webhookUrl string
+	client     *http.Client
+}
+
+func NewTeamsSender(webhookUrl string) *TeamsSender {
+	return &TeamsSender{
+		webhookUrl: webhookUrl,
+		client:     &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+func (s *TeamsSender) Send(ctx *armadacontext.Context, msg Message) error {
+	return postJson(ctx, s.client, s.webhookUrl, struct {
+		Type    string `json:"@type"`
+		Context string `json:"@context"`
+		Title   string `json:"title,omitempty"`
+		Text    string `json:"text"`
+	}{
+		Type:    "MessageCard",
+		Context: "https://schema.org/extensions",
+		Title:   msg.Title,
+		Text:    msg.Text,
+	})
+}
+
+type SmtpConfig struct {
+	// Address of the smtp server, e.g., smtp.example.com:587.
+	Address  string
+	Username string
+	Password string
+	// Address emails are sent from.
+	From string
+}
+
+// EmailSender sends messages as plain-text emails via smtp.
+type EmailSender struct {
+	config SmtpConfig
+	to     []string
+	// Overridable for testing.
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewEmailSender(config SmtpConfig, to []string) *EmailSender {
+	return &EmailSender{
+		config:   config,
+		to:       to,
+		sendMail: smtp.SendMail,
+	}
+}
+
+func (s *EmailSender) Send(_ *armadacontext.Context, msg Message) error {
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		host := s.config.Address
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, host)
+	}
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&body, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&body, "Subject: %s\r\n", msg.Title)
+	body.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	body.WriteString(msg.Text)
+	if err := s.sendMail(s.config.Address, auth, s.config.From, s.to, body.Bytes()); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func postJson(ctx *armadacontext.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("unexpected status %d posting to %s: %s", resp.StatusCode, url, respBody)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+func TestWebhookSender(t *testing.T) {
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	err := NewWebhookSender(server.URL).Send(armadacontext.Background(), Message{Title: "title", Text: "text"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"title": "title", "text": "text"}, received)
+}
+
+func TestSlackSender(t *testing.T) {
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	err := NewSlackSender(server.URL).Send(armadacontext.Background(), Message{Title: "title", Text: "text"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"text": "*title*\ntext"}, received)
+}
+
+func TestTeamsSender(t *testing.T) {
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	err := NewTeamsSender(server.URL).Send(armadacontext.Background(), Message{Title: "title", Text: "text"})
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"title":    "title",
+			"text":     "text",
+		},
+		received,
+	)
+}
+
+func TestSender_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := NewWebhookSender(server.URL).Send(armadacontext.Background(), Message{Text: "text"})
+	assert.Error(t, err)
+}
+
+func TestEmailSender(t *testing.T) {
+	sender := NewEmailSender(SmtpConfig{Address: "smtp.example.com:587", From: "armada@example.com"}, []string{"a@example.com", "b@example.com"})
+	var sentTo []string
+	var sentMsg string
+	sender.sendMail = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		assert.Equal(t, "armada@example.com", from)
+		sentTo = to
+		sentMsg = string(msg)
+		return nil
+	}
+	err := sender.Send(armadacontext.Background(), Message{Title: "title", Text: "text"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, sentTo)
+	assert.Contains(t, sentMsg, "Subject: title\r\n")
+	assert.Contains(t, sentMsg, "\r\n\r\ntext")
+}
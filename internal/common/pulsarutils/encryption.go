@@ -0,0 +1,63 @@
+package pulsarutils
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsar/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+)
+
+// ProducerEncryption returns the options for producers to encrypt messages with, or nil if encryption is disabled.
+func ProducerEncryption(config *configuration.PulsarConfig) *pulsar.ProducerEncryptionInfo {
+	if len(config.EncryptionKeyNames) == 0 {
+		return nil
+	}
+	return &pulsar.ProducerEncryptionInfo{
+		KeyReader: NewDirectoryKeyReader(config.EncryptionKeyDirectory),
+		Keys:      config.EncryptionKeyNames,
+	}
+}
+
+// ConsumerDecryption returns the options for consumers to decrypt messages with, or nil if no keys are configured.
+func ConsumerDecryption(config *configuration.PulsarConfig) *pulsar.MessageDecryptionInfo {
+	if config.EncryptionKeyDirectory == "" {
+		return nil
+	}
+	return &pulsar.MessageDecryptionInfo{
+		KeyReader:                   NewDirectoryKeyReader(config.EncryptionKeyDirectory),
+		ConsumerCryptoFailureAction: crypto.ConsumerCryptoFailureActionFail,
+	}
+}
+
+// DirectoryKeyReader is a crypto.KeyReader reading PEM-encoded keys from a directory, such that keys can be rotated by adding files.
+// Other key readers, e.g., backed by a KMS, can be plugged in via the pulsar.ProducerEncryptionInfo and pulsar.MessageDecryptionInfo options.
+type DirectoryKeyReader struct {
+	directory string
+}
+
+func NewDirectoryKeyReader(directory string) *DirectoryKeyReader {
+	return &DirectoryKeyReader{directory: directory}
+}
+
+func (r *DirectoryKeyReader) PublicKey(keyName string, metadata map[string]string) (*crypto.EncryptionKeyInfo, error) {
+	return r.readKey(keyName, keyName+".pub", metadata)
+}
+
+func (r *DirectoryKeyReader) PrivateKey(keyName string, metadata map[string]string) (*crypto.EncryptionKeyInfo, error) {
+	return r.readKey(keyName, keyName+".pem", metadata)
+}
+
+func (r *DirectoryKeyReader) readKey(keyName string, fileName string, metadata map[string]string) (*crypto.EncryptionKeyInfo, error) {
+	if filepath.Base(fileName) != fileName {
+		return nil, errors.Errorf("invalid encryption key name %s", keyName)
+	}
+	key, err := os.ReadFile(filepath.Join(r.directory, fileName))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return crypto.NewEncryptionKeyInfo(keyName, key, metadata), nil
+}
@@ -15,6 +15,14 @@ import (
 	"github.com/armadaproject/armada/pkg/armadaevents"
 )
 
+const (
+	// LanePropertyName is the name of the message property indicating which lane of ingestion pipelines a message should be processed in.
+	LanePropertyName = "lane"
+	// PriorityLane marks messages that ingestion pipelines may process ahead of earlier messages of other job sets.
+	// Messages without this property are processed in the bulk lane.
+	PriorityLane = "priority"
+)
+
 // CompactAndPublishSequences reduces the number of sequences to the smallest possible,
 // while respecting per-job set ordering and max Pulsar message size, and then publishes to Pulsar.
 func CompactAndPublishSequences(ctx *armadacontext.Context, sequences []*armadaevents.EventSequence, producer pulsar.Producer, maxMessageSizeInBytes uint, scheduler schedulers.Scheduler) error {
@@ -64,15 +72,19 @@ func PublishSequences(ctx *armadacontext.Context, producer pulsar.Producer, sequ
 	ch := make(chan error, len(sequences))
 	var numSendCompleted uint32
 	for i := range sequences {
+		properties := map[string]string{
+			requestid.MetadataKey:   requestId,
+			schedulers.PropertyName: schedulers.MsgPropertyFromScheduler(scheduler),
+		}
+		if eventutil.IsPriorityEventSequence(sequences[i]) {
+			properties[LanePropertyName] = PriorityLane
+		}
 		producer.SendAsync(
 			ctx,
 			&pulsar.ProducerMessage{
-				Payload: payloads[i],
-				Properties: map[string]string{
-					requestid.MetadataKey:   requestId,
-					schedulers.PropertyName: schedulers.MsgPropertyFromScheduler(scheduler),
-				},
-				Key: sequences[i].JobSetName,
+				Payload:    payloads[i],
+				Properties: properties,
+				Key:        sequences[i].JobSetName,
 			},
 			// Callback on send.
 			func(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
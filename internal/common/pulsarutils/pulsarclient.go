@@ -1,13 +1,17 @@
 package pulsarutils
 
 import (
+	"crypto/tls"
 	"strings"
+	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
 	"github.com/pkg/errors"
 
 	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
 	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/certs"
 )
 
 func NewPulsarClient(config *configuration.PulsarConfig) (pulsar.Client, error) {
@@ -15,21 +19,40 @@ func NewPulsarClient(config *configuration.PulsarConfig) (pulsar.Client, error)
 
 	// Sanity check that supplied Pulsar authentication parameters make sense
 	if config.AuthenticationEnabled {
-		if strings.ToLower(config.AuthenticationType) != "jwt" {
+		switch strings.ToLower(config.AuthenticationType) {
+		case "jwt":
+			if strings.TrimSpace(config.JwtTokenPath) == "" {
+				return nil, errors.WithStack(&armadaerrors.ErrInvalidArgument{
+					Name:    "pulsar.JwtTokenPath",
+					Value:   config.JwtTokenPath,
+					Message: "JWT authentication was configured for Pulsar but no JwtTokenPath was supplied",
+				})
+			}
+			authentication = pulsar.NewAuthenticationTokenFromFile(config.JwtTokenPath)
+		case "tls":
+			if strings.TrimSpace(config.TLSCertFilePath) == "" || strings.TrimSpace(config.TLSKeyFilePath) == "" {
+				return nil, errors.WithStack(&armadaerrors.ErrInvalidArgument{
+					Name:    "pulsar.TLSCertFilePath",
+					Value:   config.TLSCertFilePath,
+					Message: "TLS authentication was configured for Pulsar but TLSCertFilePath or TLSKeyFilePath was not supplied",
+				})
+			}
+			// NewCachedCertificateService panics if the certificate can't be loaded; check that it can be beforehand.
+			if _, err := tls.LoadX509KeyPair(config.TLSCertFilePath, config.TLSKeyFilePath); err != nil {
+				return nil, errors.Wrap(err, "error loading Pulsar client certificate")
+			}
+			certificates := certs.NewCachedCertificateService(config.TLSCertFilePath, config.TLSKeyFilePath, time.Minute)
+			go certificates.Run(armadacontext.Background())
+			authentication = pulsar.NewAuthenticationFromTLSCertSupplier(func() (*tls.Certificate, error) {
+				return certificates.GetCertificate(), nil
+			})
+		default:
 			return nil, errors.WithStack(&armadaerrors.ErrInvalidArgument{
 				Name:    "pulsar.AuthenticationType",
 				Value:   config.AuthenticationType,
-				Message: "Only JWT Authentication for Pulsar is supported right now.",
-			})
-		}
-		if strings.TrimSpace(config.JwtTokenPath) == "" {
-			return nil, errors.WithStack(&armadaerrors.ErrInvalidArgument{
-				Name:    "pulsar.JwtTokenPath",
-				Value:   config.JwtTokenPath,
-				Message: "JWT authentication was configured for Pulsar but no JwtTokenPath was supplied",
+				Message: "Only JWT and TLS Authentication for Pulsar are supported right now.",
 			})
 		}
-		authentication = pulsar.NewAuthenticationTokenFromFile(config.JwtTokenPath)
 	}
 
 	return pulsar.NewClient(pulsar.ClientOptions{
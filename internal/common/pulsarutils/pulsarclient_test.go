@@ -56,4 +56,20 @@ func TestCreatePulsarClientInvalidAuth(t *testing.T) {
 		AuthenticationType:    "JWT",
 	})
 	assert.Error(t, err)
+
+	// No certificate
+	_, err = NewPulsarClient(&configuration.PulsarConfig{
+		AuthenticationEnabled: true,
+		AuthenticationType:    "TLS",
+	})
+	assert.Error(t, err)
+
+	// Certificate can't be loaded
+	_, err = NewPulsarClient(&configuration.PulsarConfig{
+		AuthenticationEnabled: true,
+		AuthenticationType:    "TLS",
+		TLSCertFilePath:       "does-not-exist.crt",
+		TLSKeyFilePath:        "does-not-exist.key",
+	})
+	assert.Error(t, err)
 }
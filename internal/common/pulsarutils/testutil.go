@@ -17,6 +17,7 @@ type MockPulsarMessage struct {
 	payload     []byte
 	publishTime time.Time
 	properties  map[string]string
+	key         string
 }
 
 func NewMessageId(id int) pulsar.MessageID {
@@ -45,6 +46,14 @@ func NewPulsarMessage(id int, publishTime time.Time, payload []byte) MockPulsarM
 	}
 }
 
+func NewKeyedPulsarMessage(id int, key string, properties map[string]string) MockPulsarMessage {
+	return MockPulsarMessage{
+		messageId:  NewMessageId(id),
+		key:        key,
+		properties: properties,
+	}
+}
+
 func (m MockPulsarMessage) ID() pulsar.MessageID {
 	return m.messageId
 }
@@ -60,3 +69,7 @@ func (m MockPulsarMessage) PublishTime() time.Time {
 func (m MockPulsarMessage) Properties() map[string]string {
 	return m.properties
 }
+
+func (m MockPulsarMessage) Key() string {
+	return m.key
+}
@@ -0,0 +1,72 @@
+// Package rightsizing contains the types shared between Lookout, which computes resource rightsizing recommendations
+// from the resources used by previous runs of a job template, and the Armada server, which may apply them at submission.
+package rightsizing
+
+import (
+	"github.com/armadaproject/armada/internal/armada/configuration"
+)
+
+const (
+	// Recommendations are rounded up to multiples of these.
+	cpuGranularity    = 100              // millicores
+	memoryGranularity = 64 * 1024 * 1024 // bytes
+)
+
+// Recommendation compares the resources requested by jobs of a template with those actually used by their runs.
+// Cpu is measured in millicores and memory in bytes.
+type Recommendation struct {
+	Queue    string `json:"queue"`
+	Template string `json:"template"`
+	// Number of runs for which usage was reported.
+	NumRuns int `json:"numRuns"`
+	// Largest request among the jobs of this template.
+	RequestedCpu    int64 `json:"requestedCpu"`
+	RequestedMemory int64 `json:"requestedMemory"`
+	// 95th percentile of the peak usage of each run.
+	UsedCpu    int64 `json:"usedCpu"`
+	UsedMemory int64 `json:"usedMemory"`
+	// Recommended requests, i.e., used resources plus headroom.
+	RecommendedCpu    int64 `json:"recommendedCpu"`
+	RecommendedMemory int64 `json:"recommendedMemory"`
+	// Fraction of requested resources used, between 0 and 1 unless jobs use more than they request.
+	CpuEfficiency    float64 `json:"cpuEfficiency"`
+	MemoryEfficiency float64 `json:"memoryEfficiency"`
+}
+
+// Recommend sets the recommended requests and efficiency of r from its requested and used resources.
+// Headroom is the fraction of used resources added on top to absorb variation between runs, e.g., 0.2 for 20%.
+func (r *Recommendation) Recommend(headroom float64) {
+	r.RecommendedCpu = roundUp(int64(float64(r.UsedCpu)*(1+headroom)), cpuGranularity)
+	r.RecommendedMemory = roundUp(int64(float64(r.UsedMemory)*(1+headroom)), memoryGranularity)
+	r.CpuEfficiency = efficiency(r.UsedCpu, r.RequestedCpu)
+	r.MemoryEfficiency = efficiency(r.UsedMemory, r.RequestedMemory)
+}
+
+func roundUp(v int64, granularity int64) int64 {
+	if v <= 0 {
+		return granularity
+	}
+	return (v + granularity - 1) / granularity * granularity
+}
+
+func efficiency(used int64, requested int64) float64 {
+	if requested <= 0 {
+		return 0
+	}
+	return float64(used) / float64(requested)
+}
+
+// TemplateKey returns the template a job with the given job set and annotations is an instance of.
+// This is the value of the job template annotation if set, the Airflow dag and task id if both are set,
+// and the job set otherwise.
+func TemplateKey(jobSet string, annotations map[string]string) string {
+	if template, ok := annotations[configuration.JobTemplateAnnotation]; ok && template != "" {
+		return template
+	}
+	dagId, dagOk := annotations[configuration.AirflowDagIdAnnotation]
+	taskId, taskOk := annotations[configuration.AirflowTaskIdAnnotation]
+	if dagOk && taskOk {
+		return dagId + "." + taskId
+	}
+	return jobSet
+}
@@ -0,0 +1,99 @@
+package rightsizing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+)
+
+func TestRecommend(t *testing.T) {
+	tests := map[string]struct {
+		recommendation            Recommendation
+		headroom                  float64
+		expectedRecommendedCpu    int64
+		expectedRecommendedMemory int64
+		expectedCpuEfficiency     float64
+		expectedMemoryEfficiency  float64
+	}{
+		"over-provisioned": {
+			recommendation:            Recommendation{RequestedCpu: 4000, RequestedMemory: 8 * gib, UsedCpu: 1000, UsedMemory: 2 * gib},
+			headroom:                  0.2,
+			expectedRecommendedCpu:    1200,
+			expectedRecommendedMemory: 2*gib + 448*mib,
+			expectedCpuEfficiency:     0.25,
+			expectedMemoryEfficiency:  0.25,
+		},
+		"rounds up": {
+			recommendation:            Recommendation{RequestedCpu: 1000, RequestedMemory: gib, UsedCpu: 101, UsedMemory: mib},
+			expectedRecommendedCpu:    200,
+			expectedRecommendedMemory: 64 * mib,
+			expectedCpuEfficiency:     0.101,
+			expectedMemoryEfficiency:  1.0 / 1024,
+		},
+		"no usage": {
+			recommendation:            Recommendation{RequestedCpu: 1000, RequestedMemory: gib},
+			headroom:                  0.2,
+			expectedRecommendedCpu:    100,
+			expectedRecommendedMemory: 64 * mib,
+		},
+		"under-provisioned": {
+			recommendation:            Recommendation{RequestedCpu: 1000, RequestedMemory: gib, UsedCpu: 2000, UsedMemory: 2 * gib},
+			expectedRecommendedCpu:    2000,
+			expectedRecommendedMemory: 2 * gib,
+			expectedCpuEfficiency:     2,
+			expectedMemoryEfficiency:  2,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := tc.recommendation
+			r.Recommend(tc.headroom)
+			assert.Equal(t, tc.expectedRecommendedCpu, r.RecommendedCpu)
+			assert.Equal(t, tc.expectedRecommendedMemory, r.RecommendedMemory)
+			assert.InDelta(t, tc.expectedCpuEfficiency, r.CpuEfficiency, 1e-9)
+			assert.InDelta(t, tc.expectedMemoryEfficiency, r.MemoryEfficiency, 1e-9)
+		})
+	}
+}
+
+func TestTemplateKey(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		expected    string
+	}{
+		"template annotation": {
+			annotations: map[string]string{
+				configuration.JobTemplateAnnotation:   "template",
+				configuration.AirflowDagIdAnnotation:  "dag",
+				configuration.AirflowTaskIdAnnotation: "task",
+			},
+			expected: "template",
+		},
+		"airflow task": {
+			annotations: map[string]string{
+				configuration.AirflowDagIdAnnotation:  "dag",
+				configuration.AirflowTaskIdAnnotation: "task",
+			},
+			expected: "dag.task",
+		},
+		"dag id only": {
+			annotations: map[string]string{configuration.AirflowDagIdAnnotation: "dag"},
+			expected:    "job-set",
+		},
+		"no annotations": {
+			expected: "job-set",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, TemplateKey("job-set", tc.annotations))
+		})
+	}
+}
+
+const (
+	mib = 1024 * 1024
+	gib = 1024 * mib
+)
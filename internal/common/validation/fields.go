@@ -0,0 +1,278 @@
+package validation
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	v1 "k8s.io/api/core/v1"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	executorutil "github.com/armadaproject/armada/internal/executor/util"
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+// FieldError describes why a single field of a job submit request is invalid.
+type FieldError struct {
+	// Path of the invalid field within the request, e.g., jobRequestItems[0].podSpecs[0].containers[1].image.
+	Path string `json:"path"`
+	// Reason the field is invalid.
+	Reason string `json:"reason"`
+	// Suggestion for how to fix the field, if any.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+func (e *FieldError) Error() string {
+	if e.Suggestion == "" {
+		return fmt.Sprintf("%s: %s", e.Path, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s (%s)", e.Path, e.Reason, e.Suggestion)
+}
+
+// ValidateJobSubmitRequestFields checks request against the job spec schema supported by Armada and returns an error
+// for each invalid field. Unlike the checks performed at submission, which stop at the first error,
+// all fields are checked, such that users can fix all problems at once.
+func ValidateJobSubmitRequestFields(request *api.JobSubmitRequest, config *configuration.SchedulingConfig) []*FieldError {
+	var rv []*FieldError
+	if request.Queue == "" {
+		rv = append(rv, &FieldError{Path: "queue", Reason: "queue not specified", Suggestion: "set the queue to submit to"})
+	}
+	if request.JobSetId == "" {
+		rv = append(rv, &FieldError{Path: "jobSetId", Reason: "job set not specified", Suggestion: "set a job set id to group jobs by"})
+	}
+	if len(request.JobRequestItems) == 0 {
+		rv = append(rv, &FieldError{Path: "jobRequestItems", Reason: "no jobs provided", Suggestion: "add at least one job"})
+	}
+	for i, item := range request.JobRequestItems {
+		rv = append(rv, validateJobSubmitRequestItemFields(fmt.Sprintf("jobRequestItems[%d]", i), item, config)...)
+	}
+	return rv
+}
+
+func validateJobSubmitRequestItemFields(itemPath string, item *api.JobSubmitRequestItem, config *configuration.SchedulingConfig) []*FieldError {
+	var rv []*FieldError
+	// Jobs of a node flavor get their resources from the flavor.
+	var nodeFlavor *nodeFlavorFields
+	if name, ok := item.Annotations[configuration.NodeFlavorAnnotation]; ok {
+		nodeFlavor = &nodeFlavorFields{name: name}
+		if flavor, ok := config.NodeFlavors[name]; ok {
+			nodeFlavor.resources = flavor.Resources
+		} else {
+			suggestion := "remove the annotation and specify resource requests instead"
+			if len(config.NodeFlavors) > 0 {
+				names := maps.Keys(config.NodeFlavors)
+				sort.Strings(names)
+				suggestion = fmt.Sprintf("use one of %s", strings.Join(names, ", "))
+			}
+			rv = append(rv, &FieldError{
+				Path:       fmt.Sprintf("%s.annotations[%s]", itemPath, configuration.NodeFlavorAnnotation),
+				Reason:     fmt.Sprintf("node flavor %s does not exist", name),
+				Suggestion: suggestion,
+			})
+		}
+	}
+	switch {
+	case item.PodSpec != nil && len(item.PodSpecs) > 0:
+		rv = append(rv, &FieldError{
+			Path:       itemPath,
+			Reason:     "both podSpec and podSpecs provided",
+			Suggestion: "provide the pod spec in podSpecs only",
+		})
+	case item.PodSpec == nil && len(item.PodSpecs) == 0:
+		rv = append(rv, &FieldError{
+			Path:       itemPath + ".podSpecs",
+			Reason:     "no pod spec provided",
+			Suggestion: "provide exactly one pod spec",
+		})
+	case len(item.PodSpecs) > 1:
+		rv = append(rv, &FieldError{
+			Path:       itemPath + ".podSpecs",
+			Reason:     fmt.Sprintf("%d pod specs provided, but jobs with multiple pods are not supported", len(item.PodSpecs)),
+			Suggestion: "submit each pod as a separate job, optionally as a gang",
+		})
+	case item.PodSpec != nil:
+		rv = append(rv, validatePodSpecFields(itemPath+".podSpec", item.PodSpec, nodeFlavor, config)...)
+	default:
+		rv = append(rv, validatePodSpecFields(itemPath+".podSpecs[0]", item.PodSpecs[0], nodeFlavor, config)...)
+	}
+
+	portsPath := make(map[uint32]string)
+	for i, ingress := range item.Ingress {
+		ingressPath := fmt.Sprintf("%s.ingress[%d]", itemPath, i)
+		if len(ingress.Ports) == 0 {
+			rv = append(rv, &FieldError{Path: ingressPath + ".ports", Reason: "ingress has no ports", Suggestion: "list the ports to expose"})
+		}
+		for j, port := range ingress.Ports {
+			portPath := fmt.Sprintf("%s.ports[%d]", ingressPath, j)
+			if existingPath, ok := portsPath[port]; ok {
+				rv = append(rv, &FieldError{
+					Path:       portPath,
+					Reason:     fmt.Sprintf("port %d already has an ingress configuration at %s", port, existingPath),
+					Suggestion: "configure each port in at most one ingress",
+				})
+			} else {
+				portsPath[port] = portPath
+			}
+		}
+	}
+
+	if filePath, ok := executorutil.OutputCaptureFilePath(item.Annotations); ok && !path.IsAbs(filePath) {
+		rv = append(rv, &FieldError{
+			Path:       fmt.Sprintf("%s.annotations[%s]", itemPath, configuration.CaptureOutputAnnotation),
+			Reason:     fmt.Sprintf("%q is neither %q nor an absolute file path", filePath, configuration.CaptureOutputLogs),
+			Suggestion: "use an absolute path, e.g., /tmp/output.json",
+		})
+	}
+	return rv
+}
+
+// nodeFlavorFields is the node flavor requested by a job, if any;
+// resources is nil if the flavor doesn't exist.
+type nodeFlavorFields struct {
+	name      string
+	resources v1.ResourceList
+}
+
+func validatePodSpecFields(podSpecPath string, spec *v1.PodSpec, nodeFlavor *nodeFlavorFields, config *configuration.SchedulingConfig) []*FieldError {
+	var rv []*FieldError
+	if size := uint(spec.Size()); size > config.MaxPodSpecSizeBytes {
+		rv = append(rv, &FieldError{
+			Path:       podSpecPath,
+			Reason:     fmt.Sprintf("pod spec has a size of %d bytes, but at most %d bytes are allowed", size, config.MaxPodSpecSizeBytes),
+			Suggestion: "move large values, e.g., scripts or environment variables, into a config map or the container image",
+		})
+	}
+	if len(spec.Containers) == 0 {
+		rv = append(rv, &FieldError{Path: podSpecPath + ".containers", Reason: "pod spec has no containers", Suggestion: "add at least one container"})
+	}
+	if spec.TerminationGracePeriodSeconds != nil {
+		minSeconds := int64(config.MinTerminationGracePeriod.Seconds())
+		maxSeconds := int64(config.MaxTerminationGracePeriod.Seconds())
+		if seconds := *spec.TerminationGracePeriodSeconds; seconds < minSeconds || seconds > maxSeconds {
+			rv = append(rv, &FieldError{
+				Path:       podSpecPath + ".terminationGracePeriodSeconds",
+				Reason:     fmt.Sprintf("%d is not in [%d, %d]", seconds, minSeconds, maxSeconds),
+				Suggestion: "omit the field to use the default",
+			})
+		}
+	}
+	if spec.PriorityClassName != "" {
+		if _, ok := config.Preemption.PriorityClasses[spec.PriorityClassName]; !ok {
+			allowed := maps.Keys(config.Preemption.PriorityClasses)
+			sort.Strings(allowed)
+			rv = append(rv, &FieldError{
+				Path:       podSpecPath + ".priorityClassName",
+				Reason:     fmt.Sprintf("priority class %s is not supported", spec.PriorityClassName),
+				Suggestion: fmt.Sprintf("use one of %s", strings.Join(allowed, ", ")),
+			})
+		}
+	}
+	if spec.Affinity != nil && spec.Affinity.NodeAffinity != nil {
+		if err := validatePreferredNodeAffinity(spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution); err != nil {
+			rv = append(rv, &FieldError{
+				Path:       podSpecPath + ".affinity.nodeAffinity.preferredDuringSchedulingIgnoredDuringExecution",
+				Reason:     err.Error(),
+				Suggestion: "give each term a weight between 1 and 100, with higher weights for stronger preferences",
+			})
+		}
+		if err := validateRequiredNodeAffinity(spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution); err != nil {
+			rv = append(rv, &FieldError{
+				Path:   podSpecPath + ".affinity.nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution",
+				Reason: err.Error(),
+			})
+		}
+	}
+
+	portsPath := make(map[int32]string)
+	for i, container := range spec.Containers {
+		containerPath := fmt.Sprintf("%s.containers[%d]", podSpecPath, i)
+		rv = append(rv, validateContainerFields(containerPath, container, nodeFlavor, config)...)
+		for j, port := range container.Ports {
+			portPath := fmt.Sprintf("%s.ports[%d]", containerPath, j)
+			if existingPath, ok := portsPath[port.ContainerPort]; ok {
+				rv = append(rv, &FieldError{
+					Path:       portPath,
+					Reason:     fmt.Sprintf("container port %d is already exposed at %s", port.ContainerPort, existingPath),
+					Suggestion: "expose each port only once",
+				})
+			} else {
+				portsPath[port.ContainerPort] = portPath
+			}
+		}
+	}
+	return rv
+}
+
+func validateContainerFields(containerPath string, container v1.Container, nodeFlavor *nodeFlavorFields, config *configuration.SchedulingConfig) []*FieldError {
+	var rv []*FieldError
+	if errs := k8svalidation.IsDNS1123Label(container.Name); len(errs) > 0 {
+		rv = append(rv, &FieldError{
+			Path:       containerPath + ".name",
+			Reason:     fmt.Sprintf("%q is not a valid container name: %s", container.Name, strings.Join(errs, ", ")),
+			Suggestion: "use lower case alphanumeric characters and '-', e.g., main",
+		})
+	}
+	if container.Image == "" {
+		rv = append(rv, &FieldError{Path: containerPath + ".image", Reason: "no image specified", Suggestion: "set the container image to run"})
+	}
+	resourcesPath := containerPath + ".resources"
+	if nodeFlavor == nil && len(container.Resources.Limits) == 0 {
+		rv = append(rv, &FieldError{
+			Path:       resourcesPath + ".limits",
+			Reason:     "no resource limits specified",
+			Suggestion: "set limits equal to requests, e.g., cpu and memory",
+		})
+	}
+	if nodeFlavor == nil && len(container.Resources.Requests) == 0 {
+		rv = append(rv, &FieldError{
+			Path:       resourcesPath + ".requests",
+			Reason:     "no resource requests specified",
+			Suggestion: "set requests equal to limits, e.g., cpu and memory",
+		})
+	}
+	for _, requestType := range []string{"limits", "requests"} {
+		resources := container.Resources.Limits
+		if requestType == "requests" {
+			resources = container.Resources.Requests
+		}
+		for _, name := range sortedResourceNames(resources) {
+			if nodeFlavor != nil {
+				if _, ok := nodeFlavor.resources[name]; ok {
+					rv = append(rv, &FieldError{
+						Path:       fmt.Sprintf("%s.%s[%s]", resourcesPath, requestType, name),
+						Reason:     fmt.Sprintf("%s is set by node flavor %s", name, nodeFlavor.name),
+						Suggestion: "remove the field",
+					})
+					continue
+				}
+			}
+			quantity := resources[name]
+			minimum, ok := config.MinJobResources[name]
+			if ok && quantity.Value() < minimum.Value() {
+				rv = append(rv, &FieldError{
+					Path:       fmt.Sprintf("%s.%s[%s]", resourcesPath, requestType, name),
+					Reason:     fmt.Sprintf("%s is below the minimum of %s", quantity.String(), minimum.String()),
+					Suggestion: fmt.Sprintf("request at least %s", minimum.String()),
+				})
+			}
+		}
+	}
+	if len(container.Resources.Limits) > 0 && len(container.Resources.Requests) > 0 &&
+		!resourceListEquals(container.Resources.Requests, container.Resources.Limits) {
+		rv = append(rv, &FieldError{
+			Path:       resourcesPath,
+			Reason:     "resource requests and limits differ, which is not supported",
+			Suggestion: "set limits equal to requests",
+		})
+	}
+	return rv
+}
+
+func sortedResourceNames(resources v1.ResourceList) []v1.ResourceName {
+	rv := maps.Keys(resources)
+	sort.Slice(rv, func(i, j int) bool { return rv[i] < rv[j] })
+	return rv
+}
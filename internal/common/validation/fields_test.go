@@ -0,0 +1,152 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/types"
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+func TestValidateJobSubmitRequestFields(t *testing.T) {
+	config := &configuration.SchedulingConfig{
+		MinJobResources:           v1.ResourceList{"memory": resource.MustParse("1Gi")},
+		MaxPodSpecSizeBytes:       65535,
+		MinTerminationGracePeriod: time.Second,
+		MaxTerminationGracePeriod: time.Minute,
+		Preemption: configuration.PreemptionConfig{
+			PriorityClasses: map[string]types.PriorityClass{"armada-default": {}, "armada-preemptible": {}},
+		},
+		NodeFlavors: map[string]configuration.NodeFlavor{
+			"gpu-a100-1x": {Resources: v1.ResourceList{"cpu": resource.MustParse("8"), "nvidia.com/gpu": resource.MustParse("1")}},
+		},
+	}
+	resources := v1.ResourceList{"cpu": resource.MustParse("1"), "memory": resource.MustParse("1Gi")}
+	validRequest := func() *api.JobSubmitRequest {
+		return &api.JobSubmitRequest{
+			Queue:    "queue",
+			JobSetId: "job-set",
+			JobRequestItems: []*api.JobSubmitRequestItem{
+				{
+					PodSpecs: []*v1.PodSpec{
+						{
+							Containers: []v1.Container{
+								{
+									Name:      "main",
+									Image:     "alpine:3.18",
+									Ports:     []v1.ContainerPort{{ContainerPort: 8080}},
+									Resources: v1.ResourceRequirements{Requests: resources, Limits: resources},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := map[string]struct {
+		modify        func(req *api.JobSubmitRequest)
+		expectedPaths []string
+	}{
+		"valid": {
+			modify: func(req *api.JobSubmitRequest) {},
+		},
+		"missing queue and job set": {
+			modify: func(req *api.JobSubmitRequest) {
+				req.Queue = ""
+				req.JobSetId = ""
+			},
+			expectedPaths: []string{"queue", "jobSetId"},
+		},
+		"no pod spec": {
+			modify: func(req *api.JobSubmitRequest) {
+				req.JobRequestItems[0].PodSpecs = nil
+			},
+			expectedPaths: []string{"jobRequestItems[0].podSpecs"},
+		},
+		"multiple pod specs": {
+			modify: func(req *api.JobSubmitRequest) {
+				item := req.JobRequestItems[0]
+				item.PodSpecs = append(item.PodSpecs, item.PodSpecs[0])
+			},
+			expectedPaths: []string{"jobRequestItems[0].podSpecs"},
+		},
+		"all invalid fields reported": {
+			modify: func(req *api.JobSubmitRequest) {
+				podSpec := req.JobRequestItems[0].PodSpecs[0]
+				podSpec.PriorityClassName = "unknown"
+				podSpec.TerminationGracePeriodSeconds = pointer.Int64(3600)
+				podSpec.Containers = append(podSpec.Containers, v1.Container{
+					Name:  "Sidecar",
+					Ports: []v1.ContainerPort{{ContainerPort: 8080}},
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{"memory": resource.MustParse("512Mi")},
+						Limits:   v1.ResourceList{"memory": resource.MustParse("1Gi")},
+					},
+				})
+			},
+			expectedPaths: []string{
+				"jobRequestItems[0].podSpecs[0].terminationGracePeriodSeconds",
+				"jobRequestItems[0].podSpecs[0].priorityClassName",
+				"jobRequestItems[0].podSpecs[0].containers[1].name",
+				"jobRequestItems[0].podSpecs[0].containers[1].image",
+				"jobRequestItems[0].podSpecs[0].containers[1].resources.requests[memory]",
+				"jobRequestItems[0].podSpecs[0].containers[1].resources",
+				"jobRequestItems[0].podSpecs[0].containers[1].ports[0]",
+			},
+		},
+		"duplicate ingress port": {
+			modify: func(req *api.JobSubmitRequest) {
+				req.JobRequestItems[0].Ingress = []*api.IngressConfig{{Ports: []uint32{8080}}, {Ports: []uint32{8080}}}
+			},
+			expectedPaths: []string{"jobRequestItems[0].ingress[1].ports[0]"},
+		},
+		"node flavor": {
+			modify: func(req *api.JobSubmitRequest) {
+				req.JobRequestItems[0].Annotations = map[string]string{configuration.NodeFlavorAnnotation: "gpu-a100-1x"}
+				req.JobRequestItems[0].PodSpecs[0].Containers[0].Resources = v1.ResourceRequirements{}
+			},
+		},
+		"unknown node flavor": {
+			modify: func(req *api.JobSubmitRequest) {
+				req.JobRequestItems[0].Annotations = map[string]string{configuration.NodeFlavorAnnotation: "gpu-h100-1x"}
+				req.JobRequestItems[0].PodSpecs[0].Containers[0].Resources = v1.ResourceRequirements{}
+			},
+			expectedPaths: []string{"jobRequestItems[0].annotations[" + configuration.NodeFlavorAnnotation + "]"},
+		},
+		"resource set by node flavor": {
+			modify: func(req *api.JobSubmitRequest) {
+				req.JobRequestItems[0].Annotations = map[string]string{configuration.NodeFlavorAnnotation: "gpu-a100-1x"}
+			},
+			expectedPaths: []string{
+				"jobRequestItems[0].podSpecs[0].containers[0].resources.limits[cpu]",
+				"jobRequestItems[0].podSpecs[0].containers[0].resources.requests[cpu]",
+			},
+		},
+		"relative output capture path": {
+			modify: func(req *api.JobSubmitRequest) {
+				req.JobRequestItems[0].Annotations = map[string]string{configuration.CaptureOutputAnnotation: "output.json"}
+			},
+			expectedPaths: []string{"jobRequestItems[0].annotations[" + configuration.CaptureOutputAnnotation + "]"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := validRequest()
+			tc.modify(req)
+			fieldErrors := ValidateJobSubmitRequestFields(req, config)
+			var paths []string
+			for _, fieldError := range fieldErrors {
+				paths = append(paths, fieldError.Path)
+				assert.NotEmpty(t, fieldError.Reason)
+			}
+			assert.Equal(t, tc.expectedPaths, paths)
+		})
+	}
+}
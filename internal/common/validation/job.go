@@ -1,13 +1,20 @@
 package validation
 
 import (
+	"fmt"
+	"path"
+	"strings"
+
 	"github.com/pkg/errors"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/armadaproject/armada/internal/scheduler"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
 
 	"github.com/armadaproject/armada/internal/armada/configuration"
 	"github.com/armadaproject/armada/internal/common/armadaerrors"
 	"github.com/armadaproject/armada/internal/common/util"
+	executorutil "github.com/armadaproject/armada/internal/executor/util"
 	"github.com/armadaproject/armada/pkg/api"
 )
 
@@ -24,10 +31,29 @@ func ValidateApiJobs(jobs []*api.Job, config configuration.SchedulingConfig) err
 }
 
 type gangDetails = struct {
-	expectedCardinality         int
-	expectedMinimumCardinality  int
-	expectedPriorityClassName   string
-	expectedNodeUniformityLabel string
+	expectedCardinality          int
+	expectedMinimumCardinality   int
+	expectedPriorityClassName    string
+	expectedNodeUniformityLabel  string
+	expectedNodeUniformitySoft   string
+	expectedNodeUniformityLabels string
+	expectedColocationGroup      string
+	expectedColocationLabel      string
+	expectedFailureDomainLabel   string
+	expectedMinFailureDomains    int
+	expectedSpreadLabel          string
+	expectedGangQueue            string
+	expectedSchedulingDeadline   string
+	// Set for gangs made up of jobs with roles; maps each role to the details of jobs with that role.
+	gangRoleDetailsByRole map[string]gangRoleDetails
+	// Number of jobs in the gang marked as the gang leader.
+	numLeaders int
+}
+
+type gangRoleDetails = struct {
+	expectedCardinality        int
+	expectedMinimumCardinality int
+	numJobs                    int
 }
 
 func validateGangs(jobs []*api.Job) (map[string]gangDetails, error) {
@@ -36,15 +62,59 @@ func validateGangs(jobs []*api.Job) (map[string]gangDetails, error) {
 		annotations := job.Annotations
 		gangId, gangCardinality, gangMinimumCardinality, isGangJob, err := scheduler.GangIdAndCardinalityFromAnnotations(annotations)
 		nodeUniformityLabel := annotations[configuration.GangNodeUniformityLabelAnnotation]
+		nodeUniformitySoft := annotations[configuration.GangNodeUniformitySoftAnnotation]
+		colocationGroup := annotations[configuration.ColocationGroupAnnotation]
+		colocationLabel := annotations[configuration.ColocationLabelAnnotation]
+		spreadLabel := annotations[configuration.GangSpreadLabelAnnotation]
+		gangQueue, hasGangQueue := annotations[configuration.GangQueueAnnotation]
+		schedulingDeadline, hasSchedulingDeadline := annotations[configuration.GangSchedulingDeadlineAnnotation]
 		if err != nil {
 			return nil, errors.WithMessagef(err, "%d-th job with id %s in gang %s", i, job.Id, gangId)
 		}
 		if !isGangJob {
+			if executorutil.IsGangLeader(annotations) {
+				return nil, errors.Errorf("%d-th job with id %s is marked as gang leader but isn't part of a gang", i, job.Id)
+			}
+			if hasGangQueue {
+				return nil, errors.Errorf("%d-th job with id %s sets annotation %s but isn't part of a gang", i, job.Id, configuration.GangQueueAnnotation)
+			}
+			if hasSchedulingDeadline {
+				return nil, errors.Errorf("%d-th job with id %s sets annotation %s but isn't part of a gang", i, job.Id, configuration.GangSchedulingDeadlineAnnotation)
+			}
 			continue
 		}
+		if hasGangQueue && gangQueue == "" {
+			return nil, errors.Errorf("%d-th job with id %s in gang %s: annotation %s must not be empty", i, job.Id, gangId, configuration.GangQueueAnnotation)
+		}
 		if gangId == "" {
 			return nil, errors.Errorf("empty gang id for %d-th job with id %s", i, job.Id)
 		}
+		nodeUniformityLabels, err := scheduler.GangNodeUniformityLabelsFromAnnotations(annotations)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "%d-th job with id %s in gang %s", i, job.Id, gangId)
+		}
+		failureDomainLabel, minFailureDomains, err := scheduler.GangFailureDomainsFromAnnotations(annotations)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "%d-th job with id %s in gang %s", i, job.Id, gangId)
+		}
+		if spreadLabel != "" {
+			if nodeUniformityLabel != "" || len(nodeUniformityLabels) > 0 || failureDomainLabel != "" {
+				return nil, errors.Errorf(
+					"%d-th job with id %s in gang %s: annotation %s can't be combined with a node uniformity or failure-domain constraint",
+					i, job.Id, gangId, configuration.GangSpreadLabelAnnotation,
+				)
+			}
+		}
+		if minFailureDomains > gangMinimumCardinality {
+			return nil, errors.Errorf(
+				"%d-th job with id %s in gang %s: gang minimum failure domains %d cannot be greater than gang minimum cardinality %d",
+				i, job.Id, gangId, minFailureDomains, gangMinimumCardinality,
+			)
+		}
+		gangRole, gangRoleCardinality, gangRoleMinimumCardinality, hasGangRole, err := scheduler.GangRoleAndCardinalityFromAnnotations(annotations)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "%d-th job with id %s in gang %s", i, job.Id, gangId)
+		}
 		podSpec := util.PodSpecFromJob(job)
 		if details, ok := gangDetailsByGangId[gangId]; ok {
 			if details.expectedCardinality != gangCardinality {
@@ -71,6 +141,54 @@ func validateGangs(jobs []*api.Job) (map[string]gangDetails, error) {
 					i, job.Id, gangId, details.expectedNodeUniformityLabel, nodeUniformityLabel,
 				)
 			}
+			if nodeUniformitySoft != details.expectedNodeUniformitySoft {
+				return nil, errors.Errorf(
+					"inconsistent nodeUniformitySoft for %d-th job with id %s in gang %s: expected %q but got %q",
+					i, job.Id, gangId, details.expectedNodeUniformitySoft, nodeUniformitySoft,
+				)
+			}
+			if strings.Join(nodeUniformityLabels, ",") != details.expectedNodeUniformityLabels {
+				return nil, errors.Errorf(
+					"inconsistent nodeUniformityLabels for %d-th job with id %s in gang %s: expected %q but got %q",
+					i, job.Id, gangId, details.expectedNodeUniformityLabels, strings.Join(nodeUniformityLabels, ","),
+				)
+			}
+			if colocationGroup != details.expectedColocationGroup || colocationLabel != details.expectedColocationLabel {
+				return nil, errors.Errorf(
+					"inconsistent colocation group for %d-th job with id %s in gang %s: expected %q with label %q but got %q with label %q",
+					i, job.Id, gangId, details.expectedColocationGroup, details.expectedColocationLabel, colocationGroup, colocationLabel,
+				)
+			}
+			if failureDomainLabel != details.expectedFailureDomainLabel || minFailureDomains != details.expectedMinFailureDomains {
+				return nil, errors.Errorf(
+					"inconsistent failure domains for %d-th job with id %s in gang %s: expected %d values of label %q but got %d values of label %q",
+					i, job.Id, gangId, details.expectedMinFailureDomains, details.expectedFailureDomainLabel, minFailureDomains, failureDomainLabel,
+				)
+			}
+			if spreadLabel != details.expectedSpreadLabel {
+				return nil, errors.Errorf(
+					"inconsistent spread label for %d-th job with id %s in gang %s: expected %q but got %q",
+					i, job.Id, gangId, details.expectedSpreadLabel, spreadLabel,
+				)
+			}
+			if gangQueue != details.expectedGangQueue {
+				return nil, errors.Errorf(
+					"inconsistent gang queue for %d-th job with id %s in gang %s: expected %q but got %q",
+					i, job.Id, gangId, details.expectedGangQueue, gangQueue,
+				)
+			}
+			if schedulingDeadline != details.expectedSchedulingDeadline {
+				return nil, errors.Errorf(
+					"inconsistent gang scheduling deadline for %d-th job with id %s in gang %s: expected %q but got %q",
+					i, job.Id, gangId, details.expectedSchedulingDeadline, schedulingDeadline,
+				)
+			}
+			if hasGangRole != (details.gangRoleDetailsByRole != nil) {
+				return nil, errors.Errorf(
+					"inconsistent gang roles for %d-th job with id %s in gang %s: either all or none of the jobs in a gang must have a role",
+					i, job.Id, gangId,
+				)
+			}
 			gangDetailsByGangId[gangId] = details
 		} else {
 			details.expectedCardinality = gangCardinality
@@ -79,12 +197,81 @@ func validateGangs(jobs []*api.Job) (map[string]gangDetails, error) {
 				details.expectedPriorityClassName = podSpec.PriorityClassName
 			}
 			details.expectedNodeUniformityLabel = nodeUniformityLabel
+			details.expectedNodeUniformitySoft = nodeUniformitySoft
+			details.expectedNodeUniformityLabels = strings.Join(nodeUniformityLabels, ",")
+			details.expectedColocationGroup = colocationGroup
+			details.expectedColocationLabel = colocationLabel
+			details.expectedFailureDomainLabel = failureDomainLabel
+			details.expectedMinFailureDomains = minFailureDomains
+			details.expectedSpreadLabel = spreadLabel
+			details.expectedGangQueue = gangQueue
+			details.expectedSchedulingDeadline = schedulingDeadline
+			if hasGangRole {
+				details.gangRoleDetailsByRole = make(map[string]gangRoleDetails)
+			}
+			gangDetailsByGangId[gangId] = details
+		}
+		if executorutil.IsGangLeader(annotations) {
+			details := gangDetailsByGangId[gangId]
+			details.numLeaders++
+			if details.numLeaders > 1 {
+				return nil, errors.Errorf("%d-th job with id %s in gang %s: a gang may have at most one leader", i, job.Id, gangId)
+			}
+			// Service names are of the form <prefix>-<type>, where the longest type is "nodeport" or "headless".
+			if errs := k8svalidation.IsDNS1035Label(executorutil.GangServiceNamePrefix(gangId) + "-headless"); len(errs) > 0 {
+				return nil, errors.Errorf(
+					"%d-th job with id %s in gang %s: gang id can't be used to name the services of the gang leader: %s",
+					i, job.Id, gangId, strings.Join(errs, ", "),
+				)
+			}
 			gangDetailsByGangId[gangId] = details
 		}
+		if hasGangRole {
+			if err := validateGangRole(
+				gangDetailsByGangId[gangId], gangRole, gangRoleCardinality, gangRoleMinimumCardinality,
+			); err != nil {
+				return nil, errors.WithMessagef(err, "%d-th job with id %s in gang %s", i, job.Id, gangId)
+			}
+		}
 	}
 	return gangDetailsByGangId, nil
 }
 
+// validateGangRole checks that a job with the given role is consistent with other jobs in its gang with the same role,
+// and updates details to account for the job.
+func validateGangRole(details gangDetails, gangRole string, gangRoleCardinality, gangRoleMinimumCardinality int) error {
+	roleDetails, ok := details.gangRoleDetailsByRole[gangRole]
+	if !ok {
+		totalGangRoleCardinality := gangRoleCardinality
+		for _, otherRoleDetails := range details.gangRoleDetailsByRole {
+			totalGangRoleCardinality += otherRoleDetails.expectedCardinality
+		}
+		if totalGangRoleCardinality > details.expectedCardinality {
+			return errors.Errorf(
+				"sum of gang role cardinalities %d exceeds gang cardinality %d", totalGangRoleCardinality, details.expectedCardinality,
+			)
+		}
+		roleDetails.expectedCardinality = gangRoleCardinality
+		roleDetails.expectedMinimumCardinality = gangRoleMinimumCardinality
+	}
+	if roleDetails.expectedCardinality != gangRoleCardinality {
+		return errors.Errorf(
+			"inconsistent cardinality for gang role %s: expected %d but got %d", gangRole, roleDetails.expectedCardinality, gangRoleCardinality,
+		)
+	}
+	if roleDetails.expectedMinimumCardinality != gangRoleMinimumCardinality {
+		return errors.Errorf(
+			"inconsistent minimum cardinality for gang role %s: expected %d but got %d", gangRole, roleDetails.expectedMinimumCardinality, gangRoleMinimumCardinality,
+		)
+	}
+	roleDetails.numJobs++
+	if roleDetails.numJobs > roleDetails.expectedCardinality {
+		return errors.Errorf("more than %d jobs with gang role %s", roleDetails.expectedCardinality, gangRole)
+	}
+	details.gangRoleDetailsByRole[gangRole] = roleDetails
+	return nil
+}
+
 func ValidateApiJob(job *api.Job, config configuration.SchedulingConfig) error {
 	if err := ValidateApiJobPodSpecs(job); err != nil {
 		return err
@@ -97,6 +284,116 @@ func ValidateApiJob(job *api.Job, config configuration.SchedulingConfig) error {
 			return err
 		}
 	}
+	if err := validateOutputCapture(job); err != nil {
+		return err
+	}
+	if err := validateSecretReferences(job); err != nil {
+		return err
+	}
+	if err := validateExpectedRuntime(job); err != nil {
+		return err
+	}
+	if err := validateSchedulingAnnotations(job); err != nil {
+		return err
+	}
+	if err := validateScheduleNearJobs(job); err != nil {
+		return err
+	}
+	if err := validateBindOnly(job); err != nil {
+		return err
+	}
+	return nil
+}
+
+func validateOutputCapture(job *api.Job) error {
+	filePath, ok := executorutil.OutputCaptureFilePath(job.Annotations)
+	if ok && !path.IsAbs(filePath) {
+		return errors.WithStack(&armadaerrors.ErrInvalidArgument{
+			Name:  configuration.CaptureOutputAnnotation,
+			Value: filePath,
+			Message: fmt.Sprintf(
+				"output to capture must be either %q or an absolute file path",
+				configuration.CaptureOutputLogs,
+			),
+		})
+	}
+	return nil
+}
+
+func validateSecretReferences(job *api.Job) error {
+	for _, ref := range executorutil.SecretReferences(job.Annotations) {
+		if errs := k8svalidation.IsDNS1123Subdomain(ref); len(errs) > 0 {
+			return errors.WithStack(&armadaerrors.ErrInvalidArgument{
+				Name:    configuration.SecretReferencesAnnotation,
+				Value:   ref,
+				Message: fmt.Sprintf("secret reference %q is not a valid secret name: %s", ref, strings.Join(errs, ", ")),
+			})
+		}
+	}
+	return nil
+}
+
+func validateExpectedRuntime(job *api.Job) error {
+	value, ok := job.Annotations[configuration.ExpectedRuntimeAnnotation]
+	if !ok {
+		return nil
+	}
+	if _, ok := scheduler.ExpectedRuntimeFromAnnotations(job.Annotations); !ok {
+		return errors.WithStack(&armadaerrors.ErrInvalidArgument{
+			Name:    configuration.ExpectedRuntimeAnnotation,
+			Value:   value,
+			Message: "expected runtime must be a non-negative duration, e.g., \"30m\"",
+		})
+	}
+	return nil
+}
+
+// validateSchedulingAnnotations checks that the annotations the scheduler parses into schedulercontext.SchedulingAnnotations have valid values.
+func validateSchedulingAnnotations(job *api.Job) error {
+	_, err := schedulercontext.ParseSchedulingAnnotations(job.Annotations)
+	return err
+}
+
+// maxScheduleNearJobs is the maximum number of jobs a job may ask to be scheduled near via ScheduleNearJobsAnnotation.
+const maxScheduleNearJobs = 16
+
+func validateScheduleNearJobs(job *api.Job) error {
+	value, ok := job.Annotations[configuration.ScheduleNearJobsAnnotation]
+	if !ok {
+		return nil
+	}
+	if jobIds := scheduler.ScheduleNearJobIdsFromAnnotations(job.Annotations); len(jobIds) == 0 || len(jobIds) > maxScheduleNearJobs {
+		return errors.WithStack(&armadaerrors.ErrInvalidArgument{
+			Name:    configuration.ScheduleNearJobsAnnotation,
+			Value:   value,
+			Message: fmt.Sprintf("must be a comma-separated list of between 1 and %d job ids", maxScheduleNearJobs),
+		})
+	}
+	return nil
+}
+
+// validateBindOnly checks that jobs bound to a node give both the executor and the node,
+// and aren't gang jobs, since bound jobs are leased individually as soon as their node is reported.
+func validateBindOnly(job *api.Job) error {
+	executorId, hasExecutor := job.Annotations[configuration.BindToExecutorAnnotation]
+	nodeName, hasNode := job.Annotations[configuration.BindToNodeAnnotation]
+	if !hasExecutor && !hasNode {
+		return nil
+	}
+	if executorId == "" || nodeName == "" {
+		return errors.WithStack(&armadaerrors.ErrInvalidArgument{
+			Name:    configuration.BindToNodeAnnotation,
+			Value:   nodeName,
+			Message: fmt.Sprintf("%s and %s must be set together", configuration.BindToExecutorAnnotation, configuration.BindToNodeAnnotation),
+		})
+	}
+	if _, ok := job.Annotations[configuration.GangIdAnnotation]; ok {
+		return errors.WithStack(&armadaerrors.ErrInvalidArgument{
+			Name:    configuration.GangIdAnnotation,
+			Value:   job.Annotations[configuration.GangIdAnnotation],
+			Message: "jobs bound to a node can't be part of a gang",
+		})
+	}
 	return nil
 }
 
@@ -2,11 +2,13 @@ package validation
 
 import (
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 
 	"github.com/armadaproject/armada/internal/armada/configuration"
 	"github.com/armadaproject/armada/internal/common/armadaerrors"
@@ -382,6 +384,517 @@ func TestValidateGangs(t *testing.T) {
 			ExpectSuccess:                          false,
 			ExpectedGangMinimumCardinalityByGangId: nil,
 		},
+		"inconsistent NodeUniformitySoft": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                  "bar",
+						configuration.GangCardinalityAnnotation:         strconv.Itoa(2),
+						configuration.GangNodeUniformityLabelAnnotation: "foo",
+						configuration.GangNodeUniformitySoftAnnotation:  "true",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                  "bar",
+						configuration.GangCardinalityAnnotation:         strconv.Itoa(2),
+						configuration.GangNodeUniformityLabelAnnotation: "foo",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"inconsistent NodeUniformityLabels": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                   "bar",
+						configuration.GangCardinalityAnnotation:          strconv.Itoa(2),
+						configuration.GangNodeUniformityLabelsAnnotation: "zone,rack",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                   "bar",
+						configuration.GangCardinalityAnnotation:          strconv.Itoa(2),
+						configuration.GangNodeUniformityLabelsAnnotation: "zone",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"NodeUniformityLabels and NodeUniformityLabel": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                   "bar",
+						configuration.GangCardinalityAnnotation:          strconv.Itoa(1),
+						configuration.GangNodeUniformityLabelAnnotation:  "zone",
+						configuration.GangNodeUniformityLabelsAnnotation: "zone,rack",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"failure domains": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                 "bar",
+						configuration.GangCardinalityAnnotation:        strconv.Itoa(2),
+						configuration.GangFailureDomainLabelAnnotation: "rack",
+						configuration.GangMinFailureDomainsAnnotation:  strconv.Itoa(2),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                 "bar",
+						configuration.GangCardinalityAnnotation:        strconv.Itoa(2),
+						configuration.GangFailureDomainLabelAnnotation: "rack",
+						configuration.GangMinFailureDomainsAnnotation:  strconv.Itoa(2),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          true,
+			ExpectedGangMinimumCardinalityByGangId: map[string]int{"bar": 2},
+		},
+		"inconsistent failure domains": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                 "bar",
+						configuration.GangCardinalityAnnotation:        strconv.Itoa(2),
+						configuration.GangFailureDomainLabelAnnotation: "rack",
+						configuration.GangMinFailureDomainsAnnotation:  strconv.Itoa(2),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                 "bar",
+						configuration.GangCardinalityAnnotation:        strconv.Itoa(2),
+						configuration.GangFailureDomainLabelAnnotation: "zone",
+						configuration.GangMinFailureDomainsAnnotation:  strconv.Itoa(2),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"failure domains exceed minimum cardinality": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                 "bar",
+						configuration.GangCardinalityAnnotation:        strconv.Itoa(2),
+						configuration.GangMinimumCardinalityAnnotation: strconv.Itoa(1),
+						configuration.GangFailureDomainLabelAnnotation: "rack",
+						configuration.GangMinFailureDomainsAnnotation:  strconv.Itoa(2),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                 "bar",
+						configuration.GangCardinalityAnnotation:        strconv.Itoa(2),
+						configuration.GangMinimumCardinalityAnnotation: strconv.Itoa(1),
+						configuration.GangFailureDomainLabelAnnotation: "rack",
+						configuration.GangMinFailureDomainsAnnotation:  strconv.Itoa(2),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"failure domain label without minimum": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                 "bar",
+						configuration.GangCardinalityAnnotation:        strconv.Itoa(1),
+						configuration.GangFailureDomainLabelAnnotation: "rack",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"spread label": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "bar",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(2),
+						configuration.GangSpreadLabelAnnotation: "rack",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "bar",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(2),
+						configuration.GangSpreadLabelAnnotation: "rack",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          true,
+			ExpectedGangMinimumCardinalityByGangId: map[string]int{"bar": 2},
+		},
+		"inconsistent spread label": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "bar",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(2),
+						configuration.GangSpreadLabelAnnotation: "rack",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "bar",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(2),
+						configuration.GangSpreadLabelAnnotation: "zone",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"inconsistent gang queue": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "bar",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(2),
+						configuration.GangQueueAnnotation:       "infra",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "bar",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(2),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"gang queue without gang": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangQueueAnnotation: "infra",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"inconsistent gang scheduling deadline": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                 "bar",
+						configuration.GangCardinalityAnnotation:        strconv.Itoa(2),
+						configuration.GangSchedulingDeadlineAnnotation: "1h",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                 "bar",
+						configuration.GangCardinalityAnnotation:        strconv.Itoa(2),
+						configuration.GangSchedulingDeadlineAnnotation: "2h",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"gang scheduling deadline without gang": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangSchedulingDeadlineAnnotation: "1h",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"spread label and NodeUniformityLabel": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                  "bar",
+						configuration.GangCardinalityAnnotation:         strconv.Itoa(2),
+						configuration.GangSpreadLabelAnnotation:         "rack",
+						configuration.GangNodeUniformityLabelAnnotation: "zone",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                  "bar",
+						configuration.GangCardinalityAnnotation:         strconv.Itoa(2),
+						configuration.GangSpreadLabelAnnotation:         "rack",
+						configuration.GangNodeUniformityLabelAnnotation: "zone",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"gang roles": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:              "bar",
+						configuration.GangCardinalityAnnotation:     strconv.Itoa(3),
+						configuration.GangRoleAnnotation:            "ps",
+						configuration.GangRoleCardinalityAnnotation: strconv.Itoa(1),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                     "bar",
+						configuration.GangCardinalityAnnotation:            strconv.Itoa(3),
+						configuration.GangRoleAnnotation:                   "worker",
+						configuration.GangRoleCardinalityAnnotation:        strconv.Itoa(2),
+						configuration.GangRoleMinimumCardinalityAnnotation: strconv.Itoa(1),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:                     "bar",
+						configuration.GangCardinalityAnnotation:            strconv.Itoa(3),
+						configuration.GangRoleAnnotation:                   "worker",
+						configuration.GangRoleCardinalityAnnotation:        strconv.Itoa(2),
+						configuration.GangRoleMinimumCardinalityAnnotation: strconv.Itoa(1),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          true,
+			ExpectedGangMinimumCardinalityByGangId: map[string]int{"bar": 3},
+		},
+		"inconsistent gang role cardinality": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:              "bar",
+						configuration.GangCardinalityAnnotation:     strconv.Itoa(3),
+						configuration.GangRoleAnnotation:            "ps",
+						configuration.GangRoleCardinalityAnnotation: strconv.Itoa(1),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:              "bar",
+						configuration.GangCardinalityAnnotation:     strconv.Itoa(3),
+						configuration.GangRoleAnnotation:            "worker",
+						configuration.GangRoleCardinalityAnnotation: strconv.Itoa(2),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:              "bar",
+						configuration.GangCardinalityAnnotation:     strconv.Itoa(3),
+						configuration.GangRoleAnnotation:            "worker",
+						configuration.GangRoleCardinalityAnnotation: strconv.Itoa(1),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"too many jobs with gang role": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:              "bar",
+						configuration.GangCardinalityAnnotation:     strconv.Itoa(3),
+						configuration.GangRoleAnnotation:            "ps",
+						configuration.GangRoleCardinalityAnnotation: strconv.Itoa(1),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:              "bar",
+						configuration.GangCardinalityAnnotation:     strconv.Itoa(3),
+						configuration.GangRoleAnnotation:            "ps",
+						configuration.GangRoleCardinalityAnnotation: strconv.Itoa(1),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"gang role cardinalities exceed gang cardinality": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:              "bar",
+						configuration.GangCardinalityAnnotation:     strconv.Itoa(3),
+						configuration.GangRoleAnnotation:            "ps",
+						configuration.GangRoleCardinalityAnnotation: strconv.Itoa(2),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:              "bar",
+						configuration.GangCardinalityAnnotation:     strconv.Itoa(3),
+						configuration.GangRoleAnnotation:            "worker",
+						configuration.GangRoleCardinalityAnnotation: strconv.Itoa(2),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"gang with and without roles": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:              "bar",
+						configuration.GangCardinalityAnnotation:     strconv.Itoa(3),
+						configuration.GangRoleAnnotation:            "ps",
+						configuration.GangRoleCardinalityAnnotation: strconv.Itoa(1),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "bar",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(3),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"inconsistent colocation group": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "bar",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(2),
+						configuration.ColocationGroupAnnotation: "cache",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "bar",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(2),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"gang leader": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "bar",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(2),
+						configuration.GangLeaderAnnotation:      "true",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "bar",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(2),
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          true,
+			ExpectedGangMinimumCardinalityByGangId: map[string]int{"bar": 2},
+		},
+		"multiple gang leaders": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "bar",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(2),
+						configuration.GangLeaderAnnotation:      "true",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "bar",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(2),
+						configuration.GangLeaderAnnotation:      "true",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"gang leader with gang id not valid as service name": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangIdAnnotation:          "Not_Valid",
+						configuration.GangCardinalityAnnotation: strconv.Itoa(1),
+						configuration.GangLeaderAnnotation:      "true",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
+		"gang leader outside of gang": {
+			Jobs: []*api.Job{
+				{
+					Annotations: map[string]string{
+						configuration.GangLeaderAnnotation: "true",
+					},
+					PodSpec: &v1.PodSpec{},
+				},
+			},
+			ExpectSuccess:                          false,
+			ExpectedGangMinimumCardinalityByGangId: nil,
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -398,3 +911,254 @@ func TestValidateGangs(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateOutputCapture(t *testing.T) {
+	tests := map[string]struct {
+		Annotations   map[string]string
+		ExpectSuccess bool
+	}{
+		"no output capture": {
+			Annotations:   nil,
+			ExpectSuccess: true,
+		},
+		"logs": {
+			Annotations:   map[string]string{configuration.CaptureOutputAnnotation: configuration.CaptureOutputLogs},
+			ExpectSuccess: true,
+		},
+		"absolute path": {
+			Annotations:   map[string]string{configuration.CaptureOutputAnnotation: "/tmp/output.json"},
+			ExpectSuccess: true,
+		},
+		"relative path": {
+			Annotations:   map[string]string{configuration.CaptureOutputAnnotation: "output.json"},
+			ExpectSuccess: false,
+		},
+		"empty": {
+			Annotations:   map[string]string{configuration.CaptureOutputAnnotation: ""},
+			ExpectSuccess: false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateOutputCapture(&api.Job{Annotations: tc.Annotations, PodSpec: &v1.PodSpec{}})
+			if tc.ExpectSuccess {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateSecretReferences(t *testing.T) {
+	tests := map[string]struct {
+		SecretReferences *string
+		ExpectSuccess    bool
+	}{
+		"no secret references": {
+			ExpectSuccess: true,
+		},
+		"single secret": {
+			SecretReferences: pointer.String("db-creds"),
+			ExpectSuccess:    true,
+		},
+		"multiple secrets": {
+			SecretReferences: pointer.String("db-creds, api-token"),
+			ExpectSuccess:    true,
+		},
+		"invalid secret name": {
+			SecretReferences: pointer.String("db_creds"),
+			ExpectSuccess:    false,
+		},
+		"empty secret name": {
+			SecretReferences: pointer.String("db-creds,,api-token"),
+			ExpectSuccess:    false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			job := &api.Job{PodSpec: &v1.PodSpec{}}
+			if tc.SecretReferences != nil {
+				job.Annotations = map[string]string{configuration.SecretReferencesAnnotation: *tc.SecretReferences}
+			}
+			err := validateSecretReferences(job)
+			if tc.ExpectSuccess {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateExpectedRuntime(t *testing.T) {
+	tests := map[string]struct {
+		Annotations   map[string]string
+		ExpectSuccess bool
+	}{
+		"no expected runtime": {
+			ExpectSuccess: true,
+		},
+		"valid duration": {
+			Annotations:   map[string]string{configuration.ExpectedRuntimeAnnotation: "1h30m"},
+			ExpectSuccess: true,
+		},
+		"invalid duration": {
+			Annotations:   map[string]string{configuration.ExpectedRuntimeAnnotation: "90"},
+			ExpectSuccess: false,
+		},
+		"negative duration": {
+			Annotations:   map[string]string{configuration.ExpectedRuntimeAnnotation: "-5m"},
+			ExpectSuccess: false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateExpectedRuntime(&api.Job{Annotations: tc.Annotations, PodSpec: &v1.PodSpec{}})
+			if tc.ExpectSuccess {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateSchedulingAnnotations(t *testing.T) {
+	tests := map[string]struct {
+		Annotations   map[string]string
+		ExpectSuccess bool
+	}{
+		"no limit": {
+			ExpectSuccess: true,
+		},
+		"valid limit": {
+			Annotations:   map[string]string{configuration.JobSetMaxRunningJobsAnnotation: "50"},
+			ExpectSuccess: true,
+		},
+		"zero": {
+			Annotations:   map[string]string{configuration.JobSetMaxRunningJobsAnnotation: "0"},
+			ExpectSuccess: true,
+		},
+		"negative": {
+			Annotations:   map[string]string{configuration.JobSetMaxRunningJobsAnnotation: "-1"},
+			ExpectSuccess: false,
+		},
+		"not an integer": {
+			Annotations:   map[string]string{configuration.JobSetMaxRunningJobsAnnotation: "fifty"},
+			ExpectSuccess: false,
+		},
+		"valid flags": {
+			Annotations: map[string]string{
+				configuration.NonPreemptibleAnnotation:         "true",
+				configuration.PreferSpotAnnotation:             "false",
+				configuration.GangNodeUniformitySoftAnnotation: "true",
+			},
+			ExpectSuccess: true,
+		},
+		"invalid non-preemptible flag": {
+			Annotations:   map[string]string{configuration.NonPreemptibleAnnotation: "yes"},
+			ExpectSuccess: false,
+		},
+		"invalid prefer spot flag": {
+			Annotations:   map[string]string{configuration.PreferSpotAnnotation: "True"},
+			ExpectSuccess: false,
+		},
+		"valid gang scheduling deadline": {
+			Annotations:   map[string]string{configuration.GangSchedulingDeadlineAnnotation: "90m"},
+			ExpectSuccess: true,
+		},
+		"non-positive gang scheduling deadline": {
+			Annotations:   map[string]string{configuration.GangSchedulingDeadlineAnnotation: "0s"},
+			ExpectSuccess: false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateSchedulingAnnotations(&api.Job{Annotations: tc.Annotations, PodSpec: &v1.PodSpec{}})
+			if tc.ExpectSuccess {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateScheduleNearJobs(t *testing.T) {
+	tests := map[string]struct {
+		Annotations   map[string]string
+		ExpectSuccess bool
+	}{
+		"no jobs": {
+			ExpectSuccess: true,
+		},
+		"valid job ids": {
+			Annotations:   map[string]string{configuration.ScheduleNearJobsAnnotation: "01f3j0g1md4qx7z5qb148qnh4r,01f3j0g1md4qx7z5qb148qnh4s"},
+			ExpectSuccess: true,
+		},
+		"empty": {
+			Annotations:   map[string]string{configuration.ScheduleNearJobsAnnotation: " , "},
+			ExpectSuccess: false,
+		},
+		"too many job ids": {
+			Annotations:   map[string]string{configuration.ScheduleNearJobsAnnotation: strings.Repeat("a,", maxScheduleNearJobs+1)},
+			ExpectSuccess: false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateScheduleNearJobs(&api.Job{Annotations: tc.Annotations, PodSpec: &v1.PodSpec{}})
+			if tc.ExpectSuccess {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateBindOnly(t *testing.T) {
+	tests := map[string]struct {
+		Annotations   map[string]string
+		ExpectSuccess bool
+	}{
+		"not bound": {
+			ExpectSuccess: true,
+		},
+		"bound": {
+			Annotations: map[string]string{
+				configuration.BindToExecutorAnnotation: "executor",
+				configuration.BindToNodeAnnotation:     "node",
+			},
+			ExpectSuccess: true,
+		},
+		"missing node": {
+			Annotations:   map[string]string{configuration.BindToExecutorAnnotation: "executor"},
+			ExpectSuccess: false,
+		},
+		"missing executor": {
+			Annotations:   map[string]string{configuration.BindToNodeAnnotation: "node"},
+			ExpectSuccess: false,
+		},
+		"gang job": {
+			Annotations: map[string]string{
+				configuration.BindToExecutorAnnotation:  "executor",
+				configuration.BindToNodeAnnotation:      "node",
+				configuration.GangIdAnnotation:          "gang",
+				configuration.GangCardinalityAnnotation: "2",
+			},
+			ExpectSuccess: false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateBindOnly(&api.Job{Annotations: tc.Annotations, PodSpec: &v1.PodSpec{}})
+			if tc.ExpectSuccess {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -55,6 +55,71 @@ func ValidatePodSpec(spec *v1.PodSpec, schedulingConfig *configuration.Schedulin
 	return validatePorts(spec)
 }
 
+// ValidatePodSpecQueuePolicy checks that the pod spec satisfies the pod policy of the queue it's submitted to, if any,
+// and that it uses a runtime class supported by the cluster.
+func ValidatePodSpecQueuePolicy(queue string, spec *v1.PodSpec, schedulingConfig *configuration.SchedulingConfig) error {
+	if spec == nil {
+		return errors.Errorf("empty pod spec")
+	}
+	if spec.RuntimeClassName != nil && len(schedulingConfig.RuntimeClassNodeSelectors) > 0 {
+		if _, ok := schedulingConfig.RuntimeClassNodeSelectors[*spec.RuntimeClassName]; !ok {
+			return errors.Errorf("runtime class %s is not supported", *spec.RuntimeClassName)
+		}
+	}
+	policy, ok := schedulingConfig.QueuePodPolicies[queue]
+	if !ok {
+		return nil
+	}
+	if policy.RuntimeClassName != "" {
+		if spec.RuntimeClassName == nil || *spec.RuntimeClassName != policy.RuntimeClassName {
+			return errors.Errorf("jobs submitted to queue %s must use runtime class %s", queue, policy.RuntimeClassName)
+		}
+	}
+	if policy.DisallowHostNamespaces && (spec.HostNetwork || spec.HostPID || spec.HostIPC) {
+		return errors.Errorf("jobs submitted to queue %s may not use host namespaces", queue)
+	}
+	containers := make([]v1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	containers = append(containers, spec.InitContainers...)
+	containers = append(containers, spec.Containers...)
+	for _, container := range containers {
+		if policy.RequireRunAsNonRoot && !runsAsNonRoot(spec.SecurityContext, container.SecurityContext) {
+			return errors.Errorf("container %s must run as non-root in queue %s", container.Name, queue)
+		}
+		if policy.DisallowPrivileged && container.SecurityContext != nil {
+			if container.SecurityContext.Privileged != nil && *container.SecurityContext.Privileged {
+				return errors.Errorf("container %s may not be privileged in queue %s", container.Name, queue)
+			}
+			if container.SecurityContext.AllowPrivilegeEscalation != nil && *container.SecurityContext.AllowPrivilegeEscalation {
+				return errors.Errorf("container %s may not allow privilege escalation in queue %s", container.Name, queue)
+			}
+		}
+	}
+	return nil
+}
+
+// runsAsNonRoot returns true if the container is guaranteed to run as non-root,
+// with settings of the container security context taking precedence over those of the pod.
+func runsAsNonRoot(podSecurityContext *v1.PodSecurityContext, containerSecurityContext *v1.SecurityContext) bool {
+	var runAsUser *int64
+	var runAsNonRoot *bool
+	if podSecurityContext != nil {
+		runAsUser = podSecurityContext.RunAsUser
+		runAsNonRoot = podSecurityContext.RunAsNonRoot
+	}
+	if containerSecurityContext != nil {
+		if containerSecurityContext.RunAsUser != nil {
+			runAsUser = containerSecurityContext.RunAsUser
+		}
+		if containerSecurityContext.RunAsNonRoot != nil {
+			runAsNonRoot = containerSecurityContext.RunAsNonRoot
+		}
+	}
+	if runAsUser != nil {
+		return *runAsUser != 0
+	}
+	return runAsNonRoot != nil && *runAsNonRoot
+}
+
 func validateTerminationGracePeriod(spec *v1.PodSpec, config *configuration.SchedulingConfig) error {
 	specHasTerminationGracePeriod := spec.TerminationGracePeriodSeconds != nil
 	var terminationGracePeriodSeconds int64
@@ -106,7 +171,7 @@ func validateAffinity(affinity *v1.Affinity) error {
 		return nil
 	}
 
-	err := validatePreferredNodeAffinityNotPresent(nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+	err := validatePreferredNodeAffinity(nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
 	if err != nil {
 		return err
 	}
@@ -114,9 +179,19 @@ func validateAffinity(affinity *v1.Affinity) error {
 	return validateRequiredNodeAffinity(nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
 }
 
-func validatePreferredNodeAffinityNotPresent(preferred []v1.PreferredSchedulingTerm) error {
-	if preferred != nil && len(preferred) > 0 {
-		return errors.New("PreferredDuringSchedulingIgnoredDuringExecution node affinity is not supported by Armada")
+// validatePreferredNodeAffinity checks that preferred node affinity terms are well-formed,
+// with weights in the range accepted by Kubernetes.
+func validatePreferredNodeAffinity(preferred []v1.PreferredSchedulingTerm) error {
+	for i, term := range preferred {
+		if term.Weight < 1 || term.Weight > 100 {
+			return errors.Errorf(
+				"invalid PreferredDuringSchedulingIgnoredDuringExecution node affinity: weight %d of term %d must be in [1, 100]",
+				term.Weight, i,
+			)
+		}
+	}
+	if _, err := nodeaffinity.NewPreferredSchedulingTerms(preferred); err != nil {
+		return errors.Errorf("invalid PreferredDuringSchedulingIgnoredDuringExecution node affinity: %v", err)
 	}
 	return nil
 }
@@ -124,7 +124,7 @@ func Test_ValidatePodSpec_checkForPortConfiguration(t *testing.T) {
 	assert.Error(t, ValidatePodSpec(portExposeOverMultipleContainers, schedulingConfig))
 }
 
-func Test_ValidatePodSpec_WhenPreferredAffinitySet_Fails(t *testing.T) {
+func Test_ValidatePodSpec_PreferredAffinity(t *testing.T) {
 	schedulingConfig := &configuration.SchedulingConfig{
 		MinJobResources:     v1.ResourceList{},
 		MaxPodSpecSizeBytes: 65535,
@@ -138,21 +138,48 @@ func Test_ValidatePodSpec_WhenPreferredAffinitySet_Fails(t *testing.T) {
 			},
 		},
 	}
-
-	terms := []v1.PreferredSchedulingTerm{
-		{
-			Weight:     5,
-			Preference: preference,
+	invalidPreference := v1.NodeSelectorTerm{
+		MatchExpressions: []v1.NodeSelectorRequirement{
+			{
+				Key:      "a",
+				Values:   []string{"b"},
+				Operator: v1.NodeSelectorOpExists,
+			},
 		},
 	}
-	podSpec := minimalValidPodSpec()
-	podSpec.Affinity = &v1.Affinity{
-		NodeAffinity: &v1.NodeAffinity{
-			PreferredDuringSchedulingIgnoredDuringExecution: terms,
+	tests := map[string]struct {
+		terms         []v1.PreferredSchedulingTerm
+		expectSuccess bool
+	}{
+		"valid": {
+			terms:         []v1.PreferredSchedulingTerm{{Weight: 5, Preference: preference}},
+			expectSuccess: true,
+		},
+		"weight too small": {
+			terms: []v1.PreferredSchedulingTerm{{Weight: 0, Preference: preference}},
+		},
+		"weight too large": {
+			terms: []v1.PreferredSchedulingTerm{{Weight: 101, Preference: preference}},
+		},
+		"invalid preference": {
+			terms: []v1.PreferredSchedulingTerm{{Weight: 5, Preference: invalidPreference}},
 		},
 	}
-
-	assert.Error(t, ValidatePodSpec(podSpec, schedulingConfig))
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			podSpec := minimalValidPodSpec()
+			podSpec.Affinity = &v1.Affinity{
+				NodeAffinity: &v1.NodeAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: tc.terms,
+				},
+			}
+			if tc.expectSuccess {
+				assert.NoError(t, ValidatePodSpec(podSpec, schedulingConfig))
+			} else {
+				assert.Error(t, ValidatePodSpec(podSpec, schedulingConfig))
+			}
+		})
+	}
 }
 
 func Test_ValidatePodSpec_WhenValidRequiredAffinitySet_Succeeds(t *testing.T) {
@@ -311,3 +338,143 @@ func Test_ValidatePodSpecPriorityClass(t *testing.T) {
 	)
 	validateInvalidArgumentErrorMessage(t, err, "Specified Priority Class is not supported in Server config")
 }
+
+func Test_ValidatePodSpecQueuePolicy(t *testing.T) {
+	schedulingConfig := &configuration.SchedulingConfig{
+		QueuePodPolicies: map[string]configuration.QueuePodPolicy{
+			"sandboxed": {
+				RuntimeClassName:       "gvisor",
+				RequireRunAsNonRoot:    true,
+				DisallowPrivileged:     true,
+				DisallowHostNamespaces: true,
+			},
+		},
+		RuntimeClassNodeSelectors: map[string]map[string]string{
+			"gvisor": {"sandbox.gke.io/runtime": "gvisor"},
+			"kata":   {"katacontainers.io/kata-runtime": "true"},
+		},
+	}
+	sandboxedPodSpec := func() *v1.PodSpec {
+		spec := minimalValidPodSpec()
+		spec.RuntimeClassName = pointer.String("gvisor")
+		spec.SecurityContext = &v1.PodSecurityContext{RunAsUser: pointer.Int64(1000)}
+		return spec
+	}
+	tests := map[string]struct {
+		queue         string
+		spec          *v1.PodSpec
+		expectSuccess bool
+	}{
+		"queue without policy": {
+			queue:         "other",
+			spec:          minimalValidPodSpec(),
+			expectSuccess: true,
+		},
+		"supported runtime class in queue without policy": {
+			queue: "other",
+			spec: func() *v1.PodSpec {
+				spec := minimalValidPodSpec()
+				spec.RuntimeClassName = pointer.String("kata")
+				return spec
+			}(),
+			expectSuccess: true,
+		},
+		"unsupported runtime class": {
+			queue: "other",
+			spec: func() *v1.PodSpec {
+				spec := minimalValidPodSpec()
+				spec.RuntimeClassName = pointer.String("runc")
+				return spec
+			}(),
+			expectSuccess: false,
+		},
+		"compliant": {
+			queue:         "sandboxed",
+			spec:          sandboxedPodSpec(),
+			expectSuccess: true,
+		},
+		"wrong runtime class": {
+			queue: "sandboxed",
+			spec: func() *v1.PodSpec {
+				spec := sandboxedPodSpec()
+				spec.RuntimeClassName = pointer.String("kata")
+				return spec
+			}(),
+			expectSuccess: false,
+		},
+		"missing runtime class": {
+			queue: "sandboxed",
+			spec: func() *v1.PodSpec {
+				spec := sandboxedPodSpec()
+				spec.RuntimeClassName = nil
+				return spec
+			}(),
+			expectSuccess: false,
+		},
+		"container overrides pod to run as root": {
+			queue: "sandboxed",
+			spec: func() *v1.PodSpec {
+				spec := sandboxedPodSpec()
+				spec.Containers[0].SecurityContext = &v1.SecurityContext{RunAsUser: pointer.Int64(0)}
+				return spec
+			}(),
+			expectSuccess: false,
+		},
+		"run as non-root set on container": {
+			queue: "sandboxed",
+			spec: func() *v1.PodSpec {
+				spec := sandboxedPodSpec()
+				spec.SecurityContext = nil
+				spec.Containers[0].SecurityContext = &v1.SecurityContext{RunAsNonRoot: pointer.Bool(true)}
+				return spec
+			}(),
+			expectSuccess: true,
+		},
+		"run as user not set": {
+			queue: "sandboxed",
+			spec: func() *v1.PodSpec {
+				spec := sandboxedPodSpec()
+				spec.SecurityContext = nil
+				return spec
+			}(),
+			expectSuccess: false,
+		},
+		"privileged": {
+			queue: "sandboxed",
+			spec: func() *v1.PodSpec {
+				spec := sandboxedPodSpec()
+				spec.Containers[0].SecurityContext = &v1.SecurityContext{Privileged: pointer.Bool(true)}
+				return spec
+			}(),
+			expectSuccess: false,
+		},
+		"privilege escalation": {
+			queue: "sandboxed",
+			spec: func() *v1.PodSpec {
+				spec := sandboxedPodSpec()
+				spec.Containers[0].SecurityContext = &v1.SecurityContext{AllowPrivilegeEscalation: pointer.Bool(true)}
+				return spec
+			}(),
+			expectSuccess: false,
+		},
+		"host network": {
+			queue: "sandboxed",
+			spec: func() *v1.PodSpec {
+				spec := sandboxedPodSpec()
+				spec.HostNetwork = true
+				return spec
+			}(),
+			expectSuccess: false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidatePodSpecQueuePolicy(tc.queue, tc.spec, schedulingConfig)
+			if tc.expectSuccess {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/go-redis/redis"
 
 	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/compress"
+	"github.com/armadaproject/armada/internal/common/ingest"
 )
 
 type EventIngesterConfiguration struct {
@@ -19,6 +21,9 @@ type EventIngesterConfiguration struct {
 	SubscriptionName string
 	// Size in bytes above which event message will be compressed when inserting in the database
 	MinMessageCompressionSize int
+	// Codec used to compress event messages when inserting in the database; one of zlib, zstd, lz4, or snappy.
+	// Readers detect the codec of each message, so this may be changed without migrating messages already stored.
+	MessageCompressionCodec compress.Codec
 	// Number of messages that will be batched together before being inserted into the database
 	BatchMessages int
 	// Size of messages that will be batched together before being inserted into the database
@@ -29,6 +34,8 @@ type EventIngesterConfiguration struct {
 	EventRetentionPolicy EventRetentionPolicy
 	// List of Regexes which will identify fatal errors when inserting into redis
 	FatalInsertionErrors []string
+	// Rules for dropping redundant events, e.g., repeated pending updates of flapping pods, before they're stored.
+	EventCompaction ingest.EventCompactionConfig
 	// If non-nil, net/http/pprof endpoints are exposed on localhost on this port.
 	PprofPort *uint16
 }
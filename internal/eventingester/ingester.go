@@ -57,7 +57,7 @@ func Run(config *configuration.EventIngesterConfiguration) {
 	eventDb := store.NewRedisEventStore(rc, config.EventRetentionPolicy, fatalRegexes, 100*time.Millisecond, 60*time.Second)
 
 	// Turn the messages into event rows
-	compressor, err := compress.NewZlibCompressor(config.MinMessageCompressionSize)
+	compressor, err := compress.NewCompressor(config.MessageCompressionCodec, config.MinMessageCompressionSize)
 	if err != nil {
 		log.Errorf("Error creating compressor for consumer")
 		panic(err)
@@ -75,6 +75,9 @@ func Run(config *configuration.EventIngesterConfiguration) {
 		config.Metrics,
 		metrics,
 	)
+	if err := ingester.EnableEventCompaction(config.EventCompaction); err != nil {
+		panic(errors.WithMessage(err, "Error configuring event compaction"))
+	}
 	if err := ingester.Run(app.CreateContextWithShutdown()); err != nil {
 		panic(errors.WithMessage(err, "Error running ingestion pipeline"))
 	}
@@ -15,6 +15,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/armadaproject/armada/internal/common/armadacontext"
 	"github.com/armadaproject/armada/internal/common/cluster"
@@ -31,9 +32,12 @@ import (
 	"github.com/armadaproject/armada/internal/executor/metrics/pod_metrics"
 	"github.com/armadaproject/armada/internal/executor/metrics/runstate"
 	"github.com/armadaproject/armada/internal/executor/node"
+	"github.com/armadaproject/armada/internal/executor/output"
 	"github.com/armadaproject/armada/internal/executor/podchecks"
 	"github.com/armadaproject/armada/internal/executor/reporter"
+	"github.com/armadaproject/armada/internal/executor/secrets"
 	"github.com/armadaproject/armada/internal/executor/service"
+	executor_util "github.com/armadaproject/armada/internal/executor/util"
 	"github.com/armadaproject/armada/internal/executor/utilisation"
 	"github.com/armadaproject/armada/pkg/api"
 	"github.com/armadaproject/armada/pkg/client"
@@ -56,6 +60,7 @@ func StartUp(ctx *armadacontext.Context, log *logrus.Entry, config configuration
 		log.Errorf("Failed to connect to kubernetes because %s", err)
 		os.Exit(-1)
 	}
+	config.Application.Capabilities = discoverCapabilities(ctx, log, kubernetesClientProvider.Client(), config.Application.Capabilities)
 
 	// Create an errgroup to run services in.
 	g, ctx := armadacontext.ErrGroup(ctx)
@@ -197,21 +202,31 @@ func setupExecutorApiComponents(
 		config.Kubernetes.MinimumResourcesMarkedAllocatedToNonArmadaPodsPerNode,
 		config.Kubernetes.MinimumResourcesMarkedAllocatedToNonArmadaPodsPerNodePriority,
 	)
+	if config.Kubernetes.ProbeNodeHardware {
+		clusterUtilisationService.SetHardwareProber(node.NewHardwareProber())
+	}
 
 	eventReporter, stopReporter := reporter.NewJobEventReporter(
 		clusterContext,
 		jobRunState,
-		eventSender)
+		eventSender,
+		newOutputCapturer(config.OutputCapture, clusterContext))
 
 	submitter := job.NewSubmitter(
 		clusterContext,
 		config.Kubernetes.PodDefaults,
 		config.Application.SubmitConcurrencyLimit,
 		config.Kubernetes.FatalPodSubmissionErrors,
+		newSecretProvider(config.Secrets, clusterContext),
 	)
 
 	leaseRequester := service.NewJobLeaseRequester(
 		executorApiClient, clusterContext, config.Kubernetes.MinimumJobSize)
+	leaseRequester.SetCapabilities(
+		config.Application.Capabilities.KubernetesVersion,
+		config.Application.Capabilities.RuntimeClasses,
+		config.Application.Capabilities.Features,
+	)
 	preemptRunProcessor := processors.NewRunPreemptedProcessor(clusterContext, jobRunState, eventReporter)
 	removeRunProcessor := processors.NewRemoveRunProcessor(clusterContext, jobRunState)
 
@@ -230,6 +245,7 @@ func setupExecutorApiComponents(
 		jobRunState,
 		submitter,
 		clusterHealthMonitor,
+		executor_util.NewPodRateLimiter(config.Application.PodCreationRateLimit, config.Application.PodCreationBurst),
 	)
 	podIssueService := service.NewIssueHandler(
 		jobRunState,
@@ -246,6 +262,14 @@ func setupExecutorApiComponents(
 	taskManager.Register(jobRequester.RequestJobsRuns, config.Task.AllocateSpareClusterCapacityInterval, "request_runs")
 	taskManager.Register(clusterAllocationService.AllocateSpareClusterCapacity, config.Task.AllocateSpareClusterCapacityInterval, "submit_runs")
 	taskManager.Register(eventReporter.ReportMissingJobEvents, config.Task.MissingJobEventReconciliationInterval, "event_reconciliation")
+	if config.Kubernetes.PodDefaults != nil && config.Kubernetes.PodDefaults.GangSchedulingGates {
+		schedulingGateReleaser := service.NewSchedulingGateReleaser(
+			clusterContext,
+			config.Kubernetes.NodeIdLabel,
+			config.Kubernetes.GangSchedulingGateTimeout,
+		)
+		taskManager.Register(schedulingGateReleaser.ReleaseGates, config.Task.SchedulingGateReleaseInterval, "scheduling_gate_release")
+	}
 	pod_metrics.ExposeClusterContextMetrics(clusterContext, clusterUtilisationService, podUtilisationService, nodeInfoService)
 	runStateMetricsCollector := runstate.NewJobRunStateStoreMetricsCollector(jobRunState)
 	prometheus.MustRegister(runStateMetricsCollector)
@@ -296,7 +320,8 @@ func setupServerApiComponents(
 	eventReporter, stopReporter := reporter.NewJobEventReporter(
 		clusterContext,
 		nil,
-		eventSender)
+		eventSender,
+		newOutputCapturer(config.OutputCapture, clusterContext))
 
 	jobContext := job.NewClusterJobContext(
 		clusterContext,
@@ -314,6 +339,9 @@ func setupServerApiComponents(
 		config.Kubernetes.MinimumResourcesMarkedAllocatedToNonArmadaPodsPerNode,
 		config.Kubernetes.MinimumResourcesMarkedAllocatedToNonArmadaPodsPerNodePriority,
 	)
+	if config.Kubernetes.ProbeNodeHardware {
+		clusterUtilisationService.SetHardwareProber(node.NewHardwareProber())
+	}
 
 	jobLeaseService := service.NewJobLeaseService(
 		clusterContext,
@@ -328,6 +356,7 @@ func setupServerApiComponents(
 		config.Kubernetes.PodDefaults,
 		config.Application.SubmitConcurrencyLimit,
 		config.Kubernetes.FatalPodSubmissionErrors,
+		newSecretProvider(config.Secrets, clusterContext),
 	)
 
 	clusterAllocationService := service.NewLegacyClusterAllocationService(
@@ -403,5 +432,81 @@ func validateConfig(config configuration.ExecutorConfiguration) error {
 	if config.Application.DeleteConcurrencyLimit <= 0 {
 		return fmt.Errorf("DeleteConcurrencyLimit was %d, must be greater or equal to 1", config.Application.DeleteConcurrencyLimit)
 	}
+	if config.Application.PodCreationRateLimit < 0 {
+		return fmt.Errorf("PodCreationRateLimit was %f, must be greater or equal to 0", config.Application.PodCreationRateLimit)
+	}
+	if config.Application.PodCreationRateLimit > 0 && config.Application.PodCreationBurst <= 0 {
+		return fmt.Errorf("PodCreationBurst was %d, must be greater or equal to 1 when PodCreationRateLimit is set", config.Application.PodCreationBurst)
+	}
+	if config.Application.PodDeletionRateLimit < 0 {
+		return fmt.Errorf("PodDeletionRateLimit was %f, must be greater or equal to 0", config.Application.PodDeletionRateLimit)
+	}
+	if config.Application.PodDeletionRateLimit > 0 && config.Application.PodDeletionBurst <= 0 {
+		return fmt.Errorf("PodDeletionBurst was %d, must be greater or equal to 1 when PodDeletionRateLimit is set", config.Application.PodDeletionBurst)
+	}
+	if config.Kubernetes.PodDefaults != nil {
+		for armadaPriorityClassName, priorityClassName := range config.Kubernetes.PodDefaults.PriorityClassNames {
+			if armadaPriorityClassName == "" {
+				return fmt.Errorf("PriorityClassNames contains an empty Armada priority class name")
+			}
+			if errs := validation.IsDNS1123Subdomain(priorityClassName); len(errs) > 0 {
+				return fmt.Errorf(
+					"PriorityClassNames maps %s to invalid Kubernetes priority class name %q: %s",
+					armadaPriorityClassName, priorityClassName, strings.Join(errs, ", "),
+				)
+			}
+		}
+	}
+	switch config.Secrets.Provider {
+	case "":
+	case configuration.KubernetesSecretsProvider:
+		if config.Secrets.Kubernetes.Namespace == "" {
+			return fmt.Errorf("Secrets.Kubernetes.Namespace must be set when using the %s secrets provider", config.Secrets.Provider)
+		}
+	case configuration.VaultSecretsProvider:
+		if config.Secrets.Vault.Address == "" || config.Secrets.Vault.MountPath == "" {
+			return fmt.Errorf("Secrets.Vault.Address and Secrets.Vault.MountPath must be set when using the %s secrets provider", config.Secrets.Provider)
+		}
+	default:
+		return fmt.Errorf("Secrets.Provider was %s, must be one of %s or %s", config.Secrets.Provider, configuration.KubernetesSecretsProvider, configuration.VaultSecretsProvider)
+	}
+	if config.OutputCapture.Enabled {
+		if config.OutputCapture.Endpoint == "" || config.OutputCapture.Bucket == "" {
+			return fmt.Errorf("OutputCapture is enabled but its endpoint or bucket is not set")
+		}
+		if config.OutputCapture.MaxLogBytes < 0 {
+			return fmt.Errorf("OutputCapture.MaxLogBytes was %d, must be greater or equal to 0", config.OutputCapture.MaxLogBytes)
+		}
+	}
 	return nil
 }
+
+// newSecretProvider returns the provider used to resolve secrets referenced by jobs,
+// or nil if no provider is configured.
+func newSecretProvider(config configuration.SecretsConfiguration, clusterContext executor_context.ClusterContext) secrets.Provider {
+	switch config.Provider {
+	case configuration.KubernetesSecretsProvider:
+		return secrets.NewKubernetesProvider(clusterContext, config.Kubernetes.Namespace)
+	case configuration.VaultSecretsProvider:
+		return secrets.NewVaultProvider(config.Vault.Address, config.Vault.Token, config.Vault.MountPath, config.Vault.Timeout)
+	default:
+		return nil
+	}
+}
+
+// newOutputCapturer returns the capturer used to upload the output of succeeded jobs,
+// or nil if output capture is disabled.
+func newOutputCapturer(config configuration.OutputCaptureConfiguration, clusterContext executor_context.ClusterContext) *output.Capturer {
+	if !config.Enabled {
+		return nil
+	}
+	uploader := output.NewS3Uploader(
+		config.Endpoint,
+		config.Region,
+		config.Bucket,
+		config.AccessKeyId,
+		config.SecretAccessKey,
+		config.UploadTimeout,
+	)
+	return output.NewCapturer(uploader, clusterContext, config.KeyPrefix, config.MaxLogBytes)
+}
@@ -74,6 +74,43 @@ func Test_ValidateConfig_When_DeleteThreadCount_LessThanOrEqualToZero(t *testing
 	assert.Error(t, validateConfig(config))
 }
 
+func Test_ValidateConfig_PodRateLimits(t *testing.T) {
+	config := createBasicValidExecutorConfiguration()
+
+	config.Application.PodCreationRateLimit = 10
+	config.Application.PodCreationBurst = 100
+	config.Application.PodDeletionRateLimit = 5
+	config.Application.PodDeletionBurst = 50
+	assert.NoError(t, validateConfig(config))
+
+	config.Application.PodCreationBurst = 0
+	assert.Error(t, validateConfig(config))
+	config.Application.PodCreationRateLimit = 0
+	assert.NoError(t, validateConfig(config))
+	config.Application.PodCreationRateLimit = -1
+	assert.Error(t, validateConfig(config))
+	config.Application.PodCreationRateLimit = 0
+
+	config.Application.PodDeletionBurst = 0
+	assert.Error(t, validateConfig(config))
+	config.Application.PodDeletionRateLimit = -1
+	assert.Error(t, validateConfig(config))
+}
+
+func Test_ValidateConfig_PriorityClassNames(t *testing.T) {
+	config := createBasicValidExecutorConfiguration()
+
+	config.Kubernetes.PodDefaults = &configuration.PodDefaults{PriorityClassNames: map[string]string{"armada-preemptible": "preemptible"}}
+	assert.NoError(t, validateConfig(config))
+
+	config.Kubernetes.PodDefaults = &configuration.PodDefaults{PriorityClassNames: map[string]string{"": "preemptible"}}
+	assert.Error(t, validateConfig(config))
+	config.Kubernetes.PodDefaults = &configuration.PodDefaults{PriorityClassNames: map[string]string{"armada-preemptible": ""}}
+	assert.Error(t, validateConfig(config))
+	config.Kubernetes.PodDefaults = &configuration.PodDefaults{PriorityClassNames: map[string]string{"armada-preemptible": "Not_Valid"}}
+	assert.Error(t, validateConfig(config))
+}
+
 func createBasicValidExecutorConfiguration() configuration.ExecutorConfiguration {
 	return configuration.ExecutorConfiguration{
 		Application: configuration.ApplicationConfiguration{
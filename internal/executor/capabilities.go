@@ -0,0 +1,41 @@
+package executor
+
+import (
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/executor/configuration"
+)
+
+// discoverCapabilities fills in any capabilities not provided via config by querying the cluster.
+// Capabilities that can't be discovered are left empty, such that they're not reported to the scheduler.
+func discoverCapabilities(
+	ctx *armadacontext.Context,
+	log *logrus.Entry,
+	client kubernetes.Interface,
+	capabilities configuration.CapabilitiesConfiguration,
+) configuration.CapabilitiesConfiguration {
+	if capabilities.KubernetesVersion == "" {
+		if info, err := client.Discovery().ServerVersion(); err != nil {
+			log.WithError(err).Warn("failed to discover Kubernetes version; it won't be reported to the scheduler")
+		} else {
+			capabilities.KubernetesVersion = info.GitVersion
+		}
+	}
+	if len(capabilities.RuntimeClasses) == 0 {
+		if runtimeClasses, err := client.NodeV1().RuntimeClasses().List(ctx, metav1.ListOptions{}); err != nil {
+			log.WithError(err).Warn("failed to discover runtime classes; they won't be reported to the scheduler")
+		} else {
+			for _, runtimeClass := range runtimeClasses.Items {
+				capabilities.RuntimeClasses = append(capabilities.RuntimeClasses, runtimeClass.Name)
+			}
+		}
+	}
+	log.Infof(
+		"reporting Kubernetes version %q, runtime classes %v, and features %v to the scheduler",
+		capabilities.KubernetesVersion, capabilities.RuntimeClasses, capabilities.Features,
+	)
+	return capabilities
+}
@@ -11,6 +11,9 @@ type Action string
 const (
 	ActionFail  Action = "Fail"
 	ActionRetry Action = "Retry"
+	// ActionReturnLease returns the lease without counting the run as an attempt of the job,
+	// such that it may be scheduled onto the same node again.
+	ActionReturnLease Action = "ReturnLease"
 )
 
 type ContainerState string
@@ -25,9 +28,23 @@ const (
 	EventTypeNormal = EventType(v1.EventTypeNormal)
 )
 
+// StuckPodCause is the cause a pod is stuck pending, as classified by the executor.
+type StuckPodCause string
+
+const (
+	// The pod's volumes can't be attached or mounted, as indicated by FailedAttachVolume, FailedMount, or FailedMapVolume events.
+	StuckPodCauseVolumeAttach StuckPodCause = "VolumeAttach"
+	// The images of the pod's containers can't be pulled.
+	StuckPodCauseImagePull StuckPodCause = "ImagePull"
+	// The pod has scheduling gates that haven't been removed.
+	StuckPodCauseSchedulingGate StuckPodCause = "SchedulingGate"
+)
+
 type Checks struct {
-	Events                    []EventCheck
-	ContainerStatuses         []ContainerStatusCheck
+	Events            []EventCheck
+	ContainerStatuses []ContainerStatusCheck
+	// Checks applied to pods stuck pending for a known cause. These take precedence over all other checks.
+	StuckPods                 []StuckPodCheck
 	DeadlineForUpdates        time.Duration
 	DeadlineForNodeAssignment time.Duration
 }
@@ -47,3 +64,10 @@ type ContainerStatusCheck struct {
 	GracePeriod  time.Duration
 	Action       Action
 }
+
+// StuckPodCheck applies Action to pods that have been stuck pending due to Cause for longer than GracePeriod.
+type StuckPodCheck struct {
+	Cause       StuckPodCause
+	GracePeriod time.Duration
+	Action      Action
+}
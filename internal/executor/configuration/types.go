@@ -22,11 +22,41 @@ type ApplicationConfiguration struct {
 	// MaxLeasedJobs is the maximum jobs the executor should have in Leased state ay any one time (i.e jobs not submitted to kubernetes)
 	// It is largely used to calculate how many new jobs to request from the scheduler
 	MaxLeasedJobs int
+	// Maximum number of pods created per second, and the maximum number of pods created in a burst.
+	// Leased jobs not submitted because of this limit stay leased until the limit allows submitting them.
+	// Since no new jobs are requested while any jobs are leased, this also paces how quickly jobs are leased to this executor.
+	// A limit of zero means pod creation isn't rate limited.
+	PodCreationRateLimit float64
+	PodCreationBurst     int
+	// Maximum number of pods deleted per second, and the maximum number of pods deleted in a burst.
+	// Pods of preempted runs are deleted before any other pods.
+	// A limit of zero means pod deletion isn't rate limited.
+	PodDeletionRateLimit float64
+	PodDeletionBurst     int
+	// Capabilities reported to the scheduler, which only schedules jobs onto this cluster that it can run.
+	Capabilities CapabilitiesConfiguration
+}
+
+type CapabilitiesConfiguration struct {
+	// Optional features supported by this executor and its cluster, e.g., "in-place-resize".
+	Features []string
+	// Runtime classes available in the cluster. If empty, the runtime classes of the cluster are discovered at startup.
+	RuntimeClasses []string
+	// Kubernetes version of the cluster, e.g., "v1.27.3". If empty, the version of the cluster is discovered at startup.
+	KubernetesVersion string
 }
 
 type PodDefaults struct {
 	SchedulerName string
 	Ingress       *IngressConfiguration
+	// Map from Armada priority class names to the names of the Kubernetes PriorityClasses to set on pods
+	// of jobs with that priority class, such that kubelet evicts pods in an order consistent with Armada preemption.
+	// Pods with a priority class not in this map keep the priority class name they were submitted with.
+	PriorityClassNames map[string]string
+	// If true, pods of gang jobs are created with a Kubernetes scheduling gate, which is removed only once the pods of
+	// all members of the gang exist and the nodes they're assigned to are ready, such that no member of a gang starts
+	// unless all of them can. Requires Kubernetes 1.27 or later.
+	GangSchedulingGates bool
 }
 
 type StateChecksConfiguration struct {
@@ -80,6 +110,13 @@ type KubernetesConfiguration struct {
 	// MinimumResourcesMarkedAllocatedToNonArmadaPodsPerNode, those resources are marked allocated at this priority.
 	MinimumResourcesMarkedAllocatedToNonArmadaPodsPerNodePriority int32
 	PodKillTimeout                                                time.Duration
+	// Gangs with a minimum cardinality lower than their cardinality may be leased only in part.
+	// The scheduling gates of such gangs are removed once at least the minimum cardinality of members exist
+	// and no further member has been created for this long.
+	GangSchedulingGateTimeout time.Duration
+	// If true, the hardware attributes of each node, e.g., its GPU model and CPU generation, are derived from the labels
+	// published by hardware discovery daemonsets and reported as labels prefixed with hardware.armadaproject.io/.
+	ProbeNodeHardware bool
 }
 
 type EtcdConfiguration struct {
@@ -116,6 +153,54 @@ type EtcdClusterHealthMonitoringConfiguration struct {
 	ScrapeDelayBucketsCount  int     `validate:"gt=0"`
 }
 
+// OutputCaptureConfiguration controls uploading the output of succeeded jobs that request it
+// to an S3-compatible object store, e.g., AWS S3 or GCS via its XML interoperability API.
+type OutputCaptureConfiguration struct {
+	// If false, output capture requested by jobs is ignored.
+	Enabled bool
+	// Base URL of the object store, e.g., https://s3.eu-west-2.amazonaws.com or https://storage.googleapis.com.
+	// Objects are addressed path-style, i.e., as <Endpoint>/<Bucket>/<key>, which is also the URL recorded on the job succeeded event.
+	Endpoint string
+	// Region used when signing requests, e.g., eu-west-2, or auto for GCS.
+	Region    string
+	Bucket    string
+	KeyPrefix string
+	// HMAC credentials used to sign requests. If AccessKeyId is empty, requests are sent unsigned.
+	AccessKeyId     string
+	SecretAccessKey string
+	// Maximum number of bytes of logs captured per job. Zero means no limit.
+	MaxLogBytes int64
+	// Timeout for each upload.
+	UploadTimeout time.Duration
+}
+
+const (
+	KubernetesSecretsProvider = "kubernetes"
+	VaultSecretsProvider      = "vault"
+)
+
+// SecretsConfiguration controls how the executor resolves the secrets jobs reference via the armadaproject.io/secretRefs annotation.
+type SecretsConfiguration struct {
+	// Provider to resolve secrets from; one of "kubernetes" or "vault".
+	// If empty, jobs referencing secrets fail to start.
+	Provider   string
+	Kubernetes KubernetesSecretsConfiguration
+	Vault      VaultSecretsConfiguration
+}
+
+type KubernetesSecretsConfiguration struct {
+	// Namespace to read secrets from. Only secrets labelled with armada_queue_id=<queue> are visible to jobs of that queue.
+	Namespace string
+}
+
+type VaultSecretsConfiguration struct {
+	Address string
+	Token   string
+	// Mount path of the KV version 2 secrets engine. Secrets are read from <MountPath>/data/<queue>/<name>.
+	MountPath string
+	Timeout   time.Duration
+}
+
 type TaskConfiguration struct {
 	UtilisationReportingInterval          time.Duration
 	MissingJobEventReconciliationInterval time.Duration
@@ -128,6 +213,7 @@ type TaskConfiguration struct {
 	UtilisationEventReportingInterval     time.Duration
 	ResourceCleanupInterval               time.Duration
 	StateProcessorInterval                time.Duration
+	SchedulingGateReleaseInterval         time.Duration
 }
 
 type MetricConfiguration struct {
@@ -169,6 +255,8 @@ type ExecutorConfiguration struct {
 	Client                ClientConfiguration
 	GRPC                  keepalive.ClientParameters
 
-	Kubernetes KubernetesConfiguration
-	Task       TaskConfiguration
+	Kubernetes    KubernetesConfiguration
+	Task          TaskConfiguration
+	OutputCapture OutputCaptureConfiguration
+	Secrets       SecretsConfiguration
 }
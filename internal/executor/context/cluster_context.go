@@ -3,10 +3,12 @@ package context
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 	v1 "k8s.io/api/core/v1"
 	discovery "k8s.io/api/discovery/v1"
 	networking "k8s.io/api/networking/v1"
@@ -52,19 +54,25 @@ type ClusterContext interface {
 	GetNode(nodeName string) (*v1.Node, error)
 	GetNodeStatsSummary(*armadacontext.Context, *v1.Node) (*v1alpha1.Summary, error)
 	GetPodEvents(pod *v1.Pod) ([]*v1.Event, error)
+	GetPodLogs(pod *v1.Pod, containerName string, limitBytes int64) ([]byte, error)
 	GetServices(pod *v1.Pod) ([]*v1.Service, error)
 	GetIngresses(pod *v1.Pod) ([]*networking.Ingress, error)
 	GetEndpointSlices(namespace string, labelName string, labelValue string) ([]*discovery.EndpointSlice, error)
+	GetSecret(namespace string, name string) (*v1.Secret, error)
 
 	SubmitPod(pod *v1.Pod, owner string, ownerGroups []string) (*v1.Pod, error)
 	SubmitService(service *v1.Service) (*v1.Service, error)
 	SubmitIngress(ingress *networking.Ingress) (*networking.Ingress, error)
+	SubmitSecret(secret *v1.Secret) (*v1.Secret, error)
 	DeletePodWithCondition(pod *v1.Pod, condition func(pod *v1.Pod) bool, pessimistic bool) error
 	DeletePods(pods []*v1.Pod)
 	DeleteService(service *v1.Service) error
 	DeleteIngress(ingress *networking.Ingress) error
 
 	AddAnnotation(pod *v1.Pod, annotations map[string]string) error
+	// ReleaseSchedulingGates removes the gang scheduling gate from a pod created with it,
+	// after which Kubernetes may bind the pod to a node.
+	ReleaseSchedulingGates(pod *v1.Pod) error
 	AddClusterEventAnnotation(event *v1.Event, annotations map[string]string) error
 
 	Stop()
@@ -87,6 +95,8 @@ type KubernetesClusterContext struct {
 	eventInformer            informer.EventInformer
 	podKillTimeout           time.Duration
 	clock                    clock.Clock
+	// Limits the rate at which pods are deleted. May be nil, in which case pod deletion isn't rate limited.
+	podDeletionLimiter *rate.Limiter
 }
 
 func (c *KubernetesClusterContext) GetClusterId() string {
@@ -124,6 +134,7 @@ func NewClusterContext(
 		kubernetesClientProvider: kubernetesClientProvider,
 		podKillTimeout:           killTimeout,
 		clock:                    clock.RealClock{},
+		podDeletionLimiter:       util.NewPodRateLimiter(configuration.PodDeletionRateLimit, configuration.PodDeletionBurst),
 	}
 
 	context.AddPodEventHandler(cache.ResourceEventHandlerFuncs{
@@ -215,6 +226,14 @@ func (c *KubernetesClusterContext) GetPodEvents(pod *v1.Pod) ([]*v1.Event, error
 	return eventsTyped, nil
 }
 
+func (c *KubernetesClusterContext) GetPodLogs(pod *v1.Pod, containerName string, limitBytes int64) ([]byte, error) {
+	options := &v1.PodLogOptions{Container: containerName}
+	if limitBytes > 0 {
+		options.LimitBytes = &limitBytes
+	}
+	return c.kubernetesClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).DoRaw(armadacontext.Background())
+}
+
 func (c *KubernetesClusterContext) GetNodes() ([]*v1.Node, error) {
 	return c.nodeInformer.Lister().List(labels.Everything())
 }
@@ -253,13 +272,56 @@ func (c *KubernetesClusterContext) SubmitPod(pod *v1.Pod, owner string, ownerGro
 		return nil, err
 	}
 
-	returnedPod, err := ownerClient.CoreV1().Pods(pod.Namespace).Create(armadacontext.Background(), pod, metav1.CreateOptions{})
+	var returnedPod *v1.Pod
+	if util.IsSchedulingGated(pod) {
+		returnedPod, err = createGatedPod(ownerClient, pod)
+	} else {
+		returnedPod, err = ownerClient.CoreV1().Pods(pod.Namespace).Create(armadacontext.Background(), pod, metav1.CreateOptions{})
+	}
 	if err != nil {
 		c.submittedPods.Delete(util.ExtractPodKey(pod))
 	}
 	return returnedPod, err
 }
 
+// createGatedPod creates the pod with the gang scheduling gate set.
+// The Kubernetes API types used by the executor predate scheduling gates, so the gate is added to the serialised pod.
+func createGatedPod(client kubernetes.Interface, pod *v1.Pod) (*v1.Pod, error) {
+	podBytes, err := json.Marshal(pod)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var object map[string]any
+	if err := json.Unmarshal(podBytes, &object); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	spec, ok := object["spec"].(map[string]any)
+	if !ok {
+		return nil, errors.Errorf("pod %s has no spec", pod.Name)
+	}
+	spec["schedulingGates"] = []map[string]string{{"name": domain.GangSchedulingGate}}
+	object["apiVersion"] = "v1"
+	object["kind"] = "Pod"
+	body, err := json.Marshal(object)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	returnedPod := &v1.Pod{}
+	err = client.CoreV1().RESTClient().
+		Post().
+		Namespace(pod.Namespace).
+		Resource("pods").
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		Do(armadacontext.Background()).
+		Into(returnedPod)
+	if err != nil {
+		return nil, err
+	}
+	return returnedPod, nil
+}
+
 func (c *KubernetesClusterContext) SubmitService(service *v1.Service) (*v1.Service, error) {
 	return c.kubernetesClient.CoreV1().Services(service.Namespace).Create(armadacontext.Background(), service, metav1.CreateOptions{})
 }
@@ -268,6 +330,16 @@ func (c *KubernetesClusterContext) SubmitIngress(ingress *networking.Ingress) (*
 	return c.kubernetesClient.NetworkingV1().Ingresses(ingress.Namespace).Create(armadacontext.Background(), ingress, metav1.CreateOptions{})
 }
 
+func (c *KubernetesClusterContext) SubmitSecret(secret *v1.Secret) (*v1.Secret, error) {
+	return c.kubernetesClient.CoreV1().Secrets(secret.Namespace).Create(armadacontext.Background(), secret, metav1.CreateOptions{})
+}
+
+// GetSecret reads the secret directly from the api server, as opposed to from an informer,
+// such that the executor doesn't keep copies of all secrets in the cluster in memory.
+func (c *KubernetesClusterContext) GetSecret(namespace string, name string) (*v1.Secret, error) {
+	return c.kubernetesClient.CoreV1().Secrets(namespace).Get(armadacontext.Background(), name, metav1.GetOptions{})
+}
+
 func (c *KubernetesClusterContext) AddAnnotation(pod *v1.Pod, annotations map[string]string) error {
 	patch := &domain.Patch{
 		MetaData: metav1.ObjectMeta{
@@ -287,6 +359,21 @@ func (c *KubernetesClusterContext) AddAnnotation(pod *v1.Pod, annotations map[st
 	return nil
 }
 
+func (c *KubernetesClusterContext) ReleaseSchedulingGates(pod *v1.Pod) error {
+	patch := []map[string]string{
+		{"op": "remove", "path": "/spec/schedulingGates"},
+		{"op": "remove", "path": "/metadata/annotations/" + domain.SchedulingGated},
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = c.kubernetesClient.CoreV1().
+		Pods(pod.Namespace).
+		Patch(armadacontext.Background(), pod.Name, types.JSONPatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
+
 func (c *KubernetesClusterContext) AddClusterEventAnnotation(event *v1.Event, annotations map[string]string) error {
 	patch := &domain.Patch{
 		MetaData: metav1.ObjectMeta{
@@ -386,29 +473,48 @@ func (c *KubernetesClusterContext) DeleteIngress(ingress *networking.Ingress) er
 
 func (c *KubernetesClusterContext) ProcessPodsToDelete() {
 	pods := c.podsToDelete.GetAll()
-	util.ProcessItemsWithThreadPool(armadacontext.Background(), c.deleteThreadCount, pods, func(podToDelete *v1.Pod) {
+	podsToDelete := make([]*v1.Pod, 0, len(pods))
+	for _, podToDelete := range pods {
 		if podToDelete == nil {
-			return
+			continue
 		}
+		if podToDelete.DeletionTimestamp != nil && !c.isPastKillTime(podToDelete) {
+			log.Debugf("Asked to delete pod %s/%s but this pod is already being deleted", podToDelete.Namespace, podToDelete.Name)
+			continue
+		}
+		podsToDelete = append(podsToDelete, podToDelete)
+	}
+
+	// Pods not deleted due to the rate limit remain in podsToDelete and are deleted in a later round.
+	// Deleting preempted pods frees up resources for the jobs preempting them, so these are deleted first.
+	sort.SliceStable(podsToDelete, func(i, j int) bool {
+		return util.IsReportedPreempted(podsToDelete[i]) && !util.IsReportedPreempted(podsToDelete[j])
+	})
+	numToDelete := util.TakeTokens(c.podDeletionLimiter, len(podsToDelete))
+	if numToDelete < len(podsToDelete) {
+		log.Infof("Pod deletion rate limit reached; deferring deletion of %d of %d pods", len(podsToDelete)-numToDelete, len(podsToDelete))
+		podsToDelete = podsToDelete[:numToDelete]
+	}
+
+	util.ProcessItemsWithThreadPool(armadacontext.Background(), c.deleteThreadCount, podsToDelete, func(podToDelete *v1.Pod) {
 		if podToDelete.DeletionTimestamp == nil {
 			// We've never tried to delete this pod before.  Delete using the grace period
 			c.doDelete(podToDelete, false)
 		} else {
-			// we've tried to delete this pod before. If we're after the kill period then force delete
-			// else it's a no-op
-			killTime := podToDelete.DeletionTimestamp.
-				Add(util.GetDeletionGracePeriodOrDefault(podToDelete)).
-				Add(c.podKillTimeout)
-			if c.clock.Now().After(killTime) {
-				log.Infof("Pod %s/%s was requested deleted at %s, but is still present.  Force killing.", podToDelete.Namespace, podToDelete.Name, podToDelete.DeletionTimestamp)
-				c.doDelete(podToDelete, true)
-			} else {
-				log.Debugf("Asked to delete pod %s/%s but this pod is already being deleted", podToDelete.Namespace, podToDelete.Name)
-			}
+			// We've tried to delete this pod before and we're after the kill period, so force delete
+			log.Infof("Pod %s/%s was requested deleted at %s, but is still present.  Force killing.", podToDelete.Namespace, podToDelete.Name, podToDelete.DeletionTimestamp)
+			c.doDelete(podToDelete, true)
 		}
 	})
 }
 
+func (c *KubernetesClusterContext) isPastKillTime(pod *v1.Pod) bool {
+	killTime := pod.DeletionTimestamp.
+		Add(util.GetDeletionGracePeriodOrDefault(pod)).
+		Add(c.podKillTimeout)
+	return c.clock.Now().After(killTime)
+}
+
 func (c *KubernetesClusterContext) doDelete(pod *v1.Pod, force bool) {
 	podId := util.ExtractPodKey(pod)
 	var err error
@@ -8,6 +8,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
 	v1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
 	errors2 "k8s.io/apimachinery/pkg/api/errors"
@@ -544,6 +545,36 @@ func TestKubernetesClusterContext_ProcessPodsToDelete_AllowsRepeatedDeleteCallTo
 	assert.Equal(t, len(client.Fake.Actions()), 2)
 }
 
+func TestKubernetesClusterContext_ProcessPodsToDelete_RespectsRateLimit_PrioritisingPreemptedPods(t *testing.T) {
+	clusterContext, client := setupTest()
+	clusterContext.podDeletionLimiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+
+	pod := createSubmittedBatchPod(t, clusterContext)
+	preemptedPod := createSubmittedBatchPod(t, clusterContext)
+	preemptedPod.Annotations = map[string]string{domain.JobPreemptedAnnotation: "now"}
+
+	client.Fake.ClearActions()
+	clusterContext.DeletePods([]*v1.Pod{pod, preemptedPod})
+	clusterContext.ProcessPodsToDelete()
+
+	assert.Equal(t, len(client.Fake.Actions()), 2)
+	deleteAction, ok := client.Fake.Actions()[1].(clientTesting.DeleteAction)
+	assert.True(t, ok)
+	assert.Equal(t, deleteAction.GetName(), preemptedPod.Name)
+
+	// The limit is reached, so the other pod isn't deleted until more deletions are allowed.
+	client.Fake.ClearActions()
+	clusterContext.ProcessPodsToDelete()
+	assert.Equal(t, len(client.Fake.Actions()), 0)
+
+	clusterContext.podDeletionLimiter = nil
+	clusterContext.ProcessPodsToDelete()
+	assert.Equal(t, len(client.Fake.Actions()), 2)
+	deleteAction, ok = client.Fake.Actions()[1].(clientTesting.DeleteAction)
+	assert.True(t, ok)
+	assert.Equal(t, deleteAction.GetName(), pod.Name)
+}
+
 func TestKubernetesClusterContext_AddAnnotation(t *testing.T) {
 	clusterContext, _ := setupTest()
 	pod := createSubmittedBatchPod(t, clusterContext)
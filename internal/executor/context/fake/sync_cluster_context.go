@@ -17,6 +17,7 @@ import (
 
 type SyncFakeClusterContext struct {
 	Pods                 map[string]*v1.Pod
+	Nodes                []*v1.Node
 	AnnotationsAdded     map[string]map[string]string
 	podEventHandlers     []*cache.ResourceEventHandlerFuncs
 	clusterEventHandlers []*cache.ResourceEventHandlerFuncs
@@ -54,7 +55,8 @@ func (c *SyncFakeClusterContext) GetActiveBatchPods() ([]*v1.Pod, error) {
 }
 
 func (c *SyncFakeClusterContext) GetNodes() ([]*v1.Node, error) {
-	return make([]*v1.Node, 0), nil
+	nodes := make([]*v1.Node, 0, len(c.Nodes))
+	return append(nodes, c.Nodes...), nil
 }
 
 func (c *SyncFakeClusterContext) GetNode(nodeName string) (*v1.Node, error) {
@@ -65,6 +67,10 @@ func (c *SyncFakeClusterContext) GetPodEvents(pod *v1.Pod) ([]*v1.Event, error)
 	return []*v1.Event{}, nil
 }
 
+func (c *SyncFakeClusterContext) GetPodLogs(pod *v1.Pod, containerName string, limitBytes int64) ([]byte, error) {
+	return []byte{}, nil
+}
+
 func (c *SyncFakeClusterContext) SubmitService(service *v1.Service) (*v1.Service, error) {
 	return nil, fmt.Errorf("Services not implemented in SyncFakeClusterContext")
 }
@@ -81,6 +87,14 @@ func (c *SyncFakeClusterContext) SubmitIngress(ingress *networking.Ingress) (*ne
 	return nil, fmt.Errorf("Ingresses not implemented in SyncFakeClusterContext")
 }
 
+func (c *SyncFakeClusterContext) SubmitSecret(secret *v1.Secret) (*v1.Secret, error) {
+	return nil, fmt.Errorf("Secrets not implemented in SyncFakeClusterContext")
+}
+
+func (c *SyncFakeClusterContext) GetSecret(namespace string, name string) (*v1.Secret, error) {
+	return nil, fmt.Errorf("Secrets not implemented in SyncFakeClusterContext")
+}
+
 func (c *SyncFakeClusterContext) GetIngresses(pod *v1.Pod) ([]*networking.Ingress, error) {
 	return nil, fmt.Errorf("Ingresses not implemented in SyncFakeClusterContext")
 }
@@ -107,6 +121,15 @@ func (c *SyncFakeClusterContext) AddAnnotation(pod *v1.Pod, annotations map[stri
 	return nil
 }
 
+func (c *SyncFakeClusterContext) ReleaseSchedulingGates(pod *v1.Pod) error {
+	p, ok := c.Pods[pod.Labels[domain.JobId]]
+	if !ok {
+		return fmt.Errorf("missing pod to release: %s", pod.Name)
+	}
+	delete(p.Annotations, domain.SchedulingGated)
+	return nil
+}
+
 func (c *SyncFakeClusterContext) AddClusterEventAnnotation(event *v1.Event, annotations map[string]string) error {
 	return nil
 }
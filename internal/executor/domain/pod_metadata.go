@@ -15,4 +15,11 @@ const (
 	MarkedForDeletion        = "deletion_requested"
 	JobDoneAnnotation        = "reported_done"
 	JobPreemptedAnnotation   = "reported_preempted"
+	OutputUrl                = "armada_output_url"
+	// Set on pods created with the gang scheduling gate; removed together with the gate.
+	SchedulingGated = "armada_scheduling_gated"
 )
+
+// GangSchedulingGate is the name of the Kubernetes scheduling gate set on gated gang pods.
+// Kubernetes doesn't bind gated pods to nodes until the gate is removed.
+const GangSchedulingGate = "armadaproject.io/gang"
@@ -27,6 +27,8 @@ import (
 	"github.com/armadaproject/armada/internal/common/util"
 	"github.com/armadaproject/armada/internal/executor/configuration"
 	cluster_context "github.com/armadaproject/armada/internal/executor/context"
+	"github.com/armadaproject/armada/internal/executor/domain"
+	executorutil "github.com/armadaproject/armada/internal/executor/util"
 )
 
 type NodeSpec struct {
@@ -130,6 +132,10 @@ func (c *FakeClusterContext) GetPodEvents(pod *v1.Pod) ([]*v1.Event, error) {
 	return []*v1.Event{}, nil
 }
 
+func (c *FakeClusterContext) GetPodLogs(pod *v1.Pod, containerName string, limitBytes int64) ([]byte, error) {
+	return []byte{}, nil
+}
+
 func (c *FakeClusterContext) SubmitPod(pod *v1.Pod, owner string, ownerGroups []string) (*v1.Pod, error) {
 	saved := c.savePod(pod)
 
@@ -201,6 +207,14 @@ func (c *FakeClusterContext) SubmitIngress(ingress *networking.Ingress) (*networ
 	return nil, errors.Errorf("Ingresses not implemented in FakeClusterContext")
 }
 
+func (c *FakeClusterContext) SubmitSecret(secret *v1.Secret) (*v1.Secret, error) {
+	return nil, errors.Errorf("Secrets not implemented in FakeClusterContext")
+}
+
+func (c *FakeClusterContext) GetSecret(namespace string, name string) (*v1.Secret, error) {
+	return nil, errors.Errorf("Secrets not implemented in FakeClusterContext")
+}
+
 func (c *FakeClusterContext) GetIngresses(pod *v1.Pod) ([]*networking.Ingress, error) {
 	return nil, errors.Errorf("Ingresses not implemented in FakeClusterContext")
 }
@@ -270,6 +284,18 @@ func (c *FakeClusterContext) AddAnnotation(pod *v1.Pod, annotations map[string]s
 	return nil
 }
 
+func (c *FakeClusterContext) ReleaseSchedulingGates(pod *v1.Pod) error {
+	c.rwLock.Lock()
+	defer c.rwLock.Unlock()
+
+	p, found := c.pods[pod.Name]
+	if !found {
+		return errors.Errorf("missing pod to release: %s", pod.Name)
+	}
+	delete(p.Annotations, domain.SchedulingGated)
+	return nil
+}
+
 func (c *FakeClusterContext) AddClusterEventAnnotation(event *v1.Event, annotations map[string]string) error {
 	c.rwLock.Lock()
 	defer c.rwLock.Unlock()
@@ -358,6 +384,11 @@ func (c *FakeClusterContext) trySchedule(pod *v1.Pod) (scheduled bool, removed b
 		return false, true
 	}
 
+	// Gated pods aren't bound to nodes until their scheduling gates are removed.
+	if executorutil.IsSchedulingGated(c.pods[pod.Name]) {
+		return false, false
+	}
+
 	// Use node index if job is targeting a node based on nodeIdLabe
 	// This will likely account for all pods, as the armada scheduler now sets the selector
 	nodes := c.nodes
@@ -8,6 +8,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 
 	"github.com/armadaproject/armada/internal/common/armadacontext"
+	util2 "github.com/armadaproject/armada/internal/common/util"
 	executorContext "github.com/armadaproject/armada/internal/executor/context"
 	"github.com/armadaproject/armada/internal/executor/domain"
 	"github.com/armadaproject/armada/internal/executor/job"
@@ -66,6 +67,9 @@ func (j *RunPreemptedProcessor) Run() {
 						runInfo.Run.Meta.RunId, runInfo.Run.Meta.JobId, err)
 					return
 				}
+				// Mark the pod as preempted locally too, such that its deletion is prioritised without waiting for the informer.
+				pod = pod.DeepCopy()
+				pod.Annotations = util2.MergeMaps(pod.Annotations, map[string]string{domain.JobPreemptedAnnotation: time.Now().String()})
 			}
 
 			j.clusterContext.DeletePods([]*v1.Pod{pod})
@@ -11,11 +11,13 @@ import (
 	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/armadaproject/armada/internal/common/armadacontext"
 	"github.com/armadaproject/armada/internal/common/armadaerrors"
 	"github.com/armadaproject/armada/internal/common/util"
 	"github.com/armadaproject/armada/internal/executor/configuration"
 	"github.com/armadaproject/armada/internal/executor/context"
 	"github.com/armadaproject/armada/internal/executor/domain"
+	"github.com/armadaproject/armada/internal/executor/secrets"
 	util2 "github.com/armadaproject/armada/internal/executor/util"
 	"github.com/armadaproject/armada/pkg/api"
 )
@@ -30,6 +32,8 @@ type SubmitService struct {
 	podDefaults              *configuration.PodDefaults
 	submissionThreadCount    int
 	fatalPodSubmissionErrors []string
+	// Used to resolve secrets referenced by jobs. May be nil, in which case jobs referencing secrets fail to submit.
+	secretProvider secrets.Provider
 }
 
 func NewSubmitter(
@@ -37,12 +41,14 @@ func NewSubmitter(
 	podDefaults *configuration.PodDefaults,
 	submissionThreadCount int,
 	fatalPodSubmissionErrors []string,
+	secretProvider secrets.Provider,
 ) *SubmitService {
 	return &SubmitService{
 		clusterContext:           clusterContext,
 		podDefaults:              podDefaults,
 		submissionThreadCount:    submissionThreadCount,
 		fatalPodSubmissionErrors: fatalPodSubmissionErrors,
+		secretProvider:           secretProvider,
 	}
 }
 
@@ -114,7 +120,8 @@ func (submitService *SubmitService) submitWorker(wg *sync.WaitGroup, jobsToSubmi
 	}
 }
 
-// submitPod submits a pod to k8s together with any services and ingresses bundled with the Armada job.
+// submitPod submits a pod to k8s together with any services and ingresses bundled with the Armada job,
+// and secrets holding the values of any secrets referenced by the job.
 // This function may fail partly, i.e., it may successfully create a subset of the requested objects before failing.
 // In case of failure, any already created objects are not cleaned up.
 func (submitService *SubmitService) submitPod(job *SubmitJob) (*v1.Pod, error) {
@@ -130,11 +137,26 @@ func (submitService *SubmitService) submitPod(job *SubmitJob) (*v1.Pod, error) {
 		})
 	}
 
+	podSecrets, err := secrets.Resolve(armadacontext.Background(), submitService.secretProvider, pod)
+	if err != nil {
+		return pod, err
+	}
+
 	submittedPod, err := submitService.clusterContext.SubmitPod(pod, job.Meta.Owner, job.Meta.OwnershipGroups)
 	if err != nil {
 		return pod, err
 	}
 
+	// Secrets are created after the pod, such that they can be owned by, and hence deleted together with, the pod.
+	// Kubelet retries starting containers until the secrets they depend on exist.
+	for _, secret := range podSecrets {
+		secret.ObjectMeta.OwnerReferences = []metav1.OwnerReference{util2.CreateOwnerReference(submittedPod)}
+		_, err = submitService.clusterContext.SubmitSecret(secret)
+		if err != nil {
+			return pod, err
+		}
+	}
+
 	for _, service := range job.Services {
 		service.ObjectMeta.OwnerReferences = []metav1.OwnerReference{util2.CreateOwnerReference(submittedPod)}
 		_, err = submitService.clusterContext.SubmitService(service)
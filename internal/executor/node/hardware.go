@@ -0,0 +1,104 @@
+package node
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Labels under which the hardware attributes of each node are reported to the scheduler.
+// To target hardware with node selectors efficiently, the scheduler should index these labels.
+const (
+	GpuModelLabel        = "hardware.armadaproject.io/gpu-model"
+	GpuMemoryLabel       = "hardware.armadaproject.io/gpu-memory-mib"
+	GpuCountLabel        = "hardware.armadaproject.io/gpu-count"
+	CpuVendorLabel       = "hardware.armadaproject.io/cpu-vendor"
+	CpuGenerationLabel   = "hardware.armadaproject.io/cpu-generation"
+	CpuArchitectureLabel = "hardware.armadaproject.io/cpu-architecture"
+	NumaLabel            = "hardware.armadaproject.io/numa"
+	NumaCellsLabel       = "hardware.armadaproject.io/numa-cells"
+	LocalDiskTypeLabel   = "hardware.armadaproject.io/local-disk-type"
+)
+
+// Labels published by NVIDIA GPU feature discovery and node feature discovery,
+// which probe the hardware of each node from a daemonset.
+const (
+	nvidiaGpuProductLabel     = "nvidia.com/gpu.product"
+	nvidiaGpuMemoryLabel      = "nvidia.com/gpu.memory"
+	nvidiaGpuCountLabel       = "nvidia.com/gpu.count"
+	nfdCpuVendorLabel         = "feature.node.kubernetes.io/cpu-model.vendor_id"
+	nfdCpuFamilyLabel         = "feature.node.kubernetes.io/cpu-model.family"
+	nfdCpuModelLabel          = "feature.node.kubernetes.io/cpu-model.id"
+	nfdNumaLabel              = "feature.node.kubernetes.io/memory-numa"
+	nfdNumaNodeCountLabel     = "feature.node.kubernetes.io/memory-numa.node_count"
+	nfdNonRotationalDiskLabel = "feature.node.kubernetes.io/storage-nonrotationaldisk"
+	nvidiaGpuResource         = "nvidia.com/gpu"
+)
+
+var invalidLabelValueChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// HardwareProber derives the hardware attributes of nodes, e.g., their GPU model and CPU generation,
+// such that scheduling constraints can target hardware without operators labelling nodes by hand.
+// Attributes are derived from the node status and from the labels published by the hardware discovery daemonsets
+// commonly deployed alongside device plugins; attributes that can't be determined are omitted.
+type HardwareProber struct{}
+
+func NewHardwareProber() *HardwareProber {
+	return &HardwareProber{}
+}
+
+// Probe returns the hardware attributes of node, indexed by the label they're reported under.
+func (p *HardwareProber) Probe(node *v1.Node) map[string]string {
+	rv := make(map[string]string)
+	set := func(label string, value string) {
+		if value = sanitiseLabelValue(value); value != "" {
+			rv[label] = value
+		}
+	}
+
+	set(GpuModelLabel, node.Labels[nvidiaGpuProductLabel])
+	set(GpuMemoryLabel, node.Labels[nvidiaGpuMemoryLabel])
+	if gpus, ok := node.Status.Capacity[nvidiaGpuResource]; ok && !gpus.IsZero() {
+		set(GpuCountLabel, strconv.FormatInt(gpus.Value(), 10))
+	} else {
+		set(GpuCountLabel, node.Labels[nvidiaGpuCountLabel])
+	}
+
+	set(CpuArchitectureLabel, node.Status.NodeInfo.Architecture)
+	set(CpuVendorLabel, node.Labels[nfdCpuVendorLabel])
+	// Family and model together identify the microarchitecture, e.g., family 6, model 143 for Intel Sapphire Rapids.
+	if family, model := node.Labels[nfdCpuFamilyLabel], node.Labels[nfdCpuModelLabel]; family != "" && model != "" {
+		set(CpuGenerationLabel, family+"-"+model)
+	}
+
+	if numa, ok := node.Labels[nfdNumaLabel]; ok {
+		set(NumaLabel, strconv.FormatBool(numa == "true"))
+		// The number of cells is only published if labelled from the node_count attribute of the memory.numa feature;
+		// nodes without NUMA have a single cell.
+		if numa != "true" {
+			set(NumaCellsLabel, "1")
+		} else if cells, err := strconv.Atoi(node.Labels[nfdNumaNodeCountLabel]); err == nil && cells > 0 {
+			set(NumaCellsLabel, strconv.Itoa(cells))
+		}
+	}
+	if nonRotational, ok := node.Labels[nfdNonRotationalDiskLabel]; ok {
+		if nonRotational == "true" {
+			set(LocalDiskTypeLabel, "ssd")
+		} else {
+			set(LocalDiskTypeLabel, "hdd")
+		}
+	}
+	return rv
+}
+
+// sanitiseLabelValue returns value modified to be a valid Kubernetes label value,
+// i.e., at most 63 alphanumeric characters, '-', '_', or '.', starting and ending with an alphanumeric character.
+func sanitiseLabelValue(value string) string {
+	value = invalidLabelValueChars.ReplaceAllString(strings.TrimSpace(value), "-")
+	if len(value) > 63 {
+		value = value[:63]
+	}
+	return strings.Trim(value, "-_.")
+}
@@ -0,0 +1,78 @@
+package node
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHardwareProber_Probe(t *testing.T) {
+	tests := map[string]struct {
+		labels       map[string]string
+		capacity     v1.ResourceList
+		architecture string
+		expected     map[string]string
+	}{
+		"gpu node": {
+			labels: map[string]string{
+				nvidiaGpuProductLabel:     "NVIDIA A100-SXM4-80GB",
+				nvidiaGpuMemoryLabel:      "81920",
+				nfdCpuVendorLabel:         "AMD",
+				nfdCpuFamilyLabel:         "25",
+				nfdCpuModelLabel:          "1",
+				nfdNumaLabel:              "true",
+				nfdNumaNodeCountLabel:     "2",
+				nfdNonRotationalDiskLabel: "true",
+			},
+			capacity:     v1.ResourceList{nvidiaGpuResource: resource.MustParse("8")},
+			architecture: "amd64",
+			expected: map[string]string{
+				GpuModelLabel:        "NVIDIA-A100-SXM4-80GB",
+				GpuMemoryLabel:       "81920",
+				GpuCountLabel:        "8",
+				CpuVendorLabel:       "AMD",
+				CpuGenerationLabel:   "25-1",
+				CpuArchitectureLabel: "amd64",
+				NumaLabel:            "true",
+				NumaCellsLabel:       "2",
+				LocalDiskTypeLabel:   "ssd",
+			},
+		},
+		"cpu node without discovery labels": {
+			architecture: "arm64",
+			expected: map[string]string{
+				CpuArchitectureLabel: "arm64",
+			},
+		},
+		"rotational disk": {
+			labels:   map[string]string{nfdNonRotationalDiskLabel: "false", nfdCpuFamilyLabel: "6"},
+			expected: map[string]string{LocalDiskTypeLabel: "hdd"},
+		},
+		"single numa cell": {
+			labels:   map[string]string{nfdNumaLabel: "false"},
+			expected: map[string]string{NumaLabel: "false", NumaCellsLabel: "1"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			node := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: tc.labels},
+				Status: v1.NodeStatus{
+					Capacity: tc.capacity,
+					NodeInfo: v1.NodeSystemInfo{Architecture: tc.architecture},
+				},
+			}
+			assert.Equal(t, tc.expected, NewHardwareProber().Probe(node))
+		})
+	}
+}
+
+func TestSanitiseLabelValue(t *testing.T) {
+	assert.Equal(t, "Tesla-V100-SXM2-16GB", sanitiseLabelValue(" Tesla V100 (SXM2) 16GB "))
+	assert.Equal(t, "a", sanitiseLabelValue("--a--"))
+	assert.Equal(t, strings.Repeat("b", 63), sanitiseLabelValue(strings.Repeat("b", 100)))
+}
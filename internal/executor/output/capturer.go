@@ -0,0 +1,90 @@
+package output
+
+import (
+	"path"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+
+	armadaconfiguration "github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/executor/util"
+)
+
+// LogSource is the subset of the cluster context used to read the logs of job containers.
+type LogSource interface {
+	GetPodLogs(pod *v1.Pod, containerName string, limitBytes int64) ([]byte, error)
+}
+
+// Capturer collects the output of succeeded job pods that request it via armadaconfiguration.CaptureOutputAnnotation
+// and uploads it to object storage, such that downstream consumers can find it via the URL recorded on the job succeeded event.
+// Only the first container is captured, since by convention that's the container running the job.
+type Capturer struct {
+	uploader    Uploader
+	logSource   LogSource
+	keyPrefix   string
+	maxLogBytes int64
+}
+
+func NewCapturer(uploader Uploader, logSource LogSource, keyPrefix string, maxLogBytes int64) *Capturer {
+	return &Capturer{
+		uploader:    uploader,
+		logSource:   logSource,
+		keyPrefix:   keyPrefix,
+		maxLogBytes: maxLogBytes,
+	}
+}
+
+// Capture uploads the output of pod and returns the URL it was uploaded to,
+// or the empty string if the pod didn't request output capture.
+func (c *Capturer) Capture(ctx *armadacontext.Context, pod *v1.Pod) (string, error) {
+	request, ok := pod.Annotations[armadaconfiguration.CaptureOutputAnnotation]
+	if !ok {
+		return "", nil
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return "", errors.Errorf("pod %s has no containers to capture output from", pod.Name)
+	}
+	containerName := pod.Spec.Containers[0].Name
+
+	var content []byte
+	if request == armadaconfiguration.CaptureOutputLogs {
+		logs, err := c.logSource.GetPodLogs(pod, containerName, c.maxLogBytes)
+		if err != nil {
+			return "", errors.WithMessagef(err, "failed to get logs of container %s of pod %s", containerName, pod.Name)
+		}
+		content = logs
+	} else {
+		message, err := terminationMessage(pod, containerName)
+		if err != nil {
+			return "", err
+		}
+		content = []byte(message)
+	}
+	return c.uploader.Upload(ctx, c.objectKey(pod), content)
+}
+
+// objectKey returns the key output is stored under, which is unique per job run
+// such that retries of a job don't overwrite the output of earlier runs.
+func (c *Capturer) objectKey(pod *v1.Pod) string {
+	return path.Join(
+		c.keyPrefix,
+		util.ExtractQueue(pod),
+		util.ExtractJobSet(pod),
+		util.ExtractJobId(pod),
+		string(pod.UID),
+	)
+}
+
+func terminationMessage(pod *v1.Pod, containerName string) (string, error) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != containerName {
+			continue
+		}
+		if status.State.Terminated == nil {
+			return "", errors.Errorf("container %s of pod %s has not terminated", containerName, pod.Name)
+		}
+		return status.State.Terminated.Message, nil
+	}
+	return "", errors.Errorf("no status found for container %s of pod %s", containerName, pod.Name)
+}
@@ -0,0 +1,128 @@
+package output
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	armadaconfiguration "github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/executor/domain"
+)
+
+type fakeUploader struct {
+	uploaded map[string][]byte
+}
+
+func (u *fakeUploader) Upload(_ *armadacontext.Context, key string, content []byte) (string, error) {
+	u.uploaded[key] = content
+	return "https://store/bucket/" + key, nil
+}
+
+type fakeLogSource struct {
+	logs map[string][]byte
+}
+
+func (s *fakeLogSource) GetPodLogs(pod *v1.Pod, containerName string, limitBytes int64) ([]byte, error) {
+	logs, ok := s.logs[containerName]
+	if !ok {
+		return nil, fmt.Errorf("no logs for container %s", containerName)
+	}
+	if limitBytes > 0 && int64(len(logs)) > limitBytes {
+		logs = logs[:limitBytes]
+	}
+	return logs, nil
+}
+
+func TestCapturer_Capture(t *testing.T) {
+	tests := map[string]struct {
+		captureOutput     *string
+		containerStatuses []v1.ContainerStatus
+		expectedUrl       string
+		expectedContent   []byte
+		expectError       bool
+	}{
+		"no output requested": {
+			captureOutput: nil,
+			expectedUrl:   "",
+		},
+		"logs": {
+			captureOutput:   pointer(armadaconfiguration.CaptureOutputLogs),
+			expectedUrl:     "https://store/bucket/prefix/queue/jobset/job-id/pod-uid",
+			expectedContent: []byte("hello"),
+		},
+		"file": {
+			captureOutput: pointer("/tmp/output.json"),
+			containerStatuses: []v1.ContainerStatus{
+				{
+					Name:  "main",
+					State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Message: `{"result": 1}`}},
+				},
+			},
+			expectedUrl:     "https://store/bucket/prefix/queue/jobset/job-id/pod-uid",
+			expectedContent: []byte(`{"result": 1}`),
+		},
+		"file of container that hasn't terminated": {
+			captureOutput: pointer("/tmp/output.json"),
+			containerStatuses: []v1.ContainerStatus{
+				{
+					Name:  "main",
+					State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+				},
+			},
+			expectError: true,
+		},
+		"file of container without status": {
+			captureOutput: pointer("/tmp/output.json"),
+			expectError:   true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			uploader := &fakeUploader{uploaded: map[string][]byte{}}
+			logSource := &fakeLogSource{logs: map[string][]byte{"main": []byte("hello world")}}
+			capturer := NewCapturer(uploader, logSource, "prefix", 5)
+
+			pod := makePod(tc.captureOutput)
+			pod.Status.ContainerStatuses = tc.containerStatuses
+			url, err := capturer.Capture(armadacontext.Background(), pod)
+			if tc.expectError {
+				assert.Error(t, err)
+				assert.Empty(t, uploader.uploaded)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedUrl, url)
+			if tc.expectedUrl == "" {
+				assert.Empty(t, uploader.uploaded)
+			} else {
+				assert.Equal(t, tc.expectedContent, uploader.uploaded["prefix/queue/jobset/job-id/pod-uid"])
+			}
+		})
+	}
+}
+
+func makePod(captureOutput *string) *v1.Pod {
+	annotations := map[string]string{domain.JobSetId: "jobset"}
+	if captureOutput != nil {
+		annotations[armadaconfiguration.CaptureOutputAnnotation] = *captureOutput
+	}
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "armada-job-id-0",
+			UID:         "pod-uid",
+			Labels:      map[string]string{domain.JobId: "job-id", domain.Queue: "queue"},
+			Annotations: annotations,
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "main"}, {Name: "sidecar"}},
+		},
+	}
+}
+
+func pointer(s string) *string {
+	return &s
+}
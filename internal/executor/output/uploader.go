@@ -0,0 +1,155 @@
+package output
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	amzDateFormat    = "20060102T150405Z"
+	amzDayFormat     = "20060102"
+)
+
+// Uploader stores captured job output under the given key and returns the URL of the resulting object.
+type Uploader interface {
+	Upload(ctx *armadacontext.Context, key string, content []byte) (string, error)
+}
+
+// S3Uploader uploads objects via the S3 PutObject API, signing requests with AWS signature version 4.
+// This is supported by AWS S3 as well as by most S3-compatible stores, e.g., GCS via its XML interoperability API and MinIO.
+type S3Uploader struct {
+	endpoint        string
+	region          string
+	bucket          string
+	accessKeyId     string
+	secretAccessKey string
+	client          *http.Client
+	// Used to get the current time when signing requests; overridden in tests.
+	clock func() time.Time
+}
+
+func NewS3Uploader(endpoint, region, bucket, accessKeyId, secretAccessKey string, timeout time.Duration) *S3Uploader {
+	return &S3Uploader{
+		endpoint:        strings.TrimSuffix(endpoint, "/"),
+		region:          region,
+		bucket:          bucket,
+		accessKeyId:     accessKeyId,
+		secretAccessKey: secretAccessKey,
+		client:          &http.Client{Timeout: timeout},
+		clock:           time.Now,
+	}
+}
+
+func (u *S3Uploader) Upload(ctx *armadacontext.Context, key string, content []byte) (string, error) {
+	objectUrl := u.endpoint + "/" + uriEncodePath(u.bucket+"/"+key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectUrl, bytes.NewReader(content))
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	u.sign(req, content)
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", errors.Errorf("uploading %s failed with status %s: %s", objectUrl, resp.Status, string(body))
+	}
+	return objectUrl, nil
+}
+
+// sign adds the headers required to authenticate req with AWS signature version 4.
+// Requests are left unsigned if no credentials are configured.
+func (u *S3Uploader) sign(req *http.Request, content []byte) {
+	payloadHash := sha256Hex(content)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if u.accessKeyId == "" {
+		return
+	}
+
+	now := u.clock().UTC()
+	amzDate := now.Format(amzDateFormat)
+	day := now.Format(amzDayFormat)
+	req.Header.Set("x-amz-date", amzDate)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"",
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", day, u.region)
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSha256([]byte("AWS4"+u.secretAccessKey), day)
+	signingKey = hmacSha256(signingKey, u.region)
+	signingKey = hmacSha256(signingKey, "s3")
+	signingKey = hmacSha256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(signingKey, stringToSign))
+
+	req.Header.Set(
+		"Authorization",
+		fmt.Sprintf(
+			"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			signingAlgorithm, u.accessKeyId, scope, signedHeaders, signature,
+		),
+	)
+}
+
+// uriEncodePath escapes each segment of p as required by AWS signature version 4,
+// i.e., all bytes except unreserved characters are percent-encoded.
+func uriEncodePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		var sb strings.Builder
+		for _, b := range []byte(segment) {
+			if isUnreserved(b) {
+				sb.WriteByte(b)
+			} else {
+				fmt.Fprintf(&sb, "%%%02X", b)
+			}
+		}
+		segments[i] = sb.String()
+	}
+	return strings.Join(segments, "/")
+}
+
+func isUnreserved(b byte) bool {
+	return (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+		b == '-' || b == '_' || b == '.' || b == '~'
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,73 @@
+package output
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+func TestS3Uploader_Upload(t *testing.T) {
+	var receivedPath, receivedBody string
+	var receivedHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		receivedPath = r.URL.EscapedPath()
+		receivedHeaders = r.Header
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		receivedBody = string(body)
+	}))
+	defer server.Close()
+
+	uploader := NewS3Uploader(server.URL+"/", "eu-west-2", "bucket", "access-key", "secret", time.Second)
+	uploader.clock = func() time.Time { return time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC) }
+	url, err := uploader.Upload(armadacontext.Background(), "prefix/job set/output", []byte("hello"))
+	require.NoError(t, err)
+
+	assert.Equal(t, server.URL+"/bucket/prefix/job%20set/output", url)
+	assert.Equal(t, "/bucket/prefix/job%20set/output", receivedPath)
+	assert.Equal(t, "hello", receivedBody)
+	assert.Equal(t, "20230102T030405Z", receivedHeaders.Get("x-amz-date"))
+	assert.Equal(t, sha256Hex([]byte("hello")), receivedHeaders.Get("x-amz-content-sha256"))
+	assert.True(t, strings.HasPrefix(
+		receivedHeaders.Get("Authorization"),
+		"AWS4-HMAC-SHA256 Credential=access-key/20230102/eu-west-2/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=",
+	))
+}
+
+func TestS3Uploader_Upload_Unsigned(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	uploader := NewS3Uploader(server.URL, "", "bucket", "", "", time.Second)
+	_, err := uploader.Upload(armadacontext.Background(), "output", []byte("hello"))
+	require.NoError(t, err)
+	assert.Empty(t, authorization)
+}
+
+func TestS3Uploader_Upload_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("AccessDenied"))
+	}))
+	defer server.Close()
+
+	uploader := NewS3Uploader(server.URL, "eu-west-2", "bucket", "access-key", "secret", time.Second)
+	_, err := uploader.Upload(armadacontext.Background(), "output", []byte("hello"))
+	assert.ErrorContains(t, err, "AccessDenied")
+}
+
+func TestUriEncodePath(t *testing.T) {
+	assert.Equal(t, "bucket/a%2Bb/c~d_e.f-g/%24h", uriEncodePath("bucket/a+b/c~d_e.f-g/$h"))
+}
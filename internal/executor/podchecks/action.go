@@ -11,6 +11,7 @@ type Action int
 const (
 	// Order matters here, actions with higher numbers trump those with lower.
 	ActionWait Action = iota
+	ActionReturnLease
 	ActionRetry
 	ActionFail
 )
@@ -19,6 +20,8 @@ func (a Action) String() string {
 	switch a {
 	case ActionWait:
 		return "Wait"
+	case ActionReturnLease:
+		return "ReturnLease"
 	case ActionRetry:
 		return "Retry"
 	case ActionFail:
@@ -34,6 +37,8 @@ func mapAction(action config.Action) (Action, error) {
 		return ActionFail, nil
 	case config.ActionRetry:
 		return ActionRetry, nil
+	case config.ActionReturnLease:
+		return ActionReturnLease, nil
 	default:
 		return ActionWait, fmt.Errorf("Invalid action: \"%s\"", action)
 	}
@@ -7,4 +7,5 @@ const (
 	PodStartupIssue
 	NoStatusUpdates
 	NoNodeAssigned
+	StuckPod
 )
@@ -19,6 +19,7 @@ type PodChecker interface {
 type PodChecks struct {
 	eventChecks               eventChecker
 	containerStateChecks      containerStateChecker
+	stuckPodChecks            stuckPodChecker
 	deadlineForUpdates        time.Duration
 	deadlineForNodeAssignment time.Duration
 }
@@ -35,9 +36,15 @@ func NewPodChecks(cfg config.Checks) (*PodChecks, error) {
 		return nil, err
 	}
 
+	spc, err := newStuckPodChecks(cfg.StuckPods)
+	if err != nil {
+		return nil, err
+	}
+
 	return &PodChecks{
 		eventChecks:               ec,
 		containerStateChecks:      csc,
+		stuckPodChecks:            spc,
 		deadlineForUpdates:        cfg.DeadlineForUpdates,
 		deadlineForNodeAssignment: cfg.DeadlineForNodeAssignment,
 	}, nil
@@ -46,6 +53,12 @@ func NewPodChecks(cfg config.Checks) (*PodChecks, error) {
 func (pc *PodChecks) GetAction(pod *v1.Pod, podEvents []*v1.Event, timeInState time.Duration) (Action, Cause, string) {
 	messages := []string{}
 
+	if pc.stuckPodChecks != nil {
+		if stuckPodAction, message := pc.stuckPodChecks.getAction(pod, podEvents, timeInState); stuckPodAction != ActionWait {
+			return stuckPodAction, StuckPod, message
+		}
+	}
+
 	isAssignedToNode := pod.Spec.NodeName != ""
 	if timeInState > pc.deadlineForNodeAssignment && !isAssignedToNode {
 		return ActionRetry, NoNodeAssigned, fmt.Sprintf("Pod could not been scheduled in within %s deadline. Retrying", pc.deadlineForNodeAssignment)
@@ -90,6 +90,8 @@ func mockMessage(result Action) string {
 		return "please fail"
 	case ActionRetry:
 		return "please retry"
+	case ActionReturnLease:
+		return "please return lease"
 	case ActionWait:
 		return ""
 	default:
@@ -107,6 +109,11 @@ type mockContainerStateChecks struct {
 	message string
 }
 
+type mockStuckPodChecks struct {
+	result  Action
+	message string
+}
+
 func (ec *mockEventChecks) getAction(podName string, podEvents []*v1.Event, timeInState time.Duration) (Action, string) {
 	return ec.result, ec.message
 }
@@ -114,3 +121,20 @@ func (ec *mockEventChecks) getAction(podName string, podEvents []*v1.Event, time
 func (csc *mockContainerStateChecks) getAction(pod *v1.Pod, timeInState time.Duration) (Action, string) {
 	return csc.result, csc.message
 }
+
+func (spc *mockStuckPodChecks) getAction(pod *v1.Pod, podEvents []*v1.Event, timeInState time.Duration) (Action, string) {
+	return spc.result, spc.message
+}
+
+func Test_GetAction_StuckPodChecksTakePrecedence(t *testing.T) {
+	podChecks := podChecksWithMocks(ActionFail, ActionFail)
+	podChecks.stuckPodChecks = &mockStuckPodChecks{result: ActionReturnLease, message: mockMessage(ActionReturnLease)}
+	result, cause, _ := podChecks.GetAction(createBasicPod(true), []*v1.Event{{Message: "MockEvent", Type: "None"}}, time.Minute)
+	assert.Equal(t, ActionReturnLease, result)
+	assert.Equal(t, StuckPod, cause)
+
+	podChecks.stuckPodChecks = &mockStuckPodChecks{result: ActionWait}
+	result, cause, _ = podChecks.GetAction(createBasicPod(true), []*v1.Event{{Message: "MockEvent", Type: "None"}}, time.Minute)
+	assert.Equal(t, ActionFail, result)
+	assert.Equal(t, PodStartupIssue, cause)
+}
@@ -0,0 +1,121 @@
+package podchecks
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+
+	config "github.com/armadaproject/armada/internal/executor/configuration/podchecks"
+	"github.com/armadaproject/armada/internal/executor/util"
+)
+
+// Reason of the PodScheduled condition of pods with scheduling gates.
+const schedulingGatedReason = "SchedulingGated"
+
+var imagePullWaitingReasons = map[string]bool{
+	"ErrImagePull":        true,
+	"ImagePullBackOff":    true,
+	"ErrImageNeverPull":   true,
+	"InvalidImageName":    true,
+	"RegistryUnavailable": true,
+}
+
+var volumeAttachEventReasons = map[string]bool{
+	"FailedAttachVolume": true,
+	"FailedMount":        true,
+	"FailedMapVolume":    true,
+}
+
+// StuckPodCondition describes why a pod is stuck pending.
+type StuckPodCondition struct {
+	Cause config.StuckPodCause
+	// Reason reported by Kubernetes, e.g., the waiting reason of a container or the reason of an event.
+	Reason  string
+	Message string
+}
+
+func (c *StuckPodCondition) String() string {
+	return fmt.Sprintf("cause=%s reason=%s: %s", c.Cause, c.Reason, c.Message)
+}
+
+// ClassifyStuckPod returns the condition explaining why pod is stuck pending, or nil if the cause isn't known.
+func ClassifyStuckPod(pod *v1.Pod, podEvents []*v1.Event) *StuckPodCondition {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse && condition.Reason == schedulingGatedReason {
+			return &StuckPodCondition{Cause: config.StuckPodCauseSchedulingGate, Reason: condition.Reason, Message: condition.Message}
+		}
+	}
+	for _, containerStatus := range util.GetPodContainerStatuses(pod) {
+		if waiting := containerStatus.State.Waiting; waiting != nil && imagePullWaitingReasons[waiting.Reason] {
+			return &StuckPodCondition{
+				Cause:   config.StuckPodCauseImagePull,
+				Reason:  waiting.Reason,
+				Message: fmt.Sprintf("container %s: %s", containerStatus.Name, waiting.Message),
+			}
+		}
+	}
+	for _, event := range podEvents {
+		if event.Type == v1.EventTypeWarning && volumeAttachEventReasons[event.Reason] {
+			return &StuckPodCondition{Cause: config.StuckPodCauseVolumeAttach, Reason: event.Reason, Message: event.Message}
+		}
+	}
+	return nil
+}
+
+type stuckPodChecker interface {
+	getAction(pod *v1.Pod, podEvents []*v1.Event, timeInState time.Duration) (Action, string)
+}
+
+type stuckPodChecks struct {
+	checks map[config.StuckPodCause]stuckPodCheck
+}
+
+type stuckPodCheck struct {
+	gracePeriod time.Duration
+	action      Action
+}
+
+func newStuckPodChecks(configs []config.StuckPodCheck) (*stuckPodChecks, error) {
+	stuckPodChecks := &stuckPodChecks{checks: make(map[config.StuckPodCause]stuckPodCheck, len(configs))}
+	for _, cfg := range configs {
+		switch cfg.Cause {
+		case config.StuckPodCauseVolumeAttach, config.StuckPodCauseImagePull, config.StuckPodCauseSchedulingGate:
+		default:
+			return nil, fmt.Errorf("Invalid stuck pod cause: \"%s\"", cfg.Cause)
+		}
+		if _, ok := stuckPodChecks.checks[cfg.Cause]; ok {
+			return nil, fmt.Errorf("Duplicate stuck pod check for cause \"%s\"", cfg.Cause)
+		}
+
+		action, err := mapAction(cfg.Action)
+		if err != nil {
+			return nil, err
+		}
+
+		stuckPodChecks.checks[cfg.Cause] = stuckPodCheck{gracePeriod: cfg.GracePeriod, action: action}
+		log.Infof("   Created stuck pod check %s %s %s", cfg.Cause, cfg.GracePeriod, action)
+	}
+	return stuckPodChecks, nil
+}
+
+func (spc *stuckPodChecks) getAction(pod *v1.Pod, podEvents []*v1.Event, timeInState time.Duration) (Action, string) {
+	condition := ClassifyStuckPod(pod, podEvents)
+	if condition == nil {
+		return ActionWait, ""
+	}
+	check, ok := spc.checks[condition.Cause]
+	if !ok || timeInState < check.gracePeriod {
+		return ActionWait, ""
+	}
+	log.Warnf(
+		"Pod %s in namespace %s has been stuck pending for more than %v (%s), required action is %s",
+		pod.Name,
+		pod.Namespace,
+		check.gracePeriod,
+		condition,
+		check.action,
+	)
+	return check.action, fmt.Sprintf("Pod has been stuck pending for more than timeout %v (%s)", check.gracePeriod, condition)
+}
@@ -0,0 +1,112 @@
+package podchecks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	config "github.com/armadaproject/armada/internal/executor/configuration/podchecks"
+)
+
+func Test_ClassifyStuckPod(t *testing.T) {
+	schedulingGatedPod := basicPod()
+	schedulingGatedPod.Status.Conditions = []v1.PodCondition{
+		{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: "SchedulingGated", Message: "Scheduling is blocked due to non-empty scheduling gates"},
+	}
+	imagePullPod := basicPod()
+	imagePullPod.Status.ContainerStatuses[0].State.Waiting = &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}
+	initImagePullPod := basicPod()
+	initImagePullPod.Status.InitContainerStatuses = []v1.ContainerStatus{
+		{Name: "init", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ErrImagePull"}}},
+	}
+	creatingPod := basicPod()
+	creatingPod.Status.ContainerStatuses[0].State.Waiting = &v1.ContainerStateWaiting{Reason: "ContainerCreating"}
+	failedAttachEvent := &v1.Event{Type: v1.EventTypeWarning, Reason: "FailedAttachVolume", Message: "Multi-Attach error for volume"}
+
+	tests := map[string]struct {
+		pod           *v1.Pod
+		events        []*v1.Event
+		expectedCause config.StuckPodCause
+		expectedNil   bool
+	}{
+		"scheduling gate": {
+			pod:           schedulingGatedPod,
+			expectedCause: config.StuckPodCauseSchedulingGate,
+		},
+		"image pull": {
+			pod:           imagePullPod,
+			expectedCause: config.StuckPodCauseImagePull,
+		},
+		"image pull of init container": {
+			pod:           initImagePullPod,
+			expectedCause: config.StuckPodCauseImagePull,
+		},
+		"volume attach": {
+			pod:           creatingPod,
+			events:        []*v1.Event{failedAttachEvent},
+			expectedCause: config.StuckPodCauseVolumeAttach,
+		},
+		"volume attach event that isn't a warning": {
+			pod:         creatingPod,
+			events:      []*v1.Event{{Type: v1.EventTypeNormal, Reason: "FailedAttachVolume"}},
+			expectedNil: true,
+		},
+		"unknown cause": {
+			pod:         creatingPod,
+			events:      []*v1.Event{{Type: v1.EventTypeNormal, Reason: "Pulling"}},
+			expectedNil: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			condition := ClassifyStuckPod(tc.pod, tc.events)
+			if tc.expectedNil {
+				assert.Nil(t, condition)
+				return
+			}
+			if assert.NotNil(t, condition) {
+				assert.Equal(t, tc.expectedCause, condition.Cause)
+				assert.NotEmpty(t, condition.Reason)
+			}
+		})
+	}
+}
+
+func Test_stuckPodChecks_getAction(t *testing.T) {
+	spc, err := newStuckPodChecks([]config.StuckPodCheck{
+		{Cause: config.StuckPodCauseImagePull, GracePeriod: time.Minute, Action: config.ActionReturnLease},
+	})
+	assert.Nil(t, err)
+
+	pod := basicPod()
+	pod.Status.ContainerStatuses[0].State.Waiting = &v1.ContainerStateWaiting{Reason: "ErrImagePull"}
+
+	action, message := spc.getAction(pod, nil, time.Second)
+	assert.Equal(t, ActionWait, action)
+	assert.Empty(t, message)
+
+	action, message = spc.getAction(pod, nil, 2*time.Minute)
+	assert.Equal(t, ActionReturnLease, action)
+	assert.Contains(t, message, "cause=ImagePull")
+
+	// Pods stuck for a cause without a check are left to the other checks.
+	pod.Status.ContainerStatuses[0].State.Waiting = nil
+	action, _ = spc.getAction(pod, []*v1.Event{{Type: v1.EventTypeWarning, Reason: "FailedMount"}}, 2*time.Minute)
+	assert.Equal(t, ActionWait, action)
+}
+
+func Test_newStuckPodChecks_InvalidConfig(t *testing.T) {
+	_, err := newStuckPodChecks([]config.StuckPodCheck{{Cause: "NotACause", Action: config.ActionRetry}})
+	assert.Error(t, err)
+
+	_, err = newStuckPodChecks([]config.StuckPodCheck{{Cause: config.StuckPodCauseImagePull, Action: "NotAnAction"}})
+	assert.Error(t, err)
+
+	_, err = newStuckPodChecks([]config.StuckPodCheck{
+		{Cause: config.StuckPodCauseImagePull, Action: config.ActionRetry},
+		{Cause: config.StuckPodCauseImagePull, Action: config.ActionFail},
+	})
+	assert.Error(t, err)
+}
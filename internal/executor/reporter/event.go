@@ -106,8 +106,17 @@ func CreateJobIngressInfoEvent(pod *v1.Pod, clusterId string, associatedServices
 		return nil, errors.Errorf("unable to create JobIngressInfoEvent for pod %s (%s), as no associated ingress provided", pod.Name, pod.Namespace)
 	}
 	containerPortMapping := map[int32]string{}
+	isGangLeader := util.IsGangLeader(pod.Annotations)
 	for _, service := range associatedServices {
 		if service.Spec.Type != v1.ServiceTypeNodePort {
+			// Other jobs in the gang connect to the gang leader from within the cluster.
+			if isGangLeader {
+				for _, servicePort := range service.Spec.Ports {
+					if _, ok := containerPortMapping[servicePort.Port]; !ok {
+						containerPortMapping[servicePort.Port] = fmt.Sprintf("%s.%s.svc:%d", service.Name, service.Namespace, servicePort.Port)
+					}
+				}
+			}
 			continue
 		}
 		for _, servicePort := range service.Spec.Ports {
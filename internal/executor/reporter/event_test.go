@@ -7,6 +7,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
 
+	"github.com/armadaproject/armada/internal/armada/configuration"
 	"github.com/armadaproject/armada/pkg/api"
 )
 
@@ -113,6 +114,28 @@ func TestCreateJobIngressInfoEvent_OnlyIncludesNodePortServices(t *testing.T) {
 	assert.Equal(t, expectedIngressMapping, ingressEvent.IngressAddresses)
 }
 
+func TestCreateJobIngressInfoEvent_GangLeaderIncludesClusterIpServices(t *testing.T) {
+	expectedIngressMapping := map[int32]string{
+		8080: "192.0.0.1:32001",
+		8081: "armada-gang-foo-headless.namespace.svc:8081",
+	}
+	pod := createNodeAllocatedPod()
+	pod.Annotations = map[string]string{configuration.GangLeaderAnnotation: "true"}
+
+	nodePortService := createService(v1.ServiceTypeNodePort, 8080, 32001)
+	clusterIpService := createService(v1.ServiceTypeClusterIP, 8081, 0)
+	clusterIpService.Name = "armada-gang-foo-headless"
+	clusterIpService.Namespace = "namespace"
+
+	event, err := CreateJobIngressInfoEvent(pod, "cluster1", []*v1.Service{nodePortService, clusterIpService}, []*networking.Ingress{})
+	assert.NoError(t, err)
+
+	ingressEvent, ok := event.(*api.JobIngressInfoEvent)
+	assert.True(t, ok)
+
+	assert.Equal(t, expectedIngressMapping, ingressEvent.IngressAddresses)
+}
+
 func TestCreateJobIngressInfoEvent_PodNotAllocatedToNode(t *testing.T) {
 	service := &v1.Service{}
 
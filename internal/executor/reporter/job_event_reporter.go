@@ -8,10 +8,13 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
 
+	"github.com/armadaproject/armada/internal/common/armadacontext"
 	clusterContext "github.com/armadaproject/armada/internal/executor/context"
 	domain2 "github.com/armadaproject/armada/internal/executor/domain"
 	"github.com/armadaproject/armada/internal/executor/job"
+	"github.com/armadaproject/armada/internal/executor/output"
 	"github.com/armadaproject/armada/internal/executor/util"
+	"github.com/armadaproject/armada/pkg/api"
 )
 
 const batchSize = 200
@@ -35,14 +38,22 @@ type JobEventReporter struct {
 	legacyMode       bool
 	jobRunStateStore *job.JobRunStateStore
 	clusterContext   clusterContext.ClusterContext
+	// If non-nil, used to upload the output of succeeded jobs that request it before reporting them as succeeded.
+	outputCapturer *output.Capturer
 }
 
-func NewJobEventReporter(clusterContext clusterContext.ClusterContext, jobRunState *job.JobRunStateStore, eventSender EventSender) (*JobEventReporter, chan bool) {
+func NewJobEventReporter(
+	clusterContext clusterContext.ClusterContext,
+	jobRunState *job.JobRunStateStore,
+	eventSender EventSender,
+	outputCapturer *output.Capturer,
+) (*JobEventReporter, chan bool) {
 	stop := make(chan bool)
 	reporter := &JobEventReporter{
 		eventSender:      eventSender,
 		clusterContext:   clusterContext,
 		jobRunStateStore: jobRunState,
+		outputCapturer:   outputCapturer,
 		eventBuffer:      make(chan *queuedEvent, 1000000),
 		eventQueued:      map[string]uint8{},
 		eventQueuedMutex: sync.Mutex{},
@@ -188,6 +199,14 @@ func (eventReporter *JobEventReporter) reportCurrentStatus(pod *v1.Pod) {
 		log.Errorf("Failed to report event: %v", err)
 		return
 	}
+	if succeededEvent, ok := event.(*api.JobSucceededEvent); ok && eventReporter.outputCapturer != nil {
+		// Failing to capture output doesn't fail the job; consumers of the output find no url on the succeeded event instead.
+		outputUrl, err := eventReporter.outputCapturer.Capture(armadacontext.Background(), pod)
+		if err != nil {
+			log.Errorf("Failed to capture output of pod %s: %v", pod.Name, err)
+		}
+		succeededEvent.OutputUrl = outputUrl
+	}
 
 	eventReporter.QueueEvent(EventMessage{Event: event, JobRunId: util.ExtractJobRunId(pod)}, func(err error) {
 		if err != nil {
@@ -113,7 +113,7 @@ func setupTest(t *testing.T, existingPods []*v1.Pod) (EventReporter, *fakecontex
 
 	eventSender := NewFakeEventSender()
 	jobRunState := job.NewJobRunStateStore(executorContext)
-	jobEventReporter, _ := NewJobEventReporter(executorContext, jobRunState, eventSender)
+	jobEventReporter, _ := NewJobEventReporter(executorContext, jobRunState, eventSender, nil)
 
 	return jobEventReporter, executorContext, jobRunState, eventSender
 }
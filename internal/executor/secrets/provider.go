@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/executor/domain"
+)
+
+// Provider resolves the secrets referenced by jobs.
+type Provider interface {
+	// GetSecret returns the data of the secret with the given name, as visible to jobs in the given queue.
+	// Returns armadaerrors.ErrNotFound if there's no such secret.
+	GetSecret(ctx *armadacontext.Context, queue string, name string) (map[string][]byte, error)
+}
+
+// SecretGetter is the subset of the cluster context used to read secrets.
+type SecretGetter interface {
+	GetSecret(namespace string, name string) (*v1.Secret, error)
+}
+
+// KubernetesProvider resolves secrets from a namespace of the cluster the executor is running jobs on.
+// To prevent jobs from reading secrets of other queues, only secrets labelled with the queue of the job are visible to it.
+type KubernetesProvider struct {
+	secretGetter SecretGetter
+	namespace    string
+}
+
+func NewKubernetesProvider(secretGetter SecretGetter, namespace string) *KubernetesProvider {
+	return &KubernetesProvider{
+		secretGetter: secretGetter,
+		namespace:    namespace,
+	}
+}
+
+func (p *KubernetesProvider) GetSecret(_ *armadacontext.Context, queue string, name string) (map[string][]byte, error) {
+	secret, err := p.secretGetter.GetSecret(p.namespace, name)
+	if k8s_errors.IsNotFound(err) || (err == nil && secret.Labels[domain.Queue] != queue) {
+		return nil, errors.WithStack(&armadaerrors.ErrNotFound{
+			Type:    "secret",
+			Value:   name,
+			Message: fmt.Sprintf("no secret %s labelled with queue %s in namespace %s", name, queue, p.namespace),
+		})
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return secret.Data, nil
+}
+
+// VaultProvider resolves secrets from the KV version 2 secrets engine of HashiCorp Vault.
+// Secrets of each queue are stored under a separate path, i.e., <mountPath>/data/<queue>/<name>.
+type VaultProvider struct {
+	address   string
+	token     string
+	mountPath string
+	client    *http.Client
+}
+
+func NewVaultProvider(address string, token string, mountPath string, timeout time.Duration) *VaultProvider {
+	return &VaultProvider{
+		address:   strings.TrimSuffix(address, "/"),
+		token:     token,
+		mountPath: strings.Trim(mountPath, "/"),
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *VaultProvider) GetSecret(ctx *armadacontext.Context, queue string, name string) (map[string][]byte, error) {
+	url := fmt.Sprintf("%s/v1/%s/data/%s/%s", p.address, p.mountPath, queue, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.WithStack(&armadaerrors.ErrNotFound{
+			Type:    "secret",
+			Value:   name,
+			Message: fmt.Sprintf("no secret %s for queue %s in vault", name, queue),
+		})
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("reading secret %s for queue %s from vault failed with status %s", name, queue, resp.Status)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode secret %s for queue %s read from vault", name, queue)
+	}
+	data := make(map[string][]byte, len(body.Data.Data))
+	for key, value := range body.Data.Data {
+		data[key] = []byte(value)
+	}
+	return data, nil
+}
@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/executor/domain"
+)
+
+type fakeSecretGetter struct {
+	secrets []*v1.Secret
+}
+
+func (g *fakeSecretGetter) GetSecret(namespace string, name string) (*v1.Secret, error) {
+	for _, secret := range g.secrets {
+		if secret.Namespace == namespace && secret.Name == name {
+			return secret, nil
+		}
+	}
+	return nil, k8s_errors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+}
+
+func TestKubernetesProvider_GetSecret(t *testing.T) {
+	getter := &fakeSecretGetter{
+		secrets: []*v1.Secret{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-creds", Namespace: "secrets", Labels: map[string]string{domain.Queue: "queue"}},
+				Data:       map[string][]byte{"password": []byte("hunter2")},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "unlabelled", Namespace: "secrets"},
+				Data:       map[string][]byte{"password": []byte("hunter2")},
+			},
+		},
+	}
+	provider := NewKubernetesProvider(getter, "secrets")
+
+	data, err := provider.GetSecret(armadacontext.Background(), "queue", "db-creds")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"password": []byte("hunter2")}, data)
+
+	var notFoundErr *armadaerrors.ErrNotFound
+	_, err = provider.GetSecret(armadacontext.Background(), "other-queue", "db-creds")
+	assert.True(t, errors.As(err, &notFoundErr))
+	_, err = provider.GetSecret(armadacontext.Background(), "queue", "unlabelled")
+	assert.True(t, errors.As(err, &notFoundErr))
+	_, err = provider.GetSecret(armadacontext.Background(), "queue", "missing")
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestVaultProvider_GetSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/armada/data/queue/db-creds" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"data": {"password": "hunter2"}, "metadata": {"version": 1}}}`))
+	}))
+	defer server.Close()
+
+	provider := NewVaultProvider(server.URL, "token", "/armada/", time.Second)
+	data, err := provider.GetSecret(armadacontext.Background(), "queue", "db-creds")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"password": []byte("hunter2")}, data)
+
+	var notFoundErr *armadaerrors.ErrNotFound
+	_, err = provider.GetSecret(armadacontext.Background(), "other-queue", "db-creds")
+	assert.True(t, errors.As(err, &notFoundErr))
+
+	_, err = NewVaultProvider(server.URL, "wrong-token", "armada", time.Second).GetSecret(armadacontext.Background(), "queue", "db-creds")
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &notFoundErr))
+}
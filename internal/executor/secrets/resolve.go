@@ -0,0 +1,103 @@
+package secrets
+
+import (
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/executor/domain"
+	"github.com/armadaproject/armada/internal/executor/util"
+)
+
+// Resolve resolves the secrets referenced by pod via provider and returns secrets holding the resolved values,
+// which must be created alongside the pod. References to the secrets in the pod spec are rewritten to refer to the returned secrets.
+// If resolving a secret fails for reasons other than the secret not existing, an armadaerrors.ErrCreateResource is returned,
+// such that the job is retried rather than failed.
+func Resolve(ctx *armadacontext.Context, provider Provider, pod *v1.Pod) ([]*v1.Secret, error) {
+	refs := util.SecretReferences(pod.Annotations)
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	if provider == nil {
+		return nil, errors.Errorf("pod %s references secrets %v but no secret provider is configured", pod.Name, refs)
+	}
+
+	queue := util.ExtractQueue(pod)
+	secrets := make([]*v1.Secret, 0, len(refs))
+	secretNames := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		if _, ok := secretNames[ref]; ok {
+			continue
+		}
+		data, err := provider.GetSecret(ctx, queue, ref)
+		if err != nil {
+			var notFoundErr *armadaerrors.ErrNotFound
+			if errors.As(err, &notFoundErr) {
+				return nil, err
+			}
+			return nil, errors.WithStack(&armadaerrors.ErrCreateResource{
+				Type:    "secret",
+				Name:    ref,
+				Message: err.Error(),
+			})
+		}
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pod.Name + "-" + ref,
+				Namespace: pod.Namespace,
+				Labels: map[string]string{
+					domain.JobId: util.ExtractJobId(pod),
+					domain.Queue: queue,
+				},
+			},
+			Type: v1.SecretTypeOpaque,
+			Data: data,
+		}
+		secrets = append(secrets, secret)
+		secretNames[ref] = secret.Name
+	}
+	rewriteSecretReferences(&pod.Spec, secretNames)
+	return secrets, nil
+}
+
+// rewriteSecretReferences replaces each reference in spec to a secret in secretNames with a reference to the secret it maps to.
+func rewriteSecretReferences(spec *v1.PodSpec, secretNames map[string]string) {
+	rewrite := func(name *string) {
+		if newName, ok := secretNames[*name]; ok {
+			*name = newName
+		}
+	}
+	rewriteContainers := func(containers []v1.Container) {
+		for i := range containers {
+			for j := range containers[i].Env {
+				if valueFrom := containers[i].Env[j].ValueFrom; valueFrom != nil && valueFrom.SecretKeyRef != nil {
+					rewrite(&valueFrom.SecretKeyRef.Name)
+				}
+			}
+			for j := range containers[i].EnvFrom {
+				if secretRef := containers[i].EnvFrom[j].SecretRef; secretRef != nil {
+					rewrite(&secretRef.Name)
+				}
+			}
+		}
+	}
+	rewriteContainers(spec.InitContainers)
+	rewriteContainers(spec.Containers)
+	for i := range spec.Volumes {
+		if secret := spec.Volumes[i].Secret; secret != nil {
+			rewrite(&secret.SecretName)
+		}
+		if projected := spec.Volumes[i].Projected; projected != nil {
+			for j := range projected.Sources {
+				if secret := projected.Sources[j].Secret; secret != nil {
+					rewrite(&secret.Name)
+				}
+			}
+		}
+	}
+	for i := range spec.ImagePullSecrets {
+		rewrite(&spec.ImagePullSecrets[i].Name)
+	}
+}
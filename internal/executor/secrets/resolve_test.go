@@ -0,0 +1,154 @@
+package secrets
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	armadaconfiguration "github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/executor/domain"
+)
+
+type fakeProvider struct {
+	secretsByQueueAndName map[string]map[string][]byte
+	err                   error
+}
+
+func (p *fakeProvider) GetSecret(_ *armadacontext.Context, queue string, name string) (map[string][]byte, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	data, ok := p.secretsByQueueAndName[queue+"/"+name]
+	if !ok {
+		return nil, &armadaerrors.ErrNotFound{Type: "secret", Value: name}
+	}
+	return data, nil
+}
+
+func TestResolve(t *testing.T) {
+	provider := &fakeProvider{
+		secretsByQueueAndName: map[string]map[string][]byte{
+			"queue/db-creds":  {"password": []byte("hunter2")},
+			"queue/api-token": {"token": []byte("abc")},
+		},
+	}
+	pod := makePod("db-creds, api-token")
+
+	secrets, err := Resolve(armadacontext.Background(), provider, pod)
+	require.NoError(t, err)
+	require.Len(t, secrets, 2)
+	assert.Equal(t, "armada-job-id-0-db-creds", secrets[0].Name)
+	assert.Equal(t, "namespace", secrets[0].Namespace)
+	assert.Equal(t, map[string]string{domain.JobId: "job-id", domain.Queue: "queue"}, secrets[0].Labels)
+	assert.Equal(t, map[string][]byte{"password": []byte("hunter2")}, secrets[0].Data)
+	assert.Equal(t, "armada-job-id-0-api-token", secrets[1].Name)
+
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, "armada-job-id-0-db-creds", container.Env[0].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "other", container.Env[1].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "armada-job-id-0-api-token", container.EnvFrom[0].SecretRef.Name)
+	assert.Equal(t, "armada-job-id-0-db-creds", pod.Spec.InitContainers[0].EnvFrom[0].SecretRef.Name)
+	assert.Equal(t, "armada-job-id-0-api-token", pod.Spec.Volumes[0].Secret.SecretName)
+	assert.Equal(t, "armada-job-id-0-db-creds", pod.Spec.Volumes[1].Projected.Sources[0].Secret.Name)
+}
+
+func TestResolve_NoReferences(t *testing.T) {
+	pod := makePod("")
+	delete(pod.Annotations, armadaconfiguration.SecretReferencesAnnotation)
+	expected := pod.DeepCopy()
+
+	secrets, err := Resolve(armadacontext.Background(), nil, pod)
+	assert.NoError(t, err)
+	assert.Empty(t, secrets)
+	assert.Equal(t, expected, pod)
+}
+
+func TestResolve_Errors(t *testing.T) {
+	tests := map[string]struct {
+		provider          Provider
+		expectRecoverable bool
+	}{
+		"no provider": {
+			provider:          nil,
+			expectRecoverable: false,
+		},
+		"secret not found": {
+			provider:          &fakeProvider{},
+			expectRecoverable: false,
+		},
+		"provider unavailable": {
+			provider:          &fakeProvider{err: fmt.Errorf("connection refused")},
+			expectRecoverable: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Resolve(armadacontext.Background(), tc.provider, makePod("db-creds"))
+			require.Error(t, err)
+			var createResourceErr *armadaerrors.ErrCreateResource
+			assert.Equal(t, tc.expectRecoverable, errors.As(err, &createResourceErr))
+		})
+	}
+}
+
+func makePod(secretRefs string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "armada-job-id-0",
+			Namespace:   "namespace",
+			Labels:      map[string]string{domain.JobId: "job-id", domain.Queue: "queue"},
+			Annotations: map[string]string{armadaconfiguration.SecretReferencesAnnotation: secretRefs},
+		},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{
+				{
+					Name:    "init",
+					EnvFrom: []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "db-creds"}}}},
+				},
+			},
+			Containers: []v1.Container{
+				{
+					Name: "main",
+					Env: []v1.EnvVar{
+						{
+							Name: "PASSWORD",
+							ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "db-creds"},
+								Key:                  "password",
+							}},
+						},
+						{
+							Name: "OTHER",
+							ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{
+								LocalObjectReference: v1.LocalObjectReference{Name: "other"},
+								Key:                  "other",
+							}},
+						},
+					},
+					EnvFrom: []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "api-token"}}}},
+				},
+			},
+			Volumes: []v1.Volume{
+				{
+					Name:         "token",
+					VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "api-token"}},
+				},
+				{
+					Name: "projected",
+					VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{
+						Sources: []v1.VolumeProjection{
+							{Secret: &v1.SecretProjection{LocalObjectReference: v1.LocalObjectReference{Name: "db-creds"}}},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
@@ -2,9 +2,13 @@ package service
 
 import (
 	"fmt"
+	"sort"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/sirupsen/logrus"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 
@@ -14,12 +18,20 @@ import (
 	util2 "github.com/armadaproject/armada/internal/common/util"
 	executorContext "github.com/armadaproject/armada/internal/executor/context"
 	"github.com/armadaproject/armada/internal/executor/job"
+	"github.com/armadaproject/armada/internal/executor/metrics"
 	"github.com/armadaproject/armada/internal/executor/reporter"
 	"github.com/armadaproject/armada/internal/executor/util"
 	"github.com/armadaproject/armada/internal/executor/utilisation"
 	"github.com/armadaproject/armada/pkg/api"
 )
 
+var throttledPodCreationsGauge = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: metrics.ArmadaExecutorMetricsPrefix + "throttled_pod_creations",
+		Help: "Number of leased jobs whose pods weren't created in the last submission round because of the pod creation rate limit",
+	},
+)
+
 type ClusterAllocator interface {
 	AllocateSpareClusterCapacity()
 }
@@ -30,6 +42,8 @@ type ClusterAllocationService struct {
 	submitter            job.Submitter
 	eventReporter        reporter.EventReporter
 	clusterHealthMonitor healthmonitor.HealthMonitor
+	// Limits the rate at which pods are created. May be nil, in which case pod creation isn't rate limited.
+	podCreationLimiter *rate.Limiter
 }
 
 func NewClusterAllocationService(
@@ -38,6 +52,7 @@ func NewClusterAllocationService(
 	jobRunStateManager job.RunStateStore,
 	submitter job.Submitter,
 	clusterHealthMonitor healthmonitor.HealthMonitor,
+	podCreationLimiter *rate.Limiter,
 ) *ClusterAllocationService {
 	return &ClusterAllocationService{
 		eventReporter:        eventReporter,
@@ -45,6 +60,7 @@ func NewClusterAllocationService(
 		submitter:            submitter,
 		jobRunStateStore:     jobRunStateManager,
 		clusterHealthMonitor: clusterHealthMonitor,
+		podCreationLimiter:   podCreationLimiter,
 	}
 }
 
@@ -64,6 +80,14 @@ func (allocationService *ClusterAllocationService) AllocateSpareClusterCapacity(
 	jobRuns := allocationService.jobRunStateStore.GetAllWithFilter(func(state *job.RunState) bool {
 		return state.Phase == job.Leased
 	})
+	// Submit jobs in the order they were leased, such that jobs leased together, e.g., the members of a gang,
+	// are submitted together where the pod creation rate limit allows.
+	sort.SliceStable(jobRuns, func(i, j int) bool {
+		if !jobRuns[i].LastPhaseTransitionTime.Equal(jobRuns[j].LastPhaseTransitionTime) {
+			return jobRuns[i].LastPhaseTransitionTime.Before(jobRuns[j].LastPhaseTransitionTime)
+		}
+		return jobRuns[i].Meta.RunId < jobRuns[j].Meta.RunId
+	})
 	jobs := make([]*job.SubmitJob, 0, len(jobRuns))
 	for _, run := range jobRuns {
 		if run.Job == nil {
@@ -74,6 +98,14 @@ func (allocationService *ClusterAllocationService) AllocateSpareClusterCapacity(
 		jobs = append(jobs, run.Job)
 	}
 
+	// Jobs not submitted due to the rate limit remain leased and are submitted in a later round.
+	numToSubmit := util.TakeTokens(allocationService.podCreationLimiter, len(jobs))
+	throttledPodCreationsGauge.Set(float64(len(jobs) - numToSubmit))
+	if numToSubmit < len(jobs) {
+		log.Infof("pod creation rate limit reached; deferring submission of %d of %d leased jobs", len(jobs)-numToSubmit, len(jobs))
+		jobs = jobs[:numToSubmit]
+	}
+
 	failedJobSubmissions := allocationService.submitter.SubmitJobs(jobs)
 	allocationService.processSuccessfulSubmissions(jobs, failedJobSubmissions)
 	allocationService.processFailedJobSubmissions(failedJobSubmissions)
@@ -3,8 +3,10 @@ package service
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
 
 	"github.com/armadaproject/armada/internal/common/healthmonitor"
 	fakecontext "github.com/armadaproject/armada/internal/executor/context/fake"
@@ -67,6 +69,30 @@ func TestAllocateSpareClusterCapacity_DoesNotSubmitJobs_WhenEtcdIsNotWithinSoftL
 	assert.Len(t, submitter.ReceivedSubmitJobs, 0)
 }
 
+func TestAllocateSpareClusterCapacity_RespectsPodCreationRateLimit(t *testing.T) {
+	now := time.Now()
+	initialRuns := make([]*job.RunState, 5)
+	for i := range initialRuns {
+		initialRuns[i] = createRun(fmt.Sprintf("leased-%d", i), job.Leased)
+		initialRuns[i].LastPhaseTransitionTime = now.Add(time.Duration(len(initialRuns)-i) * time.Second)
+	}
+	limiter := rate.NewLimiter(rate.Every(time.Hour), 2)
+	clusterAllocationService, _, _, submitter, runStore := setupClusterAllocationServiceTestWithLimiter(initialRuns, limiter)
+
+	clusterAllocationService.AllocateSpareClusterCapacity()
+
+	// The two runs leased first are submitted; the others stay leased until the limit allows submitting them.
+	assert.Len(t, submitter.ReceivedSubmitJobs, 2)
+	assert.ElementsMatch(t, []*job.SubmitJob{initialRuns[4].Job, initialRuns[3].Job}, submitter.ReceivedSubmitJobs)
+	for i, run := range initialRuns {
+		expectedPhase := job.Leased
+		if i >= 3 {
+			expectedPhase = job.SuccessfulSubmission
+		}
+		assert.Equal(t, expectedPhase, runStore.Get(run.Meta.RunId).Phase)
+	}
+}
+
 func TestAllocateSpareClusterCapacity_HandlesFailedPodCreations(t *testing.T) {
 	tests := map[string]struct {
 		recoverableSubmitFailure bool
@@ -139,6 +165,16 @@ func setupClusterAllocationServiceTest(initialJobRuns []*job.RunState) (
 	*mocks2.FakeEventReporter,
 	*mocks.FakeSubmitter,
 	*job.JobRunStateStore,
+) {
+	return setupClusterAllocationServiceTestWithLimiter(initialJobRuns, nil)
+}
+
+func setupClusterAllocationServiceTestWithLimiter(initialJobRuns []*job.RunState, podCreationLimiter *rate.Limiter) (
+	*ClusterAllocationService,
+	*healthmonitor.ManualHealthMonitor,
+	*mocks2.FakeEventReporter,
+	*mocks.FakeSubmitter,
+	*job.JobRunStateStore,
 ) {
 	clusterId := fakecontext.NewFakeClusterIdentity("cluster-1", "pool-1")
 	eventReporter := mocks2.NewFakeEventReporter()
@@ -153,6 +189,7 @@ func setupClusterAllocationServiceTest(initialJobRuns []*job.RunState) (
 		jobRunStateManager,
 		submitter,
 		healthMonitor,
+		podCreationLimiter,
 	), healthMonitor, eventReporter, submitter, jobRunStateManager
 }
 
@@ -39,6 +39,10 @@ type JobLeaseRequester struct {
 	executorApiClient executorapi.ExecutorApiClient
 	clusterIdentity   clusterContext.ClusterIdentity
 	minimumJobSize    armadaresource.ComputeResources
+	// Capabilities reported to the scheduler with each lease request.
+	kubernetesVersion string
+	runtimeClasses    []string
+	features          []string
 }
 
 func NewJobLeaseRequester(
@@ -53,6 +57,14 @@ func NewJobLeaseRequester(
 	}
 }
 
+// SetCapabilities sets the capabilities of the executor and its cluster reported to the scheduler,
+// which only schedules jobs onto this cluster that it can run.
+func (requester *JobLeaseRequester) SetCapabilities(kubernetesVersion string, runtimeClasses []string, features []string) {
+	requester.kubernetesVersion = kubernetesVersion
+	requester.runtimeClasses = runtimeClasses
+	requester.features = features
+}
+
 func (requester *JobLeaseRequester) LeaseJobRuns(ctx *armadacontext.Context, request *LeaseRequest) (*LeaseResponse, error) {
 	stream, err := requester.executorApiClient.LeaseJobRuns(ctx, grpcretry.Disable(), grpc.UseCompressor(gzip.Name))
 	if err != nil {
@@ -66,6 +78,9 @@ func (requester *JobLeaseRequester) LeaseJobRuns(ctx *armadacontext.Context, req
 		Nodes:               request.Nodes,
 		UnassignedJobRunIds: request.UnassignedJobRunIds,
 		MaxJobsToLease:      request.MaxJobsToLease,
+		KubernetesVersion:   requester.kubernetesVersion,
+		RuntimeClasses:      requester.runtimeClasses,
+		Features:            requester.features,
 	}
 	if err := stream.Send(leaseRequest); err != nil {
 		return nil, errors.WithStack(err)
@@ -113,9 +113,13 @@ func TestLeaseJobRuns_Send(t *testing.T) {
 		Nodes:               leaseRequest.Nodes,
 		UnassignedJobRunIds: leaseRequest.UnassignedJobRunIds,
 		MaxJobsToLease:      leaseRequest.MaxJobsToLease,
+		KubernetesVersion:   "v1.27.3",
+		RuntimeClasses:      []string{"gvisor"},
+		Features:            []string{"in-place-resize"},
 	}
 
 	jobRequester, mockExecutorApiClient, mockStream := setup(t)
+	jobRequester.SetCapabilities("v1.27.3", []string{"gvisor"}, []string{"in-place-resize"})
 	mockExecutorApiClient.EXPECT().LeaseJobRuns(gomock.Any(), gomock.Any(), gomock.Any()).Return(mockStream, nil)
 	mockStream.EXPECT().Send(expectedRequest).Return(nil)
 	mockStream.EXPECT().Recv().Return(endMarker, nil)
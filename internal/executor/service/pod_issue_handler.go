@@ -200,10 +200,11 @@ func (p *IssueHandler) detectPodIssues(allManagedPods []*v1.Pod) {
 			action, cause, podCheckMessage := p.pendingPodChecker.GetAction(pod, podEvents, p.clock.Now().Sub(lastStateChange))
 
 			if action != podchecks.ActionWait {
-				retryable := action == podchecks.ActionRetry
+				retryable := action == podchecks.ActionRetry || action == podchecks.ActionReturnLease
 				message := createStuckPodMessage(retryable, podCheckMessage)
 				podIssueType := StuckStartingUp
-				if cause == podchecks.NoNodeAssigned {
+				// Leases of runs unable to schedule are returned without the run counting as an attempt of the job.
+				if cause == podchecks.NoNodeAssigned || action == podchecks.ActionReturnLease {
 					podIssueType = UnableToSchedule
 				}
 
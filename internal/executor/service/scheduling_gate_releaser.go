@@ -0,0 +1,122 @@
+package service
+
+import (
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	armadaconfiguration "github.com/armadaproject/armada/internal/armada/configuration"
+	executorContext "github.com/armadaproject/armada/internal/executor/context"
+	"github.com/armadaproject/armada/internal/executor/util"
+)
+
+// SchedulingGateReleaser removes the scheduling gates of gang pods once their gang is complete,
+// i.e., once the pods of all members of the gang exist and the nodes they're assigned to are ready.
+//
+// Gang members are leased together, but their pods are created individually, and nodes may be removed
+// between a gang being leased and its pods being created. Gating the pods ensures Kubernetes binds either all
+// members of a gang or none of them; gangs that can't complete are left gated until the pending pod checks fail them.
+type SchedulingGateReleaser struct {
+	clusterContext executorContext.ClusterContext
+	nodeIdLabel    string
+	// Gangs with fewer members than their cardinality, but at least their minimum cardinality,
+	// are released once no further member has been created for this long.
+	timeout time.Duration
+	clock   clock.Clock
+}
+
+func NewSchedulingGateReleaser(
+	clusterContext executorContext.ClusterContext,
+	nodeIdLabel string,
+	timeout time.Duration,
+) *SchedulingGateReleaser {
+	return &SchedulingGateReleaser{
+		clusterContext: clusterContext,
+		nodeIdLabel:    nodeIdLabel,
+		timeout:        timeout,
+		clock:          clock.RealClock{},
+	}
+}
+
+func (r *SchedulingGateReleaser) ReleaseGates() {
+	pods, err := r.clusterContext.GetActiveBatchPods()
+	if err != nil {
+		log.Errorf("Failed to release scheduling gates because unable to get pods: %s", err)
+		return
+	}
+	nodes, err := r.clusterContext.GetNodes()
+	if err != nil {
+		log.Errorf("Failed to release scheduling gates because unable to get nodes: %s", err)
+		return
+	}
+
+	readyNodeIds := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if nodeId, ok := node.Labels[r.nodeIdLabel]; ok && util.IsReady(node) && !node.Spec.Unschedulable {
+			readyNodeIds[nodeId] = true
+		}
+	}
+
+	podsByGangId := make(map[string][]*v1.Pod)
+	for _, pod := range pods {
+		if util.IsMarkedForDeletion(pod) {
+			continue
+		}
+		if gangId, ok := pod.Annotations[armadaconfiguration.GangIdAnnotation]; ok {
+			podsByGangId[gangId] = append(podsByGangId[gangId], pod)
+		}
+	}
+
+	for gangId, gangPods := range podsByGangId {
+		gatedPods := util.FilterPods(gangPods, util.IsSchedulingGated)
+		if len(gatedPods) == 0 || !r.isGangComplete(gangPods) {
+			continue
+		}
+		if nodeId, ok := r.unreadyNodeId(gangPods, readyNodeIds); ok {
+			log.Warnf("Not releasing scheduling gates of gang %s because its node %s is not ready", gangId, nodeId)
+			continue
+		}
+		for _, pod := range gatedPods {
+			if err := r.clusterContext.ReleaseSchedulingGates(pod); err != nil {
+				log.Errorf("Failed to release scheduling gates of pod %s (%s) because %s", pod.Name, pod.Namespace, err)
+			}
+		}
+	}
+}
+
+// isGangComplete returns true if the pods of all members of the gang leased to this cluster exist.
+func (r *SchedulingGateReleaser) isGangComplete(gangPods []*v1.Pod) bool {
+	annotations := gangPods[0].Annotations
+	cardinality, err := strconv.Atoi(annotations[armadaconfiguration.GangCardinalityAnnotation])
+	if err != nil || len(gangPods) >= cardinality {
+		return true
+	}
+	minimumCardinality, err := strconv.Atoi(annotations[armadaconfiguration.GangMinimumCardinalityAnnotation])
+	if err != nil {
+		minimumCardinality = cardinality
+	}
+	if len(gangPods) < minimumCardinality {
+		return false
+	}
+	var lastCreated time.Time
+	for _, pod := range gangPods {
+		if pod.CreationTimestamp.Time.After(lastCreated) {
+			lastCreated = pod.CreationTimestamp.Time
+		}
+	}
+	return r.clock.Since(lastCreated) > r.timeout
+}
+
+// unreadyNodeId returns the id of a node some pod of the gang is assigned to that isn't ready, if any.
+func (r *SchedulingGateReleaser) unreadyNodeId(gangPods []*v1.Pod, readyNodeIds map[string]bool) (string, bool) {
+	for _, pod := range gangPods {
+		nodeId, ok := pod.Spec.NodeSelector[r.nodeIdLabel]
+		if ok && !readyNodeIds[nodeId] {
+			return nodeId, true
+		}
+	}
+	return "", false
+}
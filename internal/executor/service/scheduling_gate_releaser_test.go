@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	armadaconfiguration "github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/executor/context/fake"
+	"github.com/armadaproject/armada/internal/executor/domain"
+	"github.com/armadaproject/armada/internal/executor/util"
+)
+
+const testNodeIdLabel = "node-id"
+
+func TestSchedulingGateReleaser_ReleaseGates(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		pods            []*v1.Pod
+		nodes           []*v1.Node
+		expectReleased  bool
+		clockAdvancedBy time.Duration
+	}{
+		"complete gang": {
+			pods:           []*v1.Pod{makeGangPod("a", 2, 2, "node1", now), makeGangPod("b", 2, 2, "node2", now)},
+			nodes:          []*v1.Node{makeReadyNode("node1"), makeReadyNode("node2")},
+			expectReleased: true,
+		},
+		"incomplete gang": {
+			pods:           []*v1.Pod{makeGangPod("a", 2, 2, "node1", now)},
+			nodes:          []*v1.Node{makeReadyNode("node1"), makeReadyNode("node2")},
+			expectReleased: false,
+		},
+		"complete gang with missing node": {
+			pods:           []*v1.Pod{makeGangPod("a", 2, 2, "node1", now), makeGangPod("b", 2, 2, "node2", now)},
+			nodes:          []*v1.Node{makeReadyNode("node1")},
+			expectReleased: false,
+		},
+		"complete gang with unschedulable node": {
+			pods:  []*v1.Pod{makeGangPod("a", 2, 2, "node1", now), makeGangPod("b", 2, 2, "node2", now)},
+			nodes: []*v1.Node{makeReadyNode("node1"), func() *v1.Node { node := makeReadyNode("node2"); node.Spec.Unschedulable = true; return node }()},
+		},
+		"gang at minimum cardinality before timeout": {
+			pods:           []*v1.Pod{makeGangPod("a", 3, 2, "node1", now), makeGangPod("b", 3, 2, "node2", now)},
+			nodes:          []*v1.Node{makeReadyNode("node1"), makeReadyNode("node2")},
+			expectReleased: false,
+		},
+		"gang at minimum cardinality after timeout": {
+			pods:            []*v1.Pod{makeGangPod("a", 3, 2, "node1", now), makeGangPod("b", 3, 2, "node2", now)},
+			nodes:           []*v1.Node{makeReadyNode("node1"), makeReadyNode("node2")},
+			clockAdvancedBy: 2 * time.Minute,
+			expectReleased:  true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			clusterContext := fake.NewSyncFakeClusterContext()
+			for _, pod := range tc.pods {
+				clusterContext.Pods[pod.Labels[domain.JobId]] = pod
+			}
+			clusterContext.Nodes = tc.nodes
+			releaser := NewSchedulingGateReleaser(clusterContext, testNodeIdLabel, time.Minute)
+			releaser.clock = clock.NewFakeClock(now.Add(tc.clockAdvancedBy))
+
+			releaser.ReleaseGates()
+
+			for _, pod := range clusterContext.Pods {
+				assert.Equal(t, !tc.expectReleased, util.IsSchedulingGated(pod), pod.Name)
+			}
+		})
+	}
+}
+
+func makeGangPod(jobId string, cardinality int, minimumCardinality int, nodeId string, created time.Time) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "armada-" + jobId + "-0",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{
+				domain.JobId: jobId,
+			},
+			Annotations: map[string]string{
+				armadaconfiguration.GangIdAnnotation:                 "gang",
+				armadaconfiguration.GangCardinalityAnnotation:        fmt.Sprintf("%d", cardinality),
+				armadaconfiguration.GangMinimumCardinalityAnnotation: fmt.Sprintf("%d", minimumCardinality),
+				domain.SchedulingGated:                               "true",
+			},
+		},
+		Spec: v1.PodSpec{
+			NodeSelector: map[string]string{testNodeIdLabel: nodeId},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+		},
+	}
+}
+
+func makeReadyNode(nodeId string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   nodeId,
+			Labels: map[string]string{testNodeIdLabel: nodeId},
+		},
+		Status: v1.NodeStatus{
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+		},
+	}
+}
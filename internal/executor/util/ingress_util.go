@@ -7,6 +7,8 @@ import (
 	v1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
 
+	armadaconfiguration "github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common"
 	"github.com/armadaproject/armada/internal/common/util"
 	"github.com/armadaproject/armada/internal/executor/configuration"
 	"github.com/armadaproject/armada/pkg/api"
@@ -29,7 +31,7 @@ func GenerateIngresses(job *api.Job, pod *v1.Pod, ingressConfig *configuration.I
 					}
 					// TODO: This results in an invalid name (one starting with "-") if pod.Name is the empty string;
 					// we should return an error if that's the case.
-					ingressName := fmt.Sprintf("%s-%s-%d", pod.Name, strings.ToLower(svcType.String()), index)
+					ingressName := fmt.Sprintf("%s-%s-%d", ServiceNamePrefix(job, pod), strings.ToLower(svcType.String()), index)
 					ingress := CreateIngress(ingressName, job, pod, service, ingressConfig, config)
 					ingresses = append(ingresses, ingress)
 				}
@@ -40,6 +42,26 @@ func GenerateIngresses(job *api.Job, pod *v1.Pod, ingressConfig *configuration.I
 	return services, ingresses
 }
 
+// ServiceNamePrefix returns the prefix of the names of the services and ingresses created for the given pod of the job.
+// For gang leaders, the prefix is derived from the gang id rather than the pod name,
+// such that other jobs in the gang can address the leader by a name known before the gang is scheduled.
+func ServiceNamePrefix(job *api.Job, pod *v1.Pod) string {
+	if gangId, ok := job.Annotations[armadaconfiguration.GangIdAnnotation]; ok && IsGangLeader(job.Annotations) {
+		return GangServiceNamePrefix(gangId)
+	}
+	return pod.Name
+}
+
+// GangServiceNamePrefix returns the prefix of the names of the services and ingresses of the leader of the given gang.
+func GangServiceNamePrefix(gangId string) string {
+	return common.PodNamePrefix + "gang-" + gangId
+}
+
+// IsGangLeader returns true if the annotations mark a job, or the pod of a job, as the leader of its gang.
+func IsGangLeader(annotations map[string]string) bool {
+	return annotations[armadaconfiguration.GangLeaderAnnotation] == "true"
+}
+
 func groupIngressConfig(configs []*IngressServiceConfig) map[IngressServiceType][]*IngressServiceConfig {
 	result := gatherIngressConfig(configs)
 
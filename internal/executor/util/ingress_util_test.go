@@ -5,7 +5,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	armadaconfiguration "github.com/armadaproject/armada/internal/armada/configuration"
 	"github.com/armadaproject/armada/pkg/api"
 )
 
@@ -462,3 +464,31 @@ func TestCombineIngressService(t *testing.T) {
 
 	assert.Equal(t, expected, CombineIngressService(ingress, services))
 }
+
+func TestServiceNamePrefix(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "armada-job-0"}}
+	tests := map[string]struct {
+		annotations map[string]string
+		expected    string
+	}{
+		"non-gang job": {
+			expected: "armada-job-0",
+		},
+		"gang member": {
+			annotations: map[string]string{armadaconfiguration.GangIdAnnotation: "foo"},
+			expected:    "armada-job-0",
+		},
+		"gang leader": {
+			annotations: map[string]string{
+				armadaconfiguration.GangIdAnnotation:     "foo",
+				armadaconfiguration.GangLeaderAnnotation: "true",
+			},
+			expected: "armada-gang-foo",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ServiceNamePrefix(&api.Job{Annotations: tc.annotations}, pod))
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	armadaconfiguration "github.com/armadaproject/armada/internal/armada/configuration"
 	"github.com/armadaproject/armada/internal/common"
 	"github.com/armadaproject/armada/internal/common/util"
 	"github.com/armadaproject/armada/internal/executor/configuration"
@@ -56,7 +57,7 @@ func CreateService(
 	})
 	service := &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        fmt.Sprintf("%s-%s", pod.Name, strings.ToLower(ingSvcType.String())),
+			Name:        fmt.Sprintf("%s-%s", ServiceNamePrefix(job, pod), strings.ToLower(ingSvcType.String())),
 			Labels:      labels,
 			Annotations: annotation,
 			Namespace:   job.Namespace,
@@ -94,7 +95,7 @@ func CreateIngress(
 		if !contains(jobConfig, uint32(servicePort.Port)) {
 			continue
 		}
-		host := fmt.Sprintf("%s-%s.%s.%s", servicePort.Name, pod.Name, pod.Namespace, executorIngressConfig.HostnameSuffix)
+		host := fmt.Sprintf("%s-%s.%s.%s", servicePort.Name, ServiceNamePrefix(job, pod), pod.Namespace, executorIngressConfig.HostnameSuffix)
 		tlsHosts = append(tlsHosts, host)
 
 		// Workaround to get constant's address
@@ -262,6 +263,8 @@ func CreatePodFromExecutorApiJob(job *executorapi.JobRunLease, defaults *configu
 	})
 
 	applyDefaults(podSpec, defaults)
+	applyGangSchedulingGate(annotation, defaults)
+	applyOutputCapture(podSpec, annotation)
 	setRestartPolicyNever(podSpec)
 
 	pod := &v1.Pod{
@@ -302,6 +305,8 @@ func CreatePod(job *api.Job, defaults *configuration.PodDefaults) *v1.Pod {
 		domain.Owner:    job.Owner,
 	})
 
+	applyGangSchedulingGate(annotation, defaults)
+	applyOutputCapture(podSpec, annotation)
 	setRestartPolicyNever(podSpec)
 
 	pod := &v1.Pod{
@@ -324,6 +329,59 @@ func applyDefaults(spec *v1.PodSpec, defaults *configuration.PodDefaults) {
 	if defaults.SchedulerName != "" && spec.SchedulerName == "" {
 		spec.SchedulerName = defaults.SchedulerName
 	}
+	if priorityClassName, ok := defaults.PriorityClassNames[spec.PriorityClassName]; ok {
+		spec.PriorityClassName = priorityClassName
+		// The priority admission controller rejects pods with a priority inconsistent with their priority class;
+		// clear it so that it's populated from the mapped priority class instead.
+		spec.Priority = nil
+	}
+}
+
+// applyGangSchedulingGate marks pods of gangs with more than one member to be created with the gang scheduling gate,
+// if gang scheduling gates are enabled.
+func applyGangSchedulingGate(annotations map[string]string, defaults *configuration.PodDefaults) {
+	if defaults == nil || !defaults.GangSchedulingGates {
+		return
+	}
+	if _, ok := annotations[armadaconfiguration.GangIdAnnotation]; !ok {
+		return
+	}
+	if cardinality, err := strconv.Atoi(annotations[armadaconfiguration.GangCardinalityAnnotation]); err != nil || cardinality <= 1 {
+		return
+	}
+	annotations[domain.SchedulingGated] = "true"
+}
+
+// OutputCaptureFilePath returns the path of the file to capture for jobs that request output capture of a file,
+// as opposed to their logs, via armadaconfiguration.CaptureOutputAnnotation.
+func OutputCaptureFilePath(annotations map[string]string) (string, bool) {
+	value, ok := annotations[armadaconfiguration.CaptureOutputAnnotation]
+	if !ok || value == armadaconfiguration.CaptureOutputLogs {
+		return "", false
+	}
+	return value, true
+}
+
+// SecretReferences returns the names of the secrets a job references via armadaconfiguration.SecretReferencesAnnotation.
+func SecretReferences(annotations map[string]string) []string {
+	value, ok := annotations[armadaconfiguration.SecretReferencesAnnotation]
+	if !ok {
+		return nil
+	}
+	refs := strings.Split(value, ",")
+	for i, ref := range refs {
+		refs[i] = strings.TrimSpace(ref)
+	}
+	return refs
+}
+
+// applyOutputCapture makes the first container report the file to capture as its termination message,
+// such that it can be read from the container status once the container has exited.
+func applyOutputCapture(spec *v1.PodSpec, annotations map[string]string) {
+	if filePath, ok := OutputCaptureFilePath(annotations); ok && len(spec.Containers) > 0 {
+		spec.Containers[0].TerminationMessagePath = filePath
+		spec.Containers[0].TerminationMessagePolicy = v1.TerminationMessageReadFile
+	}
 }
 
 func setRestartPolicyNever(podSpec *v1.PodSpec) {
@@ -10,6 +10,7 @@ import (
 	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	armadaconfiguration "github.com/armadaproject/armada/internal/armada/configuration"
 	"github.com/armadaproject/armada/internal/common"
 	"github.com/armadaproject/armada/internal/executor/configuration"
 	"github.com/armadaproject/armada/internal/executor/domain"
@@ -123,6 +124,72 @@ func TestApplyDefaults_DoesNotOverrideExistingValues(t *testing.T) {
 	assert.Equal(t, podSpecOriginal, podSpec)
 }
 
+func TestApplyDefaults_PriorityClassNames(t *testing.T) {
+	priority := int32(1000)
+	defaults := &configuration.PodDefaults{PriorityClassNames: map[string]string{"armada-preemptible": "preemptible"}}
+
+	podSpec := makePodSpec()
+	podSpec.PriorityClassName = "armada-preemptible"
+	podSpec.Priority = &priority
+	expected := podSpec.DeepCopy()
+	expected.PriorityClassName = "preemptible"
+	expected.Priority = nil
+	applyDefaults(podSpec, defaults)
+	assert.Equal(t, expected, podSpec)
+
+	podSpec = makePodSpec()
+	podSpec.PriorityClassName = "armada-default"
+	podSpec.Priority = &priority
+	expected = podSpec.DeepCopy()
+	applyDefaults(podSpec, defaults)
+	assert.Equal(t, expected, podSpec)
+}
+
+func TestApplyGangSchedulingGate(t *testing.T) {
+	gangAnnotations := func(cardinality string) map[string]string {
+		return map[string]string{
+			armadaconfiguration.GangIdAnnotation:          "gang",
+			armadaconfiguration.GangCardinalityAnnotation: cardinality,
+		}
+	}
+	enabled := &configuration.PodDefaults{GangSchedulingGates: true}
+
+	annotations := gangAnnotations("2")
+	applyGangSchedulingGate(annotations, enabled)
+	assert.Equal(t, "true", annotations[domain.SchedulingGated])
+
+	annotations = gangAnnotations("2")
+	applyGangSchedulingGate(annotations, &configuration.PodDefaults{})
+	assert.NotContains(t, annotations, domain.SchedulingGated)
+
+	annotations = gangAnnotations("1")
+	applyGangSchedulingGate(annotations, enabled)
+	assert.NotContains(t, annotations, domain.SchedulingGated)
+
+	annotations = map[string]string{}
+	applyGangSchedulingGate(annotations, enabled)
+	assert.NotContains(t, annotations, domain.SchedulingGated)
+}
+
+func TestApplyOutputCapture(t *testing.T) {
+	podSpec := makePodSpec()
+	expected := podSpec.DeepCopy()
+	expected.Containers[0].TerminationMessagePath = "/tmp/output.json"
+	expected.Containers[0].TerminationMessagePolicy = v1.TerminationMessageReadFile
+	applyOutputCapture(podSpec, map[string]string{armadaconfiguration.CaptureOutputAnnotation: "/tmp/output.json"})
+	assert.Equal(t, expected, podSpec)
+
+	podSpec = makePodSpec()
+	expected = podSpec.DeepCopy()
+	applyOutputCapture(podSpec, map[string]string{armadaconfiguration.CaptureOutputAnnotation: armadaconfiguration.CaptureOutputLogs})
+	assert.Equal(t, expected, podSpec)
+
+	podSpec = makePodSpec()
+	expected = podSpec.DeepCopy()
+	applyOutputCapture(podSpec, nil)
+	assert.Equal(t, expected, podSpec)
+}
+
 func makePodSpec() *v1.PodSpec {
 	containers := make([]v1.Container, 1)
 	containers[0] = v1.Container{
@@ -268,6 +268,11 @@ func IsMarkedForDeletion(pod *v1.Pod) bool {
 	return exists
 }
 
+// IsSchedulingGated returns true if the pod was created with the gang scheduling gate and the gate hasn't been removed.
+func IsSchedulingGated(pod *v1.Pod) bool {
+	return pod.Annotations[domain.SchedulingGated] == "true"
+}
+
 func IsReportedDone(pod *v1.Pod) bool {
 	_, exists := pod.Annotations[domain.JobDoneAnnotation]
 	return exists
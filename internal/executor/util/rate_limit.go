@@ -0,0 +1,26 @@
+package util
+
+import "golang.org/x/time/rate"
+
+// NewPodRateLimiter returns a token bucket rate limiter allowing limit pod operations per second with bursts of up to burst operations,
+// or nil if limit isn't positive, which indicates operations aren't rate limited.
+func NewPodRateLimiter(limit float64, burst int) *rate.Limiter {
+	if limit <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(limit), burst)
+}
+
+// TakeTokens consumes up to n tokens from limiter, one at a time, and returns the number of tokens consumed.
+// A nil limiter allows all n.
+func TakeTokens(limiter *rate.Limiter, n int) int {
+	if limiter == nil {
+		return n
+	}
+	for i := 0; i < n; i++ {
+		if !limiter.Allow() {
+			return i
+		}
+	}
+	return n
+}
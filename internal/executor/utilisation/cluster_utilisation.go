@@ -32,6 +32,8 @@ type ClusterUtilisationService struct {
 	nodeIdLabel                                                   string
 	minimumResourcesMarkedAllocatedToNonArmadaPodsPerNode         armadaresource.ComputeResources
 	minimumResourcesMarkedAllocatedToNonArmadaPodsPerNodePriority int32
+	// If not nil, the hardware attributes of each node are reported as node labels in addition to the tracked labels.
+	hardwareProber *node.HardwareProber
 }
 
 func NewClusterUtilisationService(
@@ -56,6 +58,10 @@ func NewClusterUtilisationService(
 	}
 }
 
+func (clusterUtilisationService *ClusterUtilisationService) SetHardwareProber(hardwareProber *node.HardwareProber) {
+	clusterUtilisationService.hardwareProber = hardwareProber
+}
+
 type NodeGroupAllocationInfo struct {
 	NodeType                     *api.NodeTypeIdentifier
 	Nodes                        []*v1.Node
@@ -177,7 +183,7 @@ func (cls *ClusterUtilisationService) GetAvailableClusterCapacity(legacy bool) (
 		}
 		nodes = append(nodes, api.NodeInfo{
 			Name:                        node.Name,
-			Labels:                      cls.filterTrackedLabels(node.Labels),
+			Labels:                      cls.nodeLabels(node),
 			Taints:                      node.Spec.Taints,
 			AllocatableResources:        allocatable,
 			AvailableResources:          available,
@@ -454,6 +460,17 @@ func (clusterUtilisationService *ClusterUtilisationService) getTotalPodUtilisati
 	return totalUtilisation
 }
 
+// nodeLabels returns the labels of node reported to the server, i.e., its tracked labels and its hardware attributes, if probed.
+func (clusterUtilisationService *ClusterUtilisationService) nodeLabels(node *v1.Node) map[string]string {
+	labels := clusterUtilisationService.filterTrackedLabels(node.Labels)
+	if clusterUtilisationService.hardwareProber != nil {
+		for k, v := range clusterUtilisationService.hardwareProber.Probe(node) {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
 func (clusterUtilisationService *ClusterUtilisationService) filterTrackedLabels(labels map[string]string) map[string]string {
 	result := map[string]string{}
 	for _, k := range clusterUtilisationService.trackedNodeLabels {
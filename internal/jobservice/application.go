@@ -107,12 +107,15 @@ func (a *App) StartUp(ctx context.Context, config *configuration.JobServiceConfi
 	}
 
 	log := log.WithField("JobService", "Startup")
-	grpcServer := grpcCommon.CreateGrpcServer(
+	grpcServer, err := grpcCommon.CreateGrpcServer(
 		config.Grpc.KeepaliveParams,
 		config.Grpc.KeepaliveEnforcementPolicy,
 		[]authorization.AuthService{&authorization.AnonymousAuthService{}},
 		config.Grpc.Tls,
 	)
+	if err != nil {
+		return err
+	}
 
 	err, sqlJobRepo, dbCallbackFn := repository.NewSQLJobService(config, log)
 	if err != nil {
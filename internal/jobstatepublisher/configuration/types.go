@@ -0,0 +1,25 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+)
+
+type JobStatePublisherConfiguration struct {
+	// Metrics configuration
+	Metrics configuration.MetricsConfig
+	// General Pulsar configuration
+	Pulsar configuration.PulsarConfig
+	// Pulsar subscription name
+	SubscriptionName string
+	// Compacted topic the current state of each job is published to, keyed by job id.
+	// Compaction must be enabled for this topic, e.g., via a compaction threshold set with pulsar-admin.
+	JobStateTopic string
+	// Number of messages that will be batched together before being published
+	BatchSize int
+	// Maximum time since the last batch before a batch will be published
+	BatchDuration time.Duration
+	// If non-nil, net/http/pprof endpoints are exposed on localhost on this port.
+	PprofPort *uint16
+}
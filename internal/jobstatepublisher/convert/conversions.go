@@ -0,0 +1,126 @@
+package convert
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/ingest"
+	"github.com/armadaproject/armada/internal/common/ingest/metrics"
+	"github.com/armadaproject/armada/internal/common/jobstate"
+	"github.com/armadaproject/armada/internal/jobstatepublisher/model"
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+// EventConverter derives job state transitions from event sequences.
+type EventConverter struct {
+	metrics      *metrics.Metrics
+	stateMachine *jobstate.StateMachine
+}
+
+func NewEventConverter(metrics *metrics.Metrics) ingest.InstructionConverter[*model.BatchUpdate] {
+	return &EventConverter{
+		metrics:      metrics,
+		stateMachine: jobstate.NewStateMachine("jobstatepublisher"),
+	}
+}
+
+// Convert returns the state of each job that transitioned in the provided event sequences.
+// Only the latest state of each job is returned, since earlier states would be compacted away regardless.
+func (ec *EventConverter) Convert(_ *armadacontext.Context, sequencesWithIds *ingest.EventSequencesWithIds) *model.BatchUpdate {
+	updatesByJobId := make(map[string]*model.JobStateUpdate)
+	jobIds := make([]string, 0)
+	for _, es := range sequencesWithIds.EventSequences {
+		for _, event := range es.Events {
+			update, err := ec.convertEvent(event)
+			if err != nil {
+				ec.metrics.RecordPulsarMessageError(metrics.PulsarMessageErrorProcessing)
+				log.WithError(err).Warnf("Could not convert event for job set %s in queue %s", es.JobSetName, es.Queue)
+				continue
+			}
+			if update == nil {
+				continue
+			}
+			update.Queue = es.Queue
+			update.JobSet = es.JobSetName
+			if previous, ok := updatesByJobId[update.JobId]; !ok {
+				jobIds = append(jobIds, update.JobId)
+			} else if err := ec.stateMachine.Transition(update.JobId, previous.State, update.State); err != nil {
+				// Publishing the update would make the published state diverge from the true state of the job.
+				log.WithError(err).Warnf("Discarding state update for job set %s in queue %s", es.JobSetName, es.Queue)
+				continue
+			}
+			updatesByJobId[update.JobId] = update
+		}
+	}
+	updates := make([]*model.JobStateUpdate, len(jobIds))
+	for i, jobId := range jobIds {
+		updates[i] = updatesByJobId[jobId]
+	}
+	return &model.BatchUpdate{
+		MessageIds: sequencesWithIds.MessageIds,
+		Updates:    updates,
+	}
+}
+
+// convertEvent returns the job state the provided event transitions a job into,
+// or nil if the event doesn't change the state of a job.
+func (ec *EventConverter) convertEvent(event *armadaevents.EventSequence_Event) (*model.JobStateUpdate, error) {
+	state, ok := jobstate.ForEvent(event)
+	if !ok {
+		return nil, nil
+	}
+	var protoJobId, protoRunId *armadaevents.Uuid
+	executor := ""
+	switch e := event.Event.(type) {
+	case *armadaevents.EventSequence_Event_SubmitJob:
+		protoJobId = e.SubmitJob.JobId
+	case *armadaevents.EventSequence_Event_JobRequeued:
+		protoJobId = e.JobRequeued.JobId
+	case *armadaevents.EventSequence_Event_JobRunLeased:
+		protoJobId = e.JobRunLeased.JobId
+		protoRunId = e.JobRunLeased.RunId
+		executor = e.JobRunLeased.ExecutorId
+	case *armadaevents.EventSequence_Event_JobRunAssigned:
+		protoJobId = e.JobRunAssigned.JobId
+		protoRunId = e.JobRunAssigned.RunId
+	case *armadaevents.EventSequence_Event_JobRunRunning:
+		protoJobId = e.JobRunRunning.JobId
+		protoRunId = e.JobRunRunning.RunId
+	case *armadaevents.EventSequence_Event_JobSucceeded:
+		protoJobId = e.JobSucceeded.JobId
+	case *armadaevents.EventSequence_Event_CancelledJob:
+		protoJobId = e.CancelledJob.JobId
+	case *armadaevents.EventSequence_Event_JobRunPreempted:
+		protoJobId = e.JobRunPreempted.PreemptedJobId
+		protoRunId = e.JobRunPreempted.PreemptedRunId
+	case *armadaevents.EventSequence_Event_JobErrors:
+		protoJobId = e.JobErrors.JobId
+	default:
+		return nil, errors.Errorf("no job id for event of type %T", event.Event)
+	}
+	jobId, err := armadaevents.UlidStringFromProtoUuid(protoJobId)
+	if err != nil {
+		return nil, err
+	}
+	runId := ""
+	if protoRunId != nil {
+		runId, err = armadaevents.UuidStringFromProtoUuid(protoRunId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var ts time.Time
+	if event.Created != nil {
+		ts = *event.Created
+	}
+	return &model.JobStateUpdate{
+		JobId:              jobId,
+		State:              state,
+		RunId:              runId,
+		Executor:           executor,
+		LastTransitionTime: ts,
+	}, nil
+}
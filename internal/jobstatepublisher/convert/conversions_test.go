@@ -0,0 +1,192 @@
+package convert
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/ingest"
+	"github.com/armadaproject/armada/internal/common/pulsarutils"
+	"github.com/armadaproject/armada/internal/jobstatepublisher/metrics"
+	"github.com/armadaproject/armada/internal/jobstatepublisher/model"
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+const (
+	jobset       = "testJobset"
+	queue        = "testQueue"
+	executor     = "testExecutor"
+	jobIdString  = "01f3j0g1md4qx7z5qb148qnh4r"
+	job2IdString = "01f3j0g1md4qx7z5qb148qnh4s"
+	runIdString  = "123e4567-e89b-12d3-a456-426614174000"
+)
+
+var (
+	jobIdProto, _  = armadaevents.ProtoUuidFromUlidString(jobIdString)
+	job2IdProto, _ = armadaevents.ProtoUuidFromUlidString(job2IdString)
+	runIdProto     = armadaevents.ProtoUuidFromUuid(uuid.MustParse(runIdString))
+	baseTime, _    = time.Parse("2006-01-02T15:04:05.000Z", "2022-03-01T15:04:05.000Z")
+)
+
+var submitted = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_SubmitJob{
+		SubmitJob: &armadaevents.SubmitJob{
+			JobId: jobIdProto,
+		},
+	},
+}
+
+var submitted2 = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_SubmitJob{
+		SubmitJob: &armadaevents.SubmitJob{
+			JobId: job2IdProto,
+		},
+	},
+}
+
+var leased = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_JobRunLeased{
+		JobRunLeased: &armadaevents.JobRunLeased{
+			JobId:      jobIdProto,
+			RunId:      runIdProto,
+			ExecutorId: executor,
+		},
+	},
+}
+
+var running = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_JobRunRunning{
+		JobRunRunning: &armadaevents.JobRunRunning{
+			JobId: jobIdProto,
+			RunId: runIdProto,
+		},
+	},
+}
+
+var succeeded = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_JobSucceeded{
+		JobSucceeded: &armadaevents.JobSucceeded{
+			JobId: jobIdProto,
+		},
+	},
+}
+
+var preemptedError = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_JobErrors{
+		JobErrors: &armadaevents.JobErrors{
+			JobId: jobIdProto,
+			Errors: []*armadaevents.Error{
+				{
+					Terminal: true,
+					Reason: &armadaevents.Error_JobRunPreemptedError{
+						JobRunPreemptedError: &armadaevents.JobRunPreemptedError{},
+					},
+				},
+			},
+		},
+	},
+}
+
+var nonTerminalError = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_JobErrors{
+		JobErrors: &armadaevents.JobErrors{
+			JobId: jobIdProto,
+			Errors: []*armadaevents.Error{
+				{
+					Terminal: false,
+					Reason: &armadaevents.Error_PodError{
+						PodError: &armadaevents.PodError{Message: "oom"},
+					},
+				},
+			},
+		},
+	},
+}
+
+var reprioritised = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_ReprioritisedJob{
+		ReprioritisedJob: &armadaevents.ReprioritisedJob{
+			JobId: jobIdProto,
+		},
+	},
+}
+
+func TestConvert(t *testing.T) {
+	tests := map[string]struct {
+		events   []*armadaevents.EventSequence_Event
+		expected []*model.JobStateUpdate
+	}{
+		"submitted": {
+			events:   []*armadaevents.EventSequence_Event{submitted},
+			expected: []*model.JobStateUpdate{expectedUpdate(jobIdString, model.JobQueued, "", "")},
+		},
+		"leased": {
+			events:   []*armadaevents.EventSequence_Event{leased},
+			expected: []*model.JobStateUpdate{expectedUpdate(jobIdString, model.JobLeased, runIdString, executor)},
+		},
+		"only the latest state of each job is published": {
+			events: []*armadaevents.EventSequence_Event{submitted, submitted2, leased, running, succeeded},
+			expected: []*model.JobStateUpdate{
+				expectedUpdate(jobIdString, model.JobSucceeded, "", ""),
+				expectedUpdate(job2IdString, model.JobQueued, "", ""),
+			},
+		},
+		"preempted": {
+			events:   []*armadaevents.EventSequence_Event{running, preemptedError},
+			expected: []*model.JobStateUpdate{expectedUpdate(jobIdString, model.JobPreempted, "", "")},
+		},
+		"invalid transitions are discarded": {
+			events:   []*armadaevents.EventSequence_Event{submitted, running, succeeded, running},
+			expected: []*model.JobStateUpdate{expectedUpdate(jobIdString, model.JobSucceeded, "", "")},
+		},
+		"events that don't change the state are ignored": {
+			events:   []*armadaevents.EventSequence_Event{reprioritised, nonTerminalError},
+			expected: []*model.JobStateUpdate{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			msg := NewMsg(tc.events...)
+			batchUpdate := NewEventConverter(metrics.Get()).Convert(armadacontext.Background(), msg)
+			assert.Equal(t, msg.MessageIds, batchUpdate.MessageIds)
+			assert.Equal(t, tc.expected, batchUpdate.Updates)
+		})
+	}
+}
+
+func expectedUpdate(jobId string, state model.JobState, runId string, executor string) *model.JobStateUpdate {
+	return &model.JobStateUpdate{
+		JobId:              jobId,
+		Queue:              queue,
+		JobSet:             jobset,
+		State:              state,
+		RunId:              runId,
+		Executor:           executor,
+		LastTransitionTime: baseTime,
+	}
+}
+
+func NewMsg(event ...*armadaevents.EventSequence_Event) *ingest.EventSequencesWithIds {
+	seq := &armadaevents.EventSequence{
+		Queue:      queue,
+		JobSetName: jobset,
+		Events:     event,
+	}
+	return &ingest.EventSequencesWithIds{
+		EventSequences: []*armadaevents.EventSequence{seq},
+		MessageIds:     []pulsar.MessageID{pulsarutils.NewMessageId(rand.Int())},
+	}
+}
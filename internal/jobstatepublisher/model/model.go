@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+
+	"github.com/armadaproject/armada/internal/common/jobstate"
+)
+
+// JobState is the state of a job, as defined by the shared job state machine.
+type JobState = jobstate.State
+
+const (
+	JobQueued    = jobstate.Queued
+	JobLeased    = jobstate.Leased
+	JobPending   = jobstate.Pending
+	JobRunning   = jobstate.Running
+	JobSucceeded = jobstate.Succeeded
+	JobFailed    = jobstate.Failed
+	JobCancelled = jobstate.Cancelled
+	JobPreempted = jobstate.Preempted
+)
+
+// JobStateUpdate is the current state of a job, as published to the compacted job state topic keyed by job id.
+// Since compaction only retains the latest message per job, each message contains everything known about the job;
+// fields only known for some states, e.g., the executor a job is leased to, are empty for other states.
+type JobStateUpdate struct {
+	JobId  string   `json:"jobId"`
+	Queue  string   `json:"queue"`
+	JobSet string   `json:"jobSet"`
+	State  JobState `json:"state"`
+	// Id of the current run of the job, if any.
+	RunId    string `json:"runId,omitempty"`
+	Executor string `json:"executor,omitempty"`
+	// Time of the event that caused the job to transition into its current state.
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+// BatchUpdate represents the job state updates to publish
+// along with information about the originating pulsar messages.
+type BatchUpdate struct {
+	MessageIds []pulsar.MessageID
+	Updates    []*JobStateUpdate
+}
+
+func (b *BatchUpdate) GetMessageIDs() []pulsar.MessageID {
+	return b.MessageIds
+}
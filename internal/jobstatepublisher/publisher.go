@@ -0,0 +1,75 @@
+package jobstatepublisher
+
+import (
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/armadaproject/armada/internal/common/app"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/ingest"
+	"github.com/armadaproject/armada/internal/common/logging"
+	"github.com/armadaproject/armada/internal/common/profiling"
+	"github.com/armadaproject/armada/internal/common/pulsarutils"
+	"github.com/armadaproject/armada/internal/common/serve"
+	"github.com/armadaproject/armada/internal/jobstatepublisher/configuration"
+	"github.com/armadaproject/armada/internal/jobstatepublisher/convert"
+	"github.com/armadaproject/armada/internal/jobstatepublisher/metrics"
+	"github.com/armadaproject/armada/internal/jobstatepublisher/model"
+	"github.com/armadaproject/armada/internal/jobstatepublisher/sink"
+)
+
+// Run will create a pipeline that will take Armada event messages from Pulsar and publish the resulting
+// state of each job to the compacted job state topic. This pipeline will run until a SIGTERM is received
+func Run(config *configuration.JobStatePublisherConfiguration) {
+	log.Info("Job State Publisher Starting")
+
+	// Expose profiling endpoints if enabled.
+	pprofServer := profiling.SetupPprofHttpServer(config.PprofPort)
+	go func() {
+		ctx := armadacontext.Background()
+		if err := serve.ListenAndServe(ctx, pprofServer); err != nil {
+			logging.WithStacktrace(ctx, err).Error("pprof server failure")
+		}
+	}()
+
+	metrics := metrics.Get()
+
+	pulsarClient, err := pulsarutils.NewPulsarClient(&config.Pulsar)
+	if err != nil {
+		panic(errors.WithMessage(err, "Error creating pulsar client"))
+	}
+	defer pulsarClient.Close()
+	producer, err := pulsarClient.CreateProducer(pulsar.ProducerOptions{
+		Name:             "armada-job-state-publisher",
+		Topic:            config.JobStateTopic,
+		CompressionType:  config.Pulsar.CompressionType,
+		CompressionLevel: config.Pulsar.CompressionLevel,
+		Encryption:       pulsarutils.ProducerEncryption(&config.Pulsar),
+	})
+	if err != nil {
+		panic(errors.WithMessage(err, "Error creating pulsar producer"))
+	}
+	defer producer.Close()
+
+	converter := convert.NewEventConverter(metrics)
+	pulsarSink := sink.NewPulsarSink(producer, config.Pulsar.BackoffTime, 60*time.Second)
+
+	// Events of a job set must be processed in order, such that the latest state of each job is published last.
+	ingester := ingest.NewIngestionPipeline[*model.BatchUpdate](
+		config.Pulsar,
+		config.SubscriptionName,
+		config.BatchSize,
+		config.BatchDuration,
+		pulsar.KeyShared,
+		converter,
+		pulsarSink,
+		config.Metrics,
+		metrics,
+	)
+	if err := ingester.Run(app.CreateContextWithShutdown()); err != nil {
+		panic(errors.WithMessage(err, "Error running ingestion pipeline"))
+	}
+}
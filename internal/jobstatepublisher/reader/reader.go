@@ -0,0 +1,51 @@
+package reader
+
+import (
+	"encoding/json"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/jobstatepublisher/model"
+)
+
+// ReadCurrentStates bootstraps a consumer from the compacted job state topic, i.e., it reads the current state of
+// each job and calls fn for each of them, which is much faster than replaying the event stream.
+//
+// The job state topic lags the event stream slightly. Hence, consumers should subscribe to the event stream before
+// bootstrapping and then apply events from the subscription on top of the bootstrapped state, discarding any
+// transitions older than the LastTransitionTime of the bootstrapped state of the job.
+func ReadCurrentStates(ctx *armadacontext.Context, client pulsar.Client, topic string, fn func(update *model.JobStateUpdate) error) error {
+	reader, err := client.CreateReader(pulsar.ReaderOptions{
+		Topic:          topic,
+		StartMessageID: pulsar.EarliestMessageID(),
+		ReadCompacted:  true,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer reader.Close()
+	return readAll(ctx, reader, fn)
+}
+
+func readAll(ctx *armadacontext.Context, reader pulsar.Reader, fn func(update *model.JobStateUpdate) error) error {
+	for reader.HasNext() {
+		msg, err := reader.Next(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		// Messages with an empty payload are tombstones, i.e., the job has been removed from the topic.
+		if len(msg.Payload()) == 0 {
+			continue
+		}
+		update := &model.JobStateUpdate{}
+		if err := json.Unmarshal(msg.Payload(), update); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal job state of message %s", msg.ID())
+		}
+		if err := fn(update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/ingest"
+	"github.com/armadaproject/armada/internal/jobstatepublisher/model"
+)
+
+// PulsarSink publishes job state updates to a compacted topic, keyed by job id,
+// such that compaction retains only the current state of each job.
+type PulsarSink struct {
+	producer       pulsar.Producer
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+func NewPulsarSink(producer pulsar.Producer, initialBackoff time.Duration, maxBackoff time.Duration) *PulsarSink {
+	return &PulsarSink{
+		producer:       producer,
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+	}
+}
+
+// Store publishes all updates in the batch, retrying until they've all been received by Pulsar or ctx is cancelled.
+// Publishing an update more than once is harmless, since only the latest message for each job is retained.
+func (s *PulsarSink) Store(ctx *armadacontext.Context, update *model.BatchUpdate) error {
+	payloads := make([][]byte, len(update.Updates))
+	for i, u := range update.Updates {
+		payload, err := json.Marshal(u)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		payloads[i] = payload
+	}
+	return ingest.WithRetry(func() (bool, error) {
+		if ctx.Err() != nil {
+			// The pipeline stops without acking messages on context.DeadlineExceeded,
+			// such that unpublished updates are published again after a restart.
+			return false, errors.WithMessage(context.DeadlineExceeded, "shutting down before job state updates were published")
+		}
+		err := s.publish(ctx, update.Updates, payloads)
+		return true, err
+	}, s.initialBackoff, s.maxBackoff)
+}
+
+func (s *PulsarSink) publish(ctx *armadacontext.Context, updates []*model.JobStateUpdate, payloads [][]byte) error {
+	ch := make(chan error, len(updates))
+	for i, u := range updates {
+		s.producer.SendAsync(
+			ctx,
+			&pulsar.ProducerMessage{
+				Payload: payloads[i],
+				Key:     u.JobId,
+			},
+			func(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
+				ch <- err
+			},
+		)
+	}
+	var result *multierror.Error
+	for range updates {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-ch:
+			result = multierror.Append(result, err)
+		}
+	}
+	return result.ErrorOrNil()
+}
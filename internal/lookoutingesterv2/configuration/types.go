@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/compress"
+	"github.com/armadaproject/armada/internal/common/ingest"
 )
 
 type LookoutIngesterV2Configuration struct {
@@ -17,6 +19,9 @@ type LookoutIngesterV2Configuration struct {
 	SubscriptionName string
 	// Size in bytes above which job specs will be compressed when inserting in the database
 	MinJobSpecCompressionSize int
+	// Codec used to compress job specs when inserting in the database; one of zlib, zstd, lz4, or snappy.
+	// Readers detect the codec of each job spec, so this may be changed without migrating job specs already stored.
+	JobSpecCompressionCodec compress.Codec
 	// Number of messages that will be batched together before being inserted into the database
 	BatchSize int
 	// Maximum time since the last batch before a batch will be inserted into the database
@@ -37,6 +42,8 @@ type LookoutIngesterV2Configuration struct {
 	// If the ingester should process events using the legacy event conversion logic
 	// The two schedulers produce slightly different events - so need to be processed differently
 	UseLegacyEventConversion bool
+	// Rules for dropping redundant events, e.g., repeated pending updates of flapping pods, before they're stored.
+	EventCompaction ingest.EventCompactionConfig
 	// If non-nil, net/http/pprof endpoints are exposed on localhost on this port.
 	PprofPort *uint16
 }
@@ -31,7 +31,7 @@ func Run(config *configuration.LookoutIngesterV2Configuration) {
 	}
 	lookoutDb := lookoutdb.NewLookoutDb(db, m, config.MaxAttempts, config.MaxBackoff)
 
-	compressor, err := compress.NewZlibCompressor(config.MinJobSpecCompressionSize)
+	compressor, err := compress.NewCompressor(config.JobSpecCompressionCodec, config.MinJobSpecCompressionSize)
 	if err != nil {
 		panic(errors.WithMessage(err, "Error creating compressor"))
 	}
@@ -58,6 +58,9 @@ func Run(config *configuration.LookoutIngesterV2Configuration) {
 		config.Metrics,
 		m,
 	)
+	if err := ingester.EnableEventCompaction(config.EventCompaction); err != nil {
+		panic(errors.WithMessage(err, "Error configuring event compaction"))
+	}
 
 	if err := ingester.Run(app.CreateContextWithShutdown()); err != nil {
 		panic(errors.WithMessage(err, "Error running ingestion pipeline"))
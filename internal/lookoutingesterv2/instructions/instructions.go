@@ -125,10 +125,11 @@ func (c *InstructionConverter) convertSequence(
 			if !c.useLegacyEventConversion {
 				err = c.handleJobRunLeased(ts, event.GetJobRunLeased(), update)
 			}
+		case *armadaevents.EventSequence_Event_ResourceUtilisation:
+			err = c.handleResourceUtilisation(ts, event.GetResourceUtilisation(), update)
 		case *armadaevents.EventSequence_Event_ReprioritiseJobSet:
 		case *armadaevents.EventSequence_Event_CancelJob:
 		case *armadaevents.EventSequence_Event_CancelJobSet:
-		case *armadaevents.EventSequence_Event_ResourceUtilisation:
 		case *armadaevents.EventSequence_Event_StandaloneIngressInfo:
 		case *armadaevents.EventSequence_Event_PartitionMarker:
 			log.Debugf("Ignoring event type %T", event.GetEvent())
@@ -419,12 +420,14 @@ func (c *InstructionConverter) handleJobRunLeased(ts time.Time, event *armadaeve
 	update.JobsToUpdate = append(update.JobsToUpdate, &job)
 	// Now create a job run
 	jobRun := model.CreateJobRunInstruction{
-		RunId:       runId,
-		JobId:       jobId,
-		Cluster:     event.ExecutorId,
-		Node:        pointer.String(event.NodeId),
-		Leased:      &ts,
-		JobRunState: lookout.JobRunLeasedOrdinal,
+		RunId:           runId,
+		JobId:           jobId,
+		Cluster:         event.ExecutorId,
+		Node:            pointer.String(event.NodeId),
+		Leased:          &ts,
+		JobRunState:     lookout.JobRunLeasedOrdinal,
+		JobIngested:     event.GetJobIngested(),
+		FirstConsidered: event.GetFirstConsidered(),
 	}
 	update.JobRunsToCreate = append(update.JobRunsToCreate, &jobRun)
 	return nil
@@ -519,6 +522,32 @@ func (c *InstructionConverter) handleJobRunSucceeded(ts time.Time, event *armada
 	return nil
 }
 
+func (c *InstructionConverter) handleResourceUtilisation(ts time.Time, event *armadaevents.ResourceUtilisation, update *model.InstructionSet) error {
+	jobId, err := armadaevents.UlidStringFromProtoUuid(event.GetJobId())
+	if err != nil {
+		c.metrics.RecordPulsarMessageError(metrics.PulsarMessageErrorProcessing)
+		return errors.WithStack(err)
+	}
+	runId, err := armadaevents.UuidStringFromProtoUuid(event.GetRunId())
+	if err != nil {
+		c.metrics.RecordPulsarMessageError(metrics.PulsarMessageErrorProcessing)
+		return errors.WithStack(err)
+	}
+	jobRunUsage := model.UpsertJobRunUsageInstruction{
+		RunId:   runId,
+		JobId:   jobId,
+		Updated: ts,
+	}
+	if cpu, ok := event.MaxResourcesForPeriod[string(v1.ResourceCPU)]; ok {
+		jobRunUsage.MaxCpu = cpu.MilliValue()
+	}
+	if memory, ok := event.MaxResourcesForPeriod[string(v1.ResourceMemory)]; ok {
+		jobRunUsage.MaxMemory = memory.Value()
+	}
+	update.JobRunUsagesToUpsert = append(update.JobRunUsagesToUpsert, &jobRunUsage)
+	return nil
+}
+
 func (c *InstructionConverter) handleJobRunErrors(ts time.Time, event *armadaevents.JobRunErrors, update *model.InstructionSet) error {
 	jobId, err := armadaevents.UlidStringFromProtoUuid(event.GetJobId())
 	if err != nil {
@@ -616,24 +645,55 @@ func (c *InstructionConverter) handleJobRunPreempted(ts time.Time, event *armada
 	update.JobsToUpdate = append(update.JobsToUpdate, &job)
 
 	// Update job run
-	errorString := "preempted by non armada pod"
-	preemptiveJobId, err := parseUlidString(event.PreemptiveJobId)
-	if err != nil {
-		log.WithError(err).Debug("failed to convert preemptive job id")
-	} else {
-		errorString = fmt.Sprintf("preempted by job %s", preemptiveJobId)
-	}
-
 	jobRun := model.UpdateJobRunInstruction{
 		RunId:       runId,
 		JobRunState: pointer.Int32(lookout.JobRunPreemptedOrdinal),
 		Finished:    &ts,
-		Error:       tryCompressError(jobId, errorString, c.compressor),
+		Error:       tryCompressError(jobId, preemptionErrorString(event), c.compressor),
 	}
 	update.JobRunsToUpdate = append(update.JobRunsToUpdate, &jobRun)
 	return nil
 }
 
+// preemptionErrorString returns a human-readable explanation of why a job run was preempted.
+func preemptionErrorString(event *armadaevents.JobRunPreempted) string {
+	var sb strings.Builder
+	preemptiveJobId, err := parseUlidString(event.PreemptiveJobId)
+	if err != nil {
+		log.WithError(err).Debug("failed to convert preemptive job id")
+		if event.Cause == "" {
+			return "preempted by non armada pod"
+		}
+		sb.WriteString("preempted")
+	} else {
+		fmt.Fprintf(&sb, "preempted by job %s", preemptiveJobId)
+		if event.PreemptiveGangId != "" {
+			fmt.Fprintf(&sb, " of gang %s", event.PreemptiveGangId)
+		}
+		if event.PreemptiveQueue != "" {
+			fmt.Fprintf(&sb, " in queue %s", event.PreemptiveQueue)
+		}
+		if event.PreemptivePriorityClass != "" {
+			fmt.Fprintf(&sb, " with priority class %s", event.PreemptivePriorityClass)
+		}
+	}
+	switch event.Cause {
+	case "FairShare":
+		sb.WriteString(" to balance resource usage across queues")
+	case "NodeOversubscribed":
+		sb.WriteString(" since its node was oversubscribed")
+	case "NodeDrained":
+		sb.WriteString(" since its executor was drained")
+	}
+	if event.QueueCompensated {
+		sb.WriteString("; its queue is below its fair share and is prioritised in subsequent scheduling rounds")
+	}
+	if event.ExpectedRequeuePosition > 0 {
+		fmt.Fprintf(&sb, "; its queue is considered for scheduling in position %d", event.ExpectedRequeuePosition)
+	}
+	return sb.String()
+}
+
 func parseUlidString(id *armadaevents.Uuid) (string, error) {
 	if id == nil {
 		return "", errors.New("uuid is nil")
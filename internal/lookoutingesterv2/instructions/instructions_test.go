@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
 	"github.com/golang/protobuf/proto"
@@ -209,15 +210,48 @@ func TestConvert(t *testing.T) {
 	preempted.GetJobRunPreempted().PreemptiveJobId = otherJobIdProto
 	preempted.GetJobRunPreempted().PreemptiveRunId = otherRunIdProto
 
+	preemptedWithDetails, err := testfixtures.DeepCopy(preempted)
+	assert.NoError(t, err)
+	preemptedWithDetails.GetJobRunPreempted().Cause = "FairShare"
+	preemptedWithDetails.GetJobRunPreempted().PreemptiveQueue = "other-queue"
+	preemptedWithDetails.GetJobRunPreempted().PreemptivePriorityClass = "armada-default"
+	preemptedWithDetails.GetJobRunPreempted().PreemptiveGangId = "gang"
+	preemptedWithDetails.GetJobRunPreempted().ExpectedRequeuePosition = 2
+	preemptedWithDetails.GetJobRunPreempted().QueueCompensated = true
+
 	preemptedWithPrempteeWithZeroId, err := testfixtures.DeepCopy(testfixtures.JobPreempted)
 	assert.NoError(t, err)
 	preemptedWithPrempteeWithZeroId.GetJobRunPreempted().PreemptiveJobId = &armadaevents.Uuid{}
 	preemptedWithPrempteeWithZeroId.GetJobRunPreempted().PreemptiveRunId = &armadaevents.Uuid{}
 
+	resourceUtilisation := &armadaevents.EventSequence_Event{
+		Created: &testfixtures.BaseTime,
+		Event: &armadaevents.EventSequence_Event_ResourceUtilisation{
+			ResourceUtilisation: &armadaevents.ResourceUtilisation{
+				RunId: testfixtures.RunIdProto,
+				JobId: testfixtures.JobIdProto,
+				MaxResourcesForPeriod: map[string]resource.Quantity{
+					"cpu":    resource.MustParse("1500m"),
+					"memory": resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}
+
 	cancelledWithReason, err := testfixtures.DeepCopy(testfixtures.JobCancelled)
 	assert.NoError(t, err)
 	cancelledWithReason.GetCancelledJob().Reason = "some reason"
 
+	ingested := testfixtures.BaseTime.Add(-2 * time.Minute)
+	firstConsidered := testfixtures.BaseTime.Add(-time.Minute)
+	leasedWithLatencies, err := testfixtures.DeepCopy(testfixtures.Leased)
+	assert.NoError(t, err)
+	leasedWithLatencies.GetJobRunLeased().JobIngested = &ingested
+	leasedWithLatencies.GetJobRunLeased().FirstConsidered = &firstConsidered
+	expectedLeasedRunWithLatencies := expectedLeasedRun
+	expectedLeasedRunWithLatencies.JobIngested = &ingested
+	expectedLeasedRunWithLatencies.FirstConsidered = &firstConsidered
+
 	tests := map[string]struct {
 		events                   *ingest.EventSequencesWithIds
 		expected                 *model.InstructionSet
@@ -344,6 +378,18 @@ func TestConvert(t *testing.T) {
 			},
 			useLegacyEventConversion: true,
 		},
+		"leased with latencies": {
+			events: &ingest.EventSequencesWithIds{
+				EventSequences: []*armadaevents.EventSequence{testfixtures.NewEventSequence(leasedWithLatencies)},
+				MessageIds:     []pulsar.MessageID{pulsarutils.NewMessageId(1)},
+			},
+			expected: &model.InstructionSet{
+				JobsToUpdate:    []*model.UpdateJobInstruction{&expectedLeased},
+				JobRunsToCreate: []*model.CreateJobRunInstruction{&expectedLeasedRunWithLatencies},
+				MessageIds:      []pulsar.MessageID{pulsarutils.NewMessageId(1)},
+			},
+			useLegacyEventConversion: false,
+		},
 		"requeued": {
 			events: &ingest.EventSequencesWithIds{
 				EventSequences: []*armadaevents.EventSequence{testfixtures.NewEventSequence(testfixtures.JobRequeued)},
@@ -355,6 +401,23 @@ func TestConvert(t *testing.T) {
 			},
 			useLegacyEventConversion: false,
 		},
+		"resource utilisation": {
+			events: &ingest.EventSequencesWithIds{
+				EventSequences: []*armadaevents.EventSequence{testfixtures.NewEventSequence(resourceUtilisation)},
+				MessageIds:     []pulsar.MessageID{pulsarutils.NewMessageId(1)},
+			},
+			expected: &model.InstructionSet{
+				JobRunUsagesToUpsert: []*model.UpsertJobRunUsageInstruction{{
+					RunId:     testfixtures.RunIdString,
+					JobId:     testfixtures.JobIdString,
+					MaxCpu:    1500,
+					MaxMemory: 1024 * 1024 * 1024,
+					Updated:   testfixtures.BaseTime,
+				}},
+				MessageIds: []pulsar.MessageID{pulsarutils.NewMessageId(1)},
+			},
+			useLegacyEventConversion: false,
+		},
 		"cancelled": {
 			events: &ingest.EventSequencesWithIds{
 				EventSequences: []*armadaevents.EventSequence{testfixtures.NewEventSequence(testfixtures.JobCancelled)},
@@ -477,6 +540,27 @@ func TestConvert(t *testing.T) {
 			},
 			useLegacyEventConversion: true,
 		},
+		"preempted with details": {
+			events: &ingest.EventSequencesWithIds{
+				EventSequences: []*armadaevents.EventSequence{testfixtures.NewEventSequence(preemptedWithDetails)},
+				MessageIds:     []pulsar.MessageID{pulsarutils.NewMessageId(1)},
+			},
+			expected: &model.InstructionSet{
+				JobsToUpdate: []*model.UpdateJobInstruction{&expectedPreempted},
+				JobRunsToUpdate: []*model.UpdateJobRunInstruction{{
+					RunId:       testfixtures.RunIdString,
+					Finished:    &testfixtures.BaseTime,
+					JobRunState: pointer.Int32(lookout.JobRunPreemptedOrdinal),
+					Error: []byte(fmt.Sprintf(
+						"preempted by job %s of gang gang in queue other-queue with priority class armada-default to balance resource usage across queues; "+
+							"its queue is below its fair share and is prioritised in subsequent scheduling rounds; its queue is considered for scheduling in position 2",
+						otherJobId,
+					)),
+				}},
+				MessageIds: []pulsar.MessageID{pulsarutils.NewMessageId(1)},
+			},
+			useLegacyEventConversion: true,
+		},
 		"preempted with zeroed preemptee id": {
 			events: &ingest.EventSequencesWithIds{
 				EventSequences: []*armadaevents.EventSequence{testfixtures.NewEventSequence(preemptedWithPrempteeWithZeroId)},
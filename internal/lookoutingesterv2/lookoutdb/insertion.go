@@ -32,7 +32,7 @@ func NewLookoutDb(db *pgxpool.Pool, metrics *metrics.Metrics, maxAttempts int, m
 // Store updates the lookout database according to the supplied InstructionSet.
 // The updates are applied in the following order:
 // * New Job Creations
-// * Job Updates, New Job Creations, New User Annotations
+// * Job Updates, New Job Creations, New User Annotations, Job Run Usages
 // * Job Run Updates
 // In each case we first try to bach insert the rows using the postgres copy protocol.  If this fails then we try a
 // slower, serial insert and discard any rows that cannot be inserted.
@@ -41,13 +41,14 @@ func (l *LookoutDb) Store(ctx *armadacontext.Context, instructions *model.Instru
 	// These can be conflated to help performance
 	jobsToUpdate := conflateJobUpdates(instructions.JobsToUpdate)
 	jobRunsToUpdate := conflateJobRunUpdates(instructions.JobRunsToUpdate)
+	jobRunUsagesToUpsert := conflateJobRunUsages(instructions.JobRunUsagesToUpsert)
 
 	// Jobs need to be ingested first as other updates may reference these
 	l.CreateJobs(ctx, instructions.JobsToCreate)
 
 	// Now we can job updates, annotations and new job runs
 	wg := sync.WaitGroup{}
-	wg.Add(3)
+	wg.Add(4)
 	go func() {
 		defer wg.Done()
 		l.UpdateJobs(ctx, jobsToUpdate)
@@ -60,6 +61,10 @@ func (l *LookoutDb) Store(ctx *armadacontext.Context, instructions *model.Instru
 		defer wg.Done()
 		l.CreateUserAnnotations(ctx, instructions.UserAnnotationsToCreate)
 	}()
+	go func() {
+		defer wg.Done()
+		l.UpsertJobRunUsages(ctx, jobRunUsagesToUpsert)
+	}()
 
 	wg.Wait()
 
@@ -124,6 +129,17 @@ func (l *LookoutDb) CreateUserAnnotations(ctx *armadacontext.Context, instructio
 	}
 }
 
+func (l *LookoutDb) UpsertJobRunUsages(ctx *armadacontext.Context, instructions []*model.UpsertJobRunUsageInstruction) {
+	if len(instructions) == 0 {
+		return
+	}
+	err := l.UpsertJobRunUsagesBatch(ctx, instructions)
+	if err != nil {
+		log.WithError(err).Warn("Upserting job run usages via batch failed, will attempt to insert serially (this might be slow).")
+		l.UpsertJobRunUsagesScalar(ctx, instructions)
+	}
+}
+
 func (l *LookoutDb) CreateJobsBatch(ctx *armadacontext.Context, instructions []*model.CreateJobInstruction) error {
 	return l.withDatabaseRetryInsert(func() error {
 		tmpTable := database.UniqueTableName("job")
@@ -400,13 +416,15 @@ func (l *LookoutDb) CreateJobRunsBatch(ctx *armadacontext.Context, instructions
 		createTmp := func(tx pgx.Tx) error {
 			_, err := tx.Exec(ctx, fmt.Sprintf(`
 				CREATE TEMPORARY TABLE  %s (
-					run_id        varchar(36),
-					job_id        varchar(32),
-					cluster       varchar(512),
-					node          varchar(512),
-					leased        timestamp,
-					pending       timestamp,
-					job_run_state smallint
+					run_id           varchar(36),
+					job_id           varchar(32),
+					cluster          varchar(512),
+					node             varchar(512),
+					leased           timestamp,
+					pending          timestamp,
+					job_run_state    smallint,
+					job_ingested     timestamp,
+					first_considered timestamp
 				) ON COMMIT DROP;`, tmpTable))
 			if err != nil {
 				l.metrics.RecordDBError(metrics.DBOperationCreateTempTable)
@@ -425,6 +443,8 @@ func (l *LookoutDb) CreateJobRunsBatch(ctx *armadacontext.Context, instructions
 					"leased",
 					"pending",
 					"job_run_state",
+					"job_ingested",
+					"first_considered",
 				},
 				pgx.CopyFromSlice(len(instructions), func(i int) ([]interface{}, error) {
 					return []interface{}{
@@ -435,6 +455,8 @@ func (l *LookoutDb) CreateJobRunsBatch(ctx *armadacontext.Context, instructions
 						instructions[i].Leased,
 						instructions[i].Pending,
 						instructions[i].JobRunState,
+						instructions[i].JobIngested,
+						instructions[i].FirstConsidered,
 					}, nil
 				}),
 			)
@@ -452,7 +474,9 @@ func (l *LookoutDb) CreateJobRunsBatch(ctx *armadacontext.Context, instructions
 						node,
 						leased,
 						pending,
-						job_run_state
+						job_run_state,
+						job_ingested,
+						first_considered
 					) SELECT * from %s
 					ON CONFLICT DO NOTHING`, tmpTable))
 			if err != nil {
@@ -472,8 +496,10 @@ func (l *LookoutDb) CreateJobRunsScalar(ctx *armadacontext.Context, instructions
 			node,
 			leased,
 			pending,
-			job_run_state)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+			job_run_state,
+			job_ingested,
+			first_considered)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		ON CONFLICT DO NOTHING`
 	for _, i := range instructions {
 		err := l.withDatabaseRetryInsert(func() error {
@@ -484,7 +510,9 @@ func (l *LookoutDb) CreateJobRunsScalar(ctx *armadacontext.Context, instructions
 				i.Node,
 				i.Leased,
 				i.Pending,
-				i.JobRunState)
+				i.JobRunState,
+				i.JobIngested,
+				i.FirstConsidered)
 			if err != nil {
 				l.metrics.RecordDBError(metrics.DBOperationInsert)
 			}
@@ -696,6 +724,104 @@ func (l *LookoutDb) CreateUserAnnotationsScalar(ctx *armadacontext.Context, inst
 	}
 }
 
+// UpsertJobRunUsagesBatch requires that instructions contain at most one instruction per run.
+func (l *LookoutDb) UpsertJobRunUsagesBatch(ctx *armadacontext.Context, instructions []*model.UpsertJobRunUsageInstruction) error {
+	return l.withDatabaseRetryInsert(func() error {
+		tmpTable := database.UniqueTableName("job_run_usage")
+
+		createTmp := func(tx pgx.Tx) error {
+			_, err := tx.Exec(ctx, fmt.Sprintf(`
+				CREATE TEMPORARY TABLE %s (
+					run_id     varchar(36),
+					job_id     varchar(32),
+					max_cpu    bigint,
+					max_memory bigint,
+					updated    timestamp
+				) ON COMMIT DROP;`, tmpTable))
+			if err != nil {
+				l.metrics.RecordDBError(metrics.DBOperationCreateTempTable)
+			}
+			return err
+		}
+
+		insertTmp := func(tx pgx.Tx) error {
+			_, err := tx.CopyFrom(ctx,
+				pgx.Identifier{tmpTable},
+				[]string{
+					"run_id",
+					"job_id",
+					"max_cpu",
+					"max_memory",
+					"updated",
+				},
+				pgx.CopyFromSlice(len(instructions), func(i int) ([]interface{}, error) {
+					return []interface{}{
+						instructions[i].RunId,
+						instructions[i].JobId,
+						instructions[i].MaxCpu,
+						instructions[i].MaxMemory,
+						instructions[i].Updated,
+					}, nil
+				}),
+			)
+			return err
+		}
+
+		copyToDest := func(tx pgx.Tx) error {
+			_, err := tx.Exec(
+				ctx,
+				fmt.Sprintf(`
+					INSERT INTO job_run_usage (
+						run_id,
+						job_id,
+						max_cpu,
+						max_memory,
+						updated
+					) SELECT * from %s
+					ON CONFLICT (run_id) DO UPDATE SET
+						max_cpu = GREATEST(job_run_usage.max_cpu, EXCLUDED.max_cpu),
+						max_memory = GREATEST(job_run_usage.max_memory, EXCLUDED.max_memory),
+						updated = GREATEST(job_run_usage.updated, EXCLUDED.updated)`, tmpTable))
+			if err != nil {
+				l.metrics.RecordDBError(metrics.DBOperationInsert)
+			}
+			return err
+		}
+		return batchInsert(ctx, l.db, createTmp, insertTmp, copyToDest)
+	})
+}
+
+func (l *LookoutDb) UpsertJobRunUsagesScalar(ctx *armadacontext.Context, instructions []*model.UpsertJobRunUsageInstruction) {
+	sqlStatement := `INSERT INTO job_run_usage (
+			run_id,
+			job_id,
+			max_cpu,
+			max_memory,
+			updated)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (run_id) DO UPDATE SET
+			max_cpu = GREATEST(job_run_usage.max_cpu, EXCLUDED.max_cpu),
+			max_memory = GREATEST(job_run_usage.max_memory, EXCLUDED.max_memory),
+			updated = GREATEST(job_run_usage.updated, EXCLUDED.updated)`
+	for _, i := range instructions {
+		err := l.withDatabaseRetryInsert(func() error {
+			_, err := l.db.Exec(ctx, sqlStatement,
+				i.RunId,
+				i.JobId,
+				i.MaxCpu,
+				i.MaxMemory,
+				i.Updated)
+			if err != nil {
+				l.metrics.RecordDBError(metrics.DBOperationInsert)
+			}
+			return err
+		})
+		if err != nil {
+			log.WithError(err).Warnf("Upsert job run usage for run %s failed", i.RunId)
+		}
+	}
+}
+
 func batchInsert(ctx *armadacontext.Context, db *pgxpool.Pool, createTmp func(pgx.Tx) error,
 	insertTmp func(pgx.Tx) error, copyToDest func(pgx.Tx) error,
 ) error {
@@ -819,6 +945,30 @@ func conflateJobRunUpdates(updates []*model.UpdateJobRunInstruction) []*model.Up
 	return conflated
 }
 
+func conflateJobRunUsages(usages []*model.UpsertJobRunUsageInstruction) []*model.UpsertJobRunUsageInstruction {
+	usagesById := make(map[string]*model.UpsertJobRunUsageInstruction)
+	conflated := make([]*model.UpsertJobRunUsageInstruction, 0, len(usages))
+	for _, usage := range usages {
+		existing, ok := usagesById[usage.RunId]
+		if !ok {
+			usage := *usage
+			usagesById[usage.RunId] = &usage
+			conflated = append(conflated, &usage)
+			continue
+		}
+		if usage.MaxCpu > existing.MaxCpu {
+			existing.MaxCpu = usage.MaxCpu
+		}
+		if usage.MaxMemory > existing.MaxMemory {
+			existing.MaxMemory = usage.MaxMemory
+		}
+		if usage.Updated.After(existing.Updated) {
+			existing.Updated = usage.Updated
+		}
+	}
+	return conflated
+}
+
 // updateInstructionsForJob is used in filterEventsForTerminalJobs, and records a list of job updates for a single job,
 // along with whether it contains an instruction corresponding to a JobPreempted event
 type updateInstructionsForJob struct {
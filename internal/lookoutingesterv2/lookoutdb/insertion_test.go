@@ -87,6 +87,14 @@ type JobRunRow struct {
 	ExitCode    *int32
 }
 
+type JobRunUsageRow struct {
+	RunId     string
+	JobId     string
+	MaxCpu    int64
+	MaxMemory int64
+	Updated   time.Time
+}
+
 type UserAnnotationRow struct {
 	JobId  string
 	Key    string
@@ -624,6 +632,55 @@ func TestCreateUserAnnotationsBatch(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestUpsertJobRunUsagesBatch(t *testing.T) {
+	err := lookout.WithLookoutDb(func(db *pgxpool.Pool) error {
+		ldb := NewLookoutDb(db, m, 2, 10)
+		err := ldb.UpsertJobRunUsagesBatch(armadacontext.Background(), []*model.UpsertJobRunUsageInstruction{
+			{RunId: runIdString, JobId: jobIdString, MaxCpu: 1000, MaxMemory: 2048, Updated: baseTime},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, JobRunUsageRow{RunId: runIdString, JobId: jobIdString, MaxCpu: 1000, MaxMemory: 2048, Updated: baseTime}, getJobRunUsage(t, db, runIdString))
+
+		// The maximum of the recorded and new usage is stored.
+		err = ldb.UpsertJobRunUsagesBatch(armadacontext.Background(), []*model.UpsertJobRunUsageInstruction{
+			{RunId: runIdString, JobId: jobIdString, MaxCpu: 500, MaxMemory: 4096, Updated: updateTime},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, JobRunUsageRow{RunId: runIdString, JobId: jobIdString, MaxCpu: 1000, MaxMemory: 4096, Updated: updateTime}, getJobRunUsage(t, db, runIdString))
+
+		// If a row is bad then we should return an error and no updates should happen
+		_, err = ldb.db.Exec(armadacontext.Background(), "DELETE FROM job_run_usage")
+		assert.NoError(t, err)
+		err = ldb.UpsertJobRunUsagesBatch(armadacontext.Background(), []*model.UpsertJobRunUsageInstruction{
+			{RunId: runIdString, JobId: jobIdString, Updated: baseTime},
+			{RunId: invalidId, JobId: jobIdString, Updated: baseTime},
+		})
+		assert.Error(t, err)
+		assertNoRows(t, ldb.db, "job_run_usage")
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestUpsertJobRunUsagesScalar(t *testing.T) {
+	err := lookout.WithLookoutDb(func(db *pgxpool.Pool) error {
+		ldb := NewLookoutDb(db, m, 2, 10)
+		// If a row is bad then we should update the rows we can
+		ldb.UpsertJobRunUsagesScalar(armadacontext.Background(), []*model.UpsertJobRunUsageInstruction{
+			{RunId: runIdString, JobId: jobIdString, MaxCpu: 1000, MaxMemory: 2048, Updated: baseTime},
+			{RunId: invalidId, JobId: jobIdString, Updated: baseTime},
+		})
+		assert.Equal(t, JobRunUsageRow{RunId: runIdString, JobId: jobIdString, MaxCpu: 1000, MaxMemory: 2048, Updated: baseTime}, getJobRunUsage(t, db, runIdString))
+
+		ldb.UpsertJobRunUsagesScalar(armadacontext.Background(), []*model.UpsertJobRunUsageInstruction{
+			{RunId: runIdString, JobId: jobIdString, MaxCpu: 2000, MaxMemory: 1024, Updated: updateTime},
+		})
+		assert.Equal(t, JobRunUsageRow{RunId: runIdString, JobId: jobIdString, MaxCpu: 2000, MaxMemory: 2048, Updated: updateTime}, getJobRunUsage(t, db, runIdString))
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
 func TestStoreWithEmptyInstructionSet(t *testing.T) {
 	err := lookout.WithLookoutDb(func(db *pgxpool.Pool) error {
 		ldb := NewLookoutDb(db, m, 2, 10)
@@ -833,6 +890,18 @@ func TestConflateJobRunUpdates(t *testing.T) {
 	assert.Equal(t, expected, updates)
 }
 
+func TestConflateJobRunUsages(t *testing.T) {
+	usages := conflateJobRunUsages([]*model.UpsertJobRunUsageInstruction{
+		{RunId: runIdString, JobId: jobIdString, MaxCpu: 1000, MaxMemory: 2048, Updated: baseTime},
+		{RunId: "other-run", JobId: jobIdString, MaxCpu: 1, MaxMemory: 1, Updated: baseTime},
+		{RunId: runIdString, JobId: jobIdString, MaxCpu: 500, MaxMemory: 4096, Updated: updateTime},
+	})
+	assert.Equal(t, []*model.UpsertJobRunUsageInstruction{
+		{RunId: runIdString, JobId: jobIdString, MaxCpu: 1000, MaxMemory: 4096, Updated: updateTime},
+		{RunId: "other-run", JobId: jobIdString, MaxCpu: 1, MaxMemory: 1, Updated: baseTime},
+	}, usages)
+}
+
 func TestStoreNullValue(t *testing.T) {
 	err := lookout.WithLookoutDb(func(db *pgxpool.Pool) error {
 		jobProto := []byte("hello \000 world \000")
@@ -1033,6 +1102,17 @@ func getUserAnnotationLookup(t *testing.T, db *pgxpool.Pool, jobId string) UserA
 	return annotation
 }
 
+func getJobRunUsage(t *testing.T, db *pgxpool.Pool, runId string) JobRunUsageRow {
+	usage := JobRunUsageRow{}
+	r := db.QueryRow(
+		armadacontext.Background(),
+		`SELECT run_id, job_id, max_cpu, max_memory, updated FROM job_run_usage WHERE run_id = $1`,
+		runId)
+	err := r.Scan(&usage.RunId, &usage.JobId, &usage.MaxCpu, &usage.MaxMemory, &usage.Updated)
+	assert.NoError(t, err)
+	return usage
+}
+
 func assertNoRows(t *testing.T, db *pgxpool.Pool, table string) {
 	t.Helper()
 	var count int
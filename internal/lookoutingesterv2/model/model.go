@@ -56,6 +56,9 @@ type CreateJobRunInstruction struct {
 	Leased      *time.Time
 	Pending     *time.Time
 	JobRunState int32
+	// Time at which the job was ingested by the scheduler, and first considered for scheduling before this run was leased.
+	JobIngested     *time.Time
+	FirstConsidered *time.Time
 }
 
 // UpdateJobRunInstruction is an instruction to update an existing row in the job runs table
@@ -70,6 +73,18 @@ type UpdateJobRunInstruction struct {
 	ExitCode    *int32
 }
 
+// UpsertJobRunUsageInstruction is an instruction to record the resource usage of a job run in the job run usage table.
+// If the run already has an entry, the maximum of the recorded and new usage is stored.
+type UpsertJobRunUsageInstruction struct {
+	RunId string
+	JobId string
+	// Millicores.
+	MaxCpu int64
+	// Bytes.
+	MaxMemory int64
+	Updated   time.Time
+}
+
 // InstructionSet represents a set of instructions to apply to the database.  Each type of instruction is stored in its
 // own ordered list representign the order it was received.  We also store the original message ids corresponding to
 // these instructions so that when they are saved to the database, we can ACK the corresponding messages.
@@ -79,6 +94,7 @@ type InstructionSet struct {
 	JobRunsToCreate         []*CreateJobRunInstruction
 	JobRunsToUpdate         []*UpdateJobRunInstruction
 	UserAnnotationsToCreate []*CreateUserAnnotationInstruction
+	JobRunUsagesToUpsert    []*UpsertJobRunUsageInstruction
 	MessageIds              []pulsar.MessageID
 }
 
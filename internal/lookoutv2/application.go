@@ -3,20 +3,26 @@
 package lookoutv2
 
 import (
+	"net/http"
+
 	"github.com/caarlos0/log"
 	"github.com/go-openapi/loads"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/jessevdk/go-flags"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
 	"github.com/armadaproject/armada/internal/common/compress"
 	"github.com/armadaproject/armada/internal/common/database"
+	"github.com/armadaproject/armada/internal/common/logging"
 	"github.com/armadaproject/armada/internal/common/util"
 	"github.com/armadaproject/armada/internal/lookoutv2/configuration"
 	"github.com/armadaproject/armada/internal/lookoutv2/conversions"
 	"github.com/armadaproject/armada/internal/lookoutv2/gen/restapi"
 	"github.com/armadaproject/armada/internal/lookoutv2/gen/restapi/operations"
+	"github.com/armadaproject/armada/internal/lookoutv2/model"
 	"github.com/armadaproject/armada/internal/lookoutv2/repository"
 )
 
@@ -34,9 +40,16 @@ func Serve(configuration configuration.LookoutV2Config) error {
 
 	getJobsRepo := repository.NewSqlGetJobsRepository(db)
 	groupJobsRepo := repository.NewSqlGroupJobsRepository(db)
-	decompressor := compress.NewThreadSafeZlibDecompressor()
+	decompressor, err := compress.NewThreadSafeDecompressor()
+	if err != nil {
+		return err
+	}
 	getJobRunErrorRepo := repository.NewSqlGetJobRunErrorRepository(db, decompressor)
 	getJobSpecRepo := repository.NewSqlGetJobSpecRepository(db, decompressor)
+	getJobLineageRepo := repository.NewSqlGetJobLineageRepository(db, configuration.UIConfig.UserAnnotationPrefix)
+	getResourceUsageRepo := repository.NewSqlGetResourceUsageRepository(db)
+	getStartLatencyRepo := repository.NewSqlGetStartLatencyRepository(db)
+	getTemplateUsageRepo := repository.NewSqlGetTemplateUsageRepository(db, configuration.UIConfig.UserAnnotationPrefix)
 
 	// create new service API
 	api := operations.NewLookoutAPI(swaggerSpec)
@@ -121,6 +134,56 @@ func Serve(configuration configuration.LookoutV2Config) error {
 		},
 	)
 
+	api.GetJobLineageHandler = operations.GetJobLineageHandlerFunc(
+		func(params operations.GetJobLineageParams) middleware.Responder {
+			ctx := armadacontext.New(params.HTTPRequest.Context(), logger)
+			request := params.GetJobLineageRequest
+			var result *model.JobLineage
+			var err error
+			if request.JobID != "" {
+				result, err = getJobLineageRepo.GetJobLineage(ctx, request.JobID)
+			} else if request.DagID != "" && request.TaskRunID != "" {
+				result, err = getJobLineageRepo.GetWorkflowRunLineage(ctx, request.DagID, request.TaskRunID)
+			} else {
+				return operations.NewGetJobLineageBadRequest().WithPayload(
+					conversions.ToSwaggerError("either jobId or both of dagId and taskRunId must be provided"),
+				)
+			}
+			var notFound *armadaerrors.ErrNotFound
+			if errors.As(err, &notFound) {
+				return operations.NewGetJobLineageBadRequest().WithPayload(conversions.ToSwaggerError(err.Error()))
+			} else if err != nil {
+				logging.WithStacktrace(ctx, err).Error("failed to get job lineage")
+				return operations.NewGetJobLineageDefault(http.StatusInternalServerError).WithPayload(conversions.ToSwaggerError(err.Error()))
+			}
+			return operations.NewGetJobLineageOK().WithPayload(&operations.GetJobLineageOKBody{
+				Jobs: util.Map(result.Jobs, conversions.ToSwaggerLineageJob),
+			})
+		},
+	)
+
+	restapi.ChargebackHandler = &chargebackHandler{
+		repo:   getResourceUsageRepo,
+		config: configuration.Chargeback,
+		logger: logger,
+	}
+
+	restapi.ResourceUsageHandler = &resourceUsageHandler{
+		repo:   getResourceUsageRepo,
+		logger: logger,
+	}
+
+	restapi.StartLatencyHandler = &startLatencyHandler{
+		repo:   getStartLatencyRepo,
+		logger: logger,
+	}
+
+	restapi.RightsizingHandler = &rightsizingHandler{
+		repo:   getTemplateUsageRepo,
+		config: configuration.Rightsizing,
+		logger: logger,
+	}
+
 	server := restapi.NewServer(api)
 	defer func() {
 		shutdownErr := server.Shutdown()
@@ -0,0 +1,54 @@
+package lookoutv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/lookoutv2/chargeback"
+	"github.com/armadaproject/armada/internal/lookoutv2/configuration"
+	"github.com/armadaproject/armada/internal/lookoutv2/repository"
+)
+
+// chargebackHandler serves the chargeback report for the month given by the month query parameter (YYYY-MM),
+// or for the current month if not provided. The report is returned as json, or as csv if format=csv.
+type chargebackHandler struct {
+	repo   repository.GetResourceUsageRepository
+	config configuration.ChargebackConfig
+	logger *logrus.Entry
+}
+
+func (h *chargebackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := armadacontext.New(r.Context(), h.logger)
+	query := r.URL.Query()
+	from, to, err := chargeback.MonthInterval(query.Get("month"), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	usage, err := h.repo.GetResourceUsage(ctx, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	report := chargeback.NewReport(h.config, usage, from, to)
+	switch query.Get("format") {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=chargeback-%s.csv", from.Format("2006-01")))
+		err = report.WriteCsv(w)
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(report)
+	default:
+		http.Error(w, "format must be one of json or csv", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.logger.WithError(err).Error("failed to write chargeback response")
+	}
+}
@@ -0,0 +1,180 @@
+// Package chargeback computes per-queue and per-tenant cost reports from the resources consumed by job runs.
+package chargeback
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/armadaproject/armada/internal/lookoutv2/configuration"
+	"github.com/armadaproject/armada/internal/lookoutv2/model"
+)
+
+// Pool reported for clusters not assigned to any pool.
+const UnknownPool = "unknown"
+
+type Report struct {
+	From     time.Time    `json:"from"`
+	To       time.Time    `json:"to"`
+	Currency string       `json:"currency,omitempty"`
+	Queues   []*QueueCost `json:"queues"`
+	Tenants  []*Cost      `json:"tenants"`
+}
+
+// QueueCost is the cost of the resources consumed by a queue in a particular pool.
+type QueueCost struct {
+	Tenant        string             `json:"tenant"`
+	Queue         string             `json:"queue"`
+	Pool          string             `json:"pool"`
+	ResourceHours map[string]float64 `json:"resourceHours"`
+	Cost          float64            `json:"cost"`
+}
+
+type Cost struct {
+	Name string  `json:"name"`
+	Cost float64 `json:"cost"`
+}
+
+// NewReport prices the given usage over the interval [from, to) according to config.
+func NewReport(config configuration.ChargebackConfig, usage []*model.QueueClusterResourceUsage, from time.Time, to time.Time) *Report {
+	type queuePool struct {
+		queue string
+		pool  string
+	}
+	costByQueuePool := make(map[queuePool]*QueueCost)
+	costByTenant := make(map[string]*Cost)
+	for _, u := range usage {
+		pool, ok := config.ClusterPools[u.Cluster]
+		if !ok {
+			pool = UnknownPool
+		}
+		tenant, ok := config.QueueTenants[u.Queue]
+		if !ok {
+			tenant = u.Queue
+		}
+		key := queuePool{queue: u.Queue, pool: pool}
+		queueCost, ok := costByQueuePool[key]
+		if !ok {
+			queueCost = &QueueCost{
+				Tenant:        tenant,
+				Queue:         u.Queue,
+				Pool:          pool,
+				ResourceHours: make(map[string]float64),
+			}
+			costByQueuePool[key] = queueCost
+		}
+		tenantCost, ok := costByTenant[tenant]
+		if !ok {
+			tenantCost = &Cost{Name: tenant}
+			costByTenant[tenant] = tenantCost
+		}
+		for resourceName, hours := range u.ResourceHours {
+			cost := hours * price(config, pool, resourceName)
+			queueCost.ResourceHours[resourceName] += hours
+			queueCost.Cost += cost
+			tenantCost.Cost += cost
+		}
+	}
+
+	queues := maps.Values(costByQueuePool)
+	slices.SortFunc(queues, func(a, b *QueueCost) bool {
+		if a.Queue != b.Queue {
+			return a.Queue < b.Queue
+		}
+		return a.Pool < b.Pool
+	})
+	tenants := maps.Values(costByTenant)
+	slices.SortFunc(tenants, func(a, b *Cost) bool {
+		return a.Name < b.Name
+	})
+	return &Report{
+		From:     from,
+		To:       to,
+		Currency: config.Currency,
+		Queues:   queues,
+		Tenants:  tenants,
+	}
+}
+
+func price(config configuration.ChargebackConfig, pool string, resourceName string) float64 {
+	if poolPrices, ok := config.PoolPrices[pool]; ok {
+		if p, ok := poolPrices[resourceName]; ok {
+			return p
+		}
+	}
+	return config.Prices[resourceName]
+}
+
+// WriteCsv writes one line per queue and pool, with one resource-hours column per resource.
+func (r *Report) WriteCsv(w io.Writer) error {
+	resourceNameSet := make(map[string]bool)
+	for _, queueCost := range r.Queues {
+		for resourceName := range queueCost.ResourceHours {
+			resourceNameSet[resourceName] = true
+		}
+	}
+	resourceNames := maps.Keys(resourceNameSet)
+	slices.Sort(resourceNames)
+
+	writer := csv.NewWriter(w)
+	header := []string{"from", "to", "tenant", "queue", "pool"}
+	for _, resourceName := range resourceNames {
+		header = append(header, resourceName+"_hours")
+	}
+	header = append(header, "cost", "currency")
+	if err := writer.Write(header); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, queueCost := range r.Queues {
+		record := []string{
+			r.From.Format(time.RFC3339),
+			r.To.Format(time.RFC3339),
+			queueCost.Tenant,
+			queueCost.Queue,
+			queueCost.Pool,
+		}
+		for _, resourceName := range resourceNames {
+			record = append(record, formatFloat(queueCost.ResourceHours[resourceName]))
+		}
+		record = append(record, formatFloat(queueCost.Cost), r.Currency)
+		if err := writer.Write(record); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	writer.Flush()
+	return errors.WithStack(writer.Error())
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
+
+// MonthInterval returns the interval covered by the given month, formatted as YYYY-MM, in UTC.
+// The interval is truncated at now, such that reports for the current month only include usage so far.
+// If month is empty, the current month is used.
+func MonthInterval(month string, now time.Time) (time.Time, time.Time, error) {
+	now = now.UTC()
+	var from time.Time
+	if month == "" {
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	} else {
+		var err error
+		from, err = time.Parse("2006-01", month)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.Errorf("invalid month %q; expected format YYYY-MM", month)
+		}
+	}
+	to := from.AddDate(0, 1, 0)
+	if to.After(now) {
+		to = now
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.Errorf("month %s is in the future", from.Format("2006-01"))
+	}
+	return from, to, nil
+}
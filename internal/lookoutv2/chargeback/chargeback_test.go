@@ -0,0 +1,124 @@
+package chargeback
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/lookoutv2/configuration"
+	"github.com/armadaproject/armada/internal/lookoutv2/model"
+)
+
+var (
+	from = time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	to   = time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+)
+
+var testConfig = configuration.ChargebackConfig{
+	Currency: "USD",
+	Prices:   map[string]float64{"cpu": 1, "nvidia.com/gpu": 10},
+	PoolPrices: map[string]map[string]float64{
+		"gpu-pool": {"nvidia.com/gpu": 20},
+	},
+	ClusterPools: map[string]string{
+		"cluster-a": "cpu-pool",
+		"cluster-b": "gpu-pool",
+	},
+	QueueTenants: map[string]string{
+		"queue-a": "tenant-1",
+		"queue-b": "tenant-1",
+	},
+}
+
+func TestNewReport(t *testing.T) {
+	usage := []*model.QueueClusterResourceUsage{
+		{Queue: "queue-a", Cluster: "cluster-a", ResourceHours: map[string]float64{"cpu": 10, "memory": 5}},
+		{Queue: "queue-a", Cluster: "cluster-b", ResourceHours: map[string]float64{"cpu": 1, "nvidia.com/gpu": 2}},
+		{Queue: "queue-b", Cluster: "cluster-c", ResourceHours: map[string]float64{"nvidia.com/gpu": 1}},
+		{Queue: "queue-c", Cluster: "cluster-a", ResourceHours: map[string]float64{"cpu": 3}},
+	}
+	report := NewReport(testConfig, usage, from, to)
+	assert.Equal(
+		t,
+		&Report{
+			From:     from,
+			To:       to,
+			Currency: "USD",
+			Queues: []*QueueCost{
+				{Tenant: "tenant-1", Queue: "queue-a", Pool: "cpu-pool", ResourceHours: map[string]float64{"cpu": 10, "memory": 5}, Cost: 10},
+				{Tenant: "tenant-1", Queue: "queue-a", Pool: "gpu-pool", ResourceHours: map[string]float64{"cpu": 1, "nvidia.com/gpu": 2}, Cost: 41},
+				{Tenant: "tenant-1", Queue: "queue-b", Pool: UnknownPool, ResourceHours: map[string]float64{"nvidia.com/gpu": 1}, Cost: 10},
+				{Tenant: "queue-c", Queue: "queue-c", Pool: "cpu-pool", ResourceHours: map[string]float64{"cpu": 3}, Cost: 3},
+			},
+			Tenants: []*Cost{
+				{Name: "queue-c", Cost: 3},
+				{Name: "tenant-1", Cost: 61},
+			},
+		},
+		report,
+	)
+}
+
+func TestReport_WriteCsv(t *testing.T) {
+	usage := []*model.QueueClusterResourceUsage{
+		{Queue: "queue-a", Cluster: "cluster-b", ResourceHours: map[string]float64{"cpu": 1, "nvidia.com/gpu": 2}},
+		{Queue: "queue-c", Cluster: "cluster-a", ResourceHours: map[string]float64{"cpu": 3}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, NewReport(testConfig, usage, from, to).WriteCsv(&buf))
+	assert.Equal(
+		t,
+		"from,to,tenant,queue,pool,cpu_hours,nvidia.com/gpu_hours,cost,currency\n"+
+			"2023-09-01T00:00:00Z,2023-10-01T00:00:00Z,tenant-1,queue-a,gpu-pool,1.0000,2.0000,41.0000,USD\n"+
+			"2023-09-01T00:00:00Z,2023-10-01T00:00:00Z,queue-c,queue-c,cpu-pool,3.0000,0.0000,3.0000,USD\n",
+		buf.String(),
+	)
+}
+
+func TestMonthInterval(t *testing.T) {
+	now := time.Date(2023, 10, 15, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		month        string
+		expectedFrom time.Time
+		expectedTo   time.Time
+		expectError  bool
+	}{
+		"past month": {
+			month:        "2023-09",
+			expectedFrom: from,
+			expectedTo:   to,
+		},
+		"current month": {
+			month:        "2023-10",
+			expectedFrom: to,
+			expectedTo:   now,
+		},
+		"default": {
+			expectedFrom: to,
+			expectedTo:   now,
+		},
+		"future month": {
+			month:       "2023-11",
+			expectError: true,
+		},
+		"invalid month": {
+			month:       "september",
+			expectError: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			actualFrom, actualTo, err := MonthInterval(tc.month, now)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedFrom, actualFrom)
+			assert.Equal(t, tc.expectedTo, actualTo)
+		})
+	}
+}
@@ -18,6 +18,10 @@ type LookoutV2Config struct {
 
 	PrunerConfig PrunerConfig
 
+	Chargeback ChargebackConfig
+
+	Rightsizing RightsizingConfig
+
 	UIConfig
 }
 
@@ -33,6 +37,32 @@ type PrunerConfig struct {
 	BatchSize   int
 }
 
+// ChargebackConfig configures the prices used to compute chargeback reports.
+// Resource names are those reported by the resource usage repository, i.e., cpu (priced per core-hour),
+// memory and ephemeral-storage (per GiB-hour), and nvidia.com/gpu (per gpu-hour).
+type ChargebackConfig struct {
+	// Included in reports for information only.
+	Currency string
+	// Price per resource-hour by resource name.
+	Prices map[string]float64
+	// Per-pool prices; overrides Prices for runs on clusters in that pool.
+	PoolPrices map[string]map[string]float64
+	// Map from cluster name to the pool that cluster belongs to.
+	ClusterPools map[string]string
+	// Map from queue to the tenant billed for that queue. Queues not in this map are billed as their own tenant.
+	QueueTenants map[string]string
+}
+
+// RightsizingConfig configures the resource recommendations computed from the resources used by previous runs of each job template.
+type RightsizingConfig struct {
+	// Fraction of used resources added on top of recommendations to absorb variation between runs, e.g., 0.2 for 20%.
+	Headroom float64
+	// No recommendation is made for templates with fewer runs than this in the lookback period.
+	MinRuns int
+	// Only runs that reported usage within this period are considered.
+	Lookback time.Duration
+}
+
 type UIConfig struct {
 	CustomTitle string
 
@@ -40,15 +40,17 @@ func ToSwaggerJob(job *model.Job) *models.Job {
 
 func ToSwaggerRun(run *model.Run) *models.Run {
 	return &models.Run{
-		Cluster:     run.Cluster,
-		ExitCode:    run.ExitCode,
-		Finished:    toSwaggerTimePtr(run.Finished),
-		JobRunState: run.JobRunState,
-		Node:        run.Node,
-		Leased:      toSwaggerTimePtr(run.Leased),
-		Pending:     toSwaggerTimePtr(run.Pending),
-		RunID:       run.RunId,
-		Started:     toSwaggerTimePtr(run.Started),
+		Cluster:         run.Cluster,
+		ExitCode:        run.ExitCode,
+		Finished:        toSwaggerTimePtr(run.Finished),
+		JobRunState:     run.JobRunState,
+		Node:            run.Node,
+		Leased:          toSwaggerTimePtr(run.Leased),
+		Pending:         toSwaggerTimePtr(run.Pending),
+		RunID:           run.RunId,
+		Started:         toSwaggerTimePtr(run.Started),
+		JobIngested:     toSwaggerTimePtr(run.JobIngested),
+		FirstConsidered: toSwaggerTimePtr(run.FirstConsidered),
 	}
 }
 
@@ -60,6 +62,21 @@ func ToSwaggerGroup(group *model.JobGroup) *models.Group {
 	}
 }
 
+func ToSwaggerLineageJob(job *model.LineageJob) *models.LineageJob {
+	return &models.LineageJob{
+		DagID:       job.DagId,
+		JobID:       job.JobId,
+		JobSet:      job.JobSet,
+		NumRuns:     int64(job.NumRuns),
+		ParentJobID: job.ParentJobId,
+		Queue:       job.Queue,
+		State:       job.State,
+		Submitted:   strfmt.DateTime(job.Submitted),
+		TaskID:      job.TaskId,
+		TaskRunID:   job.TaskRunId,
+	}
+}
+
 func ToSwaggerError(err string) *models.Error {
 	return &models.Error{
 		Error: err,
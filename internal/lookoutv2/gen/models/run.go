@@ -32,6 +32,14 @@ type Run struct {
 	// Format: date-time
 	Finished *strfmt.DateTime `json:"finished,omitempty"`
 
+	// Time at which the scheduler first considered the job for scheduling before leasing this run. Unset if not recorded.
+	// Format: date-time
+	FirstConsidered *strfmt.DateTime `json:"firstConsidered,omitempty"`
+
+	// Time at which the job was ingested by the scheduler. Only set for the first run of a job, if recorded.
+	// Format: date-time
+	JobIngested *strfmt.DateTime `json:"jobIngested,omitempty"`
+
 	// job run state
 	// Required: true
 	// Enum: [RUN_PENDING RUN_RUNNING RUN_SUCCEEDED RUN_FAILED RUN_TERMINATED RUN_PREEMPTED RUN_UNABLE_TO_SCHEDULE RUN_LEASE_RETURNED RUN_LEASE_EXPIRED RUN_MAX_RUNS_EXCEEDED RUN_LEASED]
@@ -72,6 +80,14 @@ func (m *Run) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateFirstConsidered(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateJobIngested(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateJobRunState(formats); err != nil {
 		res = append(res, err)
 	}
@@ -123,6 +139,30 @@ func (m *Run) validateFinished(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Run) validateFirstConsidered(formats strfmt.Registry) error {
+	if swag.IsZero(m.FirstConsidered) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("firstConsidered", "body", "date-time", m.FirstConsidered.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Run) validateJobIngested(formats strfmt.Registry) error {
+	if swag.IsZero(m.JobIngested) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("jobIngested", "body", "date-time", m.JobIngested.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 var runTypeJobRunStatePropEnum []interface{}
 
 func init() {
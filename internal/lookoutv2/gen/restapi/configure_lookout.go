@@ -86,6 +86,20 @@ func setupMiddlewares(handler http.Handler) http.Handler {
 
 var UIConfig configuration.UIConfig
 
+// ChargebackHandler serves chargeback reports. It's not part of the swagger api since it may also serve csv.
+var ChargebackHandler http.Handler
+
+// ResourceUsageHandler serves the resources consumed per queue over arbitrary intervals, e.g., to track budgets.
+// It's not part of the swagger api since it's served directly as json.
+var ResourceUsageHandler http.Handler
+
+// StartLatencyHandler serves a breakdown of the time it takes to start runs per queue, e.g., to locate where start latency is spent.
+// It's not part of the swagger api since it's served directly as json.
+var StartLatencyHandler http.Handler
+
+// RightsizingHandler serves resource rightsizing recommendations. It's not part of the swagger api since it's served directly as json.
+var RightsizingHandler http.Handler
+
 // The middleware configuration happens before anything, this middleware also applies to serving the swagger.json document.
 // So this is a good place to plug in a panic handling middleware, logging and metrics.
 func setupGlobalMiddleware(apiHandler http.Handler) http.Handler {
@@ -104,6 +118,18 @@ func uiHandler(apiHandler http.Handler) http.Handler {
 		}
 	})
 
+	if ChargebackHandler != nil {
+		mux.Handle("/api/v1/chargeback", ChargebackHandler)
+	}
+	if ResourceUsageHandler != nil {
+		mux.Handle("/api/v1/resourceUsage", ResourceUsageHandler)
+	}
+	if StartLatencyHandler != nil {
+		mux.Handle("/api/v1/startLatency", StartLatencyHandler)
+	}
+	if RightsizingHandler != nil {
+		mux.Handle("/api/v1/rightsizing", RightsizingHandler)
+	}
 	mux.Handle("/api/", apiHandler)
 	mux.Handle("/health", apiHandler)
 
@@ -144,6 +144,73 @@ func init() {
         }
       }
     },
+    "/api/v1/jobLineage": {
+      "post": {
+        "consumes": [
+          "application/json"
+        ],
+        "produces": [
+          "application/json"
+        ],
+        "operationId": "getJobLineage",
+        "parameters": [
+          {
+            "name": "getJobLineageRequest",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "type": "object",
+              "properties": {
+                "dagId": {
+                  "description": "Id of the workflow DAG of the workflow run to get the lineage graph of.",
+                  "type": "string"
+                },
+                "jobId": {
+                  "description": "Id of a job to get the lineage graph of. Either jobId, or both of dagId and taskRunId, must be provided.",
+                  "type": "string"
+                },
+                "taskRunId": {
+                  "description": "Id of the workflow task run to get the lineage graph of.",
+                  "type": "string"
+                }
+              }
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Returns the lineage graph of a job or workflow run",
+            "schema": {
+              "type": "object",
+              "required": [
+                "jobs"
+              ],
+              "properties": {
+                "jobs": {
+                  "description": "Jobs of the lineage graph",
+                  "type": "array",
+                  "items": {
+                    "$ref": "#/definitions/lineageJob"
+                  }
+                }
+              }
+            }
+          },
+          "400": {
+            "description": "Error response",
+            "schema": {
+              "$ref": "#/definitions/error"
+            }
+          },
+          "default": {
+            "description": "Error response",
+            "schema": {
+              "$ref": "#/definitions/error"
+            }
+          }
+        }
+      }
+    },
     "/api/v1/jobRunError": {
       "post": {
         "consumes": [
@@ -571,6 +638,69 @@ func init() {
         }
       }
     },
+    "lineageJob": {
+      "type": "object",
+      "required": [
+        "jobId",
+        "queue",
+        "jobSet",
+        "state",
+        "submitted",
+        "numRuns"
+      ],
+      "properties": {
+        "dagId": {
+          "description": "Id of the workflow DAG that spawned this job, if any.",
+          "type": "string",
+          "x-nullable": true
+        },
+        "jobId": {
+          "type": "string",
+          "minLength": 1,
+          "x-nullable": false
+        },
+        "jobSet": {
+          "type": "string",
+          "minLength": 1,
+          "x-nullable": false
+        },
+        "numRuns": {
+          "type": "integer",
+          "x-nullable": false
+        },
+        "parentJobId": {
+          "description": "Id of the job this job was resubmitted from, if any. Edges of the lineage graph are given by this field.",
+          "type": "string",
+          "x-nullable": true
+        },
+        "queue": {
+          "type": "string",
+          "minLength": 1,
+          "x-nullable": false
+        },
+        "state": {
+          "type": "string",
+          "minLength": 1,
+          "x-nullable": false
+        },
+        "submitted": {
+          "type": "string",
+          "format": "date-time",
+          "minLength": 1,
+          "x-nullable": false
+        },
+        "taskId": {
+          "description": "Id of the workflow task that spawned this job, if any.",
+          "type": "string",
+          "x-nullable": true
+        },
+        "taskRunId": {
+          "description": "Id of the workflow task run that spawned this job, if any.",
+          "type": "string",
+          "x-nullable": true
+        }
+      }
+    },
     "order": {
       "type": "object",
       "required": [
@@ -616,6 +746,18 @@ func init() {
           "format": "date-time",
           "x-nullable": true
         },
+        "firstConsidered": {
+          "description": "Time at which the scheduler first considered the job for scheduling before leasing this run. Unset if not recorded.",
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
+        "jobIngested": {
+          "description": "Time at which the job was ingested by the scheduler. Only set for the first run of a job, if recorded.",
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
         "jobRunState": {
           "type": "string",
           "enum": [
@@ -790,6 +932,73 @@ func init() {
         }
       }
     },
+    "/api/v1/jobLineage": {
+      "post": {
+        "consumes": [
+          "application/json"
+        ],
+        "produces": [
+          "application/json"
+        ],
+        "operationId": "getJobLineage",
+        "parameters": [
+          {
+            "name": "getJobLineageRequest",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "type": "object",
+              "properties": {
+                "dagId": {
+                  "description": "Id of the workflow DAG of the workflow run to get the lineage graph of.",
+                  "type": "string"
+                },
+                "jobId": {
+                  "description": "Id of a job to get the lineage graph of. Either jobId, or both of dagId and taskRunId, must be provided.",
+                  "type": "string"
+                },
+                "taskRunId": {
+                  "description": "Id of the workflow task run to get the lineage graph of.",
+                  "type": "string"
+                }
+              }
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Returns the lineage graph of a job or workflow run",
+            "schema": {
+              "type": "object",
+              "required": [
+                "jobs"
+              ],
+              "properties": {
+                "jobs": {
+                  "description": "Jobs of the lineage graph",
+                  "type": "array",
+                  "items": {
+                    "$ref": "#/definitions/lineageJob"
+                  }
+                }
+              }
+            }
+          },
+          "400": {
+            "description": "Error response",
+            "schema": {
+              "$ref": "#/definitions/error"
+            }
+          },
+          "default": {
+            "description": "Error response",
+            "schema": {
+              "$ref": "#/definitions/error"
+            }
+          }
+        }
+      }
+    },
     "/api/v1/jobRunError": {
       "post": {
         "consumes": [
@@ -1234,6 +1443,69 @@ func init() {
         }
       }
     },
+    "lineageJob": {
+      "type": "object",
+      "required": [
+        "jobId",
+        "queue",
+        "jobSet",
+        "state",
+        "submitted",
+        "numRuns"
+      ],
+      "properties": {
+        "dagId": {
+          "description": "Id of the workflow DAG that spawned this job, if any.",
+          "type": "string",
+          "x-nullable": true
+        },
+        "jobId": {
+          "type": "string",
+          "minLength": 1,
+          "x-nullable": false
+        },
+        "jobSet": {
+          "type": "string",
+          "minLength": 1,
+          "x-nullable": false
+        },
+        "numRuns": {
+          "type": "integer",
+          "x-nullable": false
+        },
+        "parentJobId": {
+          "description": "Id of the job this job was resubmitted from, if any. Edges of the lineage graph are given by this field.",
+          "type": "string",
+          "x-nullable": true
+        },
+        "queue": {
+          "type": "string",
+          "minLength": 1,
+          "x-nullable": false
+        },
+        "state": {
+          "type": "string",
+          "minLength": 1,
+          "x-nullable": false
+        },
+        "submitted": {
+          "type": "string",
+          "format": "date-time",
+          "minLength": 1,
+          "x-nullable": false
+        },
+        "taskId": {
+          "description": "Id of the workflow task that spawned this job, if any.",
+          "type": "string",
+          "x-nullable": true
+        },
+        "taskRunId": {
+          "description": "Id of the workflow task run that spawned this job, if any.",
+          "type": "string",
+          "x-nullable": true
+        }
+      }
+    },
     "order": {
       "type": "object",
       "required": [
@@ -1279,6 +1551,18 @@ func init() {
           "format": "date-time",
           "x-nullable": true
         },
+        "firstConsidered": {
+          "description": "Time at which the scheduler first considered the job for scheduling before leasing this run. Unset if not recorded.",
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
+        "jobIngested": {
+          "description": "Time at which the job was ingested by the scheduler. Only set for the first run of a job, if recorded.",
+          "type": "string",
+          "format": "date-time",
+          "x-nullable": true
+        },
         "jobRunState": {
           "type": "string",
           "enum": [
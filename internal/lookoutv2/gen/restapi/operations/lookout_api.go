@@ -46,6 +46,9 @@ func NewLookoutAPI(spec *loads.Document) *LookoutAPI {
 		GetHealthHandler: GetHealthHandlerFunc(func(params GetHealthParams) middleware.Responder {
 			return middleware.NotImplemented("operation GetHealth has not yet been implemented")
 		}),
+		GetJobLineageHandler: GetJobLineageHandlerFunc(func(params GetJobLineageParams) middleware.Responder {
+			return middleware.NotImplemented("operation GetJobLineage has not yet been implemented")
+		}),
 		GetJobRunErrorHandler: GetJobRunErrorHandlerFunc(func(params GetJobRunErrorParams) middleware.Responder {
 			return middleware.NotImplemented("operation GetJobRunError has not yet been implemented")
 		}),
@@ -99,6 +102,8 @@ type LookoutAPI struct {
 
 	// GetHealthHandler sets the operation handler for the get health operation
 	GetHealthHandler GetHealthHandler
+	// GetJobLineageHandler sets the operation handler for the get job lineage operation
+	GetJobLineageHandler GetJobLineageHandler
 	// GetJobRunErrorHandler sets the operation handler for the get job run error operation
 	GetJobRunErrorHandler GetJobRunErrorHandler
 	// GetJobSpecHandler sets the operation handler for the get job spec operation
@@ -190,6 +195,9 @@ func (o *LookoutAPI) Validate() error {
 	if o.GetHealthHandler == nil {
 		unregistered = append(unregistered, "GetHealthHandler")
 	}
+	if o.GetJobLineageHandler == nil {
+		unregistered = append(unregistered, "GetJobLineageHandler")
+	}
 	if o.GetJobRunErrorHandler == nil {
 		unregistered = append(unregistered, "GetJobRunErrorHandler")
 	}
@@ -299,6 +307,10 @@ func (o *LookoutAPI) initHandlerCache() {
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
 	}
+	o.handlers["POST"]["/api/v1/jobLineage"] = NewGetJobLineage(o.context, o.GetJobLineageHandler)
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
 	o.handlers["POST"]["/api/v1/jobRunError"] = NewGetJobRunError(o.context, o.GetJobRunErrorHandler)
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
@@ -51,6 +51,30 @@ type Run struct {
 	Pending     *time.Time
 	RunId       string
 	Started     *time.Time
+	// Time at which the job was ingested by the scheduler, and first considered for scheduling before this run was leased.
+	// Together with the time the job was submitted and the leased, pending, and started times of the run,
+	// these break down the time it took to start the run.
+	JobIngested     *time.Time
+	FirstConsidered *time.Time
+}
+
+// JobLineage is the set of jobs related to each other via parent/child relationships or a common workflow run.
+// Edges of the lineage graph are given by LineageJob.ParentJobId.
+type JobLineage struct {
+	Jobs []*LineageJob
+}
+
+type LineageJob struct {
+	JobId       string
+	Queue       string
+	JobSet      string
+	State       string
+	Submitted   time.Time
+	NumRuns     int
+	ParentJobId *string
+	DagId       *string
+	TaskId      *string
+	TaskRunId   *string
 }
 
 type JobGroup struct {
@@ -75,3 +99,24 @@ type GroupedField struct {
 	Field        string
 	IsAnnotation bool
 }
+
+// QueueClusterResourceUsage is the resources consumed by runs of jobs in a queue on a particular cluster over some period.
+type QueueClusterResourceUsage struct {
+	Queue   string
+	Cluster string
+	// Resource-hours by resource name, e.g., cpu core-hours and memory GiB-hours.
+	ResourceHours map[string]float64
+}
+
+// QueueStartLatency is the distribution of the time runs of jobs in a queue spent in one segment of the path from
+// the job being submitted to the run starting, e.g., from being leased to the pod being created.
+type QueueStartLatency struct {
+	Queue   string
+	Segment string
+	// Number of runs for which this segment was recorded.
+	Count int64
+	// Percentiles of the time spent in this segment, in seconds.
+	P50Seconds float64
+	P90Seconds float64
+	P99Seconds float64
+}
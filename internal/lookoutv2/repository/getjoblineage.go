@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/database/lookout"
+	"github.com/armadaproject/armada/internal/lookoutv2/model"
+)
+
+// Upper bound on the number of parent links followed when looking for the root of a lineage graph.
+// Protects against cycles and unreasonably deep resubmission chains.
+const maxLineageDepth = 1000
+
+type GetJobLineageRepository interface {
+	// GetJobLineage returns all ancestors and descendants of the job with the given id.
+	GetJobLineage(ctx *armadacontext.Context, jobId string) (*model.JobLineage, error)
+	// GetWorkflowRunLineage returns all jobs spawned by the given workflow run, including their descendants.
+	GetWorkflowRunLineage(ctx *armadacontext.Context, dagId string, taskRunId string) (*model.JobLineage, error)
+}
+
+type SqlGetJobLineageRepository struct {
+	db *pgxpool.Pool
+	// Keys of the lineage annotations as stored in the user_annotation_lookup table,
+	// i.e., with the user annotation prefix removed.
+	parentJobIdKey string
+	dagIdKey       string
+	taskIdKey      string
+	taskRunIdKey   string
+}
+
+func NewSqlGetJobLineageRepository(db *pgxpool.Pool, userAnnotationPrefix string) *SqlGetJobLineageRepository {
+	return &SqlGetJobLineageRepository{
+		db:             db,
+		parentJobIdKey: strings.TrimPrefix(configuration.ParentJobIdAnnotation, userAnnotationPrefix),
+		dagIdKey:       strings.TrimPrefix(configuration.AirflowDagIdAnnotation, userAnnotationPrefix),
+		taskIdKey:      strings.TrimPrefix(configuration.AirflowTaskIdAnnotation, userAnnotationPrefix),
+		taskRunIdKey:   strings.TrimPrefix(configuration.AirflowTaskRunIdAnnotation, userAnnotationPrefix),
+	}
+}
+
+func (r *SqlGetJobLineageRepository) GetJobLineage(ctx *armadacontext.Context, jobId string) (*model.JobLineage, error) {
+	rootJobId, err := r.findRoot(ctx, jobId)
+	if err != nil {
+		return nil, err
+	}
+	jobIds, err := r.withDescendants(ctx, []string{rootJobId})
+	if err != nil {
+		return nil, err
+	}
+	lineage, err := r.getLineageJobs(ctx, jobIds)
+	if err != nil {
+		return nil, err
+	}
+	if len(lineage.Jobs) == 0 {
+		return nil, errors.WithStack(&armadaerrors.ErrNotFound{Type: "job", Value: jobId})
+	}
+	return lineage, nil
+}
+
+func (r *SqlGetJobLineageRepository) GetWorkflowRunLineage(ctx *armadacontext.Context, dagId string, taskRunId string) (*model.JobLineage, error) {
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT d.job_id FROM user_annotation_lookup AS d
+		JOIN user_annotation_lookup AS t ON d.job_id = t.job_id
+		WHERE d.key = $1 AND d.value = $2 AND t.key = $3 AND t.value = $4`,
+		r.dagIdKey, dagId, r.taskRunIdKey, taskRunId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var jobIds []string
+	for rows.Next() {
+		var jobId string
+		if err := rows.Scan(&jobId); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		jobIds = append(jobIds, jobId)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// Jobs resubmitted from a workflow job may not carry the workflow annotations themselves.
+	jobIds, err = r.withDescendants(ctx, jobIds)
+	if err != nil {
+		return nil, err
+	}
+	return r.getLineageJobs(ctx, jobIds)
+}
+
+// findRoot follows parent links from the given job until reaching a job without a (known) parent.
+func (r *SqlGetJobLineageRepository) findRoot(ctx *armadacontext.Context, jobId string) (string, error) {
+	visited := map[string]bool{jobId: true}
+	for i := 0; i < maxLineageDepth; i++ {
+		var parentJobId string
+		err := r.db.QueryRow(
+			ctx,
+			`SELECT ual.value FROM user_annotation_lookup AS ual
+			JOIN job AS j ON j.job_id = ual.value
+			WHERE ual.job_id = $1 AND ual.key = $2`,
+			jobId, r.parentJobIdKey,
+		).Scan(&parentJobId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return jobId, nil
+			}
+			return "", err
+		}
+		if visited[parentJobId] {
+			return jobId, nil
+		}
+		visited[parentJobId] = true
+		jobId = parentJobId
+	}
+	return jobId, nil
+}
+
+// withDescendants returns the given job ids together with the ids of all jobs that descend from those jobs.
+func (r *SqlGetJobLineageRepository) withDescendants(ctx *armadacontext.Context, jobIds []string) ([]string, error) {
+	visited := make(map[string]bool, len(jobIds))
+	for _, jobId := range jobIds {
+		visited[jobId] = true
+	}
+	rv := slices.Clone(jobIds)
+	frontier := jobIds
+	for i := 0; i < maxLineageDepth && len(frontier) > 0; i++ {
+		rows, err := r.db.Query(
+			ctx,
+			"SELECT job_id FROM user_annotation_lookup WHERE key = $1 AND value = ANY($2)",
+			r.parentJobIdKey, frontier,
+		)
+		if err != nil {
+			return nil, err
+		}
+		var next []string
+		for rows.Next() {
+			var jobId string
+			if err := rows.Scan(&jobId); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if !visited[jobId] {
+				visited[jobId] = true
+				next = append(next, jobId)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		rv = append(rv, next...)
+		frontier = next
+	}
+	return rv, nil
+}
+
+func (r *SqlGetJobLineageRepository) getLineageJobs(ctx *armadacontext.Context, jobIds []string) (*model.JobLineage, error) {
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT j.job_id, j.queue, j.jobset, j.state, j.submitted, (SELECT count(*) FROM job_run AS jr WHERE jr.job_id = j.job_id)
+		FROM job AS j
+		WHERE j.job_id = ANY($1)
+		ORDER BY j.submitted, j.job_id`,
+		jobIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*model.LineageJob, 0, len(jobIds))
+	jobsById := make(map[string]*model.LineageJob, len(jobIds))
+	for rows.Next() {
+		var (
+			jobId     string
+			queue     string
+			jobSet    string
+			state     int
+			submitted time.Time
+			numRuns   int
+		)
+		if err := rows.Scan(&jobId, &queue, &jobSet, &state, &submitted, &numRuns); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		job := &model.LineageJob{
+			JobId:     jobId,
+			Queue:     queue,
+			JobSet:    jobSet,
+			State:     string(lookout.JobStateMap[state]),
+			Submitted: submitted,
+			NumRuns:   numRuns,
+		}
+		jobs = append(jobs, job)
+		jobsById[jobId] = job
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.Query(
+		ctx,
+		"SELECT job_id, key, value FROM user_annotation_lookup WHERE job_id = ANY($1) AND key = ANY($2)",
+		jobIds, []string{r.parentJobIdKey, r.dagIdKey, r.taskIdKey, r.taskRunIdKey},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var row annotationRow
+		if err := rows.Scan(&row.jobId, &row.annotationKey, &row.annotationValue); err != nil {
+			return nil, err
+		}
+		job, ok := jobsById[row.jobId]
+		if !ok {
+			continue
+		}
+		value := row.annotationValue
+		switch row.annotationKey {
+		case r.parentJobIdKey:
+			job.ParentJobId = &value
+		case r.dagIdKey:
+			job.DagId = &value
+		case r.taskIdKey:
+			job.TaskId = &value
+		case r.taskRunIdKey:
+			job.TaskRunId = &value
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &model.JobLineage{Jobs: jobs}, nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+	"github.com/armadaproject/armada/internal/common/compress"
+	"github.com/armadaproject/armada/internal/common/database/lookout"
+	"github.com/armadaproject/armada/internal/common/util"
+	"github.com/armadaproject/armada/internal/lookoutingesterv2/instructions"
+	"github.com/armadaproject/armada/internal/lookoutingesterv2/lookoutdb"
+	"github.com/armadaproject/armada/internal/lookoutingesterv2/metrics"
+	"github.com/armadaproject/armada/internal/lookoutv2/model"
+)
+
+func TestGetJobLineage(t *testing.T) {
+	err := lookout.WithLookoutDb(func(db *pgxpool.Pool) error {
+		converter := instructions.NewInstructionConverter(metrics.Get(), userAnnotationPrefix, &compress.NoOpCompressor{}, true)
+		store := lookoutdb.NewLookoutDb(db, metrics.Get(), 3, 10)
+
+		// root -> child -> grandchild, where root and sibling were submitted by the same workflow run.
+		rootId := util.NewULID()
+		childId := util.NewULID()
+		grandchildId := util.NewULID()
+		siblingId := util.NewULID()
+		unrelatedId := util.NewULID()
+		workflowAnnotations := map[string]string{
+			"dagId":     "my-dag",
+			"taskId":    "my-task",
+			"taskRunId": "run-1",
+		}
+		NewJobSimulator(converter, store).
+			Submit(queue, jobSet, owner, baseTime, &JobOptions{JobId: rootId, Annotations: workflowAnnotations}).
+			Failed(node, 1, "oops", baseTime).
+			Build()
+		NewJobSimulator(converter, store).
+			Submit(queue, jobSet, owner, baseTime.Add(1), &JobOptions{JobId: childId, Annotations: map[string]string{"parentJobId": rootId}}).
+			Failed(node, 1, "oops", baseTime).
+			Build()
+		NewJobSimulator(converter, store).
+			Submit(queue, jobSet, owner, baseTime.Add(2), &JobOptions{JobId: grandchildId, Annotations: map[string]string{"parentJobId": childId}}).
+			Build()
+		NewJobSimulator(converter, store).
+			Submit(queue, jobSet, owner, baseTime.Add(3), &JobOptions{JobId: siblingId, Annotations: map[string]string{
+				"dagId":     "my-dag",
+				"taskId":    "my-other-task",
+				"taskRunId": "run-1",
+			}}).
+			Build()
+		NewJobSimulator(converter, store).
+			Submit(queue, jobSet, owner, baseTime.Add(4), &JobOptions{JobId: unrelatedId, Annotations: map[string]string{
+				"dagId":     "my-dag",
+				"taskId":    "my-task",
+				"taskRunId": "run-2",
+			}}).
+			Build()
+
+		repo := NewSqlGetJobLineageRepository(db, userAnnotationPrefix)
+
+		lineage, err := repo.GetJobLineage(armadacontext.TODO(), grandchildId)
+		require.NoError(t, err)
+		assert.Equal(t, []string{rootId, childId, grandchildId}, lineageJobIds(lineage))
+		assert.Equal(t, childId, *lineage.Jobs[2].ParentJobId)
+		assert.Equal(t, "my-task", *lineage.Jobs[0].TaskId)
+		assert.Equal(t, string(lookout.JobFailed), lineage.Jobs[0].State)
+
+		lineage, err = repo.GetWorkflowRunLineage(armadacontext.TODO(), "my-dag", "run-1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{rootId, childId, grandchildId, siblingId}, lineageJobIds(lineage))
+
+		var notFound *armadaerrors.ErrNotFound
+		_, err = repo.GetJobLineage(armadacontext.TODO(), util.NewULID())
+		assert.True(t, errors.As(err, &notFound))
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func lineageJobIds(lineage *model.JobLineage) []string {
+	return util.Map(lineage.Jobs, func(job *model.LineageJob) string {
+		return job.JobId
+	})
+}
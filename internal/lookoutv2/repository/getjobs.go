@@ -62,6 +62,9 @@ type runRow struct {
 	finished    sql.NullTime
 	jobRunState int
 	exitCode    sql.NullInt32
+	// Recorded by the scheduler when leasing the run.
+	jobIngested     sql.NullTime
+	firstConsidered sql.NullTime
 }
 
 type annotationRow struct {
@@ -182,15 +185,17 @@ func rowsToJobs(jobRows []*jobRow, runRows []*runRow, annotationRows []*annotati
 
 	for _, row := range runRows {
 		run := &model.Run{
-			Cluster:     row.cluster,
-			ExitCode:    database.ParseNullInt32(row.exitCode),
-			Finished:    database.ParseNullTime(row.finished),
-			JobRunState: string(lookout.JobRunStateMap[row.jobRunState]),
-			Node:        database.ParseNullString(row.node),
-			Leased:      database.ParseNullTime(row.leased),
-			Pending:     database.ParseNullTime(row.pending),
-			RunId:       row.runId,
-			Started:     database.ParseNullTime(row.started),
+			Cluster:         row.cluster,
+			ExitCode:        database.ParseNullInt32(row.exitCode),
+			Finished:        database.ParseNullTime(row.finished),
+			JobRunState:     string(lookout.JobRunStateMap[row.jobRunState]),
+			Node:            database.ParseNullString(row.node),
+			Leased:          database.ParseNullTime(row.leased),
+			Pending:         database.ParseNullTime(row.pending),
+			RunId:           row.runId,
+			Started:         database.ParseNullTime(row.started),
+			JobIngested:     database.ParseNullTime(row.jobIngested),
+			FirstConsidered: database.ParseNullTime(row.firstConsidered),
 		}
 		job, ok := jobMap[row.jobId]
 		if !ok {
@@ -314,7 +319,9 @@ func makeRunRows(ctx *armadacontext.Context, tx pgx.Tx, tmpTableName string) ([]
 			jr.started,
 			jr.finished,
 			jr.job_run_state,
-			jr.exit_code
+			jr.exit_code,
+			jr.job_ingested,
+			jr.first_considered
 		FROM %s AS t
 		INNER JOIN job_run AS jr ON t.job_id = jr.job_id
 	`, tmpTableName)
@@ -338,6 +345,8 @@ func makeRunRows(ctx *armadacontext.Context, tx pgx.Tx, tmpTableName string) ([]
 			&row.finished,
 			&row.jobRunState,
 			&row.exitCode,
+			&row.jobIngested,
+			&row.firstConsidered,
 		)
 		if err != nil {
 			log.WithError(err).Errorf("failed to scan run row at index %d", len(rows))
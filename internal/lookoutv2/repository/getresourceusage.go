@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/lookoutv2/model"
+)
+
+// Names of the resources reported by GetResourceUsageRepository.
+// Cpu is measured in core-hours, memory and ephemeral storage in GiB-hours, and gpu in gpu-hours.
+const (
+	CpuResource              = "cpu"
+	MemoryResource           = "memory"
+	EphemeralStorageResource = "ephemeral-storage"
+	GpuResource              = "nvidia.com/gpu"
+)
+
+type GetResourceUsageRepository interface {
+	// GetResourceUsage returns the resource-hours consumed by runs in the interval [from, to), per queue and cluster.
+	// Runs are charged for the time between starting and finishing; runs still running are charged up to the end of the interval.
+	GetResourceUsage(ctx *armadacontext.Context, from time.Time, to time.Time) ([]*model.QueueClusterResourceUsage, error)
+}
+
+type SqlGetResourceUsageRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSqlGetResourceUsageRepository(db *pgxpool.Pool) *SqlGetResourceUsageRepository {
+	return &SqlGetResourceUsageRepository{db: db}
+}
+
+func (r *SqlGetResourceUsageRepository) GetResourceUsage(ctx *armadacontext.Context, from time.Time, to time.Time) ([]*model.QueueClusterResourceUsage, error) {
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT j.queue, jr.cluster,
+			SUM(d.hours * j.cpu / 1000.0)::float8,
+			SUM(d.hours * j.memory / 1073741824.0)::float8,
+			SUM(d.hours * j.ephemeral_storage / 1073741824.0)::float8,
+			SUM(d.hours * j.gpu)::float8
+		FROM job_run AS jr
+		JOIN job AS j ON j.job_id = jr.job_id
+		CROSS JOIN LATERAL (
+			SELECT EXTRACT(EPOCH FROM LEAST(COALESCE(jr.finished, $2), $2) - GREATEST(jr.started, $1)) / 3600.0 AS hours
+		) AS d
+		WHERE jr.started IS NOT NULL AND jr.started < $2 AND (jr.finished IS NULL OR jr.finished > $1)
+		GROUP BY j.queue, jr.cluster
+		ORDER BY j.queue, jr.cluster`,
+		// Timestamps are stored in UTC without a time zone.
+		from.UTC(), to.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var rv []*model.QueueClusterResourceUsage
+	for rows.Next() {
+		var queue, cluster string
+		var cpu, memory, ephemeralStorage, gpu float64
+		if err := rows.Scan(&queue, &cluster, &cpu, &memory, &ephemeralStorage, &gpu); err != nil {
+			return nil, err
+		}
+		rv = append(rv, &model.QueueClusterResourceUsage{
+			Queue:   queue,
+			Cluster: cluster,
+			ResourceHours: map[string]float64{
+				CpuResource:              cpu,
+				MemoryResource:           memory,
+				EphemeralStorageResource: ephemeralStorage,
+				GpuResource:              gpu,
+			},
+		})
+	}
+	return rv, rows.Err()
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/compress"
+	"github.com/armadaproject/armada/internal/common/database/lookout"
+	"github.com/armadaproject/armada/internal/lookoutingesterv2/instructions"
+	"github.com/armadaproject/armada/internal/lookoutingesterv2/lookoutdb"
+	"github.com/armadaproject/armada/internal/lookoutingesterv2/metrics"
+	"github.com/armadaproject/armada/internal/lookoutv2/model"
+)
+
+func TestGetResourceUsage(t *testing.T) {
+	err := lookout.WithLookoutDb(func(db *pgxpool.Pool) error {
+		converter := instructions.NewInstructionConverter(metrics.Get(), userAnnotationPrefix, &compress.NoOpCompressor{}, true)
+		store := lookoutdb.NewLookoutDb(db, metrics.Get(), 3, 10)
+		opts := func() *JobOptions {
+			return &JobOptions{
+				Cpu:    resource.MustParse("2"),
+				Memory: resource.MustParse("4Gi"),
+				Gpu:    resource.MustParse("1"),
+			}
+		}
+
+		// Runs for two hours within the interval.
+		finishedRunId := uuid.NewString()
+		NewJobSimulator(converter, store).
+			Submit(queue, jobSet, owner, baseTime, opts()).
+			Pending(finishedRunId, cluster, baseTime).
+			Running(finishedRunId, node, baseTime).
+			RunSucceeded(finishedRunId, baseTime.Add(2*time.Hour)).
+			Succeeded(baseTime.Add(2 * time.Hour)).
+			Build()
+		// Started an hour before the interval and still running; only charged within the interval.
+		runningRunId := uuid.NewString()
+		NewJobSimulator(converter, store).
+			Submit(queue, jobSet, owner, baseTime.Add(-time.Hour), opts()).
+			Pending(runningRunId, cluster, baseTime.Add(-time.Hour)).
+			Running(runningRunId, node, baseTime.Add(-time.Hour)).
+			Build()
+		// Never started.
+		NewJobSimulator(converter, store).
+			Submit("queue-2", jobSet, owner, baseTime, opts()).
+			Pending(uuid.NewString(), cluster, baseTime).
+			Build()
+
+		repo := NewSqlGetResourceUsageRepository(db)
+		usage, err := repo.GetResourceUsage(armadacontext.TODO(), baseTime, baseTime.Add(3*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			[]*model.QueueClusterResourceUsage{
+				{
+					Queue:   queue,
+					Cluster: cluster,
+					ResourceHours: map[string]float64{
+						CpuResource:              10,
+						MemoryResource:           20,
+						EphemeralStorageResource: 0,
+						GpuResource:              5,
+					},
+				},
+			},
+			usage,
+		)
+		return nil
+	})
+	assert.NoError(t, err)
+}
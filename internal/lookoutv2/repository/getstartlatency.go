@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/lookoutv2/model"
+)
+
+// Segments of the path from a job being submitted to a run of it starting, reported by GetStartLatencyRepository.
+// The segments up to the job being first considered for scheduling are only recorded for the first run of each job.
+const (
+	SubmittedToIngestedSegment  = "submittedToIngested"
+	IngestedToConsideredSegment = "ingestedToConsidered"
+	ConsideredToLeasedSegment   = "consideredToLeased"
+	LeasedToPendingSegment      = "leasedToPending"
+	PendingToStartedSegment     = "pendingToStarted"
+)
+
+type GetStartLatencyRepository interface {
+	// GetStartLatency returns the distribution of the time spent in each segment of starting runs leased in the interval [from, to), per queue.
+	// Segments for which either end isn't known, e.g., since the run hasn't started yet, aren't included.
+	GetStartLatency(ctx *armadacontext.Context, from time.Time, to time.Time) ([]*model.QueueStartLatency, error)
+}
+
+type SqlGetStartLatencyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSqlGetStartLatencyRepository(db *pgxpool.Pool) *SqlGetStartLatencyRepository {
+	return &SqlGetStartLatencyRepository{db: db}
+}
+
+func (r *SqlGetStartLatencyRepository) GetStartLatency(ctx *armadacontext.Context, from time.Time, to time.Time) ([]*model.QueueStartLatency, error) {
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT j.queue, s.segment,
+			COUNT(*),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY s.seconds),
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY s.seconds),
+			percentile_cont(0.99) WITHIN GROUP (ORDER BY s.seconds)
+		FROM job_run AS jr
+		JOIN job AS j ON j.job_id = jr.job_id
+		CROSS JOIN LATERAL (VALUES
+			($3::text, EXTRACT(EPOCH FROM jr.job_ingested - j.submitted)::float8),
+			($4, EXTRACT(EPOCH FROM jr.first_considered - jr.job_ingested)::float8),
+			($5, EXTRACT(EPOCH FROM jr.leased - jr.first_considered)::float8),
+			($6, EXTRACT(EPOCH FROM jr.pending - jr.leased)::float8),
+			($7, EXTRACT(EPOCH FROM jr.started - jr.pending)::float8)
+		) AS s(segment, seconds)
+		WHERE jr.leased >= $1 AND jr.leased < $2 AND s.seconds IS NOT NULL
+		GROUP BY j.queue, s.segment
+		ORDER BY j.queue, s.segment`,
+		// Timestamps are stored in UTC without a time zone.
+		from.UTC(), to.UTC(),
+		SubmittedToIngestedSegment,
+		IngestedToConsideredSegment,
+		ConsideredToLeasedSegment,
+		LeasedToPendingSegment,
+		PendingToStartedSegment,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var rv []*model.QueueStartLatency
+	for rows.Next() {
+		latency := &model.QueueStartLatency{}
+		if err := rows.Scan(
+			&latency.Queue,
+			&latency.Segment,
+			&latency.Count,
+			&latency.P50Seconds,
+			&latency.P90Seconds,
+			&latency.P99Seconds,
+		); err != nil {
+			return nil, err
+		}
+		rv = append(rv, latency)
+	}
+	return rv, rows.Err()
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/compress"
+	"github.com/armadaproject/armada/internal/common/database/lookout"
+	"github.com/armadaproject/armada/internal/lookoutingesterv2/instructions"
+	"github.com/armadaproject/armada/internal/lookoutingesterv2/lookoutdb"
+	"github.com/armadaproject/armada/internal/lookoutingesterv2/metrics"
+	"github.com/armadaproject/armada/internal/lookoutv2/model"
+)
+
+func TestGetStartLatency(t *testing.T) {
+	err := lookout.WithLookoutDb(func(db *pgxpool.Pool) error {
+		converter := instructions.NewInstructionConverter(metrics.Get(), userAnnotationPrefix, &compress.NoOpCompressor{}, true)
+		store := lookoutdb.NewLookoutDb(db, metrics.Get(), 3, 10)
+
+		// Started within the interval, with every segment recorded.
+		startedRunId := uuid.NewString()
+		NewJobSimulator(converter, store).
+			Submit(queue, jobSet, owner, baseTime, basicJobOpts).
+			LeaseWithStartTimes(startedRunId, baseTime.Add(time.Second), baseTime.Add(3*time.Second), baseTime.Add(7*time.Second)).
+			Pending(startedRunId, cluster, baseTime.Add(15*time.Second)).
+			Running(startedRunId, node, baseTime.Add(31*time.Second)).
+			Build()
+		// Leased within the interval but not yet pending; only the segments up to being leased are known.
+		leasedRunId := uuid.NewString()
+		NewJobSimulator(converter, store).
+			Submit(queue, jobSet, owner, baseTime, basicJobOpts).
+			LeaseWithStartTimes(leasedRunId, baseTime.Add(time.Second), baseTime.Add(3*time.Second), baseTime.Add(7*time.Second)).
+			Build()
+		// Leased before the interval.
+		earlyRunId := uuid.NewString()
+		NewJobSimulator(converter, store).
+			Submit("queue-2", jobSet, owner, baseTime.Add(-time.Hour), basicJobOpts).
+			LeaseWithStartTimes(earlyRunId, baseTime.Add(-time.Hour), baseTime.Add(-time.Hour), baseTime.Add(-time.Hour)).
+			Pending(earlyRunId, cluster, baseTime.Add(-time.Hour)).
+			Build()
+
+		repo := NewSqlGetStartLatencyRepository(db)
+		latencies, err := repo.GetStartLatency(armadacontext.TODO(), baseTime, baseTime.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			[]*model.QueueStartLatency{
+				{Queue: queue, Segment: ConsideredToLeasedSegment, Count: 2, P50Seconds: 4, P90Seconds: 4, P99Seconds: 4},
+				{Queue: queue, Segment: IngestedToConsideredSegment, Count: 2, P50Seconds: 2, P90Seconds: 2, P99Seconds: 2},
+				{Queue: queue, Segment: LeasedToPendingSegment, Count: 1, P50Seconds: 8, P90Seconds: 8, P99Seconds: 8},
+				{Queue: queue, Segment: PendingToStartedSegment, Count: 1, P50Seconds: 16, P90Seconds: 16, P99Seconds: 16},
+				{Queue: queue, Segment: SubmittedToIngestedSegment, Count: 2, P50Seconds: 1, P90Seconds: 1, P99Seconds: 1},
+			},
+			latencies,
+		)
+		return nil
+	})
+	assert.NoError(t, err)
+}
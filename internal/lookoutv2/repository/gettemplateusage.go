@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/rightsizing"
+)
+
+type GetTemplateUsageRepository interface {
+	// GetTemplateUsage returns the resources requested and used by each job template, per queue,
+	// for templates with at least minRuns runs reporting usage since the given time.
+	// If queue is empty, templates of all queues are returned.
+	// Only the requested and used resources of the returned recommendations are set.
+	GetTemplateUsage(ctx *armadacontext.Context, queue string, since time.Time, minRuns int) ([]*rightsizing.Recommendation, error)
+}
+
+type SqlGetTemplateUsageRepository struct {
+	db *pgxpool.Pool
+	// Keys of the template annotations as stored in the user_annotation_lookup table,
+	// i.e., with the user annotation prefix removed.
+	templateKey string
+	dagIdKey    string
+	taskIdKey   string
+}
+
+func NewSqlGetTemplateUsageRepository(db *pgxpool.Pool, userAnnotationPrefix string) *SqlGetTemplateUsageRepository {
+	return &SqlGetTemplateUsageRepository{
+		db:          db,
+		templateKey: strings.TrimPrefix(configuration.JobTemplateAnnotation, userAnnotationPrefix),
+		dagIdKey:    strings.TrimPrefix(configuration.AirflowDagIdAnnotation, userAnnotationPrefix),
+		taskIdKey:   strings.TrimPrefix(configuration.AirflowTaskIdAnnotation, userAnnotationPrefix),
+	}
+}
+
+func (r *SqlGetTemplateUsageRepository) GetTemplateUsage(ctx *armadacontext.Context, queue string, since time.Time, minRuns int) ([]*rightsizing.Recommendation, error) {
+	var queueFilter *string
+	if queue != "" {
+		queueFilter = &queue
+	}
+	// Must group jobs into templates in the same way as rightsizing.TemplateKey.
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT j.queue, COALESCE(NULLIF(t.value, ''), dag.value || '.' || task.value, j.jobset) AS template,
+			COUNT(*),
+			MAX(j.cpu),
+			MAX(j.memory),
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY u.max_cpu)::float8,
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY u.max_memory)::float8
+		FROM job_run_usage AS u
+		JOIN job AS j ON j.job_id = u.job_id
+		LEFT JOIN user_annotation_lookup AS t ON t.job_id = j.job_id AND t.key = $1
+		LEFT JOIN user_annotation_lookup AS dag ON dag.job_id = j.job_id AND dag.key = $2
+		LEFT JOIN user_annotation_lookup AS task ON task.job_id = j.job_id AND task.key = $3
+		WHERE u.updated >= $4 AND ($5::text IS NULL OR j.queue = $5)
+		GROUP BY 1, 2
+		HAVING COUNT(*) >= $6
+		ORDER BY 1, 2`,
+		r.templateKey, r.dagIdKey, r.taskIdKey,
+		// Timestamps are stored in UTC without a time zone.
+		since.UTC(), queueFilter, minRuns,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var rv []*rightsizing.Recommendation
+	for rows.Next() {
+		recommendation := &rightsizing.Recommendation{}
+		var usedCpu, usedMemory float64
+		if err := rows.Scan(
+			&recommendation.Queue,
+			&recommendation.Template,
+			&recommendation.NumRuns,
+			&recommendation.RequestedCpu,
+			&recommendation.RequestedMemory,
+			&usedCpu,
+			&usedMemory,
+		); err != nil {
+			return nil, err
+		}
+		recommendation.UsedCpu = int64(usedCpu)
+		recommendation.UsedMemory = int64(usedMemory)
+		rv = append(rv, recommendation)
+	}
+	return rv, rows.Err()
+}
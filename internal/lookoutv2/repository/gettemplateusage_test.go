@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/compress"
+	"github.com/armadaproject/armada/internal/common/database/lookout"
+	"github.com/armadaproject/armada/internal/common/rightsizing"
+	"github.com/armadaproject/armada/internal/common/util"
+	"github.com/armadaproject/armada/internal/lookoutingesterv2/instructions"
+	"github.com/armadaproject/armada/internal/lookoutingesterv2/lookoutdb"
+	"github.com/armadaproject/armada/internal/lookoutingesterv2/metrics"
+	ingestermodel "github.com/armadaproject/armada/internal/lookoutingesterv2/model"
+)
+
+func TestGetTemplateUsage(t *testing.T) {
+	err := lookout.WithLookoutDb(func(db *pgxpool.Pool) error {
+		converter := instructions.NewInstructionConverter(metrics.Get(), userAnnotationPrefix, &compress.NoOpCompressor{}, true)
+		store := lookoutdb.NewLookoutDb(db, metrics.Get(), 3, 10)
+
+		var usages []*ingestermodel.UpsertJobRunUsageInstruction
+		submit := func(queue string, annotations map[string]string, usedCpu int64, usedMemory int64) {
+			jobId := util.NewULID()
+			runId := uuid.NewString()
+			NewJobSimulator(converter, store).
+				Submit(queue, jobSet, owner, baseTime, &JobOptions{
+					JobId:       jobId,
+					Cpu:         resource.MustParse("4"),
+					Memory:      resource.MustParse("8Gi"),
+					Annotations: annotations,
+				}).
+				Pending(runId, cluster, baseTime).
+				Running(runId, node, baseTime).
+				Build()
+			usages = append(usages, &ingestermodel.UpsertJobRunUsageInstruction{
+				RunId:     runId,
+				JobId:     jobId,
+				MaxCpu:    usedCpu,
+				MaxMemory: usedMemory,
+				Updated:   baseTime,
+			})
+		}
+		for i := 0; i < 3; i++ {
+			submit(queue, map[string]string{"jobTemplate": "my-template"}, 1000, 2*1024*1024*1024)
+		}
+		submit(queue, map[string]string{"dagId": "my-dag", "taskId": "my-task"}, 500, 1024*1024*1024)
+		submit("queue-2", nil, 500, 1024*1024*1024)
+		require.NoError(t, store.UpsertJobRunUsagesBatch(armadacontext.TODO(), usages))
+
+		repo := NewSqlGetTemplateUsageRepository(db, userAnnotationPrefix)
+		usage, err := repo.GetTemplateUsage(armadacontext.TODO(), queue, baseTime, 1)
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			[]*rightsizing.Recommendation{
+				{
+					Queue:           queue,
+					Template:        "my-dag.my-task",
+					NumRuns:         1,
+					RequestedCpu:    4000,
+					RequestedMemory: 8 * 1024 * 1024 * 1024,
+					UsedCpu:         500,
+					UsedMemory:      1024 * 1024 * 1024,
+				},
+				{
+					Queue:           queue,
+					Template:        "my-template",
+					NumRuns:         3,
+					RequestedCpu:    4000,
+					RequestedMemory: 8 * 1024 * 1024 * 1024,
+					UsedCpu:         1000,
+					UsedMemory:      2 * 1024 * 1024 * 1024,
+				},
+			},
+			usage,
+		)
+
+		// Templates with too few runs are excluded.
+		usage, err = repo.GetTemplateUsage(armadacontext.TODO(), "", baseTime, 2)
+		require.NoError(t, err)
+		require.Len(t, usage, 1)
+		assert.Equal(t, "my-template", usage[0].Template)
+
+		// Usage reported before the given time is excluded.
+		usage, err = repo.GetTemplateUsage(armadacontext.TODO(), "", baseTime.Add(1), 1)
+		require.NoError(t, err)
+		assert.Empty(t, usage)
+		return nil
+	})
+	assert.NoError(t, err)
+}
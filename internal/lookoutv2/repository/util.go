@@ -59,6 +59,9 @@ type runPatch struct {
 	leased      *time.Time
 	pending     *time.Time
 	started     *time.Time
+	// Times at which the job was ingested and first considered for scheduling, as reported when the run was leased.
+	jobIngested     *time.Time
+	firstConsidered *time.Time
 }
 
 func NewJobSimulator(converter *instructions.InstructionConverter, store *lookoutdb.LookoutDb) *JobSimulator {
@@ -191,6 +194,20 @@ func (js *JobSimulator) Lease(runId string, timestamp time.Time) *JobSimulator {
 	return js
 }
 
+// LeaseWithStartTimes is like Lease, but also reports the times at which the job was ingested and first considered for scheduling.
+func (js *JobSimulator) LeaseWithStartTimes(runId string, jobIngested, firstConsidered, timestamp time.Time) *JobSimulator {
+	js.Lease(runId, timestamp)
+	leased := js.events[len(js.events)-1].GetJobRunLeased()
+	leased.JobIngested = &jobIngested
+	leased.FirstConsidered = &firstConsidered
+	updateRun(js.job, &runPatch{
+		runId:           runId,
+		jobIngested:     &jobIngested,
+		firstConsidered: &firstConsidered,
+	})
+	return js
+}
+
 func (js *JobSimulator) Pending(runId string, cluster string, timestamp time.Time) *JobSimulator {
 	ts := timestampOrNow(timestamp)
 	assignedEvent := &armadaevents.EventSequence_Event{
@@ -625,15 +642,17 @@ func updateRun(job *model.Job, patch *runPatch) {
 		state = *patch.jobRunState
 	}
 	job.Runs = append(job.Runs, &model.Run{
-		Cluster:     cluster,
-		ExitCode:    patch.exitCode,
-		Finished:    patch.finished,
-		JobRunState: state,
-		Node:        patch.node,
-		Leased:      patch.leased,
-		Pending:     patch.pending,
-		RunId:       patch.runId,
-		Started:     patch.started,
+		Cluster:         cluster,
+		ExitCode:        patch.exitCode,
+		Finished:        patch.finished,
+		JobRunState:     state,
+		Node:            patch.node,
+		Leased:          patch.leased,
+		Pending:         patch.pending,
+		RunId:           patch.runId,
+		Started:         patch.started,
+		JobIngested:     patch.jobIngested,
+		FirstConsidered: patch.firstConsidered,
 	})
 }
 
@@ -662,6 +681,12 @@ func patchRun(run *model.Run, patch *runPatch) {
 	if patch.started != nil {
 		run.Started = patch.started
 	}
+	if patch.jobIngested != nil {
+		run.JobIngested = patch.jobIngested
+	}
+	if patch.firstConsidered != nil {
+		run.FirstConsidered = patch.firstConsidered
+	}
 }
 
 func prefixAnnotations(prefix string, annotations map[string]string) map[string]string {
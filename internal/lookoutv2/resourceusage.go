@@ -0,0 +1,68 @@
+package lookoutv2
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/lookoutv2/repository"
+)
+
+type queueClusterResourceUsage struct {
+	Queue         string             `json:"queue"`
+	Cluster       string             `json:"cluster"`
+	ResourceHours map[string]float64 `json:"resourceHours"`
+}
+
+// resourceUsageHandler serves the resource-hours consumed per queue and cluster in the interval given by the
+// from and to query parameters, formatted as RFC3339. If to is not provided, it defaults to now.
+type resourceUsageHandler struct {
+	repo   repository.GetResourceUsageRepository
+	logger *logrus.Entry
+}
+
+func (h *resourceUsageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := armadacontext.New(r.Context(), h.logger)
+	query := r.URL.Query()
+	from, err := time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		http.Error(w, "from must be provided as an RFC3339 timestamp", http.StatusBadRequest)
+		return
+	}
+	to := time.Now()
+	if query.Get("to") != "" {
+		to, err = time.Parse(time.RFC3339, query.Get("to"))
+		if err != nil {
+			http.Error(w, "to must be an RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+	}
+	if !from.Before(to) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return
+	}
+	usage, err := h.repo.GetResourceUsage(ctx, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := struct {
+		From  time.Time                   `json:"from"`
+		To    time.Time                   `json:"to"`
+		Usage []queueClusterResourceUsage `json:"usage"`
+	}{
+		From:  from,
+		To:    to,
+		Usage: make([]queueClusterResourceUsage, len(usage)),
+	}
+	for i, u := range usage {
+		response.Usage[i] = queueClusterResourceUsage(*u)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.WithError(err).Error("failed to write resource usage response")
+	}
+}
@@ -0,0 +1,47 @@
+package lookoutv2
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/rightsizing"
+	"github.com/armadaproject/armada/internal/lookoutv2/configuration"
+	"github.com/armadaproject/armada/internal/lookoutv2/repository"
+)
+
+// rightsizingHandler serves resource recommendations for all job templates with enough recent runs,
+// optionally restricted to those of the queue given by the queue query parameter.
+type rightsizingHandler struct {
+	repo   repository.GetTemplateUsageRepository
+	config configuration.RightsizingConfig
+	logger *logrus.Entry
+}
+
+func (h *rightsizingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := armadacontext.New(r.Context(), h.logger)
+	since := time.Now().Add(-h.config.Lookback)
+	recommendations, err := h.repo.GetTemplateUsage(ctx, r.URL.Query().Get("queue"), since, h.config.MinRuns)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, recommendation := range recommendations {
+		recommendation.Recommend(h.config.Headroom)
+	}
+	if recommendations == nil {
+		recommendations = []*rightsizing.Recommendation{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	response := struct {
+		Recommendations []*rightsizing.Recommendation `json:"recommendations"`
+	}{
+		Recommendations: recommendations,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.WithError(err).Error("failed to write rightsizing response")
+	}
+}
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS job_run_usage (
+    run_id     varchar(36) NOT NULL PRIMARY KEY,
+    job_id     varchar(32) NOT NULL,
+    max_cpu    bigint      NOT NULL,
+    max_memory bigint      NOT NULL,
+    updated    timestamp   NOT NULL
+);
+
+CREATE INDEX idx_job_run_usage_job_id ON job_run_usage (job_id);
+CREATE INDEX idx_job_run_usage_updated ON job_run_usage (updated);
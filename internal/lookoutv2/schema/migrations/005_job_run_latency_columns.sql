@@ -0,0 +1,2 @@
+ALTER TABLE job_run ADD COLUMN job_ingested timestamp NULL;
+ALTER TABLE job_run ADD COLUMN first_considered timestamp NULL;
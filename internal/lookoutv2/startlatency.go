@@ -0,0 +1,71 @@
+package lookoutv2
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/lookoutv2/repository"
+)
+
+type queueStartLatency struct {
+	Queue      string  `json:"queue"`
+	Segment    string  `json:"segment"`
+	Count      int64   `json:"count"`
+	P50Seconds float64 `json:"p50Seconds"`
+	P90Seconds float64 `json:"p90Seconds"`
+	P99Seconds float64 `json:"p99Seconds"`
+}
+
+// startLatencyHandler serves, per queue, the distribution of the time spent in each segment of starting runs leased in
+// the interval given by the from and to query parameters, formatted as RFC3339. If to is not provided, it defaults to now.
+type startLatencyHandler struct {
+	repo   repository.GetStartLatencyRepository
+	logger *logrus.Entry
+}
+
+func (h *startLatencyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := armadacontext.New(r.Context(), h.logger)
+	query := r.URL.Query()
+	from, err := time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		http.Error(w, "from must be provided as an RFC3339 timestamp", http.StatusBadRequest)
+		return
+	}
+	to := time.Now()
+	if query.Get("to") != "" {
+		to, err = time.Parse(time.RFC3339, query.Get("to"))
+		if err != nil {
+			http.Error(w, "to must be an RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+	}
+	if !from.Before(to) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return
+	}
+	latencies, err := h.repo.GetStartLatency(ctx, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := struct {
+		From      time.Time           `json:"from"`
+		To        time.Time           `json:"to"`
+		Latencies []queueStartLatency `json:"latencies"`
+	}{
+		From:      from,
+		To:        to,
+		Latencies: make([]queueStartLatency, len(latencies)),
+	}
+	for i, l := range latencies {
+		response.Latencies[i] = queueStartLatency(*l)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.WithError(err).Error("failed to write start latency response")
+	}
+}
@@ -0,0 +1,61 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/notify"
+)
+
+type NotifierConfiguration struct {
+	// Metrics configuration
+	Metrics configuration.MetricsConfig
+	// General Pulsar configuration
+	Pulsar configuration.PulsarConfig
+	// Pulsar subscription name
+	SubscriptionName string
+	// Number of messages that will be batched together before being processed
+	BatchSize int
+	// Maximum time since the last batch before a batch will be processed
+	BatchDuration time.Duration
+	// Smtp server used to send emails.
+	Smtp notify.SmtpConfig
+	// How often digests are sent, e.g., 24h for a daily digest.
+	DigestInterval time.Duration
+	// Notification preferences. A job event may match several preferences,
+	// in which case a notification is sent for each matching preference.
+	Preferences []NotificationPreferences
+	// If non-nil, net/http/pprof endpoints are exposed on localhost on this port.
+	PprofPort *uint16
+}
+
+// NotificationPreferences describes which notifications should be sent for jobs of a particular queue and/or user,
+// and where those notifications should be sent.
+type NotificationPreferences struct {
+	// Name used to identify this set of preferences in logs and metrics.
+	Name string
+	// Only jobs in this queue match these preferences. Matches all queues if empty.
+	Queue string
+	// Only jobs submitted by this user match these preferences. Matches all users if empty.
+	User string
+	// Send a notification immediately when a job fails.
+	OnFailure bool
+	// Send a notification immediately when a job is preempted.
+	OnPreemption bool
+	// Include jobs in the periodic digest of completed job sets.
+	Digest bool
+	// Destinations. Immediate notifications are sent to the webhook and Slack destinations;
+	// digests are sent to all destinations, including email.
+	WebhookUrl      string
+	SlackWebhookUrl string
+	Emails          []string
+	// Optional text/template overrides used to render messages.
+	// If empty, the default templates are used.
+	Templates Templates
+}
+
+type Templates struct {
+	Failure    string
+	Preemption string
+	Digest     string
+}
@@ -0,0 +1,124 @@
+package convert
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/ingest"
+	"github.com/armadaproject/armada/internal/common/ingest/metrics"
+	"github.com/armadaproject/armada/internal/notifier/model"
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+// EventConverter extracts the job events relevant for notifications from event sequences.
+type EventConverter struct {
+	metrics *metrics.Metrics
+}
+
+func NewEventConverter(metrics *metrics.Metrics) ingest.InstructionConverter[*model.BatchUpdate] {
+	return &EventConverter{
+		metrics: metrics,
+	}
+}
+
+func (ec *EventConverter) Convert(_ *armadacontext.Context, sequencesWithIds *ingest.EventSequencesWithIds) *model.BatchUpdate {
+	events := make([]*model.JobEvent, 0)
+	for _, es := range sequencesWithIds.EventSequences {
+		for _, event := range es.Events {
+			jobEvent, err := ec.convertEvent(event)
+			if err != nil {
+				ec.metrics.RecordPulsarMessageError(metrics.PulsarMessageErrorProcessing)
+				log.WithError(err).Warnf("Could not convert event for job set %s in queue %s", es.JobSetName, es.Queue)
+				continue
+			}
+			if jobEvent == nil {
+				continue
+			}
+			jobEvent.Queue = es.Queue
+			jobEvent.JobSet = es.JobSetName
+			jobEvent.User = es.UserId
+			events = append(events, jobEvent)
+		}
+	}
+	return &model.BatchUpdate{
+		MessageIds: sequencesWithIds.MessageIds,
+		Events:     events,
+	}
+}
+
+// convertEvent returns the job event corresponding to the provided event,
+// or nil if the event isn't relevant for notifications.
+func (ec *EventConverter) convertEvent(event *armadaevents.EventSequence_Event) (*model.JobEvent, error) {
+	var eventType model.JobEventType
+	var protoJobId *armadaevents.Uuid
+	message := ""
+	switch e := event.Event.(type) {
+	case *armadaevents.EventSequence_Event_JobSucceeded:
+		eventType = model.JobSucceeded
+		protoJobId = e.JobSucceeded.JobId
+	case *armadaevents.EventSequence_Event_CancelledJob:
+		eventType = model.JobCancelled
+		protoJobId = e.CancelledJob.JobId
+		message = e.CancelledJob.Reason
+	case *armadaevents.EventSequence_Event_JobRunPreempted:
+		eventType = model.JobPreempted
+		protoJobId = e.JobRunPreempted.PreemptedJobId
+	case *armadaevents.EventSequence_Event_JobErrors:
+		var terminalError *armadaevents.Error
+		for _, e := range e.JobErrors.Errors {
+			if e.Terminal {
+				terminalError = e
+				break
+			}
+		}
+		if terminalError == nil {
+			return nil, nil
+		}
+		// Preempted jobs are reported via the corresponding JobRunPreempted event.
+		if terminalError.GetJobRunPreemptedError() != nil {
+			return nil, nil
+		}
+		eventType = model.JobFailed
+		protoJobId = e.JobErrors.JobId
+		message = errorMessage(terminalError)
+	default:
+		return nil, nil
+	}
+	jobId, err := armadaevents.UlidStringFromProtoUuid(protoJobId)
+	if err != nil {
+		return nil, err
+	}
+	var ts time.Time
+	if event.Created != nil {
+		ts = *event.Created
+	}
+	return &model.JobEvent{
+		Type:    eventType,
+		JobId:   jobId,
+		Time:    ts,
+		Message: message,
+	}, nil
+}
+
+func errorMessage(e *armadaevents.Error) string {
+	switch reason := e.Reason.(type) {
+	case *armadaevents.Error_PodError:
+		return reason.PodError.GetMessage()
+	case *armadaevents.Error_PodUnschedulable:
+		return reason.PodUnschedulable.GetMessage()
+	case *armadaevents.Error_MaxRunsExceeded:
+		return reason.MaxRunsExceeded.GetMessage()
+	case *armadaevents.Error_PodLeaseReturned:
+		return reason.PodLeaseReturned.GetMessage()
+	case *armadaevents.Error_PodTerminated:
+		return reason.PodTerminated.GetMessage()
+	case *armadaevents.Error_GangJobUnschedulable:
+		return reason.GangJobUnschedulable.GetMessage()
+	case *armadaevents.Error_LeaseExpired:
+		return "lease expired"
+	default:
+		return ""
+	}
+}
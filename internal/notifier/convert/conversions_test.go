@@ -0,0 +1,178 @@
+package convert
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/ingest"
+	"github.com/armadaproject/armada/internal/common/pulsarutils"
+	"github.com/armadaproject/armada/internal/notifier/metrics"
+	"github.com/armadaproject/armada/internal/notifier/model"
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+const (
+	jobset      = "testJobset"
+	queue       = "testQueue"
+	user        = "testUser"
+	jobIdString = "01f3j0g1md4qx7z5qb148qnh4r"
+)
+
+var (
+	jobIdProto, _ = armadaevents.ProtoUuidFromUlidString(jobIdString)
+	baseTime, _   = time.Parse("2006-01-02T15:04:05.000Z", "2022-03-01T15:04:05.000Z")
+)
+
+var succeeded = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_JobSucceeded{
+		JobSucceeded: &armadaevents.JobSucceeded{
+			JobId: jobIdProto,
+		},
+	},
+}
+
+var cancelled = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_CancelledJob{
+		CancelledJob: &armadaevents.CancelledJob{
+			JobId:  jobIdProto,
+			Reason: "user request",
+		},
+	},
+}
+
+var preempted = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_JobRunPreempted{
+		JobRunPreempted: &armadaevents.JobRunPreempted{
+			PreemptedJobId: jobIdProto,
+		},
+	},
+}
+
+var failed = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_JobErrors{
+		JobErrors: &armadaevents.JobErrors{
+			JobId: jobIdProto,
+			Errors: []*armadaevents.Error{
+				{
+					Terminal: true,
+					Reason: &armadaevents.Error_PodError{
+						PodError: &armadaevents.PodError{Message: "oom"},
+					},
+				},
+			},
+		},
+	},
+}
+
+var nonTerminalError = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_JobErrors{
+		JobErrors: &armadaevents.JobErrors{
+			JobId: jobIdProto,
+			Errors: []*armadaevents.Error{
+				{
+					Terminal: false,
+					Reason: &armadaevents.Error_PodError{
+						PodError: &armadaevents.PodError{Message: "oom"},
+					},
+				},
+			},
+		},
+	},
+}
+
+var preemptedError = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_JobErrors{
+		JobErrors: &armadaevents.JobErrors{
+			JobId: jobIdProto,
+			Errors: []*armadaevents.Error{
+				{
+					Terminal: true,
+					Reason: &armadaevents.Error_JobRunPreemptedError{
+						JobRunPreemptedError: &armadaevents.JobRunPreemptedError{},
+					},
+				},
+			},
+		},
+	},
+}
+
+var running = &armadaevents.EventSequence_Event{
+	Created: &baseTime,
+	Event: &armadaevents.EventSequence_Event_JobRunRunning{
+		JobRunRunning: &armadaevents.JobRunRunning{
+			JobId: jobIdProto,
+		},
+	},
+}
+
+func TestConvert(t *testing.T) {
+	tests := map[string]struct {
+		events   []*armadaevents.EventSequence_Event
+		expected []*model.JobEvent
+	}{
+		"succeeded": {
+			events:   []*armadaevents.EventSequence_Event{succeeded},
+			expected: []*model.JobEvent{expectedEvent(model.JobSucceeded, "")},
+		},
+		"cancelled": {
+			events:   []*armadaevents.EventSequence_Event{cancelled},
+			expected: []*model.JobEvent{expectedEvent(model.JobCancelled, "user request")},
+		},
+		"preempted": {
+			events:   []*armadaevents.EventSequence_Event{preempted, preemptedError},
+			expected: []*model.JobEvent{expectedEvent(model.JobPreempted, "")},
+		},
+		"failed": {
+			events:   []*armadaevents.EventSequence_Event{nonTerminalError, failed},
+			expected: []*model.JobEvent{expectedEvent(model.JobFailed, "oom")},
+		},
+		"irrelevant events are ignored": {
+			events:   []*armadaevents.EventSequence_Event{running},
+			expected: []*model.JobEvent{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			msg := NewMsg(tc.events...)
+			batchUpdate := NewEventConverter(metrics.Get()).Convert(armadacontext.Background(), msg)
+			assert.Equal(t, msg.MessageIds, batchUpdate.MessageIds)
+			assert.Equal(t, tc.expected, batchUpdate.Events)
+		})
+	}
+}
+
+func expectedEvent(eventType model.JobEventType, message string) *model.JobEvent {
+	return &model.JobEvent{
+		Type:    eventType,
+		Queue:   queue,
+		JobSet:  jobset,
+		User:    user,
+		JobId:   jobIdString,
+		Time:    baseTime,
+		Message: message,
+	}
+}
+
+func NewMsg(event ...*armadaevents.EventSequence_Event) *ingest.EventSequencesWithIds {
+	seq := &armadaevents.EventSequence{
+		Queue:      queue,
+		JobSetName: jobset,
+		UserId:     user,
+		Events:     event,
+	}
+	return &ingest.EventSequencesWithIds{
+		EventSequences: []*armadaevents.EventSequence{seq},
+		MessageIds:     []pulsar.MessageID{pulsarutils.NewMessageId(rand.Int())},
+	}
+}
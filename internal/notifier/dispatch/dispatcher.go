@@ -0,0 +1,205 @@
+package dispatch
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/logging"
+	"github.com/armadaproject/armada/internal/common/notify"
+	"github.com/armadaproject/armada/internal/notifier/configuration"
+	"github.com/armadaproject/armada/internal/notifier/model"
+)
+
+// Dispatcher sends notifications for job events according to the configured notification preferences.
+// Failure and preemption notifications are sent immediately, whereas completed jobs are accumulated
+// and sent periodically as a digest.
+//
+// Dispatcher implements ingest.Sink. Since sending a notification is not idempotent,
+// failures to send are logged rather than retried.
+type Dispatcher struct {
+	preferences []*preference
+	clock       clock.Clock
+	// Start of the current digest period.
+	digestStart time.Time
+	// Job set summaries for the current digest period, indexed by preference.
+	digests []map[jobSetKey]*JobSetSummary
+	mu      sync.Mutex
+}
+
+type preference struct {
+	configuration.NotificationPreferences
+	templates *templates
+	// Senders used for failure and preemption notifications.
+	immediateSenders []notify.Sender
+	// Senders used for digests.
+	digestSenders []notify.Sender
+}
+
+type jobSetKey struct {
+	queue  string
+	jobSet string
+}
+
+func NewDispatcher(preferences []configuration.NotificationPreferences, smtpConfig notify.SmtpConfig) (*Dispatcher, error) {
+	return newDispatcher(preferences, func(p configuration.NotificationPreferences) ([]notify.Sender, []notify.Sender) {
+		var immediate []notify.Sender
+		if p.WebhookUrl != "" {
+			immediate = append(immediate, notify.NewWebhookSender(p.WebhookUrl))
+		}
+		if p.SlackWebhookUrl != "" {
+			immediate = append(immediate, notify.NewSlackSender(p.SlackWebhookUrl))
+		}
+		digest := append([]notify.Sender{}, immediate...)
+		if len(p.Emails) > 0 {
+			digest = append(digest, notify.NewEmailSender(smtpConfig, p.Emails))
+		}
+		return immediate, digest
+	}, clock.RealClock{})
+}
+
+func newDispatcher(
+	preferences []configuration.NotificationPreferences,
+	createSenders func(configuration.NotificationPreferences) ([]notify.Sender, []notify.Sender),
+	clock clock.Clock,
+) (*Dispatcher, error) {
+	rv := &Dispatcher{
+		preferences: make([]*preference, len(preferences)),
+		digests:     make([]map[jobSetKey]*JobSetSummary, len(preferences)),
+		clock:       clock,
+		digestStart: clock.Now(),
+	}
+	for i, p := range preferences {
+		templates, err := parseTemplates(p.Name, p.Templates.Failure, p.Templates.Preemption, p.Templates.Digest)
+		if err != nil {
+			return nil, err
+		}
+		immediate, digest := createSenders(p)
+		rv.preferences[i] = &preference{
+			NotificationPreferences: p,
+			templates:               templates,
+			immediateSenders:        immediate,
+			digestSenders:           digest,
+		}
+		rv.digests[i] = make(map[jobSetKey]*JobSetSummary)
+	}
+	return rv, nil
+}
+
+// Store sends immediate notifications for the events in the batch and records them for the next digest.
+func (d *Dispatcher) Store(ctx *armadacontext.Context, update *model.BatchUpdate) error {
+	for _, event := range update.Events {
+		for i, p := range d.preferences {
+			if !p.matches(event) {
+				continue
+			}
+			if (event.Type == model.JobFailed && p.OnFailure) || (event.Type == model.JobPreempted && p.OnPreemption) {
+				msg, err := p.templates.renderEvent(event)
+				if err != nil {
+					logging.WithStacktrace(ctx, err).Errorf("failed to render notification for preferences %s", p.Name)
+				} else {
+					send(ctx, p.Name, p.immediateSenders, msg)
+				}
+			}
+			if p.Digest {
+				d.recordForDigest(i, event)
+			}
+		}
+	}
+	return nil
+}
+
+// Run periodically sends digests until the context is cancelled.
+func (d *Dispatcher) Run(ctx *armadacontext.Context, interval time.Duration) error {
+	ticker := d.clock.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			d.SendDigests(ctx)
+		}
+	}
+}
+
+// SendDigests sends a digest of the job sets with completed jobs for each preference with digests enabled,
+// and starts a new digest period.
+func (d *Dispatcher) SendDigests(ctx *armadacontext.Context) {
+	d.mu.Lock()
+	from := d.digestStart
+	to := d.clock.Now()
+	digests := d.digests
+	d.digestStart = to
+	d.digests = make([]map[jobSetKey]*JobSetSummary, len(d.preferences))
+	for i := range d.digests {
+		d.digests[i] = make(map[jobSetKey]*JobSetSummary)
+	}
+	d.mu.Unlock()
+
+	for i, p := range d.preferences {
+		if len(digests[i]) == 0 {
+			continue
+		}
+		data := digestData{From: from, To: to}
+		for _, summary := range digests[i] {
+			data.JobSets = append(data.JobSets, summary)
+		}
+		sort.Slice(data.JobSets, func(i, j int) bool {
+			if data.JobSets[i].Queue != data.JobSets[j].Queue {
+				return data.JobSets[i].Queue < data.JobSets[j].Queue
+			}
+			return data.JobSets[i].JobSet < data.JobSets[j].JobSet
+		})
+		msg, err := p.templates.renderDigest(data)
+		if err != nil {
+			logging.WithStacktrace(ctx, err).Errorf("failed to render digest for preferences %s", p.Name)
+			continue
+		}
+		send(ctx, p.Name, p.digestSenders, msg)
+	}
+}
+
+func (d *Dispatcher) recordForDigest(i int, event *model.JobEvent) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := jobSetKey{queue: event.Queue, jobSet: event.JobSet}
+	summary, ok := d.digests[i][key]
+	if !ok {
+		summary = &JobSetSummary{Queue: event.Queue, JobSet: event.JobSet}
+		d.digests[i][key] = summary
+	}
+	switch event.Type {
+	case model.JobSucceeded:
+		summary.Succeeded++
+	case model.JobFailed:
+		summary.Failed++
+	case model.JobCancelled:
+		summary.Cancelled++
+	case model.JobPreempted:
+		summary.Preempted++
+	}
+}
+
+// matches returns true if the event is for a job covered by these preferences.
+// Events generated by Armada itself, e.g., preemptions, carry no user and thus only match preferences without a user.
+func (p *preference) matches(event *model.JobEvent) bool {
+	if p.Queue != "" && p.Queue != event.Queue {
+		return false
+	}
+	if p.User != "" && p.User != event.User {
+		return false
+	}
+	return true
+}
+
+func send(ctx *armadacontext.Context, name string, senders []notify.Sender, msg notify.Message) {
+	for _, sender := range senders {
+		if err := sender.Send(ctx, msg); err != nil {
+			logging.WithStacktrace(ctx, err).Warnf("failed to send notification for preferences %s", name)
+		}
+	}
+}
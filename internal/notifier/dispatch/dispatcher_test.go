@@ -0,0 +1,150 @@
+package dispatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/notify"
+	"github.com/armadaproject/armada/internal/notifier/configuration"
+	"github.com/armadaproject/armada/internal/notifier/model"
+)
+
+var baseTime, _ = time.Parse("2006-01-02T15:04:05.000Z", "2022-03-01T15:04:05.000Z")
+
+type recordingSender struct {
+	messages []notify.Message
+}
+
+func (s *recordingSender) Send(_ *armadacontext.Context, msg notify.Message) error {
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func TestDispatcher_Store(t *testing.T) {
+	tests := map[string]struct {
+		preferences       configuration.NotificationPreferences
+		events            []*model.JobEvent
+		expectedImmediate []string
+	}{
+		"failure notification": {
+			preferences:       configuration.NotificationPreferences{Queue: "queue-a", OnFailure: true},
+			events:            []*model.JobEvent{jobEvent(model.JobFailed, "queue-a", "user-a")},
+			expectedImmediate: []string{"Job job-id in job set job-set of queue queue-a failed at 2022-03-01T15:04:05Z.\nReason: oom"},
+		},
+		"preemption notification": {
+			preferences:       configuration.NotificationPreferences{OnPreemption: true},
+			events:            []*model.JobEvent{jobEvent(model.JobPreempted, "queue-a", "")},
+			expectedImmediate: []string{"Job job-id in job set job-set of queue queue-a was preempted at 2022-03-01T15:04:05Z."},
+		},
+		"custom template": {
+			preferences: configuration.NotificationPreferences{
+				OnFailure: true,
+				Templates: configuration.Templates{Failure: "{{ .Queue }}/{{ .JobId }}: {{ .Message }}"},
+			},
+			events:            []*model.JobEvent{jobEvent(model.JobFailed, "queue-a", "user-a")},
+			expectedImmediate: []string{"queue-a/job-id: oom"},
+		},
+		"other queue": {
+			preferences: configuration.NotificationPreferences{Queue: "queue-b", OnFailure: true},
+			events:      []*model.JobEvent{jobEvent(model.JobFailed, "queue-a", "user-a")},
+		},
+		"other user": {
+			preferences: configuration.NotificationPreferences{User: "user-b", OnFailure: true},
+			events:      []*model.JobEvent{jobEvent(model.JobFailed, "queue-a", "user-a")},
+		},
+		"notifications disabled": {
+			preferences: configuration.NotificationPreferences{},
+			events: []*model.JobEvent{
+				jobEvent(model.JobFailed, "queue-a", "user-a"),
+				jobEvent(model.JobPreempted, "queue-a", ""),
+			},
+		},
+		"succeeded jobs are only included in digests": {
+			preferences: configuration.NotificationPreferences{OnFailure: true, OnPreemption: true},
+			events:      []*model.JobEvent{jobEvent(model.JobSucceeded, "queue-a", "user-a")},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			sender := &recordingSender{}
+			dispatcher, err := newDispatcher(
+				[]configuration.NotificationPreferences{tc.preferences},
+				func(configuration.NotificationPreferences) ([]notify.Sender, []notify.Sender) {
+					return []notify.Sender{sender}, nil
+				},
+				clock.NewFakeClock(baseTime),
+			)
+			require.NoError(t, err)
+			err = dispatcher.Store(armadacontext.Background(), &model.BatchUpdate{Events: tc.events})
+			require.NoError(t, err)
+			var actual []string
+			for _, msg := range sender.messages {
+				actual = append(actual, msg.Text)
+			}
+			assert.Equal(t, tc.expectedImmediate, actual)
+		})
+	}
+}
+
+func TestDispatcher_SendDigests(t *testing.T) {
+	fakeClock := clock.NewFakeClock(baseTime)
+	sender := &recordingSender{}
+	dispatcher, err := newDispatcher(
+		[]configuration.NotificationPreferences{{Digest: true}},
+		func(configuration.NotificationPreferences) ([]notify.Sender, []notify.Sender) {
+			return nil, []notify.Sender{sender}
+		},
+		fakeClock,
+	)
+	require.NoError(t, err)
+
+	err = dispatcher.Store(armadacontext.Background(), &model.BatchUpdate{Events: []*model.JobEvent{
+		jobEvent(model.JobSucceeded, "queue-b", "user-a"),
+		jobEvent(model.JobSucceeded, "queue-a", "user-a"),
+		jobEvent(model.JobFailed, "queue-a", "user-a"),
+		jobEvent(model.JobPreempted, "queue-a", ""),
+		jobEvent(model.JobCancelled, "queue-a", "user-a"),
+	}})
+	require.NoError(t, err)
+
+	fakeClock.Step(24 * time.Hour)
+	dispatcher.SendDigests(armadacontext.Background())
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, "Armada job set digest", sender.messages[0].Title)
+	assert.Equal(
+		t,
+		"Job sets with completed jobs between 2022-03-01T15:04:05Z and 2022-03-02T15:04:05Z:\n\n"+
+			"queue-a/job-set: 1 succeeded, 1 failed, 1 cancelled, 1 preempted\n"+
+			"queue-b/job-set: 1 succeeded, 0 failed, 0 cancelled, 0 preempted",
+		sender.messages[0].Text,
+	)
+
+	// Nothing is sent if no jobs completed since the last digest.
+	dispatcher.SendDigests(armadacontext.Background())
+	assert.Len(t, sender.messages, 1)
+}
+
+func TestNewDispatcher_InvalidTemplate(t *testing.T) {
+	_, err := NewDispatcher(
+		[]configuration.NotificationPreferences{{Templates: configuration.Templates{Failure: "{{ .JobId "}}},
+		notify.SmtpConfig{},
+	)
+	assert.Error(t, err)
+}
+
+func jobEvent(eventType model.JobEventType, queue, user string) *model.JobEvent {
+	return &model.JobEvent{
+		Type:    eventType,
+		Queue:   queue,
+		JobSet:  "job-set",
+		User:    user,
+		JobId:   "job-id",
+		Time:    baseTime,
+		Message: "oom",
+	}
+}
@@ -0,0 +1,108 @@
+package dispatch
+
+import (
+	"bytes"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common/notify"
+	"github.com/armadaproject/armada/internal/notifier/model"
+)
+
+// Templates are rendered with a *model.JobEvent as data.
+const (
+	defaultFailureTemplate = `Job {{ .JobId }} in job set {{ .JobSet }} of queue {{ .Queue }} failed at {{ .Time.Format "2006-01-02T15:04:05Z07:00" }}.
+{{ if .Message }}Reason: {{ .Message }}{{ end }}`
+	defaultPreemptionTemplate = `Job {{ .JobId }} in job set {{ .JobSet }} of queue {{ .Queue }} was preempted at {{ .Time.Format "2006-01-02T15:04:05Z07:00" }}.`
+	// Rendered with a digestData as data.
+	defaultDigestTemplate = `Job sets with completed jobs between {{ .From.Format "2006-01-02T15:04:05Z07:00" }} and {{ .To.Format "2006-01-02T15:04:05Z07:00" }}:
+{{ range .JobSets }}
+{{ .Queue }}/{{ .JobSet }}: {{ .Succeeded }} succeeded, {{ .Failed }} failed, {{ .Cancelled }} cancelled, {{ .Preempted }} preempted
+{{- end }}`
+)
+
+type templates struct {
+	failure    *template.Template
+	preemption *template.Template
+	digest     *template.Template
+}
+
+// JobSetSummary counts the jobs of a job set that completed during a digest period.
+type JobSetSummary struct {
+	Queue     string
+	JobSet    string
+	Succeeded int
+	Failed    int
+	Cancelled int
+	Preempted int
+}
+
+type digestData struct {
+	From    time.Time
+	To      time.Time
+	JobSets []*JobSetSummary
+}
+
+func parseTemplates(name string, failure, preemption, digest string) (*templates, error) {
+	var err error
+	rv := &templates{}
+	if rv.failure, err = parseTemplate(name+"-failure", failure, defaultFailureTemplate); err != nil {
+		return nil, err
+	}
+	if rv.preemption, err = parseTemplate(name+"-preemption", preemption, defaultPreemptionTemplate); err != nil {
+		return nil, err
+	}
+	if rv.digest, err = parseTemplate(name+"-digest", digest, defaultDigestTemplate); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+func parseTemplate(name, text, defaultText string) (*template.Template, error) {
+	if text == "" {
+		text = defaultText
+	}
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse template %s", name)
+	}
+	return t, nil
+}
+
+func (t *templates) renderEvent(event *model.JobEvent) (notify.Message, error) {
+	var tmpl *template.Template
+	var title string
+	switch event.Type {
+	case model.JobFailed:
+		tmpl = t.failure
+		title = "Armada job failed"
+	case model.JobPreempted:
+		tmpl = t.preemption
+		title = "Armada job preempted"
+	default:
+		return notify.Message{}, errors.Errorf("no template for job event of type %s", event.Type)
+	}
+	text, err := render(tmpl, event)
+	if err != nil {
+		return notify.Message{}, err
+	}
+	return notify.Message{Title: title, Text: text}, nil
+}
+
+func (t *templates) renderDigest(data digestData) (notify.Message, error) {
+	text, err := render(t.digest, data)
+	if err != nil {
+		return notify.Message{}, err
+	}
+	return notify.Message{Title: "Armada job set digest", Text: text}, nil
+}
+
+func render(tmpl *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrapf(err, "failed to render template %s", tmpl.Name())
+	}
+	return buf.String(), nil
+}
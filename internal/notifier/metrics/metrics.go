@@ -0,0 +1,11 @@
+package metrics
+
+import (
+	"github.com/armadaproject/armada/internal/common/ingest/metrics"
+)
+
+var m = metrics.NewMetrics(metrics.ArmadaNotifierMetricsPrefix)
+
+func Get() *metrics.Metrics {
+	return m
+}
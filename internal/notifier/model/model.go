@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+type JobEventType string
+
+const (
+	JobSucceeded JobEventType = "succeeded"
+	JobFailed    JobEventType = "failed"
+	JobCancelled JobEventType = "cancelled"
+	JobPreempted JobEventType = "preempted"
+)
+
+// BatchUpdate represents the job events of interest for notifications
+// along with information about the originating pulsar messages.
+type BatchUpdate struct {
+	MessageIds []pulsar.MessageID
+	Events     []*JobEvent
+}
+
+func (b *BatchUpdate) GetMessageIDs() []pulsar.MessageID {
+	return b.MessageIds
+}
+
+type JobEvent struct {
+	Type    JobEventType
+	Queue   string
+	JobSet  string
+	User    string
+	JobId   string
+	Time    time.Time
+	Message string
+}
@@ -0,0 +1,67 @@
+package notifier
+
+import (
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/armadaproject/armada/internal/common/app"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/ingest"
+	"github.com/armadaproject/armada/internal/common/logging"
+	"github.com/armadaproject/armada/internal/common/profiling"
+	"github.com/armadaproject/armada/internal/common/serve"
+	"github.com/armadaproject/armada/internal/notifier/configuration"
+	"github.com/armadaproject/armada/internal/notifier/convert"
+	"github.com/armadaproject/armada/internal/notifier/dispatch"
+	"github.com/armadaproject/armada/internal/notifier/metrics"
+	"github.com/armadaproject/armada/internal/notifier/model"
+)
+
+// Run will create a pipeline that will take Armada event messages from Pulsar and send notifications
+// according to the configured notification preferences. This pipeline will run until a SIGTERM is received
+func Run(config *configuration.NotifierConfiguration) {
+	log.Info("Notifier Starting")
+
+	// Expose profiling endpoints if enabled.
+	pprofServer := profiling.SetupPprofHttpServer(config.PprofPort)
+	go func() {
+		ctx := armadacontext.Background()
+		if err := serve.ListenAndServe(ctx, pprofServer); err != nil {
+			logging.WithStacktrace(ctx, err).Error("pprof server failure")
+		}
+	}()
+
+	metrics := metrics.Get()
+
+	dispatcher, err := dispatch.NewDispatcher(config.Preferences, config.Smtp)
+	if err != nil {
+		panic(errors.WithMessage(err, "Error creating notification dispatcher"))
+	}
+	converter := convert.NewEventConverter(metrics)
+
+	ctx := app.CreateContextWithShutdown()
+	if config.DigestInterval > 0 {
+		go func() {
+			if err := dispatcher.Run(ctx, config.DigestInterval); err != nil {
+				logging.WithStacktrace(ctx, err).Error("digest loop failure")
+			}
+		}()
+	}
+
+	// Notifications are sent per event, so there's no need for events of a job set to be processed in order.
+	ingester := ingest.NewIngestionPipeline[*model.BatchUpdate](
+		config.Pulsar,
+		config.SubscriptionName,
+		config.BatchSize,
+		config.BatchDuration,
+		pulsar.Shared,
+		converter,
+		dispatcher,
+		config.Metrics,
+		metrics,
+	)
+	if err := ingester.Run(ctx); err != nil {
+		panic(errors.WithMessage(err, "Error running ingestion pipeline"))
+	}
+}
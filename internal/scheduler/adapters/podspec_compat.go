@@ -0,0 +1,173 @@
+package adapters
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+const (
+	seccompPodAnnotation             = "seccomp.security.alpha.kubernetes.io/pod"
+	seccompContainerAnnotationPrefix = "container.seccomp.security.alpha.kubernetes.io/"
+)
+
+// podSpecFeature is a pod spec feature only available on clusters running at least some Kubernetes version.
+type podSpecFeature struct {
+	name       string
+	minVersion *version.Version
+	// usedBy returns true if podSpec makes use of the feature.
+	usedBy func(podSpec *v1.PodSpec) bool
+	// fallback rewrites job such that it no longer uses the feature, preserving its behaviour as far as possible.
+	// Features without fallback are only scheduled onto clusters supporting them; see MinKubernetesVersionForPodSpec.
+	fallback func(job *armadaevents.SubmitJob, podSpec *v1.PodSpec)
+}
+
+// podSpecFeatures lists pod spec features introduced by Kubernetes versions more recent than the oldest one supported.
+// Features introduced after the version of the Kubernetes API Armada is built against (e.g., native sidecars and
+// in-place pod resize) can't be expressed in job specs and hence need not be listed.
+var podSpecFeatures = []podSpecFeature{
+	{
+		name:       "seccompProfile",
+		minVersion: version.MustParseGeneric("1.19"),
+		usedBy:     usesSeccompProfile,
+		fallback:   seccompProfileToAnnotations,
+	},
+	{
+		name:       "fsGroupChangePolicy",
+		minVersion: version.MustParseGeneric("1.20"),
+		usedBy: func(podSpec *v1.PodSpec) bool {
+			return podSpec.SecurityContext != nil && podSpec.SecurityContext.FSGroupChangePolicy != nil
+		},
+		// Without the policy, ownership is always changed; this is slower but otherwise equivalent.
+		fallback: func(_ *armadaevents.SubmitJob, podSpec *v1.PodSpec) {
+			podSpec.SecurityContext.FSGroupChangePolicy = nil
+		},
+	},
+	{
+		name:       "setHostnameAsFQDN",
+		minVersion: version.MustParseGeneric("1.20"),
+		usedBy: func(podSpec *v1.PodSpec) bool {
+			return podSpec.SetHostnameAsFQDN != nil && *podSpec.SetHostnameAsFQDN
+		},
+	},
+	{
+		name:       "ephemeralVolumes",
+		minVersion: version.MustParseGeneric("1.21"),
+		usedBy: func(podSpec *v1.PodSpec) bool {
+			for _, volume := range podSpec.Volumes {
+				if volume.Ephemeral != nil {
+					return true
+				}
+			}
+			return false
+		},
+	},
+}
+
+// MinKubernetesVersionForPodSpec returns the oldest Kubernetes version able to run podSpec,
+// or the empty string if podSpec only uses features that either all supported versions provide or that can be
+// rewritten by AdaptJobToKubernetesVersion.
+func MinKubernetesVersionForPodSpec(podSpec *v1.PodSpec) string {
+	if podSpec == nil {
+		return ""
+	}
+	var rv *version.Version
+	for _, feature := range podSpecFeatures {
+		if feature.fallback != nil || !feature.usedBy(podSpec) {
+			continue
+		}
+		if rv == nil || !rv.AtLeast(feature.minVersion) {
+			rv = feature.minVersion
+		}
+	}
+	if rv == nil {
+		return ""
+	}
+	return rv.String()
+}
+
+// AdaptJobToKubernetesVersion rewrites job in-place to replace pod spec features not available on clusters running
+// the given Kubernetes version with compatible alternatives. Returns the names of the features replaced.
+// Jobs are left unchanged if kubernetesVersion is empty or can't be parsed,
+// e.g., since the executor predates reporting its Kubernetes version.
+func AdaptJobToKubernetesVersion(job *armadaevents.SubmitJob, kubernetesVersion string) []string {
+	if job == nil || job.MainObject == nil || kubernetesVersion == "" {
+		return nil
+	}
+	typed, ok := job.MainObject.Object.(*armadaevents.KubernetesMainObject_PodSpec)
+	if !ok || typed.PodSpec == nil || typed.PodSpec.PodSpec == nil {
+		return nil
+	}
+	actual, err := version.ParseGeneric(kubernetesVersion)
+	if err != nil {
+		return nil
+	}
+	podSpec := typed.PodSpec.PodSpec
+	var rv []string
+	for _, feature := range podSpecFeatures {
+		if feature.fallback == nil || actual.AtLeast(feature.minVersion) || !feature.usedBy(podSpec) {
+			continue
+		}
+		feature.fallback(job, podSpec)
+		rv = append(rv, feature.name)
+	}
+	return rv
+}
+
+func usesSeccompProfile(podSpec *v1.PodSpec) bool {
+	if podSpec.SecurityContext != nil && podSpec.SecurityContext.SeccompProfile != nil {
+		return true
+	}
+	for _, containers := range [][]v1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for _, container := range containers {
+			if container.SecurityContext != nil && container.SecurityContext.SeccompProfile != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// seccompProfileToAnnotations replaces seccomp profile fields with the equivalent annotations,
+// which Kubernetes honoured prior to the fields being introduced.
+func seccompProfileToAnnotations(job *armadaevents.SubmitJob, podSpec *v1.PodSpec) {
+	if job.ObjectMeta == nil {
+		job.ObjectMeta = &armadaevents.ObjectMeta{}
+	}
+	if job.ObjectMeta.Annotations == nil {
+		job.ObjectMeta.Annotations = make(map[string]string)
+	}
+	if podSpec.SecurityContext != nil && podSpec.SecurityContext.SeccompProfile != nil {
+		if value := seccompAnnotationValue(podSpec.SecurityContext.SeccompProfile); value != "" {
+			job.ObjectMeta.Annotations[seccompPodAnnotation] = value
+		}
+		podSpec.SecurityContext.SeccompProfile = nil
+	}
+	for _, containers := range [][]v1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			securityContext := containers[i].SecurityContext
+			if securityContext == nil || securityContext.SeccompProfile == nil {
+				continue
+			}
+			if value := seccompAnnotationValue(securityContext.SeccompProfile); value != "" {
+				job.ObjectMeta.Annotations[seccompContainerAnnotationPrefix+containers[i].Name] = value
+			}
+			securityContext.SeccompProfile = nil
+		}
+	}
+}
+
+func seccompAnnotationValue(profile *v1.SeccompProfile) string {
+	switch profile.Type {
+	case v1.SeccompProfileTypeRuntimeDefault:
+		return "runtime/default"
+	case v1.SeccompProfileTypeUnconfined:
+		return "unconfined"
+	case v1.SeccompProfileTypeLocalhost:
+		if profile.LocalhostProfile != nil {
+			return "localhost/" + *profile.LocalhostProfile
+		}
+	}
+	return ""
+}
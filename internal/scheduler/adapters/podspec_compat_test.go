@@ -0,0 +1,146 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slices"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+func TestAdaptJobToKubernetesVersion(t *testing.T) {
+	onRootMismatch := v1.FSGroupChangeOnRootMismatch
+	podSpecUsingAllFeatures := func() *v1.PodSpec {
+		return &v1.PodSpec{
+			SecurityContext: &v1.PodSecurityContext{
+				SeccompProfile:      &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault},
+				FSGroupChangePolicy: &onRootMismatch,
+			},
+			InitContainers: []v1.Container{
+				{
+					Name: "init",
+					SecurityContext: &v1.SecurityContext{
+						SeccompProfile: &v1.SeccompProfile{Type: v1.SeccompProfileTypeUnconfined},
+					},
+				},
+			},
+			Containers: []v1.Container{
+				{
+					Name: "main",
+					SecurityContext: &v1.SecurityContext{
+						SeccompProfile: &v1.SeccompProfile{
+							Type:             v1.SeccompProfileTypeLocalhost,
+							LocalhostProfile: pointer.String("profiles/audit.json"),
+						},
+					},
+				},
+				{Name: "sidecar"},
+			},
+			SetHostnameAsFQDN: pointer.Bool(true),
+		}
+	}
+	tests := map[string]struct {
+		kubernetesVersion   string
+		expectedAdapted     []string
+		expectedAnnotations map[string]string
+	}{
+		"version not reported": {
+			kubernetesVersion: "",
+		},
+		"invalid version": {
+			kubernetesVersion: "latest",
+		},
+		"all features supported": {
+			kubernetesVersion: "v1.27.3",
+		},
+		"fsGroupChangePolicy not supported": {
+			kubernetesVersion: "v1.19.16",
+			expectedAdapted:   []string{"fsGroupChangePolicy"},
+		},
+		"no features supported": {
+			kubernetesVersion: "v1.18.20",
+			expectedAdapted:   []string{"seccompProfile", "fsGroupChangePolicy"},
+			expectedAnnotations: map[string]string{
+				"seccomp.security.alpha.kubernetes.io/pod":            "runtime/default",
+				"container.seccomp.security.alpha.kubernetes.io/init": "unconfined",
+				"container.seccomp.security.alpha.kubernetes.io/main": "localhost/profiles/audit.json",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			podSpec := podSpecUsingAllFeatures()
+			job := &armadaevents.SubmitJob{
+				MainObject: &armadaevents.KubernetesMainObject{
+					Object: &armadaevents.KubernetesMainObject_PodSpec{
+						PodSpec: &armadaevents.PodSpecWithAvoidList{PodSpec: podSpec},
+					},
+				},
+			}
+			usedBefore := make(map[string]bool, len(podSpecFeatures))
+			for _, feature := range podSpecFeatures {
+				usedBefore[feature.name] = feature.usedBy(podSpec)
+			}
+			adapted := AdaptJobToKubernetesVersion(job, tc.kubernetesVersion)
+			assert.Equal(t, tc.expectedAdapted, adapted)
+			if tc.expectedAnnotations != nil {
+				assert.Equal(t, tc.expectedAnnotations, job.ObjectMeta.Annotations)
+			} else {
+				assert.Nil(t, job.ObjectMeta)
+			}
+			for _, feature := range podSpecFeatures {
+				// Features without fallback are gated at scheduling time instead and so are never removed.
+				expectUsed := usedBefore[feature.name] && (feature.fallback == nil || !slices.Contains(adapted, feature.name))
+				assert.Equal(t, expectUsed, feature.usedBy(podSpec), feature.name)
+			}
+		})
+	}
+}
+
+func TestMinKubernetesVersionForPodSpec(t *testing.T) {
+	tests := map[string]struct {
+		podSpec  *v1.PodSpec
+		expected string
+	}{
+		"nil": {
+			podSpec:  nil,
+			expected: "",
+		},
+		"no gated features": {
+			podSpec: &v1.PodSpec{
+				SecurityContext: &v1.PodSecurityContext{
+					SeccompProfile: &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault},
+				},
+			},
+			expected: "",
+		},
+		"setHostnameAsFQDN": {
+			podSpec:  &v1.PodSpec{SetHostnameAsFQDN: pointer.Bool(true)},
+			expected: "1.20",
+		},
+		"setHostnameAsFQDN disabled": {
+			podSpec:  &v1.PodSpec{SetHostnameAsFQDN: pointer.Bool(false)},
+			expected: "",
+		},
+		"most recent feature determines version": {
+			podSpec: &v1.PodSpec{
+				SetHostnameAsFQDN: pointer.Bool(true),
+				Volumes: []v1.Volume{
+					{
+						Name:         "scratch",
+						VolumeSource: v1.VolumeSource{Ephemeral: &v1.EphemeralVolumeSource{}},
+					},
+				},
+			},
+			expected: "1.21",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, MinKubernetesVersionForPodSpec(tc.podSpec))
+		})
+	}
+}
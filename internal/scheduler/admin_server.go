@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// SchedulingRoundTrigger runs scheduling rounds on request; implemented by Scheduler.
+type SchedulingRoundTrigger interface {
+	TriggerSchedulingRound(ctx *armadacontext.Context, scope RoundScope) (*SchedulerResult, error)
+}
+
+// SchedulerAdminServer serves admin requests by acting on the local scheduler.
+// Callers are authorised by the Armada server, which proxies requests to the scheduler.
+type SchedulerAdminServer struct {
+	trigger SchedulingRoundTrigger
+}
+
+func NewSchedulerAdminServer(trigger SchedulingRoundTrigger) *SchedulerAdminServer {
+	return &SchedulerAdminServer{trigger: trigger}
+}
+
+func (s *SchedulerAdminServer) TriggerSchedulingRound(grpcCtx context.Context, req *schedulerobjects.TriggerSchedulingRoundRequest) (*schedulerobjects.TriggerSchedulingRoundResponse, error) {
+	ctx := armadacontext.FromGrpcCtx(grpcCtx)
+	result, err := s.trigger.TriggerSchedulingRound(ctx, RoundScope{Pool: req.Pool, Queue: req.Queue})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "[TriggerSchedulingRound] error: %s", err)
+	}
+	return &schedulerobjects.TriggerSchedulingRoundResponse{Report: schedulingRoundReport(result)}, nil
+}
+
+// schedulingRoundReport returns a human-readable summary of the decisions made in a scheduling round.
+func schedulingRoundReport(result *SchedulerResult) string {
+	var sb strings.Builder
+	fmt.Fprintf(
+		&sb, "Scheduled %d jobs, preempted %d jobs, and failed %d jobs\n",
+		len(result.ScheduledJobs), len(result.PreemptedJobs), len(result.FailedJobs),
+	)
+	for _, sctx := range result.SchedulingContexts {
+		fmt.Fprintf(&sb, "\nPool %s, executor %s:\n", sctx.Pool, sctx.ExecutorId)
+		sb.WriteString(sctx.ReportString(0))
+	}
+	return sb.String()
+}
+
+// LeaderProxyingSchedulerAdminServer serves admin requests locally if this scheduler is leader,
+// and forwards them to the leader otherwise, since only the leader runs scheduling rounds.
+type LeaderProxyingSchedulerAdminServer struct {
+	localAdminServer     schedulerobjects.SchedulerAdminServer
+	leaderClientProvider LeaderClientConnectionProvider
+	adminClientProvider  func(conn *grpc.ClientConn) schedulerobjects.SchedulerAdminClient
+}
+
+func NewLeaderProxyingSchedulerAdminServer(
+	localAdminServer schedulerobjects.SchedulerAdminServer,
+	leaderClientProvider LeaderClientConnectionProvider,
+) *LeaderProxyingSchedulerAdminServer {
+	return &LeaderProxyingSchedulerAdminServer{
+		localAdminServer:     localAdminServer,
+		leaderClientProvider: leaderClientProvider,
+		adminClientProvider:  schedulerobjects.NewSchedulerAdminClient,
+	}
+}
+
+func (s *LeaderProxyingSchedulerAdminServer) TriggerSchedulingRound(ctx context.Context, request *schedulerobjects.TriggerSchedulingRoundRequest) (*schedulerobjects.TriggerSchedulingRoundResponse, error) {
+	isCurrentProcessLeader, leaderConnection, err := s.leaderClientProvider.GetCurrentLeaderClientConnection()
+	if isCurrentProcessLeader {
+		return s.localAdminServer.TriggerSchedulingRound(ctx, request)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.adminClientProvider(leaderConnection).TriggerSchedulingRound(ctx, request)
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestSchedulerAdminServer_TriggerSchedulingRound(t *testing.T) {
+	job := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0)
+	trigger := &testSchedulingRoundTrigger{result: NewSchedulerResultForTest([]*jobdb.Job{}, []*jobdb.Job{job}, []*jobdb.Job{}, nil)}
+	server := NewSchedulerAdminServer(trigger)
+
+	resp, err := server.TriggerSchedulingRound(armadacontext.Background(), &schedulerobjects.TriggerSchedulingRoundRequest{Pool: "cpu", Queue: "A"})
+	require.NoError(t, err)
+	assert.Equal(t, []RoundScope{{Pool: "cpu", Queue: "A"}}, trigger.scopes)
+	assert.Contains(t, resp.Report, "Scheduled 1 jobs, preempted 0 jobs, and failed 0 jobs")
+
+	trigger.err = errors.New("another triggered scheduling round is pending")
+	_, err = server.TriggerSchedulingRound(armadacontext.Background(), &schedulerobjects.TriggerSchedulingRoundRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestLeaderProxyingSchedulerAdminServer_TriggerSchedulingRound(t *testing.T) {
+	for name, isCurrentProcessLeader := range map[string]bool{"current process leader": true, "remote process is leader": false} {
+		t.Run(name, func(t *testing.T) {
+			localServer := &testSchedulerAdminServer{}
+			remoteServer := &testSchedulerAdminClient{}
+			clientProvider := NewFakeClientProvider()
+			clientProvider.IsCurrentProcessLeader = isCurrentProcessLeader
+			sut := NewLeaderProxyingSchedulerAdminServer(localServer, clientProvider)
+			sut.adminClientProvider = func(*grpc.ClientConn) schedulerobjects.SchedulerAdminClient { return remoteServer }
+
+			_, err := sut.TriggerSchedulingRound(armadacontext.Background(), &schedulerobjects.TriggerSchedulingRoundRequest{})
+			require.NoError(t, err)
+			if isCurrentProcessLeader {
+				assert.Equal(t, 1, localServer.numCalls)
+				assert.Equal(t, 0, remoteServer.numCalls)
+			} else {
+				assert.Equal(t, 0, localServer.numCalls)
+				assert.Equal(t, 1, remoteServer.numCalls)
+			}
+		})
+	}
+}
+
+type testSchedulingRoundTrigger struct {
+	scopes []RoundScope
+	result *SchedulerResult
+	err    error
+}
+
+func (t *testSchedulingRoundTrigger) TriggerSchedulingRound(_ *armadacontext.Context, scope RoundScope) (*SchedulerResult, error) {
+	t.scopes = append(t.scopes, scope)
+	return t.result, t.err
+}
+
+type testSchedulerAdminServer struct {
+	numCalls int
+}
+
+func (s *testSchedulerAdminServer) TriggerSchedulingRound(_ context.Context, _ *schedulerobjects.TriggerSchedulingRoundRequest) (*schedulerobjects.TriggerSchedulingRoundResponse, error) {
+	s.numCalls++
+	return &schedulerobjects.TriggerSchedulingRoundResponse{}, nil
+}
+
+type testSchedulerAdminClient struct {
+	numCalls int
+}
+
+func (c *testSchedulerAdminClient) TriggerSchedulingRound(_ context.Context, _ *schedulerobjects.TriggerSchedulingRoundRequest, _ ...grpc.CallOption) (*schedulerobjects.TriggerSchedulingRoundResponse, error) {
+	c.numCalls++
+	return &schedulerobjects.TriggerSchedulingRoundResponse{}, nil
+}
@@ -0,0 +1,325 @@
+package scheduler
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/logging"
+	"github.com/armadaproject/armada/internal/common/notify"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+)
+
+type AlertType string
+
+const (
+	// A queue with demand has been unable to schedule any jobs for several consecutive rounds while below its fair share.
+	AlertStarvation AlertType = "starvation"
+	// An executor hasn't sent a heartbeat within the executor timeout; the scheduler's view of the cluster is stale.
+	AlertStaleClusterSnapshot AlertType = "staleClusterSnapshot"
+	// A scheduling round was cut short by the maximum scheduling duration.
+	AlertRoundDeadlineExceeded AlertType = "roundDeadlineExceeded"
+	// A queue is using significantly more than its fair share while other queues are unable to schedule.
+	AlertFairShareBreach AlertType = "fairShareBreach"
+	// A drained executor cluster has no more jobs running on it and may be decommissioned.
+	AlertClusterDrained AlertType = "clusterDrained"
+	// A queue has at least as many queued jobs as the configured queue depth threshold.
+	AlertQueueDepth AlertType = "queueDepth"
+	// A scheduling round differs from the previous round in a way likely caused by a configuration or code change,
+	// e.g., a sudden drop in the number of jobs scheduled.
+	AlertRoundRegression AlertType = "roundRegression"
+	// A synthetic canary job failed or took longer than the configured maximum latency to succeed.
+	AlertCanary AlertType = "canary"
+)
+
+// Alert is an operator-facing notification about the health of scheduling in a pool.
+type Alert struct {
+	Type AlertType
+	// Empty for alerts not specific to any pool.
+	Pool string
+	// The queue or executor the alert is about; may be empty.
+	Subject string
+	Text    string
+	// Optional deep link, e.g., to Lookout.
+	Link string
+}
+
+// Alerter receives alerts raised by the scheduler. Implementations must not block.
+type Alerter interface {
+	Alert(alert Alert)
+}
+
+type NoOpAlerter struct{}
+
+func (NoOpAlerter) Alert(Alert) {}
+
+type alertKey struct {
+	alertType AlertType
+	pool      string
+	subject   string
+}
+
+type alertDestination struct {
+	pools   map[string]bool
+	alerts  map[AlertType]bool
+	senders []notify.Sender
+	limiter *rate.Limiter
+	// Time at which an alert was last sent to this destination.
+	lastSentByKey map[alertKey]time.Time
+}
+
+// NotifyingAlerter sends alerts to Slack and Microsoft Teams.
+// Alerts are queued and sent asynchronously by Run, such that raising an alert never blocks scheduling.
+// Repeated alerts of the same type for the same pool and subject are suppressed for MinAlertInterval.
+type NotifyingAlerter struct {
+	config       schedulerconfig.AlertingConfig
+	destinations []*alertDestination
+	alerts       chan Alert
+	clock        clock.Clock
+	mu           sync.Mutex
+}
+
+func NewNotifyingAlerter(config schedulerconfig.AlertingConfig) *NotifyingAlerter {
+	return newNotifyingAlerter(config, func(destination schedulerconfig.AlertDestination) []notify.Sender {
+		var senders []notify.Sender
+		if destination.SlackWebhookUrl != "" {
+			senders = append(senders, notify.NewSlackSender(destination.SlackWebhookUrl))
+		}
+		if destination.TeamsWebhookUrl != "" {
+			senders = append(senders, notify.NewTeamsSender(destination.TeamsWebhookUrl))
+		}
+		return senders
+	}, clock.RealClock{})
+}
+
+func newNotifyingAlerter(
+	config schedulerconfig.AlertingConfig,
+	createSenders func(schedulerconfig.AlertDestination) []notify.Sender,
+	clock clock.Clock,
+) *NotifyingAlerter {
+	limit := rate.Inf
+	burst := 1
+	if config.MaxAlertsPerMinute > 0 {
+		limit = rate.Limit(float64(config.MaxAlertsPerMinute) / 60)
+		burst = config.MaxAlertsPerMinute
+	}
+	destinations := make([]*alertDestination, len(config.Destinations))
+	for i, destination := range config.Destinations {
+		d := &alertDestination{
+			senders:       createSenders(destination),
+			limiter:       rate.NewLimiter(limit, burst),
+			lastSentByKey: make(map[alertKey]time.Time),
+		}
+		if len(destination.Pools) > 0 {
+			d.pools = make(map[string]bool)
+			for _, pool := range destination.Pools {
+				d.pools[pool] = true
+			}
+		}
+		if len(destination.Alerts) > 0 {
+			d.alerts = make(map[AlertType]bool)
+			for _, alert := range destination.Alerts {
+				d.alerts[AlertType(alert)] = true
+			}
+		}
+		destinations[i] = d
+	}
+	return &NotifyingAlerter{
+		config:       config,
+		destinations: destinations,
+		alerts:       make(chan Alert, 100),
+		clock:        clock,
+	}
+}
+
+// Alert queues an alert to be sent. If the queue is full, the alert is dropped.
+func (a *NotifyingAlerter) Alert(alert Alert) {
+	if alert.Link == "" && a.config.LookoutQueueUrlFormat != "" && alert.Type != AlertStaleClusterSnapshot && alert.Type != AlertClusterDrained && alert.Type != AlertRoundRegression && alert.Subject != "" {
+		alert.Link = fmt.Sprintf(a.config.LookoutQueueUrlFormat, url.QueryEscape(alert.Subject))
+	}
+	select {
+	case a.alerts <- alert:
+	default:
+	}
+}
+
+// Run sends queued alerts until the context is cancelled.
+func (a *NotifyingAlerter) Run(ctx *armadacontext.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case alert := <-a.alerts:
+			a.send(ctx, alert)
+		}
+	}
+}
+
+func (a *NotifyingAlerter) send(ctx *armadacontext.Context, alert Alert) {
+	key := alertKey{alertType: alert.Type, pool: alert.Pool, subject: alert.Subject}
+	now := a.clock.Now()
+	msg := notify.Message{
+		Title: fmt.Sprintf("Armada %s alert for pool %s", alert.Type, alert.Pool),
+		Text:  alert.Text,
+	}
+	if alert.Pool == "" {
+		msg.Title = fmt.Sprintf("Armada %s alert", alert.Type)
+	}
+	if alert.Link != "" {
+		msg.Text = fmt.Sprintf("%s\n%s", msg.Text, alert.Link)
+	}
+	for _, d := range a.destinations {
+		if d.pools != nil && alert.Pool != "" && !d.pools[alert.Pool] {
+			continue
+		}
+		if d.alerts != nil && !d.alerts[alert.Type] {
+			continue
+		}
+		a.mu.Lock()
+		lastSent, ok := d.lastSentByKey[key]
+		if ok && now.Sub(lastSent) < a.config.MinAlertInterval {
+			a.mu.Unlock()
+			continue
+		}
+		if !d.limiter.AllowN(now, 1) {
+			a.mu.Unlock()
+			ctx.Warnf("dropping %s alert for pool %s; alert rate limit exceeded", alert.Type, alert.Pool)
+			continue
+		}
+		d.lastSentByKey[key] = now
+		a.mu.Unlock()
+		for _, sender := range d.senders {
+			if err := sender.Send(ctx, msg); err != nil {
+				logging.WithStacktrace(ctx, err).Warnf("failed to send %s alert", alert.Type)
+			}
+		}
+	}
+}
+
+// SchedulingAlertDetector inspects scheduling contexts for starvation and fair share breaches.
+// Starvation is only reported after a queue has been starved for several consecutive rounds,
+// so the detector keeps track of starvation across rounds.
+type SchedulingAlertDetector struct {
+	starvationRounds      int
+	fairShareBreachFactor float64
+	queueDepthThreshold   int
+	// Number of consecutive rounds each queue has been starved for, by the pool and executor scheduled.
+	starvedRoundsByExecutorAndQueue map[executorGroupKey]map[string]int
+}
+
+type executorGroupKey struct {
+	pool       string
+	executorId string
+}
+
+func NewSchedulingAlertDetector(config schedulerconfig.AlertingConfig) *SchedulingAlertDetector {
+	return &SchedulingAlertDetector{
+		starvationRounds:                config.StarvationRounds,
+		fairShareBreachFactor:           config.FairShareBreachFactor,
+		queueDepthThreshold:             config.QueueDepthThreshold,
+		starvedRoundsByExecutorAndQueue: make(map[executorGroupKey]map[string]int),
+	}
+}
+
+// Detect returns the alerts raised by the scheduling round captured by sctx.
+func (d *SchedulingAlertDetector) Detect(sctx *schedulercontext.SchedulingContext) []Alert {
+	if sctx.WeightSum == 0 || sctx.FairnessCostProvider == nil {
+		return nil
+	}
+	var alerts []Alert
+	// Queues not starved in this round are dropped.
+	key := executorGroupKey{pool: sctx.Pool, executorId: sctx.ExecutorId}
+	previousStarvedRoundsByQueue := d.starvedRoundsByExecutorAndQueue[key]
+	starvedRoundsByQueue := make(map[string]int)
+	d.starvedRoundsByExecutorAndQueue[key] = starvedRoundsByQueue
+
+	queues := make([]string, 0, len(sctx.QueueSchedulingContexts))
+	for queue := range sctx.QueueSchedulingContexts {
+		queues = append(queues, queue)
+	}
+	slices.Sort(queues)
+
+	isAnyQueueBlocked := false
+	shareByQueue := make(map[string]float64, len(queues))
+	fairShareByQueue := make(map[string]float64, len(queues))
+	for _, queue := range queues {
+		qctx := sctx.QueueSchedulingContexts[queue]
+		share := sctx.FairnessCostProvider.CostFromAllocationAndWeight(qctx.Allocated, 1)
+		fairShare := qctx.Weight / sctx.WeightSum
+		shareByQueue[queue] = share
+		fairShareByQueue[queue] = fairShare
+		isStarved := len(qctx.UnsuccessfulJobSchedulingContexts) > 0 &&
+			len(qctx.SuccessfulJobSchedulingContexts) == 0 &&
+			share < fairShare
+		if !isStarved {
+			continue
+		}
+		isAnyQueueBlocked = true
+		starvedRoundsByQueue[queue] = previousStarvedRoundsByQueue[queue] + 1
+		if d.starvationRounds > 0 && starvedRoundsByQueue[queue] >= d.starvationRounds {
+			alerts = append(alerts, Alert{
+				Type:    AlertStarvation,
+				Pool:    sctx.Pool,
+				Subject: queue,
+				Text: fmt.Sprintf(
+					"Queue %s has been unable to schedule jobs in pool %s for %d consecutive rounds; its share is %.3f while its fair share is %.3f.",
+					queue, sctx.Pool, starvedRoundsByQueue[queue], share, fairShare,
+				),
+			})
+		}
+	}
+
+	if d.fairShareBreachFactor > 0 && isAnyQueueBlocked {
+		for _, queue := range queues {
+			share := shareByQueue[queue]
+			fairShare := fairShareByQueue[queue]
+			if share > fairShare*d.fairShareBreachFactor {
+				alerts = append(alerts, Alert{
+					Type:    AlertFairShareBreach,
+					Pool:    sctx.Pool,
+					Subject: queue,
+					Text: fmt.Sprintf(
+						"Queue %s is using %.3f of pool %s, exceeding its fair share of %.3f by more than a factor %.2f, while other queues are unable to schedule.",
+						queue, share, sctx.Pool, fairShare, d.fairShareBreachFactor,
+					),
+				})
+			}
+		}
+	}
+	return alerts
+}
+
+// DetectQueueDepth returns a queue depth alert for each queue with at least as many queued jobs as the queue depth threshold.
+func (d *SchedulingAlertDetector) DetectQueueDepth(numQueuedJobsByQueue map[string]int) []Alert {
+	if d.queueDepthThreshold <= 0 {
+		return nil
+	}
+	queues := make([]string, 0, len(numQueuedJobsByQueue))
+	for queue := range numQueuedJobsByQueue {
+		queues = append(queues, queue)
+	}
+	slices.Sort(queues)
+	var alerts []Alert
+	for _, queue := range queues {
+		numQueuedJobs := numQueuedJobsByQueue[queue]
+		if numQueuedJobs < d.queueDepthThreshold {
+			continue
+		}
+		alerts = append(alerts, Alert{
+			Type:    AlertQueueDepth,
+			Subject: queue,
+			Text: fmt.Sprintf(
+				"Queue %s has %d queued jobs, reaching the queue depth threshold of %d.",
+				queue, numQueuedJobs, d.queueDepthThreshold,
+			),
+		})
+	}
+	return alerts
+}
@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/notify"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/fairness"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+type recordingSender struct {
+	mu       sync.Mutex
+	messages []notify.Message
+}
+
+func (s *recordingSender) Send(_ *armadacontext.Context, msg notify.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func TestNotifyingAlerter(t *testing.T) {
+	fakeClock := clock.NewFakeClock(testfixtures.BaseTime)
+	poolASender := &recordingSender{}
+	starvationSender := &recordingSender{}
+	alerter := newNotifyingAlerter(
+		schedulerconfig.AlertingConfig{
+			MinAlertInterval:      time.Hour,
+			LookoutQueueUrlFormat: "https://lookout.example.com/?queue=%s",
+			Destinations: []schedulerconfig.AlertDestination{
+				{Pools: []string{"pool-a"}, SlackWebhookUrl: "pool-a"},
+				{Alerts: []string{string(AlertStarvation)}, SlackWebhookUrl: "starvation"},
+			},
+		},
+		func(destination schedulerconfig.AlertDestination) []notify.Sender {
+			if destination.SlackWebhookUrl == "pool-a" {
+				return []notify.Sender{poolASender}
+			}
+			return []notify.Sender{starvationSender}
+		},
+		fakeClock,
+	)
+	ctx := armadacontext.Background()
+
+	alerter.Alert(Alert{Type: AlertStarvation, Pool: "pool-a", Subject: "queue-a", Text: "starved"})
+	alerter.send(ctx, <-alerter.alerts)
+	alerter.Alert(Alert{Type: AlertRoundDeadlineExceeded, Pool: "pool-b", Text: "too slow"})
+	alerter.send(ctx, <-alerter.alerts)
+
+	require.Len(t, poolASender.messages, 1)
+	assert.Equal(t, "Armada starvation alert for pool pool-a", poolASender.messages[0].Title)
+	assert.Equal(t, "starved\nhttps://lookout.example.com/?queue=queue-a", poolASender.messages[0].Text)
+	require.Len(t, starvationSender.messages, 1)
+
+	// Repeated alerts are suppressed until MinAlertInterval has passed.
+	alerter.Alert(Alert{Type: AlertStarvation, Pool: "pool-a", Subject: "queue-a", Text: "starved"})
+	alerter.send(ctx, <-alerter.alerts)
+	assert.Len(t, poolASender.messages, 1)
+	fakeClock.Step(time.Hour)
+	alerter.Alert(Alert{Type: AlertStarvation, Pool: "pool-a", Subject: "queue-a", Text: "starved"})
+	alerter.send(ctx, <-alerter.alerts)
+	assert.Len(t, poolASender.messages, 2)
+}
+
+func TestNotifyingAlerter_RateLimit(t *testing.T) {
+	sender := &recordingSender{}
+	alerter := newNotifyingAlerter(
+		schedulerconfig.AlertingConfig{
+			MaxAlertsPerMinute: 2,
+			Destinations:       []schedulerconfig.AlertDestination{{}},
+		},
+		func(schedulerconfig.AlertDestination) []notify.Sender {
+			return []notify.Sender{sender}
+		},
+		clock.NewFakeClock(testfixtures.BaseTime),
+	)
+	for _, queue := range []string{"queue-a", "queue-b", "queue-c"} {
+		alerter.send(armadacontext.Background(), Alert{Type: AlertStarvation, Pool: "pool", Subject: queue})
+	}
+	assert.Len(t, sender.messages, 2)
+}
+
+func TestSchedulingAlertDetector(t *testing.T) {
+	detector := NewSchedulingAlertDetector(schedulerconfig.AlertingConfig{
+		StarvationRounds:      2,
+		FairShareBreachFactor: 1.5,
+	})
+
+	// queue-a uses the entire pool, queue-b has unschedulable jobs and nothing allocated.
+	newSctx := func() *schedulercontext.SchedulingContext {
+		totalResources := schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("10")}}
+		fairnessCostProvider, err := fairness.NewDominantResourceFairness(totalResources, []string{"cpu"})
+		require.NoError(t, err)
+		sctx := schedulercontext.NewSchedulingContext(
+			"executor",
+			"pool",
+			testfixtures.TestPriorityClasses,
+			testfixtures.TestDefaultPriorityClass,
+			fairnessCostProvider,
+			nil,
+			totalResources,
+		)
+		err = sctx.AddQueueSchedulingContext(
+			"queue-a",
+			1,
+			schedulerobjects.QuantityByTAndResourceType[string]{
+				testfixtures.TestDefaultPriorityClass: schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("10")}},
+			},
+			nil,
+		)
+		require.NoError(t, err)
+		err = sctx.AddQueueSchedulingContext("queue-b", 1, nil, nil)
+		require.NoError(t, err)
+		sctx.QueueSchedulingContexts["queue-b"].UnsuccessfulJobSchedulingContexts["job"] = &schedulercontext.JobSchedulingContext{}
+		return sctx
+	}
+
+	alerts := detector.Detect(newSctx())
+	require.Len(t, alerts, 1)
+	assert.Equal(t, AlertFairShareBreach, alerts[0].Type)
+	assert.Equal(t, "queue-a", alerts[0].Subject)
+
+	alerts = detector.Detect(newSctx())
+	require.Len(t, alerts, 2)
+	assert.Equal(t, AlertStarvation, alerts[0].Type)
+	assert.Equal(t, "queue-b", alerts[0].Subject)
+	assert.Equal(t, AlertFairShareBreach, alerts[1].Type)
+
+	// Starvation is reset once queue-b is able to schedule.
+	sctx := newSctx()
+	sctx.QueueSchedulingContexts["queue-b"].UnsuccessfulJobSchedulingContexts = map[string]*schedulercontext.JobSchedulingContext{}
+	assert.Empty(t, detector.Detect(sctx))
+	alerts = detector.Detect(newSctx())
+	require.Len(t, alerts, 1)
+	assert.Equal(t, AlertFairShareBreach, alerts[0].Type)
+}
+
+func TestSchedulingAlertDetector_QueueDepth(t *testing.T) {
+	detector := NewSchedulingAlertDetector(schedulerconfig.AlertingConfig{QueueDepthThreshold: 10})
+	alerts := detector.DetectQueueDepth(map[string]int{"queue-c": 100, "queue-a": 10, "queue-b": 9})
+	require.Len(t, alerts, 2)
+	assert.Equal(t, AlertQueueDepth, alerts[0].Type)
+	assert.Equal(t, "queue-a", alerts[0].Subject)
+	assert.Equal(t, "", alerts[0].Pool)
+	assert.Equal(t, "queue-c", alerts[1].Subject)
+
+	detector = NewSchedulingAlertDetector(schedulerconfig.AlertingConfig{})
+	assert.Empty(t, detector.DetectQueueDepth(map[string]int{"queue-a": 100}))
+}
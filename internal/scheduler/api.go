@@ -13,10 +13,12 @@ import (
 
 	"github.com/armadaproject/armada/internal/common/armadacontext"
 	"github.com/armadaproject/armada/internal/common/compress"
+	"github.com/armadaproject/armada/internal/common/encryption"
 	"github.com/armadaproject/armada/internal/common/logging"
 	"github.com/armadaproject/armada/internal/common/pulsarutils"
 	"github.com/armadaproject/armada/internal/common/schedulers"
 	"github.com/armadaproject/armada/internal/common/util"
+	"github.com/armadaproject/armada/internal/scheduler/adapters"
 	"github.com/armadaproject/armada/internal/scheduler/database"
 	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
 	"github.com/armadaproject/armada/pkg/api"
@@ -42,7 +44,9 @@ type ExecutorApi struct {
 	nodeIdLabel string
 	// See scheduling schedulingConfig.
 	priorityClassNameOverride *string
-	clock                     clock.Clock
+	// Used to decrypt job specs encrypted at rest by the scheduler ingester; may be nil.
+	jobSpecEnvelope *encryption.Envelope
+	clock           clock.Clock
 }
 
 func NewExecutorApi(producer pulsar.Producer,
@@ -70,6 +74,11 @@ func NewExecutorApi(producer pulsar.Producer,
 	}, nil
 }
 
+// SetJobSpecEncryption sets the envelope used to decrypt job specs encrypted at rest by the scheduler ingester.
+func (srv *ExecutorApi) SetJobSpecEncryption(envelope *encryption.Envelope) {
+	srv.jobSpecEnvelope = envelope
+}
+
 // LeaseJobRuns reconciles the state of the executor with that of the scheduler. Specifically it:
 // 1. Stores job and capacity information received from the executor to make it available to the scheduler.
 // 2. Notifies the executor if any of its jobs are no longer active, e.g., due to being preempted by the scheduler.
@@ -124,7 +133,11 @@ func (srv *ExecutorApi) LeaseJobRuns(stream executorapi.ExecutorApi_LeaseJobRuns
 	}
 
 	// Send any scheduled jobs the executor doesn't already have.
-	decompressor := compress.NewZlibDecompressor()
+	codecDecompressor, err := compress.NewDecompressor()
+	if err != nil {
+		return err
+	}
+	decompressor := encryption.NewDecryptingDecompressor(codecDecompressor, srv.jobSpecEnvelope)
 	for _, lease := range newRuns {
 		submitMsg := &armadaevents.SubmitJob{}
 		if err := unmarshalFromCompressedBytes(lease.SubmitMessage, decompressor, submitMsg); err != nil {
@@ -134,6 +147,12 @@ func (srv *ExecutorApi) LeaseJobRuns(stream executorapi.ExecutorApi_LeaseJobRuns
 			srv.setPriorityClassName(submitMsg, *srv.priorityClassNameOverride)
 		}
 		srv.addNodeIdSelector(submitMsg, lease.Node)
+		if adapted := adapters.AdaptJobToKubernetesVersion(submitMsg, req.KubernetesVersion); len(adapted) > 0 {
+			ctx.Infof(
+				"replaced pod spec features %v of run %s not supported by Kubernetes version %s of executor %s",
+				adapted, lease.RunID, req.KubernetesVersion, req.ExecutorId,
+			)
+		}
 
 		var groups []string
 		if len(lease.Groups) > 0 {
@@ -242,6 +261,9 @@ func (srv *ExecutorApi) executorFromLeaseRequest(ctx *armadacontext.Context, req
 		UnassignedJobRuns: util.Map(req.UnassignedJobRunIds, func(jobId armadaevents.Uuid) string {
 			return strings.ToLower(armadaevents.UuidFromProtoUuid(&jobId).String())
 		}),
+		KubernetesVersion: req.KubernetesVersion,
+		RuntimeClasses:    req.RuntimeClasses,
+		Features:          req.Features,
 	}
 }
 
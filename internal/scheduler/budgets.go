@@ -0,0 +1,340 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/logging"
+	"github.com/armadaproject/armada/internal/common/types"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+)
+
+type BudgetAction string
+
+const (
+	// Schedule new jobs of the queue at the scavenger priority class.
+	BudgetActionDemote BudgetAction = "demote"
+	// Don't schedule new jobs of the queue.
+	BudgetActionBlock BudgetAction = "block"
+)
+
+const (
+	BudgetWindowDay   = "day"
+	BudgetWindowWeek  = "week"
+	BudgetWindowMonth = "month"
+)
+
+// Name under which Lookout reports gpu-hours.
+const gpuResourceName = "nvidia.com/gpu"
+
+// GpuUsageSource reports the gpu-hours consumed by each queue over some interval.
+type GpuUsageSource interface {
+	GetGpuHours(ctx *armadacontext.Context, from time.Time, to time.Time) (map[string]float64, error)
+}
+
+// LookoutGpuUsageSource computes gpu-hours from the run history recorded by Lookout, via its resource usage api.
+type LookoutGpuUsageSource struct {
+	url    string
+	client *http.Client
+}
+
+func NewLookoutGpuUsageSource(url string, timeout time.Duration) *LookoutGpuUsageSource {
+	return &LookoutGpuUsageSource{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *LookoutGpuUsageSource) GetGpuHours(ctx *armadacontext.Context, from time.Time, to time.Time) (map[string]float64, error) {
+	usage, err := s.getResourceUsage(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	gpuHoursByQueue := make(map[string]float64)
+	for _, u := range usage {
+		gpuHoursByQueue[u.Queue] += u.ResourceHours[gpuResourceName]
+	}
+	return gpuHoursByQueue, nil
+}
+
+// GetResourceHoursByCluster returns the resource-hours consumed on each cluster, i.e., executor, summed over all queues.
+func (s *LookoutGpuUsageSource) GetResourceHoursByCluster(ctx *armadacontext.Context, from time.Time, to time.Time) (map[string]map[string]float64, error) {
+	usage, err := s.getResourceUsage(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	rv := make(map[string]map[string]float64)
+	for _, u := range usage {
+		resourceHours := rv[u.Cluster]
+		if resourceHours == nil {
+			resourceHours = make(map[string]float64)
+			rv[u.Cluster] = resourceHours
+		}
+		for t, hours := range u.ResourceHours {
+			resourceHours[t] += hours
+		}
+	}
+	return rv, nil
+}
+
+type lookoutResourceUsage struct {
+	Queue         string             `json:"queue"`
+	Cluster       string             `json:"cluster"`
+	ResourceHours map[string]float64 `json:"resourceHours"`
+}
+
+func (s *LookoutGpuUsageSource) getResourceUsage(ctx *armadacontext.Context, from time.Time, to time.Time) ([]lookoutResourceUsage, error) {
+	query := url.Values{}
+	query.Set("from", from.UTC().Format(time.RFC3339))
+	query.Set("to", to.UTC().Format(time.RFC3339))
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	resp, err := s.client.Do(httpRequest)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("resource usage api %s returned status %s", s.url, resp.Status)
+	}
+	var response struct {
+		Usage []lookoutResourceUsage `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return response.Usage, nil
+}
+
+// BudgetStatus is the state of a queue budget in its current window.
+type BudgetStatus struct {
+	Queue       string       `json:"queue"`
+	Window      string       `json:"window"`
+	WindowStart time.Time    `json:"windowStart"`
+	WindowEnd   time.Time    `json:"windowEnd"`
+	Action      BudgetAction `json:"action"`
+	// Gpu-hours allowed per window.
+	GpuHours     float64 `json:"gpuHours"`
+	UsedGpuHours float64 `json:"usedGpuHours"`
+	// Gpu-hours that will have been used by the end of the window if usage continues at the average rate so far.
+	ProjectedGpuHours float64 `json:"projectedGpuHours"`
+	// Time at which the budget is projected to be exhausted at that rate; nil if not projected to be exhausted within the window.
+	ProjectedExhaustion *time.Time `json:"projectedExhaustion,omitempty"`
+	Exhausted           bool       `json:"exhausted"`
+	// Time at which usage was last computed.
+	Updated time.Time `json:"updated"`
+}
+
+// BudgetTracker tracks the gpu-hours used by queues with budgets.
+// Usage is refreshed periodically by Run; the scheduler enforces budgets based on the most recently computed usage.
+type BudgetTracker struct {
+	budgets                []schedulerconfig.QueueBudget
+	scavengerPriorityClass string
+	source                 GpuUsageSource
+	clock                  clock.Clock
+	// Status of each budget, in the same order as budgets. Nil until usage is first computed.
+	statuses []*BudgetStatus
+	mu       sync.RWMutex
+}
+
+func NewBudgetTracker(
+	config schedulerconfig.BudgetsConfig,
+	priorityClasses map[string]types.PriorityClass,
+	source GpuUsageSource,
+) (*BudgetTracker, error) {
+	for _, budget := range config.Queues {
+		if budget.Queue == "" {
+			return nil, errors.New("queue budgets must specify a queue")
+		}
+		if _, _, err := budgetWindow(budget.Window, time.Now()); err != nil {
+			return nil, err
+		}
+		switch BudgetAction(budget.Action) {
+		case BudgetActionBlock:
+		case BudgetActionDemote:
+			if _, ok := priorityClasses[config.ScavengerPriorityClass]; !ok {
+				return nil, errors.Errorf(
+					"budget of queue %s demotes to scavenger priority class %q, which is missing from priority class mapping %v",
+					budget.Queue, config.ScavengerPriorityClass, priorityClasses,
+				)
+			}
+		default:
+			return nil, errors.Errorf("unknown action %q for budget of queue %s; must be one of demote or block", budget.Action, budget.Queue)
+		}
+	}
+	return &BudgetTracker{
+		budgets:                config.Queues,
+		scavengerPriorityClass: config.ScavengerPriorityClass,
+		source:                 source,
+		clock:                  clock.RealClock{},
+	}, nil
+}
+
+// Run refreshes usage every interval until the provided context is cancelled.
+func (b *BudgetTracker) Run(ctx *armadacontext.Context, interval time.Duration) error {
+	logger := log.StandardLogger().WithField("service", "BudgetTracker")
+	logger.Info("service started")
+	ticker := b.clock.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := b.Refresh(ctx); err != nil {
+			logging.WithStacktrace(logger, err).Warn("failed to refresh budget usage")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
+
+// Refresh recomputes the status of all budgets.
+// If usage can't be computed, the previously computed statuses are kept.
+func (b *BudgetTracker) Refresh(ctx *armadacontext.Context) error {
+	now := b.clock.Now()
+	// Usage is fetched once per window, since budgets with the same window share the same interval.
+	gpuHoursByQueueByWindow := make(map[string]map[string]float64)
+	statuses := make([]*BudgetStatus, len(b.budgets))
+	for i, budget := range b.budgets {
+		windowStart, windowEnd, err := budgetWindow(budget.Window, now)
+		if err != nil {
+			return err
+		}
+		gpuHoursByQueue, ok := gpuHoursByQueueByWindow[budget.Window]
+		if !ok {
+			gpuHoursByQueue, err = b.source.GetGpuHours(ctx, windowStart, now)
+			if err != nil {
+				return err
+			}
+			gpuHoursByQueueByWindow[budget.Window] = gpuHoursByQueue
+		}
+		statuses[i] = newBudgetStatus(budget, windowStart, windowEnd, gpuHoursByQueue[budget.Queue], now)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.statuses = statuses
+	return nil
+}
+
+func newBudgetStatus(budget schedulerconfig.QueueBudget, windowStart, windowEnd time.Time, usedGpuHours float64, now time.Time) *BudgetStatus {
+	status := &BudgetStatus{
+		Queue:             budget.Queue,
+		Window:            budget.Window,
+		WindowStart:       windowStart,
+		WindowEnd:         windowEnd,
+		Action:            BudgetAction(budget.Action),
+		GpuHours:          budget.GpuHours,
+		UsedGpuHours:      usedGpuHours,
+		ProjectedGpuHours: usedGpuHours,
+		Exhausted:         usedGpuHours >= budget.GpuHours,
+		Updated:           now,
+	}
+	elapsed := now.Sub(windowStart)
+	if elapsed <= 0 || usedGpuHours <= 0 {
+		return status
+	}
+	gpuHoursPerSecond := usedGpuHours / elapsed.Seconds()
+	status.ProjectedGpuHours = gpuHoursPerSecond * windowEnd.Sub(windowStart).Seconds()
+	if !status.Exhausted {
+		exhaustion := windowStart.Add(time.Duration(budget.GpuHours / gpuHoursPerSecond * float64(time.Second)))
+		if exhaustion.Before(windowEnd) {
+			status.ProjectedExhaustion = &exhaustion
+		}
+	}
+	return status
+}
+
+// budgetWindow returns the window of the given type containing now.
+func budgetWindow(window string, now time.Time) (time.Time, time.Time, error) {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch window {
+	case BudgetWindowDay:
+		return midnight, midnight.AddDate(0, 0, 1), nil
+	case BudgetWindowWeek:
+		// Weeks start on Monday.
+		start := midnight.AddDate(0, 0, -((int(now.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7), nil
+	case BudgetWindowMonth:
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0), nil
+	default:
+		return time.Time{}, time.Time{}, errors.Errorf("unknown budget window %q; must be one of day, week, or month", window)
+	}
+}
+
+// Statuses returns the status of all budgets, ordered by queue.
+func (b *BudgetTracker) Statuses() []*BudgetStatus {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	rv := slices.Clone(b.statuses)
+	slices.SortStableFunc(rv, func(a, b *BudgetStatus) bool {
+		return a.Queue < b.Queue
+	})
+	return rv
+}
+
+// ExhaustedActionByQueue returns the action to take for each queue with an exhausted budget.
+// If a queue has several exhausted budgets, blocking takes precedence over demoting.
+func (b *BudgetTracker) ExhaustedActionByQueue() map[string]BudgetAction {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	rv := make(map[string]BudgetAction)
+	for _, status := range b.statuses {
+		if !status.Exhausted || rv[status.Queue] == BudgetActionBlock {
+			continue
+		}
+		rv[status.Queue] = status.Action
+	}
+	return rv
+}
+
+// ScavengerPriorityClass returns the name of the priority class jobs of demoted queues are scheduled at.
+func (b *BudgetTracker) ScavengerPriorityClass() string {
+	return b.scavengerPriorityClass
+}
+
+// BudgetsHttpHandler serves the status of all budgets as json, optionally restricted to the queue given by the queue query parameter.
+type BudgetsHttpHandler struct {
+	budgetTracker *BudgetTracker
+}
+
+func NewBudgetsHttpHandler(budgetTracker *BudgetTracker) *BudgetsHttpHandler {
+	return &BudgetsHttpHandler{budgetTracker: budgetTracker}
+}
+
+func (h *BudgetsHttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	statuses := h.budgetTracker.Statuses()
+	if queue := r.URL.Query().Get("queue"); queue != "" {
+		var filtered []*BudgetStatus
+		for _, status := range statuses {
+			if status.Queue == queue {
+				filtered = append(filtered, status)
+			}
+		}
+		statuses = filtered
+	}
+	if statuses == nil {
+		statuses = []*BudgetStatus{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	response := struct {
+		Budgets []*BudgetStatus `json:"budgets"`
+	}{
+		Budgets: statuses,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.WithError(err).Error("failed to write budgets response")
+	}
+}
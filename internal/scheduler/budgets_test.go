@@ -0,0 +1,207 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/types"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+)
+
+func TestBudgetWindow(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2023, 11, 15, 13, 30, 0, 0, time.UTC)
+	tests := map[string]struct {
+		window        string
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		"day": {
+			window:        BudgetWindowDay,
+			expectedStart: time.Date(2023, 11, 15, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 11, 16, 0, 0, 0, 0, time.UTC),
+		},
+		"week": {
+			window:        BudgetWindowWeek,
+			expectedStart: time.Date(2023, 11, 13, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 11, 20, 0, 0, 0, 0, time.UTC),
+		},
+		"month": {
+			window:        BudgetWindowMonth,
+			expectedStart: time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			start, end, err := budgetWindow(tc.window, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStart, start)
+			assert.Equal(t, tc.expectedEnd, end)
+		})
+	}
+
+	// Weeks starting on a Sunday still start on the preceding Monday.
+	start, _, err := budgetWindow(BudgetWindowWeek, time.Date(2023, 11, 19, 23, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 11, 13, 0, 0, 0, 0, time.UTC), start)
+
+	_, _, err = budgetWindow("year", now)
+	assert.Error(t, err)
+}
+
+func TestBudgetTracker(t *testing.T) {
+	source := &fakeGpuUsageSource{gpuHoursByQueue: map[string]float64{
+		"queue-a": 100,
+		"queue-b": 20,
+	}}
+	tracker, err := NewBudgetTracker(
+		schedulerconfig.BudgetsConfig{
+			ScavengerPriorityClass: "scavenger",
+			Queues: []schedulerconfig.QueueBudget{
+				{Queue: "queue-a", GpuHours: 100, Window: BudgetWindowDay, Action: string(BudgetActionDemote)},
+				{Queue: "queue-b", GpuHours: 60, Window: BudgetWindowDay, Action: string(BudgetActionBlock)},
+				{Queue: "queue-c", GpuHours: 10, Window: BudgetWindowDay, Action: string(BudgetActionBlock)},
+			},
+		},
+		map[string]types.PriorityClass{"scavenger": {Preemptible: true}},
+		source,
+	)
+	require.NoError(t, err)
+	// A quarter of the way through the day.
+	now := time.Date(2023, 11, 15, 6, 0, 0, 0, time.UTC)
+	tracker.clock = clock.NewFakeClock(now)
+
+	// Nothing is enforced until usage has been computed.
+	assert.Empty(t, tracker.ExhaustedActionByQueue())
+
+	require.NoError(t, tracker.Refresh(armadacontext.Background()))
+	assert.Equal(t, map[string]BudgetAction{"queue-a": BudgetActionDemote}, tracker.ExhaustedActionByQueue())
+	assert.Equal(t, 1, source.numCalls)
+
+	statuses := tracker.Statuses()
+	require.Len(t, statuses, 3)
+	status := statuses[1]
+	assert.Equal(t, "queue-b", status.Queue)
+	assert.Equal(t, time.Date(2023, 11, 15, 0, 0, 0, 0, time.UTC), status.WindowStart)
+	assert.Equal(t, time.Date(2023, 11, 16, 0, 0, 0, 0, time.UTC), status.WindowEnd)
+	assert.Equal(t, 20.0, status.UsedGpuHours)
+	assert.InDelta(t, 80, status.ProjectedGpuHours, 1e-6)
+	if assert.NotNil(t, status.ProjectedExhaustion) {
+		assert.WithinDuration(t, time.Date(2023, 11, 15, 18, 0, 0, 0, time.UTC), *status.ProjectedExhaustion, time.Second)
+	}
+	assert.False(t, status.Exhausted)
+	assert.Equal(t, now, status.Updated)
+	assert.True(t, statuses[0].Exhausted)
+	assert.Nil(t, statuses[0].ProjectedExhaustion)
+	assert.False(t, statuses[2].Exhausted)
+	assert.Nil(t, statuses[2].ProjectedExhaustion)
+
+	// Previously computed usage is kept if usage can't be refreshed.
+	source.err = assert.AnError
+	assert.Error(t, tracker.Refresh(armadacontext.Background()))
+	assert.Equal(t, map[string]BudgetAction{"queue-a": BudgetActionDemote}, tracker.ExhaustedActionByQueue())
+}
+
+func TestBudgetTracker_BlockTakesPrecedence(t *testing.T) {
+	tracker := &BudgetTracker{
+		statuses: []*BudgetStatus{
+			{Queue: "queue-a", Action: BudgetActionBlock, Exhausted: true},
+			{Queue: "queue-a", Action: BudgetActionDemote, Exhausted: true},
+			{Queue: "queue-b", Action: BudgetActionBlock, Exhausted: false},
+		},
+	}
+	assert.Equal(t, map[string]BudgetAction{"queue-a": BudgetActionBlock}, tracker.ExhaustedActionByQueue())
+}
+
+func TestNewBudgetTracker_InvalidConfig(t *testing.T) {
+	priorityClasses := map[string]types.PriorityClass{"scavenger": {}}
+	tests := map[string]schedulerconfig.BudgetsConfig{
+		"missing queue": {
+			Queues: []schedulerconfig.QueueBudget{{GpuHours: 1, Window: BudgetWindowDay, Action: string(BudgetActionBlock)}},
+		},
+		"unknown window": {
+			Queues: []schedulerconfig.QueueBudget{{Queue: "queue-a", GpuHours: 1, Window: "year", Action: string(BudgetActionBlock)}},
+		},
+		"unknown action": {
+			Queues: []schedulerconfig.QueueBudget{{Queue: "queue-a", GpuHours: 1, Window: BudgetWindowDay, Action: "cancel"}},
+		},
+		"unknown scavenger priority class": {
+			ScavengerPriorityClass: "foo",
+			Queues:                 []schedulerconfig.QueueBudget{{Queue: "queue-a", GpuHours: 1, Window: BudgetWindowDay, Action: string(BudgetActionDemote)}},
+		},
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewBudgetTracker(config, priorityClasses, &fakeGpuUsageSource{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLookoutGpuUsageSource(t *testing.T) {
+	from := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 11, 15, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2023-11-01T00:00:00Z", r.URL.Query().Get("from"))
+		assert.Equal(t, "2023-11-15T00:00:00Z", r.URL.Query().Get("to"))
+		_, _ = w.Write([]byte(`{"usage": [
+			{"queue": "queue-a", "cluster": "cluster-1", "resourceHours": {"cpu": 100, "nvidia.com/gpu": 10}},
+			{"queue": "queue-a", "cluster": "cluster-2", "resourceHours": {"cpu": 100, "nvidia.com/gpu": 5}},
+			{"queue": "queue-b", "cluster": "cluster-1", "resourceHours": {"cpu": 100}}
+		]}`))
+	}))
+	defer server.Close()
+
+	gpuHoursByQueue, err := NewLookoutGpuUsageSource(server.URL, time.Second).GetGpuHours(armadacontext.Background(), from, to)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"queue-a": 15, "queue-b": 0}, gpuHoursByQueue)
+}
+
+func TestBudgetsHttpHandler(t *testing.T) {
+	tracker := &BudgetTracker{
+		statuses: []*BudgetStatus{
+			{Queue: "queue-b", GpuHours: 10},
+			{Queue: "queue-a", GpuHours: 20},
+		},
+	}
+	handler := NewBudgetsHttpHandler(tracker)
+
+	var response struct {
+		Budgets []*BudgetStatus `json:"budgets"`
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/budgets", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	require.Len(t, response.Budgets, 2)
+	assert.Equal(t, "queue-a", response.Budgets[0].Queue)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/budgets?queue=queue-b", nil))
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	require.Len(t, response.Budgets, 1)
+	assert.Equal(t, float64(10), response.Budgets[0].GpuHours)
+}
+
+type fakeGpuUsageSource struct {
+	gpuHoursByQueue map[string]float64
+	err             error
+	numCalls        int
+}
+
+func (s *fakeGpuUsageSource) GetGpuHours(_ *armadacontext.Context, _ time.Time, _ time.Time) (map[string]float64, error) {
+	s.numCalls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.gpuHoursByQueue, nil
+}
@@ -0,0 +1,449 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+	"google.golang.org/grpc"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/logging"
+	"github.com/armadaproject/armada/internal/common/metrics"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	"github.com/armadaproject/armada/pkg/api"
+	"github.com/armadaproject/armada/pkg/client"
+)
+
+// CanaryOutcome is the outcome of a single canary job.
+type CanaryOutcome struct {
+	JobId     string    `json:"jobId"`
+	Submitted time.Time `json:"submitted"`
+	// Time at which the job started running; zero if it never did.
+	Running time.Time `json:"running"`
+	// Time at which the job succeeded or failed; zero if it did neither, e.g., since it timed out.
+	Finished  time.Time `json:"finished"`
+	Succeeded bool      `json:"succeeded"`
+	// Why the job didn't succeed, if it didn't.
+	Reason string `json:"reason,omitempty"`
+}
+
+// CanaryRunner submits canary jobs and follows them until they terminate.
+type CanaryRunner interface {
+	// Run submits a canary job for probe and blocks until it succeeds, fails, or ctx is cancelled.
+	// If ctx is cancelled, the job is cancelled and the outcome returned has no finish time.
+	Run(ctx *armadacontext.Context, probe schedulerconfig.CanaryProbe) (*CanaryOutcome, error)
+}
+
+// CanaryStatus is the state of one canary probe.
+type CanaryStatus struct {
+	Pool              string `json:"pool"`
+	PriorityClassName string `json:"priorityClassName"`
+	Queue             string `json:"queue"`
+	// True if a canary job of this probe is in flight.
+	InFlight bool `json:"inFlight"`
+	// Outcome of the most recent canary job to terminate; nil if none has yet.
+	LastOutcome *CanaryOutcome `json:"lastOutcome,omitempty"`
+	// Time from the most recent canary job being submitted to it running and succeeding, respectively; zero if unknown.
+	LastStartLatency time.Duration `json:"lastStartLatency"`
+	LastLatency      time.Duration `json:"lastLatency"`
+	// True if the most recent canary job succeeded within the maximum latency of the probe.
+	Healthy bool `json:"healthy"`
+	// Number of canary jobs in a row that failed or exceeded the maximum latency.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+}
+
+var (
+	canaryLatencyDesc = prometheus.NewDesc(
+		metrics.MetricPrefix+"scheduler_canary_latency_seconds",
+		"Time from the most recent canary job of each pool and priority class being submitted to it succeeding.",
+		[]string{"pool", "priorityClass"}, nil,
+	)
+	canaryStartLatencyDesc = prometheus.NewDesc(
+		metrics.MetricPrefix+"scheduler_canary_start_latency_seconds",
+		"Time from the most recent canary job of each pool and priority class being submitted to it running.",
+		[]string{"pool", "priorityClass"}, nil,
+	)
+	canaryHealthyDesc = prometheus.NewDesc(
+		metrics.MetricPrefix+"scheduler_canary_healthy",
+		"1 if the most recent canary job of each pool and priority class succeeded within the maximum latency, 0 otherwise.",
+		[]string{"pool", "priorityClass"}, nil,
+	)
+)
+
+// Canary periodically submits a small canary job to each configured pool and priority class and measures how long it takes
+// to be scheduled and to run, giving operators a continuous end-to-end health signal.
+// An alert is raised whenever a canary job fails, times out, or takes longer than the maximum latency of its probe to succeed.
+// Only the leader submits canary jobs.
+type Canary struct {
+	config           schedulerconfig.CanaryConfig
+	runner           CanaryRunner
+	leaderController LeaderController
+	alerter          Alerter
+	clock            clock.Clock
+
+	mu sync.Mutex
+	// Status of each probe, in the order probes are configured.
+	statuses []*CanaryStatus
+}
+
+func NewCanary(config schedulerconfig.CanaryConfig, runner CanaryRunner, leaderController LeaderController, alerter Alerter) (*Canary, error) {
+	if config.Interval <= 0 {
+		return nil, errors.Errorf("canary interval must be positive, but is %s", config.Interval)
+	}
+	statuses := make([]*CanaryStatus, len(config.Probes))
+	for i, probe := range config.Probes {
+		if probe.Queue == "" || probe.Pool == "" || probe.Image == "" {
+			return nil, errors.Errorf("canary probe %d must specify a queue, pool, and image", i)
+		}
+		if probe.Timeout <= 0 {
+			return nil, errors.Errorf("timeout of canary probe for pool %s must be positive, but is %s", probe.Pool, probe.Timeout)
+		}
+		if _, err := canaryResourceList(probe); err != nil {
+			return nil, err
+		}
+		statuses[i] = &CanaryStatus{
+			Pool:              probe.Pool,
+			PriorityClassName: probe.PriorityClassName,
+			Queue:             probe.Queue,
+		}
+	}
+	if alerter == nil {
+		alerter = NoOpAlerter{}
+	}
+	return &Canary{
+		config:           config,
+		runner:           runner,
+		leaderController: leaderController,
+		alerter:          alerter,
+		clock:            clock.RealClock{},
+		statuses:         statuses,
+	}, nil
+}
+
+// Run submits a canary job for each probe every interval until the provided context is cancelled.
+func (c *Canary) Run(ctx *armadacontext.Context) error {
+	ctx = armadacontext.WithLogField(ctx, "service", "Canary")
+	ctx.Info("service started")
+	ticker := c.clock.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+		if !c.leaderController.GetToken().leader {
+			continue
+		}
+		for i := range c.config.Probes {
+			if !c.startProbe(i) {
+				continue
+			}
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				c.probe(ctx, i)
+			}(i)
+		}
+	}
+}
+
+// startProbe marks the i-th probe as in flight and returns true, or returns false if it already is.
+func (c *Canary) startProbe(i int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.statuses[i].InFlight {
+		return false
+	}
+	c.statuses[i].InFlight = true
+	return true
+}
+
+// probe runs a canary job for the i-th probe and records its outcome.
+func (c *Canary) probe(ctx *armadacontext.Context, i int) {
+	probe := c.config.Probes[i]
+	probeCtx, cancel := armadacontext.WithTimeout(ctx, probe.Timeout)
+	defer cancel()
+	outcome, err := c.runner.Run(probeCtx, probe)
+	if err != nil && ctx.Err() != nil {
+		// The scheduler is shutting down; the outcome says nothing about the health of the pool.
+		c.mu.Lock()
+		c.statuses[i].InFlight = false
+		c.mu.Unlock()
+		return
+	}
+	if err != nil {
+		logging.WithStacktrace(ctx, err).Warnf("canary job for pool %s failed", probe.Pool)
+		if outcome == nil {
+			outcome = &CanaryOutcome{}
+		}
+		if outcome.Reason == "" {
+			outcome.Reason = err.Error()
+		}
+		outcome.Succeeded = false
+	}
+	c.record(i, outcome)
+}
+
+// record updates the status of the i-th probe with the outcome of its most recent canary job and raises an alert if it's unhealthy.
+func (c *Canary) record(i int, outcome *CanaryOutcome) {
+	probe := c.config.Probes[i]
+	c.mu.Lock()
+	status := c.statuses[i]
+	status.InFlight = false
+	status.LastOutcome = outcome
+	status.LastStartLatency = 0
+	status.LastLatency = 0
+	if !outcome.Submitted.IsZero() && !outcome.Running.IsZero() {
+		status.LastStartLatency = outcome.Running.Sub(outcome.Submitted)
+	}
+	if !outcome.Submitted.IsZero() && !outcome.Finished.IsZero() {
+		status.LastLatency = outcome.Finished.Sub(outcome.Submitted)
+	}
+	var problem string
+	if !outcome.Succeeded {
+		problem = fmt.Sprintf("failed: %s", outcome.Reason)
+	} else if probe.MaxLatency > 0 && status.LastLatency > probe.MaxLatency {
+		problem = fmt.Sprintf("took %s to succeed, exceeding the maximum latency of %s", status.LastLatency, probe.MaxLatency)
+	}
+	status.Healthy = problem == ""
+	if status.Healthy {
+		status.ConsecutiveFailures = 0
+	} else {
+		status.ConsecutiveFailures++
+	}
+	consecutiveFailures := status.ConsecutiveFailures
+	c.mu.Unlock()
+
+	if problem == "" {
+		return
+	}
+	log.Warnf("canary job %s for pool %s and priority class %s %s", outcome.JobId, probe.Pool, probe.PriorityClassName, problem)
+	c.alerter.Alert(Alert{
+		Type:    AlertCanary,
+		Pool:    probe.Pool,
+		Subject: probe.Queue,
+		Text: fmt.Sprintf(
+			"Canary job %s submitted to queue %s at priority class %s %s (%d unhealthy canary jobs in a row)",
+			outcome.JobId, probe.Queue, probe.PriorityClassName, problem, consecutiveFailures,
+		),
+	})
+}
+
+// Statuses returns the state of each probe, in the order probes are configured.
+func (c *Canary) Statuses() []CanaryStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rv := make([]CanaryStatus, len(c.statuses))
+	for i, status := range c.statuses {
+		rv[i] = *status
+		if status.LastOutcome != nil {
+			outcome := *status.LastOutcome
+			rv[i].LastOutcome = &outcome
+		}
+	}
+	return rv
+}
+
+func (c *Canary) Describe(desc chan<- *prometheus.Desc) {
+	desc <- canaryLatencyDesc
+	desc <- canaryStartLatencyDesc
+	desc <- canaryHealthyDesc
+}
+
+func (c *Canary) Collect(ch chan<- prometheus.Metric) {
+	for _, status := range c.Statuses() {
+		if status.LastOutcome == nil {
+			continue
+		}
+		healthy := 0.0
+		if status.Healthy {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(canaryHealthyDesc, prometheus.GaugeValue, healthy, status.Pool, status.PriorityClassName)
+		if status.LastOutcome.Succeeded && status.LastLatency > 0 {
+			ch <- prometheus.MustNewConstMetric(canaryLatencyDesc, prometheus.GaugeValue, status.LastLatency.Seconds(), status.Pool, status.PriorityClassName)
+		}
+		if status.LastStartLatency > 0 {
+			ch <- prometheus.MustNewConstMetric(canaryStartLatencyDesc, prometheus.GaugeValue, status.LastStartLatency.Seconds(), status.Pool, status.PriorityClassName)
+		}
+	}
+}
+
+// canaryResourceList returns the resources requested by canary jobs of probe.
+func canaryResourceList(probe schedulerconfig.CanaryProbe) (v1.ResourceList, error) {
+	rv := make(v1.ResourceList, len(probe.Resources))
+	for t, s := range probe.Resources {
+		q, err := resource.ParseQuantity(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid quantity %q of resource %s for canary probe for pool %s", s, t, probe.Pool)
+		}
+		rv[v1.ResourceName(t)] = q
+	}
+	return rv, nil
+}
+
+// canaryJobSubmitRequest returns a request to submit a canary job for probe as part of the job set with id jobSetId.
+func canaryJobSubmitRequest(probe schedulerconfig.CanaryProbe, jobSetId string) (*api.JobSubmitRequest, error) {
+	resources, err := canaryResourceList(probe)
+	if err != nil {
+		return nil, err
+	}
+	return &api.JobSubmitRequest{
+		Queue:    probe.Queue,
+		JobSetId: jobSetId,
+		JobRequestItems: []*api.JobSubmitRequestItem{
+			{
+				Namespace:   probe.Namespace,
+				Annotations: map[string]string{configuration.PoolAnnotation: probe.Pool},
+				PodSpecs: []*v1.PodSpec{
+					{
+						RestartPolicy:     v1.RestartPolicyNever,
+						PriorityClassName: probe.PriorityClassName,
+						Containers: []v1.Container{
+							{
+								Name:    "canary",
+								Image:   probe.Image,
+								Command: slices.Clone(probe.Command),
+								Resources: v1.ResourceRequirements{
+									Requests: resources,
+									Limits:   resources,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// ApiCanaryRunner submits canary jobs via the Armada api and follows them via their job set events.
+// Each canary job is submitted as part of its own job set, such that following it never requires replaying the events of other jobs.
+type ApiCanaryRunner struct {
+	apiConnectionDetails *client.ApiConnectionDetails
+	clock                clock.Clock
+}
+
+func NewApiCanaryRunner(apiConnectionDetails *client.ApiConnectionDetails) *ApiCanaryRunner {
+	return &ApiCanaryRunner{
+		apiConnectionDetails: apiConnectionDetails,
+		clock:                clock.RealClock{},
+	}
+}
+
+func (r *ApiCanaryRunner) Run(ctx *armadacontext.Context, probe schedulerconfig.CanaryProbe) (*CanaryOutcome, error) {
+	jobSetId := fmt.Sprintf("armada-canary-%s", uuid.NewString())
+	req, err := canaryJobSubmitRequest(probe, jobSetId)
+	if err != nil {
+		return nil, err
+	}
+	outcome := &CanaryOutcome{}
+	err = client.WithConnection(r.apiConnectionDetails, func(cc *grpc.ClientConn) error {
+		outcome.Submitted = r.clock.Now()
+		res, err := api.NewSubmitClient(cc).SubmitJobs(ctx, req)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if len(res.JobResponseItems) != 1 {
+			return errors.Errorf("expected 1 job response item, but got %d", len(res.JobResponseItems))
+		}
+		if res.JobResponseItems[0].Error != "" {
+			return errors.Errorf("failed to submit canary job: %s", res.JobResponseItems[0].Error)
+		}
+		outcome.JobId = res.JobResponseItems[0].JobId
+
+		err = r.follow(ctx, api.NewEventClient(cc), probe.Queue, jobSetId, outcome)
+		if err != nil && ctx.Err() != nil {
+			// Timed out; cancel the job such that it doesn't linger, using a fresh context since ctx is done.
+			cancelCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if _, cancelErr := api.NewSubmitClient(cc).CancelJobs(cancelCtx, &api.JobCancelRequest{
+				JobId:    outcome.JobId,
+				JobSetId: jobSetId,
+				Queue:    probe.Queue,
+				Reason:   "canary job timed out",
+			}); cancelErr != nil {
+				logging.WithStacktrace(ctx, cancelErr).Warnf("failed to cancel canary job %s", outcome.JobId)
+			}
+			outcome.Reason = fmt.Sprintf("timed out after %s", probe.Timeout)
+		}
+		return err
+	})
+	return outcome, err
+}
+
+// follow updates outcome from the events of the canary job until it succeeds or fails.
+func (r *ApiCanaryRunner) follow(ctx *armadacontext.Context, eventClient api.EventClient, queue string, jobSetId string, outcome *CanaryOutcome) error {
+	stream, err := eventClient.GetJobSetEvents(ctx, &api.JobSetRequest{
+		Id:    jobSetId,
+		Queue: queue,
+		Watch: true,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		switch e := msg.GetMessage().GetEvents().(type) {
+		case *api.EventMessage_Running:
+			if outcome.Running.IsZero() {
+				outcome.Running = r.clock.Now()
+			}
+		case *api.EventMessage_Succeeded:
+			outcome.Finished = r.clock.Now()
+			outcome.Succeeded = true
+			return nil
+		case *api.EventMessage_Failed:
+			outcome.Finished = r.clock.Now()
+			outcome.Reason = e.Failed.GetReason()
+			return nil
+		case *api.EventMessage_FailedCompressed:
+			outcome.Finished = r.clock.Now()
+			outcome.Reason = "job failed"
+			return nil
+		case *api.EventMessage_Cancelled:
+			outcome.Finished = r.clock.Now()
+			outcome.Reason = "job cancelled"
+			return nil
+		case *api.EventMessage_Preempted:
+			outcome.Finished = r.clock.Now()
+			outcome.Reason = "job preempted"
+			return nil
+		}
+	}
+}
+
+// CanaryHttpHandler serves the state of each canary probe as json.
+type CanaryHttpHandler struct {
+	canary *Canary
+}
+
+func NewCanaryHttpHandler(canary *Canary) *CanaryHttpHandler {
+	return &CanaryHttpHandler{canary: canary}
+}
+
+func (h *CanaryHttpHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.canary.Statuses()); err != nil {
+		log.WithError(err).Error("failed to write canary response")
+	}
+}
@@ -0,0 +1,163 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+)
+
+func TestCanary(t *testing.T) {
+	submitted := time.Date(2023, 11, 15, 6, 0, 0, 0, time.UTC)
+	runner := &fakeCanaryRunner{}
+	alerter := &recordingAlerter{}
+	canary, err := NewCanary(
+		schedulerconfig.CanaryConfig{
+			Interval: time.Minute,
+			Probes: []schedulerconfig.CanaryProbe{
+				{Queue: "canary", Pool: "cpu", PriorityClassName: "armada-default", Image: "alpine", MaxLatency: time.Minute, Timeout: time.Hour},
+			},
+		},
+		runner,
+		&FakeLeaderController{IsCurrentlyLeader: true},
+		alerter,
+	)
+	require.NoError(t, err)
+
+	// A canary job succeeding within the maximum latency is healthy.
+	runner.outcome = &CanaryOutcome{
+		JobId:     "job1",
+		Submitted: submitted,
+		Running:   submitted.Add(10 * time.Second),
+		Finished:  submitted.Add(30 * time.Second),
+		Succeeded: true,
+	}
+	require.True(t, canary.startProbe(0))
+	assert.False(t, canary.startProbe(0), "probe already in flight")
+	canary.probe(armadacontext.Background(), 0)
+	status := canary.Statuses()[0]
+	assert.False(t, status.InFlight)
+	assert.True(t, status.Healthy)
+	assert.Equal(t, 10*time.Second, status.LastStartLatency)
+	assert.Equal(t, 30*time.Second, status.LastLatency)
+	assert.Empty(t, alerter.alerts)
+
+	// A canary job exceeding the maximum latency is unhealthy.
+	runner.outcome = &CanaryOutcome{
+		JobId:     "job2",
+		Submitted: submitted,
+		Running:   submitted.Add(time.Minute),
+		Finished:  submitted.Add(2 * time.Minute),
+		Succeeded: true,
+	}
+	canary.probe(armadacontext.Background(), 0)
+	status = canary.Statuses()[0]
+	assert.False(t, status.Healthy)
+	assert.Equal(t, 1, status.ConsecutiveFailures)
+	require.Len(t, alerter.alerts, 1)
+	assert.Equal(t, AlertCanary, alerter.alerts[0].Type)
+	assert.Equal(t, "cpu", alerter.alerts[0].Pool)
+	assert.Equal(t, "canary", alerter.alerts[0].Subject)
+	assert.Contains(t, alerter.alerts[0].Text, "job2")
+
+	// Canary jobs that can't be run are unhealthy.
+	runner.outcome = nil
+	runner.err = assert.AnError
+	canary.probe(armadacontext.Background(), 0)
+	status = canary.Statuses()[0]
+	assert.False(t, status.Healthy)
+	assert.Equal(t, 2, status.ConsecutiveFailures)
+	assert.Equal(t, assert.AnError.Error(), status.LastOutcome.Reason)
+	require.Len(t, alerter.alerts, 2)
+
+	// Consecutive failures reset once a canary job is healthy again.
+	runner.outcome = &CanaryOutcome{JobId: "job3", Submitted: submitted, Finished: submitted.Add(time.Second), Succeeded: true}
+	runner.err = nil
+	canary.probe(armadacontext.Background(), 0)
+	status = canary.Statuses()[0]
+	assert.True(t, status.Healthy)
+	assert.Equal(t, 0, status.ConsecutiveFailures)
+	assert.Len(t, alerter.alerts, 2)
+}
+
+func TestNewCanary_InvalidConfig(t *testing.T) {
+	valid := schedulerconfig.CanaryConfig{
+		Interval: time.Minute,
+		Probes: []schedulerconfig.CanaryProbe{
+			{Queue: "canary", Pool: "cpu", Image: "alpine", Resources: map[string]string{"cpu": "100m"}, Timeout: time.Hour},
+		},
+	}
+	_, err := NewCanary(valid, &fakeCanaryRunner{}, &FakeLeaderController{}, nil)
+	require.NoError(t, err)
+
+	tests := map[string]func(config *schedulerconfig.CanaryConfig){
+		"zero interval": func(config *schedulerconfig.CanaryConfig) { config.Interval = 0 },
+		"missing queue": func(config *schedulerconfig.CanaryConfig) { config.Probes[0].Queue = "" },
+		"missing pool":  func(config *schedulerconfig.CanaryConfig) { config.Probes[0].Pool = "" },
+		"missing image": func(config *schedulerconfig.CanaryConfig) { config.Probes[0].Image = "" },
+		"zero timeout":  func(config *schedulerconfig.CanaryConfig) { config.Probes[0].Timeout = 0 },
+		"invalid resource": func(config *schedulerconfig.CanaryConfig) {
+			config.Probes[0].Resources = map[string]string{"cpu": "lots"}
+		},
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := valid
+			config.Probes = []schedulerconfig.CanaryProbe{valid.Probes[0]}
+			modify(&config)
+			_, err := NewCanary(config, &fakeCanaryRunner{}, &FakeLeaderController{}, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCanaryJobSubmitRequest(t *testing.T) {
+	req, err := canaryJobSubmitRequest(
+		schedulerconfig.CanaryProbe{
+			Queue:             "canary",
+			Pool:              "gpu",
+			PriorityClassName: "armada-preemptible",
+			Namespace:         "armada-canary",
+			Image:             "alpine",
+			Command:           []string{"true"},
+			Resources:         map[string]string{"cpu": "100m", "memory": "64Mi"},
+		},
+		"jobSet",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "canary", req.Queue)
+	assert.Equal(t, "jobSet", req.JobSetId)
+	require.Len(t, req.JobRequestItems, 1)
+	item := req.JobRequestItems[0]
+	assert.Equal(t, "armada-canary", item.Namespace)
+	assert.Equal(t, map[string]string{configuration.PoolAnnotation: "gpu"}, item.Annotations)
+	require.Len(t, item.PodSpecs, 1)
+	podSpec := item.PodSpecs[0]
+	assert.Equal(t, "armada-preemptible", podSpec.PriorityClassName)
+	require.Len(t, podSpec.Containers, 1)
+	assert.Equal(t, "alpine", podSpec.Containers[0].Image)
+	assert.Equal(t, []string{"true"}, podSpec.Containers[0].Command)
+	expectedResources := v1.ResourceList{"cpu": resource.MustParse("100m"), "memory": resource.MustParse("64Mi")}
+	assert.Equal(t, expectedResources, podSpec.Containers[0].Resources.Requests)
+	assert.Equal(t, expectedResources, podSpec.Containers[0].Resources.Limits)
+}
+
+type fakeCanaryRunner struct {
+	outcome *CanaryOutcome
+	err     error
+}
+
+func (r *fakeCanaryRunner) Run(_ *armadacontext.Context, _ schedulerconfig.CanaryProbe) (*CanaryOutcome, error) {
+	if r.outcome == nil {
+		return nil, r.err
+	}
+	outcome := *r.outcome
+	return &outcome, r.err
+}
@@ -0,0 +1,196 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/scheduler/adapters"
+	"github.com/armadaproject/armada/internal/scheduler/database"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/nodedb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// CandidateNodesHttpHandler reports the nodes a pod could currently be scheduled on, to help users debug
+// node selectors, tolerations, and resource requests before submitting.
+// The pod spec is given as the json-encoded request body; the nodes considered are those of all executors
+// that have heartbeated recently, with the resources allocated to running jobs accounted for.
+// Results may be restricted to a single pool by the pool query parameter, e.g., /candidateNodes?pool=cpu.
+type CandidateNodesHttpHandler struct {
+	schedulingConfig   configuration.SchedulingConfig
+	executorRepository database.ExecutorRepository
+	jobDb              *jobdb.JobDb
+	clock              clock.Clock
+}
+
+// CandidateNodes is the response returned by CandidateNodesHttpHandler.
+type CandidateNodes struct {
+	PriorityClassName string `json:"priorityClassName"`
+	Priority          int32  `json:"priority"`
+	// Number of nodes considered.
+	NumNodes int `json:"numNodes"`
+	// Number of nodes the pod can never be scheduled on, e.g., due to untolerated taints or unmatched selectors,
+	// by reason for exclusion.
+	NumExcludedNodesByReason map[string]int `json:"numExcludedNodesByReason"`
+	// Nodes the pod could be scheduled on at each priority up to and including its own,
+	// ordered by increasing priority. Scheduling at the lowest priority requires no preemption;
+	// scheduling at priority p requires preempting jobs of priority lower than p.
+	CandidatesByPriority []*PriorityCandidateNodes `json:"candidatesByPriority"`
+}
+
+type PriorityCandidateNodes struct {
+	Priority int32                  `json:"priority"`
+	NumNodes int                    `json:"numNodes"`
+	Buckets  []*CandidateNodeBucket `json:"buckets"`
+}
+
+// CandidateNodeBucket is a set of candidate nodes of the same shape, i.e., in the same executor and with the same total resources.
+type CandidateNodeBucket struct {
+	Pool           string                        `json:"pool"`
+	Executor       string                        `json:"executor"`
+	TotalResources schedulerobjects.ResourceList `json:"totalResources"`
+	Nodes          []string                      `json:"nodes"`
+}
+
+func NewCandidateNodesHttpHandler(
+	schedulingConfig configuration.SchedulingConfig,
+	executorRepository database.ExecutorRepository,
+	jobDb *jobdb.JobDb,
+) *CandidateNodesHttpHandler {
+	return &CandidateNodesHttpHandler{
+		schedulingConfig:   schedulingConfig,
+		executorRepository: executorRepository,
+		jobDb:              jobDb,
+		clock:              clock.RealClock{},
+	}
+}
+
+func (h *CandidateNodesHttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "expected a POST request with a pod spec as body", http.StatusMethodNotAllowed)
+		return
+	}
+	var podSpec v1.PodSpec
+	if err := json.NewDecoder(r.Body).Decode(&podSpec); err != nil {
+		http.Error(w, "invalid pod spec: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if podSpec.PriorityClassName == "" {
+		podSpec.PriorityClassName = h.schedulingConfig.Preemption.DefaultPriorityClass
+	}
+	if _, ok := h.schedulingConfig.Preemption.PriorityClasses[podSpec.PriorityClassName]; !ok {
+		http.Error(w, "unknown priority class "+podSpec.PriorityClassName, http.StatusBadRequest)
+		return
+	}
+	// Only the priority class determines the priority jobs are scheduled at.
+	podSpec.Priority = nil
+
+	ctx := armadacontext.New(r.Context(), log.NewEntry(log.StandardLogger()))
+	nodeDbs, err := liveExecutorNodeDbs(ctx, h.schedulingConfig, h.executorRepository, h.jobDb, h.clock.Now(), r.URL.Query().Get("pool"))
+	if err != nil {
+		log.WithError(err).Error("failed to construct node dbs")
+		http.Error(w, "failed to construct node dbs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rv, err := h.candidateNodes(&podSpec, nodeDbs)
+	if err != nil {
+		log.WithError(err).Error("failed to compute candidate nodes")
+		http.Error(w, "failed to compute candidate nodes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rv); err != nil {
+		log.WithError(err).Error("failed to write candidate nodes response")
+	}
+}
+
+func (h *CandidateNodesHttpHandler) candidateNodes(podSpec *v1.PodSpec, nodeDbs []executorNodeDb) (*CandidateNodes, error) {
+	priorityClasses := h.schedulingConfig.Preemption.PriorityClasses
+	req := adapters.PodRequirementsFromPodSpec(podSpec, priorityClasses)
+	rv := &CandidateNodes{
+		PriorityClassName:        podSpec.PriorityClassName,
+		Priority:                 req.Priority,
+		NumExcludedNodesByReason: make(map[string]int),
+	}
+	var priorities []int32
+	for _, priority := range h.schedulingConfig.Preemption.AllowedPriorities() {
+		if priority <= req.Priority {
+			priorities = append(priorities, priority)
+		}
+	}
+	bucketsByPriority := make([]map[string]*CandidateNodeBucket, len(priorities))
+	for i := range bucketsByPriority {
+		bucketsByPriority[i] = make(map[string]*CandidateNodeBucket)
+	}
+
+	for _, e := range nodeDbs {
+		executor := e.executor
+		it, err := nodedb.NewNodesIterator(e.nodeDb.Txn(false))
+		if err != nil {
+			return nil, err
+		}
+		for node := it.NextNode(); node != nil; node = it.NextNode() {
+			rv.NumNodes++
+			matches, reason, err := schedulerobjects.StaticPodRequirementsMet(node.Taints, node.Labels, node.TotalResources, req)
+			if err != nil {
+				return nil, err
+			}
+			if !matches {
+				if reason != nil {
+					rv.NumExcludedNodesByReason[reason.String()]++
+				} else {
+					rv.NumExcludedNodesByReason[schedulerobjects.PodRequirementsNotMetReasonUnknown]++
+				}
+				continue
+			}
+			key := executor.Id + "/" + node.TotalResources.CompactString()
+			for i, priority := range priorities {
+				matches, _, _, err := schedulerobjects.DynamicPodRequirementsMet(node.AllocatableByPriority[priority], req)
+				if err != nil {
+					return nil, err
+				}
+				if !matches {
+					continue
+				}
+				bucket, ok := bucketsByPriority[i][key]
+				if !ok {
+					bucket = &CandidateNodeBucket{
+						Pool:           executor.Pool,
+						Executor:       executor.Id,
+						TotalResources: node.TotalResources,
+					}
+					bucketsByPriority[i][key] = bucket
+				}
+				bucket.Nodes = append(bucket.Nodes, node.Name)
+			}
+		}
+	}
+
+	for i, priority := range priorities {
+		candidates := &PriorityCandidateNodes{
+			Priority: priority,
+			Buckets:  maps.Values(bucketsByPriority[i]),
+		}
+		slices.SortFunc(candidates.Buckets, func(a, b *CandidateNodeBucket) bool {
+			if a.Executor != b.Executor {
+				return a.Executor < b.Executor
+			}
+			return a.TotalResources.CompactString() < b.TotalResources.CompactString()
+		})
+		for _, bucket := range candidates.Buckets {
+			slices.Sort(bucket.Nodes)
+			candidates.NumNodes += len(bucket.Nodes)
+		}
+		rv.CandidatesByPriority = append(rv.CandidatesByPriority, candidates)
+	}
+	return rv, nil
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	schedulermocks "github.com/armadaproject/armada/internal/scheduler/mocks"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestCandidateNodesHttpHandler(t *testing.T) {
+	baseTime := time.Now()
+	freeNode := testfixtures.Test32CpuNode(testfixtures.TestPriorities)
+	usedNode := testfixtures.WithUsedResourcesNodes(
+		2,
+		schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("32")}},
+		testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+	)[0]
+	taintedNode := testfixtures.TestTainted32CpuNode(testfixtures.TestPriorities)
+	executors := []*schedulerobjects.Executor{
+		{
+			Id:             "executor-1",
+			Pool:           "cpu",
+			Nodes:          []*schedulerobjects.Node{freeNode, usedNode, taintedNode},
+			LastUpdateTime: baseTime,
+		},
+		{
+			Id:             "stale-executor",
+			Pool:           "cpu",
+			Nodes:          testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+			LastUpdateTime: baseTime.Add(-time.Hour),
+		},
+	}
+	podSpec := v1.PodSpec{
+		Containers: []v1.Container{
+			{
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{"cpu": resource.MustParse("1"), "memory": resource.MustParse("4Gi")},
+					Limits:   v1.ResourceList{"cpu": resource.MustParse("1"), "memory": resource.MustParse("4Gi")},
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		priorityClassName     string
+		pool                  string
+		expectedStatus        int
+		expectedNumNodes      int
+		expectedNumExcluded   int
+		expectedNumByPriority map[int32]int
+	}{
+		"default priority class": {
+			expectedStatus:        http.StatusOK,
+			expectedNumNodes:      3,
+			expectedNumExcluded:   1,
+			expectedNumByPriority: map[int32]int{0: 1, 1: 1, 2: 1, 3: 2},
+		},
+		"low priority class": {
+			priorityClassName:     testfixtures.PriorityClass1,
+			expectedStatus:        http.StatusOK,
+			expectedNumNodes:      3,
+			expectedNumExcluded:   1,
+			expectedNumByPriority: map[int32]int{0: 1, 1: 1},
+		},
+		"other pool": {
+			pool:                  "gpu",
+			expectedStatus:        http.StatusOK,
+			expectedNumByPriority: map[int32]int{0: 0, 1: 0, 2: 0, 3: 0},
+		},
+		"unknown priority class": {
+			priorityClassName: "foo",
+			expectedStatus:    http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockExecutorRepo := schedulermocks.NewMockExecutorRepository(ctrl)
+			mockExecutorRepo.EXPECT().GetExecutors(gomock.Any()).Return(executors, nil).AnyTimes()
+			handler := NewCandidateNodesHttpHandler(testfixtures.TestSchedulingConfig(), mockExecutorRepo, testfixtures.NewJobDb())
+			handler.clock = clock.NewFakeClock(baseTime)
+
+			podSpec := podSpec
+			podSpec.PriorityClassName = tc.priorityClassName
+			body, err := json.Marshal(podSpec)
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/candidateNodes?pool="+tc.pool, bytes.NewReader(body)))
+			require.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var rv CandidateNodes
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&rv))
+			assert.Equal(t, tc.expectedNumNodes, rv.NumNodes)
+			numExcluded := 0
+			for _, n := range rv.NumExcludedNodesByReason {
+				numExcluded += n
+			}
+			assert.Equal(t, tc.expectedNumExcluded, numExcluded)
+			numByPriority := make(map[int32]int)
+			for _, candidates := range rv.CandidatesByPriority {
+				numByPriority[candidates.Priority] = candidates.NumNodes
+				for _, bucket := range candidates.Buckets {
+					assert.Equal(t, "executor-1", bucket.Executor)
+				}
+			}
+			assert.Equal(t, tc.expectedNumByPriority, numByPriority)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// PoolCapacityForecast is the forecast capacity of a pool Horizon from now.
+// Capacity only counts schedulable nodes, i.e., nodes that aren't cordoned.
+type PoolCapacityForecast struct {
+	Pool    string        `json:"pool"`
+	Horizon time.Duration `json:"horizon"`
+	// Schedulable capacity of the pool as of the most recent scheduling round.
+	Current schedulerobjects.ResourceList `json:"current"`
+	// Net capacity added by nodes joining and leaving the pool over the trend window, extrapolated over Horizon.
+	// Nodes being cordoned or uncordoned don't count towards the trend; planned maintenance is accounted for separately.
+	Trend schedulerobjects.ResourceList `json:"trend"`
+	// Capacity of cordoned nodes scheduled to leave maintenance within Horizon.
+	MaintenanceEnding schedulerobjects.ResourceList `json:"maintenanceEnding"`
+	// Capacity of schedulable nodes scheduled to enter maintenance within Horizon.
+	MaintenanceStarting schedulerobjects.ResourceList `json:"maintenanceStarting"`
+	// Current + Trend + MaintenanceEnding - MaintenanceStarting.
+	Forecast schedulerobjects.ResourceList `json:"forecast"`
+}
+
+// nodeLifecycleEvent is a change in the capacity of a pool since nodes joined or left it.
+type nodeLifecycleEvent struct {
+	time  time.Time
+	pool  string
+	delta schedulerobjects.ResourceList
+}
+
+// CapacityForecaster forecasts the capacity of each pool a short horizon ahead, from the trend of nodes joining and leaving
+// the pool, as observed by scheduling rounds, and from the maintenance schedules of nodes, as set via node labels.
+// Scheduling rounds use the forecast to hold large gangs past their scheduling deadline, rather than failing them,
+// while capacity they'd fit onto is imminent.
+type CapacityForecaster struct {
+	config schedulerconfig.CapacityForecastConfig
+	clock  clock.Clock
+
+	mu sync.Mutex
+	// Capacity of each node as of the most recent observation, by pool and node id; nil before the first one.
+	capacityByPoolAndNodeId map[string]map[string]schedulerobjects.ResourceList
+	// Capacity of the schedulable nodes of each pool as of the most recent observation.
+	schedulableCapacityByPool map[string]schedulerobjects.ResourceList
+	// Nodes joining and leaving pools within the trend window, oldest first.
+	events []nodeLifecycleEvent
+	// Capacity scheduled to leave and enter maintenance within the horizon, by pool, as of the most recent observation.
+	maintenanceEndingByPool   map[string]schedulerobjects.ResourceList
+	maintenanceStartingByPool map[string]schedulerobjects.ResourceList
+}
+
+func NewCapacityForecaster(config schedulerconfig.CapacityForecastConfig) (*CapacityForecaster, error) {
+	if config.Horizon <= 0 {
+		return nil, errors.Errorf("capacity forecast horizon %s must be positive", config.Horizon)
+	}
+	if config.TrendWindow <= 0 {
+		return nil, errors.Errorf("capacity forecast trend window %s must be positive", config.TrendWindow)
+	}
+	return &CapacityForecaster{
+		config:                    config,
+		clock:                     clock.RealClock{},
+		schedulableCapacityByPool: make(map[string]schedulerobjects.ResourceList),
+		maintenanceEndingByPool:   make(map[string]schedulerobjects.ResourceList),
+		maintenanceStartingByPool: make(map[string]schedulerobjects.ResourceList),
+	}, nil
+}
+
+// Observe records the capacity of the nodes of each executor and their maintenance schedules.
+// Nodes joining or leaving a pool since the previous observation count towards its trend; nothing does on the first observation.
+func (f *CapacityForecaster) Observe(executors []*schedulerobjects.Executor) {
+	now := f.clock.Now()
+	capacityByPoolAndNodeId := make(map[string]map[string]schedulerobjects.ResourceList)
+	schedulableCapacityByPool := make(map[string]schedulerobjects.ResourceList)
+	maintenanceEndingByPool := make(map[string]schedulerobjects.ResourceList)
+	maintenanceStartingByPool := make(map[string]schedulerobjects.ResourceList)
+	for _, executor := range executors {
+		capacityByNodeId := capacityByPoolAndNodeId[executor.Pool]
+		if capacityByNodeId == nil {
+			capacityByNodeId = make(map[string]schedulerobjects.ResourceList)
+			capacityByPoolAndNodeId[executor.Pool] = capacityByNodeId
+		}
+		for _, node := range executor.Nodes {
+			capacityByNodeId[node.Id] = node.TotalResources
+			if node.Unschedulable {
+				if f.inHorizon(node, f.config.MaintenanceEndLabel, now) {
+					addToResourceListByPool(maintenanceEndingByPool, executor.Pool, node.TotalResources)
+				}
+				continue
+			}
+			addToResourceListByPool(schedulableCapacityByPool, executor.Pool, node.TotalResources)
+			if f.inHorizon(node, f.config.MaintenanceStartLabel, now) {
+				addToResourceListByPool(maintenanceStartingByPool, executor.Pool, node.TotalResources)
+			}
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.capacityByPoolAndNodeId != nil {
+		for pool, capacityByNodeId := range capacityByPoolAndNodeId {
+			var delta schedulerobjects.ResourceList
+			previous := f.capacityByPoolAndNodeId[pool]
+			for nodeId, capacity := range capacityByNodeId {
+				if _, ok := previous[nodeId]; !ok {
+					delta.Add(capacity)
+				}
+			}
+			for nodeId, capacity := range previous {
+				if _, ok := capacityByNodeId[nodeId]; !ok {
+					delta.Sub(capacity)
+				}
+			}
+			if !delta.IsZero() {
+				f.events = append(f.events, nodeLifecycleEvent{time: now, pool: pool, delta: delta})
+			}
+		}
+		for pool, previous := range f.capacityByPoolAndNodeId {
+			if _, ok := capacityByPoolAndNodeId[pool]; ok {
+				continue
+			}
+			var delta schedulerobjects.ResourceList
+			for _, capacity := range previous {
+				delta.Sub(capacity)
+			}
+			if !delta.IsZero() {
+				f.events = append(f.events, nodeLifecycleEvent{time: now, pool: pool, delta: delta})
+			}
+		}
+	}
+	i := 0
+	for i < len(f.events) && now.Sub(f.events[i].time) > f.config.TrendWindow {
+		i++
+	}
+	f.events = f.events[i:]
+	f.capacityByPoolAndNodeId = capacityByPoolAndNodeId
+	f.schedulableCapacityByPool = schedulableCapacityByPool
+	f.maintenanceEndingByPool = maintenanceEndingByPool
+	f.maintenanceStartingByPool = maintenanceStartingByPool
+}
+
+// inHorizon returns true if the node has a label with the provided name whose value,
+// in seconds since the unix epoch, is after now but no later than the forecast horizon.
+func (f *CapacityForecaster) inHorizon(node *schedulerobjects.Node, label string, now time.Time) bool {
+	if label == "" {
+		return false
+	}
+	value, ok := node.Labels[label]
+	if !ok {
+		return false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Warnf("ignoring invalid value %s of label %s of node %s; must be seconds since the unix epoch", value, label, node.Id)
+		return false
+	}
+	t := time.Unix(seconds, 0)
+	return t.After(now) && !t.After(now.Add(f.config.Horizon))
+}
+
+func addToResourceListByPool(rlByPool map[string]schedulerobjects.ResourceList, pool string, rl schedulerobjects.ResourceList) {
+	sum := rlByPool[pool]
+	sum.Add(rl)
+	rlByPool[pool] = sum
+}
+
+// Forecast returns the forecast capacity of the provided pool.
+func (f *CapacityForecaster) Forecast(pool string) PoolCapacityForecast {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.forecast(pool)
+}
+
+func (f *CapacityForecaster) forecast(pool string) PoolCapacityForecast {
+	rv := PoolCapacityForecast{
+		Pool:                pool,
+		Horizon:             f.config.Horizon,
+		Current:             f.schedulableCapacityByPool[pool].DeepCopy(),
+		Trend:               schedulerobjects.NewResourceListWithDefaultSize(),
+		MaintenanceEnding:   f.maintenanceEndingByPool[pool].DeepCopy(),
+		MaintenanceStarting: f.maintenanceStartingByPool[pool].DeepCopy(),
+	}
+	for _, event := range f.events {
+		if event.pool == pool {
+			rv.Trend.Add(event.delta)
+		}
+	}
+	scale := float64(f.config.Horizon) / float64(f.config.TrendWindow)
+	for t, q := range rv.Trend.Resources {
+		rv.Trend.Resources[t] = *resource.NewMilliQuantity(int64(float64(q.MilliValue())*scale), q.Format)
+	}
+	rv.Forecast = rv.Current.DeepCopy()
+	rv.Forecast.Add(rv.Trend)
+	rv.Forecast.Add(rv.MaintenanceEnding)
+	rv.Forecast.Sub(rv.MaintenanceStarting)
+	return rv
+}
+
+// Forecasts returns the forecast capacity of each pool observed, sorted by pool.
+func (f *CapacityForecaster) Forecasts() []PoolCapacityForecast {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	pools := maps.Keys(f.capacityByPoolAndNodeId)
+	slices.Sort(pools)
+	rv := make([]PoolCapacityForecast, len(pools))
+	for i, pool := range pools {
+		rv[i] = f.forecast(pool)
+	}
+	return rv
+}
+
+// SchedulingForecast returns the forecast used by scheduling rounds of the provided pool to decide which gangs to hold.
+func (f *CapacityForecaster) SchedulingForecast(pool string) *schedulercontext.CapacityForecast {
+	return &schedulercontext.CapacityForecast{
+		Capacity:           f.Forecast(pool).Forecast,
+		MinGangCardinality: int(f.config.MinGangCardinality),
+		MaxHoldDuration:    f.config.MaxHoldDuration,
+	}
+}
+
+// CapacityForecastHttpHandler serves the capacity forecast of each pool as json.
+type CapacityForecastHttpHandler struct {
+	forecaster *CapacityForecaster
+}
+
+func NewCapacityForecastHttpHandler(forecaster *CapacityForecaster) *CapacityForecastHttpHandler {
+	return &CapacityForecastHttpHandler{forecaster: forecaster}
+}
+
+func (h *CapacityForecastHttpHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.forecaster.Forecasts()); err != nil {
+		log.WithError(err).Error("failed to write capacity forecast response")
+	}
+}
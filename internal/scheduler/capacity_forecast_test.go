@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestCapacityForecaster(t *testing.T) {
+	forecaster, err := NewCapacityForecaster(schedulerconfig.CapacityForecastConfig{
+		Horizon:               10 * time.Minute,
+		TrendWindow:           20 * time.Minute,
+		MaintenanceStartLabel: "maintenance-start",
+		MaintenanceEndLabel:   "maintenance-end",
+		MinGangCardinality:    8,
+		MaxHoldDuration:       time.Minute,
+	})
+	require.NoError(t, err)
+	fakeClock := clock.NewFakeClock(time.Date(2023, 11, 15, 6, 0, 0, 0, time.UTC))
+	forecaster.clock = fakeClock
+	cpu := func(rl schedulerobjects.ResourceList) int64 {
+		q := rl.Get("cpu")
+		return q.Value()
+	}
+
+	// Nodes observed first don't count towards the trend.
+	executor := &schedulerobjects.Executor{Id: "executor", Pool: "pool", Nodes: testfixtures.N32CpuNodes(2, testfixtures.TestPriorities)}
+	forecaster.Observe([]*schedulerobjects.Executor{executor})
+	forecast := forecaster.Forecast("pool")
+	assert.Equal(t, int64(64), cpu(forecast.Current))
+	assert.Equal(t, int64(0), cpu(forecast.Trend))
+	assert.Equal(t, int64(64), cpu(forecast.Forecast))
+
+	// Two nodes joining within the trend window add half their capacity over a horizon half as long.
+	fakeClock.Step(5 * time.Minute)
+	executor.Nodes = append(executor.Nodes, testfixtures.N32CpuNodes(2, testfixtures.TestPriorities)...)
+	forecaster.Observe([]*schedulerobjects.Executor{executor})
+	forecast = forecaster.Forecast("pool")
+	assert.Equal(t, int64(128), cpu(forecast.Current))
+	assert.Equal(t, int64(32), cpu(forecast.Trend))
+	assert.Equal(t, int64(160), cpu(forecast.Forecast))
+
+	// Cordoned nodes leaving maintenance within the horizon count towards forecast capacity,
+	// whereas nodes entering maintenance within the horizon don't, and nodes entering maintenance later on do.
+	fakeClock.Step(time.Minute)
+	withinHorizon := strconv.FormatInt(fakeClock.Now().Add(5*time.Minute).Unix(), 10)
+	pastHorizon := strconv.FormatInt(fakeClock.Now().Add(time.Hour).Unix(), 10)
+	executor.Nodes[0].Unschedulable = true
+	executor.Nodes[0].Labels["maintenance-end"] = withinHorizon
+	executor.Nodes[1].Labels["maintenance-start"] = withinHorizon
+	executor.Nodes[2].Labels["maintenance-start"] = pastHorizon
+	forecaster.Observe([]*schedulerobjects.Executor{executor})
+	forecast = forecaster.Forecast("pool")
+	assert.Equal(t, int64(96), cpu(forecast.Current))
+	assert.Equal(t, int64(32), cpu(forecast.Trend))
+	assert.Equal(t, int64(32), cpu(forecast.MaintenanceEnding))
+	assert.Equal(t, int64(32), cpu(forecast.MaintenanceStarting))
+	assert.Equal(t, int64(128), cpu(forecast.Forecast))
+
+	// Nodes leaving the pool count against its trend, and nodes that joined outside the trend window don't count at all.
+	fakeClock.Step(20 * time.Minute)
+	executor.Nodes = executor.Nodes[:3]
+	forecaster.Observe([]*schedulerobjects.Executor{executor})
+	forecast = forecaster.Forecast("pool")
+	assert.Equal(t, int64(-16), cpu(forecast.Trend))
+
+	schedulingForecast := forecaster.SchedulingForecast("pool")
+	assert.Equal(t, cpu(forecast.Forecast), cpu(schedulingForecast.Capacity))
+	assert.Equal(t, 8, schedulingForecast.MinGangCardinality)
+	assert.Equal(t, time.Minute, schedulingForecast.MaxHoldDuration)
+
+	forecasts := forecaster.Forecasts()
+	require.Len(t, forecasts, 1)
+	assert.Equal(t, "pool", forecasts[0].Pool)
+}
@@ -0,0 +1,240 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/logging"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// ClusterResourceUsageSource reports the resource-hours consumed on each cluster, i.e., executor, over some interval.
+type ClusterResourceUsageSource interface {
+	GetResourceHoursByCluster(ctx *armadacontext.Context, from time.Time, to time.Time) (map[string]map[string]float64, error)
+}
+
+// CloudBurstStatus is the state of the cloud burst policy.
+type CloudBurstStatus struct {
+	CloudPool string   `json:"cloudPool"`
+	Queues    []string `json:"queues"`
+	// Time since which all on-prem pools have been saturated; nil if any isn't.
+	SaturatedSince *time.Time `json:"saturatedSince,omitempty"`
+	// True if queued jobs of Queues currently overflow into CloudPool.
+	Overflowing bool `json:"overflowing"`
+	// Spend in CloudPool since MonthStart.
+	MonthStart        time.Time `json:"monthStart"`
+	Spend             float64   `json:"spend"`
+	MonthlySpendLimit float64   `json:"monthlySpendLimit"`
+	// True if overflow is halted, since spend reached the limit or hasn't been computed yet.
+	Halted bool `json:"halted"`
+	// Time at which spend was last computed; zero if it hasn't been yet.
+	SpendUpdated time.Time `json:"spendUpdated"`
+}
+
+// CloudBurstPolicy decides when queued jobs of designated queues may overflow into a cloud pool.
+// Overflow starts once all on-prem pools have been saturated for longer than the configured duration, as observed by
+// scheduling rounds, and stops as soon as any of them isn't or once the monthly spend limit of the cloud pool is reached.
+// Spend is computed from the resource-hours consumed on the executors of the cloud pool, as recorded by Lookout,
+// and is refreshed periodically by Run.
+type CloudBurstPolicy struct {
+	config schedulerconfig.CloudBurstConfig
+	source ClusterResourceUsageSource
+	clock  clock.Clock
+	queues map[string]bool
+
+	mu sync.RWMutex
+	// Ids of the executors observed in the cloud pool, whose usage counts towards spend.
+	cloudExecutorIds map[string]bool
+	saturatedSince   *time.Time
+	// Resource-hours consumed on each cluster since monthStart, as of spendUpdated.
+	resourceHoursByCluster map[string]map[string]float64
+	monthStart             time.Time
+	spendUpdated           time.Time
+}
+
+func NewCloudBurstPolicy(config schedulerconfig.CloudBurstConfig, source ClusterResourceUsageSource) (*CloudBurstPolicy, error) {
+	if config.CloudPool == "" {
+		return nil, errors.New("cloud burst policy must specify a cloud pool")
+	}
+	if len(config.OnPremPools) == 0 {
+		return nil, errors.New("cloud burst policy must specify at least one on-prem pool")
+	}
+	if slices.Contains(config.OnPremPools, config.CloudPool) {
+		return nil, errors.Errorf("cloud pool %s can't also be an on-prem pool", config.CloudPool)
+	}
+	if config.SaturationThreshold <= 0 || config.SaturationThreshold > 1 {
+		return nil, errors.Errorf("saturation threshold %f must be in (0, 1]", config.SaturationThreshold)
+	}
+	queues := make(map[string]bool, len(config.Queues))
+	for _, queue := range config.Queues {
+		queues[queue] = true
+	}
+	return &CloudBurstPolicy{
+		config:           config,
+		source:           source,
+		clock:            clock.RealClock{},
+		queues:           queues,
+		cloudExecutorIds: make(map[string]bool),
+	}, nil
+}
+
+// Run refreshes spend every interval until the provided context is cancelled.
+func (p *CloudBurstPolicy) Run(ctx *armadacontext.Context, interval time.Duration) error {
+	logger := log.StandardLogger().WithField("service", "CloudBurstPolicy")
+	logger.Info("service started")
+	ticker := p.clock.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := p.Refresh(ctx); err != nil {
+			logging.WithStacktrace(logger, err).Warn("failed to refresh cloud burst spend")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
+
+// Refresh recomputes the resource-hours consumed since the start of the month.
+// If usage can't be computed, the previously computed usage is kept.
+func (p *CloudBurstPolicy) Refresh(ctx *armadacontext.Context) error {
+	now := p.clock.Now()
+	monthStart, _, err := budgetWindow(BudgetWindowMonth, now)
+	if err != nil {
+		return err
+	}
+	resourceHoursByCluster, err := p.source.GetResourceHoursByCluster(ctx, monthStart, now)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resourceHoursByCluster = resourceHoursByCluster
+	p.monthStart = monthStart
+	p.spendUpdated = now
+	return nil
+}
+
+// Observe records the executors of the cloud pool and whether the on-prem pools are saturated,
+// given the capacity of each pool and the resources allocated in each pool by queue and priority class.
+func (p *CloudBurstPolicy) Observe(
+	executors []*schedulerobjects.Executor,
+	totalCapacityByPool schedulerobjects.QuantityByTAndResourceType[string],
+	allocationByPoolAndQueueAndPriorityClass map[string]map[string]schedulerobjects.QuantityByTAndResourceType[string],
+) {
+	saturated := true
+	for _, pool := range p.config.OnPremPools {
+		var allocated schedulerobjects.ResourceList
+		for _, allocatedByPriorityClass := range allocationByPoolAndQueueAndPriorityClass[pool] {
+			allocated.Add(allocatedByPriorityClass.AggregateByResource())
+		}
+		if !isPoolSaturated(totalCapacityByPool[pool], allocated, p.config.SaturationThreshold) {
+			saturated = false
+			break
+		}
+	}
+
+	now := p.clock.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, executor := range executors {
+		if executor.Pool == p.config.CloudPool {
+			p.cloudExecutorIds[executor.Id] = true
+		}
+	}
+	if saturated && p.saturatedSince == nil {
+		log.Infof("on-prem pools %v saturated; queues %v overflow into pool %s after %s", p.config.OnPremPools, p.config.Queues, p.config.CloudPool, p.config.SaturationDuration)
+		p.saturatedSince = &now
+	} else if !saturated && p.saturatedSince != nil {
+		log.Infof("on-prem pools %v no longer saturated; queues %v no longer overflow into pool %s", p.config.OnPremPools, p.config.Queues, p.config.CloudPool)
+		p.saturatedSince = nil
+	}
+}
+
+// isPoolSaturated returns true if the fraction of capacity of any resource allocated is at least threshold.
+func isPoolSaturated(capacity schedulerobjects.ResourceList, allocated schedulerobjects.ResourceList, threshold float64) bool {
+	for t, total := range capacity.Resources {
+		if total.Sign() <= 0 {
+			continue
+		}
+		q := allocated.Get(t)
+		if float64(q.MilliValue())/float64(total.MilliValue()) >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// CloudPool returns the pool jobs overflow into.
+func (p *CloudBurstPolicy) CloudPool() string {
+	return p.config.CloudPool
+}
+
+// OverflowQueues returns the queues whose queued jobs may currently be scheduled onto the cloud pool without targeting it.
+// The returned map is empty, but not nil, while no queue may overflow.
+func (p *CloudBurstPolicy) OverflowQueues() map[string]bool {
+	if !p.Status().Overflowing {
+		return map[string]bool{}
+	}
+	return maps.Clone(p.queues)
+}
+
+// Status returns the current state of the policy.
+func (p *CloudBurstPolicy) Status() CloudBurstStatus {
+	now := p.clock.Now()
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	status := CloudBurstStatus{
+		CloudPool:         p.config.CloudPool,
+		Queues:            slices.Clone(p.config.Queues),
+		MonthStart:        p.monthStart,
+		Spend:             p.spend(),
+		MonthlySpendLimit: p.config.MonthlySpendLimit,
+		SpendUpdated:      p.spendUpdated,
+	}
+	if p.saturatedSince != nil {
+		saturatedSince := *p.saturatedSince
+		status.SaturatedSince = &saturatedSince
+	}
+	status.Halted = p.spendUpdated.IsZero() || status.Spend >= p.config.MonthlySpendLimit
+	status.Overflowing = !status.Halted && status.SaturatedSince != nil && now.Sub(*status.SaturatedSince) >= p.config.SaturationDuration
+	return status
+}
+
+// spend returns the cost of the resource-hours consumed on the executors of the cloud pool. Must be called with p.mu held.
+func (p *CloudBurstPolicy) spend() float64 {
+	rv := 0.0
+	for executorId := range p.cloudExecutorIds {
+		for t, hours := range p.resourceHoursByCluster[executorId] {
+			rv += hours * p.config.CostPerResourceHour[t]
+		}
+	}
+	return rv
+}
+
+// CloudBurstHttpHandler serves the status of the cloud burst policy as json.
+type CloudBurstHttpHandler struct {
+	policy *CloudBurstPolicy
+}
+
+func NewCloudBurstHttpHandler(policy *CloudBurstPolicy) *CloudBurstHttpHandler {
+	return &CloudBurstHttpHandler{policy: policy}
+}
+
+func (h *CloudBurstHttpHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.policy.Status()); err != nil {
+		log.WithError(err).Error("failed to write cloud burst response")
+	}
+}
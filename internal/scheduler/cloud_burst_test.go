@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestCloudBurstPolicy(t *testing.T) {
+	source := &fakeClusterResourceUsageSource{resourceHoursByCluster: map[string]map[string]float64{
+		"cloud-executor":  {"cpu": 1000, "nvidia.com/gpu": 10},
+		"onprem-executor": {"cpu": 100000},
+	}}
+	policy, err := NewCloudBurstPolicy(
+		schedulerconfig.CloudBurstConfig{
+			CloudPool:           "cloud",
+			OnPremPools:         []string{"onprem"},
+			Queues:              []string{"A"},
+			SaturationThreshold: 0.9,
+			SaturationDuration:  10 * time.Minute,
+			CostPerResourceHour: map[string]float64{"cpu": 0.1, "nvidia.com/gpu": 2},
+			MonthlySpendLimit:   200,
+		},
+		source,
+	)
+	require.NoError(t, err)
+	now := time.Date(2023, 11, 15, 6, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFakeClock(now)
+	policy.clock = fakeClock
+
+	executors := []*schedulerobjects.Executor{
+		{Id: "cloud-executor", Pool: "cloud"},
+		{Id: "onprem-executor", Pool: "onprem"},
+	}
+	capacity := schedulerobjects.QuantityByTAndResourceType[string]{
+		"onprem": cpuResourceList("100"),
+		"cloud":  cpuResourceList("100"),
+	}
+	saturated := map[string]map[string]schedulerobjects.QuantityByTAndResourceType[string]{
+		"onprem": {
+			"A": {testfixtures.PriorityClass0: cpuResourceList("50")},
+			"B": {testfixtures.PriorityClass0: cpuResourceList("45")},
+		},
+	}
+	unsaturated := map[string]map[string]schedulerobjects.QuantityByTAndResourceType[string]{
+		"onprem": {"A": {testfixtures.PriorityClass0: cpuResourceList("50")}},
+	}
+
+	// Nothing overflows until spend has been computed.
+	policy.Observe(executors, capacity, saturated)
+	fakeClock.Step(time.Hour)
+	policy.Observe(executors, capacity, saturated)
+	assert.Empty(t, policy.OverflowQueues())
+	assert.True(t, policy.Status().Halted)
+
+	// Only usage of cloud executors counts towards spend.
+	require.NoError(t, policy.Refresh(armadacontext.Background()))
+	status := policy.Status()
+	assert.Equal(t, time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), status.MonthStart)
+	assert.InDelta(t, 120, status.Spend, 1e-6)
+	assert.False(t, status.Halted)
+	assert.True(t, status.Overflowing)
+	assert.Equal(t, map[string]bool{"A": true}, policy.OverflowQueues())
+
+	// Overflow stops as soon as the on-prem pools are no longer saturated,
+	// and only restarts once they've been saturated for long enough again.
+	policy.Observe(executors, capacity, unsaturated)
+	assert.Empty(t, policy.OverflowQueues())
+	policy.Observe(executors, capacity, saturated)
+	fakeClock.Step(5 * time.Minute)
+	assert.Empty(t, policy.OverflowQueues())
+	fakeClock.Step(5 * time.Minute)
+	assert.Equal(t, map[string]bool{"A": true}, policy.OverflowQueues())
+
+	// Overflow is halted once the spend limit is reached.
+	source.resourceHoursByCluster["cloud-executor"]["nvidia.com/gpu"] = 50
+	require.NoError(t, policy.Refresh(armadacontext.Background()))
+	assert.True(t, policy.Status().Halted)
+	assert.Empty(t, policy.OverflowQueues())
+
+	// Previously computed spend is kept if it can't be refreshed.
+	source.err = assert.AnError
+	assert.Error(t, policy.Refresh(armadacontext.Background()))
+	assert.InDelta(t, 200, policy.Status().Spend, 1e-6)
+}
+
+func TestNewCloudBurstPolicy_InvalidConfig(t *testing.T) {
+	valid := schedulerconfig.CloudBurstConfig{
+		CloudPool:           "cloud",
+		OnPremPools:         []string{"onprem"},
+		SaturationThreshold: 0.9,
+	}
+	_, err := NewCloudBurstPolicy(valid, &fakeClusterResourceUsageSource{})
+	require.NoError(t, err)
+
+	tests := map[string]func(config *schedulerconfig.CloudBurstConfig){
+		"missing cloud pool":        func(config *schedulerconfig.CloudBurstConfig) { config.CloudPool = "" },
+		"missing on-prem pools":     func(config *schedulerconfig.CloudBurstConfig) { config.OnPremPools = nil },
+		"cloud pool is on-prem":     func(config *schedulerconfig.CloudBurstConfig) { config.OnPremPools = []string{"cloud"} },
+		"zero saturation threshold": func(config *schedulerconfig.CloudBurstConfig) { config.SaturationThreshold = 0 },
+		"saturation threshold > 1":  func(config *schedulerconfig.CloudBurstConfig) { config.SaturationThreshold = 1.5 },
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := valid
+			modify(&config)
+			_, err := NewCloudBurstPolicy(config, &fakeClusterResourceUsageSource{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSchedulerJobRepositoryAdapter_OverflowQueues(t *testing.T) {
+	overflowingJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).WithQueued(true)
+	otherQueueJob := testfixtures.Test1Cpu4GiJob("B", testfixtures.PriorityClass0).WithQueued(true)
+	targetingJob := testfixtures.WithAnnotationsJobs(
+		map[string]string{configuration.PoolAnnotation: "cloud"},
+		[]*jobdb.Job{testfixtures.Test1Cpu4GiJob("B", testfixtures.PriorityClass0).WithQueued(true)},
+	)[0]
+	txn := testfixtures.NewJobDb().WriteTxn()
+	require.NoError(t, txn.Upsert([]*jobdb.Job{overflowingJob, otherQueueJob, targetingJob}))
+
+	repo := NewSchedulerJobRepositoryAdapter(txn)
+	repo.overflowQueues = map[string]bool{"A": true}
+	jobIds, err := repo.GetQueueJobIds("A")
+	require.NoError(t, err)
+	assert.Equal(t, []string{overflowingJob.Id()}, jobIds)
+	jobIds, err = repo.GetQueueJobIds("B")
+	require.NoError(t, err)
+	assert.Equal(t, []string{targetingJob.Id()}, jobIds)
+}
+
+func cpuResourceList(quantity string) schedulerobjects.ResourceList {
+	return schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse(quantity)}}
+}
+
+type fakeClusterResourceUsageSource struct {
+	resourceHoursByCluster map[string]map[string]float64
+	err                    error
+}
+
+func (s *fakeClusterResourceUsageSource) GetResourceHoursByCluster(_ *armadacontext.Context, _ time.Time, _ time.Time) (map[string]map[string]float64, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.resourceHoursByCluster, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/armadaproject/armada/internal/armada/configuration"
 	armadamaps "github.com/armadaproject/armada/internal/common/maps"
 	armadaslices "github.com/armadaproject/armada/internal/common/slices"
+	"github.com/armadaproject/armada/internal/common/types"
 	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
 	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
 	"github.com/armadaproject/armada/internal/scheduler/interfaces"
@@ -20,6 +21,8 @@ import (
 type SchedulerResult struct {
 	// Whether the scheduler failed to create a result for some reason
 	EmptyResult bool
+	// Whether the scheduling round was skipped since its inputs were unchanged from the previous round.
+	InputsUnchanged bool
 	// Running jobs that should be preempted.
 	PreemptedJobs []interfaces.LegacySchedulerJob
 	// Queued jobs that should be scheduled.
@@ -27,12 +30,20 @@ type SchedulerResult struct {
 	// Queued jobs that could not be scheduled.
 	// This is used to fail jobs that could not schedule above `minimumGangCardinality`.
 	FailedJobs []interfaces.LegacySchedulerJob
+	// For failed jobs that didn't fail for not meeting the minimum cardinality of their gang,
+	// maps the job id to a description of why the job failed, e.g., since its gang wasn't scheduled within its deadline.
+	FailureReasonByJobId map[string]string
 	// For each preempted job, maps the job id to the id of the node on which the job was running.
 	// For each scheduled job, maps the job id to the id of the node on which the job should be scheduled.
 	NodeIdByJobId map[string]string
+	// For each preempted job, maps the job id to a description of why it was preempted.
+	PreemptionByJobId map[string]*Preemption
 	// The Scheduling Context. Being passed up for metrics decisions made in scheduler.go and scheduler_metrics.go.
 	// Passing a pointer as the structure is enormous
 	SchedulingContexts []*schedulercontext.SchedulingContext
+	// If InputsUnchanged, the scheduling contexts of the most recent round that wasn't skipped,
+	// whose decisions are unchanged; republished as a heartbeat, e.g., to keep reporting fair shares.
+	UnchangedSchedulingContexts []*schedulercontext.SchedulingContext
 }
 
 func NewSchedulerResultForTest[S ~[]T, T interfaces.LegacySchedulerJob](
@@ -186,3 +197,113 @@ func GangIdAndCardinalityFromAnnotations(annotations map[string]string) (string,
 		return gangId, gangCardinality, gangMinimumCardinality, true, nil
 	}
 }
+
+// GangRoleAndCardinalityFromAnnotations returns a tuple (gangRole, gangRoleCardinality, gangRoleMinimumCardinality, hasGangRole, error).
+func GangRoleAndCardinalityFromAnnotations(annotations map[string]string) (string, int, int, bool, error) {
+	if annotations == nil {
+		return "", 0, 0, false, nil
+	}
+	gangRole, ok := annotations[configuration.GangRoleAnnotation]
+	if !ok {
+		return "", 0, 0, false, nil
+	}
+	if gangRole == "" {
+		return "", 0, 0, false, errors.Errorf("empty gang role")
+	}
+	gangRoleCardinalityString, ok := annotations[configuration.GangRoleCardinalityAnnotation]
+	if !ok {
+		return "", 0, 0, false, errors.Errorf("missing annotation %s", configuration.GangRoleCardinalityAnnotation)
+	}
+	gangRoleCardinality, err := strconv.Atoi(gangRoleCardinalityString)
+	if err != nil {
+		return "", 0, 0, false, errors.WithStack(err)
+	}
+	if gangRoleCardinality <= 0 {
+		return "", 0, 0, false, errors.Errorf("gang role cardinality is non-positive %d", gangRoleCardinality)
+	}
+	gangRoleMinimumCardinalityString, ok := annotations[configuration.GangRoleMinimumCardinalityAnnotation]
+	if !ok {
+		return gangRole, gangRoleCardinality, gangRoleCardinality, true, nil
+	}
+	gangRoleMinimumCardinality, err := strconv.Atoi(gangRoleMinimumCardinalityString)
+	if err != nil {
+		return "", 0, 0, false, errors.WithStack(err)
+	}
+	if gangRoleMinimumCardinality < 0 {
+		return "", 0, 0, false, errors.Errorf("gang role minimum cardinality is negative %d", gangRoleMinimumCardinality)
+	}
+	if gangRoleMinimumCardinality > gangRoleCardinality {
+		return "", 0, 0, false, errors.Errorf("gang role minimum cardinality %d cannot be greater than gang role cardinality %d", gangRoleMinimumCardinality, gangRoleCardinality)
+	}
+	return gangRole, gangRoleCardinality, gangRoleMinimumCardinality, true, nil
+}
+
+// GangFailureDomainsFromAnnotations returns a tuple (failureDomainLabel, minFailureDomains, error),
+// where minFailureDomains is zero if the gang isn't required to span multiple failure domains.
+func GangFailureDomainsFromAnnotations(annotations map[string]string) (string, int, error) {
+	failureDomainLabel := annotations[configuration.GangFailureDomainLabelAnnotation]
+	minFailureDomainsString, ok := annotations[configuration.GangMinFailureDomainsAnnotation]
+	if !ok {
+		if failureDomainLabel != "" {
+			return "", 0, errors.Errorf("missing annotation %s", configuration.GangMinFailureDomainsAnnotation)
+		}
+		return "", 0, nil
+	}
+	if failureDomainLabel == "" {
+		return "", 0, errors.Errorf("missing annotation %s", configuration.GangFailureDomainLabelAnnotation)
+	}
+	minFailureDomains, err := strconv.Atoi(minFailureDomainsString)
+	if err != nil {
+		return "", 0, errors.WithStack(err)
+	}
+	if minFailureDomains <= 0 {
+		return "", 0, errors.Errorf("gang minimum failure domains is non-positive %d", minFailureDomains)
+	}
+	return failureDomainLabel, minFailureDomains, nil
+}
+
+// GangNodeUniformityLabelsFromAnnotations returns the node uniformity labels of a gang, ordered from coarsest to finest,
+// as provided via GangNodeUniformityLabelsAnnotation, or nil if that annotation isn't set.
+func GangNodeUniformityLabelsFromAnnotations(annotations map[string]string) ([]string, error) {
+	value, ok := annotations[configuration.GangNodeUniformityLabelsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	if annotations[configuration.GangNodeUniformityLabelAnnotation] != "" {
+		return nil, errors.Errorf(
+			"annotations %s and %s can't both be set",
+			configuration.GangNodeUniformityLabelAnnotation, configuration.GangNodeUniformityLabelsAnnotation,
+		)
+	}
+	labels := schedulercontext.NodeUniformityLabelsFromAnnotation(value)
+	if len(labels) == 0 {
+		return nil, errors.Errorf("no labels in annotation %s", configuration.GangNodeUniformityLabelsAnnotation)
+	}
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		if label == "" {
+			return nil, errors.Errorf("empty label in annotation %s", configuration.GangNodeUniformityLabelsAnnotation)
+		}
+		if seen[label] {
+			return nil, errors.Errorf("duplicate label %s in annotation %s", label, configuration.GangNodeUniformityLabelsAnnotation)
+		}
+		seen[label] = true
+	}
+	return labels, nil
+}
+
+// jobSchedulingContextsFromJobs returns a job scheduling context for each job,
+// with gang minimum cardinalities and roles populated from job annotations.
+func jobSchedulingContextsFromJobs[J interfaces.LegacySchedulerJob](priorityClasses map[string]types.PriorityClass, jobs []J) []*schedulercontext.JobSchedulingContext {
+	jctxs := schedulercontext.JobSchedulingContextsFromJobs(priorityClasses, jobs, GangIdAndCardinalityFromAnnotations)
+	for _, jctx := range jctxs {
+		gangRole, _, gangRoleMinCardinality, hasGangRole, err := GangRoleAndCardinalityFromAnnotations(jctx.Job.GetAnnotations())
+		if err != nil || !hasGangRole {
+			// Jobs with invalid roles are scheduled as if they had no role.
+			continue
+		}
+		jctx.GangRole = gangRole
+		jctx.GangRoleMinCardinality = gangRoleMinCardinality
+	}
+	return jctxs
+}
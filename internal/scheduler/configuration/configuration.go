@@ -26,6 +26,8 @@ type Configuration struct {
 	Redis config.RedisConfig
 	// General Pulsar configuration
 	Pulsar configuration.PulsarConfig
+	// Envelope encryption of job specs stored in Postgres; must use the same keys as the scheduler ingester.
+	JobSpecEncryption config.EncryptionConfig
 	// Configuration controlling leader election
 	Leader LeaderConfig
 	// Configuration controlling metrics
@@ -35,6 +37,8 @@ type Configuration struct {
 	Auth       authconfig.AuthConfig
 	Grpc       grpcconfig.GrpcConfig
 	Http       HttpConfig
+	// Configuration controlling the dependency checks reported by the health status and readiness endpoints
+	Health HealthConfig
 	// If non-nil, net/http/pprof endpoints are exposed on localhost on this port.
 	PprofPort *uint16
 	// Maximum number of strings that should be cached at any one time
@@ -49,10 +53,195 @@ type Configuration struct {
 	MaxSchedulingDuration time.Duration `validate:"required"`
 	// How long after a heartbeat an executor will be considered lost
 	ExecutorTimeout time.Duration `validate:"required"`
+	// If an executor reports in without acknowledging a run leased to it for this amount of time,
+	// the run is returned and the job requeued. If zero, unacknowledged runs aren't returned.
+	// Should exceed the time executors may take to request the runs leased to them,
+	// e.g., while waiting for pods of previously leased runs to be created.
+	LeaseAcknowledgementTimeout time.Duration
+	// Maximum amount of time the in-flight cycle is given to complete once the scheduler is shutting down, e.g., when redeployed.
+	// Within this time, the leader also publishes any outstanding updates and hands over its in-memory state to the next leader
+	// before releasing leadership. If zero, the in-flight cycle is aborted immediately and no state is handed over.
+	// Should be less than the time the scheduler is given to terminate, e.g., its termination grace period.
+	ShutdownGracePeriod time.Duration
 	// Maximum number of rows to fetch in a given query
 	DatabaseFetchSize int `validate:"required"`
 	// Timeout to use when sending messages to pulsar
 	PulsarSendTimeout time.Duration `validate:"required"`
+	// Configuration controlling operator alerts
+	Alerting AlertingConfig
+	// Per-queue GPU-hour budgets
+	Budgets BudgetsConfig
+	// Overflow of designated queues into a cloud pool while on-prem pools are saturated
+	CloudBurst CloudBurstConfig
+	// Configuration controlling persistence of the reports of scheduling rounds
+	SchedulingReports SchedulingReportsConfig
+	// Synthetic canary jobs submitted periodically to measure the end-to-end latency of scheduling and running jobs
+	Canary CanaryConfig
+	// Short-horizon forecasting of the capacity of each pool, used to hold large gangs while capacity they'd fit onto is imminent
+	CapacityForecast CapacityForecastConfig
+}
+
+// CanaryConfig configures synthetic canary jobs, submitted periodically by the leader to each configured pool and priority class
+// to measure the end-to-end latency of scheduling and running a job. Disabled if Probes is empty.
+type CanaryConfig struct {
+	// Connection details of the Armada api canary jobs are submitted via.
+	ArmadaApi client.ApiConnectionDetails
+	// How often a canary job is submitted for each probe. Probes with a canary job still in flight are skipped.
+	Interval time.Duration
+	Probes   []CanaryProbe
+}
+
+// CanaryProbe describes the canary jobs submitted to one pool at one priority class.
+type CanaryProbe struct {
+	// Queue canary jobs are submitted to; must already exist.
+	Queue string
+	// Pool canary jobs target via PoolAnnotation.
+	Pool              string
+	PriorityClassName string
+	Namespace         string
+	Image             string
+	Command           []string
+	// Resources requested by canary jobs, e.g., {"cpu": "100m", "memory": "64Mi"}.
+	Resources map[string]string
+	// A canary alert is sent if a canary job takes longer than this from being submitted to succeeding. Not checked if zero.
+	MaxLatency time.Duration
+	// Canary jobs that haven't succeeded this long after being submitted are cancelled and considered failed.
+	Timeout time.Duration
+}
+
+// SchedulingReportsConfig controls persistence of the reports of scheduling rounds, such that reports survive scheduler restarts.
+type SchedulingReportsConfig struct {
+	// If true, the reports of each round, and of each queue considered in it, are persisted to postgres,
+	// and the reporting APIs serve the most recent persisted report of executors the scheduler has no report of in memory.
+	Persist bool
+	// Reports of rounds started longer ago than this are deleted.
+	Retention time.Duration
+	// How often reports older than Retention are deleted.
+	PruneInterval time.Duration
+	// Verbosity reports are persisted at.
+	Verbosity int32
+	// Maximum number of rounds waiting to be persisted. Reports of further rounds are dropped until the backlog clears.
+	MaxPendingRounds int
+}
+
+// AlertingConfig controls which operator alerts are sent and where they're sent to.
+type AlertingConfig struct {
+	// Minimum time between two alerts of the same type for the same pool and subject (e.g., queue or executor).
+	MinAlertInterval time.Duration
+	// Maximum number of alerts sent per minute to each destination.
+	MaxAlertsPerMinute int
+	// If non-empty, alerts for a queue include a deep link to Lookout, created by substituting the queue name into this format string,
+	// e.g., "https://lookout.example.com/?queue=%s".
+	LookoutQueueUrlFormat string
+	// Number of consecutive scheduling rounds a queue must be starved for before an alert is sent.
+	StarvationRounds int
+	// A fair share breach alert is sent if a queue's share of a pool exceeds its fair share by this factor
+	// while other queues are unable to schedule jobs. Disabled if zero.
+	FairShareBreachFactor float64
+	// A queue depth alert is sent if the number of queued jobs of a queue reaches this threshold. Disabled if zero.
+	QueueDepthThreshold int
+	// Controls which differences between consecutive scheduling rounds are flagged as regressions.
+	RoundRegression RoundRegressionConfig
+	Destinations    []AlertDestination
+}
+
+// RoundRegressionConfig controls round regression alerts, sent if a scheduling round on an executor group
+// differs from the previous round on that group in a way likely caused by a configuration or code change.
+type RoundRegressionConfig struct {
+	// An alert is sent if the number of jobs scheduled drops by at least this fraction, e.g., 0.9, from the previous round
+	// while jobs remain unschedulable. Disabled if zero.
+	ScheduledJobsDropFraction float64
+	// An alert is sent if the number of jobs unschedulable for any one reason grows by at least this factor, e.g., 10,
+	// from the previous round. Disabled if zero.
+	UnschedulableReasonSpikeFactor float64
+	// Drops and spikes are only flagged if at least this many jobs were scheduled in the previous round,
+	// or were unschedulable for the reason in this round, respectively, to avoid alerting on small rounds.
+	MinJobs int
+	// If true, an alert is sent if, of two queues unable to schedule all their jobs, the queue with the larger fair share
+	// has the smaller share of the pool, where this was not the case in the previous round.
+	FairShareInversion bool
+}
+
+type AlertDestination struct {
+	// Pools for which alerts are sent to this destination. All pools if empty.
+	// Alerts not specific to any pool, e.g., queue depth alerts, are sent to all destinations.
+	Pools []string
+	// Alert types sent to this destination; one of starvation, staleClusterSnapshot, roundDeadlineExceeded, fairShareBreach, clusterDrained, queueDepth, roundRegression, and canary.
+	// All alert types if empty.
+	Alerts          []string
+	SlackWebhookUrl string
+	TeamsWebhookUrl string
+}
+
+// BudgetsConfig configures per-queue GPU-hour budgets.
+// Usage is computed from the run history recorded by Lookout; budgets are not enforced if ResourceUsageUrl is empty.
+type BudgetsConfig struct {
+	// Url of the Lookout resource usage api, e.g., http://lookoutv2:10000/api/v1/resourceUsage.
+	ResourceUsageUrl string
+	// How often usage is fetched from Lookout.
+	RefreshInterval time.Duration
+	Timeout         time.Duration
+	// Priority class queued jobs are scheduled at once the budget of their queue is exhausted, if the budget action is demote.
+	// Should be a preemptible priority class with low priority.
+	ScavengerPriorityClass string
+	Queues                 []QueueBudget
+}
+
+type QueueBudget struct {
+	Queue    string
+	GpuHours float64
+	// Period after which the budget resets; one of day, week (starting on Monday), or month. Periods start at midnight UTC.
+	Window string
+	// What happens once the budget is exhausted until the end of the window; either demote or block.
+	// Demote schedules new jobs of the queue at the scavenger priority class, and block prevents new jobs from being scheduled.
+	// Jobs already running are unaffected in either case.
+	Action string
+}
+
+// CloudBurstConfig configures overflow of queued jobs of designated queues into a cloud pool
+// once all on-prem pools have been saturated for some time, subject to a monthly spend limit.
+// Only jobs not targeting a pool via PoolAnnotation are subject to the policy. Disabled if CloudPool is empty.
+type CloudBurstConfig struct {
+	// Pool that jobs overflow into. Other queued jobs that don't target this pool aren't scheduled onto it.
+	CloudPool string
+	// Pools whose saturation triggers overflow.
+	OnPremPools []string
+	// Queues whose jobs may overflow into CloudPool.
+	Queues []string
+	// A pool is saturated if the fraction of its capacity of any resource allocated to jobs is at least this, e.g., 0.95.
+	SaturationThreshold float64
+	// Overflow starts once all of OnPremPools have been saturated for this long, and stops as soon as any of them isn't.
+	SaturationDuration time.Duration
+	// Cost per hour of each unit of each resource in CloudPool, e.g., {"cpu": 0.04, "nvidia.com/gpu": 2.5}.
+	CostPerResourceHour map[string]float64
+	// Overflow is halted once the spend in CloudPool since the start of the calendar month (UTC) reaches this amount.
+	// Jobs already running are unaffected.
+	MonthlySpendLimit float64
+	// Url of the Lookout resource usage api, from which spend is computed, e.g., http://lookoutv2:10000/api/v1/resourceUsage.
+	ResourceUsageUrl string
+	// How often spend is recomputed.
+	RefreshInterval time.Duration
+	Timeout         time.Duration
+}
+
+// CapacityForecastConfig configures forecasting of the capacity of each pool a short horizon ahead,
+// from the trend of nodes joining and leaving the pool and from node maintenance schedules.
+// Gangs past their scheduling deadline are held, rather than failed, while capacity they'd fit onto is forecast.
+// Disabled if Horizon is zero.
+type CapacityForecastConfig struct {
+	// How far ahead capacity is forecast.
+	Horizon time.Duration
+	// Nodes joining and leaving a pool over this window are extrapolated over Horizon.
+	TrendWindow time.Duration
+	// Labels of nodes whose values are the times, in seconds since the unix epoch, at which the node is scheduled to enter
+	// and leave maintenance, respectively. Cordoned nodes leaving maintenance within Horizon count towards forecast capacity,
+	// whereas schedulable nodes entering maintenance within Horizon don't. Executors must track these labels.
+	MaintenanceStartLabel string
+	MaintenanceEndLabel   string
+	// Only gangs with at least this many jobs are held.
+	MinGangCardinality uint
+	// Gangs are held for at most this long past their scheduling deadline.
+	MaxHoldDuration time.Duration
 }
 
 type LeaderConfig struct {
@@ -75,6 +264,17 @@ type LeaderConfig struct {
 	LeaderConnection client.ApiConnectionDetails
 }
 
+// HealthConfig controls the checks backing the /health/status and /ready endpoints.
+// The /health endpoint only reports whether the scheduler has started, and is suitable for liveness probes.
+type HealthConfig struct {
+	// Maximum time each dependency check may take before the dependency is considered unavailable.
+	CheckTimeout time.Duration
+	// The executor api is considered unavailable if no executor has reported in for this long. Not checked if zero.
+	MaxExecutorUpdateAge time.Duration
+	// The scheduler isn't ready if it hasn't completed a cycle successfully for this long. Not checked if zero.
+	MaxCycleAge time.Duration
+}
+
 type HttpConfig struct {
 	Port int `validate:"required"`
 }
@@ -1,10 +1,11 @@
 package constraints
 
 import (
-	"fmt"
 	"math"
 
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/armadaproject/armada/internal/armada/configuration"
@@ -31,24 +32,36 @@ const (
 	// This means the gang can not be scheduled without first increasing the burst size.
 	GangExceedsGlobalBurstSizeUnschedulableReason = "gang cardinality too large: exceeds global max burst size"
 	GangExceedsQueueBurstSizeUnschedulableReason  = "gang cardinality too large: exceeds queue max burst size"
+
+	// Indicates that the job set of a gang already has the maximum number of running jobs it allows.
+	JobSetMaxRunningJobsExceededUnschedulableReason = "maximum number of running jobs for this job set exceeded"
+
+	// Indicates that a gang was paused at submission for exceeding the queued jobs limit of its queue
+	// and the queue still has other queued jobs.
+	JobPausedUnschedulableReason = "job is paused until the other queued jobs of its queue have been scheduled"
+
+	// Indicates that new jobs of a queue may not be scheduled, e.g., because the queue has exhausted its budget.
+	QueueBlockedUnschedulableReason = "queue is blocked from scheduling new jobs"
 )
 
 // IsTerminalUnschedulableReason returns true if reason indicates
 // it's not possible to schedule any more jobs in this round.
-func IsTerminalUnschedulableReason(reason string) bool {
-	if reason == MaximumResourcesScheduledUnschedulableReason {
-		return true
-	}
-	if reason == GlobalRateLimitExceededUnschedulableReason {
-		return true
-	}
-	return false
+func IsTerminalUnschedulableReason(reason *schedulerobjects.UnschedulableReason) bool {
+	code := reason.GetCode()
+	return code == schedulerobjects.UnschedulableReasonCodeMaximumResourcesScheduled ||
+		code == schedulerobjects.UnschedulableReasonCodeGlobalRateLimitExceeded
 }
 
 // IsTerminalQueueUnschedulableReason returns true if reason indicates
 // it's not possible to schedule any more jobs from this queue in this round.
-func IsTerminalQueueUnschedulableReason(reason string) bool {
-	return reason == QueueRateLimitExceededUnschedulableReason
+func IsTerminalQueueUnschedulableReason(reason *schedulerobjects.UnschedulableReason) bool {
+	code := reason.GetCode()
+	return code == schedulerobjects.UnschedulableReasonCodeQueueRateLimitExceeded ||
+		code == schedulerobjects.UnschedulableReasonCodeQueueBlocked
+}
+
+func newUnschedulableReason(code schedulerobjects.UnschedulableReasonCode, message string) *schedulerobjects.UnschedulableReason {
+	return &schedulerobjects.UnschedulableReason{Code: code, Message: message}
 }
 
 // SchedulingConstraints contains scheduling constraints, e.g., per-queue resource limits.
@@ -58,10 +71,14 @@ type SchedulingConstraints struct {
 	// Jobs leased to this executor must be at least this large.
 	// Used, e.g., to avoid scheduling CPU-only jobs onto clusters with GPUs.
 	MinimumJobSize schedulerobjects.ResourceList
+	// Jobs scheduled in this pool must be at least this large.
+	PoolMinimumJobSize schedulerobjects.ResourceList
 	// Scheduling constraints for specific priority classes.
 	PriorityClassSchedulingConstraintsByPriorityClassName map[string]PriorityClassSchedulingConstraints
 	// Limits total resources scheduled per invocation.
 	MaximumResourcesToSchedule schedulerobjects.ResourceList
+	// No new jobs are scheduled from these queues; jobs already running may still be rescheduled if evicted.
+	BlockedQueues map[string]bool
 }
 
 // PriorityClassSchedulingConstraints contains scheduling constraints that apply to jobs of a specific priority class.
@@ -99,6 +116,7 @@ func SchedulingConstraintsFromSchedulingConfig(
 	return SchedulingConstraints{
 		MaxQueueLookback:           config.MaxQueueLookback,
 		MinimumJobSize:             minimumJobSize,
+		PoolMinimumJobSize:         schedulerobjects.ResourceList{Resources: config.MinimumJobSizeByPool[pool]},
 		MaximumResourcesToSchedule: absoluteFromRelativeLimits(totalResources, maximumResourceFractionToSchedule),
 		PriorityClassSchedulingConstraintsByPriorityClassName: priorityClassSchedulingConstraintsByPriorityClassName,
 	}
@@ -120,67 +138,158 @@ func ScaleQuantity(q resource.Quantity, f float64) resource.Quantity {
 	return q
 }
 
-func (constraints *SchedulingConstraints) CheckRoundConstraints(sctx *schedulercontext.SchedulingContext, queue string) (bool, string, error) {
+func (constraints *SchedulingConstraints) CheckRoundConstraints(sctx *schedulercontext.SchedulingContext, queue string) (bool, *schedulerobjects.UnschedulableReason, error) {
 	// MaximumResourcesToSchedule check.
 	if !sctx.ScheduledResources.IsStrictlyLessOrEqual(constraints.MaximumResourcesToSchedule) {
-		return false, MaximumResourcesScheduledUnschedulableReason, nil
+		return false, newUnschedulableReason(
+			schedulerobjects.UnschedulableReasonCodeMaximumResourcesScheduled,
+			MaximumResourcesScheduledUnschedulableReason,
+		).WithResources(exceededResources(sctx.ScheduledResources, constraints.MaximumResourcesToSchedule)...), nil
 	}
-	return true, "", nil
+	return true, nil, nil
 }
 
 func (constraints *SchedulingConstraints) CheckConstraints(
 	sctx *schedulercontext.SchedulingContext,
 	gctx *schedulercontext.GangSchedulingContext,
-) (bool, string, error) {
-	qctx := sctx.QueueSchedulingContexts[gctx.Queue]
-	if qctx == nil {
-		return false, "", errors.Errorf("no QueueSchedulingContext for queue %s", gctx.Queue)
+) (bool, *schedulerobjects.UnschedulableReason, error) {
+	if sctx.QueueSchedulingContexts[gctx.Queue] == nil {
+		return false, nil, errors.Errorf("no QueueSchedulingContext for queue %s", gctx.Queue)
+	}
+	// Each job counts against the per-queue constraints of the queue it's submitted to,
+	// which for cross-queue gangs may differ from the queue owning the gang.
+	queues, jctxsByQueue := gctx.JobSchedulingContextsByQueue()
+	for _, queue := range queues {
+		if sctx.QueueSchedulingContexts[queue] == nil {
+			return false, nil, errors.Errorf("no QueueSchedulingContext for queue %s", queue)
+		}
+		if constraints.BlockedQueues[queue] {
+			return false, newUnschedulableReason(schedulerobjects.UnschedulableReasonCodeQueueBlocked, QueueBlockedUnschedulableReason), nil
+		}
 	}
 
-	// Check that the job is large enough for this executor.
+	// Check that the job is large enough for this executor and pool.
 	if ok, unschedulableReason := RequestsAreLargeEnough(gctx.TotalResourceRequests, constraints.MinimumJobSize); !ok {
 		return false, unschedulableReason, nil
 	}
+	if ok, unschedulableReason := RequestsMeetPoolMinimum(gctx.TotalResourceRequests, constraints.PoolMinimumJobSize); !ok {
+		return false, unschedulableReason, nil
+	}
 
 	// Global rate limiter check.
 	tokens := sctx.Limiter.TokensAt(sctx.Started)
 	if tokens <= 0 {
-		return false, GlobalRateLimitExceededUnschedulableReason, nil
+		return false, newUnschedulableReason(schedulerobjects.UnschedulableReasonCodeGlobalRateLimitExceeded, GlobalRateLimitExceededUnschedulableReason), nil
 	}
 	if sctx.Limiter.Burst() < gctx.Cardinality() {
-		return false, GangExceedsGlobalBurstSizeUnschedulableReason, nil
+		return false, newUnschedulableReason(schedulerobjects.UnschedulableReasonCodeGangExceedsGlobalBurstSize, GangExceedsGlobalBurstSizeUnschedulableReason), nil
 	}
 	if tokens < float64(gctx.Cardinality()) {
-		return false, GlobalRateLimitExceededByGangUnschedulableReason, nil
+		return false, newUnschedulableReason(schedulerobjects.UnschedulableReasonCodeGlobalRateLimitExceededByGang, GlobalRateLimitExceededByGangUnschedulableReason), nil
 	}
 
+	for _, queue := range queues {
+		if ok, unschedulableReason := constraints.checkQueueConstraints(sctx, sctx.QueueSchedulingContexts[queue], gctx, jctxsByQueue[queue]); !ok {
+			return false, unschedulableReason, nil
+		}
+	}
+	return true, nil, nil
+}
+
+// checkQueueConstraints checks the per-queue constraints of the queue of qctx for jctxs, i.e., the jobs of gctx submitted to that queue.
+func (constraints *SchedulingConstraints) checkQueueConstraints(
+	sctx *schedulercontext.SchedulingContext,
+	qctx *schedulercontext.QueueSchedulingContext,
+	gctx *schedulercontext.GangSchedulingContext,
+	jctxs []*schedulercontext.JobSchedulingContext,
+) (bool, *schedulerobjects.UnschedulableReason) {
 	// Per-queue rate limiter check.
-	tokens = qctx.Limiter.TokensAt(sctx.Started)
+	tokens := qctx.Limiter.TokensAt(sctx.Started)
 	if tokens <= 0 {
-		return false, QueueRateLimitExceededUnschedulableReason, nil
+		return false, newUnschedulableReason(schedulerobjects.UnschedulableReasonCodeQueueRateLimitExceeded, QueueRateLimitExceededUnschedulableReason)
 	}
-	if qctx.Limiter.Burst() < gctx.Cardinality() {
-		return false, GangExceedsQueueBurstSizeUnschedulableReason, nil
+	if qctx.Limiter.Burst() < len(jctxs) {
+		return false, newUnschedulableReason(schedulerobjects.UnschedulableReasonCodeGangExceedsQueueBurstSize, GangExceedsQueueBurstSizeUnschedulableReason)
 	}
-	if tokens < float64(gctx.Cardinality()) {
-		return false, QueueRateLimitExceededByGangUnschedulableReason, nil
+	if tokens < float64(len(jctxs)) {
+		return false, newUnschedulableReason(schedulerobjects.UnschedulableReasonCodeQueueRateLimitExceededByGang, QueueRateLimitExceededByGangUnschedulableReason)
+	}
+
+	// Job set running jobs limit check.
+	if maxRunningJobs, ok := JobSetMaxRunningJobsFromGang(gctx); ok && qctx.RunningJobsByJobSet != nil {
+		if qctx.RunningJobsByJobSet[jctxs[0].Job.GetJobSet()] > maxRunningJobs {
+			return false, newUnschedulableReason(schedulerobjects.UnschedulableReasonCodeJobSetMaxRunningJobsExceeded, JobSetMaxRunningJobsExceededUnschedulableReason)
+		}
+	}
+
+	// Paused jobs check.
+	if qctx.HoldPausedJobs && jctxs[0].SchedulingAnnotations.Paused {
+		return false, newUnschedulableReason(schedulerobjects.UnschedulableReasonCodeJobPaused, JobPausedUnschedulableReason)
 	}
 
 	// PriorityClassSchedulingConstraintsByPriorityClassName check.
 	if priorityClassConstraint, ok := constraints.PriorityClassSchedulingConstraintsByPriorityClassName[gctx.PriorityClassName]; ok {
-		if !qctx.AllocatedByPriorityClass[gctx.PriorityClassName].IsStrictlyLessOrEqual(priorityClassConstraint.MaximumResourcesPerQueue) {
-			return false, MaximumResourcesPerQueueExceededUnschedulableReason, nil
+		allocated := qctx.AllocatedByPriorityClass[gctx.PriorityClassName]
+		if !allocated.IsStrictlyLessOrEqual(priorityClassConstraint.MaximumResourcesPerQueue) {
+			return false, newUnschedulableReason(
+				schedulerobjects.UnschedulableReasonCodeMaximumResourcesPerQueue,
+				MaximumResourcesPerQueueExceededUnschedulableReason,
+			).WithResources(exceededResources(allocated, priorityClassConstraint.MaximumResourcesPerQueue)...)
+		}
+	}
+	return true, nil
+}
+
+// exceededResources returns the sorted names of the resources of which rl contains more than limit.
+func exceededResources(rl schedulerobjects.ResourceList, limit schedulerobjects.ResourceList) []string {
+	var rv []string
+	for t, q := range limit.Resources {
+		if q.Cmp(rl.Get(t)) == -1 {
+			rv = append(rv, t)
 		}
 	}
-	return true, "", nil
+	slices.Sort(rv)
+	return rv
 }
 
-func RequestsAreLargeEnough(totalResourceRequests, minRequest schedulerobjects.ResourceList) (bool, string) {
-	for t, minQuantity := range minRequest.Resources {
+// JobSetMaxRunningJobsFromGang returns (maxRunningJobs, true) if the first job of the gang
+// limits the number of running jobs of its job set, and (0, false) otherwise.
+func JobSetMaxRunningJobsFromGang(gctx *schedulercontext.GangSchedulingContext) (int, bool) {
+	if len(gctx.JobSchedulingContexts) == 0 {
+		return 0, false
+	}
+	schedulingAnnotations := gctx.JobSchedulingContexts[0].SchedulingAnnotations
+	return schedulingAnnotations.JobSetMaxRunningJobs, schedulingAnnotations.LimitsJobSetRunningJobs
+}
+
+func RequestsAreLargeEnough(totalResourceRequests, minRequest schedulerobjects.ResourceList) (bool, *schedulerobjects.UnschedulableReason) {
+	return requestsAtLeast(totalResourceRequests, minRequest, schedulerobjects.UnschedulableReasonCodeJobTooSmall, "job requests %s %s, but the minimum is %s")
+}
+
+// RequestsMeetPoolMinimum is like RequestsAreLargeEnough, but for the minimum job size configured for a pool.
+func RequestsMeetPoolMinimum(totalResourceRequests, poolMinRequest schedulerobjects.ResourceList) (bool, *schedulerobjects.UnschedulableReason) {
+	return requestsAtLeast(totalResourceRequests, poolMinRequest, schedulerobjects.UnschedulableReasonCodeBelowPoolMinimumJobSize, "job requests %s %s, but the minimum for this pool is %s")
+}
+
+// requestsAtLeast returns false along with a reason with the given code if totalResourceRequests contains less of any
+// resource than minRequest, where format is formatted with the requested amount, the resource name, and the minimum.
+func requestsAtLeast(
+	totalResourceRequests, minRequest schedulerobjects.ResourceList,
+	code schedulerobjects.UnschedulableReasonCode,
+	format string,
+) (bool, *schedulerobjects.UnschedulableReason) {
+	for _, t := range sortedResourceNames(minRequest) {
+		minQuantity := minRequest.Get(t)
 		q := totalResourceRequests.Get(t)
 		if minQuantity.Cmp(q) == 1 {
-			return false, fmt.Sprintf("job requests %s %s, but the minimum is %s", q.String(), t, minQuantity.String())
+			return false, schedulerobjects.NewUnschedulableReason(code, format, q.String(), t, minQuantity.String()).WithResources(t)
 		}
 	}
-	return true, ""
+	return true, nil
+}
+
+func sortedResourceNames(rl schedulerobjects.ResourceList) []string {
+	rv := maps.Keys(rl.Resources)
+	slices.Sort(rv)
+	return rv
 }
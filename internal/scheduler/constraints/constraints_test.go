@@ -7,33 +7,90 @@ import (
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/api/resource"
 
+	"github.com/armadaproject/armada/internal/armada/configuration"
 	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
 )
 
 func TestConstraints(t *testing.T) {
 	tests := map[string]struct {
 		constraints                                 SchedulingConstraints
 		sctx                                        *schedulercontext.SchedulingContext
-		globalUnschedulableReason                   string
+		globalUnschedulableReason                   *schedulerobjects.UnschedulableReason
 		queue                                       string
 		priorityClassName                           string
-		perQueueAndPriorityClassUnschedulableReason string
+		perQueueAndPriorityClassUnschedulableReason *schedulerobjects.UnschedulableReason
 	}{} // TODO: Add tests.
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			ok, unschedulableReason, err := tc.constraints.CheckRoundConstraints(tc.sctx, tc.queue)
 			require.NoError(t, err)
-			require.Equal(t, tc.globalUnschedulableReason == "", ok)
+			require.Equal(t, tc.globalUnschedulableReason == nil, ok)
 			require.Equal(t, tc.globalUnschedulableReason, unschedulableReason)
 
 			ok, unschedulableReason, err = tc.constraints.CheckConstraints(tc.sctx, nil)
 			require.NoError(t, err)
-			require.Equal(t, tc.perQueueAndPriorityClassUnschedulableReason == "", ok)
+			require.Equal(t, tc.perQueueAndPriorityClassUnschedulableReason == nil, ok)
 			require.Equal(t, tc.perQueueAndPriorityClassUnschedulableReason, unschedulableReason)
 		})
 	}
 }
 
+func TestCheckRoundConstraints_MaximumResourcesScheduled(t *testing.T) {
+	constraints := SchedulingConstraints{
+		MaximumResourcesToSchedule: schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{
+			"cpu":    resource.MustParse("10"),
+			"memory": resource.MustParse("10Gi"),
+		}},
+	}
+	sctx := &schedulercontext.SchedulingContext{
+		ScheduledResources: schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{
+			"cpu":    resource.MustParse("11"),
+			"memory": resource.MustParse("1Gi"),
+		}},
+	}
+	ok, reason, err := constraints.CheckRoundConstraints(sctx, "queue")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, schedulerobjects.UnschedulableReasonCodeMaximumResourcesScheduled, reason.Code)
+	assert.Equal(t, []string{"cpu"}, reason.Resources)
+	assert.True(t, IsTerminalUnschedulableReason(reason))
+	assert.False(t, IsTerminalQueueUnschedulableReason(reason))
+}
+
+func TestRequestsAreLargeEnough(t *testing.T) {
+	minimumJobSize := schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"nvidia.com/gpu": resource.MustParse("1")}}
+
+	ok, reason := RequestsAreLargeEnough(schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("1")}}, minimumJobSize)
+	assert.False(t, ok)
+	assert.Equal(t, schedulerobjects.UnschedulableReasonCodeJobTooSmall, reason.Code)
+	assert.Equal(t, []string{"nvidia.com/gpu"}, reason.Resources)
+
+	ok, reason = RequestsAreLargeEnough(schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"nvidia.com/gpu": resource.MustParse("2")}}, minimumJobSize)
+	assert.True(t, ok)
+	assert.Nil(t, reason)
+}
+
+func TestRequestsMeetPoolMinimum(t *testing.T) {
+	config := configuration.SchedulingConfig{
+		MinimumJobSizeByPool: map[string]map[string]resource.Quantity{
+			"highmem": {"memory": resource.MustParse("32Gi")},
+		},
+	}
+	requests := schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"memory": resource.MustParse("1Gi")}}
+
+	constraints := SchedulingConstraintsFromSchedulingConfig("highmem", schedulerobjects.ResourceList{}, schedulerobjects.ResourceList{}, config)
+	ok, reason := RequestsMeetPoolMinimum(requests, constraints.PoolMinimumJobSize)
+	assert.False(t, ok)
+	assert.Equal(t, schedulerobjects.UnschedulableReasonCodeBelowPoolMinimumJobSize, reason.Code)
+	assert.Equal(t, []string{"memory"}, reason.Resources)
+
+	constraints = SchedulingConstraintsFromSchedulingConfig("cpu", schedulerobjects.ResourceList{}, schedulerobjects.ResourceList{}, config)
+	ok, reason = RequestsMeetPoolMinimum(requests, constraints.PoolMinimumJobSize)
+	assert.True(t, ok)
+	assert.Nil(t, reason)
+}
+
 func TestScaleQuantity(t *testing.T) {
 	tests := map[string]struct {
 		input    resource.Quantity
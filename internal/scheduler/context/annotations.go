@@ -0,0 +1,93 @@
+package context
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadaerrors"
+)
+
+// SchedulingAnnotations are the options controlling how a job is scheduled that jobs may set via annotations, parsed into typed fields.
+// Annotations are validated at submission; jobs with invalid values that were submitted before an annotation was validated
+// are scheduled as if the annotation wasn't set.
+type SchedulingAnnotations struct {
+	// Set via NonPreemptibleAnnotation.
+	NonPreemptible bool
+	// Set via PreferSpotAnnotation.
+	PreferSpot bool
+	// Set via GangNodeUniformityLabelAnnotation.
+	GangNodeUniformityLabel string
+	// Set via GangNodeUniformitySoftAnnotation, i.e., if true, the gang may span several values of its node uniformity label, e.g., zones.
+	GangNodeUniformitySoft bool
+	// Set via PausedAnnotation.
+	Paused bool
+	// Set via JobSetMaxRunningJobsAnnotation. Only meaningful if LimitsJobSetRunningJobs is true.
+	JobSetMaxRunningJobs    int
+	LimitsJobSetRunningJobs bool
+	// Set via GangSchedulingDeadlineAnnotation. If zero, the gang has no deadline.
+	GangSchedulingDeadline time.Duration
+}
+
+// ParseSchedulingAnnotations parses the scheduling options set via annotations.
+// Returns an error describing the first annotation with an invalid value, if any,
+// alongside the options parsed from the other annotations.
+func ParseSchedulingAnnotations(annotations map[string]string) (SchedulingAnnotations, error) {
+	var rv SchedulingAnnotations
+	var err error
+	parseBool := func(name string, dst *bool) {
+		value, ok := annotations[name]
+		if !ok {
+			return
+		}
+		switch value {
+		case "true":
+			*dst = true
+		case "false":
+			*dst = false
+		default:
+			if err == nil {
+				err = errors.WithStack(&armadaerrors.ErrInvalidArgument{
+					Name:    name,
+					Value:   value,
+					Message: "must be either \"true\" or \"false\"",
+				})
+			}
+		}
+	}
+	parseBool(configuration.NonPreemptibleAnnotation, &rv.NonPreemptible)
+	parseBool(configuration.PreferSpotAnnotation, &rv.PreferSpot)
+	parseBool(configuration.GangNodeUniformitySoftAnnotation, &rv.GangNodeUniformitySoft)
+	parseBool(configuration.PausedAnnotation, &rv.Paused)
+	rv.GangNodeUniformityLabel = annotations[configuration.GangNodeUniformityLabelAnnotation]
+	if value, ok := annotations[configuration.JobSetMaxRunningJobsAnnotation]; ok {
+		if maxRunningJobs, parseErr := strconv.Atoi(value); parseErr != nil || maxRunningJobs < 0 {
+			if err == nil {
+				err = errors.WithStack(&armadaerrors.ErrInvalidArgument{
+					Name:    configuration.JobSetMaxRunningJobsAnnotation,
+					Value:   value,
+					Message: "maximum number of running jobs of a job set must be a non-negative integer",
+				})
+			}
+		} else {
+			rv.JobSetMaxRunningJobs = maxRunningJobs
+			rv.LimitsJobSetRunningJobs = true
+		}
+	}
+	if value, ok := annotations[configuration.GangSchedulingDeadlineAnnotation]; ok {
+		if deadline, parseErr := time.ParseDuration(value); parseErr != nil || deadline <= 0 {
+			if err == nil {
+				err = errors.WithStack(&armadaerrors.ErrInvalidArgument{
+					Name:    configuration.GangSchedulingDeadlineAnnotation,
+					Value:   value,
+					Message: "gang scheduling deadline must be a positive duration, e.g., \"2h\"",
+				})
+			}
+		} else {
+			rv.GangSchedulingDeadline = deadline
+		}
+	}
+	return rv, err
+}
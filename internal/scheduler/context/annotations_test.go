@@ -0,0 +1,70 @@
+package context
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+)
+
+func TestParseSchedulingAnnotations(t *testing.T) {
+	tests := map[string]struct {
+		Annotations   map[string]string
+		Expected      SchedulingAnnotations
+		ExpectSuccess bool
+	}{
+		"no annotations": {
+			ExpectSuccess: true,
+		},
+		"all annotations": {
+			Annotations: map[string]string{
+				configuration.NonPreemptibleAnnotation:          "true",
+				configuration.PreferSpotAnnotation:              "true",
+				configuration.GangNodeUniformityLabelAnnotation: "zone",
+				configuration.GangNodeUniformitySoftAnnotation:  "true",
+				configuration.PausedAnnotation:                  "false",
+				configuration.JobSetMaxRunningJobsAnnotation:    "10",
+				configuration.GangSchedulingDeadlineAnnotation:  "2h",
+			},
+			Expected: SchedulingAnnotations{
+				NonPreemptible:          true,
+				PreferSpot:              true,
+				GangNodeUniformityLabel: "zone",
+				GangNodeUniformitySoft:  true,
+				JobSetMaxRunningJobs:    10,
+				LimitsJobSetRunningJobs: true,
+				GangSchedulingDeadline:  2 * time.Hour,
+			},
+			ExpectSuccess: true,
+		},
+		"invalid flag ignored": {
+			Annotations: map[string]string{
+				configuration.NonPreemptibleAnnotation: "yes",
+				configuration.PreferSpotAnnotation:     "true",
+			},
+			Expected: SchedulingAnnotations{
+				PreferSpot: true,
+			},
+			ExpectSuccess: false,
+		},
+		"invalid job set limit ignored": {
+			Annotations: map[string]string{
+				configuration.JobSetMaxRunningJobsAnnotation: "-1",
+			},
+			ExpectSuccess: false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := ParseSchedulingAnnotations(tc.Annotations)
+			if tc.ExpectSuccess {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+			assert.Equal(t, tc.Expected, actual)
+		})
+	}
+}
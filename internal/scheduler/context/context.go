@@ -2,10 +2,12 @@ package context
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/openconfig/goyang/pkg/indent"
 	"github.com/pkg/errors"
 	"golang.org/x/exp/maps"
@@ -69,6 +71,33 @@ type SchedulingContext struct {
 	// Used to immediately reject new jobs with identical reqirements.
 	// Maps to the JobSchedulingContext of a previous job attempted to schedule with the same key.
 	UnfeasibleSchedulingKeys map[schedulerobjects.SchedulingKey]*JobSchedulingContext
+	// Scheduling keys known to be unfeasible before the round started, e.g., since a previous round with the same inputs found them to be.
+	// ClearUnfeasibleSchedulingKeys resets UnfeasibleSchedulingKeys to these keys rather than emptying it.
+	InitialUnfeasibleSchedulingKeys map[schedulerobjects.SchedulingKey]*JobSchedulingContext
+	// If non-nil, members of a gang left unscheduled once the gang meets its minimum cardinality remain queued instead of failing.
+	// Maps the id of each gang with running jobs to the number of its members still queued;
+	// those members are scheduled together as a remainder gang.
+	GangRemainderCardinalityByGangId map[string]int
+	// If non-zero, at most this many unsuccessful job scheduling contexts are retained per queue;
+	// see QueueSchedulingContext.NumDiscardedUnsuccessfulJobsByReason.
+	MaxUnsuccessfulJobSchedulingContextsPerQueue int
+	// If non-zero, the first new gang of at least this many jobs that doesn't fit onto the nodes of the pool
+	// is recorded as GangAccumulationCandidate, such that capacity may be reserved for it across rounds.
+	GangAccumulationMinCardinality int
+	// See GangAccumulationMinCardinality. Nil if there was no such gang.
+	GangAccumulationCandidate *GangSchedulingContext
+	// If non-nil, large gangs past their scheduling deadline are held, rather than failed, while capacity they'd fit onto is forecast.
+	CapacityForecast *CapacityForecast
+}
+
+// CapacityForecast is the capacity of the pool being scheduled forecast a short horizon ahead,
+// alongside the policy for which gangs past their scheduling deadline are held.
+type CapacityForecast struct {
+	Capacity schedulerobjects.ResourceList
+	// Only gangs with at least this many jobs are held.
+	MinGangCardinality int
+	// Gangs are held for at most this long past their scheduling deadline.
+	MaxHoldDuration time.Duration
 }
 
 func NewSchedulingContext(
@@ -112,8 +141,38 @@ func (sctx *SchedulingContext) SchedulingKeyFromLegacySchedulerJob(job interface
 	)
 }
 
+// ShouldHoldGang returns true if gctx, whose scheduling deadline passed at time t, should be held rather than failed.
+// That's the case if it has at least MinGangCardinality jobs, its deadline passed at most MaxHoldDuration ago,
+// and its total requests fit within the forecast capacity of the pool left over by jobs already allocated.
+func (sctx *SchedulingContext) ShouldHoldGang(gctx *GangSchedulingContext, t time.Time) bool {
+	forecast := sctx.CapacityForecast
+	if forecast == nil || gctx.Cardinality() < forecast.MinGangCardinality {
+		return false
+	}
+	if t.Sub(gctx.Submitted) > gctx.SchedulingDeadline+forecast.MaxHoldDuration {
+		return false
+	}
+	available := forecast.Capacity.DeepCopy()
+	for _, qctx := range sctx.QueueSchedulingContexts {
+		available.Sub(qctx.Allocated)
+	}
+	for t, q := range gctx.TotalResourceRequests.Resources {
+		if q.Cmp(available.Get(t)) == 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// SetInitialUnfeasibleSchedulingKeys sets the scheduling keys known to be unfeasible before the round started.
+func (sctx *SchedulingContext) SetInitialUnfeasibleSchedulingKeys(keys map[schedulerobjects.SchedulingKey]*JobSchedulingContext) {
+	sctx.InitialUnfeasibleSchedulingKeys = keys
+	sctx.ClearUnfeasibleSchedulingKeys()
+}
+
 func (sctx *SchedulingContext) ClearUnfeasibleSchedulingKeys() {
-	sctx.UnfeasibleSchedulingKeys = make(map[schedulerobjects.SchedulingKey]*JobSchedulingContext)
+	sctx.UnfeasibleSchedulingKeys = make(map[schedulerobjects.SchedulingKey]*JobSchedulingContext, len(sctx.InitialUnfeasibleSchedulingKeys))
+	maps.Copy(sctx.UnfeasibleSchedulingKeys, sctx.InitialUnfeasibleSchedulingKeys)
 }
 
 func (sctx *SchedulingContext) AddQueueSchedulingContext(
@@ -352,6 +411,9 @@ type QueueSchedulingContext struct {
 	// Limits job scheduling rate for this queue.
 	// Use the "Started" time to ensure limiter state remains constant within each scheduling round.
 	Limiter *rate.Limiter
+	// Order in which the queued jobs of this queue are considered for scheduling.
+	// If empty, jobs are considered in Fifo order.
+	IntraQueueOrdering configuration.IntraQueueOrdering
 	// Total resources assigned to the queue across all clusters by priority class priority.
 	// Includes jobs scheduled during this invocation of the scheduler.
 	Allocated schedulerobjects.ResourceList
@@ -365,9 +427,25 @@ type QueueSchedulingContext struct {
 	// Job scheduling contexts associated with successful scheduling attempts.
 	SuccessfulJobSchedulingContexts map[string]*JobSchedulingContext
 	// Job scheduling contexts associated with unsuccessful scheduling attempts.
+	// If SchedulingContext.MaxUnsuccessfulJobSchedulingContextsPerQueue is non-zero, this is a sample of those contexts,
+	// including at least one context per unschedulable reason if the limit permits.
 	UnsuccessfulJobSchedulingContexts map[string]*JobSchedulingContext
+	// Number of unsuccessful scheduling attempts the job scheduling contexts of which were discarded
+	// since the limit on retained contexts was reached, indexed by unschedulable reason and by unschedulable reason code.
+	NumDiscardedUnsuccessfulJobsByReason     map[string]int
+	NumDiscardedUnsuccessfulJobsByReasonCode map[schedulerobjects.UnschedulableReasonCode]int
+	// Ids of the jobs in UnsuccessfulJobSchedulingContexts indexed by unschedulable reason.
+	// Only maintained if the number of retained contexts is limited.
+	retainedUnsuccessfulJobIdsByReason map[string][]string
 	// Jobs evicted in this round.
 	EvictedJobsById map[string]bool
+	// Number of running jobs of each job set of this queue across all clusters, indexed by job set name.
+	// Includes jobs scheduled during this invocation of the scheduler.
+	// If nil, running jobs aren't counted by job set and job set limits aren't enforced.
+	RunningJobsByJobSet map[string]int
+	// If true, jobs paused at submission for exceeding the queued jobs limit of this queue aren't scheduled,
+	// since the queue has other queued jobs.
+	HoldPausedJobs bool
 }
 
 func GetSchedulingContextFromQueueSchedulingContext(qctx *QueueSchedulingContext) *SchedulingContext {
@@ -399,6 +477,9 @@ func (qctx *QueueSchedulingContext) ReportString(verbosity int32) string {
 	if verbosity >= 0 {
 		fmt.Fprintf(w, "Time:\t%s\n", qctx.Created)
 		fmt.Fprintf(w, "Queue:\t%s\n", qctx.Queue)
+		if qctx.IntraQueueOrdering != "" {
+			fmt.Fprintf(w, "Intra-queue ordering:\t%s\n", qctx.IntraQueueOrdering)
+		}
 	}
 	fmt.Fprintf(w, "Scheduled resources:\t%s\n", qctx.ScheduledResourcesByPriorityClass.AggregateByResource().CompactString())
 	fmt.Fprintf(w, "Scheduled resources (by priority):\t%s\n", qctx.ScheduledResourcesByPriorityClass.String())
@@ -409,7 +490,16 @@ func (qctx *QueueSchedulingContext) ReportString(verbosity int32) string {
 		fmt.Fprintf(w, "Total allocated resources after scheduling by priority class:\t%s\n", qctx.AllocatedByPriorityClass)
 		fmt.Fprintf(w, "Number of jobs scheduled:\t%d\n", len(qctx.SuccessfulJobSchedulingContexts))
 		fmt.Fprintf(w, "Number of jobs preempted:\t%d\n", len(qctx.EvictedJobsById))
-		fmt.Fprintf(w, "Number of jobs that could not be scheduled:\t%d\n", len(qctx.UnsuccessfulJobSchedulingContexts))
+		fmt.Fprintf(w, "Number of jobs that could not be scheduled:\t%d\n", qctx.NumUnsuccessfulJobs())
+		numJobsWithNodeUniformityPenalty := 0
+		for _, jctx := range qctx.SuccessfulJobSchedulingContexts {
+			if jctx.NodeUniformityPenalty > 0 {
+				numJobsWithNodeUniformityPenalty++
+			}
+		}
+		if numJobsWithNodeUniformityPenalty > 0 {
+			fmt.Fprintf(w, "Number of gang jobs scheduled without node uniformity:\t%d\n", numJobsWithNodeUniformityPenalty)
+		}
 		if len(qctx.SuccessfulJobSchedulingContexts) > 0 {
 			jobIdsToPrint := maps.Keys(qctx.SuccessfulJobSchedulingContexts)
 			if len(jobIdsToPrint) > maxJobIdsToPrint {
@@ -434,26 +524,31 @@ func (qctx *QueueSchedulingContext) ReportString(verbosity int32) string {
 				fmt.Fprint(w, "\n")
 			}
 		}
-		if len(qctx.UnsuccessfulJobSchedulingContexts) > 0 {
+		if qctx.NumUnsuccessfulJobs() > 0 {
 			fmt.Fprint(w, "Unschedulable jobs:\n")
 			jobIdsByReason := armadaslices.MapAndGroupByFuncs(
 				maps.Values(qctx.UnsuccessfulJobSchedulingContexts),
-				func(jctx *JobSchedulingContext) string {
-					return jctx.UnschedulableReason
-				},
+				unschedulableReasonString,
 				func(jctx *JobSchedulingContext) string {
 					return jctx.JobId
 				},
 			)
-			reasons := maps.Keys(jobIdsByReason)
-			slices.SortFunc(reasons, func(a, b string) bool { return len(jobIdsByReason[a]) < len(jobIdsByReason[b]) })
+			numJobsByReason := maps.Clone(qctx.NumDiscardedUnsuccessfulJobsByReason)
+			if numJobsByReason == nil {
+				numJobsByReason = make(map[string]int, len(jobIdsByReason))
+			}
+			for reason, jobIds := range jobIdsByReason {
+				numJobsByReason[reason] += len(jobIds)
+			}
+			reasons := maps.Keys(numJobsByReason)
+			slices.SortFunc(reasons, func(a, b string) bool { return numJobsByReason[a] < numJobsByReason[b] })
 			for i := len(reasons) - 1; i >= 0; i-- {
 				reason := reasons[i]
-				jobIds := jobIdsByReason[reason]
-				if len(jobIds) <= 0 {
-					continue
+				if jobIds := jobIdsByReason[reason]; len(jobIds) > 0 {
+					fmt.Fprintf(w, "\t%d:\t%s (e.g., %s)\n", numJobsByReason[reason], reason, jobIds[0])
+				} else {
+					fmt.Fprintf(w, "\t%d:\t%s\n", numJobsByReason[reason], reason)
 				}
-				fmt.Fprintf(w, "\t%d:\t%s (e.g., %s)\n", len(jobIds), reason, jobIds[0])
 			}
 		}
 	}
@@ -480,6 +575,9 @@ func (qctx *QueueSchedulingContext) AddJobSchedulingContext(jctx *JobSchedulingC
 		// Since ResourcesByPriority is used to order queues by fraction of fair share.
 		qctx.Allocated.AddV1ResourceList(jctx.PodRequirements.ResourceRequirements.Requests)
 		qctx.AllocatedByPriorityClass.AddV1ResourceList(jctx.Job.GetPriorityClassName(), jctx.PodRequirements.ResourceRequirements.Requests)
+		if qctx.RunningJobsByJobSet != nil {
+			qctx.RunningJobsByJobSet[jctx.Job.GetJobSet()]++
+		}
 
 		// Only if the job is not evicted, update ScheduledResourcesByPriority.
 		// Since ScheduledResourcesByPriority is used to control per-round scheduling constraints.
@@ -491,11 +589,106 @@ func (qctx *QueueSchedulingContext) AddJobSchedulingContext(jctx *JobSchedulingC
 			qctx.ScheduledResourcesByPriorityClass.AddV1ResourceList(jctx.Job.GetPriorityClassName(), jctx.PodRequirements.ResourceRequirements.Requests)
 		}
 	} else {
-		qctx.UnsuccessfulJobSchedulingContexts[jctx.JobId] = jctx
+		qctx.addUnsuccessfulJobSchedulingContext(jctx)
 	}
 	return evictedInThisRound, nil
 }
 
+// addUnsuccessfulJobSchedulingContext retains jctx, unless the limit on retained unsuccessful contexts is reached,
+// in which case only the number of unsuccessful attempts is recorded. Once the limit is reached, a context with an
+// unschedulable reason not yet retained replaces a context of the reason with the most retained contexts.
+// A context retained for the same job, possibly with another reason, is replaced by jctx.
+func (qctx *QueueSchedulingContext) addUnsuccessfulJobSchedulingContext(jctx *JobSchedulingContext) {
+	maxRetained := 0
+	if qctx.SchedulingContext != nil {
+		maxRetained = qctx.SchedulingContext.MaxUnsuccessfulJobSchedulingContextsPerQueue
+	}
+	if maxRetained <= 0 {
+		qctx.UnsuccessfulJobSchedulingContexts[jctx.JobId] = jctx
+		return
+	}
+	if qctx.retainedUnsuccessfulJobIdsByReason == nil {
+		qctx.retainedUnsuccessfulJobIdsByReason = make(map[string][]string)
+	}
+	qctx.removeRetainedUnsuccessfulJobSchedulingContext(jctx.JobId)
+	reason := unschedulableReasonString(jctx)
+	if len(qctx.UnsuccessfulJobSchedulingContexts) >= maxRetained {
+		if len(qctx.retainedUnsuccessfulJobIdsByReason[reason]) > 0 {
+			qctx.discardUnsuccessfulJobSchedulingContext(jctx)
+			return
+		}
+		mostRetainedReason := ""
+		for r, jobIds := range qctx.retainedUnsuccessfulJobIdsByReason {
+			numMostRetained := len(qctx.retainedUnsuccessfulJobIdsByReason[mostRetainedReason])
+			if len(jobIds) > numMostRetained || (len(jobIds) == numMostRetained && r < mostRetainedReason) {
+				mostRetainedReason = r
+			}
+		}
+		jobIds := qctx.retainedUnsuccessfulJobIdsByReason[mostRetainedReason]
+		if len(jobIds) <= 1 {
+			qctx.discardUnsuccessfulJobSchedulingContext(jctx)
+			return
+		}
+		replacedJobId := jobIds[len(jobIds)-1]
+		qctx.retainedUnsuccessfulJobIdsByReason[mostRetainedReason] = jobIds[:len(jobIds)-1]
+		qctx.discardUnsuccessfulJobSchedulingContext(qctx.UnsuccessfulJobSchedulingContexts[replacedJobId])
+		delete(qctx.UnsuccessfulJobSchedulingContexts, replacedJobId)
+	}
+	qctx.UnsuccessfulJobSchedulingContexts[jctx.JobId] = jctx
+	qctx.retainedUnsuccessfulJobIdsByReason[reason] = append(qctx.retainedUnsuccessfulJobIdsByReason[reason], jctx.JobId)
+}
+
+// removeRetainedUnsuccessfulJobSchedulingContext removes the retained unsuccessful context of the job with the provided id, if any.
+// The job id is looked up under all reasons, since the reason of the retained context may have changed since it was retained.
+func (qctx *QueueSchedulingContext) removeRetainedUnsuccessfulJobSchedulingContext(jobId string) {
+	if _, ok := qctx.UnsuccessfulJobSchedulingContexts[jobId]; !ok {
+		return
+	}
+	delete(qctx.UnsuccessfulJobSchedulingContexts, jobId)
+	for reason, jobIds := range qctx.retainedUnsuccessfulJobIdsByReason {
+		i := slices.Index(jobIds, jobId)
+		if i < 0 {
+			continue
+		}
+		if jobIds = slices.Delete(jobIds, i, i+1); len(jobIds) > 0 {
+			qctx.retainedUnsuccessfulJobIdsByReason[reason] = jobIds
+		} else {
+			delete(qctx.retainedUnsuccessfulJobIdsByReason, reason)
+		}
+	}
+}
+
+func (qctx *QueueSchedulingContext) discardUnsuccessfulJobSchedulingContext(jctx *JobSchedulingContext) {
+	if qctx.NumDiscardedUnsuccessfulJobsByReason == nil {
+		qctx.NumDiscardedUnsuccessfulJobsByReason = make(map[string]int)
+	}
+	if qctx.NumDiscardedUnsuccessfulJobsByReasonCode == nil {
+		qctx.NumDiscardedUnsuccessfulJobsByReasonCode = make(map[schedulerobjects.UnschedulableReasonCode]int)
+	}
+	qctx.NumDiscardedUnsuccessfulJobsByReason[unschedulableReasonString(jctx)]++
+	code := jctx.UnschedulableReason.GetCode()
+	if code == "" {
+		code = schedulerobjects.UnschedulableReasonCodeUnknown
+	}
+	qctx.NumDiscardedUnsuccessfulJobsByReasonCode[code]++
+}
+
+// NumUnsuccessfulJobs returns the number of unsuccessful scheduling attempts, including those the contexts of which were discarded.
+func (qctx *QueueSchedulingContext) NumUnsuccessfulJobs() int {
+	rv := len(qctx.UnsuccessfulJobSchedulingContexts)
+	for _, n := range qctx.NumDiscardedUnsuccessfulJobsByReason {
+		rv += n
+	}
+	return rv
+}
+
+func unschedulableReasonString(jctx *JobSchedulingContext) string {
+	if jctx.UnschedulableReason == nil {
+		return ""
+	}
+	return jctx.UnschedulableReason.Error()
+}
+
 func (qctx *QueueSchedulingContext) EvictJob(job interfaces.LegacySchedulerJob) (bool, error) {
 	jobId := job.GetId()
 	if _, ok := qctx.UnsuccessfulJobSchedulingContexts[jobId]; ok {
@@ -515,6 +708,9 @@ func (qctx *QueueSchedulingContext) EvictJob(job interfaces.LegacySchedulerJob)
 	}
 	qctx.Allocated.SubV1ResourceList(rl)
 	qctx.AllocatedByPriorityClass.SubV1ResourceList(job.GetPriorityClassName(), rl)
+	if qctx.RunningJobsByJobSet != nil {
+		qctx.RunningJobsByJobSet[job.GetJobSet()]--
+	}
 	return scheduledInThisRound, nil
 }
 
@@ -529,36 +725,118 @@ func (qctx *QueueSchedulingContext) ClearJobSpecs() {
 }
 
 type GangSchedulingContext struct {
-	Created               time.Time
+	Created time.Time
+	// Queue owning the gang, i.e., the queue in the turn of which the gang is scheduled.
+	// All jobs of the gang are submitted to this queue, except for the jobs of cross-queue gangs,
+	// which may be submitted to other queues; see JobSchedulingContextsByQueue.
 	Queue                 string
 	PriorityClassName     string
 	JobSchedulingContexts []*JobSchedulingContext
 	TotalResourceRequests schedulerobjects.ResourceList
 	AllJobsEvicted        bool
 	NodeUniformityLabel   string
-	GangMinCardinality    int
+	// If true, the gang may be scheduled across nodes with different values for NodeUniformityLabel
+	// if it can't be scheduled onto nodes with a single value.
+	NodeUniformityIsSoft bool
+	// If set, the gang has a hierarchical node uniformity constraint, with labels ordered from coarsest to finest, e.g., region, zone, and rack.
+	// The gang is scheduled onto nodes with a single value for the finest label possible; NodeUniformityLabel is empty.
+	NodeUniformityLabels []string
+	// The node uniformity label all nodes the gang was scheduled onto have a single value for,
+	// i.e., the level of a hierarchical node uniformity constraint achieved. Empty if the gang wasn't scheduled uniformly.
+	AchievedNodeUniformityLabel string
+	// Number of values of the node uniformity label scheduling attempts were made for and the number skipped,
+	// i.e., the search was truncated if NodeUniformityLabelValuesSkipped is non-zero.
+	// Values are skipped if there are more than the maximum number of values to consider for the pool,
+	// in which case values with less free capacity relative to the resources requested by the gang are skipped first.
+	NodeUniformityLabelValuesConsidered int
+	NodeUniformityLabelValuesSkipped    int
+	GangMinCardinality                  int
+	// If set, the gang is scheduled alongside running jobs in the same queue and colocation group,
+	// i.e., onto the same node or onto a node with the same value for ColocationLabel, if set.
+	ColocationGroup string
+	ColocationLabel string
+	// If MinFailureDomains is non-zero, the gang must be scheduled onto nodes spanning at least that many values
+	// of FailureDomainLabel, e.g., racks.
+	FailureDomainLabel string
+	MinFailureDomains  int
+	// If set, each job of the gang must be scheduled onto a node with a distinct value for this label, e.g., a distinct rack.
+	SpreadLabel string
+	// If set, this is a remainder gang, i.e., it's made up of the members of the gang with this id
+	// left unscheduled when that gang was scheduled at its minimum cardinality.
+	// Members of a remainder gang are scheduled independently of each other and rejoin the parent gang once scheduled.
+	ParentGangId string
+	// True if all jobs in the gang have the same scheduling requirements, i.e., resource requests, node selectors,
+	// affinity, tolerations, and priority. Jobs of heterogeneous gangs are given their own unschedulable reasons.
+	Homogeneous bool
+	// Time at which the first job of the gang was submitted.
+	Submitted time.Time
+	// If non-zero, the gang is failed if it hasn't been scheduled within this duration of Submitted.
+	SchedulingDeadline time.Duration
 }
 
+// gangSchedulingKeyGenerator is used to compare the scheduling requirements of the jobs within a gang.
+// Keys generated by it are only meaningful relative to each other.
+var gangSchedulingKeyGenerator = schedulerobjects.NewSchedulingKeyGenerator()
+
 func NewGangSchedulingContext(jctxs []*JobSchedulingContext) *GangSchedulingContext {
-	// We assume that all jobs in a gang are in the same queue and have the same priority class
-	// (which we enforce at job submission).
+	// We assume that all jobs in a gang are in the same queue, or have the same gang queue for cross-queue gangs,
+	// and have the same priority class (which we enforce at job submission).
 	queue := ""
 	priorityClassName := ""
 	nodeUniformityLabel := ""
+	nodeUniformityIsSoft := false
+	var nodeUniformityLabels []string
+	colocationGroup := ""
+	colocationLabel := ""
+	failureDomainLabel := ""
+	minFailureDomains := 0
+	spreadLabel := ""
 	gangMinCardinality := 1
+	var schedulingDeadline time.Duration
 	if len(jctxs) > 0 {
 		queue = jctxs[0].Job.GetQueue()
+		if gangQueue := jctxs[0].Job.GetAnnotations()[configuration.GangQueueAnnotation]; gangQueue != "" {
+			queue = gangQueue
+		}
 		priorityClassName = jctxs[0].Job.GetPriorityClassName()
 		if jctxs[0].PodRequirements != nil {
-			nodeUniformityLabel = jctxs[0].PodRequirements.Annotations[configuration.GangNodeUniformityLabelAnnotation]
+			nodeUniformityLabel = jctxs[0].SchedulingAnnotations.GangNodeUniformityLabel
+			nodeUniformityIsSoft = jctxs[0].SchedulingAnnotations.GangNodeUniformitySoft
+			if value, ok := jctxs[0].PodRequirements.Annotations[configuration.GangNodeUniformityLabelsAnnotation]; ok {
+				nodeUniformityLabels = NodeUniformityLabelsFromAnnotation(value)
+				nodeUniformityLabel = ""
+			}
+			colocationGroup = jctxs[0].PodRequirements.Annotations[configuration.ColocationGroupAnnotation]
+			colocationLabel = jctxs[0].PodRequirements.Annotations[configuration.ColocationLabelAnnotation]
+			// Invalid values are rejected at submission.
+			failureDomainLabel = jctxs[0].PodRequirements.Annotations[configuration.GangFailureDomainLabelAnnotation]
+			minFailureDomains, _ = strconv.Atoi(jctxs[0].PodRequirements.Annotations[configuration.GangMinFailureDomainsAnnotation])
+			spreadLabel = jctxs[0].PodRequirements.Annotations[configuration.GangSpreadLabelAnnotation]
 		}
 		gangMinCardinality = jctxs[0].GangMinCardinality
+		schedulingDeadline = jctxs[0].SchedulingAnnotations.GangSchedulingDeadline
+	}
+	var submitted time.Time
+	for i, jctx := range jctxs {
+		if i == 0 || jctx.Job.GetSubmitTime().Before(submitted) {
+			submitted = jctx.Job.GetSubmitTime()
+		}
 	}
 	allJobsEvicted := true
+	homogeneous := true
 	totalResourceRequests := schedulerobjects.NewResourceList(4)
-	for _, jctx := range jctxs {
+	var firstSchedulingKey schedulerobjects.SchedulingKey
+	for i, jctx := range jctxs {
 		allJobsEvicted = allJobsEvicted && isEvictedJob(jctx.Job)
 		totalResourceRequests.AddV1ResourceList(jctx.PodRequirements.ResourceRequirements.Requests)
+		if len(jctxs) > 1 {
+			schedulingKey := gangSchedulingKeyGenerator.KeyFromPodRequirements(jctx.PodRequirements)
+			if i == 0 {
+				firstSchedulingKey = schedulingKey
+			} else if schedulingKey != firstSchedulingKey {
+				homogeneous = false
+			}
+		}
 	}
 	return &GangSchedulingContext{
 		Created:               time.Now(),
@@ -568,15 +846,92 @@ func NewGangSchedulingContext(jctxs []*JobSchedulingContext) *GangSchedulingCont
 		TotalResourceRequests: totalResourceRequests,
 		AllJobsEvicted:        allJobsEvicted,
 		NodeUniformityLabel:   nodeUniformityLabel,
+		NodeUniformityIsSoft:  nodeUniformityIsSoft,
+		NodeUniformityLabels:  nodeUniformityLabels,
 		GangMinCardinality:    gangMinCardinality,
+		ColocationGroup:       colocationGroup,
+		ColocationLabel:       colocationLabel,
+		FailureDomainLabel:    failureDomainLabel,
+		MinFailureDomains:     minFailureDomains,
+		SpreadLabel:           spreadLabel,
+		Homogeneous:           homogeneous,
+		Submitted:             submitted,
+		SchedulingDeadline:    schedulingDeadline,
 	}
 }
 
+// SchedulingDeadlineExceeded returns true if the gang has a scheduling deadline that's passed at time t.
+func (gctx *GangSchedulingContext) SchedulingDeadlineExceeded(t time.Time) bool {
+	return gctx.SchedulingDeadline > 0 && t.Sub(gctx.Submitted) > gctx.SchedulingDeadline
+}
+
+// NodeUniformityLabelsFromAnnotation returns the labels of a comma-separated list of node uniformity labels,
+// as provided via GangNodeUniformityLabelsAnnotation.
+func NodeUniformityLabelsFromAnnotation(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	labels := strings.Split(value, ",")
+	for i, label := range labels {
+		labels[i] = strings.TrimSpace(label)
+	}
+	return labels
+}
+
 // Cardinality returns the number of jobs in the gang.
 func (gctx *GangSchedulingContext) Cardinality() int {
 	return len(gctx.JobSchedulingContexts)
 }
 
+// JobSchedulingContextsByQueue returns the job scheduling contexts of the gang grouped by the queue each job is submitted to,
+// alongside the names of those queues, with the queue owning the gang first, if it has any jobs, followed by the others in lexicographic order.
+// Each job counts against the constraints of the queue it's submitted to.
+func (gctx *GangSchedulingContext) JobSchedulingContextsByQueue() ([]string, map[string][]*JobSchedulingContext) {
+	jctxsByQueue := make(map[string][]*JobSchedulingContext, 1)
+	for _, jctx := range gctx.JobSchedulingContexts {
+		queue := jctx.Job.GetQueue()
+		jctxsByQueue[queue] = append(jctxsByQueue[queue], jctx)
+	}
+	queues := make([]string, 0, len(jctxsByQueue))
+	for queue := range jctxsByQueue {
+		if queue != gctx.Queue {
+			queues = append(queues, queue)
+		}
+	}
+	slices.Sort(queues)
+	if _, ok := jctxsByQueue[gctx.Queue]; ok {
+		queues = append([]string{gctx.Queue}, queues...)
+	}
+	return queues, jctxsByQueue
+}
+
+// UnmetGangRole returns the first role, in order of appearance, for which fewer than the role minimum cardinality
+// of jobs have been assigned a node, and true, or the empty string and false if all role minimums are met.
+// Used to enforce that either enough jobs of every role in a gang are scheduled or none are.
+func UnmetGangRole(jctxs []*JobSchedulingContext) (string, bool) {
+	var roles []string
+	numScheduledByRole := make(map[string]int)
+	minCardinalityByRole := make(map[string]int)
+	for _, jctx := range jctxs {
+		if jctx.GangRole == "" {
+			continue
+		}
+		if _, ok := minCardinalityByRole[jctx.GangRole]; !ok {
+			roles = append(roles, jctx.GangRole)
+			minCardinalityByRole[jctx.GangRole] = jctx.GangRoleMinCardinality
+		}
+		if jctx.PodSchedulingContext != nil && jctx.PodSchedulingContext.NodeId != "" {
+			numScheduledByRole[jctx.GangRole]++
+		}
+	}
+	for _, role := range roles {
+		if numScheduledByRole[role] < minCardinalityByRole[role] {
+			return role, true
+		}
+	}
+	return "", false
+}
+
 func isEvictedJob(job interfaces.LegacySchedulerJob) bool {
 	return job.GetAnnotations()[schedulerconfig.IsEvictedAnnotation] == "true"
 }
@@ -594,14 +949,30 @@ type JobSchedulingContext struct {
 	// We currently require that each job contains exactly one pod spec.
 	PodRequirements *schedulerobjects.PodRequirements
 	// Reason for why the job could not be scheduled.
-	// Empty if the job was scheduled successfully.
-	UnschedulableReason string
+	// Nil if the job was scheduled successfully.
+	UnschedulableReason *schedulerobjects.UnschedulableReason
 	// Pod scheduling contexts for the individual pods that make up the job.
 	PodSchedulingContext *PodSchedulingContext
 	// The minimum size of the gang associated with this job.
 	GangMinCardinality int
 	// If set, indicates this job should be failed back to the client when the gang is scheduled.
 	ShouldFail bool
+	// Role of this job within its gang, e.g., "ps" or "worker". Empty if the job has no role.
+	GangRole string
+	// The minimum number of jobs with the same role as this job that must be scheduled for the gang to be scheduled.
+	GangRoleMinCardinality int
+	// Number of values of the node uniformity label the gang of this job was spread across beyond the first.
+	// Only non-zero for gangs with a soft node uniformity constraint that couldn't be scheduled onto nodes with a single value.
+	NodeUniformityPenalty int
+	// Reason this job in particular couldn't be placed in the most recent attempt to schedule its gang,
+	// e.g., since it doesn't fit on any node alongside the other members. Nil if it was placed.
+	// If the gang is heterogeneous and fails to schedule, this becomes the UnschedulableReason of the job.
+	GangMemberUnschedulableReason *schedulerobjects.UnschedulableReason
+	// Scheduling options set via the annotations of the job.
+	SchedulingAnnotations SchedulingAnnotations
+	// If node rejection diagnostics are enabled and the gang of this job failed to schedule since its jobs don't fit,
+	// the number of nodes rejecting this job on its own for each reason. Nil otherwise.
+	NodeRejections *NodeRejections
 }
 
 func (jctx *JobSchedulingContext) String() string {
@@ -609,8 +980,9 @@ func (jctx *JobSchedulingContext) String() string {
 	w := tabwriter.NewWriter(&sb, 1, 1, 1, ' ', 0)
 	fmt.Fprintf(w, "Time:\t%s\n", jctx.Created)
 	fmt.Fprintf(w, "Job ID:\t%s\n", jctx.JobId)
-	if jctx.UnschedulableReason != "" {
-		fmt.Fprintf(w, "UnschedulableReason:\t%s\n", jctx.UnschedulableReason)
+	if jctx.UnschedulableReason != nil {
+		fmt.Fprintf(w, "UnschedulableReason:\t%s\n", jctx.UnschedulableReason.Error())
+		fmt.Fprintf(w, "UnschedulableReasonCode:\t%s\n", jctx.UnschedulableReason.Code)
 	} else {
 		fmt.Fprint(w, "UnschedulableReason:\tnone\n")
 	}
@@ -618,12 +990,58 @@ func (jctx *JobSchedulingContext) String() string {
 		fmt.Fprint(w, jctx.PodSchedulingContext.String())
 	}
 	fmt.Fprintf(w, "GangMinCardinality:\t%d\n", jctx.GangMinCardinality)
+	if jctx.GangRole != "" {
+		fmt.Fprintf(w, "GangRole:\t%s\n", jctx.GangRole)
+		fmt.Fprintf(w, "GangRoleMinCardinality:\t%d\n", jctx.GangRoleMinCardinality)
+	}
+	if jctx.NodeUniformityPenalty > 0 {
+		fmt.Fprintf(w, "NodeUniformityPenalty:\t%d\n", jctx.NodeUniformityPenalty)
+	}
+	if jctx.NodeRejections != nil {
+		fmt.Fprintf(w, "NodeRejections:\t%s\n", jctx.NodeRejections)
+	}
 	w.Flush()
 	return sb.String()
 }
 
+// NodeRejections summarises why nodes reject a job, similar to the filter summaries of kube-scheduler,
+// from evaluating every node against the requirements of the job on its own.
+type NodeRejections struct {
+	// Number of nodes evaluated.
+	NumNodes int
+	// Number of nodes rejected for each category of reason, e.g., "insufficient cpu".
+	NumRejectedNodesByReason map[string]int
+}
+
+// String returns a summary of the rejections with the most common reasons first,
+// e.g., "7/10 nodes rejected: 4 insufficient cpu, 3 taint gpu=true:NoSchedule not tolerated".
+func (r *NodeRejections) String() string {
+	numRejectedNodes := 0
+	reasons := maps.Keys(r.NumRejectedNodesByReason)
+	for _, count := range r.NumRejectedNodesByReason {
+		numRejectedNodes += count
+	}
+	slices.SortFunc(reasons, func(a, b string) bool {
+		if countA, countB := r.NumRejectedNodesByReason[a], r.NumRejectedNodesByReason[b]; countA != countB {
+			return countA > countB
+		}
+		return a < b
+	})
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d/%d nodes rejected", numRejectedNodes, r.NumNodes)
+	for i, reason := range reasons {
+		if i == 0 {
+			sb.WriteString(": ")
+		} else {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%d %s", r.NumRejectedNodesByReason[reason], reason)
+	}
+	return sb.String()
+}
+
 func (jctx *JobSchedulingContext) IsSuccessful() bool {
-	return jctx.UnschedulableReason == ""
+	return jctx.UnschedulableReason == nil
 }
 
 func JobSchedulingContextsFromJobs[J interfaces.LegacySchedulerJob](priorityClasses map[string]types.PriorityClass, jobs []J, extractGangInfo func(map[string]string) (string, int, int, bool, error)) []*JobSchedulingContext {
@@ -638,18 +1056,31 @@ func JobSchedulingContextsFromJobs[J interfaces.LegacySchedulerJob](priorityClas
 			gangMinCardinality = 1
 		}
 
+		// Invalid annotations are rejected at submission.
+		schedulingAnnotations, _ := ParseSchedulingAnnotations(job.GetAnnotations())
 		jctxs[i] = &JobSchedulingContext{
-			Created:            timestamp,
-			JobId:              job.GetId(),
-			Job:                job,
-			PodRequirements:    job.GetPodRequirements(priorityClasses),
-			GangMinCardinality: gangMinCardinality,
-			ShouldFail:         false,
+			Created:               timestamp,
+			JobId:                 job.GetId(),
+			Job:                   job,
+			PodRequirements:       podRequirementsCopy(job.GetPodRequirements(priorityClasses)),
+			GangMinCardinality:    gangMinCardinality,
+			ShouldFail:            false,
+			SchedulingAnnotations: schedulingAnnotations,
 		}
 	}
 	return jctxs
 }
 
+// podRequirementsCopy returns a copy of the pod requirements of a job, which the job scheduling context may modify,
+// e.g., since the gang scheduler adds node selectors to the jobs of a gang while trying to schedule it.
+// Jobs are shared between executor groups scheduled concurrently, and must not be modified outside a jobDb transaction.
+func podRequirementsCopy(req *schedulerobjects.PodRequirements) *schedulerobjects.PodRequirements {
+	if req == nil {
+		return nil
+	}
+	return proto.Clone(req).(*schedulerobjects.PodRequirements)
+}
+
 // PodSchedulingContext is returned by SelectAndBindNodeToPod and
 // contains detailed information on the scheduling decision made for this pod.
 type PodSchedulingContext struct {
@@ -662,6 +1093,8 @@ type PodSchedulingContext struct {
 	// Priority class priority at which this pod was scheduled.
 	// Only set if NodeId is.
 	ScheduledAtPriority int32
+	// True if the pod only fits onto the node by preempting other jobs.
+	PreemptionRequired bool
 	// Node types on which this pod could be scheduled.
 	MatchingNodeTypes []*schedulerobjects.NodeType
 	// Total number of nodes in the cluster when trying to schedule.
@@ -675,6 +1108,7 @@ func (pctx *PodSchedulingContext) String() string {
 	w := tabwriter.NewWriter(&sb, 1, 1, 1, ' ', 0)
 	if pctx.NodeId != "" {
 		fmt.Fprintf(w, "Node:\t%s\n", pctx.NodeId)
+		fmt.Fprintf(w, "Preemption required:\t%t\n", pctx.PreemptionRequired)
 	} else {
 		fmt.Fprint(w, "Node:\tnone\n")
 	}
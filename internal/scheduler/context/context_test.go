@@ -2,6 +2,7 @@ package context
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,6 +21,7 @@ func TestNewGangSchedulingContext(t *testing.T) {
 	assert.Equal(t, jctxs, gctx.JobSchedulingContexts)
 	assert.Equal(t, "A", gctx.Queue)
 	assert.Equal(t, testfixtures.TestDefaultPriorityClass, gctx.PriorityClassName)
+	assert.True(t, gctx.Homogeneous)
 	assert.True(
 		t,
 		schedulerobjects.ResourceList{
@@ -78,6 +80,146 @@ func TestSchedulingContextAccounting(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSchedulingContext_ShouldHoldGang(t *testing.T) {
+	fairnessCostProvider, err := fairness.NewAssetFairness(map[string]float64{"cpu": 1})
+	require.NoError(t, err)
+	sctx := NewSchedulingContext(
+		"executor",
+		"pool",
+		testfixtures.TestPriorityClasses,
+		testfixtures.TestDefaultPriorityClass,
+		fairnessCostProvider,
+		nil,
+		schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("4")}},
+	)
+	allocatedByPriorityClass := schedulerobjects.QuantityByTAndResourceType[string]{
+		testfixtures.TestDefaultPriorityClass: schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("3")}},
+	}
+	require.NoError(t, sctx.AddQueueSchedulingContext("A", 1, allocatedByPriorityClass, nil))
+	gctx := NewGangSchedulingContext(testNSmallCpuJobSchedulingContext("A", testfixtures.TestDefaultPriorityClass, 2))
+	gctx.SchedulingDeadline = time.Hour
+	deadline := gctx.Submitted.Add(time.Hour)
+
+	// Gangs aren't held without a forecast.
+	assert.False(t, sctx.ShouldHoldGang(gctx, deadline.Add(time.Minute)))
+
+	// Forecast capacity minus allocated resources leaves 3 cpu, which fits the gang.
+	sctx.CapacityForecast = &CapacityForecast{
+		Capacity:           schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("6"), "memory": resource.MustParse("1Ti")}},
+		MinGangCardinality: 2,
+		MaxHoldDuration:    10 * time.Minute,
+	}
+	assert.True(t, sctx.ShouldHoldGang(gctx, deadline.Add(time.Minute)))
+
+	// Gangs are held for at most MaxHoldDuration past their deadline.
+	assert.False(t, sctx.ShouldHoldGang(gctx, deadline.Add(11*time.Minute)))
+
+	// Small gangs aren't held.
+	sctx.CapacityForecast.MinGangCardinality = 3
+	assert.False(t, sctx.ShouldHoldGang(gctx, deadline.Add(time.Minute)))
+	sctx.CapacityForecast.MinGangCardinality = 2
+
+	// Gangs that don't fit within the forecast capacity aren't held.
+	sctx.CapacityForecast.Capacity = schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("4"), "memory": resource.MustParse("1Ti")}}
+	assert.False(t, sctx.ShouldHoldGang(gctx, deadline.Add(time.Minute)))
+}
+
+func TestQueueSchedulingContext_MaxUnsuccessfulJobSchedulingContexts(t *testing.T) {
+	sctx := NewSchedulingContext(
+		"executor",
+		"pool",
+		testfixtures.TestPriorityClasses,
+		testfixtures.TestDefaultPriorityClass,
+		nil,
+		nil,
+		schedulerobjects.ResourceList{},
+	)
+	sctx.MaxUnsuccessfulJobSchedulingContextsPerQueue = 3
+	require.NoError(t, sctx.AddQueueSchedulingContext("A", 1, nil, nil))
+	qctx := sctx.QueueSchedulingContexts["A"]
+
+	addUnsuccessful := func(code schedulerobjects.UnschedulableReasonCode, reason string, n int) {
+		for _, jctx := range testNSmallCpuJobSchedulingContext("A", testfixtures.TestDefaultPriorityClass, n) {
+			jctx.UnschedulableReason = schedulerobjects.NewUnschedulableReason(code, reason)
+			_, err := qctx.AddJobSchedulingContext(jctx)
+			require.NoError(t, err)
+		}
+	}
+	countRetainedByReason := func() map[string]int {
+		rv := make(map[string]int)
+		for _, jctx := range qctx.UnsuccessfulJobSchedulingContexts {
+			rv[jctx.UnschedulableReason.Error()]++
+		}
+		return rv
+	}
+
+	// Contexts are retained up to the limit and counted beyond it.
+	addUnsuccessful(schedulerobjects.UnschedulableReasonCodeJobDoesNotFit, "foo", 5)
+	assert.Equal(t, map[string]int{"foo": 3}, countRetainedByReason())
+	assert.Equal(t, map[string]int{"foo": 2}, qctx.NumDiscardedUnsuccessfulJobsByReason)
+	assert.Equal(t, 5, qctx.NumUnsuccessfulJobs())
+
+	// Contexts with a new reason replace contexts of the reason with the most retained contexts.
+	addUnsuccessful(schedulerobjects.UnschedulableReasonCodeUnknown, "bar", 2)
+	assert.Equal(t, map[string]int{"foo": 2, "bar": 1}, countRetainedByReason())
+	assert.Equal(t, map[string]int{"foo": 3, "bar": 1}, qctx.NumDiscardedUnsuccessfulJobsByReason)
+	assert.Equal(
+		t,
+		map[schedulerobjects.UnschedulableReasonCode]int{
+			schedulerobjects.UnschedulableReasonCodeJobDoesNotFit: 3,
+			schedulerobjects.UnschedulableReasonCodeUnknown:       1,
+		},
+		qctx.NumDiscardedUnsuccessfulJobsByReasonCode,
+	)
+	assert.Equal(t, 7, qctx.NumUnsuccessfulJobs())
+	assert.Contains(t, qctx.ReportString(0), "5: foo")
+}
+
+func TestQueueSchedulingContext_ReaddUnsuccessfulJobSchedulingContext(t *testing.T) {
+	sctx := NewSchedulingContext(
+		"executor",
+		"pool",
+		testfixtures.TestPriorityClasses,
+		testfixtures.TestDefaultPriorityClass,
+		nil,
+		nil,
+		schedulerobjects.ResourceList{},
+	)
+	sctx.MaxUnsuccessfulJobSchedulingContextsPerQueue = 3
+	require.NoError(t, sctx.AddQueueSchedulingContext("A", 1, nil, nil))
+	qctx := sctx.QueueSchedulingContexts["A"]
+	jctxs := testNSmallCpuJobSchedulingContext("A", testfixtures.TestDefaultPriorityClass, 3)
+	for _, jctx := range jctxs[:2] {
+		jctx.UnschedulableReason = schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeJobDoesNotFit, "foo")
+		qctx.addUnsuccessfulJobSchedulingContext(jctx)
+	}
+
+	// Re-adding a job under the limit replaces its context, including if its reason changed.
+	readded := &JobSchedulingContext{
+		JobId:               jctxs[0].JobId,
+		Job:                 jctxs[0].Job,
+		PodRequirements:     jctxs[0].PodRequirements,
+		GangMinCardinality:  1,
+		UnschedulableReason: schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeUnknown, "bar"),
+	}
+	qctx.addUnsuccessfulJobSchedulingContext(readded)
+	qctx.addUnsuccessfulJobSchedulingContext(readded)
+	assert.Equal(t, map[string][]string{"foo": {jctxs[1].JobId}, "bar": {jctxs[0].JobId}}, qctx.retainedUnsuccessfulJobIdsByReason)
+	assert.Equal(t, readded, qctx.UnsuccessfulJobSchedulingContexts[jctxs[0].JobId])
+	assert.Len(t, qctx.UnsuccessfulJobSchedulingContexts, 2)
+	assert.Equal(t, 2, qctx.NumUnsuccessfulJobs())
+
+	// Replacing contexts once the limit is reached only removes contexts no longer retained.
+	jctxs[2].UnschedulableReason = schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeJobDoesNotFit, "foo")
+	qctx.addUnsuccessfulJobSchedulingContext(jctxs[2])
+	baz := testSmallCpuJobSchedulingContext("A", testfixtures.TestDefaultPriorityClass)
+	baz.UnschedulableReason = schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeUnknown, "baz")
+	qctx.addUnsuccessfulJobSchedulingContext(baz)
+	assert.Equal(t, map[string][]string{"foo": {jctxs[1].JobId}, "bar": {jctxs[0].JobId}, "baz": {baz.JobId}}, qctx.retainedUnsuccessfulJobIdsByReason)
+	assert.Len(t, qctx.UnsuccessfulJobSchedulingContexts, 3)
+	assert.Equal(t, map[string]int{"foo": 1}, qctx.NumDiscardedUnsuccessfulJobsByReason)
+}
+
 func testNSmallCpuJobSchedulingContext(queue, priorityClassName string, n int) []*JobSchedulingContext {
 	rv := make([]*JobSchedulingContext, n)
 	for i := 0; i < n; i++ {
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+// ExecutorDrainRepository is an interface to be implemented by structs which store requests to drain executor clusters.
+type ExecutorDrainRepository interface {
+	// GetExecutorDrains returns all drains, including completed ones.
+	GetExecutorDrains(ctx *armadacontext.Context) ([]ExecutorDrain, error)
+	// StartExecutorDrain persists a request to drain a cluster, replacing any existing drain of that cluster.
+	StartExecutorDrain(ctx *armadacontext.Context, drain ExecutorDrain) error
+	// CancelExecutorDrain removes the drain of a cluster, such that jobs are again scheduled onto it.
+	CancelExecutorDrain(ctx *armadacontext.Context, executorId string) error
+	// MarkExecutorDrainCompleted records that no more jobs are running on a drained cluster.
+	MarkExecutorDrainCompleted(ctx *armadacontext.Context, executorId string, completed time.Time) error
+}
+
+// PostgresExecutorDrainRepository is an implementation of ExecutorDrainRepository that stores its state in postgres
+type PostgresExecutorDrainRepository struct {
+	// pool of database connections
+	db *pgxpool.Pool
+}
+
+func NewPostgresExecutorDrainRepository(db *pgxpool.Pool) *PostgresExecutorDrainRepository {
+	return &PostgresExecutorDrainRepository{db: db}
+}
+
+func (r *PostgresExecutorDrainRepository) GetExecutorDrains(ctx *armadacontext.Context) ([]ExecutorDrain, error) {
+	drains, err := New(r.db).SelectAllExecutorDrains(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range drains {
+		// pgx defaults to local time so we convert to utc here
+		drains[i].Started = drains[i].Started.UTC()
+		drains[i].Deadline = utcOrNil(drains[i].Deadline)
+		drains[i].Completed = utcOrNil(drains[i].Completed)
+	}
+	return drains, nil
+}
+
+func (r *PostgresExecutorDrainRepository) StartExecutorDrain(ctx *armadacontext.Context, drain ExecutorDrain) error {
+	err := New(r.db).UpsertExecutorDrain(ctx, UpsertExecutorDrainParams{
+		ExecutorID:         drain.ExecutorID,
+		MigratePreemptible: drain.MigratePreemptible,
+		Started:            drain.Started,
+		Deadline:           drain.Deadline,
+	})
+	return errors.WithStack(err)
+}
+
+func (r *PostgresExecutorDrainRepository) CancelExecutorDrain(ctx *armadacontext.Context, executorId string) error {
+	return errors.WithStack(New(r.db).DeleteExecutorDrain(ctx, executorId))
+}
+
+func (r *PostgresExecutorDrainRepository) MarkExecutorDrainCompleted(ctx *armadacontext.Context, executorId string, completed time.Time) error {
+	err := New(r.db).MarkExecutorDrainCompleted(ctx, MarkExecutorDrainCompletedParams{
+		Completed:  &completed,
+		ExecutorID: executorId,
+	})
+	return errors.WithStack(err)
+}
+
+func utcOrNil(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
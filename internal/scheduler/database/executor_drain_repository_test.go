@@ -0,0 +1,47 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+func TestExecutorDrainRepository(t *testing.T) {
+	t1 := time.Now().UTC().Round(1 * time.Microsecond) // postgres only stores times with micro precision
+	deadline := t1.Add(time.Hour)
+	completed := t1.Add(time.Minute)
+	err := withExecutorDrainRepository(func(repo *PostgresExecutorDrainRepository) error {
+		ctx, cancel := armadacontext.WithTimeout(armadacontext.Background(), 5*time.Second)
+		defer cancel()
+
+		drain := ExecutorDrain{ExecutorID: "test-executor-1", MigratePreemptible: true, Started: t1, Deadline: &deadline}
+		require.NoError(t, repo.StartExecutorDrain(ctx, drain))
+		require.NoError(t, repo.StartExecutorDrain(ctx, ExecutorDrain{ExecutorID: "test-executor-2", Started: t1}))
+		require.NoError(t, repo.MarkExecutorDrainCompleted(ctx, "test-executor-1", completed))
+		require.NoError(t, repo.CancelExecutorDrain(ctx, "test-executor-2"))
+
+		drains, err := repo.GetExecutorDrains(ctx)
+		require.NoError(t, err)
+		drain.Completed = &completed
+		assert.Equal(t, []ExecutorDrain{drain}, drains)
+
+		// Restarting a drain resets its completion time.
+		require.NoError(t, repo.StartExecutorDrain(ctx, ExecutorDrain{ExecutorID: "test-executor-1", Started: t1}))
+		drains, err = repo.GetExecutorDrains(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []ExecutorDrain{{ExecutorID: "test-executor-1", Started: t1}}, drains)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func withExecutorDrainRepository(action func(repository *PostgresExecutorDrainRepository) error) error {
+	return WithTestDb(func(_ *Queries, db *pgxpool.Pool) error {
+		return action(NewPostgresExecutorDrainRepository(db))
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/armadaproject/armada/internal/common/armadacontext"
 	"github.com/armadaproject/armada/internal/common/compress"
 	"github.com/armadaproject/armada/internal/common/database"
+	"github.com/armadaproject/armada/internal/common/encryption"
 	protoutil "github.com/armadaproject/armada/internal/common/proto"
 	armadaslices "github.com/armadaproject/armada/internal/common/slices"
 	"github.com/armadaproject/armada/pkg/armadaevents"
@@ -53,6 +54,10 @@ type JobRepository interface {
 	// FetchJobRunLeases fetches new job runs for a given executor.  A maximum of maxResults rows will be returned, while run
 	// in excludedRunIds will be excluded
 	FetchJobRunLeases(ctx *armadacontext.Context, executor string, maxResults uint, excludedRunIds []uuid.UUID) ([]*JobRunLease, error)
+
+	// FindLatestRunNodes returns a map from job id to the id of the node the most recent run of that job was leased on.
+	// Jobs that don't exist or have never been leased are absent from the map.
+	FindLatestRunNodes(ctx *armadacontext.Context, jobIds []string) (map[string]string, error)
 }
 
 // PostgresJobRepository is an implementation of JobRepository that stores its state in postgres
@@ -61,6 +66,8 @@ type PostgresJobRepository struct {
 	db *pgxpool.Pool
 	// maximum number of rows to fetch from postgres in a single query
 	batchSize int32
+	// used to decrypt data encrypted by the scheduler ingester; may be nil
+	envelope *encryption.Envelope
 }
 
 func NewPostgresJobRepository(db *pgxpool.Pool, batchSize int32) *PostgresJobRepository {
@@ -70,6 +77,11 @@ func NewPostgresJobRepository(db *pgxpool.Pool, batchSize int32) *PostgresJobRep
 	}
 }
 
+// SetEncryption sets the envelope used to decrypt data encrypted at rest by the scheduler ingester.
+func (r *PostgresJobRepository) SetEncryption(envelope *encryption.Envelope) {
+	r.envelope = envelope
+}
+
 // FetchJobRunErrors returns all armadaevents.JobRunErrors for the provided job run ids.  The returned map is
 // keyed by job run id.  Any dbRuns which don't have errors wil be absent from the map.
 func (r *PostgresJobRepository) FetchJobRunErrors(ctx *armadacontext.Context, runIds []uuid.UUID) (map[uuid.UUID]*armadaevents.Error, error) {
@@ -80,9 +92,13 @@ func (r *PostgresJobRepository) FetchJobRunErrors(ctx *armadacontext.Context, ru
 	chunks := armadaslices.PartitionToMaxLen(runIds, int(r.batchSize))
 
 	errorsByRunId := make(map[uuid.UUID]*armadaevents.Error, len(runIds))
-	decompressor := compress.NewZlibDecompressor()
+	codecDecompressor, err := compress.NewDecompressor()
+	if err != nil {
+		return nil, err
+	}
+	decompressor := encryption.NewDecryptingDecompressor(codecDecompressor, r.envelope)
 
-	err := pgx.BeginTxFunc(ctx, r.db, pgx.TxOptions{
+	err = pgx.BeginTxFunc(ctx, r.db, pgx.TxOptions{
 		IsoLevel:       pgx.ReadCommitted,
 		AccessMode:     pgx.ReadWrite,
 		DeferrableMode: pgx.Deferrable,
@@ -160,6 +176,7 @@ func (r *PostgresJobRepository) FetchJobUpdates(ctx *armadacontext.Context, jobS
 				SchedulingInfo:          row.SchedulingInfo,
 				SchedulingInfoVersion:   row.SchedulingInfoVersion,
 				Serial:                  row.Serial,
+				Ingested:                row.Ingested,
 			}
 		}
 
@@ -219,6 +236,22 @@ func (r *PostgresJobRepository) FindInactiveRuns(ctx *armadacontext.Context, run
 	return inactiveRuns, err
 }
 
+// FindLatestRunNodes returns a map from job id to the id of the node the most recent run of that job was leased on.
+// Jobs that don't exist or have never been leased are absent from the map.
+func (r *PostgresJobRepository) FindLatestRunNodes(ctx *armadacontext.Context, jobIds []string) (map[string]string, error) {
+	rows, err := New(r.db).SelectLatestRunNodes(ctx, jobIds)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	nodesByJobId := make(map[string]string, len(rows))
+	for _, row := range rows {
+		if row.Node != "" {
+			nodesByJobId[row.JobID] = row.Node
+		}
+	}
+	return nodesByJobId, nil
+}
+
 // FetchJobRunLeases fetches new job runs for a given executor.  A maximum of maxResults rows will be returned, while run
 // in excludedRunIds will be excluded
 func (r *PostgresJobRepository) FetchJobRunLeases(ctx *armadacontext.Context, executor string, maxResults uint, excludedRunIds []uuid.UUID) ([]*JobRunLease, error) {
@@ -377,6 +377,26 @@ func TestFindInactiveRuns(t *testing.T) {
 	}
 }
 
+func TestFindLatestRunNodes(t *testing.T) {
+	dbRuns := []Run{
+		{RunID: uuid.New(), JobID: "job-1", Node: "node-1", Created: 1},
+		{RunID: uuid.New(), JobID: "job-1", Node: "node-2", Created: 2},
+		{RunID: uuid.New(), JobID: "job-2", Node: "node-3", Created: 1},
+		{RunID: uuid.New(), JobID: "job-3", Node: "node-4", Created: 1},
+	}
+	err := withJobRepository(func(repo *PostgresJobRepository) error {
+		ctx, cancel := armadacontext.WithTimeout(armadacontext.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, database.UpsertWithTransaction(ctx, repo.db, "runs", dbRuns))
+
+		nodesByJobId, err := repo.FindLatestRunNodes(ctx, []string{"job-1", "job-2", "job-5"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"job-1": "node-2", "job-2": "node-3"}, nodesByJobId)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestFetchJobRunLeases(t *testing.T) {
 	const executorName = "testExecutor"
 	dbJobs, _ := createTestJobs(5)
@@ -0,0 +1,12 @@
+CREATE TABLE executor_drains (
+    -- the executor whose cluster is being drained
+    executor_id text PRIMARY KEY,
+    -- if true, preemptible jobs running on the cluster are returned to the queue to be scheduled elsewhere
+    migrate_preemptible boolean NOT NULL,
+    -- the time at which the drain was requested
+    started timestamptz NOT NULL,
+    -- jobs still running on the cluster at this time are preempted; if null, the drain waits for jobs to finish
+    deadline timestamptz,
+    -- the time at which no more jobs were running on the cluster
+    completed timestamptz
+);
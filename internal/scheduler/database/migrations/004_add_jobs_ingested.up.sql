@@ -0,0 +1,2 @@
+-- the time at which the job was ingested into this database; null for jobs ingested before this column was added
+ALTER TABLE jobs ADD COLUMN ingested timestamptz;
@@ -0,0 +1,13 @@
+CREATE TABLE scheduling_exclusions (
+    -- the queue whose jobs aren't scheduled
+    queue text NOT NULL,
+    -- if non-empty, only jobs of this job set are excluded; otherwise, all jobs of the queue are
+    job_set text NOT NULL,
+    -- why the jobs were excluded, e.g., a link to an incident
+    reason text NOT NULL,
+    -- the time at which the exclusion was requested
+    created timestamptz NOT NULL,
+    -- the time after which the exclusion no longer applies; if null, it applies until removed
+    expires timestamptz,
+    PRIMARY KEY (queue, job_set)
+);
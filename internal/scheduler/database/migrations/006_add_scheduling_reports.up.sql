@@ -0,0 +1,13 @@
+CREATE TABLE scheduling_reports (
+    serial bigserial PRIMARY KEY,
+    -- the executor, or executor group, scheduled in the round the report is for
+    executor_id text NOT NULL,
+    -- the queue the report is for; empty for reports for the round as a whole
+    queue text NOT NULL,
+    -- the time at which the round started
+    created timestamptz NOT NULL,
+    -- the report, rendered as returned by the scheduling reports API
+    report text NOT NULL
+);
+CREATE INDEX idx_scheduling_reports_queue_executor_id_created ON scheduling_reports (queue, executor_id, created);
+CREATE INDEX idx_scheduling_reports_created ON scheduling_reports (created);
@@ -0,0 +1,8 @@
+CREATE TABLE scheduler_state (
+    -- the component of the scheduler the state is of, e.g., preemption_compensation
+    name text PRIMARY KEY,
+    -- the state, serialised by the component it's of
+    state bytea NOT NULL,
+    -- the time at which the state was persisted
+    last_modified timestamptz NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE runs ADD COLUMN preempted boolean NOT NULL DEFAULT false;
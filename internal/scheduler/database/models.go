@@ -16,26 +16,35 @@ type Executor struct {
 	LastUpdated time.Time `db:"last_updated"`
 }
 
+type ExecutorDrain struct {
+	ExecutorID         string     `db:"executor_id"`
+	MigratePreemptible bool       `db:"migrate_preemptible"`
+	Started            time.Time  `db:"started"`
+	Deadline           *time.Time `db:"deadline"`
+	Completed          *time.Time `db:"completed"`
+}
+
 type Job struct {
-	JobID                   string    `db:"job_id"`
-	JobSet                  string    `db:"job_set"`
-	Queue                   string    `db:"queue"`
-	UserID                  string    `db:"user_id"`
-	Submitted               int64     `db:"submitted"`
-	Groups                  []byte    `db:"groups"`
-	Priority                int64     `db:"priority"`
-	Queued                  bool      `db:"queued"`
-	QueuedVersion           int32     `db:"queued_version"`
-	CancelRequested         bool      `db:"cancel_requested"`
-	Cancelled               bool      `db:"cancelled"`
-	CancelByJobsetRequested bool      `db:"cancel_by_jobset_requested"`
-	Succeeded               bool      `db:"succeeded"`
-	Failed                  bool      `db:"failed"`
-	SubmitMessage           []byte    `db:"submit_message"`
-	SchedulingInfo          []byte    `db:"scheduling_info"`
-	SchedulingInfoVersion   int32     `db:"scheduling_info_version"`
-	Serial                  int64     `db:"serial"`
-	LastModified            time.Time `db:"last_modified"`
+	JobID                   string     `db:"job_id"`
+	JobSet                  string     `db:"job_set"`
+	Queue                   string     `db:"queue"`
+	UserID                  string     `db:"user_id"`
+	Submitted               int64      `db:"submitted"`
+	Groups                  []byte     `db:"groups"`
+	Priority                int64      `db:"priority"`
+	Queued                  bool       `db:"queued"`
+	QueuedVersion           int32      `db:"queued_version"`
+	CancelRequested         bool       `db:"cancel_requested"`
+	Cancelled               bool       `db:"cancelled"`
+	CancelByJobsetRequested bool       `db:"cancel_by_jobset_requested"`
+	Succeeded               bool       `db:"succeeded"`
+	Failed                  bool       `db:"failed"`
+	SubmitMessage           []byte     `db:"submit_message"`
+	SchedulingInfo          []byte     `db:"scheduling_info"`
+	SchedulingInfoVersion   int32      `db:"scheduling_info_version"`
+	Serial                  int64      `db:"serial"`
+	LastModified            time.Time  `db:"last_modified"`
+	Ingested                *time.Time `db:"ingested"`
 }
 
 type JobRunError struct {
@@ -74,4 +83,27 @@ type Run struct {
 	PendingTimestamp    *time.Time `db:"pending_timestamp"`
 	RunningTimestamp    *time.Time `db:"running_timestamp"`
 	TerminatedTimestamp *time.Time `db:"terminated_timestamp"`
+	Preempted           bool       `db:"preempted"`
+}
+
+type SchedulerState struct {
+	Name         string    `db:"name"`
+	State        []byte    `db:"state"`
+	LastModified time.Time `db:"last_modified"`
+}
+
+type SchedulingExclusion struct {
+	Queue   string     `db:"queue"`
+	JobSet  string     `db:"job_set"`
+	Reason  string     `db:"reason"`
+	Created time.Time  `db:"created"`
+	Expires *time.Time `db:"expires"`
+}
+
+type SchedulingReport struct {
+	Serial     int64     `db:"serial"`
+	ExecutorID string    `db:"executor_id"`
+	Queue      string    `db:"queue"`
+	Created    time.Time `db:"created"`
+	Report     string    `db:"report"`
 }
@@ -23,6 +23,15 @@ func (q *Queries) CountGroup(ctx context.Context, groupID uuid.UUID) (int64, err
 	return count, err
 }
 
+const deleteExecutorDrain = `-- name: DeleteExecutorDrain :exec
+DELETE FROM executor_drains WHERE executor_id = $1
+`
+
+func (q *Queries) DeleteExecutorDrain(ctx context.Context, executorID string) error {
+	_, err := q.db.Exec(ctx, deleteExecutorDrain, executorID)
+	return err
+}
+
 const deleteOldMarkers = `-- name: DeleteOldMarkers :exec
 DELETE FROM markers WHERE created < $1::timestamptz
 `
@@ -32,6 +41,29 @@ func (q *Queries) DeleteOldMarkers(ctx context.Context, cutoff time.Time) error
 	return err
 }
 
+const deleteSchedulingExclusion = `-- name: DeleteSchedulingExclusion :exec
+DELETE FROM scheduling_exclusions WHERE queue = $1 AND job_set = $2
+`
+
+type DeleteSchedulingExclusionParams struct {
+	Queue  string `db:"queue"`
+	JobSet string `db:"job_set"`
+}
+
+func (q *Queries) DeleteSchedulingExclusion(ctx context.Context, arg DeleteSchedulingExclusionParams) error {
+	_, err := q.db.Exec(ctx, deleteSchedulingExclusion, arg.Queue, arg.JobSet)
+	return err
+}
+
+const deleteSchedulingReportsBefore = `-- name: DeleteSchedulingReportsBefore :exec
+DELETE FROM scheduling_reports WHERE created < $1::timestamptz
+`
+
+func (q *Queries) DeleteSchedulingReportsBefore(ctx context.Context, cutoff time.Time) error {
+	_, err := q.db.Exec(ctx, deleteSchedulingReportsBefore, cutoff)
+	return err
+}
+
 const findActiveRuns = `-- name: FindActiveRuns :many
 SELECT run_id FROM runs WHERE run_id = ANY($1::UUID[])
                          AND (succeeded = false AND failed = false AND cancelled = false)
@@ -72,6 +104,41 @@ func (q *Queries) InsertMarker(ctx context.Context, arg InsertMarkerParams) erro
 	return err
 }
 
+const insertSchedulingReport = `-- name: InsertSchedulingReport :exec
+INSERT INTO scheduling_reports (executor_id, queue, created, report) VALUES ($1, $2, $3, $4)
+`
+
+type InsertSchedulingReportParams struct {
+	ExecutorID string    `db:"executor_id"`
+	Queue      string    `db:"queue"`
+	Created    time.Time `db:"created"`
+	Report     string    `db:"report"`
+}
+
+func (q *Queries) InsertSchedulingReport(ctx context.Context, arg InsertSchedulingReportParams) error {
+	_, err := q.db.Exec(ctx, insertSchedulingReport,
+		arg.ExecutorID,
+		arg.Queue,
+		arg.Created,
+		arg.Report,
+	)
+	return err
+}
+
+const markExecutorDrainCompleted = `-- name: MarkExecutorDrainCompleted :exec
+UPDATE executor_drains SET completed = $1 WHERE executor_id = $2
+`
+
+type MarkExecutorDrainCompletedParams struct {
+	Completed  *time.Time `db:"completed"`
+	ExecutorID string     `db:"executor_id"`
+}
+
+func (q *Queries) MarkExecutorDrainCompleted(ctx context.Context, arg MarkExecutorDrainCompletedParams) error {
+	_, err := q.db.Exec(ctx, markExecutorDrainCompleted, arg.Completed, arg.ExecutorID)
+	return err
+}
+
 const markJobRunsAttemptedById = `-- name: MarkJobRunsAttemptedById :exec
 UPDATE runs SET run_attempted = true WHERE run_id = ANY($1::UUID[])
 `
@@ -90,6 +157,15 @@ func (q *Queries) MarkJobRunsFailedById(ctx context.Context, runIds []uuid.UUID)
 	return err
 }
 
+const markJobRunsPreemptedById = `-- name: MarkJobRunsPreemptedById :exec
+UPDATE runs SET preempted = true WHERE run_id = ANY($1::UUID[])
+`
+
+func (q *Queries) MarkJobRunsPreemptedById(ctx context.Context, runIds []uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markJobRunsPreemptedById, runIds)
+	return err
+}
+
 const markJobRunsReturnedById = `-- name: MarkJobRunsReturnedById :exec
 UPDATE runs SET returned = true WHERE run_id = ANY($1::UUID[])
 `
@@ -177,6 +253,36 @@ func (q *Queries) MarkRunsCancelledByJobId(ctx context.Context, jobIds []string)
 	return err
 }
 
+const selectAllExecutorDrains = `-- name: SelectAllExecutorDrains :many
+SELECT executor_id, migrate_preemptible, started, deadline, completed FROM executor_drains
+`
+
+func (q *Queries) SelectAllExecutorDrains(ctx context.Context) ([]ExecutorDrain, error) {
+	rows, err := q.db.Query(ctx, selectAllExecutorDrains)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExecutorDrain
+	for rows.Next() {
+		var i ExecutorDrain
+		if err := rows.Scan(
+			&i.ExecutorID,
+			&i.MigratePreemptible,
+			&i.Started,
+			&i.Deadline,
+			&i.Completed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectAllExecutors = `-- name: SelectAllExecutors :many
 SELECT executor_id, last_request, last_updated FROM executors
 `
@@ -297,6 +403,36 @@ func (q *Queries) SelectAllRunIds(ctx context.Context) ([]uuid.UUID, error) {
 	return items, nil
 }
 
+const selectAllSchedulingExclusions = `-- name: SelectAllSchedulingExclusions :many
+SELECT queue, job_set, reason, created, expires FROM scheduling_exclusions
+`
+
+func (q *Queries) SelectAllSchedulingExclusions(ctx context.Context) ([]SchedulingExclusion, error) {
+	rows, err := q.db.Query(ctx, selectAllSchedulingExclusions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SchedulingExclusion
+	for rows.Next() {
+		var i SchedulingExclusion
+		if err := rows.Scan(
+			&i.Queue,
+			&i.JobSet,
+			&i.Reason,
+			&i.Created,
+			&i.Expires,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectExecutorUpdateTimes = `-- name: SelectExecutorUpdateTimes :many
 SELECT executor_id, last_updated FROM executors
 `
@@ -377,8 +513,67 @@ func (q *Queries) SelectJobsForExecutor(ctx context.Context, arg SelectJobsForEx
 	return items, nil
 }
 
+const selectLatestRunNodes = `-- name: SelectLatestRunNodes :many
+SELECT DISTINCT ON (job_id) job_id, node FROM runs WHERE job_id = ANY($1::text[]) ORDER BY job_id, created DESC
+`
+
+type SelectLatestRunNodesRow struct {
+	JobID string `db:"job_id"`
+	Node  string `db:"node"`
+}
+
+func (q *Queries) SelectLatestRunNodes(ctx context.Context, jobIds []string) ([]SelectLatestRunNodesRow, error) {
+	rows, err := q.db.Query(ctx, selectLatestRunNodes, jobIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectLatestRunNodesRow
+	for rows.Next() {
+		var i SelectLatestRunNodesRow
+		if err := rows.Scan(&i.JobID, &i.Node); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectLatestSchedulingReports = `-- name: SelectLatestSchedulingReports :many
+SELECT DISTINCT ON (executor_id) serial, executor_id, queue, created, report FROM scheduling_reports WHERE queue = $1 ORDER BY executor_id, created DESC
+`
+
+func (q *Queries) SelectLatestSchedulingReports(ctx context.Context, queue string) ([]SchedulingReport, error) {
+	rows, err := q.db.Query(ctx, selectLatestSchedulingReports, queue)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SchedulingReport
+	for rows.Next() {
+		var i SchedulingReport
+		if err := rows.Scan(
+			&i.Serial,
+			&i.ExecutorID,
+			&i.Queue,
+			&i.Created,
+			&i.Report,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectNewJobs = `-- name: SelectNewJobs :many
-SELECT job_id, job_set, queue, user_id, submitted, groups, priority, queued, queued_version, cancel_requested, cancelled, cancel_by_jobset_requested, succeeded, failed, submit_message, scheduling_info, scheduling_info_version, serial, last_modified FROM jobs WHERE serial > $1 ORDER BY serial LIMIT $2
+SELECT job_id, job_set, queue, user_id, submitted, groups, priority, queued, queued_version, cancel_requested, cancelled, cancel_by_jobset_requested, succeeded, failed, submit_message, scheduling_info, scheduling_info_version, serial, last_modified, ingested FROM jobs WHERE serial > $1 ORDER BY serial LIMIT $2
 `
 
 type SelectNewJobsParams struct {
@@ -415,6 +610,7 @@ func (q *Queries) SelectNewJobs(ctx context.Context, arg SelectNewJobsParams) ([
 			&i.SchedulingInfoVersion,
 			&i.Serial,
 			&i.LastModified,
+			&i.Ingested,
 		); err != nil {
 			return nil, err
 		}
@@ -427,7 +623,7 @@ func (q *Queries) SelectNewJobs(ctx context.Context, arg SelectNewJobsParams) ([
 }
 
 const selectNewRuns = `-- name: SelectNewRuns :many
-SELECT run_id, job_id, created, job_set, executor, node, cancelled, running, succeeded, failed, returned, run_attempted, serial, last_modified, leased_timestamp, pending_timestamp, running_timestamp, terminated_timestamp FROM runs WHERE serial > $1 ORDER BY serial LIMIT $2
+SELECT run_id, job_id, created, job_set, executor, node, cancelled, running, succeeded, failed, returned, run_attempted, serial, last_modified, leased_timestamp, pending_timestamp, running_timestamp, terminated_timestamp, preempted FROM runs WHERE serial > $1 ORDER BY serial LIMIT $2
 `
 
 type SelectNewRunsParams struct {
@@ -463,6 +659,7 @@ func (q *Queries) SelectNewRuns(ctx context.Context, arg SelectNewRunsParams) ([
 			&i.PendingTimestamp,
 			&i.RunningTimestamp,
 			&i.TerminatedTimestamp,
+			&i.Preempted,
 		); err != nil {
 			return nil, err
 		}
@@ -475,7 +672,7 @@ func (q *Queries) SelectNewRuns(ctx context.Context, arg SelectNewRunsParams) ([
 }
 
 const selectNewRunsForJobs = `-- name: SelectNewRunsForJobs :many
-SELECT run_id, job_id, created, job_set, executor, node, cancelled, running, succeeded, failed, returned, run_attempted, serial, last_modified, leased_timestamp, pending_timestamp, running_timestamp, terminated_timestamp FROM runs WHERE serial > $1 AND job_id = ANY($2::text[]) ORDER BY serial
+SELECT run_id, job_id, created, job_set, executor, node, cancelled, running, succeeded, failed, returned, run_attempted, serial, last_modified, leased_timestamp, pending_timestamp, running_timestamp, terminated_timestamp, preempted FROM runs WHERE serial > $1 AND job_id = ANY($2::text[]) ORDER BY serial
 `
 
 type SelectNewRunsForJobsParams struct {
@@ -511,6 +708,7 @@ func (q *Queries) SelectNewRunsForJobs(ctx context.Context, arg SelectNewRunsFor
 			&i.PendingTimestamp,
 			&i.RunningTimestamp,
 			&i.TerminatedTimestamp,
+			&i.Preempted,
 		); err != nil {
 			return nil, err
 		}
@@ -547,8 +745,32 @@ func (q *Queries) SelectRunErrorsById(ctx context.Context, runIds []uuid.UUID) (
 	return items, nil
 }
 
+const selectSchedulerState = `-- name: SelectSchedulerState :many
+SELECT name, state, last_modified FROM scheduler_state WHERE name = $1
+`
+
+func (q *Queries) SelectSchedulerState(ctx context.Context, name string) ([]SchedulerState, error) {
+	rows, err := q.db.Query(ctx, selectSchedulerState, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SchedulerState
+	for rows.Next() {
+		var i SchedulerState
+		if err := rows.Scan(&i.Name, &i.State, &i.LastModified); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectUpdatedJobs = `-- name: SelectUpdatedJobs :many
-SELECT job_id, job_set, queue, priority, submitted, queued, queued_version, cancel_requested, cancel_by_jobset_requested, cancelled, succeeded, failed, scheduling_info, scheduling_info_version, serial FROM jobs WHERE serial > $1 ORDER BY serial LIMIT $2
+SELECT job_id, job_set, queue, priority, submitted, queued, queued_version, cancel_requested, cancel_by_jobset_requested, cancelled, succeeded, failed, scheduling_info, scheduling_info_version, serial, ingested FROM jobs WHERE serial > $1 ORDER BY serial LIMIT $2
 `
 
 type SelectUpdatedJobsParams struct {
@@ -557,21 +779,22 @@ type SelectUpdatedJobsParams struct {
 }
 
 type SelectUpdatedJobsRow struct {
-	JobID                   string `db:"job_id"`
-	JobSet                  string `db:"job_set"`
-	Queue                   string `db:"queue"`
-	Priority                int64  `db:"priority"`
-	Submitted               int64  `db:"submitted"`
-	Queued                  bool   `db:"queued"`
-	QueuedVersion           int32  `db:"queued_version"`
-	CancelRequested         bool   `db:"cancel_requested"`
-	CancelByJobsetRequested bool   `db:"cancel_by_jobset_requested"`
-	Cancelled               bool   `db:"cancelled"`
-	Succeeded               bool   `db:"succeeded"`
-	Failed                  bool   `db:"failed"`
-	SchedulingInfo          []byte `db:"scheduling_info"`
-	SchedulingInfoVersion   int32  `db:"scheduling_info_version"`
-	Serial                  int64  `db:"serial"`
+	JobID                   string     `db:"job_id"`
+	JobSet                  string     `db:"job_set"`
+	Queue                   string     `db:"queue"`
+	Priority                int64      `db:"priority"`
+	Submitted               int64      `db:"submitted"`
+	Queued                  bool       `db:"queued"`
+	QueuedVersion           int32      `db:"queued_version"`
+	CancelRequested         bool       `db:"cancel_requested"`
+	CancelByJobsetRequested bool       `db:"cancel_by_jobset_requested"`
+	Cancelled               bool       `db:"cancelled"`
+	Succeeded               bool       `db:"succeeded"`
+	Failed                  bool       `db:"failed"`
+	SchedulingInfo          []byte     `db:"scheduling_info"`
+	SchedulingInfoVersion   int32      `db:"scheduling_info_version"`
+	Serial                  int64      `db:"serial"`
+	Ingested                *time.Time `db:"ingested"`
 }
 
 func (q *Queries) SelectUpdatedJobs(ctx context.Context, arg SelectUpdatedJobsParams) ([]SelectUpdatedJobsRow, error) {
@@ -599,6 +822,7 @@ func (q *Queries) SelectUpdatedJobs(ctx context.Context, arg SelectUpdatedJobsPa
 			&i.SchedulingInfo,
 			&i.SchedulingInfoVersion,
 			&i.Serial,
+			&i.Ingested,
 		); err != nil {
 			return nil, err
 		}
@@ -711,3 +935,67 @@ func (q *Queries) UpsertExecutor(ctx context.Context, arg UpsertExecutorParams)
 	_, err := q.db.Exec(ctx, upsertExecutor, arg.ExecutorID, arg.LastRequest, arg.UpdateTime)
 	return err
 }
+
+const upsertExecutorDrain = `-- name: UpsertExecutorDrain :exec
+INSERT INTO executor_drains (executor_id, migrate_preemptible, started, deadline, completed)
+VALUES($1, $2, $3, $4, NULL)
+ON CONFLICT (executor_id) DO UPDATE SET (migrate_preemptible, started, deadline, completed) = (excluded.migrate_preemptible, excluded.started, excluded.deadline, NULL)
+`
+
+type UpsertExecutorDrainParams struct {
+	ExecutorID         string     `db:"executor_id"`
+	MigratePreemptible bool       `db:"migrate_preemptible"`
+	Started            time.Time  `db:"started"`
+	Deadline           *time.Time `db:"deadline"`
+}
+
+func (q *Queries) UpsertExecutorDrain(ctx context.Context, arg UpsertExecutorDrainParams) error {
+	_, err := q.db.Exec(ctx, upsertExecutorDrain,
+		arg.ExecutorID,
+		arg.MigratePreemptible,
+		arg.Started,
+		arg.Deadline,
+	)
+	return err
+}
+
+const upsertSchedulerState = `-- name: UpsertSchedulerState :exec
+INSERT INTO scheduler_state (name, state, last_modified) VALUES ($1, $2, $3)
+ON CONFLICT (name) DO UPDATE SET (state, last_modified) = (excluded.state, excluded.last_modified)
+`
+
+type UpsertSchedulerStateParams struct {
+	Name         string    `db:"name"`
+	State        []byte    `db:"state"`
+	LastModified time.Time `db:"last_modified"`
+}
+
+func (q *Queries) UpsertSchedulerState(ctx context.Context, arg UpsertSchedulerStateParams) error {
+	_, err := q.db.Exec(ctx, upsertSchedulerState, arg.Name, arg.State, arg.LastModified)
+	return err
+}
+
+const upsertSchedulingExclusion = `-- name: UpsertSchedulingExclusion :exec
+INSERT INTO scheduling_exclusions (queue, job_set, reason, created, expires)
+VALUES($1, $2, $3, $4, $5)
+ON CONFLICT (queue, job_set) DO UPDATE SET (reason, created, expires) = (excluded.reason, excluded.created, excluded.expires)
+`
+
+type UpsertSchedulingExclusionParams struct {
+	Queue   string     `db:"queue"`
+	JobSet  string     `db:"job_set"`
+	Reason  string     `db:"reason"`
+	Created time.Time  `db:"created"`
+	Expires *time.Time `db:"expires"`
+}
+
+func (q *Queries) UpsertSchedulingExclusion(ctx context.Context, arg UpsertSchedulingExclusionParams) error {
+	_, err := q.db.Exec(ctx, upsertSchedulingExclusion,
+		arg.Queue,
+		arg.JobSet,
+		arg.Reason,
+		arg.Created,
+		arg.Expires,
+	)
+	return err
+}
@@ -5,7 +5,7 @@ SELECT * FROM jobs WHERE serial > $1 ORDER BY serial LIMIT $2;
 SELECT job_id FROM jobs;
 
 -- name: SelectUpdatedJobs :many
-SELECT job_id, job_set, queue, priority, submitted, queued, queued_version, cancel_requested, cancel_by_jobset_requested, cancelled, succeeded, failed, scheduling_info, scheduling_info_version, serial FROM jobs WHERE serial > $1 ORDER BY serial LIMIT $2;
+SELECT job_id, job_set, queue, priority, submitted, queued, queued_version, cancel_requested, cancel_by_jobset_requested, cancelled, succeeded, failed, scheduling_info, scheduling_info_version, serial, ingested FROM jobs WHERE serial > $1 ORDER BY serial LIMIT $2;
 
 -- name: UpdateJobPriorityByJobSet :exec
 UPDATE jobs SET priority = $1 WHERE job_set = $2 and queue = $3;
@@ -37,6 +37,9 @@ SELECT run_id FROM runs;
 -- name: SelectNewRunsForJobs :many
 SELECT * FROM runs WHERE serial > $1 AND job_id = ANY(sqlc.arg(job_ids)::text[]) ORDER BY serial;
 
+-- name: SelectLatestRunNodes :many
+SELECT DISTINCT ON (job_id) job_id, node FROM runs WHERE job_id = ANY(sqlc.arg(job_ids)::text[]) ORDER BY job_id, created DESC;
+
 -- name: MarkJobRunsSucceededById :exec
 UPDATE runs SET succeeded = true WHERE run_id = ANY(sqlc.arg(run_ids)::UUID[]);
 
@@ -49,6 +52,9 @@ UPDATE runs SET returned = true WHERE run_id = ANY(sqlc.arg(run_ids)::UUID[]);
 -- name: MarkJobRunsAttemptedById :exec
 UPDATE runs SET run_attempted = true WHERE run_id = ANY(sqlc.arg(run_ids)::UUID[]);
 
+-- name: MarkJobRunsPreemptedById :exec
+UPDATE runs SET preempted = true WHERE run_id = ANY(sqlc.arg(run_ids)::UUID[]);
+
 -- name: MarkJobRunsRunningById :exec
 UPDATE runs SET running = true WHERE run_id = ANY(sqlc.arg(run_ids)::UUID[]);
 
@@ -110,3 +116,44 @@ UPDATE runs SET running_timestamp = $1 WHERE run_id = $2;
 -- name: SetTerminatedTime :exec
 UPDATE runs SET terminated_timestamp = $1 WHERE run_id = $2;
 
+
+-- name: SelectAllExecutorDrains :many
+SELECT * FROM executor_drains;
+
+-- name: UpsertExecutorDrain :exec
+INSERT INTO executor_drains (executor_id, migrate_preemptible, started, deadline, completed)
+VALUES($1, $2, $3, $4, NULL)
+ON CONFLICT (executor_id) DO UPDATE SET (migrate_preemptible, started, deadline, completed) = (excluded.migrate_preemptible, excluded.started, excluded.deadline, NULL);
+
+-- name: DeleteExecutorDrain :exec
+DELETE FROM executor_drains WHERE executor_id = $1;
+
+-- name: MarkExecutorDrainCompleted :exec
+UPDATE executor_drains SET completed = $1 WHERE executor_id = $2;
+
+-- name: SelectAllSchedulingExclusions :many
+SELECT * FROM scheduling_exclusions;
+
+-- name: UpsertSchedulingExclusion :exec
+INSERT INTO scheduling_exclusions (queue, job_set, reason, created, expires)
+VALUES($1, $2, $3, $4, $5)
+ON CONFLICT (queue, job_set) DO UPDATE SET (reason, created, expires) = (excluded.reason, excluded.created, excluded.expires);
+
+-- name: DeleteSchedulingExclusion :exec
+DELETE FROM scheduling_exclusions WHERE queue = $1 AND job_set = $2;
+
+-- name: InsertSchedulingReport :exec
+INSERT INTO scheduling_reports (executor_id, queue, created, report) VALUES ($1, $2, $3, $4);
+
+-- name: SelectLatestSchedulingReports :many
+SELECT DISTINCT ON (executor_id) * FROM scheduling_reports WHERE queue = $1 ORDER BY executor_id, created DESC;
+
+-- name: DeleteSchedulingReportsBefore :exec
+DELETE FROM scheduling_reports WHERE created < sqlc.arg(cutoff)::timestamptz;
+
+-- name: SelectSchedulerState :many
+SELECT * FROM scheduler_state WHERE name = $1;
+
+-- name: UpsertSchedulerState :exec
+INSERT INTO scheduler_state (name, state, last_modified) VALUES ($1, $2, $3)
+ON CONFLICT (name) DO UPDATE SET (state, last_modified) = (excluded.state, excluded.last_modified);
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+// SchedulerStateRepository is an interface to be implemented by structs which store state of the leading scheduler,
+// such that it's handed over to the next leader, e.g., when the scheduler is redeployed.
+type SchedulerStateRepository interface {
+	// GetSchedulerState returns the state persisted under name, or nil if no state has been persisted under that name.
+	GetSchedulerState(ctx *armadacontext.Context, name string) ([]byte, error)
+	// SetSchedulerState persists state under name, replacing any state previously persisted under that name.
+	SetSchedulerState(ctx *armadacontext.Context, name string, state []byte, lastModified time.Time) error
+}
+
+// PostgresSchedulerStateRepository is an implementation of SchedulerStateRepository that stores its state in postgres
+type PostgresSchedulerStateRepository struct {
+	// pool of database connections
+	db *pgxpool.Pool
+}
+
+func NewPostgresSchedulerStateRepository(db *pgxpool.Pool) *PostgresSchedulerStateRepository {
+	return &PostgresSchedulerStateRepository{db: db}
+}
+
+func (r *PostgresSchedulerStateRepository) GetSchedulerState(ctx *armadacontext.Context, name string) ([]byte, error) {
+	states, err := New(r.db).SelectSchedulerState(ctx, name)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(states) == 0 {
+		return nil, nil
+	}
+	return states[0].State, nil
+}
+
+func (r *PostgresSchedulerStateRepository) SetSchedulerState(ctx *armadacontext.Context, name string, state []byte, lastModified time.Time) error {
+	err := New(r.db).UpsertSchedulerState(ctx, UpsertSchedulerStateParams{
+		Name:         name,
+		State:        state,
+		LastModified: lastModified,
+	})
+	return errors.WithStack(err)
+}
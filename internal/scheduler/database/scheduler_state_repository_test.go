@@ -0,0 +1,44 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+func TestSchedulerStateRepository(t *testing.T) {
+	now := time.Now().UTC()
+	err := withSchedulerStateRepository(func(repo *PostgresSchedulerStateRepository) error {
+		ctx, cancel := armadacontext.WithTimeout(armadacontext.Background(), 5*time.Second)
+		defer cancel()
+
+		state, err := repo.GetSchedulerState(ctx, "foo")
+		require.NoError(t, err)
+		assert.Nil(t, state)
+
+		require.NoError(t, repo.SetSchedulerState(ctx, "foo", []byte("bar"), now))
+		require.NoError(t, repo.SetSchedulerState(ctx, "baz", []byte("qux"), now))
+		state, err = repo.GetSchedulerState(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("bar"), state)
+
+		// Persisting state replaces any previously persisted state.
+		require.NoError(t, repo.SetSchedulerState(ctx, "foo", []byte("quux"), now.Add(time.Minute)))
+		state, err = repo.GetSchedulerState(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("quux"), state)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func withSchedulerStateRepository(action func(repository *PostgresSchedulerStateRepository) error) error {
+	return WithTestDb(func(_ *Queries, db *pgxpool.Pool) error {
+		return action(NewPostgresSchedulerStateRepository(db))
+	})
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+// SchedulingExclusionRepository is an interface to be implemented by structs which store queues and job sets
+// excluded from scheduling.
+type SchedulingExclusionRepository interface {
+	// GetSchedulingExclusions returns all exclusions, including expired ones.
+	GetSchedulingExclusions(ctx *armadacontext.Context) ([]SchedulingExclusion, error)
+	// UpsertSchedulingExclusion persists an exclusion, replacing any existing exclusion of the same queue and job set.
+	UpsertSchedulingExclusion(ctx *armadacontext.Context, exclusion SchedulingExclusion) error
+	// DeleteSchedulingExclusion removes the exclusion of a queue, or of a job set if jobSet is non-empty,
+	// such that its jobs are again scheduled.
+	DeleteSchedulingExclusion(ctx *armadacontext.Context, queue string, jobSet string) error
+}
+
+// PostgresSchedulingExclusionRepository is an implementation of SchedulingExclusionRepository that stores its state in postgres
+type PostgresSchedulingExclusionRepository struct {
+	// pool of database connections
+	db *pgxpool.Pool
+}
+
+func NewPostgresSchedulingExclusionRepository(db *pgxpool.Pool) *PostgresSchedulingExclusionRepository {
+	return &PostgresSchedulingExclusionRepository{db: db}
+}
+
+func (r *PostgresSchedulingExclusionRepository) GetSchedulingExclusions(ctx *armadacontext.Context) ([]SchedulingExclusion, error) {
+	exclusions, err := New(r.db).SelectAllSchedulingExclusions(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range exclusions {
+		// pgx defaults to local time so we convert to utc here
+		exclusions[i].Created = exclusions[i].Created.UTC()
+		exclusions[i].Expires = utcOrNil(exclusions[i].Expires)
+	}
+	return exclusions, nil
+}
+
+func (r *PostgresSchedulingExclusionRepository) UpsertSchedulingExclusion(ctx *armadacontext.Context, exclusion SchedulingExclusion) error {
+	err := New(r.db).UpsertSchedulingExclusion(ctx, UpsertSchedulingExclusionParams(exclusion))
+	return errors.WithStack(err)
+}
+
+func (r *PostgresSchedulingExclusionRepository) DeleteSchedulingExclusion(ctx *armadacontext.Context, queue string, jobSet string) error {
+	err := New(r.db).DeleteSchedulingExclusion(ctx, DeleteSchedulingExclusionParams{Queue: queue, JobSet: jobSet})
+	return errors.WithStack(err)
+}
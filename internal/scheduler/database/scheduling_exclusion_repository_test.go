@@ -0,0 +1,48 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+func TestSchedulingExclusionRepository(t *testing.T) {
+	t1 := time.Now().UTC().Round(1 * time.Microsecond) // postgres only stores times with micro precision
+	expires := t1.Add(time.Hour)
+	err := withSchedulingExclusionRepository(func(repo *PostgresSchedulingExclusionRepository) error {
+		ctx, cancel := armadacontext.WithTimeout(armadacontext.Background(), 5*time.Second)
+		defer cancel()
+
+		queueExclusion := SchedulingExclusion{Queue: "queue-a", Reason: "incident", Created: t1, Expires: &expires}
+		jobSetExclusion := SchedulingExclusion{Queue: "queue-a", JobSet: "job-set-a", Reason: "incident", Created: t1}
+		require.NoError(t, repo.UpsertSchedulingExclusion(ctx, queueExclusion))
+		require.NoError(t, repo.UpsertSchedulingExclusion(ctx, jobSetExclusion))
+		require.NoError(t, repo.UpsertSchedulingExclusion(ctx, SchedulingExclusion{Queue: "queue-b", Created: t1}))
+		require.NoError(t, repo.DeleteSchedulingExclusion(ctx, "queue-b", ""))
+
+		exclusions, err := repo.GetSchedulingExclusions(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []SchedulingExclusion{queueExclusion, jobSetExclusion}, exclusions)
+
+		// Upserting an exclusion replaces the existing one; deleting a job set exclusion leaves the queue exclusion as is.
+		queueExclusion = SchedulingExclusion{Queue: "queue-a", Reason: "still investigating", Created: t1}
+		require.NoError(t, repo.UpsertSchedulingExclusion(ctx, queueExclusion))
+		require.NoError(t, repo.DeleteSchedulingExclusion(ctx, "queue-a", "job-set-a"))
+		exclusions, err = repo.GetSchedulingExclusions(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []SchedulingExclusion{queueExclusion}, exclusions)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func withSchedulingExclusionRepository(action func(repository *PostgresSchedulingExclusionRepository) error) error {
+	return WithTestDb(func(_ *Queries, db *pgxpool.Pool) error {
+		return action(NewPostgresSchedulingExclusionRepository(db))
+	})
+}
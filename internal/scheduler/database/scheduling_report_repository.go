@@ -0,0 +1,71 @@
+package database
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+// SchedulingReportRepository is an interface to be implemented by structs which store the reports of recent scheduling rounds,
+// such that reports outlive the scheduler that produced them.
+type SchedulingReportRepository interface {
+	// StoreSchedulingReports persists reports, either all or none of them.
+	StoreSchedulingReports(ctx *armadacontext.Context, reports []SchedulingReport) error
+	// GetLatestSchedulingReports returns the most recent report for the given queue of each executor,
+	// or the most recent report for the round as a whole of each executor if queue is empty.
+	GetLatestSchedulingReports(ctx *armadacontext.Context, queue string) ([]SchedulingReport, error)
+	// DeleteSchedulingReportsBefore removes all reports of rounds started before cutoff.
+	DeleteSchedulingReportsBefore(ctx *armadacontext.Context, cutoff time.Time) error
+}
+
+// PostgresSchedulingReportRepository is an implementation of SchedulingReportRepository that stores its state in postgres
+type PostgresSchedulingReportRepository struct {
+	// pool of database connections
+	db *pgxpool.Pool
+}
+
+func NewPostgresSchedulingReportRepository(db *pgxpool.Pool) *PostgresSchedulingReportRepository {
+	return &PostgresSchedulingReportRepository{db: db}
+}
+
+func (r *PostgresSchedulingReportRepository) StoreSchedulingReports(ctx *armadacontext.Context, reports []SchedulingReport) error {
+	return pgx.BeginTxFunc(ctx, r.db, pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.Deferrable,
+	}, func(tx pgx.Tx) error {
+		queries := New(tx)
+		for _, report := range reports {
+			err := queries.InsertSchedulingReport(ctx, InsertSchedulingReportParams{
+				ExecutorID: report.ExecutorID,
+				Queue:      report.Queue,
+				Created:    report.Created,
+				Report:     report.Report,
+			})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *PostgresSchedulingReportRepository) GetLatestSchedulingReports(ctx *armadacontext.Context, queue string) ([]SchedulingReport, error) {
+	reports, err := New(r.db).SelectLatestSchedulingReports(ctx, queue)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range reports {
+		// pgx defaults to local time so we convert to utc here
+		reports[i].Created = reports[i].Created.UTC()
+	}
+	return reports, nil
+}
+
+func (r *PostgresSchedulingReportRepository) DeleteSchedulingReportsBefore(ctx *armadacontext.Context, cutoff time.Time) error {
+	return errors.WithStack(New(r.db).DeleteSchedulingReportsBefore(ctx, cutoff))
+}
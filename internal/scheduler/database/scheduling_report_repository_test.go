@@ -0,0 +1,61 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+func TestSchedulingReportRepository(t *testing.T) {
+	t1 := time.Now().UTC().Round(1 * time.Microsecond) // postgres only stores times with micro precision
+	t2 := t1.Add(time.Minute)
+	err := withSchedulingReportRepository(func(repo *PostgresSchedulingReportRepository) error {
+		ctx, cancel := armadacontext.WithTimeout(armadacontext.Background(), 5*time.Second)
+		defer cancel()
+
+		require.NoError(t, repo.StoreSchedulingReports(ctx, []SchedulingReport{
+			{ExecutorID: "executor-a", Created: t1, Report: "round 1"},
+			{ExecutorID: "executor-a", Queue: "queue-a", Created: t1, Report: "queue-a round 1"},
+			{ExecutorID: "executor-b", Created: t1, Report: "round 1"},
+		}))
+		require.NoError(t, repo.StoreSchedulingReports(ctx, []SchedulingReport{
+			{ExecutorID: "executor-a", Created: t2, Report: "round 2"},
+		}))
+
+		reports, err := repo.GetLatestSchedulingReports(ctx, "")
+		require.NoError(t, err)
+		require.Len(t, reports, 2)
+		assert.Equal(t, "executor-a", reports[0].ExecutorID)
+		assert.Equal(t, "round 2", reports[0].Report)
+		assert.Equal(t, t2, reports[0].Created)
+		assert.Equal(t, "executor-b", reports[1].ExecutorID)
+
+		reports, err = repo.GetLatestSchedulingReports(ctx, "queue-a")
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		assert.Equal(t, "queue-a round 1", reports[0].Report)
+
+		// Only reports of rounds started before the cutoff are deleted.
+		require.NoError(t, repo.DeleteSchedulingReportsBefore(ctx, t2))
+		reports, err = repo.GetLatestSchedulingReports(ctx, "")
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		assert.Equal(t, "round 2", reports[0].Report)
+		reports, err = repo.GetLatestSchedulingReports(ctx, "queue-a")
+		require.NoError(t, err)
+		assert.Empty(t, reports)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func withSchedulingReportRepository(action func(repository *PostgresSchedulingReportRepository) error) error {
+	return WithTestDb(func(_ *Queries, db *pgxpool.Pool) error {
+		return action(NewPostgresSchedulingReportRepository(db))
+	})
+}
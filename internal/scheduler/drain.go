@@ -0,0 +1,312 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/types"
+	"github.com/armadaproject/armada/internal/scheduler/database"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+// ClusterDrainer drains executor clusters on request, e.g., before decommissioning them. For each cluster being drained:
+// 1. No new jobs are scheduled onto the cluster.
+// 2. If requested, preemptible jobs running on the cluster are returned to their queue to be scheduled elsewhere.
+// 3. Once the deadline of the drain, if any, has passed, all jobs still running on the cluster are preempted.
+// 4. Once no more jobs are running on the cluster, the drain is marked as completed and an alert is raised.
+// Drains are stored in postgres, such that they may be requested via any scheduler replica and survive leader changes.
+// A cluster remains drained until its drain is cancelled.
+type ClusterDrainer struct {
+	repository         database.ExecutorDrainRepository
+	executorRepository database.ExecutorRepository
+	priorityClasses    map[string]types.PriorityClass
+	alerter            Alerter
+	clock              clock.Clock
+	// Executors being drained as of the most recent call to Drain.
+	drainingExecutors     map[string]bool
+	drainingExecutorsLock sync.Mutex
+}
+
+func NewClusterDrainer(
+	repository database.ExecutorDrainRepository,
+	executorRepository database.ExecutorRepository,
+	priorityClasses map[string]types.PriorityClass,
+	alerter Alerter,
+) *ClusterDrainer {
+	return &ClusterDrainer{
+		repository:         repository,
+		executorRepository: executorRepository,
+		priorityClasses:    priorityClasses,
+		alerter:            alerter,
+		clock:              clock.RealClock{},
+		drainingExecutors:  make(map[string]bool),
+	}
+}
+
+// IsDraining returns true if no new jobs should be scheduled onto the cluster of the given executor.
+func (d *ClusterDrainer) IsDraining(executorId string) bool {
+	d.drainingExecutorsLock.Lock()
+	defer d.drainingExecutorsLock.Unlock()
+	return d.drainingExecutors[executorId]
+}
+
+// Drain returns the events necessary to progress all incomplete drains and updates the jobs affected in txn accordingly.
+// Must only be called by the leader.
+func (d *ClusterDrainer) Drain(ctx *armadacontext.Context, txn *jobdb.Txn) ([]*armadaevents.EventSequence, error) {
+	drains, err := d.repository.GetExecutorDrains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	drainingExecutors := make(map[string]bool, len(drains))
+	activeDrains := make(map[string]database.ExecutorDrain, len(drains))
+	for _, drain := range drains {
+		drainingExecutors[drain.ExecutorID] = true
+		if drain.Completed == nil {
+			activeDrains[drain.ExecutorID] = drain
+		}
+	}
+	d.drainingExecutorsLock.Lock()
+	d.drainingExecutors = drainingExecutors
+	d.drainingExecutorsLock.Unlock()
+	if len(activeDrains) == 0 {
+		return nil, nil
+	}
+
+	runningJobsByExecutor := make(map[string][]*jobdb.Job, len(activeDrains))
+	for _, job := range txn.GetAll() {
+		if job.InTerminalState() || job.Queued() {
+			continue
+		}
+		run := job.LatestRun()
+		if run == nil || run.InTerminalState() {
+			continue
+		}
+		if _, ok := activeDrains[run.Executor()]; ok {
+			runningJobsByExecutor[run.Executor()] = append(runningJobsByExecutor[run.Executor()], job)
+		}
+	}
+
+	now := d.clock.Now()
+	var events []*armadaevents.EventSequence
+	var jobsToUpdate []*jobdb.Job
+	var jobsToPreempt []*jobdb.Job
+	preemptionByJobId := make(map[string]*Preemption)
+	for executorId, drain := range activeDrains {
+		jobs := runningJobsByExecutor[executorId]
+		if len(jobs) == 0 {
+			if err := d.completeDrain(ctx, executorId, now); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		deadlineExceeded := drain.Deadline != nil && !now.Before(*drain.Deadline)
+		for _, job := range jobs {
+			if deadlineExceeded {
+				ctx.Infof("preempting job %s as the drain deadline of executor %s has passed", job.Id(), executorId)
+				run := job.LatestRun()
+				jobsToPreempt = append(jobsToPreempt, job.WithQueued(false).WithFailed(true).WithUpdatedRun(run.WithFailed(true)))
+				preemptionByJobId[job.Id()] = &Preemption{Cause: PreemptionCauseNodeDrained}
+			} else if drain.MigratePreemptible && d.priorityClasses[job.GetPriorityClassName()].Preemptible {
+				ctx.Infof("returning job %s to its queue as executor %s is being drained", job.Id(), executorId)
+				es, err := returnLeaseEventSequence(job, fmt.Sprintf("executor %s is being drained", executorId), now)
+				if err != nil {
+					return nil, err
+				}
+				events = append(events, es)
+				// The job is requeued once the returned run has been written to the database.
+				run := job.LatestRun()
+				jobsToUpdate = append(jobsToUpdate, job.WithUpdatedRun(run.WithFailed(true).WithReturned(true)))
+			}
+		}
+	}
+	events, err = AppendEventSequencesFromPreemptedJobs(events, jobsToPreempt, preemptionByJobId, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := txn.Upsert(append(jobsToUpdate, jobsToPreempt...)); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (d *ClusterDrainer) completeDrain(ctx *armadacontext.Context, executorId string, now time.Time) error {
+	if err := d.repository.MarkExecutorDrainCompleted(ctx, executorId, now); err != nil {
+		return err
+	}
+	ctx.Infof("drain of executor %s completed; no jobs are running on its cluster", executorId)
+	pool := ""
+	if executors, err := d.executorRepository.GetExecutors(ctx); err != nil {
+		ctx.WithError(err).Warnf("failed to look up pool of executor %s", executorId)
+	} else {
+		for _, executor := range executors {
+			if executor.Id == executorId {
+				pool = executor.Pool
+			}
+		}
+	}
+	d.alerter.Alert(Alert{
+		Type:    AlertClusterDrained,
+		Pool:    pool,
+		Subject: executorId,
+		Text:    fmt.Sprintf("Executor %s has been drained; no jobs are running on its cluster and none will be scheduled onto it.", executorId),
+	})
+	return nil
+}
+
+// returnLeaseEventSequence returns the events necessary to return the lease of the latest run of job,
+// such that the job is requeued without the run counting towards its maximum number of attempts.
+func returnLeaseEventSequence(job *jobdb.Job, message string, time time.Time) (*armadaevents.EventSequence, error) {
+	jobId, err := armadaevents.ProtoUuidFromUlidString(job.Id())
+	if err != nil {
+		return nil, err
+	}
+	return &armadaevents.EventSequence{
+		Queue:      job.Queue(),
+		JobSetName: job.Jobset(),
+		Events: []*armadaevents.EventSequence_Event{
+			{
+				Created: &time,
+				Event: &armadaevents.EventSequence_Event_JobRunErrors{
+					JobRunErrors: &armadaevents.JobRunErrors{
+						RunId: armadaevents.ProtoUuidFromUuid(job.LatestRun().Id()),
+						JobId: jobId,
+						Errors: []*armadaevents.Error{
+							{
+								Terminal: true,
+								Reason: &armadaevents.Error_PodLeaseReturned{
+									PodLeaseReturned: &armadaevents.PodLeaseReturned{Message: message},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// ExecutorDrainsHttpHandler is the admin API for draining executor clusters:
+// - GET /drains returns all drains as json.
+// - POST /drains?executor=<id>&deadline=<duration>&migratePreemptible=<bool> starts draining the cluster of the given executor.
+// Deadline and migratePreemptible are optional; if no deadline is given, the drain waits for all jobs to finish.
+// - DELETE /drains?executor=<id> cancels the drain of the given executor, such that jobs are again scheduled onto it.
+type ExecutorDrainsHttpHandler struct {
+	repository database.ExecutorDrainRepository
+	clock      clock.Clock
+}
+
+// ExecutorDrains is the response returned by ExecutorDrainsHttpHandler.
+type ExecutorDrains struct {
+	Drains []*ExecutorDrainStatus `json:"drains"`
+}
+
+type ExecutorDrainStatus struct {
+	Executor           string     `json:"executor"`
+	MigratePreemptible bool       `json:"migratePreemptible"`
+	Started            time.Time  `json:"started"`
+	Deadline           *time.Time `json:"deadline,omitempty"`
+	Completed          *time.Time `json:"completed,omitempty"`
+}
+
+func NewExecutorDrainsHttpHandler(repository database.ExecutorDrainRepository) *ExecutorDrainsHttpHandler {
+	return &ExecutorDrainsHttpHandler{
+		repository: repository,
+		clock:      clock.RealClock{},
+	}
+}
+
+func (h *ExecutorDrainsHttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := armadacontext.New(r.Context(), log.NewEntry(log.StandardLogger()))
+	switch r.Method {
+	case http.MethodGet:
+		h.getDrains(ctx, w)
+	case http.MethodPost:
+		drain, err := h.drainFromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.repository.StartExecutorDrain(ctx, drain); err != nil {
+			log.WithError(err).Error("failed to start drain")
+			http.Error(w, "failed to start drain: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ctx.Infof("started draining executor %s", drain.ExecutorID)
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodDelete:
+		executorId := r.URL.Query().Get("executor")
+		if executorId == "" {
+			http.Error(w, "executor must be provided", http.StatusBadRequest)
+			return
+		}
+		if err := h.repository.CancelExecutorDrain(ctx, executorId); err != nil {
+			log.WithError(err).Error("failed to cancel drain")
+			http.Error(w, "failed to cancel drain: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ctx.Infof("cancelled draining executor %s", executorId)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ExecutorDrainsHttpHandler) getDrains(ctx *armadacontext.Context, w http.ResponseWriter) {
+	drains, err := h.repository.GetExecutorDrains(ctx)
+	if err != nil {
+		log.WithError(err).Error("failed to get drains")
+		http.Error(w, "failed to get drains: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rv := &ExecutorDrains{Drains: make([]*ExecutorDrainStatus, len(drains))}
+	for i, drain := range drains {
+		rv.Drains[i] = &ExecutorDrainStatus{
+			Executor:           drain.ExecutorID,
+			MigratePreemptible: drain.MigratePreemptible,
+			Started:            drain.Started,
+			Deadline:           drain.Deadline,
+			Completed:          drain.Completed,
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rv); err != nil {
+		log.WithError(err).Error("failed to write drains response")
+	}
+}
+
+func (h *ExecutorDrainsHttpHandler) drainFromRequest(r *http.Request) (database.ExecutorDrain, error) {
+	query := r.URL.Query()
+	drain := database.ExecutorDrain{
+		ExecutorID: query.Get("executor"),
+		Started:    h.clock.Now().UTC(),
+	}
+	if drain.ExecutorID == "" {
+		return drain, errors.New("executor must be provided")
+	}
+	if s := query.Get("deadline"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return drain, errors.Errorf("invalid deadline %s: %s", s, err)
+		}
+		deadline := drain.Started.Add(d)
+		drain.Deadline = &deadline
+	}
+	if s := query.Get("migratePreemptible"); s != "" {
+		migratePreemptible, err := strconv.ParseBool(s)
+		if err != nil {
+			return drain, errors.Errorf("invalid migratePreemptible %s: %s", s, err)
+		}
+		drain.MigratePreemptible = migratePreemptible
+	}
+	return drain, nil
+}
@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/scheduler/database"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+func TestClusterDrainer_Drain(t *testing.T) {
+	now := testfixtures.BaseTime
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+	preemptibleJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).
+		WithQueued(false).WithNewRun("drained", "node", "node")
+	nonPreemptibleJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass3).
+		WithQueued(false).WithNewRun("drained", "node", "node")
+	otherExecutorJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).
+		WithQueued(false).WithNewRun("other", "node", "node")
+	queuedJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0)
+
+	tests := map[string]struct {
+		drain                 database.ExecutorDrain
+		jobs                  []*jobdb.Job
+		expectedReturnedJobs  []string
+		expectedPreemptedJobs []string
+		expectCompleted       bool
+		expectedUnchangedJobs []string
+	}{
+		"wait for jobs to finish": {
+			drain:                 database.ExecutorDrain{ExecutorID: "drained", Started: past},
+			jobs:                  []*jobdb.Job{preemptibleJob, nonPreemptibleJob, otherExecutorJob, queuedJob},
+			expectedUnchangedJobs: []string{preemptibleJob.Id(), nonPreemptibleJob.Id(), otherExecutorJob.Id()},
+		},
+		"migrate preemptible jobs": {
+			drain:                 database.ExecutorDrain{ExecutorID: "drained", Started: past, MigratePreemptible: true, Deadline: &future},
+			jobs:                  []*jobdb.Job{preemptibleJob, nonPreemptibleJob, otherExecutorJob, queuedJob},
+			expectedReturnedJobs:  []string{preemptibleJob.Id()},
+			expectedUnchangedJobs: []string{nonPreemptibleJob.Id(), otherExecutorJob.Id()},
+		},
+		"preempt jobs once the deadline has passed": {
+			drain:                 database.ExecutorDrain{ExecutorID: "drained", Started: past, MigratePreemptible: true, Deadline: &past},
+			jobs:                  []*jobdb.Job{preemptibleJob, nonPreemptibleJob, otherExecutorJob, queuedJob},
+			expectedPreemptedJobs: []string{preemptibleJob.Id(), nonPreemptibleJob.Id()},
+			expectedUnchangedJobs: []string{otherExecutorJob.Id()},
+		},
+		"complete once no jobs are running": {
+			drain:           database.ExecutorDrain{ExecutorID: "drained", Started: past},
+			jobs:            []*jobdb.Job{otherExecutorJob, queuedJob},
+			expectCompleted: true,
+		},
+		"completed drains are left as is": {
+			drain:                 database.ExecutorDrain{ExecutorID: "drained", Started: past, Deadline: &past, Completed: &past},
+			jobs:                  []*jobdb.Job{preemptibleJob},
+			expectedUnchangedJobs: []string{preemptibleJob.Id()},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := armadacontext.Background()
+			repo := &testExecutorDrainRepository{drains: map[string]database.ExecutorDrain{tc.drain.ExecutorID: tc.drain}}
+			executorRepo := &testExecutorRepositoryWithExecutors{
+				executors: []*schedulerobjects.Executor{{Id: "drained", Pool: "pool"}},
+			}
+			alerter := &recordingAlerter{}
+			drainer := NewClusterDrainer(repo, executorRepo, testfixtures.TestPriorityClasses, alerter)
+			drainer.clock = clock.NewFakeClock(now)
+
+			jobDb := testfixtures.NewJobDb()
+			txn := jobDb.WriteTxn()
+			require.NoError(t, txn.Upsert(tc.jobs))
+
+			events, err := drainer.Drain(ctx, txn)
+			require.NoError(t, err)
+			assert.True(t, drainer.IsDraining("drained"))
+			assert.False(t, drainer.IsDraining("other"))
+
+			// Job ids are upper-case ulids, whereas UlidStringFromProtoUuid returns lower-case ones.
+			var returnedJobs, preemptedJobs []string
+			for _, es := range events {
+				for _, event := range es.Events {
+					switch typed := event.Event.(type) {
+					case *armadaevents.EventSequence_Event_JobRunErrors:
+						if typed.JobRunErrors.Errors[0].GetPodLeaseReturned() != nil {
+							jobId, err := armadaevents.UlidStringFromProtoUuid(typed.JobRunErrors.JobId)
+							require.NoError(t, err)
+							returnedJobs = append(returnedJobs, strings.ToUpper(jobId))
+						}
+					case *armadaevents.EventSequence_Event_JobRunPreempted:
+						jobId, err := armadaevents.UlidStringFromProtoUuid(typed.JobRunPreempted.PreemptedJobId)
+						require.NoError(t, err)
+						preemptedJobs = append(preemptedJobs, strings.ToUpper(jobId))
+					}
+				}
+			}
+			assert.ElementsMatch(t, tc.expectedReturnedJobs, returnedJobs)
+			assert.ElementsMatch(t, tc.expectedPreemptedJobs, preemptedJobs)
+			for _, jobId := range tc.expectedReturnedJobs {
+				run := txn.GetById(jobId).LatestRun()
+				assert.True(t, run.Failed())
+				assert.True(t, run.Returned())
+			}
+			for _, jobId := range tc.expectedPreemptedJobs {
+				assert.True(t, txn.GetById(jobId).Failed())
+			}
+			for _, jobId := range tc.expectedUnchangedJobs {
+				assert.False(t, txn.GetById(jobId).LatestRun().InTerminalState())
+			}
+
+			if tc.expectCompleted {
+				assert.Equal(t, now, *repo.drains["drained"].Completed)
+				require.Len(t, alerter.alerts, 1)
+				assert.Equal(t, AlertClusterDrained, alerter.alerts[0].Type)
+				assert.Equal(t, "pool", alerter.alerts[0].Pool)
+			} else {
+				assert.Equal(t, tc.drain.Completed, repo.drains["drained"].Completed)
+				assert.Empty(t, alerter.alerts)
+			}
+		})
+	}
+}
+
+type testExecutorDrainRepository struct {
+	drains map[string]database.ExecutorDrain
+}
+
+func (r *testExecutorDrainRepository) GetExecutorDrains(_ *armadacontext.Context) ([]database.ExecutorDrain, error) {
+	rv := make([]database.ExecutorDrain, 0, len(r.drains))
+	for _, drain := range r.drains {
+		rv = append(rv, drain)
+	}
+	return rv, nil
+}
+
+func (r *testExecutorDrainRepository) StartExecutorDrain(_ *armadacontext.Context, drain database.ExecutorDrain) error {
+	r.drains[drain.ExecutorID] = drain
+	return nil
+}
+
+func (r *testExecutorDrainRepository) CancelExecutorDrain(_ *armadacontext.Context, executorId string) error {
+	delete(r.drains, executorId)
+	return nil
+}
+
+func (r *testExecutorDrainRepository) MarkExecutorDrainCompleted(_ *armadacontext.Context, executorId string, completed time.Time) error {
+	drain := r.drains[executorId]
+	drain.Completed = &completed
+	r.drains[executorId] = drain
+	return nil
+}
+
+type testExecutorRepositoryWithExecutors struct {
+	testExecutorRepository
+	executors []*schedulerobjects.Executor
+}
+
+func (r *testExecutorRepositoryWithExecutors) GetExecutors(_ *armadacontext.Context) ([]*schedulerobjects.Executor, error) {
+	return r.executors, nil
+}
+
+type recordingAlerter struct {
+	alerts []Alert
+}
+
+func (a *recordingAlerter) Alert(alert Alert) {
+	a.alerts = append(a.alerts, alert)
+}
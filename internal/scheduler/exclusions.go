@@ -0,0 +1,195 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/logging"
+	"github.com/armadaproject/armada/internal/scheduler/database"
+)
+
+// SchedulingExclusions excludes queues, or individual job sets, from scheduling on request,
+// e.g., to quickly quarantine a misbehaving workload without cancelling it.
+// Queued jobs that are excluded remain queued but aren't considered for scheduling; running jobs are left as is.
+// Exclusions are stored in postgres, such that they may be requested via any scheduler replica and survive leader changes.
+// An exclusion applies until it expires or is removed.
+type SchedulingExclusions struct {
+	repository database.SchedulingExclusionRepository
+	clock      clock.Clock
+	// Exclusions in effect as of the most recent successful call to Refresh.
+	active     schedulingExclusionSet
+	activeLock sync.Mutex
+}
+
+func NewSchedulingExclusions(repository database.SchedulingExclusionRepository) *SchedulingExclusions {
+	return &SchedulingExclusions{
+		repository: repository,
+		clock:      clock.RealClock{},
+	}
+}
+
+// Refresh loads and returns the exclusions currently in effect.
+// If they can't be loaded, those loaded previously are returned,
+// such that quarantined workloads aren't released just because postgres is briefly unavailable.
+func (e *SchedulingExclusions) Refresh(ctx *armadacontext.Context) schedulingExclusionSet {
+	e.activeLock.Lock()
+	defer e.activeLock.Unlock()
+	exclusions, err := e.repository.GetSchedulingExclusions(ctx)
+	if err != nil {
+		logging.WithStacktrace(ctx, err).Error("failed to load scheduling exclusions; using those loaded previously")
+		return e.active
+	}
+	now := e.clock.Now()
+	active := make(schedulingExclusionSet)
+	for _, exclusion := range exclusions {
+		if exclusion.Expires != nil && !now.Before(*exclusion.Expires) {
+			continue
+		}
+		if active[exclusion.Queue] == nil {
+			active[exclusion.Queue] = make(map[string]bool)
+		}
+		active[exclusion.Queue][exclusion.JobSet] = true
+	}
+	e.active = active
+	return active
+}
+
+// schedulingExclusionSet maps each queue with exclusions to the job sets excluded,
+// where the empty job set denotes that all job sets of the queue are excluded.
+type schedulingExclusionSet map[string]map[string]bool
+
+// IsQueueExcluded returns true if all jobs of queue are excluded.
+func (s schedulingExclusionSet) IsQueueExcluded(queue string) bool {
+	return s[queue][""]
+}
+
+// IsExcluded returns true if jobs of the given queue and job set are excluded.
+func (s schedulingExclusionSet) IsExcluded(queue string, jobSet string) bool {
+	jobSets := s[queue]
+	return jobSets[""] || jobSets[jobSet]
+}
+
+// SchedulingExclusionsHttpHandler is the admin API for excluding queues and job sets from scheduling:
+// - GET /exclusions returns all exclusions as json.
+// - POST /exclusions?queue=<name>&jobSet=<name>&expiry=<duration>&reason=<text> excludes the given queue, or job set of that queue, from scheduling.
+// JobSet, expiry, and reason are optional; if no job set is given, the entire queue is excluded, and if no expiry is given, the exclusion applies until removed.
+// - DELETE /exclusions?queue=<name>&jobSet=<name> removes the given exclusion, such that the affected jobs are again scheduled.
+type SchedulingExclusionsHttpHandler struct {
+	repository database.SchedulingExclusionRepository
+	clock      clock.Clock
+}
+
+// SchedulingExclusionsResponse is the response returned by SchedulingExclusionsHttpHandler.
+type SchedulingExclusionsResponse struct {
+	Exclusions []*SchedulingExclusionStatus `json:"exclusions"`
+}
+
+type SchedulingExclusionStatus struct {
+	Queue   string     `json:"queue"`
+	JobSet  string     `json:"jobSet,omitempty"`
+	Reason  string     `json:"reason,omitempty"`
+	Created time.Time  `json:"created"`
+	Expires *time.Time `json:"expires,omitempty"`
+	Expired bool       `json:"expired"`
+}
+
+func NewSchedulingExclusionsHttpHandler(repository database.SchedulingExclusionRepository) *SchedulingExclusionsHttpHandler {
+	return &SchedulingExclusionsHttpHandler{
+		repository: repository,
+		clock:      clock.RealClock{},
+	}
+}
+
+func (h *SchedulingExclusionsHttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := armadacontext.New(r.Context(), log.NewEntry(log.StandardLogger()))
+	switch r.Method {
+	case http.MethodGet:
+		h.getExclusions(ctx, w)
+	case http.MethodPost:
+		exclusion, err := h.exclusionFromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.repository.UpsertSchedulingExclusion(ctx, exclusion); err != nil {
+			log.WithError(err).Error("failed to add scheduling exclusion")
+			http.Error(w, "failed to add scheduling exclusion: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ctx.Infof("excluded queue %q job set %q from scheduling: %s", exclusion.Queue, exclusion.JobSet, exclusion.Reason)
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodDelete:
+		queue := r.URL.Query().Get("queue")
+		jobSet := r.URL.Query().Get("jobSet")
+		if queue == "" {
+			http.Error(w, "queue must be provided", http.StatusBadRequest)
+			return
+		}
+		if err := h.repository.DeleteSchedulingExclusion(ctx, queue, jobSet); err != nil {
+			log.WithError(err).Error("failed to remove scheduling exclusion")
+			http.Error(w, "failed to remove scheduling exclusion: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ctx.Infof("removed scheduling exclusion of queue %q job set %q", queue, jobSet)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *SchedulingExclusionsHttpHandler) getExclusions(ctx *armadacontext.Context, w http.ResponseWriter) {
+	exclusions, err := h.repository.GetSchedulingExclusions(ctx)
+	if err != nil {
+		log.WithError(err).Error("failed to get scheduling exclusions")
+		http.Error(w, "failed to get scheduling exclusions: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	now := h.clock.Now()
+	rv := &SchedulingExclusionsResponse{Exclusions: make([]*SchedulingExclusionStatus, len(exclusions))}
+	for i, exclusion := range exclusions {
+		rv.Exclusions[i] = &SchedulingExclusionStatus{
+			Queue:   exclusion.Queue,
+			JobSet:  exclusion.JobSet,
+			Reason:  exclusion.Reason,
+			Created: exclusion.Created,
+			Expires: exclusion.Expires,
+			Expired: exclusion.Expires != nil && !now.Before(*exclusion.Expires),
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rv); err != nil {
+		log.WithError(err).Error("failed to write scheduling exclusions response")
+	}
+}
+
+func (h *SchedulingExclusionsHttpHandler) exclusionFromRequest(r *http.Request) (database.SchedulingExclusion, error) {
+	query := r.URL.Query()
+	exclusion := database.SchedulingExclusion{
+		Queue:   query.Get("queue"),
+		JobSet:  query.Get("jobSet"),
+		Reason:  query.Get("reason"),
+		Created: h.clock.Now().UTC(),
+	}
+	if exclusion.Queue == "" {
+		return exclusion, errors.New("queue must be provided")
+	}
+	if s := query.Get("expiry"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return exclusion, errors.Errorf("invalid expiry %s: %s", s, err)
+		}
+		if d <= 0 {
+			return exclusion, errors.Errorf("expiry must be positive, but got %s", s)
+		}
+		expires := exclusion.Created.Add(d)
+		exclusion.Expires = &expires
+	}
+	return exclusion, nil
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/scheduler/database"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestSchedulingExclusions_Refresh(t *testing.T) {
+	now := testfixtures.BaseTime
+	expired := now.Add(-time.Minute)
+	notExpired := now.Add(time.Minute)
+	repository := &testSchedulingExclusionRepository{
+		exclusions: []database.SchedulingExclusion{
+			{Queue: "A"},
+			{Queue: "B", JobSet: "quarantined", Expires: &notExpired},
+			{Queue: "C", Expires: &expired},
+		},
+	}
+	exclusions := NewSchedulingExclusions(repository)
+	exclusions.clock = clock.NewFakeClock(now)
+
+	active := exclusions.Refresh(armadacontext.Background())
+	assert.True(t, active.IsQueueExcluded("A"))
+	assert.True(t, active.IsExcluded("A", "any"))
+	assert.False(t, active.IsQueueExcluded("B"))
+	assert.True(t, active.IsExcluded("B", "quarantined"))
+	assert.False(t, active.IsExcluded("B", "other"))
+	assert.False(t, active.IsExcluded("C", "any"))
+	assert.False(t, active.IsExcluded("D", "any"))
+
+	// Exclusions loaded previously continue to apply if they can't be loaded.
+	repository.err = errors.New("postgres unavailable")
+	assert.Equal(t, active, exclusions.Refresh(armadacontext.Background()))
+}
+
+func TestSchedulerJobRepositoryAdapter_Exclusions(t *testing.T) {
+	queuedJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).WithJobset("ok").WithQueued(true)
+	quarantinedJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).WithJobset("quarantined").WithQueued(true)
+	otherQueueJob := testfixtures.Test1Cpu4GiJob("B", testfixtures.PriorityClass0).WithQueued(true)
+	txn := testfixtures.NewJobDb().WriteTxn()
+	require.NoError(t, txn.Upsert([]*jobdb.Job{queuedJob, quarantinedJob, otherQueueJob}))
+
+	repo := NewSchedulerJobRepositoryAdapter(txn)
+	repo.exclusions = schedulingExclusionSet{
+		"A": {"quarantined": true},
+		"B": {"": true},
+	}
+	jobIds, err := repo.GetQueueJobIds("A")
+	require.NoError(t, err)
+	assert.Equal(t, []string{queuedJob.Id()}, jobIds)
+	jobIds, err = repo.GetQueueJobIds("B")
+	require.NoError(t, err)
+	assert.Empty(t, jobIds)
+}
+
+type testSchedulingExclusionRepository struct {
+	exclusions []database.SchedulingExclusion
+	err        error
+}
+
+func (r *testSchedulingExclusionRepository) GetSchedulingExclusions(_ *armadacontext.Context) ([]database.SchedulingExclusion, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.exclusions, nil
+}
+
+func (r *testSchedulingExclusionRepository) UpsertSchedulingExclusion(_ *armadacontext.Context, exclusion database.SchedulingExclusion) error {
+	r.exclusions = append(r.exclusions, exclusion)
+	return nil
+}
+
+func (r *testSchedulingExclusionRepository) DeleteSchedulingExclusion(_ *armadacontext.Context, queue string, jobSet string) error {
+	exclusions := r.exclusions[:0]
+	for _, exclusion := range r.exclusions {
+		if exclusion.Queue != queue || exclusion.JobSet != jobSet {
+			exclusions = append(exclusions, exclusion)
+		}
+	}
+	r.exclusions = exclusions
+	return nil
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// capabilitiesReported returns true if the executor reported its capabilities when registering with the scheduler.
+// Executors predating capability reporting don't, in which case all clusters are assumed to be able to run any job.
+func capabilitiesReported(executor *schedulerobjects.Executor) bool {
+	return executor.KubernetesVersion != "" || len(executor.RuntimeClasses) > 0 || len(executor.Features) > 0
+}
+
+// unsupportedJobReason returns a human-readable explanation of why the cluster of executor
+// can't run jobs with the given annotations, or the empty string if it can.
+// Jobs targeting a pool via PoolAnnotation can only run on executors of that pool.
+func unsupportedJobReason(executor *schedulerobjects.Executor, annotations map[string]string) string {
+	if pool := annotations[configuration.PoolAnnotation]; pool != "" && pool != executor.Pool {
+		return fmt.Sprintf("executor %s is in pool %s, but the job targets pool %s", executor.Id, executor.Pool, pool)
+	}
+	if !capabilitiesReported(executor) {
+		return ""
+	}
+	if runtimeClassName := annotations[configuration.RuntimeClassNameAnnotation]; runtimeClassName != "" {
+		if !slices.Contains(executor.RuntimeClasses, runtimeClassName) {
+			return fmt.Sprintf("executor %s does not support runtime class %s", executor.Id, runtimeClassName)
+		}
+	}
+	for _, feature := range strings.Split(annotations[configuration.RequiredFeaturesAnnotation], ",") {
+		feature = strings.TrimSpace(feature)
+		if feature != "" && !slices.Contains(executor.Features, feature) {
+			return fmt.Sprintf("executor %s does not support feature %s", executor.Id, feature)
+		}
+	}
+	if minVersion := annotations[configuration.MinKubernetesVersionAnnotation]; minVersion != "" {
+		required, err := version.ParseGeneric(minVersion)
+		if err != nil {
+			return fmt.Sprintf("invalid minimum Kubernetes version %s: %s", minVersion, err)
+		}
+		actual, err := version.ParseGeneric(executor.KubernetesVersion)
+		if err != nil || !actual.AtLeast(required) {
+			return fmt.Sprintf(
+				"executor %s runs Kubernetes version %s, but at least %s is required",
+				executor.Id, executor.KubernetesVersion, minVersion,
+			)
+		}
+	}
+	return ""
+}
+
+// executorsSupportJob returns true if the clusters of all executors can run jobs with the given annotations.
+// Executor groups are scheduled onto jointly; hence, a job is only considered if it can run wherever it's placed.
+func executorsSupportJob(executors []*schedulerobjects.Executor, annotations map[string]string) bool {
+	for _, executor := range executors {
+		if unsupportedJobReason(executor, annotations) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+func TestUnsupportedJobReason(t *testing.T) {
+	executor := &schedulerobjects.Executor{
+		Id:                "executor",
+		Pool:              "cpu",
+		KubernetesVersion: "v1.27.3",
+		RuntimeClasses:    []string{"gvisor"},
+		Features:          []string{"in-place-resize"},
+	}
+	tests := map[string]struct {
+		executor    *schedulerobjects.Executor
+		annotations map[string]string
+		supported   bool
+	}{
+		"no requirements": {
+			executor:  executor,
+			supported: true,
+		},
+		"supported runtime class": {
+			executor:    executor,
+			annotations: map[string]string{configuration.RuntimeClassNameAnnotation: "gvisor"},
+			supported:   true,
+		},
+		"unsupported runtime class": {
+			executor:    executor,
+			annotations: map[string]string{configuration.RuntimeClassNameAnnotation: "kata"},
+		},
+		"supported features": {
+			executor:    executor,
+			annotations: map[string]string{configuration.RequiredFeaturesAnnotation: " in-place-resize, "},
+			supported:   true,
+		},
+		"unsupported feature": {
+			executor:    executor,
+			annotations: map[string]string{configuration.RequiredFeaturesAnnotation: "in-place-resize,dra"},
+		},
+		"sufficient Kubernetes version": {
+			executor:    executor,
+			annotations: map[string]string{configuration.MinKubernetesVersionAnnotation: "v1.27"},
+			supported:   true,
+		},
+		"insufficient Kubernetes version": {
+			executor:    executor,
+			annotations: map[string]string{configuration.MinKubernetesVersionAnnotation: "1.28.0"},
+		},
+		"invalid Kubernetes version": {
+			executor:    executor,
+			annotations: map[string]string{configuration.MinKubernetesVersionAnnotation: "latest"},
+		},
+		"targets executor pool": {
+			executor:    executor,
+			annotations: map[string]string{configuration.PoolAnnotation: "cpu"},
+			supported:   true,
+		},
+		"targets other pool": {
+			executor:    executor,
+			annotations: map[string]string{configuration.PoolAnnotation: "gpu"},
+		},
+		"targets other pool with capabilities not reported": {
+			executor:    &schedulerobjects.Executor{Id: "executor", Pool: "cpu"},
+			annotations: map[string]string{configuration.PoolAnnotation: "gpu"},
+		},
+		"capabilities not reported": {
+			executor: &schedulerobjects.Executor{Id: "executor"},
+			annotations: map[string]string{
+				configuration.RuntimeClassNameAnnotation:     "kata",
+				configuration.RequiredFeaturesAnnotation:     "dra",
+				configuration.MinKubernetesVersionAnnotation: "v1.28",
+			},
+			supported: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			reason := unsupportedJobReason(tc.executor, tc.annotations)
+			if tc.supported {
+				assert.Empty(t, reason)
+			} else {
+				assert.NotEmpty(t, reason)
+			}
+			assert.Equal(t, tc.supported, executorsSupportJob([]*schedulerobjects.Executor{tc.executor}, tc.annotations))
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/nodedb"
+)
+
+// GangAccumulator reserves nodes for large gangs that can't currently be scheduled, such that the resources of these
+// nodes accumulate across scheduling rounds as the jobs bound to them finish, until the gang fits.
+// At most one gang accumulates capacity in each executor group at a time, so gangs never wait on each other's nodes.
+// Reservations are released once the gang is no longer queued, or once the configured timeout has elapsed;
+// a gang whose reservation timed out isn't reserved nodes for again while it remains queued.
+type GangAccumulator struct {
+	config configuration.GangAccumulationConfig
+	clock  clock.Clock
+
+	mu sync.Mutex
+	// Reservation of each executor group, i.e., the pool or executor jobs are scheduled across together.
+	reservationByExecutorGroup map[string]*GangReservation
+	// Gangs whose reservation timed out.
+	timedOutGangIds map[string]bool
+}
+
+// GangReservation is a set of nodes reserved for a gang accumulating capacity.
+type GangReservation struct {
+	GangId  string   `json:"gangId"`
+	Queue   string   `json:"queue"`
+	Pool    string   `json:"pool"`
+	NodeIds []string `json:"nodeIds"`
+	// Time at which the nodes were reserved.
+	Reserved time.Time `json:"reserved"`
+}
+
+func NewGangAccumulator(config configuration.GangAccumulationConfig) (*GangAccumulator, error) {
+	if config.MinGangCardinality < 2 {
+		return nil, errors.Errorf("gang accumulation minimum gang cardinality must be at least 2, but is %d", config.MinGangCardinality)
+	}
+	if config.Timeout <= 0 {
+		return nil, errors.Errorf("gang accumulation timeout must be positive, but is %s", config.Timeout)
+	}
+	return &GangAccumulator{
+		config:                     config,
+		clock:                      clock.RealClock{},
+		reservationByExecutorGroup: make(map[string]*GangReservation),
+		timedOutGangIds:            make(map[string]bool),
+	}, nil
+}
+
+// NodeReservations returns a map from the id of each node of executorGroup reserved for a gang to the id of that gang,
+// after releasing the reservation of executorGroup if the gang is no longer queued or the reservation has timed out.
+// isQueuedGang reports whether the gang with the given id is still queued, i.e., has neither been scheduled nor cancelled.
+func (a *GangAccumulator) NodeReservations(executorGroup string, isQueuedGang func(gangId string) bool) map[string]string {
+	now := a.clock.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for gangId := range a.timedOutGangIds {
+		if !isQueuedGang(gangId) {
+			delete(a.timedOutGangIds, gangId)
+		}
+	}
+	reservation := a.reservationByExecutorGroup[executorGroup]
+	if reservation == nil {
+		return nil
+	}
+	if !isQueuedGang(reservation.GangId) {
+		log.Infof(
+			"releasing %d nodes in pool %s reserved for gang %s of queue %s, which is no longer queued",
+			len(reservation.NodeIds), reservation.Pool, reservation.GangId, reservation.Queue,
+		)
+		delete(a.reservationByExecutorGroup, executorGroup)
+		return nil
+	}
+	if a.isTimedOut(*reservation, now) {
+		log.Infof(
+			"releasing %d nodes in pool %s reserved for gang %s of queue %s, since the reservation timed out after %s",
+			len(reservation.NodeIds), reservation.Pool, reservation.GangId, reservation.Queue, a.config.Timeout,
+		)
+		a.timedOutGangIds[reservation.GangId] = true
+		delete(a.reservationByExecutorGroup, executorGroup)
+		return nil
+	}
+	rv := make(map[string]string, len(reservation.NodeIds))
+	for _, nodeId := range reservation.NodeIds {
+		rv[nodeId] = reservation.GangId
+	}
+	return rv
+}
+
+// isTimedOut returns true if reservation has been held for at least the configured timeout at now.
+func (a *GangAccumulator) isTimedOut(reservation GangReservation, now time.Time) bool {
+	return now.Sub(reservation.Reserved) >= a.config.Timeout
+}
+
+// Reserve reserves nodes of nodeDb for the gang of gctx, which failed to schedule onto the nodes of executorGroup.
+// Does nothing if nodes of executorGroup are already reserved, if the reservation of this gang previously timed out,
+// or if there's no set of nodes the gang would fit onto once empty.
+func (a *GangAccumulator) Reserve(executorGroup string, pool string, gctx *schedulercontext.GangSchedulingContext, nodeDb *nodedb.NodeDb) error {
+	if len(gctx.JobSchedulingContexts) == 0 {
+		return nil
+	}
+	gangId := gctx.JobSchedulingContexts[0].PodRequirements.Annotations[configuration.GangIdAnnotation]
+	now := a.clock.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.reservationByExecutorGroup[executorGroup] != nil || a.timedOutGangIds[gangId] {
+		return nil
+	}
+	nodeIds, err := nodeDb.NodeIdsToReserveForGang(gctx)
+	if err != nil {
+		return err
+	}
+	if len(nodeIds) == 0 {
+		return nil
+	}
+	a.reservationByExecutorGroup[executorGroup] = &GangReservation{
+		GangId:   gangId,
+		Queue:    gctx.Queue,
+		Pool:     pool,
+		NodeIds:  nodeIds,
+		Reserved: now,
+	}
+	log.Infof(
+		"reserving %d nodes in pool %s for gang %s of queue %s with cardinality %d",
+		len(nodeIds), pool, gangId, gctx.Queue, gctx.Cardinality(),
+	)
+	return nil
+}
+
+// Reservations returns all current reservations, sorted by pool and gang id.
+func (a *GangAccumulator) Reservations() []GangReservation {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	rv := make([]GangReservation, 0, len(a.reservationByExecutorGroup))
+	for _, reservation := range a.reservationByExecutorGroup {
+		r := *reservation
+		r.NodeIds = slices.Clone(reservation.NodeIds)
+		rv = append(rv, r)
+	}
+	slices.SortFunc(rv, func(a, b GangReservation) bool {
+		if a.Pool != b.Pool {
+			return a.Pool < b.Pool
+		}
+		return a.GangId < b.GangId
+	})
+	return rv
+}
+
+// GangAccumulationHttpHandler serves the current gang reservations as json.
+type GangAccumulationHttpHandler struct {
+	accumulator *GangAccumulator
+}
+
+func NewGangAccumulationHttpHandler(accumulator *GangAccumulator) *GangAccumulationHttpHandler {
+	return &GangAccumulationHttpHandler{accumulator: accumulator}
+}
+
+func (h *GangAccumulationHttpHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.accumulator.Reservations()); err != nil {
+		log.WithError(err).Error("failed to write gang accumulation response")
+	}
+}
@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestGangAccumulator(t *testing.T) {
+	accumulator, err := NewGangAccumulator(configuration.GangAccumulationConfig{
+		Enabled:            true,
+		MinGangCardinality: 2,
+		Timeout:            time.Hour,
+	})
+	require.NoError(t, err)
+	fakeClock := clock.NewFakeClock(time.Date(2023, 11, 15, 6, 0, 0, 0, time.UTC))
+	accumulator.clock = fakeClock
+
+	nodeDb, err := NewNodeDb()
+	require.NoError(t, err)
+	txn := nodeDb.Txn(true)
+	for _, node := range testfixtures.N32CpuNodes(3, testfixtures.TestPriorities) {
+		require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, nil, node))
+	}
+	txn.Commit()
+
+	// Gangs requiring 48 cpus, i.e., two of the three nodes.
+	newGang := func() (*schedulercontext.GangSchedulingContext, string) {
+		jobs := testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 48))
+		gctx := schedulercontext.NewGangSchedulingContext(jobSchedulingContextsFromJobs(testfixtures.TestPriorityClasses, jobs))
+		return gctx, gctx.JobSchedulingContexts[0].PodRequirements.Annotations[configuration.GangIdAnnotation]
+	}
+	gctx, gangId := newGang()
+	otherGctx, otherGangId := newGang()
+	queuedGangIds := map[string]bool{gangId: true, otherGangId: true}
+	isQueuedGang := func(gangId string) bool { return queuedGangIds[gangId] }
+
+	assert.Empty(t, accumulator.NodeReservations("executor", isQueuedGang))
+	require.NoError(t, accumulator.Reserve("executor", "pool", gctx, nodeDb))
+	reservations := accumulator.NodeReservations("executor", isQueuedGang)
+	assert.Len(t, reservations, 2)
+	for _, reservedFor := range reservations {
+		assert.Equal(t, gangId, reservedFor)
+	}
+	assert.Empty(t, accumulator.NodeReservations("otherExecutor", isQueuedGang))
+
+	// Only one gang accumulates capacity per executor group at a time.
+	require.NoError(t, accumulator.Reserve("executor", "pool", otherGctx, nodeDb))
+	assert.Equal(t, reservations, accumulator.NodeReservations("executor", isQueuedGang))
+	require.Len(t, accumulator.Reservations(), 1)
+	assert.Equal(t, gangId, accumulator.Reservations()[0].GangId)
+
+	// Reservations time out, after which the gang isn't reserved nodes for again while queued.
+	fakeClock.Step(time.Hour)
+	assert.Empty(t, accumulator.NodeReservations("executor", isQueuedGang))
+	require.NoError(t, accumulator.Reserve("executor", "pool", gctx, nodeDb))
+	assert.Empty(t, accumulator.NodeReservations("executor", isQueuedGang))
+
+	// Other gangs may accumulate capacity once the reservation is released.
+	require.NoError(t, accumulator.Reserve("executor", "pool", otherGctx, nodeDb))
+	for _, reservedFor := range accumulator.NodeReservations("executor", isQueuedGang) {
+		assert.Equal(t, otherGangId, reservedFor)
+	}
+
+	// Reservations are released once the gang is no longer queued.
+	delete(queuedGangIds, otherGangId)
+	assert.Empty(t, accumulator.NodeReservations("executor", isQueuedGang))
+	assert.Empty(t, accumulator.Reservations())
+
+	// Gangs that don't fit onto the pool even if all nodes were empty aren't reserved nodes for.
+	jobs := testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 97))
+	require.NoError(t, accumulator.Reserve("executor", "pool", schedulercontext.NewGangSchedulingContext(jobSchedulingContextsFromJobs(testfixtures.TestPriorityClasses, jobs)), nodeDb))
+	assert.Empty(t, accumulator.Reservations())
+}
+
+func TestNewGangAccumulator_InvalidConfig(t *testing.T) {
+	_, err := NewGangAccumulator(configuration.GangAccumulationConfig{MinGangCardinality: 1, Timeout: time.Hour})
+	assert.Error(t, err)
+	_, err = NewGangAccumulator(configuration.GangAccumulationConfig{MinGangCardinality: 16})
+	assert.Error(t, err)
+}
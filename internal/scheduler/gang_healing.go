@@ -0,0 +1,220 @@
+package scheduler
+
+import (
+	"github.com/gogo/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+// GangHealer replaces members of running gangs that failed since, e.g., their node was lost or they ran out of memory,
+// instead of leaving the gang short of those members until it's resubmitted.
+// The failed member is requeued as a replacement, which is scheduled as a remainder gang of the running gang
+// and, if the gang has a node uniformity label, onto nodes with the same value as the surviving members.
+type GangHealer struct {
+	// Ids of the members of each gang that may be running, indexed by gang healing key; see gangHealingKey.
+	// Maintained from the jobs updated each cycle, such that surviving members are found without scanning the jobDb.
+	// Since jobs may leave the jobDb without being seen again, members are checked against the jobDb when looked up.
+	runningJobIdsByGangKey map[string]map[string]bool
+}
+
+func NewGangHealer() *GangHealer {
+	return &GangHealer{runningJobIdsByGangKey: make(map[string]map[string]bool)}
+}
+
+// Replacements returns, indexed by job id, the replacement for each failed job among jobs that should be healed,
+// i.e., each member of a gang with other members still running whose last run failed for a reason healing applies to.
+// Jobs with the fail fast annotation set and jobs that already reached maxAttemptedRuns aren't healed.
+// Replacements are requeued copies of the failed job, pinned to the node uniformity label value of the surviving members.
+// jobs must include all jobs updated since the previous call; executors returns the executors loaded by the cycle,
+// and is only called if there are failed jobs to replace.
+func (h *GangHealer) Replacements(
+	ctx *armadacontext.Context,
+	jobs []*jobdb.Job,
+	jobRunErrors map[uuid.UUID]*armadaevents.Error,
+	txn *jobdb.Txn,
+	executors func() ([]*schedulerobjects.Executor, error),
+	maxAttemptedRuns uint,
+) (map[string]*jobdb.Job, error) {
+	h.updateRunningJobIds(jobs)
+	failedJobsByGangKey := make(map[string][]*jobdb.Job)
+	for _, job := range jobs {
+		lastRun := job.LatestRun()
+		if job.Queued() || lastRun == nil || !lastRun.Failed() || lastRun.Returned() {
+			// Returned runs are requeued, or failed, regardless of healing.
+			continue
+		}
+		if job.GetAnnotations()[configuration.FailFastAnnotation] == "true" || job.NumAttempts() >= maxAttemptedRuns {
+			continue
+		}
+		if !isHealableRunError(jobRunErrors[lastRun.Id()]) {
+			continue
+		}
+		if key, ok := gangHealingKey(job); ok {
+			failedJobsByGangKey[key] = append(failedJobsByGangKey[key], job)
+		}
+	}
+	if len(failedJobsByGangKey) == 0 {
+		return nil, nil
+	}
+
+	// Find the runs of the surviving members of each of these gangs.
+	survivingRunsByGangKey := make(map[string][]*jobdb.JobRun)
+	for key := range failedJobsByGangKey {
+		for jobId := range h.runningJobIdsByGangKey[key] {
+			job := txn.GetById(jobId)
+			if job == nil || !isRunningGangMember(job) {
+				delete(h.runningJobIdsByGangKey[key], jobId)
+				continue
+			}
+			survivingRunsByGangKey[key] = append(survivingRunsByGangKey[key], job.LatestRun())
+		}
+		if len(h.runningJobIdsByGangKey[key]) == 0 {
+			delete(h.runningJobIdsByGangKey, key)
+		}
+	}
+	if len(survivingRunsByGangKey) == 0 {
+		return nil, nil
+	}
+
+	loadedExecutors, err := executors()
+	if err != nil {
+		return nil, err
+	}
+	nodesByExecutorIdAndName := nodesByExecutorIdAndName(loadedExecutors)
+	rv := make(map[string]*jobdb.Job)
+	for key, survivingRuns := range survivingRunsByGangKey {
+		for _, job := range failedJobsByGangKey[key] {
+			replacement, err := replacementGangJob(job, survivingRuns, nodesByExecutorIdAndName)
+			if err != nil {
+				return nil, err
+			}
+			ctx.Infof("replacing job %s of gang %s, which has %d surviving members", job.Id(), key, len(survivingRuns))
+			rv[job.Id()] = replacement
+		}
+	}
+	return rv, nil
+}
+
+// updateRunningJobIds updates the index of the members of each gang that may be running with the provided updated jobs.
+func (h *GangHealer) updateRunningJobIds(jobs []*jobdb.Job) {
+	for _, job := range jobs {
+		key, ok := gangHealingKey(job)
+		if !ok {
+			continue
+		}
+		if isRunningGangMember(job) {
+			if h.runningJobIdsByGangKey[key] == nil {
+				h.runningJobIdsByGangKey[key] = make(map[string]bool)
+			}
+			h.runningJobIdsByGangKey[key][job.Id()] = true
+		} else if jobIds := h.runningJobIdsByGangKey[key]; jobIds != nil {
+			delete(jobIds, job.Id())
+			if len(jobIds) == 0 {
+				delete(h.runningJobIdsByGangKey, key)
+			}
+		}
+	}
+}
+
+// isRunningGangMember returns true if job is leased and its latest run hasn't terminated, i.e., if it survives failed members of its gang.
+func isRunningGangMember(job *jobdb.Job) bool {
+	if job.Queued() || job.InTerminalState() {
+		return false
+	}
+	run := job.LatestRun()
+	return run != nil && !run.InTerminalState()
+}
+
+// gangHealingKey returns the id of the gang the job belongs to, qualified by the queue owning the gang,
+// and true if the job belongs to a gang of more than one job.
+func gangHealingKey(job *jobdb.Job) (string, bool) {
+	gangId, gangCardinality, _, isGangJob, err := GangIdAndCardinalityFromAnnotations(job.GetAnnotations())
+	if err != nil || !isGangJob || gangCardinality < 2 {
+		return "", false
+	}
+	queue := job.Queue()
+	if gangQueue := job.GetAnnotations()[configuration.GangQueueAnnotation]; gangQueue != "" {
+		queue = gangQueue
+	}
+	return queue + "/" + gangId, true
+}
+
+// isHealableRunError returns true if a run failing with runError should be healed, i.e.,
+// if its lease expired, e.g., since its node was lost, or if its pod was evicted or ran out of memory.
+func isHealableRunError(runError *armadaevents.Error) bool {
+	if runError.GetLeaseExpired() != nil {
+		return true
+	}
+	podError := runError.GetPodError()
+	if podError == nil {
+		return false
+	}
+	if isHealableKubernetesReason(podError.KubernetesReason) {
+		return true
+	}
+	for _, containerError := range podError.ContainerErrors {
+		if isHealableKubernetesReason(containerError.KubernetesReason) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHealableKubernetesReason(reason armadaevents.KubernetesReason) bool {
+	return reason == armadaevents.KubernetesReason_Evicted || reason == armadaevents.KubernetesReason_OOM
+}
+
+// replacementGangJob returns a requeued copy of the failed gang member job.
+// If the gang has node uniformity labels, the copy is pinned via its node selector to the value of the finest of these labels
+// shared by the nodes of all surviving runs, if any.
+func replacementGangJob(
+	job *jobdb.Job,
+	survivingRuns []*jobdb.JobRun,
+	nodesByExecutorIdAndName map[string]map[string]*schedulerobjects.Node,
+) (*jobdb.Job, error) {
+	labels := schedulercontext.NodeUniformityLabelsFromAnnotation(job.GetAnnotations()[configuration.GangNodeUniformityLabelAnnotation])
+	for i := len(labels) - 1; i >= 0; i-- {
+		value, ok := sharedNodeLabelValue(labels[i], survivingRuns, nodesByExecutorIdAndName)
+		if !ok {
+			continue
+		}
+		schedulingInfo := proto.Clone(job.JobSchedulingInfo()).(*schedulerobjects.JobSchedulingInfo)
+		schedulingInfo.Version = job.JobSchedulingInfo().Version + 1
+		podRequirements := schedulingInfo.GetPodRequirements()
+		if podRequirements == nil {
+			return nil, errors.Errorf("no pod scheduling requirement found for job %s", job.Id())
+		}
+		if podRequirements.NodeSelector == nil {
+			podRequirements.NodeSelector = make(map[string]string)
+		}
+		podRequirements.NodeSelector[labels[i]] = value
+		job = job.WithJobSchedulingInfo(schedulingInfo)
+		break
+	}
+	return job, nil
+}
+
+// sharedNodeLabelValue returns the value of label shared by the nodes of all runs, and true, if there is one.
+// Nodes no longer reported by their executor are ignored.
+func sharedNodeLabelValue(label string, runs []*jobdb.JobRun, nodesByExecutorIdAndName map[string]map[string]*schedulerobjects.Node) (string, bool) {
+	sharedValue := ""
+	for _, run := range runs {
+		node, ok := nodesByExecutorIdAndName[run.Executor()][run.NodeName()]
+		if !ok {
+			continue
+		}
+		value, ok := node.Labels[label]
+		if !ok || (sharedValue != "" && value != sharedValue) {
+			return "", false
+		}
+		sharedValue = value
+	}
+	return sharedValue, sharedValue != ""
+}
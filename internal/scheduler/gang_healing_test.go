@@ -0,0 +1,144 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+func TestGangHealer_Replacements(t *testing.T) {
+	oomError := &armadaevents.Error{
+		Reason: &armadaevents.Error_PodError{
+			PodError: &armadaevents.PodError{
+				ContainerErrors: []*armadaevents.ContainerError{{KubernetesReason: armadaevents.KubernetesReason_OOM}},
+			},
+		},
+	}
+	appError := &armadaevents.Error{
+		Reason: &armadaevents.Error_PodError{PodError: &armadaevents.PodError{KubernetesReason: armadaevents.KubernetesReason_AppError}},
+	}
+	executor := &schedulerobjects.Executor{Id: "executor"}
+	for i, zone := range []string{"b", "a", "a"} {
+		executor.Nodes = append(executor.Nodes, &schedulerobjects.Node{
+			Id:     fmt.Sprintf("node-%d", i),
+			Name:   fmt.Sprintf("node-%d", i),
+			Labels: map[string]string{"zone": zone},
+		})
+	}
+	// Returns a gang of three running jobs, one per node.
+	gang := func() []*jobdb.Job {
+		jobs := testfixtures.WithNodeUniformityLabelAnnotationJobs(
+			"zone",
+			testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 3)),
+		)
+		for i, job := range jobs {
+			job = job.WithQueued(false).WithNewRun("executor", fmt.Sprintf("node-%d", i), fmt.Sprintf("node-%d", i))
+			jobs[i] = job.WithUpdatedRun(job.LatestRun().WithAttempted(true).WithRunning(true))
+		}
+		return jobs
+	}
+
+	tests := map[string]struct {
+		jobs     []*jobdb.Job
+		runError *armadaevents.Error
+		// If true, the surviving members failed too.
+		survivorsFailed  bool
+		maxAttemptedRuns uint
+		expectReplaced   bool
+	}{
+		"out of memory": {
+			jobs:             gang(),
+			runError:         oomError,
+			maxAttemptedRuns: 3,
+			expectReplaced:   true,
+		},
+		"lease expired": {
+			jobs:             gang(),
+			runError:         &armadaevents.Error{Reason: &armadaevents.Error_LeaseExpired{LeaseExpired: &armadaevents.LeaseExpired{}}},
+			maxAttemptedRuns: 3,
+			expectReplaced:   true,
+		},
+		"application error": {
+			jobs:             gang(),
+			runError:         appError,
+			maxAttemptedRuns: 3,
+		},
+		"maximum attempts reached": {
+			jobs:             gang(),
+			runError:         oomError,
+			maxAttemptedRuns: 1,
+		},
+		"no surviving members": {
+			jobs:             gang(),
+			runError:         oomError,
+			survivorsFailed:  true,
+			maxAttemptedRuns: 3,
+		},
+		"not a gang": {
+			jobs: []*jobdb.Job{
+				testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).WithQueued(false).WithNewRun("executor", "node-0", "node-0"),
+			},
+			runError:         oomError,
+			maxAttemptedRuns: 3,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			numExecutorLoads := 0
+			executors := func() ([]*schedulerobjects.Executor, error) {
+				numExecutorLoads++
+				return []*schedulerobjects.Executor{executor}, nil
+			}
+			healer := NewGangHealer()
+
+			// The healer sees the gang running in an earlier cycle.
+			jobs := tc.jobs
+			txn := testfixtures.NewJobDb().WriteTxn()
+			require.NoError(t, txn.Upsert(jobs))
+			replacementByJobId, err := healer.Replacements(armadacontext.Background(), jobs, nil, txn, executors, tc.maxAttemptedRuns)
+			require.NoError(t, err)
+			assert.Empty(t, replacementByJobId)
+
+			jobs[0] = jobs[0].WithUpdatedRun(jobs[0].LatestRun().WithRunning(false).WithFailed(true))
+			updatedJobs := jobs[:1]
+			if tc.survivorsFailed {
+				for i := 1; i < len(jobs); i++ {
+					jobs[i] = jobs[i].WithFailed(true).WithUpdatedRun(jobs[i].LatestRun().WithRunning(false).WithFailed(true))
+				}
+				updatedJobs = jobs
+			}
+			require.NoError(t, txn.Upsert(jobs))
+			replacementByJobId, err = healer.Replacements(
+				armadacontext.Background(),
+				updatedJobs,
+				map[uuid.UUID]*armadaevents.Error{jobs[0].LatestRun().Id(): tc.runError},
+				txn,
+				executors,
+				tc.maxAttemptedRuns,
+			)
+			require.NoError(t, err)
+			if !tc.expectReplaced {
+				assert.Empty(t, replacementByJobId)
+				// Executors are only loaded if a job is replaced.
+				assert.Equal(t, 0, numExecutorLoads)
+				return
+			}
+			assert.Equal(t, 1, numExecutorLoads)
+			require.Len(t, replacementByJobId, 1)
+			replacement := replacementByJobId[jobs[0].Id()]
+			require.NotNil(t, replacement)
+			// Replacements are pinned to the zone of the surviving members.
+			assert.Equal(t, "a", replacement.PodRequirements().NodeSelector["zone"])
+			assert.Equal(t, jobs[0].JobSchedulingInfo().Version+1, replacement.JobSchedulingInfo().Version)
+		})
+	}
+}
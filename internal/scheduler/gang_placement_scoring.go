@@ -0,0 +1,153 @@
+package scheduler
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/nodedb"
+)
+
+// GangPlacementScorer scores candidate placements of a gang, e.g., onto nodes with different values of the gang's node uniformity label.
+// GangScheduler chooses the placement with the smallest score.
+type GangPlacementScorer interface {
+	// Score returns the score of the placement recorded in the job scheduling contexts of gctx,
+	// where txn reflects the state of nodeDb with the gang placed. Placements for which false is returned aren't considered.
+	Score(nodeDb *nodedb.NodeDb, txn *memdb.Txn, gctx *schedulercontext.GangSchedulingContext) (float64, bool, error)
+	// MinScore returns a score no placement can improve on. Once a placement with this score is found, no other placements are tried.
+	MinScore() float64
+}
+
+// NewGangPlacementScorer returns the GangPlacementScorer configured for the given pool.
+func NewGangPlacementScorer(config configuration.SchedulingConfig, pool string) (GangPlacementScorer, error) {
+	switch scoring := config.GangPlacementScoringForPool(pool); scoring {
+	case configuration.MeanScheduledAtPriority:
+		return MeanScheduledAtPriorityScorer{}, nil
+	case configuration.PreemptionCount:
+		return PreemptionCountScorer{}, nil
+	case configuration.Fragmentation:
+		return FragmentationScorer{}, nil
+	case configuration.NodeCost:
+		if config.GangPlacementNodeCostLabel == "" {
+			return nil, errors.Errorf("gang placement scoring %s requires a node cost label", scoring)
+		}
+		return NodeCostScorer{NodeCostLabel: config.GangPlacementNodeCostLabel}, nil
+	default:
+		return nil, errors.Errorf("unknown gang placement scoring %s for pool %s", scoring, pool)
+	}
+}
+
+// MeanScheduledAtPriorityScorer scores placements by the mean priority the jobs of the gang are scheduled at.
+type MeanScheduledAtPriorityScorer struct{}
+
+func (MeanScheduledAtPriorityScorer) Score(_ *nodedb.NodeDb, _ *memdb.Txn, gctx *schedulercontext.GangSchedulingContext) (float64, bool, error) {
+	score, ok := meanScheduledAtPriorityFromGctx(gctx)
+	return score, ok, nil
+}
+
+func (MeanScheduledAtPriorityScorer) MinScore() float64 {
+	return float64(nodedb.MinPriority)
+}
+
+// PreemptionCountScorer scores placements by the number of jobs of the gang that only fit by preempting other jobs.
+type PreemptionCountScorer struct{}
+
+func (PreemptionCountScorer) Score(_ *nodedb.NodeDb, _ *memdb.Txn, gctx *schedulercontext.GangSchedulingContext) (float64, bool, error) {
+	n := 0
+	for _, jctx := range gctx.JobSchedulingContexts {
+		if jctx.PodSchedulingContext == nil {
+			return 0, false, nil
+		}
+		if jctx.PodSchedulingContext.PreemptionRequired {
+			n++
+		}
+	}
+	return float64(n), true, nil
+}
+
+func (PreemptionCountScorer) MinScore() float64 {
+	return 0
+}
+
+// FragmentationScorer scores placements by the resources left unallocated on the nodes the gang is placed on,
+// summed over those nodes, with the resources left on each node given as the mean over resource types of the fraction left.
+type FragmentationScorer struct{}
+
+func (FragmentationScorer) Score(nodeDb *nodedb.NodeDb, txn *memdb.Txn, gctx *schedulercontext.GangSchedulingContext) (float64, bool, error) {
+	nodes, ok, err := nodesFromGctx(nodeDb, txn, gctx)
+	if err != nil || !ok {
+		return 0, ok, err
+	}
+	score := 0.0
+	for _, node := range nodes {
+		sum := 0.0
+		n := 0
+		for t, total := range node.TotalResources.Resources {
+			if total.Sign() <= 0 {
+				continue
+			}
+			unallocated := node.AllocatableByPriority.Get(nodedb.MinPriority, t)
+			sum += math.Max(0, float64(unallocated.MilliValue())/float64(total.MilliValue()))
+			n++
+		}
+		if n > 0 {
+			score += sum / float64(n)
+		}
+	}
+	return score, true, nil
+}
+
+func (FragmentationScorer) MinScore() float64 {
+	return 0
+}
+
+// NodeCostScorer scores placements by the total cost of the nodes the gang is placed on, as given by the value of NodeCostLabel.
+type NodeCostScorer struct {
+	NodeCostLabel string
+}
+
+func (s NodeCostScorer) Score(nodeDb *nodedb.NodeDb, txn *memdb.Txn, gctx *schedulercontext.GangSchedulingContext) (float64, bool, error) {
+	nodes, ok, err := nodesFromGctx(nodeDb, txn, gctx)
+	if err != nil || !ok {
+		return 0, ok, err
+	}
+	score := 0.0
+	for _, node := range nodes {
+		if cost, err := strconv.ParseFloat(node.Labels[s.NodeCostLabel], 64); err == nil && cost > 0 {
+			score += cost
+		}
+	}
+	return score, true, nil
+}
+
+func (NodeCostScorer) MinScore() float64 {
+	return 0
+}
+
+// nodesFromGctx returns the distinct nodes the jobs of gctx are placed on, or false if any job wasn't attempted.
+func nodesFromGctx(nodeDb *nodedb.NodeDb, txn *memdb.Txn, gctx *schedulercontext.GangSchedulingContext) ([]*nodedb.Node, bool, error) {
+	seen := make(map[string]bool)
+	var nodes []*nodedb.Node
+	for _, jctx := range gctx.JobSchedulingContexts {
+		pctx := jctx.PodSchedulingContext
+		if pctx == nil {
+			return nil, false, nil
+		}
+		if pctx.NodeId == "" || seen[pctx.NodeId] {
+			continue
+		}
+		seen[pctx.NodeId] = true
+		node, err := nodeDb.GetNodeWithTxn(txn, pctx.NodeId)
+		if err != nil {
+			return nil, false, err
+		}
+		if node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, true, nil
+}
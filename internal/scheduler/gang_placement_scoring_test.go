@@ -0,0 +1,234 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	armadaslices "github.com/armadaproject/armada/internal/common/slices"
+	schedulerconstraints "github.com/armadaproject/armada/internal/scheduler/constraints"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/fairness"
+	"github.com/armadaproject/armada/internal/scheduler/nodedb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestNewGangPlacementScorer(t *testing.T) {
+	config := testfixtures.TestSchedulingConfig()
+	config.GangPlacementScoring = configuration.Fragmentation
+	config.GangPlacementScoringByPool = map[string]configuration.GangPlacementScoring{
+		"cheap":   configuration.NodeCost,
+		"invalid": "foo",
+	}
+
+	scorer, err := NewGangPlacementScorer(config, "pool")
+	require.NoError(t, err)
+	assert.Equal(t, FragmentationScorer{}, scorer)
+
+	// NodeCost requires a node cost label.
+	_, err = NewGangPlacementScorer(config, "cheap")
+	assert.Error(t, err)
+	config.GangPlacementNodeCostLabel = "cost"
+	scorer, err = NewGangPlacementScorer(config, "cheap")
+	require.NoError(t, err)
+	assert.Equal(t, NodeCostScorer{NodeCostLabel: "cost"}, scorer)
+
+	_, err = NewGangPlacementScorer(config, "invalid")
+	assert.Error(t, err)
+
+	// Placements are scored by mean scheduled-at priority by default.
+	scorer, err = NewGangPlacementScorer(testfixtures.TestSchedulingConfig(), "pool")
+	require.NoError(t, err)
+	assert.Equal(t, MeanScheduledAtPriorityScorer{}, scorer)
+}
+
+func TestGangScheduler_NodeCostPlacementScoring(t *testing.T) {
+	config := testfixtures.WithIndexedNodeLabelsConfig([]string{"zone"}, testfixtures.TestSchedulingConfig())
+	config.GangPlacementScoring = configuration.NodeCost
+	config.GangPlacementNodeCostLabel = "cost"
+	nodes := append(
+		testfixtures.WithLabelsNodes(
+			map[string]string{"zone": "a", "cost": "10"},
+			testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+		),
+		testfixtures.WithLabelsNodes(
+			map[string]string{"zone": "b", "cost": "1"},
+			testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+		)...,
+	)
+	nodeDb, err := nodedb.NewNodeDb(
+		testfixtures.TestPriorityClasses,
+		testfixtures.TestMaxExtraNodesToConsider,
+		config.IndexedResources,
+		testfixtures.TestIndexedTaints,
+		config.IndexedNodeLabels,
+	)
+	require.NoError(t, err)
+	txn := nodeDb.Txn(true)
+	for _, node := range nodes {
+		require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, nil, node))
+	}
+	txn.Commit()
+
+	totalResources := nodeDb.TotalResources()
+	fairnessCostProvider, err := fairness.NewDominantResourceFairness(
+		totalResources,
+		config.DominantResourceFairnessResourcesToConsider,
+	)
+	require.NoError(t, err)
+	sctx := schedulercontext.NewSchedulingContext(
+		"executor",
+		"pool",
+		config.Preemption.PriorityClasses,
+		config.Preemption.DefaultPriorityClass,
+		fairnessCostProvider,
+		rate.NewLimiter(rate.Limit(config.MaximumSchedulingRate), config.MaximumSchedulingBurst),
+		totalResources,
+	)
+	require.NoError(t, sctx.AddQueueSchedulingContext(
+		"A",
+		1,
+		nil,
+		rate.NewLimiter(rate.Limit(config.MaximumPerQueueSchedulingRate), config.MaximumPerQueueSchedulingBurst),
+	))
+	constraints := schedulerconstraints.SchedulingConstraintsFromSchedulingConfig(
+		"pool",
+		totalResources,
+		schedulerobjects.ResourceList{},
+		config,
+	)
+	sch, err := NewGangScheduler(sctx, constraints, nodeDb)
+	require.NoError(t, err)
+	scorer, err := NewGangPlacementScorer(config, "pool")
+	require.NoError(t, err)
+	sch.SetPlacementScorer(scorer)
+
+	// The gang fits equally well in either zone; it should be placed in the cheaper one.
+	jobs := testfixtures.WithGangAnnotationsJobs(
+		testfixtures.WithNodeUniformityLabelAnnotationJobs(
+			"zone",
+			testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 2),
+		),
+	)
+	jctxs := jobSchedulingContextsFromJobs(testfixtures.TestPriorityClasses, jobs)
+	ok, reason, err := sch.Schedule(armadacontext.Background(), schedulercontext.NewGangSchedulingContext(jctxs))
+	require.NoError(t, err)
+	require.True(t, ok, reason)
+	for _, jctx := range jctxs {
+		require.NotNil(t, jctx.PodSchedulingContext)
+		node, err := nodeDb.GetNode(jctx.PodSchedulingContext.NodeId)
+		require.NoError(t, err)
+		assert.Equal(t, "b", node.Labels["zone"])
+	}
+}
+
+func TestGangScheduler_PlacementTieBreak(t *testing.T) {
+	tests := map[string]struct {
+		tieBreak      configuration.GangPlacementTieBreak
+		expectedValue string
+	}{
+		"default": {
+			expectedValue: "a",
+		},
+		"lexicographic": {
+			tieBreak:      configuration.Lexicographic,
+			expectedValue: "a",
+		},
+		"fewest nodes": {
+			tieBreak:      configuration.FewestNodes,
+			expectedValue: "b",
+		},
+		"most headroom": {
+			tieBreak:      configuration.MostHeadroom,
+			expectedValue: "c",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := testfixtures.WithIndexedNodeLabelsConfig([]string{"zone"}, testfixtures.TestSchedulingConfig())
+			config.GangPlacementTieBreak = tc.tieBreak
+			// The gang fits in every zone without preemption, i.e., with equal score.
+			// In zone a, it's spread across two partially allocated nodes,
+			// in zone b, it fills the only node, and in zone c, it leaves the most resources unallocated.
+			nodes := armadaslices.Concatenate(
+				testfixtures.WithLabelsNodes(
+					map[string]string{"zone": "a"},
+					testfixtures.WithUsedResourcesNodes(
+						0,
+						schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("16")}},
+						testfixtures.N32CpuNodes(4, testfixtures.TestPriorities),
+					),
+				),
+				testfixtures.WithLabelsNodes(map[string]string{"zone": "b"}, testfixtures.N32CpuNodes(1, testfixtures.TestPriorities)),
+				testfixtures.WithLabelsNodes(map[string]string{"zone": "c"}, testfixtures.N32CpuNodes(3, testfixtures.TestPriorities)),
+			)
+			nodeDb, err := nodedb.NewNodeDb(
+				testfixtures.TestPriorityClasses,
+				testfixtures.TestMaxExtraNodesToConsider,
+				config.IndexedResources,
+				testfixtures.TestIndexedTaints,
+				config.IndexedNodeLabels,
+			)
+			require.NoError(t, err)
+			txn := nodeDb.Txn(true)
+			for _, node := range nodes {
+				require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, nil, node))
+			}
+			txn.Commit()
+
+			totalResources := nodeDb.TotalResources()
+			fairnessCostProvider, err := fairness.NewDominantResourceFairness(
+				totalResources,
+				config.DominantResourceFairnessResourcesToConsider,
+			)
+			require.NoError(t, err)
+			sctx := schedulercontext.NewSchedulingContext(
+				"executor",
+				"pool",
+				config.Preemption.PriorityClasses,
+				config.Preemption.DefaultPriorityClass,
+				fairnessCostProvider,
+				rate.NewLimiter(rate.Limit(config.MaximumSchedulingRate), config.MaximumSchedulingBurst),
+				totalResources,
+			)
+			require.NoError(t, sctx.AddQueueSchedulingContext(
+				"A",
+				1,
+				nil,
+				rate.NewLimiter(rate.Limit(config.MaximumPerQueueSchedulingRate), config.MaximumPerQueueSchedulingBurst),
+			))
+			constraints := schedulerconstraints.SchedulingConstraintsFromSchedulingConfig(
+				"pool",
+				totalResources,
+				schedulerobjects.ResourceList{},
+				config,
+			)
+			sch, err := NewGangScheduler(sctx, constraints, nodeDb)
+			require.NoError(t, err)
+			sch.SetPlacementTieBreak(config.GangPlacementTieBreak)
+
+			jobs := testfixtures.WithGangAnnotationsJobs(
+				testfixtures.WithNodeUniformityLabelAnnotationJobs(
+					"zone",
+					testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 2),
+				),
+			)
+			jctxs := jobSchedulingContextsFromJobs(testfixtures.TestPriorityClasses, jobs)
+			ok, reason, err := sch.Schedule(armadacontext.Background(), schedulercontext.NewGangSchedulingContext(jctxs))
+			require.NoError(t, err)
+			require.True(t, ok, reason)
+			for _, jctx := range jctxs {
+				require.NotNil(t, jctx.PodSchedulingContext)
+				node, err := nodeDb.GetNode(jctx.PodSchedulingContext.NodeId)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedValue, node.Labels["zone"])
+			}
+		})
+	}
+}
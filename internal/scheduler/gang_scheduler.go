@@ -2,11 +2,17 @@ package scheduler
 
 import (
 	"fmt"
+	"math"
+	"strings"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/hashicorp/go-memdb"
+	"golang.org/x/exp/slices"
 
+	"github.com/armadaproject/armada/internal/armada/configuration"
 	"github.com/armadaproject/armada/internal/common/armadacontext"
 	"github.com/armadaproject/armada/internal/common/util"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
 	schedulerconstraints "github.com/armadaproject/armada/internal/scheduler/constraints"
 	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
 	"github.com/armadaproject/armada/internal/scheduler/interfaces"
@@ -21,6 +27,17 @@ type GangScheduler struct {
 	nodeDb            *nodedb.NodeDb
 	// If true, the unsuccessfulSchedulingKeys check is omitted.
 	skipUnsuccessfulSchedulingKeyCheck bool
+	// Maximum number of values of the node uniformity label to make scheduling attempts for per gang.
+	// If zero, all values are considered.
+	maxNodeUniformityLabelValuesToConsider uint
+	// Used to choose between candidate placements of gangs with a node uniformity constraint.
+	placementScorer GangPlacementScorer
+	// Used to choose between candidate placements with equal score. Lexicographic if empty.
+	placementTieBreak configuration.GangPlacementTieBreak
+	// If true, nodeDb transactions are aborted instead of committed; see DryRunSchedule.
+	dryRun bool
+	// If true, the nodes rejecting the jobs of gangs that don't fit are counted by reason; see diagnoseNodeRejections.
+	enableNodeRejectionDiagnostics bool
 }
 
 func NewGangScheduler(
@@ -32,6 +49,7 @@ func NewGangScheduler(
 		constraints:       constraints,
 		schedulingContext: sctx,
 		nodeDb:            nodeDb,
+		placementScorer:   MeanScheduledAtPriorityScorer{},
 	}, nil
 }
 
@@ -39,6 +57,22 @@ func (sch *GangScheduler) SkipUnsuccessfulSchedulingKeyCheck() {
 	sch.skipUnsuccessfulSchedulingKeyCheck = true
 }
 
+func (sch *GangScheduler) EnableNodeRejectionDiagnostics() {
+	sch.enableNodeRejectionDiagnostics = true
+}
+
+func (sch *GangScheduler) SetMaxNodeUniformityLabelValuesToConsider(n uint) {
+	sch.maxNodeUniformityLabelValuesToConsider = n
+}
+
+func (sch *GangScheduler) SetPlacementScorer(placementScorer GangPlacementScorer) {
+	sch.placementScorer = placementScorer
+}
+
+func (sch *GangScheduler) SetPlacementTieBreak(tieBreak configuration.GangPlacementTieBreak) {
+	sch.placementTieBreak = tieBreak
+}
+
 func (sch *GangScheduler) updateGangSchedulingContextOnSuccess(gctx *schedulercontext.GangSchedulingContext, gangAddedToSchedulingContext bool) error {
 	if !gangAddedToSchedulingContext {
 		// Nothing to do.
@@ -59,7 +93,7 @@ func (sch *GangScheduler) updateGangSchedulingContextOnSuccess(gctx *schedulerco
 	return nil
 }
 
-func (sch *GangScheduler) updateGangSchedulingContextOnFailure(gctx *schedulercontext.GangSchedulingContext, gangAddedToSchedulingContext bool, unschedulableReason string) error {
+func (sch *GangScheduler) updateGangSchedulingContextOnFailure(gctx *schedulercontext.GangSchedulingContext, gangAddedToSchedulingContext bool, unschedulableReason *schedulerobjects.UnschedulableReason) error {
 	// If the job was added to the context, remove it first.
 	if gangAddedToSchedulingContext {
 		failedJobs := util.Map(gctx.JobSchedulingContexts, func(jctx *schedulercontext.JobSchedulingContext) interfaces.LegacySchedulerJob { return jctx.Job })
@@ -71,7 +105,12 @@ func (sch *GangScheduler) updateGangSchedulingContextOnFailure(gctx *schedulerco
 	// Ensure all jobs have an unschedulableReason.
 	// Adding jobs with an unschedulableReason to the context ensures they're correctly accounted for as failed.
 	for _, jctx := range gctx.JobSchedulingContexts {
-		jctx.UnschedulableReason = unschedulableReason
+		jctx.UnschedulableReason = gangMemberUnschedulableReason(gctx, jctx, unschedulableReason)
+	}
+	if sch.enableNodeRejectionDiagnostics {
+		if err := sch.diagnoseNodeRejections(gctx); err != nil {
+			return err
+		}
 	}
 	if _, err := sch.schedulingContext.AddGangSchedulingContext(gctx); err != nil {
 		return err
@@ -81,7 +120,10 @@ func (sch *GangScheduler) updateGangSchedulingContextOnFailure(gctx *schedulerco
 	//
 	// Only record unfeasible scheduling keys for single-job gangs.
 	// Since a gang may be unschedulable even if all its members are individually schedulable.
-	if !sch.skipUnsuccessfulSchedulingKeyCheck && gctx.Cardinality() == 1 {
+	// Jobs unschedulable because of the limit of their job set, or because their deadline passed, say nothing about other jobs.
+	if !sch.skipUnsuccessfulSchedulingKeyCheck && gctx.Cardinality() == 1 &&
+		unschedulableReason.GetCode() != schedulerobjects.UnschedulableReasonCodeJobSetMaxRunningJobsExceeded &&
+		unschedulableReason.GetCode() != schedulerobjects.UnschedulableReasonCodeGangSchedulingDeadlineExceeded {
 		jctx := gctx.JobSchedulingContexts[0]
 		schedulingKey, ok := jctx.Job.GetSchedulingKey()
 		if !ok {
@@ -98,7 +140,23 @@ func (sch *GangScheduler) updateGangSchedulingContextOnFailure(gctx *schedulerco
 	return nil
 }
 
-func (sch *GangScheduler) Schedule(ctx *armadacontext.Context, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason string, err error) {
+func (sch *GangScheduler) Schedule(ctx *armadacontext.Context, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason *schedulerobjects.UnschedulableReason, err error) {
+	// Fail queued gangs that weren't scheduled within their deadline, even if we've hit any round limits.
+	// Remainder gangs belong to gangs already scheduled, so they're exempt,
+	// as are large gangs held since capacity they'd fit onto is forecast; those are attempted as usual.
+	if !gctx.AllJobsEvicted && gctx.ParentGangId == "" && gctx.SchedulingDeadlineExceeded(sch.schedulingContext.Started) &&
+		!sch.schedulingContext.ShouldHoldGang(gctx, sch.schedulingContext.Started) {
+		unschedulableReason = schedulerobjects.NewUnschedulableReason(
+			schedulerobjects.UnschedulableReasonCodeGangSchedulingDeadlineExceeded,
+			"gang was not scheduled within its scheduling deadline of %s from submission", gctx.SchedulingDeadline,
+		)
+		for _, jctx := range gctx.JobSchedulingContexts {
+			jctx.ShouldFail = true
+		}
+		err = sch.updateGangSchedulingContextOnFailure(gctx, false, unschedulableReason)
+		return
+	}
+
 	// Exit immediately if this is a new gang and we've hit any round limits.
 	if !gctx.AllJobsEvicted {
 		if ok, unschedulableReason, err = sch.constraints.CheckRoundConstraints(sch.schedulingContext, gctx.Queue); err != nil || !ok {
@@ -117,8 +175,11 @@ func (sch *GangScheduler) Schedule(ctx *armadacontext.Context, gctx *schedulerco
 		// Update rate-limiters to account for new successfully scheduled jobs.
 		if ok && !gctx.AllJobsEvicted {
 			sch.schedulingContext.Limiter.ReserveN(sch.schedulingContext.Started, gctx.Cardinality())
-			if qctx := sch.schedulingContext.QueueSchedulingContexts[gctx.Queue]; qctx != nil {
-				qctx.Limiter.ReserveN(sch.schedulingContext.Started, gctx.Cardinality())
+			queues, jctxsByQueue := gctx.JobSchedulingContextsByQueue()
+			for _, queue := range queues {
+				if qctx := sch.schedulingContext.QueueSchedulingContexts[queue]; qctx != nil {
+					qctx.Limiter.ReserveN(sch.schedulingContext.Started, len(jctxsByQueue[queue]))
+				}
 			}
 		}
 
@@ -139,86 +200,512 @@ func (sch *GangScheduler) Schedule(ctx *armadacontext.Context, gctx *schedulerco
 			return
 		}
 	}
-	return sch.trySchedule(ctx, gctx)
+	if ok, unschedulableReason, err = sch.trySchedule(ctx, gctx); err == nil && !ok {
+		sch.recordGangAccumulationCandidate(gctx)
+	}
+	return
+}
+
+// recordGangAccumulationCandidate records gctx as the gang to reserve capacity for across rounds,
+// if it's the first new gang this round of at least SchedulingContext.GangAccumulationMinCardinality jobs that didn't fit.
+// Remainder gangs belong to gangs already scheduled, so they're never recorded.
+func (sch *GangScheduler) recordGangAccumulationCandidate(gctx *schedulercontext.GangSchedulingContext) {
+	sctx := sch.schedulingContext
+	if sctx.GangAccumulationMinCardinality == 0 || sctx.GangAccumulationCandidate != nil {
+		return
+	}
+	if gctx.AllJobsEvicted || gctx.ParentGangId != "" || gctx.Cardinality() < sctx.GangAccumulationMinCardinality {
+		return
+	}
+	sctx.GangAccumulationCandidate = gctx
+}
+
+// GangPlacement is the outcome of a dry-run scheduling attempt for a gang.
+type GangPlacement struct {
+	// True if the gang would be scheduled.
+	Ok bool
+	// Reason the gang would not be scheduled. Nil if Ok is true.
+	UnschedulableReason *schedulerobjects.UnschedulableReason
+	// Id of the node each job of the gang would be bound to, indexed by job id.
+	NodeIdByJobId map[string]string
+	// Reason each job of the gang that would not be scheduled wouldn't be, indexed by job id.
+	// If the gang would be scheduled, only contains jobs beyond the minimum cardinality of the gang that don't fit.
+	UnschedulableReasonByJobId map[string]*schedulerobjects.UnschedulableReason
+}
+
+// DryRunSchedule determines whether the gang could be scheduled right now and where,
+// performing the same constraint checks and node binding attempts as Schedule.
+// Unlike Schedule, the SchedulingContext is not modified and nodeDb transactions are never committed.
+// The job scheduling contexts of gctx are updated to reflect the would-be placements.
+func (sch *GangScheduler) DryRunSchedule(ctx *armadacontext.Context, gctx *schedulercontext.GangSchedulingContext) (*GangPlacement, error) {
+	ok, unschedulableReason, err := sch.dryRunSchedule(ctx, gctx)
+	if err != nil {
+		return nil, err
+	}
+	rv := &GangPlacement{
+		Ok:                         ok,
+		UnschedulableReason:        unschedulableReason,
+		NodeIdByJobId:              make(map[string]string),
+		UnschedulableReasonByJobId: make(map[string]*schedulerobjects.UnschedulableReason),
+	}
+	for _, jctx := range gctx.JobSchedulingContexts {
+		if !ok {
+			jctx.UnschedulableReason = gangMemberUnschedulableReason(gctx, jctx, unschedulableReason)
+		}
+		if !jctx.IsSuccessful() {
+			rv.UnschedulableReasonByJobId[jctx.JobId] = jctx.UnschedulableReason
+		} else if jctx.PodSchedulingContext != nil && jctx.PodSchedulingContext.NodeId != "" {
+			rv.NodeIdByJobId[jctx.JobId] = jctx.PodSchedulingContext.NodeId
+		}
+	}
+	return rv, nil
+}
+
+func (sch *GangScheduler) dryRunSchedule(ctx *armadacontext.Context, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason *schedulerobjects.UnschedulableReason, err error) {
+	if !gctx.AllJobsEvicted {
+		if ok, unschedulableReason, err = sch.constraints.CheckRoundConstraints(sch.schedulingContext, gctx.Queue); err != nil || !ok {
+			return
+		}
+		if ok, unschedulableReason, err = sch.checkConstraintsAsIfAdded(gctx); err != nil || !ok {
+			return
+		}
+	}
+	dryRunScheduler := *sch
+	dryRunScheduler.dryRun = true
+	return dryRunScheduler.trySchedule(ctx, gctx)
 }
 
-func (sch *GangScheduler) trySchedule(ctx *armadacontext.Context, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason string, err error) {
+// checkConstraintsAsIfAdded checks the per-gang constraints as Schedule does, i.e., with each job of the gang accounted for as allocated
+// to its queue, but without adding it to the SchedulingContext.
+func (sch *GangScheduler) checkConstraintsAsIfAdded(gctx *schedulercontext.GangSchedulingContext) (bool, *schedulerobjects.UnschedulableReason, error) {
+	queues, jctxsByQueue := gctx.JobSchedulingContextsByQueue()
+	for _, queue := range queues {
+		qctx := sch.schedulingContext.QueueSchedulingContexts[queue]
+		if qctx == nil {
+			continue
+		}
+		allocatedByPriorityClass := qctx.AllocatedByPriorityClass
+		defer func() {
+			qctx.AllocatedByPriorityClass = allocatedByPriorityClass
+		}()
+		qctx.AllocatedByPriorityClass = allocatedByPriorityClass.DeepCopy()
+		for _, jctx := range jctxsByQueue[queue] {
+			if jctx.IsSuccessful() {
+				qctx.AllocatedByPriorityClass.AddV1ResourceList(jctx.Job.GetPriorityClassName(), jctx.PodRequirements.ResourceRequirements.Requests)
+			}
+		}
+	}
+	return sch.constraints.CheckConstraints(sch.schedulingContext, gctx)
+}
+
+// diagnoseNodeRejections records, for each job of the gang unschedulable since it doesn't fit,
+// how many nodes reject the job on its own for each reason, and appends a summary to its unschedulable reason.
+// Node selectors added to enforce node uniformity or spread are ignored, since the gang may be scheduled onto any value of those labels.
+// Jobs of homogeneous gangs share the same requirements, so nodes are only evaluated once per gang.
+func (sch *GangScheduler) diagnoseNodeRejections(gctx *schedulercontext.GangSchedulingContext) error {
+	txn := sch.nodeDb.Txn(false)
+	defer txn.Abort()
+	var gangRejections *schedulercontext.NodeRejections
+	reasonWithRejectionsByReason := make(map[*schedulerobjects.UnschedulableReason]*schedulerobjects.UnschedulableReason)
+	for _, jctx := range gctx.JobSchedulingContexts {
+		reason := jctx.UnschedulableReason
+		if code := reason.GetCode(); code != schedulerobjects.UnschedulableReasonCodeJobDoesNotFit &&
+			code != schedulerobjects.UnschedulableReasonCodeGangMinCardinalityNotMet {
+			continue
+		}
+		rejections := gangRejections
+		if rejections == nil {
+			req := jctx.PodRequirements
+			if addedLabels := schedulerAddedNodeSelectorLabels(gctx); len(addedLabels) > 0 {
+				req = proto.Clone(req).(*schedulerobjects.PodRequirements)
+				for _, label := range addedLabels {
+					delete(req.NodeSelector, label)
+				}
+			}
+			numRejectedNodesByReason, numNodes, err := sch.nodeDb.NodeRejectionsWithTxn(txn, req)
+			if err != nil {
+				return err
+			}
+			rejections = &schedulercontext.NodeRejections{NumNodes: numNodes, NumRejectedNodesByReason: numRejectedNodesByReason}
+			if gctx.Homogeneous {
+				gangRejections = rejections
+			}
+		}
+		jctx.NodeRejections = rejections
+		reasonWithRejections, ok := reasonWithRejectionsByReason[reason]
+		if !ok || !gctx.Homogeneous {
+			reasonWithRejections = reason.WithMessage(fmt.Sprintf("%s; %s", reason.Message, rejections))
+			reasonWithRejectionsByReason[reason] = reasonWithRejections
+		}
+		jctx.UnschedulableReason = reasonWithRejections
+	}
+	return nil
+}
+
+// schedulerAddedNodeSelectorLabels returns the labels the scheduler may add to the node selectors of the jobs of the gang
+// while trying to schedule it, e.g., to schedule it onto nodes with a single value of its node uniformity label.
+func schedulerAddedNodeSelectorLabels(gctx *schedulercontext.GangSchedulingContext) []string {
+	var rv []string
+	if gctx.NodeUniformityLabel != "" {
+		rv = append(rv, gctx.NodeUniformityLabel)
+	}
+	rv = append(rv, gctx.NodeUniformityLabels...)
+	if gctx.SpreadLabel != "" {
+		rv = append(rv, gctx.SpreadLabel)
+	}
+	return rv
+}
+
+// gangMemberUnschedulableReason returns the reason the job of jctx is unschedulable, given its gang is unschedulable for unschedulableReason.
+// Members of heterogeneous gangs that couldn't be placed are given their own reason, since which members fit may depend on their requirements;
+// all other members are given the reason of the gang.
+func gangMemberUnschedulableReason(gctx *schedulercontext.GangSchedulingContext, jctx *schedulercontext.JobSchedulingContext, unschedulableReason *schedulerobjects.UnschedulableReason) *schedulerobjects.UnschedulableReason {
+	if !gctx.Homogeneous && jctx.GangMemberUnschedulableReason != nil {
+		return jctx.GangMemberUnschedulableReason
+	}
+	return unschedulableReason
+}
+
+// clearGangMemberUnschedulableReasons clears the reasons recorded for individual members of the gang,
+// e.g., if the gang is unschedulable for reasons that don't depend on which members could be placed.
+func clearGangMemberUnschedulableReasons(gctx *schedulercontext.GangSchedulingContext) {
+	for _, jctx := range gctx.JobSchedulingContexts {
+		jctx.GangMemberUnschedulableReason = nil
+	}
+}
+
+func (sch *GangScheduler) trySchedule(ctx *armadacontext.Context, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason *schedulerobjects.UnschedulableReason, err error) {
+	clearGangMemberUnschedulableReasons(gctx)
+	if gctx.ColocationGroup != "" {
+		return sch.tryScheduleColocated(ctx, gctx)
+	}
+	return sch.tryScheduleWithNodeUniformity(ctx, gctx)
+}
+
+// tryScheduleColocated tries scheduling a gang alongside the running jobs of its colocation group.
+// A separate scheduling attempt is made for each node, or each value of the colocation label, hosting such jobs.
+// If no jobs in the group are running, the gang is scheduled without a colocation constraint.
+func (sch *GangScheduler) tryScheduleColocated(ctx *armadacontext.Context, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason *schedulerobjects.UnschedulableReason, err error) {
+	label := gctx.ColocationLabel
+	if label == "" {
+		label = schedulerconfig.NodeIdLabel
+	}
+	values, err := sch.nodeDb.ColocationGroupLabelValues(gctx.Queue, gctx.ColocationGroup, label)
+	if err != nil {
+		return
+	}
+	if len(values) == 0 {
+		return sch.tryScheduleWithNodeUniformity(ctx, gctx)
+	}
+	for _, value := range values {
+		addNodeSelectorToGctx(gctx, label, value)
+		if ok, unschedulableReason, err = sch.tryScheduleWithNodeUniformity(ctx, gctx); err != nil || ok {
+			return
+		}
+	}
+	ok = false
+	clearGangMemberUnschedulableReasons(gctx)
+	unschedulableReason = schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeColocation, "unable to schedule alongside running jobs in colocation group %s", gctx.ColocationGroup)
+	return
+}
+
+func (sch *GangScheduler) tryScheduleWithNodeUniformity(ctx *armadacontext.Context, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason *schedulerobjects.UnschedulableReason, err error) {
+	gctx.AchievedNodeUniformityLabel = ""
+	if len(gctx.NodeUniformityLabels) > 0 {
+		return sch.tryScheduleWithHierarchicalNodeUniformity(ctx, gctx)
+	}
+	return sch.tryScheduleWithNodeUniformityLabel(ctx, gctx)
+}
+
+// tryScheduleWithHierarchicalNodeUniformity tries scheduling a gang onto nodes with a single value for each of gctx.NodeUniformityLabels in turn,
+// from the finest label to the coarsest, e.g., onto a single rack, then onto a single zone, and then onto a single region.
+// The label for which the gang was scheduled is recorded in gctx.AchievedNodeUniformityLabel.
+// If the gang can't be scheduled for any of the labels and the constraint is soft, the gang is scheduled across any nodes.
+func (sch *GangScheduler) tryScheduleWithHierarchicalNodeUniformity(ctx *armadacontext.Context, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason *schedulerobjects.UnschedulableReason, err error) {
+	isSoft := gctx.NodeUniformityIsSoft
+	gctx.NodeUniformityIsSoft = false
+	defer func() {
+		gctx.NodeUniformityLabel = ""
+		gctx.NodeUniformityIsSoft = isSoft
+	}()
+	labels := gctx.NodeUniformityLabels
+	for i := len(labels) - 1; i >= 0; i-- {
+		gctx.NodeUniformityLabel = labels[i]
+		if ok, unschedulableReason, err = sch.tryScheduleWithNodeUniformityLabel(ctx, gctx); err != nil || ok {
+			return
+		}
+		removeNodeSelectorFromGctx(gctx, labels[i])
+	}
+	if isSoft {
+		// The spread of the gang is measured in terms of the coarsest label.
+		gctx.NodeUniformityLabel = labels[0]
+		return sch.tryScheduleGangWithoutUniformity(ctx, gctx)
+	}
+	clearGangMemberUnschedulableReasons(gctx)
+	return false, schedulerobjects.NewUnschedulableReason(
+		schedulerobjects.UnschedulableReasonCodeNodeUniformity,
+		"unable to schedule gang onto nodes with a single value for any of the uniformity labels %s", strings.Join(labels, ", "),
+	), nil
+}
+
+// tryScheduleWithNodeUniformityLabel tries scheduling a gang such that all its jobs land on nodes with the same value for gctx.NodeUniformityLabel, if set.
+func (sch *GangScheduler) tryScheduleWithNodeUniformityLabel(ctx *armadacontext.Context, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason *schedulerobjects.UnschedulableReason, err error) {
 	// If no node uniformity constraint, try scheduling across all nodes.
 	if gctx.NodeUniformityLabel == "" {
 		return sch.tryScheduleGang(ctx, gctx)
 	}
 
 	// Otherwise try scheduling such that all nodes onto which a gang job lands have the same value for gctx.NodeUniformityLabel.
-	// We do this by making a separate scheduling attempt for each unique value of gctx.NodeUniformityLabel.
+	// We do this by making a separate scheduling attempt for each unique value of gctx.NodeUniformityLabel,
+	// or for the most promising maxNodeUniformityLabelValuesToConsider values if set.
 	nodeUniformityLabelValues, ok := sch.nodeDb.IndexedNodeLabelValues(gctx.NodeUniformityLabel)
 	if !ok {
 		ok = false
-		unschedulableReason = fmt.Sprintf("uniformity label %s is not indexed", gctx.NodeUniformityLabel)
+		unschedulableReason = schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeNodeUniformity, "uniformity label %s is not indexed", gctx.NodeUniformityLabel)
 		return
 	}
 	if len(nodeUniformityLabelValues) == 0 {
+		if gctx.NodeUniformityIsSoft {
+			return sch.tryScheduleGangWithoutUniformity(ctx, gctx)
+		}
 		ok = false
-		unschedulableReason = fmt.Sprintf("no nodes with uniformity label %s", gctx.NodeUniformityLabel)
+		unschedulableReason = schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeNodeUniformity, "no nodes with uniformity label %s", gctx.NodeUniformityLabel)
 		return
 	}
 
-	// Try all possible values of nodeUniformityLabel one at a time to find the best fit.
-	bestValue := ""
-	var minMeanScheduledAtPriority float64
-	var i int
-	for value := range nodeUniformityLabelValues {
-		i++
-		if value == "" {
-			continue
-		}
+	values, numSkipped, err := sch.nodeUniformityLabelValuesToConsider(gctx, nodeUniformityLabelValues)
+	if err != nil {
+		return
+	}
+	gctx.NodeUniformityLabelValuesConsidered = len(values)
+	gctx.NodeUniformityLabelValuesSkipped = numSkipped
+
+	// Try the values of nodeUniformityLabel one at a time to find the placement with the smallest score,
+	// breaking ties according to placementTieBreak.
+	// Values are tried in lexicographic order; hence, for lexicographic tie-breaking, the first placement with the best possible score wins.
+	var best *gangPlacementCandidate
+	for i, value := range values {
 		addNodeSelectorToGctx(gctx, gctx.NodeUniformityLabel, value)
 		txn := sch.nodeDb.Txn(true)
 		if ok, unschedulableReason, err = sch.tryScheduleGangWithTxn(ctx, txn, gctx); err != nil {
 			txn.Abort()
 			return
 		} else if ok {
-			meanScheduledAtPriority, ok := meanScheduledAtPriorityFromGctx(gctx)
+			candidate, ok, err := sch.gangPlacementCandidate(txn, gctx, value)
+			if err != nil {
+				txn.Abort()
+				return false, nil, err
+			}
 			if !ok {
 				txn.Abort()
 				continue
 			}
-			if meanScheduledAtPriority == float64(nodedb.MinPriority) {
+			if candidate.score <= sch.placementScorer.MinScore() && sch.placementTieBreakOrDefault() == configuration.Lexicographic {
 				// Best possible; no need to keep looking.
-				txn.Commit()
-				return true, "", nil
+				sch.commit(txn)
+				gctx.AchievedNodeUniformityLabel = gctx.NodeUniformityLabel
+				return true, nil, nil
 			}
-			if bestValue == "" || meanScheduledAtPriority <= minMeanScheduledAtPriority {
-				if i == len(nodeUniformityLabelValues) {
-					// Minimal meanScheduledAtPriority and no more options; commit and return.
-					txn.Commit()
-					return true, "", nil
+			if best == nil || sch.isBetterGangPlacement(candidate, best) {
+				if i == len(values)-1 {
+					// Best placement and no more options; commit and return.
+					sch.commit(txn)
+					gctx.AchievedNodeUniformityLabel = gctx.NodeUniformityLabel
+					return true, nil, nil
 				}
-				// Record the best value seen so far.
-				bestValue = value
-				minMeanScheduledAtPriority = meanScheduledAtPriority
+				// Record the best placement seen so far.
+				best = candidate
 			}
 		}
 		txn.Abort()
 	}
-	if bestValue == "" {
+	if best == nil {
+		if gctx.NodeUniformityIsSoft {
+			return sch.tryScheduleGangWithoutUniformity(ctx, gctx)
+		}
 		ok = false
-		unschedulableReason = "at least one job in the gang does not fit on any node"
+		clearGangMemberUnschedulableReasons(gctx)
+		if numSkipped > 0 {
+			unschedulableReason = schedulerobjects.NewUnschedulableReason(
+				schedulerobjects.UnschedulableReasonCodeJobDoesNotFit,
+				"at least one job in the gang does not fit on any node with the %d of %d values of uniformity label %s considered",
+				len(values), len(values)+numSkipped, gctx.NodeUniformityLabel,
+			)
+			return
+		}
+		unschedulableReason = schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeJobDoesNotFit, "at least one job in the gang does not fit on any node")
+		return
+	}
+	addNodeSelectorToGctx(gctx, gctx.NodeUniformityLabel, best.value)
+	if ok, unschedulableReason, err = sch.tryScheduleGang(ctx, gctx); err == nil && ok {
+		gctx.AchievedNodeUniformityLabel = gctx.NodeUniformityLabel
+	}
+	return
+}
+
+// gangPlacementCandidate is a placement of a gang onto nodes with a single value of its node uniformity label.
+type gangPlacementCandidate struct {
+	value string
+	score float64
+	// Number of distinct nodes the gang is placed on. Only computed for FewestNodes tie-breaking.
+	numNodes int
+	// How many times over the resources requested by the gang fit within the resources left unallocated on nodes with value
+	// once the gang is placed. Only computed for MostHeadroom tie-breaking.
+	headroom float64
+}
+
+// gangPlacementCandidate returns the candidate placement recorded in the job scheduling contexts of gctx,
+// where txn reflects the state of the nodeDb with the gang placed onto nodes with value, or false if it isn't to be considered.
+func (sch *GangScheduler) gangPlacementCandidate(txn *memdb.Txn, gctx *schedulercontext.GangSchedulingContext, value string) (*gangPlacementCandidate, bool, error) {
+	score, ok, err := sch.placementScorer.Score(sch.nodeDb, txn, gctx)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	candidate := &gangPlacementCandidate{value: value, score: score}
+	switch sch.placementTieBreakOrDefault() {
+	case configuration.FewestNodes:
+		nodes, ok, err := nodesFromGctx(sch.nodeDb, txn, gctx)
+		if err != nil || !ok {
+			return nil, ok, err
+		}
+		candidate.numNodes = len(nodes)
+	case configuration.MostHeadroom:
+		allocatableByValue, err := sch.nodeDb.AllocatableByNodeLabelValueWithTxn(txn, gctx.NodeUniformityLabel)
+		if err != nil {
+			return nil, false, err
+		}
+		candidate.headroom = freeCapacityScore(allocatableByValue[value], gctx.TotalResourceRequests)
+	}
+	return candidate, true, nil
+}
+
+// isBetterGangPlacement returns true if placement a is preferred over placement b,
+// i.e., if it has a smaller score or, for equal scores, if it's preferred by placementTieBreak.
+// Remaining ties are broken lexicographically by node uniformity label value, such that placements are reproducible.
+func (sch *GangScheduler) isBetterGangPlacement(a, b *gangPlacementCandidate) bool {
+	if a.score != b.score {
+		return a.score < b.score
+	}
+	switch sch.placementTieBreakOrDefault() {
+	case configuration.FewestNodes:
+		if a.numNodes != b.numNodes {
+			return a.numNodes < b.numNodes
+		}
+	case configuration.MostHeadroom:
+		if a.headroom != b.headroom {
+			return a.headroom > b.headroom
+		}
+	}
+	return a.value < b.value
+}
+
+func (sch *GangScheduler) placementTieBreakOrDefault() configuration.GangPlacementTieBreak {
+	if sch.placementTieBreak == "" {
+		return configuration.Lexicographic
+	}
+	return sch.placementTieBreak
+}
+
+// nodeUniformityLabelValuesToConsider returns the values of gctx.NodeUniformityLabel to make scheduling attempts for, in lexicographic order.
+// If maxNodeUniformityLabelValuesToConsider is set, values are ranked by free capacity relative to the resources
+// requested by the gang, since the gang is more likely to fit where more resources are free,
+// and only the top maxNodeUniformityLabelValuesToConsider values are returned, alongside the number of values skipped.
+// This avoids an exhaustive search over all values for labels with many values, e.g., one per rack.
+func (sch *GangScheduler) nodeUniformityLabelValuesToConsider(gctx *schedulercontext.GangSchedulingContext, nodeUniformityLabelValues map[string]struct{}) ([]string, int, error) {
+	values := make([]string, 0, len(nodeUniformityLabelValues))
+	for value := range nodeUniformityLabelValues {
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	if sch.maxNodeUniformityLabelValuesToConsider == 0 || uint(len(values)) <= sch.maxNodeUniformityLabelValuesToConsider {
+		slices.Sort(values)
+		return values, 0, nil
+	}
+	allocatableByValue, err := sch.nodeDb.AllocatableByNodeLabelValueWithTxn(sch.nodeDb.Txn(false), gctx.NodeUniformityLabel)
+	if err != nil {
+		return nil, 0, err
+	}
+	scoreByValue := make(map[string]float64, len(values))
+	for _, value := range values {
+		scoreByValue[value] = freeCapacityScore(allocatableByValue[value], gctx.TotalResourceRequests)
+	}
+	slices.SortFunc(values, func(a, b string) bool {
+		if scoreByValue[a] != scoreByValue[b] {
+			return scoreByValue[a] > scoreByValue[b]
+		}
+		return a < b
+	})
+	numSkipped := len(values) - int(sch.maxNodeUniformityLabelValuesToConsider)
+	values = values[:sch.maxNodeUniformityLabelValuesToConsider]
+	slices.Sort(values)
+	return values, numSkipped, nil
+}
+
+// freeCapacityScore returns how many times over the requested resources fit within the allocatable resources,
+// computed separately for each requested resource type and taking the minimum.
+func freeCapacityScore(allocatable schedulerobjects.ResourceList, requests schedulerobjects.ResourceList) float64 {
+	score := math.Inf(1)
+	for t, request := range requests.Resources {
+		if request.Sign() <= 0 {
+			continue
+		}
+		available := allocatable.Get(t)
+		score = math.Min(score, float64(available.MilliValue())/float64(request.MilliValue()))
+	}
+	return score
+}
+
+// tryScheduleGangWithoutUniformity tries scheduling a gang with a soft node uniformity constraint across all nodes,
+// for use once it's been found not to fit onto nodes with any single value of the node uniformity label.
+// If successful, the number of additional label values the gang is spread across is recorded as a penalty.
+func (sch *GangScheduler) tryScheduleGangWithoutUniformity(ctx *armadacontext.Context, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason *schedulerobjects.UnschedulableReason, err error) {
+	removeNodeSelectorFromGctx(gctx, gctx.NodeUniformityLabel)
+	if ok, unschedulableReason, err = sch.tryScheduleGang(ctx, gctx); err != nil || !ok {
 		return
 	}
-	addNodeSelectorToGctx(gctx, gctx.NodeUniformityLabel, bestValue)
-	return sch.tryScheduleGang(ctx, gctx)
+	values := make(map[string]bool)
+	for _, jctx := range gctx.JobSchedulingContexts {
+		if jctx.PodSchedulingContext == nil || jctx.PodSchedulingContext.NodeId == "" {
+			continue
+		}
+		node, err := sch.nodeDb.GetNode(jctx.PodSchedulingContext.NodeId)
+		if err != nil {
+			return false, nil, err
+		}
+		if node != nil {
+			values[node.Labels[gctx.NodeUniformityLabel]] = true
+		}
+	}
+	if len(values) > 1 {
+		for _, jctx := range gctx.JobSchedulingContexts {
+			jctx.NodeUniformityPenalty = len(values) - 1
+		}
+	} else if len(values) == 1 {
+		gctx.AchievedNodeUniformityLabel = gctx.NodeUniformityLabel
+	}
+	return true, nil, nil
 }
 
-func (sch *GangScheduler) tryScheduleGang(ctx *armadacontext.Context, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason string, err error) {
+func (sch *GangScheduler) tryScheduleGang(ctx *armadacontext.Context, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason *schedulerobjects.UnschedulableReason, err error) {
 	txn := sch.nodeDb.Txn(true)
 	defer txn.Abort()
 	ok, unschedulableReason, err = sch.tryScheduleGangWithTxn(ctx, txn, gctx)
 	if ok && err == nil {
-		txn.Commit()
+		sch.commit(txn)
 	}
 	return
 }
 
+// commit commits txn, unless this is a dry run, in which case txn is aborted.
+func (sch *GangScheduler) commit(txn *memdb.Txn) {
+	if sch.dryRun {
+		txn.Abort()
+		return
+	}
+	txn.Commit()
+}
+
 func clearNodeBindings(jctx *schedulercontext.JobSchedulingContext) {
 	if jctx.PodSchedulingContext != nil {
 		// Clear any node bindings on failure to schedule.
@@ -226,24 +713,246 @@ func clearNodeBindings(jctx *schedulercontext.JobSchedulingContext) {
 	}
 }
 
-func (sch *GangScheduler) tryScheduleGangWithTxn(_ *armadacontext.Context, txn *memdb.Txn, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason string, err error) {
+func (sch *GangScheduler) tryScheduleGangWithTxn(ctx *armadacontext.Context, txn *memdb.Txn, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason *schedulerobjects.UnschedulableReason, err error) {
+	// Evicted gangs are re-scheduled onto the nodes they were running on, which already satisfied the constraint.
+	if gctx.SpreadLabel != "" && gctx.Cardinality() > 1 && !gctx.AllJobsEvicted {
+		return sch.tryScheduleGangSpreadWithTxn(ctx, txn, gctx)
+	}
+	if gctx.MinFailureDomains > 1 && !gctx.AllJobsEvicted {
+		return sch.tryScheduleGangAcrossFailureDomainsWithTxn(ctx, txn, gctx)
+	}
+	return sch.tryScheduleGangMembersWithTxn(ctx, txn, gctx)
+}
+
+// tryScheduleGangSpreadWithTxn tries scheduling a gang such that each of its jobs lands on a node with a distinct value of gctx.SpreadLabel.
+// To do so, each member of the gang is assigned a different value, picking the values with the most free capacity
+// relative to the resources requested by a member. Members with a node selector for the label are never re-assigned.
+func (sch *GangScheduler) tryScheduleGangSpreadWithTxn(ctx *armadacontext.Context, txn *memdb.Txn, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason *schedulerobjects.UnschedulableReason, err error) {
+	label := gctx.SpreadLabel
+	if _, ok := sch.nodeDb.IndexedNodeLabelValues(label); !ok {
+		return false, schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeSpread, "spread label %s is not indexed", label), nil
+	}
+	assigned := make(map[string]bool)
+	numUnassigned := 0
+	for _, jctx := range gctx.JobSchedulingContexts {
+		value, ok := jctx.PodRequirements.NodeSelector[label]
+		if !ok {
+			numUnassigned++
+		} else if assigned[value] {
+			return false, schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeSpread,
+				"several gang jobs select value %s of spread label %s", value, label,
+			), nil
+		} else {
+			assigned[value] = true
+		}
+	}
+	allocatableByValue, err := sch.nodeDb.AllocatableByNodeLabelValueWithTxn(txn, label)
+	if err != nil {
+		return false, nil, err
+	}
+	memberRequests := smallestMemberRequests(gctx)
+	scoreByValue := make(map[string]float64, len(allocatableByValue))
+	values := make([]string, 0, len(allocatableByValue))
+	for value, allocatable := range allocatableByValue {
+		if value == "" || assigned[value] {
+			continue
+		}
+		scoreByValue[value] = freeCapacityScore(allocatable, memberRequests)
+		if scoreByValue[value] >= 1 {
+			values = append(values, value)
+		}
+	}
+	if len(values) < numUnassigned {
+		return false, schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeSpread,
+			"gang requires %d distinct values of spread label %s, but only %d have capacity for a gang member",
+			gctx.Cardinality(), label, len(values)+len(assigned),
+		), nil
+	}
+	slices.SortFunc(values, func(a, b string) bool {
+		if scoreByValue[a] != scoreByValue[b] {
+			return scoreByValue[a] > scoreByValue[b]
+		}
+		return a < b
+	})
+
+	pinned := assignJobsToFailureDomains(gctx, label, values[:numUnassigned])
+	defer func() {
+		for _, jctx := range pinned {
+			delete(jctx.PodRequirements.NodeSelector, label)
+		}
+	}()
+	return sch.tryScheduleGangMembersWithTxn(ctx, txn, gctx)
+}
+
+// tryScheduleGangAcrossFailureDomainsWithTxn tries scheduling a gang such that its nodes span at least
+// gctx.MinFailureDomains values of gctx.FailureDomainLabel.
+// To do so, the first gctx.MinFailureDomains members of the gang are each assigned a different value,
+// picking the values with the most free capacity relative to the resources requested by a member,
+// after which the remaining members are scheduled without additional constraints.
+// Members with a node selector for the label are never re-assigned.
+func (sch *GangScheduler) tryScheduleGangAcrossFailureDomainsWithTxn(ctx *armadacontext.Context, txn *memdb.Txn, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason *schedulerobjects.UnschedulableReason, err error) {
+	label := gctx.FailureDomainLabel
+	if _, ok := sch.nodeDb.IndexedNodeLabelValues(label); !ok {
+		return false, schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeFailureDomain, "failure-domain label %s is not indexed", label), nil
+	}
+	allocatableByValue, err := sch.nodeDb.AllocatableByNodeLabelValueWithTxn(txn, label)
+	if err != nil {
+		return false, nil, err
+	}
+	memberRequests := smallestMemberRequests(gctx)
+	scoreByValue := make(map[string]float64, len(allocatableByValue))
+	values := make([]string, 0, len(allocatableByValue))
+	for value, allocatable := range allocatableByValue {
+		if value == "" {
+			continue
+		}
+		scoreByValue[value] = freeCapacityScore(allocatable, memberRequests)
+		if scoreByValue[value] >= 1 {
+			values = append(values, value)
+		}
+	}
+	if len(values) < gctx.MinFailureDomains {
+		return false, schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeFailureDomain,
+			"gang requires at least %d values of failure-domain label %s, but only %d have capacity for a gang member",
+			gctx.MinFailureDomains, label, len(values),
+		), nil
+	}
+	slices.SortFunc(values, func(a, b string) bool {
+		if scoreByValue[a] != scoreByValue[b] {
+			return scoreByValue[a] > scoreByValue[b]
+		}
+		return a < b
+	})
+
+	pinned := assignJobsToFailureDomains(gctx, label, values[:gctx.MinFailureDomains])
+	defer func() {
+		for _, jctx := range pinned {
+			delete(jctx.PodRequirements.NodeSelector, label)
+		}
+	}()
+	if ok, unschedulableReason, err = sch.tryScheduleGangMembersWithTxn(ctx, txn, gctx); err != nil || !ok {
+		return
+	}
+
+	numFailureDomains, err := sch.numFailureDomainsWithTxn(txn, gctx)
+	if err != nil {
+		return false, nil, err
+	}
+	if numFailureDomains < gctx.MinFailureDomains {
+		for _, jctx := range gctx.JobSchedulingContexts {
+			clearNodeBindings(jctx)
+		}
+		clearGangMemberUnschedulableReasons(gctx)
+		return false, schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeFailureDomain,
+			"unable to spread gang across %d values of failure-domain label %s; spans only %d",
+			gctx.MinFailureDomains, label, numFailureDomains,
+		), nil
+	}
+	return true, nil, nil
+}
+
+// smallestMemberRequests returns the resources requested by the smallest member of the gang, computed separately for each resource type,
+// i.e., the resources that must be available for any member of the gang to fit.
+func smallestMemberRequests(gctx *schedulercontext.GangSchedulingContext) schedulerobjects.ResourceList {
+	rv := schedulerobjects.ResourceListFromV1ResourceList(
+		gctx.JobSchedulingContexts[0].PodRequirements.ResourceRequirements.Requests,
+	)
+	if gctx.Homogeneous {
+		return rv
+	}
+	for _, jctx := range gctx.JobSchedulingContexts[1:] {
+		requests := schedulerobjects.ResourceListFromV1ResourceList(jctx.PodRequirements.ResourceRequirements.Requests)
+		for t, q := range rv.Resources {
+			if request, ok := requests.Resources[t]; !ok {
+				delete(rv.Resources, t)
+			} else if request.Cmp(q) < 0 {
+				rv.Resources[t] = request
+			}
+		}
+	}
+	return rv
+}
+
+// assignJobsToFailureDomains adds a node selector to jobs of the gang such that each of values is selected by at least one job,
+// and returns the jobs a selector was added to.
+func assignJobsToFailureDomains(gctx *schedulercontext.GangSchedulingContext, label string, values []string) []*schedulercontext.JobSchedulingContext {
+	unassigned := make(map[string]bool, len(values))
+	for _, value := range values {
+		unassigned[value] = true
+	}
+	var unconstrained []*schedulercontext.JobSchedulingContext
+	for _, jctx := range gctx.JobSchedulingContexts {
+		if value, ok := jctx.PodRequirements.NodeSelector[label]; ok {
+			delete(unassigned, value)
+		} else {
+			unconstrained = append(unconstrained, jctx)
+		}
+	}
+	var rv []*schedulercontext.JobSchedulingContext
+	for _, value := range values {
+		if !unassigned[value] || len(unconstrained) == 0 {
+			continue
+		}
+		jctx := unconstrained[0]
+		unconstrained = unconstrained[1:]
+		if jctx.PodRequirements.NodeSelector == nil {
+			jctx.PodRequirements.NodeSelector = make(map[string]string)
+		}
+		jctx.PodRequirements.NodeSelector[label] = value
+		rv = append(rv, jctx)
+	}
+	return rv
+}
+
+// numFailureDomainsWithTxn returns the number of distinct values of gctx.FailureDomainLabel across the nodes
+// the jobs of the gang have been scheduled onto.
+func (sch *GangScheduler) numFailureDomainsWithTxn(txn *memdb.Txn, gctx *schedulercontext.GangSchedulingContext) (int, error) {
+	values := make(map[string]bool)
+	for _, jctx := range gctx.JobSchedulingContexts {
+		if jctx.PodSchedulingContext == nil || jctx.PodSchedulingContext.NodeId == "" {
+			continue
+		}
+		node, err := sch.nodeDb.GetNodeWithTxn(txn, jctx.PodSchedulingContext.NodeId)
+		if err != nil {
+			return 0, err
+		}
+		if node == nil {
+			continue
+		}
+		if value, ok := node.Labels[gctx.FailureDomainLabel]; ok {
+			values[value] = true
+		}
+	}
+	return len(values), nil
+}
+
+func (sch *GangScheduler) tryScheduleGangMembersWithTxn(_ *armadacontext.Context, txn *memdb.Txn, gctx *schedulercontext.GangSchedulingContext) (ok bool, unschedulableReason *schedulerobjects.UnschedulableReason, err error) {
 	if ok, err = sch.nodeDb.ScheduleManyWithTxn(txn, gctx.JobSchedulingContexts); err == nil {
 		if !ok {
+			unmetGangRole, hasUnmetGangRole := schedulercontext.UnmetGangRole(gctx.JobSchedulingContexts)
 			for _, jctx := range gctx.JobSchedulingContexts {
+				if jctx.PodSchedulingContext == nil || jctx.PodSchedulingContext.NodeId == "" {
+					jctx.GangMemberUnschedulableReason = schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeJobDoesNotFit, "job does not fit on any node alongside the other members of its gang")
+				} else {
+					jctx.GangMemberUnschedulableReason = nil
+				}
 				clearNodeBindings(jctx)
 			}
 
-			if gctx.Cardinality() > 1 {
-				unschedulableReason = "unable to schedule gang since minimum cardinality not met"
+			if hasUnmetGangRole {
+				unschedulableReason = schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeGangMinCardinalityNotMet, "unable to schedule gang since minimum cardinality of role %s not met", unmetGangRole)
+			} else if gctx.Cardinality() > 1 {
+				unschedulableReason = schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeGangMinCardinalityNotMet, "unable to schedule gang since minimum cardinality not met")
 			} else {
-				unschedulableReason = "job does not fit on any node"
+				unschedulableReason = schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeJobDoesNotFit, "job does not fit on any node")
 			}
 		} else {
+			clearGangMemberUnschedulableReasons(gctx)
 			// When a gang schedules successfully, update state for failed jobs if they exist.
 			for _, jctx := range gctx.JobSchedulingContexts {
 				if jctx.ShouldFail {
 					clearNodeBindings(jctx)
-					jctx.UnschedulableReason = "job does not fit on any node"
+					jctx.UnschedulableReason = schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeJobDoesNotFit, "job does not fit on any node")
 				}
 			}
 		}
@@ -263,6 +972,12 @@ func addNodeSelectorToGctx(gctx *schedulercontext.GangSchedulingContext, nodeSel
 	}
 }
 
+func removeNodeSelectorFromGctx(gctx *schedulercontext.GangSchedulingContext, nodeSelectorKey string) {
+	for _, jctx := range gctx.JobSchedulingContexts {
+		delete(jctx.PodRequirements.NodeSelector, nodeSelectorKey)
+	}
+}
+
 func meanScheduledAtPriorityFromGctx(gctx *schedulercontext.GangSchedulingContext) (float64, bool) {
 	var sum int32
 	for _, jctx := range gctx.JobSchedulingContexts {
@@ -11,6 +11,7 @@ import (
 	"github.com/armadaproject/armada/internal/armada/configuration"
 	"github.com/armadaproject/armada/internal/common/armadacontext"
 	armadaslices "github.com/armadaproject/armada/internal/common/slices"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
 	schedulerconstraints "github.com/armadaproject/armada/internal/scheduler/constraints"
 	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
 	"github.com/armadaproject/armada/internal/scheduler/fairness"
@@ -27,6 +28,8 @@ func TestGangScheduler(t *testing.T) {
 		MinimumJobSize map[string]resource.Quantity
 		// Nodes to be considered by the scheduler.
 		Nodes []*schedulerobjects.Node
+		// Jobs bound to each node at the start of the test, by node index.
+		RunningJobsByNode map[int][]*jobdb.Job
 		// Total resources across all clusters.
 		// Set to the total resources across all nodes if not provided.
 		TotalResources schedulerobjects.ResourceList
@@ -37,6 +40,12 @@ func TestGangScheduler(t *testing.T) {
 		// Cumulative number of jobs we expect to schedule successfully.
 		// Each index `i` is the expected value when processing gang `i`.
 		ExpectedScheduledJobs []int
+		// Expected node uniformity penalty of each gang expected to be scheduled.
+		ExpectedNodeUniformityPenalties []int
+		// If set, the reason gangs not scheduled are expected to be unschedulable for.
+		ExpectedUnschedulableReason string
+		// Expected number of node uniformity label values skipped for each gang with a node uniformity label.
+		ExpectedNodeUniformityLabelValuesSkipped int
 	}{
 		"simple success": {
 			SchedulingConfig: testfixtures.TestSchedulingConfig(),
@@ -355,6 +364,362 @@ func TestGangScheduler(t *testing.T) {
 			ExpectedScheduledIndices: []int{0},
 			ExpectedScheduledJobs:    []int{4},
 		},
+		"NodeUniformityLabel max values to consider": {
+			// foov1 has the most free capacity but is too fragmented for the gang; only foov2 fits it.
+			SchedulingConfig: testfixtures.WithMaxNodeUniformityLabelValuesToConsiderConfig(
+				1,
+				testfixtures.WithIndexedNodeLabelsConfig(
+					[]string{"foo", "bar"},
+					testfixtures.TestSchedulingConfig(),
+				),
+			),
+			Nodes: armadaslices.Concatenate(
+				testfixtures.WithLabelsNodes(
+					map[string]string{"foo": "foov1"},
+					testfixtures.WithUsedResourcesNodes(
+						0,
+						schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("20")}},
+						testfixtures.N32CpuNodes(8, testfixtures.TestPriorities),
+					),
+				),
+				testfixtures.WithLabelsNodes(
+					map[string]string{"foo": "foov2"},
+					testfixtures.N32CpuNodes(2, testfixtures.TestPriorities),
+				),
+			),
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithNodeUniformityLabelAnnotationJobs(
+						"foo",
+						testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 4),
+					)),
+			},
+			ExpectedScheduledIndices:                 nil,
+			ExpectedScheduledJobs:                    []int{0},
+			ExpectedUnschedulableReason:              "at least one job in the gang does not fit on any node with the 1 of 2 values of uniformity label foo considered",
+			ExpectedNodeUniformityLabelValuesSkipped: 1,
+		},
+		"NodeUniformityLabel max values to consider includes fitting value": {
+			SchedulingConfig: testfixtures.WithMaxNodeUniformityLabelValuesToConsiderConfig(
+				2,
+				testfixtures.WithIndexedNodeLabelsConfig(
+					[]string{"foo", "bar"},
+					testfixtures.TestSchedulingConfig(),
+				),
+			),
+			Nodes: armadaslices.Concatenate(
+				testfixtures.WithLabelsNodes(
+					map[string]string{"foo": "foov1"},
+					testfixtures.WithUsedResourcesNodes(
+						0,
+						schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("20")}},
+						testfixtures.N32CpuNodes(8, testfixtures.TestPriorities),
+					),
+				),
+				testfixtures.WithLabelsNodes(
+					map[string]string{"foo": "foov2"},
+					testfixtures.N32CpuNodes(2, testfixtures.TestPriorities),
+				),
+				testfixtures.WithLabelsNodes(
+					map[string]string{"foo": "foov3"},
+					testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+				),
+			),
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithNodeUniformityLabelAnnotationJobs(
+						"foo",
+						testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 4),
+					)),
+			},
+			ExpectedScheduledIndices:                 []int{0},
+			ExpectedScheduledJobs:                    []int{4},
+			ExpectedNodeUniformityLabelValuesSkipped: 1,
+		},
+		"soft NodeUniformityLabel": {
+			SchedulingConfig: testfixtures.WithIndexedNodeLabelsConfig(
+				[]string{"foo", "bar"},
+				testfixtures.TestSchedulingConfig(),
+			),
+			Nodes: armadaslices.Concatenate(
+				testfixtures.WithLabelsNodes(
+					map[string]string{"foo": "foov1"},
+					testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+				),
+				testfixtures.WithLabelsNodes(
+					map[string]string{"foo": "foov2"},
+					testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+				),
+			),
+			Gangs: [][]*jobdb.Job{
+				// Fits within a single value.
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithAnnotationsJobs(
+						map[string]string{configuration.GangNodeUniformitySoftAnnotation: "true"},
+						testfixtures.WithNodeUniformityLabelAnnotationJobs("foo", testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 1)),
+					),
+				),
+				// Only fits by spreading across both values.
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithAnnotationsJobs(
+						map[string]string{configuration.GangNodeUniformitySoftAnnotation: "true"},
+						testfixtures.WithNodeUniformityLabelAnnotationJobs("foo", testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 3)),
+					),
+				),
+			},
+			ExpectedScheduledIndices:        []int{0, 1},
+			ExpectedScheduledJobs:           []int{1, 4},
+			ExpectedNodeUniformityPenalties: []int{0, 1},
+		},
+		"soft NodeUniformityLabel insufficient capacity": {
+			SchedulingConfig: testfixtures.WithIndexedNodeLabelsConfig(
+				[]string{"foo", "bar"},
+				testfixtures.TestSchedulingConfig(),
+			),
+			Nodes: armadaslices.Concatenate(
+				testfixtures.WithLabelsNodes(
+					map[string]string{"foo": "foov1"},
+					testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+				),
+				testfixtures.WithLabelsNodes(
+					map[string]string{"foo": "foov2"},
+					testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+				),
+			),
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithAnnotationsJobs(
+						map[string]string{configuration.GangNodeUniformitySoftAnnotation: "true"},
+						testfixtures.WithNodeUniformityLabelAnnotationJobs("foo", testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 5)),
+					),
+				),
+			},
+			ExpectedScheduledIndices: nil,
+			ExpectedScheduledJobs:    []int{0},
+		},
+		"gang roles": {
+			SchedulingConfig: testfixtures.TestSchedulingConfig(),
+			Nodes:            testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsAndMinCardinalityJobs(
+					9,
+					armadaslices.Concatenate(
+						testfixtures.WithGangRoleAnnotationsJobs("worker", 8, testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 32)),
+						testfixtures.WithGangRoleAnnotationsJobs("ps", 1, testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 1)),
+					),
+				),
+			},
+			ExpectedScheduledIndices: testfixtures.IntRange(0, 0),
+			ExpectedScheduledJobs:    []int{17},
+		},
+		"gang role min cardinality not met": {
+			SchedulingConfig: testfixtures.TestSchedulingConfig(),
+			Nodes:            testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsAndMinCardinalityJobs(
+					1,
+					armadaslices.Concatenate(
+						testfixtures.WithGangRoleAnnotationsJobs("worker", 0, testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 4)),
+						testfixtures.WithGangRoleAnnotationsJobs("ps", 3, testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 3)),
+					),
+				),
+			},
+			ExpectedScheduledIndices: nil,
+			ExpectedScheduledJobs:    []int{0},
+		},
+		"colocation group": {
+			SchedulingConfig: testfixtures.TestSchedulingConfig(),
+			Nodes:            testfixtures.N32CpuNodes(2, testfixtures.TestPriorities),
+			RunningJobsByNode: map[int][]*jobdb.Job{
+				1: testfixtures.WithAnnotationsJobs(
+					map[string]string{configuration.ColocationGroupAnnotation: "cache"},
+					testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 1),
+				),
+			},
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithAnnotationsJobs(
+						map[string]string{configuration.ColocationGroupAnnotation: "cache"},
+						testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 31),
+					),
+				),
+			},
+			ExpectedScheduledIndices: testfixtures.IntRange(0, 0),
+			ExpectedScheduledJobs:    []int{31},
+		},
+		"colocation group insufficient capacity": {
+			SchedulingConfig: testfixtures.TestSchedulingConfig(),
+			Nodes:            testfixtures.N32CpuNodes(2, testfixtures.TestPriorities),
+			RunningJobsByNode: map[int][]*jobdb.Job{
+				1: testfixtures.WithAnnotationsJobs(
+					map[string]string{configuration.ColocationGroupAnnotation: "cache"},
+					testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 1),
+				),
+			},
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithAnnotationsJobs(
+						map[string]string{configuration.ColocationGroupAnnotation: "cache"},
+						testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 2),
+					),
+				),
+			},
+			ExpectedScheduledIndices: nil,
+			ExpectedScheduledJobs:    []int{0},
+		},
+		"colocation group with label": {
+			SchedulingConfig: testfixtures.TestSchedulingConfig(),
+			Nodes: armadaslices.Concatenate(
+				testfixtures.WithLabelsNodes(
+					map[string]string{"rack": "r1"},
+					testfixtures.N32CpuNodes(2, testfixtures.TestPriorities),
+				),
+				testfixtures.WithLabelsNodes(
+					map[string]string{"rack": "r2"},
+					testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+				),
+			),
+			RunningJobsByNode: map[int][]*jobdb.Job{
+				0: testfixtures.WithAnnotationsJobs(
+					map[string]string{configuration.ColocationGroupAnnotation: "cache"},
+					testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 1),
+				),
+			},
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithAnnotationsJobs(
+						map[string]string{
+							configuration.ColocationGroupAnnotation: "cache",
+							configuration.ColocationLabelAnnotation: "rack",
+						},
+						testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 2),
+					),
+				),
+			},
+			ExpectedScheduledIndices: testfixtures.IntRange(0, 0),
+			ExpectedScheduledJobs:    []int{2},
+		},
+		"failure domains": {
+			// The nodes of rack1 are considered first, but together can't host more than two jobs.
+			SchedulingConfig: testfixtures.WithIndexedNodeLabelsConfig([]string{"rack"}, testfixtures.TestSchedulingConfig()),
+			Nodes: armadaslices.Concatenate(
+				testfixtures.WithLabelsNodes(
+					map[string]string{"rack": "rack1"},
+					testfixtures.WithUsedResourcesNodes(
+						0,
+						schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("1")}},
+						testfixtures.N32CpuNodes(2, testfixtures.TestPriorities),
+					),
+				),
+				testfixtures.WithLabelsNodes(map[string]string{"rack": "rack2"}, testfixtures.N32CpuNodes(2, testfixtures.TestPriorities)),
+				testfixtures.WithLabelsNodes(map[string]string{"rack": "rack3"}, testfixtures.N32CpuNodes(2, testfixtures.TestPriorities)),
+			),
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithFailureDomainsAnnotationJobs(
+						"rack", 3,
+						testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 4),
+					),
+				),
+			},
+			ExpectedScheduledIndices: []int{0},
+			ExpectedScheduledJobs:    []int{4},
+		},
+		"failure domains insufficient values": {
+			SchedulingConfig: testfixtures.WithIndexedNodeLabelsConfig([]string{"rack"}, testfixtures.TestSchedulingConfig()),
+			Nodes: armadaslices.Concatenate(
+				testfixtures.WithLabelsNodes(map[string]string{"rack": "rack1"}, testfixtures.N32CpuNodes(2, testfixtures.TestPriorities)),
+				testfixtures.WithLabelsNodes(map[string]string{"rack": "rack2"}, testfixtures.N32CpuNodes(2, testfixtures.TestPriorities)),
+			),
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithFailureDomainsAnnotationJobs(
+						"rack", 3,
+						testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 4),
+					),
+				),
+			},
+			ExpectedScheduledIndices:    nil,
+			ExpectedScheduledJobs:       []int{0},
+			ExpectedUnschedulableReason: "gang requires at least 3 values of failure-domain label rack, but only 2 have capacity for a gang member",
+		},
+		"failure domain label not indexed": {
+			SchedulingConfig: testfixtures.TestSchedulingConfig(),
+			Nodes:            testfixtures.WithLabelsNodes(map[string]string{"rack": "rack1"}, testfixtures.N32CpuNodes(2, testfixtures.TestPriorities)),
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithFailureDomainsAnnotationJobs(
+						"rack", 2,
+						testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 2),
+					),
+				),
+			},
+			ExpectedScheduledIndices:    nil,
+			ExpectedScheduledJobs:       []int{0},
+			ExpectedUnschedulableReason: "failure-domain label rack is not indexed",
+		},
+		"spread": {
+			// rack1 has capacity for several jobs, but each job must land in a different rack.
+			SchedulingConfig: testfixtures.WithIndexedNodeLabelsConfig([]string{"rack"}, testfixtures.TestSchedulingConfig()),
+			Nodes: armadaslices.Concatenate(
+				testfixtures.WithLabelsNodes(map[string]string{"rack": "rack1"}, testfixtures.N32CpuNodes(2, testfixtures.TestPriorities)),
+				testfixtures.WithLabelsNodes(map[string]string{"rack": "rack2"}, testfixtures.N32CpuNodes(1, testfixtures.TestPriorities)),
+				testfixtures.WithLabelsNodes(map[string]string{"rack": "rack3"}, testfixtures.N32CpuNodes(1, testfixtures.TestPriorities)),
+			),
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithSpreadLabelAnnotationJobs(
+						"rack",
+						testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 3),
+					),
+				),
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithSpreadLabelAnnotationJobs(
+						"rack",
+						testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 3),
+					),
+				),
+				// Only rack1 has capacity left.
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithSpreadLabelAnnotationJobs(
+						"rack",
+						testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 2),
+					),
+				),
+			},
+			ExpectedScheduledIndices:    []int{0, 1},
+			ExpectedScheduledJobs:       []int{3, 6, 6},
+			ExpectedUnschedulableReason: "gang requires 2 distinct values of spread label rack, but only 1 have capacity for a gang member",
+		},
+		"spread label not indexed": {
+			SchedulingConfig: testfixtures.TestSchedulingConfig(),
+			Nodes:            testfixtures.WithLabelsNodes(map[string]string{"rack": "rack1"}, testfixtures.N32CpuNodes(2, testfixtures.TestPriorities)),
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithSpreadLabelAnnotationJobs(
+						"rack",
+						testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 2),
+					),
+				),
+			},
+			ExpectedScheduledIndices:    nil,
+			ExpectedScheduledJobs:       []int{0},
+			ExpectedUnschedulableReason: "spread label rack is not indexed",
+		},
+		"colocation group without running jobs": {
+			SchedulingConfig: testfixtures.TestSchedulingConfig(),
+			Nodes:            testfixtures.N32CpuNodes(2, testfixtures.TestPriorities),
+			Gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsJobs(
+					testfixtures.WithAnnotationsJobs(
+						map[string]string{configuration.ColocationGroupAnnotation: "cache"},
+						testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 4),
+					),
+				),
+			},
+			ExpectedScheduledIndices: testfixtures.IntRange(0, 0),
+			ExpectedScheduledJobs:    []int{4},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -371,8 +736,8 @@ func TestGangScheduler(t *testing.T) {
 			)
 			require.NoError(t, err)
 			txn := nodeDb.Txn(true)
-			for _, node := range tc.Nodes {
-				err := nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, nil, node)
+			for i, node := range tc.Nodes {
+				err := nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, tc.RunningJobsByNode[i], node)
 				require.NoError(t, err)
 			}
 			txn.Commit()
@@ -424,19 +789,23 @@ func TestGangScheduler(t *testing.T) {
 			)
 			sch, err := NewGangScheduler(sctx, constraints, nodeDb)
 			require.NoError(t, err)
+			sch.SetMaxNodeUniformityLabelValuesToConsider(tc.SchedulingConfig.MaxNodeUniformityLabelValuesToConsider)
 
 			var actualScheduledIndices []int
 			scheduledGangs := 0
 			for i, gang := range tc.Gangs {
-				jctxs := schedulercontext.JobSchedulingContextsFromJobs(testfixtures.TestPriorityClasses, gang, GangIdAndCardinalityFromAnnotations)
+				jctxs := jobSchedulingContextsFromJobs(testfixtures.TestPriorityClasses, gang)
 				gctx := schedulercontext.NewGangSchedulingContext(jctxs)
 				ok, reason, err := sch.Schedule(armadacontext.Background(), gctx)
 				require.NoError(t, err)
+				if gctx.NodeUniformityLabel != "" {
+					assert.Equal(t, tc.ExpectedNodeUniformityLabelValuesSkipped, gctx.NodeUniformityLabelValuesSkipped)
+				}
 				if ok {
 					require.Empty(t, reason)
 					actualScheduledIndices = append(actualScheduledIndices, i)
 
-					// If there's a node uniformity constraint, check that it's met.
+					// If there's a node uniformity constraint, check that it's met or that the penalty is recorded.
 					if gctx.NodeUniformityLabel != "" {
 						nodeUniformityLabelValues := make(map[string]bool)
 						for _, jctx := range jctxs {
@@ -447,10 +816,59 @@ func TestGangScheduler(t *testing.T) {
 							require.True(t, ok, "gang job scheduled onto node with missing nodeUniformityLabel")
 							nodeUniformityLabelValues[value] = true
 						}
-						require.Equal(
-							t, 1, len(nodeUniformityLabelValues),
-							"node uniformity constraint not met: %s", nodeUniformityLabelValues,
-						)
+						if gctx.NodeUniformityIsSoft {
+							expectedPenalty := tc.ExpectedNodeUniformityPenalties[len(actualScheduledIndices)-1]
+							require.Equal(t, expectedPenalty+1, len(nodeUniformityLabelValues))
+							for _, jctx := range jctxs {
+								require.Equal(t, expectedPenalty, jctx.NodeUniformityPenalty)
+							}
+						} else {
+							require.Equal(
+								t, 1, len(nodeUniformityLabelValues),
+								"node uniformity constraint not met: %s", nodeUniformityLabelValues,
+							)
+						}
+					}
+
+					// If the gang must span several failure domains, check that it does.
+					if gctx.MinFailureDomains > 0 {
+						failureDomains := make(map[string]bool)
+						for _, jctx := range jctxs {
+							node := nodesById[jctx.PodSchedulingContext.NodeId]
+							require.NotNil(t, node)
+							failureDomains[node.Labels[gctx.FailureDomainLabel]] = true
+						}
+						require.GreaterOrEqual(t, len(failureDomains), gctx.MinFailureDomains, "gang not spread across failure domains")
+					}
+
+					// If the gang must be spread, check that each job landed on a distinct value of the spread label.
+					if gctx.SpreadLabel != "" {
+						spreadLabelValues := make(map[string]bool)
+						for _, jctx := range jctxs {
+							node := nodesById[jctx.PodSchedulingContext.NodeId]
+							require.NotNil(t, node)
+							value := node.Labels[gctx.SpreadLabel]
+							require.False(t, spreadLabelValues[value], "several gang jobs scheduled onto value %s of spread label", value)
+							spreadLabelValues[value] = true
+						}
+					}
+
+					// If the gang is part of a colocation group with running jobs, check that it's colocated with those jobs.
+					if gctx.ColocationGroup != "" {
+						label := gctx.ColocationLabel
+						if label == "" {
+							label = schedulerconfig.NodeIdLabel
+						}
+						values, err := nodeDb.ColocationGroupLabelValues(gctx.Queue, gctx.ColocationGroup, label)
+						require.NoError(t, err)
+						// Gangs in a group without running jobs are scheduled without a colocation constraint.
+						if len(values) > 0 {
+							for _, jctx := range jctxs {
+								node, err := nodeDb.GetNode(jctx.PodSchedulingContext.NodeId)
+								require.NoError(t, err)
+								require.Contains(t, values, node.Labels[label], "gang job not colocated with colocation group")
+							}
+						}
 					}
 
 					// Verify any excess jobs that failed have the correct state set
@@ -459,7 +877,7 @@ func TestGangScheduler(t *testing.T) {
 							if jctx.PodSchedulingContext != nil {
 								require.Equal(t, "", jctx.PodSchedulingContext.NodeId)
 							}
-							require.Equal(t, "job does not fit on any node", jctx.UnschedulableReason)
+							require.Equal(t, schedulerobjects.UnschedulableReasonCodeJobDoesNotFit, jctx.UnschedulableReason.GetCode())
 						}
 					}
 
@@ -469,7 +887,10 @@ func TestGangScheduler(t *testing.T) {
 					require.Equal(t, tc.ExpectedScheduledJobs[i], sch.schedulingContext.NumScheduledJobs)
 					require.Equal(t, 0, sch.schedulingContext.NumEvictedJobs)
 				} else {
-					require.NotEmpty(t, reason)
+					require.NotNil(t, reason)
+					if tc.ExpectedUnschedulableReason != "" {
+						require.Equal(t, tc.ExpectedUnschedulableReason, reason.Error())
+					}
 
 					// Verify all jobs have been correctly unbound from nodes
 					for _, jctx := range jctxs {
@@ -488,3 +909,340 @@ func TestGangScheduler(t *testing.T) {
 		})
 	}
 }
+
+func TestGangScheduler_DryRunSchedule(t *testing.T) {
+	config := testfixtures.TestSchedulingConfig()
+	nodeDb, err := nodedb.NewNodeDb(
+		testfixtures.TestPriorityClasses,
+		testfixtures.TestMaxExtraNodesToConsider,
+		config.IndexedResources,
+		testfixtures.TestIndexedTaints,
+		config.IndexedNodeLabels,
+	)
+	require.NoError(t, err)
+	txn := nodeDb.Txn(true)
+	for _, node := range testfixtures.N32CpuNodes(2, testfixtures.TestPriorities) {
+		require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, nil, node))
+	}
+	txn.Commit()
+	totalResources := nodeDb.TotalResources()
+	fairnessCostProvider, err := fairness.NewDominantResourceFairness(
+		totalResources,
+		config.DominantResourceFairnessResourcesToConsider,
+	)
+	require.NoError(t, err)
+	sctx := schedulercontext.NewSchedulingContext(
+		"executor",
+		"pool",
+		config.Preemption.PriorityClasses,
+		config.Preemption.DefaultPriorityClass,
+		fairnessCostProvider,
+		rate.NewLimiter(rate.Limit(config.MaximumSchedulingRate), config.MaximumSchedulingBurst),
+		totalResources,
+	)
+	require.NoError(t, sctx.AddQueueSchedulingContext(
+		"A",
+		1,
+		nil,
+		rate.NewLimiter(rate.Limit(config.MaximumPerQueueSchedulingRate), config.MaximumPerQueueSchedulingBurst),
+	))
+	constraints := schedulerconstraints.SchedulingConstraintsFromSchedulingConfig(
+		"pool",
+		totalResources,
+		schedulerobjects.ResourceList{},
+		config,
+	)
+	sch, err := NewGangScheduler(sctx, constraints, nodeDb)
+	require.NoError(t, err)
+
+	dryRun := func(jobs []*jobdb.Job) *GangPlacement {
+		gctx := schedulercontext.NewGangSchedulingContext(jobSchedulingContextsFromJobs(testfixtures.TestPriorityClasses, jobs))
+		placement, err := sch.DryRunSchedule(armadacontext.Background(), gctx)
+		require.NoError(t, err)
+		return placement
+	}
+
+	// Gangs that fit are placed without being added to the context or bound in the nodeDb,
+	// such that consecutive dry runs each see all nodes as empty.
+	for i := 0; i < 2; i++ {
+		jobs := testfixtures.WithGangAnnotationsJobs(testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 4))
+		placement := dryRun(jobs)
+		require.True(t, placement.Ok)
+		assert.Nil(t, placement.UnschedulableReason)
+		assert.Len(t, placement.NodeIdByJobId, 4)
+		assert.Empty(t, placement.UnschedulableReasonByJobId)
+		assert.Equal(t, 0, sctx.NumScheduledJobs)
+		assert.Equal(t, 0, sctx.NumScheduledGangs)
+		assert.True(t, sctx.ScheduledResources.IsZero())
+		assert.Empty(t, sctx.QueueSchedulingContexts["A"].UnsuccessfulJobSchedulingContexts)
+		assert.True(t, sctx.QueueSchedulingContexts["A"].AllocatedByPriorityClass.IsZero())
+	}
+
+	// Gangs that don't fit are reported with the reason they don't.
+	jobs := testfixtures.WithGangAnnotationsJobs(testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 5))
+	placement := dryRun(jobs)
+	require.False(t, placement.Ok)
+	require.NotNil(t, placement.UnschedulableReason)
+	assert.Equal(t, schedulerobjects.UnschedulableReasonCodeGangMinCardinalityNotMet, placement.UnschedulableReason.Code)
+	assert.Empty(t, placement.NodeIdByJobId)
+	assert.Len(t, placement.UnschedulableReasonByJobId, 5)
+	assert.Empty(t, sctx.QueueSchedulingContexts["A"].UnsuccessfulJobSchedulingContexts)
+	assert.Empty(t, sctx.UnfeasibleSchedulingKeys)
+}
+
+func TestGangScheduler_HeterogeneousGang(t *testing.T) {
+	config := testfixtures.TestSchedulingConfig()
+	nodeDb, err := nodedb.NewNodeDb(
+		testfixtures.TestPriorityClasses,
+		testfixtures.TestMaxExtraNodesToConsider,
+		config.IndexedResources,
+		testfixtures.TestIndexedTaints,
+		config.IndexedNodeLabels,
+	)
+	require.NoError(t, err)
+	txn := nodeDb.Txn(true)
+	for _, node := range testfixtures.N32CpuNodes(2, testfixtures.TestPriorities) {
+		require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, nil, node))
+	}
+	txn.Commit()
+	totalResources := nodeDb.TotalResources()
+	fairnessCostProvider, err := fairness.NewDominantResourceFairness(
+		totalResources,
+		config.DominantResourceFairnessResourcesToConsider,
+	)
+	require.NoError(t, err)
+	sctx := schedulercontext.NewSchedulingContext(
+		"executor",
+		"pool",
+		config.Preemption.PriorityClasses,
+		config.Preemption.DefaultPriorityClass,
+		fairnessCostProvider,
+		rate.NewLimiter(rate.Limit(config.MaximumSchedulingRate), config.MaximumSchedulingBurst),
+		totalResources,
+	)
+	require.NoError(t, sctx.AddQueueSchedulingContext(
+		"A",
+		1,
+		nil,
+		rate.NewLimiter(rate.Limit(config.MaximumPerQueueSchedulingRate), config.MaximumPerQueueSchedulingBurst),
+	))
+	constraints := schedulerconstraints.SchedulingConstraintsFromSchedulingConfig(
+		"pool",
+		totalResources,
+		schedulerobjects.ResourceList{},
+		config,
+	)
+	sch, err := NewGangScheduler(sctx, constraints, nodeDb)
+	require.NoError(t, err)
+
+	// The cpu members of the gang fit, but the gpu member doesn't fit on any node.
+	cpuJobs := testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 3)
+	gpuJobs := testfixtures.N1GpuJobs("A", testfixtures.PriorityClass0, 1)
+	jobs := testfixtures.WithGangAnnotationsJobs(armadaslices.Concatenate(cpuJobs, gpuJobs))
+	jctxs := jobSchedulingContextsFromJobs(testfixtures.TestPriorityClasses, jobs)
+	gctx := schedulercontext.NewGangSchedulingContext(jctxs)
+	assert.False(t, gctx.Homogeneous)
+	ok, reason, err := sch.Schedule(armadacontext.Background(), gctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+	assert.Equal(t, schedulerobjects.UnschedulableReasonCodeGangMinCardinalityNotMet, reason.GetCode())
+
+	// Each member is given its own reason.
+	for _, jctx := range jctxs[:3] {
+		assert.Equal(t, schedulerobjects.UnschedulableReasonCodeGangMinCardinalityNotMet, jctx.UnschedulableReason.GetCode())
+	}
+	assert.Equal(t, schedulerobjects.UnschedulableReasonCodeJobDoesNotFit, jctxs[3].UnschedulableReason.GetCode())
+
+	// Members of homogeneous gangs are all given the reason of the gang.
+	jctxs = jobSchedulingContextsFromJobs(
+		testfixtures.TestPriorityClasses,
+		testfixtures.WithGangAnnotationsJobs(testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 5)),
+	)
+	gctx = schedulercontext.NewGangSchedulingContext(jctxs)
+	assert.True(t, gctx.Homogeneous)
+	ok, _, err = sch.Schedule(armadacontext.Background(), gctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+	for _, jctx := range jctxs {
+		assert.Equal(t, schedulerobjects.UnschedulableReasonCodeGangMinCardinalityNotMet, jctx.UnschedulableReason.GetCode())
+	}
+}
+
+func TestGangScheduler_HierarchicalNodeUniformity(t *testing.T) {
+	config := testfixtures.WithIndexedNodeLabelsConfig([]string{"region", "zone", "rack"}, testfixtures.TestSchedulingConfig())
+	nodeDb, err := nodedb.NewNodeDb(
+		testfixtures.TestPriorityClasses,
+		testfixtures.TestMaxExtraNodesToConsider,
+		config.IndexedResources,
+		testfixtures.TestIndexedTaints,
+		config.IndexedNodeLabels,
+	)
+	require.NoError(t, err)
+	nodes := armadaslices.Concatenate(
+		testfixtures.WithLabelsNodes(
+			map[string]string{"region": "r1", "zone": "z1", "rack": "a"},
+			testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+		),
+		testfixtures.WithLabelsNodes(
+			map[string]string{"region": "r1", "zone": "z1", "rack": "b"},
+			testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+		),
+		testfixtures.WithLabelsNodes(
+			map[string]string{"region": "r1", "zone": "z2", "rack": "c"},
+			testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+		),
+	)
+	txn := nodeDb.Txn(true)
+	for _, node := range nodes {
+		require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, nil, node))
+	}
+	txn.Commit()
+	totalResources := nodeDb.TotalResources()
+	fairnessCostProvider, err := fairness.NewDominantResourceFairness(
+		totalResources,
+		config.DominantResourceFairnessResourcesToConsider,
+	)
+	require.NoError(t, err)
+	sctx := schedulercontext.NewSchedulingContext(
+		"executor",
+		"pool",
+		config.Preemption.PriorityClasses,
+		config.Preemption.DefaultPriorityClass,
+		fairnessCostProvider,
+		rate.NewLimiter(rate.Limit(config.MaximumSchedulingRate), config.MaximumSchedulingBurst),
+		totalResources,
+	)
+	require.NoError(t, sctx.AddQueueSchedulingContext(
+		"A",
+		1,
+		nil,
+		rate.NewLimiter(rate.Limit(config.MaximumPerQueueSchedulingRate), config.MaximumPerQueueSchedulingBurst),
+	))
+	constraints := schedulerconstraints.SchedulingConstraintsFromSchedulingConfig(
+		"pool",
+		totalResources,
+		schedulerobjects.ResourceList{},
+		config,
+	)
+	sch, err := NewGangScheduler(sctx, constraints, nodeDb)
+	require.NoError(t, err)
+
+	newGang := func(n int) *schedulercontext.GangSchedulingContext {
+		jobs := testfixtures.WithGangAnnotationsJobs(
+			testfixtures.WithAnnotationsJobs(
+				map[string]string{configuration.GangNodeUniformityLabelsAnnotation: "region,zone,rack"},
+				testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, n),
+			),
+		)
+		return schedulercontext.NewGangSchedulingContext(jobSchedulingContextsFromJobs(testfixtures.TestPriorityClasses, jobs))
+	}
+
+	// No rack can fit this gang, but zone z1 can.
+	gctx := newGang(4)
+	assert.Equal(t, []string{"region", "zone", "rack"}, gctx.NodeUniformityLabels)
+	ok, reason, err := sch.Schedule(armadacontext.Background(), gctx)
+	require.NoError(t, err)
+	require.True(t, ok, reason)
+	assert.Equal(t, "zone", gctx.AchievedNodeUniformityLabel)
+	for _, jctx := range gctx.JobSchedulingContexts {
+		require.NotNil(t, jctx.PodSchedulingContext)
+		node, err := nodeDb.GetNode(jctx.PodSchedulingContext.NodeId)
+		require.NoError(t, err)
+		assert.Equal(t, "z1", node.Labels["zone"])
+	}
+
+	// The finest label is preferred whenever the gang fits within a single value of it.
+	gctx = newGang(2)
+	ok, reason, err = sch.Schedule(armadacontext.Background(), gctx)
+	require.NoError(t, err)
+	require.True(t, ok, reason)
+	assert.Equal(t, "rack", gctx.AchievedNodeUniformityLabel)
+
+	// There's no capacity left for this gang at any level.
+	gctx = newGang(1)
+	ok, reason, err = sch.Schedule(armadacontext.Background(), gctx)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, schedulerobjects.UnschedulableReasonCodeNodeUniformity, reason.GetCode())
+	assert.Empty(t, gctx.AchievedNodeUniformityLabel)
+}
+
+func TestGangScheduler_NodeRejectionDiagnostics(t *testing.T) {
+	config := testfixtures.TestSchedulingConfig()
+	nodeDb, err := nodedb.NewNodeDb(
+		testfixtures.TestPriorityClasses,
+		testfixtures.TestMaxExtraNodesToConsider,
+		config.IndexedResources,
+		testfixtures.TestIndexedTaints,
+		config.IndexedNodeLabels,
+	)
+	require.NoError(t, err)
+	txn := nodeDb.Txn(true)
+	nodes := armadaslices.Concatenate(
+		testfixtures.WithLabelsNodes(map[string]string{"zone": "a"}, testfixtures.N32CpuNodes(2, testfixtures.TestPriorities)),
+		testfixtures.WithLabelsNodes(map[string]string{"zone": "a"}, testfixtures.NTainted32CpuNodes(1, testfixtures.TestPriorities)),
+		testfixtures.WithLabelsNodes(map[string]string{"zone": "b"}, testfixtures.N8GpuNodes(1, testfixtures.TestPriorities)),
+	)
+	for _, node := range nodes {
+		require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, nil, node))
+	}
+	txn.Commit()
+	totalResources := nodeDb.TotalResources()
+	fairnessCostProvider, err := fairness.NewDominantResourceFairness(
+		totalResources,
+		config.DominantResourceFairnessResourcesToConsider,
+	)
+	require.NoError(t, err)
+	sctx := schedulercontext.NewSchedulingContext(
+		"executor",
+		"pool",
+		config.Preemption.PriorityClasses,
+		config.Preemption.DefaultPriorityClass,
+		fairnessCostProvider,
+		rate.NewLimiter(rate.Limit(config.MaximumSchedulingRate), config.MaximumSchedulingBurst),
+		totalResources,
+	)
+	require.NoError(t, sctx.AddQueueSchedulingContext(
+		"A",
+		1,
+		nil,
+		rate.NewLimiter(rate.Limit(config.MaximumPerQueueSchedulingRate), config.MaximumPerQueueSchedulingBurst),
+	))
+	constraints := schedulerconstraints.SchedulingConstraintsFromSchedulingConfig(
+		"pool",
+		totalResources,
+		schedulerobjects.ResourceList{},
+		config,
+	)
+	sch, err := NewGangScheduler(sctx, constraints, nodeDb)
+	require.NoError(t, err)
+	sch.EnableNodeRejectionDiagnostics()
+
+	// The only gpu node is in a different zone than the one selected by the gang.
+	jobs := testfixtures.WithGangAnnotationsJobs(
+		testfixtures.WithNodeSelectorJobs(map[string]string{"zone": "a"}, testfixtures.N1GpuJobs("A", testfixtures.PriorityClass0, 2)),
+	)
+	jctxs := jobSchedulingContextsFromJobs(testfixtures.TestPriorityClasses, jobs)
+	gctx := schedulercontext.NewGangSchedulingContext(jctxs)
+	ok, _, err := sch.Schedule(armadacontext.Background(), gctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	expected := &schedulercontext.NodeRejections{
+		NumNodes: 4,
+		NumRejectedNodesByReason: map[string]int{
+			"insufficient gpu": 2,
+			"taint largeJobsOnly=true:NoSchedule not tolerated": 1,
+			"node selector mismatch on label zone":              1,
+		},
+	}
+	for _, jctx := range jctxs {
+		assert.Equal(t, expected, jctx.NodeRejections)
+		assert.Contains(t, jctx.UnschedulableReason.Message, expected.String())
+	}
+	assert.Equal(
+		t,
+		"4/4 nodes rejected: 2 insufficient gpu, 1 node selector mismatch on label zone, 1 taint largeJobsOnly=true:NoSchedule not tolerated",
+		expected.String(),
+	)
+}
@@ -0,0 +1,210 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/jobstate"
+	"github.com/armadaproject/armada/internal/scheduler/database"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// GangStatusHttpHandler serves the status of a gang, aggregated over the jobs in the jobDb that make it up,
+// such that users don't need to reconstruct the health of a gang from the events of its individual jobs.
+// The gang to look up is given by the gangId query parameter, optionally restricted to a queue by the queue query parameter,
+// e.g., /gangStatus?gangId=my-gang&queue=my-queue.
+type GangStatusHttpHandler struct {
+	jobDb                       *jobdb.JobDb
+	executorRepository          database.ExecutorRepository
+	schedulingContextRepository *SchedulingContextRepository
+}
+
+// GangStatus is the response returned by GangStatusHttpHandler.
+type GangStatus struct {
+	GangId string `json:"gangId"`
+	Queue  string `json:"queue"`
+	JobSet string `json:"jobSet"`
+	// Number of jobs in the gang and minimum number of jobs that must be scheduled for the gang to be scheduled,
+	// as given by the gang annotations of its jobs.
+	Cardinality        int `json:"cardinality"`
+	MinimumCardinality int `json:"minimumCardinality"`
+	// Number of jobs in the gang yet to terminate.
+	CurrentCardinality int `json:"currentCardinality"`
+	// Number of jobs in the gang that are leased, pending, or running.
+	NumScheduled int `json:"numScheduled"`
+	NumRunning   int `json:"numRunning"`
+	NumFailed    int `json:"numFailed"`
+	// Number of jobs in the gang by state. Jobs that have terminated and since been removed from the jobDb
+	// aren't counted, and neither are jobs the gang is missing; see NumMissing.
+	NumMembersByState map[jobstate.State]int `json:"numMembersByState"`
+	// Cardinality less the number of jobs of the gang in the jobDb.
+	NumMissing int `json:"numMissing"`
+	// Node label all jobs in the gang must be scheduled across nodes with equal value for, if any,
+	// and the values of that label on the nodes jobs of the gang are scheduled on.
+	// There's more than one value only for gangs with a soft uniformity constraint that were spread out.
+	NodeUniformityLabel       string   `json:"nodeUniformityLabel,omitempty"`
+	NodeUniformityLabelValues []string `json:"nodeUniformityLabelValues,omitempty"`
+	// Most recent reason any job in the gang couldn't be scheduled, if any.
+	LastUnschedulableReason *GangUnschedulableReason `json:"lastUnschedulableReason,omitempty"`
+	Members                 []GangMember             `json:"members"`
+}
+
+type GangMember struct {
+	JobId string         `json:"jobId"`
+	State jobstate.State `json:"state"`
+	// Node the latest run of the job is assigned to, if any.
+	Node string `json:"node,omitempty"`
+}
+
+type GangUnschedulableReason struct {
+	Time     time.Time                                `json:"time"`
+	Executor string                                   `json:"executor"`
+	JobId    string                                   `json:"jobId"`
+	Code     schedulerobjects.UnschedulableReasonCode `json:"code"`
+	Message  string                                   `json:"message"`
+}
+
+func NewGangStatusHttpHandler(
+	jobDb *jobdb.JobDb,
+	executorRepository database.ExecutorRepository,
+	schedulingContextRepository *SchedulingContextRepository,
+) *GangStatusHttpHandler {
+	return &GangStatusHttpHandler{
+		jobDb:                       jobDb,
+		executorRepository:          executorRepository,
+		schedulingContextRepository: schedulingContextRepository,
+	}
+}
+
+func (h *GangStatusHttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	gangId := r.URL.Query().Get("gangId")
+	if gangId == "" {
+		http.Error(w, "missing gangId query parameter", http.StatusBadRequest)
+		return
+	}
+	queue := r.URL.Query().Get("queue")
+	var jobs []*jobdb.Job
+	for _, job := range h.jobDb.ReadTxn().GetAll() {
+		if job.GetAnnotations()[configuration.GangIdAnnotation] == gangId && (queue == "" || job.Queue() == queue) {
+			jobs = append(jobs, job)
+		}
+	}
+	if len(jobs) == 0 {
+		http.Error(w, "gang "+gangId+" not found", http.StatusNotFound)
+		return
+	}
+	nodeLabelsById, err := h.nodeLabelsById(armadacontext.New(r.Context(), log.NewEntry(log.StandardLogger())))
+	if err != nil {
+		// Node labels are only needed to report the uniformity label values; return the status without them.
+		log.WithError(err).Warn("failed to look up node labels")
+	}
+	rv := h.gangStatus(gangId, jobs, nodeLabelsById)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rv); err != nil {
+		log.WithError(err).Error("failed to write gang status response")
+	}
+}
+
+func (h *GangStatusHttpHandler) gangStatus(gangId string, jobs []*jobdb.Job, nodeLabelsById map[string]map[string]string) *GangStatus {
+	slices.SortFunc(jobs, func(a, b *jobdb.Job) bool { return a.Id() < b.Id() })
+	rv := &GangStatus{
+		GangId:              gangId,
+		Queue:               jobs[0].Queue(),
+		JobSet:              jobs[0].Jobset(),
+		NumMembersByState:   make(map[jobstate.State]int),
+		NodeUniformityLabel: jobs[0].GetAnnotations()[configuration.GangNodeUniformityLabelAnnotation],
+	}
+	if _, cardinality, minimumCardinality, _, err := GangIdAndCardinalityFromAnnotations(jobs[0].GetAnnotations()); err == nil {
+		rv.Cardinality = cardinality
+		rv.MinimumCardinality = minimumCardinality
+	}
+	if rv.NumMissing = rv.Cardinality - len(jobs); rv.NumMissing < 0 {
+		rv.NumMissing = 0
+	}
+
+	nodeUniformityLabelValues := make(map[string]bool)
+	for _, job := range jobs {
+		state := job.State()
+		member := GangMember{JobId: job.Id(), State: state}
+		rv.NumMembersByState[state]++
+		if !state.IsTerminal() {
+			rv.CurrentCardinality++
+		}
+		switch state {
+		case jobstate.Leased, jobstate.Pending:
+			rv.NumScheduled++
+		case jobstate.Running:
+			rv.NumScheduled++
+			rv.NumRunning++
+		case jobstate.Failed:
+			rv.NumFailed++
+		}
+		if run := job.LatestRun(); run != nil {
+			member.Node = run.NodeName()
+			if rv.NodeUniformityLabel != "" && !state.IsTerminal() {
+				if value, ok := nodeLabelsById[run.NodeId()][rv.NodeUniformityLabel]; ok {
+					nodeUniformityLabelValues[value] = true
+				}
+			}
+		}
+		rv.Members = append(rv.Members, member)
+		if reason := h.lastUnschedulableReason(job.Id()); reason != nil {
+			if rv.LastUnschedulableReason == nil || reason.Time.After(rv.LastUnschedulableReason.Time) {
+				rv.LastUnschedulableReason = reason
+			}
+		}
+	}
+	rv.NodeUniformityLabelValues = maps.Keys(nodeUniformityLabelValues)
+	slices.Sort(rv.NodeUniformityLabelValues)
+	return rv
+}
+
+// lastUnschedulableReason returns the most recent reason the job couldn't be scheduled across all executors, if any.
+func (h *GangStatusHttpHandler) lastUnschedulableReason(jobId string) *GangUnschedulableReason {
+	if h.schedulingContextRepository == nil {
+		return nil
+	}
+	byExecutor, ok := h.schedulingContextRepository.GetMostRecentSchedulingContextByExecutorForJob(jobId)
+	if !ok {
+		return nil
+	}
+	var rv *GangUnschedulableReason
+	for executorId, sctx := range byExecutor {
+		jctx := getSchedulingReportForJob(sctx, jobId).jobSchedulingContext
+		if jctx == nil || jctx.UnschedulableReason == nil {
+			continue
+		}
+		if rv == nil || jctx.Created.After(rv.Time) {
+			rv = &GangUnschedulableReason{
+				Time:     jctx.Created,
+				Executor: executorId,
+				JobId:    jobId,
+				Code:     jctx.UnschedulableReason.Code,
+				Message:  jctx.UnschedulableReason.Error(),
+			}
+		}
+	}
+	return rv
+}
+
+func (h *GangStatusHttpHandler) nodeLabelsById(ctx *armadacontext.Context) (map[string]map[string]string, error) {
+	executors, err := h.executorRepository.GetExecutors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rv := make(map[string]map[string]string)
+	for _, executor := range executors {
+		for _, node := range executor.Nodes {
+			rv[node.Id] = node.Labels
+		}
+	}
+	return rv, nil
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/jobstate"
+	schedulermocks "github.com/armadaproject/armada/internal/scheduler/mocks"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestGangStatusHttpHandler(t *testing.T) {
+	jobs := testfixtures.WithNodeUniformityLabelAnnotationJobs(
+		"zone",
+		testfixtures.WithGangAnnotationsAndMinCardinalityJobs(
+			2,
+			testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 4),
+		),
+	)
+	gangId := jobs[0].GetAnnotations()[configuration.GangIdAnnotation]
+	running := jobs[0].WithQueued(false).WithNewRun("executor", "node-1", "node-1")
+	running = running.WithUpdatedRun(running.LatestRun().WithRunning(true))
+	leased := jobs[1].WithQueued(false).WithNewRun("executor", "node-2", "node-2")
+	failed := jobs[2].WithQueued(false).WithFailed(true)
+	// The fourth member of the gang has terminated and been removed from the jobDb.
+
+	jobDb := testfixtures.NewJobDb()
+	txn := jobDb.WriteTxn()
+	require.NoError(t, txn.Upsert(append(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 1), running, leased, failed)))
+	txn.Commit()
+
+	executors := []*schedulerobjects.Executor{
+		{
+			Id: "executor",
+			Nodes: []*schedulerobjects.Node{
+				{Id: "node-1", Labels: map[string]string{"zone": "a"}},
+				{Id: "node-2", Labels: map[string]string{"zone": "a"}},
+			},
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mockExecutorRepo := schedulermocks.NewMockExecutorRepository(ctrl)
+	mockExecutorRepo.EXPECT().GetExecutors(gomock.Any()).Return(executors, nil).AnyTimes()
+	handler := NewGangStatusHttpHandler(jobDb, mockExecutorRepo, nil)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/gangStatus?gangId="+gangId, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var rv GangStatus
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&rv))
+	assert.Equal(t, gangId, rv.GangId)
+	assert.Equal(t, "A", rv.Queue)
+	assert.Equal(t, 4, rv.Cardinality)
+	assert.Equal(t, 2, rv.MinimumCardinality)
+	assert.Equal(t, 2, rv.CurrentCardinality)
+	assert.Equal(t, 2, rv.NumScheduled)
+	assert.Equal(t, 1, rv.NumRunning)
+	assert.Equal(t, 1, rv.NumFailed)
+	assert.Equal(t, 1, rv.NumMissing)
+	assert.Equal(t, map[jobstate.State]int{jobstate.Running: 1, jobstate.Leased: 1, jobstate.Failed: 1}, rv.NumMembersByState)
+	assert.Equal(t, "zone", rv.NodeUniformityLabel)
+	assert.Equal(t, []string{"a"}, rv.NodeUniformityLabelValues)
+	assert.Nil(t, rv.LastUnschedulableReason)
+	assert.Len(t, rv.Members, 3)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/gangStatus?gangId="+gangId+"&queue=B", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/gangStatus", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
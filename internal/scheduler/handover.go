@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/logging"
+)
+
+// HandoverState is in-memory state of the leading scheduler that's handed over to the next leader,
+// e.g., the credit queues have accrued for work lost to preemption, such that it isn't lost when the leader is redeployed.
+// The state is persisted when the leader shuts down and restored when a scheduler becomes leader.
+type HandoverState interface {
+	// MarshalState serialises the state.
+	MarshalState() ([]byte, error)
+	// UnmarshalState replaces the state with serialised state previously returned by MarshalState.
+	UnmarshalState(state []byte) error
+}
+
+// persistHandoverState persists all state to be handed over to the next leader.
+// Failing to persist some state doesn't prevent persisting the rest.
+func (s *Scheduler) persistHandoverState(ctx *armadacontext.Context) {
+	if s.handoverStateRepository == nil {
+		return
+	}
+	now := s.clock.Now()
+	for name, state := range s.handoverStateByName {
+		bytes, err := state.MarshalState()
+		if err == nil {
+			err = s.handoverStateRepository.SetSchedulerState(ctx, name, bytes, now)
+		}
+		if err != nil {
+			logging.WithStacktrace(ctx, err).Errorf("failed to persist %s state for the next leader", name)
+			continue
+		}
+		ctx.Infof("persisted %s state for the next leader", name)
+	}
+}
+
+// restoreHandoverState restores all state handed over by the previous leader.
+// State never persisted, e.g., since there was no previous leader, is left unchanged.
+func (s *Scheduler) restoreHandoverState(ctx *armadacontext.Context) {
+	if s.handoverStateRepository == nil {
+		return
+	}
+	for name, state := range s.handoverStateByName {
+		bytes, err := s.handoverStateRepository.GetSchedulerState(ctx, name)
+		if err == nil && bytes != nil {
+			err = errors.WithMessagef(state.UnmarshalState(bytes), "failed to unmarshal %s state", name)
+		}
+		if err != nil {
+			logging.WithStacktrace(ctx, err).Errorf("failed to restore %s state of the previous leader", name)
+			continue
+		}
+		if bytes != nil {
+			ctx.Infof("restored %s state of the previous leader", name)
+		}
+	}
+}
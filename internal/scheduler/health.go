@@ -0,0 +1,209 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/go-redis/redis"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/database"
+)
+
+// DependencyCheck checks whether a dependency of the scheduler, e.g., Postgres, is available.
+type DependencyCheck struct {
+	Name  string
+	Check func(ctx *armadacontext.Context) error
+	// If true, the dependency being unavailable makes the scheduler unhealthy, but not unready.
+	// Used for dependencies that may be unavailable because the scheduler isn't ready, which would otherwise never recover.
+	IgnoredForReadiness bool
+}
+
+// PostgresCheck returns a check that pings Postgres.
+func PostgresCheck(db *pgxpool.Pool) DependencyCheck {
+	return DependencyCheck{
+		Name:  "postgres",
+		Check: func(ctx *armadacontext.Context) error { return db.Ping(ctx) },
+	}
+}
+
+// RedisCheck returns a check that pings Redis.
+func RedisCheck(client redis.UniversalClient) DependencyCheck {
+	return DependencyCheck{
+		Name:  "redis",
+		Check: func(_ *armadacontext.Context) error { return client.Ping().Err() },
+	}
+}
+
+// PulsarCheck returns a check that looks up the partitions of topic, which requires a connection to a Pulsar broker.
+func PulsarCheck(client pulsar.Client, topic string) DependencyCheck {
+	return DependencyCheck{
+		Name: "pulsar",
+		Check: func(_ *armadacontext.Context) error {
+			_, err := client.TopicPartitions(topic)
+			return err
+		},
+	}
+}
+
+// ExecutorApiCheck returns a check that fails if no executor has reported in via the executor api for more than maxAge,
+// e.g., since the executor api is unreachable. Since executors reach the executor api via ready schedulers only,
+// this check doesn't affect readiness.
+func ExecutorApiCheck(executorRepository database.ExecutorRepository, maxAge time.Duration, clock clock.Clock) DependencyCheck {
+	return DependencyCheck{
+		Name:                "executorApi",
+		IgnoredForReadiness: true,
+		Check: func(ctx *armadacontext.Context) error {
+			lastUpdateTimes, err := executorRepository.GetLastUpdateTimes(ctx)
+			if err != nil {
+				return err
+			}
+			var lastUpdateTime time.Time
+			for _, t := range lastUpdateTimes {
+				if t.After(lastUpdateTime) {
+					lastUpdateTime = t
+				}
+			}
+			if lastUpdateTime.IsZero() {
+				return errors.New("no executor has reported in")
+			}
+			if age := clock.Since(lastUpdateTime); age > maxAge {
+				return errors.Errorf("no executor has reported in for %s", age.Round(time.Second))
+			}
+			return nil
+		},
+	}
+}
+
+// DependencyStatus is the result of a DependencyCheck.
+type DependencyStatus struct {
+	Name    string  `json:"name"`
+	Healthy bool    `json:"healthy"`
+	Error   string  `json:"error,omitempty"`
+	Seconds float64 `json:"seconds"`
+}
+
+// HealthStatus is the status reported by SchedulerHealthChecker.
+type HealthStatus struct {
+	// Healthy is true if all checks passed, and Ready if all checks affecting readiness passed.
+	Healthy      bool               `json:"healthy"`
+	Ready        bool               `json:"ready"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+	// Time at which the scheduler last completed a cycle, and a scheduling round, successfully.
+	// Only the leader runs scheduling rounds. Unset if there hasn't been any such cycle or round.
+	LastSuccessfulCycle           *time.Time `json:"lastSuccessfulCycle,omitempty"`
+	LastSuccessfulCycleAgeSeconds float64    `json:"lastSuccessfulCycleAgeSeconds,omitempty"`
+	LastSuccessfulRound           *time.Time `json:"lastSuccessfulRound,omitempty"`
+	LastSuccessfulRoundAgeSeconds float64    `json:"lastSuccessfulRoundAgeSeconds,omitempty"`
+	// Reasons the scheduler isn't healthy, if any.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// SchedulerHealthChecker checks the dependencies of the scheduler and how long ago it last completed a cycle successfully.
+// It implements health.Checker, such that it can back a readiness endpoint, and serves a detailed HealthStatus as json.
+type SchedulerHealthChecker struct {
+	checks []DependencyCheck
+	// Returns the time at which the scheduler last completed a cycle, and a scheduling round, successfully.
+	lastSuccessfulCycle func() time.Time
+	lastSuccessfulRound func() time.Time
+	config              schedulerconfig.HealthConfig
+	clock               clock.Clock
+}
+
+func NewSchedulerHealthChecker(scheduler *Scheduler, config schedulerconfig.HealthConfig, checks ...DependencyCheck) *SchedulerHealthChecker {
+	return &SchedulerHealthChecker{
+		checks:              checks,
+		lastSuccessfulCycle: scheduler.LastSuccessfulCycleTime,
+		lastSuccessfulRound: scheduler.LastSuccessfulRoundTime,
+		config:              config,
+		clock:               clock.RealClock{},
+	}
+}
+
+// Status runs all dependency checks and returns the resulting status.
+func (c *SchedulerHealthChecker) Status(ctx *armadacontext.Context) *HealthStatus {
+	status := &HealthStatus{Dependencies: make([]DependencyStatus, len(c.checks)), Ready: true}
+	for i, check := range c.checks {
+		start := c.clock.Now()
+		err := c.runCheck(ctx, check)
+		status.Dependencies[i] = DependencyStatus{
+			Name:    check.Name,
+			Healthy: err == nil,
+			Seconds: c.clock.Since(start).Seconds(),
+		}
+		if err != nil {
+			status.Dependencies[i].Error = err.Error()
+			status.Errors = append(status.Errors, fmt.Sprintf("%s: %s", check.Name, err))
+			status.Ready = status.Ready && check.IgnoredForReadiness
+		}
+	}
+	if t := c.lastSuccessfulCycle(); !t.IsZero() {
+		age := c.clock.Since(t)
+		status.LastSuccessfulCycle = &t
+		status.LastSuccessfulCycleAgeSeconds = age.Seconds()
+		if c.config.MaxCycleAge > 0 && age > c.config.MaxCycleAge {
+			status.Errors = append(status.Errors, fmt.Sprintf("no cycle has completed successfully for %s", age.Round(time.Second)))
+			status.Ready = false
+		}
+	} else {
+		status.Errors = append(status.Errors, "no cycle has completed successfully yet")
+		status.Ready = false
+	}
+	if t := c.lastSuccessfulRound(); !t.IsZero() {
+		status.LastSuccessfulRound = &t
+		status.LastSuccessfulRoundAgeSeconds = c.clock.Since(t).Seconds()
+	}
+	status.Healthy = len(status.Errors) == 0
+	return status
+}
+
+// runCheck runs check, giving up once the configured timeout has elapsed;
+// some clients, e.g., the Redis client, don't accept a context.
+func (c *SchedulerHealthChecker) runCheck(ctx *armadacontext.Context, check DependencyCheck) error {
+	if c.config.CheckTimeout > 0 {
+		var cancel func()
+		ctx, cancel = armadacontext.WithTimeout(ctx, c.config.CheckTimeout)
+		defer cancel()
+	}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- check.Check(ctx)
+	}()
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return errors.WithMessage(ctx.Err(), "check timed out")
+	}
+}
+
+// Check returns an error if the scheduler isn't ready, i.e., if any dependency affecting readiness is unavailable
+// or if the scheduler hasn't completed a cycle recently.
+func (c *SchedulerHealthChecker) Check() error {
+	status := c.Status(armadacontext.Background())
+	if status.Ready {
+		return nil
+	}
+	return errors.New(strings.Join(status.Errors, "\n"))
+}
+
+// ServeHTTP serves the HealthStatus as json, with status code 503 if the scheduler isn't healthy.
+func (c *SchedulerHealthChecker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status := c.Status(armadacontext.New(r.Context(), log.NewEntry(log.StandardLogger())))
+	w.Header().Set("Content-Type", "application/json")
+	if !status.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.WithError(err).Error("failed to write health status response")
+	}
+}
@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+)
+
+func TestSchedulerHealthChecker_Status(t *testing.T) {
+	now := time.Now()
+	healthy := DependencyCheck{Name: "healthy", Check: func(_ *armadacontext.Context) error { return nil }}
+	unhealthy := DependencyCheck{Name: "unhealthy", Check: func(_ *armadacontext.Context) error { return errors.New("unavailable") }}
+	unhealthyIgnoredForReadiness := unhealthy
+	unhealthyIgnoredForReadiness.IgnoredForReadiness = true
+	hanging := DependencyCheck{Name: "hanging", Check: func(ctx *armadacontext.Context) error {
+		<-ctx.Done()
+		time.Sleep(time.Second)
+		return nil
+	}}
+
+	tests := map[string]struct {
+		checks              []DependencyCheck
+		lastSuccessfulCycle time.Time
+		expectedHealthy     bool
+		expectedReady       bool
+		expectedErrors      []string
+	}{
+		"healthy": {
+			checks:              []DependencyCheck{healthy},
+			lastSuccessfulCycle: now.Add(-time.Second),
+			expectedHealthy:     true,
+			expectedReady:       true,
+		},
+		"dependency unavailable": {
+			checks:              []DependencyCheck{healthy, unhealthy},
+			lastSuccessfulCycle: now.Add(-time.Second),
+			expectedErrors:      []string{"unhealthy: unavailable"},
+		},
+		"dependency ignored for readiness unavailable": {
+			checks:              []DependencyCheck{healthy, unhealthyIgnoredForReadiness},
+			lastSuccessfulCycle: now.Add(-time.Second),
+			expectedReady:       true,
+			expectedErrors:      []string{"unhealthy: unavailable"},
+		},
+		"dependency check times out": {
+			checks:              []DependencyCheck{hanging},
+			lastSuccessfulCycle: now.Add(-time.Second),
+			expectedErrors:      []string{"hanging: check timed out: context deadline exceeded"},
+		},
+		"no cycle completed": {
+			checks:         []DependencyCheck{healthy},
+			expectedErrors: []string{"no cycle has completed successfully yet"},
+		},
+		"last cycle too old": {
+			checks:              []DependencyCheck{healthy},
+			lastSuccessfulCycle: now.Add(-time.Hour),
+			expectedErrors:      []string{"no cycle has completed successfully for 1h0m0s"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			checker := &SchedulerHealthChecker{
+				checks:              tc.checks,
+				lastSuccessfulCycle: func() time.Time { return tc.lastSuccessfulCycle },
+				lastSuccessfulRound: func() time.Time { return time.Time{} },
+				config: schedulerconfig.HealthConfig{
+					CheckTimeout: 10 * time.Millisecond,
+					MaxCycleAge:  time.Minute,
+				},
+				clock: clock.NewFakeClock(now),
+			}
+			status := checker.Status(armadacontext.Background())
+			assert.Equal(t, tc.expectedHealthy, status.Healthy)
+			assert.Equal(t, tc.expectedReady, status.Ready)
+			assert.Equal(t, tc.expectedErrors, status.Errors)
+			assert.Len(t, status.Dependencies, len(tc.checks))
+			if tc.expectedReady {
+				assert.NoError(t, checker.Check())
+			} else {
+				assert.Error(t, checker.Check())
+			}
+		})
+	}
+}
+
+func TestExecutorApiCheck(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		updateTimes map[string]time.Time
+		expectError bool
+	}{
+		"recent update": {
+			updateTimes: map[string]time.Time{"stale": now.Add(-time.Hour), "fresh": now.Add(-time.Second)},
+		},
+		"no recent update": {
+			updateTimes: map[string]time.Time{"stale": now.Add(-time.Hour)},
+			expectError: true,
+		},
+		"no executors": {
+			expectError: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			check := ExecutorApiCheck(testExecutorRepository{updateTimes: tc.updateTimes}, time.Minute, clock.NewFakeClock(now))
+			err := check.Check(armadacontext.Background())
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/interfaces"
+)
+
+// IntraQueueOrderingJobRepository wraps a JobRepository, returning the queued jobs of each queue
+// in the order given by an IntraQueueOrdering rather than in Fifo order.
+type IntraQueueOrderingJobRepository struct {
+	JobRepository
+	ordering configuration.IntraQueueOrdering
+}
+
+func NewIntraQueueOrderingJobRepository(jobRepo JobRepository, ordering configuration.IntraQueueOrdering) *IntraQueueOrderingJobRepository {
+	return &IntraQueueOrderingJobRepository{
+		JobRepository: jobRepo,
+		ordering:      ordering,
+	}
+}
+
+// GetQueueJobIds returns the ids of the queued jobs of the given queue, ordered according to the ordering of repo.
+// Assumes the underlying repository returns job ids in Fifo order.
+func (repo *IntraQueueOrderingJobRepository) GetQueueJobIds(queue string) ([]string, error) {
+	jobIds, err := repo.JobRepository.GetQueueJobIds(queue)
+	if err != nil {
+		return nil, err
+	}
+	if repo.ordering == "" || repo.ordering == configuration.Fifo {
+		return jobIds, nil
+	}
+	jobs, err := repo.GetExistingJobsByIds(jobIds)
+	if err != nil {
+		return nil, err
+	}
+	switch repo.ordering {
+	case configuration.JobSetRoundRobin:
+		jobs = orderJobsByJobSetRoundRobin(jobs)
+	case configuration.ShortestJobFirst:
+		orderJobsByExpectedRuntime(jobs)
+	default:
+		return nil, errors.Errorf("unknown intra-queue ordering %s for queue %s", repo.ordering, queue)
+	}
+	rv := make([]string, len(jobs))
+	for i, job := range jobs {
+		rv[i] = job.GetId()
+	}
+	return rv, nil
+}
+
+// orderJobsByJobSetRoundRobin returns jobs reordered such that job sets take turns,
+// in the order of their first job in jobs, with the jobs of each job set retaining their relative order.
+func orderJobsByJobSetRoundRobin(jobs []interfaces.LegacySchedulerJob) []interfaces.LegacySchedulerJob {
+	jobSets := make([]string, 0)
+	jobsByJobSet := make(map[string][]interfaces.LegacySchedulerJob)
+	for _, job := range jobs {
+		jobSet := job.GetJobSet()
+		if _, ok := jobsByJobSet[jobSet]; !ok {
+			jobSets = append(jobSets, jobSet)
+		}
+		jobsByJobSet[jobSet] = append(jobsByJobSet[jobSet], job)
+	}
+	rv := make([]interfaces.LegacySchedulerJob, 0, len(jobs))
+	for i := 0; len(rv) < len(jobs); i++ {
+		for _, jobSet := range jobSets {
+			if jobsInJobSet := jobsByJobSet[jobSet]; i < len(jobsInJobSet) {
+				rv = append(rv, jobsInJobSet[i])
+			}
+		}
+	}
+	return rv
+}
+
+// orderJobsByExpectedRuntime sorts jobs in-place by their expected runtime, shortest first.
+// Jobs without a valid expected runtime come last. The sort is stable.
+func orderJobsByExpectedRuntime(jobs []interfaces.LegacySchedulerJob) {
+	expectedRuntimeById := make(map[string]time.Duration, len(jobs))
+	for _, job := range jobs {
+		if expectedRuntime, ok := ExpectedRuntimeFromAnnotations(job.GetAnnotations()); ok {
+			expectedRuntimeById[job.GetId()] = expectedRuntime
+		}
+	}
+	slices.SortStableFunc(jobs, func(a, b interfaces.LegacySchedulerJob) bool {
+		aRuntime, aOk := expectedRuntimeById[a.GetId()]
+		bRuntime, bOk := expectedRuntimeById[b.GetId()]
+		if aOk && bOk {
+			return aRuntime < bRuntime
+		}
+		return aOk && !bOk
+	})
+}
+
+// ExpectedRuntimeFromAnnotations returns (expectedRuntime, true) if the annotations contain a valid non-negative
+// expected runtime and (0, false) otherwise.
+func ExpectedRuntimeFromAnnotations(annotations map[string]string) (time.Duration, bool) {
+	value, ok := annotations[configuration.ExpectedRuntimeAnnotation]
+	if !ok {
+		return 0, false
+	}
+	expectedRuntime, err := time.ParseDuration(value)
+	if err != nil || expectedRuntime < 0 {
+		return 0, false
+	}
+	return expectedRuntime, true
+}
@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/interfaces"
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+func TestIntraQueueOrderingJobRepository(t *testing.T) {
+	T := time.Now()
+	// Jobs in Fifo order.
+	jobs := []*api.Job{
+		{Id: "0", JobSetId: "A", Annotations: map[string]string{configuration.ExpectedRuntimeAnnotation: "1h"}},
+		{Id: "1", JobSetId: "A", Annotations: map[string]string{configuration.ExpectedRuntimeAnnotation: "5m"}},
+		{Id: "2", JobSetId: "A"},
+		{Id: "3", JobSetId: "B", Annotations: map[string]string{configuration.ExpectedRuntimeAnnotation: "30m"}},
+		{Id: "4", JobSetId: "C", Annotations: map[string]string{configuration.ExpectedRuntimeAnnotation: "not-a-duration"}},
+		{Id: "5", JobSetId: "B", Annotations: map[string]string{configuration.ExpectedRuntimeAnnotation: "5m"}},
+	}
+	legacySchedulerJobs := make([]interfaces.LegacySchedulerJob, len(jobs))
+	for i, job := range jobs {
+		job.Queue = "queue"
+		job.Priority = 1
+		job.Created = T.Add(time.Duration(i) * time.Second)
+		job.PodSpec = &v1.PodSpec{}
+		legacySchedulerJobs[i] = job
+	}
+	repo := NewInMemoryJobRepository()
+	repo.EnqueueMany(legacySchedulerJobs)
+
+	tests := map[string]struct {
+		ordering       configuration.IntraQueueOrdering
+		expectedJobIds []string
+		expectError    bool
+	}{
+		"unset": {
+			expectedJobIds: []string{"0", "1", "2", "3", "4", "5"},
+		},
+		"Fifo": {
+			ordering:       configuration.Fifo,
+			expectedJobIds: []string{"0", "1", "2", "3", "4", "5"},
+		},
+		"JobSetRoundRobin": {
+			ordering:       configuration.JobSetRoundRobin,
+			expectedJobIds: []string{"0", "3", "4", "1", "5", "2"},
+		},
+		"ShortestJobFirst": {
+			ordering:       configuration.ShortestJobFirst,
+			expectedJobIds: []string{"1", "5", "3", "0", "2", "4"},
+		},
+		"unknown ordering": {
+			ordering:    "Lifo",
+			expectError: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			jobIds, err := NewIntraQueueOrderingJobRepository(repo, tc.ordering).GetQueueJobIds("queue")
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedJobIds, jobIds)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"time"
+
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+)
+
+// Segments of the path from a job being submitted to it being leased, as measured by the scheduler.
+// The remaining segments, i.e., from being leased to the pod being created and from the pod being created to the job running,
+// are recorded by lookout from the events published by the executor.
+const (
+	SubmittedToIngestedSegment  = "submitted_to_ingested"
+	IngestedToConsideredSegment = "ingested_to_considered"
+	ConsideredToLeasedSegment   = "considered_to_leased"
+)
+
+// JobStartLatencySegment is the time a job spent in one segment of the path from submission to being leased.
+type JobStartLatencySegment struct {
+	Name     string
+	Duration time.Duration
+}
+
+// JobStartLatencySegments returns the time job spent in each segment of the path from submission to its latest run being leased.
+// Segments for which either end isn't known, e.g., since the job was ingested before ingestion times were recorded, are omitted.
+// For runs other than the first, the job was requeued rather than submitted; hence, only the last segment is reported.
+func JobStartLatencySegments(job *jobdb.Job) []JobStartLatencySegment {
+	run := job.LatestRun()
+	if run == nil {
+		return nil
+	}
+	type timestamp struct {
+		segment string
+		end     int64
+	}
+	var start int64
+	var timestamps []timestamp
+	if len(job.AllRuns()) == 1 {
+		start = job.Created()
+		timestamps = []timestamp{
+			{segment: SubmittedToIngestedSegment, end: job.IngestedTime()},
+			{segment: IngestedToConsideredSegment, end: run.FirstConsideredTime()},
+			{segment: ConsideredToLeasedSegment, end: run.Created()},
+		}
+	} else {
+		start = run.FirstConsideredTime()
+		timestamps = []timestamp{{segment: ConsideredToLeasedSegment, end: run.Created()}}
+	}
+	segments := make([]JobStartLatencySegment, 0, len(timestamps))
+	for _, t := range timestamps {
+		if start != 0 && t.end != 0 && t.end >= start {
+			segments = append(segments, JobStartLatencySegment{Name: t.segment, Duration: time.Duration(t.end - start)})
+		}
+		start = t.end
+	}
+	return segments
+}
+
+// jobIngestedTimeOfRun returns the time at which job was ingested if its latest run is its first, and nil otherwise,
+// since later runs are waiting to be scheduled since the job was requeued rather than since it was ingested.
+func jobIngestedTimeOfRun(job *jobdb.Job) *time.Time {
+	if len(job.AllRuns()) != 1 {
+		return nil
+	}
+	return timeFromUnixNano(job.IngestedTime())
+}
+
+// recordFirstConsideredTimes records, for each queued job, the time at which it was first considered for scheduling
+// since it was last queued, and stores that time on the runs created for jobs scheduled this round,
+// such that it's included in the lease events of those runs.
+func (s *Scheduler) recordFirstConsideredTimes(txn *jobdb.Txn, result *SchedulerResult) error {
+	scheduledJobIds := make(map[string]bool, len(result.ScheduledJobs))
+	for _, job := range result.ScheduledJobs {
+		scheduledJobIds[job.GetId()] = true
+	}
+
+	// Jobs no longer queued, e.g., since they were cancelled, are forgotten.
+	firstConsideredTimeByJobId := make(map[string]time.Time, len(s.firstConsideredTimeByJobId))
+	for jobId, t := range s.firstConsideredTimeByJobId {
+		if job := txn.GetById(jobId); scheduledJobIds[jobId] || (job != nil && job.Queued()) {
+			firstConsideredTimeByJobId[jobId] = t
+		}
+	}
+	for _, sctx := range result.SchedulingContexts {
+		for _, qctx := range sctx.QueueSchedulingContexts {
+			for _, jctxs := range []map[string]*schedulercontext.JobSchedulingContext{
+				qctx.SuccessfulJobSchedulingContexts,
+				qctx.UnsuccessfulJobSchedulingContexts,
+			} {
+				for jobId, jctx := range jctxs {
+					if _, ok := firstConsideredTimeByJobId[jobId]; ok {
+						continue
+					}
+					// Running jobs evicted and re-scheduled by the scheduler aren't waiting to be scheduled.
+					if job := txn.GetById(jobId); scheduledJobIds[jobId] || (job != nil && job.Queued()) {
+						firstConsideredTimeByJobId[jobId] = jctx.Created
+					}
+				}
+			}
+		}
+	}
+
+	scheduledJobs := make([]*jobdb.Job, 0, len(result.ScheduledJobs))
+	for i, job := range result.ScheduledJobs {
+		t, ok := firstConsideredTimeByJobId[job.GetId()]
+		if !ok {
+			continue
+		}
+		delete(firstConsideredTimeByJobId, job.GetId())
+		jobDbJob := job.(*jobdb.Job)
+		if run := jobDbJob.LatestRun(); run != nil {
+			jobDbJob = jobDbJob.WithUpdatedRun(run.WithFirstConsideredTime(t.UnixNano()))
+		}
+		result.ScheduledJobs[i] = jobDbJob
+		scheduledJobs = append(scheduledJobs, jobDbJob)
+	}
+	if err := txn.Upsert(scheduledJobs); err != nil {
+		return err
+	}
+	s.firstConsideredTimeByJobId = firstConsideredTimeByJobId
+	return nil
+}
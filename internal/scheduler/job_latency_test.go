@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestJobStartLatencySegments(t *testing.T) {
+	submitted := testfixtures.BaseTime
+	at := func(d time.Duration) int64 {
+		return submitted.Add(d).UnixNano()
+	}
+	job := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).WithCreated(submitted.UnixNano())
+	firstRun := jobdb.MinimalRun(uuid.New(), at(7*time.Second)).WithFirstConsideredTime(at(3 * time.Second))
+	secondRun := jobdb.MinimalRun(uuid.New(), at(time.Minute)).WithFirstConsideredTime(at(50 * time.Second))
+
+	tests := map[string]struct {
+		job      *jobdb.Job
+		expected []JobStartLatencySegment
+	}{
+		"no runs": {
+			job: job.WithIngestedTime(at(time.Second)),
+		},
+		"first run": {
+			job: job.WithIngestedTime(at(time.Second)).WithUpdatedRun(firstRun),
+			expected: []JobStartLatencySegment{
+				{Name: SubmittedToIngestedSegment, Duration: time.Second},
+				{Name: IngestedToConsideredSegment, Duration: 2 * time.Second},
+				{Name: ConsideredToLeasedSegment, Duration: 4 * time.Second},
+			},
+		},
+		"ingestion time not recorded": {
+			job: job.WithUpdatedRun(firstRun),
+			expected: []JobStartLatencySegment{
+				{Name: ConsideredToLeasedSegment, Duration: 4 * time.Second},
+			},
+		},
+		"later runs wait from being requeued": {
+			job: job.WithIngestedTime(at(time.Second)).WithUpdatedRun(firstRun).WithUpdatedRun(secondRun),
+			expected: []JobStartLatencySegment{
+				{Name: ConsideredToLeasedSegment, Duration: 10 * time.Second},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, JobStartLatencySegments(tc.job))
+		})
+	}
+}
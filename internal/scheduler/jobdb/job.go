@@ -8,6 +8,7 @@ import (
 	"golang.org/x/exp/maps"
 	v1 "k8s.io/api/core/v1"
 
+	"github.com/armadaproject/armada/internal/common/jobstate"
 	armadamaps "github.com/armadaproject/armada/internal/common/maps"
 	"github.com/armadaproject/armada/internal/common/types"
 	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
@@ -30,6 +31,9 @@ type Job struct {
 	// Job submission time in nanoseconds since the epoch.
 	// I.e., the value returned by time.UnixNano().
 	submittedTime int64
+	// Time at which the job was ingested into the scheduler database, in nanoseconds since the epoch.
+	// Zero if not known.
+	ingestedTime int64
 	// Hash of the scheduling requirements of the job.
 	schedulingKey schedulerobjects.SchedulingKey
 	// True if the job is currently queued.
@@ -110,6 +114,9 @@ func (job *Job) Equal(other *Job) bool {
 	if job.submittedTime != other.submittedTime {
 		return false
 	}
+	if job.ingestedTime != other.ingestedTime {
+		return false
+	}
 	if job.schedulingKey != other.schedulingKey {
 		// We assume jobSchedulingInfo is equal if schedulingKey is equal.
 		return false
@@ -387,11 +394,49 @@ func (job *Job) Created() int64 {
 	return job.submittedTime
 }
 
+// IngestedTime returns the time at which the job was ingested into the scheduler database, or zero if not known.
+func (job *Job) IngestedTime() int64 {
+	return job.ingestedTime
+}
+
+// WithIngestedTime returns a copy of the job with the ingested time updated.
+func (job *Job) WithIngestedTime(ingestedTime int64) *Job {
+	j := copyJob(*job)
+	j.ingestedTime = ingestedTime
+	return j
+}
+
 // InTerminalState returns true if the job  is in a terminal state
 func (job *Job) InTerminalState() bool {
 	return job.succeeded || job.cancelled || job.failed
 }
 
+// State returns the state of the job as defined by the shared job state machine.
+// The scheduler doesn't distinguish between leased and pending jobs; both are reported as leased.
+func (job *Job) State() jobstate.State {
+	switch {
+	case job.cancelled:
+		return jobstate.Cancelled
+	case job.succeeded:
+		return jobstate.Succeeded
+	case job.failed:
+		if run := job.LatestRun(); run != nil && run.Preempted() {
+			return jobstate.Preempted
+		}
+		return jobstate.Failed
+	case job.queued:
+		return jobstate.Queued
+	}
+	if run := job.LatestRun(); run != nil && !run.InTerminalState() {
+		if run.Running() {
+			return jobstate.Running
+		}
+		return jobstate.Leased
+	}
+	// The latest run of the job has finished, but the job hasn't been requeued yet.
+	return jobstate.Queued
+}
+
 // HasRuns returns true if the job has been run
 // If this is returns true then LatestRun is guaranteed to return a non-nil value.
 func (job *Job) HasRuns() bool {
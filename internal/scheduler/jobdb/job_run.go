@@ -32,6 +32,17 @@ type JobRun struct {
 	returned bool
 	// True if the job has been returned and the job was given a chance to run.
 	runAttempted bool
+	// True if the run was preempted by the scheduler.
+	preempted bool
+	// Time at which the run was reported as running, in nanoseconds since the epoch.
+	// Zero if the run has not yet been reported as running.
+	runningTime int64
+	// Time at which the run reached a terminal state, in nanoseconds since the epoch.
+	// Zero if the run has not yet terminated.
+	terminatedTime int64
+	// Time at which the scheduler first considered the job for scheduling before creating this run,
+	// in nanoseconds since the epoch. Zero if not known.
+	firstConsideredTime int64
 }
 
 func (run *JobRun) Equal(other *JobRun) bool {
@@ -181,6 +192,55 @@ func (run *JobRun) WithAttempted(attempted bool) *JobRun {
 	return run
 }
 
+// Preempted Returns true if the run was preempted by the scheduler.
+func (run *JobRun) Preempted() bool {
+	return run.preempted
+}
+
+// WithPreempted returns a copy of the job run with the preempted status updated.
+func (run *JobRun) WithPreempted(preempted bool) *JobRun {
+	run = run.DeepCopy()
+	run.preempted = preempted
+	return run
+}
+
+// RunningTime returns the time at which the run was reported as running, or zero if it hasn't been.
+func (run *JobRun) RunningTime() int64 {
+	return run.runningTime
+}
+
+// WithRunningTime returns a copy of the job run with the running time updated.
+func (run *JobRun) WithRunningTime(runningTime int64) *JobRun {
+	run = run.DeepCopy()
+	run.runningTime = runningTime
+	return run
+}
+
+// TerminatedTime returns the time at which the run reached a terminal state, or zero if it hasn't.
+func (run *JobRun) TerminatedTime() int64 {
+	return run.terminatedTime
+}
+
+// WithTerminatedTime returns a copy of the job run with the terminated time updated.
+func (run *JobRun) WithTerminatedTime(terminatedTime int64) *JobRun {
+	run = run.DeepCopy()
+	run.terminatedTime = terminatedTime
+	return run
+}
+
+// FirstConsideredTime returns the time at which the scheduler first considered the job for scheduling before creating this run,
+// or zero if not known.
+func (run *JobRun) FirstConsideredTime() int64 {
+	return run.firstConsideredTime
+}
+
+// WithFirstConsideredTime returns a copy of the job run with the first considered time updated.
+func (run *JobRun) WithFirstConsideredTime(firstConsideredTime int64) *JobRun {
+	run = run.DeepCopy()
+	run.firstConsideredTime = firstConsideredTime
+	return run
+}
+
 // Created Returns the creation time of the job run
 func (run *JobRun) Created() int64 {
 	return run.created
@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/armadaproject/armada/internal/common/jobstate"
 	"github.com/armadaproject/armada/internal/common/types"
 	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
 )
@@ -90,6 +91,21 @@ func TestJob_TestQueued(t *testing.T) {
 	assert.Equal(t, false, newJob.Queued())
 }
 
+func TestJob_State(t *testing.T) {
+	leased := baseJob.WithQueued(false).WithNewRun("test-executor", "test-nodeId", "node")
+	running := leased.WithUpdatedRun(leased.LatestRun().WithRunning(true))
+	returned := running.WithUpdatedRun(running.LatestRun().WithReturned(true))
+	preempted := running.WithFailed(true).WithUpdatedRun(running.LatestRun().WithFailed(true).WithPreempted(true))
+	assert.Equal(t, jobstate.Queued, baseJob.State())
+	assert.Equal(t, jobstate.Leased, leased.State())
+	assert.Equal(t, jobstate.Running, running.State())
+	assert.Equal(t, jobstate.Queued, returned.State())
+	assert.Equal(t, jobstate.Succeeded, running.WithSucceeded(true).State())
+	assert.Equal(t, jobstate.Failed, running.WithFailed(true).State())
+	assert.Equal(t, jobstate.Preempted, preempted.State())
+	assert.Equal(t, jobstate.Cancelled, running.WithCancelled(true).State())
+}
+
 func TestJob_QueuedVersion(t *testing.T) {
 	newJob := baseJob.WithQueuedVersion(1)
 	assert.Equal(t, int32(0), baseJob.QueuedVersion())
@@ -5,10 +5,13 @@ import (
 	"sync"
 
 	"github.com/benbjohnson/immutable"
+	"github.com/gogo/protobuf/proto"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 	"golang.org/x/exp/maps"
 
+	"github.com/armadaproject/armada/internal/common/jobstate"
 	"github.com/armadaproject/armada/internal/common/types"
 	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
 )
@@ -28,8 +31,10 @@ type JobDb struct {
 	// Priority class assigned to jobs with a priorityClassName not in jobDb.priorityClasses.
 	defaultPriorityClass   types.PriorityClass
 	schedulingKeyGenerator *schedulerobjects.SchedulingKeyGenerator
-	copyMutex              sync.Mutex
-	writerMutex            sync.Mutex
+	// Validates the state transitions of upserted jobs.
+	stateMachine *jobstate.StateMachine
+	copyMutex    sync.Mutex
+	writerMutex  sync.Mutex
 }
 
 func NewJobDb(priorityClasses map[string]types.PriorityClass, defaultPriorityClassName string) *JobDb {
@@ -58,9 +63,16 @@ func NewJobDbWithSchedulingKeyGenerator(
 		priorityClasses:        priorityClasses,
 		defaultPriorityClass:   defaultPriorityClass,
 		schedulingKeyGenerator: skg,
+		stateMachine:           jobstate.NewStateMachine("scheduler"),
 	}
 }
 
+// StateMachine returns the state machine validating the state transitions of jobs upserted into the jobDb,
+// on which hooks can be registered to be notified of transitions.
+func (jobDb *JobDb) StateMachine() *jobstate.StateMachine {
+	return jobDb.stateMachine
+}
+
 // NewJob creates a new scheduler job.
 // The new job is not automatically inserted into the jobDb; call jobDb.Upsert to upsert it.
 func (jobDb *JobDb) NewJob(
@@ -196,6 +208,11 @@ func (txn *Txn) Upsert(jobs []*Job) error {
 		for _, job := range jobs {
 			existingJob, ok := txn.jobsById.Get(job.id)
 			if ok {
+				// Invalid transitions indicate a bug or events applied out of order;
+				// they're recorded but not rejected, since the jobDb must reflect the state of the database.
+				if err := txn.jobDb.stateMachine.Transition(job.id, existingJob.State(), job.State()); err != nil {
+					log.WithError(err).Warn("upserted job with invalid state transition")
+				}
 				existingQueue, ok := txn.jobsByQueue[existingJob.queue]
 				if ok {
 					txn.jobsByQueue[existingJob.queue] = existingQueue.Delete(existingJob)
@@ -310,6 +327,27 @@ func (txn *Txn) QueuedJobsByTtl() *immutable.SortedSetIterator[*Job] {
 	return txn.queuedJobsByTtl.Iterator()
 }
 
+// WithPriorityClass returns a copy of job with its priority class replaced by the priority class with the given name,
+// e.g., to schedule a job at a lower priority than it was submitted at.
+// The priority of the job's pod requirements and its scheduling key are updated accordingly.
+// The returned job is not stored in the jobDb.
+func (txn *Txn) WithPriorityClass(job *Job, priorityClassName string) *Job {
+	priorityClass, ok := txn.jobDb.priorityClasses[priorityClassName]
+	if !ok {
+		priorityClass = txn.jobDb.defaultPriorityClass
+	}
+	schedulingInfo := proto.Clone(job.JobSchedulingInfo()).(*schedulerobjects.JobSchedulingInfo)
+	schedulingInfo.PriorityClassName = priorityClassName
+	j := job.WithJobSchedulingInfo(schedulingInfo)
+	j.priorityClass = priorityClass
+	if preq := schedulingInfo.GetPodRequirements(); preq != nil {
+		preq.Priority = priorityClass.Priority
+		preq.CachedSchedulingKey = nil
+		j.schedulingKey = txn.jobDb.schedulingKeyGenerator.KeyFromPodRequirements(preq)
+	}
+	return j
+}
+
 // GetAll returns all jobs in the database.
 // The Jobs returned by this function *must not* be subsequently modified
 func (txn *Txn) GetAll() []*Job {
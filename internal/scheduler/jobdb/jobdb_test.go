@@ -249,3 +249,37 @@ func newJob() *Job {
 		jobSchedulingInfo: schedulingInfo,
 	}
 }
+
+func TestJobDb_WithPriorityClass(t *testing.T) {
+	jobDb := NewJobDb(
+		map[string]types.PriorityClass{
+			"foo": {Priority: 2},
+			"bar": {Priority: 1, Preemptible: true},
+		},
+		"foo",
+	)
+	jobSchedulingInfo := &schedulerobjects.JobSchedulingInfo{
+		PriorityClassName: "foo",
+		ObjectRequirements: []*schedulerobjects.ObjectRequirements{
+			{
+				Requirements: &schedulerobjects.ObjectRequirements_PodRequirements{
+					PodRequirements: &schedulerobjects.PodRequirements{
+						NodeSelector: map[string]string{"foo": "bar"},
+						Priority:     2,
+					},
+				},
+			},
+		},
+	}
+	job := jobDb.NewJob("jobId", "jobSet", "queue", 1, jobSchedulingInfo, true, 0, false, false, false, 2)
+
+	demoted := jobDb.ReadTxn().WithPriorityClass(job, "bar")
+	assert.Equal(t, "bar", demoted.GetPriorityClassName())
+	assert.Equal(t, int32(1), demoted.PodRequirements().Priority)
+	assert.Equal(t, types.PriorityClass{Priority: 1, Preemptible: true}, demoted.priorityClass)
+	assert.NotEqual(t, job.schedulingKey, demoted.schedulingKey)
+
+	// The original job is unchanged.
+	assert.Equal(t, "foo", job.GetPriorityClassName())
+	assert.Equal(t, int32(2), job.PodRequirements().Priority)
+}
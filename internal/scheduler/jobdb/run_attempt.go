@@ -0,0 +1,108 @@
+package jobdb
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+)
+
+// RunTerminationReason describes why a job run stopped.
+type RunTerminationReason string
+
+const (
+	// The run has not yet terminated.
+	RunTerminationReasonNone      RunTerminationReason = ""
+	RunTerminationReasonSucceeded RunTerminationReason = "succeeded"
+	RunTerminationReasonFailed    RunTerminationReason = "failed"
+	RunTerminationReasonCancelled RunTerminationReason = "cancelled"
+	// The executor returned the lease, e.g., because the pod could not be started.
+	RunTerminationReasonReturned RunTerminationReason = "returned"
+	// The scheduler preempted the run.
+	RunTerminationReasonPreempted RunTerminationReason = "preempted"
+)
+
+// RunAttempt is an immutable summary of a single job run.
+// Run attempts are exposed to users and to retry policies to reason about, e.g.,
+// how many times a job was preempted on a particular executor.
+type RunAttempt struct {
+	RunId             uuid.UUID            `json:"runId"`
+	Executor          string               `json:"executor"`
+	NodeId            string               `json:"nodeId"`
+	NodeName          string               `json:"nodeName"`
+	Created           time.Time            `json:"created"`
+	Started           *time.Time           `json:"started,omitempty"`
+	Finished          *time.Time           `json:"finished,omitempty"`
+	RunAttempted      bool                 `json:"runAttempted"`
+	TerminationReason RunTerminationReason `json:"terminationReason,omitempty"`
+}
+
+// TerminationReason returns the reason for why the run terminated,
+// or RunTerminationReasonNone if the run is still active.
+func (run *JobRun) TerminationReason() RunTerminationReason {
+	// Preempted runs are also marked as failed; check for preemption first.
+	if run.preempted {
+		return RunTerminationReasonPreempted
+	} else if run.cancelled {
+		return RunTerminationReasonCancelled
+	} else if run.returned {
+		return RunTerminationReasonReturned
+	} else if run.failed {
+		return RunTerminationReasonFailed
+	} else if run.succeeded {
+		return RunTerminationReasonSucceeded
+	}
+	return RunTerminationReasonNone
+}
+
+// Attempt returns a RunAttempt summarising this run.
+func (run *JobRun) Attempt() RunAttempt {
+	return RunAttempt{
+		RunId:             run.id,
+		Executor:          run.executor,
+		NodeId:            run.nodeId,
+		NodeName:          run.nodeName,
+		Created:           time.Unix(0, run.created).UTC(),
+		Started:           timeFromUnixNanoOrNil(run.runningTime),
+		Finished:          timeFromUnixNanoOrNil(run.terminatedTime),
+		RunAttempted:      run.runAttempted,
+		TerminationReason: run.TerminationReason(),
+	}
+}
+
+// RunAttempts returns a summary of all runs associated with this job, ordered by creation time.
+func (job *Job) RunAttempts() []RunAttempt {
+	runs := job.AllRuns()
+	slices.SortFunc(runs, func(a, b *JobRun) bool {
+		return a.created < b.created
+	})
+	rv := make([]RunAttempt, len(runs))
+	for i, run := range runs {
+		rv[i] = run.Attempt()
+	}
+	return rv
+}
+
+// NumRunAttemptsMatching returns the number of runs of this job for which filter returns true.
+// For example, to count the number of times a job was preempted on a given executor:
+//
+//	job.NumRunAttemptsMatching(func(attempt RunAttempt) bool {
+//		return attempt.Executor == executor && attempt.TerminationReason == RunTerminationReasonPreempted
+//	})
+func (job *Job) NumRunAttemptsMatching(filter func(RunAttempt) bool) int {
+	n := 0
+	for _, run := range job.runsById {
+		if filter(run.Attempt()) {
+			n++
+		}
+	}
+	return n
+}
+
+func timeFromUnixNanoOrNil(t int64) *time.Time {
+	if t == 0 {
+		return nil
+	}
+	rv := time.Unix(0, t).UTC()
+	return &rv
+}
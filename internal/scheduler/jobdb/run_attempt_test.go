@@ -0,0 +1,93 @@
+package jobdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRun_TerminationReason(t *testing.T) {
+	tests := map[string]struct {
+		run      *JobRun
+		expected RunTerminationReason
+	}{
+		"active": {
+			run:      baseJobRun.WithRunning(true),
+			expected: RunTerminationReasonNone,
+		},
+		"succeeded": {
+			run:      baseJobRun.WithSucceeded(true),
+			expected: RunTerminationReasonSucceeded,
+		},
+		"failed": {
+			run:      baseJobRun.WithFailed(true),
+			expected: RunTerminationReasonFailed,
+		},
+		"cancelled": {
+			run:      baseJobRun.WithCancelled(true),
+			expected: RunTerminationReasonCancelled,
+		},
+		"returned": {
+			run:      baseJobRun.WithReturned(true).WithAttempted(true),
+			expected: RunTerminationReasonReturned,
+		},
+		"preempted takes precedence over failed": {
+			run:      baseJobRun.WithFailed(true).WithPreempted(true),
+			expected: RunTerminationReasonPreempted,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.run.TerminationReason())
+		})
+	}
+}
+
+func TestJobRun_Attempt(t *testing.T) {
+	started := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	finished := started.Add(time.Hour)
+	run := baseJobRun.
+		WithRunning(true).
+		WithRunningTime(started.UnixNano()).
+		WithFailed(true).
+		WithPreempted(true).
+		WithTerminatedTime(finished.UnixNano())
+	assert.Equal(
+		t,
+		RunAttempt{
+			RunId:             baseJobRun.Id(),
+			Executor:          "test-executor",
+			NodeId:            "test-nodeId",
+			NodeName:          "test-nodeName",
+			Created:           time.Unix(0, 5).UTC(),
+			Started:           &started,
+			Finished:          &finished,
+			TerminationReason: RunTerminationReasonPreempted,
+		},
+		run.Attempt(),
+	)
+	assert.Nil(t, baseJobRun.Attempt().Started)
+	assert.Nil(t, baseJobRun.Attempt().Finished)
+}
+
+func TestJob_RunAttempts(t *testing.T) {
+	firstRun := CreateRun(uuid.New(), baseJob.Id(), 1, "executor-a", "node-a", "node-a", false, false, true, false, false, false).WithPreempted(true)
+	secondRun := CreateRun(uuid.New(), baseJob.Id(), 2, "executor-a", "node-b", "node-b", false, false, true, false, false, false).WithPreempted(true)
+	thirdRun := CreateRun(uuid.New(), baseJob.Id(), 3, "executor-b", "node-c", "node-c", true, false, false, false, false, false)
+	job := baseJob.WithUpdatedRun(thirdRun).WithUpdatedRun(firstRun).WithUpdatedRun(secondRun)
+
+	attempts := job.RunAttempts()
+	if assert.Len(t, attempts, 3) {
+		assert.Equal(t, firstRun.Id(), attempts[0].RunId)
+		assert.Equal(t, secondRun.Id(), attempts[1].RunId)
+		assert.Equal(t, thirdRun.Id(), attempts[2].RunId)
+	}
+
+	numPreemptedOnExecutorA := job.NumRunAttemptsMatching(func(attempt RunAttempt) bool {
+		return attempt.Executor == "executor-a" && attempt.TerminationReason == RunTerminationReasonPreempted
+	})
+	assert.Equal(t, 2, numPreemptedOnExecutorA)
+	assert.Empty(t, baseJob.RunAttempts())
+}
@@ -16,6 +16,48 @@ func AddNodeAntiAffinity(affinity *v1.Affinity, labelName string, labelValue str
 	return nil
 }
 
+// SetPreferredNodeAffinity sets a preferred node affinity term of the given weight matching nodes with any of labelValues for labelName,
+// replacing any such term for labelName previously set. If labelValues is empty, any such term is removed.
+func SetPreferredNodeAffinity(affinity *v1.Affinity, labelName string, labelValues []string, weight int32) error {
+	if affinity == nil {
+		return errors.Errorf("failed to set preferred node affinity, as provided affinity is nil")
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &v1.NodeAffinity{}
+	}
+	nodeAffinity := affinity.NodeAffinity
+	terms := make([]v1.PreferredSchedulingTerm, 0, len(nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution)+1)
+	for _, term := range nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		if !isPreferredNodeAffinityTermForLabel(term, labelName) {
+			terms = append(terms, term)
+		}
+	}
+	if len(labelValues) > 0 {
+		terms = append(terms, v1.PreferredSchedulingTerm{
+			Weight: weight,
+			Preference: v1.NodeSelectorTerm{
+				MatchExpressions: []v1.NodeSelectorRequirement{
+					{
+						Key:      labelName,
+						Operator: v1.NodeSelectorOpIn,
+						Values:   labelValues,
+					},
+				},
+			},
+		})
+	}
+	if len(terms) == 0 {
+		terms = nil
+	}
+	nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = terms
+	return nil
+}
+
+func isPreferredNodeAffinityTermForLabel(term v1.PreferredSchedulingTerm, labelName string) bool {
+	mexps := term.Preference.MatchExpressions
+	return len(mexps) == 1 && len(term.Preference.MatchFields) == 0 && mexps[0].Key == labelName && mexps[0].Operator == v1.NodeSelectorOpIn
+}
+
 func ensureAffinityHasNodeSelectorTerms(affinity *v1.Affinity) {
 	if affinity.NodeAffinity == nil {
 		affinity.NodeAffinity = &v1.NodeAffinity{}
@@ -57,6 +57,36 @@ func TestAddNodeAntiAffinity_WhenDifferentLabelAlreadyThere_IncludesBothLabels(t
 	assert.Equal(t, expected, affinity)
 }
 
+func TestSetPreferredNodeAffinity(t *testing.T) {
+	userTerm := v1.PreferredSchedulingTerm{
+		Weight: 10,
+		Preference: v1.NodeSelectorTerm{
+			MatchExpressions: []v1.NodeSelectorRequirement{{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{"a"}}},
+		},
+	}
+	preferredTerm := func(values ...string) v1.PreferredSchedulingTerm {
+		return v1.PreferredSchedulingTerm{
+			Weight: 50,
+			Preference: v1.NodeSelectorTerm{
+				MatchExpressions: []v1.NodeSelectorRequirement{{Key: "a", Operator: v1.NodeSelectorOpIn, Values: values}},
+			},
+		}
+	}
+	affinity := &v1.Affinity{NodeAffinity: &v1.NodeAffinity{PreferredDuringSchedulingIgnoredDuringExecution: []v1.PreferredSchedulingTerm{userTerm}}}
+
+	assert.NoError(t, SetPreferredNodeAffinity(affinity, "a", []string{"b"}, 50))
+	assert.Equal(t, []v1.PreferredSchedulingTerm{userTerm, preferredTerm("b")}, affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+
+	// Setting the preference again replaces the existing term.
+	assert.NoError(t, SetPreferredNodeAffinity(affinity, "a", []string{"b", "c"}, 50))
+	assert.Equal(t, []v1.PreferredSchedulingTerm{userTerm, preferredTerm("b", "c")}, affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+
+	assert.NoError(t, SetPreferredNodeAffinity(affinity, "a", nil, 50))
+	assert.Equal(t, []v1.PreferredSchedulingTerm{userTerm}, affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+
+	assert.Error(t, SetPreferredNodeAffinity(nil, "a", []string{"b"}, 50))
+}
+
 func vanillaAvoidLabelAffinity(key string, val string) *v1.Affinity {
 	return vanillaAvoidLabelAffinites([]*api.StringKeyValuePair{{Key: key, Value: val}})
 }
@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+// LeaseAcknowledger returns runs to their queue if the executor they're leased to doesn't acknowledge them in time.
+// Executors acknowledge runs by including them in their lease requests, which list all runs the executor holds.
+// A run is returned if the executor it's leased to has reported in after the acknowledgement timeout has passed
+// without including the run, e.g., because the executor never received the lease or lost the run after failing to create its pod.
+// Returned runs remain part of the run attempt history of the job, but don't count towards its maximum number of attempts.
+// Runs on executors that have stopped reporting are instead expired once the executor timeout has passed.
+type LeaseAcknowledger struct {
+	timeout time.Duration
+	clock   clock.Clock
+	// Runs acknowledged by their executor as of the most recent call to ReturnUnacknowledgedLeases.
+	acknowledgedRunIds map[uuid.UUID]bool
+	// For each unacknowledged run, the time at which it was first seen.
+	// Runs are only tracked in memory; unless handed over via MarshalState and UnmarshalState,
+	// this is also the time a new leader first saw each run.
+	unacknowledgedSince map[uuid.UUID]time.Time
+}
+
+func NewLeaseAcknowledger(timeout time.Duration) *LeaseAcknowledger {
+	return &LeaseAcknowledger{
+		timeout:             timeout,
+		clock:               clock.RealClock{},
+		acknowledgedRunIds:  make(map[uuid.UUID]bool),
+		unacknowledgedSince: make(map[uuid.UUID]time.Time),
+	}
+}
+
+// ReturnUnacknowledgedLeases returns the events necessary to return all runs not acknowledged in time
+// and updates the jobs affected in txn accordingly, given the executors as last reported. Must only be called by the leader.
+func (a *LeaseAcknowledger) ReturnUnacknowledgedLeases(
+	ctx *armadacontext.Context,
+	txn *jobdb.Txn,
+	executors []*schedulerobjects.Executor,
+) ([]*armadaevents.EventSequence, error) {
+	lastUpdateTimeByExecutor := make(map[string]time.Time, len(executors))
+	reportedRunIds := make(map[string]bool)
+	for _, executor := range executors {
+		lastUpdateTimeByExecutor[executor.Id] = executor.LastUpdateTime
+		for _, runId := range executor.UnassignedJobRuns {
+			reportedRunIds[runId] = true
+		}
+		for _, node := range executor.Nodes {
+			for runId := range node.StateByJobRunId {
+				reportedRunIds[runId] = true
+			}
+		}
+	}
+
+	now := a.clock.Now()
+	acknowledgedRunIds := make(map[uuid.UUID]bool, len(a.acknowledgedRunIds))
+	unacknowledgedSince := make(map[uuid.UUID]time.Time, len(a.unacknowledgedSince))
+	var events []*armadaevents.EventSequence
+	var jobsToUpdate []*jobdb.Job
+	for _, job := range txn.GetAll() {
+		if job.InTerminalState() || job.Queued() {
+			continue
+		}
+		run := job.LatestRun()
+		if run == nil || run.InTerminalState() {
+			continue
+		}
+		if run.Running() || a.acknowledgedRunIds[run.Id()] || reportedRunIds[run.Id().String()] {
+			acknowledgedRunIds[run.Id()] = true
+			continue
+		}
+		since, ok := a.unacknowledgedSince[run.Id()]
+		if !ok {
+			since = now
+		}
+		deadline := since.Add(a.timeout)
+		if now.Before(deadline) || !lastUpdateTimeByExecutor[run.Executor()].After(deadline) {
+			unacknowledgedSince[run.Id()] = since
+			continue
+		}
+		ctx.Warnf("returning job %s to its queue as executor %s hasn't acknowledged run %s within %s", job.Id(), run.Executor(), run.Id(), a.timeout)
+		es, err := returnLeaseEventSequence(job, fmt.Sprintf("lease not acknowledged by executor %s within %s", run.Executor(), a.timeout), now)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, es)
+		// The job is requeued once the returned run has been written to the database.
+		jobsToUpdate = append(jobsToUpdate, job.WithUpdatedRun(run.WithFailed(true).WithReturned(true)))
+	}
+	if err := txn.Upsert(jobsToUpdate); err != nil {
+		return nil, err
+	}
+	a.acknowledgedRunIds = acknowledgedRunIds
+	a.unacknowledgedSince = unacknowledgedSince
+	return events, nil
+}
+
+// leaseAcknowledgerState is the state of a LeaseAcknowledger handed over between leaders.
+type leaseAcknowledgerState struct {
+	AcknowledgedRunIds  map[uuid.UUID]bool      `json:"acknowledgedRunIds"`
+	UnacknowledgedSince map[uuid.UUID]time.Time `json:"unacknowledgedSince"`
+}
+
+// MarshalState serialises the acknowledgement state of the runs tracked, such that it's handed over to the next leader,
+// which then returns runs not acknowledged in time without restarting their acknowledgement timeout.
+func (a *LeaseAcknowledger) MarshalState() ([]byte, error) {
+	bytes, err := json.Marshal(leaseAcknowledgerState{
+		AcknowledgedRunIds:  a.acknowledgedRunIds,
+		UnacknowledgedSince: a.unacknowledgedSince,
+	})
+	return bytes, errors.WithStack(err)
+}
+
+// UnmarshalState replaces the acknowledgement state of the runs tracked with that handed over by the previous leader.
+func (a *LeaseAcknowledger) UnmarshalState(state []byte) error {
+	var ackState leaseAcknowledgerState
+	if err := json.Unmarshal(state, &ackState); err != nil {
+		return errors.WithStack(err)
+	}
+	if ackState.AcknowledgedRunIds == nil {
+		ackState.AcknowledgedRunIds = make(map[uuid.UUID]bool)
+	}
+	if ackState.UnacknowledgedSince == nil {
+		ackState.UnacknowledgedSince = make(map[uuid.UUID]time.Time)
+	}
+	a.acknowledgedRunIds = ackState.AcknowledgedRunIds
+	a.unacknowledgedSince = ackState.UnacknowledgedSince
+	return nil
+}
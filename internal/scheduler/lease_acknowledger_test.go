@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+func TestLeaseAcknowledger_ReturnUnacknowledgedLeases(t *testing.T) {
+	const timeout = time.Minute
+	start := testfixtures.BaseTime
+	afterTimeout := start.Add(2 * timeout)
+
+	leasedJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).
+		WithQueued(false).WithNewRun("executor", "node", "node")
+	runningJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).
+		WithQueued(false).WithNewRun("executor", "node", "node")
+	runningJob = runningJob.WithUpdatedRun(runningJob.LatestRun().WithRunning(true))
+	queuedJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0)
+	leasedRunId := leasedJob.LatestRun().Id().String()
+
+	tests := map[string]struct {
+		// Run ids reported by the executor in the first and second call, respectively.
+		initialUnassignedRunIds []string
+		unassignedRunIds        []string
+		nodeRunIds              []string
+		// Time at which the executor last reported in, as of the second call.
+		executorLastUpdateTime time.Time
+		expectReturned         bool
+	}{
+		"unacknowledged run is returned": {
+			executorLastUpdateTime: afterTimeout,
+			expectReturned:         true,
+		},
+		"run acknowledged as unassigned is left as is": {
+			unassignedRunIds:       []string{leasedRunId},
+			executorLastUpdateTime: afterTimeout,
+		},
+		"run acknowledged on a node is left as is": {
+			nodeRunIds:             []string{leasedRunId},
+			executorLastUpdateTime: afterTimeout,
+		},
+		"run acknowledged previously is left as is": {
+			initialUnassignedRunIds: []string{leasedRunId},
+			executorLastUpdateTime:  afterTimeout,
+		},
+		"run is left as is if the executor hasn't reported in since the timeout": {
+			executorLastUpdateTime: start.Add(timeout / 2),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := armadacontext.Background()
+			executor := &schedulerobjects.Executor{
+				Id:                "executor",
+				LastUpdateTime:    start,
+				UnassignedJobRuns: tc.initialUnassignedRunIds,
+			}
+			executors := []*schedulerobjects.Executor{executor}
+			testClock := clock.NewFakeClock(start)
+			acknowledger := NewLeaseAcknowledger(timeout)
+			acknowledger.clock = testClock
+
+			jobDb := testfixtures.NewJobDb()
+			txn := jobDb.WriteTxn()
+			require.NoError(t, txn.Upsert([]*jobdb.Job{leasedJob, runningJob, queuedJob}))
+
+			// Runs are never returned the first time they're seen.
+			events, err := acknowledger.ReturnUnacknowledgedLeases(ctx, txn, executors)
+			require.NoError(t, err)
+			assert.Empty(t, events)
+
+			testClock.SetTime(afterTimeout)
+			executor.LastUpdateTime = tc.executorLastUpdateTime
+			executor.UnassignedJobRuns = tc.unassignedRunIds
+			stateByJobRunId := make(map[string]schedulerobjects.JobRunState)
+			for _, runId := range tc.nodeRunIds {
+				stateByJobRunId[runId] = schedulerobjects.JobRunState_PENDING
+			}
+			executor.Nodes = []*schedulerobjects.Node{{Id: "node", StateByJobRunId: stateByJobRunId}}
+			events, err = acknowledger.ReturnUnacknowledgedLeases(ctx, txn, executors)
+			require.NoError(t, err)
+
+			// Job ids are upper-case ulids, whereas UlidStringFromProtoUuid returns lower-case ones.
+			var returnedJobs []string
+			for _, es := range events {
+				for _, event := range es.Events {
+					if runErrors := event.GetJobRunErrors(); runErrors != nil && runErrors.Errors[0].GetPodLeaseReturned() != nil {
+						jobId, err := armadaevents.UlidStringFromProtoUuid(runErrors.JobId)
+						require.NoError(t, err)
+						returnedJobs = append(returnedJobs, strings.ToUpper(jobId))
+					}
+				}
+			}
+			if tc.expectReturned {
+				assert.Equal(t, []string{leasedJob.Id()}, returnedJobs)
+				run := txn.GetById(leasedJob.Id()).LatestRun()
+				assert.True(t, run.Failed())
+				assert.True(t, run.Returned())
+				assert.False(t, run.RunAttempted())
+			} else {
+				assert.Empty(t, returnedJobs)
+				assert.False(t, txn.GetById(leasedJob.Id()).LatestRun().InTerminalState())
+			}
+			assert.False(t, txn.GetById(runningJob.Id()).LatestRun().InTerminalState())
+		})
+	}
+}
+
+func TestLeaseAcknowledger_Handover(t *testing.T) {
+	const timeout = time.Minute
+	ctx := armadacontext.Background()
+	start := testfixtures.BaseTime
+	leasedJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).
+		WithQueued(false).WithNewRun("executor", "node", "node")
+	executor := &schedulerobjects.Executor{Id: "executor", LastUpdateTime: start}
+	executors := []*schedulerobjects.Executor{executor}
+	testClock := clock.NewFakeClock(start)
+	jobDb := testfixtures.NewJobDb()
+	txn := jobDb.WriteTxn()
+	require.NoError(t, txn.Upsert([]*jobdb.Job{leasedJob}))
+
+	acknowledger := NewLeaseAcknowledger(timeout)
+	acknowledger.clock = testClock
+	events, err := acknowledger.ReturnUnacknowledgedLeases(ctx, txn, executors)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	state, err := acknowledger.MarshalState()
+	require.NoError(t, err)
+
+	// The next leader returns the run once the timeout has passed since the previous leader first saw it,
+	// rather than restarting the timeout.
+	nextAcknowledger := NewLeaseAcknowledger(timeout)
+	nextAcknowledger.clock = testClock
+	require.NoError(t, nextAcknowledger.UnmarshalState(state))
+	testClock.SetTime(start.Add(2 * timeout))
+	executor.LastUpdateTime = start.Add(2 * timeout)
+	events, err = nextAcknowledger.ReturnUnacknowledgedLeases(ctx, txn, executors)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.True(t, txn.GetById(leasedJob.Id()).LatestRun().Returned())
+}
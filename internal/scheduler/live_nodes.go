@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/scheduler/database"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/nodedb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// executorNodeDb is a nodeDb containing the nodes of a single executor.
+type executorNodeDb struct {
+	executor *schedulerobjects.Executor
+	nodeDb   *nodedb.NodeDb
+}
+
+// liveExecutorNodeDbs returns a nodeDb for each executor that has heartbeated within the executor timeout,
+// with the resources of jobs currently leased to the executor marked as allocated.
+// If pool is non-empty, only executors in that pool are included.
+func liveExecutorNodeDbs(
+	ctx *armadacontext.Context,
+	schedulingConfig configuration.SchedulingConfig,
+	executorRepository database.ExecutorRepository,
+	jobDb *jobdb.JobDb,
+	now time.Time,
+	pool string,
+) ([]executorNodeDb, error) {
+	executors, err := executorRepository.GetExecutors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobsByExecutorId := make(map[string][]*jobdb.Job)
+	for _, job := range jobDb.ReadTxn().GetAll() {
+		if job.Queued() || job.InTerminalState() {
+			continue
+		}
+		run := job.LatestRun()
+		if run == nil || run.Executor() == "" {
+			continue
+		}
+		jobsByExecutorId[run.Executor()] = append(jobsByExecutorId[run.Executor()], job)
+	}
+	cutoff := now.Add(-schedulingConfig.ExecutorTimeout)
+	rv := make([]executorNodeDb, 0, len(executors))
+	for _, executor := range executors {
+		if !executor.LastUpdateTime.After(cutoff) || (pool != "" && executor.Pool != pool) {
+			continue
+		}
+		nodeDb, err := nodedb.NewNodeDb(
+			schedulingConfig.Preemption.PriorityClasses,
+			schedulingConfig.MaxExtraNodesToConsider,
+			schedulingConfig.IndexedResources,
+			schedulingConfig.IndexedTaints,
+			schedulingConfig.IndexedNodeLabels,
+		)
+		if err != nil {
+			return nil, err
+		}
+		nodeDb.SetNodeReservedResources(schedulingConfig.NodeReservedResources, schedulingConfig.NodeReservedResourceFractions)
+		nodeDb.SetExternalWorkloadCoexistence(schedulingConfig.EnableExternalWorkloadCoexistence)
+		if err := addExecutorToNodeDb(nodeDb, jobsByExecutorId[executor.Id], executor.Nodes); err != nil {
+			return nil, err
+		}
+		rv = append(rv, executorNodeDb{executor: executor, nodeDb: nodeDb})
+	}
+	return rv, nil
+}
@@ -225,6 +225,7 @@ func (c *MetricsCollector) updateClusterMetrics(ctx *armadacontext.Context) ([]p
 	usedResourceByQueue := map[queueMetricKey]schedulerobjects.ResourceList{}
 	availableResourceByCluster := map[clusterMetricKey]schedulerobjects.ResourceList{}
 	totalResourceByCluster := map[clusterMetricKey]schedulerobjects.ResourceList{}
+	nonArmadaAllocatedResourceByCluster := map[clusterMetricKey]schedulerobjects.ResourceList{}
 	schedulableNodeCountByCluster := map[clusterMetricKey]int{}
 	totalNodeCountByCluster := map[clusterMetricKey]int{}
 
@@ -241,6 +242,7 @@ func (c *MetricsCollector) updateClusterMetrics(ctx *armadacontext.Context) ([]p
 				schedulableNodeCountByCluster[clusterKey]++
 			}
 			addToResourceListMap(totalResourceByCluster, clusterKey, node.TotalResources)
+			addToResourceListMap(nonArmadaAllocatedResourceByCluster, clusterKey, node.NonArmadaAllocatedResource())
 			totalNodeCountByCluster[clusterKey]++
 
 			for queueName, resourceUsage := range node.ResourceUsageByQueue {
@@ -306,6 +308,11 @@ func (c *MetricsCollector) updateClusterMetrics(ctx *armadacontext.Context) ([]p
 			clusterMetrics = append(clusterMetrics, commonmetrics.NewClusterTotalCapacity(resource.QuantityAsFloat64(resourceValue), k.cluster, k.pool, resourceKey, k.nodeType))
 		}
 	}
+	for k, r := range nonArmadaAllocatedResourceByCluster {
+		for resourceKey, resourceValue := range r.Resources {
+			clusterMetrics = append(clusterMetrics, commonmetrics.NewClusterNonArmadaAllocated(resource.QuantityAsFloat64(resourceValue), k.cluster, k.pool, resourceKey, k.nodeType))
+		}
+	}
 	for k, v := range schedulableNodeCountByCluster {
 		clusterMetrics = append(clusterMetrics, commonmetrics.NewClusterAvailableCapacity(float64(v), k.cluster, k.pool, "nodes", k.nodeType))
 	}
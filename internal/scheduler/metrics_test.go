@@ -152,6 +152,18 @@ func TestMetricsCollector_TestCollect_ClusterMetrics(t *testing.T) {
 	}
 	executorWithJobs := createExecutor("cluster-1", nodeWithJobs)
 
+	nodeWithNonArmadaPods := createNode("type-1")
+	nodeWithNonArmadaPods.NonArmadaAllocatedResources = map[int32]schedulerobjects.ResourceList{
+		0: {Resources: map[string]resource.Quantity{"cpu": resource.MustParse("1")}},
+		1000: {
+			Resources: map[string]resource.Quantity{
+				"cpu":    resource.MustParse("1"),
+				"memory": resource.MustParse("1Gi"),
+			},
+		},
+	}
+	executorWithNonArmadaPods := createExecutor("cluster-1", nodeWithNonArmadaPods)
+
 	tests := map[string]struct {
 		jobDbJobs []*jobdb.Job
 		executors []*schedulerobjects.Executor
@@ -217,6 +229,20 @@ func TestMetricsCollector_TestCollect_ClusterMetrics(t *testing.T) {
 				commonmetrics.NewClusterTotalCapacity(1, "cluster-1", testfixtures.TestPool, "nodes", "type-1"),
 			},
 		},
+		"cluster with non-Armada pods": {
+			jobDbJobs: []*jobdb.Job{},
+			executors: []*schedulerobjects.Executor{executorWithNonArmadaPods},
+			expected: []prometheus.Metric{
+				commonmetrics.NewClusterAvailableCapacity(30, "cluster-1", testfixtures.TestPool, "cpu", "type-1"),
+				commonmetrics.NewClusterAvailableCapacity(255*1024*1024*1024, "cluster-1", testfixtures.TestPool, "memory", "type-1"),
+				commonmetrics.NewClusterAvailableCapacity(1, "cluster-1", testfixtures.TestPool, "nodes", "type-1"),
+				commonmetrics.NewClusterTotalCapacity(32, "cluster-1", testfixtures.TestPool, "cpu", "type-1"),
+				commonmetrics.NewClusterTotalCapacity(256*1024*1024*1024, "cluster-1", testfixtures.TestPool, "memory", "type-1"),
+				commonmetrics.NewClusterTotalCapacity(1, "cluster-1", testfixtures.TestPool, "nodes", "type-1"),
+				commonmetrics.NewClusterNonArmadaAllocated(2, "cluster-1", testfixtures.TestPool, "cpu", "type-1"),
+				commonmetrics.NewClusterNonArmadaAllocated(1024*1024*1024, "cluster-1", testfixtures.TestPool, "memory", "type-1"),
+			},
+		},
 		"jobs missing from jobDb": {
 			jobDbJobs: []*jobdb.Job{},
 			executors: []*schedulerobjects.Executor{executorWithJobs},
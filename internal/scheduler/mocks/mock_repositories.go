@@ -219,3 +219,18 @@ func (mr *MockJobRepositoryMockRecorder) FindInactiveRuns(arg0, arg1 interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInactiveRuns", reflect.TypeOf((*MockJobRepository)(nil).FindInactiveRuns), arg0, arg1)
 }
+
+// FindLatestRunNodes mocks base method.
+func (m *MockJobRepository) FindLatestRunNodes(arg0 *armadacontext.Context, arg1 []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLatestRunNodes", arg0, arg1)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLatestRunNodes indicates an expected call of FindLatestRunNodes.
+func (mr *MockJobRepositoryMockRecorder) FindLatestRunNodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLatestRunNodes", reflect.TypeOf((*MockJobRepository)(nil).FindLatestRunNodes), arg0, arg1)
+}
@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/scheduler/database"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/nodedb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// NodeLabelsHttpHandler serves, for each indexed node label, the values that label currently takes across nodes,
+// together with the number of nodes and the total and free resources of nodes with each value.
+// Useful for choosing node selector values and node uniformity labels.
+// Results may be restricted to a single label and pool by the label and pool query parameters,
+// e.g., /nodeLabels?label=topology.kubernetes.io/zone&pool=cpu.
+type NodeLabelsHttpHandler struct {
+	schedulingConfig   configuration.SchedulingConfig
+	executorRepository database.ExecutorRepository
+	jobDb              *jobdb.JobDb
+	clock              clock.Clock
+}
+
+// NodeLabels is the response returned by NodeLabelsHttpHandler.
+type NodeLabels struct {
+	Labels []*NodeLabel `json:"labels"`
+}
+
+type NodeLabel struct {
+	Label string `json:"label"`
+	// Values of this label, ordered by value.
+	Values []*NodeLabelValue `json:"values"`
+}
+
+type NodeLabelValue struct {
+	Value    string `json:"value"`
+	NumNodes int    `json:"numNodes"`
+	// Number of nodes with this value marked as unschedulable, e.g., because they're cordoned.
+	NumUnschedulableNodes int                           `json:"numUnschedulableNodes"`
+	TotalResources        schedulerobjects.ResourceList `json:"totalResources"`
+	// Resources not allocated to any job on schedulable nodes, i.e., resources available without preemption.
+	FreeResources schedulerobjects.ResourceList `json:"freeResources"`
+}
+
+func NewNodeLabelsHttpHandler(
+	schedulingConfig configuration.SchedulingConfig,
+	executorRepository database.ExecutorRepository,
+	jobDb *jobdb.JobDb,
+) *NodeLabelsHttpHandler {
+	return &NodeLabelsHttpHandler{
+		schedulingConfig:   schedulingConfig,
+		executorRepository: executorRepository,
+		jobDb:              jobDb,
+		clock:              clock.RealClock{},
+	}
+}
+
+func (h *NodeLabelsHttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	labels := h.schedulingConfig.IndexedNodeLabels
+	if label := r.URL.Query().Get("label"); label != "" {
+		if !slices.Contains(labels, label) {
+			http.Error(w, "label "+label+" is not indexed; must be one of the indexed node labels", http.StatusBadRequest)
+			return
+		}
+		labels = []string{label}
+	}
+	ctx := armadacontext.New(r.Context(), log.NewEntry(log.StandardLogger()))
+	nodeDbs, err := liveExecutorNodeDbs(ctx, h.schedulingConfig, h.executorRepository, h.jobDb, h.clock.Now(), r.URL.Query().Get("pool"))
+	if err != nil {
+		log.WithError(err).Error("failed to construct node dbs")
+		http.Error(w, "failed to construct node dbs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rv, err := nodeLabels(labels, nodeDbs)
+	if err != nil {
+		log.WithError(err).Error("failed to compute node label values")
+		http.Error(w, "failed to compute node label values: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rv); err != nil {
+		log.WithError(err).Error("failed to write node labels response")
+	}
+}
+
+func nodeLabels(labels []string, nodeDbs []executorNodeDb) (*NodeLabels, error) {
+	valuesByLabel := make(map[string]map[string]*NodeLabelValue, len(labels))
+	for _, label := range labels {
+		valuesByLabel[label] = make(map[string]*NodeLabelValue)
+	}
+	unschedulableTaint := nodedb.UnschedulableTaint()
+	for _, e := range nodeDbs {
+		it, err := nodedb.NewNodesIterator(e.nodeDb.Txn(false))
+		if err != nil {
+			return nil, err
+		}
+		for node := it.NextNode(); node != nil; node = it.NextNode() {
+			isUnschedulable := false
+			for _, taint := range node.Taints {
+				if taint.MatchTaint(&unschedulableTaint) {
+					isUnschedulable = true
+					break
+				}
+			}
+			for _, label := range labels {
+				value, ok := node.Labels[label]
+				if !ok {
+					continue
+				}
+				v, ok := valuesByLabel[label][value]
+				if !ok {
+					v = &NodeLabelValue{Value: value}
+					valuesByLabel[label][value] = v
+				}
+				v.NumNodes++
+				v.TotalResources.Add(node.TotalResources)
+				if isUnschedulable {
+					v.NumUnschedulableNodes++
+				} else {
+					v.FreeResources.Add(node.AllocatableByPriority[nodedb.MinPriority])
+				}
+			}
+		}
+	}
+	rv := &NodeLabels{Labels: make([]*NodeLabel, len(labels))}
+	for i, label := range labels {
+		values := maps.Values(valuesByLabel[label])
+		slices.SortFunc(values, func(a, b *NodeLabelValue) bool {
+			return a.Value < b.Value
+		})
+		rv.Labels[i] = &NodeLabel{Label: label, Values: values}
+	}
+	return rv, nil
+}
@@ -0,0 +1,116 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	schedulermocks "github.com/armadaproject/armada/internal/scheduler/mocks"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestNodeLabelsHttpHandler(t *testing.T) {
+	baseTime := time.Now()
+	largeJobsOnlyNodes := append(
+		testfixtures.NTainted32CpuNodes(1, testfixtures.TestPriorities),
+		testfixtures.WithUsedResourcesNodes(
+			0,
+			schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("16")}},
+			testfixtures.NTainted32CpuNodes(1, testfixtures.TestPriorities),
+		)...,
+	)
+	gpuNode := testfixtures.Test8GpuNode(testfixtures.TestPriorities)
+	gpuNode.Unschedulable = true
+	executors := []*schedulerobjects.Executor{
+		{
+			Id:             "executor-1",
+			Pool:           "cpu",
+			Nodes:          append(largeJobsOnlyNodes, testfixtures.Test32CpuNode(testfixtures.TestPriorities)),
+			LastUpdateTime: baseTime,
+		},
+		{
+			Id:             "executor-2",
+			Pool:           "gpu",
+			Nodes:          []*schedulerobjects.Node{gpuNode},
+			LastUpdateTime: baseTime,
+		},
+	}
+
+	tests := map[string]struct {
+		query          string
+		expectedStatus int
+		expected       map[string][]*NodeLabelValue
+	}{
+		"all labels": {
+			expectedStatus: http.StatusOK,
+			expected: map[string][]*NodeLabelValue{
+				"largeJobsOnly": {
+					{
+						Value:          "true",
+						NumNodes:       2,
+						TotalResources: schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("64"), "memory": resource.MustParse("512Gi")}},
+						FreeResources:  schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("48"), "memory": resource.MustParse("512Gi")}},
+					},
+				},
+				"gpu": {
+					{
+						Value:                 "true",
+						NumNodes:              1,
+						NumUnschedulableNodes: 1,
+						TotalResources:        schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("64"), "memory": resource.MustParse("1024Gi"), "gpu": resource.MustParse("8")}},
+					},
+				},
+			},
+		},
+		"single label in pool": {
+			query:          "?label=gpu&pool=cpu",
+			expectedStatus: http.StatusOK,
+			expected:       map[string][]*NodeLabelValue{"gpu": {}},
+		},
+		"unindexed label": {
+			query:          "?label=foo",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockExecutorRepo := schedulermocks.NewMockExecutorRepository(ctrl)
+			mockExecutorRepo.EXPECT().GetExecutors(gomock.Any()).Return(executors, nil).AnyTimes()
+			handler := NewNodeLabelsHttpHandler(testfixtures.TestSchedulingConfig(), mockExecutorRepo, testfixtures.NewJobDb())
+			handler.clock = clock.NewFakeClock(baseTime)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/nodeLabels"+tc.query, nil))
+			require.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var rv NodeLabels
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&rv))
+			require.Len(t, rv.Labels, len(tc.expected))
+			for _, label := range rv.Labels {
+				expected, ok := tc.expected[label.Label]
+				require.True(t, ok, "unexpected label %s", label.Label)
+				require.Len(t, label.Values, len(expected))
+				for i, value := range label.Values {
+					assert.Equal(t, expected[i].Value, value.Value)
+					assert.Equal(t, expected[i].NumNodes, value.NumNodes)
+					assert.Equal(t, expected[i].NumUnschedulableNodes, value.NumUnschedulableNodes)
+					assert.True(t, expected[i].TotalResources.Equal(value.TotalResources), "expected %s, got %s", expected[i].TotalResources.CompactString(), value.TotalResources.CompactString())
+					assert.True(t, expected[i].FreeResources.Equal(value.FreeResources), "expected %s, got %s", expected[i].FreeResources.CompactString(), value.FreeResources.CompactString())
+				}
+			}
+		})
+	}
+}
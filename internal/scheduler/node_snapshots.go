@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/scheduler/nodedb"
+)
+
+// maxNodeSnapshotsPerExecutorGroup is the number of snapshots retained for each executor group.
+const maxNodeSnapshotsPerExecutorGroup = 64
+
+// NodeSnapshots records how the nodes of each executor group change across scheduling rounds,
+// such that it's possible to find out why jobs that were scheduled previously no longer are,
+// e.g., because nodes were removed, lost capacity, or had their labels or taints changed.
+// A snapshot is only retained if it differs from the previous one, i.e., each snapshot marks a change to the nodes.
+type NodeSnapshots struct {
+	// Snapshots of each executor group, oldest first.
+	snapshotsByGroup map[string][]*nodedb.Snapshot
+	// Pool of each executor group.
+	poolByGroup map[string]string
+	mu          sync.Mutex
+}
+
+func NewNodeSnapshots() *NodeSnapshots {
+	return &NodeSnapshots{
+		snapshotsByGroup: make(map[string][]*nodedb.Snapshot),
+		poolByGroup:      make(map[string]string),
+	}
+}
+
+// Record stores a snapshot of the nodes of an executor group if those nodes changed since the previous snapshot.
+func (s *NodeSnapshots) Record(group string, pool string, snapshot *nodedb.Snapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshots := s.snapshotsByGroup[group]
+	if n := len(snapshots); n > 0 && nodedb.DiffSnapshots(snapshots[n-1], snapshot).IsEmpty() {
+		return
+	}
+	snapshots = append(snapshots, snapshot)
+	if len(snapshots) > maxNodeSnapshotsPerExecutorGroup {
+		snapshots = snapshots[len(snapshots)-maxNodeSnapshotsPerExecutorGroup:]
+	}
+	s.snapshotsByGroup[group] = snapshots
+	s.poolByGroup[group] = pool
+}
+
+// Latest returns the most recent snapshot of each executor group of the given pool, or of all pools if pool is empty.
+func (s *NodeSnapshots) Latest(pool string) map[string]*nodedb.Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rv := make(map[string]*nodedb.Snapshot)
+	for group, snapshots := range s.snapshotsByGroup {
+		if pool != "" && s.poolByGroup[group] != pool {
+			continue
+		}
+		rv[group] = snapshots[len(snapshots)-1]
+	}
+	return rv
+}
+
+// Diff returns, for each executor group of the given pool, or of all pools if pool is empty,
+// the difference between the snapshot in effect at time t and the most recent snapshot.
+// If t is zero, the diff is instead relative to the snapshot before the most recent one, i.e., it's the most recent change.
+// If t predates all retained snapshots, the diff is relative to the oldest one.
+func (s *NodeSnapshots) Diff(pool string, t time.Time) map[string]*nodedb.SnapshotDiff {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rv := make(map[string]*nodedb.SnapshotDiff)
+	for group, snapshots := range s.snapshotsByGroup {
+		if pool != "" && s.poolByGroup[group] != pool {
+			continue
+		}
+		latest := snapshots[len(snapshots)-1]
+		from := snapshots[0]
+		if t.IsZero() {
+			if len(snapshots) > 1 {
+				from = snapshots[len(snapshots)-2]
+			}
+		} else {
+			for _, snapshot := range snapshots {
+				if snapshot.Created.After(t) {
+					break
+				}
+				from = snapshot
+			}
+		}
+		rv[group] = nodedb.DiffSnapshots(from, latest)
+	}
+	return rv
+}
+
+// NodeSnapshotsHttpHandler exposes the node snapshots recorded by the scheduler:
+// - GET /nodeSnapshots?pool=<name> returns the most recent snapshot of each executor group of the pool as json.
+// - GET /nodeSnapshots/diff?pool=<name>&since=<duration> returns, for each executor group of the pool,
+// the nodes added, removed, and changed between the given duration ago and now.
+// If no duration is given, the most recent change is returned.
+// - POST /nodeSnapshots/diff with a json body {"from": <snapshot>, "to": <snapshot>} returns the diff between two snapshots,
+// e.g., as previously returned by GET /nodeSnapshots, possibly by different schedulers.
+// In all cases pool is optional; if omitted, all pools are included.
+type NodeSnapshotsHttpHandler struct {
+	nodeSnapshots *NodeSnapshots
+	clock         clock.Clock
+}
+
+// NodeSnapshotsResponse is the response returned by NodeSnapshotsHttpHandler for GET /nodeSnapshots.
+type NodeSnapshotsResponse struct {
+	// Snapshots indexed by executor group.
+	Snapshots map[string]*nodedb.Snapshot `json:"snapshots"`
+}
+
+// NodeSnapshotDiffsResponse is the response returned by NodeSnapshotsHttpHandler for GET /nodeSnapshots/diff.
+type NodeSnapshotDiffsResponse struct {
+	// Diffs indexed by executor group.
+	Diffs map[string]*nodedb.SnapshotDiff `json:"diffs"`
+}
+
+// NodeSnapshotDiffRequest is the request body accepted by NodeSnapshotsHttpHandler for POST /nodeSnapshots/diff.
+type NodeSnapshotDiffRequest struct {
+	From *nodedb.Snapshot `json:"from"`
+	To   *nodedb.Snapshot `json:"to"`
+}
+
+func NewNodeSnapshotsHttpHandler(nodeSnapshots *NodeSnapshots) *NodeSnapshotsHttpHandler {
+	return &NodeSnapshotsHttpHandler{
+		nodeSnapshots: nodeSnapshots,
+		clock:         clock.RealClock{},
+	}
+}
+
+func (h *NodeSnapshotsHttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pool := r.URL.Query().Get("pool")
+	isDiff := strings.HasSuffix(r.URL.Path, "/diff")
+	switch {
+	case r.Method == http.MethodGet && !isDiff:
+		h.writeResponse(w, &NodeSnapshotsResponse{Snapshots: h.nodeSnapshots.Latest(pool)})
+	case r.Method == http.MethodGet && isDiff:
+		var t time.Time
+		if s := r.URL.Query().Get("since"); s != "" {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				http.Error(w, errors.Errorf("invalid since %s: %s", s, err).Error(), http.StatusBadRequest)
+				return
+			}
+			t = h.clock.Now().Add(-d)
+		}
+		h.writeResponse(w, &NodeSnapshotDiffsResponse{Diffs: h.nodeSnapshots.Diff(pool, t)})
+	case r.Method == http.MethodPost && isDiff:
+		var req NodeSnapshotDiffRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.From == nil || req.To == nil {
+			http.Error(w, "both from and to snapshots must be provided", http.StatusBadRequest)
+			return
+		}
+		h.writeResponse(w, nodedb.DiffSnapshots(req.From, req.To))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *NodeSnapshotsHttpHandler) writeResponse(w http.ResponseWriter, rv any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rv); err != nil {
+		log.WithError(err).Error("failed to write node snapshots response")
+	}
+}
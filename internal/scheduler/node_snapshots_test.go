@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/scheduler/nodedb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestNodeSnapshots(t *testing.T) {
+	t1 := testfixtures.BaseTime
+	t2 := t1.Add(time.Hour)
+	t3 := t2.Add(time.Hour)
+	snapshot1 := testNodeSnapshot(t1, "node-1")
+	snapshot2 := testNodeSnapshot(t2, "node-1", "node-2")
+	snapshot3 := testNodeSnapshot(t3, "node-2")
+
+	nodeSnapshots := NewNodeSnapshots()
+	nodeSnapshots.Record("executor-1", "pool-1", snapshot1)
+	// Unchanged snapshots aren't recorded.
+	nodeSnapshots.Record("executor-1", "pool-1", testNodeSnapshot(t1.Add(time.Minute), "node-1"))
+	nodeSnapshots.Record("executor-1", "pool-1", snapshot2)
+	nodeSnapshots.Record("executor-1", "pool-1", snapshot3)
+	nodeSnapshots.Record("executor-2", "pool-2", testNodeSnapshot(t1, "node-3"))
+
+	assert.Equal(t, map[string]*nodedb.Snapshot{"executor-1": snapshot3}, nodeSnapshots.Latest("pool-1"))
+	assert.Len(t, nodeSnapshots.Latest(""), 2)
+
+	// Without a time, the most recent change is returned.
+	diffs := nodeSnapshots.Diff("pool-1", time.Time{})
+	require.Contains(t, diffs, "executor-1")
+	assert.Equal(t, t2, diffs["executor-1"].From)
+	assert.Empty(t, diffs["executor-1"].AddedNodes)
+	assert.Len(t, diffs["executor-1"].RemovedNodes, 1)
+
+	// Otherwise, the diff is relative to the snapshot in effect at that time.
+	diffs = nodeSnapshots.Diff("pool-1", t1.Add(30*time.Minute))
+	require.Contains(t, diffs, "executor-1")
+	assert.Equal(t, t1, diffs["executor-1"].From)
+	assert.Len(t, diffs["executor-1"].AddedNodes, 1)
+	assert.Len(t, diffs["executor-1"].RemovedNodes, 1)
+
+	// Diffs of groups that haven't changed are empty.
+	diffs = nodeSnapshots.Diff("pool-2", time.Time{})
+	require.Contains(t, diffs, "executor-2")
+	assert.True(t, diffs["executor-2"].IsEmpty())
+}
+
+func TestNodeSnapshots_Retention(t *testing.T) {
+	nodeSnapshots := NewNodeSnapshots()
+	for i := 0; i < 2*maxNodeSnapshotsPerExecutorGroup; i++ {
+		snapshot := testNodeSnapshot(testfixtures.BaseTime.Add(time.Duration(i)*time.Minute), "node-1")
+		snapshot.Nodes["node-1"].TotalResources.Set("cpu", *resource.NewQuantity(int64(i+1), resource.DecimalSI))
+		nodeSnapshots.Record("executor-1", "pool-1", snapshot)
+	}
+	assert.Len(t, nodeSnapshots.snapshotsByGroup["executor-1"], maxNodeSnapshotsPerExecutorGroup)
+}
+
+func TestNodeSnapshotsHttpHandler(t *testing.T) {
+	t1 := testfixtures.BaseTime
+	snapshot1 := testNodeSnapshot(t1, "node-1")
+	snapshot2 := testNodeSnapshot(t1.Add(time.Hour), "node-1", "node-2")
+	nodeSnapshots := NewNodeSnapshots()
+	nodeSnapshots.Record("executor-1", "pool-1", snapshot1)
+	nodeSnapshots.Record("executor-1", "pool-1", snapshot2)
+	handler := NewNodeSnapshotsHttpHandler(nodeSnapshots)
+	handler.clock = clock.NewFakeClock(t1.Add(2 * time.Hour))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/nodeSnapshots?pool=pool-1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var snapshotsResponse NodeSnapshotsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&snapshotsResponse))
+	require.Contains(t, snapshotsResponse.Snapshots, "executor-1")
+	assert.Len(t, snapshotsResponse.Snapshots["executor-1"].Nodes, 2)
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/nodeSnapshots/diff?pool=pool-1&since=2h", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var diffsResponse NodeSnapshotDiffsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&diffsResponse))
+	require.Contains(t, diffsResponse.Diffs, "executor-1")
+	if assert.Len(t, diffsResponse.Diffs["executor-1"].AddedNodes, 1) {
+		assert.Equal(t, "node-2", diffsResponse.Diffs["executor-1"].AddedNodes[0].Id)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/nodeSnapshots/diff?since=yesterday", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Snapshots obtained previously can be diffed, e.g., to compare the nodes of a pool across schedulers or days.
+	body, err := json.Marshal(NodeSnapshotDiffRequest{From: snapshot2, To: snapshot1})
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/nodeSnapshots/diff", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+	var diff nodedb.SnapshotDiff
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&diff))
+	if assert.Len(t, diff.RemovedNodes, 1) {
+		assert.Equal(t, "node-2", diff.RemovedNodes[0].Id)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/nodeSnapshots/diff", bytes.NewReader([]byte("{}"))))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func testNodeSnapshot(created time.Time, nodeIds ...string) *nodedb.Snapshot {
+	snapshot := &nodedb.Snapshot{
+		Created: created,
+		Nodes:   make(map[string]*nodedb.NodeSnapshot),
+	}
+	for _, nodeId := range nodeIds {
+		snapshot.Nodes[nodeId] = &nodedb.NodeSnapshot{
+			Id:   nodeId,
+			Name: nodeId,
+			TotalResources: schedulerobjects.ResourceList{
+				Resources: map[string]resource.Quantity{
+					"cpu":    resource.MustParse("32"),
+					"memory": resource.MustParse("256Gi"),
+				},
+			},
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,219 @@
+package nodedb
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// defaultGangPackingTimeBudget is the time the gang packing solver is given per gang if no time budget is configured.
+const defaultGangPackingTimeBudget = 100 * time.Millisecond
+
+// GangPackingProblem is the problem of placing all jobs of a gang onto nodes, such that the resources requested by
+// the jobs placed on each node don't exceed what's available on that node, at minimum total cost of the nodes used.
+// Resources are given in milli-units and in the same order for all jobs and nodes.
+type GangPackingProblem struct {
+	// Resources requested by each job.
+	Requests [][]int64
+	// Resources available on each node.
+	Capacity [][]int64
+	// Cost incurred by placing one or more jobs on each node.
+	NodeCost []float64
+	// Indices of the nodes each job may be placed on, i.e., the nodes meeting the static requirements of that job.
+	CandidateNodes [][]int
+}
+
+// GangPackingSolver places all jobs of a gang onto nodes.
+// Implementations may wrap, e.g., a min-cost flow or a mixed-integer programming solver.
+type GangPackingSolver interface {
+	// Solve returns the index of the node each job is placed on, or nil if no placement was found.
+	// Solve should return the best placement found so far once ctx is done.
+	Solve(ctx context.Context, problem *GangPackingProblem) ([]int, error)
+}
+
+// SetGangPackingSolver makes the NodeDb place gangs of at least config.MinGangCardinality jobs using solver,
+// falling back to placing jobs one at a time if the solver finds no placement within config.TimeBudget.
+// A nil solver disables solver-based placement.
+func (nodeDb *NodeDb) SetGangPackingSolver(solver GangPackingSolver, config configuration.GangPackingConfig) {
+	nodeDb.gangPackingSolver = solver
+	nodeDb.gangPackingConfig = config
+}
+
+// scheduleManyWithSolverWithTxn tries placing all jobs in jctxs using the gang packing solver.
+// Jobs are only placed on resources that are unallocated, i.e., the solver never causes preemptions.
+// Returns false, without mutating the NodeDb, if the solver isn't applicable to this gang or finds no placement.
+func (nodeDb *NodeDb) scheduleManyWithSolverWithTxn(txn *memdb.Txn, jctxs []*schedulercontext.JobSchedulingContext) (bool, error) {
+	if nodeDb.gangPackingSolver == nil || len(jctxs) == 0 || len(jctxs) < int(nodeDb.gangPackingConfig.MinGangCardinality) {
+		return false, nil
+	}
+	// Per-node job limits, gang roles, and NUMA cell constraints are only enforced by the greedy path.
+	if nodeDb.maxJobsPerNode != 0 || nodeDb.maxGangMembersPerNode != 0 {
+		return false, nil
+	}
+	for _, jctx := range jctxs {
+		if jctx.GangRole != "" || requiresSingleNumaCell(jctx.PodRequirements.Annotations) {
+			return false, nil
+		}
+		// Jobs pinned to a node, e.g., evicted jobs being re-scheduled, are left to the greedy path.
+		if _, ok := jctx.PodRequirements.NodeSelector[schedulerconfig.NodeIdLabel]; ok {
+			return false, nil
+		}
+	}
+
+	it, err := txn.Get("nodes", "id")
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	var nodes []*Node
+	for obj := it.Next(); obj != nil; obj = it.Next() {
+		nodes = append(nodes, obj.(*Node))
+	}
+
+	resourceTypesSet := make(map[string]bool)
+	for _, jctx := range jctxs {
+		for t := range jctx.PodRequirements.ResourceRequirements.Requests {
+			resourceTypesSet[string(t)] = true
+		}
+	}
+	resourceTypes := maps.Keys(resourceTypesSet)
+
+	emptyNodeCost := nodeDb.gangPackingConfig.EmptyNodeCost
+	if emptyNodeCost < 1 {
+		emptyNodeCost = 1
+	}
+	problem := &GangPackingProblem{
+		Requests:       make([][]int64, len(jctxs)),
+		Capacity:       make([][]int64, len(nodes)),
+		NodeCost:       make([]float64, len(nodes)),
+		CandidateNodes: make([][]int, len(jctxs)),
+	}
+	for i, node := range nodes {
+		allocatable := node.AllocatableByPriority[evictedPriority]
+		problem.Capacity[i] = make([]int64, len(resourceTypes))
+		for j, t := range resourceTypes {
+			q := allocatable.Get(t)
+			problem.Capacity[i][j] = q.MilliValue()
+		}
+		problem.NodeCost[i] = 1
+		if len(node.AllocatedByJobId) == 0 {
+			problem.NodeCost[i] = emptyNodeCost
+		}
+	}
+	for i, jctx := range jctxs {
+		req := jctx.PodRequirements
+		problem.Requests[i] = make([]int64, len(resourceTypes))
+		for j, t := range resourceTypes {
+			q := req.ResourceRequirements.Requests[v1.ResourceName(t)]
+			problem.Requests[i][j] = q.MilliValue()
+		}
+		for j, node := range nodes {
+			if notReserved, _ := nodeDb.nodeReservationMet(node, req); !notReserved {
+				continue
+			}
+			if matches, _, err := schedulerobjects.StaticPodRequirementsMet(node.Taints, node.Labels, node.TotalResources, req); err != nil {
+				return false, err
+			} else if matches {
+				problem.CandidateNodes[i] = append(problem.CandidateNodes[i], j)
+			}
+		}
+		if len(problem.CandidateNodes[i]) == 0 {
+			return false, nil
+		}
+	}
+
+	timeBudget := nodeDb.gangPackingConfig.TimeBudget
+	if timeBudget <= 0 {
+		timeBudget = defaultGangPackingTimeBudget
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeBudget)
+	defer cancel()
+	assignment, err := nodeDb.gangPackingSolver.Solve(ctx, problem)
+	if err != nil {
+		return false, err
+	}
+	if assignment == nil {
+		return false, nil
+	}
+	if err := validateGangPackingAssignment(problem, assignment); err != nil {
+		return false, err
+	}
+
+	// Bind jobs to nodes via copies, such that nodes with several jobs placed on them are only upserted once.
+	boundNodes := make(map[int]*Node)
+	for i, jctx := range jctxs {
+		j := assignment[i]
+		node, ok := boundNodes[j]
+		if !ok {
+			node = nodes[j]
+		}
+		node, err = bindJobToNode(nodeDb.priorityClasses, jctx.Job, node)
+		if err != nil {
+			return false, err
+		}
+		boundNodes[j] = node
+		jctx.ShouldFail = false
+		jctx.PodSchedulingContext = &schedulercontext.PodSchedulingContext{
+			Created:                  time.Now(),
+			NodeId:                   node.Id,
+			Score:                    schedulerobjects.SchedulableScore,
+			ScheduledAtPriority:      evictedPriority,
+			NumNodes:                 nodeDb.numNodes,
+			NumExcludedNodesByReason: make(map[string]int),
+		}
+	}
+	for _, node := range boundNodes {
+		if err := nodeDb.UpsertWithTxn(txn, node); err != nil {
+			return false, err
+		}
+	}
+	if nodeDb.enableNewPreemptionStrategy {
+		for _, jctx := range jctxs {
+			if err := deleteEvictedJobSchedulingContextIfExistsWithTxn(txn, jctx.JobId); err != nil {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// validateGangPackingAssignment returns an error if assignment isn't a feasible solution to problem,
+// to guard against faulty solvers.
+func validateGangPackingAssignment(problem *GangPackingProblem, assignment []int) error {
+	if len(assignment) != len(problem.Requests) {
+		return errors.Errorf("gang packing solver placed %d jobs, but the gang has %d jobs", len(assignment), len(problem.Requests))
+	}
+	used := make(map[int][]int64)
+	for i, j := range assignment {
+		if !isCandidateNode(problem.CandidateNodes[i], j) {
+			return errors.Errorf("gang packing solver placed job %d on node %d, which doesn't meet the requirements of that job", i, j)
+		}
+		if used[j] == nil {
+			used[j] = make([]int64, len(problem.Capacity[j]))
+		}
+		for k, q := range problem.Requests[i] {
+			used[j][k] += q
+			if used[j][k] > problem.Capacity[j][k] {
+				return errors.Errorf("gang packing solver exceeded the resources available on node %d", j)
+			}
+		}
+	}
+	return nil
+}
+
+func isCandidateNode(candidates []int, j int) bool {
+	for _, c := range candidates {
+		if c == j {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package nodedb
+
+import (
+	"context"
+	"math"
+
+	"golang.org/x/exp/slices"
+)
+
+// BranchAndBoundGangPackingSolver is a GangPackingSolver that searches over placements depth-first,
+// considering for each job the nodes other jobs are already placed on before any other nodes.
+// Hence, the first placement found is a first-fit placement; the solver then keeps looking for cheaper placements,
+// pruning partial placements at least as costly as the best found so far, until the search completes or ctx is done.
+type BranchAndBoundGangPackingSolver struct{}
+
+// ctxCheckInterval is the number of search steps between checks of whether the context is done.
+const ctxCheckInterval = 1024
+
+func (BranchAndBoundGangPackingSolver) Solve(ctx context.Context, problem *GangPackingProblem) ([]int, error) {
+	numJobs := len(problem.Requests)
+	if numJobs == 0 {
+		return []int{}, nil
+	}
+
+	// Place large jobs first, as measured by their largest request relative to the largest node;
+	// they're the hardest to place and constrain the search the most.
+	maxCapacity := make([]int64, len(problem.Requests[0]))
+	for _, capacity := range problem.Capacity {
+		for k, q := range capacity {
+			if q > maxCapacity[k] {
+				maxCapacity[k] = q
+			}
+		}
+	}
+	size := make([]float64, numJobs)
+	for i, req := range problem.Requests {
+		for k, q := range req {
+			if maxCapacity[k] > 0 {
+				size[i] = math.Max(size[i], float64(q)/float64(maxCapacity[k]))
+			}
+		}
+	}
+	jobOrder := make([]int, numJobs)
+	for i := range jobOrder {
+		jobOrder[i] = i
+	}
+	slices.SortStableFunc(jobOrder, func(a, b int) bool { return size[a] > size[b] })
+
+	// Consider cheap nodes first. Ties are broken by node index to keep the search deterministic.
+	cheaper := func(a, b int) bool {
+		if problem.NodeCost[a] != problem.NodeCost[b] {
+			return problem.NodeCost[a] < problem.NodeCost[b]
+		}
+		return a < b
+	}
+	nodesByCost := make([]int, len(problem.Capacity))
+	for j := range nodesByCost {
+		nodesByCost[j] = j
+	}
+	slices.SortFunc(nodesByCost, cheaper)
+	nodeRank := make([]int, len(problem.Capacity))
+	for rank, j := range nodesByCost {
+		nodeRank[j] = rank
+	}
+	candidates := make([][]int, numJobs)
+	for i, j := range jobOrder {
+		candidates[i] = slices.Clone(problem.CandidateNodes[j])
+		slices.SortFunc(candidates[i], cheaper)
+	}
+
+	s := &branchAndBoundSearch{
+		ctx:        ctx,
+		problem:    problem,
+		jobOrder:   jobOrder,
+		candidates: candidates,
+		nodeRank:   nodeRank,
+		remaining:  make([][]int64, len(problem.Capacity)),
+		numJobsOn:  make([]int, len(problem.Capacity)),
+		placement:  make([]int, numJobs),
+		bestCost:   math.Inf(1),
+	}
+	for j, capacity := range problem.Capacity {
+		s.remaining[j] = slices.Clone(capacity)
+	}
+	s.search(0, 0)
+	if s.best == nil {
+		return nil, nil
+	}
+	rv := make([]int, numJobs)
+	for i, j := range jobOrder {
+		rv[j] = s.best[i]
+	}
+	return rv, nil
+}
+
+type branchAndBoundSearch struct {
+	ctx        context.Context
+	problem    *GangPackingProblem
+	jobOrder   []int
+	candidates [][]int
+	nodeRank   []int
+	// Resources remaining on each node given the current partial placement.
+	remaining [][]int64
+	// Number of jobs placed on each node in the current partial placement.
+	numJobsOn []int
+	// Node each job, in search order, is placed on in the current partial placement.
+	placement []int
+	best      []int
+	bestCost  float64
+	numSteps  int
+	done      bool
+}
+
+// search places the i-th job, in search order, and all jobs after it, given a partial placement of cost cost.
+func (s *branchAndBoundSearch) search(i int, cost float64) {
+	if s.done {
+		return
+	}
+	s.numSteps++
+	if s.numSteps%ctxCheckInterval == 0 && s.ctx.Err() != nil {
+		s.done = true
+		return
+	}
+	if cost >= s.bestCost {
+		return
+	}
+	if i == len(s.jobOrder) {
+		s.best = slices.Clone(s.placement)
+		s.bestCost = cost
+		return
+	}
+
+	// Identical consecutive jobs are interchangeable, so only placements where such jobs are on nodes
+	// of non-decreasing rank are considered.
+	minRank := -1
+	if i > 0 && s.isIdentical(s.jobOrder[i-1], s.jobOrder[i]) {
+		minRank = s.nodeRank[s.placement[i-1]]
+	}
+	req := s.problem.Requests[s.jobOrder[i]]
+
+	// Try nodes already in use first, since placing the job on these incurs no extra cost.
+	for _, j := range s.candidates[i] {
+		if s.numJobsOn[j] == 0 || s.nodeRank[j] < minRank || !fits(req, s.remaining[j]) {
+			continue
+		}
+		s.place(i, j, req)
+		s.search(i+1, cost)
+		s.unplace(i, j, req)
+		if s.done {
+			return
+		}
+	}
+	for _, j := range s.candidates[i] {
+		if s.numJobsOn[j] != 0 || s.nodeRank[j] < minRank || !fits(req, s.remaining[j]) {
+			continue
+		}
+		// Candidates are sorted by cost; all remaining nodes are at least as costly.
+		if cost+s.problem.NodeCost[j] >= s.bestCost {
+			break
+		}
+		s.place(i, j, req)
+		s.search(i+1, cost+s.problem.NodeCost[j])
+		s.unplace(i, j, req)
+		if s.done {
+			return
+		}
+	}
+}
+
+func (s *branchAndBoundSearch) place(i, j int, req []int64) {
+	for k, q := range req {
+		s.remaining[j][k] -= q
+	}
+	s.numJobsOn[j]++
+	s.placement[i] = j
+}
+
+func (s *branchAndBoundSearch) unplace(i, j int, req []int64) {
+	for k, q := range req {
+		s.remaining[j][k] += q
+	}
+	s.numJobsOn[j]--
+	s.placement[i] = -1
+}
+
+func (s *branchAndBoundSearch) isIdentical(a, b int) bool {
+	return slices.Equal(s.problem.Requests[a], s.problem.Requests[b]) &&
+		slices.Equal(s.problem.CandidateNodes[a], s.problem.CandidateNodes[b])
+}
+
+func fits(req, remaining []int64) bool {
+	for k, q := range req {
+		if q > remaining[k] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package nodedb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/pointer"
+	armadaslices "github.com/armadaproject/armada/internal/common/slices"
+)
+
+func TestBranchAndBoundGangPackingSolver(t *testing.T) {
+	tests := map[string]struct {
+		problem *GangPackingProblem
+		// Total cost of the nodes used by the best placement, or nil if there's no feasible placement.
+		expectedCost *float64
+	}{
+		"empty gang": {
+			problem:      &GangPackingProblem{},
+			expectedCost: pointer.Pointer(0.0),
+		},
+		"prefer cheap node": {
+			problem: &GangPackingProblem{
+				Requests:       [][]int64{{3}, {3}},
+				Capacity:       [][]int64{{10}, {6}},
+				NodeCost:       []float64{2, 1},
+				CandidateNodes: [][]int{{0, 1}, {0, 1}},
+			},
+			expectedCost: pointer.Pointer(1.0),
+		},
+		"minimise number of nodes": {
+			problem: &GangPackingProblem{
+				Requests:       [][]int64{{4, 1}, {6, 1}, {4, 1}, {6, 1}},
+				Capacity:       [][]int64{{10, 2}, {10, 2}, {10, 2}},
+				NodeCost:       []float64{1, 1, 1},
+				CandidateNodes: [][]int{{0, 1, 2}, {0, 1, 2}, {0, 1, 2}, {0, 1, 2}},
+			},
+			expectedCost: pointer.Pointer(2.0),
+		},
+		"cheaper placement than first fit": {
+			problem: &GangPackingProblem{
+				Requests:       [][]int64{{5}, {4}, {3}, {3}, {3}, {2}},
+				Capacity:       [][]int64{{10}, {10}, {10}},
+				NodeCost:       []float64{1, 1, 1},
+				CandidateNodes: [][]int{{0, 1, 2}, {0, 1, 2}, {0, 1, 2}, {0, 1, 2}, {0, 1, 2}, {0, 1, 2}},
+			},
+			expectedCost: pointer.Pointer(2.0),
+		},
+		"respect candidate nodes": {
+			problem: &GangPackingProblem{
+				Requests:       [][]int64{{1}, {1}},
+				Capacity:       [][]int64{{10}, {10}},
+				NodeCost:       []float64{1, 1},
+				CandidateNodes: [][]int{{0}, {1}},
+			},
+			expectedCost: pointer.Pointer(2.0),
+		},
+		"infeasible": {
+			problem: &GangPackingProblem{
+				Requests:       [][]int64{{6}, {6}},
+				Capacity:       [][]int64{{10}},
+				NodeCost:       []float64{1},
+				CandidateNodes: [][]int{{0}, {0}},
+			},
+			expectedCost: nil,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assignment, err := BranchAndBoundGangPackingSolver{}.Solve(context.Background(), tc.problem)
+			require.NoError(t, err)
+			if tc.expectedCost == nil {
+				assert.Nil(t, assignment)
+				return
+			}
+			require.NotNil(t, assignment)
+			require.NoError(t, validateGangPackingAssignment(tc.problem, assignment))
+			cost := 0.0
+			for _, j := range armadaslices.Unique(assignment) {
+				cost += tc.problem.NodeCost[j]
+			}
+			assert.Equal(t, *tc.expectedCost, cost)
+		})
+	}
+}
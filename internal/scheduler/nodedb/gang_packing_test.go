@@ -0,0 +1,100 @@
+package nodedb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+// fixedGangPackingSolver returns the same assignment for any problem.
+type fixedGangPackingSolver struct {
+	assignment []int
+	numCalls   int
+}
+
+func (s *fixedGangPackingSolver) Solve(_ context.Context, _ *GangPackingProblem) ([]int, error) {
+	s.numCalls++
+	return s.assignment, nil
+}
+
+func TestScheduleManyWithGangPackingSolver(t *testing.T) {
+	tests := map[string]struct {
+		solver             *fixedGangPackingSolver
+		minGangCardinality uint
+		expectSolverCalled bool
+		expectSuccess      bool
+		expectError        bool
+		// If true, all jobs are expected to be placed on the same node.
+		expectSingleNode bool
+	}{
+		"solver placement is used": {
+			solver:             &fixedGangPackingSolver{assignment: []int{1, 1, 1, 1}},
+			expectSolverCalled: true,
+			expectSuccess:      true,
+			expectSingleNode:   true,
+		},
+		"fall back to greedy placement if solver finds no placement": {
+			solver:             &fixedGangPackingSolver{},
+			expectSolverCalled: true,
+			expectSuccess:      true,
+		},
+		"gang smaller than min cardinality": {
+			solver:             &fixedGangPackingSolver{assignment: []int{1, 1, 1, 1}},
+			minGangCardinality: 5,
+			expectSuccess:      true,
+		},
+		"infeasible solver placement": {
+			solver:             &fixedGangPackingSolver{assignment: []int{0, 0, 0, 2}},
+			expectSolverCalled: true,
+			expectError:        true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodeDb, err := newNodeDbWithNodes(testfixtures.N32CpuNodes(2, testfixtures.TestPriorities))
+			require.NoError(t, err)
+			nodeDb.SetGangPackingSolver(
+				tc.solver,
+				configuration.GangPackingConfig{
+					Enabled:            true,
+					MinGangCardinality: tc.minGangCardinality,
+					TimeBudget:         time.Second,
+				},
+			)
+			gang := testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 4))
+			jctxs := schedulercontext.JobSchedulingContextsFromJobs(
+				testfixtures.TestPriorityClasses,
+				gang,
+				func(_ map[string]string) (string, int, int, bool, error) { return "", len(gang), len(gang), true, nil },
+			)
+			ok, err := nodeDb.ScheduleMany(jctxs)
+			assert.Equal(t, tc.expectSolverCalled, tc.solver.numCalls > 0)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectSuccess, ok)
+
+			nodeIds := make(map[string]bool)
+			for _, jctx := range jctxs {
+				require.NotNil(t, jctx.PodSchedulingContext)
+				require.NotEmpty(t, jctx.PodSchedulingContext.NodeId)
+				nodeIds[jctx.PodSchedulingContext.NodeId] = true
+				node, err := nodeDb.GetNode(jctx.PodSchedulingContext.NodeId)
+				require.NoError(t, err)
+				assert.Contains(t, node.AllocatedByJobId, jctx.JobId)
+			}
+			if tc.expectSingleNode {
+				assert.Len(t, nodeIds, 1)
+			}
+		})
+	}
+}
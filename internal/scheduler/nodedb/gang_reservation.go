@@ -0,0 +1,135 @@
+package nodedb
+
+import (
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// SetNodeReservations reserves nodes for gangs accumulating capacity across scheduling rounds,
+// given as a map from node id to the id of the gang the node is reserved for.
+// Jobs not in that gang aren't scheduled onto reserved nodes, such that the resources of these nodes accumulate
+// as the jobs bound to them finish, until they suffice to schedule the gang.
+func (nodeDb *NodeDb) SetNodeReservations(gangIdByNodeId map[string]string) {
+	nodeDb.gangIdByReservedNodeId = gangIdByNodeId
+}
+
+// nodeReservationMet returns true if node isn't reserved, or is reserved for the gang of a job with requirements req,
+// or the reason why the job can't be scheduled onto node otherwise.
+// Jobs pinned to a node, e.g., evicted jobs being re-scheduled onto the node they're running on, are exempt,
+// such that reserving a node never preempts the jobs already bound to it.
+func (nodeDb *NodeDb) nodeReservationMet(node *Node, req *schedulerobjects.PodRequirements) (bool, schedulerobjects.PodRequirementsNotMetReason) {
+	gangId, ok := nodeDb.gangIdByReservedNodeId[node.Id]
+	if !ok || req.Annotations[configuration.GangIdAnnotation] == gangId {
+		return true, nil
+	}
+	if _, ok := req.NodeSelector[schedulerconfig.NodeIdLabel]; ok {
+		return true, nil
+	}
+	return false, &schedulerobjects.NodeReservedForGang{GangId: gangId}
+}
+
+// NodeIdsToReserveForGang returns the ids of nodes to reserve for a gang that can't currently be scheduled,
+// such that the gang fits onto these nodes once the jobs bound to them have finished.
+// Only nodes that meet the static requirements, e.g., node selectors and tolerations, of some job of the gang are chosen.
+// Nodes with fewer jobs bound to them are preferred, since they're expected to free up sooner.
+//
+// If the gang has a node uniformity constraint, all nodes chosen have the same value for the uniformity label,
+// choosing the value requiring the fewest nodes; for hierarchical constraints, the finest label with such a value is used.
+// Returns nil if no such set of nodes exists, e.g., if the gang requires more resources than there are across all nodes.
+func (nodeDb *NodeDb) NodeIdsToReserveForGang(gctx *schedulercontext.GangSchedulingContext) ([]string, error) {
+	if len(gctx.JobSchedulingContexts) == 0 {
+		return nil, nil
+	}
+	gangId := gctx.JobSchedulingContexts[0].PodRequirements.Annotations[configuration.GangIdAnnotation]
+	txn := nodeDb.Txn(false)
+	it, err := NewNodesIterator(txn)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []*Node
+	for node := it.NextNode(); node != nil; node = it.NextNode() {
+		if reservedFor, ok := nodeDb.gangIdByReservedNodeId[node.Id]; ok && reservedFor != gangId {
+			continue
+		}
+		if ok, err := nodeMeetsStaticRequirementsOfAnyJob(node, gctx.JobSchedulingContexts); err != nil {
+			return nil, err
+		} else if ok {
+			candidates = append(candidates, node)
+		}
+	}
+	slices.SortFunc(candidates, func(a, b *Node) bool {
+		if len(a.AllocatedByJobId) != len(b.AllocatedByJobId) {
+			return len(a.AllocatedByJobId) < len(b.AllocatedByJobId)
+		}
+		return a.Id < b.Id
+	})
+
+	// Labels to group nodes by, finest first; the empty label puts all nodes in a single group.
+	labels := []string{gctx.NodeUniformityLabel}
+	if len(gctx.NodeUniformityLabels) > 0 {
+		labels = make([]string, len(gctx.NodeUniformityLabels))
+		for i, label := range gctx.NodeUniformityLabels {
+			labels[len(labels)-1-i] = label
+		}
+	}
+	for _, label := range labels {
+		candidatesByValue := make(map[string][]*Node)
+		for _, node := range candidates {
+			value := ""
+			if label != "" {
+				var ok bool
+				if value, ok = node.Labels[label]; !ok {
+					continue
+				}
+			}
+			candidatesByValue[value] = append(candidatesByValue[value], node)
+		}
+		values := maps.Keys(candidatesByValue)
+		slices.Sort(values)
+		var rv []string
+		for _, value := range values {
+			if nodeIds := nodeDb.nodeIdsCoveringRequests(candidatesByValue[value], gctx.TotalResourceRequests); nodeIds != nil && (rv == nil || len(nodeIds) < len(rv)) {
+				rv = nodeIds
+			}
+		}
+		if rv != nil {
+			return rv, nil
+		}
+	}
+	return nil, nil
+}
+
+// nodeIdsCoveringRequests returns the ids of the shortest prefix of nodes the resources of which, less those set aside
+// on each node, add up to at least requests, or nil if all nodes together don't suffice.
+func (nodeDb *NodeDb) nodeIdsCoveringRequests(nodes []*Node, requests schedulerobjects.ResourceList) []string {
+	var rv []string
+	available := schedulerobjects.NewResourceListWithDefaultSize()
+	for _, node := range nodes {
+		available.Add(node.TotalResources)
+		available.Sub(nodeDb.reservedResources(node.TotalResources))
+		rv = append(rv, node.Id)
+		remaining := available.DeepCopy()
+		remaining.Sub(requests)
+		if remaining.IsStrictlyNonNegative() {
+			return rv
+		}
+	}
+	return nil
+}
+
+// nodeMeetsStaticRequirementsOfAnyJob returns true if some job of jctxs could be scheduled onto node if it were empty.
+func nodeMeetsStaticRequirementsOfAnyJob(node *Node, jctxs []*schedulercontext.JobSchedulingContext) (bool, error) {
+	for _, jctx := range jctxs {
+		if matches, _, err := schedulerobjects.StaticPodRequirementsMet(node.Taints, node.Labels, node.TotalResources, jctx.PodRequirements); err != nil {
+			return false, err
+		} else if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
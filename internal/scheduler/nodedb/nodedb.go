@@ -60,6 +60,12 @@ type Node struct {
 	AllocatedByQueue      map[string]schedulerobjects.ResourceList
 	AllocatedByJobId      map[string]schedulerobjects.ResourceList
 	EvictedJobRunIds      map[string]bool
+	// Gang id of each gang job bound to the node, used to enforce maxGangMembersPerNode.
+	GangIdByJobId map[string]string
+	// Number of NUMA cells of the node, among which its cpu and memory are assumed to be split evenly; zero if unknown.
+	NumaCells int
+	// NUMA cell of each job bound to the node whose cpu and memory requests must fit within a single cell.
+	NumaCellByJobId map[string]int
 }
 
 // UnsafeCopy returns a pointer to a new value of type Node; it is unsafe because it only makes
@@ -83,6 +89,9 @@ func (node *Node) UnsafeCopy() *Node {
 		AllocatedByQueue:      armadamaps.DeepCopy(node.AllocatedByQueue),
 		AllocatedByJobId:      armadamaps.DeepCopy(node.AllocatedByJobId),
 		EvictedJobRunIds:      maps.Clone(node.EvictedJobRunIds),
+		GangIdByJobId:         maps.Clone(node.GangIdByJobId),
+		NumaCells:             node.NumaCells,
+		NumaCellByJobId:       maps.Clone(node.NumaCellByJobId),
 	}
 }
 
@@ -117,6 +126,22 @@ func (nodeDb *NodeDb) create(node *schedulerobjects.Node) (*Node, error) {
 	if minimumPriority < 0 {
 		return nil, errors.Errorf("found negative priority %d on node %s; negative priorities are reserved for internal use", minimumPriority, node.Id)
 	}
+	if nodeDb.externalWorkloadCoexistence {
+		// Resources allocated to non-Armada pods of priority p are already marked as allocated at priorities up to p.
+		for p, rl := range node.NonArmadaAllocatedResources {
+			for priority, allocatable := range allocatableByPriority {
+				if priority > p {
+					allocatable.Sub(rl)
+				}
+			}
+		}
+	}
+	if len(nodeDb.nodeReservedResources) > 0 || len(nodeDb.nodeReservedResourceFractions) > 0 {
+		reservedResources := nodeDb.reservedResources(totalResources)
+		for _, allocatable := range allocatableByPriority {
+			allocatable.Sub(reservedResources)
+		}
+	}
 	allocatableByPriority[evictedPriority] = allocatableByPriority[minimumPriority].DeepCopy()
 
 	allocatedByQueue := node.AllocatedByQueue
@@ -164,6 +189,8 @@ func (nodeDb *NodeDb) create(node *schedulerobjects.Node) (*Node, error) {
 		AllocatedByQueue:      allocatedByQueue,
 		AllocatedByJobId:      allocatedByJobId,
 		EvictedJobRunIds:      evictedJobRunIds,
+
+		NumaCells: nodeDb.numaCells(labels),
 	}
 	return entry, nil
 }
@@ -177,6 +204,7 @@ func (nodeDb *NodeDb) CreateAndInsertWithApiJobsWithTxn(txn *memdb.Txn, jobs []*
 		if err := bindJobToNodeInPlace(nodeDb.priorityClasses, job, entry); err != nil {
 			return err
 		}
+		nodeDb.addColocationGroupNode(job, entry.Id)
 	}
 	if err := nodeDb.UpsertWithTxn(txn, entry); err != nil {
 		return err
@@ -193,6 +221,7 @@ func (nodeDb *NodeDb) CreateAndInsertWithJobDbJobsWithTxn(txn *memdb.Txn, jobs [
 		if err := bindJobToNodeInPlace(nodeDb.priorityClasses, job, entry); err != nil {
 			return err
 		}
+		nodeDb.addColocationGroupNode(job, entry.Id)
 	}
 	if err := nodeDb.UpsertWithTxn(txn, entry); err != nil {
 		return err
@@ -200,6 +229,27 @@ func (nodeDb *NodeDb) CreateAndInsertWithJobDbJobsWithTxn(txn *memdb.Txn, jobs [
 	return nil
 }
 
+// addColocationGroupNode records that a job, if part of a colocation group, is bound to the node with the given id.
+func (nodeDb *NodeDb) addColocationGroupNode(job interfaces.LegacySchedulerJob, nodeId string) {
+	group := job.GetAnnotations()[configuration.ColocationGroupAnnotation]
+	if group == "" {
+		return
+	}
+	key := colocationGroupKey(job.GetQueue(), group)
+	nodeDb.mu.Lock()
+	defer nodeDb.mu.Unlock()
+	nodeIds, ok := nodeDb.nodeIdsByColocationGroup[key]
+	if !ok {
+		nodeIds = make(map[string]struct{})
+		nodeDb.nodeIdsByColocationGroup[key] = nodeIds
+	}
+	nodeIds[nodeId] = empty
+}
+
+func colocationGroupKey(queue, group string) string {
+	return queue + "/" + group
+}
+
 // EvictedJobSchedulingContext represents an evicted job.
 // NodeDb may track these to ensure preemptions are fair.
 type EvictedJobSchedulingContext struct {
@@ -223,9 +273,16 @@ type NodeDb struct {
 	// The NodeDb selects the node with the best score out of the considered nodes.
 	// In particular, the score expresses whether preemption is necessary to schedule a pod.
 	// Hence, a larger maxExtraNodesToConsider would reduce the expected number of preemptions.
-	//
-	// TODO: Currently gives no benefit. Since all nodes are given the same score.
 	maxExtraNodesToConsider uint
+	// Like maxExtraNodesToConsider, but for pods with preferred node affinity terms,
+	// which are scored by the sum of the weights of the terms a node matches.
+	// If smaller than maxExtraNodesToConsider, maxExtraNodesToConsider is used instead.
+	maxExtraNodesToConsiderForPreferences uint
+	// Maximum number of jobs bound to any node; zero means unlimited.
+	maxJobsPerNode uint
+	// Maximum number of members of the same gang bound to any node; zero means unlimited.
+	// Limits the number of members of a gang lost if a single node fails.
+	maxGangMembersPerNode uint
 	// Allowed priority classes.
 	// Because the number of database indices scales linearly with the number of distinct priorities,
 	// the efficiency of the NodeDb relies on the number of distinct priorities being small.
@@ -270,6 +327,9 @@ type NodeDb struct {
 
 	// Map from indexed label names to the set of values that label takes across all nodes in the NodeDb.
 	indexedNodeLabelValues map[string]map[string]struct{}
+	// Map from colocation group, qualified by queue, to the ids of nodes onto which jobs in that group are bound.
+	// Only accounts for jobs bound to nodes as they're inserted into the NodeDb.
+	nodeIdsByColocationGroup map[string]map[string]struct{}
 	// Total number of nodes in the db.
 	numNodes int
 	// Number of nodes in the db by node type.
@@ -285,6 +345,24 @@ type NodeDb struct {
 
 	// If true, use experimental preemption strategy.
 	enableNewPreemptionStrategy bool
+
+	// Resources set aside on each node that jobs are never scheduled onto.
+	// The amount reserved of each resource is the larger of the absolute amount and the fraction of the node total.
+	nodeReservedResources         map[string]resource.Quantity
+	nodeReservedResourceFractions map[string]float64
+	// If true, resources allocated to pods not managed by Armada are considered allocated at all priorities,
+	// since Armada can't preempt such pods.
+	externalWorkloadCoexistence bool
+	// If true, jobs are only scheduled onto unallocated resources, i.e., scheduling never preempts running jobs.
+	preemptionDisabled bool
+	// If set, gangs are placed using this solver, falling back to placing jobs one at a time.
+	gangPackingSolver GangPackingSolver
+	gangPackingConfig configuration.GangPackingConfig
+	// Node label whose value is the number of NUMA cells of each node.
+	// If empty, jobs requiring a single NUMA cell can't be scheduled.
+	numaCellsLabel string
+	// Id of the gang each reserved node is reserved for; jobs not in that gang aren't scheduled onto reserved nodes.
+	gangIdByReservedNodeId map[string]string
 }
 
 func NewNodeDb(
@@ -352,14 +430,15 @@ func NewNodeDb(
 			indexedResources,
 			func(v configuration.IndexedResource) int64 { return v.Resolution.MilliValue() },
 		),
-		indexNameByPriority:    indexNameByPriority,
-		indexedTaints:          mapFromSlice(indexedTaints),
-		indexedNodeLabels:      mapFromSlice(indexedNodeLabels),
-		indexedNodeLabelValues: indexedNodeLabelValues,
-		nodeTypes:              make(map[uint64]*schedulerobjects.NodeType),
-		numNodesByNodeType:     make(map[uint64]int),
-		totalResources:         schedulerobjects.ResourceList{Resources: make(map[string]resource.Quantity)},
-		db:                     db,
+		indexNameByPriority:      indexNameByPriority,
+		indexedTaints:            mapFromSlice(indexedTaints),
+		indexedNodeLabels:        mapFromSlice(indexedNodeLabels),
+		indexedNodeLabelValues:   indexedNodeLabelValues,
+		nodeIdsByColocationGroup: make(map[string]map[string]struct{}),
+		nodeTypes:                make(map[uint64]*schedulerobjects.NodeType),
+		numNodesByNodeType:       make(map[uint64]int),
+		totalResources:           schedulerobjects.ResourceList{Resources: make(map[string]resource.Quantity)},
+		db:                       db,
 		// Set the initial capacity (somewhat arbitrarily) to 128 reasons.
 		podRequirementsNotMetReasonStringCache: make(map[uint64]string, 128),
 	}, nil
@@ -387,6 +466,84 @@ func (nodeDb *NodeDb) EnableNewPreemptionStrategy() {
 	nodeDb.enableNewPreemptionStrategy = true
 }
 
+// SetNodeReservedResources sets the resources set aside on each node that jobs are never scheduled onto,
+// given as absolute amounts and as fractions of the total resources of each node.
+// Only applies to nodes inserted after this call; hence, it should be called before any nodes are inserted.
+func (nodeDb *NodeDb) SetNodeReservedResources(reserved map[string]resource.Quantity, reservedFractions map[string]float64) {
+	nodeDb.nodeReservedResources = reserved
+	nodeDb.nodeReservedResourceFractions = reservedFractions
+}
+
+// SetMaxExtraNodesToConsiderForPreferences sets the number of nodes considered, once a node has been found a pod
+// fits onto, when looking for a node matching more of the preferred node affinity terms of the pod.
+func (nodeDb *NodeDb) SetMaxExtraNodesToConsiderForPreferences(maxExtraNodesToConsiderForPreferences uint) {
+	nodeDb.maxExtraNodesToConsiderForPreferences = maxExtraNodesToConsiderForPreferences
+}
+
+// SetPerNodeJobLimits sets the maximum number of jobs, and of members of the same gang, bound to any node.
+// Zero means unlimited.
+func (nodeDb *NodeDb) SetPerNodeJobLimits(maxJobsPerNode uint, maxGangMembersPerNode uint) {
+	nodeDb.maxJobsPerNode = maxJobsPerNode
+	nodeDb.maxGangMembersPerNode = maxGangMembersPerNode
+}
+
+// perNodeJobLimitsMet returns true if binding a job with requirements req to node at the given priority
+// wouldn't exceed the per-node job limits, or the reason why it would otherwise.
+// Jobs evicted from the node are only counted at evictedPriority, mirroring how their resources are accounted for,
+// such that scheduling at evictedPriority never takes the place of an evicted job.
+func (nodeDb *NodeDb) perNodeJobLimitsMet(node *Node, priority int32, req *schedulerobjects.PodRequirements) (bool, schedulerobjects.PodRequirementsNotMetReason) {
+	if nodeDb.maxJobsPerNode == 0 && nodeDb.maxGangMembersPerNode == 0 {
+		return true, nil
+	}
+	gangId := req.Annotations[configuration.GangIdAnnotation]
+	var numJobs, numGangMembers uint
+	for jobId := range node.AllocatedByJobId {
+		if priority != evictedPriority && node.EvictedJobRunIds[jobId] {
+			continue
+		}
+		numJobs++
+		if gangId != "" && node.GangIdByJobId[jobId] == gangId {
+			numGangMembers++
+		}
+	}
+	if nodeDb.maxJobsPerNode != 0 && numJobs >= nodeDb.maxJobsPerNode {
+		return false, &schedulerobjects.MaxJobsPerNodeReached{Limit: nodeDb.maxJobsPerNode}
+	}
+	if nodeDb.maxGangMembersPerNode != 0 && gangId != "" && numGangMembers >= nodeDb.maxGangMembersPerNode {
+		return false, &schedulerobjects.MaxGangMembersPerNodeReached{Limit: nodeDb.maxGangMembersPerNode}
+	}
+	return true, nil
+}
+
+// SetExternalWorkloadCoexistence controls whether resources allocated to pods not managed by Armada are
+// considered unavailable to jobs of any priority, rather than only to jobs of priority up to that of those pods.
+// Only applies to nodes inserted after this call; hence, it should be called before any nodes are inserted.
+func (nodeDb *NodeDb) SetExternalWorkloadCoexistence(enabled bool) {
+	nodeDb.externalWorkloadCoexistence = enabled
+}
+
+// DisablePreemption makes the NodeDb schedule jobs only onto unallocated resources,
+// such that scheduling never requires preempting running jobs, regardless of their priority.
+func (nodeDb *NodeDb) DisablePreemption() {
+	nodeDb.preemptionDisabled = true
+}
+
+// reservedResources returns the resources set aside on a node with the given total resources.
+func (nodeDb *NodeDb) reservedResources(totalResources schedulerobjects.ResourceList) schedulerobjects.ResourceList {
+	rv := schedulerobjects.NewResourceList(len(nodeDb.nodeReservedResources) + len(nodeDb.nodeReservedResourceFractions))
+	for t, q := range nodeDb.nodeReservedResources {
+		rv.Set(t, q.DeepCopy())
+	}
+	for t, f := range nodeDb.nodeReservedResourceFractions {
+		total := totalResources.Get(t)
+		q := resource.NewMilliQuantity(int64(f*float64(total.MilliValue())), total.Format)
+		if q.Cmp(rv.Get(t)) > 0 {
+			rv.Set(t, *q)
+		}
+	}
+	return rv
+}
+
 func (nodeDb *NodeDb) String() string {
 	var sb strings.Builder
 	w := tabwriter.NewWriter(&sb, 1, 1, 1, ' ', 0)
@@ -412,6 +569,52 @@ func (nodeDb *NodeDb) IndexedNodeLabelValues(label string) (map[string]struct{},
 	return values, ok
 }
 
+// ColocationGroupLabelValues returns, in sorted order, the values of the given node label across nodes onto which
+// jobs in the given queue and colocation group are bound. Nodes without the label are ignored.
+// Since each node has a unique value for schedulerconfig.NodeIdLabel, passing that label returns the ids of these nodes.
+func (nodeDb *NodeDb) ColocationGroupLabelValues(queue, group, label string) ([]string, error) {
+	nodeDb.mu.Lock()
+	nodeIds := maps.Keys(nodeDb.nodeIdsByColocationGroup[colocationGroupKey(queue, group)])
+	nodeDb.mu.Unlock()
+	values := make(map[string]struct{}, len(nodeIds))
+	txn := nodeDb.Txn(false)
+	for _, nodeId := range nodeIds {
+		node, err := nodeDb.GetNodeWithTxn(txn, nodeId)
+		if err != nil {
+			return nil, err
+		}
+		if node == nil {
+			continue
+		}
+		if value, ok := node.Labels[label]; ok {
+			values[value] = empty
+		}
+	}
+	rv := maps.Keys(values)
+	slices.Sort(rv)
+	return rv, nil
+}
+
+// AllocatableByNodeLabelValueWithTxn returns, for each value of the given label, the resources allocatable
+// without preemption summed over all nodes with that value, within the provided transaction.
+func (nodeDb *NodeDb) AllocatableByNodeLabelValueWithTxn(txn *memdb.Txn, label string) (map[string]schedulerobjects.ResourceList, error) {
+	it, err := NewNodesIterator(txn)
+	if err != nil {
+		return nil, err
+	}
+	rv := make(map[string]schedulerobjects.ResourceList)
+	for node := it.NextNode(); node != nil; node = it.NextNode() {
+		value, ok := node.Labels[label]
+		if !ok {
+			continue
+		}
+		allocatable := rv[value]
+		allocatable.Add(node.AllocatableByPriority[evictedPriority])
+		rv[value] = allocatable
+	}
+	return rv, nil
+}
+
 func (nodeDb *NodeDb) NumNodes() int {
 	nodeDb.mu.Lock()
 	defer nodeDb.mu.Unlock()
@@ -513,14 +716,42 @@ func gangMinCardinality(jctxs []*schedulercontext.JobSchedulingContext) int {
 }
 
 func (nodeDb *NodeDb) ScheduleManyWithTxn(txn *memdb.Txn, jctxs []*schedulercontext.JobSchedulingContext) (bool, error) {
+	// Large gangs are placed by the gang packing solver, if set.
+	if ok, err := nodeDb.scheduleManyWithSolverWithTxn(txn, jctxs); err != nil {
+		return false, err
+	} else if ok {
+		return true, nil
+	}
+
 	// Attempt to schedule pods one by one in a transaction.
+	// Pods are first placed onto unallocated resources only, and only those that don't fit are then placed by preempting
+	// other jobs, such that gang jobs that could run on free capacity don't take the place of those that can't.
+	// Since all placements, and hence the preemptions they imply, are made in the same transaction, the combined plan is
+	// only committed if enough jobs of the gang can be scheduled.
 	cumulativeScheduled := 0
 	gangMinCardinality := gangMinCardinality(jctxs)
 
+	jctxs = jctxsInGangRoleOrder(jctxs)
 	for _, jctx := range jctxs {
 		// Defensively reset `ShouldFail` (this should always be false as the state is re-constructed per cycle but just in case)
 		jctx.ShouldFail = false
-
+	}
+	remaining := jctxs
+	if !nodeDb.preemptionDisabled && len(jctxs) > 1 {
+		remaining = make([]*schedulercontext.JobSchedulingContext, 0, len(jctxs))
+		for _, jctx := range jctxs {
+			if node, err := nodeDb.selectNodeForJobWithTxn(txn, jctx, false); err != nil {
+				return false, err
+			} else if node == nil {
+				remaining = append(remaining, jctx)
+			} else if err := nodeDb.bindScheduledJobWithTxn(txn, jctx, node); err != nil {
+				return false, err
+			} else {
+				cumulativeScheduled++
+			}
+		}
+	}
+	for _, jctx := range remaining {
 		node, err := nodeDb.SelectNodeForJobWithTxn(txn, jctx)
 		if err != nil {
 			return false, err
@@ -533,21 +764,9 @@ func (nodeDb *NodeDb) ScheduleManyWithTxn(txn *memdb.Txn, jctxs []*schedulercont
 		}
 
 		// If we found a node for this pod, bind it and continue to the next pod.
-		if node, err := bindJobToNode(nodeDb.priorityClasses, jctx.Job, node); err != nil {
+		if err := nodeDb.bindScheduledJobWithTxn(txn, jctx, node); err != nil {
 			return false, err
-		} else {
-			if err := nodeDb.UpsertWithTxn(txn, node); err != nil {
-				return false, err
-			}
 		}
-
-		// Once a job is scheduled, it should no longer be considered for preemption.
-		if nodeDb.enableNewPreemptionStrategy {
-			if err := deleteEvictedJobSchedulingContextIfExistsWithTxn(txn, jctx.JobId); err != nil {
-				return false, err
-			}
-		}
-
 		cumulativeScheduled++
 	}
 
@@ -555,9 +774,59 @@ func (nodeDb *NodeDb) ScheduleManyWithTxn(txn *memdb.Txn, jctxs []*schedulercont
 		return false, nil
 	}
 
+	// Gangs made up of jobs with different roles are only scheduled if the minimum cardinality of each role is met.
+	if _, ok := schedulercontext.UnmetGangRole(jctxs); ok {
+		return false, nil
+	}
+
 	return true, nil
 }
 
+// bindScheduledJobWithTxn binds the job of jctx to the node selected for it.
+func (nodeDb *NodeDb) bindScheduledJobWithTxn(txn *memdb.Txn, jctx *schedulercontext.JobSchedulingContext, node *Node) error {
+	if node, err := bindJobToNode(nodeDb.priorityClasses, jctx.Job, node); err != nil {
+		return err
+	} else if err := nodeDb.UpsertWithTxn(txn, node); err != nil {
+		return err
+	}
+
+	// Once a job is scheduled, it should no longer be considered for preemption.
+	if nodeDb.enableNewPreemptionStrategy {
+		if err := deleteEvictedJobSchedulingContextIfExistsWithTxn(txn, jctx.JobId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jctxsInGangRoleOrder returns jctxs reordered such that the jobs required to meet the minimum cardinality of each gang role
+// come first, so that resources aren't taken up by optional jobs of one role at the expense of required jobs of another.
+// The relative order of jobs is otherwise preserved.
+func jctxsInGangRoleOrder(jctxs []*schedulercontext.JobSchedulingContext) []*schedulercontext.JobSchedulingContext {
+	hasGangRole := false
+	for _, jctx := range jctxs {
+		if jctx.GangRole != "" {
+			hasGangRole = true
+			break
+		}
+	}
+	if !hasGangRole {
+		return jctxs
+	}
+	required := make([]*schedulercontext.JobSchedulingContext, 0, len(jctxs))
+	optional := make([]*schedulercontext.JobSchedulingContext, 0, len(jctxs))
+	numRequiredByRole := make(map[string]int)
+	for _, jctx := range jctxs {
+		if jctx.GangRole != "" && numRequiredByRole[jctx.GangRole] < jctx.GangRoleMinCardinality {
+			numRequiredByRole[jctx.GangRole]++
+			required = append(required, jctx)
+		} else {
+			optional = append(optional, jctx)
+		}
+	}
+	return append(required, optional...)
+}
+
 func deleteEvictedJobSchedulingContextIfExistsWithTxn(txn *memdb.Txn, jobId string) error {
 	if err := txn.Delete("evictedJobs", &EvictedJobSchedulingContext{JobId: jobId}); err == memdb.ErrNotFound {
 		return nil
@@ -570,6 +839,12 @@ func deleteEvictedJobSchedulingContextIfExistsWithTxn(txn *memdb.Txn, jobId stri
 
 // SelectNodeForJobWithTxn selects a node on which the job can be scheduled.
 func (nodeDb *NodeDb) SelectNodeForJobWithTxn(txn *memdb.Txn, jctx *schedulercontext.JobSchedulingContext) (*Node, error) {
+	return nodeDb.selectNodeForJobWithTxn(txn, jctx, true)
+}
+
+// selectNodeForJobWithTxn selects a node on which the job can be scheduled.
+// If allowPreemption is false, only nodes with enough unallocated resources are considered.
+func (nodeDb *NodeDb) selectNodeForJobWithTxn(txn *memdb.Txn, jctx *schedulercontext.JobSchedulingContext, allowPreemption bool) (*Node, error) {
 	req := jctx.PodRequirements
 
 	// Collect all node types that could potentially schedule the pod.
@@ -605,10 +880,14 @@ func (nodeDb *NodeDb) SelectNodeForJobWithTxn(txn *memdb.Txn, jctx *schedulercon
 
 	// If the targetNodeIdAnnocation is set, consider only that node.
 	if nodeId, ok := req.NodeSelector[schedulerconfig.NodeIdLabel]; ok {
+		priority := req.Priority
+		if nodeDb.preemptionDisabled || !allowPreemption {
+			priority = evictedPriority
+		}
 		if it, err := txn.Get("nodes", "id", nodeId); err != nil {
 			return nil, errors.WithStack(err)
 		} else {
-			if node, err := nodeDb.selectNodeForPodWithIt(pctx, it, req.Priority, req, true); err != nil {
+			if node, err := nodeDb.selectNodeForPodWithIt(pctx, it, priority, req, true); err != nil {
 				return nil, err
 			} else {
 				return node, nil
@@ -625,6 +904,9 @@ func (nodeDb *NodeDb) SelectNodeForJobWithTxn(txn *memdb.Txn, jctx *schedulercon
 	} else if node != nil {
 		return node, nil
 	}
+	if nodeDb.preemptionDisabled || !allowPreemption {
+		return nil, nil
+	}
 
 	// Try scheduling at the job priority. If this fails, scheduling is impossible and we return.
 	// This is an optimisation to avoid looking for preemption targets for unschedulable jobs.
@@ -640,6 +922,11 @@ func (nodeDb *NodeDb) SelectNodeForJobWithTxn(txn *memdb.Txn, jctx *schedulercon
 	pctx.Score = 0
 	pctx.ScheduledAtPriority = 0
 
+	// Since the job doesn't fit onto unallocated resources, any node found from here on requires preempting other jobs.
+	defer func() {
+		pctx.PreemptionRequired = pctx.NodeId != ""
+	}()
+
 	// Schedule by preventing evicted jobs from being re-scheduled.
 	// This method respect fairness by preventing from re-scheduling jobs that appear as far back in the total order as possible.
 	if nodeDb.enableNewPreemptionStrategy {
@@ -759,27 +1046,44 @@ func (nodeDb *NodeDb) selectNodeForPodWithIt(
 	req *schedulerobjects.PodRequirements,
 	onlyCheckDynamicRequirements bool,
 ) (*Node, error) {
+	preferenceScorer, err := schedulerobjects.NewNodePreferenceScorer(req)
+	if err != nil {
+		return nil, err
+	}
+	bestScore := schedulerobjects.SchedulableBestScore + preferenceScorer.MaxScore()
+	maxExtraNodesToConsider := nodeDb.maxExtraNodesToConsider
+	if preferenceScorer != nil && nodeDb.maxExtraNodesToConsiderForPreferences > maxExtraNodesToConsider {
+		maxExtraNodesToConsider = nodeDb.maxExtraNodesToConsiderForPreferences
+	}
+
 	var selectedNode *Node
 	var selectedNodeScore int
 	var numExtraNodes uint
 	for obj := it.Next(); obj != nil; obj = it.Next() {
 		if selectedNode != nil {
 			numExtraNodes++
-			if numExtraNodes > nodeDb.maxExtraNodesToConsider {
+			if numExtraNodes > maxExtraNodesToConsider {
 				break
 			}
 		}
 
 		node := obj.(*Node)
 		if node == nil {
-			return nil, nil
+			// Some iterators signal exhaustion with a typed nil; keep the best node found so far.
+			break
 		}
 
 		var matches bool
 		var score int
 		var reason schedulerobjects.PodRequirementsNotMetReason
 		var err error
-		if onlyCheckDynamicRequirements {
+		if notReserved, reservedReason := nodeDb.nodeReservationMet(node, req); !notReserved {
+			matches, reason = false, reservedReason
+		} else if withinLimits, limitReason := nodeDb.perNodeJobLimitsMet(node, priority, req); !withinLimits {
+			matches, reason = false, limitReason
+		} else if fits, numaReason := numaCellRequirementsMet(node, priority, req); !fits {
+			matches, reason = false, numaReason
+		} else if onlyCheckDynamicRequirements {
 			matches, score, reason, err = schedulerobjects.DynamicPodRequirementsMet(node.AllocatableByPriority[priority], req)
 		} else {
 			matches, score, reason, err = schedulerobjects.PodRequirementsMet(node.Taints, node.Labels, node.TotalResources, node.AllocatableByPriority[priority], req)
@@ -789,10 +1093,11 @@ func (nodeDb *NodeDb) selectNodeForPodWithIt(
 		}
 
 		if matches {
+			score += preferenceScorer.Score(node.Labels)
 			if selectedNode == nil || score > selectedNodeScore {
 				selectedNode = node
 				selectedNodeScore = score
-				if selectedNodeScore == schedulerobjects.SchedulableBestScore {
+				if selectedNodeScore == bestScore {
 					break
 				}
 			}
@@ -847,15 +1152,24 @@ func (nodeDb *NodeDb) selectNodeForJobWithFairPreemption(txn *memdb.Txn, jctx *s
 		nodesById[nodeId] = node
 		evictedJobSchedulingContextsByNodeId[nodeId] = append(evictedJobSchedulingContextsByNodeId[nodeId], evictedJobSchedulingContext)
 
-		matches, _, reason, err := schedulerobjects.PodRequirementsMet(
-			node.Taints,
-			node.Labels,
-			node.TotalResources,
-			node.AllocatableByPriority[evictedPriority],
-			jctx.PodRequirements,
-		)
-		if err != nil {
-			return nil, err
+		matches, reason := nodeDb.nodeReservationMet(node, jctx.PodRequirements)
+		if matches {
+			matches, reason = nodeDb.perNodeJobLimitsMet(node, jctx.PodRequirements.Priority, jctx.PodRequirements)
+		}
+		if matches {
+			matches, reason = numaCellRequirementsMet(node, jctx.PodRequirements.Priority, jctx.PodRequirements)
+		}
+		if matches {
+			matches, _, reason, err = schedulerobjects.PodRequirementsMet(
+				node.Taints,
+				node.Labels,
+				node.TotalResources,
+				node.AllocatableByPriority[evictedPriority],
+				jctx.PodRequirements,
+			)
+			if err != nil {
+				return nil, err
+			}
 		}
 		if matches {
 			selectedNode = node
@@ -911,6 +1225,15 @@ func bindJobToNodeInPlace(priorityClasses map[string]types.PriorityClass, job in
 		allocatedToQueue := node.AllocatedByQueue[queue]
 		allocatedToQueue.AddV1ResourceList(requests)
 		node.AllocatedByQueue[queue] = allocatedToQueue
+
+		if gangId := job.GetAnnotations()[configuration.GangIdAnnotation]; gangId != "" {
+			if node.GangIdByJobId == nil {
+				node.GangIdByJobId = make(map[string]string)
+			}
+			node.GangIdByJobId[jobId] = gangId
+		}
+
+		pinJobToNumaCell(job, node)
 	}
 
 	allocatable := node.AllocatableByPriority
@@ -1014,6 +1337,8 @@ func unbindJobFromNodeInPlace(priorityClasses map[string]types.PriorityClass, jo
 		return nil
 	} else {
 		delete(node.AllocatedByJobId, jobId)
+		delete(node.GangIdByJobId, jobId)
+		delete(node.NumaCellByJobId, jobId)
 	}
 
 	queue := job.GetQueue()
@@ -1192,6 +1517,40 @@ func nodeIndexName(keyIndex int) string {
 	return fmt.Sprintf("%d", keyIndex)
 }
 
+// NodeRejectionsWithTxn evaluates every node against req on its own, i.e., ignoring other jobs being scheduled alongside it,
+// with resources allocated to jobs of lower priority than req counted as available unless preemption is disabled.
+// Returns the number of nodes rejected for each category of reason, as returned by schedulerobjects.NodeRejectionCategory,
+// and the number of nodes evaluated. Unlike scheduling, this considers every node and is hence expensive for large pools.
+func (nodeDb *NodeDb) NodeRejectionsWithTxn(txn *memdb.Txn, req *schedulerobjects.PodRequirements) (map[string]int, int, error) {
+	priority := req.Priority
+	if nodeDb.preemptionDisabled {
+		priority = evictedPriority
+	}
+	it, err := NewNodesIterator(txn)
+	if err != nil {
+		return nil, 0, err
+	}
+	numRejectedNodesByReason := make(map[string]int)
+	numNodes := 0
+	for node := it.NextNode(); node != nil; node = it.NextNode() {
+		numNodes++
+		matches, reason := nodeDb.perNodeJobLimitsMet(node, priority, req)
+		if matches {
+			matches, reason = numaCellRequirementsMet(node, priority, req)
+		}
+		if matches {
+			matches, _, reason, err = schedulerobjects.PodRequirementsMet(node.Taints, node.Labels, node.TotalResources, node.AllocatableByPriority[priority], req)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+		if !matches {
+			numRejectedNodesByReason[schedulerobjects.NodeRejectionCategory(reason)]++
+		}
+	}
+	return numRejectedNodesByReason, numNodes, nil
+}
+
 // stringFromPodRequirementsNotMetReason returns the string representation of reason,
 // using a cache to avoid allocating new strings when possible.
 func (nodeDb *NodeDb) stringFromPodRequirementsNotMetReason(reason schedulerobjects.PodRequirementsNotMetReason) string {
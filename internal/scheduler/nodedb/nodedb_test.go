@@ -9,11 +9,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/armadaproject/armada/internal/armada/configuration"
 	armadamaps "github.com/armadaproject/armada/internal/common/maps"
+	armadaslices "github.com/armadaproject/armada/internal/common/slices"
 	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
 	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
 	"github.com/armadaproject/armada/internal/scheduler/interfaces"
@@ -120,6 +122,83 @@ func TestSelectNodeForPod_NodeIdLabel_Failure(t *testing.T) {
 	}
 }
 
+func TestSelectNodeForPod_PreferredNodeAffinity(t *testing.T) {
+	zoneTerm := func(weight int32, zone string) v1.PreferredSchedulingTerm {
+		return v1.PreferredSchedulingTerm{
+			Weight: weight,
+			Preference: v1.NodeSelectorTerm{
+				MatchExpressions: []v1.NodeSelectorRequirement{
+					{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{zone}},
+				},
+			},
+		}
+	}
+	tests := map[string]struct {
+		terms                                 []v1.PreferredSchedulingTerm
+		maxExtraNodesToConsiderForPreferences uint
+		// Zones of the nodes in the order the nodeDb iterates over them.
+		zones        []string
+		expectedZone string
+	}{
+		"no preferences": {
+			zones:        []string{"a", "b", "c"},
+			expectedZone: "a",
+		},
+		"preferred zone": {
+			terms:                                 []v1.PreferredSchedulingTerm{zoneTerm(10, "c")},
+			maxExtraNodesToConsiderForPreferences: 10,
+			zones:                                 []string{"a", "b", "c"},
+			expectedZone:                          "c",
+		},
+		"highest weight wins": {
+			terms:                                 []v1.PreferredSchedulingTerm{zoneTerm(10, "b"), zoneTerm(20, "c")},
+			maxExtraNodesToConsiderForPreferences: 10,
+			zones:                                 []string{"a", "b", "c"},
+			expectedZone:                          "c",
+		},
+		"preferred zone beyond maxExtraNodesToConsider": {
+			terms:        []v1.PreferredSchedulingTerm{zoneTerm(10, "c")},
+			zones:        []string{"a", "b", "c"},
+			expectedZone: "a",
+		},
+		"preferred zone unavailable": {
+			terms:                                 []v1.PreferredSchedulingTerm{zoneTerm(10, "d")},
+			maxExtraNodesToConsiderForPreferences: 10,
+			zones:                                 []string{"a", "b", "c"},
+			expectedZone:                          "a",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var nodes []*schedulerobjects.Node
+			for _, zone := range tc.zones {
+				nodes = append(nodes, testfixtures.WithLabelsNodes(
+					map[string]string{"zone": zone},
+					testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+				)...)
+			}
+			// Nodes with less available resources are considered first.
+			for i, node := range nodes {
+				schedulerobjects.AllocatableByPriorityAndResourceType(node.AllocatableByPriorityAndResource).MarkAllocated(
+					0, schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse(strconv.Itoa(len(nodes) - i))}},
+				)
+			}
+			db, err := newNodeDbWithNodes(nodes)
+			require.NoError(t, err)
+			db.SetMaxExtraNodesToConsiderForPreferences(tc.maxExtraNodesToConsiderForPreferences)
+
+			jobs := testfixtures.WithPreferredNodeAffinityJobs(tc.terms, testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 1))
+			jctxs := schedulercontext.JobSchedulingContextsFromJobs(testfixtures.TestPriorityClasses, jobs, func(_ map[string]string) (string, int, int, bool, error) { return "", 1, 1, true, nil })
+			txn := db.Txn(false)
+			node, err := db.SelectNodeForJobWithTxn(txn, jctxs[0])
+			txn.Abort()
+			require.NoError(t, err)
+			require.NotNil(t, node)
+			assert.Equal(t, tc.expectedZone, node.Labels["zone"])
+		})
+	}
+}
+
 func TestNodeBindingEvictionUnbinding(t *testing.T) {
 	node := testfixtures.Test8GpuNode(testfixtures.TestPriorities)
 	nodeDb, err := newNodeDbWithNodes([]*schedulerobjects.Node{node})
@@ -308,6 +387,429 @@ func TestEviction(t *testing.T) {
 	}
 }
 
+func TestColocationGroupLabelValues(t *testing.T) {
+	nodes := armadaslices.Concatenate(
+		testfixtures.WithLabelsNodes(map[string]string{"rack": "r1"}, testfixtures.N32CpuNodes(2, testfixtures.TestPriorities)),
+		testfixtures.WithLabelsNodes(map[string]string{"rack": "r2"}, testfixtures.N32CpuNodes(1, testfixtures.TestPriorities)),
+	)
+	jobsByNode := [][]*jobdb.Job{
+		testfixtures.WithAnnotationsJobs(
+			map[string]string{configuration.ColocationGroupAnnotation: "cache"},
+			testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 1),
+		),
+		testfixtures.WithAnnotationsJobs(
+			map[string]string{configuration.ColocationGroupAnnotation: "cache"},
+			testfixtures.N1Cpu4GiJobs("B", testfixtures.PriorityClass0, 1),
+		),
+		testfixtures.WithAnnotationsJobs(
+			map[string]string{configuration.ColocationGroupAnnotation: "cache"},
+			testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 1),
+		),
+	}
+	nodeDb, err := newNodeDbWithNodes(nil)
+	require.NoError(t, err)
+	txn := nodeDb.Txn(true)
+	for i, node := range nodes {
+		require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, jobsByNode[i], node))
+	}
+	txn.Commit()
+
+	tests := map[string]struct {
+		queue    string
+		group    string
+		label    string
+		expected []string
+	}{
+		"node id": {
+			queue:    "A",
+			group:    "cache",
+			label:    schedulerconfig.NodeIdLabel,
+			expected: []string{nodes[0].Id, nodes[2].Id},
+		},
+		"node label": {
+			queue:    "A",
+			group:    "cache",
+			label:    "rack",
+			expected: []string{"r1", "r2"},
+		},
+		"groups are per queue": {
+			queue:    "B",
+			group:    "cache",
+			label:    "rack",
+			expected: []string{"r1"},
+		},
+		"missing label": {
+			queue: "A",
+			group: "cache",
+			label: "zone",
+		},
+		"no running jobs": {
+			queue: "A",
+			group: "foo",
+			label: schedulerconfig.NodeIdLabel,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			values, err := nodeDb.ColocationGroupLabelValues(tc.queue, tc.group, tc.label)
+			require.NoError(t, err)
+			if tc.expected == nil {
+				assert.Empty(t, values)
+			} else {
+				slices.Sort(tc.expected)
+				assert.Equal(t, tc.expected, values)
+			}
+		})
+	}
+}
+
+func TestNodeReservedResources(t *testing.T) {
+	tests := map[string]struct {
+		reserved            map[string]resource.Quantity
+		reservedFractions   map[string]float64
+		expectedAllocatable schedulerobjects.ResourceList
+	}{
+		"none": {
+			expectedAllocatable: schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("32"), "memory": resource.MustParse("256Gi")}},
+		},
+		"absolute": {
+			reserved:            map[string]resource.Quantity{"cpu": resource.MustParse("1")},
+			expectedAllocatable: schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("31"), "memory": resource.MustParse("256Gi")}},
+		},
+		"fraction": {
+			reservedFractions:   map[string]float64{"memory": 0.25},
+			expectedAllocatable: schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("32"), "memory": resource.MustParse("192Gi")}},
+		},
+		"larger of absolute and fraction": {
+			reserved:            map[string]resource.Quantity{"cpu": resource.MustParse("4"), "memory": resource.MustParse("1Gi")},
+			reservedFractions:   map[string]float64{"cpu": 0.0625, "memory": 0.25},
+			expectedAllocatable: schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("28"), "memory": resource.MustParse("192Gi")}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodeDb, err := newNodeDbWithNodes(nil)
+			require.NoError(t, err)
+			nodeDb.SetNodeReservedResources(tc.reserved, tc.reservedFractions)
+			node := testfixtures.Test32CpuNode(testfixtures.TestPriorities)
+			txn := nodeDb.Txn(true)
+			require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, nil, node))
+			txn.Commit()
+
+			entry, err := nodeDb.GetNode(node.Id)
+			require.NoError(t, err)
+			assert.True(t, entry.TotalResources.Equal(node.TotalResources))
+			for priority, allocatable := range entry.AllocatableByPriority {
+				assert.True(
+					t, tc.expectedAllocatable.Equal(allocatable),
+					"at priority %d: expected %s, got %s", priority, tc.expectedAllocatable.CompactString(), allocatable.CompactString(),
+				)
+			}
+		})
+	}
+}
+
+func TestExternalWorkloadCoexistence(t *testing.T) {
+	nonArmadaAllocated := schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("4")}}
+	full := schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("32"), "memory": resource.MustParse("256Gi")}}
+	reduced := schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("28"), "memory": resource.MustParse("256Gi")}}
+	tests := map[string]struct {
+		enabled                       bool
+		expectedAllocatableByPriority map[int32]schedulerobjects.ResourceList
+	}{
+		"disabled": {
+			expectedAllocatableByPriority: map[int32]schedulerobjects.ResourceList{
+				evictedPriority: reduced,
+				0:               reduced,
+				1:               reduced,
+				2:               full,
+				3:               full,
+			},
+		},
+		"enabled": {
+			enabled: true,
+			expectedAllocatableByPriority: map[int32]schedulerobjects.ResourceList{
+				evictedPriority: reduced,
+				0:               reduced,
+				1:               reduced,
+				2:               reduced,
+				3:               reduced,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodeDb, err := newNodeDbWithNodes(nil)
+			require.NoError(t, err)
+			nodeDb.SetExternalWorkloadCoexistence(tc.enabled)
+			node := testfixtures.Test32CpuNode(testfixtures.TestPriorities)
+			node.NonArmadaAllocatedResources = map[int32]schedulerobjects.ResourceList{1: nonArmadaAllocated}
+			schedulerobjects.AllocatableByPriorityAndResourceType(node.AllocatableByPriorityAndResource).MarkAllocated(1, nonArmadaAllocated)
+			txn := nodeDb.Txn(true)
+			require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, nil, node))
+			txn.Commit()
+
+			entry, err := nodeDb.GetNode(node.Id)
+			require.NoError(t, err)
+			for priority, expected := range tc.expectedAllocatableByPriority {
+				allocatable := entry.AllocatableByPriority[priority]
+				assert.True(
+					t, expected.Equal(allocatable),
+					"at priority %d: expected %s, got %s", priority, expected.CompactString(), allocatable.CompactString(),
+				)
+			}
+		})
+	}
+}
+
+func TestDisablePreemption(t *testing.T) {
+	tests := map[string]struct {
+		preemptionDisabled bool
+		expectSuccess      bool
+	}{
+		"preemption enabled": {
+			expectSuccess: true,
+		},
+		"preemption disabled": {
+			preemptionDisabled: true,
+			expectSuccess:      false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodeDb, err := newNodeDbWithNodes(nil)
+			require.NoError(t, err)
+			if tc.preemptionDisabled {
+				nodeDb.DisablePreemption()
+			}
+			// Fill the node with a preemptible job.
+			node := testfixtures.Test32CpuNode(testfixtures.TestPriorities)
+			txn := nodeDb.Txn(true)
+			require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(
+				txn,
+				testfixtures.N32Cpu256GiJobs("A", testfixtures.PriorityClass0, 1),
+				node,
+			))
+			txn.Commit()
+
+			// A job of higher priority can only be scheduled by preempting the running job.
+			jctxs := schedulercontext.JobSchedulingContextsFromJobs(
+				testfixtures.TestPriorityClasses,
+				testfixtures.N1Cpu4GiJobs("B", testfixtures.PriorityClass3, 1),
+				func(_ map[string]string) (string, int, int, bool, error) { return "", 1, 1, true, nil },
+			)
+			ok, err := nodeDb.ScheduleMany(jctxs)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectSuccess, ok)
+		})
+	}
+}
+
+func TestPerNodeJobLimits(t *testing.T) {
+	tests := map[string]struct {
+		maxJobsPerNode        uint
+		maxGangMembersPerNode uint
+		// Each element is scheduled via a separate call to ScheduleMany.
+		gangs          [][]*jobdb.Job
+		expectSuccess  []bool
+		expectedReason string
+	}{
+		"no limits": {
+			gangs:         [][]*jobdb.Job{testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 5))},
+			expectSuccess: []bool{true},
+		},
+		"max jobs per node": {
+			maxJobsPerNode: 2,
+			gangs:          armadaslices.PartitionToMaxLen(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 5), 1),
+			expectSuccess:  []bool{true, true, true, true, false},
+			expectedReason: "node already runs the maximum of 2 jobs per node",
+		},
+		"gang spread across nodes": {
+			maxGangMembersPerNode: 1,
+			gangs:                 [][]*jobdb.Job{testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 2))},
+			expectSuccess:         []bool{true},
+		},
+		"gang larger than limit allows": {
+			maxGangMembersPerNode: 1,
+			gangs:                 [][]*jobdb.Job{testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 3))},
+			expectSuccess:         []bool{false},
+			expectedReason:        "node already runs the maximum of 1 members of the gang per node",
+		},
+		"limit applies per gang": {
+			maxGangMembersPerNode: 1,
+			gangs: [][]*jobdb.Job{
+				testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 2)),
+				testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 2)),
+			},
+			expectSuccess: []bool{true, true},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodeDb, err := newNodeDbWithNodes(testfixtures.N32CpuNodes(2, testfixtures.TestPriorities))
+			require.NoError(t, err)
+			nodeDb.SetPerNodeJobLimits(tc.maxJobsPerNode, tc.maxGangMembersPerNode)
+			for i, gang := range tc.gangs {
+				jctxs := schedulercontext.JobSchedulingContextsFromJobs(
+					testfixtures.TestPriorityClasses,
+					gang,
+					func(_ map[string]string) (string, int, int, bool, error) { return "", len(gang), len(gang), true, nil },
+				)
+				ok, err := nodeDb.ScheduleMany(jctxs)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectSuccess[i], ok, "gang %d", i)
+				if !ok && tc.expectedReason != "" {
+					unscheduled := armadaslices.Filter(jctxs, func(jctx *schedulercontext.JobSchedulingContext) bool {
+						return jctx.PodSchedulingContext != nil && jctx.PodSchedulingContext.NodeId == ""
+					})
+					require.NotEmpty(t, unscheduled)
+					assert.Contains(t, unscheduled[0].PodSchedulingContext.NumExcludedNodesByReason, tc.expectedReason)
+				}
+			}
+		})
+	}
+}
+
+func TestNodeReservations(t *testing.T) {
+	nodeDb, err := newNodeDbWithNodes(testfixtures.N32CpuNodes(3, testfixtures.TestPriorities))
+	require.NoError(t, err)
+	jctxsFromJobs := func(jobs []*jobdb.Job) []*schedulercontext.JobSchedulingContext {
+		return schedulercontext.JobSchedulingContextsFromJobs(
+			testfixtures.TestPriorityClasses,
+			jobs,
+			func(_ map[string]string) (string, int, int, bool, error) { return "", len(jobs), len(jobs), true, nil },
+		)
+	}
+
+	// A gang requiring 48 cpus needs two of the three nodes.
+	gang := testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 48))
+	gctx := schedulercontext.NewGangSchedulingContext(jctxsFromJobs(gang))
+	gangId := gctx.JobSchedulingContexts[0].PodRequirements.Annotations[configuration.GangIdAnnotation]
+	nodeIds, err := nodeDb.NodeIdsToReserveForGang(gctx)
+	require.NoError(t, err)
+	require.Len(t, nodeIds, 2)
+	gangIdByNodeId := make(map[string]string)
+	for _, nodeId := range nodeIds {
+		gangIdByNodeId[nodeId] = gangId
+	}
+	nodeDb.SetNodeReservations(gangIdByNodeId)
+
+	// Jobs not in the gang are only scheduled onto the node that isn't reserved.
+	jctxs := jctxsFromJobs(testfixtures.N1Cpu4GiJobs("B", testfixtures.PriorityClass0, 32))
+	ok, err := nodeDb.ScheduleMany(jctxs)
+	require.NoError(t, err)
+	require.True(t, ok)
+	for _, jctx := range jctxs {
+		assert.NotContains(t, gangIdByNodeId, jctx.PodSchedulingContext.NodeId)
+	}
+	jctxs = jctxsFromJobs(testfixtures.N1Cpu4GiJobs("B", testfixtures.PriorityClass0, 1))
+	ok, err = nodeDb.ScheduleMany(jctxs)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 2, jctxs[0].PodSchedulingContext.NumExcludedNodesByReason[fmt.Sprintf("node is reserved for gang %s", gangId)])
+
+	// Members of the gang may be scheduled onto reserved nodes.
+	ok, err = nodeDb.ScheduleMany(jctxsFromJobs(gang[:16]))
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Nodes reserved for other gangs aren't reserved again.
+	otherGang := testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 2))
+	nodeIds, err = nodeDb.NodeIdsToReserveForGang(schedulercontext.NewGangSchedulingContext(jctxsFromJobs(otherGang)))
+	require.NoError(t, err)
+	require.Len(t, nodeIds, 1)
+	assert.NotContains(t, gangIdByNodeId, nodeIds[0])
+}
+
+func TestNumaCellRequirements(t *testing.T) {
+	tests := map[string]struct {
+		// Number of NUMA cells each node is labelled with; if empty, nodes aren't labelled.
+		numaCells string
+		// Whether jobs require a single NUMA cell.
+		singleNumaCell bool
+		// Each job is scheduled via a separate call to ScheduleMany.
+		expectSuccess  []bool
+		expectedReason string
+		// Sorted NUMA cells of the jobs pinned to the node.
+		expectedNumaCells []int
+	}{
+		"no constraint": {
+			numaCells:     "2",
+			expectSuccess: []bool{true, true, true},
+		},
+		"jobs fit within a single cell": {
+			numaCells:         "2",
+			singleNumaCell:    true,
+			expectSuccess:     []bool{true, true, false},
+			expectedReason:    "pod requires a single NUMA cell, but doesn't fit within any of the 2 cells of the node",
+			expectedNumaCells: []int{0, 1},
+		},
+		"single cell": {
+			numaCells:         "1",
+			singleNumaCell:    true,
+			expectSuccess:     []bool{true, true, true},
+			expectedNumaCells: []int{0, 0, 0},
+		},
+		"unknown topology": {
+			singleNumaCell: true,
+			expectSuccess:  []bool{false, false, false},
+			expectedReason: "pod requires a single NUMA cell, but the NUMA topology of the node is unknown",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodeDb, err := NewNodeDb(
+				testfixtures.TestPriorityClasses,
+				testfixtures.TestMaxExtraNodesToConsider,
+				testfixtures.TestResources,
+				testfixtures.TestIndexedTaints,
+				testfixtures.TestIndexedNodeLabels,
+			)
+			require.NoError(t, err)
+			nodeDb.SetNumaCellsLabel("numaCells")
+			nodes := testfixtures.N32CpuNodes(1, testfixtures.TestPriorities)
+			if tc.numaCells != "" {
+				nodes = testfixtures.WithLabelsNodes(map[string]string{"numaCells": tc.numaCells}, nodes)
+			}
+			txn := nodeDb.Txn(true)
+			require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, nil, nodes[0]))
+			txn.Commit()
+
+			// Three jobs fit onto the node, but only one fits within each half of it.
+			jobs := testfixtures.WithRequestsJobs(
+				schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("10")}},
+				testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, len(tc.expectSuccess)),
+			)
+			if tc.singleNumaCell {
+				jobs = testfixtures.WithAnnotationsJobs(map[string]string{configuration.SingleNumaCellAnnotation: "true"}, jobs)
+			}
+			for i, job := range jobs {
+				jctxs := schedulercontext.JobSchedulingContextsFromJobs(
+					testfixtures.TestPriorityClasses,
+					[]*jobdb.Job{job},
+					func(_ map[string]string) (string, int, int, bool, error) { return "", 1, 1, true, nil },
+				)
+				ok, err := nodeDb.ScheduleMany(jctxs)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectSuccess[i], ok, "job %d", i)
+				if !ok && tc.expectedReason != "" {
+					assert.Contains(t, jctxs[0].PodSchedulingContext.NumExcludedNodesByReason, tc.expectedReason)
+				}
+			}
+
+			node, err := nodeDb.GetNode(nodes[0].Id)
+			require.NoError(t, err)
+			numaCells := maps.Values(node.NumaCellByJobId)
+			slices.Sort(numaCells)
+			if tc.expectedNumaCells == nil {
+				assert.Empty(t, numaCells)
+			} else {
+				assert.Equal(t, tc.expectedNumaCells, numaCells)
+			}
+		})
+	}
+}
+
 func TestScheduleIndividually(t *testing.T) {
 	tests := map[string]struct {
 		Nodes         []*schedulerobjects.Node
@@ -562,6 +1064,70 @@ func TestScheduleMany(t *testing.T) {
 	}
 }
 
+func TestScheduleMany_PartialPreemption(t *testing.T) {
+	tests := map[string]struct {
+		// Jobs making up the gang.
+		Jobs []*jobdb.Job
+		// Whether we expect scheduling the gang to succeed.
+		ExpectSuccess bool
+	}{
+		// The smaller job fits onto the free half of node1, whereas the larger job requires preempting the job on node2.
+		"some jobs require preemption": {
+			Jobs: append(
+				testfixtures.N32Cpu256GiJobs("B", testfixtures.PriorityClass3, 1),
+				testfixtures.N16Cpu128GiJobs("B", testfixtures.PriorityClass3, 1)...,
+			),
+			ExpectSuccess: true,
+		},
+		// Even with preemption, there's only room for one of the larger jobs.
+		"gang doesn't fit after preemption": {
+			Jobs: append(
+				testfixtures.N32Cpu256GiJobs("B", testfixtures.PriorityClass3, 2),
+				testfixtures.N16Cpu128GiJobs("B", testfixtures.PriorityClass3, 1)...,
+			),
+			ExpectSuccess: false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			nodeDb, err := newNodeDbWithNodes(nil)
+			require.NoError(t, err)
+			node1 := testfixtures.Test32CpuNode(testfixtures.TestPriorities)
+			node2 := testfixtures.Test32CpuNode(testfixtures.TestPriorities)
+			txn := nodeDb.Txn(true)
+			require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 1), node1))
+			require.NoError(t, nodeDb.CreateAndInsertWithJobDbJobsWithTxn(txn, testfixtures.N32Cpu256GiJobs("A", testfixtures.PriorityClass0, 1), node2))
+			txn.Commit()
+
+			jctxs := schedulercontext.JobSchedulingContextsFromJobs(
+				testfixtures.TestPriorityClasses,
+				testfixtures.WithGangAnnotationsJobs(tc.Jobs),
+				func(_ map[string]string) (string, int, int, bool, error) {
+					return "gang", len(tc.Jobs), len(tc.Jobs), true, nil
+				},
+			)
+			ok, err := nodeDb.ScheduleMany(jctxs)
+			require.NoError(t, err)
+			assert.Equal(t, tc.ExpectSuccess, ok)
+
+			if tc.ExpectSuccess {
+				largeJctx, smallJctx := jctxs[0], jctxs[1]
+				assert.Equal(t, node2.Id, largeJctx.PodSchedulingContext.NodeId)
+				assert.True(t, largeJctx.PodSchedulingContext.PreemptionRequired)
+				assert.Equal(t, node1.Id, smallJctx.PodSchedulingContext.NodeId)
+				assert.False(t, smallJctx.PodSchedulingContext.PreemptionRequired)
+			} else {
+				// None of the planned placements were committed.
+				for _, nodeId := range []string{node1.Id, node2.Id} {
+					node, err := nodeDb.GetNode(nodeId)
+					require.NoError(t, err)
+					assert.Len(t, node.AllocatedByJobId, 1)
+				}
+			}
+		})
+	}
+}
+
 func benchmarkUpsert(nodes []*schedulerobjects.Node, b *testing.B) {
 	nodeDb, err := NewNodeDb(
 		testfixtures.TestPriorityClasses,
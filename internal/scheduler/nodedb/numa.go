@@ -0,0 +1,105 @@
+package nodedb
+
+import (
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/interfaces"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// numaCellResources are the resources split across the NUMA cells of a node.
+var numaCellResources = []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory}
+
+// SetNumaCellsLabel sets the node label whose value is the number of NUMA cells of each node,
+// which is required to schedule jobs whose cpu and memory requests must fit within a single cell.
+// Only applies to nodes inserted after this call; hence, it should be called before any nodes are inserted.
+func (nodeDb *NodeDb) SetNumaCellsLabel(label string) {
+	nodeDb.numaCellsLabel = label
+}
+
+// numaCells returns the number of NUMA cells of a node with the given labels, or zero if unknown.
+func (nodeDb *NodeDb) numaCells(labels map[string]string) int {
+	if nodeDb.numaCellsLabel == "" {
+		return 0
+	}
+	cells, err := strconv.Atoi(labels[nodeDb.numaCellsLabel])
+	if err != nil || cells < 0 {
+		return 0
+	}
+	return cells
+}
+
+// requiresSingleNumaCell returns true if the cpu and memory requests of a job with the given annotations must fit within a single NUMA cell.
+func requiresSingleNumaCell(annotations map[string]string) bool {
+	return annotations[configuration.SingleNumaCellAnnotation] == "true"
+}
+
+// numaCellRequirementsMet returns true if a job with requirements req doesn't require a single NUMA cell,
+// or if it fits within a cell of node at the given priority, or the reason why not otherwise.
+// Jobs evicted from the node are only accounted for at evictedPriority, mirroring how their resources are accounted for.
+func numaCellRequirementsMet(node *Node, priority int32, req *schedulerobjects.PodRequirements) (bool, schedulerobjects.PodRequirementsNotMetReason) {
+	if !requiresSingleNumaCell(req.Annotations) {
+		return true, nil
+	}
+	if numaCellForRequests(node, req.ResourceRequirements.Requests, priority != evictedPriority) == -1 {
+		return false, &schedulerobjects.NumaCellRequirementsNotMet{NumaCells: node.NumaCells}
+	}
+	return true, nil
+}
+
+// numaCellForRequests returns the first NUMA cell of node within which requests fit, or -1 if there's no such cell.
+// Only the resources allocated to jobs pinned to a cell are accounted for against that cell.
+// If excludeEvicted is true, resources allocated to jobs evicted from the node are considered free.
+func numaCellForRequests(node *Node, requests v1.ResourceList, excludeEvicted bool) int {
+	if node.NumaCells <= 0 {
+		return -1
+	}
+	allocatedByCell := make([]schedulerobjects.ResourceList, node.NumaCells)
+	for jobId, cell := range node.NumaCellByJobId {
+		if cell < 0 || cell >= node.NumaCells || (excludeEvicted && node.EvictedJobRunIds[jobId]) {
+			continue
+		}
+		allocatedByCell[cell].Add(node.AllocatedByJobId[jobId])
+	}
+	for cell, allocated := range allocatedByCell {
+		fits := true
+		for _, t := range numaCellResources {
+			total := node.TotalResources.Get(string(t))
+			capacity := total.MilliValue() / int64(node.NumaCells)
+			used := allocated.Get(string(t))
+			request := requests[t]
+			if used.MilliValue()+request.MilliValue() > capacity {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			return cell
+		}
+	}
+	return -1
+}
+
+// pinJobToNumaCell records the NUMA cell of node job is pinned to, if the job requires a single cell.
+// Cells occupied by evicted jobs are avoided where possible, since those jobs may be re-scheduled.
+// Jobs that don't fit within any cell, e.g., running jobs bound to a node whose topology has since changed, aren't pinned.
+func pinJobToNumaCell(job interfaces.LegacySchedulerJob, node *Node) {
+	if node.NumaCells <= 0 || !requiresSingleNumaCell(job.GetAnnotations()) {
+		return
+	}
+	requests := job.GetResourceRequirements().Requests
+	cell := numaCellForRequests(node, requests, false)
+	if cell == -1 {
+		cell = numaCellForRequests(node, requests, true)
+	}
+	if cell == -1 {
+		return
+	}
+	if node.NumaCellByJobId == nil {
+		node.NumaCellByJobId = make(map[string]int)
+	}
+	node.NumaCellByJobId[job.GetId()] = cell
+}
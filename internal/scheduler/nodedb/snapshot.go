@@ -0,0 +1,269 @@
+package nodedb
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// Snapshot records the nodes of a NodeDb at a point in time, i.e., the capacity, labels, and taints of each node,
+// but not the jobs bound to them. Snapshots taken at different times can be compared using DiffSnapshots,
+// e.g., to find out why jobs that were scheduled previously no longer are.
+type Snapshot struct {
+	Created time.Time `json:"created"`
+	// Nodes indexed by id.
+	Nodes map[string]*NodeSnapshot `json:"nodes"`
+}
+
+type NodeSnapshot struct {
+	Id             string                        `json:"id"`
+	Name           string                        `json:"name"`
+	Executor       string                        `json:"executor"`
+	Labels         map[string]string             `json:"labels,omitempty"`
+	Taints         []v1.Taint                    `json:"taints,omitempty"`
+	TotalResources schedulerobjects.ResourceList `json:"totalResources"`
+}
+
+// NewSnapshot returns a snapshot of the nodes currently in the NodeDb.
+func (nodeDb *NodeDb) NewSnapshot(created time.Time) (*Snapshot, error) {
+	it, err := NewNodesIterator(nodeDb.Txn(false))
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{
+		Created: created,
+		Nodes:   make(map[string]*NodeSnapshot),
+	}
+	for node := it.NextNode(); node != nil; node = it.NextNode() {
+		snapshot.Nodes[node.Id] = &NodeSnapshot{
+			Id:             node.Id,
+			Name:           node.Name,
+			Executor:       node.Executor,
+			Labels:         maps.Clone(node.Labels),
+			Taints:         slices.Clone(node.Taints),
+			TotalResources: node.TotalResources.DeepCopy(),
+		}
+	}
+	return snapshot, nil
+}
+
+// SnapshotDiff is the difference between two snapshots, as returned by DiffSnapshots.
+type SnapshotDiff struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Nodes present only in the later snapshot, ordered by id.
+	AddedNodes []*NodeSnapshot `json:"addedNodes,omitempty"`
+	// Nodes present only in the earlier snapshot, ordered by id.
+	RemovedNodes []*NodeSnapshot `json:"removedNodes,omitempty"`
+	// Nodes present in both snapshots that differ between them, ordered by id.
+	ChangedNodes []*NodeDiff `json:"changedNodes,omitempty"`
+	// Change in the total resources of all nodes, for resource types that changed.
+	TotalResources map[string]ResourceChange `json:"totalResources,omitempty"`
+}
+
+// NodeDiff is the difference between two snapshots of the same node.
+type NodeDiff struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Executor string `json:"executor"`
+	// Resource types for which the capacity of the node changed.
+	TotalResources map[string]ResourceChange `json:"totalResources,omitempty"`
+	// Labels that were added to, removed from, or changed value on the node.
+	LabelsAdded   map[string]string      `json:"labelsAdded,omitempty"`
+	LabelsRemoved map[string]string      `json:"labelsRemoved,omitempty"`
+	LabelsChanged map[string]LabelChange `json:"labelsChanged,omitempty"`
+	TaintsAdded   []v1.Taint             `json:"taintsAdded,omitempty"`
+	TaintsRemoved []v1.Taint             `json:"taintsRemoved,omitempty"`
+}
+
+type ResourceChange struct {
+	From resource.Quantity `json:"from"`
+	To   resource.Quantity `json:"to"`
+}
+
+type LabelChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// IsEmpty returns true if the snapshots compared were identical.
+func (diff *SnapshotDiff) IsEmpty() bool {
+	return len(diff.AddedNodes) == 0 && len(diff.RemovedNodes) == 0 && len(diff.ChangedNodes) == 0
+}
+
+// DiffSnapshots returns the nodes added, removed, and changed between snapshot a and the later snapshot b.
+func DiffSnapshots(a, b *Snapshot) *SnapshotDiff {
+	diff := &SnapshotDiff{
+		From: a.Created,
+		To:   b.Created,
+	}
+	var totalA, totalB schedulerobjects.ResourceList
+	for _, nodeId := range sortedNodeIds(a, b) {
+		nodeA, nodeB := a.Nodes[nodeId], b.Nodes[nodeId]
+		if nodeA != nil {
+			totalA.Add(nodeA.TotalResources)
+		}
+		if nodeB != nil {
+			totalB.Add(nodeB.TotalResources)
+		}
+		if nodeA == nil {
+			diff.AddedNodes = append(diff.AddedNodes, nodeB)
+		} else if nodeB == nil {
+			diff.RemovedNodes = append(diff.RemovedNodes, nodeA)
+		} else if nodeDiff := diffNodeSnapshots(nodeA, nodeB); nodeDiff != nil {
+			diff.ChangedNodes = append(diff.ChangedNodes, nodeDiff)
+		}
+	}
+	diff.TotalResources = diffResourceLists(totalA, totalB)
+	return diff
+}
+
+func sortedNodeIds(a, b *Snapshot) []string {
+	nodeIds := maps.Keys(a.Nodes)
+	for nodeId := range b.Nodes {
+		if _, ok := a.Nodes[nodeId]; !ok {
+			nodeIds = append(nodeIds, nodeId)
+		}
+	}
+	slices.Sort(nodeIds)
+	return nodeIds
+}
+
+// diffNodeSnapshots returns the difference between two snapshots of the same node, or nil if they're identical.
+func diffNodeSnapshots(a, b *NodeSnapshot) *NodeDiff {
+	diff := &NodeDiff{
+		Id:             b.Id,
+		Name:           b.Name,
+		Executor:       b.Executor,
+		TotalResources: diffResourceLists(a.TotalResources, b.TotalResources),
+	}
+	for key, valueA := range a.Labels {
+		if valueB, ok := b.Labels[key]; !ok {
+			if diff.LabelsRemoved == nil {
+				diff.LabelsRemoved = make(map[string]string)
+			}
+			diff.LabelsRemoved[key] = valueA
+		} else if valueA != valueB {
+			if diff.LabelsChanged == nil {
+				diff.LabelsChanged = make(map[string]LabelChange)
+			}
+			diff.LabelsChanged[key] = LabelChange{From: valueA, To: valueB}
+		}
+	}
+	for key, valueB := range b.Labels {
+		if _, ok := a.Labels[key]; !ok {
+			if diff.LabelsAdded == nil {
+				diff.LabelsAdded = make(map[string]string)
+			}
+			diff.LabelsAdded[key] = valueB
+		}
+	}
+	diff.TaintsAdded = taintsNotIn(b.Taints, a.Taints)
+	diff.TaintsRemoved = taintsNotIn(a.Taints, b.Taints)
+	if len(diff.TotalResources) == 0 &&
+		len(diff.LabelsAdded) == 0 &&
+		len(diff.LabelsRemoved) == 0 &&
+		len(diff.LabelsChanged) == 0 &&
+		len(diff.TaintsAdded) == 0 &&
+		len(diff.TaintsRemoved) == 0 {
+		return nil
+	}
+	return diff
+}
+
+// diffResourceLists returns the resource types for which the amount differs between a and b.
+func diffResourceLists(a, b schedulerobjects.ResourceList) map[string]ResourceChange {
+	var rv map[string]ResourceChange
+	resourceTypes := maps.Keys(a.Resources)
+	for t := range b.Resources {
+		if _, ok := a.Resources[t]; !ok {
+			resourceTypes = append(resourceTypes, t)
+		}
+	}
+	for _, t := range resourceTypes {
+		qa, qb := a.Get(t), b.Get(t)
+		if qa.Cmp(qb) == 0 {
+			continue
+		}
+		if rv == nil {
+			rv = make(map[string]ResourceChange)
+		}
+		rv[t] = ResourceChange{From: qa, To: qb}
+	}
+	return rv
+}
+
+// taintsNotIn returns the taints in a that aren't in b.
+func taintsNotIn(a, b []v1.Taint) []v1.Taint {
+	var rv []v1.Taint
+	for _, taint := range a {
+		if slices.IndexFunc(b, func(other v1.Taint) bool {
+			return taint.Key == other.Key && taint.Value == other.Value && taint.Effect == other.Effect
+		}) == -1 {
+			rv = append(rv, taint)
+		}
+	}
+	return rv
+}
+
+// String returns a human-readable summary of the diff.
+func (diff *SnapshotDiff) String() string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 1, 1, 1, ' ', 0)
+	fmt.Fprintf(w, "From:\t%s\n", diff.From.Format(time.RFC3339))
+	fmt.Fprintf(w, "To:\t%s\n", diff.To.Format(time.RFC3339))
+	if diff.IsEmpty() {
+		fmt.Fprint(w, "No changes\n")
+		w.Flush()
+		return sb.String()
+	}
+	for _, t := range sortedKeys(diff.TotalResources) {
+		change := diff.TotalResources[t]
+		fmt.Fprintf(w, "Total %s:\t%s -> %s\n", t, change.From.String(), change.To.String())
+	}
+	for _, node := range diff.AddedNodes {
+		fmt.Fprintf(w, "Added node %s:\texecutor %s, %s\n", node.Id, node.Executor, node.TotalResources.CompactString())
+	}
+	for _, node := range diff.RemovedNodes {
+		fmt.Fprintf(w, "Removed node %s:\texecutor %s, %s\n", node.Id, node.Executor, node.TotalResources.CompactString())
+	}
+	for _, node := range diff.ChangedNodes {
+		fmt.Fprintf(w, "Changed node %s:\texecutor %s\n", node.Id, node.Executor)
+		for _, t := range sortedKeys(node.TotalResources) {
+			change := node.TotalResources[t]
+			fmt.Fprintf(w, "\t%s: %s -> %s\n", t, change.From.String(), change.To.String())
+		}
+		for _, key := range sortedKeys(node.LabelsAdded) {
+			fmt.Fprintf(w, "\tlabel added: %s=%s\n", key, node.LabelsAdded[key])
+		}
+		for _, key := range sortedKeys(node.LabelsRemoved) {
+			fmt.Fprintf(w, "\tlabel removed: %s=%s\n", key, node.LabelsRemoved[key])
+		}
+		for _, key := range sortedKeys(node.LabelsChanged) {
+			change := node.LabelsChanged[key]
+			fmt.Fprintf(w, "\tlabel changed: %s=%s -> %s=%s\n", key, change.From, key, change.To)
+		}
+		for _, taint := range node.TaintsAdded {
+			fmt.Fprintf(w, "\ttaint added: %s\n", taint.ToString())
+		}
+		for _, taint := range node.TaintsRemoved {
+			fmt.Fprintf(w, "\ttaint removed: %s\n", taint.ToString())
+		}
+	}
+	w.Flush()
+	return sb.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
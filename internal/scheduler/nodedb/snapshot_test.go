@@ -0,0 +1,97 @@
+package nodedb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	unchangedNode := testfixtures.Test32CpuNode(testfixtures.TestPriorities)
+	removedNode := testfixtures.Test32CpuNode(testfixtures.TestPriorities)
+	changedNode := testfixtures.Test32CpuNode(testfixtures.TestPriorities)
+	changedNode.Labels["zone"] = "a"
+	changedNode.Labels["rack"] = "1"
+	before := snapshotOf(t, testfixtures.BaseTime, unchangedNode, removedNode, changedNode)
+
+	changedNode = changedNode.DeepCopy()
+	changedNode.TotalResources.Set("cpu", resource.MustParse("16"))
+	changedNode.AllocatableByPriorityAndResource = schedulerobjects.NewAllocatableByPriorityAndResourceType(
+		testfixtures.TestPriorities,
+		changedNode.TotalResources,
+	)
+	changedNode.Labels["zone"] = "b"
+	delete(changedNode.Labels, "rack")
+	changedNode.Labels["gpu"] = "true"
+	changedNode.Taints = []v1.Taint{{Key: "gpu", Value: "true", Effect: v1.TaintEffectNoSchedule}}
+	addedNode := testfixtures.Test8GpuNode(testfixtures.TestPriorities)
+	after := snapshotOf(t, testfixtures.BaseTime.Add(time.Hour), unchangedNode, changedNode, addedNode)
+
+	diff := DiffSnapshots(before, after)
+	assert.False(t, diff.IsEmpty())
+	assert.Equal(t, testfixtures.BaseTime, diff.From)
+	assert.Equal(t, testfixtures.BaseTime.Add(time.Hour), diff.To)
+	if assert.Len(t, diff.AddedNodes, 1) {
+		assert.Equal(t, addedNode.Id, diff.AddedNodes[0].Id)
+	}
+	if assert.Len(t, diff.RemovedNodes, 1) {
+		assert.Equal(t, removedNode.Id, diff.RemovedNodes[0].Id)
+	}
+	if assert.Len(t, diff.ChangedNodes, 1) {
+		nodeDiff := diff.ChangedNodes[0]
+		assert.Equal(t, changedNode.Id, nodeDiff.Id)
+		assert.Equal(
+			t,
+			map[string]ResourceChange{"cpu": {From: resource.MustParse("32"), To: resource.MustParse("16")}},
+			nodeDiff.TotalResources,
+		)
+		assert.Equal(t, map[string]string{"gpu": "true"}, nodeDiff.LabelsAdded)
+		assert.Equal(t, map[string]string{"rack": "1"}, nodeDiff.LabelsRemoved)
+		assert.Equal(t, map[string]LabelChange{"zone": {From: "a", To: "b"}}, nodeDiff.LabelsChanged)
+		assert.Equal(t, changedNode.Taints, nodeDiff.TaintsAdded)
+		assert.Empty(t, nodeDiff.TaintsRemoved)
+	}
+	// 32 + 32 + 32 cpu before and 32 + 16 + 64 after.
+	cpu := diff.TotalResources["cpu"]
+	assert.Equal(t, 0, cpu.From.Cmp(resource.MustParse("96")))
+	assert.Equal(t, 0, cpu.To.Cmp(resource.MustParse("112")))
+	assert.NotEmpty(t, diff.String())
+
+	assert.True(t, DiffSnapshots(after, after).IsEmpty())
+}
+
+func TestNewSnapshot_IgnoresAllocatedResources(t *testing.T) {
+	node := testfixtures.Test32CpuNode(testfixtures.TestPriorities)
+	nodeDb, err := newNodeDbWithNodes([]*schedulerobjects.Node{node})
+	require.NoError(t, err)
+	before, err := nodeDb.NewSnapshot(testfixtures.BaseTime)
+	require.NoError(t, err)
+
+	job := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0)
+	txn := nodeDb.Txn(true)
+	dbNode, err := nodeDb.GetNodeWithTxn(txn, node.Id)
+	require.NoError(t, err)
+	dbNode, err = bindJobToNode(testfixtures.TestPriorityClasses, job, dbNode)
+	require.NoError(t, err)
+	require.NoError(t, nodeDb.UpsertWithTxn(txn, dbNode))
+	txn.Commit()
+
+	after, err := nodeDb.NewSnapshot(testfixtures.BaseTime.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, DiffSnapshots(before, after).IsEmpty())
+}
+
+func snapshotOf(t *testing.T, created time.Time, nodes ...*schedulerobjects.Node) *Snapshot {
+	nodeDb, err := newNodeDbWithNodes(nodes)
+	require.NoError(t, err)
+	snapshot, err := nodeDb.NewSnapshot(created)
+	require.NoError(t, err)
+	return snapshot
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/gogo/protobuf/proto"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/clock"
 
 	"github.com/armadaproject/armada/internal/armada/configuration"
@@ -32,18 +33,22 @@ type executor struct {
 }
 
 type DefaultPoolAssigner struct {
-	executorTimeout        time.Duration
-	priorityClasses        map[string]types.PriorityClass
-	priorities             []int32
-	indexedResources       []configuration.IndexedResource
-	indexedTaints          []string
-	indexedNodeLabels      []string
-	poolByExecutorId       map[string]string
-	executorsByPool        map[string][]*executor
-	executorRepository     database.ExecutorRepository
-	schedulingKeyGenerator *schedulerobjects.SchedulingKeyGenerator
-	poolCache              *lru.Cache
-	clock                  clock.Clock
+	executorTimeout               time.Duration
+	priorityClasses               map[string]types.PriorityClass
+	priorities                    []int32
+	indexedResources              []configuration.IndexedResource
+	indexedTaints                 []string
+	indexedNodeLabels             []string
+	nodeReservedResources         map[string]resource.Quantity
+	nodeReservedResourceFractions map[string]float64
+	externalWorkloadCoexistence   bool
+	minimumJobSizeByPool          map[string]map[string]resource.Quantity
+	poolByExecutorId              map[string]string
+	executorsByPool               map[string][]*executor
+	executorRepository            database.ExecutorRepository
+	schedulingKeyGenerator        *schedulerobjects.SchedulingKeyGenerator
+	poolCache                     *lru.Cache
+	clock                         clock.Clock
 }
 
 func NewPoolAssigner(executorTimeout time.Duration,
@@ -55,18 +60,22 @@ func NewPoolAssigner(executorTimeout time.Duration,
 		return nil, errors.Wrap(err, "error  creating PoolAssigner pool cache")
 	}
 	return &DefaultPoolAssigner{
-		executorTimeout:        executorTimeout,
-		priorityClasses:        schedulingConfig.Preemption.PriorityClasses,
-		executorsByPool:        map[string][]*executor{},
-		poolByExecutorId:       map[string]string{},
-		priorities:             schedulingConfig.Preemption.AllowedPriorities(),
-		indexedResources:       schedulingConfig.IndexedResources,
-		indexedTaints:          schedulingConfig.IndexedTaints,
-		indexedNodeLabels:      schedulingConfig.IndexedNodeLabels,
-		executorRepository:     executorRepository,
-		schedulingKeyGenerator: schedulerobjects.NewSchedulingKeyGenerator(),
-		poolCache:              poolCache,
-		clock:                  clock.RealClock{},
+		executorTimeout:               executorTimeout,
+		priorityClasses:               schedulingConfig.Preemption.PriorityClasses,
+		executorsByPool:               map[string][]*executor{},
+		poolByExecutorId:              map[string]string{},
+		priorities:                    schedulingConfig.Preemption.AllowedPriorities(),
+		indexedResources:              schedulingConfig.IndexedResources,
+		indexedTaints:                 schedulingConfig.IndexedTaints,
+		indexedNodeLabels:             schedulingConfig.IndexedNodeLabels,
+		nodeReservedResources:         schedulingConfig.NodeReservedResources,
+		nodeReservedResourceFractions: schedulingConfig.NodeReservedResourceFractions,
+		externalWorkloadCoexistence:   schedulingConfig.EnableExternalWorkloadCoexistence,
+		minimumJobSizeByPool:          schedulingConfig.MinimumJobSizeByPool,
+		executorRepository:            executorRepository,
+		schedulingKeyGenerator:        schedulerobjects.NewSchedulingKeyGenerator(),
+		poolCache:                     poolCache,
+		clock:                         clock.RealClock{},
 	}, nil
 }
 
@@ -126,10 +135,14 @@ func (p *DefaultPoolAssigner) AssignPool(j *jobdb.Job) (string, error) {
 
 	// Otherwise iterate through each pool and detect the first one the job is potentially schedulable on.
 	// TODO: We should use the real scheduler instead since this check may go out of sync with the scheduler.
+	requests := schedulerobjects.ResourceListFromV1ResourceList(req.GetResourceRequirements().Requests)
 	for pool, executors := range p.executorsByPool {
+		poolMinimumJobSize := schedulerobjects.ResourceList{Resources: p.minimumJobSizeByPool[pool]}
+		if ok, _ := constraints.RequestsMeetPoolMinimum(requests, poolMinimumJobSize); !ok {
+			continue
+		}
 		for _, e := range executors {
-			requests := req.GetResourceRequirements().Requests
-			if ok, _ := constraints.RequestsAreLargeEnough(schedulerobjects.ResourceListFromV1ResourceList(requests), e.minimumJobSize); !ok {
+			if ok, _ := constraints.RequestsAreLargeEnough(requests, e.minimumJobSize); !ok {
 				continue
 			}
 			nodeDb := e.nodeDb
@@ -167,6 +180,8 @@ func (p *DefaultPoolAssigner) constructNodeDb(nodes []*schedulerobjects.Node) (*
 	if err != nil {
 		return nil, err
 	}
+	nodeDb.SetNodeReservedResources(p.nodeReservedResources, p.nodeReservedResourceFractions)
+	nodeDb.SetExternalWorkloadCoexistence(p.externalWorkloadCoexistence)
 	txn := nodeDb.Txn(true)
 	defer txn.Abort()
 	for _, node := range nodes {
@@ -50,6 +50,14 @@ type PreemptingQueueScheduler struct {
 	enableAssertions bool
 	// If true, a newer preemption strategy is used.
 	enableNewPreemptionStrategy bool
+	// If true, the nodes rejecting the jobs of gangs that don't fit are counted by reason.
+	enableNodeRejectionDiagnostics bool
+	// Maximum number of node uniformity label values the gang scheduler makes scheduling attempts for per gang.
+	maxNodeUniformityLabelValuesToConsider uint
+	// If not nil, used to choose between candidate placements of gangs.
+	gangPlacementScorer GangPlacementScorer
+	// Used to choose between candidate placements of gangs with equal score.
+	gangPlacementTieBreak configuration.GangPlacementTieBreak
 }
 
 func NewPreemptingQueueScheduler(
@@ -104,6 +112,22 @@ func (sch *PreemptingQueueScheduler) EnableNewPreemptionStrategy() {
 	sch.nodeDb.EnableNewPreemptionStrategy()
 }
 
+func (sch *PreemptingQueueScheduler) EnableNodeRejectionDiagnostics() {
+	sch.enableNodeRejectionDiagnostics = true
+}
+
+func (sch *PreemptingQueueScheduler) SetMaxNodeUniformityLabelValuesToConsider(n uint) {
+	sch.maxNodeUniformityLabelValuesToConsider = n
+}
+
+func (sch *PreemptingQueueScheduler) SetGangPlacementScorer(placementScorer GangPlacementScorer) {
+	sch.gangPlacementScorer = placementScorer
+}
+
+func (sch *PreemptingQueueScheduler) SetGangPlacementTieBreak(tieBreak configuration.GangPlacementTieBreak) {
+	sch.gangPlacementTieBreak = tieBreak
+}
+
 // Schedule
 // - preempts jobs belonging to queues with total allocation above their fair share and
 // - schedules new jobs belonging to queues with total allocation less than their fair share.
@@ -114,6 +138,7 @@ func (sch *PreemptingQueueScheduler) Schedule(ctx *armadacontext.Context) (*Sche
 
 	preemptedJobsById := make(map[string]interfaces.LegacySchedulerJob)
 	scheduledJobsById := make(map[string]interfaces.LegacySchedulerJob)
+	preemptionCauseByJobId := make(map[string]PreemptionCause)
 
 	// NodeDb snapshot prior to making any changes.
 	// We compare against this snapshot after scheduling to detect changes.
@@ -127,7 +152,7 @@ func (sch *PreemptingQueueScheduler) Schedule(ctx *armadacontext.Context) (*Sche
 			sch.jobRepo,
 			sch.schedulingContext.PriorityClasses,
 			sch.nodeEvictionProbability,
-			func(ctx *armadacontext.Context, job interfaces.LegacySchedulerJob) bool {
+			sch.gangJobFilter(func(ctx *armadacontext.Context, job interfaces.LegacySchedulerJob) bool {
 				if job.GetAnnotations() == nil {
 					ctx.Errorf("can't evict job %s: annotations not initialised", job.GetId())
 					return false
@@ -137,18 +162,20 @@ func (sch *PreemptingQueueScheduler) Schedule(ctx *armadacontext.Context) (*Sche
 					return false
 				}
 				if qctx, ok := sch.schedulingContext.QueueSchedulingContexts[job.GetQueue()]; ok {
-					fairShare := qctx.Weight / sch.schedulingContext.WeightSum
-					actualShare := sch.schedulingContext.FairnessCostProvider.CostFromQueue(qctx) / totalCost
-					fractionOfFairShare := actualShare / fairShare
+					fractionOfFairShare := fractionOfFairShare(sch.schedulingContext, qctx, totalCost)
 					if fractionOfFairShare <= sch.protectedFractionOfFairShare {
 						return false
 					}
+					// Jobs opting out of preemption aren't evicted to balance resource usage while their queue is within its fair share.
+					if schedulingAnnotations, _ := schedulercontext.ParseSchedulingAnnotations(job.GetAnnotations()); schedulingAnnotations.NonPreemptible && fractionOfFairShare <= 1 {
+						return false
+					}
 				}
 				if priorityClass, ok := sch.schedulingContext.PriorityClasses[job.GetPriorityClassName()]; ok {
 					return priorityClass.Preemptible
 				}
 				return false
-			},
+			}),
 			nil,
 		),
 	)
@@ -157,6 +184,9 @@ func (sch *PreemptingQueueScheduler) Schedule(ctx *armadacontext.Context) (*Sche
 	}
 	maps.Copy(preemptedJobsById, evictorResult.EvictedJobsById)
 	maps.Copy(sch.nodeIdByJobId, evictorResult.NodeIdByJobId)
+	for jobId := range evictorResult.EvictedJobsById {
+		preemptionCauseByJobId[jobId] = PreemptionCauseFairShare
+	}
 
 	// Re-schedule evicted jobs/schedule new jobs.
 	schedulerResult, err := sch.schedule(
@@ -202,6 +232,7 @@ func (sch *PreemptingQueueScheduler) Schedule(ctx *armadacontext.Context) (*Sche
 			delete(scheduledJobsById, jobId)
 		} else {
 			preemptedJobsById[jobId] = job
+			preemptionCauseByJobId[jobId] = PreemptionCauseNodeOversubscribed
 		}
 	}
 	maps.Copy(sch.nodeIdByJobId, evictorResult.NodeIdByJobId)
@@ -258,12 +289,24 @@ func (sch *PreemptingQueueScheduler) Schedule(ctx *armadacontext.Context) (*Sche
 			return nil, err
 		}
 	}
+	preemptionByJobId, err := preemptions(
+		sch.schedulingContext,
+		preemptedJobs,
+		scheduledJobs,
+		preemptionCauseByJobId,
+		sch.nodeIdByJobId,
+	)
+	if err != nil {
+		return nil, err
+	}
 	return &SchedulerResult{
-		PreemptedJobs:      preemptedJobs,
-		ScheduledJobs:      scheduledJobs,
-		FailedJobs:         schedulerResult.FailedJobs,
-		NodeIdByJobId:      sch.nodeIdByJobId,
-		SchedulingContexts: []*schedulercontext.SchedulingContext{sch.schedulingContext},
+		PreemptedJobs:        preemptedJobs,
+		ScheduledJobs:        scheduledJobs,
+		FailedJobs:           schedulerResult.FailedJobs,
+		FailureReasonByJobId: schedulerResult.FailureReasonByJobId,
+		NodeIdByJobId:        sch.nodeIdByJobId,
+		PreemptionByJobId:    preemptionByJobId,
+		SchedulingContexts:   []*schedulercontext.SchedulingContext{sch.schedulingContext},
 	}, nil
 }
 
@@ -331,6 +374,40 @@ func (sch *PreemptingQueueScheduler) evict(ctx *armadacontext.Context, evictor *
 	return result, inMemoryJobRepo, nil
 }
 
+// gangJobFilter returns a job filter under which gang jobs pass only if all jobs of their gang pass jobFilter,
+// i.e., gangs are selected for eviction as a whole: if any job of a gang is exempt from eviction, so is the whole gang.
+// Gangs any job of which is evicted are subsequently evicted in full by evictGangs.
+func (sch *PreemptingQueueScheduler) gangJobFilter(
+	jobFilter func(*armadacontext.Context, interfaces.LegacySchedulerJob) bool,
+) func(*armadacontext.Context, interfaces.LegacySchedulerJob) bool {
+	passesByGangId := make(map[string]bool)
+	return func(ctx *armadacontext.Context, job interfaces.LegacySchedulerJob) bool {
+		gangId, ok := sch.gangIdByJobId[job.GetId()]
+		if !ok {
+			return jobFilter(ctx, job)
+		}
+		if passes, ok := passesByGangId[gangId]; ok {
+			return passes
+		}
+		gangJobs, err := sch.jobRepo.GetExistingJobsByIds(maps.Keys(sch.jobIdsByGangId[gangId]))
+		if err != nil {
+			ctx.Errorf("can't evict job %s: failed to get the jobs of gang %s: %s", job.GetId(), gangId, err)
+			return false
+		}
+		passes := jobFilter(ctx, job)
+		for _, gangJob := range gangJobs {
+			if !passes {
+				break
+			}
+			if gangJob.GetId() != job.GetId() {
+				passes = jobFilter(ctx, gangJob)
+			}
+		}
+		passesByGangId[gangId] = passes
+		return passes
+	}
+}
+
 // When evicting jobs, gangs may have been partially evicted.
 // Here, we evict all jobs in any gang for which at least one job was already evicted.
 func (sch *PreemptingQueueScheduler) evictGangs(ctx *armadacontext.Context, txn *memdb.Txn, previousEvictorResult *EvictorResult) (*EvictorResult, error) {
@@ -409,6 +486,8 @@ func (sch *PreemptingQueueScheduler) collectIdsForGangEviction(evictedJobsById m
 // Some jobs in a gang may have terminated since the gang was scheduled.
 // For these gangs, we need to set the gang cardinality to the number of jobs in the gang yet to terminate.
 // Otherwise the evicted gang jobs will not be schedulable, since some gang jobs will be considered missing.
+// The minimum cardinality of evicted gangs is set to the same value, since gangs are preempted atomically:
+// an evicted gang is re-scheduled in full or not at all, such that no job of a running gang is preempted without the others.
 func (sch *PreemptingQueueScheduler) setEvictedGangCardinality(evictedJobsById map[string]interfaces.LegacySchedulerJob) error {
 	for _, job := range evictedJobsById {
 		gangId, ok := sch.gangIdByJobId[job.GetId()]
@@ -422,6 +501,7 @@ func (sch *PreemptingQueueScheduler) setEvictedGangCardinality(evictedJobsById m
 			return errors.Errorf("error setting gang cardinality for job %s: annotations not initialised", job.GetId())
 		}
 		annotations[configuration.GangCardinalityAnnotation] = fmt.Sprintf("%d", gangCardinality)
+		annotations[configuration.GangMinimumCardinalityAnnotation] = fmt.Sprintf("%d", gangCardinality)
 	}
 	return nil
 }
@@ -525,9 +605,11 @@ func addEvictedJobsToNodeDb(ctx *armadacontext.Context, sctx *schedulercontext.S
 					return err
 				}
 				i++
+				// The jobs of cross-queue gangs are allocated to the queue each job is submitted to.
+				q := qr.queues[jctx.Job.GetQueue()]
+				q.allocation.AddV1ResourceList(jctx.PodRequirements.ResourceRequirements.Requests)
+				qr.queues[jctx.Job.GetQueue()] = q
 			}
-			q := qr.queues[gctx.Queue]
-			q.allocation.Add(gctx.TotalResourceRequests)
 		}
 		if err := candidateGangIterator.Clear(); err != nil {
 			return err
@@ -544,7 +626,7 @@ func (sch *PreemptingQueueScheduler) schedule(ctx *armadacontext.Context, inMemo
 		if err != nil {
 			return nil, err
 		}
-		queueIt, err := NewQueuedJobsIterator(ctx, qctx.Queue, jobRepo)
+		queueIt, err := NewQueuedJobsIterator(ctx, qctx.Queue, NewIntraQueueOrderingJobRepository(jobRepo, qctx.IntraQueueOrdering))
 		if err != nil {
 			return nil, err
 		}
@@ -566,6 +648,14 @@ func (sch *PreemptingQueueScheduler) schedule(ctx *armadacontext.Context, inMemo
 	if sch.skipUnsuccessfulSchedulingKeyCheck {
 		sched.SkipUnsuccessfulSchedulingKeyCheck()
 	}
+	if sch.enableNodeRejectionDiagnostics {
+		sched.EnableNodeRejectionDiagnostics()
+	}
+	sched.SetMaxNodeUniformityLabelValuesToConsider(sch.maxNodeUniformityLabelValuesToConsider)
+	if sch.gangPlacementScorer != nil {
+		sched.SetGangPlacementScorer(sch.gangPlacementScorer)
+	}
+	sched.SetGangPlacementTieBreak(sch.gangPlacementTieBreak)
 	result, err := sched.Schedule(ctx)
 	if err != nil {
 		return nil, err
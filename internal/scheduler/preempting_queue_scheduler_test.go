@@ -70,6 +70,38 @@ func TestEvictOversubscribed(t *testing.T) {
 	}
 }
 
+func TestGangJobFilter(t *testing.T) {
+	gang := testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 3))
+	exemptGang := testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 2))
+	notGangJob := testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 1)[0]
+	jobRepo := NewInMemoryJobRepository()
+	sch := &PreemptingQueueScheduler{
+		jobRepo:        jobRepo,
+		jobIdsByGangId: make(map[string]map[string]bool),
+		gangIdByJobId:  make(map[string]string),
+	}
+	var gangJobs []interfaces.LegacySchedulerJob
+	for _, job := range append(slices.Clone(gang), exemptGang...) {
+		jobRepo.Enqueue(job)
+		gangJobs = append(gangJobs, job)
+	}
+	jobRepo.Enqueue(notGangJob)
+	require.NoError(t, sch.updateGangAccounting(nil, gangJobs))
+
+	// A single job of exemptGang is exempt from eviction.
+	filter := sch.gangJobFilter(func(_ *armadacontext.Context, job interfaces.LegacySchedulerJob) bool {
+		return job.GetId() != exemptGang[1].GetId()
+	})
+	ctx := armadacontext.Background()
+	for _, job := range gang {
+		assert.True(t, filter(ctx, job))
+	}
+	for _, job := range exemptGang {
+		assert.False(t, filter(ctx, job), "all jobs of a gang are exempt if any job of the gang is")
+	}
+	assert.True(t, filter(ctx, notGangJob))
+}
+
 type InMemoryNodeIterator struct {
 	i     int
 	nodes []*nodedb.Node
@@ -617,6 +649,43 @@ func TestPreemptingQueueScheduler(t *testing.T) {
 				"A": 1,
 			},
 		},
+		"gang preemption is atomic for gangs with min cardinality": {
+			SchedulingConfig: testfixtures.TestSchedulingConfig(),
+			Nodes:            testfixtures.N32CpuNodes(2, testfixtures.TestPriorities),
+			Rounds: []SchedulingRound{
+				{
+					// Schedule a gang across two nodes.
+					JobsByQueue: map[string][]*jobdb.Job{
+						"A": testfixtures.WithGangAnnotationsAndMinCardinalityJobs(
+							1,
+							testfixtures.N32Cpu256GiJobs("A", testfixtures.PriorityClass0, 2),
+						),
+					},
+					ExpectedScheduledIndices: map[string][]int{
+						"A": testfixtures.IntRange(0, 1),
+					},
+				},
+				{
+					// Schedule jobs taking up one node, and assert that the whole gang is preempted,
+					// instead of the gang being re-scheduled at its minimum cardinality.
+					JobsByQueue: map[string][]*jobdb.Job{
+						"B": testfixtures.N1Cpu4GiJobs("B", testfixtures.PriorityClass0, 32),
+					},
+					ExpectedScheduledIndices: map[string][]int{
+						"B": testfixtures.IntRange(0, 31),
+					},
+					ExpectedPreemptedIndices: map[string]map[int][]int{
+						"A": {
+							0: testfixtures.IntRange(0, 1),
+						},
+					},
+				},
+			},
+			PriorityFactorByQueue: map[string]float64{
+				"A": 1,
+				"B": 1,
+			},
+		},
 		"gang preemption with NodeEvictionProbability 0": {
 			SchedulingConfig: testfixtures.WithNodeEvictionProbabilityConfig(
 				0.0, // To test the gang evictor, we need to disable stochastic eviction.
@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/interfaces"
+)
+
+// PreemptionCause is the reason a running job was preempted.
+type PreemptionCause string
+
+const (
+	// PreemptionCauseFairShare indicates the job was preempted to balance resource usage across queues.
+	PreemptionCauseFairShare PreemptionCause = "FairShare"
+	// PreemptionCauseNodeOversubscribed indicates the job was preempted since its node was oversubscribed,
+	// e.g., since jobs of higher priority were scheduled onto it.
+	PreemptionCauseNodeOversubscribed PreemptionCause = "NodeOversubscribed"
+	// PreemptionCauseNodeDrained indicates the job was preempted since the drain deadline of its executor passed.
+	PreemptionCauseNodeDrained PreemptionCause = "NodeDrained"
+)
+
+// Preemption describes why a running job was preempted, such that its owner can be told.
+type Preemption struct {
+	Cause PreemptionCause
+	// Job scheduled onto the node the job was preempted from that displaced it.
+	// Empty if no job was scheduled onto that node in the same round, e.g., if the node was oversubscribed by non-Armada pods.
+	PreemptingJobId         string
+	PreemptingQueue         string
+	PreemptingPriorityClass string
+	PreemptingGangId        string
+	// Run of the preempting job. Only known once runs have been created for scheduled jobs; uuid.Nil until then.
+	PreemptingRunId uuid.UUID
+	// Position of the queue of the preempted job in the order in which queues are considered for scheduling
+	// as of the end of the round, starting at 1; zero if unknown.
+	ExpectedRequeuePosition int
+	// True if the queue of the preempted job is below its fair share once the preemption is accounted for,
+	// in which case fair share accounting prioritises scheduling jobs of the queue in subsequent rounds.
+	QueueCompensated bool
+}
+
+// fractionOfFairShare returns the fraction of its fair share the provided queue is allocated,
+// given the total cost across all queues.
+func fractionOfFairShare(sctx *schedulercontext.SchedulingContext, qctx *schedulercontext.QueueSchedulingContext, totalCost float64) float64 {
+	fairShare := qctx.Weight / sctx.WeightSum
+	actualShare := sctx.FairnessCostProvider.CostFromQueue(qctx) / totalCost
+	return actualShare / fairShare
+}
+
+// preemptions returns a description of the preemption of each preempted job, indexed by job id.
+// causeByJobId gives the cause of each preemption and nodeIdByJobId the node each job was preempted from or scheduled onto.
+func preemptions(
+	sctx *schedulercontext.SchedulingContext,
+	preemptedJobs []interfaces.LegacySchedulerJob,
+	scheduledJobs []interfaces.LegacySchedulerJob,
+	causeByJobId map[string]PreemptionCause,
+	nodeIdByJobId map[string]string,
+) (map[string]*Preemption, error) {
+	if len(preemptedJobs) == 0 {
+		return nil, nil
+	}
+
+	// Queues are considered for scheduling in order of increasing cost.
+	queues := maps.Keys(sctx.QueueSchedulingContexts)
+	costByQueue := make(map[string]float64, len(queues))
+	for _, queue := range queues {
+		costByQueue[queue] = sctx.FairnessCostProvider.CostFromQueue(sctx.QueueSchedulingContexts[queue])
+	}
+	slices.SortFunc(queues, func(a, b string) bool {
+		if costByQueue[a] == costByQueue[b] {
+			return a < b
+		}
+		return costByQueue[a] < costByQueue[b]
+	})
+	positionByQueue := make(map[string]int, len(queues))
+	for i, queue := range queues {
+		positionByQueue[queue] = i + 1
+	}
+	totalCost := sctx.TotalCost()
+
+	// Of the jobs scheduled onto each node, the one of highest priority is considered to have displaced the preempted jobs.
+	preemptingJobByNodeId := make(map[string]interfaces.LegacySchedulerJob)
+	for _, job := range scheduledJobs {
+		nodeId := nodeIdByJobId[job.GetId()]
+		if nodeId == "" {
+			continue
+		}
+		if other, ok := preemptingJobByNodeId[nodeId]; !ok || isPreemptingJobLess(sctx, job, other) {
+			preemptingJobByNodeId[nodeId] = job
+		}
+	}
+
+	rv := make(map[string]*Preemption, len(preemptedJobs))
+	for _, job := range preemptedJobs {
+		preemption := &Preemption{
+			Cause:                   causeByJobId[job.GetId()],
+			ExpectedRequeuePosition: positionByQueue[job.GetQueue()],
+		}
+		if qctx, ok := sctx.QueueSchedulingContexts[job.GetQueue()]; ok && totalCost > 0 {
+			preemption.QueueCompensated = fractionOfFairShare(sctx, qctx, totalCost) < 1
+		}
+		if preemptingJob, ok := preemptingJobByNodeId[nodeIdByJobId[job.GetId()]]; ok {
+			gangId, _, _, isGangJob, err := GangIdAndCardinalityFromLegacySchedulerJob(preemptingJob)
+			if err != nil {
+				return nil, err
+			}
+			preemption.PreemptingJobId = preemptingJob.GetId()
+			preemption.PreemptingQueue = preemptingJob.GetQueue()
+			preemption.PreemptingPriorityClass = preemptingJob.GetPriorityClassName()
+			if isGangJob {
+				preemption.PreemptingGangId = gangId
+			}
+		}
+		rv[job.GetId()] = preemption
+	}
+	return rv, nil
+}
+
+// isPreemptingJobLess returns true if job a is of higher priority than job b, tie-breaking by job id.
+func isPreemptingJobLess(sctx *schedulercontext.SchedulingContext, a, b interfaces.LegacySchedulerJob) bool {
+	priorityA := sctx.PriorityClasses[a.GetPriorityClassName()].Priority
+	priorityB := sctx.PriorityClasses[b.GetPriorityClassName()].Priority
+	if priorityA != priorityB {
+		return priorityA > priorityB
+	}
+	return a.GetId() < b.GetId()
+}
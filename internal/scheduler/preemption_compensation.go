@@ -0,0 +1,197 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/fairness"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// PreemptionCompensationLedger credits queues for the work they lose to preemption, such that they're prioritised
+// when rescheduling and queues of similar share don't repeatedly preempt each other.
+// Work lost is measured in pool-hours, i.e., the dominant share of the pool allocated to each preempted run
+// multiplied by how long it had been running. Credit is tracked separately for each pool and decays exponentially.
+type PreemptionCompensationLedger struct {
+	config configuration.PreemptionCompensationConfig
+	// Resources considered when computing the share of the pool allocated to each run.
+	resourcesToConsider []string
+	clock               clock.Clock
+
+	mu sync.Mutex
+	// Credit of each queue in each pool, in pool-hours, as of updated.
+	creditByPoolAndQueue map[string]map[string]float64
+	updated              time.Time
+}
+
+func NewPreemptionCompensationLedger(config configuration.PreemptionCompensationConfig, resourcesToConsider []string) (*PreemptionCompensationLedger, error) {
+	if config.HalfLife <= 0 {
+		return nil, errors.Errorf("preemption compensation half-life must be positive, but is %s", config.HalfLife)
+	}
+	if config.WeightBoostPerPoolHour < 0 || config.MaxWeightBoost < 0 {
+		return nil, errors.Errorf(
+			"preemption compensation weight boosts must be non-negative, but are %f per pool-hour and %f at most",
+			config.WeightBoostPerPoolHour, config.MaxWeightBoost,
+		)
+	}
+	if len(resourcesToConsider) == 0 {
+		return nil, errors.New("preemption compensation requires at least one resource to consider")
+	}
+	return &PreemptionCompensationLedger{
+		config:               config,
+		resourcesToConsider:  resourcesToConsider,
+		clock:                clock.RealClock{},
+		creditByPoolAndQueue: make(map[string]map[string]float64),
+	}, nil
+}
+
+// Record credits the queues of the provided jobs, preempted from a pool with the given total resources,
+// for the time their runs had been running. Runs that never started running aren't credited.
+func (l *PreemptionCompensationLedger) Record(pool string, totalResources schedulerobjects.ResourceList, preemptedJobs []*jobdb.Job) error {
+	if len(preemptedJobs) == 0 {
+		return nil
+	}
+	costProvider, err := fairness.NewDominantResourceFairness(totalResources, l.resourcesToConsider)
+	if err != nil {
+		return err
+	}
+	now := l.clock.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.decay(now)
+	creditByQueue := l.creditByPoolAndQueue[pool]
+	if creditByQueue == nil {
+		creditByQueue = make(map[string]float64)
+		l.creditByPoolAndQueue[pool] = creditByQueue
+	}
+	for _, job := range preemptedJobs {
+		run := job.LatestRun()
+		if run == nil || run.RunningTime() == 0 {
+			continue
+		}
+		hours := now.Sub(time.Unix(0, run.RunningTime())).Hours()
+		if hours <= 0 {
+			continue
+		}
+		share := costProvider.CostFromAllocationAndWeight(schedulerobjects.ResourceListFromV1ResourceList(job.GetResourceRequirements().Requests), 1)
+		if share <= 0 {
+			continue
+		}
+		creditByQueue[job.Queue()] += share * hours
+		log.Infof(
+			"crediting queue %s with %f pool-hours in pool %s for preempted job %s",
+			job.Queue(), share*hours, pool, job.Id(),
+		)
+	}
+	return nil
+}
+
+// WeightMultiplier returns the factor by which to multiply the weight of queue in pool to compensate it for work lost to preemption.
+func (l *PreemptionCompensationLedger) WeightMultiplier(pool string, queue string) float64 {
+	now := l.clock.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.decay(now)
+	boost := l.creditByPoolAndQueue[pool][queue] * l.config.WeightBoostPerPoolHour
+	return 1 + math.Min(boost, l.config.MaxWeightBoost)
+}
+
+// Credits returns the current credit of each queue in each pool, in pool-hours.
+func (l *PreemptionCompensationLedger) Credits() map[string]map[string]float64 {
+	now := l.clock.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.decay(now)
+	rv := make(map[string]map[string]float64, len(l.creditByPoolAndQueue))
+	for pool, creditByQueue := range l.creditByPoolAndQueue {
+		rv[pool] = make(map[string]float64, len(creditByQueue))
+		for queue, credit := range creditByQueue {
+			rv[pool][queue] = credit
+		}
+	}
+	return rv
+}
+
+// preemptionCompensationLedgerState is the state of a PreemptionCompensationLedger handed over between leaders.
+type preemptionCompensationLedgerState struct {
+	CreditByPoolAndQueue map[string]map[string]float64 `json:"creditByPoolAndQueue"`
+	Updated              time.Time                     `json:"updated"`
+}
+
+// MarshalState serialises the credit of each queue in each pool, such that it's handed over to the next leader.
+func (l *PreemptionCompensationLedger) MarshalState() ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	bytes, err := json.Marshal(preemptionCompensationLedgerState{
+		CreditByPoolAndQueue: l.creditByPoolAndQueue,
+		Updated:              l.updated,
+	})
+	return bytes, errors.WithStack(err)
+}
+
+// UnmarshalState replaces the credit of each queue in each pool with that handed over by the previous leader.
+// Credit continues to decay from the time the previous leader last updated it.
+func (l *PreemptionCompensationLedger) UnmarshalState(state []byte) error {
+	var ledgerState preemptionCompensationLedgerState
+	if err := json.Unmarshal(state, &ledgerState); err != nil {
+		return errors.WithStack(err)
+	}
+	if ledgerState.CreditByPoolAndQueue == nil {
+		ledgerState.CreditByPoolAndQueue = make(map[string]map[string]float64)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.creditByPoolAndQueue = ledgerState.CreditByPoolAndQueue
+	l.updated = ledgerState.Updated
+	return nil
+}
+
+// minCredit is the credit below which a queue is no longer tracked, in pool-hours.
+const minCredit = 1e-6
+
+// decay decays all credit to the provided time. Must be called with l.mu held.
+func (l *PreemptionCompensationLedger) decay(now time.Time) {
+	if elapsed := now.Sub(l.updated); !l.updated.IsZero() && elapsed > 0 {
+		factor := math.Pow(0.5, float64(elapsed)/float64(l.config.HalfLife))
+		for pool, creditByQueue := range l.creditByPoolAndQueue {
+			for queue, credit := range creditByQueue {
+				if credit *= factor; credit < minCredit {
+					delete(creditByQueue, queue)
+				} else {
+					creditByQueue[queue] = credit
+				}
+			}
+			if len(creditByQueue) == 0 {
+				delete(l.creditByPoolAndQueue, pool)
+			}
+		}
+	}
+	if now.After(l.updated) {
+		l.updated = now
+	}
+}
+
+// PreemptionCompensationHttpHandler serves the credit of each queue in each pool as json.
+type PreemptionCompensationHttpHandler struct {
+	ledger *PreemptionCompensationLedger
+}
+
+func NewPreemptionCompensationHttpHandler(ledger *PreemptionCompensationLedger) *PreemptionCompensationHttpHandler {
+	return &PreemptionCompensationHttpHandler{ledger: ledger}
+}
+
+func (h *PreemptionCompensationHttpHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.ledger.Credits()); err != nil {
+		log.WithError(err).Error("failed to write preemption compensation response")
+	}
+}
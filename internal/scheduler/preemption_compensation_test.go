@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestPreemptionCompensationLedger(t *testing.T) {
+	ledger, err := NewPreemptionCompensationLedger(
+		configuration.PreemptionCompensationConfig{
+			HalfLife:               time.Hour,
+			WeightBoostPerPoolHour: 2,
+			MaxWeightBoost:         1,
+		},
+		[]string{"cpu"},
+	)
+	require.NoError(t, err)
+	now := time.Date(2023, 11, 15, 6, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFakeClock(now)
+	ledger.clock = fakeClock
+
+	// A job of queue A that had been running on 1 of 32 cpus for 2 hours, and one of queue B that never started running.
+	runningJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).WithNewRun("executor", "node", "node")
+	runningJob = runningJob.WithUpdatedRun(runningJob.LatestRun().WithRunningTime(now.Add(-2 * time.Hour).UnixNano()))
+	leasedJob := testfixtures.Test1Cpu4GiJob("B", testfixtures.PriorityClass0).WithNewRun("executor", "node", "node")
+	require.NoError(t, ledger.Record("pool", cpuResourceList("32"), []*jobdb.Job{runningJob, leasedJob}))
+
+	credits := ledger.Credits()
+	require.Contains(t, credits, "pool")
+	assert.InDelta(t, 2.0/32, credits["pool"]["A"], 1e-9)
+	assert.NotContains(t, credits["pool"], "B")
+	assert.InDelta(t, 1+4.0/32, ledger.WeightMultiplier("pool", "A"), 1e-9)
+	assert.Equal(t, 1.0, ledger.WeightMultiplier("pool", "B"))
+	assert.Equal(t, 1.0, ledger.WeightMultiplier("other", "A"))
+
+	// Credit halves every half-life.
+	fakeClock.Step(time.Hour)
+	assert.InDelta(t, 1.0/32, ledger.Credits()["pool"]["A"], 1e-9)
+
+	// The boost is capped.
+	for i := 0; i < 20; i++ {
+		require.NoError(t, ledger.Record("pool", cpuResourceList("32"), []*jobdb.Job{runningJob}))
+	}
+	assert.Equal(t, 2.0, ledger.WeightMultiplier("pool", "A"))
+
+	// Credit is eventually forgotten.
+	fakeClock.Step(100 * time.Hour)
+	assert.Empty(t, ledger.Credits())
+	assert.Equal(t, 1.0, ledger.WeightMultiplier("pool", "A"))
+}
+
+func TestPreemptionCompensationLedger_Handover(t *testing.T) {
+	config := configuration.PreemptionCompensationConfig{HalfLife: time.Hour, WeightBoostPerPoolHour: 2, MaxWeightBoost: 1}
+	now := time.Date(2023, 11, 15, 6, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFakeClock(now)
+	ledger, err := NewPreemptionCompensationLedger(config, []string{"cpu"})
+	require.NoError(t, err)
+	ledger.clock = fakeClock
+	runningJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).WithNewRun("executor", "node", "node")
+	runningJob = runningJob.WithUpdatedRun(runningJob.LatestRun().WithRunningTime(now.Add(-2 * time.Hour).UnixNano()))
+	require.NoError(t, ledger.Record("pool", cpuResourceList("32"), []*jobdb.Job{runningJob}))
+	state, err := ledger.MarshalState()
+	require.NoError(t, err)
+
+	// Credit handed over to the next leader continues to decay from when it was handed over.
+	nextLedger, err := NewPreemptionCompensationLedger(config, []string{"cpu"})
+	require.NoError(t, err)
+	nextLedger.clock = fakeClock
+	require.NoError(t, nextLedger.UnmarshalState(state))
+	fakeClock.Step(time.Hour)
+	assert.InDelta(t, 1.0/32, nextLedger.Credits()["pool"]["A"], 1e-9)
+
+	assert.Error(t, nextLedger.UnmarshalState([]byte("not json")))
+}
+
+func TestNewPreemptionCompensationLedger_InvalidConfig(t *testing.T) {
+	valid := configuration.PreemptionCompensationConfig{
+		HalfLife:               time.Hour,
+		WeightBoostPerPoolHour: 1,
+		MaxWeightBoost:         1,
+	}
+	_, err := NewPreemptionCompensationLedger(valid, []string{"cpu"})
+	require.NoError(t, err)
+
+	tests := map[string]func(config *configuration.PreemptionCompensationConfig){
+		"zero half-life":          func(config *configuration.PreemptionCompensationConfig) { config.HalfLife = 0 },
+		"negative boost per hour": func(config *configuration.PreemptionCompensationConfig) { config.WeightBoostPerPoolHour = -1 },
+		"negative max boost":      func(config *configuration.PreemptionCompensationConfig) { config.MaxWeightBoost = -1 },
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := valid
+			modify(&config)
+			_, err := NewPreemptionCompensationLedger(config, []string{"cpu"})
+			assert.Error(t, err)
+		})
+	}
+	_, err = NewPreemptionCompensationLedger(valid, nil)
+	assert.Error(t, err)
+}
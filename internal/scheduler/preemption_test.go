@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/fairness"
+	"github.com/armadaproject/armada/internal/scheduler/interfaces"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+	"github.com/armadaproject/armada/pkg/armadaevents"
+)
+
+func TestPreemptions(t *testing.T) {
+	config := testfixtures.TestSchedulingConfig()
+	totalResources := cpuResourceList("10")
+	fairnessCostProvider, err := fairness.NewDominantResourceFairness(totalResources, []string{"cpu"})
+	require.NoError(t, err)
+	sctx := schedulercontext.NewSchedulingContext(
+		"executor",
+		"pool",
+		config.Preemption.PriorityClasses,
+		config.Preemption.DefaultPriorityClass,
+		fairnessCostProvider,
+		rate.NewLimiter(rate.Inf, 0),
+		totalResources,
+	)
+	for queue, cpu := range map[string]string{"A": "1", "B": "6", "C": "3"} {
+		require.NoError(t, sctx.AddQueueSchedulingContext(
+			queue,
+			1,
+			schedulerobjects.QuantityByTAndResourceType[string]{testfixtures.PriorityClass0: cpuResourceList(cpu)},
+			rate.NewLimiter(rate.Inf, 0),
+		))
+	}
+
+	preemptedA := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0)
+	preemptedB := testfixtures.Test1Cpu4GiJob("B", testfixtures.PriorityClass0)
+	scheduledLow := testfixtures.Test1Cpu4GiJob("C", testfixtures.PriorityClass0)
+	scheduledHigh := testfixtures.WithGangAnnotationsJobs([]*jobdb.Job{testfixtures.Test1Cpu4GiJob("C", testfixtures.PriorityClass2)})[0]
+	rv, err := preemptions(
+		sctx,
+		[]interfaces.LegacySchedulerJob{preemptedA, preemptedB},
+		[]interfaces.LegacySchedulerJob{scheduledLow, scheduledHigh},
+		map[string]PreemptionCause{
+			preemptedA.Id(): PreemptionCauseFairShare,
+			preemptedB.Id(): PreemptionCauseNodeOversubscribed,
+		},
+		map[string]string{
+			preemptedA.Id():    "node1",
+			preemptedB.Id():    "node2",
+			scheduledLow.Id():  "node1",
+			scheduledHigh.Id(): "node1",
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		map[string]*Preemption{
+			// The job of highest priority scheduled onto node1 is considered to have displaced preemptedA.
+			// Queue A is furthest below its fair share.
+			preemptedA.Id(): {
+				Cause:                   PreemptionCauseFairShare,
+				PreemptingJobId:         scheduledHigh.Id(),
+				PreemptingQueue:         "C",
+				PreemptingPriorityClass: testfixtures.PriorityClass2,
+				PreemptingGangId:        scheduledHigh.GetAnnotations()[configuration.GangIdAnnotation],
+				ExpectedRequeuePosition: 1,
+				QueueCompensated:        true,
+			},
+			// No job was scheduled onto node2 and queue B is above its fair share.
+			preemptedB.Id(): {
+				Cause:                   PreemptionCauseNodeOversubscribed,
+				ExpectedRequeuePosition: 3,
+			},
+		},
+		rv,
+	)
+}
+
+func TestAppendEventSequencesFromPreemptedJobs_PreemptionDetails(t *testing.T) {
+	job := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).WithNewRun("executor", "node", "node")
+	preemptingJob := testfixtures.Test1Cpu4GiJob("B", testfixtures.PriorityClass2)
+	preemptingRunId := uuid.New()
+	eventSequences, err := AppendEventSequencesFromPreemptedJobs(
+		nil,
+		[]*jobdb.Job{job},
+		map[string]*Preemption{
+			job.Id(): {
+				Cause:                   PreemptionCauseFairShare,
+				PreemptingJobId:         preemptingJob.Id(),
+				PreemptingQueue:         "B",
+				PreemptingPriorityClass: testfixtures.PriorityClass2,
+				PreemptingGangId:        "gang",
+				PreemptingRunId:         preemptingRunId,
+				ExpectedRequeuePosition: 2,
+				QueueCompensated:        true,
+			},
+		},
+		time.Now(),
+	)
+	require.NoError(t, err)
+	require.Len(t, eventSequences, 1)
+	preempted := eventSequences[0].Events[0].GetJobRunPreempted()
+	require.NotNil(t, preempted)
+	preemptingJobId, err := armadaevents.ProtoUuidFromUlidString(preemptingJob.Id())
+	require.NoError(t, err)
+	assert.Equal(t, preemptingJobId, preempted.PreemptiveJobId)
+	assert.Equal(t, armadaevents.ProtoUuidFromUuid(preemptingRunId), preempted.PreemptiveRunId)
+	assert.Equal(t, "FairShare", preempted.Cause)
+	assert.Equal(t, "B", preempted.PreemptiveQueue)
+	assert.Equal(t, testfixtures.PriorityClass2, preempted.PreemptivePriorityClass)
+	assert.Equal(t, "gang", preempted.PreemptiveGangId)
+	assert.Equal(t, int32(2), preempted.ExpectedRequeuePosition)
+	assert.True(t, preempted.QueueCompensated)
+
+	// Jobs without a description of their preemption are only marked as preempted.
+	eventSequences, err = AppendEventSequencesFromPreemptedJobs(nil, []*jobdb.Job{job}, nil, time.Now())
+	require.NoError(t, err)
+	preempted = eventSequences[0].Events[0].GetJobRunPreempted()
+	assert.Nil(t, preempted.PreemptiveJobId)
+	assert.Empty(t, preempted.Cause)
+}
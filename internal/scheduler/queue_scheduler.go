@@ -3,11 +3,15 @@ package scheduler
 import (
 	"container/heap"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 
+	"github.com/armadaproject/armada/internal/armada/configuration"
 	"github.com/armadaproject/armada/internal/common/armadacontext"
 	"github.com/armadaproject/armada/internal/common/logging"
 	schedulerconstraints "github.com/armadaproject/armada/internal/scheduler/constraints"
@@ -60,10 +64,27 @@ func (sch *QueueScheduler) SkipUnsuccessfulSchedulingKeyCheck() {
 	sch.gangScheduler.SkipUnsuccessfulSchedulingKeyCheck()
 }
 
+func (sch *QueueScheduler) EnableNodeRejectionDiagnostics() {
+	sch.gangScheduler.EnableNodeRejectionDiagnostics()
+}
+
+func (sch *QueueScheduler) SetMaxNodeUniformityLabelValuesToConsider(n uint) {
+	sch.gangScheduler.SetMaxNodeUniformityLabelValuesToConsider(n)
+}
+
+func (sch *QueueScheduler) SetGangPlacementScorer(placementScorer GangPlacementScorer) {
+	sch.gangScheduler.SetPlacementScorer(placementScorer)
+}
+
+func (sch *QueueScheduler) SetGangPlacementTieBreak(tieBreak configuration.GangPlacementTieBreak) {
+	sch.gangScheduler.SetPlacementTieBreak(tieBreak)
+}
+
 func (sch *QueueScheduler) Schedule(ctx *armadacontext.Context) (*SchedulerResult, error) {
 	nodeIdByJobId := make(map[string]string)
 	scheduledJobs := make([]interfaces.LegacySchedulerJob, 0)
 	failedJobs := make([]interfaces.LegacySchedulerJob, 0)
+	failureReasonByJobId := make(map[string]string)
 	for {
 		// Peek() returns the next gang to try to schedule. Call Clear() before calling Peek() again.
 		// Calling Clear() after (failing to) schedule ensures we get the next gang in order of smallest fair share.
@@ -101,12 +122,21 @@ func (sch *QueueScheduler) Schedule(ctx *armadacontext.Context) (*SchedulerResul
 				}
 			}
 
-			// Report any excess gang jobs that failed
-			for _, jctx := range gctx.JobSchedulingContexts {
-				if jctx.ShouldFail {
-					failedJobs = append(failedJobs, jctx.Job)
+			// Report any excess gang jobs that failed.
+			// If gang remainders are enabled, these jobs instead remain queued and are later scheduled as a remainder gang.
+			if sch.schedulingContext.GangRemainderCardinalityByGangId == nil {
+				for _, jctx := range gctx.JobSchedulingContexts {
+					if jctx.ShouldFail {
+						failedJobs = append(failedJobs, jctx.Job)
+					}
 				}
 			}
+		} else if unschedulableReason.GetCode() == schedulerobjects.UnschedulableReasonCodeGangSchedulingDeadlineExceeded {
+			// Gangs not scheduled within their deadline are failed.
+			for _, jctx := range gctx.JobSchedulingContexts {
+				failedJobs = append(failedJobs, jctx.Job)
+				failureReasonByJobId[jctx.JobId] = unschedulableReason.Error()
+			}
 		} else if schedulerconstraints.IsTerminalUnschedulableReason(unschedulableReason) {
 			// If unschedulableReason indicates no more new jobs can be scheduled,
 			// instruct the underlying iterator to only yield evicted jobs from now on.
@@ -130,11 +160,12 @@ func (sch *QueueScheduler) Schedule(ctx *armadacontext.Context) (*SchedulerResul
 		return nil, errors.Errorf("only %d out of %d jobs mapped to a node", len(nodeIdByJobId), len(scheduledJobs))
 	}
 	return &SchedulerResult{
-		PreemptedJobs:      nil,
-		ScheduledJobs:      scheduledJobs,
-		FailedJobs:         failedJobs,
-		NodeIdByJobId:      nodeIdByJobId,
-		SchedulingContexts: []*schedulercontext.SchedulingContext{sch.schedulingContext},
+		PreemptedJobs:        nil,
+		ScheduledJobs:        scheduledJobs,
+		FailedJobs:           failedJobs,
+		FailureReasonByJobId: failureReasonByJobId,
+		NodeIdByJobId:        nodeIdByJobId,
+		SchedulingContexts:   []*schedulercontext.SchedulingContext{sch.schedulingContext},
 	}, nil
 }
 
@@ -153,6 +184,10 @@ type QueuedGangIterator struct {
 	// Number of jobs we have seen so far.
 	jobsSeen uint
 	next     *schedulercontext.GangSchedulingContext
+	// Queue the jobs of the underlying iterator are submitted to and the members of cross-queue gangs
+	// collected across the iterators of all queues. Set by CandidateGangIterator; if nil, cross-queue gangs are never complete.
+	queue           string
+	crossQueueGangs *crossQueueGangs
 }
 
 func NewQueuedGangIterator(sctx *schedulercontext.SchedulingContext, it JobIterator, maxLookback uint, skipKnownUnschedulableJobs bool) *QueuedGangIterator {
@@ -190,18 +225,29 @@ func (it *QueuedGangIterator) Peek() (*schedulercontext.GangSchedulingContext, e
 	}
 
 	// Get one job at a time from the underlying iterator until we either
-	// 1. get a job that isn't part of a gang, in which case we yield it immediately, or
-	// 2. get the final job in a gang, in which case we yield the entire gang.
+	// 1. get a job that isn't part of a gang, in which case we yield it immediately,
+	// 2. get the final job in a gang, in which case we yield the entire gang, or
+	// 3. find that the iterator of another queue received the final job of a cross-queue gang owned by this queue,
+	//    in which case we yield that gang.
 	for {
+		if gang := it.crossQueueGangs.popCompleteGang(it.queue); gang != nil {
+			if skipped, err := it.skipUnfeasibleJobs(gang); err != nil {
+				return nil, err
+			} else if skipped {
+				continue
+			}
+			it.next = schedulercontext.NewGangSchedulingContext(jobSchedulingContextsFromJobs(it.schedulingContext.PriorityClasses, gang))
+			return it.next, nil
+		}
 		job, err := it.queuedJobsIterator.Next()
 		if err != nil {
 			return nil, err
 		}
-		if job == nil {
-			return nil, nil
-		}
-		if reflect.ValueOf(job).IsNil() {
-			return nil, nil
+		if job == nil || reflect.ValueOf(job).IsNil() {
+			// Remainder gangs are yielded once the underlying iterator is exhausted even if fewer members than expected were seen,
+			// since members may, e.g., belong to a job set excluded from scheduling.
+			it.next = it.nextPartialRemainderGang()
+			return it.next, nil
 		}
 		if !isEvictedJob(job) {
 			// Rescheduled jobs don't count towards the limit.
@@ -211,32 +257,6 @@ func (it *QueuedGangIterator) Peek() (*schedulercontext.GangSchedulingContext, e
 			return nil, nil
 		}
 
-		// Skip this job if it's known to be unschedulable.
-		if it.skipKnownUnschedulableJobs && len(it.schedulingContext.UnfeasibleSchedulingKeys) > 0 {
-			schedulingKey, ok := job.GetSchedulingKey()
-			if !ok {
-				schedulingKey = it.schedulingContext.SchedulingKeyFromLegacySchedulerJob(job)
-			}
-			if schedulingKey != schedulerobjects.EmptySchedulingKey {
-				if unsuccessfulJctx, ok := it.schedulingContext.UnfeasibleSchedulingKeys[schedulingKey]; ok {
-					// TODO: For performance, we should avoid creating new objects and instead reference the existing one.
-					jctx := &schedulercontext.JobSchedulingContext{
-						Created:              time.Now(),
-						JobId:                job.GetId(),
-						Job:                  job,
-						UnschedulableReason:  unsuccessfulJctx.UnschedulableReason,
-						PodSchedulingContext: unsuccessfulJctx.PodSchedulingContext,
-						// TODO: Move this into gang scheduling context
-						GangMinCardinality: 1,
-					}
-					if _, err := it.schedulingContext.AddJobSchedulingContext(jctx); err != nil {
-						return nil, err
-					}
-					continue
-				}
-			}
-		}
-
 		gangId, gangCardinality, _, isGangJob, err := GangIdAndCardinalityFromAnnotations(job.GetAnnotations())
 		if err != nil {
 			// TODO: Get from context passed in.
@@ -244,26 +264,57 @@ func (it *QueuedGangIterator) Peek() (*schedulercontext.GangSchedulingContext, e
 			logging.WithStacktrace(log, err).Errorf("failed to get gang cardinality for job %s", job.GetId())
 			gangCardinality = 1 // Schedule jobs with invalid gang cardinality one by one.
 		}
-		if isGangJob {
+		remainderCardinality, isRemainderGangJob := it.schedulingContext.GangRemainderCardinalityByGangId[gangId]
+		isRemainderGangJob = isGangJob && isRemainderGangJob && !isEvictedJob(job)
+
+		// Skip this job if it's known to be unschedulable.
+		// Members of gangs that aren't remainder gangs are instead checked once the gang is complete,
+		// since the gang is unschedulable if any of its members are, whether or not the members have the same requirements.
+		if !isGangJob || isRemainderGangJob {
+			if skipped, err := it.skipUnfeasibleJobs([]interfaces.LegacySchedulerJob{job}); err != nil {
+				return nil, err
+			} else if skipped {
+				continue
+			}
+		}
+
+		if isRemainderGangJob {
+			// Members of a gang with running jobs that are still queued make up a remainder gang,
+			// which is grouped separately from any evicted members of the same gang.
+			remainderGangId := remainderGangIdPrefix + gangId
+			it.jobsByGangId[remainderGangId] = append(it.jobsByGangId[remainderGangId], job)
+			gang := it.jobsByGangId[remainderGangId]
+			if len(gang) >= remainderCardinality {
+				delete(it.jobsByGangId, remainderGangId)
+				it.next = newRemainderGangSchedulingContext(gangId, jobSchedulingContextsFromJobs(it.schedulingContext.PriorityClasses, gang))
+				return it.next, nil
+			}
+		} else if gangQueue := job.GetAnnotations()[configuration.GangQueueAnnotation]; isGangJob && gangQueue != "" && it.crossQueueGangs != nil {
+			// Members of cross-queue gangs are collected across queues and yielded by the iterator of the queue owning the gang.
+			it.crossQueueGangs.add(gangQueue, gangId, gangCardinality, job)
+		} else if isGangJob {
 			it.jobsByGangId[gangId] = append(it.jobsByGangId[gangId], job)
 			gang := it.jobsByGangId[gangId]
 			if len(gang) == gangCardinality {
 				delete(it.jobsByGangId, gangId)
+				if skipped, err := it.skipUnfeasibleJobs(gang); err != nil {
+					return nil, err
+				} else if skipped {
+					continue
+				}
 				it.next = schedulercontext.NewGangSchedulingContext(
-					schedulercontext.JobSchedulingContextsFromJobs(
+					jobSchedulingContextsFromJobs(
 						it.schedulingContext.PriorityClasses,
 						gang,
-						GangIdAndCardinalityFromAnnotations,
 					),
 				)
 				return it.next, nil
 			}
 		} else {
 			it.next = schedulercontext.NewGangSchedulingContext(
-				schedulercontext.JobSchedulingContextsFromJobs(
+				jobSchedulingContextsFromJobs(
 					it.schedulingContext.PriorityClasses,
 					[]interfaces.LegacySchedulerJob{job},
-					GangIdAndCardinalityFromAnnotations,
 				),
 			)
 			return it.next, nil
@@ -271,6 +322,158 @@ func (it *QueuedGangIterator) Peek() (*schedulercontext.GangSchedulingContext, e
 	}
 }
 
+// skipUnfeasibleJobs returns true if any of jobs, which make up a gang, has the scheduling key of a job previously found
+// to be unschedulable, in which case all of jobs are marked as unschedulable in the scheduling context and should be skipped.
+// Members with a known unfeasible scheduling key are given the reason of the job previously found to be unschedulable,
+// and the other members a reason referring to the first such member, such that each member of a gang of jobs with
+// different requirements is given its own reason.
+func (it *QueuedGangIterator) skipUnfeasibleJobs(jobs []interfaces.LegacySchedulerJob) (bool, error) {
+	if !it.skipKnownUnschedulableJobs || len(it.schedulingContext.UnfeasibleSchedulingKeys) == 0 {
+		return false, nil
+	}
+	unsuccessfulJctxs := make([]*schedulercontext.JobSchedulingContext, len(jobs))
+	var firstUnfeasibleJob interfaces.LegacySchedulerJob
+	var firstUnschedulableReason *schedulerobjects.UnschedulableReason
+	for i, job := range jobs {
+		schedulingKey, ok := job.GetSchedulingKey()
+		if !ok {
+			schedulingKey = it.schedulingContext.SchedulingKeyFromLegacySchedulerJob(job)
+		}
+		if schedulingKey == schedulerobjects.EmptySchedulingKey {
+			continue
+		}
+		if unsuccessfulJctx, ok := it.schedulingContext.UnfeasibleSchedulingKeys[schedulingKey]; ok {
+			unsuccessfulJctxs[i] = unsuccessfulJctx
+			if firstUnfeasibleJob == nil {
+				firstUnfeasibleJob = job
+				firstUnschedulableReason = unsuccessfulJctx.UnschedulableReason
+			}
+		}
+	}
+	if firstUnfeasibleJob == nil {
+		return false, nil
+	}
+	for i, job := range jobs {
+		// TODO: For performance, we should avoid creating new objects and instead reference the existing one.
+		jctx := &schedulercontext.JobSchedulingContext{
+			Created: time.Now(),
+			JobId:   job.GetId(),
+			Job:     job,
+			// TODO: Move this into gang scheduling context
+			GangMinCardinality: 1,
+		}
+		if unsuccessfulJctx := unsuccessfulJctxs[i]; unsuccessfulJctx != nil {
+			jctx.UnschedulableReason = unsuccessfulJctx.UnschedulableReason
+			jctx.PodSchedulingContext = unsuccessfulJctx.PodSchedulingContext
+		} else {
+			jctx.UnschedulableReason = schedulerobjects.NewUnschedulableReason(
+				schedulerobjects.UnschedulableReasonCodeGangMinCardinalityNotMet,
+				"gang member %s is unschedulable: %s", firstUnfeasibleJob.GetId(), firstUnschedulableReason.Error(),
+			)
+		}
+		if _, err := it.schedulingContext.AddJobSchedulingContext(jctx); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// nextPartialRemainderGang removes and returns a remainder gang for which fewer members than expected were seen, if any,
+// in order of parent gang id.
+func (it *QueuedGangIterator) nextPartialRemainderGang() *schedulercontext.GangSchedulingContext {
+	var remainderGangIds []string
+	for gangId := range it.jobsByGangId {
+		if strings.HasPrefix(gangId, remainderGangIdPrefix) {
+			remainderGangIds = append(remainderGangIds, gangId)
+		}
+	}
+	if len(remainderGangIds) == 0 {
+		return nil
+	}
+	slices.Sort(remainderGangIds)
+	remainderGangId := remainderGangIds[0]
+	gang := it.jobsByGangId[remainderGangId]
+	delete(it.jobsByGangId, remainderGangId)
+	return newRemainderGangSchedulingContext(
+		strings.TrimPrefix(remainderGangId, remainderGangIdPrefix),
+		jobSchedulingContextsFromJobs(it.schedulingContext.PriorityClasses, gang),
+	)
+}
+
+// crossQueueGangs collects the members of cross-queue gangs, i.e., gangs with jobs submitted to several queues,
+// received by the QueuedGangIterators of all queues. Complete gangs are held until yielded by the iterator of the queue owning the gang.
+type crossQueueGangs struct {
+	// Members received so far of each incomplete gang, indexed by the queue owning the gang and gang id.
+	jobsByGangKey map[crossQueueGangKey][]interfaces.LegacySchedulerJob
+	// Complete gangs, indexed by the queue owning the gang, in the order they were completed.
+	completeGangsByQueue map[string][][]interfaces.LegacySchedulerJob
+}
+
+type crossQueueGangKey struct {
+	queue  string
+	gangId string
+}
+
+func newCrossQueueGangs() *crossQueueGangs {
+	return &crossQueueGangs{
+		jobsByGangKey:        make(map[crossQueueGangKey][]interfaces.LegacySchedulerJob),
+		completeGangsByQueue: make(map[string][][]interfaces.LegacySchedulerJob),
+	}
+}
+
+// add adds job to the gang with id gangId owned by queue, and marks the gang as complete once it has gangCardinality members.
+func (gangs *crossQueueGangs) add(queue, gangId string, gangCardinality int, job interfaces.LegacySchedulerJob) {
+	key := crossQueueGangKey{queue: queue, gangId: gangId}
+	gang := append(gangs.jobsByGangKey[key], job)
+	if len(gang) < gangCardinality {
+		gangs.jobsByGangKey[key] = gang
+		return
+	}
+	delete(gangs.jobsByGangKey, key)
+	gangs.completeGangsByQueue[queue] = append(gangs.completeGangsByQueue[queue], gang)
+}
+
+// popCompleteGang removes and returns the first complete gang owned by queue, if any.
+func (gangs *crossQueueGangs) popCompleteGang(queue string) []interfaces.LegacySchedulerJob {
+	if gangs == nil || len(gangs.completeGangsByQueue[queue]) == 0 {
+		return nil
+	}
+	gang := gangs.completeGangsByQueue[queue][0]
+	gangs.completeGangsByQueue[queue] = gangs.completeGangsByQueue[queue][1:]
+	if len(gangs.completeGangsByQueue[queue]) == 0 {
+		delete(gangs.completeGangsByQueue, queue)
+	}
+	return gang
+}
+
+// queuesWithCompleteGangs returns the queues owning a complete gang, in lexicographic order.
+func (gangs *crossQueueGangs) queuesWithCompleteGangs() []string {
+	queues := maps.Keys(gangs.completeGangsByQueue)
+	slices.Sort(queues)
+	return queues
+}
+
+// Prefix of the keys under which members of remainder gangs are grouped by QueuedGangIterator.
+const remainderGangIdPrefix = "remainder:"
+
+// newRemainderGangSchedulingContext returns a gang scheduling context for the queued members of the gang with id parentGangId,
+// i.e., the members left unscheduled when that gang was scheduled at its minimum cardinality.
+// The parent gang has already met its minimum cardinality and any failure domain and role constraints,
+// so each member of a remainder gang may be scheduled independently of the others.
+// Spread constraints are kept, but only apply among the members of the remainder gang.
+func newRemainderGangSchedulingContext(parentGangId string, jctxs []*schedulercontext.JobSchedulingContext) *schedulercontext.GangSchedulingContext {
+	for _, jctx := range jctxs {
+		jctx.GangMinCardinality = 1
+		jctx.GangRoleMinCardinality = 0
+	}
+	gctx := schedulercontext.NewGangSchedulingContext(jctxs)
+	// Members of remainder gangs are grouped by the queue they're submitted to, even for cross-queue gangs.
+	gctx.Queue = jctxs[0].Job.GetQueue()
+	gctx.ParentGangId = parentGangId
+	gctx.MinFailureDomains = 0
+	return gctx
+}
+
 func (it *QueuedGangIterator) hitLookbackLimit() bool {
 	if it.maxLookback == 0 {
 		return false
@@ -294,6 +497,10 @@ type CandidateGangIterator struct {
 	// Priority queue containing per-queue iterators.
 	// Determines the order in which queues are processed.
 	pq QueueCandidateGangIteratorPQ
+	// Item of each queue, including those not in pq since their iterator has no gang left to yield, indexed by queue name.
+	itemsByQueue map[string]*QueueCandidateGangIteratorItem
+	// Members of cross-queue gangs collected across the per-queue iterators.
+	crossQueueGangs *crossQueueGangs
 }
 
 func NewCandidateGangIterator(
@@ -307,15 +514,40 @@ func NewCandidateGangIterator(
 		onlyYieldEvictedByQueue: make(map[string]bool),
 		buffer:                  schedulerobjects.NewResourceListWithDefaultSize(),
 		pq:                      make(QueueCandidateGangIteratorPQ, 0, len(iteratorsByQueue)),
+		itemsByQueue:            make(map[string]*QueueCandidateGangIteratorItem, len(iteratorsByQueue)),
+		crossQueueGangs:         newCrossQueueGangs(),
 	}
 	for queue, queueIt := range iteratorsByQueue {
-		if _, err := it.updateAndPushPQItem(it.newPQItem(queue, queueIt)); err != nil {
+		queueIt.queue = queue
+		queueIt.crossQueueGangs = it.crossQueueGangs
+	}
+	for queue, queueIt := range iteratorsByQueue {
+		item := it.newPQItem(queue, queueIt)
+		it.itemsByQueue[queue] = item
+		if _, err := it.updateAndPushPQItem(item); err != nil {
 			return nil, err
 		}
 	}
+	if err := it.pushQueuesWithCompleteCrossQueueGangs(); err != nil {
+		return nil, err
+	}
 	return it, nil
 }
 
+// pushQueuesWithCompleteCrossQueueGangs pushes the items of queues not in pq that own a complete cross-queue gang,
+// since the iterator of such a queue may have had no gang left to yield before the final member of the gang
+// was received by the iterator of another queue.
+func (it *CandidateGangIterator) pushQueuesWithCompleteCrossQueueGangs() error {
+	for _, queue := range it.crossQueueGangs.queuesWithCompleteGangs() {
+		if item := it.itemsByQueue[queue]; item != nil && item.index == -1 {
+			if _, err := it.updateAndPushPQItem(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (it *CandidateGangIterator) OnlyYieldEvicted() {
 	it.onlyYieldEvicted = true
 }
@@ -337,6 +569,9 @@ func (it *CandidateGangIterator) Clear() error {
 	if _, err := it.updateAndPushPQItem(item); err != nil {
 		return err
 	}
+	if err := it.pushQueuesWithCompleteCrossQueueGangs(); err != nil {
+		return err
+	}
 
 	// If set to only yield evicted gangs, drop any queues for which the next gang is non-evicted here.
 	// We assume here that all evicted jobs appear before non-evicted jobs in the queue.
@@ -366,6 +601,8 @@ func (it *CandidateGangIterator) newPQItem(queue string, queueIt *QueuedGangIter
 	return &QueueCandidateGangIteratorItem{
 		queue: queue,
 		it:    queueIt,
+		// Not in pq until pushed.
+		index: -1,
 	}
 }
 
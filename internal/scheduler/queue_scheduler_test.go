@@ -46,6 +46,9 @@ func TestQueueScheduler(t *testing.T) {
 		ExpectedScheduledIndices []int
 		// Indices of jobs the scheduler should never have tried to schedule.
 		ExpectedNeverAttemptedIndices []int
+		// If non-nil, indices of jobs expected to be failed and the expected code of the reason they failed with.
+		ExpectedFailedIndices []int
+		ExpectedFailureCode   schedulerobjects.UnschedulableReasonCode
 	}{
 		"simple success": {
 			SchedulingConfig:         testfixtures.TestSchedulingConfig(),
@@ -54,6 +57,22 @@ func TestQueueScheduler(t *testing.T) {
 			Jobs:                     testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 32),
 			ExpectedScheduledIndices: testfixtures.IntRange(0, 31),
 		},
+		"gang past its scheduling deadline": {
+			SchedulingConfig:      testfixtures.TestSchedulingConfig(),
+			PriorityFactorByQueue: map[string]float64{"A": 1.0},
+			Nodes:                 testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
+			// Test jobs are submitted at the zero time, i.e., long before the deadline passed.
+			Jobs: armadaslices.Concatenate(
+				testfixtures.WithAnnotationsJobs(
+					map[string]string{configuration.GangSchedulingDeadlineAnnotation: "1h"},
+					testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 2)),
+				),
+				testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 2)),
+			),
+			ExpectedScheduledIndices: testfixtures.IntRange(2, 3),
+			ExpectedFailedIndices:    testfixtures.IntRange(0, 1),
+			ExpectedFailureCode:      schedulerobjects.UnschedulableReasonCodeGangSchedulingDeadlineExceeded,
+		},
 		"simple failure": {
 			SchedulingConfig:         testfixtures.TestSchedulingConfig(),
 			PriorityFactorByQueue:    map[string]float64{"A": 1.0},
@@ -456,6 +475,69 @@ func TestQueueScheduler(t *testing.T) {
 			PriorityFactorByQueue:    map[string]float64{"A": 1},
 			ExpectedScheduledIndices: []int{1},
 		},
+		"cross-queue gang success": {
+			SchedulingConfig: testfixtures.TestSchedulingConfig(),
+			Nodes:            testfixtures.N32CpuNodes(2, testfixtures.TestPriorities),
+			Jobs: armadaslices.Concatenate(
+				testfixtures.WithAnnotationsJobs(map[string]string{
+					configuration.GangIdAnnotation:                 "my-gang",
+					configuration.GangCardinalityAnnotation:        "3",
+					configuration.GangMinimumCardinalityAnnotation: "3",
+					configuration.GangQueueAnnotation:              "A",
+				},
+					armadaslices.Concatenate(
+						testfixtures.N16Cpu128GiJobs("B", testfixtures.PriorityClass0, 2),
+						testfixtures.N32Cpu256GiJobs("A", testfixtures.PriorityClass0, 1),
+					)),
+			),
+			PriorityFactorByQueue:    map[string]float64{"A": 1, "B": 1},
+			ExpectedScheduledIndices: []int{0, 1, 2},
+		},
+		"cross-queue gang failure": {
+			SchedulingConfig: testfixtures.TestSchedulingConfig(),
+			Nodes:            testfixtures.N32CpuNodes(2, testfixtures.TestPriorities),
+			Jobs: armadaslices.Concatenate(
+				testfixtures.WithAnnotationsJobs(map[string]string{
+					configuration.GangIdAnnotation:                 "my-gang",
+					configuration.GangCardinalityAnnotation:        "3",
+					configuration.GangMinimumCardinalityAnnotation: "3",
+					configuration.GangQueueAnnotation:              "A",
+				},
+					armadaslices.Concatenate(
+						testfixtures.N32Cpu256GiJobs("A", testfixtures.PriorityClass0, 1),
+						testfixtures.N32Cpu256GiJobs("B", testfixtures.PriorityClass0, 2),
+					)),
+				testfixtures.N1Cpu4GiJobs("B", testfixtures.PriorityClass0, 1),
+			),
+			PriorityFactorByQueue:    map[string]float64{"A": 1, "B": 1},
+			ExpectedScheduledIndices: []int{3},
+		},
+		"cross-queue gangs with the same id owned by different queues": {
+			SchedulingConfig: testfixtures.TestSchedulingConfig(),
+			Nodes:            testfixtures.N32CpuNodes(2, testfixtures.TestPriorities),
+			Jobs: armadaslices.Concatenate(
+				testfixtures.WithAnnotationsJobs(map[string]string{
+					configuration.GangIdAnnotation:                 "my-gang",
+					configuration.GangCardinalityAnnotation:        "2",
+					configuration.GangMinimumCardinalityAnnotation: "2",
+					configuration.GangQueueAnnotation:              "A",
+				},
+					testfixtures.N16Cpu128GiJobs("B", testfixtures.PriorityClass0, 1),
+				),
+				testfixtures.WithAnnotationsJobs(map[string]string{
+					configuration.GangIdAnnotation:                 "my-gang",
+					configuration.GangCardinalityAnnotation:        "2",
+					configuration.GangMinimumCardinalityAnnotation: "2",
+					configuration.GangQueueAnnotation:              "B",
+				},
+					testfixtures.N16Cpu128GiJobs("B", testfixtures.PriorityClass0, 2),
+				),
+			),
+			PriorityFactorByQueue: map[string]float64{"A": 1, "B": 1},
+			// The gang owned by A only has one of its two members.
+			ExpectedScheduledIndices:      []int{1, 2},
+			ExpectedNeverAttemptedIndices: []int{0},
+		},
 		"job priority": {
 			SchedulingConfig: testfixtures.TestSchedulingConfig(),
 			Nodes:            testfixtures.N32CpuNodes(1, testfixtures.TestPriorities),
@@ -590,6 +672,20 @@ func TestQueueScheduler(t *testing.T) {
 			slices.Sort(actualScheduledIndices)
 			assert.Equal(t, tc.ExpectedScheduledIndices, actualScheduledIndices, "actual scheduled indices does not match expected")
 
+			// Check that the right jobs failed.
+			if tc.ExpectedFailedIndices != nil {
+				var actualFailedIndices []int
+				for _, job := range result.FailedJobs {
+					actualFailedIndices = append(actualFailedIndices, indexByJobId[job.GetId()])
+					assert.Contains(t, result.FailureReasonByJobId, job.GetId())
+					jctx := sctx.QueueSchedulingContexts[job.GetQueue()].UnsuccessfulJobSchedulingContexts[job.GetId()]
+					require.NotNil(t, jctx)
+					assert.Equal(t, tc.ExpectedFailureCode, jctx.UnschedulableReason.GetCode())
+				}
+				slices.Sort(actualFailedIndices)
+				assert.Equal(t, tc.ExpectedFailedIndices, actualFailedIndices, "actual failed indices does not match expected")
+			}
+
 			// Check that the right job scheduling contexts were created.
 			expectedScheduledIndicesByQueue := armadaslices.GroupByFunc(
 				tc.ExpectedScheduledIndices,
@@ -712,3 +808,93 @@ func NewNodeDb() (*nodedb.NodeDb, error) {
 	}
 	return nodeDb, nil
 }
+
+func TestQueuedGangIterator_RemainderGangs(t *testing.T) {
+	tests := map[string]struct {
+		// Number of members of a gang of cardinality 4 that are still queued.
+		NumQueued int
+		// Number of queued members expected by the scheduling context, i.e., the remainder gang cardinality.
+		RemainderCardinality int
+	}{
+		"all remaining members queued": {
+			NumQueued:            2,
+			RemainderCardinality: 2,
+		},
+		"fewer members queued than expected": {
+			NumQueued:            2,
+			RemainderCardinality: 3,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			jobs := testfixtures.WithGangAnnotationsAndMinCardinalityJobs(
+				2,
+				testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 4),
+			)
+			gangId := jobs[0].GetAnnotations()[configuration.GangIdAnnotation]
+			queuedJobs := make([]interfaces.LegacySchedulerJob, tc.NumQueued)
+			for i := range queuedJobs {
+				queuedJobs[i] = jobs[i]
+			}
+			sctx := schedulercontext.NewSchedulingContext(
+				"executor",
+				"pool",
+				testfixtures.TestPriorityClasses,
+				testfixtures.TestDefaultPriorityClass,
+				nil,
+				nil,
+				schedulerobjects.ResourceList{},
+			)
+			sctx.GangRemainderCardinalityByGangId = map[string]int{gangId: tc.RemainderCardinality}
+			it := NewQueuedGangIterator(sctx, NewInMemoryJobIterator(queuedJobs), 0, false)
+
+			gctx, err := it.Next()
+			require.NoError(t, err)
+			require.NotNil(t, gctx)
+			assert.Equal(t, gangId, gctx.ParentGangId)
+			assert.Equal(t, tc.NumQueued, gctx.Cardinality())
+			assert.Equal(t, 1, gctx.GangMinCardinality)
+
+			gctx, err = it.Next()
+			require.NoError(t, err)
+			assert.Nil(t, gctx)
+		})
+	}
+}
+
+func TestQueuedGangIterator_UnfeasibleGangMember(t *testing.T) {
+	cpuJobs := testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass0, 2)
+	gpuJobs := testfixtures.N1GpuJobs("A", testfixtures.PriorityClass0, 1)
+	gang := testfixtures.WithGangAnnotationsJobs(armadaslices.Concatenate(cpuJobs, gpuJobs))
+	jobs := make([]interfaces.LegacySchedulerJob, len(gang))
+	for i, job := range gang {
+		jobs[i] = job
+	}
+	sctx := schedulercontext.NewSchedulingContext(
+		"executor",
+		"pool",
+		testfixtures.TestPriorityClasses,
+		testfixtures.TestDefaultPriorityClass,
+		nil,
+		nil,
+		schedulerobjects.ResourceList{},
+	)
+	require.NoError(t, sctx.AddQueueSchedulingContext("A", 1, nil, nil))
+	// A job with the same requirements as the gpu member was previously found to be unschedulable.
+	gpuSchedulingKey, _ := gpuJobs[0].GetSchedulingKey()
+	sctx.UnfeasibleSchedulingKeys[gpuSchedulingKey] = &schedulercontext.JobSchedulingContext{
+		UnschedulableReason: schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeJobDoesNotFit, "job does not fit on any node"),
+	}
+	it := NewQueuedGangIterator(sctx, NewInMemoryJobIterator(jobs), 0, true)
+
+	// The whole gang is skipped, and each member is given its own reason.
+	gctx, err := it.Next()
+	require.NoError(t, err)
+	assert.Nil(t, gctx)
+	unsuccessful := sctx.QueueSchedulingContexts["A"].UnsuccessfulJobSchedulingContexts
+	require.Len(t, unsuccessful, 3)
+	for _, job := range cpuJobs {
+		assert.Equal(t, schedulerobjects.UnschedulableReasonCodeGangMinCardinalityNotMet, unsuccessful[job.Id()].UnschedulableReason.GetCode())
+	}
+	assert.Equal(t, schedulerobjects.UnschedulableReasonCodeJobDoesNotFit, unsuccessful[gpuJobs[0].Id()].UnschedulableReason.GetCode())
+}
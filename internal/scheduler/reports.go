@@ -7,6 +7,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"text/tabwriter"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/oklog/ulid"
@@ -15,6 +16,7 @@ import (
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 
+	"github.com/armadaproject/armada/internal/common/armadacontext"
 	"github.com/armadaproject/armada/internal/common/armadaerrors"
 	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
 	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
@@ -51,12 +53,27 @@ type SchedulingContextRepository struct {
 	// All executors in sorted order.
 	sortedExecutorIds atomic.Pointer[[]string]
 
+	// Scheduling rounds skipped since their inputs were unchanged, since the most recent context was added.
+	// Nil if no round has been skipped since.
+	unchangedRounds atomic.Pointer[unchangedRoundHeartbeats]
+
+	// Persists reports, such that reports of executors with no context in memory, e.g., after a restart, can be served.
+	// May be nil, in which case reports aren't persisted.
+	persistedReports *PersistedSchedulingReports
+
 	// Protects the fields in this struct from concurrent and dirty writes.
 	mu sync.Mutex
 }
 
 type SchedulingContextByExecutor map[string]*schedulercontext.SchedulingContext
 
+// unchangedRoundHeartbeats records scheduling rounds skipped since their inputs were unchanged,
+// such that reports show the most recent contexts are still current.
+type unchangedRoundHeartbeats struct {
+	numRounds  int
+	mostRecent time.Time
+}
+
 func NewSchedulingContextRepository(jobCacheSize uint) (*SchedulingContextRepository, error) {
 	mostRecentByExecutorByJobId, err := lru.New(int(jobCacheSize))
 	if err != nil {
@@ -90,6 +107,12 @@ func NewSchedulingContextRepository(jobCacheSize uint) (*SchedulingContextReposi
 	return rv, nil
 }
 
+// SetPersistedSchedulingReports sets the component persisting the reports of the scheduling contexts added to the repo.
+// Must be called before any contexts are added.
+func (repo *SchedulingContextRepository) SetPersistedSchedulingReports(persistedReports *PersistedSchedulingReports) {
+	repo.persistedReports = persistedReports
+}
+
 // AddSchedulingContext adds a scheduling context to the repo.
 // It also extracts the queue and job scheduling contexts it contains and stores those separately.
 //
@@ -113,9 +136,37 @@ func (repo *SchedulingContextRepository) AddSchedulingContext(sctx *schedulercon
 	if err := repo.addExecutorId(sctx.ExecutorId); err != nil {
 		return err
 	}
+	if repo.persistedReports != nil {
+		repo.persistedReports.Add(sctx)
+	}
+	repo.unchangedRounds.Store(nil)
 	return nil
 }
 
+// AddUnchangedRoundHeartbeat records a scheduling round at time t that was skipped since its inputs were unchanged,
+// i.e., that would have made the same decisions as the round of the most recent contexts.
+func (repo *SchedulingContextRepository) AddUnchangedRoundHeartbeat(t time.Time) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	heartbeats := &unchangedRoundHeartbeats{numRounds: 1, mostRecent: t}
+	if previous := repo.unchangedRounds.Load(); previous != nil {
+		heartbeats.numRounds += previous.numRounds
+	}
+	repo.unchangedRounds.Store(heartbeats)
+}
+
+// getUnchangedRoundsReportString returns a line describing the rounds skipped since the most recent contexts were added, if any.
+func (repo *SchedulingContextRepository) getUnchangedRoundsReportString() string {
+	heartbeats := repo.unchangedRounds.Load()
+	if heartbeats == nil {
+		return ""
+	}
+	return fmt.Sprintf(
+		"Scheduling rounds skipped since the rounds below, as their inputs were unchanged: %d (most recent at %s)\n",
+		heartbeats.numRounds, heartbeats.mostRecent,
+	)
+}
+
 // Should only be called from AddSchedulingContext to avoid concurrent and/or dirty writes.
 func (repo *SchedulingContextRepository) addExecutorId(executorId string) error {
 	n := len(repo.executorIds)
@@ -271,22 +322,39 @@ func (repo *SchedulingContextRepository) addSchedulingContextForJobs(sctx *sched
 
 // GetSchedulingReport is a gRPC endpoint for querying scheduler reports.
 // TODO: Further separate this from internal contexts.
-func (repo *SchedulingContextRepository) GetSchedulingReport(_ context.Context, request *schedulerobjects.SchedulingReportRequest) (*schedulerobjects.SchedulingReport, error) {
+func (repo *SchedulingContextRepository) GetSchedulingReport(ctx context.Context, request *schedulerobjects.SchedulingReportRequest) (*schedulerobjects.SchedulingReport, error) {
 	var report string
 	verbosity := request.GetVerbosity()
 	switch filter := request.GetFilter().(type) {
 	case *schedulerobjects.SchedulingReportRequest_MostRecentForQueue:
 		queueName := strings.TrimSpace(filter.MostRecentForQueue.GetQueueName())
-		report = repo.getSchedulingReportStringForQueue(queueName, verbosity)
+		report = repo.getUnchangedRoundsReportString()
+		report += repo.getSchedulingReportStringForQueue(queueName, verbosity)
+		report += repo.getPersistedReportString(ctx, queueName)
 	case *schedulerobjects.SchedulingReportRequest_MostRecentForJob:
 		jobId := strings.TrimSpace(filter.MostRecentForJob.GetJobId())
 		report = repo.getSchedulingReportStringForJob(jobId, verbosity)
 	default:
-		report = repo.getSchedulingReportString(verbosity)
+		report = repo.getUnchangedRoundsReportString()
+		report += repo.getSchedulingReportString(verbosity)
+		report += repo.getPersistedReportString(ctx, "")
 	}
 	return &schedulerobjects.SchedulingReport{Report: report}, nil
 }
 
+// getPersistedReportString returns the most recent persisted report, for the given queue or for the round as a whole if queue is empty,
+// of each executor with no scheduling context in memory, if reports are persisted.
+func (repo *SchedulingContextRepository) getPersistedReportString(ctx context.Context, queue string) string {
+	if repo.persistedReports == nil {
+		return ""
+	}
+	report, err := repo.persistedReports.ReportString(armadacontext.FromGrpcCtx(ctx), queue, repo.GetSortedExecutorIds())
+	if err != nil {
+		return fmt.Sprintf("failed to get persisted reports: %s\n", err)
+	}
+	return report
+}
+
 func (repo *SchedulingContextRepository) getSchedulingReportString(verbosity int32) string {
 	mostRecentByExecutor := repo.GetMostRecentSchedulingContextByExecutor()
 	mostRecentSuccessfulByExecutor := repo.GetMostRecentSuccessfulSchedulingContextByExecutor()
@@ -430,11 +498,11 @@ func getSchedulingReportForJob(sctx *schedulercontext.SchedulingContext, jobId s
 
 // GetQueueReport is a gRPC endpoint for querying queue reports.
 // TODO: Further separate this from internal contexts.
-func (repo *SchedulingContextRepository) GetQueueReport(_ context.Context, request *schedulerobjects.QueueReportRequest) (*schedulerobjects.QueueReport, error) {
+func (repo *SchedulingContextRepository) GetQueueReport(ctx context.Context, request *schedulerobjects.QueueReportRequest) (*schedulerobjects.QueueReport, error) {
 	queueName := strings.TrimSpace(request.GetQueueName())
 	verbosity := request.GetVerbosity()
 	return &schedulerobjects.QueueReport{
-		Report: repo.getQueueReportString(queueName, verbosity),
+		Report: repo.getUnchangedRoundsReportString() + repo.getQueueReportString(queueName, verbosity) + repo.getPersistedReportString(ctx, queueName),
 	}, nil
 }
 
@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openconfig/goyang/pkg/indent"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/logging"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/database"
+)
+
+// PersistedSchedulingReports persists the reports of scheduling rounds, and of each queue considered in them,
+// such that reports survive scheduler restarts and failovers, and deletes them once they're older than the configured retention.
+// Reports are rendered and persisted asynchronously by Run, such that persisting reports never slows down scheduling.
+type PersistedSchedulingReports struct {
+	repository database.SchedulingReportRepository
+	config     schedulerconfig.SchedulingReportsConfig
+	// Scheduling contexts waiting to be persisted.
+	pending chan *schedulercontext.SchedulingContext
+	clock   clock.Clock
+}
+
+func NewPersistedSchedulingReports(repository database.SchedulingReportRepository, config schedulerconfig.SchedulingReportsConfig) *PersistedSchedulingReports {
+	maxPendingRounds := config.MaxPendingRounds
+	if maxPendingRounds < 1 {
+		maxPendingRounds = 1
+	}
+	return &PersistedSchedulingReports{
+		repository: repository,
+		config:     config,
+		pending:    make(chan *schedulercontext.SchedulingContext, maxPendingRounds),
+		clock:      clock.RealClock{},
+	}
+}
+
+// Add queues the reports of the round captured by sctx to be persisted. If too many rounds are pending, the reports are dropped.
+// It's not safe to mutate sctx once it's been provided to this method.
+func (p *PersistedSchedulingReports) Add(sctx *schedulercontext.SchedulingContext) {
+	select {
+	case p.pending <- sctx:
+	default:
+		log.Warnf("not persisting reports of scheduling round on executor %s; too many rounds pending", sctx.ExecutorId)
+	}
+}
+
+// Run persists pending reports and deletes expired reports until the context is cancelled.
+func (p *PersistedSchedulingReports) Run(ctx *armadacontext.Context) error {
+	pruneInterval := p.config.PruneInterval
+	if pruneInterval <= 0 {
+		pruneInterval = time.Hour
+	}
+	ticker := p.clock.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case sctx := <-p.pending:
+			if err := p.persist(ctx, sctx); err != nil {
+				logging.WithStacktrace(ctx, err).Warnf("failed to persist reports of scheduling round on executor %s", sctx.ExecutorId)
+			}
+		case <-ticker.C():
+			if err := p.prune(ctx); err != nil {
+				logging.WithStacktrace(ctx, err).Warn("failed to delete expired scheduling reports")
+			}
+		}
+	}
+}
+
+func (p *PersistedSchedulingReports) persist(ctx *armadacontext.Context, sctx *schedulercontext.SchedulingContext) error {
+	reports := make([]database.SchedulingReport, 0, len(sctx.QueueSchedulingContexts)+1)
+	reports = append(reports, database.SchedulingReport{
+		ExecutorID: sctx.ExecutorId,
+		Created:    sctx.Started,
+		Report:     sctx.ReportString(p.config.Verbosity),
+	})
+	queues := maps.Keys(sctx.QueueSchedulingContexts)
+	slices.Sort(queues)
+	for _, queue := range queues {
+		reports = append(reports, database.SchedulingReport{
+			ExecutorID: sctx.ExecutorId,
+			Queue:      queue,
+			Created:    sctx.Started,
+			Report:     sctx.QueueSchedulingContexts[queue].ReportString(p.config.Verbosity),
+		})
+	}
+	return p.repository.StoreSchedulingReports(ctx, reports)
+}
+
+func (p *PersistedSchedulingReports) prune(ctx *armadacontext.Context) error {
+	if p.config.Retention <= 0 {
+		return nil
+	}
+	return p.repository.DeleteSchedulingReportsBefore(ctx, p.clock.Now().Add(-p.config.Retention))
+}
+
+// ReportString returns the most recent persisted report of each executor not in excludedExecutorIds,
+// for the given queue, or for the round as a whole if queue is empty.
+// Used to report on executors the scheduler has no report of in memory, e.g., after a restart.
+func (p *PersistedSchedulingReports) ReportString(ctx *armadacontext.Context, queue string, excludedExecutorIds []string) (string, error) {
+	reports, err := p.repository.GetLatestSchedulingReports(ctx, queue)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 1, 1, 1, ' ', 0)
+	for _, report := range reports {
+		if slices.Contains(excludedExecutorIds, report.ExecutorID) {
+			continue
+		}
+		fmt.Fprintf(w, "%s:\n", report.ExecutorID)
+		if queue == "" {
+			fmt.Fprintf(w, "\tMost recent persisted scheduling round (started %s):\n", report.Created.Format(time.RFC3339))
+		} else {
+			fmt.Fprintf(w, "\tMost recent persisted scheduling round that considered queue %s (started %s):\n", queue, report.Created.Format(time.RFC3339))
+		}
+		fmt.Fprint(w, indent.String("\t\t", report.Report))
+	}
+	w.Flush()
+	return sb.String(), nil
+}
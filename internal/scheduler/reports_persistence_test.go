@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/database"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+type inMemorySchedulingReportRepository struct {
+	mu      sync.Mutex
+	reports []database.SchedulingReport
+}
+
+func (r *inMemorySchedulingReportRepository) StoreSchedulingReports(_ *armadacontext.Context, reports []database.SchedulingReport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, reports...)
+	return nil
+}
+
+func (r *inMemorySchedulingReportRepository) GetLatestSchedulingReports(_ *armadacontext.Context, queue string) ([]database.SchedulingReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	latestByExecutorId := make(map[string]database.SchedulingReport)
+	for _, report := range r.reports {
+		if latest, ok := latestByExecutorId[report.ExecutorID]; report.Queue == queue && (!ok || report.Created.After(latest.Created)) {
+			latestByExecutorId[report.ExecutorID] = report
+		}
+	}
+	var rv []database.SchedulingReport
+	for _, report := range latestByExecutorId {
+		rv = append(rv, report)
+	}
+	slices.SortFunc(rv, func(a, b database.SchedulingReport) bool { return a.ExecutorID < b.ExecutorID })
+	return rv, nil
+}
+
+func (r *inMemorySchedulingReportRepository) DeleteSchedulingReportsBefore(_ *armadacontext.Context, cutoff time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rv []database.SchedulingReport
+	for _, report := range r.reports {
+		if !report.Created.Before(cutoff) {
+			rv = append(rv, report)
+		}
+	}
+	r.reports = rv
+	return nil
+}
+
+func TestPersistedSchedulingReports(t *testing.T) {
+	ctx := armadacontext.Background()
+	reportRepository := &inMemorySchedulingReportRepository{}
+	persistedReports := NewPersistedSchedulingReports(reportRepository, schedulerconfig.SchedulingReportsConfig{Retention: time.Hour, MaxPendingRounds: 1})
+
+	// Reports of a round of a previous scheduler instance.
+	sctx := withSuccessfulJobSchedulingContext(testSchedulingContext("foo"), "A", "successFooA")
+	require.NoError(t, persistedReports.persist(ctx, sctx))
+	require.Len(t, reportRepository.reports, 2)
+	assert.Equal(t, "", reportRepository.reports[0].Queue)
+	assert.Equal(t, "A", reportRepository.reports[1].Queue)
+
+	// Persisted reports are served for executors with no context in memory.
+	repo, err := NewSchedulingContextRepository(10)
+	require.NoError(t, err)
+	repo.SetPersistedSchedulingReports(persistedReports)
+	report, err := repo.GetSchedulingReport(ctx, &schedulerobjects.SchedulingReportRequest{})
+	require.NoError(t, err)
+	assert.Contains(t, report.Report, "foo:")
+	assert.Contains(t, report.Report, "Most recent persisted scheduling round")
+	queueReport, err := repo.GetQueueReport(ctx, &schedulerobjects.QueueReportRequest{QueueName: "A"})
+	require.NoError(t, err)
+	assert.Contains(t, queueReport.Report, "Most recent persisted scheduling round that considered queue A")
+
+	// Once a context is added, it's reported instead, and its reports are queued to be persisted.
+	require.NoError(t, repo.AddSchedulingContext(testSchedulingContext("foo")))
+	report, err = repo.GetSchedulingReport(ctx, &schedulerobjects.SchedulingReportRequest{})
+	require.NoError(t, err)
+	assert.NotContains(t, report.Report, "persisted")
+	assert.Len(t, persistedReports.pending, 1)
+
+	// Further rounds are dropped while too many are pending.
+	persistedReports.Add(testSchedulingContext("bar"))
+	assert.Len(t, persistedReports.pending, 1)
+
+	// Expired reports are deleted.
+	persistedReports.clock = clock.NewFakeClock(sctx.Started.Add(2 * time.Hour))
+	require.NoError(t, persistedReports.prune(ctx))
+	assert.Empty(t, reportRepository.reports)
+}
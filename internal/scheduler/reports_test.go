@@ -197,6 +197,33 @@ func TestTestAddGetSchedulingContextConcurrency(t *testing.T) {
 	<-ctx.Done()
 }
 
+func TestUnchangedRoundHeartbeats(t *testing.T) {
+	repo, err := NewSchedulingContextRepository(1024)
+	require.NoError(t, err)
+	require.NoError(t, repo.AddSchedulingContext(testSchedulingContext("executor-01")))
+	ctx := armadacontext.Background()
+	t0 := time.Date(2023, 11, 15, 6, 0, 0, 0, time.UTC)
+
+	report, err := repo.GetSchedulingReport(ctx, &schedulerobjects.SchedulingReportRequest{})
+	require.NoError(t, err)
+	assert.NotContains(t, report.Report, "inputs were unchanged")
+
+	repo.AddUnchangedRoundHeartbeat(t0)
+	repo.AddUnchangedRoundHeartbeat(t0.Add(time.Second))
+	report, err = repo.GetSchedulingReport(ctx, &schedulerobjects.SchedulingReportRequest{})
+	require.NoError(t, err)
+	assert.Contains(t, report.Report, fmt.Sprintf("inputs were unchanged: 2 (most recent at %s)", t0.Add(time.Second)))
+	queueReport, err := repo.GetQueueReport(ctx, &schedulerobjects.QueueReportRequest{QueueName: "A"})
+	require.NoError(t, err)
+	assert.Contains(t, queueReport.Report, "inputs were unchanged: 2")
+
+	// Heartbeats are reset once a round isn't skipped.
+	require.NoError(t, repo.AddSchedulingContext(testSchedulingContext("executor-01")))
+	report, err = repo.GetSchedulingReport(ctx, &schedulerobjects.SchedulingReportRequest{})
+	require.NoError(t, err)
+	assert.NotContains(t, report.Report, "inputs were unchanged")
+}
+
 func TestReportDoesNotExist(t *testing.T) {
 	repo, err := NewSchedulingContextRepository(1024)
 	require.NoError(t, err)
@@ -293,7 +320,7 @@ func withUnsuccessfulJobSchedulingContext(sctx *schedulercontext.SchedulingConte
 		qctx.SchedulingContext = nil
 		qctx.Created = time.Time{}
 	}
-	qctx.UnsuccessfulJobSchedulingContexts[jobId] = &schedulercontext.JobSchedulingContext{JobId: jobId, UnschedulableReason: "unknown", GangMinCardinality: 1}
+	qctx.UnsuccessfulJobSchedulingContexts[jobId] = &schedulercontext.JobSchedulingContext{JobId: jobId, UnschedulableReason: schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeUnknown, "unknown"), GangMinCardinality: 1}
 	return sctx
 }
 
@@ -0,0 +1,276 @@
+package scheduler
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// roundInputDigests are digests of the inputs of a scheduling round, i.e., the jobs, the executors and their nodes,
+// the queue weights, the queues and job sets excluded from scheduling, and the inputs that change as time passes,
+// such that rounds with equal digests would make the same scheduling decisions.
+// Fields that change without affecting scheduling, e.g., heartbeat times and actual resource usage, are excluded.
+type roundInputDigests struct {
+	// Digest of the executors and their nodes, the queue weights, the exclusions, the jobs that aren't queued,
+	// and the time-dependent inputs affecting all queues.
+	cluster uint64
+	// Digest of the queued jobs of each queue, indexed by queue name.
+	queuedJobsByQueue map[string]uint64
+}
+
+// roundTimeDependentInputs are the inputs of a scheduling round that change as time passes, rather than as jobs,
+// executors, or queues change. Exclusions expiring and drains starting are already accounted for,
+// since expired exclusions and executors being drained are removed from the context of the round.
+type roundTimeDependentInputs struct {
+	// Time at which the round started, at which gang scheduling deadlines are evaluated.
+	now time.Time
+	// Action taken for each queue with an exhausted budget.
+	exhaustedBudgetActionByQueue map[string]BudgetAction
+	// Factor by which the weight of each queue credited for preemption is multiplied, indexed by pool and queue.
+	weightMultiplierByPoolAndQueue map[string]map[string]float64
+	// Nodes reserved for gangs accumulating capacity.
+	gangReservations []GangReservation
+	// Ids of the gangs of gangReservations whose reservation timed out.
+	timedOutGangIds map[string]bool
+}
+
+// roundTimeDependentInputs returns the time-dependent inputs of a round starting now.
+func (l *FairSchedulingAlgo) roundTimeDependentInputs(now time.Time) *roundTimeDependentInputs {
+	rv := &roundTimeDependentInputs{now: now}
+	if l.budgetTracker != nil {
+		rv.exhaustedBudgetActionByQueue = l.budgetTracker.ExhaustedActionByQueue()
+	}
+	if l.preemptionCompensationLedger != nil {
+		// Credit decays continuously, so rounds are only ever skipped once the weight boost of each queue is capped or has decayed away.
+		rv.weightMultiplierByPoolAndQueue = make(map[string]map[string]float64)
+		for pool, creditByQueue := range l.preemptionCompensationLedger.Credits() {
+			rv.weightMultiplierByPoolAndQueue[pool] = make(map[string]float64, len(creditByQueue))
+			for queue := range creditByQueue {
+				rv.weightMultiplierByPoolAndQueue[pool][queue] = l.preemptionCompensationLedger.WeightMultiplier(pool, queue)
+			}
+		}
+	}
+	if l.gangAccumulator != nil {
+		rv.gangReservations = l.gangAccumulator.Reservations()
+		rv.timedOutGangIds = make(map[string]bool)
+		for _, reservation := range rv.gangReservations {
+			if l.gangAccumulator.isTimedOut(reservation, now) {
+				rv.timedOutGangIds[reservation.GangId] = true
+			}
+		}
+	}
+	return rv
+}
+
+// newRoundInputDigests computes the digests of the inputs of the round described by fsctx and inputs.
+// Jobs, executors, and queues are digested individually and the results summed, such that the digests don't depend on iteration order.
+func newRoundInputDigests(fsctx *fairSchedulingAlgoContext, inputs *roundTimeDependentInputs) *roundInputDigests {
+	d := &roundInputDigests{queuedJobsByQueue: make(map[string]uint64)}
+	h := fnv.New64a()
+	for _, job := range fsctx.txn.GetAll() {
+		h.Reset()
+		writeJob(h, job)
+		if job.Queued() {
+			// Queued gangs are failed once their deadline passes, so the queue is considered again once it does.
+			writeBool(h, gangSchedulingDeadlineExceeded(job, inputs.now))
+			d.queuedJobsByQueue[job.Queue()] += h.Sum64()
+		} else {
+			d.cluster += h.Sum64()
+		}
+	}
+	for _, executor := range fsctx.executors {
+		h.Reset()
+		writeExecutor(h, executor)
+		d.cluster += h.Sum64()
+	}
+	for queue, priorityFactor := range fsctx.priorityFactorByQueue {
+		h.Reset()
+		writeString(h, queue)
+		writeUint64(h, math.Float64bits(priorityFactor))
+		d.cluster += h.Sum64()
+	}
+	// Any change to the exclusions results in a full round, such that the jobs of queues no longer excluded are considered.
+	for queue, jobSets := range fsctx.exclusions {
+		for jobSet := range jobSets {
+			h.Reset()
+			writeString(h, queue)
+			writeString(h, jobSet)
+			d.cluster += h.Sum64()
+		}
+	}
+	h.Reset()
+	writeTimeDependentInputs(h, inputs)
+	d.cluster += h.Sum64()
+	return d
+}
+
+// gangSchedulingDeadlineExceeded returns true if job sets a gang scheduling deadline that's passed at now.
+// Since all jobs of a gang set the same deadline, and the deadline of a gang counts from the submission of its first job,
+// the deadline of a gang has passed if and only if that of any of its jobs has.
+func gangSchedulingDeadlineExceeded(job *jobdb.Job, now time.Time) bool {
+	value, ok := job.GetAnnotations()[configuration.GangSchedulingDeadlineAnnotation]
+	if !ok {
+		return false
+	}
+	deadline, err := time.ParseDuration(value)
+	return err == nil && deadline > 0 && now.Sub(job.GetSubmitTime()) > deadline
+}
+
+// Equal returns true if d and other are digests of the same inputs. Nil digests are equal to no other digests.
+func (d *roundInputDigests) Equal(other *roundInputDigests) bool {
+	if d == nil || other == nil {
+		return false
+	}
+	if d.cluster != other.cluster || len(d.queuedJobsByQueue) != len(other.queuedJobsByQueue) {
+		return false
+	}
+	for queue, digest := range d.queuedJobsByQueue {
+		if otherDigest, ok := other.queuedJobsByQueue[queue]; !ok || otherDigest != digest {
+			return false
+		}
+	}
+	return true
+}
+
+// UnchangedQueues returns the queues with the same queued jobs in d and other, provided the cluster digests are equal,
+// i.e., the queues whose queued jobs need not be considered again if they weren't scheduled in the round of other.
+// Returns nil if the cluster digests differ, or if either d or other is nil.
+func (d *roundInputDigests) UnchangedQueues(other *roundInputDigests) map[string]bool {
+	if d == nil || other == nil || d.cluster != other.cluster {
+		return nil
+	}
+	rv := make(map[string]bool)
+	for queue, digest := range d.queuedJobsByQueue {
+		if otherDigest, ok := other.queuedJobsByQueue[queue]; ok && otherDigest == digest {
+			rv[queue] = true
+		}
+	}
+	return rv
+}
+
+func writeJob(h hash.Hash64, job *jobdb.Job) {
+	writeString(h, job.Id())
+	writeBool(h, job.Queued())
+	writeUint64(h, uint64(job.QueuedVersion()))
+	writeUint64(h, uint64(job.Priority()))
+	writeBool(h, job.CancelRequested())
+	writeBool(h, job.CancelByJobsetRequested())
+	writeBool(h, job.InTerminalState())
+	writeUint64(h, uint64(job.JobSchedulingInfo().GetVersion()))
+	if run := job.LatestRun(); run != nil {
+		writeString(h, run.Id().String())
+		writeString(h, run.NodeId())
+		writeBool(h, run.Running())
+		writeBool(h, run.Returned())
+		writeBool(h, run.Preempted())
+		writeBool(h, run.InTerminalState())
+	}
+}
+
+func writeTimeDependentInputs(h hash.Hash64, inputs *roundTimeDependentInputs) {
+	queues := maps.Keys(inputs.exhaustedBudgetActionByQueue)
+	slices.Sort(queues)
+	for _, queue := range queues {
+		writeString(h, queue)
+		writeString(h, string(inputs.exhaustedBudgetActionByQueue[queue]))
+	}
+	pools := maps.Keys(inputs.weightMultiplierByPoolAndQueue)
+	slices.Sort(pools)
+	for _, pool := range pools {
+		writeString(h, pool)
+		queues := maps.Keys(inputs.weightMultiplierByPoolAndQueue[pool])
+		slices.Sort(queues)
+		for _, queue := range queues {
+			writeString(h, queue)
+			writeUint64(h, math.Float64bits(inputs.weightMultiplierByPoolAndQueue[pool][queue]))
+		}
+	}
+	// Reservations are sorted by pool and gang id.
+	for _, reservation := range inputs.gangReservations {
+		writeString(h, reservation.Pool)
+		writeString(h, reservation.GangId)
+		writeStrings(h, reservation.NodeIds)
+		writeBool(h, inputs.timedOutGangIds[reservation.GangId])
+	}
+}
+
+func writeExecutor(h hash.Hash64, executor *schedulerobjects.Executor) {
+	writeString(h, executor.Id)
+	writeString(h, executor.Pool)
+	writeResourceList(h, executor.MinimumJobSize)
+	writeString(h, executor.KubernetesVersion)
+	writeStrings(h, executor.RuntimeClasses)
+	writeStrings(h, executor.Features)
+	writeStrings(h, executor.UnassignedJobRuns)
+	for _, node := range executor.Nodes {
+		writeString(h, node.Id)
+		writeBool(h, node.Unschedulable)
+		writeResourceList(h, node.TotalResources)
+		for _, taint := range node.Taints {
+			writeString(h, taint.ToString())
+		}
+		keys := maps.Keys(node.Labels)
+		slices.Sort(keys)
+		for _, key := range keys {
+			writeString(h, key)
+			writeString(h, node.Labels[key])
+		}
+		runIds := maps.Keys(node.StateByJobRunId)
+		slices.Sort(runIds)
+		for _, runId := range runIds {
+			writeString(h, runId)
+			writeUint64(h, uint64(node.StateByJobRunId[runId]))
+		}
+		priorities := maps.Keys(node.NonArmadaAllocatedResources)
+		slices.Sort(priorities)
+		for _, priority := range priorities {
+			writeUint64(h, uint64(priority))
+			writeResourceList(h, node.NonArmadaAllocatedResources[priority])
+		}
+	}
+}
+
+func writeResourceList(h hash.Hash64, rl schedulerobjects.ResourceList) {
+	resourceTypes := maps.Keys(rl.Resources)
+	slices.Sort(resourceTypes)
+	for _, t := range resourceTypes {
+		q := rl.Resources[t]
+		writeString(h, t)
+		writeString(h, q.String())
+	}
+}
+
+func writeStrings(h hash.Hash64, ss []string) {
+	for _, s := range ss {
+		writeString(h, s)
+	}
+}
+
+func writeString(h hash.Hash64, s string) {
+	// Length-prefixed, such that, e.g., ("ab", "c") and ("a", "bc") are digested differently.
+	writeUint64(h, uint64(len(s)))
+	_, _ = h.Write([]byte(s))
+}
+
+func writeBool(h hash.Hash64, b bool) {
+	if b {
+		_, _ = h.Write([]byte{1})
+	} else {
+		_, _ = h.Write([]byte{0})
+	}
+}
+
+func writeUint64(h hash.Hash64, v uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	_, _ = h.Write(buf[:])
+}
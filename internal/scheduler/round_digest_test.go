@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	armadaslices "github.com/armadaproject/armada/internal/common/slices"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestRoundInputDigests(t *testing.T) {
+	queuedJobsA := testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass3, 2)
+	queuedJobsB := testfixtures.N1Cpu4GiJobs("B", testfixtures.PriorityClass3, 2)
+	newDigests := func(jobs []*jobdb.Job, executor *schedulerobjects.Executor) *roundInputDigests {
+		txn := testfixtures.NewJobDb().WriteTxn()
+		for _, job := range jobs {
+			require.NoError(t, txn.Upsert([]*jobdb.Job{job.WithQueued(true)}))
+		}
+		return newRoundInputDigests(&fairSchedulingAlgoContext{
+			txn:                   txn,
+			executors:             []*schedulerobjects.Executor{executor},
+			priorityFactorByQueue: map[string]float64{"A": 1, "B": 1},
+		}, &roundTimeDependentInputs{now: testfixtures.BaseTime})
+	}
+	executor := testfixtures.Test1Node32CoreExecutor("executor1")
+	digests := newDigests(armadaslices.Concatenate(queuedJobsA, queuedJobsB), executor)
+
+	// Digests don't depend on the order of jobs or on heartbeat times.
+	otherExecutor := proto.Clone(executor).(*schedulerobjects.Executor)
+	otherExecutor.LastUpdateTime = testfixtures.BaseTime.Add(time.Minute)
+	otherDigests := newDigests(armadaslices.Concatenate(queuedJobsB, queuedJobsA), otherExecutor)
+	assert.True(t, digests.Equal(otherDigests))
+	assert.Equal(t, map[string]bool{"A": true, "B": true}, digests.UnchangedQueues(otherDigests))
+
+	// Only queue B changed.
+	otherDigests = newDigests(armadaslices.Concatenate(queuedJobsA, queuedJobsB[:1]), executor)
+	assert.False(t, digests.Equal(otherDigests))
+	assert.Equal(t, map[string]bool{"A": true}, digests.UnchangedQueues(otherDigests))
+
+	// The cluster changed.
+	otherExecutor = proto.Clone(executor).(*schedulerobjects.Executor)
+	otherExecutor.Nodes[0].Unschedulable = true
+	otherDigests = newDigests(armadaslices.Concatenate(queuedJobsA, queuedJobsB), otherExecutor)
+	assert.False(t, digests.Equal(otherDigests))
+	assert.Nil(t, digests.UnchangedQueues(otherDigests))
+
+	assert.False(t, digests.Equal(nil))
+	assert.Nil(t, digests.UnchangedQueues(nil))
+}
+
+func TestRoundInputDigests_TimeDependentInputs(t *testing.T) {
+	gangJobs := testfixtures.WithAnnotationsJobs(
+		map[string]string{configuration.GangSchedulingDeadlineAnnotation: "1h"},
+		testfixtures.WithGangAnnotationsJobs(testfixtures.N1Cpu4GiJobs("A", testfixtures.PriorityClass3, 2)),
+	)
+	txn := testfixtures.NewJobDb().WriteTxn()
+	for _, job := range gangJobs {
+		require.NoError(t, txn.Upsert([]*jobdb.Job{job.WithQueued(true)}))
+	}
+	fsctx := &fairSchedulingAlgoContext{
+		txn:                   txn,
+		executors:             []*schedulerobjects.Executor{testfixtures.Test1Node32CoreExecutor("executor1")},
+		priorityFactorByQueue: map[string]float64{"A": 1},
+	}
+	submitted := gangJobs[0].GetSubmitTime()
+	digests := newRoundInputDigests(fsctx, &roundTimeDependentInputs{now: submitted.Add(time.Minute)})
+	assert.True(t, digests.Equal(newRoundInputDigests(fsctx, &roundTimeDependentInputs{now: submitted.Add(time.Hour)})))
+
+	// Queues with gangs whose scheduling deadline passed are considered again.
+	otherDigests := newRoundInputDigests(fsctx, &roundTimeDependentInputs{now: submitted.Add(2 * time.Hour)})
+	assert.False(t, digests.Equal(otherDigests))
+	assert.Empty(t, digests.UnchangedQueues(otherDigests))
+
+	// Budgets being exhausted, preemption compensation decaying, and gang reservations timing out change all queues.
+	for _, inputs := range []*roundTimeDependentInputs{
+		{now: submitted.Add(time.Minute), exhaustedBudgetActionByQueue: map[string]BudgetAction{"A": BudgetActionBlock}},
+		{now: submitted.Add(time.Minute), weightMultiplierByPoolAndQueue: map[string]map[string]float64{"pool": {"A": 1.5}}},
+		{now: submitted.Add(time.Minute), gangReservations: []GangReservation{{GangId: "gang", Pool: "pool", NodeIds: []string{"node"}}}},
+		{
+			now:              submitted.Add(time.Minute),
+			gangReservations: []GangReservation{{GangId: "gang", Pool: "pool", NodeIds: []string{"node"}}},
+			timedOutGangIds:  map[string]bool{"gang": true},
+		},
+	} {
+		otherDigests := newRoundInputDigests(fsctx, inputs)
+		assert.False(t, digests.Equal(otherDigests))
+		assert.Nil(t, digests.UnchangedQueues(otherDigests))
+	}
+}
@@ -0,0 +1,211 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// RoundRegressionDetector compares the report of each scheduling round with that of the previous round
+// on the same executor group and flags anomalies likely caused by a configuration or code change,
+// e.g., a sudden drop in the number of jobs scheduled or a spike in jobs unschedulable for a single reason.
+// Alerts include the difference between the two rounds, such that operators can tell what changed.
+type RoundRegressionDetector struct {
+	config schedulerconfig.RoundRegressionConfig
+	// Summary of the previous round, by the pool and executor scheduled.
+	previousSummaryByExecutorGroup map[executorGroupKey]*roundSummary
+}
+
+// roundSummary is the part of a round report compared across rounds.
+type roundSummary struct {
+	numScheduledJobs             int
+	numUnschedulableJobsByReason map[schedulerobjects.UnschedulableReasonCode]int
+	// Share and fair share of each queue, and whether the queue had jobs it couldn't schedule.
+	shareByQueue     map[string]float64
+	fairShareByQueue map[string]float64
+	hasDemandByQueue map[string]bool
+}
+
+func NewRoundRegressionDetector(config schedulerconfig.RoundRegressionConfig) *RoundRegressionDetector {
+	return &RoundRegressionDetector{
+		config:                         config,
+		previousSummaryByExecutorGroup: make(map[executorGroupKey]*roundSummary),
+	}
+}
+
+// Detect returns a round regression alert if the round captured by sctx regressed compared to the previous round
+// on the same executor group, and records the round for comparison with the next one.
+func (d *RoundRegressionDetector) Detect(sctx *schedulercontext.SchedulingContext) []Alert {
+	key := executorGroupKey{pool: sctx.Pool, executorId: sctx.ExecutorId}
+	summary := newRoundSummary(sctx)
+	previous := d.previousSummaryByExecutorGroup[key]
+	d.previousSummaryByExecutorGroup[key] = summary
+	if previous == nil {
+		return nil
+	}
+
+	var anomalies []string
+	if anomaly, ok := d.scheduledJobsDrop(previous, summary); ok {
+		anomalies = append(anomalies, anomaly)
+	}
+	anomalies = append(anomalies, d.unschedulableReasonSpikes(previous, summary)...)
+	anomalies = append(anomalies, d.fairShareInversions(previous, summary)...)
+	if len(anomalies) == 0 {
+		return nil
+	}
+	return []Alert{{
+		Type:    AlertRoundRegression,
+		Pool:    sctx.Pool,
+		Subject: sctx.ExecutorId,
+		Text: fmt.Sprintf(
+			"Scheduling round on executor %s in pool %s regressed compared to the previous round:\n%s\nChanges since the previous round:\n%s",
+			sctx.ExecutorId, sctx.Pool, strings.Join(anomalies, "\n"), previous.diff(summary),
+		),
+	}}
+}
+
+// scheduledJobsDrop flags rounds scheduling far fewer jobs than the previous round while jobs remain unschedulable,
+// i.e., where the drop isn't explained by a lack of demand.
+func (d *RoundRegressionDetector) scheduledJobsDrop(previous, current *roundSummary) (string, bool) {
+	if d.config.ScheduledJobsDropFraction <= 0 || previous.numScheduledJobs == 0 || previous.numScheduledJobs < d.config.MinJobs {
+		return "", false
+	}
+	if current.numUnschedulableJobs() == 0 {
+		return "", false
+	}
+	if float64(current.numScheduledJobs) > float64(previous.numScheduledJobs)*(1-d.config.ScheduledJobsDropFraction) {
+		return "", false
+	}
+	return fmt.Sprintf(
+		"the number of jobs scheduled dropped from %d to %d while %d jobs were unschedulable",
+		previous.numScheduledJobs, current.numScheduledJobs, current.numUnschedulableJobs(),
+	), true
+}
+
+// unschedulableReasonSpikes flags reasons for which far more jobs were unschedulable than in the previous round.
+func (d *RoundRegressionDetector) unschedulableReasonSpikes(previous, current *roundSummary) []string {
+	if d.config.UnschedulableReasonSpikeFactor <= 0 {
+		return nil
+	}
+	reasons := maps.Keys(current.numUnschedulableJobsByReason)
+	slices.Sort(reasons)
+	var rv []string
+	for _, reason := range reasons {
+		n := current.numUnschedulableJobsByReason[reason]
+		previousN := previous.numUnschedulableJobsByReason[reason]
+		// Reasons not seen in the previous round spike if they apply to at least MinJobs jobs.
+		baseline := float64(previousN)
+		if baseline < 1 {
+			baseline = 1
+		}
+		if n < d.config.MinJobs || float64(n) < baseline*d.config.UnschedulableReasonSpikeFactor {
+			continue
+		}
+		rv = append(rv, fmt.Sprintf("the number of jobs unschedulable with reason %s grew from %d to %d", reason, previousN, n))
+	}
+	return rv
+}
+
+// fairShareInversions flags pairs of queues with demand where the queue with the larger fair share
+// has the smaller share of the pool, but didn't in the previous round.
+func (d *RoundRegressionDetector) fairShareInversions(previous, current *roundSummary) []string {
+	if !d.config.FairShareInversion {
+		return nil
+	}
+	queues := maps.Keys(current.shareByQueue)
+	slices.Sort(queues)
+	var rv []string
+	for _, a := range queues {
+		for _, b := range queues {
+			if !current.isInverted(a, b) || !previous.hasDemandByQueue[a] || !previous.hasDemandByQueue[b] || previous.isInverted(a, b) {
+				continue
+			}
+			rv = append(rv, fmt.Sprintf(
+				"queue %s has a share of %.3f, less than the share of %.3f of queue %s, despite its larger fair share (%.3f against %.3f)",
+				a, current.shareByQueue[a], current.shareByQueue[b], b, current.fairShareByQueue[a], current.fairShareByQueue[b],
+			))
+		}
+	}
+	return rv
+}
+
+func newRoundSummary(sctx *schedulercontext.SchedulingContext) *roundSummary {
+	rv := &roundSummary{
+		numScheduledJobs:             sctx.NumScheduledJobs,
+		numUnschedulableJobsByReason: make(map[schedulerobjects.UnschedulableReasonCode]int),
+		shareByQueue:                 make(map[string]float64, len(sctx.QueueSchedulingContexts)),
+		fairShareByQueue:             make(map[string]float64, len(sctx.QueueSchedulingContexts)),
+		hasDemandByQueue:             make(map[string]bool, len(sctx.QueueSchedulingContexts)),
+	}
+	for queue, qctx := range sctx.QueueSchedulingContexts {
+		for _, jctx := range qctx.UnsuccessfulJobSchedulingContexts {
+			code := jctx.UnschedulableReason.GetCode()
+			if code == "" {
+				code = schedulerobjects.UnschedulableReasonCodeUnknown
+			}
+			rv.numUnschedulableJobsByReason[code]++
+		}
+		for code, n := range qctx.NumDiscardedUnsuccessfulJobsByReasonCode {
+			rv.numUnschedulableJobsByReason[code] += n
+		}
+		rv.hasDemandByQueue[queue] = len(qctx.UnsuccessfulJobSchedulingContexts) > 0
+		if sctx.WeightSum > 0 && sctx.FairnessCostProvider != nil {
+			rv.shareByQueue[queue] = sctx.FairnessCostProvider.CostFromAllocationAndWeight(qctx.Allocated, 1)
+			rv.fairShareByQueue[queue] = qctx.Weight / sctx.WeightSum
+		}
+	}
+	return rv
+}
+
+func (s *roundSummary) numUnschedulableJobs() int {
+	rv := 0
+	for _, n := range s.numUnschedulableJobsByReason {
+		rv += n
+	}
+	return rv
+}
+
+// isInverted returns true if queues a and b both have demand and a has a larger fair share than b, but a smaller share.
+func (s *roundSummary) isInverted(a, b string) bool {
+	return s.hasDemandByQueue[a] && s.hasDemandByQueue[b] &&
+		s.fairShareByQueue[a] > s.fairShareByQueue[b] &&
+		s.shareByQueue[a] < s.shareByQueue[b]
+}
+
+// diff returns a human-readable description of the differences between s and other, one per line.
+func (s *roundSummary) diff(other *roundSummary) string {
+	var lines []string
+	if s.numScheduledJobs != other.numScheduledJobs {
+		lines = append(lines, fmt.Sprintf("scheduled jobs: %d -> %d", s.numScheduledJobs, other.numScheduledJobs))
+	}
+	reasons := append(maps.Keys(s.numUnschedulableJobsByReason), maps.Keys(other.numUnschedulableJobsByReason)...)
+	slices.Sort(reasons)
+	reasons = slices.Compact(reasons)
+	for _, reason := range reasons {
+		if n, otherN := s.numUnschedulableJobsByReason[reason], other.numUnschedulableJobsByReason[reason]; n != otherN {
+			lines = append(lines, fmt.Sprintf("unschedulable jobs (%s): %d -> %d", reason, n, otherN))
+		}
+	}
+	queues := append(maps.Keys(s.shareByQueue), maps.Keys(other.shareByQueue)...)
+	slices.Sort(queues)
+	queues = slices.Compact(queues)
+	for _, queue := range queues {
+		share, otherShare := s.shareByQueue[queue], other.shareByQueue[queue]
+		if fmt.Sprintf("%.3f", share) != fmt.Sprintf("%.3f", otherShare) {
+			lines = append(lines, fmt.Sprintf(
+				"share of queue %s: %.3f -> %.3f (fair share %.3f)",
+				queue, share, otherShare, other.fairShareByQueue[queue],
+			))
+		}
+	}
+	if len(lines) == 0 {
+		return "none"
+	}
+	return strings.Join(lines, "\n")
+}
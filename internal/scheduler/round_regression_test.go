@@ -0,0 +1,116 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/fairness"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestRoundRegressionDetector(t *testing.T) {
+	// Returns the report of a round in which queue-a, with weight 2, has cpuA allocated, and queue-b, with weight 1, has cpuB allocated.
+	// Both queues have unschedulable jobs, numUnschedulable of which with reason JobDoesNotFit.
+	newSctx := func(numScheduledJobs int, numUnschedulable int, cpuA string, cpuB string) *schedulercontext.SchedulingContext {
+		totalResources := schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse("10")}}
+		fairnessCostProvider, err := fairness.NewDominantResourceFairness(totalResources, []string{"cpu"})
+		require.NoError(t, err)
+		sctx := schedulercontext.NewSchedulingContext(
+			"executor",
+			"pool",
+			testfixtures.TestPriorityClasses,
+			testfixtures.TestDefaultPriorityClass,
+			fairnessCostProvider,
+			nil,
+			totalResources,
+		)
+		for queue, cpu := range map[string]string{"queue-a": cpuA, "queue-b": cpuB} {
+			weight := 1.0
+			if queue == "queue-a" {
+				weight = 2
+			}
+			err = sctx.AddQueueSchedulingContext(
+				queue,
+				weight,
+				schedulerobjects.QuantityByTAndResourceType[string]{
+					testfixtures.TestDefaultPriorityClass: schedulerobjects.ResourceList{Resources: map[string]resource.Quantity{"cpu": resource.MustParse(cpu)}},
+				},
+				nil,
+			)
+			require.NoError(t, err)
+			sctx.QueueSchedulingContexts[queue].UnsuccessfulJobSchedulingContexts["job-"+queue] = &schedulercontext.JobSchedulingContext{}
+		}
+		for i := 0; i < numUnschedulable; i++ {
+			sctx.QueueSchedulingContexts["queue-a"].UnsuccessfulJobSchedulingContexts[string(rune('A'+i))] = &schedulercontext.JobSchedulingContext{
+				UnschedulableReason: schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeJobDoesNotFit, "job does not fit on any node"),
+			}
+		}
+		sctx.NumScheduledJobs = numScheduledJobs
+		return sctx
+	}
+
+	tests := map[string]struct {
+		config        schedulerconfig.RoundRegressionConfig
+		previous      *schedulercontext.SchedulingContext
+		current       *schedulercontext.SchedulingContext
+		expectAnomaly bool
+		expectInDiff  string
+	}{
+		"unchanged": {
+			config:   schedulerconfig.RoundRegressionConfig{ScheduledJobsDropFraction: 0.5, UnschedulableReasonSpikeFactor: 2, FairShareInversion: true},
+			previous: newSctx(10, 1, "6", "2"),
+			current:  newSctx(10, 1, "6", "2"),
+		},
+		"scheduled jobs drop": {
+			config:        schedulerconfig.RoundRegressionConfig{ScheduledJobsDropFraction: 0.5, MinJobs: 5},
+			previous:      newSctx(10, 0, "6", "2"),
+			current:       newSctx(2, 0, "6", "2"),
+			expectAnomaly: true,
+			expectInDiff:  "scheduled jobs: 10 -> 2",
+		},
+		"scheduled jobs drop below minimum jobs": {
+			config:   schedulerconfig.RoundRegressionConfig{ScheduledJobsDropFraction: 0.5, MinJobs: 20},
+			previous: newSctx(10, 0, "6", "2"),
+			current:  newSctx(2, 0, "6", "2"),
+		},
+		"unschedulable reason spike": {
+			config:        schedulerconfig.RoundRegressionConfig{UnschedulableReasonSpikeFactor: 5, MinJobs: 5},
+			previous:      newSctx(10, 1, "6", "2"),
+			current:       newSctx(10, 6, "6", "2"),
+			expectAnomaly: true,
+			expectInDiff:  "unschedulable jobs (JobDoesNotFit): 1 -> 6",
+		},
+		"fair share inversion": {
+			config:        schedulerconfig.RoundRegressionConfig{FairShareInversion: true},
+			previous:      newSctx(10, 0, "6", "2"),
+			current:       newSctx(10, 0, "2", "6"),
+			expectAnomaly: true,
+			expectInDiff:  "share of queue queue-a: 0.600 -> 0.200",
+		},
+		"fair share inversion disabled": {
+			previous: newSctx(10, 0, "6", "2"),
+			current:  newSctx(10, 0, "2", "6"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			detector := NewRoundRegressionDetector(tc.config)
+			assert.Empty(t, detector.Detect(tc.previous))
+			alerts := detector.Detect(tc.current)
+			if !tc.expectAnomaly {
+				assert.Empty(t, alerts)
+				return
+			}
+			require.Len(t, alerts, 1)
+			assert.Equal(t, AlertRoundRegression, alerts[0].Type)
+			assert.Equal(t, "pool", alerts[0].Pool)
+			assert.Contains(t, alerts[0].Text, tc.expectInDiff)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/scheduler/database"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+)
+
+// RunAttemptsHttpHandler serves the run attempt history of jobs in the jobDb as json.
+// The job to look up is given by the jobId query parameter, e.g., /runAttempts?jobId=01gkv9jxhzw3n8wv5d3y1qm6ve.
+type RunAttemptsHttpHandler struct {
+	jobDb              *jobdb.JobDb
+	executorRepository database.ExecutorRepository
+}
+
+// JobRunAttempts is the response returned by RunAttemptsHttpHandler.
+type JobRunAttempts struct {
+	JobId    string           `json:"jobId"`
+	Queue    string           `json:"queue"`
+	JobSet   string           `json:"jobSet"`
+	Attempts []PoolRunAttempt `json:"attempts"`
+}
+
+// PoolRunAttempt is a run attempt annotated with the pool of the executor the run was leased to.
+type PoolRunAttempt struct {
+	jobdb.RunAttempt
+	Pool string `json:"pool,omitempty"`
+}
+
+func NewRunAttemptsHttpHandler(jobDb *jobdb.JobDb, executorRepository database.ExecutorRepository) *RunAttemptsHttpHandler {
+	return &RunAttemptsHttpHandler{
+		jobDb:              jobDb,
+		executorRepository: executorRepository,
+	}
+}
+
+func (h *RunAttemptsHttpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	jobId := r.URL.Query().Get("jobId")
+	if jobId == "" {
+		http.Error(w, "missing jobId query parameter", http.StatusBadRequest)
+		return
+	}
+	job := h.jobDb.ReadTxn().GetById(jobId)
+	if job == nil {
+		http.Error(w, "job "+jobId+" not found", http.StatusNotFound)
+		return
+	}
+	poolByExecutor, err := h.poolByExecutor(armadacontext.New(r.Context(), log.NewEntry(log.StandardLogger())))
+	if err != nil {
+		// The pool is informational only; return the attempts without it.
+		log.WithError(err).Warn("failed to look up executor pools")
+	}
+	rv := JobRunAttempts{
+		JobId:  job.Id(),
+		Queue:  job.Queue(),
+		JobSet: job.Jobset(),
+	}
+	for _, attempt := range job.RunAttempts() {
+		rv.Attempts = append(rv.Attempts, PoolRunAttempt{
+			RunAttempt: attempt,
+			Pool:       poolByExecutor[attempt.Executor],
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rv); err != nil {
+		log.WithError(err).Error("failed to write run attempts response")
+	}
+}
+
+func (h *RunAttemptsHttpHandler) poolByExecutor(ctx *armadacontext.Context) (map[string]string, error) {
+	executors, err := h.executorRepository.GetExecutors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rv := make(map[string]string, len(executors))
+	for _, executor := range executors {
+		rv[executor.Id] = executor.Pool
+	}
+	return rv, nil
+}
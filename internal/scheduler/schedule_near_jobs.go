@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/kubernetesobjects/affinity"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// ScheduleNearJobIdsFromAnnotations returns the ids of the jobs given via configuration.ScheduleNearJobsAnnotation, if any.
+func ScheduleNearJobIdsFromAnnotations(annotations map[string]string) []string {
+	value, ok := annotations[configuration.ScheduleNearJobsAnnotation]
+	if !ok {
+		return nil
+	}
+	var jobIds []string
+	for _, jobId := range strings.Split(value, ",") {
+		if jobId = strings.TrimSpace(jobId); jobId != "" {
+			jobIds = append(jobIds, jobId)
+		}
+	}
+	return jobIds
+}
+
+// SetScheduleNearJobsPreferenceWeight sets the weight of the node preference given to jobs asking to be scheduled near other jobs
+// via configuration.ScheduleNearJobsAnnotation. If zero, the annotation is ignored.
+func (s *Scheduler) SetScheduleNearJobsPreferenceWeight(weight int32) {
+	s.scheduleNearJobsPreferenceWeight = weight
+}
+
+// addScheduleNearJobsPreferences gives each queued job in jobsById asking to be scheduled near other jobs a preferred node affinity
+// for the nodes those jobs most recently ran on, e.g., such that jobs reading the same data are scheduled where that data is cached.
+// Nodes are looked up in the jobDb for jobs that are still active and otherwise in the run history stored in postgres.
+// Jobs referring only to jobs that have never run are scheduled as usual.
+//
+// The preference only affects scheduling decisions and isn't persisted; it's re-added whenever the job is updated from postgres.
+func (s *Scheduler) addScheduleNearJobsPreferences(ctx *armadacontext.Context, txn *jobdb.Txn, jobsById map[string]*jobdb.Job) error {
+	if s.scheduleNearJobsPreferenceWeight <= 0 {
+		return nil
+	}
+	jobIdsByJobId := make(map[string][]string)
+	for jobId, job := range jobsById {
+		if !job.Queued() {
+			continue
+		}
+		if jobIds := ScheduleNearJobIdsFromAnnotations(job.GetAnnotations()); len(jobIds) > 0 {
+			jobIdsByJobId[jobId] = jobIds
+		}
+	}
+	if len(jobIdsByJobId) == 0 {
+		return nil
+	}
+
+	// Resolve the referenced jobs to the nodes they most recently ran on.
+	nodeIdByJobId := make(map[string]string)
+	jobIdsToLookUp := make(map[string]bool)
+	for _, jobIds := range jobIdsByJobId {
+		for _, jobId := range jobIds {
+			job, ok := jobsById[jobId]
+			if !ok {
+				job = txn.GetById(jobId)
+			}
+			if job == nil {
+				jobIdsToLookUp[jobId] = true
+			} else if run := job.LatestRun(); run != nil && run.NodeId() != "" {
+				nodeIdByJobId[jobId] = run.NodeId()
+			}
+		}
+	}
+	if len(jobIdsToLookUp) > 0 {
+		nodeIds, err := s.jobRepository.FindLatestRunNodes(ctx, maps.Keys(jobIdsToLookUp))
+		if err != nil {
+			return err
+		}
+		maps.Copy(nodeIdByJobId, nodeIds)
+	}
+
+	for jobId, jobIds := range jobIdsByJobId {
+		nodeIds := make(map[string]bool, len(jobIds))
+		for _, id := range jobIds {
+			if nodeId, ok := nodeIdByJobId[id]; ok {
+				nodeIds[nodeId] = true
+			}
+		}
+		if len(nodeIds) == 0 {
+			ctx.Debugf("none of the jobs job %s asked to be scheduled near have run; scheduling it as usual", jobId)
+			continue
+		}
+		job, err := s.withScheduleNearJobsPreference(jobsById[jobId], nodeIds)
+		if err != nil {
+			return err
+		}
+		jobsById[jobId] = job
+	}
+	return nil
+}
+
+// withScheduleNearJobsPreference returns a copy of the job that prefers to be scheduled onto the nodes with the given ids.
+func (s *Scheduler) withScheduleNearJobsPreference(job *jobdb.Job, nodeIds map[string]bool) (*jobdb.Job, error) {
+	schedulingInfo := proto.Clone(job.JobSchedulingInfo()).(*schedulerobjects.JobSchedulingInfo)
+	podRequirements := schedulingInfo.GetPodRequirements()
+	if podRequirements == nil {
+		return nil, errors.Errorf("no pod scheduling requirement found for job %s", job.GetId())
+	}
+	if podRequirements.Affinity == nil {
+		podRequirements.Affinity = &v1.Affinity{}
+	}
+	values := maps.Keys(nodeIds)
+	slices.Sort(values)
+	if err := affinity.SetPreferredNodeAffinity(podRequirements.Affinity, schedulerconfig.NodeIdLabel, values, s.scheduleNearJobsPreferenceWeight); err != nil {
+		return nil, err
+	}
+	return job.WithJobSchedulingInfo(schedulingInfo), nil
+}
@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	schedulerconfig "github.com/armadaproject/armada/internal/scheduler/configuration"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestScheduleNearJobIdsFromAnnotations(t *testing.T) {
+	assert.Nil(t, ScheduleNearJobIdsFromAnnotations(nil))
+	assert.Equal(
+		t,
+		[]string{"a", "b"},
+		ScheduleNearJobIdsFromAnnotations(map[string]string{configuration.ScheduleNearJobsAnnotation: "a, b,"}),
+	)
+}
+
+func TestScheduler_AddScheduleNearJobsPreferences(t *testing.T) {
+	runningJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).WithNewRun("executor", "node-1", "node-1")
+	queuedJob := testfixtures.WithAnnotationsJobs(
+		map[string]string{configuration.ScheduleNearJobsAnnotation: runningJob.Id() + ",finished-job,unknown-job"},
+		[]*jobdb.Job{testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).WithQueued(true)},
+	)[0]
+	otherJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0).WithQueued(true)
+
+	txn := testfixtures.NewJobDb().WriteTxn()
+	require.NoError(t, txn.Upsert([]*jobdb.Job{runningJob}))
+	sched := &Scheduler{
+		jobRepository:                    &testJobRepository{latestRunNodes: map[string]string{"finished-job": "node-2"}},
+		scheduleNearJobsPreferenceWeight: 50,
+	}
+	jobsById := map[string]*jobdb.Job{queuedJob.Id(): queuedJob, otherJob.Id(): otherJob}
+	require.NoError(t, sched.addScheduleNearJobsPreferences(armadacontext.Background(), txn, jobsById))
+
+	assert.Equal(
+		t,
+		[]v1.PreferredSchedulingTerm{
+			{
+				Weight: 50,
+				Preference: v1.NodeSelectorTerm{
+					MatchExpressions: []v1.NodeSelectorRequirement{
+						{Key: schedulerconfig.NodeIdLabel, Operator: v1.NodeSelectorOpIn, Values: []string{"node-1", "node-2"}},
+					},
+				},
+			},
+		},
+		jobsById[queuedJob.Id()].PodRequirements().GetPreferredNodeAffinityTerms(),
+	)
+	assert.Empty(t, jobsById[otherJob.Id()].PodRequirements().GetPreferredNodeAffinityTerms())
+	// The job in the jobDb isn't modified in-place.
+	assert.Empty(t, queuedJob.PodRequirements().GetPreferredNodeAffinityTerms())
+}
@@ -2,6 +2,8 @@ package scheduler
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -75,6 +77,50 @@ type Scheduler struct {
 	onCycleCompleted func()
 	// metrics set for the scheduler.
 	metrics *SchedulerMetrics
+	// Drains executor clusters on request. May be nil, in which case clusters can't be drained.
+	clusterDrainer *ClusterDrainer
+	// Returns runs not acknowledged by their executor in time. May be nil, in which case runs aren't returned.
+	leaseAcknowledger *LeaseAcknowledger
+	// Number of consecutive scheduling rounds a queued job may be unsatisfiable before it's failed.
+	// If zero, unsatisfiable jobs are left queued.
+	maxUnsatisfiableRounds uint
+	// For each queued job that was unsatisfiable in the previous scheduling round,
+	// the number of consecutive rounds it has been unsatisfiable for.
+	unsatisfiableRoundsByJobId map[string]uint
+	// Weight of the node preference given to jobs asking to be scheduled near other jobs.
+	// If zero, jobs aren't given such preferences.
+	scheduleNearJobsPreferenceWeight int32
+	// For each queued job considered for scheduling since it was last queued, the time at which it was first considered.
+	firstConsideredTimeByJobId map[string]time.Time
+	// Unix nanoseconds at which the last successful cycle, and the last successful scheduling round, completed.
+	// Read concurrently by health checks.
+	lastSuccessfulCycleTime atomic.Int64
+	lastSuccessfulRoundTime atomic.Int64
+	// Scheduling rounds triggered via TriggerSchedulingRound, which are run by the scheduling loop.
+	roundTriggers chan *roundTrigger
+	// Held while a triggered scheduling round is pending, such that at most one round may be triggered at a time.
+	roundTriggerMutex sync.Mutex
+	// Maximum amount of time the in-flight cycle is given to complete once the scheduler is shutting down,
+	// after which it's aborted. If zero, the in-flight cycle is aborted immediately.
+	shutdownGracePeriod time.Duration
+	// Persists state handed over between leaders. May be nil, in which case no state is handed over.
+	handoverStateRepository database.SchedulerStateRepository
+	// State handed over between leaders, by the name it's persisted under.
+	handoverStateByName map[string]HandoverState
+	// Replaces failed members of running gangs. May be nil, in which case failed gang members aren't replaced.
+	gangHealer *GangHealer
+}
+
+// roundTrigger is a request for the scheduling loop to run a scheduling round restricted to scope.
+type roundTrigger struct {
+	scope RoundScope
+	// Receives the outcome of the round once it has completed.
+	done chan roundTriggerResult
+}
+
+type roundTriggerResult struct {
+	result *SchedulerResult
+	err    error
 }
 
 func NewScheduler(
@@ -112,10 +158,96 @@ func NewScheduler(
 		jobsSerial:                 -1,
 		runsSerial:                 -1,
 		metrics:                    schedulerMetrics,
+		roundTriggers:              make(chan *roundTrigger),
 	}, nil
 }
 
+// SetClusterDrainer sets the component used to drain executor clusters on request.
+func (s *Scheduler) SetClusterDrainer(clusterDrainer *ClusterDrainer) {
+	s.clusterDrainer = clusterDrainer
+}
+
+// SetLeaseAcknowledger sets the component used to return runs not acknowledged by their executor in time.
+func (s *Scheduler) SetLeaseAcknowledger(leaseAcknowledger *LeaseAcknowledger) {
+	s.leaseAcknowledger = leaseAcknowledger
+}
+
+// SetMaxUnsatisfiableRounds sets the number of consecutive scheduling rounds a queued job may be unsatisfiable,
+// i.e., fail to fit onto any node even if that node were empty, before it's failed.
+// If zero, unsatisfiable jobs are left queued.
+func (s *Scheduler) SetMaxUnsatisfiableRounds(maxUnsatisfiableRounds uint) {
+	s.maxUnsatisfiableRounds = maxUnsatisfiableRounds
+}
+
+// SetGangHealer sets the healer replacing failed members of running gangs.
+func (s *Scheduler) SetGangHealer(gangHealer *GangHealer) {
+	s.gangHealer = gangHealer
+}
+
+// SetShutdownGracePeriod sets the maximum amount of time the in-flight cycle is given to complete once the scheduler is shutting down.
+// If zero, the in-flight cycle is aborted immediately and no state is handed over to the next leader.
+func (s *Scheduler) SetShutdownGracePeriod(shutdownGracePeriod time.Duration) {
+	s.shutdownGracePeriod = shutdownGracePeriod
+}
+
+// SetHandoverState sets the state handed over between leaders, by the name it's persisted under, and the repository it's persisted to.
+func (s *Scheduler) SetHandoverState(repository database.SchedulerStateRepository, stateByName map[string]HandoverState) {
+	s.handoverStateRepository = repository
+	s.handoverStateByName = stateByName
+}
+
+// LastSuccessfulCycleTime returns the time at which the scheduler last completed a cycle successfully,
+// or the zero time if it hasn't yet. Safe to call concurrently with Run.
+func (s *Scheduler) LastSuccessfulCycleTime() time.Time {
+	return unixNanoOrZero(s.lastSuccessfulCycleTime.Load())
+}
+
+// LastSuccessfulRoundTime returns the time at which the scheduler last completed a scheduling round successfully,
+// or the zero time if it hasn't yet, e.g., since it has never been leader. Safe to call concurrently with Run.
+func (s *Scheduler) LastSuccessfulRoundTime() time.Time {
+	return unixNanoOrZero(s.lastSuccessfulRoundTime.Load())
+}
+
+// TriggerSchedulingRound makes the scheduling loop run a scheduling round restricted to scope as soon as it's idle,
+// rather than waiting for the next regular round, and returns the result of that round.
+// Since the round is run by the scheduling loop, it never runs concurrently with a regular round.
+// Returns an error if another triggered round is pending, or if this scheduler isn't leader.
+func (s *Scheduler) TriggerSchedulingRound(ctx *armadacontext.Context, scope RoundScope) (*SchedulerResult, error) {
+	if !scope.IsEmpty() {
+		if _, ok := s.schedulingAlgo.(ScopedSchedulingAlgo); !ok {
+			return nil, errors.New("scheduling algo doesn't support scoped scheduling rounds")
+		}
+	}
+	if !s.roundTriggerMutex.TryLock() {
+		return nil, errors.New("another triggered scheduling round is pending")
+	}
+	defer s.roundTriggerMutex.Unlock()
+
+	// Buffered such that the scheduling loop never blocks on sending the result, even if ctx is cancelled in the meantime.
+	trigger := &roundTrigger{scope: scope, done: make(chan roundTriggerResult, 1)}
+	select {
+	case s.roundTriggers <- trigger:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	select {
+	case result := <-trigger.done:
+		return result.result, result.err
+	case <-ctx.Done():
+		return nil, errors.WithMessage(ctx.Err(), "scheduling round was triggered but didn't complete in time")
+	}
+}
+
+func unixNanoOrZero(t int64) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, t)
+}
+
 // Run enters the scheduling loop, which will continue until ctx is cancelled.
+// The scheduler then shuts down gracefully: the in-flight cycle is given up to the shutdown grace period to complete,
+// after which it's aborted, and, if this scheduler is leader, it hands over to the next leader before returning.
 func (s *Scheduler) Run(ctx *armadacontext.Context) error {
 	ctx.Infof("starting scheduler with cycle time %s", s.cyclePeriod)
 	defer ctx.Info("scheduler stopped")
@@ -127,76 +259,150 @@ func (s *Scheduler) Run(ctx *armadacontext.Context) error {
 	}
 	ctx.Infof("JobDb initialised in %s", s.clock.Since(start))
 
+	// Cycles run with a context that's only cancelled once the shutdown grace period has passed since ctx was cancelled,
+	// such that the in-flight cycle isn't aborted midway, e.g., after scheduling decisions were made but before they were published.
+	cycleCtx, cancelCycles := armadacontext.WithCancel(armadacontext.WithoutCancel(ctx))
+	defer cancelCycles()
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-cycleCtx.Done():
+			return
+		}
+		if s.shutdownGracePeriod > 0 {
+			select {
+			case <-s.clock.After(s.shutdownGracePeriod):
+				ctx.Warnf("scheduler didn't shut down within %s; aborting", s.shutdownGracePeriod)
+			case <-cycleCtx.Done():
+				return
+			}
+		}
+		cancelCycles()
+	}()
+
 	ticker := s.clock.NewTicker(s.cyclePeriod)
 	prevLeaderToken := InvalidLeaderToken()
 	for {
 		select {
 		case <-ctx.Done():
-			ctx.Infof("context cancelled; returning.")
-			return ctx.Err()
 		case <-ticker.C():
-			start := s.clock.Now()
-			ctx := armadacontext.WithLogField(ctx, "cycleId", shortuuid.New())
-			leaderToken := s.leaderController.GetToken()
-			fullUpdate := false
-			ctx.Infof("received leaderToken; leader status is %t", leaderToken.leader)
-
-			// If we are becoming leader then we must ensure we have caught up to all Pulsar messages
-			if leaderToken.leader && leaderToken != prevLeaderToken {
-				ctx.Infof("becoming leader")
-				syncContext, cancel := armadacontext.WithTimeout(ctx, 5*time.Minute)
-				err := s.ensureDbUpToDate(syncContext, 1*time.Second)
-				if err != nil {
-					logging.WithStacktrace(ctx, err).Error("could not become leader")
-					leaderToken = InvalidLeaderToken()
-				} else {
-					fullUpdate = true
-				}
-				cancel()
-			}
+			prevLeaderToken = s.runCycle(cycleCtx, prevLeaderToken, nil)
+		case trigger := <-s.roundTriggers:
+			prevLeaderToken = s.runCycle(cycleCtx, prevLeaderToken, trigger)
+		}
+		// Checked after every cycle, such that no further cycles are started once ctx is cancelled.
+		if ctx.Err() != nil {
+			ctx.Infof("context cancelled; shutting down.")
+			s.shutdown(cycleCtx, prevLeaderToken)
+			return ctx.Err()
+		}
+	}
+}
+
+// shutdown hands over to the next leader if this scheduler is still leader once the in-flight cycle has completed.
+// It runs a final cycle without a scheduling round, which publishes any outstanding updates,
+// e.g., returns of runs not acknowledged by their executor in time, and persists the state handed over between leaders.
+// The leader controller should only release leadership, and thereby signal the standby to take over, once shutdown returns.
+func (s *Scheduler) shutdown(ctx *armadacontext.Context, leaderToken LeaderToken) {
+	if s.shutdownGracePeriod == 0 {
+		return
+	}
+	if ctx.Err() != nil {
+		ctx.Warn("shutdown grace period exceeded; not handing over to the next leader")
+		return
+	}
+	if !s.leaderController.ValidateToken(leaderToken) {
+		return
+	}
+	ctx.Info("handing over to the next leader")
+	if _, err := s.cycle(ctx, false, leaderToken, false, RoundScope{}); err != nil {
+		logging.WithStacktrace(ctx, err).Error("final cycle failed; outstanding updates are published by the next leader")
+	}
+	s.persistHandoverState(ctx)
+}
 
-			// Run a scheduler cycle.
-			//
-			// If there is an error, we can't guarantee that the scheduler-internal state is consistent with what was published
-			// (scheduling decisions may have been partially published)
-			// and we must invalidate the held leader token to trigger flushing Pulsar at the next cycle.
-			//
-			// TODO: Once the Pulsar client supports transactions, we can guarantee consistency even in case of errors.
+// runCycle runs a single cycle of the main scheduling loop and returns the leader token held during that cycle.
+// If trigger is non-nil, the cycle includes a scheduling round restricted to the scope of trigger, the result of which is sent to trigger.
+func (s *Scheduler) runCycle(ctx *armadacontext.Context, prevLeaderToken LeaderToken, trigger *roundTrigger) LeaderToken {
+	start := s.clock.Now()
+	ctx = armadacontext.WithLogField(ctx, "cycleId", shortuuid.New())
+	leaderToken := s.leaderController.GetToken()
+	fullUpdate := false
+	ctx.Infof("received leaderToken; leader status is %t", leaderToken.leader)
+
+	// If we are becoming leader then we must ensure we have caught up to all Pulsar messages
+	if leaderToken.leader && leaderToken != prevLeaderToken {
+		ctx.Infof("becoming leader")
+		syncContext, cancel := armadacontext.WithTimeout(ctx, 5*time.Minute)
+		err := s.ensureDbUpToDate(syncContext, 1*time.Second)
+		if err != nil {
+			logging.WithStacktrace(ctx, err).Error("could not become leader")
+			leaderToken = InvalidLeaderToken()
+		} else {
+			fullUpdate = true
+			s.restoreHandoverState(ctx)
+		}
+		cancel()
+	}
 
-			shouldSchedule := s.clock.Now().Sub(s.previousSchedulingRoundEnd) > s.schedulePeriod
+	// Run a scheduler cycle.
+	//
+	// If there is an error, we can't guarantee that the scheduler-internal state is consistent with what was published
+	// (scheduling decisions may have been partially published)
+	// and we must invalidate the held leader token to trigger flushing Pulsar at the next cycle.
+	//
+	// TODO: Once the Pulsar client supports transactions, we can guarantee consistency even in case of errors.
+
+	shouldSchedule := trigger != nil || s.clock.Now().Sub(s.previousSchedulingRoundEnd) > s.schedulePeriod
+	var scope RoundScope
+	if trigger != nil {
+		scope = trigger.scope
+		ctx.Infof("running triggered scheduling round with scope %+v", scope)
+	}
 
-			result, err := s.cycle(ctx, fullUpdate, leaderToken, shouldSchedule)
-			if err != nil {
-				logging.WithStacktrace(ctx, err).Error("scheduling cycle failure")
-				leaderToken = InvalidLeaderToken()
-			}
+	result, err := s.cycle(ctx, fullUpdate, leaderToken, shouldSchedule, scope)
+	if err != nil {
+		logging.WithStacktrace(ctx, err).Error("scheduling cycle failure")
+		leaderToken = InvalidLeaderToken()
+	}
 
-			cycleTime := s.clock.Since(start)
+	cycleTime := s.clock.Since(start)
+	if err == nil {
+		s.lastSuccessfulCycleTime.Store(s.clock.Now().UnixNano())
+		if shouldSchedule && leaderToken.leader {
+			s.lastSuccessfulRoundTime.Store(s.clock.Now().UnixNano())
+		}
+	}
+	if trigger != nil {
+		if err == nil && !leaderToken.leader {
+			err = errors.New("scheduling round not run since this scheduler isn't leader")
+		}
+		trigger.done <- roundTriggerResult{result: &result, err: err}
+	}
 
-			s.metrics.ResetGaugeMetrics()
+	s.metrics.ResetGaugeMetrics()
 
-			if shouldSchedule && leaderToken.leader {
-				// Only the leader does real scheduling rounds.
-				s.metrics.ReportScheduleCycleTime(cycleTime)
-				s.metrics.ReportSchedulerResult(ctx, result)
-				ctx.Infof("scheduling cycle completed in %s", cycleTime)
-			} else {
-				s.metrics.ReportReconcileCycleTime(cycleTime)
-				ctx.Infof("reconciliation cycle completed in %s", cycleTime)
-			}
+	if shouldSchedule && leaderToken.leader {
+		// Only the leader does real scheduling rounds.
+		s.metrics.ReportScheduleCycleTime(cycleTime)
+		s.metrics.ReportSchedulerResult(ctx, result)
+		ctx.Infof("scheduling cycle completed in %s", cycleTime)
+	} else {
+		s.metrics.ReportReconcileCycleTime(cycleTime)
+		ctx.Infof("reconciliation cycle completed in %s", cycleTime)
+	}
 
-			prevLeaderToken = leaderToken
-			if s.onCycleCompleted != nil {
-				s.onCycleCompleted()
-			}
-		}
+	if s.onCycleCompleted != nil {
+		s.onCycleCompleted()
 	}
+	return leaderToken
 }
 
 // cycle is a single iteration of the main scheduling loop.
 // If updateAll is true, we generate events from all jobs in the jobDb.
 // Otherwise, we only generate events from jobs updated since the last cycle.
-func (s *Scheduler) cycle(ctx *armadacontext.Context, updateAll bool, leaderToken LeaderToken, shouldSchedule bool) (overallSchedulerResult SchedulerResult, err error) {
+// If shouldSchedule is true, the cycle includes a scheduling round restricted to scope.
+func (s *Scheduler) cycle(ctx *armadacontext.Context, updateAll bool, leaderToken LeaderToken, shouldSchedule bool, scope RoundScope) (overallSchedulerResult SchedulerResult, err error) {
 	overallSchedulerResult = SchedulerResult{EmptyResult: true}
 
 	// Update job state.
@@ -218,8 +424,11 @@ func (s *Scheduler) cycle(ctx *armadacontext.Context, updateAll bool, leaderToke
 		updatedJobs = txn.GetAll()
 	}
 
+	// Executors are loaded at most once per cycle and shared by the components of the cycle needing them.
+	executors := &cycleExecutors{repository: s.executorRepository}
+
 	// Generate any events that came out of synchronising the db state.
-	events, err := s.generateUpdateMessages(ctx, updatedJobs, txn)
+	events, err := s.generateUpdateMessages(ctx, updatedJobs, txn, executors)
 	if err != nil {
 		return
 	}
@@ -231,6 +440,31 @@ func (s *Scheduler) cycle(ctx *armadacontext.Context, updateAll bool, leaderToke
 	}
 	events = append(events, expirationEvents...)
 
+	// Progress any drains of executor clusters.
+	if s.clusterDrainer != nil {
+		var drainEvents []*armadaevents.EventSequence
+		drainEvents, err = s.clusterDrainer.Drain(ctx, txn)
+		if err != nil {
+			return
+		}
+		events = append(events, drainEvents...)
+	}
+
+	// Return any runs not acknowledged by their executor in time.
+	if s.leaseAcknowledger != nil {
+		var loadedExecutors []*schedulerobjects.Executor
+		loadedExecutors, err = executors.get(ctx)
+		if err != nil {
+			return
+		}
+		var leaseReturnEvents []*armadaevents.EventSequence
+		leaseReturnEvents, err = s.leaseAcknowledger.ReturnUnacknowledgedLeases(ctx, txn, loadedExecutors)
+		if err != nil {
+			return
+		}
+		events = append(events, leaseReturnEvents...)
+	}
+
 	// Request cancel for any jobs that exceed queueTtl
 	queueTtlCancelEvents, err := s.cancelQueuedJobsIfExpired(txn)
 	if err != nil {
@@ -241,10 +475,13 @@ func (s *Scheduler) cycle(ctx *armadacontext.Context, updateAll bool, leaderToke
 	// Schedule jobs.
 	if shouldSchedule {
 		var result *SchedulerResult
-		result, err = s.schedulingAlgo.Schedule(ctx, txn)
+		result, err = s.schedule(ctx, txn, scope)
 		if err != nil {
 			return
 		}
+		if err = s.recordFirstConsideredTimes(txn, result); err != nil {
+			return
+		}
 
 		var resultEvents []*armadaevents.EventSequence
 		resultEvents, err = s.eventsFromSchedulerResult(result)
@@ -252,7 +489,19 @@ func (s *Scheduler) cycle(ctx *armadacontext.Context, updateAll bool, leaderToke
 			return
 		}
 		events = append(events, resultEvents...)
-		s.previousSchedulingRoundEnd = s.clock.Now()
+
+		// Fail any jobs that have been unsatisfiable for too many consecutive rounds.
+		// Scoped rounds don't consider all jobs, so they don't count towards the number of consecutive rounds,
+		// nor do they delay the next regular round.
+		if scope.IsEmpty() {
+			var unsatisfiableEvents []*armadaevents.EventSequence
+			unsatisfiableEvents, err = s.failUnsatisfiableJobs(ctx, txn, result)
+			if err != nil {
+				return
+			}
+			events = append(events, unsatisfiableEvents...)
+			s.previousSchedulingRoundEnd = s.clock.Now()
+		}
 
 		overallSchedulerResult = *result
 	}
@@ -270,6 +519,18 @@ func (s *Scheduler) cycle(ctx *armadacontext.Context, updateAll bool, leaderToke
 	return
 }
 
+// schedule runs a scheduling round restricted to scope.
+func (s *Scheduler) schedule(ctx *armadacontext.Context, txn *jobdb.Txn, scope RoundScope) (*SchedulerResult, error) {
+	if scope.IsEmpty() {
+		return s.schedulingAlgo.Schedule(ctx, txn)
+	}
+	scopedSchedulingAlgo, ok := s.schedulingAlgo.(ScopedSchedulingAlgo)
+	if !ok {
+		return nil, errors.New("scheduling algo doesn't support scoped scheduling rounds")
+	}
+	return scopedSchedulingAlgo.ScheduleScoped(ctx, txn, scope)
+}
+
 // syncState updates jobs in jobDb to match state in postgres and returns all updated jobs.
 func (s *Scheduler) syncState(ctx *armadacontext.Context) ([]*jobdb.Job, error) {
 	start := s.clock.Now()
@@ -337,6 +598,10 @@ func (s *Scheduler) syncState(ctx *armadacontext.Context) ([]*jobdb.Job, error)
 		jobsToUpdateById[jobId] = job
 	}
 
+	if err := s.addScheduleNearJobsPreferences(ctx, txn, jobsToUpdateById); err != nil {
+		return nil, err
+	}
+
 	jobsToUpdate := maps.Values(jobsToUpdateById)
 	if err := txn.Upsert(jobsToUpdate); err != nil {
 		return nil, err
@@ -396,7 +661,7 @@ func (s *Scheduler) eventsFromSchedulerResult(result *SchedulerResult) ([]*armad
 // EventsFromSchedulerResult generates necessary EventSequences from the provided SchedulerResult.
 func EventsFromSchedulerResult(result *SchedulerResult, time time.Time) ([]*armadaevents.EventSequence, error) {
 	eventSequences := make([]*armadaevents.EventSequence, 0, len(result.PreemptedJobs)+len(result.ScheduledJobs)+len(result.FailedJobs))
-	eventSequences, err := AppendEventSequencesFromPreemptedJobs(eventSequences, PreemptedJobsFromSchedulerResult[*jobdb.Job](result), time)
+	eventSequences, err := AppendEventSequencesFromPreemptedJobs(eventSequences, PreemptedJobsFromSchedulerResult[*jobdb.Job](result), result.PreemptionByJobId, time)
 	if err != nil {
 		return nil, err
 	}
@@ -404,14 +669,21 @@ func EventsFromSchedulerResult(result *SchedulerResult, time time.Time) ([]*arma
 	if err != nil {
 		return nil, err
 	}
-	eventSequences, err = AppendEventSequencesFromUnschedulableJobs(eventSequences, FailedJobsFromSchedulerResult[*jobdb.Job](result), time)
+	eventSequences, err = AppendEventSequencesFromUnschedulableJobs(eventSequences, FailedJobsFromSchedulerResult[*jobdb.Job](result), result.FailureReasonByJobId, time)
 	if err != nil {
 		return nil, err
 	}
 	return eventSequences, nil
 }
 
-func AppendEventSequencesFromPreemptedJobs(eventSequences []*armadaevents.EventSequence, jobs []*jobdb.Job, time time.Time) ([]*armadaevents.EventSequence, error) {
+// AppendEventSequencesFromPreemptedJobs appends the events marking each of the provided jobs as preempted.
+// If preemptionByJobId describes why a job was preempted, its preempted event includes that description.
+func AppendEventSequencesFromPreemptedJobs(
+	eventSequences []*armadaevents.EventSequence,
+	jobs []*jobdb.Job,
+	preemptionByJobId map[string]*Preemption,
+	time time.Time,
+) ([]*armadaevents.EventSequence, error) {
 	for _, job := range jobs {
 		jobId, err := armadaevents.ProtoUuidFromUlidString(job.Id())
 		if err != nil {
@@ -421,6 +693,13 @@ func AppendEventSequencesFromPreemptedJobs(eventSequences []*armadaevents.EventS
 		if run == nil {
 			return nil, errors.Errorf("attempting to generate preempted events for job %s with no associated runs", job.Id())
 		}
+		jobRunPreempted := &armadaevents.JobRunPreempted{
+			PreemptedRunId: armadaevents.ProtoUuidFromUuid(run.Id()),
+			PreemptedJobId: jobId,
+		}
+		if err := setPreemptionDetails(jobRunPreempted, preemptionByJobId[job.Id()]); err != nil {
+			return nil, err
+		}
 		eventSequences = append(eventSequences, &armadaevents.EventSequence{
 			Queue:      job.Queue(),
 			JobSetName: job.Jobset(),
@@ -428,10 +707,7 @@ func AppendEventSequencesFromPreemptedJobs(eventSequences []*armadaevents.EventS
 				{
 					Created: &time,
 					Event: &armadaevents.EventSequence_Event_JobRunPreempted{
-						JobRunPreempted: &armadaevents.JobRunPreempted{
-							PreemptedRunId: armadaevents.ProtoUuidFromUuid(run.Id()),
-							PreemptedJobId: jobId,
-						},
+						JobRunPreempted: jobRunPreempted,
 					},
 				},
 				{
@@ -473,6 +749,31 @@ func AppendEventSequencesFromPreemptedJobs(eventSequences []*armadaevents.EventS
 	return eventSequences, nil
 }
 
+// setPreemptionDetails copies the provided description of a preemption, if any, into its preempted event.
+func setPreemptionDetails(event *armadaevents.JobRunPreempted, preemption *Preemption) error {
+	if preemption == nil {
+		return nil
+	}
+	event.Cause = string(preemption.Cause)
+	event.ExpectedRequeuePosition = int32(preemption.ExpectedRequeuePosition)
+	event.QueueCompensated = preemption.QueueCompensated
+	if preemption.PreemptingJobId == "" {
+		return nil
+	}
+	preemptiveJobId, err := armadaevents.ProtoUuidFromUlidString(preemption.PreemptingJobId)
+	if err != nil {
+		return err
+	}
+	event.PreemptiveJobId = preemptiveJobId
+	if preemption.PreemptingRunId != uuid.Nil {
+		event.PreemptiveRunId = armadaevents.ProtoUuidFromUuid(preemption.PreemptingRunId)
+	}
+	event.PreemptiveQueue = preemption.PreemptingQueue
+	event.PreemptivePriorityClass = preemption.PreemptingPriorityClass
+	event.PreemptiveGangId = preemption.PreemptingGangId
+	return nil
+}
+
 func AppendEventSequencesFromScheduledJobs(eventSequences []*armadaevents.EventSequence, jobs []*jobdb.Job, time time.Time) ([]*armadaevents.EventSequence, error) {
 	for _, job := range jobs {
 		jobId, err := armadaevents.ProtoUuidFromUlidString(job.Id())
@@ -498,6 +799,8 @@ func AppendEventSequencesFromScheduledJobs(eventSequences []*armadaevents.EventS
 							// which is referred to as the NodeName within the scheduler.
 							NodeId:               run.NodeName(),
 							UpdateSequenceNumber: job.QueuedVersion(),
+							JobIngested:          jobIngestedTimeOfRun(job),
+							FirstConsidered:      timeFromUnixNano(run.FirstConsideredTime()),
 						},
 					},
 				},
@@ -507,15 +810,19 @@ func AppendEventSequencesFromScheduledJobs(eventSequences []*armadaevents.EventS
 	return eventSequences, nil
 }
 
-func AppendEventSequencesFromUnschedulableJobs(eventSequences []*armadaevents.EventSequence, jobs []*jobdb.Job, time time.Time) ([]*armadaevents.EventSequence, error) {
+func AppendEventSequencesFromUnschedulableJobs(eventSequences []*armadaevents.EventSequence, jobs []*jobdb.Job, failureReasonByJobId map[string]string, time time.Time) ([]*armadaevents.EventSequence, error) {
 	for _, job := range jobs {
 		jobId, err := armadaevents.ProtoUuidFromUlidString(job.GetId())
 		if err != nil {
 			return nil, err
 		}
+		message := "Job did not meet the minimum gang cardinality"
+		if reason, ok := failureReasonByJobId[job.GetId()]; ok {
+			message = fmt.Sprintf("Job failed: %s", reason)
+		}
 		gangJobUnschedulableError := &armadaevents.Error{
 			Terminal: true,
-			Reason:   &armadaevents.Error_GangJobUnschedulable{GangJobUnschedulable: &armadaevents.GangJobUnschedulable{Message: "Job did not meet the minimum gang cardinality"}},
+			Reason:   &armadaevents.Error_GangJobUnschedulable{GangJobUnschedulable: &armadaevents.GangJobUnschedulable{Message: message}},
 		}
 		eventSequences = append(eventSequences, &armadaevents.EventSequence{
 			Queue:      job.GetQueue(),
@@ -533,9 +840,33 @@ func AppendEventSequencesFromUnschedulableJobs(eventSequences []*armadaevents.Ev
 	return eventSequences, nil
 }
 
+// cycleExecutors loads the executors at most once per cycle, such that components of the cycle needing them share them.
+type cycleExecutors struct {
+	repository database.ExecutorRepository
+	executors  []*schedulerobjects.Executor
+	loaded     bool
+}
+
+func (c *cycleExecutors) get(ctx *armadacontext.Context) ([]*schedulerobjects.Executor, error) {
+	if !c.loaded {
+		executors, err := c.repository.GetExecutors(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.executors = executors
+		c.loaded = true
+	}
+	return c.executors, nil
+}
+
 // generateUpdateMessages generates EventSequences representing the state changes on updated jobs
 // If there are no state changes then an empty slice will be returned
-func (s *Scheduler) generateUpdateMessages(ctx *armadacontext.Context, updatedJobs []*jobdb.Job, txn *jobdb.Txn) ([]*armadaevents.EventSequence, error) {
+func (s *Scheduler) generateUpdateMessages(
+	ctx *armadacontext.Context,
+	updatedJobs []*jobdb.Job,
+	txn *jobdb.Txn,
+	executors *cycleExecutors,
+) ([]*armadaevents.EventSequence, error) {
 	failedRunIds := make([]uuid.UUID, 0, len(updatedJobs))
 	for _, job := range updatedJobs {
 		run := job.LatestRun()
@@ -547,11 +878,25 @@ func (s *Scheduler) generateUpdateMessages(ctx *armadacontext.Context, updatedJo
 	if err != nil {
 		return nil, err
 	}
+	var replacementByJobId map[string]*jobdb.Job
+	if s.gangHealer != nil {
+		replacementByJobId, err = s.gangHealer.Replacements(
+			ctx,
+			updatedJobs,
+			jobRunErrors,
+			txn,
+			func() ([]*schedulerobjects.Executor, error) { return executors.get(ctx) },
+			s.maxAttemptedRuns,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Generate any events that came out of synchronising the db state
 	var events []*armadaevents.EventSequence
 	for _, job := range updatedJobs {
-		jobEvents, err := s.generateUpdateMessagesFromJob(job, jobRunErrors, txn)
+		jobEvents, err := s.generateUpdateMessagesFromJob(job, jobRunErrors, replacementByJobId, txn)
 		if err != nil {
 			return nil, err
 		}
@@ -564,7 +909,13 @@ func (s *Scheduler) generateUpdateMessages(ctx *armadacontext.Context, updatedJo
 
 // generateUpdateMessages generates EventSequence representing the state change on a single jobs
 // If there are no state changes then nil will be returned
-func (s *Scheduler) generateUpdateMessagesFromJob(job *jobdb.Job, jobRunErrors map[uuid.UUID]*armadaevents.Error, txn *jobdb.Txn) (*armadaevents.EventSequence, error) {
+// Failed jobs with an entry in replacementByJobId are requeued as that replacement, provided it's schedulable.
+func (s *Scheduler) generateUpdateMessagesFromJob(
+	job *jobdb.Job,
+	jobRunErrors map[uuid.UUID]*armadaevents.Error,
+	replacementByJobId map[string]*jobdb.Job,
+	txn *jobdb.Txn,
+) (*armadaevents.EventSequence, error) {
 	var events []*armadaevents.EventSequence_Event
 
 	// Is the job already in a terminal state?  If so then don't send any more messages
@@ -639,6 +990,13 @@ func (s *Scheduler) generateUpdateMessagesFromJob(job *jobdb.Job, jobRunErrors m
 					}
 				}
 			}
+			if replacement, ok := replacementByJobId[job.Id()]; ok && !requeueJob {
+				// Failed members of running gangs are replaced, i.e., requeued, if the replacement is schedulable.
+				if schedulable, _ := s.submitChecker.CheckJobDbJobs([]*jobdb.Job{replacement}); schedulable {
+					job = replacement
+					requeueJob = true
+				}
+			}
 
 			if requeueJob {
 				job = job.WithQueued(true)
@@ -868,6 +1226,83 @@ func (s *Scheduler) cancelQueuedJobsIfExpired(txn *jobdb.Txn) ([]*armadaevents.E
 	return events, nil
 }
 
+// failUnsatisfiableJobs fails queued jobs that have been unsatisfiable, i.e., that don't fit onto any node even if
+// that node were empty, for maxUnsatisfiableRounds consecutive scheduling rounds.
+// Such jobs can never be scheduled; failing them saves the scheduler from considering them in every round.
+func (s *Scheduler) failUnsatisfiableJobs(ctx *armadacontext.Context, txn *jobdb.Txn, result *SchedulerResult) ([]*armadaevents.EventSequence, error) {
+	if s.maxUnsatisfiableRounds == 0 {
+		return nil, nil
+	}
+	// A round in which no executor was considered, e.g., since all executors are stale,
+	// says nothing about whether jobs fit onto nodes.
+	// Rounds skipped since their inputs were unchanged are counted, since the jobs and nodes are the same as in the previous round.
+	if len(result.SchedulingContexts) == 0 && !result.InputsUnchanged {
+		return nil, nil
+	}
+
+	jobsToFail := make([]*jobdb.Job, 0)
+	events := make([]*armadaevents.EventSequence, 0)
+	unsatisfiableRoundsByJobId := make(map[string]uint)
+	for _, job := range txn.GetAll() {
+		if !job.Queued() || job.InTerminalState() {
+			continue
+		}
+		isSchedulable, reason := s.submitChecker.CheckJobDbJobs([]*jobdb.Job{job})
+		if isSchedulable {
+			continue
+		}
+		numRounds := s.unsatisfiableRoundsByJobId[job.Id()] + 1
+		if numRounds < s.maxUnsatisfiableRounds {
+			unsatisfiableRoundsByJobId[job.Id()] = numRounds
+			continue
+		}
+
+		ctx.Infof("Failing job %s as it has been unsatisfiable for %d consecutive scheduling rounds", job.Id(), numRounds)
+		jobsToFail = append(jobsToFail, job.WithQueued(false).WithFailed(true))
+		jobId, err := armadaevents.ProtoUuidFromUlidString(job.Id())
+		if err != nil {
+			return nil, err
+		}
+		unsatisfiableError := &armadaevents.Error{
+			Terminal: true,
+			Reason: &armadaevents.Error_PodUnschedulable{
+				PodUnschedulable: &armadaevents.PodUnschedulable{
+					Message: fmt.Sprintf(
+						"Job does not fit onto any node, even if that node were empty, and was unschedulable for %d consecutive scheduling rounds - this job will no longer be considered for scheduling:\n%s",
+						numRounds, reason,
+					),
+				},
+			},
+		}
+		events = append(events, &armadaevents.EventSequence{
+			Queue:      job.Queue(),
+			JobSetName: job.Jobset(),
+			Events: []*armadaevents.EventSequence_Event{
+				{
+					Created: s.now(),
+					Event: &armadaevents.EventSequence_Event_JobErrors{
+						JobErrors: &armadaevents.JobErrors{JobId: jobId, Errors: []*armadaevents.Error{unsatisfiableError}},
+					},
+				},
+			},
+		})
+	}
+	if err := txn.Upsert(jobsToFail); err != nil {
+		return nil, err
+	}
+	s.unsatisfiableRoundsByJobId = unsatisfiableRoundsByJobId
+	return events, nil
+}
+
+// timeFromUnixNano returns a pointer to the time t nanoseconds since the epoch, or nil if t is zero, i.e., unknown.
+func timeFromUnixNano(t int64) *time.Time {
+	if t == 0 {
+		return nil
+	}
+	tm := time.Unix(0, t).UTC()
+	return &tm
+}
+
 // now is a convenience function for generating a pointer to a time.Time (as required by armadaevents).
 // It exists because Go won't let you do &s.clock.Now().
 func (s *Scheduler) now() *time.Time {
@@ -950,7 +1385,7 @@ func (s *Scheduler) schedulerJobFromDatabaseJob(dbJob *database.Job) (*jobdb.Job
 		return nil, errors.Wrapf(err, "error unmarshalling scheduling info for job %s", dbJob.JobID)
 	}
 	s.internJobSchedulingInfoStrings(schedulingInfo)
-	return s.jobDb.NewJob(
+	job := s.jobDb.NewJob(
 		dbJob.JobID,
 		s.stringInterner.Intern(dbJob.JobSet),
 		s.stringInterner.Intern(dbJob.Queue),
@@ -962,13 +1397,17 @@ func (s *Scheduler) schedulerJobFromDatabaseJob(dbJob *database.Job) (*jobdb.Job
 		dbJob.CancelByJobsetRequested,
 		dbJob.Cancelled,
 		dbJob.Submitted,
-	), nil
+	)
+	if dbJob.Ingested != nil {
+		job = job.WithIngestedTime(dbJob.Ingested.UnixNano())
+	}
+	return job, nil
 }
 
 // createSchedulerRun creates a new scheduler job run from a database job run
 func (s *Scheduler) createSchedulerRun(dbRun *database.Run) *jobdb.JobRun {
 	nodeId := api.NodeIdFromExecutorAndNodeName(dbRun.Executor, dbRun.Node)
-	return jobdb.CreateRun(
+	run := jobdb.CreateRun(
 		dbRun.RunID,
 		dbRun.JobID,
 		dbRun.Created,
@@ -982,6 +1421,7 @@ func (s *Scheduler) createSchedulerRun(dbRun *database.Run) *jobdb.JobRun {
 		dbRun.Returned,
 		dbRun.RunAttempted,
 	)
+	return updateSchedulerRunHistory(run, dbRun)
 }
 
 func (s *Scheduler) internJobSchedulingInfoStrings(info *schedulerobjects.JobSchedulingInfo) {
@@ -1016,6 +1456,21 @@ func updateSchedulerRun(run *jobdb.JobRun, dbRun *database.Run) *jobdb.JobRun {
 	if dbRun.RunAttempted && !run.RunAttempted() {
 		run = run.WithAttempted(true)
 	}
+	return updateSchedulerRunHistory(run, dbRun)
+}
+
+// updateSchedulerRunHistory copies the fields of the database job run only used for the run attempt history,
+// i.e., whether the run was preempted and its running and terminated timestamps, onto the scheduler job run.
+func updateSchedulerRunHistory(run *jobdb.JobRun, dbRun *database.Run) *jobdb.JobRun {
+	if dbRun.Preempted && !run.Preempted() {
+		run = run.WithPreempted(true)
+	}
+	if dbRun.RunningTimestamp != nil && run.RunningTime() != dbRun.RunningTimestamp.UnixNano() {
+		run = run.WithRunningTime(dbRun.RunningTimestamp.UnixNano())
+	}
+	if dbRun.TerminatedTimestamp != nil && run.TerminatedTime() != dbRun.TerminatedTimestamp.UnixNano() {
+		run = run.WithTerminatedTime(dbRun.TerminatedTimestamp.UnixNano())
+	}
 	return run
 }
 
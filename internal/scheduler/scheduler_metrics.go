@@ -9,6 +9,7 @@ import (
 	"github.com/armadaproject/armada/internal/common/armadacontext"
 	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
 	"github.com/armadaproject/armada/internal/scheduler/interfaces"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
 )
 
 const (
@@ -21,6 +22,10 @@ type SchedulerMetrics struct {
 	scheduleCycleTime prometheus.Histogram
 	// Cycle time when reconciling, as leader or follower.
 	reconcileCycleTime prometheus.Histogram
+	// Number of scheduling rounds skipped since their inputs were unchanged from the previous round.
+	skippedUnchangedRounds prometheus.Counter
+	// Time of the most recent scheduling round skipped since its inputs were unchanged.
+	unchangedRoundHeartbeat prometheus.Gauge
 	// Number of jobs scheduled per queue.
 	scheduledJobsPerQueue prometheus.CounterVec
 	// Number of jobs preempted per queue.
@@ -31,6 +36,8 @@ type SchedulerMetrics struct {
 	fairSharePerQueue prometheus.GaugeVec
 	// Actual share of each queue.
 	actualSharePerQueue prometheus.GaugeVec
+	// Time scheduled jobs spent in each segment of the path from submission to being leased, per queue.
+	jobStartLatency prometheus.HistogramVec
 }
 
 func NewSchedulerMetrics(config configuration.SchedulerMetricsConfig) *SchedulerMetrics {
@@ -60,6 +67,24 @@ func NewSchedulerMetrics(config configuration.SchedulerMetricsConfig) *Scheduler
 		},
 	)
 
+	skippedUnchangedRounds := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: SUBSYSTEM,
+			Name:      "skipped_unchanged_rounds",
+			Help:      "Number of scheduling rounds skipped since their inputs were unchanged from the previous round.",
+		},
+	)
+
+	unchangedRoundHeartbeat := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: NAMESPACE,
+			Subsystem: SUBSYSTEM,
+			Name:      "unchanged_round_heartbeat_timestamp_seconds",
+			Help:      "Unix time of the most recent scheduling round skipped since its inputs were unchanged from the previous round.",
+		},
+	)
+
 	scheduledJobs := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: NAMESPACE,
@@ -125,22 +150,45 @@ func NewSchedulerMetrics(config configuration.SchedulerMetricsConfig) *Scheduler
 		},
 	)
 
+	jobStartLatency := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: NAMESPACE,
+			Subsystem: SUBSYSTEM,
+			Name:      "job_start_latency_seconds",
+			Help:      "Time scheduled jobs spent in each segment of the path from submission to being leased.",
+			Buckets: prometheus.ExponentialBuckets(
+				config.JobStartLatencyHistogramSettings.Start,
+				config.JobStartLatencyHistogramSettings.Factor,
+				config.JobStartLatencyHistogramSettings.Count),
+		},
+		[]string{
+			"queue",
+			"segment",
+		},
+	)
+
 	prometheus.MustRegister(scheduleCycleTime)
 	prometheus.MustRegister(reconcileCycleTime)
+	prometheus.MustRegister(skippedUnchangedRounds)
+	prometheus.MustRegister(unchangedRoundHeartbeat)
 	prometheus.MustRegister(scheduledJobs)
 	prometheus.MustRegister(preemptedJobs)
 	prometheus.MustRegister(consideredJobs)
 	prometheus.MustRegister(fairSharePerQueue)
 	prometheus.MustRegister(actualSharePerQueue)
+	prometheus.MustRegister(jobStartLatency)
 
 	return &SchedulerMetrics{
-		scheduleCycleTime:     scheduleCycleTime,
-		reconcileCycleTime:    reconcileCycleTime,
-		scheduledJobsPerQueue: *scheduledJobs,
-		preemptedJobsPerQueue: *preemptedJobs,
-		consideredJobs:        *consideredJobs,
-		fairSharePerQueue:     *fairSharePerQueue,
-		actualSharePerQueue:   *actualSharePerQueue,
+		scheduleCycleTime:       scheduleCycleTime,
+		reconcileCycleTime:      reconcileCycleTime,
+		skippedUnchangedRounds:  skippedUnchangedRounds,
+		unchangedRoundHeartbeat: unchangedRoundHeartbeat,
+		scheduledJobsPerQueue:   *scheduledJobs,
+		preemptedJobsPerQueue:   *preemptedJobs,
+		consideredJobs:          *consideredJobs,
+		fairSharePerQueue:       *fairSharePerQueue,
+		actualSharePerQueue:     *actualSharePerQueue,
+		jobStartLatency:         *jobStartLatency,
 	}
 }
 
@@ -161,10 +209,19 @@ func (metrics *SchedulerMetrics) ReportSchedulerResult(ctx *armadacontext.Contex
 	if result.EmptyResult {
 		return // TODO: Add logging or maybe place to add failure metric?
 	}
+	if result.InputsUnchanged {
+		// Gauges are reset every cycle; republish those of the round whose decisions are unchanged,
+		// such that skipped rounds aren't mistaken for the scheduler having stopped.
+		metrics.skippedUnchangedRounds.Inc()
+		metrics.unchangedRoundHeartbeat.SetToCurrentTime()
+		metrics.reportQueueShares(ctx, result.UnchangedSchedulingContexts)
+		return
+	}
 
 	// Report the total scheduled jobs (possibly we can get these out of contexts?)
 	metrics.reportScheduledJobs(ctx, result.ScheduledJobs)
 	metrics.reportPreemptedJobs(ctx, result.PreemptedJobs)
+	metrics.reportJobStartLatencies(ctx, result.ScheduledJobs)
 
 	// TODO: When more metrics are added, consider consolidating into a single loop over the data.
 	// Report the number of considered jobs.
@@ -182,6 +239,25 @@ func (metrics *SchedulerMetrics) reportPreemptedJobs(ctx *armadacontext.Context,
 	observeJobAggregates(ctx, metrics.preemptedJobsPerQueue, jobAggregates)
 }
 
+// reportJobStartLatencies reports the time each scheduled job spent being ingested, waiting to be considered for scheduling,
+// and waiting to be scheduled once considered. Segments with an unknown start are skipped.
+func (metrics *SchedulerMetrics) reportJobStartLatencies(ctx *armadacontext.Context, scheduledJobs []interfaces.LegacySchedulerJob) {
+	for _, job := range scheduledJobs {
+		jobDbJob, ok := job.(*jobdb.Job)
+		if !ok {
+			continue
+		}
+		for _, segment := range JobStartLatencySegments(jobDbJob) {
+			observer, err := metrics.jobStartLatency.GetMetricWithLabelValues(jobDbJob.Queue(), segment.Name)
+			if err != nil {
+				ctx.Errorf("error retrieving job start latency observer for queue %s, segment %s", jobDbJob.Queue(), segment.Name)
+			} else {
+				observer.Observe(segment.Duration.Seconds())
+			}
+		}
+	}
+}
+
 type collectionKey struct {
 	queue         string
 	priorityClass string
@@ -220,7 +296,7 @@ func (metrics *SchedulerMetrics) reportNumberOfJobsConsidered(ctx *armadacontext
 	for _, schedContext := range schedulingContexts {
 		pool := schedContext.Pool
 		for queue, queueContext := range schedContext.QueueSchedulingContexts {
-			count := len(queueContext.UnsuccessfulJobSchedulingContexts) + len(queueContext.SuccessfulJobSchedulingContexts)
+			count := queueContext.NumUnsuccessfulJobs() + len(queueContext.SuccessfulJobSchedulingContexts)
 
 			observer, err := metrics.consideredJobs.GetMetricWithLabelValues(queue, pool)
 			if err != nil {
@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
@@ -18,6 +19,7 @@ import (
 	protoutil "github.com/armadaproject/armada/internal/common/proto"
 	"github.com/armadaproject/armada/internal/common/stringinterner"
 	"github.com/armadaproject/armada/internal/common/util"
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
 	"github.com/armadaproject/armada/internal/scheduler/database"
 	"github.com/armadaproject/armada/internal/scheduler/jobdb"
 	"github.com/armadaproject/armada/internal/scheduler/kubernetesobjects/affinity"
@@ -103,6 +105,11 @@ var (
 			Factor: 1.1,
 			Count:  100,
 		},
+		JobStartLatencyHistogramSettings: configuration.HistogramConfig{
+			Start:  0.1,
+			Factor: 2,
+			Count:  16,
+		},
 	})
 )
 
@@ -643,7 +650,7 @@ func TestScheduler_TestCycle(t *testing.T) {
 
 			// run a scheduler cycle
 			ctx, cancel := armadacontext.WithTimeout(armadacontext.Background(), 5*time.Second)
-			_, err = sched.cycle(ctx, false, sched.leaderController.GetToken(), true)
+			_, err = sched.cycle(ctx, false, sched.leaderController.GetToken(), true, RoundScope{})
 			if tc.fetchError || tc.publishError || tc.scheduleError {
 				assert.Error(t, err)
 			} else {
@@ -841,6 +848,219 @@ func TestRun(t *testing.T) {
 	cancel()
 }
 
+func TestScheduler_TriggerSchedulingRound(t *testing.T) {
+	jobId := util.NewULID()
+	jobRepo := testJobRepository{
+		numReceivedPartitions: 100,
+		updatedJobs:           []database.Job{{JobID: jobId, Queue: "testQueue", Queued: true}},
+	}
+	schedulingAlgo := &testSchedulingAlgo{jobsToSchedule: []string{jobId}}
+	stringInterner, err := stringinterner.New(100)
+	require.NoError(t, err)
+	sched, err := NewScheduler(
+		testfixtures.NewJobDb(),
+		&jobRepo,
+		&testExecutorRepository{},
+		schedulingAlgo,
+		NewStandaloneLeaderController(),
+		&testPublisher{},
+		stringInterner,
+		&testSubmitChecker{checkSuccess: true},
+		1*time.Hour,
+		1*time.Hour,
+		1*time.Hour,
+		maxNumberOfAttempts,
+		nodeIdLabel,
+		schedulerMetrics)
+	require.NoError(t, err)
+	// Regular rounds never run, since the clock never advances.
+	sched.clock = clock.NewFakeClock(time.Now())
+
+	ctx, cancel := armadacontext.WithTimeout(armadacontext.Background(), 10*time.Second)
+	defer cancel()
+	//nolint:errcheck
+	go sched.Run(ctx)
+
+	scope := RoundScope{Pool: "cpu", Queue: "testQueue"}
+	result, err := sched.TriggerSchedulingRound(ctx, scope)
+	require.NoError(t, err)
+	require.Len(t, result.ScheduledJobs, 1)
+	assert.Equal(t, jobId, result.ScheduledJobs[0].GetId())
+	assert.Equal(t, []RoundScope{scope}, schedulingAlgo.scopes)
+	assert.Equal(t, 1, schedulingAlgo.numberOfScheduleCalls)
+
+	// Only one round may be triggered at a time.
+	sched.roundTriggerMutex.Lock()
+	_, err = sched.TriggerSchedulingRound(ctx, RoundScope{})
+	assert.Error(t, err)
+	sched.roundTriggerMutex.Unlock()
+	assert.Equal(t, 1, schedulingAlgo.numberOfScheduleCalls)
+}
+
+func TestScheduler_Shutdown(t *testing.T) {
+	jobRepo := testJobRepository{numReceivedPartitions: 100}
+	stringInterner, err := stringinterner.New(100)
+	require.NoError(t, err)
+	publisher := &testPublisher{}
+	sched, err := NewScheduler(
+		testfixtures.NewJobDb(),
+		&jobRepo,
+		&testExecutorRepository{},
+		&testSchedulingAlgo{},
+		NewStandaloneLeaderController(),
+		publisher,
+		stringInterner,
+		&testSubmitChecker{checkSuccess: true},
+		1*time.Second,
+		1*time.Hour,
+		1*time.Hour,
+		maxNumberOfAttempts,
+		nodeIdLabel,
+		schedulerMetrics)
+	require.NoError(t, err)
+	testClock := clock.NewFakeClock(time.Now())
+	sched.clock = testClock
+	sched.SetShutdownGracePeriod(time.Minute)
+	stateRepo := &testSchedulerStateRepository{stateByName: map[string][]byte{"foo": []byte("previous")}}
+	foo := &testHandoverState{}
+	bar := &testHandoverState{state: []byte("bar")}
+	sched.SetHandoverState(stateRepo, map[string]HandoverState{"foo": foo, "bar": bar})
+
+	ctx, cancel := armadacontext.WithCancel(armadacontext.Background())
+	done := make(chan error)
+	go func() {
+		done <- sched.Run(ctx)
+	}()
+
+	// State handed over by the previous leader is restored once this scheduler becomes leader.
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	sched.onCycleCompleted = func() { wg.Done() }
+	time.Sleep(100 * time.Millisecond)
+	testClock.Step(10 * time.Second)
+	wg.Wait()
+	assert.Equal(t, []byte("previous"), foo.state)
+	assert.Equal(t, []byte("bar"), bar.state)
+
+	// On shutdown, the leader hands over its state to the next leader.
+	// The final cycle publishes outstanding updates, e.g., of jobs cancelled since the previous cycle.
+	foo.state = []byte("foo")
+	publisher.events = nil
+	jobRepo.updatedJobs = []database.Job{{JobID: util.NewULID(), Queue: "testQueue", Queued: true, CancelRequested: true}}
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(10 * time.Second):
+		t.Fatal("scheduler didn't shut down")
+	}
+	assert.Len(t, publisher.events, 1)
+	assert.Equal(t, map[string][]byte{"foo": []byte("foo"), "bar": []byte("bar")}, stateRepo.stateByName)
+}
+
+func TestScheduler_FailUnsatisfiableJobs(t *testing.T) {
+	tests := map[string]struct {
+		maxUnsatisfiableRounds uint
+		// If true, the submit checker says the job doesn't fit onto any node.
+		unsatisfiable bool
+		// If true, the scheduling algo reports having considered no executors.
+		noSchedulingContexts bool
+		numCycles            int
+		// Index of the cycle in which the job is expected to fail, or -1 if it's expected to remain queued.
+		expectedFailedInCycle int
+	}{
+		"unsatisfiable job is failed after maxUnsatisfiableRounds": {
+			maxUnsatisfiableRounds: 3,
+			unsatisfiable:          true,
+			numCycles:              5,
+			expectedFailedInCycle:  2,
+		},
+		"unsatisfiable job is failed in the first round if maxUnsatisfiableRounds is 1": {
+			maxUnsatisfiableRounds: 1,
+			unsatisfiable:          true,
+			numCycles:              2,
+			expectedFailedInCycle:  0,
+		},
+		"disabled": {
+			maxUnsatisfiableRounds: 0,
+			unsatisfiable:          true,
+			numCycles:              5,
+			expectedFailedInCycle:  -1,
+		},
+		"satisfiable job remains queued": {
+			maxUnsatisfiableRounds: 1,
+			numCycles:              5,
+			expectedFailedInCycle:  -1,
+		},
+		"rounds without executors are not counted": {
+			maxUnsatisfiableRounds: 1,
+			unsatisfiable:          true,
+			noSchedulingContexts:   true,
+			numCycles:              5,
+			expectedFailedInCycle:  -1,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			schedulingAlgo := &testSchedulingAlgo{}
+			if !tc.noSchedulingContexts {
+				schedulingAlgo.schedulingContexts = []*schedulercontext.SchedulingContext{{}}
+			}
+			publisher := &testPublisher{}
+			stringInterner, err := stringinterner.New(100)
+			require.NoError(t, err)
+			testClock := clock.NewFakeClock(time.Now())
+			sched, err := NewScheduler(
+				testfixtures.NewJobDb(),
+				&testJobRepository{},
+				&testExecutorRepository{updateTimes: map[string]time.Time{"testExecutor": testClock.Now()}},
+				schedulingAlgo,
+				NewStandaloneLeaderController(),
+				publisher,
+				stringInterner,
+				&testSubmitChecker{checkSuccess: !tc.unsatisfiable},
+				1*time.Second,
+				5*time.Second,
+				1*time.Hour,
+				maxNumberOfAttempts,
+				nodeIdLabel,
+				schedulerMetrics,
+			)
+			require.NoError(t, err)
+			sched.clock = testClock
+			sched.SetMaxUnsatisfiableRounds(tc.maxUnsatisfiableRounds)
+
+			txn := sched.jobDb.WriteTxn()
+			require.NoError(t, txn.Upsert([]*jobdb.Job{queuedJob}))
+			txn.Commit()
+
+			for i := 0; i < tc.numCycles; i++ {
+				publisher.Reset()
+				_, err := sched.cycle(armadacontext.Background(), false, sched.leaderController.GetToken(), true, RoundScope{})
+				require.NoError(t, err)
+
+				job := sched.jobDb.ReadTxn().GetById(queuedJob.Id())
+				if tc.expectedFailedInCycle == -1 || i < tc.expectedFailedInCycle {
+					assert.Empty(t, publisher.events, "cycle %d", i)
+					assert.True(t, job.Queued(), "cycle %d", i)
+				} else if i == tc.expectedFailedInCycle {
+					require.Len(t, publisher.events, 1, "cycle %d", i)
+					require.Len(t, publisher.events[0].Events, 1)
+					jobErrors := publisher.events[0].Events[0].GetJobErrors()
+					require.NotNil(t, jobErrors)
+					require.Len(t, jobErrors.Errors, 1)
+					assert.True(t, jobErrors.Errors[0].Terminal)
+					assert.NotNil(t, jobErrors.Errors[0].GetPodUnschedulable())
+					assert.False(t, job.Queued())
+					assert.True(t, job.InTerminalState())
+				} else {
+					assert.Empty(t, publisher.events, "cycle %d", i)
+				}
+			}
+		})
+	}
+}
+
 func TestScheduler_TestSyncState(t *testing.T) {
 	tests := map[string]struct {
 		initialJobs         []*jobdb.Job   // jobs in the jobdb at the start of the cycle
@@ -951,6 +1171,32 @@ func TestScheduler_TestSyncState(t *testing.T) {
 			expectedUpdatedJobs: []*jobdb.Job{leasedJob.WithUpdatedRun(leasedJob.LatestRun().WithSucceeded(true))},
 			expectedJobDbIds:    []string{},
 		},
+		"job run preempted": {
+			initialJobs: []*jobdb.Job{leasedJob},
+			jobUpdates: []database.Job{
+				{
+					JobID:          leasedJob.Id(),
+					JobSet:         leasedJob.Jobset(),
+					Queue:          leasedJob.Queue(),
+					Submitted:      leasedJob.Created(),
+					Priority:       int64(leasedJob.Priority()),
+					SchedulingInfo: schedulingInfoBytes,
+					Failed:         true,
+					Serial:         1,
+				},
+			},
+			runUpdates: []database.Run{
+				{
+					RunID:     leasedJob.LatestRun().Id(),
+					JobID:     leasedJob.LatestRun().JobId(),
+					JobSet:    leasedJob.GetJobSet(),
+					Failed:    true,
+					Preempted: true,
+				},
+			},
+			expectedUpdatedJobs: []*jobdb.Job{leasedJob.WithUpdatedRun(leasedJob.LatestRun().WithFailed(true).WithPreempted(true))},
+			expectedJobDbIds:    []string{},
+		},
 		"job requeued": {
 			initialJobs: []*jobdb.Job{leasedJob},
 			jobUpdates: []database.Job{
@@ -1064,6 +1310,7 @@ type testJobRepository struct {
 	errors                map[uuid.UUID]*armadaevents.Error
 	shouldError           bool
 	numReceivedPartitions uint32
+	latestRunNodes        map[string]string
 }
 
 func (t *testJobRepository) FindInactiveRuns(ctx *armadacontext.Context, runIds []uuid.UUID) ([]uuid.UUID, error) {
@@ -1071,6 +1318,19 @@ func (t *testJobRepository) FindInactiveRuns(ctx *armadacontext.Context, runIds
 	panic("implement me")
 }
 
+func (t *testJobRepository) FindLatestRunNodes(ctx *armadacontext.Context, jobIds []string) (map[string]string, error) {
+	if t.shouldError {
+		return nil, errors.New("error finding latest run nodes")
+	}
+	nodesByJobId := make(map[string]string)
+	for _, jobId := range jobIds {
+		if node, ok := t.latestRunNodes[jobId]; ok {
+			nodesByJobId[jobId] = node
+		}
+	}
+	return nodesByJobId, nil
+}
+
 func (t *testJobRepository) FetchJobRunLeases(ctx *armadacontext.Context, executor string, maxResults uint, excludedRunIds []uuid.UUID) ([]*database.JobRunLease, error) {
 	// TODO implement me
 	panic("implement me")
@@ -1122,7 +1382,15 @@ type testSchedulingAlgo struct {
 	jobsToPreempt         []string
 	jobsToSchedule        []string
 	jobsToFail            []string
+	schedulingContexts    []*schedulercontext.SchedulingContext
 	shouldError           bool
+	// Scopes of the scoped scheduling rounds run.
+	scopes []RoundScope
+}
+
+func (t *testSchedulingAlgo) ScheduleScoped(ctx *armadacontext.Context, txn *jobdb.Txn, scope RoundScope) (*SchedulerResult, error) {
+	t.scopes = append(t.scopes, scope)
+	return t.Schedule(ctx, txn)
 }
 
 func (t *testSchedulingAlgo) Schedule(ctx *armadacontext.Context, txn *jobdb.Txn) (*SchedulerResult, error) {
@@ -1180,7 +1448,35 @@ func (t *testSchedulingAlgo) Schedule(ctx *armadacontext.Context, txn *jobdb.Txn
 	if err := txn.Upsert(failedJobs); err != nil {
 		return nil, err
 	}
-	return NewSchedulerResultForTest(preemptedJobs, scheduledJobs, failedJobs, nil), nil
+	result := NewSchedulerResultForTest(preemptedJobs, scheduledJobs, failedJobs, nil)
+	result.SchedulingContexts = t.schedulingContexts
+	return result, nil
+}
+
+type testSchedulerStateRepository struct {
+	stateByName map[string][]byte
+}
+
+func (r *testSchedulerStateRepository) GetSchedulerState(_ *armadacontext.Context, name string) ([]byte, error) {
+	return r.stateByName[name], nil
+}
+
+func (r *testSchedulerStateRepository) SetSchedulerState(_ *armadacontext.Context, name string, state []byte, _ time.Time) error {
+	r.stateByName[name] = state
+	return nil
+}
+
+type testHandoverState struct {
+	state []byte
+}
+
+func (s *testHandoverState) MarshalState() ([]byte, error) {
+	return s.state, nil
+}
+
+func (s *testHandoverState) UnmarshalState(state []byte) error {
+	s.state = state
+	return nil
 }
 
 type testPublisher struct {
@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -22,6 +23,7 @@ import (
 	"github.com/armadaproject/armada/internal/common/armadacontext"
 	"github.com/armadaproject/armada/internal/common/auth"
 	dbcommon "github.com/armadaproject/armada/internal/common/database"
+	"github.com/armadaproject/armada/internal/common/encryption"
 	grpcCommon "github.com/armadaproject/armada/internal/common/grpc"
 	"github.com/armadaproject/armada/internal/common/health"
 	"github.com/armadaproject/armada/internal/common/logging"
@@ -73,7 +75,12 @@ func Run(config schedulerconfig.Configuration) error {
 		return errors.WithMessage(err, "Error opening connection to postgres")
 	}
 	defer db.Close()
+	jobSpecEnvelope, err := encryption.NewEnvelopeFromConfig(config.JobSpecEncryption)
+	if err != nil {
+		return errors.WithMessage(err, "Error creating job spec encryption envelope")
+	}
 	jobRepository := database.NewPostgresJobRepository(db, int32(config.DatabaseFetchSize))
+	jobRepository.SetEncryption(jobSpecEnvelope)
 	executorRepository := database.NewPostgresExecutorRepository(db)
 
 	redisClient := redis.NewUniversalClient(config.Redis.AsUniversalOptions())
@@ -103,6 +110,7 @@ func Run(config schedulerconfig.Configuration) error {
 		CompressionLevel: config.Pulsar.CompressionLevel,
 		BatchingMaxSize:  config.Pulsar.MaxAllowedMessageSize,
 		Topic:            config.Pulsar.JobsetEventsTopic,
+		Encryption:       pulsarutils.ProducerEncryption(&config.Pulsar),
 	}, config.PulsarSendTimeout)
 	if err != nil {
 		return errors.WithMessage(err, "error creating pulsar publisher")
@@ -115,7 +123,10 @@ func Run(config schedulerconfig.Configuration) error {
 	if err != nil {
 		return errors.WithMessage(err, "error creating leader controller")
 	}
-	services = append(services, func() error { return leaderController.Run(ctx) })
+	// Leadership is only released once the scheduler has shut down, such that the leader may hand over to the next leader first.
+	leaderCtx, releaseLeadership := armadacontext.WithCancel(armadacontext.WithoutCancel(ctx))
+	defer releaseLeadership()
+	services = append(services, func() error { return leaderController.Run(leaderCtx) })
 
 	//////////////////////////////////////////////////////////////////////////
 	// Executor Api
@@ -127,6 +138,7 @@ func Run(config schedulerconfig.Configuration) error {
 		CompressionLevel: config.Pulsar.CompressionLevel,
 		BatchingMaxSize:  config.Pulsar.MaxAllowedMessageSize,
 		Topic:            config.Pulsar.JobsetEventsTopic,
+		Encryption:       pulsarutils.ProducerEncryption(&config.Pulsar),
 	})
 	if err != nil {
 		return errors.Wrapf(err, "error creating pulsar producer for executor api")
@@ -136,7 +148,10 @@ func Run(config schedulerconfig.Configuration) error {
 	if err != nil {
 		return errors.WithMessage(err, "error creating auth services")
 	}
-	grpcServer := grpcCommon.CreateGrpcServer(config.Grpc.KeepaliveParams, config.Grpc.KeepaliveEnforcementPolicy, authServices, config.Grpc.Tls)
+	grpcServer, err := grpcCommon.CreateGrpcServer(config.Grpc.KeepaliveParams, config.Grpc.KeepaliveEnforcementPolicy, authServices, config.Grpc.Tls)
+	if err != nil {
+		return errors.WithMessage(err, "error creating gRPC server")
+	}
 	defer grpcServer.GracefulStop()
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", config.Grpc.Port))
 	if err != nil {
@@ -156,6 +171,7 @@ func Run(config schedulerconfig.Configuration) error {
 	if err != nil {
 		return errors.WithMessage(err, "error creating executorApi")
 	}
+	executorServer.SetJobSpecEncryption(jobSpecEnvelope)
 	executorapi.RegisterExecutorApiServer(grpcServer, executorServer)
 	services = append(services, func() error {
 		ctx.Infof("Executor api listening on %s", lis.Addr())
@@ -189,16 +205,40 @@ func Run(config schedulerconfig.Configuration) error {
 		return errors.WithMessage(err, "error creating scheduling context repository")
 	}
 
+	if config.SchedulingReports.Persist {
+		persistedReports := NewPersistedSchedulingReports(database.NewPostgresSchedulingReportRepository(db), config.SchedulingReports)
+		services = append(services, func() error { return persistedReports.Run(ctx) })
+		schedulingContextRepository.SetPersistedSchedulingReports(persistedReports)
+	}
+
 	leaderClientConnectionProvider := NewLeaderConnectionProvider(leaderController, config.Leader)
 	schedulingReportServer := NewLeaderProxyingSchedulingReportsServer(schedulingContextRepository, leaderClientConnectionProvider)
 	schedulerobjects.RegisterSchedulerReportingServer(grpcServer, schedulingReportServer)
 
+	alerter := NewNotifyingAlerter(config.Alerting)
+	services = append(services, func() error { return alerter.Run(ctx) })
+	var budgetTracker *BudgetTracker
+	if config.Budgets.ResourceUsageUrl != "" {
+		budgetTracker, err = NewBudgetTracker(
+			config.Budgets,
+			config.Scheduling.Preemption.PriorityClasses,
+			NewLookoutGpuUsageSource(config.Budgets.ResourceUsageUrl, config.Budgets.Timeout),
+		)
+		if err != nil {
+			return errors.WithMessage(err, "error creating budget tracker")
+		}
+		services = append(services, func() error { return budgetTracker.Run(ctx, config.Budgets.RefreshInterval) })
+		mux.Handle("/budgets", NewBudgetsHttpHandler(budgetTracker))
+	}
 	schedulingAlgo, err := NewFairSchedulingAlgo(
 		config.Scheduling,
 		config.MaxSchedulingDuration,
 		executorRepository,
 		queueRepository,
 		schedulingContextRepository,
+		alerter,
+		NewSchedulingAlertDetector(config.Alerting),
+		budgetTracker,
 	)
 	if err != nil {
 		return errors.WithMessage(err, "error creating scheduling algo")
@@ -226,7 +266,114 @@ func Run(config schedulerconfig.Configuration) error {
 	if err != nil {
 		return errors.WithMessage(err, "error creating scheduler")
 	}
-	services = append(services, func() error { return scheduler.Run(ctx) })
+	executorDrainRepository := database.NewPostgresExecutorDrainRepository(db)
+	clusterDrainer := NewClusterDrainer(executorDrainRepository, executorRepository, config.Scheduling.Preemption.PriorityClasses, alerter)
+	scheduler.SetClusterDrainer(clusterDrainer)
+	scheduler.SetMaxUnsatisfiableRounds(config.Scheduling.MaxUnsatisfiableRounds)
+	scheduler.SetScheduleNearJobsPreferenceWeight(config.Scheduling.ScheduleNearJobsPreferenceWeight)
+	scheduler.SetShutdownGracePeriod(config.ShutdownGracePeriod)
+	if config.Scheduling.EnableGangHealing {
+		if !config.Scheduling.EnableGangRemainders {
+			return errors.New("gang healing requires gang remainders to be enabled")
+		}
+		scheduler.SetGangHealer(NewGangHealer())
+	}
+	handoverStateByName := make(map[string]HandoverState)
+	if config.LeaseAcknowledgementTimeout > 0 {
+		leaseAcknowledger := NewLeaseAcknowledger(config.LeaseAcknowledgementTimeout)
+		scheduler.SetLeaseAcknowledger(leaseAcknowledger)
+		handoverStateByName["lease_acknowledgements"] = leaseAcknowledger
+	}
+	schedulingAlgo.SetClusterDrainer(clusterDrainer)
+	schedulingExclusionRepository := database.NewPostgresSchedulingExclusionRepository(db)
+	schedulingAlgo.SetSchedulingExclusions(NewSchedulingExclusions(schedulingExclusionRepository))
+	nodeSnapshots := NewNodeSnapshots()
+	schedulingAlgo.SetNodeSnapshots(nodeSnapshots)
+	schedulingAlgo.SetRoundRegressionDetector(NewRoundRegressionDetector(config.Alerting.RoundRegression))
+	if config.CloudBurst.CloudPool != "" {
+		cloudBurstPolicy, err := NewCloudBurstPolicy(
+			config.CloudBurst,
+			NewLookoutGpuUsageSource(config.CloudBurst.ResourceUsageUrl, config.CloudBurst.Timeout),
+		)
+		if err != nil {
+			return errors.WithMessage(err, "error creating cloud burst policy")
+		}
+		services = append(services, func() error { return cloudBurstPolicy.Run(ctx, config.CloudBurst.RefreshInterval) })
+		mux.Handle("/cloudBurst", NewCloudBurstHttpHandler(cloudBurstPolicy))
+		schedulingAlgo.SetCloudBurstPolicy(cloudBurstPolicy)
+	}
+	if config.CapacityForecast.Horizon > 0 {
+		capacityForecaster, err := NewCapacityForecaster(config.CapacityForecast)
+		if err != nil {
+			return errors.WithMessage(err, "error creating capacity forecaster")
+		}
+		mux.Handle("/capacityForecast", NewCapacityForecastHttpHandler(capacityForecaster))
+		schedulingAlgo.SetCapacityForecaster(capacityForecaster)
+	}
+	if config.Scheduling.PreemptionCompensation.HalfLife > 0 {
+		preemptionCompensationLedger, err := NewPreemptionCompensationLedger(
+			config.Scheduling.PreemptionCompensation,
+			config.Scheduling.DominantResourceFairnessResourcesToConsider,
+		)
+		if err != nil {
+			return errors.WithMessage(err, "error creating preemption compensation ledger")
+		}
+		mux.Handle("/preemptionCompensation", NewPreemptionCompensationHttpHandler(preemptionCompensationLedger))
+		schedulingAlgo.SetPreemptionCompensationLedger(preemptionCompensationLedger)
+		handoverStateByName["preemption_compensation"] = preemptionCompensationLedger
+	}
+	if config.Scheduling.GangAccumulation.Enabled {
+		gangAccumulator, err := NewGangAccumulator(config.Scheduling.GangAccumulation)
+		if err != nil {
+			return errors.WithMessage(err, "error creating gang accumulator")
+		}
+		mux.Handle("/gangAccumulation", NewGangAccumulationHttpHandler(gangAccumulator))
+		schedulingAlgo.SetGangAccumulator(gangAccumulator)
+	}
+	if config.Scheduling.MaxSkippedUnchangedRounds > 0 || config.Scheduling.MaxConsecutiveIncrementalRounds > 0 {
+		// Only enabled if rounds are digested anyway, since the cache relies on the digests of rounds.
+		unfeasibleSchedulingKeyCache := NewUnfeasibleSchedulingKeyCache()
+		schedulingAlgo.SetUnfeasibleSchedulingKeyCache(unfeasibleSchedulingKeyCache)
+		handoverStateByName["unfeasible_scheduling_keys"] = unfeasibleSchedulingKeyCache
+	}
+	scheduler.SetHandoverState(database.NewPostgresSchedulerStateRepository(db), handoverStateByName)
+	if len(config.Canary.Probes) > 0 {
+		canary, err := NewCanary(config.Canary, NewApiCanaryRunner(&config.Canary.ArmadaApi), leaderController, alerter)
+		if err != nil {
+			return errors.WithMessage(err, "error creating canary")
+		}
+		services = append(services, func() error { return canary.Run(ctx) })
+		mux.Handle("/canary", NewCanaryHttpHandler(canary))
+		prometheus.MustRegister(canary)
+	}
+	services = append(services, func() error {
+		defer releaseLeadership()
+		return scheduler.Run(ctx)
+	})
+	schedulerAdminServer := NewLeaderProxyingSchedulerAdminServer(NewSchedulerAdminServer(scheduler), leaderClientConnectionProvider)
+	schedulerobjects.RegisterSchedulerAdminServer(grpcServer, schedulerAdminServer)
+
+	// Readiness additionally requires the dependencies of the scheduler to be available and the scheduler to be cycling.
+	dependencyChecks := []DependencyCheck{
+		PostgresCheck(db),
+		RedisCheck(redisClient),
+		PulsarCheck(pulsarClient, config.Pulsar.JobsetEventsTopic),
+	}
+	if config.Health.MaxExecutorUpdateAge > 0 {
+		dependencyChecks = append(dependencyChecks, ExecutorApiCheck(executorRepository, config.Health.MaxExecutorUpdateAge, clock.RealClock{}))
+	}
+	schedulerHealthChecker := NewSchedulerHealthChecker(scheduler, config.Health, dependencyChecks...)
+	mux.Handle("/health/status", schedulerHealthChecker)
+	mux.Handle("/ready", health.NewCheckHttpHandler(health.NewMultiChecker(startupCompleteCheck, schedulerHealthChecker)))
+	mux.Handle("/drains", NewExecutorDrainsHttpHandler(executorDrainRepository))
+	mux.Handle("/exclusions", NewSchedulingExclusionsHttpHandler(schedulingExclusionRepository))
+	mux.Handle("/runAttempts", NewRunAttemptsHttpHandler(jobDb, executorRepository))
+	mux.Handle("/gangStatus", NewGangStatusHttpHandler(jobDb, executorRepository, schedulingContextRepository))
+	mux.Handle("/candidateNodes", NewCandidateNodesHttpHandler(config.Scheduling, executorRepository, jobDb))
+	mux.Handle("/nodeLabels", NewNodeLabelsHttpHandler(config.Scheduling, executorRepository, jobDb))
+	nodeSnapshotsHttpHandler := NewNodeSnapshotsHttpHandler(nodeSnapshots)
+	mux.Handle("/nodeSnapshots", nodeSnapshotsHttpHandler)
+	mux.Handle("/nodeSnapshots/diff", nodeSnapshotsHttpHandler)
 
 	//////////////////////////////////////////////////////////////////////////
 	// Metrics
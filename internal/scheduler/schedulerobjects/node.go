@@ -48,6 +48,15 @@ func (node *Node) CompactString() string {
 	return fmt.Sprintf("Node{Id; %s}", node.Id)
 }
 
+// NonArmadaAllocatedResource returns the total resources allocated to pods on this node not managed by Armada.
+func (node *Node) NonArmadaAllocatedResource() ResourceList {
+	var rv ResourceList
+	for _, rl := range node.NonArmadaAllocatedResources {
+		rv.Add(rl)
+	}
+	return rv
+}
+
 func (node *Node) AvailableArmadaResource() ResourceList {
 	tr := node.TotalResources.DeepCopy()
 	for _, rl := range node.NonArmadaAllocatedResources {
@@ -8,11 +8,12 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
 )
 
 const (
 	// When checking if a pod fits on a node, this score indicates how well the pods fits.
-	// However, all nodes are currently given the same score.
+	// However, all nodes are given the same score, except for the score added by a NodePreferenceScorer.
 	SchedulableScore                                 = 0
 	SchedulableBestScore                             = SchedulableScore
 	PodRequirementsNotMetReasonUnmatchedNodeSelector = "node does not match pod NodeAffinity"
@@ -107,6 +108,100 @@ func (err *InsufficientResources) String() string {
 		err.Available.String() + " is available"
 }
 
+// MaxJobsPerNodeReached indicates that a node already has the maximum number of jobs per node bound to it.
+type MaxJobsPerNodeReached struct {
+	Limit uint
+}
+
+func (r *MaxJobsPerNodeReached) Sum64() uint64 {
+	h := fnv1a.Init64
+	h = fnv1a.AddString64(h, "maxJobsPerNode")
+	h = fnv1a.AddUint64(h, uint64(r.Limit))
+	return h
+}
+
+func (r *MaxJobsPerNodeReached) String() string {
+	return fmt.Sprintf("node already runs the maximum of %d jobs per node", r.Limit)
+}
+
+// MaxGangMembersPerNodeReached indicates that a node already has the maximum number of members of a gang per node bound to it.
+// The gang id is omitted, such that the reason is the same for all gangs.
+type MaxGangMembersPerNodeReached struct {
+	Limit uint
+}
+
+func (r *MaxGangMembersPerNodeReached) Sum64() uint64 {
+	h := fnv1a.Init64
+	h = fnv1a.AddString64(h, "maxGangMembersPerNode")
+	h = fnv1a.AddUint64(h, uint64(r.Limit))
+	return h
+}
+
+func (r *MaxGangMembersPerNodeReached) String() string {
+	return fmt.Sprintf("node already runs the maximum of %d members of the gang per node", r.Limit)
+}
+
+// NodeReservedForGang indicates that a node is reserved for a gang accumulating capacity across scheduling rounds,
+// and hence unavailable to jobs not in that gang.
+type NodeReservedForGang struct {
+	GangId string
+}
+
+func (r *NodeReservedForGang) Sum64() uint64 {
+	h := fnv1a.Init64
+	h = fnv1a.AddString64(h, "nodeReservedForGang")
+	h = fnv1a.AddString64(h, r.GangId)
+	return h
+}
+
+func (r *NodeReservedForGang) String() string {
+	return fmt.Sprintf("node is reserved for gang %s", r.GangId)
+}
+
+// NumaCellRequirementsNotMet indicates that a pod requiring a single NUMA cell doesn't fit within any cell of a node,
+// or that the NUMA topology of the node is unknown.
+type NumaCellRequirementsNotMet struct {
+	// Number of NUMA cells of the node; zero if unknown.
+	NumaCells int
+}
+
+func (r *NumaCellRequirementsNotMet) Sum64() uint64 {
+	h := fnv1a.Init64
+	h = fnv1a.AddString64(h, "numaCell")
+	h = fnv1a.AddUint64(h, uint64(r.NumaCells))
+	return h
+}
+
+func (r *NumaCellRequirementsNotMet) String() string {
+	if r.NumaCells == 0 {
+		return "pod requires a single NUMA cell, but the NUMA topology of the node is unknown"
+	}
+	return fmt.Sprintf("pod requires a single NUMA cell, but doesn't fit within any of the %d cells of the node", r.NumaCells)
+}
+
+// NodeRejectionCategory returns a summary of reason that's the same for all nodes rejected for the same kind of reason,
+// e.g., "insufficient cpu" rather than the amounts required and available, such that rejections can be counted across nodes.
+func NodeRejectionCategory(reason PodRequirementsNotMetReason) string {
+	switch r := reason.(type) {
+	case nil:
+		return PodRequirementsNotMetReasonUnknown
+	case *InsufficientResources:
+		return "insufficient " + r.Resource
+	case *UntoleratedTaint:
+		return r.String()
+	case *MissingLabel:
+		return "node selector mismatch on label " + r.Label
+	case *UnmatchedLabel:
+		return "node selector mismatch on label " + r.Label
+	case *UnmatchedNodeSelector:
+		return "node affinity mismatch"
+	case *NumaCellRequirementsNotMet:
+		return "no single NUMA cell fits"
+	default:
+		return reason.String()
+	}
+}
+
 // PodRequirementsMet determines whether a pod can be scheduled on nodes of this NodeType.
 // If the requirements are not met, it returns the reason for why.
 // If the requirements can't be parsed, an error is returned.
@@ -171,6 +266,51 @@ func DynamicPodRequirementsMet(allocatableResources ResourceList, req *PodRequir
 	return matches, SchedulableScore, reason, err
 }
 
+// NodePreferenceScorer scores nodes by the preferred node affinity terms of a pod,
+// such that pods can prefer, e.g., a zone without becoming unschedulable once that zone is full.
+type NodePreferenceScorer struct {
+	terms *nodeaffinity.PreferredSchedulingTerms
+	// Score of nodes matching all preferred terms.
+	maxScore int
+}
+
+// NewNodePreferenceScorer returns a scorer for the preferred node affinity terms of req,
+// or nil if req has no preferences, in which case all nodes score equally.
+func NewNodePreferenceScorer(req *PodRequirements) (*NodePreferenceScorer, error) {
+	preferredTerms := req.GetPreferredNodeAffinityTerms()
+	if len(preferredTerms) == 0 {
+		return nil, nil
+	}
+	terms, err := nodeaffinity.NewPreferredSchedulingTerms(preferredTerms)
+	if err != nil {
+		return nil, err
+	}
+	maxScore := 0
+	for _, term := range preferredTerms {
+		// Empty terms match no nodes.
+		if len(term.Preference.MatchExpressions) > 0 || len(term.Preference.MatchFields) > 0 {
+			maxScore += int(term.Weight)
+		}
+	}
+	return &NodePreferenceScorer{terms: terms, maxScore: maxScore}, nil
+}
+
+// Score returns the sum of the weights of the preferred terms matched by a node with the given labels.
+func (s *NodePreferenceScorer) Score(labels map[string]string) int {
+	if s == nil {
+		return 0
+	}
+	return int(s.terms.Score(&v1.Node{ObjectMeta: metav1.ObjectMeta{Labels: labels}}))
+}
+
+// MaxScore returns the score of nodes matching all preferred terms.
+func (s *NodePreferenceScorer) MaxScore() int {
+	if s == nil {
+		return 0
+	}
+	return s.maxScore
+}
+
 func podTolerationRequirementsMet(nodeTaints []v1.Taint, req *PodRequirements) (bool, PodRequirementsNotMetReason, error) {
 	untoleratedTaint, hasUntoleratedTaint := corev1.FindMatchingUntoleratedTaint(
 		nodeTaints,
@@ -78,6 +78,12 @@ type Executor struct {
 	LastUpdateTime time.Time `protobuf:"bytes,5,opt,name=lastUpdateTime,proto3,stdtime" json:"lastUpdateTime"`
 	// Jobs that are owned by the cluster but are not assigned to any node.
 	UnassignedJobRuns []string `protobuf:"bytes,9,rep,name=unassigned_job_runs,json=unassignedJobRuns,proto3" json:"unassignedJobRuns,omitempty"`
+	// Kubernetes version of the cluster, as reported by the executor.
+	KubernetesVersion string `protobuf:"bytes,10,opt,name=kubernetes_version,json=kubernetesVersion,proto3" json:"kubernetesVersion,omitempty"`
+	// Runtime classes available in the cluster, as reported by the executor.
+	RuntimeClasses []string `protobuf:"bytes,11,rep,name=runtime_classes,json=runtimeClasses,proto3" json:"runtimeClasses,omitempty"`
+	// Optional features supported by the executor, e.g., in-place pod resize.
+	Features []string `protobuf:"bytes,12,rep,name=features,proto3" json:"features,omitempty"`
 }
 
 func (m *Executor) Reset()         { *m = Executor{} }
@@ -155,6 +161,27 @@ func (m *Executor) GetUnassignedJobRuns() []string {
 	return nil
 }
 
+func (m *Executor) GetKubernetesVersion() string {
+	if m != nil {
+		return m.KubernetesVersion
+	}
+	return ""
+}
+
+func (m *Executor) GetRuntimeClasses() []string {
+	if m != nil {
+		return m.RuntimeClasses
+	}
+	return nil
+}
+
+func (m *Executor) GetFeatures() []string {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
 // Node represents a node in a worker cluster.
 type Node struct {
 	// Id associated with the node. Must be unique across all clusters.
@@ -1196,6 +1223,31 @@ func (m *Executor) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Features) > 0 {
+		for iNdEx := len(m.Features) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Features[iNdEx])
+			copy(dAtA[i:], m.Features[iNdEx])
+			i = encodeVarintSchedulerobjects(dAtA, i, uint64(len(m.Features[iNdEx])))
+			i--
+			dAtA[i] = 0x62
+		}
+	}
+	if len(m.RuntimeClasses) > 0 {
+		for iNdEx := len(m.RuntimeClasses) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.RuntimeClasses[iNdEx])
+			copy(dAtA[i:], m.RuntimeClasses[iNdEx])
+			i = encodeVarintSchedulerobjects(dAtA, i, uint64(len(m.RuntimeClasses[iNdEx])))
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
+	if len(m.KubernetesVersion) > 0 {
+		i -= len(m.KubernetesVersion)
+		copy(dAtA[i:], m.KubernetesVersion)
+		i = encodeVarintSchedulerobjects(dAtA, i, uint64(len(m.KubernetesVersion)))
+		i--
+		dAtA[i] = 0x52
+	}
 	if len(m.UnassignedJobRuns) > 0 {
 		for iNdEx := len(m.UnassignedJobRuns) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.UnassignedJobRuns[iNdEx])
@@ -2195,6 +2247,22 @@ func (m *Executor) Size() (n int) {
 			n += 1 + l + sovSchedulerobjects(uint64(l))
 		}
 	}
+	l = len(m.KubernetesVersion)
+	if l > 0 {
+		n += 1 + l + sovSchedulerobjects(uint64(l))
+	}
+	if len(m.RuntimeClasses) > 0 {
+		for _, s := range m.RuntimeClasses {
+			l = len(s)
+			n += 1 + l + sovSchedulerobjects(uint64(l))
+		}
+	}
+	if len(m.Features) > 0 {
+		for _, s := range m.Features {
+			l = len(s)
+			n += 1 + l + sovSchedulerobjects(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -2805,6 +2873,102 @@ func (m *Executor) Unmarshal(dAtA []byte) error {
 			}
 			m.UnassignedJobRuns = append(m.UnassignedJobRuns, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KubernetesVersion", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSchedulerobjects
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSchedulerobjects
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSchedulerobjects
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.KubernetesVersion = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RuntimeClasses", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSchedulerobjects
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSchedulerobjects
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSchedulerobjects
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RuntimeClasses = append(m.RuntimeClasses, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Features", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSchedulerobjects
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSchedulerobjects
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSchedulerobjects
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Features = append(m.Features, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSchedulerobjects(dAtA[iNdEx:])
@@ -0,0 +1,93 @@
+package schedulerobjects
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// UnschedulableReasonCode identifies the cause of a job being unschedulable,
+// such that consumers can branch on it without parsing messages.
+type UnschedulableReasonCode string
+
+const (
+	UnschedulableReasonCodeUnknown                       UnschedulableReasonCode = "Unknown"
+	UnschedulableReasonCodeMaximumResourcesScheduled     UnschedulableReasonCode = "MaximumResourcesScheduled"
+	UnschedulableReasonCodeMaximumResourcesPerQueue      UnschedulableReasonCode = "MaximumResourcesPerQueueExceeded"
+	UnschedulableReasonCodeJobTooSmall                   UnschedulableReasonCode = "JobTooSmall"
+	UnschedulableReasonCodeBelowPoolMinimumJobSize       UnschedulableReasonCode = "BelowPoolMinimumJobSize"
+	UnschedulableReasonCodeGlobalRateLimitExceeded       UnschedulableReasonCode = "GlobalRateLimitExceeded"
+	UnschedulableReasonCodeQueueRateLimitExceeded        UnschedulableReasonCode = "QueueRateLimitExceeded"
+	UnschedulableReasonCodeGlobalRateLimitExceededByGang UnschedulableReasonCode = "GlobalRateLimitExceededByGang"
+	UnschedulableReasonCodeQueueRateLimitExceededByGang  UnschedulableReasonCode = "QueueRateLimitExceededByGang"
+	UnschedulableReasonCodeGangExceedsGlobalBurstSize    UnschedulableReasonCode = "GangExceedsGlobalBurstSize"
+	UnschedulableReasonCodeGangExceedsQueueBurstSize     UnschedulableReasonCode = "GangExceedsQueueBurstSize"
+	UnschedulableReasonCodeJobSetMaxRunningJobsExceeded  UnschedulableReasonCode = "JobSetMaxRunningJobsExceeded"
+	UnschedulableReasonCodeJobPaused                     UnschedulableReasonCode = "JobPaused"
+	UnschedulableReasonCodeQueueBlocked                  UnschedulableReasonCode = "QueueBlocked"
+	UnschedulableReasonCodeJobDoesNotFit                 UnschedulableReasonCode = "JobDoesNotFit"
+	UnschedulableReasonCodeGangMinCardinalityNotMet      UnschedulableReasonCode = "GangMinCardinalityNotMet"
+	UnschedulableReasonCodeNodeUniformity                UnschedulableReasonCode = "NodeUniformity"
+	UnschedulableReasonCodeFailureDomain                 UnschedulableReasonCode = "FailureDomain"
+	UnschedulableReasonCodeSpread                        UnschedulableReasonCode = "Spread"
+	UnschedulableReasonCodeColocation                    UnschedulableReasonCode = "Colocation"
+	// Terminal: the jobs of the gang are failed.
+	UnschedulableReasonCodeGangSchedulingDeadlineExceeded UnschedulableReasonCode = "GangSchedulingDeadlineExceeded"
+)
+
+// UnschedulableReason explains why a job, or a gang of jobs, could not be scheduled.
+type UnschedulableReason struct {
+	Code UnschedulableReasonCode
+	// Human-readable explanation.
+	Message string
+	// Resources the job, or the queue or round it was considered in, has too much or too little of, if any.
+	Resources []string
+}
+
+// NewUnschedulableReason returns a reason with the given code and a message formatted according to format.
+func NewUnschedulableReason(code UnschedulableReasonCode, format string, args ...any) *UnschedulableReason {
+	return &UnschedulableReason{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// WithResources returns a copy of the reason with the offending resources set.
+func (r *UnschedulableReason) WithResources(resources ...string) *UnschedulableReason {
+	rv := *r
+	rv.Resources = resources
+	return &rv
+}
+
+// WithMessage returns a copy of the reason with its message replaced by message.
+func (r *UnschedulableReason) WithMessage(message string) *UnschedulableReason {
+	rv := *r
+	rv.Message = message
+	return &rv
+}
+
+// GetCode returns the code of the reason, or the empty string if r is nil.
+func (r *UnschedulableReason) GetCode() UnschedulableReasonCode {
+	if r == nil {
+		return ""
+	}
+	return r.Code
+}
+
+func (r *UnschedulableReason) Error() string {
+	if len(r.Resources) == 0 {
+		return r.Message
+	}
+	return fmt.Sprintf("%s (resources: %s)", r.Message, strings.Join(r.Resources, ", "))
+}
+
+// UnschedulableReasonCodeOf returns the code of the UnschedulableReason in err's chain,
+// or UnschedulableReasonCodeUnknown if there's none.
+func UnschedulableReasonCodeOf(err error) UnschedulableReasonCode {
+	var reason *UnschedulableReason
+	if errors.As(err, &reason) && reason != nil {
+		return reason.Code
+	}
+	return UnschedulableReasonCodeUnknown
+}
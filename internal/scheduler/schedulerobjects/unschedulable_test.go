@@ -0,0 +1,23 @@
+package schedulerobjects
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnschedulableReason(t *testing.T) {
+	reason := NewUnschedulableReason(UnschedulableReasonCodeMaximumResourcesPerQueue, "maximum total resources for queue %s exceeded", "A")
+	assert.Equal(t, "maximum total resources for queue A exceeded", reason.Error())
+
+	withResources := reason.WithResources("cpu", "memory")
+	assert.Equal(t, "maximum total resources for queue A exceeded (resources: cpu, memory)", withResources.Error())
+	assert.Empty(t, reason.Resources, "WithResources must not mutate the original reason")
+
+	assert.Equal(t, UnschedulableReasonCodeMaximumResourcesPerQueue, UnschedulableReasonCodeOf(errors.Wrap(withResources, "scheduling failed")))
+	assert.Equal(t, UnschedulableReasonCodeUnknown, UnschedulableReasonCodeOf(errors.New("scheduling failed")))
+
+	var nilReason *UnschedulableReason
+	assert.Equal(t, UnschedulableReasonCode(""), nilReason.GetCode())
+}
@@ -2,7 +2,10 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/benbjohnson/immutable"
@@ -37,6 +40,27 @@ type SchedulingAlgo interface {
 	Schedule(ctx *armadacontext.Context, txn *jobdb.Txn) (*SchedulerResult, error)
 }
 
+// RoundScope restricts a scheduling round to a subset of pools and queues.
+// The zero value doesn't restrict the round.
+type RoundScope struct {
+	// If non-empty, only executors of this pool are scheduled onto.
+	Pool string
+	// If non-empty, only queued jobs of this queue are scheduled.
+	// Jobs of other queues may still be preempted to make room for jobs of this queue.
+	Queue string
+}
+
+// IsEmpty returns true if scope doesn't restrict the round.
+func (scope RoundScope) IsEmpty() bool {
+	return scope == RoundScope{}
+}
+
+// ScopedSchedulingAlgo is a SchedulingAlgo that can also run rounds restricted to a RoundScope.
+type ScopedSchedulingAlgo interface {
+	SchedulingAlgo
+	ScheduleScoped(ctx *armadacontext.Context, txn *jobdb.Txn, scope RoundScope) (*SchedulerResult, error)
+}
+
 // FairSchedulingAlgo is a SchedulingAlgo based on PreemptingQueueScheduler.
 type FairSchedulingAlgo struct {
 	schedulingConfig            configuration.SchedulingConfig
@@ -47,6 +71,8 @@ type FairSchedulingAlgo struct {
 	limiter *rate.Limiter
 	// Per-queue job scheduling rate-limiters.
 	limiterByQueue map[string]*rate.Limiter
+	// Protects limiterByQueue, since pools may be scheduled concurrently.
+	limiterByQueueMu sync.Mutex
 	// Max amount of time each scheduling round is allowed to take.
 	maxSchedulingDuration time.Duration
 	// Order in which to schedule executor groups.
@@ -54,6 +80,40 @@ type FairSchedulingAlgo struct {
 	executorGroupsToSchedule []string
 	// Function that is called every time an executor is scheduled. Useful for testing.
 	onExecutorScheduled func(executor *schedulerobjects.Executor)
+	// Receives operator alerts raised during scheduling.
+	alerter Alerter
+	// Detects starvation and fair share breaches. May be nil, in which case no such alerts are raised.
+	alertDetector *SchedulingAlertDetector
+	// Tracks per-queue budgets, which are enforced when exhausted. May be nil, in which case no budgets are enforced.
+	budgetTracker *BudgetTracker
+	// Clusters being drained are excluded from scheduling. May be nil, in which case no clusters are drained.
+	clusterDrainer *ClusterDrainer
+	// Queued jobs of excluded queues and job sets aren't scheduled. May be nil, in which case nothing is excluded.
+	schedulingExclusions *SchedulingExclusions
+	// Records changes to the nodes of each executor group across rounds. May be nil, in which case nothing is recorded.
+	nodeSnapshots *NodeSnapshots
+	// Compares consecutive rounds on each executor group to flag regressions. May be nil, in which case none are flagged.
+	roundRegressionDetector *RoundRegressionDetector
+	// Decides when queued jobs of designated queues overflow into a cloud pool. May be nil, in which case jobs never overflow.
+	cloudBurstPolicy *CloudBurstPolicy
+	// If non-nil, forecasts the capacity of each pool, so that large gangs may be held rather than failed.
+	capacityForecaster *CapacityForecaster
+	// Credits queues for work lost to preemption by increasing their weight. May be nil, in which case queues aren't credited.
+	preemptionCompensationLedger *PreemptionCompensationLedger
+	// Reserves nodes for large gangs accumulating capacity across rounds. May be nil, in which case no nodes are reserved.
+	gangAccumulator *GangAccumulator
+	// Digests of the inputs of the most recent completed round that made no decisions,
+	// used to skip rounds whose inputs are unchanged and to schedule incrementally. May be nil.
+	previousRoundDigests *roundInputDigests
+	// Scheduling contexts of the round previousRoundDigests are digests of, republished by rounds skipped since their inputs are unchanged.
+	previousRoundSchedulingContexts []*schedulercontext.SchedulingContext
+	// Carries the scheduling keys found to be unfeasible over to subsequent rounds with unchanged inputs.
+	// May be nil, in which case keys are found anew each round.
+	unfeasibleSchedulingKeyCache *UnfeasibleSchedulingKeyCache
+	// Number of consecutive rounds skipped since their inputs were unchanged.
+	numSkippedUnchangedRounds uint
+	// Number of consecutive incremental rounds, i.e., rounds only considering the queued jobs of queues that changed.
+	numIncrementalRounds uint
 	// rand and clock injected here for repeatable testing.
 	rand  *rand.Rand
 	clock clock.Clock
@@ -65,10 +125,16 @@ func NewFairSchedulingAlgo(
 	executorRepository database.ExecutorRepository,
 	queueRepository database.QueueRepository,
 	schedulingContextRepository *SchedulingContextRepository,
+	alerter Alerter,
+	alertDetector *SchedulingAlertDetector,
+	budgetTracker *BudgetTracker,
 ) (*FairSchedulingAlgo, error) {
 	if _, ok := config.Preemption.PriorityClasses[config.Preemption.DefaultPriorityClass]; !ok {
 		return nil, errors.Errorf("default priority class %s is missing from priority class mapping %v", config.Preemption.DefaultPriorityClass, config.Preemption.PriorityClasses)
 	}
+	if err := validatePreemptionFreePools(config); err != nil {
+		return nil, err
+	}
 	return &FairSchedulingAlgo{
 		schedulingConfig:            config,
 		executorRepository:          executorRepository,
@@ -80,9 +146,85 @@ func NewFairSchedulingAlgo(
 		rand:                        util.NewThreadsafeRand(time.Now().UnixNano()),
 		clock:                       clock.RealClock{},
 		onExecutorScheduled:         func(executor *schedulerobjects.Executor) {},
+		alerter:                     alerter,
+		alertDetector:               alertDetector,
+		budgetTracker:               budgetTracker,
 	}, nil
 }
 
+// validatePreemptionFreePools returns an error if any priority class with per-pool limits for a preemption-free pool
+// has higher priority than a preemptible priority class, since jobs of such a priority class would otherwise rely on
+// preempting jobs of the preemptible priority class to be scheduled in that pool.
+func validatePreemptionFreePools(config configuration.SchedulingConfig) error {
+	for _, pool := range config.PreemptionFreePools {
+		for name, priorityClass := range config.Preemption.PriorityClasses {
+			if _, ok := priorityClass.MaximumResourceFractionPerQueueByPool[pool]; !ok {
+				continue
+			}
+			for otherName, otherPriorityClass := range config.Preemption.PriorityClasses {
+				if otherPriorityClass.Preemptible && otherPriorityClass.Priority < priorityClass.Priority {
+					return errors.Errorf(
+						"priority class %s targets preemption-free pool %s but may preempt jobs of priority class %s",
+						name, pool, otherName,
+					)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// SetClusterDrainer sets the component tracking which clusters are being drained, such that no jobs are scheduled onto them.
+func (l *FairSchedulingAlgo) SetClusterDrainer(clusterDrainer *ClusterDrainer) {
+	l.clusterDrainer = clusterDrainer
+}
+
+// SetSchedulingExclusions sets the component tracking which queues and job sets are excluded, such that their queued jobs aren't scheduled.
+func (l *FairSchedulingAlgo) SetSchedulingExclusions(schedulingExclusions *SchedulingExclusions) {
+	l.schedulingExclusions = schedulingExclusions
+}
+
+// SetNodeSnapshots sets the component recording changes to the nodes of each executor group across scheduling rounds.
+func (l *FairSchedulingAlgo) SetNodeSnapshots(nodeSnapshots *NodeSnapshots) {
+	l.nodeSnapshots = nodeSnapshots
+}
+
+// SetRoundRegressionDetector sets the component comparing consecutive rounds on each executor group,
+// such that regressions, e.g., a sudden drop in the number of jobs scheduled, are alerted on.
+func (l *FairSchedulingAlgo) SetRoundRegressionDetector(roundRegressionDetector *RoundRegressionDetector) {
+	l.roundRegressionDetector = roundRegressionDetector
+}
+
+// SetCapacityForecaster sets the forecaster of the capacity of each pool, which scheduling rounds hold large gangs past
+// their scheduling deadline by while capacity they'd fit onto is forecast.
+func (l *FairSchedulingAlgo) SetCapacityForecaster(capacityForecaster *CapacityForecaster) {
+	l.capacityForecaster = capacityForecaster
+}
+
+// SetCloudBurstPolicy sets the policy deciding when queued jobs of designated queues overflow into a cloud pool.
+// Other queued jobs are only scheduled onto the cloud pool if they target it.
+func (l *FairSchedulingAlgo) SetCloudBurstPolicy(cloudBurstPolicy *CloudBurstPolicy) {
+	l.cloudBurstPolicy = cloudBurstPolicy
+}
+
+// SetPreemptionCompensationLedger sets the component crediting queues for work lost to preemption,
+// such that the weight of queues is increased according to their credit.
+func (l *FairSchedulingAlgo) SetPreemptionCompensationLedger(preemptionCompensationLedger *PreemptionCompensationLedger) {
+	l.preemptionCompensationLedger = preemptionCompensationLedger
+}
+
+// SetGangAccumulator sets the component reserving nodes for large gangs that can't currently be scheduled,
+// such that capacity accumulates on these nodes across rounds until the gang fits.
+func (l *FairSchedulingAlgo) SetGangAccumulator(gangAccumulator *GangAccumulator) {
+	l.gangAccumulator = gangAccumulator
+}
+
+// SetUnfeasibleSchedulingKeyCache sets the cache carrying the scheduling keys found to be unfeasible over to subsequent rounds,
+// such that queued jobs with these keys are rejected immediately while the inputs of rounds, other than queued jobs, are unchanged.
+func (l *FairSchedulingAlgo) SetUnfeasibleSchedulingKeyCache(unfeasibleSchedulingKeyCache *UnfeasibleSchedulingKeyCache) {
+	l.unfeasibleSchedulingKeyCache = unfeasibleSchedulingKeyCache
+}
+
 // Schedule assigns jobs to nodes in the same way as the old lease call.
 // It iterates over each executor in turn (using lexicographical order) and assigns the jobs using a LegacyScheduler, before moving onto the next executor.
 // It maintains state of which executors it has considered already and may take multiple Schedule() calls to consider all executors if scheduling is slow.
@@ -90,6 +232,17 @@ func NewFairSchedulingAlgo(
 func (l *FairSchedulingAlgo) Schedule(
 	ctx *armadacontext.Context,
 	txn *jobdb.Txn,
+) (*SchedulerResult, error) {
+	return l.ScheduleScoped(ctx, txn, RoundScope{})
+}
+
+// ScheduleScoped is like Schedule, but only schedules onto executors of scope.Pool and only schedules queued jobs of scope.Queue, if set.
+// Rounds with a non-empty scope are independent of regular rounds: they consider all executor groups in scope,
+// even if the previous regular round was incomplete, and they're never skipped because their inputs are unchanged.
+func (l *FairSchedulingAlgo) ScheduleScoped(
+	ctx *armadacontext.Context,
+	txn *jobdb.Txn,
+	scope RoundScope,
 ) (*SchedulerResult, error) {
 	var cancel context.CancelFunc
 	if l.maxSchedulingDuration != 0 {
@@ -97,9 +250,11 @@ func (l *FairSchedulingAlgo) Schedule(
 		defer cancel()
 	}
 	overallSchedulerResult := &SchedulerResult{
-		NodeIdByJobId:      make(map[string]string),
-		SchedulingContexts: make([]*schedulercontext.SchedulingContext, 0, 0),
-		FailedJobs:         make([]interfaces.LegacySchedulerJob, 0),
+		NodeIdByJobId:        make(map[string]string),
+		PreemptionByJobId:    make(map[string]*Preemption),
+		SchedulingContexts:   make([]*schedulercontext.SchedulingContext, 0, 0),
+		FailedJobs:           make([]interfaces.LegacySchedulerJob, 0),
+		FailureReasonByJobId: make(map[string]string),
 	}
 
 	// Exit immediately if scheduling is disabled.
@@ -112,33 +267,241 @@ func (l *FairSchedulingAlgo) Schedule(
 	if err != nil {
 		return nil, err
 	}
+	fsctx.scope = scope
+	if l.schedulingExclusions != nil {
+		fsctx.exclusions = l.schedulingExclusions.Refresh(ctx)
+	}
+	if l.cloudBurstPolicy != nil {
+		l.cloudBurstPolicy.Observe(fsctx.executors, fsctx.totalCapacityByPool, fsctx.allocationByPoolAndQueueAndPriorityClass)
+	}
+	if l.capacityForecaster != nil {
+		l.capacityForecaster.Observe(fsctx.executors)
+	}
+
+	if l.alertDetector != nil {
+		for _, alert := range l.alertDetector.DetectQueueDepth(fsctx.numQueuedJobsByQueue) {
+			l.alerter.Alert(alert)
+		}
+	}
+
+	var digests *roundInputDigests
+	if !scope.IsEmpty() {
+		// The decisions of scoped rounds change the inputs of regular rounds.
+		l.previousRoundDigests = nil
+	} else if l.schedulingConfig.MaxSkippedUnchangedRounds > 0 ||
+		l.schedulingConfig.MaxConsecutiveIncrementalRounds > 0 ||
+		l.unfeasibleSchedulingKeyCache != nil {
+		digests = newRoundInputDigests(fsctx, l.roundTimeDependentInputs(l.clock.Now()))
+		fsctx.digests = digests
+		previousRoundDigests := l.previousRoundDigests
+		// Reset until this round is completed, such that an incomplete round is never repeated.
+		l.previousRoundDigests = nil
+		isRoundComplete := len(l.executorGroupsToSchedule) == 0
+
+		// Skip this round if its inputs are unchanged from the previous round and that round made no decisions,
+		// since this round wouldn't make any decisions either.
+		if isRoundComplete &&
+			digests.Equal(previousRoundDigests) &&
+			l.numSkippedUnchangedRounds < l.schedulingConfig.MaxSkippedUnchangedRounds {
+			l.numSkippedUnchangedRounds++
+			ctx.Infof("inputs unchanged since the previous round; skipping scheduling round (%d consecutive)", l.numSkippedUnchangedRounds)
+			overallSchedulerResult.InputsUnchanged = true
+			overallSchedulerResult.UnchangedSchedulingContexts = l.previousRoundSchedulingContexts
+			if l.schedulingContextRepository != nil {
+				l.schedulingContextRepository.AddUnchangedRoundHeartbeat(l.clock.Now())
+			}
+			l.previousRoundDigests = previousRoundDigests
+			return overallSchedulerResult, nil
+		}
+		l.numSkippedUnchangedRounds = 0
+
+		// If only the queued jobs of some queues changed since the previous round, and that round made no decisions,
+		// only the queued jobs of those queues need be considered, since the queued jobs of other queues would still not be scheduled.
+		if unchangedQueues := digests.UnchangedQueues(previousRoundDigests); isRoundComplete &&
+			len(unchangedQueues) > 0 &&
+			l.numIncrementalRounds < l.schedulingConfig.MaxConsecutiveIncrementalRounds {
+			l.numIncrementalRounds++
+			ctx.Infof(
+				"incremental scheduling round (%d consecutive); not considering queued jobs of %d unchanged queues",
+				l.numIncrementalRounds, len(unchangedQueues),
+			)
+			fsctx.unchangedQueues = unchangedQueues
+		} else {
+			l.numIncrementalRounds = 0
+		}
+	}
+
+	// Jobs bound to a node are leased regardless of which executor groups are scheduled this round.
+	if err := txn.Upsert(fsctx.boundJobs); err != nil {
+		return nil, err
+	}
+	for _, job := range fsctx.boundJobs {
+		overallSchedulerResult.ScheduledJobs = append(overallSchedulerResult.ScheduledJobs, job)
+		overallSchedulerResult.NodeIdByJobId[job.Id()] = job.LatestRun().NodeId()
+	}
 
 	executorGroups := l.groupExecutors(fsctx.executors)
-	if len(l.executorGroupsToSchedule) == 0 {
+	executorGroupsToSchedule := &l.executorGroupsToSchedule
+	if !scope.IsEmpty() {
+		scopedExecutorGroups := make([]string, 0, len(executorGroups))
+		for label, executorGroup := range executorGroups {
+			if len(executorGroup) > 0 && (scope.Pool == "" || executorGroup[0].Pool == scope.Pool) {
+				scopedExecutorGroups = append(scopedExecutorGroups, label)
+			}
+		}
+		if len(scopedExecutorGroups) == 0 {
+			ctx.Infof("no executors in scope of scheduling round %+v", scope)
+		}
+		slices.Sort(scopedExecutorGroups)
+		executorGroupsToSchedule = &scopedExecutorGroups
+	} else if len(l.executorGroupsToSchedule) == 0 {
 		// Cycle over groups in a consistent order.
 		l.executorGroupsToSchedule = maps.Keys(executorGroups)
 		slices.Sort(l.executorGroupsToSchedule)
 	}
-	for len(l.executorGroupsToSchedule) > 0 {
+	for len(*executorGroupsToSchedule) > 0 {
 		select {
 		case <-ctx.Done():
 			// We've reached the scheduling time limit; exit gracefully.
 			ctx.Info("ending scheduling round early as we have hit the maximum scheduling duration")
+			l.alertRoundDeadlineExceeded(executorGroups)
 			return overallSchedulerResult, nil
 		default:
 		}
-		executorGroupLabel := armadaslices.Pop(&l.executorGroupsToSchedule)
-		executorGroup := executorGroups[executorGroupLabel]
-		if len(executorGroup) == 0 {
-			continue
+		executorGroupLabels := l.popExecutorGroupsToSchedule(executorGroupsToSchedule, executorGroups)
+		for _, executorGroupLabel := range executorGroupLabels {
+			for _, executor := range executorGroups[executorGroupLabel] {
+				if executor == nil {
+					return nil, errors.Errorf("nil executor in group %s", executorGroups[executorGroupLabel])
+				}
+			}
 		}
-		for _, executor := range executorGroup {
-			if executor == nil {
-				return nil, errors.Errorf("nil executor in group %s", executorGroup)
+
+		// Schedule across the executors in each group, concurrently if there are several.
+		results := l.scheduleOnExecutorGroups(ctx, fsctx, executorGroups, executorGroupLabels)
+
+		// Apply the results in the order the groups were popped in.
+		// A group whose decisions overlap with those of a group applied before it in this batch
+		// was scheduled without accounting for those decisions; it's re-scheduled next.
+		deadlineExceeded := false
+		decisions := newExecutorGroupDecisions()
+		var executorGroupLabelsToReschedule []string
+		for i, executorGroupLabel := range executorGroupLabels {
+			result := results[i]
+			if result.err == context.DeadlineExceeded {
+				// We've reached the scheduling time limit;
+				// add the executorGroupLabel back to the groups to schedule such that we try it again next time.
+				executorGroupLabelsToReschedule = append(executorGroupLabelsToReschedule, executorGroupLabel)
+				deadlineExceeded = true
+				continue
+			} else if result.err != nil {
+				return nil, result.err
+			}
+			if decisions.Overlaps(result.schedulerResult) {
+				ctx.Infof("decisions on executor group %s overlap with those of a concurrently scheduled group; re-scheduling", executorGroupLabel)
+				executorGroupLabelsToReschedule = append(executorGroupLabelsToReschedule, executorGroupLabel)
+				continue
+			}
+			decisions.Add(result.schedulerResult)
+			if result.limiters.isShare {
+				// Only take the tokens of groups whose decisions are applied from the shared limiters.
+				l.consumeRateLimiterTokens(result.sctx.Started, result.schedulerResult)
+			}
+			if err := l.applyExecutorGroupResult(ctx, fsctx, txn, executorGroups[executorGroupLabel], result, overallSchedulerResult); err != nil {
+				return nil, err
 			}
 		}
+		// Groups are popped from the end; re-schedule groups in the order they were popped in.
+		for i := len(executorGroupLabelsToReschedule) - 1; i >= 0; i-- {
+			*executorGroupsToSchedule = append(*executorGroupsToSchedule, executorGroupLabelsToReschedule[i])
+		}
+		if deadlineExceeded {
+			// Exit gracefully.
+			ctx.Info("stopped scheduling early as we have hit the maximum scheduling duration")
+			l.alertRoundDeadlineExceeded(executorGroups)
+			break
+		}
+	}
+	// Only rounds that made no decisions may be repeated without changing anything;
+	// the decisions of other rounds may not have been committed, e.g., if publishing them failed.
+	if scope.IsEmpty() &&
+		len(l.executorGroupsToSchedule) == 0 &&
+		len(overallSchedulerResult.PreemptedJobs) == 0 &&
+		len(overallSchedulerResult.ScheduledJobs) == 0 &&
+		len(overallSchedulerResult.FailedJobs) == 0 {
+		l.previousRoundDigests = digests
+		l.previousRoundSchedulingContexts = overallSchedulerResult.SchedulingContexts
+		if l.unfeasibleSchedulingKeyCache != nil && digests != nil {
+			l.unfeasibleSchedulingKeyCache.Record(digests.cluster, overallSchedulerResult.SchedulingContexts)
+		}
+	}
+	return overallSchedulerResult, nil
+}
 
-		// Schedule across the executors in this group.
+func (l *FairSchedulingAlgo) groupExecutors(executors []*schedulerobjects.Executor) map[string][]*schedulerobjects.Executor {
+	if l.schedulingConfig.UnifiedSchedulingByPool {
+		return armadaslices.GroupByFunc(
+			executors,
+			func(executor *schedulerobjects.Executor) string {
+				return executor.Pool
+			},
+		)
+	} else {
+		return armadaslices.GroupByFunc(
+			executors,
+			func(executor *schedulerobjects.Executor) string {
+				return executor.Id
+			},
+		)
+	}
+}
+
+// popExecutorGroupsToSchedule pops the next executor groups to schedule, skipping empty groups.
+// Several groups, i.e., pools, are popped if pools may be scheduled concurrently; otherwise at most one group is popped.
+func (l *FairSchedulingAlgo) popExecutorGroupsToSchedule(executorGroupsToSchedule *[]string, executorGroups map[string][]*schedulerobjects.Executor) []string {
+	n := 1
+	if l.schedulingConfig.UnifiedSchedulingByPool && l.schedulingConfig.MaxConcurrentPools > 1 {
+		n = int(l.schedulingConfig.MaxConcurrentPools)
+	}
+	rv := make([]string, 0, n)
+	for len(rv) < n && len(*executorGroupsToSchedule) > 0 {
+		executorGroupLabel := armadaslices.Pop(executorGroupsToSchedule)
+		if len(executorGroups[executorGroupLabel]) == 0 {
+			continue
+		}
+		rv = append(rv, executorGroupLabel)
+	}
+	return rv
+}
+
+// executorGroupSchedulingResult is the outcome of scheduling an executor group.
+type executorGroupSchedulingResult struct {
+	schedulerResult *SchedulerResult
+	sctx            *schedulercontext.SchedulingContext
+	// NodeDb the group was scheduled with.
+	nodeDb *nodedb.NodeDb
+	// Snapshot of the nodes of the group, taken before scheduling; nil if node snapshots aren't recorded.
+	nodeSnapshot *nodedb.Snapshot
+	// Rate-limiters the group was scheduled with.
+	limiters *executorGroupRateLimiters
+	err      error
+}
+
+// scheduleOnExecutorGroups schedules each of the provided executor groups, concurrently if there are several.
+// Groups are scheduled independently of each other, i.e., without accounting for each other's decisions,
+// and with a share each of the job scheduling rate-limiters if there are several.
+// Results are returned in the same order as executorGroupLabels.
+func (l *FairSchedulingAlgo) scheduleOnExecutorGroups(
+	ctx *armadacontext.Context,
+	fsctx *fairSchedulingAlgoContext,
+	executorGroups map[string][]*schedulerobjects.Executor,
+	executorGroupLabels []string,
+) []executorGroupSchedulingResult {
+	rv := make([]executorGroupSchedulingResult, len(executorGroupLabels))
+	now := l.clock.Now()
+	schedule := func(i int) {
+		executorGroupLabel := executorGroupLabels[i]
+		executorGroup := executorGroups[executorGroupLabel]
 		// Assume pool and minimumJobSize are consistent within the group.
 		pool := executorGroup[0].Pool
 		minimumJobSize := executorGroup[0].MinimumJobSize
@@ -146,74 +509,173 @@ func (l *FairSchedulingAlgo) Schedule(
 			"scheduling on executor group %s with capacity %s",
 			executorGroupLabel, fsctx.totalCapacityByPool[pool].CompactString(),
 		)
-		schedulerResult, sctx, err := l.scheduleOnExecutors(
+		limiters := l.sharedRateLimiters()
+		if len(executorGroupLabels) > 1 {
+			limiters = l.rateLimiterShares(i, len(executorGroupLabels), now)
+		}
+		result, err := l.scheduleOnExecutors(
 			ctx,
 			fsctx,
 			pool,
 			minimumJobSize,
 			executorGroup,
+			limiters,
 		)
-		if err == context.DeadlineExceeded {
-			// We've reached the scheduling time limit;
-			// add the executorGroupLabel back to l.executorGroupsToSchedule such that we try it again next time,
-			// and exit gracefully.
-			l.executorGroupsToSchedule = append(l.executorGroupsToSchedule, executorGroupLabel)
-			ctx.Info("stopped scheduling early as we have hit the maximum scheduling duration")
-			break
-		} else if err != nil {
-			return nil, err
-		}
-		if l.schedulingContextRepository != nil {
-			if err := l.schedulingContextRepository.AddSchedulingContext(sctx); err != nil {
-				logging.WithStacktrace(ctx, err).Error("failed to add scheduling context")
-			}
+		if err != nil {
+			rv[i] = executorGroupSchedulingResult{limiters: limiters, err: err}
+			return
 		}
+		result.limiters = limiters
+		rv[i] = *result
+	}
+	if len(executorGroupLabels) == 1 {
+		schedule(0)
+		return rv
+	}
+	var wg sync.WaitGroup
+	for i := range executorGroupLabels {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			schedule(i)
+		}(i)
+	}
+	wg.Wait()
+	return rv
+}
 
-		preemptedJobs := PreemptedJobsFromSchedulerResult[*jobdb.Job](schedulerResult)
-		scheduledJobs := ScheduledJobsFromSchedulerResult[*jobdb.Job](schedulerResult)
-		failedJobs := FailedJobsFromSchedulerResult[*jobdb.Job](schedulerResult)
-		if err := txn.Upsert(preemptedJobs); err != nil {
-			return nil, err
+// applyExecutorGroupResult applies the decisions made when scheduling an executor group to txn and fsctx,
+// such that executor groups scheduled afterwards account for them, and adds them to overallSchedulerResult.
+// Other side effects of scheduling the group, e.g., reserving nodes for gangs, are also only made here,
+// since results of groups scheduled concurrently may be discarded.
+func (l *FairSchedulingAlgo) applyExecutorGroupResult(
+	ctx *armadacontext.Context,
+	fsctx *fairSchedulingAlgoContext,
+	txn *jobdb.Txn,
+	executorGroup []*schedulerobjects.Executor,
+	result executorGroupSchedulingResult,
+	overallSchedulerResult *SchedulerResult,
+) error {
+	pool := executorGroup[0].Pool
+	schedulerResult := result.schedulerResult
+	sctx := result.sctx
+	if l.nodeSnapshots != nil && result.nodeSnapshot != nil {
+		l.nodeSnapshots.Record(sctx.ExecutorId, pool, result.nodeSnapshot)
+	}
+	if l.gangAccumulator != nil && sctx.GangAccumulationCandidate != nil {
+		if err := l.gangAccumulator.Reserve(sctx.ExecutorId, pool, sctx.GangAccumulationCandidate, result.nodeDb); err != nil {
+			return err
 		}
-		if err := txn.Upsert(scheduledJobs); err != nil {
-			return nil, err
+	}
+	if l.schedulingContextRepository != nil {
+		if err := l.schedulingContextRepository.AddSchedulingContext(sctx); err != nil {
+			logging.WithStacktrace(ctx, err).Error("failed to add scheduling context")
 		}
-		if err := txn.Upsert(failedJobs); err != nil {
-			return nil, err
+	}
+	// Queues not considered in incremental rounds, or in rounds scoped to another queue, would appear to be able to schedule.
+	if l.alertDetector != nil && fsctx.unchangedQueues == nil && fsctx.scope.Queue == "" {
+		for _, alert := range l.alertDetector.Detect(sctx) {
+			l.alerter.Alert(alert)
+		}
+	}
+	// Likewise, incremental and scoped rounds aren't comparable with full rounds.
+	if l.roundRegressionDetector != nil && fsctx.unchangedQueues == nil && fsctx.scope.IsEmpty() {
+		for _, alert := range l.roundRegressionDetector.Detect(sctx) {
+			l.alerter.Alert(alert)
+		}
+	}
+
+	preemptedJobs := PreemptedJobsFromSchedulerResult[*jobdb.Job](schedulerResult)
+	scheduledJobs := ScheduledJobsFromSchedulerResult[*jobdb.Job](schedulerResult)
+	failedJobs := FailedJobsFromSchedulerResult[*jobdb.Job](schedulerResult)
+	if l.preemptionCompensationLedger != nil {
+		if err := l.preemptionCompensationLedger.Record(pool, fsctx.totalCapacityByPool[pool], preemptedJobs); err != nil {
+			logging.WithStacktrace(ctx, err).Warnf("failed to credit queues for jobs preempted from pool %s", pool)
 		}
+	}
+	if err := txn.Upsert(preemptedJobs); err != nil {
+		return err
+	}
+	if err := txn.Upsert(scheduledJobs); err != nil {
+		return err
+	}
+	if err := txn.Upsert(failedJobs); err != nil {
+		return err
+	}
 
-		// Aggregate changes across executors.
-		overallSchedulerResult.PreemptedJobs = append(overallSchedulerResult.PreemptedJobs, schedulerResult.PreemptedJobs...)
-		overallSchedulerResult.ScheduledJobs = append(overallSchedulerResult.ScheduledJobs, schedulerResult.ScheduledJobs...)
-		overallSchedulerResult.FailedJobs = append(overallSchedulerResult.FailedJobs, schedulerResult.FailedJobs...)
-		overallSchedulerResult.SchedulingContexts = append(overallSchedulerResult.SchedulingContexts, schedulerResult.SchedulingContexts...)
-		maps.Copy(overallSchedulerResult.NodeIdByJobId, schedulerResult.NodeIdByJobId)
+	// Aggregate changes across executors.
+	overallSchedulerResult.PreemptedJobs = append(overallSchedulerResult.PreemptedJobs, schedulerResult.PreemptedJobs...)
+	overallSchedulerResult.ScheduledJobs = append(overallSchedulerResult.ScheduledJobs, schedulerResult.ScheduledJobs...)
+	overallSchedulerResult.FailedJobs = append(overallSchedulerResult.FailedJobs, schedulerResult.FailedJobs...)
+	overallSchedulerResult.SchedulingContexts = append(overallSchedulerResult.SchedulingContexts, schedulerResult.SchedulingContexts...)
+	maps.Copy(overallSchedulerResult.NodeIdByJobId, schedulerResult.NodeIdByJobId)
+	maps.Copy(overallSchedulerResult.PreemptionByJobId, schedulerResult.PreemptionByJobId)
+	maps.Copy(overallSchedulerResult.FailureReasonByJobId, schedulerResult.FailureReasonByJobId)
 
-		// Update fsctx.
-		fsctx.allocationByPoolAndQueueAndPriorityClass[pool] = sctx.AllocatedByQueueAndPriority()
+	// Update fsctx.
+	fsctx.allocationByPoolAndQueueAndPriorityClass[pool] = sctx.AllocatedByQueueAndPriority()
+	for queue, qctx := range sctx.QueueSchedulingContexts {
+		fsctx.runningJobsByQueueAndJobSet[queue] = qctx.RunningJobsByJobSet
+	}
+
+	for _, executor := range executorGroup {
+		l.onExecutorScheduled(executor)
+	}
+	return nil
+}
 
-		for _, executor := range executorGroup {
-			l.onExecutorScheduled(executor)
+// executorGroupDecisions records the decisions applied from a batch of concurrently scheduled executor groups,
+// to detect groups that couldn't have been scheduled independently of the groups applied before them.
+type executorGroupDecisions struct {
+	// Ids of the jobs preempted, scheduled, or failed.
+	jobIds map[string]bool
+	// Job sets with a limit on the number of running jobs into which jobs were scheduled, by queue.
+	limitedJobSetsByQueue map[string]map[string]bool
+}
+
+func newExecutorGroupDecisions() *executorGroupDecisions {
+	return &executorGroupDecisions{
+		jobIds:                make(map[string]bool),
+		limitedJobSetsByQueue: make(map[string]map[string]bool),
+	}
+}
+
+// Overlaps returns true if result makes decisions on jobs already decided on,
+// or schedules jobs into job sets with a limit on the number of running jobs already scheduled into,
+// since the running job limit may be exceeded when the decisions are combined.
+func (d *executorGroupDecisions) Overlaps(result *SchedulerResult) bool {
+	for _, jobs := range [][]interfaces.LegacySchedulerJob{result.PreemptedJobs, result.ScheduledJobs, result.FailedJobs} {
+		for _, job := range jobs {
+			if d.jobIds[job.GetId()] {
+				return true
+			}
 		}
 	}
-	return overallSchedulerResult, nil
+	for _, job := range result.ScheduledJobs {
+		if _, ok := job.GetAnnotations()[configuration.JobSetMaxRunningJobsAnnotation]; ok && d.limitedJobSetsByQueue[job.GetQueue()][job.GetJobSet()] {
+			return true
+		}
+	}
+	return false
 }
 
-func (l *FairSchedulingAlgo) groupExecutors(executors []*schedulerobjects.Executor) map[string][]*schedulerobjects.Executor {
-	if l.schedulingConfig.UnifiedSchedulingByPool {
-		return armadaslices.GroupByFunc(
-			executors,
-			func(executor *schedulerobjects.Executor) string {
-				return executor.Pool
-			},
-		)
-	} else {
-		return armadaslices.GroupByFunc(
-			executors,
-			func(executor *schedulerobjects.Executor) string {
-				return executor.Id
-			},
-		)
+// Add records the decisions made in result.
+func (d *executorGroupDecisions) Add(result *SchedulerResult) {
+	for _, jobs := range [][]interfaces.LegacySchedulerJob{result.PreemptedJobs, result.ScheduledJobs, result.FailedJobs} {
+		for _, job := range jobs {
+			d.jobIds[job.GetId()] = true
+		}
+	}
+	for _, job := range result.ScheduledJobs {
+		if _, ok := job.GetAnnotations()[configuration.JobSetMaxRunningJobsAnnotation]; !ok {
+			continue
+		}
+		limitedJobSets := d.limitedJobSetsByQueue[job.GetQueue()]
+		if limitedJobSets == nil {
+			limitedJobSets = make(map[string]bool)
+			d.limitedJobSetsByQueue[job.GetQueue()] = limitedJobSets
+		}
+		limitedJobSets[job.GetJobSet()] = true
 	}
 }
 
@@ -237,9 +699,42 @@ type fairSchedulingAlgoContext struct {
 	nodeIdByJobId                            map[string]string
 	jobIdsByGangId                           map[string]map[string]bool
 	gangIdByJobId                            map[string]string
+	runningJobsByQueueAndJobSet              map[string]map[string]int
+	numQueuedJobsByQueue                     map[string]int
+	numQueuedUnpausedJobsByQueue             map[string]int
+	numQueuedJobsByGangId                    map[string]int
 	allocationByPoolAndQueueAndPriorityClass map[string]map[string]schedulerobjects.QuantityByTAndResourceType[string]
 	executors                                []*schedulerobjects.Executor
 	txn                                      *jobdb.Txn
+	// Queued jobs of these queues aren't considered, since they were considered in the previous round and nothing changed since.
+	// Nil for full rounds.
+	unchangedQueues map[string]bool
+	// Digests of the inputs of this round. Nil if not computed, e.g., for scoped rounds.
+	digests *roundInputDigests
+	// Restricts the round to a subset of pools and queues, e.g., for rounds triggered by an operator.
+	scope RoundScope
+	// Queues and job sets whose queued jobs aren't considered, since an operator excluded them from scheduling.
+	exclusions schedulingExclusionSet
+	// Queued jobs bound to a node by the submitter that were leased to that node when creating this context.
+	boundJobs []*jobdb.Job
+}
+
+// gangRemainderCardinalityByGangId returns the number of queued members of each gang with running jobs,
+// i.e., the cardinality of the remainder gang of each gang scheduled at less than its full cardinality.
+func (fsctx *fairSchedulingAlgoContext) gangRemainderCardinalityByGangId() map[string]int {
+	rv := make(map[string]int)
+	for gangId, n := range fsctx.numQueuedJobsByGangId {
+		if len(fsctx.jobIdsByGangId[gangId]) > 0 {
+			rv[gangId] = n
+		}
+	}
+	return rv
+}
+
+// isQueuedGang returns true if all jobs of the gang with the provided id are queued,
+// i.e., the gang has been neither scheduled nor cancelled.
+func (fsctx *fairSchedulingAlgoContext) isQueuedGang(gangId string) bool {
+	return fsctx.numQueuedJobsByGangId[gangId] > 0 && len(fsctx.jobIdsByGangId[gangId]) == 0
 }
 
 func (l *FairSchedulingAlgo) newFairSchedulingAlgoContext(ctx *armadacontext.Context, txn *jobdb.Txn) (*fairSchedulingAlgoContext, error) {
@@ -272,10 +767,30 @@ func (l *FairSchedulingAlgo) newFairSchedulingAlgoContext(ctx *armadacontext.Con
 	nodeIdByJobId := make(map[string]string)
 	jobIdsByGangId := make(map[string]map[string]bool)
 	gangIdByJobId := make(map[string]string)
+	runningJobsByQueueAndJobSet := make(map[string]map[string]int)
+	numQueuedJobsByQueue := make(map[string]int)
+	numQueuedUnpausedJobsByQueue := make(map[string]int)
+	numQueuedJobsByGangId := make(map[string]int)
+	nodesByExecutorIdAndName := nodesByExecutorIdAndName(executors)
+	var boundJobs []*jobdb.Job
 	for _, job := range txn.GetAll() {
 		isActiveByQueueName[job.Queue()] = true
 		if job.Queued() {
-			continue
+			// Jobs bound to a node aren't scheduled; they're leased to their node as soon as it's reported,
+			// and are otherwise accounted for like any other running job.
+			boundJob, ok := bindJob(job, nodesByExecutorIdAndName)
+			if !ok {
+				numQueuedJobsByQueue[job.Queue()]++
+				if schedulingAnnotations, _ := schedulercontext.ParseSchedulingAnnotations(job.GetAnnotations()); !schedulingAnnotations.Paused {
+					numQueuedUnpausedJobsByQueue[job.Queue()]++
+				}
+				if gangId, _, _, isGangJob, err := GangIdAndCardinalityFromLegacySchedulerJob(job); err == nil && isGangJob {
+					numQueuedJobsByGangId[gangId]++
+				}
+				continue
+			}
+			job = boundJob
+			boundJobs = append(boundJobs, job)
 		}
 		run := job.LatestRun()
 		if run == nil {
@@ -294,6 +809,12 @@ func (l *FairSchedulingAlgo) newFairSchedulingAlgoContext(ctx *armadacontext.Con
 		}
 		jobsByExecutorId[executorId] = append(jobsByExecutorId[executorId], job)
 		nodeIdByJobId[job.Id()] = nodeId
+		runningJobsByJobSet := runningJobsByQueueAndJobSet[job.Queue()]
+		if runningJobsByJobSet == nil {
+			runningJobsByJobSet = make(map[string]int)
+			runningJobsByQueueAndJobSet[job.Queue()] = runningJobsByJobSet
+		}
+		runningJobsByJobSet[job.Jobset()]++
 		gangId, _, _, isGangJob, err := GangIdAndCardinalityFromLegacySchedulerJob(job)
 		if err != nil {
 			return nil, err
@@ -315,6 +836,7 @@ func (l *FairSchedulingAlgo) newFairSchedulingAlgoContext(ctx *armadacontext.Con
 	// Filter out any executor that isn't acknowledging jobs in a timely fashion
 	// Note that we do this after aggregating allocation across clusters for fair share.
 	executors = l.filterLaggingExecutors(ctx, executors, jobsByExecutorId)
+	executors = l.filterDrainingExecutors(ctx, executors)
 
 	return &fairSchedulingAlgoContext{
 		priorityFactorByQueue:                    priorityFactorByQueue,
@@ -324,12 +846,152 @@ func (l *FairSchedulingAlgo) newFairSchedulingAlgoContext(ctx *armadacontext.Con
 		nodeIdByJobId:                            nodeIdByJobId,
 		jobIdsByGangId:                           jobIdsByGangId,
 		gangIdByJobId:                            gangIdByJobId,
+		runningJobsByQueueAndJobSet:              runningJobsByQueueAndJobSet,
+		numQueuedJobsByQueue:                     numQueuedJobsByQueue,
+		numQueuedUnpausedJobsByQueue:             numQueuedUnpausedJobsByQueue,
+		numQueuedJobsByGangId:                    numQueuedJobsByGangId,
 		allocationByPoolAndQueueAndPriorityClass: totalAllocationByPoolAndQueue,
 		executors:                                executors,
 		txn:                                      txn,
+		boundJobs:                                boundJobs,
 	}, nil
 }
 
+// nodesByExecutorIdAndName indexes the nodes of executors by executor id and node name.
+func nodesByExecutorIdAndName(executors []*schedulerobjects.Executor) map[string]map[string]*schedulerobjects.Node {
+	rv := make(map[string]map[string]*schedulerobjects.Node, len(executors))
+	for _, executor := range executors {
+		nodesByName := make(map[string]*schedulerobjects.Node, len(executor.Nodes))
+		for _, node := range executor.Nodes {
+			nodesByName[node.Name] = node
+		}
+		rv[executor.Id] = nodesByName
+	}
+	return rv
+}
+
+// bindJob returns a copy of a queued job bound to a node by the submitter with a new run on that node,
+// and true, if the executor of that node is active and reports the node.
+// Otherwise, e.g., if the job isn't bound or its node is yet to be reported, the job is left queued.
+func bindJob(job *jobdb.Job, nodesByExecutorIdAndName map[string]map[string]*schedulerobjects.Node) (*jobdb.Job, bool) {
+	if !isBindOnlyJob(job) {
+		return nil, false
+	}
+	executorId := job.GetAnnotations()[configuration.BindToExecutorAnnotation]
+	node, ok := nodesByExecutorIdAndName[executorId][job.GetAnnotations()[configuration.BindToNodeAnnotation]]
+	if !ok {
+		return nil, false
+	}
+	return job.WithQueuedVersion(job.QueuedVersion()+1).WithQueued(false).WithNewRun(executorId, node.Id, node.Name), true
+}
+
+// isBindOnlyJob returns true if the job was bound to a node by the submitter, in which case it isn't scheduled.
+func isBindOnlyJob(job *jobdb.Job) bool {
+	_, ok := job.GetAnnotations()[configuration.BindToNodeAnnotation]
+	return ok
+}
+
+// queueLimiter returns the job scheduling rate-limiter of queue, creating it if it doesn't exist.
+func (l *FairSchedulingAlgo) queueLimiter(queue string) *rate.Limiter {
+	l.limiterByQueueMu.Lock()
+	defer l.limiterByQueueMu.Unlock()
+	queueLimiter, ok := l.limiterByQueue[queue]
+	if !ok {
+		// Create per-queue limiters lazily.
+		queueLimiter = rate.NewLimiter(
+			rate.Limit(l.schedulingConfig.MaximumPerQueueSchedulingRate),
+			l.schedulingConfig.MaximumPerQueueSchedulingBurst,
+		)
+		l.limiterByQueue[queue] = queueLimiter
+	}
+	return queueLimiter
+}
+
+// executorGroupRateLimiters are the job scheduling rate-limiters an executor group is scheduled with.
+type executorGroupRateLimiters struct {
+	// Global job scheduling rate-limiter.
+	limiter *rate.Limiter
+	// Returns the job scheduling rate-limiter of each queue.
+	queueLimiter func(queue string) *rate.Limiter
+	// If true, the limiters are shares of the limiters shared across rounds,
+	// from which tokens are only taken once the decisions of the group are applied.
+	isShare bool
+}
+
+// sharedRateLimiters returns the rate-limiters shared across rounds, for scheduling a single executor group at a time.
+func (l *FairSchedulingAlgo) sharedRateLimiters() *executorGroupRateLimiters {
+	return &executorGroupRateLimiters{limiter: l.limiter, queueLimiter: l.queueLimiter}
+}
+
+// rateLimiterShares returns the i-th of n shares of the rate-limiters shared across rounds,
+// such that n executor groups scheduled concurrently can't together exceed the global or per-queue scheduling rates.
+func (l *FairSchedulingAlgo) rateLimiterShares(i int, n int, now time.Time) *executorGroupRateLimiters {
+	// Only accessed by the goroutine scheduling the group.
+	limiterByQueue := make(map[string]*rate.Limiter)
+	return &executorGroupRateLimiters{
+		limiter: rateLimiterShare(l.limiter, i, n, now),
+		queueLimiter: func(queue string) *rate.Limiter {
+			queueLimiter, ok := limiterByQueue[queue]
+			if !ok {
+				queueLimiter = rateLimiterShare(l.queueLimiter(queue), i, n, now)
+				limiterByQueue[queue] = queueLimiter
+			}
+			return queueLimiter
+		},
+		isShare: true,
+	}
+}
+
+// rateLimiterShare returns the i-th of n shares of limiter, i.e., a limiter with 1/n of its rate and of its burst
+// and of the tokens available at now, rounded such that the bursts and tokens of all shares add up to those of limiter.
+func rateLimiterShare(limiter *rate.Limiter, i int, n int, now time.Time) *rate.Limiter {
+	share := func(k int) int {
+		if i < k%n {
+			return k/n + 1
+		}
+		return k / n
+	}
+	burst := share(limiter.Burst())
+	if limiter.Limit() == rate.Inf {
+		// Infinite-rate limiters never run out of tokens.
+		return rate.NewLimiter(rate.Inf, burst)
+	}
+	// Converting the tokens of limiters with very large bursts to an int may overflow.
+	available := limiter.Burst()
+	if tokens := limiter.TokensAt(now); tokens < float64(available) {
+		available = int(math.Max(tokens, 0))
+	}
+	tokens := share(available)
+	rv := rate.NewLimiter(limiter.Limit()/rate.Limit(n), burst)
+	if tokens < burst {
+		rv.ReserveN(now, burst-tokens)
+	}
+	return rv
+}
+
+// consumeRateLimiterTokens takes the tokens of the jobs scheduled in result from the global and per-queue rate-limiters.
+func (l *FairSchedulingAlgo) consumeRateLimiterTokens(now time.Time, result *SchedulerResult) {
+	numScheduledByQueue := make(map[string]int)
+	for _, job := range result.ScheduledJobs {
+		numScheduledByQueue[job.GetQueue()]++
+	}
+	reserveTokens(l.limiter, now, len(result.ScheduledJobs))
+	for queue, numScheduled := range numScheduledByQueue {
+		reserveTokens(l.queueLimiter(queue), now, numScheduled)
+	}
+}
+
+// reserveTokens takes n tokens from limiter, in chunks of at most its burst since larger reservations fail.
+func reserveTokens(limiter *rate.Limiter, now time.Time, n int) {
+	for burst := limiter.Burst(); n > 0 && burst > 0; n -= burst {
+		if n < burst {
+			limiter.ReserveN(now, n)
+		} else {
+			limiter.ReserveN(now, burst)
+		}
+	}
+}
+
 // scheduleOnExecutors schedules jobs on a specified set of executors.
 func (l *FairSchedulingAlgo) scheduleOnExecutors(
 	ctx *armadacontext.Context,
@@ -337,7 +999,8 @@ func (l *FairSchedulingAlgo) scheduleOnExecutors(
 	pool string,
 	minimumJobSize schedulerobjects.ResourceList,
 	executors []*schedulerobjects.Executor,
-) (*SchedulerResult, *schedulercontext.SchedulingContext, error) {
+	limiters *executorGroupRateLimiters,
+) (*executorGroupSchedulingResult, error) {
 	nodeDb, err := nodedb.NewNodeDb(
 		l.schedulingConfig.Preemption.PriorityClasses,
 		l.schedulingConfig.MaxExtraNodesToConsider,
@@ -346,11 +1009,23 @@ func (l *FairSchedulingAlgo) scheduleOnExecutors(
 		l.schedulingConfig.IndexedNodeLabels,
 	)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	nodeDb.SetNodeReservedResources(l.schedulingConfig.NodeReservedResources, l.schedulingConfig.NodeReservedResourceFractions)
+	nodeDb.SetExternalWorkloadCoexistence(l.schedulingConfig.EnableExternalWorkloadCoexistence)
+	nodeDb.SetMaxExtraNodesToConsiderForPreferences(l.schedulingConfig.MaxExtraNodesToConsiderForPreferences)
+	nodeDb.SetPerNodeJobLimits(l.schedulingConfig.MaxJobsPerNode, l.schedulingConfig.MaxGangMembersPerNode)
+	nodeDb.SetNumaCellsLabel(l.schedulingConfig.NumaCellsLabel)
+	if l.schedulingConfig.GangPacking.Enabled {
+		nodeDb.SetGangPackingSolver(nodedb.BranchAndBoundGangPackingSolver{}, l.schedulingConfig.GangPacking)
+	}
+	preemptionFree := l.schedulingConfig.IsPreemptionFreePool(pool)
+	if preemptionFree {
+		nodeDb.DisablePreemption()
 	}
 	for _, executor := range executors {
-		if err := l.addExecutorToNodeDb(nodeDb, fsctx.jobsByExecutorId[executor.Id], executor.Nodes); err != nil {
-			return nil, nil, err
+		if err := addExecutorToNodeDb(nodeDb, fsctx.jobsByExecutorId[executor.Id], executor.Nodes); err != nil {
+			return nil, err
 		}
 	}
 
@@ -360,6 +1035,16 @@ func (l *FairSchedulingAlgo) scheduleOnExecutors(
 	if len(executors) == 1 {
 		executorId = executors[0].Id
 	}
+	var nodeSnapshot *nodedb.Snapshot
+	if l.nodeSnapshots != nil {
+		nodeSnapshot, err = nodeDb.NewSnapshot(l.clock.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if l.gangAccumulator != nil {
+		nodeDb.SetNodeReservations(l.gangAccumulator.NodeReservations(executorId, fsctx.isQueuedGang))
+	}
 	totalResources := fsctx.totalCapacityByPool[pool]
 	var fairnessCostProvider fairness.FairnessCostProvider
 	if l.schedulingConfig.FairnessModel == configuration.DominantResourceFairness {
@@ -368,12 +1053,12 @@ func (l *FairSchedulingAlgo) scheduleOnExecutors(
 			l.schedulingConfig.DominantResourceFairnessResourcesToConsider,
 		)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 	} else {
 		fairnessCostProvider, err = fairness.NewAssetFairness(l.schedulingConfig.ResourceScarcity)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 	}
 	sctx := schedulercontext.NewSchedulingContext(
@@ -382,9 +1067,24 @@ func (l *FairSchedulingAlgo) scheduleOnExecutors(
 		l.schedulingConfig.Preemption.PriorityClasses,
 		l.schedulingConfig.Preemption.DefaultPriorityClass,
 		fairnessCostProvider,
-		l.limiter,
+		limiters.limiter,
 		totalResources,
 	)
+	sctx.MaxUnsuccessfulJobSchedulingContextsPerQueue = int(l.schedulingConfig.MaxUnsuccessfulJobSchedulingContextsPerQueue)
+	if l.capacityForecaster != nil {
+		sctx.CapacityForecast = l.capacityForecaster.SchedulingForecast(pool)
+	}
+	if l.schedulingConfig.EnableGangRemainders {
+		sctx.GangRemainderCardinalityByGangId = fsctx.gangRemainderCardinalityByGangId()
+	}
+	if l.gangAccumulator != nil {
+		sctx.GangAccumulationMinCardinality = int(l.schedulingConfig.GangAccumulation.MinGangCardinality)
+	}
+	if l.unfeasibleSchedulingKeyCache != nil && fsctx.digests != nil {
+		sctx.SetInitialUnfeasibleSchedulingKeys(
+			l.unfeasibleSchedulingKeyCache.UnfeasibleSchedulingKeys(fsctx.digests.cluster, executorId, fsctx.txn),
+		)
+	}
 	for queue, priorityFactor := range fsctx.priorityFactorByQueue {
 		if !fsctx.isActiveByQueueName[queue] {
 			// To ensure fair share is computed only from active queues, i.e., queues with jobs queued or running.
@@ -398,18 +1098,17 @@ func (l *FairSchedulingAlgo) scheduleOnExecutors(
 		if priorityFactor > 0 {
 			weight = 1 / priorityFactor
 		}
-		queueLimiter, ok := l.limiterByQueue[queue]
-		if !ok {
-			// Create per-queue limiters lazily.
-			queueLimiter = rate.NewLimiter(
-				rate.Limit(l.schedulingConfig.MaximumPerQueueSchedulingRate),
-				l.schedulingConfig.MaximumPerQueueSchedulingBurst,
-			)
-			l.limiterByQueue[queue] = queueLimiter
+		if l.preemptionCompensationLedger != nil {
+			weight *= l.preemptionCompensationLedger.WeightMultiplier(pool, queue)
 		}
-		if err := sctx.AddQueueSchedulingContext(queue, weight, allocatedByPriorityClass, queueLimiter); err != nil {
-			return nil, nil, err
+		if err := sctx.AddQueueSchedulingContext(queue, weight, allocatedByPriorityClass, limiters.queueLimiter(queue)); err != nil {
+			return nil, err
 		}
+		qctx := sctx.QueueSchedulingContexts[queue]
+		qctx.IntraQueueOrdering = l.schedulingConfig.IntraQueueOrderingForQueue(queue)
+		qctx.RunningJobsByJobSet = make(map[string]int)
+		maps.Copy(qctx.RunningJobsByJobSet, fsctx.runningJobsByQueueAndJobSet[queue])
+		qctx.HoldPausedJobs = fsctx.numQueuedUnpausedJobsByQueue[queue] > 0
 	}
 	constraints := schedulerconstraints.SchedulingConstraintsFromSchedulingConfig(
 		pool,
@@ -417,13 +1116,44 @@ func (l *FairSchedulingAlgo) scheduleOnExecutors(
 		minimumJobSize,
 		l.schedulingConfig,
 	)
+	jobRepo := NewSchedulerJobRepositoryAdapter(fsctx.txn)
+	jobRepo.executors = executors
+	jobRepo.excludedQueues = fsctx.unchangedQueues
+	jobRepo.onlyQueue = fsctx.scope.Queue
+	jobRepo.exclusions = fsctx.exclusions
+	if l.cloudBurstPolicy != nil && pool == l.cloudBurstPolicy.CloudPool() {
+		jobRepo.overflowQueues = l.cloudBurstPolicy.OverflowQueues()
+	}
+	if l.budgetTracker != nil {
+		for queue, action := range l.budgetTracker.ExhaustedActionByQueue() {
+			switch action {
+			case BudgetActionBlock:
+				if constraints.BlockedQueues == nil {
+					constraints.BlockedQueues = make(map[string]bool)
+				}
+				constraints.BlockedQueues[queue] = true
+			case BudgetActionDemote:
+				if jobRepo.priorityClassByQueue == nil {
+					jobRepo.priorityClassByQueue = make(map[string]string)
+				}
+				jobRepo.priorityClassByQueue[queue] = l.budgetTracker.ScavengerPriorityClass()
+			}
+		}
+	}
+	nodeEvictionProbability := l.schedulingConfig.Preemption.NodeEvictionProbability
+	nodeOversubscriptionEvictionProbability := l.schedulingConfig.EffectiveNodeOversubscriptionEvictionProbability()
+	if preemptionFree {
+		// Never evict jobs in preemption-free pools.
+		nodeEvictionProbability = 0
+		nodeOversubscriptionEvictionProbability = 0
+	}
 	scheduler := NewPreemptingQueueScheduler(
 		sctx,
 		constraints,
-		l.schedulingConfig.Preemption.NodeEvictionProbability,
-		l.schedulingConfig.Preemption.NodeOversubscriptionEvictionProbability,
+		nodeEvictionProbability,
+		nodeOversubscriptionEvictionProbability,
 		l.schedulingConfig.Preemption.ProtectedFractionOfFairShare,
-		NewSchedulerJobRepositoryAdapter(fsctx.txn),
+		jobRepo,
 		nodeDb,
 		fsctx.nodeIdByJobId,
 		fsctx.jobIdsByGangId,
@@ -438,16 +1168,26 @@ func (l *FairSchedulingAlgo) scheduleOnExecutors(
 	if l.schedulingConfig.EnableNewPreemptionStrategy {
 		scheduler.EnableNewPreemptionStrategy()
 	}
+	if l.schedulingConfig.EnableNodeRejectionDiagnostics {
+		scheduler.EnableNodeRejectionDiagnostics()
+	}
+	scheduler.SetMaxNodeUniformityLabelValuesToConsider(l.schedulingConfig.MaxNodeUniformityLabelValuesToConsiderForPool(pool))
+	gangPlacementScorer, err := NewGangPlacementScorer(l.schedulingConfig, pool)
+	if err != nil {
+		return nil, err
+	}
+	scheduler.SetGangPlacementScorer(gangPlacementScorer)
+	scheduler.SetGangPlacementTieBreak(l.schedulingConfig.GangPlacementTieBreak)
 	result, err := scheduler.Schedule(ctx)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	for i, job := range result.PreemptedJobs {
 		jobDbJob := job.(*jobdb.Job)
 		if run := jobDbJob.LatestRun(); run != nil {
-			jobDbJob = jobDbJob.WithUpdatedRun(run.WithFailed(true))
+			jobDbJob = jobDbJob.WithUpdatedRun(run.WithFailed(true).WithPreempted(true))
 		} else {
-			return nil, nil, errors.Errorf("attempting to preempt job %s with no associated runs", jobDbJob.Id())
+			return nil, errors.Errorf("attempting to preempt job %s with no associated runs", jobDbJob.Id())
 		}
 		result.PreemptedJobs[i] = jobDbJob.WithQueued(false).WithFailed(true)
 	}
@@ -455,10 +1195,10 @@ func (l *FairSchedulingAlgo) scheduleOnExecutors(
 		jobDbJob := job.(*jobdb.Job)
 		nodeId := result.NodeIdByJobId[jobDbJob.GetId()]
 		if nodeId == "" {
-			return nil, nil, errors.Errorf("job %s not mapped to any node", jobDbJob.GetId())
+			return nil, errors.Errorf("job %s not mapped to any node", jobDbJob.GetId())
 		}
 		if node, err := nodeDb.GetNode(nodeId); err != nil {
-			return nil, nil, err
+			return nil, err
 		} else {
 			result.ScheduledJobs[i] = jobDbJob.WithQueuedVersion(jobDbJob.QueuedVersion()+1).WithQueued(false).WithNewRun(node.Executor, node.Id, node.Name)
 		}
@@ -467,7 +1207,24 @@ func (l *FairSchedulingAlgo) scheduleOnExecutors(
 		jobDbJob := job.(*jobdb.Job)
 		result.FailedJobs[i] = jobDbJob.WithQueued(false).WithFailed(true)
 	}
-	return result, sctx, nil
+	if len(result.PreemptionByJobId) > 0 {
+		// Scheduled jobs have only now been assigned runs.
+		runIdByJobId := make(map[string]uuid.UUID, len(result.ScheduledJobs))
+		for _, job := range result.ScheduledJobs {
+			runIdByJobId[job.GetId()] = job.(*jobdb.Job).LatestRun().Id()
+		}
+		for _, preemption := range result.PreemptionByJobId {
+			if preemption.PreemptingJobId != "" {
+				preemption.PreemptingRunId = runIdByJobId[preemption.PreemptingJobId]
+			}
+		}
+	}
+	return &executorGroupSchedulingResult{
+		schedulerResult: result,
+		sctx:            sctx,
+		nodeDb:          nodeDb,
+		nodeSnapshot:    nodeSnapshot,
+	}, nil
 }
 
 // Adapter to make jobDb implement the JobRepository interface.
@@ -475,6 +1232,21 @@ func (l *FairSchedulingAlgo) scheduleOnExecutors(
 // TODO: Pass JobDb into the scheduler instead of using this shim to convert to a JobRepo.
 type SchedulerJobRepositoryAdapter struct {
 	txn *jobdb.Txn
+	// Queued jobs of these queues are returned with their priority class replaced by the one given here,
+	// e.g., to schedule jobs of queues that have exhausted their budget at a lower priority.
+	priorityClassByQueue map[string]string
+	// If non-empty, only queued jobs the clusters of all these executors can run are returned,
+	// according to the capabilities reported by each executor.
+	executors []*schedulerobjects.Executor
+	// Queued jobs of these queues aren't returned, e.g., in incremental rounds.
+	excludedQueues map[string]bool
+	// If non-empty, only queued jobs of this queue are returned, e.g., in rounds scoped to a single queue.
+	onlyQueue string
+	// Queued jobs of queues and job sets excluded from scheduling by an operator aren't returned.
+	exclusions schedulingExclusionSet
+	// If non-nil, queued jobs not targeting a pool are only returned if their queue is in this set,
+	// e.g., when scheduling onto a cloud pool only jobs overflowing from saturated on-prem pools may use.
+	overflowQueues map[string]bool
 }
 
 func NewSchedulerJobRepositoryAdapter(txn *jobdb.Txn) *SchedulerJobRepositoryAdapter {
@@ -487,8 +1259,17 @@ func NewSchedulerJobRepositoryAdapter(txn *jobdb.Txn) *SchedulerJobRepositoryAda
 // to new scheduler.
 func (repo *SchedulerJobRepositoryAdapter) GetQueueJobIds(queue string) ([]string, error) {
 	rv := make([]string, 0)
+	if repo.excludedQueues[queue] || (repo.onlyQueue != "" && queue != repo.onlyQueue) || repo.exclusions.IsQueueExcluded(queue) {
+		return rv, nil
+	}
 	it := repo.txn.QueuedJobs(queue)
 	for v, _ := it.Next(); v != nil; v, _ = it.Next() {
+		if isBindOnlyJob(v) || !executorsSupportJob(repo.executors, v.GetAnnotations()) || repo.exclusions.IsExcluded(queue, v.Jobset()) {
+			continue
+		}
+		if repo.overflowQueues != nil && !repo.overflowQueues[queue] && v.GetAnnotations()[configuration.PoolAnnotation] == "" {
+			continue
+		}
 		rv = append(rv, v.Id())
 	}
 	return rv, nil
@@ -500,6 +1281,10 @@ func (repo *SchedulerJobRepositoryAdapter) GetExistingJobsByIds(ids []string) ([
 	rv := make([]interfaces.LegacySchedulerJob, 0, len(ids))
 	for _, id := range ids {
 		if job := repo.txn.GetById(id); job != nil {
+			// Only queued jobs are demoted; running jobs are bound to nodes at their original priority.
+			if priorityClassName, ok := repo.priorityClassByQueue[job.Queue()]; ok && job.Queued() && job.GetPriorityClassName() != priorityClassName {
+				job = repo.txn.WithPriorityClass(job, priorityClassName)
+			}
 			rv = append(rv, job)
 		}
 	}
@@ -507,7 +1292,7 @@ func (repo *SchedulerJobRepositoryAdapter) GetExistingJobsByIds(ids []string) ([
 }
 
 // addExecutorToNodeDb adds all the nodes and jobs associated with a particular executor to the nodeDb.
-func (l *FairSchedulingAlgo) addExecutorToNodeDb(nodeDb *nodedb.NodeDb, jobs []*jobdb.Job, nodes []*schedulerobjects.Node) error {
+func addExecutorToNodeDb(nodeDb *nodedb.NodeDb, jobs []*jobdb.Job, nodes []*schedulerobjects.Node) error {
 	txn := nodeDb.Txn(true)
 	defer txn.Abort()
 	nodesById := armadaslices.GroupByFuncUnique(
@@ -548,11 +1333,55 @@ func (l *FairSchedulingAlgo) filterStaleExecutors(executors []*schedulerobjects.
 			activeExecutors = append(activeExecutors, executor)
 		} else {
 			logrus.Debugf("Ignoring executor %s because it hasn't heartbeated since %s", executor.Id, executor.LastUpdateTime)
+			l.alerter.Alert(Alert{
+				Type:    AlertStaleClusterSnapshot,
+				Pool:    executor.Pool,
+				Subject: executor.Id,
+				Text:    fmt.Sprintf("Executor %s hasn't sent a heartbeat since %s; its nodes are excluded from scheduling.", executor.Id, executor.LastUpdateTime),
+			})
+		}
+	}
+	return activeExecutors
+}
+
+// filterDrainingExecutors returns all executors whose clusters aren't being drained.
+func (l *FairSchedulingAlgo) filterDrainingExecutors(ctx *armadacontext.Context, executors []*schedulerobjects.Executor) []*schedulerobjects.Executor {
+	if l.clusterDrainer == nil {
+		return executors
+	}
+	activeExecutors := make([]*schedulerobjects.Executor, 0, len(executors))
+	for _, executor := range executors {
+		if l.clusterDrainer.IsDraining(executor.Id) {
+			ctx.Infof("executor %s is being drained; it will not be considered for scheduling", executor.Id)
+			continue
 		}
+		activeExecutors = append(activeExecutors, executor)
 	}
 	return activeExecutors
 }
 
+// alertRoundDeadlineExceeded raises an alert for each pool with executor groups left unscheduled
+// because the round hit the maximum scheduling duration.
+func (l *FairSchedulingAlgo) alertRoundDeadlineExceeded(executorGroups map[string][]*schedulerobjects.Executor) {
+	isAlerted := make(map[string]bool)
+	for _, executorGroupLabel := range l.executorGroupsToSchedule {
+		executorGroup := executorGroups[executorGroupLabel]
+		if len(executorGroup) == 0 {
+			continue
+		}
+		pool := executorGroup[0].Pool
+		if isAlerted[pool] {
+			continue
+		}
+		isAlerted[pool] = true
+		l.alerter.Alert(Alert{
+			Type: AlertRoundDeadlineExceeded,
+			Pool: pool,
+			Text: fmt.Sprintf("Scheduling round exceeded the maximum scheduling duration of %s before scheduling all executors in pool %s.", l.maxSchedulingDuration, pool),
+		})
+	}
+}
+
 // filterLaggingExecutors returns all executors with <= l.schedulingConfig.MaxUnacknowledgedJobsPerExecutor unacknowledged jobs,
 // where unacknowledged means the executor has not echoed the job since it was scheduled.
 //
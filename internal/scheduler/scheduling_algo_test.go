@@ -9,7 +9,9 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
+	"golang.org/x/time/rate"
 	"k8s.io/apimachinery/pkg/util/clock"
 
 	"github.com/armadaproject/armada/internal/armada/configuration"
@@ -49,6 +51,11 @@ func TestSchedule(t *testing.T) {
 
 		// Count of jobs expected to fail
 		expectedFailedJobCount int
+
+		// Action taken for queues with exhausted budgets.
+		exhaustedBudgetActionByQueue map[string]BudgetAction
+		// If non-empty, the priority class all scheduled jobs are expected to be scheduled at.
+		expectedScheduledPriorityClass string
 	}{
 		"scheduling": {
 			schedulingConfig: testfixtures.TestSchedulingConfig(),
@@ -269,6 +276,35 @@ func TestSchedule(t *testing.T) {
 			},
 			expectedScheduledIndices: []int{0},
 		},
+		"no urgency-based preemption in preemption-free pool": {
+			schedulingConfig: testfixtures.WithPreemptionFreePoolsConfig([]string{testfixtures.TestPool}, testfixtures.TestSchedulingConfig()),
+			executors:        []*schedulerobjects.Executor{testfixtures.Test1Node32CoreExecutor("executor1")},
+			queues:           []*database.Queue{{Name: "A"}, {Name: "B"}},
+			queuedJobs:       testfixtures.N16Cpu128GiJobs("B", testfixtures.PriorityClass1, 2),
+			scheduledJobsByExecutorIndexAndNodeIndex: map[int]map[int]scheduledJobs{
+				0: {
+					0: scheduledJobs{
+						jobs:         testfixtures.N16Cpu128GiJobs("B", testfixtures.PriorityClass0, 1),
+						acknowledged: true,
+					},
+				},
+			},
+			expectedScheduledIndices: []int{0},
+		},
+		"no preemption to fair share in preemption-free pool": {
+			schedulingConfig: testfixtures.WithPreemptionFreePoolsConfig([]string{testfixtures.TestPool}, testfixtures.TestSchedulingConfig()),
+			executors:        []*schedulerobjects.Executor{testfixtures.Test1Node32CoreExecutor("executor1")},
+			queues:           []*database.Queue{{Name: "A", Weight: 100}, {Name: "B", Weight: 100}},
+			queuedJobs:       testfixtures.N16Cpu128GiJobs("A", testfixtures.PriorityClass0, 2),
+			scheduledJobsByExecutorIndexAndNodeIndex: map[int]map[int]scheduledJobs{
+				0: {
+					0: scheduledJobs{
+						jobs:         testfixtures.N16Cpu128GiJobs("B", testfixtures.PriorityClass0, 2),
+						acknowledged: true,
+					},
+				},
+			},
+		},
 		"gang scheduling successful": {
 			schedulingConfig:         testfixtures.TestSchedulingConfig(),
 			executors:                []*schedulerobjects.Executor{testfixtures.Test1Node32CoreExecutor("executor1")},
@@ -338,6 +374,74 @@ func TestSchedule(t *testing.T) {
 			},
 			expectedScheduledIndices: []int{0},
 		},
+		"job set max running jobs hit during scheduling": {
+			schedulingConfig: testfixtures.TestSchedulingConfig(),
+			executors: []*schedulerobjects.Executor{
+				testfixtures.Test1Node32CoreExecutor("executor1"),
+			},
+			queues: []*database.Queue{testfixtures.TestDbQueue()},
+			queuedJobs: testfixtures.WithAnnotationsJobs(
+				map[string]string{configuration.JobSetMaxRunningJobsAnnotation: "3"},
+				testfixtures.N1Cpu4GiJobs(testfixtures.TestQueue, testfixtures.PriorityClass3, 10),
+			),
+			expectedScheduledIndices: []int{0, 1, 2},
+		},
+		"job set max running jobs accounts for running jobs": {
+			schedulingConfig: testfixtures.TestSchedulingConfig(),
+			executors: []*schedulerobjects.Executor{
+				testfixtures.Test1Node32CoreExecutor("executor1"),
+			},
+			queues: []*database.Queue{testfixtures.TestDbQueue()},
+			queuedJobs: testfixtures.WithAnnotationsJobs(
+				map[string]string{configuration.JobSetMaxRunningJobsAnnotation: "3"},
+				testfixtures.N1Cpu4GiJobs(testfixtures.TestQueue, testfixtures.PriorityClass3, 10),
+			),
+			scheduledJobsByExecutorIndexAndNodeIndex: map[int]map[int]scheduledJobs{
+				0: {
+					0: scheduledJobs{
+						jobs:         testfixtures.N1Cpu4GiJobs(testfixtures.TestQueue, testfixtures.PriorityClass3, 2),
+						acknowledged: true,
+					},
+				},
+			},
+			expectedScheduledIndices: []int{0},
+		},
+		"job set max running jobs does not affect other job sets": {
+			schedulingConfig: testfixtures.TestSchedulingConfig(),
+			executors: []*schedulerobjects.Executor{
+				testfixtures.Test1Node32CoreExecutor("executor1"),
+			},
+			queues: []*database.Queue{testfixtures.TestDbQueue()},
+			queuedJobs: armadaslices.Concatenate(
+				testfixtures.WithAnnotationsJobs(
+					map[string]string{configuration.JobSetMaxRunningJobsAnnotation: "2"},
+					testfixtures.WithJobSetJobs("A", testfixtures.N1Cpu4GiJobs(testfixtures.TestQueue, testfixtures.PriorityClass3, 5)),
+				),
+				testfixtures.WithJobSetJobs("B", testfixtures.N1Cpu4GiJobs(testfixtures.TestQueue, testfixtures.PriorityClass3, 5)),
+			),
+			expectedScheduledIndices: []int{0, 1, 5, 6, 7, 8, 9},
+		},
+		"queue with exhausted budget is blocked": {
+			schedulingConfig: testfixtures.TestSchedulingConfig(),
+			executors: []*schedulerobjects.Executor{
+				testfixtures.Test1Node32CoreExecutor("executor1"),
+			},
+			queues:                       []*database.Queue{testfixtures.TestDbQueue()},
+			queuedJobs:                   testfixtures.N16Cpu128GiJobs(testfixtures.TestQueue, testfixtures.PriorityClass3, 10),
+			exhaustedBudgetActionByQueue: map[string]BudgetAction{testfixtures.TestQueue: BudgetActionBlock},
+			expectedScheduledIndices:     []int{},
+		},
+		"queue with exhausted budget is demoted": {
+			schedulingConfig: testfixtures.TestSchedulingConfig(),
+			executors: []*schedulerobjects.Executor{
+				testfixtures.Test1Node32CoreExecutor("executor1"),
+			},
+			queues:                         []*database.Queue{testfixtures.TestDbQueue()},
+			queuedJobs:                     testfixtures.N16Cpu128GiJobs(testfixtures.TestQueue, testfixtures.PriorityClass3, 10),
+			exhaustedBudgetActionByQueue:   map[string]BudgetAction{testfixtures.TestQueue: BudgetActionDemote},
+			expectedScheduledIndices:       []int{0, 1},
+			expectedScheduledPriorityClass: testfixtures.PriorityClass0,
+		},
 		"UnifiedSchedulingByPool": {
 			schedulingConfig: testfixtures.WithUnifiedSchedulingByPoolConfig(testfixtures.TestSchedulingConfig()),
 			executors: []*schedulerobjects.Executor{
@@ -371,12 +475,22 @@ func TestSchedule(t *testing.T) {
 
 			schedulingContextRepo, err := NewSchedulingContextRepository(1024)
 			require.NoError(t, err)
+			var budgetTracker *BudgetTracker
+			if tc.exhaustedBudgetActionByQueue != nil {
+				budgetTracker = &BudgetTracker{scavengerPriorityClass: testfixtures.PriorityClass0}
+				for queue, action := range tc.exhaustedBudgetActionByQueue {
+					budgetTracker.statuses = append(budgetTracker.statuses, &BudgetStatus{Queue: queue, Action: action, Exhausted: true})
+				}
+			}
 			sch, err := NewFairSchedulingAlgo(
 				tc.schedulingConfig,
 				0,
 				mockExecutorRepo,
 				mockQueueRepo,
 				schedulingContextRepo,
+				NoOpAlerter{},
+				nil,
+				budgetTracker,
 			)
 			require.NoError(t, err)
 
@@ -461,6 +575,11 @@ func TestSchedule(t *testing.T) {
 			} else {
 				assert.Equal(t, tc.expectedScheduledIndices, actualScheduledIndices)
 			}
+			if tc.expectedScheduledPriorityClass != "" {
+				for _, job := range scheduledJobs {
+					assert.Equal(t, tc.expectedScheduledPriorityClass, job.GetPriorityClassName())
+				}
+			}
 
 			// Check that we failed the correct number of excess jobs when a gang schedules >= minimum cardinality
 			failedJobs := FailedJobsFromSchedulerResult[*jobdb.Job](schedulerResult)
@@ -509,6 +628,91 @@ func TestSchedule(t *testing.T) {
 	}
 }
 
+func TestSchedule_SkipsUnchangedRounds(t *testing.T) {
+	ctx := armadacontext.Background()
+	schedulingConfig := testfixtures.TestSchedulingConfig()
+	schedulingConfig.MaxSkippedUnchangedRounds = 1
+	executors := []*schedulerobjects.Executor{testfixtures.Test1Node32CoreExecutor("executor1")}
+
+	ctrl := gomock.NewController(t)
+	mockExecutorRepo := schedulermocks.NewMockExecutorRepository(ctrl)
+	mockExecutorRepo.EXPECT().GetExecutors(ctx).Return(executors, nil).AnyTimes()
+	mockQueueRepo := schedulermocks.NewMockQueueRepository(ctrl)
+	mockQueueRepo.EXPECT().GetAllQueues().Return([]*database.Queue{testfixtures.TestDbQueue()}, nil).AnyTimes()
+	sch, err := NewFairSchedulingAlgo(schedulingConfig, 0, mockExecutorRepo, mockQueueRepo, nil, NoOpAlerter{}, nil, nil)
+	require.NoError(t, err)
+	sch.clock = clock.NewFakeClock(testfixtures.BaseTime)
+
+	// A job that doesn't fit onto the only node, such that no round makes any decisions.
+	jobDb := testfixtures.NewJobDb()
+	txn := jobDb.WriteTxn()
+	require.NoError(t, txn.Upsert([]*jobdb.Job{testfixtures.N1GpuJobs(testfixtures.TestQueue, testfixtures.PriorityClass3, 1)[0].WithQueued(true)}))
+
+	result, err := sch.Schedule(ctx, txn)
+	require.NoError(t, err)
+	assert.False(t, result.InputsUnchanged)
+	assert.Empty(t, result.ScheduledJobs)
+
+	result, err = sch.Schedule(ctx, txn)
+	require.NoError(t, err)
+	assert.True(t, result.InputsUnchanged)
+	// Skipped rounds return the contexts of the previous round, such that they're republished as a heartbeat.
+	assert.Len(t, result.UnchangedSchedulingContexts, 1)
+
+	// A full round is run after MaxSkippedUnchangedRounds skipped rounds.
+	result, err = sch.Schedule(ctx, txn)
+	require.NoError(t, err)
+	assert.False(t, result.InputsUnchanged)
+
+	// Rounds with changed inputs are never skipped.
+	require.NoError(t, txn.Upsert([]*jobdb.Job{testfixtures.N1Cpu4GiJobs(testfixtures.TestQueue, testfixtures.PriorityClass3, 1)[0].WithQueued(true)}))
+	result, err = sch.Schedule(ctx, txn)
+	require.NoError(t, err)
+	assert.False(t, result.InputsUnchanged)
+	assert.Len(t, result.ScheduledJobs, 1)
+}
+
+func TestSchedule_BindOnlyJobs(t *testing.T) {
+	ctx := armadacontext.Background()
+	executor := testfixtures.Test1Node32CoreExecutor("executor1")
+	node := executor.Nodes[0]
+
+	ctrl := gomock.NewController(t)
+	mockExecutorRepo := schedulermocks.NewMockExecutorRepository(ctrl)
+	mockExecutorRepo.EXPECT().GetExecutors(ctx).Return([]*schedulerobjects.Executor{executor}, nil).AnyTimes()
+	mockQueueRepo := schedulermocks.NewMockQueueRepository(ctrl)
+	mockQueueRepo.EXPECT().GetAllQueues().Return([]*database.Queue{testfixtures.TestDbQueue()}, nil).AnyTimes()
+	sch, err := NewFairSchedulingAlgo(testfixtures.TestSchedulingConfig(), 0, mockExecutorRepo, mockQueueRepo, nil, NoOpAlerter{}, nil, nil)
+	require.NoError(t, err)
+	sch.clock = clock.NewFakeClock(testfixtures.BaseTime)
+
+	// A job bound to the only node is leased to it, even though it doesn't fit;
+	// a job bound to a node that's yet to be reported is left queued, and neither is scheduled.
+	bound := testfixtures.WithAnnotationsJobs(
+		map[string]string{configuration.BindToExecutorAnnotation: "executor1", configuration.BindToNodeAnnotation: node.Name},
+		testfixtures.N1GpuJobs(testfixtures.TestQueue, testfixtures.PriorityClass3, 1),
+	)[0]
+	unreported := testfixtures.WithAnnotationsJobs(
+		map[string]string{configuration.BindToExecutorAnnotation: "executor1", configuration.BindToNodeAnnotation: "unreported"},
+		testfixtures.N1Cpu4GiJobs(testfixtures.TestQueue, testfixtures.PriorityClass3, 1),
+	)[0]
+	jobDb := testfixtures.NewJobDb()
+	txn := jobDb.WriteTxn()
+	require.NoError(t, txn.Upsert([]*jobdb.Job{bound.WithQueued(true), unreported.WithQueued(true)}))
+
+	result, err := sch.Schedule(ctx, txn)
+	require.NoError(t, err)
+	require.Len(t, result.ScheduledJobs, 1)
+	assert.Equal(t, bound.Id(), result.ScheduledJobs[0].GetId())
+	assert.Equal(t, node.Id, result.NodeIdByJobId[bound.Id()])
+
+	job := txn.GetById(bound.Id())
+	assert.False(t, job.Queued())
+	assert.Equal(t, "executor1", job.LatestRun().Executor())
+	assert.Equal(t, node.Id, job.LatestRun().NodeId())
+	assert.True(t, txn.GetById(unreported.Id()).Queued())
+}
+
 func BenchmarkNodeDbConstruction(b *testing.B) {
 	for e := 1; e <= 4; e++ {
 		numNodes := int(math.Pow10(e))
@@ -524,17 +728,6 @@ func BenchmarkNodeDbConstruction(b *testing.B) {
 			schedulingConfig := testfixtures.TestSchedulingConfig()
 			b.ResetTimer()
 			for n := 0; n < b.N; n++ {
-				b.StopTimer()
-				algo, err := NewFairSchedulingAlgo(
-					schedulingConfig,
-					time.Second*5,
-					nil,
-					nil,
-					nil,
-				)
-				require.NoError(b, err)
-				b.StartTimer()
-
 				nodeDb, err := nodedb.NewNodeDb(
 					schedulingConfig.Preemption.PriorityClasses,
 					schedulingConfig.MaxExtraNodesToConsider,
@@ -543,9 +736,212 @@ func BenchmarkNodeDbConstruction(b *testing.B) {
 					schedulingConfig.IndexedNodeLabels,
 				)
 				require.NoError(b, err)
-				err = algo.addExecutorToNodeDb(nodeDb, jobs, nodes)
+				err = addExecutorToNodeDb(nodeDb, jobs, nodes)
 				require.NoError(b, err)
 			}
 		})
 	}
 }
+
+func TestSchedule_IncrementalRounds(t *testing.T) {
+	ctx := armadacontext.Background()
+	schedulingConfig := testfixtures.TestSchedulingConfig()
+	schedulingConfig.MaxConsecutiveIncrementalRounds = 1
+	executors := []*schedulerobjects.Executor{testfixtures.Test1Node32CoreExecutor("executor1")}
+	queues := []*database.Queue{{Name: "A", Weight: 100}, {Name: "B", Weight: 100}}
+
+	ctrl := gomock.NewController(t)
+	mockExecutorRepo := schedulermocks.NewMockExecutorRepository(ctrl)
+	mockExecutorRepo.EXPECT().GetExecutors(ctx).Return(executors, nil).AnyTimes()
+	mockQueueRepo := schedulermocks.NewMockQueueRepository(ctrl)
+	mockQueueRepo.EXPECT().GetAllQueues().Return(queues, nil).AnyTimes()
+	sch, err := NewFairSchedulingAlgo(schedulingConfig, 0, mockExecutorRepo, mockQueueRepo, nil, NoOpAlerter{}, nil, nil)
+	require.NoError(t, err)
+	sch.clock = clock.NewFakeClock(testfixtures.BaseTime)
+
+	// Queue A has a job that doesn't fit onto the only node.
+	jobDb := testfixtures.NewJobDb()
+	txn := jobDb.WriteTxn()
+	require.NoError(t, txn.Upsert([]*jobdb.Job{testfixtures.N1GpuJobs("A", testfixtures.PriorityClass3, 1)[0].WithQueued(true)}))
+	result, err := sch.Schedule(ctx, txn)
+	require.NoError(t, err)
+	require.Len(t, result.SchedulingContexts, 1)
+	assert.Len(t, result.SchedulingContexts[0].QueueSchedulingContexts["A"].UnsuccessfulJobSchedulingContexts, 1)
+
+	// Only the queued jobs of queue B changed, so the queued jobs of queue A aren't considered.
+	require.NoError(t, txn.Upsert([]*jobdb.Job{testfixtures.N1GpuJobs("B", testfixtures.PriorityClass3, 1)[0].WithQueued(true)}))
+	result, err = sch.Schedule(ctx, txn)
+	require.NoError(t, err)
+	require.Len(t, result.SchedulingContexts, 1)
+	assert.Empty(t, result.SchedulingContexts[0].QueueSchedulingContexts["A"].UnsuccessfulJobSchedulingContexts)
+	assert.Len(t, result.SchedulingContexts[0].QueueSchedulingContexts["B"].UnsuccessfulJobSchedulingContexts, 1)
+
+	// A full round is run after MaxConsecutiveIncrementalRounds incremental rounds.
+	require.NoError(t, txn.Upsert([]*jobdb.Job{testfixtures.N1GpuJobs("B", testfixtures.PriorityClass3, 1)[0].WithQueued(true)}))
+	result, err = sch.Schedule(ctx, txn)
+	require.NoError(t, err)
+	require.Len(t, result.SchedulingContexts, 1)
+	assert.Len(t, result.SchedulingContexts[0].QueueSchedulingContexts["A"].UnsuccessfulJobSchedulingContexts, 1)
+}
+
+func TestSchedule_ConcurrentPools(t *testing.T) {
+	// Gangs of two jobs, each of which fits onto the node of a single zone.
+	zoneGangs := func(n int) []*jobdb.Job {
+		var jobs []*jobdb.Job
+		for i := 0; i < n; i++ {
+			gang := testfixtures.WithGangAnnotationsJobs(testfixtures.N16Cpu128GiJobs(testfixtures.TestQueue, testfixtures.PriorityClass3, 2))
+			jobs = append(jobs, testfixtures.WithNodeUniformityLabelAnnotationJobs("zone", gang)...)
+		}
+		return jobs
+	}
+	tests := map[string]struct {
+		// Nodes of each executor, each of which is in a pool of its own, if not a single 32-core node.
+		nodes      func(executorId string) []*schedulerobjects.Node
+		queuedJobs []*jobdb.Job
+		// Labels by which gangs are placed onto nodes.
+		indexedNodeLabels                  []string
+		expectedNumScheduledJobsByExecutor map[string]int
+	}{
+		"jobs": {
+			queuedJobs:                         testfixtures.N16Cpu128GiJobs(testfixtures.TestQueue, testfixtures.PriorityClass3, 10),
+			expectedNumScheduledJobsByExecutor: map[string]int{"executor0": 2, "executor1": 2, "executor2": 2},
+		},
+		"gangs with a node uniformity label": {
+			nodes: func(executorId string) []*schedulerobjects.Node {
+				var nodes []*schedulerobjects.Node
+				for _, zone := range []string{"a", "b"} {
+					node := testfixtures.Test32CpuNode(testfixtures.TestPriorities)
+					node.Name = fmt.Sprintf("%s-%s-node", executorId, zone)
+					node.Executor = executorId
+					node.Labels["zone"] = zone
+					nodes = append(nodes, node)
+				}
+				return nodes
+			},
+			queuedJobs:                         zoneGangs(7),
+			indexedNodeLabels:                  []string{"zone"},
+			expectedNumScheduledJobsByExecutor: map[string]int{"executor0": 4, "executor1": 4, "executor2": 4},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			executors := make([]*schedulerobjects.Executor, 3)
+			for i := range executors {
+				executor := testfixtures.Test1Node32CoreExecutor(fmt.Sprintf("executor%d", i))
+				executor.Pool = fmt.Sprintf("pool%d", i)
+				if tc.nodes != nil {
+					executor.Nodes = tc.nodes(executor.Id)
+				}
+				executors[i] = executor
+			}
+
+			schedule := func(maxConcurrentPools uint) (*SchedulerResult, *jobdb.Txn) {
+				ctx := armadacontext.Background()
+				schedulingConfig := testfixtures.WithUnifiedSchedulingByPoolConfig(testfixtures.TestSchedulingConfig())
+				schedulingConfig = testfixtures.WithIndexedNodeLabelsConfig(tc.indexedNodeLabels, schedulingConfig)
+				schedulingConfig.MaxConcurrentPools = maxConcurrentPools
+				ctrl := gomock.NewController(t)
+				mockExecutorRepo := schedulermocks.NewMockExecutorRepository(ctrl)
+				mockExecutorRepo.EXPECT().GetExecutors(ctx).Return(executors, nil).AnyTimes()
+				mockQueueRepo := schedulermocks.NewMockQueueRepository(ctrl)
+				mockQueueRepo.EXPECT().GetAllQueues().Return([]*database.Queue{testfixtures.TestDbQueue()}, nil).AnyTimes()
+				sch, err := NewFairSchedulingAlgo(schedulingConfig, 0, mockExecutorRepo, mockQueueRepo, nil, NoOpAlerter{}, nil, nil)
+				require.NoError(t, err)
+				sch.clock = clock.NewFakeClock(testfixtures.BaseTime)
+				jobDb := testfixtures.NewJobDb()
+				txn := jobDb.WriteTxn()
+				for _, job := range tc.queuedJobs {
+					require.NoError(t, txn.Upsert([]*jobdb.Job{job.WithQueued(true)}))
+				}
+				result, err := sch.Schedule(ctx, txn)
+				require.NoError(t, err)
+				return result, txn
+			}
+			expected, _ := schedule(0)
+			actual, txn := schedule(3)
+
+			// Every pool could schedule the same queued jobs; pools scheduling jobs already scheduled by another pool are re-scheduled,
+			// such that the decisions are the same as if pools were scheduled one at a time.
+			assert.Equal(t, expected.NodeIdByJobId, actual.NodeIdByJobId)
+			assert.Len(t, actual.SchedulingContexts, 3)
+			numScheduledJobsByExecutor := make(map[string]int)
+			for _, job := range ScheduledJobsFromSchedulerResult[*jobdb.Job](actual) {
+				dbJob := txn.GetById(job.Id())
+				assert.False(t, dbJob.Queued())
+				numScheduledJobsByExecutor[dbJob.LatestRun().Executor()]++
+			}
+			assert.Equal(t, tc.expectedNumScheduledJobsByExecutor, numScheduledJobsByExecutor)
+
+			// Node selectors added while placing gangs aren't added to the jobs shared between pools.
+			for _, job := range tc.queuedJobs {
+				assert.Empty(t, txn.GetById(job.Id()).PodRequirements().NodeSelector)
+			}
+		})
+	}
+}
+
+func TestRateLimiterShare(t *testing.T) {
+	now := testfixtures.BaseTime
+	limiter := rate.NewLimiter(10, 10)
+	limiter.ReserveN(now, 3)
+
+	// Pools scheduled concurrently can't together exceed the rate, burst or available tokens of the limiter.
+	totalBurst := 0
+	totalTokens := 0.0
+	for i := 0; i < 3; i++ {
+		share := rateLimiterShare(limiter, i, 3, now)
+		assert.InDelta(t, 10.0/3, float64(share.Limit()), 1e-9)
+		totalBurst += share.Burst()
+		totalTokens += share.TokensAt(now)
+	}
+	assert.Equal(t, 10, totalBurst)
+	assert.Equal(t, 7.0, totalTokens)
+	assert.Equal(t, rate.Inf, rateLimiterShare(rate.NewLimiter(rate.Inf, math.MaxInt), 0, 3, now).Limit())
+
+	// Tokens are taken from the shared limiter once decisions are applied, including more than its burst.
+	reserveTokens(limiter, now, 27)
+	assert.InDelta(t, -20, limiter.TokensAt(now), 1e-9)
+}
+
+func TestNewFairSchedulingAlgo_PreemptionFreePools(t *testing.T) {
+	tests := map[string]struct {
+		limitsByPriorityClassAndPool map[string]map[string]map[string]float64
+		expectError                  bool
+	}{
+		"no per-pool limits": {},
+		"non-preempting priority class targets preemption-free pool": {
+			limitsByPriorityClassAndPool: map[string]map[string]map[string]float64{
+				testfixtures.PriorityClass0: {testfixtures.TestPool: {"cpu": 0.5}},
+			},
+		},
+		"preempting priority class targets preemption-free pool": {
+			limitsByPriorityClassAndPool: map[string]map[string]map[string]float64{
+				testfixtures.PriorityClass3: {testfixtures.TestPool: {"cpu": 0.5}},
+			},
+			expectError: true,
+		},
+		"preempting priority class targets other pool": {
+			limitsByPriorityClassAndPool: map[string]map[string]map[string]float64{
+				testfixtures.PriorityClass3: {"otherPool": {"cpu": 0.5}},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := testfixtures.WithPreemptionFreePoolsConfig([]string{testfixtures.TestPool}, testfixtures.TestSchedulingConfig())
+			priorityClasses := maps.Clone(config.Preemption.PriorityClasses)
+			for priorityClassName, limitsByPool := range tc.limitsByPriorityClassAndPool {
+				priorityClass := priorityClasses[priorityClassName]
+				priorityClass.MaximumResourceFractionPerQueueByPool = limitsByPool
+				priorityClasses[priorityClassName] = priorityClass
+			}
+			config.Preemption.PriorityClasses = priorityClasses
+			_, err := NewFairSchedulingAlgo(config, 0, nil, nil, nil, NoOpAlerter{}, nil, nil)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -211,6 +211,9 @@ func (s *Simulator) setupClusters() error {
 			if err != nil {
 				return err
 			}
+			nodeDb.SetNodeReservedResources(s.schedulingConfig.NodeReservedResources, s.schedulingConfig.NodeReservedResourceFractions)
+			nodeDb.SetExternalWorkloadCoexistence(s.schedulingConfig.EnableExternalWorkloadCoexistence)
+			nodeDb.SetNumaCellsLabel(s.schedulingConfig.NumaCellsLabel)
 			for executorIndex, executor := range executorGroup.Clusters {
 				executorName := fmt.Sprintf("%s-%d-%d", pool.Name, executorGroupIndex, executorIndex)
 				s.nodeDbByExecutorName[executorName] = nodeDb
@@ -451,7 +454,7 @@ func (s *Simulator) handleScheduleEvent(ctx *armadacontext.Context) error {
 				sctx,
 				constraints,
 				s.schedulingConfig.Preemption.NodeEvictionProbability,
-				s.schedulingConfig.Preemption.NodeOversubscriptionEvictionProbability,
+				s.schedulingConfig.EffectiveNodeOversubscriptionEvictionProbability(),
 				s.schedulingConfig.Preemption.ProtectedFractionOfFairShare,
 				scheduler.NewSchedulerJobRepositoryAdapter(txn),
 				nodeDb,
@@ -463,6 +466,16 @@ func (s *Simulator) handleScheduleEvent(ctx *armadacontext.Context) error {
 			if s.schedulingConfig.EnableNewPreemptionStrategy {
 				sch.EnableNewPreemptionStrategy()
 			}
+			if s.schedulingConfig.EnableNodeRejectionDiagnostics {
+				sch.EnableNodeRejectionDiagnostics()
+			}
+			sch.SetMaxNodeUniformityLabelValuesToConsider(s.schedulingConfig.MaxNodeUniformityLabelValuesToConsiderForPool(pool.Name))
+			gangPlacementScorer, err := scheduler.NewGangPlacementScorer(s.schedulingConfig, pool.Name)
+			if err != nil {
+				return err
+			}
+			sch.SetGangPlacementScorer(gangPlacementScorer)
+			sch.SetGangPlacementTieBreak(s.schedulingConfig.GangPlacementTieBreak)
 			schedulerCtx := ctx
 			if s.SuppressSchedulerLogs {
 				schedulerCtx = &armadacontext.Context{
@@ -536,7 +549,7 @@ func (s *Simulator) handleScheduleEvent(ctx *armadacontext.Context) error {
 
 			// Generate eventSequences.
 			// TODO: Add time taken to run the scheduler to s.time.
-			eventSequences, err = scheduler.AppendEventSequencesFromPreemptedJobs(eventSequences, preemptedJobs, s.time)
+			eventSequences, err = scheduler.AppendEventSequencesFromPreemptedJobs(eventSequences, preemptedJobs, result.PreemptionByJobId, s.time)
 			if err != nil {
 				return err
 			}
@@ -544,7 +557,7 @@ func (s *Simulator) handleScheduleEvent(ctx *armadacontext.Context) error {
 			if err != nil {
 				return err
 			}
-			eventSequences, err = scheduler.AppendEventSequencesFromUnschedulableJobs(eventSequences, failedJobs, s.time)
+			eventSequences, err = scheduler.AppendEventSequencesFromUnschedulableJobs(eventSequences, failedJobs, result.FailureReasonByJobId, s.time)
 			if err != nil {
 				return err
 			}
@@ -9,6 +9,7 @@ import (
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 	"golang.org/x/exp/maps"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/clock"
 
 	"github.com/armadaproject/armada/internal/armada/configuration"
@@ -42,20 +43,26 @@ type SubmitScheduleChecker interface {
 }
 
 type SubmitChecker struct {
-	executorTimeout           time.Duration
-	priorityClasses           map[string]types.PriorityClass
-	gangIdAnnotation          string
-	executorById              map[string]minimalExecutor
-	priorities                []int32
-	indexedResources          []configuration.IndexedResource
-	indexedTaints             []string
-	indexedNodeLabels         []string
-	executorRepository        database.ExecutorRepository
-	clock                     clock.Clock
-	mu                        sync.Mutex
-	schedulingKeyGenerator    *schedulerobjects.SchedulingKeyGenerator
-	jobSchedulingResultsCache *lru.Cache
-	ExecutorUpdateFrequency   time.Duration
+	executorTimeout               time.Duration
+	priorityClasses               map[string]types.PriorityClass
+	gangIdAnnotation              string
+	executorById                  map[string]minimalExecutor
+	priorities                    []int32
+	indexedResources              []configuration.IndexedResource
+	indexedTaints                 []string
+	indexedNodeLabels             []string
+	nodeReservedResources         map[string]resource.Quantity
+	nodeReservedResourceFractions map[string]float64
+	externalWorkloadCoexistence   bool
+	maxJobsPerNode                uint
+	maxGangMembersPerNode         uint
+	numaCellsLabel                string
+	executorRepository            database.ExecutorRepository
+	clock                         clock.Clock
+	mu                            sync.Mutex
+	schedulingKeyGenerator        *schedulerobjects.SchedulingKeyGenerator
+	jobSchedulingResultsCache     *lru.Cache
+	ExecutorUpdateFrequency       time.Duration
 }
 
 func NewSubmitChecker(
@@ -68,19 +75,25 @@ func NewSubmitChecker(
 		panic(errors.WithStack(err))
 	}
 	return &SubmitChecker{
-		executorTimeout:           executorTimeout,
-		priorityClasses:           schedulingConfig.Preemption.PriorityClasses,
-		gangIdAnnotation:          configuration.GangIdAnnotation,
-		executorById:              map[string]minimalExecutor{},
-		priorities:                schedulingConfig.Preemption.AllowedPriorities(),
-		indexedResources:          schedulingConfig.IndexedResources,
-		indexedTaints:             schedulingConfig.IndexedTaints,
-		indexedNodeLabels:         schedulingConfig.IndexedNodeLabels,
-		executorRepository:        executorRepository,
-		clock:                     clock.RealClock{},
-		schedulingKeyGenerator:    schedulerobjects.NewSchedulingKeyGenerator(),
-		jobSchedulingResultsCache: jobSchedulingResultsCache,
-		ExecutorUpdateFrequency:   schedulingConfig.ExecutorUpdateFrequency,
+		executorTimeout:               executorTimeout,
+		priorityClasses:               schedulingConfig.Preemption.PriorityClasses,
+		gangIdAnnotation:              configuration.GangIdAnnotation,
+		executorById:                  map[string]minimalExecutor{},
+		priorities:                    schedulingConfig.Preemption.AllowedPriorities(),
+		indexedResources:              schedulingConfig.IndexedResources,
+		indexedTaints:                 schedulingConfig.IndexedTaints,
+		indexedNodeLabels:             schedulingConfig.IndexedNodeLabels,
+		nodeReservedResources:         schedulingConfig.NodeReservedResources,
+		nodeReservedResourceFractions: schedulingConfig.NodeReservedResourceFractions,
+		externalWorkloadCoexistence:   schedulingConfig.EnableExternalWorkloadCoexistence,
+		maxJobsPerNode:                schedulingConfig.MaxJobsPerNode,
+		maxGangMembersPerNode:         schedulingConfig.MaxGangMembersPerNode,
+		numaCellsLabel:                schedulingConfig.NumaCellsLabel,
+		executorRepository:            executorRepository,
+		clock:                         clock.RealClock{},
+		schedulingKeyGenerator:        schedulerobjects.NewSchedulingKeyGenerator(),
+		jobSchedulingResultsCache:     jobSchedulingResultsCache,
+		ExecutorUpdateFrequency:       schedulingConfig.ExecutorUpdateFrequency,
 	}
 }
 
@@ -134,11 +147,11 @@ func (srv *SubmitChecker) updateExecutors(ctx *armadacontext.Context) {
 }
 
 func (srv *SubmitChecker) CheckApiJobs(jobs []*api.Job) (bool, string) {
-	return srv.check(schedulercontext.JobSchedulingContextsFromJobs(srv.priorityClasses, jobs, GangIdAndCardinalityFromAnnotations))
+	return srv.check(jobSchedulingContextsFromJobs(srv.priorityClasses, jobs))
 }
 
 func (srv *SubmitChecker) CheckJobDbJobs(jobs []*jobdb.Job) (bool, string) {
-	return srv.check(schedulercontext.JobSchedulingContextsFromJobs(srv.priorityClasses, jobs, GangIdAndCardinalityFromAnnotations))
+	return srv.check(jobSchedulingContextsFromJobs(srv.priorityClasses, jobs))
 }
 
 func (srv *SubmitChecker) check(jctxs []*schedulercontext.JobSchedulingContext) (bool, string) {
@@ -291,6 +304,10 @@ func (srv *SubmitChecker) constructNodeDb(nodes []*schedulerobjects.Node) (*node
 	if err != nil {
 		return nil, err
 	}
+	nodeDb.SetNodeReservedResources(srv.nodeReservedResources, srv.nodeReservedResourceFractions)
+	nodeDb.SetExternalWorkloadCoexistence(srv.externalWorkloadCoexistence)
+	nodeDb.SetPerNodeJobLimits(srv.maxJobsPerNode, srv.maxGangMembersPerNode)
+	nodeDb.SetNumaCellsLabel(srv.numaCellsLabel)
 	txn := nodeDb.Txn(true)
 	defer txn.Abort()
 	for _, node := range nodes {
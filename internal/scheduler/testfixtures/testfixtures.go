@@ -176,6 +176,11 @@ func WithPerPriorityLimitsConfig(limits map[string]map[string]float64, config co
 	return config
 }
 
+func WithPreemptionFreePoolsConfig(pools []string, config configuration.SchedulingConfig) configuration.SchedulingConfig {
+	config.PreemptionFreePools = pools
+	return config
+}
+
 func WithIndexedResourcesConfig(indexResources []configuration.IndexedResource, config configuration.SchedulingConfig) configuration.SchedulingConfig {
 	config.IndexedResources = indexResources
 	return config
@@ -208,6 +213,11 @@ func WithIndexedNodeLabelsConfig(indexedNodeLabels []string, config configuratio
 	return config
 }
 
+func WithMaxNodeUniformityLabelValuesToConsiderConfig(n uint, config configuration.SchedulingConfig) configuration.SchedulingConfig {
+	config.MaxNodeUniformityLabelValuesToConsider = n
+	return config
+}
+
 func WithMaxQueueLookbackConfig(maxQueueLookback uint, config configuration.SchedulingConfig) configuration.SchedulingConfig {
 	config.MaxQueueLookback = maxQueueLookback
 	return config
@@ -257,6 +267,13 @@ func WithPriorityJobs(priority uint32, jobs []*jobdb.Job) []*jobdb.Job {
 	return jobs
 }
 
+func WithJobSetJobs(jobSet string, jobs []*jobdb.Job) []*jobdb.Job {
+	for i, job := range jobs {
+		jobs[i] = job.WithJobset(jobSet)
+	}
+	return jobs
+}
+
 func WithNodeUniformityLabelAnnotationJobs(label string, jobs []*jobdb.Job) []*jobdb.Job {
 	for _, job := range jobs {
 		req := job.PodRequirements()
@@ -268,6 +285,29 @@ func WithNodeUniformityLabelAnnotationJobs(label string, jobs []*jobdb.Job) []*j
 	return jobs
 }
 
+func WithFailureDomainsAnnotationJobs(label string, minFailureDomains int, jobs []*jobdb.Job) []*jobdb.Job {
+	for _, job := range jobs {
+		req := job.PodRequirements()
+		if req.Annotations == nil {
+			req.Annotations = make(map[string]string)
+		}
+		req.Annotations[configuration.GangFailureDomainLabelAnnotation] = label
+		req.Annotations[configuration.GangMinFailureDomainsAnnotation] = fmt.Sprintf("%d", minFailureDomains)
+	}
+	return jobs
+}
+
+func WithSpreadLabelAnnotationJobs(label string, jobs []*jobdb.Job) []*jobdb.Job {
+	for _, job := range jobs {
+		req := job.PodRequirements()
+		if req.Annotations == nil {
+			req.Annotations = make(map[string]string)
+		}
+		req.Annotations[configuration.GangSpreadLabelAnnotation] = label
+	}
+	return jobs
+}
+
 func WithNodeAffinityJobs(nodeSelectorTerms []v1.NodeSelectorTerm, jobs []*jobdb.Job) []*jobdb.Job {
 	for _, job := range jobs {
 		req := job.PodRequirements()
@@ -288,6 +328,23 @@ func WithNodeAffinityJobs(nodeSelectorTerms []v1.NodeSelectorTerm, jobs []*jobdb
 	return jobs
 }
 
+func WithPreferredNodeAffinityJobs(terms []v1.PreferredSchedulingTerm, jobs []*jobdb.Job) []*jobdb.Job {
+	for _, job := range jobs {
+		req := job.PodRequirements()
+		if req.Affinity == nil {
+			req.Affinity = &v1.Affinity{}
+		}
+		if req.Affinity.NodeAffinity == nil {
+			req.Affinity.NodeAffinity = &v1.NodeAffinity{}
+		}
+		req.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			req.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			terms...,
+		)
+	}
+	return jobs
+}
+
 func WithGangAnnotationsPodReqs(reqs []*schedulerobjects.PodRequirements) []*schedulerobjects.PodRequirements {
 	gangId := uuid.NewString()
 	gangCardinality := fmt.Sprintf("%d", len(reqs))
@@ -358,6 +415,17 @@ func WithGangAnnotationsAndMinCardinalityJobs(minimumCardinality int, jobs []*jo
 	)
 }
 
+func WithGangRoleAnnotationsJobs(role string, minimumCardinality int, jobs []*jobdb.Job) []*jobdb.Job {
+	return WithAnnotationsJobs(
+		map[string]string{
+			configuration.GangRoleAnnotation:                   role,
+			configuration.GangRoleCardinalityAnnotation:        fmt.Sprintf("%d", len(jobs)),
+			configuration.GangRoleMinimumCardinalityAnnotation: fmt.Sprintf("%d", minimumCardinality),
+		},
+		jobs,
+	)
+}
+
 func WithAnnotationsJobs(annotations map[string]string, jobs []*jobdb.Job) []*jobdb.Job {
 	for _, job := range jobs {
 		for _, req := range job.JobSchedulingInfo().GetObjectRequirements() {
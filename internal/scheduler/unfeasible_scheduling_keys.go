@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+)
+
+// UnfeasibleSchedulingKeyCache carries the scheduling keys found to be unfeasible on each executor group over from one
+// scheduling round to the next, such that queued jobs with these keys are rejected immediately rather than attempted again.
+// Keys are only recorded by rounds that made no decisions, and only carried over to rounds whose inputs,
+// other than the queued jobs, are unchanged, since only then are the keys known to still be unfeasible.
+// The cache is handed over to the next leader, such that its first rounds needn't find these keys again.
+//
+// Scheduling keys are derived using a secret private to each scheduler. Hence, keys are stored by the id of a job found
+// to be unfeasible, and derived anew from the jobs of the round they're carried over to.
+type UnfeasibleSchedulingKeyCache struct {
+	mu sync.Mutex
+	// Cluster digest of the rounds the keys were found unfeasible in; see roundInputDigests.
+	clusterDigest uint64
+	// For each executor group, indexed by the executor id of its scheduling context,
+	// a job found to be unfeasible for each scheduling key found to be unfeasible.
+	jobsByExecutorId map[string][]unfeasibleJob
+}
+
+// unfeasibleJob is a job found to be unfeasible, standing in for all jobs with the same scheduling key.
+type unfeasibleJob struct {
+	JobId               string                                `json:"jobId"`
+	UnschedulableReason *schedulerobjects.UnschedulableReason `json:"unschedulableReason"`
+}
+
+func NewUnfeasibleSchedulingKeyCache() *UnfeasibleSchedulingKeyCache {
+	return &UnfeasibleSchedulingKeyCache{
+		jobsByExecutorId: make(map[string][]unfeasibleJob),
+	}
+}
+
+// Record records the scheduling keys found to be unfeasible on each executor group scheduled by a round that made no decisions,
+// the digest of the cluster inputs of which is clusterDigest. Keys recorded for other executor groups are retained
+// if the cluster digest is unchanged, e.g., for groups not scheduled by an incomplete round, and dropped otherwise.
+func (c *UnfeasibleSchedulingKeyCache) Record(clusterDigest uint64, sctxs []*schedulercontext.SchedulingContext) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if clusterDigest != c.clusterDigest {
+		c.clusterDigest = clusterDigest
+		c.jobsByExecutorId = make(map[string][]unfeasibleJob)
+	}
+	for _, sctx := range sctxs {
+		jobs := make([]unfeasibleJob, 0, len(sctx.UnfeasibleSchedulingKeys))
+		for _, jctx := range sctx.UnfeasibleSchedulingKeys {
+			if jctx.UnschedulableReason == nil {
+				continue
+			}
+			jobs = append(jobs, unfeasibleJob{JobId: jctx.JobId, UnschedulableReason: jctx.UnschedulableReason})
+		}
+		slices.SortFunc(jobs, func(a, b unfeasibleJob) bool {
+			return a.JobId < b.JobId
+		})
+		c.jobsByExecutorId[sctx.ExecutorId] = jobs
+	}
+}
+
+// UnfeasibleSchedulingKeys returns the scheduling keys recorded for the executor group with the provided executor id,
+// if they were found to be unfeasible by rounds whose cluster digest is clusterDigest, and nil otherwise.
+// Keys are derived from the jobs of txn; keys recorded for jobs no longer in txn are dropped.
+func (c *UnfeasibleSchedulingKeyCache) UnfeasibleSchedulingKeys(
+	clusterDigest uint64,
+	executorId string,
+	txn *jobdb.Txn,
+) map[schedulerobjects.SchedulingKey]*schedulercontext.JobSchedulingContext {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if clusterDigest != c.clusterDigest || len(c.jobsByExecutorId[executorId]) == 0 {
+		return nil
+	}
+	now := time.Now()
+	rv := make(map[schedulerobjects.SchedulingKey]*schedulercontext.JobSchedulingContext, len(c.jobsByExecutorId[executorId]))
+	for _, recorded := range c.jobsByExecutorId[executorId] {
+		job := txn.GetById(recorded.JobId)
+		if job == nil {
+			continue
+		}
+		schedulingKey, _ := job.GetSchedulingKey()
+		if schedulingKey == schedulerobjects.EmptySchedulingKey {
+			continue
+		}
+		if _, ok := rv[schedulingKey]; !ok {
+			rv[schedulingKey] = &schedulercontext.JobSchedulingContext{
+				Created:             now,
+				JobId:               job.Id(),
+				Job:                 job,
+				UnschedulableReason: recorded.UnschedulableReason,
+				GangMinCardinality:  1,
+			}
+		}
+	}
+	return rv
+}
+
+// unfeasibleSchedulingKeyCacheState is the state of an UnfeasibleSchedulingKeyCache handed over between leaders.
+type unfeasibleSchedulingKeyCacheState struct {
+	ClusterDigest    uint64                     `json:"clusterDigest"`
+	JobsByExecutorId map[string][]unfeasibleJob `json:"jobsByExecutorId"`
+}
+
+// MarshalState serialises the cached scheduling keys, such that they're handed over to the next leader.
+func (c *UnfeasibleSchedulingKeyCache) MarshalState() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	bytes, err := json.Marshal(unfeasibleSchedulingKeyCacheState{
+		ClusterDigest:    c.clusterDigest,
+		JobsByExecutorId: c.jobsByExecutorId,
+	})
+	return bytes, errors.WithStack(err)
+}
+
+// UnmarshalState replaces the cached scheduling keys with those handed over by the previous leader.
+// The keys are carried over to the first rounds of this leader if their cluster inputs are unchanged.
+func (c *UnfeasibleSchedulingKeyCache) UnmarshalState(state []byte) error {
+	var cacheState unfeasibleSchedulingKeyCacheState
+	if err := json.Unmarshal(state, &cacheState); err != nil {
+		return errors.WithStack(err)
+	}
+	if cacheState.JobsByExecutorId == nil {
+		cacheState.JobsByExecutorId = make(map[string][]unfeasibleJob)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clusterDigest = cacheState.ClusterDigest
+	c.jobsByExecutorId = cacheState.JobsByExecutorId
+	return nil
+}
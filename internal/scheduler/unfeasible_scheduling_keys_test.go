@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	schedulercontext "github.com/armadaproject/armada/internal/scheduler/context"
+	"github.com/armadaproject/armada/internal/scheduler/jobdb"
+	"github.com/armadaproject/armada/internal/scheduler/schedulerobjects"
+	"github.com/armadaproject/armada/internal/scheduler/testfixtures"
+)
+
+func TestUnfeasibleSchedulingKeyCache(t *testing.T) {
+	queuedJob := testfixtures.Test1Cpu4GiJob("A", testfixtures.PriorityClass0)
+	removedJob := testfixtures.Test32Cpu256GiJob("A", testfixtures.PriorityClass0)
+	jobDb := testfixtures.NewJobDb()
+	txn := jobDb.WriteTxn()
+	require.NoError(t, txn.Upsert([]*jobdb.Job{queuedJob}))
+	txn.Commit()
+
+	sctx := schedulercontext.NewSchedulingContext(
+		"executor",
+		"pool",
+		testfixtures.TestPriorityClasses,
+		testfixtures.TestDefaultPriorityClass,
+		nil,
+		nil,
+		schedulerobjects.ResourceList{},
+	)
+	reason := schedulerobjects.NewUnschedulableReason(schedulerobjects.UnschedulableReasonCodeUnknown, "job does not fit on any node")
+	for _, job := range []*jobdb.Job{queuedJob, removedJob} {
+		schedulingKey, _ := job.GetSchedulingKey()
+		sctx.UnfeasibleSchedulingKeys[schedulingKey] = &schedulercontext.JobSchedulingContext{
+			JobId:               job.Id(),
+			Job:                 job,
+			UnschedulableReason: reason,
+		}
+	}
+	cache := NewUnfeasibleSchedulingKeyCache()
+	cache.Record(1, []*schedulercontext.SchedulingContext{sctx})
+	state, err := cache.MarshalState()
+	require.NoError(t, err)
+
+	// Keys handed over to the next leader are carried over to rounds with the same cluster digest.
+	nextCache := NewUnfeasibleSchedulingKeyCache()
+	require.NoError(t, nextCache.UnmarshalState(state))
+	keys := nextCache.UnfeasibleSchedulingKeys(1, "executor", jobDb.ReadTxn())
+	schedulingKey, _ := queuedJob.GetSchedulingKey()
+	// Keys of jobs no longer in the jobDb can't be derived.
+	require.Len(t, keys, 1)
+	require.Contains(t, keys, schedulingKey)
+	assert.Equal(t, queuedJob.Id(), keys[schedulingKey].JobId)
+	assert.Equal(t, reason, keys[schedulingKey].UnschedulableReason)
+
+	// Keys aren't carried over to other executor groups, or to rounds whose cluster inputs changed.
+	assert.Empty(t, nextCache.UnfeasibleSchedulingKeys(1, "otherExecutor", jobDb.ReadTxn()))
+	assert.Empty(t, nextCache.UnfeasibleSchedulingKeys(2, "executor", jobDb.ReadTxn()))
+
+	// Keys carried over remain known to be unfeasible once the keys found during the round are cleared.
+	nextSctx := schedulercontext.NewSchedulingContext(
+		"executor",
+		"pool",
+		testfixtures.TestPriorityClasses,
+		testfixtures.TestDefaultPriorityClass,
+		nil,
+		nil,
+		schedulerobjects.ResourceList{},
+	)
+	nextSctx.SetInitialUnfeasibleSchedulingKeys(keys)
+	nextSctx.UnfeasibleSchedulingKeys[schedulerobjects.SchedulingKey{1}] = &schedulercontext.JobSchedulingContext{}
+	nextSctx.ClearUnfeasibleSchedulingKeys()
+	assert.Equal(t, keys, nextSctx.UnfeasibleSchedulingKeys)
+
+	// Recording keys for a different cluster digest drops the keys recorded for the previous one.
+	cache.Record(2, nil)
+	assert.Empty(t, cache.UnfeasibleSchedulingKeys(1, "executor", jobDb.ReadTxn()))
+
+	assert.Error(t, nextCache.UnmarshalState([]byte("not json")))
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/armadaproject/armada/internal/armada/configuration"
+	"github.com/armadaproject/armada/internal/common/compress"
+	"github.com/armadaproject/armada/internal/common/config"
 	"github.com/armadaproject/armada/internal/common/types"
 )
 
@@ -14,6 +16,11 @@ type Configuration struct {
 	Metrics configuration.MetricsConfig
 	// General Pulsar configuration
 	Pulsar configuration.PulsarConfig
+	// Envelope encryption of job specs stored in Postgres; must use the same keys as the scheduler.
+	JobSpecEncryption config.EncryptionConfig
+	// Codec used to compress job specs when inserting in the database; one of zlib, zstd, lz4, or snappy.
+	// Readers detect the codec of each job spec, so this may be changed without migrating job specs already stored.
+	JobSpecCompressionCodec compress.Codec
 	// Map of allowed priority classes by name
 	PriorityClasses map[string]types.PriorityClass
 	// Pulsar subscription name
@@ -22,6 +29,9 @@ type Configuration struct {
 	BatchSize int
 	// Maximum time since the last batch before a batch will be inserted into the database
 	BatchDuration time.Duration
+	// If positive, up to this many messages are read ahead when backlogged, such that
+	// cancellations and preemptions are processed ahead of submissions of other job sets.
+	PriorityLaneBufferSize int
 	// Time for which the pulsar consumer will wait for a new message before retrying
 	PulsarReceiveTimeout time.Duration
 	// Time for which the pulsar consumer will back off after receiving an error on trying to receive a message
@@ -203,6 +203,15 @@ func TestDbOperationOptimisation(t *testing.T) {
 			MarkRunsRunning{runIds[1]: true},                          // 3
 			InsertJobs{jobIds[2]: &schedulerdb.Job{JobID: jobIds[2]}}, // 3
 		}},
+		"MarkRunsPreempted": {N: 3, Ops: []DbOperation{
+			InsertJobs{jobIds[0]: &schedulerdb.Job{JobID: jobIds[0]}},                                                                // 1
+			InsertRuns{runIds[0]: &JobRunDetails{queue: testQueueName, dbRun: &schedulerdb.Run{JobID: jobIds[0], RunID: runIds[0]}}}, // 2
+			MarkRunsPreempted{runIds[0]: true},                        // 3
+			InsertJobs{jobIds[1]: &schedulerdb.Job{JobID: jobIds[1]}}, // 3
+			InsertRuns{runIds[1]: &JobRunDetails{queue: testQueueName, dbRun: &schedulerdb.Run{JobID: jobIds[0], RunID: runIds[1]}}}, // 3
+			MarkRunsPreempted{runIds[1]: true},                        // 3
+			InsertJobs{jobIds[2]: &schedulerdb.Job{JobID: jobIds[2]}}, // 3
+		}},
 		"InsertPartitionMarker": {N: 2, Ops: []DbOperation{
 			InsertJobs{jobIds[0]: &schedulerdb.Job{JobID: jobIds[0]}}, // 1
 			&InsertPartitionMarker{markers: []*schedulerdb.Marker{}},  // 2
@@ -438,6 +447,14 @@ func (db *mockDb) apply(op DbOperation) error {
 				return errors.Errorf("run %s not in db", runId)
 			}
 		}
+	case MarkRunsPreempted:
+		for runId := range o {
+			if run, ok := db.Runs[runId]; ok {
+				run.Preempted = true
+			} else {
+				return errors.Errorf("run %s not in db", runId)
+			}
+		}
 	}
 	return nil
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/armadaproject/armada/internal/common/armadacontext"
 	"github.com/armadaproject/armada/internal/common/compress"
 	"github.com/armadaproject/armada/internal/common/database"
+	"github.com/armadaproject/armada/internal/common/encryption"
 	"github.com/armadaproject/armada/internal/common/ingest"
 	"github.com/armadaproject/armada/internal/common/ingest/metrics"
 	"github.com/armadaproject/armada/internal/common/logging"
@@ -31,11 +32,15 @@ func Run(config Configuration) {
 	}
 	schedulerDb := NewSchedulerDb(db, svcMetrics, 100*time.Millisecond, 60*time.Second, 5*time.Second)
 
-	compressor, err := compress.NewZlibCompressor(1024)
+	compressor, err := compress.NewCompressor(config.JobSpecCompressionCodec, 1024)
 	if err != nil {
 		panic(errors.WithMessage(err, "Error creating  compressor"))
 	}
-	converter := NewInstructionConverter(svcMetrics, config.PriorityClasses, compressor)
+	envelope, err := encryption.NewEnvelopeFromConfig(config.JobSpecEncryption)
+	if err != nil {
+		panic(errors.WithMessage(err, "Error creating job spec encryption envelope"))
+	}
+	converter := NewInstructionConverter(svcMetrics, config.PriorityClasses, encryption.NewEncryptingCompressor(compressor, envelope))
 
 	// Expose profiling endpoints if enabled.
 	pprofServer := profiling.SetupPprofHttpServer(config.PprofPort)
@@ -58,6 +63,7 @@ func Run(config Configuration) {
 		config.Metrics,
 		svcMetrics,
 	)
+	ingester.EnablePriorityLanes(config.PriorityLaneBufferSize)
 	if err := ingester.Run(app.CreateContextWithShutdown()); err != nil {
 		panic(errors.WithMessage(err, "Error running ingestion pipeline"))
 	}
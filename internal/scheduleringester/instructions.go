@@ -8,7 +8,10 @@ import (
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/version"
 
+	"github.com/armadaproject/armada/internal/armada/configuration"
 	"github.com/armadaproject/armada/internal/common/armadacontext"
 	"github.com/armadaproject/armada/internal/common/compress"
 	"github.com/armadaproject/armada/internal/common/ingest"
@@ -32,6 +35,8 @@ type InstructionConverter struct {
 	metrics         *metrics.Metrics
 	priorityClasses map[string]types.PriorityClass
 	compressor      compress.Compressor
+	// Used to record when jobs are ingested.
+	clock clock.Clock
 }
 
 func NewInstructionConverter(
@@ -43,6 +48,7 @@ func NewInstructionConverter(
 		metrics:         metrics,
 		priorityClasses: priorityClasses,
 		compressor:      compressor,
+		clock:           clock.RealClock{},
 	}
 }
 
@@ -103,11 +109,12 @@ func (c *InstructionConverter) dbOperationsFromEventSequence(es *armadaevents.Ev
 			operationsFromEvent, err = c.handleJobRequeued(event.GetJobRequeued())
 		case *armadaevents.EventSequence_Event_PartitionMarker:
 			operationsFromEvent, err = c.handlePartitionMarker(event.GetPartitionMarker(), *event.Created)
+		case *armadaevents.EventSequence_Event_JobRunPreempted:
+			operationsFromEvent, err = c.handleJobRunPreempted(event.GetJobRunPreempted())
 		case *armadaevents.EventSequence_Event_ReprioritisedJob,
 			*armadaevents.EventSequence_Event_JobDuplicateDetected,
 			*armadaevents.EventSequence_Event_ResourceUtilisation,
 			*armadaevents.EventSequence_Event_StandaloneIngressInfo,
-			*armadaevents.EventSequence_Event_JobRunPreempted,
 			*armadaevents.EventSequence_Event_JobRunAssigned:
 			// These events can all be safely ignored
 			log.Debugf("Ignoring event type %T", event)
@@ -160,6 +167,7 @@ func (c *InstructionConverter) handleSubmitJob(job *armadaevents.SubmitJob, subm
 	if err != nil {
 		return nil, err
 	}
+	ingested := c.clock.Now().UTC()
 
 	return []DbOperation{InsertJobs{jobId: &schedulerdb.Job{
 		JobID:                 jobId,
@@ -174,6 +182,7 @@ func (c *InstructionConverter) handleSubmitJob(job *armadaevents.SubmitJob, subm
 		SubmitMessage:         compressedSubmitJobBytes,
 		SchedulingInfo:        schedulingInfoBytes,
 		SchedulingInfoVersion: int32(schedulingInfo.Version),
+		Ingested:              &ingested,
 	}}}, nil
 }
 
@@ -232,6 +241,11 @@ func (c *InstructionConverter) handleJobRunSucceeded(jobRunSucceeded *armadaeven
 	return []DbOperation{MarkRunsSucceeded{runId: true}}, nil
 }
 
+func (c *InstructionConverter) handleJobRunPreempted(jobRunPreempted *armadaevents.JobRunPreempted) ([]DbOperation, error) {
+	runId := armadaevents.UuidFromProtoUuid(jobRunPreempted.GetPreemptedRunId())
+	return []DbOperation{MarkRunsPreempted{runId: true}}, nil
+}
+
 func (c *InstructionConverter) handleJobRunErrors(jobRunErrors *armadaevents.JobRunErrors) ([]DbOperation, error) {
 	runId := armadaevents.UuidFromProtoUuid(jobRunErrors.GetRunId())
 	jobId, err := armadaevents.UlidStringFromProtoUuid(jobRunErrors.JobId)
@@ -389,6 +403,20 @@ func SchedulingInfoFromSubmitJob(submitJob *armadaevents.SubmitJob, submitTime t
 			}
 			maps.Copy(podRequirements.Annotations, submitJob.MainObject.ObjectMeta.Annotations)
 		}
+		if podSpec.RuntimeClassName != nil && *podSpec.RuntimeClassName != "" {
+			if podRequirements.Annotations == nil {
+				podRequirements.Annotations = make(map[string]string, 1)
+			}
+			podRequirements.Annotations[configuration.RuntimeClassNameAnnotation] = *podSpec.RuntimeClassName
+		}
+		if minVersion := adapters.MinKubernetesVersionForPodSpec(podSpec); minVersion != "" {
+			if podRequirements.Annotations == nil {
+				podRequirements.Annotations = make(map[string]string, 1)
+			}
+			podRequirements.Annotations[configuration.MinKubernetesVersionAnnotation] = maxKubernetesVersion(
+				podRequirements.Annotations[configuration.MinKubernetesVersionAnnotation], minVersion,
+			)
+		}
 		schedulingInfo.ObjectRequirements = append(
 			schedulingInfo.ObjectRequirements,
 			&schedulerobjects.ObjectRequirements{
@@ -402,3 +430,19 @@ func SchedulingInfoFromSubmitJob(submitJob *armadaevents.SubmitJob, submitTime t
 	}
 	return schedulingInfo, nil
 }
+
+// maxKubernetesVersion returns the more recent of the two Kubernetes versions, where existing may be empty.
+// Existing is kept if it can't be parsed, such that invalid user-provided versions are reported when scheduling.
+func maxKubernetesVersion(existing string, required string) string {
+	if existing == "" {
+		return required
+	}
+	existingVersion, err := version.ParseGeneric(existing)
+	if err != nil {
+		return existing
+	}
+	if existingVersion.AtLeast(version.MustParseGeneric(required)) {
+		return existing
+	}
+	return required
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/clock"
 
 	"github.com/armadaproject/armada/internal/common/compress"
 	"github.com/armadaproject/armada/internal/common/ingest/metrics"
@@ -43,6 +44,7 @@ func TestConvertSequence(t *testing.T) {
 				Submitted:      f.BaseTime.UnixNano(),
 				SubmitMessage:  protoutil.MustMarshallAndCompress(f.Submit.GetSubmitJob(), compressor),
 				SchedulingInfo: protoutil.MustMarshall(getExpectedSubmitMessageSchedulingInfo(t)),
+				Ingested:       &f.BaseTime,
 			}}},
 		},
 		"ignores duplicate submit": {
@@ -69,6 +71,10 @@ func TestConvertSequence(t *testing.T) {
 			events:   []*armadaevents.EventSequence_Event{f.Running},
 			expected: []DbOperation{MarkRunsRunning{f.RunIdUuid: true}},
 		},
+		"job run preempted": {
+			events:   []*armadaevents.EventSequence_Event{f.JobPreempted},
+			expected: []DbOperation{MarkRunsPreempted{f.RunIdUuid: true}},
+		},
 		"job run succeeded": {
 			events:   []*armadaevents.EventSequence_Event{f.JobRunSucceeded},
 			expected: []DbOperation{MarkRunsSucceeded{f.RunIdUuid: true}},
@@ -189,7 +195,7 @@ func TestConvertSequence(t *testing.T) {
 			},
 		},
 		"ignored events": {
-			events: []*armadaevents.EventSequence_Event{f.Running, f.JobPreempted, f.JobSucceeded},
+			events: []*armadaevents.EventSequence_Event{f.Running, f.JobReprioritised, f.JobSucceeded},
 			expected: []DbOperation{
 				MarkRunsRunning{f.RunIdUuid: true},
 				MarkJobsSucceeded{f.JobIdString: true},
@@ -199,7 +205,7 @@ func TestConvertSequence(t *testing.T) {
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			converter := InstructionConverter{m, f.PriorityClasses, compressor}
+			converter := InstructionConverter{m, f.PriorityClasses, compressor, clock.NewFakeClock(f.BaseTime)}
 			es := f.NewEventSequence(tc.events...)
 			results := converter.dbOperationsFromEventSequence(es)
 			assertOperationsEqual(t, tc.expected, results)
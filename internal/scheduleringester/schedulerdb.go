@@ -248,6 +248,12 @@ func (s *SchedulerDb) WriteDbOp(ctx *armadacontext.Context, tx pgx.Tx, op DbOper
 		if err != nil {
 			return errors.WithStack(err)
 		}
+	case MarkRunsPreempted:
+		runIds := maps.Keys(o)
+		err := queries.MarkJobRunsPreemptedById(ctx, runIds)
+		if err != nil {
+			return errors.WithStack(err)
+		}
 	case InsertJobRunErrors:
 		records := make([]any, len(o))
 		i := 0
@@ -234,6 +234,19 @@ func TestWriteOps(t *testing.T) {
 				runIds[2]: &JobRunFailed{LeaseReturned: false},
 			},
 		}},
+		"MarkRunsPreempted": {Ops: []DbOperation{
+			InsertJobs{
+				jobIds[0]: &schedulerdb.Job{JobID: jobIds[0], JobSet: "set1"},
+				jobIds[1]: &schedulerdb.Job{JobID: jobIds[1], JobSet: "set2"},
+			},
+			InsertRuns{
+				runIds[0]: &JobRunDetails{queue: testQueueName, dbRun: &schedulerdb.Run{JobID: jobIds[0], RunID: runIds[0]}},
+				runIds[1]: &JobRunDetails{queue: testQueueName, dbRun: &schedulerdb.Run{JobID: jobIds[1], RunID: runIds[1]}},
+			},
+			MarkRunsPreempted{
+				runIds[0]: true,
+			},
+		}},
 		"MarkRunsRunning": {Ops: []DbOperation{
 			InsertJobs{
 				jobIds[0]: &schedulerdb.Job{JobID: jobIds[0], JobSet: "set1"},
@@ -307,6 +320,7 @@ func addDefaultValues(op DbOperation) DbOperation {
 	case MarkRunsSucceeded:
 	case MarkRunsFailed:
 	case MarkRunsRunning:
+	case MarkRunsPreempted:
 	}
 	return op
 }
@@ -597,6 +611,27 @@ func assertOpSuccess(t *testing.T, schedulerDb *SchedulerDb, serials map[string]
 			}
 		}
 		assert.Equal(t, len(expected), len(runs))
+	case MarkRunsPreempted:
+		jobs, err := selectNewJobs(ctx, 0)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		jobIds := make([]string, 0)
+		for _, job := range jobs {
+			jobIds = append(jobIds, job.JobID)
+		}
+
+		runs, err := queries.SelectNewRunsForJobs(ctx, schedulerdb.SelectNewRunsForJobsParams{
+			Serial: serials["runs"],
+			JobIds: jobIds,
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		for _, run := range runs {
+			_, ok := expected[run.RunID]
+			assert.Equal(t, ok, run.Preempted)
+		}
 	case InsertJobRunErrors:
 		expectedIds := maps.Keys(expected)
 		as, err := queries.SelectRunErrorsById(ctx, expectedIds)
@@ -1093,6 +1093,10 @@ func SwaggerJsonTemplate() string {
 		"    \"apiJobPreemptedEvent\": {\n" +
 		"      \"type\": \"object\",\n" +
 		"      \"properties\": {\n" +
+		"        \"cause\": {\n" +
+		"          \"description\": \"Why the job was preempted; one of \\\"FairShare\\\", if preempted to balance resource usage across queues,\\nor \\\"NodeOversubscribed\\\", if preempted since its node was oversubscribed, e.g., by jobs of higher priority.\",\n" +
+		"          \"type\": \"string\"\n" +
+		"        },\n" +
 		"        \"clusterId\": {\n" +
 		"          \"type\": \"string\"\n" +
 		"        },\n" +
@@ -1100,21 +1104,42 @@ func SwaggerJsonTemplate() string {
 		"          \"type\": \"string\",\n" +
 		"          \"format\": \"date-time\"\n" +
 		"        },\n" +
+		"        \"expectedRequeuePosition\": {\n" +
+		"          \"description\": \"Position of the queue of the preempted job in the order in which queues are considered for scheduling\\nas of the round in which the job was preempted, starting at 1.\",\n" +
+		"          \"type\": \"integer\",\n" +
+		"          \"format\": \"int32\"\n" +
+		"        },\n" +
 		"        \"jobId\": {\n" +
 		"          \"type\": \"string\"\n" +
 		"        },\n" +
 		"        \"jobSetId\": {\n" +
 		"          \"type\": \"string\"\n" +
 		"        },\n" +
+		"        \"preemptiveGangId\": {\n" +
+		"          \"description\": \"Gang id of the job that caused the preemption, if it was part of a gang.\",\n" +
+		"          \"type\": \"string\"\n" +
+		"        },\n" +
 		"        \"preemptiveJobId\": {\n" +
 		"          \"type\": \"string\"\n" +
 		"        },\n" +
+		"        \"preemptivePriorityClass\": {\n" +
+		"          \"description\": \"Priority class of the job that caused the preemption.\",\n" +
+		"          \"type\": \"string\"\n" +
+		"        },\n" +
+		"        \"preemptiveQueue\": {\n" +
+		"          \"description\": \"Queue of the job that caused the preemption.\",\n" +
+		"          \"type\": \"string\"\n" +
+		"        },\n" +
 		"        \"preemptiveRunId\": {\n" +
 		"          \"type\": \"string\"\n" +
 		"        },\n" +
 		"        \"queue\": {\n" +
 		"          \"type\": \"string\"\n" +
 		"        },\n" +
+		"        \"queueCompensated\": {\n" +
+		"          \"description\": \"True if the queue of the preempted job is below its fair share once the preemption is accounted for,\\nin which case fair share accounting prioritises scheduling jobs of the queue in subsequent rounds.\",\n" +
+		"          \"type\": \"boolean\"\n" +
+		"        },\n" +
 		"        \"runId\": {\n" +
 		"          \"type\": \"string\"\n" +
 		"        }\n" +
@@ -1492,6 +1517,10 @@ func SwaggerJsonTemplate() string {
 		"        \"nodeName\": {\n" +
 		"          \"type\": \"string\"\n" +
 		"        },\n" +
+		"        \"outputUrl\": {\n" +
+		"          \"description\": \"Location of the job's captured output, if output capture was requested for the job.\",\n" +
+		"          \"type\": \"string\"\n" +
+		"        },\n" +
 		"        \"podName\": {\n" +
 		"          \"type\": \"string\"\n" +
 		"        },\n" +
@@ -1160,6 +1160,21 @@ type JobPreemptedEvent struct {
 	RunId           string    `protobuf:"bytes,6,opt,name=run_id,json=runId,proto3" json:"runId,omitempty"`
 	PreemptiveJobId string    `protobuf:"bytes,7,opt,name=preemptive_job_id,json=preemptiveJobId,proto3" json:"preemptiveJobId,omitempty"`
 	PreemptiveRunId string    `protobuf:"bytes,8,opt,name=preemptive_run_id,json=preemptiveRunId,proto3" json:"preemptiveRunId,omitempty"`
+	// Why the job was preempted; one of "FairShare", if preempted to balance resource usage across queues,
+	// or "NodeOversubscribed", if preempted since its node was oversubscribed, e.g., by jobs of higher priority.
+	Cause string `protobuf:"bytes,9,opt,name=cause,proto3" json:"cause,omitempty"`
+	// Queue of the job that caused the preemption.
+	PreemptiveQueue string `protobuf:"bytes,10,opt,name=preemptive_queue,json=preemptiveQueue,proto3" json:"preemptiveQueue,omitempty"`
+	// Priority class of the job that caused the preemption.
+	PreemptivePriorityClass string `protobuf:"bytes,11,opt,name=preemptive_priority_class,json=preemptivePriorityClass,proto3" json:"preemptivePriorityClass,omitempty"`
+	// Gang id of the job that caused the preemption, if it was part of a gang.
+	PreemptiveGangId string `protobuf:"bytes,12,opt,name=preemptive_gang_id,json=preemptiveGangId,proto3" json:"preemptiveGangId,omitempty"`
+	// Position of the queue of the preempted job in the order in which queues are considered for scheduling
+	// as of the round in which the job was preempted, starting at 1.
+	ExpectedRequeuePosition int32 `protobuf:"varint,13,opt,name=expected_requeue_position,json=expectedRequeuePosition,proto3" json:"expectedRequeuePosition,omitempty"`
+	// True if the queue of the preempted job is below its fair share once the preemption is accounted for,
+	// in which case fair share accounting prioritises scheduling jobs of the queue in subsequent rounds.
+	QueueCompensated bool `protobuf:"varint,14,opt,name=queue_compensated,json=queueCompensated,proto3" json:"queueCompensated,omitempty"`
 }
 
 func (m *JobPreemptedEvent) Reset()      { *m = JobPreemptedEvent{} }
@@ -1250,6 +1265,48 @@ func (m *JobPreemptedEvent) GetPreemptiveRunId() string {
 	return ""
 }
 
+func (m *JobPreemptedEvent) GetCause() string {
+	if m != nil {
+		return m.Cause
+	}
+	return ""
+}
+
+func (m *JobPreemptedEvent) GetPreemptiveQueue() string {
+	if m != nil {
+		return m.PreemptiveQueue
+	}
+	return ""
+}
+
+func (m *JobPreemptedEvent) GetPreemptivePriorityClass() string {
+	if m != nil {
+		return m.PreemptivePriorityClass
+	}
+	return ""
+}
+
+func (m *JobPreemptedEvent) GetPreemptiveGangId() string {
+	if m != nil {
+		return m.PreemptiveGangId
+	}
+	return ""
+}
+
+func (m *JobPreemptedEvent) GetExpectedRequeuePosition() int32 {
+	if m != nil {
+		return m.ExpectedRequeuePosition
+	}
+	return 0
+}
+
+func (m *JobPreemptedEvent) GetQueueCompensated() bool {
+	if m != nil {
+		return m.QueueCompensated
+	}
+	return false
+}
+
 // Only used internally by Armada
 type JobFailedEventCompressed struct {
 	Event []byte `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
@@ -1305,6 +1362,7 @@ type JobSucceededEvent struct {
 	PodNumber    int32     `protobuf:"varint,8,opt,name=pod_number,json=podNumber,proto3" json:"podNumber,omitempty"`
 	PodName      string    `protobuf:"bytes,9,opt,name=pod_name,json=podName,proto3" json:"podName,omitempty"`
 	PodNamespace string    `protobuf:"bytes,10,opt,name=pod_namespace,json=podNamespace,proto3" json:"podNamespace,omitempty"`
+	OutputUrl    string    `protobuf:"bytes,11,opt,name=output_url,json=outputUrl,proto3" json:"outputUrl,omitempty"`
 }
 
 func (m *JobSucceededEvent) Reset()      { *m = JobSucceededEvent{} }
@@ -1409,6 +1467,13 @@ func (m *JobSucceededEvent) GetPodNamespace() string {
 	return ""
 }
 
+func (m *JobSucceededEvent) GetOutputUrl() string {
+	if m != nil {
+		return m.OutputUrl
+	}
+	return ""
+}
+
 type JobUtilisationEvent struct {
 	JobId                 string                       `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"jobId,omitempty"`
 	JobSetId              string                       `protobuf:"bytes,2,opt,name=job_set_id,json=jobSetId,proto3" json:"jobSetId,omitempty"`
@@ -4133,6 +4198,49 @@ func (m *JobPreemptedEvent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.QueueCompensated {
+		i--
+		if m.QueueCompensated {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x70
+	}
+	if m.ExpectedRequeuePosition != 0 {
+		i = encodeVarintEvent(dAtA, i, uint64(m.ExpectedRequeuePosition))
+		i--
+		dAtA[i] = 0x68
+	}
+	if len(m.PreemptiveGangId) > 0 {
+		i -= len(m.PreemptiveGangId)
+		copy(dAtA[i:], m.PreemptiveGangId)
+		i = encodeVarintEvent(dAtA, i, uint64(len(m.PreemptiveGangId)))
+		i--
+		dAtA[i] = 0x62
+	}
+	if len(m.PreemptivePriorityClass) > 0 {
+		i -= len(m.PreemptivePriorityClass)
+		copy(dAtA[i:], m.PreemptivePriorityClass)
+		i = encodeVarintEvent(dAtA, i, uint64(len(m.PreemptivePriorityClass)))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if len(m.PreemptiveQueue) > 0 {
+		i -= len(m.PreemptiveQueue)
+		copy(dAtA[i:], m.PreemptiveQueue)
+		i = encodeVarintEvent(dAtA, i, uint64(len(m.PreemptiveQueue)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.Cause) > 0 {
+		i -= len(m.Cause)
+		copy(dAtA[i:], m.Cause)
+		i = encodeVarintEvent(dAtA, i, uint64(len(m.Cause)))
+		i--
+		dAtA[i] = 0x4a
+	}
 	if len(m.PreemptiveRunId) > 0 {
 		i -= len(m.PreemptiveRunId)
 		copy(dAtA[i:], m.PreemptiveRunId)
@@ -4243,6 +4351,13 @@ func (m *JobSucceededEvent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.OutputUrl) > 0 {
+		i -= len(m.OutputUrl)
+		copy(dAtA[i:], m.OutputUrl)
+		i = encodeVarintEvent(dAtA, i, uint64(len(m.OutputUrl)))
+		i--
+		dAtA[i] = 0x5a
+	}
 	if len(m.PodNamespace) > 0 {
 		i -= len(m.PodNamespace)
 		copy(dAtA[i:], m.PodNamespace)
@@ -6116,6 +6231,28 @@ func (m *JobPreemptedEvent) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovEvent(uint64(l))
 	}
+	l = len(m.Cause)
+	if l > 0 {
+		n += 1 + l + sovEvent(uint64(l))
+	}
+	l = len(m.PreemptiveQueue)
+	if l > 0 {
+		n += 1 + l + sovEvent(uint64(l))
+	}
+	l = len(m.PreemptivePriorityClass)
+	if l > 0 {
+		n += 1 + l + sovEvent(uint64(l))
+	}
+	l = len(m.PreemptiveGangId)
+	if l > 0 {
+		n += 1 + l + sovEvent(uint64(l))
+	}
+	if m.ExpectedRequeuePosition != 0 {
+		n += 1 + sovEvent(uint64(m.ExpectedRequeuePosition))
+	}
+	if m.QueueCompensated {
+		n += 2
+	}
 	return n
 }
 
@@ -6175,6 +6312,10 @@ func (m *JobSucceededEvent) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovEvent(uint64(l))
 	}
+	l = len(m.OutputUrl)
+	if l > 0 {
+		n += 1 + l + sovEvent(uint64(l))
+	}
 	return n
 }
 
@@ -7052,6 +7193,12 @@ func (this *JobPreemptedEvent) String() string {
 		`RunId:` + fmt.Sprintf("%v", this.RunId) + `,`,
 		`PreemptiveJobId:` + fmt.Sprintf("%v", this.PreemptiveJobId) + `,`,
 		`PreemptiveRunId:` + fmt.Sprintf("%v", this.PreemptiveRunId) + `,`,
+		`Cause:` + fmt.Sprintf("%v", this.Cause) + `,`,
+		`PreemptiveQueue:` + fmt.Sprintf("%v", this.PreemptiveQueue) + `,`,
+		`PreemptivePriorityClass:` + fmt.Sprintf("%v", this.PreemptivePriorityClass) + `,`,
+		`PreemptiveGangId:` + fmt.Sprintf("%v", this.PreemptiveGangId) + `,`,
+		`ExpectedRequeuePosition:` + fmt.Sprintf("%v", this.ExpectedRequeuePosition) + `,`,
+		`QueueCompensated:` + fmt.Sprintf("%v", this.QueueCompensated) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -7081,6 +7228,7 @@ func (this *JobSucceededEvent) String() string {
 		`PodNumber:` + fmt.Sprintf("%v", this.PodNumber) + `,`,
 		`PodName:` + fmt.Sprintf("%v", this.PodName) + `,`,
 		`PodNamespace:` + fmt.Sprintf("%v", this.PodNamespace) + `,`,
+		`OutputUrl:` + fmt.Sprintf("%v", this.OutputUrl) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -11211,6 +11359,173 @@ func (m *JobPreemptedEvent) Unmarshal(dAtA []byte) error {
 			}
 			m.PreemptiveRunId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cause", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvent
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cause = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PreemptiveQueue", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvent
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PreemptiveQueue = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PreemptivePriorityClass", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvent
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PreemptivePriorityClass = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PreemptiveGangId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvent
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PreemptiveGangId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpectedRequeuePosition", wireType)
+			}
+			m.ExpectedRequeuePosition = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExpectedRequeuePosition |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueueCompensated", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.QueueCompensated = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipEvent(dAtA[iNdEx:])
@@ -11653,6 +11968,38 @@ func (m *JobSucceededEvent) Unmarshal(dAtA []byte) error {
 			}
 			m.PodNamespace = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OutputUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvent
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvent
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OutputUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipEvent(dAtA[iNdEx:])
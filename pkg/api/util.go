@@ -453,3 +453,11 @@ func JobSetIdFromApiEvent(msg *EventMessage) string {
 	}
 	return ""
 }
+
+// OptimalJobsPerRequestHeader is the name of the response header via which the server advertises
+// the number of jobs clients should include in each submit request, given its current load.
+const OptimalJobsPerRequestHeader = "armada-optimal-jobs-per-request"
+
+// SubmitWarningsHeader is the name of the response header via which the server returns warnings about submitted jobs,
+// e.g., that a job is too small to be scheduled in the pool it targets; there's one value per warning.
+const SubmitWarningsHeader = "armada-submit-warnings"
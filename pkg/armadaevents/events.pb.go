@@ -1657,6 +1657,11 @@ type JobRunLeased struct {
 	NodeId     string `protobuf:"bytes,4,opt,name=node_id,json=nodeId,proto3" json:"nodeId,omitempty"`
 	// Used by the scheduler to maintain a consistent state
 	UpdateSequenceNumber int32 `protobuf:"varint,5,opt,name=update_sequence_number,json=updateSequenceNumber,proto3" json:"updateSequenceNumber,omitempty"`
+	// Time at which the job was ingested into the scheduler database.
+	// Only set for the first run of a job and for jobs ingested after this was recorded.
+	JobIngested *time.Time `protobuf:"bytes,6,opt,name=job_ingested,json=jobIngested,proto3,stdtime" json:"jobIngested,omitempty"`
+	// Time at which the scheduler first considered the job for scheduling since it was last queued.
+	FirstConsidered *time.Time `protobuf:"bytes,7,opt,name=first_considered,json=firstConsidered,proto3,stdtime" json:"firstConsidered,omitempty"`
 }
 
 func (m *JobRunLeased) Reset()         { *m = JobRunLeased{} }
@@ -1727,6 +1732,20 @@ func (m *JobRunLeased) GetUpdateSequenceNumber() int32 {
 	return 0
 }
 
+func (m *JobRunLeased) GetJobIngested() *time.Time {
+	if m != nil {
+		return m.JobIngested
+	}
+	return nil
+}
+
+func (m *JobRunLeased) GetFirstConsidered() *time.Time {
+	if m != nil {
+		return m.FirstConsidered
+	}
+	return nil
+}
+
 // Indicates that a job has been assigned to nodes by Kubernetes.
 type JobRunAssigned struct {
 	RunId *Uuid `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"runId,omitempty"`
@@ -3261,6 +3280,21 @@ type JobRunPreempted struct {
 	PreemptiveJobId *Uuid `protobuf:"bytes,3,opt,name=preemptive_job_id,json=preemptiveJobId,proto3" json:"preemptiveJobId,omitempty"`
 	// Uuid of the job run that caused the preemption.
 	PreemptiveRunId *Uuid `protobuf:"bytes,4,opt,name=preemptive_run_id,json=preemptiveRunId,proto3" json:"preemptiveRunId,omitempty"`
+	// Why the job was preempted; one of "FairShare", if preempted to balance resource usage across queues,
+	// or "NodeOversubscribed", if preempted since its node was oversubscribed, e.g., by jobs of higher priority.
+	Cause string `protobuf:"bytes,5,opt,name=cause,proto3" json:"cause,omitempty"`
+	// Queue of the job that caused the preemption.
+	PreemptiveQueue string `protobuf:"bytes,6,opt,name=preemptive_queue,json=preemptiveQueue,proto3" json:"preemptiveQueue,omitempty"`
+	// Priority class of the job that caused the preemption.
+	PreemptivePriorityClass string `protobuf:"bytes,7,opt,name=preemptive_priority_class,json=preemptivePriorityClass,proto3" json:"preemptivePriorityClass,omitempty"`
+	// Gang id of the job that caused the preemption, if it was part of a gang.
+	PreemptiveGangId string `protobuf:"bytes,8,opt,name=preemptive_gang_id,json=preemptiveGangId,proto3" json:"preemptiveGangId,omitempty"`
+	// Position of the queue of the preempted job in the order in which queues are considered for scheduling
+	// as of the round in which the job was preempted, starting at 1.
+	ExpectedRequeuePosition int32 `protobuf:"varint,9,opt,name=expected_requeue_position,json=expectedRequeuePosition,proto3" json:"expectedRequeuePosition,omitempty"`
+	// True if the queue of the preempted job is below its fair share once the preemption is accounted for,
+	// in which case fair share accounting prioritises scheduling jobs of the queue in subsequent rounds.
+	QueueCompensated bool `protobuf:"varint,10,opt,name=queue_compensated,json=queueCompensated,proto3" json:"queueCompensated,omitempty"`
 }
 
 func (m *JobRunPreempted) Reset()         { *m = JobRunPreempted{} }
@@ -3324,6 +3358,48 @@ func (m *JobRunPreempted) GetPreemptiveRunId() *Uuid {
 	return nil
 }
 
+func (m *JobRunPreempted) GetCause() string {
+	if m != nil {
+		return m.Cause
+	}
+	return ""
+}
+
+func (m *JobRunPreempted) GetPreemptiveQueue() string {
+	if m != nil {
+		return m.PreemptiveQueue
+	}
+	return ""
+}
+
+func (m *JobRunPreempted) GetPreemptivePriorityClass() string {
+	if m != nil {
+		return m.PreemptivePriorityClass
+	}
+	return ""
+}
+
+func (m *JobRunPreempted) GetPreemptiveGangId() string {
+	if m != nil {
+		return m.PreemptiveGangId
+	}
+	return ""
+}
+
+func (m *JobRunPreempted) GetExpectedRequeuePosition() int32 {
+	if m != nil {
+		return m.ExpectedRequeuePosition
+	}
+	return 0
+}
+
+func (m *JobRunPreempted) GetQueueCompensated() bool {
+	if m != nil {
+		return m.QueueCompensated
+	}
+	return false
+}
+
 // Message used internally by Armada to see if messages can be propagated through a pulsar partition
 type PartitionMarker struct {
 	// group id ties together multiple messages across different partitions
@@ -5288,6 +5364,26 @@ func (m *JobRunLeased) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.FirstConsidered != nil {
+		n, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.FirstConsidered, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.FirstConsidered):])
+		if err != nil {
+			return 0, err
+		}
+		i -= n
+		i = encodeVarintEvents(dAtA, i, uint64(n))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.JobIngested != nil {
+		n, err := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.JobIngested, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.JobIngested):])
+		if err != nil {
+			return 0, err
+		}
+		i -= n
+		i = encodeVarintEvents(dAtA, i, uint64(n))
+		i--
+		dAtA[i] = 0x32
+	}
 	if m.UpdateSequenceNumber != 0 {
 		i = encodeVarintEvents(dAtA, i, uint64(m.UpdateSequenceNumber))
 		i--
@@ -6705,6 +6801,49 @@ func (m *JobRunPreempted) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.QueueCompensated {
+		i--
+		if m.QueueCompensated {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.ExpectedRequeuePosition != 0 {
+		i = encodeVarintEvents(dAtA, i, uint64(m.ExpectedRequeuePosition))
+		i--
+		dAtA[i] = 0x48
+	}
+	if len(m.PreemptiveGangId) > 0 {
+		i -= len(m.PreemptiveGangId)
+		copy(dAtA[i:], m.PreemptiveGangId)
+		i = encodeVarintEvents(dAtA, i, uint64(len(m.PreemptiveGangId)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.PreemptivePriorityClass) > 0 {
+		i -= len(m.PreemptivePriorityClass)
+		copy(dAtA[i:], m.PreemptivePriorityClass)
+		i = encodeVarintEvents(dAtA, i, uint64(len(m.PreemptivePriorityClass)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.PreemptiveQueue) > 0 {
+		i -= len(m.PreemptiveQueue)
+		copy(dAtA[i:], m.PreemptiveQueue)
+		i = encodeVarintEvents(dAtA, i, uint64(len(m.PreemptiveQueue)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.Cause) > 0 {
+		i -= len(m.Cause)
+		copy(dAtA[i:], m.Cause)
+		i = encodeVarintEvents(dAtA, i, uint64(len(m.Cause)))
+		i--
+		dAtA[i] = 0x2a
+	}
 	if m.PreemptiveRunId != nil {
 		{
 			size, err := m.PreemptiveRunId.MarshalToSizedBuffer(dAtA[:i])
@@ -7595,6 +7734,14 @@ func (m *JobRunLeased) Size() (n int) {
 	if m.UpdateSequenceNumber != 0 {
 		n += 1 + sovEvents(uint64(m.UpdateSequenceNumber))
 	}
+	if m.JobIngested != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.JobIngested)
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	if m.FirstConsidered != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.FirstConsidered)
+		n += 1 + l + sovEvents(uint64(l))
+	}
 	return n
 }
 
@@ -8217,6 +8364,28 @@ func (m *JobRunPreempted) Size() (n int) {
 		l = m.PreemptiveRunId.Size()
 		n += 1 + l + sovEvents(uint64(l))
 	}
+	l = len(m.Cause)
+	if l > 0 {
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	l = len(m.PreemptiveQueue)
+	if l > 0 {
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	l = len(m.PreemptivePriorityClass)
+	if l > 0 {
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	l = len(m.PreemptiveGangId)
+	if l > 0 {
+		n += 1 + l + sovEvents(uint64(l))
+	}
+	if m.ExpectedRequeuePosition != 0 {
+		n += 1 + sovEvents(uint64(m.ExpectedRequeuePosition))
+	}
+	if m.QueueCompensated {
+		n += 2
+	}
 	return n
 }
 
@@ -12298,6 +12467,78 @@ func (m *JobRunLeased) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field JobIngested", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.JobIngested == nil {
+				m.JobIngested = new(time.Time)
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.JobIngested, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FirstConsidered", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.FirstConsidered == nil {
+				m.FirstConsidered = new(time.Time)
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.FirstConsidered, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipEvents(dAtA[iNdEx:])
@@ -15988,6 +16229,173 @@ func (m *JobRunPreempted) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cause", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cause = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PreemptiveQueue", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PreemptiveQueue = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PreemptivePriorityClass", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PreemptivePriorityClass = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PreemptiveGangId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEvents
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PreemptiveGangId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpectedRequeuePosition", wireType)
+			}
+			m.ExpectedRequeuePosition = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExpectedRequeuePosition |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueueCompensated", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.QueueCompensated = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipEvents(dAtA[iNdEx:])
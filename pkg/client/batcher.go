@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+// SubmitBatcher coalesces jobs submitted concurrently, e.g., by the many tasks of a workflow engine,
+// into as few SubmitJobs requests as possible.
+//
+// Flushing adapts to the latency of the server: for each job set, at most one request is in flight at a time,
+// and jobs submitted while a request is in flight are sent together in the next request.
+// Hence, a lone job is sent immediately, whereas jobs submitted in quick succession are batched.
+// The number of jobs per request is the batch size most recently advertised by the server,
+// which is reduced when the server is under load, capped at MaxJobsPerRequest.
+type SubmitBatcher struct {
+	client *Client
+	// Timeout of each SubmitJobs request.
+	requestTimeout time.Duration
+	// Jobs waiting to be sent, by queue and job set.
+	batches map[batchKey]*pendingBatch
+	// Batch size most recently advertised by the server.
+	batchSize int
+	mu        sync.Mutex
+}
+
+type batchKey struct {
+	queue    string
+	jobSetId string
+}
+
+type pendingBatch struct {
+	jobs     []*api.JobSubmitRequestItem
+	results  []chan submitResult
+	inFlight bool
+}
+
+type submitResult struct {
+	item *api.JobSubmitResponseItem
+	err  error
+}
+
+func NewSubmitBatcher(client *Client) *SubmitBatcher {
+	return &SubmitBatcher{
+		client:         client,
+		requestTimeout: 30 * time.Second,
+		batches:        make(map[batchKey]*pendingBatch),
+		batchSize:      MaxJobsPerRequest,
+	}
+}
+
+// SetRequestTimeout sets the timeout of each SubmitJobs request sent by the batcher.
+func (b *SubmitBatcher) SetRequestTimeout(timeout time.Duration) {
+	b.requestTimeout = timeout
+}
+
+// Submit submits job to jobSetId in queue as part of a batch, blocking until the batch has been submitted.
+// If the job has no client id, one is assigned, such that retried submissions are deduplicated by the server.
+// If ctx is cancelled before the batch has been submitted, Submit returns ctx.Err(); the job may still be submitted.
+func (b *SubmitBatcher) Submit(ctx context.Context, queue string, jobSetId string, job *api.JobSubmitRequestItem) (*api.JobSubmitResponseItem, error) {
+	AddClientIds([]*api.JobSubmitRequestItem{job})
+	ch := make(chan submitResult, 1)
+
+	b.mu.Lock()
+	key := batchKey{queue: queue, jobSetId: jobSetId}
+	batch, ok := b.batches[key]
+	if !ok {
+		batch = &pendingBatch{}
+		b.batches[key] = batch
+	}
+	batch.jobs = append(batch.jobs, job)
+	batch.results = append(batch.results, ch)
+	if !batch.inFlight {
+		b.flush(key, batch)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-ch:
+		return result.item, result.err
+	}
+}
+
+// flush sends up to batchSize pending jobs of batch in a single request. Must be called with b.mu held.
+func (b *SubmitBatcher) flush(key batchKey, batch *pendingBatch) {
+	n := len(batch.jobs)
+	if n > b.batchSize {
+		n = b.batchSize
+	}
+	jobs, results := batch.jobs[:n], batch.results[:n]
+	batch.jobs, batch.results = batch.jobs[n:], batch.results[n:]
+	batch.inFlight = true
+	go func() {
+		items, batchSize, err := b.send(key, jobs)
+		if batchSize > 0 {
+			b.mu.Lock()
+			b.batchSize = batchSize
+			b.mu.Unlock()
+		}
+		for i, ch := range results {
+			switch {
+			case err != nil:
+				ch <- submitResult{err: err}
+			case items[i].Error != "":
+				ch <- submitResult{item: items[i], err: errors.Errorf("failed to submit job: %s", items[i].Error)}
+			default:
+				ch <- submitResult{item: items[i]}
+			}
+		}
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		batch.inFlight = false
+		if len(batch.jobs) > 0 {
+			b.flush(key, batch)
+		} else {
+			delete(b.batches, key)
+		}
+	}()
+}
+
+// send submits jobs and returns the response items along with the batch size advertised by the server, if any.
+func (b *SubmitBatcher) send(key batchKey, jobs []*api.JobSubmitRequestItem) ([]*api.JobSubmitResponseItem, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+	var header metadata.MD
+	response, err := b.client.submit.SubmitJobs(
+		ctx,
+		&api.JobSubmitRequest{Queue: key.queue, JobSetId: key.jobSetId, JobRequestItems: jobs},
+		grpc.Header(&header),
+	)
+	batchSize := advertisedBatchSize(header)
+	if err != nil {
+		return nil, batchSize, err
+	}
+	if len(response.JobResponseItems) != len(jobs) {
+		return nil, batchSize, errors.Errorf(
+			"expected %d response items but got %d", len(jobs), len(response.JobResponseItems),
+		)
+	}
+	return response.JobResponseItems, batchSize, nil
+}
+
+// advertisedBatchSize returns the batch size advertised via header, capped at MaxJobsPerRequest,
+// or zero if none was advertised.
+func advertisedBatchSize(header metadata.MD) int {
+	values := header.Get(api.OptimalJobsPerRequestHeader)
+	if len(values) == 0 {
+		return 0
+	}
+	size, err := strconv.Atoi(values[0])
+	if err != nil || size <= 0 {
+		return 0
+	}
+	if size > MaxJobsPerRequest {
+		return MaxJobsPerRequest
+	}
+	return size
+}
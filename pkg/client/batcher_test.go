@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+// blockingSubmitClient blocks each request until released and advertises batchSize to the client.
+type blockingSubmitClient struct {
+	api.SubmitClient
+	batchSize int
+	received  chan *api.JobSubmitRequest
+	release   chan struct{}
+}
+
+func (c *blockingSubmitClient) SubmitJobs(_ context.Context, request *api.JobSubmitRequest, opts ...grpc.CallOption) (*api.JobSubmitResponse, error) {
+	c.received <- request
+	<-c.release
+	for _, opt := range opts {
+		if header, ok := opt.(grpc.HeaderCallOption); ok {
+			*header.HeaderAddr = metadata.Pairs(api.OptimalJobsPerRequestHeader, strconv.Itoa(c.batchSize))
+		}
+	}
+	items := make([]*api.JobSubmitResponseItem, len(request.JobRequestItems))
+	for i, item := range request.JobRequestItems {
+		items[i] = &api.JobSubmitResponseItem{JobId: "id-" + item.ClientId}
+	}
+	return &api.JobSubmitResponse{JobResponseItems: items}, nil
+}
+
+func TestSubmitBatcher_CoalescesWhileInFlight(t *testing.T) {
+	submit := &blockingSubmitClient{
+		batchSize: 3,
+		received:  make(chan *api.JobSubmitRequest, 10),
+		release:   make(chan struct{}),
+	}
+	batcher := NewSubmitBatcher(&Client{submit: submit})
+
+	var wg sync.WaitGroup
+	results := make([]*api.JobSubmitResponseItem, 6)
+	submitJob := func(i int) {
+		defer wg.Done()
+		job := &api.JobSubmitRequestItem{ClientId: fmt.Sprintf("%d", i)}
+		item, err := batcher.Submit(context.Background(), "queue", "jobSet", job)
+		assert.NoError(t, err)
+		results[i] = item
+	}
+
+	// The first job is sent immediately, since no request is in flight.
+	wg.Add(1)
+	go submitJob(0)
+	first := <-submit.received
+	assert.Len(t, first.JobRequestItems, 1)
+
+	// Jobs submitted while the first request is in flight are sent together,
+	// limited to the batch size the server advertised in its response.
+	wg.Add(5)
+	for i := 1; i < 6; i++ {
+		go submitJob(i)
+	}
+	require.Eventually(t, func() bool { return pendingJobs(batcher) == 5 }, time.Second, time.Millisecond)
+	close(submit.release)
+
+	second := <-submit.received
+	third := <-submit.received
+	assert.Len(t, second.JobRequestItems, 3)
+	assert.Len(t, third.JobRequestItems, 2)
+
+	wg.Wait()
+	for i, item := range results {
+		assert.Equal(t, fmt.Sprintf("id-%d", i), item.JobId)
+	}
+	assert.Equal(t, 0, pendingJobs(batcher))
+}
+
+func TestAdvertisedBatchSize(t *testing.T) {
+	tests := map[string]struct {
+		header   metadata.MD
+		expected int
+	}{
+		"not advertised": {header: metadata.MD{}, expected: 0},
+		"advertised":     {header: metadata.Pairs(api.OptimalJobsPerRequestHeader, "50"), expected: 50},
+		"capped":         {header: metadata.Pairs(api.OptimalJobsPerRequestHeader, "100000"), expected: MaxJobsPerRequest},
+		"invalid":        {header: metadata.Pairs(api.OptimalJobsPerRequestHeader, "many"), expected: 0},
+		"zero":           {header: metadata.Pairs(api.OptimalJobsPerRequestHeader, "0"), expected: 0},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, advertisedBatchSize(tc.header))
+		})
+	}
+}
+
+func pendingJobs(batcher *SubmitBatcher) int {
+	batcher.mu.Lock()
+	defer batcher.mu.Unlock()
+	n := 0
+	for _, batch := range batcher.batches {
+		n += len(batch.jobs)
+	}
+	return n
+}
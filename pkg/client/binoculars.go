@@ -0,0 +1,90 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+
+	"github.com/armadaproject/armada/pkg/api/binoculars"
+)
+
+// GetBinocularsUrl returns the url of the binoculars instance serving the given executor cluster,
+// computed from the binocularsUrlTemplate setting.
+func GetBinocularsUrl(cluster string) string {
+	t := viper.GetString("binocularsUrlTemplate")
+	if t == "" {
+		t = "{{cluster}}-binoculars:50051"
+	}
+	return strings.ReplaceAll(t, "{{cluster}}", cluster)
+}
+
+// GetBinocularsHttpUrl returns the url of the http api of the binoculars instance serving the given executor cluster,
+// computed from the binocularsHttpUrlTemplate setting.
+func GetBinocularsHttpUrl(cluster string) string {
+	t := viper.GetString("binocularsHttpUrlTemplate")
+	if t == "" {
+		t = "http://{{cluster}}-binoculars:8080"
+	}
+	return strings.ReplaceAll(t, "{{cluster}}", cluster)
+}
+
+// ServeBinocularsProxy starts an http proxy listening on localhost that forwards requests, including upgraded connections,
+// to the binoculars instance at binocularsUrl, adding credentials obtained using the given connection details.
+// Used to run, e.g., kubectl exec against binoculars. Returns the url of the proxy and a function that stops it.
+func ServeBinocularsProxy(apiConnectionDetails *ApiConnectionDetails, binocularsUrl string) (string, func(), error) {
+	target, err := url.Parse(binocularsUrl)
+	if err != nil {
+		return "", nil, errors.WithStack(err)
+	}
+	creds, err := perRpcCredentials(apiConnectionDetails)
+	if err != nil {
+		return "", nil, err
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	director := proxy.Director
+	proxy.Director = func(r *http.Request) {
+		director(r)
+		r.Host = target.Host
+		r.Header.Del("Authorization")
+		if creds == nil {
+			return
+		}
+		md, err := creds.GetRequestMetadata(r.Context(), binocularsUrl)
+		if err != nil {
+			log.WithError(err).Error("failed to get credentials for binoculars")
+			return
+		}
+		for k, v := range md {
+			r.Header.Set(k, v)
+		}
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, errors.WithStack(err)
+	}
+	server := &http.Server{Handler: proxy}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Error("binoculars proxy failure")
+		}
+	}()
+	return "http://" + listener.Addr().String(), func() { _ = server.Close() }, nil
+}
+
+// WithBinocularsClient connects to the binoculars instance at the given url,
+// using the same authentication options as for connecting to the Armada server.
+func WithBinocularsClient(apiConnectionDetails *ApiConnectionDetails, url string, action func(binoculars.BinocularsClient) error) error {
+	binocularsConnectionDetails := *apiConnectionDetails
+	binocularsConnectionDetails.ArmadaUrl = url
+	return WithConnection(&binocularsConnectionDetails, func(cc *grpc.ClientConn) error {
+		client := binoculars.NewBinocularsClient(cc)
+		return action(client)
+	})
+}
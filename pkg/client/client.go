@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+// Client is a typed client for the Armada API, such that Go integrators don't need to use the generated gRPC stubs.
+// Unary calls are retried with exponential backoff by the connection (see CreateApiConnection),
+// and all calls respect the deadline and cancellation of the context they're given.
+type Client struct {
+	conn   *grpc.ClientConn
+	submit api.SubmitClient
+	event  api.EventClient
+}
+
+// NewClient connects to the Armada server described by config.
+// The returned client should be closed once no longer needed.
+func NewClient(config *ApiConnectionDetails) (*Client, error) {
+	conn, err := CreateApiConnection(config)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to connect to armada server")
+	}
+	return NewClientFromConn(conn), nil
+}
+
+// NewClientFromConn returns a client using an existing connection, which is closed when the client is closed.
+func NewClientFromConn(conn *grpc.ClientConn) *Client {
+	return &Client{
+		conn:   conn,
+		submit: api.NewSubmitClient(conn),
+		event:  api.NewEventClient(conn),
+	}
+}
+
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// SubmitJobs submits jobs to jobSetId in queue, split into as many requests as necessary to stay within the
+// maximum number of jobs per request. Jobs without a client id are assigned one, such that retried submissions
+// are deduplicated by the server. Returns the response items of all requests sent; if a request fails,
+// the items of earlier requests are returned along with the error.
+func (c *Client) SubmitJobs(ctx context.Context, queue string, jobSetId string, jobs []*api.JobSubmitRequestItem) ([]*api.JobSubmitResponseItem, error) {
+	AddClientIds(jobs)
+	items := make([]*api.JobSubmitResponseItem, 0, len(jobs))
+	for _, request := range CreateChunkedSubmitRequests(queue, jobSetId, jobs) {
+		response, err := c.submit.SubmitJobs(ctx, request)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, response.JobResponseItems...)
+	}
+	return items, nil
+}
+
+// CancelJobs cancels the jobs matching request and returns the ids of the cancelled jobs.
+func (c *Client) CancelJobs(ctx context.Context, request *api.JobCancelRequest) ([]string, error) {
+	result, err := c.submit.CancelJobs(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return result.CancelledIds, nil
+}
+
+// CancelJobSet cancels all jobs of jobSetId in queue.
+func (c *Client) CancelJobSet(ctx context.Context, queue string, jobSetId string) error {
+	_, err := c.submit.CancelJobSet(ctx, &api.JobSetCancelRequest{Queue: queue, JobSetId: jobSetId})
+	return err
+}
+
+// ReprioritizeJobs changes the priority of the jobs matching request.
+// Returns a map from job id to error message for jobs that couldn't be reprioritised.
+func (c *Client) ReprioritizeJobs(ctx context.Context, request *api.JobReprioritizeRequest) (map[string]string, error) {
+	response, err := c.submit.ReprioritizeJobs(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return response.ReprioritizationResults, nil
+}
+
+func (c *Client) CreateQueue(ctx context.Context, queue *api.Queue) error {
+	_, err := c.submit.CreateQueue(ctx, queue)
+	return err
+}
+
+func (c *Client) UpdateQueue(ctx context.Context, queue *api.Queue) error {
+	_, err := c.submit.UpdateQueue(ctx, queue)
+	return err
+}
+
+func (c *Client) DeleteQueue(ctx context.Context, name string) error {
+	_, err := c.submit.DeleteQueue(ctx, &api.QueueDeleteRequest{Name: name})
+	return err
+}
+
+func (c *Client) GetQueue(ctx context.Context, name string) (*api.Queue, error) {
+	return c.submit.GetQueue(ctx, &api.QueueGetRequest{Name: name})
+}
+
+// GetQueueInfo returns the active job sets of a queue.
+func (c *Client) GetQueueInfo(ctx context.Context, name string) (*api.QueueInfo, error) {
+	return c.submit.GetQueueInfo(ctx, &api.QueueInfoRequest{Name: name})
+}
+
+// Queues returns an iterator over all queues.
+func (c *Client) Queues(ctx context.Context) *QueueIterator {
+	return newQueueIterator(ctx, c.submit)
+}
+
+// JobSetEvents returns an iterator over the events of jobSetId in queue published so far.
+func (c *Client) JobSetEvents(ctx context.Context, queue string, jobSetId string, options WatchOptions) *EventIterator {
+	return newEventIterator(ctx, c.event, queue, jobSetId, false, options)
+}
+
+// Watch returns an iterator over the events of jobSetId in queue, which blocks waiting for new events once all
+// existing events have been returned, until ctx is cancelled or the iterator is closed.
+// Streams interrupted by transient errors are resumed from the last event received.
+func (c *Client) Watch(ctx context.Context, queue string, jobSetId string, options WatchOptions) *EventIterator {
+	return newEventIterator(ctx, c.event, queue, jobSetId, true, options)
+}
+
+// Health returns nil if the server is healthy.
+func (c *Client) Health(ctx context.Context) error {
+	response, err := c.submit.Health(ctx, &types.Empty{})
+	if err != nil {
+		return err
+	}
+	if response.Status != api.HealthCheckResponse_SERVING {
+		return errors.Errorf("armada server is %s", response.Status)
+	}
+	return nil
+}
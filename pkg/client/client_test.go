@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+type fakeSubmitClient struct {
+	api.SubmitClient
+	requests []*api.JobSubmitRequest
+	queues   []*api.Queue
+}
+
+func (c *fakeSubmitClient) SubmitJobs(_ context.Context, request *api.JobSubmitRequest, _ ...grpc.CallOption) (*api.JobSubmitResponse, error) {
+	c.requests = append(c.requests, request)
+	items := make([]*api.JobSubmitResponseItem, len(request.JobRequestItems))
+	for i, item := range request.JobRequestItems {
+		items[i] = &api.JobSubmitResponseItem{JobId: item.ClientId}
+	}
+	return &api.JobSubmitResponse{JobResponseItems: items}, nil
+}
+
+func (c *fakeSubmitClient) GetQueues(_ context.Context, _ *api.StreamingQueueGetRequest, _ ...grpc.CallOption) (api.Submit_GetQueuesClient, error) {
+	messages := make([]*api.StreamingQueueMessage, 0, len(c.queues)+1)
+	for _, queue := range c.queues {
+		messages = append(messages, &api.StreamingQueueMessage{Event: &api.StreamingQueueMessage_Queue{Queue: queue}})
+	}
+	messages = append(messages, &api.StreamingQueueMessage{Event: &api.StreamingQueueMessage_End{End: &api.EndMarker{}}})
+	return &fakeQueueStream{messages: messages}, nil
+}
+
+type fakeQueueStream struct {
+	grpc.ClientStream
+	messages []*api.StreamingQueueMessage
+}
+
+func (s *fakeQueueStream) Recv() (*api.StreamingQueueMessage, error) {
+	if len(s.messages) == 0 {
+		return nil, io.EOF
+	}
+	msg := s.messages[0]
+	s.messages = s.messages[1:]
+	return msg, nil
+}
+
+// fakeEventClient returns the events after the requested message id, failing after each failEvery messages.
+type fakeEventClient struct {
+	api.EventClient
+	messages  []*api.EventStreamMessage
+	failEvery int
+	requests  []*api.JobSetRequest
+}
+
+func (c *fakeEventClient) GetJobSetEvents(_ context.Context, request *api.JobSetRequest, _ ...grpc.CallOption) (api.Event_GetJobSetEventsClient, error) {
+	c.requests = append(c.requests, request)
+	start := 0
+	for i, msg := range c.messages {
+		if msg.Id == request.FromMessageId {
+			start = i + 1
+		}
+	}
+	return &fakeEventStream{messages: c.messages[start:], failEvery: c.failEvery}, nil
+}
+
+type fakeEventStream struct {
+	grpc.ClientStream
+	messages  []*api.EventStreamMessage
+	failEvery int
+	received  int
+}
+
+func (s *fakeEventStream) Recv() (*api.EventStreamMessage, error) {
+	if s.failEvery > 0 && s.received == s.failEvery {
+		return nil, status.Error(codes.Unavailable, "connection reset")
+	}
+	if len(s.messages) == 0 {
+		return nil, io.EOF
+	}
+	msg := s.messages[0]
+	s.messages = s.messages[1:]
+	s.received++
+	return msg, nil
+}
+
+func TestClient_SubmitJobs(t *testing.T) {
+	submit := &fakeSubmitClient{}
+	client := &Client{submit: submit}
+
+	items, err := client.SubmitJobs(context.Background(), "queue", "jobSet", createJobRequestItems(MaxJobsPerRequest+1))
+	require.NoError(t, err)
+
+	assert.Len(t, items, MaxJobsPerRequest+1)
+	assert.Len(t, submit.requests, 2)
+	for _, item := range items {
+		assert.NotEmpty(t, item.JobId)
+	}
+}
+
+func TestClient_Queues(t *testing.T) {
+	submit := &fakeSubmitClient{queues: []*api.Queue{{Name: "a"}, {Name: "b"}}}
+	client := &Client{submit: submit}
+
+	var names []string
+	it := client.Queues(context.Background())
+	for it.Next() {
+		names = append(names, it.Queue().Name)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"a", "b"}, names)
+}
+
+func TestClient_Watch(t *testing.T) {
+	event := &fakeEventClient{
+		messages: []*api.EventStreamMessage{
+			eventMessage("1", "job-1"),
+			eventMessage("2", "job-2"),
+			eventMessage("3", "job-1"),
+			eventMessage("4", "job-1"),
+		},
+		failEvery: 2,
+	}
+	client := &Client{event: event}
+
+	it := client.Watch(context.Background(), "queue", "jobSet", WatchOptions{JobIds: []string{"job-1"}})
+	it.backoff = time.Millisecond
+	defer it.Close()
+
+	var messageIds []string
+	for it.Next() {
+		assert.Equal(t, "job-1", it.Event().GetJobId())
+		messageIds = append(messageIds, it.MessageId())
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"1", "3", "4"}, messageIds)
+
+	// Interrupted streams are resumed from the last message received.
+	require.Len(t, event.requests, 3)
+	assert.Equal(t, "", event.requests[0].FromMessageId)
+	assert.Equal(t, "2", event.requests[1].FromMessageId)
+	assert.Equal(t, "4", event.requests[2].FromMessageId)
+	assert.True(t, event.requests[1].Watch)
+}
+
+func TestClient_JobSetEvents_DoesNotRetry(t *testing.T) {
+	event := &fakeEventClient{
+		messages:  []*api.EventStreamMessage{eventMessage("1", "job-1"), eventMessage("2", "job-1")},
+		failEvery: 1,
+	}
+	client := &Client{event: event}
+
+	it := client.JobSetEvents(context.Background(), "queue", "jobSet", WatchOptions{})
+	assert.True(t, it.Next())
+	assert.False(t, it.Next())
+	assert.Equal(t, codes.Unavailable, status.Code(it.Err()))
+}
+
+func eventMessage(id string, jobId string) *api.EventStreamMessage {
+	return &api.EventStreamMessage{
+		Id: id,
+		Message: &api.EventMessage{
+			Events: &api.EventMessage_Running{
+				Running: &api.JobRunningEvent{JobId: jobId, Queue: "queue", JobSetId: "jobSet"},
+			},
+		},
+	}
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"strings"
@@ -15,6 +16,8 @@ import (
 	"google.golang.org/grpc/keepalive"
 
 	"github.com/armadaproject/armada/internal/common"
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+	"github.com/armadaproject/armada/internal/common/certs"
 	"github.com/armadaproject/armada/pkg/client/auth/exec"
 	"github.com/armadaproject/armada/pkg/client/auth/kerberos"
 	"github.com/armadaproject/armada/pkg/client/auth/kubernetes"
@@ -47,6 +50,25 @@ type ApiConnectionDetails struct {
 	KerberosAuth                kerberos.ClientConfig
 	ForceNoTls                  bool
 	ExecAuth                    exec.CommandDetails
+	// TLS options, e.g., a client certificate for mTLS between Armada components.
+	Tls TlsConfig
+}
+
+// TlsConfig configures the TLS connection to the server.
+// Files are reloaded when they change, such that certificates issued by, e.g., cert-manager or a SPIFFE helper
+// are rotated without restarting the client.
+type TlsConfig struct {
+	// CA bundle used to verify the certificate of the server. If empty, the system CAs are used.
+	CAPath string
+	// Certificate and key presented to servers requesting a client certificate (mTLS). If empty, none is presented.
+	CertPath string
+	KeyPath  string
+	// Name the certificate of the server is verified against, if different from the host of ArmadaUrl.
+	ServerName string
+}
+
+func (c TlsConfig) configured() bool {
+	return c.CAPath != "" || c.CertPath != "" || c.KeyPath != "" || c.ServerName != ""
 }
 
 type ConnectionDetails func() *ApiConnectionDetails
@@ -69,11 +91,15 @@ func CreateApiConnectionWithCallOptions(
 	defaultCallOptions := grpc.WithDefaultCallOptions(callOptions...)
 	unuaryInterceptors := grpc.WithChainUnaryInterceptor(grpc_retry.UnaryClientInterceptor(retryOpts...))
 	streamInterceptors := grpc.WithChainStreamInterceptor(grpc_retry.StreamClientInterceptor(retryOpts...))
+	transportCreds, err := transportCredentials(config)
+	if err != nil {
+		return nil, err
+	}
 	dialOpts := append(additionalDialOptions,
 		defaultCallOptions,
 		unuaryInterceptors,
 		streamInterceptors,
-		transportCredentials(config),
+		transportCreds,
 	)
 	// gRPC keepalive options.
 	if config.GrpcKeepAliveTime > 0 || config.GrpcKeepAliveTimeout > 0 {
@@ -120,11 +146,46 @@ func perRpcCredentials(config *ApiConnectionDetails) (credentials.PerRPCCredenti
 	return nil, nil
 }
 
-func transportCredentials(config *ApiConnectionDetails) grpc.DialOption {
-	if !config.ForceNoTls && !strings.Contains(config.ArmadaUrl, "localhost") {
-		return grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(nil, ""))
+func transportCredentials(config *ApiConnectionDetails) (grpc.DialOption, error) {
+	if config.ForceNoTls {
+		return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
+	}
+	if config.Tls.configured() {
+		tlsConfig, err := clientTlsConfig(config.Tls)
+		if err != nil {
+			return nil, err
+		}
+		return grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), nil
+	}
+	if !strings.Contains(config.ArmadaUrl, "localhost") {
+		return grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(nil, "")), nil
+	}
+	return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
+}
+
+// How often certificate files are checked for changes.
+const certRefreshInterval = time.Minute
+
+func clientTlsConfig(config TlsConfig) (*tls.Config, error) {
+	var rootCAs *certs.CachedCertPoolService
+	if config.CAPath != "" {
+		var err error
+		rootCAs, err = certs.NewCachedCertPoolService(config.CAPath, certRefreshInterval)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error loading CA certificates")
+		}
+		go rootCAs.Run(armadacontext.Background())
+	}
+	var certificates *certs.CachedCertificateService
+	if config.CertPath != "" || config.KeyPath != "" {
+		// NewCachedCertificateService panics if the certificate can't be loaded; check that it can be beforehand.
+		if _, err := tls.LoadX509KeyPair(config.CertPath, config.KeyPath); err != nil {
+			return nil, errors.Wrap(err, "error loading client certificate")
+		}
+		certificates = certs.NewCachedCertificateService(config.CertPath, config.KeyPath, certRefreshInterval)
+		go certificates.Run(armadacontext.Background())
 	}
-	return grpc.WithTransportCredentials(insecure.NewCredentials())
+	return certs.NewClientTlsConfig(rootCAs, certificates, config.ServerName), nil
 }
 
 // ArmadaHealthCheck calls Armada Server /health endpoint.
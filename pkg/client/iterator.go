@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/armadaproject/armada/internal/common/util"
+	"github.com/armadaproject/armada/pkg/api"
+)
+
+// Number of queues requested per GetQueues call.
+const queuesPerPage = 1000
+
+// QueueIterator iterates over all queues, e.g.,
+//
+//	it := client.Queues(ctx)
+//	for it.Next() {
+//		fmt.Println(it.Queue().Name)
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type QueueIterator struct {
+	ctx    context.Context
+	submit api.SubmitClient
+	stream api.Submit_GetQueuesClient
+	queue  *api.Queue
+	done   bool
+	err    error
+}
+
+func newQueueIterator(ctx context.Context, submit api.SubmitClient) *QueueIterator {
+	return &QueueIterator{ctx: ctx, submit: submit}
+}
+
+// Next advances the iterator, returning false once there are no more queues or an error occurred.
+func (it *QueueIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	if it.stream == nil {
+		stream, err := it.submit.GetQueues(it.ctx, &api.StreamingQueueGetRequest{Num: queuesPerPage})
+		if err != nil {
+			return it.finish(err)
+		}
+		it.stream = stream
+	}
+	msg, err := it.stream.Recv()
+	if err != nil {
+		if err == io.EOF {
+			err = nil
+		}
+		return it.finish(err)
+	}
+	switch event := msg.Event.(type) {
+	case *api.StreamingQueueMessage_Queue:
+		it.queue = event.Queue
+		return true
+	default:
+		return it.finish(nil)
+	}
+}
+
+// Queue returns the queue the iterator is at.
+func (it *QueueIterator) Queue() *api.Queue {
+	return it.queue
+}
+
+// Err returns the error that stopped the iterator, if any.
+func (it *QueueIterator) Err() error {
+	return it.err
+}
+
+func (it *QueueIterator) finish(err error) bool {
+	it.done = true
+	it.queue = nil
+	it.err = err
+	return false
+}
+
+// WatchOptions filter the events returned by an EventIterator.
+type WatchOptions struct {
+	// If non-empty, only events of these jobs are returned.
+	JobIds []string
+	// If non-nil, only events for which Filter returns true are returned.
+	Filter func(event api.Event) bool
+	// If non-empty, only events after the event with this message id are returned,
+	// e.g., to resume watching from the last event seen by a previous iterator.
+	FromMessageId string
+}
+
+// EventIterator iterates over the events of a job set, e.g.,
+//
+//	it := client.Watch(ctx, queue, jobSetId, client.WatchOptions{})
+//	defer it.Close()
+//	for it.Next() {
+//		fmt.Println(it.Event().GetJobId())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type EventIterator struct {
+	ctx           context.Context
+	cancel        context.CancelFunc
+	event         api.EventClient
+	queue         string
+	jobSetId      string
+	watch         bool
+	jobIds        map[string]bool
+	filter        func(event api.Event) bool
+	lastMessageId string
+	stream        api.Event_GetJobSetEventsClient
+	current       api.Event
+	done          bool
+	err           error
+	// Time to wait before resuming a stream interrupted by a transient error.
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+func newEventIterator(ctx context.Context, event api.EventClient, queue string, jobSetId string, watch bool, options WatchOptions) *EventIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	return &EventIterator{
+		ctx:           ctx,
+		cancel:        cancel,
+		event:         event,
+		queue:         queue,
+		jobSetId:      jobSetId,
+		watch:         watch,
+		jobIds:        util.StringListToSet(options.JobIds),
+		filter:        options.Filter,
+		lastMessageId: options.FromMessageId,
+		backoff:       time.Second,
+		maxBackoff:    30 * time.Second,
+	}
+}
+
+// Next advances the iterator to the next event matching the watch options.
+// It returns false once all events have been returned (unless watching), the iterator is closed, or an error occurred.
+func (it *EventIterator) Next() bool {
+	backoff := it.backoff
+	for !it.done {
+		if it.ctx.Err() != nil {
+			return it.finish(it.ctx.Err())
+		}
+		if it.stream == nil {
+			stream, err := it.event.GetJobSetEvents(it.ctx, &api.JobSetRequest{
+				Queue:         it.queue,
+				Id:            it.jobSetId,
+				FromMessageId: it.lastMessageId,
+				Watch:         it.watch,
+			})
+			if err != nil {
+				if !it.retry(err, &backoff) {
+					return it.finish(err)
+				}
+				continue
+			}
+			it.stream = stream
+		}
+
+		msg, err := it.stream.Recv()
+		if err == io.EOF {
+			return it.finish(nil)
+		} else if err != nil {
+			it.stream = nil
+			if !it.retry(err, &backoff) {
+				return it.finish(err)
+			}
+			continue
+		}
+		backoff = it.backoff
+		it.lastMessageId = msg.Id
+
+		event, err := api.UnwrapEvent(msg.Message)
+		if err != nil {
+			// Event types unknown to this client are skipped.
+			continue
+		}
+		if len(it.jobIds) > 0 && !it.jobIds[event.GetJobId()] {
+			continue
+		}
+		if it.filter != nil && !it.filter(event) {
+			continue
+		}
+		it.current = event
+		return true
+	}
+	return false
+}
+
+// Event returns the event the iterator is at.
+func (it *EventIterator) Event() api.Event {
+	return it.current
+}
+
+// MessageId returns the id of the last message received,
+// which can be passed via WatchOptions.FromMessageId to resume from this point later.
+func (it *EventIterator) MessageId() string {
+	return it.lastMessageId
+}
+
+// Err returns the error that stopped the iterator, if any.
+// Closing the iterator or cancelling its context is reported as context.Canceled.
+func (it *EventIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator and releases the underlying stream.
+func (it *EventIterator) Close() {
+	it.cancel()
+}
+
+// retry waits before the stream is resumed and returns true if err is transient and the iterator is watching.
+func (it *EventIterator) retry(err error, backoff *time.Duration) bool {
+	if !it.watch || !isTransientError(err) {
+		return false
+	}
+	select {
+	case <-it.ctx.Done():
+		return false
+	case <-time.After(*backoff):
+	}
+	*backoff *= 2
+	if *backoff > it.maxBackoff {
+		*backoff = it.maxBackoff
+	}
+	return true
+}
+
+func (it *EventIterator) finish(err error) bool {
+	it.done = true
+	it.current = nil
+	it.err = err
+	it.cancel()
+	return false
+}
+
+func isTransientError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal:
+		return true
+	default:
+		return false
+	}
+}
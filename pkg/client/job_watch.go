@@ -0,0 +1,238 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+)
+
+// Path of the job watch api relative to ArmadaRestUrl.
+const jobWatchPath = "/api/v1/job/watch"
+
+// WatchedJobSet is a set of jobs of the same job set to wait for.
+type WatchedJobSet struct {
+	Queue    string   `json:"queue"`
+	JobSetId string   `json:"jobSetId"`
+	JobIds   []string `json:"jobIds"`
+}
+
+// JobWatchResult is the terminal state a watched job reached.
+type JobWatchResult struct {
+	JobId    string `json:"jobId"`
+	Queue    string `json:"queue"`
+	JobSetId string `json:"jobSetId"`
+	// One of "succeeded", "failed", or "cancelled".
+	Outcome string `json:"outcome"`
+	// Reason the job failed or was cancelled, if any.
+	Reason string    `json:"reason,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+type jobWatchRequest struct {
+	JobSets []WatchedJobSet `json:"jobSets"`
+}
+
+type jobWatchSubscription struct {
+	SubscriptionId string `json:"subscriptionId"`
+}
+
+type jobWatchPollResult struct {
+	Jobs    []*JobWatchResult `json:"jobs"`
+	Pending int               `json:"pending"`
+	Done    bool              `json:"done"`
+}
+
+// JobWatchClient waits for jobs to reach a terminal state using the job watch http api of the server at ArmadaRestUrl.
+// It's intended for workflow engines, e.g., for sensors tracking thousands of jobs:
+// rather than polling each job, or streaming all events of their job sets, it subscribes to the jobs once
+// and long-polls the subscription, which returns as soon as any of them reach a terminal state.
+type JobWatchClient struct {
+	url         string
+	httpClient  *http.Client
+	credentials credentials.PerRPCCredentials
+	// How long each poll waits for jobs to reach a terminal state; capped by the server.
+	PollTimeout time.Duration
+}
+
+func NewJobWatchClient(config *ApiConnectionDetails) (*JobWatchClient, error) {
+	url := config.ArmadaRestUrl
+	if url == "" {
+		return nil, errors.New("Armada server rest api url not provided")
+	}
+	if !strings.HasPrefix(url, "http") {
+		url = fmt.Sprintf("http://%s", url)
+	}
+	httpClient := &http.Client{}
+	if config.Tls.configured() {
+		tlsConfig, err := clientTlsConfig(config.Tls)
+		if err != nil {
+			return nil, err
+		}
+		httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+	creds, err := perRpcCredentials(config)
+	if err != nil {
+		return nil, err
+	}
+	return &JobWatchClient{
+		url:         strings.TrimSuffix(url, "/"),
+		httpClient:  httpClient,
+		credentials: creds,
+		PollTimeout: 30 * time.Second,
+	}, nil
+}
+
+// WaitForJobs blocks until all jobs of jobSets reached a terminal state, calling onResult once for each job as it does.
+// If the subscription is lost, e.g., since it expired or the server instance holding it was replaced,
+// WaitForJobs subscribes again to the jobs yet to reach a terminal state.
+func (c *JobWatchClient) WaitForJobs(ctx context.Context, jobSets []WatchedJobSet, onResult func(*JobWatchResult)) error {
+	pending := make(map[string]map[string]bool)
+	for _, jobSet := range jobSets {
+		key := jobSet.Queue + "/" + jobSet.JobSetId
+		if pending[key] == nil {
+			pending[key] = make(map[string]bool)
+		}
+		for _, jobId := range jobSet.JobIds {
+			pending[key][jobId] = true
+		}
+	}
+	for len(pending) > 0 {
+		subscriptionId, err := c.subscribe(ctx, pendingWatchedJobSets(jobSets, pending))
+		if err != nil {
+			return err
+		}
+		for len(pending) > 0 {
+			result, err := c.poll(ctx, subscriptionId)
+			if err != nil {
+				c.unsubscribe(subscriptionId)
+				return err
+			}
+			if result == nil {
+				// Subscription lost; subscribe again to the remaining jobs.
+				break
+			}
+			for _, job := range result.Jobs {
+				key := job.Queue + "/" + job.JobSetId
+				if !pending[key][job.JobId] {
+					continue
+				}
+				delete(pending[key], job.JobId)
+				if len(pending[key]) == 0 {
+					delete(pending, key)
+				}
+				onResult(job)
+			}
+			if result.Done {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func pendingWatchedJobSets(jobSets []WatchedJobSet, pending map[string]map[string]bool) []WatchedJobSet {
+	var rv []WatchedJobSet
+	for _, jobSet := range jobSets {
+		var jobIds []string
+		for _, jobId := range jobSet.JobIds {
+			if pending[jobSet.Queue+"/"+jobSet.JobSetId][jobId] {
+				jobIds = append(jobIds, jobId)
+			}
+		}
+		if len(jobIds) > 0 {
+			rv = append(rv, WatchedJobSet{Queue: jobSet.Queue, JobSetId: jobSet.JobSetId, JobIds: jobIds})
+		}
+	}
+	return rv
+}
+
+func (c *JobWatchClient) subscribe(ctx context.Context, jobSets []WatchedJobSet) (string, error) {
+	body, err := json.Marshal(&jobWatchRequest{JobSets: jobSets})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	resp, err := c.do(ctx, http.MethodPost, jobWatchPath, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if err := jobWatchResponseError(resp); err != nil {
+		return "", err
+	}
+	subscription := &jobWatchSubscription{}
+	if err := json.NewDecoder(resp.Body).Decode(subscription); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return subscription.SubscriptionId, nil
+}
+
+// poll returns the next result of the subscription, or nil if the subscription wasn't found.
+func (c *JobWatchClient) poll(ctx context.Context, subscriptionId string) (*jobWatchPollResult, error) {
+	path := fmt.Sprintf("%s/%s?timeout=%s", jobWatchPath, subscriptionId, c.PollTimeout)
+	resp, err := c.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err := jobWatchResponseError(resp); err != nil {
+		return nil, err
+	}
+	result := &jobWatchPollResult{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return result, nil
+}
+
+// unsubscribe removes the subscription on a best-effort basis, such that it doesn't linger until its lease expires.
+func (c *JobWatchClient) unsubscribe(subscriptionId string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := c.do(ctx, http.MethodDelete, jobWatchPath+"/"+subscriptionId, nil)
+	if err == nil {
+		resp.Body.Close()
+	}
+}
+
+func (c *JobWatchClient) do(ctx context.Context, method string, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.credentials != nil {
+		metadata, err := c.credentials.GetRequestMetadata(ctx, c.url)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error getting credentials")
+		}
+		for k, v := range metadata {
+			req.Header.Set(k, v)
+		}
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return resp, nil
+}
+
+func jobWatchResponseError(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return errors.Errorf("job watch request failed with status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
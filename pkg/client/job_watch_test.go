@@ -0,0 +1,61 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/armadaproject/armada/internal/common/armadacontext"
+)
+
+func TestJobWatchClient_WaitForJobs(t *testing.T) {
+	var subscriptions []jobWatchRequest
+	numPolls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == jobWatchPath:
+			req := jobWatchRequest{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			subscriptions = append(subscriptions, req)
+			require.NoError(t, json.NewEncoder(w).Encode(&jobWatchSubscription{SubscriptionId: fmt.Sprintf("%d", len(subscriptions))}))
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, jobWatchPath+"/"):
+			numPolls++
+			var result *jobWatchPollResult
+			switch numPolls {
+			case 1:
+				result = &jobWatchPollResult{Jobs: []*JobWatchResult{{JobId: "a", Queue: "queue", JobSetId: "set", Outcome: "succeeded"}}, Pending: 1}
+			case 2:
+				// The subscription was lost, e.g., since the server restarted.
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			default:
+				result = &jobWatchPollResult{Jobs: []*JobWatchResult{{JobId: "b", Queue: "queue", JobSetId: "set", Outcome: "failed"}}, Done: true}
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(result))
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewJobWatchClient(&ApiConnectionDetails{ArmadaRestUrl: server.URL})
+	require.NoError(t, err)
+	outcomeByJobId := make(map[string]string)
+	err = client.WaitForJobs(
+		armadacontext.Background(),
+		[]WatchedJobSet{{Queue: "queue", JobSetId: "set", JobIds: []string{"a", "b"}}},
+		func(result *JobWatchResult) { outcomeByJobId[result.JobId] = result.Outcome },
+	)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "succeeded", "b": "failed"}, outcomeByJobId)
+
+	// After losing the subscription, the client subscribes again to only the remaining jobs.
+	require.Len(t, subscriptions, 2)
+	assert.Equal(t, []WatchedJobSet{{Queue: "queue", JobSetId: "set", JobIds: []string{"b"}}}, subscriptions[1].JobSets)
+}
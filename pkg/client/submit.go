@@ -1,6 +1,9 @@
 package client
 
 import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
 	"github.com/armadaproject/armada/internal/common"
 	"github.com/armadaproject/armada/internal/common/util"
 	"github.com/armadaproject/armada/pkg/api"
@@ -38,6 +41,20 @@ func SubmitJobs(submitClient api.SubmitClient, request *api.JobSubmitRequest) (*
 	return submitClient.SubmitJobs(ctx, request)
 }
 
+// SubmitJobsWithWarnings is like SubmitJobs, but additionally returns any warnings the server returned about the jobs,
+// e.g., that a job is too small to be scheduled in the pool it targets.
+func SubmitJobsWithWarnings(submitClient api.SubmitClient, request *api.JobSubmitRequest) (*api.JobSubmitResponse, []string, error) {
+	AddClientIds(request.JobRequestItems)
+	ctx, cancel := common.ContextWithDefaultTimeout()
+	defer cancel()
+	var header metadata.MD
+	response, err := submitClient.SubmitJobs(ctx, request, grpc.Header(&header))
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, header.Get(api.SubmitWarningsHeader), nil
+}
+
 func CreateChunkedSubmitRequests(queue string, jobSetId string, jobs []*api.JobSubmitRequestItem) []*api.JobSubmitRequest {
 	requests := make([]*api.JobSubmitRequest, 0, 10)
 
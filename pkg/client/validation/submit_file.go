@@ -41,7 +41,21 @@ func ValidateSubmitFile(filePath string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	return validateSubmitFile(submitFile)
+}
+
+// ValidateSubmitData is like ValidateSubmitFile, but validates a submit file already read into memory,
+// e.g., one rendered from a template.
+func ValidateSubmitData(data []byte) (bool, error) {
+	submitFile := &rawJobSubmitFile{}
+	err := util.BindJsonOrYamlBytes(data, submitFile)
+	if err != nil {
+		return false, err
+	}
+	return validateSubmitFile(submitFile)
+}
 
+func validateSubmitFile(submitFile *rawJobSubmitFile) (bool, error) {
 	if len(submitFile.Jobs) <= 0 {
 		return false, errors.New("Warning: You have provided no jobs to submit.")
 	}
@@ -36,3 +36,10 @@ func WithSchedulerReportingClient(apiConnectionDetails *ApiConnectionDetails, ac
 		return action(client)
 	})
 }
+
+func WithSchedulerAdminClient(apiConnectionDetails *ApiConnectionDetails, action func(schedulerobjects.SchedulerAdminClient) error) error {
+	return WithConnection(apiConnectionDetails, func(cc *grpc.ClientConn) error {
+		client := schedulerobjects.NewSchedulerAdminClient(cc)
+		return action(client)
+	})
+}
@@ -97,6 +97,12 @@ type LeaseRequest struct {
 	UnassignedJobRunIds []armadaevents.Uuid `protobuf:"bytes,6,rep,name=unassigned_job_run_ids,json=unassignedJobRunIds,proto3" json:"unassignedJobRunIds"`
 	// Max number of jobs this request should return
 	MaxJobsToLease uint32 `protobuf:"varint,7,opt,name=max_jobs_to_lease,json=maxJobsToLease,proto3" json:"maxJobsToLease,omitempty"`
+	// Kubernetes version of the cluster, e.g., v1.27.3.
+	KubernetesVersion string `protobuf:"bytes,8,opt,name=kubernetes_version,json=kubernetesVersion,proto3" json:"kubernetesVersion,omitempty"`
+	// Runtime classes available in the cluster.
+	RuntimeClasses []string `protobuf:"bytes,9,rep,name=runtime_classes,json=runtimeClasses,proto3" json:"runtimeClasses,omitempty"`
+	// Optional features supported by the executor, e.g., in-place pod resize.
+	Features []string `protobuf:"bytes,10,rep,name=features,proto3" json:"features,omitempty"`
 }
 
 func (m *LeaseRequest) Reset()      { *m = LeaseRequest{} }
@@ -180,6 +186,27 @@ func (m *LeaseRequest) GetMaxJobsToLease() uint32 {
 	return 0
 }
 
+func (m *LeaseRequest) GetKubernetesVersion() string {
+	if m != nil {
+		return m.KubernetesVersion
+	}
+	return ""
+}
+
+func (m *LeaseRequest) GetRuntimeClasses() []string {
+	if m != nil {
+		return m.RuntimeClasses
+	}
+	return nil
+}
+
+func (m *LeaseRequest) GetFeatures() []string {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
 // Indicates that a job run is now leased.
 type JobRunLease struct {
 	JobRunId *armadaevents.Uuid      `protobuf:"bytes,1,opt,name=job_run_id,json=jobRunId,proto3" json:"jobRunId,omitempty"`
@@ -801,6 +828,31 @@ func (m *LeaseRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Features) > 0 {
+		for iNdEx := len(m.Features) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Features[iNdEx])
+			copy(dAtA[i:], m.Features[iNdEx])
+			i = encodeVarintExecutorapi(dAtA, i, uint64(len(m.Features[iNdEx])))
+			i--
+			dAtA[i] = 0x52
+		}
+	}
+	if len(m.RuntimeClasses) > 0 {
+		for iNdEx := len(m.RuntimeClasses) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.RuntimeClasses[iNdEx])
+			copy(dAtA[i:], m.RuntimeClasses[iNdEx])
+			i = encodeVarintExecutorapi(dAtA, i, uint64(len(m.RuntimeClasses[iNdEx])))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if len(m.KubernetesVersion) > 0 {
+		i -= len(m.KubernetesVersion)
+		copy(dAtA[i:], m.KubernetesVersion)
+		i = encodeVarintExecutorapi(dAtA, i, uint64(len(m.KubernetesVersion)))
+		i--
+		dAtA[i] = 0x42
+	}
 	if m.MaxJobsToLease != 0 {
 		i = encodeVarintExecutorapi(dAtA, i, uint64(m.MaxJobsToLease))
 		i--
@@ -1262,6 +1314,22 @@ func (m *LeaseRequest) Size() (n int) {
 	if m.MaxJobsToLease != 0 {
 		n += 1 + sovExecutorapi(uint64(m.MaxJobsToLease))
 	}
+	l = len(m.KubernetesVersion)
+	if l > 0 {
+		n += 1 + l + sovExecutorapi(uint64(l))
+	}
+	if len(m.RuntimeClasses) > 0 {
+		for _, s := range m.RuntimeClasses {
+			l = len(s)
+			n += 1 + l + sovExecutorapi(uint64(l))
+		}
+	}
+	if len(m.Features) > 0 {
+		for _, s := range m.Features {
+			l = len(s)
+			n += 1 + l + sovExecutorapi(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -1463,6 +1531,9 @@ func (this *LeaseRequest) String() string {
 		`Nodes:` + repeatedStringForNodes + `,`,
 		`UnassignedJobRunIds:` + repeatedStringForUnassignedJobRunIds + `,`,
 		`MaxJobsToLease:` + fmt.Sprintf("%v", this.MaxJobsToLease) + `,`,
+		`KubernetesVersion:` + fmt.Sprintf("%v", this.KubernetesVersion) + `,`,
+		`RuntimeClasses:` + fmt.Sprintf("%v", this.RuntimeClasses) + `,`,
+		`Features:` + fmt.Sprintf("%v", this.Features) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2101,6 +2172,102 @@ func (m *LeaseRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KubernetesVersion", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutorapi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthExecutorapi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutorapi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.KubernetesVersion = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RuntimeClasses", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutorapi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthExecutorapi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutorapi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RuntimeClasses = append(m.RuntimeClasses, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Features", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowExecutorapi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthExecutorapi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthExecutorapi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Features = append(m.Features, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipExecutorapi(dAtA[iNdEx:])